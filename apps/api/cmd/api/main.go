@@ -2,12 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"kuberan/internal/config"
 	"kuberan/internal/database"
 	"kuberan/internal/handlers"
 	"kuberan/internal/logger"
 	"kuberan/internal/middleware"
+	"kuberan/internal/notify"
 	"kuberan/internal/services"
 	"kuberan/internal/validator"
 	"net/http"
@@ -41,9 +43,14 @@ import (
 // @name X-API-Key
 // @description Pipeline API key for service-to-service authentication.
 
+// @securityDefinitions.apikey AdminKeyAuth
+// @in header
+// @name X-Admin-Key
+// @description Admin key for managing pipeline API keys.
+
 func main() {
-	// Initialize logger (use ENV var if available, default to development)
-	logger.Init(os.Getenv("ENV"))
+	// Initialize logger from ENV, LOG_LEVEL and LOG_FORMAT (defaults to development)
+	logger.Init(logger.OptionsFromEnv())
 	defer logger.Sync()
 
 	if err := run(); err != nil {
@@ -52,14 +59,16 @@ func main() {
 }
 
 func run() error {
-	log := logger.Get()
-
 	// Load configuration
 	appConfig, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
+	// Re-initialize the logger so settings from .env take effect
+	logger.Init(appConfig.LoggerOptions())
+	log := logger.Get()
+
 	// Initialize database configuration
 	dbConfig, err := database.NewConfig()
 	if err != nil {
@@ -75,31 +84,86 @@ func run() error {
 	// Run migrations automatically in development; in production, run manually via cmd/migrate
 	if appConfig.Env != config.Production {
 		if err := dbManager.RunMigrations(); err != nil {
-			return fmt.Errorf("failed to run database migrations: %w", err)
+			if !errors.Is(err, database.ErrMigrationLocked) {
+				return fmt.Errorf("failed to run database migrations: %w", err)
+			}
+			log.Warn("Skipping migrations: another process holds the migration lock")
 		}
 	}
 
 	// Initialize services
 	db := dbManager.DB()
-	userService := services.NewUserService(db)
-	accountService := services.NewAccountService(db)
+	var priceCache services.PriceCache
+	if appConfig.PriceCacheTTL > 0 {
+		priceCache = services.NewPriceCache(appConfig.PriceCacheTTL)
+	}
+	userService := services.NewUserServiceWithOptions(db, services.UserServiceOptions{
+		RejectPlusAddressDuplicates: appConfig.RejectPlusAddressDuplicates,
+		BcryptCost:                  appConfig.BcryptCost,
+	})
+	exportService := services.NewExportService(db)
+	backupService := services.NewBackupService(db)
+	smtpConfig := notify.SMTPConfig{
+		Host:     appConfig.SMTPHost,
+		Port:     appConfig.SMTPPort,
+		Username: appConfig.SMTPUsername,
+		Password: appConfig.SMTPPassword,
+		From:     appConfig.SMTPFrom,
+	}
+	// Without SMTP, alerts are skipped and verification emails only logged
+	var alertSender notify.Sender
+	var emailSender notify.Sender = notify.NewLogSender()
+	if smtpConfig.Enabled() {
+		alertSender = notify.NewSMTPSender(smtpConfig)
+		emailSender = alertSender
+	}
+	emailChangeService := services.NewEmailChangeService(db, emailSender)
+	notificationService := services.NewNotificationService(db, alertSender)
+	accountService := services.NewAccountService(db, priceCache)
+	accountGroupService := services.NewAccountGroupService(db)
+	shareService := services.NewShareService(db)
 	categoryService := services.NewCategoryService(db)
-	transactionService := services.NewTransactionService(db, accountService)
+	transactionService := services.NewTransactionService(db, accountService, notificationService)
 	budgetService := services.NewBudgetService(db)
-	investmentService := services.NewInvestmentService(db, accountService)
-	securityService := services.NewSecurityService(db)
+	ruleService := services.NewRuleService(db)
+	exchangeRateService := services.NewExchangeRateService(db, appConfig.ExchangeRateCacheTTL)
+	investmentService := services.NewInvestmentService(db, accountService, priceCache, exchangeRateService, services.TradePriceCheck{
+		MaxDeviation: appConfig.TradePriceMaxDeviation,
+		WindowDays:   appConfig.TradePriceWindowDays,
+	})
+	securityService := services.NewSecurityService(db, priceCache, appConfig.PriceMaxDeviation)
 	snapshotService := services.NewPortfolioSnapshotService(db)
+	statementService := services.NewStatementService(db)
+	statsService := services.NewStatsService(db)
+	pipelineKeyService := services.NewPipelineKeyService(db)
+	oracleRunService := services.NewOracleRunService(db)
 	auditService := services.NewAuditService(db)
+	eventDispatcher := services.NewEventDispatcher(db)
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(userService, auditService)
+	emailChangeHandler := handlers.NewEmailChangeHandler(emailChangeService, auditService)
+	exportHandler := handlers.NewExportHandler(exportService, auditService)
+	backupHandler := handlers.NewBackupHandler(backupService, auditService)
 	accountHandler := handlers.NewAccountHandler(accountService, auditService)
+	accountGroupHandler := handlers.NewAccountGroupHandler(accountGroupService, auditService)
+	shareHandler := handlers.NewShareHandler(shareService, auditService)
 	categoryHandler := handlers.NewCategoryHandler(categoryService, auditService)
-	transactionHandler := handlers.NewTransactionHandler(transactionService, auditService)
+	transactionHandler := handlers.NewTransactionHandler(transactionService, accountService, auditService).
+		WithDefaultSummaryMonths(appConfig.SummaryDefaultMonths)
 	budgetHandler := handlers.NewBudgetHandler(budgetService, auditService)
-	investmentHandler := handlers.NewInvestmentHandler(investmentService, auditService)
-	securityHandler := handlers.NewSecurityHandler(securityService, auditService)
+	ruleHandler := handlers.NewRuleHandler(ruleService, auditService)
+	investmentHandler := handlers.NewInvestmentHandler(investmentService, securityService, auditService)
+	securityHandler := handlers.NewSecurityHandler(securityService, auditService).
+		WithMaxBatchSize(appConfig.SecurityBatchMaxSize)
 	snapshotHandler := handlers.NewPortfolioSnapshotHandler(snapshotService, auditService)
+	statementHandler := handlers.NewStatementHandler(statementService)
+	statsHandler := handlers.NewStatsHandler(statsService)
+	eventHandler := handlers.NewEventHandler(eventDispatcher)
+	exchangeRateHandler := handlers.NewExchangeRateHandler(exchangeRateService)
+	pipelineKeyHandler := handlers.NewPipelineKeyHandler(pipelineKeyService)
+	oracleRunHandler := handlers.NewOracleRunHandler(oracleRunService)
+	metaHandler := handlers.NewMetaHandler()
 
 	// Register custom validators before routes
 	validator.Register()
@@ -118,7 +182,7 @@ func run() error {
 	// CORS middleware — CORS_ORIGIN env var controls allowed origins (default: *)
 	router.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", appConfig.CORSOrigin)
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 
@@ -143,6 +207,10 @@ func run() error {
 		c.JSON(http.StatusOK, gin.H{"status": "ok", "database": "connected"})
 	})
 
+	// Effective configuration with secrets redacted; admin-only in production
+	debugHandler := handlers.NewDebugHandler(appConfig, database.Pool)
+	router.GET("/api/debug/config", middleware.NonProductionOrAdmin(appConfig.Env, appConfig.PipelineAdminKey), debugHandler.GetConfig)
+
 	// API v1 group
 	v1 := router.Group("/api/v1")
 
@@ -151,6 +219,14 @@ func run() error {
 	auth.POST("/register", authHandler.Register)
 	auth.POST("/login", authHandler.Login)
 	auth.POST("/refresh", authHandler.RefreshToken)
+	auth.GET("/verify-email", emailChangeHandler.VerifyEmail)
+	auth.POST("/verify-email", emailChangeHandler.VerifyEmail)
+
+	// Error catalog, for clients generating typed error handling
+	v1.GET("/meta/errors", metaHandler.ListErrors)
+
+	// Analytics routes answer within a shorter deadline than the server's
+	analyticsTimeout := middleware.Timeout(appConfig.AnalyticsTimeout)
 
 	// Protected routes
 	protected := v1.Group("/")
@@ -158,6 +234,12 @@ func run() error {
 
 	// User profile
 	protected.GET("/profile", authHandler.GetProfile)
+	protected.PUT("/profile", authHandler.UpdateProfile)
+	protected.PUT("/profile/password", authHandler.ChangePassword)
+	protected.POST("/profile/email", emailChangeHandler.RequestEmailChange)
+	protected.GET("/profile/export", exportHandler.ExportUserData)
+	protected.GET("/profile/backup", backupHandler.CreateBackup)
+	protected.POST("/profile/restore", backupHandler.RestoreBackup)
 
 	// Account routes
 	accounts := protected.Group("/accounts")
@@ -165,39 +247,85 @@ func run() error {
 	accounts.POST("/investment", accountHandler.CreateInvestmentAccount)
 	accounts.POST("/credit-card", accountHandler.CreateCreditCardAccount)
 	accounts.GET("", accountHandler.GetUserAccounts)
+	accounts.GET("/archived", accountHandler.GetArchivedAccounts)
 	accounts.GET("/:id", accountHandler.GetAccountByID)
 	accounts.PUT("/:id", accountHandler.UpdateAccount)
+	accounts.DELETE("/:id", accountHandler.DeleteAccount)
 	accounts.GET("/:id/transactions", transactionHandler.GetAccountTransactions)
 	accounts.GET("/:id/investments", investmentHandler.GetAccountInvestments)
+	accounts.GET("/:id/statement", accountHandler.GetAccountStatement)
+	accounts.GET("/:id/timeline", accountHandler.GetAccountTimeline)
+
+	// Account group routes
+	accountGroups := protected.Group("/account-groups")
+	accountGroups.POST("", accountGroupHandler.CreateAccountGroup)
+	accountGroups.GET("", accountGroupHandler.GetAccountGroups)
+	accountGroups.PUT("/order", accountGroupHandler.ReorderAccountGroups)
+	accountGroups.GET("/:id", accountGroupHandler.GetAccountGroup)
+	accountGroups.PUT("/:id", accountGroupHandler.UpdateAccountGroup)
+	accountGroups.DELETE("/:id", accountGroupHandler.DeleteAccountGroup)
 
 	// Transaction routes
 	transactions := protected.Group("/transactions")
 	transactions.GET("", transactionHandler.GetUserTransactions)
 	transactions.POST("", transactionHandler.CreateTransaction)
 	transactions.POST("/transfer", transactionHandler.CreateTransfer)
-	transactions.GET("/spending-by-category", transactionHandler.GetSpendingByCategory)
-	transactions.GET("/monthly-summary", transactionHandler.GetMonthlySummary)
-	transactions.GET("/daily-spending", transactionHandler.GetDailySpending)
+	transactions.POST("/split-transfer", transactionHandler.CreateSplitTransfer)
+	transactions.POST("/bulk-delete", transactionHandler.BulkDeleteTransactions)
+	transactions.POST("/bulk-categorize", transactionHandler.BulkCategorizeTransactions)
+	transactions.GET("/spending-by-category", analyticsTimeout, transactionHandler.GetSpendingByCategory)
+	transactions.GET("/spending-by-currency", analyticsTimeout, transactionHandler.GetSpendingByCurrency)
+	transactions.GET("/monthly-summary", analyticsTimeout, transactionHandler.GetMonthlySummary)
+	transactions.GET("/daily-spending", analyticsTimeout, transactionHandler.GetDailySpending)
+	transactions.GET("/flagged", transactionHandler.GetFlaggedTransactions)
+	transactions.GET("/transfers", transactionHandler.GetTransfers)
 	transactions.GET("/:id", transactionHandler.GetTransactionByID)
 	transactions.PUT("/:id", transactionHandler.UpdateTransaction)
+	transactions.PATCH("/:id", transactionHandler.UpdateTransaction)
 	transactions.DELETE("/:id", transactionHandler.DeleteTransaction)
 
+	// Report routes
+	reports := protected.Group("/reports")
+	reports.GET("/spending-by-account", transactionHandler.GetSpendingByAccount)
+
 	// Budget routes
 	budgets := protected.Group("/budgets")
 	budgets.POST("", budgetHandler.CreateBudget)
+	budgets.POST("/clone-last-month", budgetHandler.CloneLastMonth)
 	budgets.GET("", budgetHandler.GetBudgets)
 	budgets.GET("/:id", budgetHandler.GetBudget)
 	budgets.PUT("/:id", budgetHandler.UpdateBudget)
 	budgets.DELETE("/:id", budgetHandler.DeleteBudget)
 	budgets.GET("/:id/progress", budgetHandler.GetBudgetProgress)
+	budgets.GET("/:id/burndown", budgetHandler.GetBudgetBurndown)
+
+	// Account sharing routes
+	shares := protected.Group("/shares")
+	shares.POST("", shareHandler.CreateShare)
+	shares.GET("", shareHandler.GetShares)
+	shares.POST("/:id/accept", shareHandler.AcceptShare)
+	shares.DELETE("/:id", shareHandler.DeleteShare)
+
+	// Categorization rule routes
+	rules := protected.Group("/rules")
+	rules.POST("", ruleHandler.CreateRule)
+	rules.GET("", ruleHandler.GetRules)
+	rules.POST("/apply", ruleHandler.ApplyRules)
+	rules.GET("/:id", ruleHandler.GetRule)
+	rules.PUT("/:id", ruleHandler.UpdateRule)
+	rules.DELETE("/:id", ruleHandler.DeleteRule)
 
 	// Investment routes
 	investments := protected.Group("/investments")
 	investments.POST("", investmentHandler.AddInvestment)
 	investments.GET("", investmentHandler.GetAllInvestments)
-	investments.GET("/portfolio", investmentHandler.GetPortfolio)
-	investments.GET("/snapshots", snapshotHandler.GetSnapshots)
+	investments.GET("/portfolio", analyticsTimeout, investmentHandler.GetPortfolio)
+	investments.GET("/tax-report", investmentHandler.GetTaxReport)
+	investments.GET("/export", investmentHandler.ExportInvestments)
+	investments.GET("/snapshots", analyticsTimeout, snapshotHandler.GetSnapshots)
+	investments.GET("/benchmark", snapshotHandler.GetBenchmark)
 	investments.GET("/:id", investmentHandler.GetInvestment)
+	investments.PUT("/:id", investmentHandler.UpdateInvestment)
 	investments.POST("/:id/buy", investmentHandler.RecordBuy)
 	investments.POST("/:id/sell", investmentHandler.RecordSell)
 	investments.POST("/:id/dividend", investmentHandler.RecordDividend)
@@ -210,6 +338,17 @@ func run() error {
 	securities.GET("/:id", securityHandler.GetSecurity)
 	securities.GET("/:id/prices", securityHandler.GetPriceHistory)
 
+	// Exchange rate routes
+	protected.GET("/exchange-rates", exchangeRateHandler.GetExchangeRates)
+
+	// Statement routes
+	protected.GET("/statements/:month", statementHandler.GetMonthlyStatement)
+
+	// Usage statistics routes
+	stats := protected.Group("/stats")
+	stats.GET("/accounts", analyticsTimeout, statsHandler.GetAccountStats)
+	stats.GET("/categories", analyticsTimeout, statsHandler.GetCategoryStats)
+
 	// Category routes
 	categories := protected.Group("/categories")
 	categories.POST("", categoryHandler.CreateCategory)
@@ -220,16 +359,35 @@ func run() error {
 
 	// Pipeline routes (API key auth, no JWT)
 	pipeline := v1.Group("/pipeline")
-	pipeline.Use(middleware.PipelineAuthMiddleware(appConfig.PipelineAPIKey))
+	pipeline.Use(middleware.PipelineAuthMiddleware(appConfig.PipelineAPIKey, pipelineKeyService))
 	pipeline.GET("/securities", securityHandler.ListAllSecurities)
 	pipeline.POST("/securities", securityHandler.CreateSecurity)
+	pipeline.POST("/securities/batch", securityHandler.CreateSecuritiesBatch)
 	pipeline.POST("/securities/prices", securityHandler.RecordPrices)
+	pipeline.PUT("/securities/:id/prices/:priceId", securityHandler.CorrectPrice)
 	pipeline.POST("/snapshots", snapshotHandler.ComputeSnapshots)
+	pipeline.POST("/snapshots/backfill", snapshotHandler.BackfillSnapshots)
+	pipeline.POST("/snapshots/recompute", snapshotHandler.RecomputeSnapshots)
+	pipeline.POST("/transactions/settle", transactionHandler.SettlePendingTransactions)
+	pipeline.POST("/budgets/rollover", budgetHandler.RolloverBudgets)
+	pipeline.POST("/email-changes/purge", emailChangeHandler.PurgeExpiredEmailChanges)
+	pipeline.POST("/events/dispatch", eventHandler.DispatchEvents)
+	pipeline.POST("/exchange-rates", exchangeRateHandler.RecordExchangeRates)
+	pipeline.POST("/oracle-runs", oracleRunHandler.RecordOracleRun)
+
+	// Admin routes (admin key auth, no JWT)
+	admin := v1.Group("/admin")
+	admin.Use(middleware.AdminAuthMiddleware(appConfig.PipelineAdminKey))
+	admin.POST("/pipeline-keys", pipelineKeyHandler.CreatePipelineKey)
+	admin.GET("/pipeline-keys", pipelineKeyHandler.ListPipelineKeys)
+	admin.DELETE("/pipeline-keys/:id", pipelineKeyHandler.RevokePipelineKey)
+	admin.GET("/oracle-runs", oracleRunHandler.ListOracleRuns)
 
 	// Create HTTP server
 	srv := &http.Server{
-		Addr:    ":" + appConfig.Port,
-		Handler: router,
+		Addr:         ":" + appConfig.Port,
+		Handler:      router,
+		WriteTimeout: appConfig.ServerWriteTimeout,
 	}
 
 	// Start server in goroutine
@@ -241,6 +399,16 @@ func run() error {
 		}
 	}()
 
+	// Deliver outbox events in the background unless the pipeline drains them
+	dispatchCtx, stopDispatch := context.WithCancel(context.Background())
+	dispatchDone := make(chan struct{})
+	go func() {
+		defer close(dispatchDone)
+		if appConfig.EventDispatchInterval > 0 {
+			eventDispatcher.Run(dispatchCtx, appConfig.EventDispatchInterval)
+		}
+	}()
+
 	// Wait for interrupt signal for graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -255,6 +423,11 @@ func run() error {
 		log.Errorf("Server forced to shutdown: %v", err)
 	}
 
+	// Let queued email alerts and the current event dispatch finish before the database goes away
+	notificationService.Wait()
+	stopDispatch()
+	<-dispatchDone
+
 	// Close database connections
 	sqlDB, dbErr := db.DB()
 	if dbErr == nil {
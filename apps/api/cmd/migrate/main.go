@@ -6,15 +6,12 @@ import (
 	"strconv"
 
 	"kuberan/internal/config"
+	"kuberan/internal/database"
 	"kuberan/internal/logger"
-
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
 func main() {
-	logger.Init(os.Getenv("ENV"))
+	logger.Init(logger.OptionsFromEnv())
 	defer logger.Sync()
 
 	if err := run(); err != nil {
@@ -24,36 +21,30 @@ func main() {
 
 func run() error {
 	if len(os.Args) < 2 {
-		return fmt.Errorf("usage: migrate <up|down|version> [N]")
+		return fmt.Errorf("usage: migrate <up|down|version|force|repair> [N]")
 	}
 
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	logger.Init(cfg.LoggerOptions())
 
-	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
-		cfg.DBUser, cfg.DBPassword, cfg.DBHost, cfg.DBPort, cfg.DBName, cfg.DBSSLMode)
+	dbConfig, err := database.NewConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load database configuration: %w", err)
+	}
 
-	m, err := migrate.New("file://migrations", dsn)
+	manager, err := database.NewManager(dbConfig)
 	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
+		return fmt.Errorf("failed to create database manager: %w", err)
 	}
-	defer func() {
-		srcErr, dbErr := m.Close()
-		if srcErr != nil {
-			logger.Get().Warnf("migrate source close error: %v", srcErr)
-		}
-		if dbErr != nil {
-			logger.Get().Warnf("migrate database close error: %v", dbErr)
-		}
-	}()
 
 	command := os.Args[1]
 
 	switch command {
 	case "up":
-		if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		if err := manager.RunMigrations(); err != nil {
 			return fmt.Errorf("migration up failed: %w", err)
 		}
 		logger.Get().Info("Migrations applied successfully")
@@ -66,20 +57,44 @@ func run() error {
 				return fmt.Errorf("invalid step count: %w", err)
 			}
 		}
-		if err := m.Steps(-steps); err != nil && err != migrate.ErrNoChange {
-			return fmt.Errorf("migration down failed: %w", err)
+		if err := manager.MigrateDown(steps); err != nil {
+			return err
 		}
 		logger.Get().Infof("Rolled back %d migration(s)", steps)
 
 	case "version":
-		version, dirty, err := m.Version()
+		version, dirty, err := manager.MigrationVersion()
 		if err != nil {
 			return fmt.Errorf("failed to get version: %w", err)
 		}
 		logger.Get().Infof("Version: %d, Dirty: %v", version, dirty)
 
+	case "force":
+		if len(os.Args) < 3 {
+			return fmt.Errorf("usage: migrate force <version>")
+		}
+		version, err := strconv.Atoi(os.Args[2])
+		if err != nil {
+			return fmt.Errorf("invalid version: %w", err)
+		}
+		if err := manager.ForceVersion(version); err != nil {
+			return err
+		}
+		logger.Get().Infof("Forced version to %d and cleared dirty flag", version)
+
+	case "repair":
+		result, err := manager.RepairDirty()
+		if err != nil {
+			return fmt.Errorf("repair failed: %w", err)
+		}
+		if result.Applied {
+			logger.Get().Infof("Migration %d was fully applied; cleared dirty flag", result.DirtyVersion)
+		} else {
+			logger.Get().Infof("Migration %d was not applied; reset version to %d", result.DirtyVersion, result.Version)
+		}
+
 	default:
-		return fmt.Errorf("unknown command: %s (use up, down, or version)", command)
+		return fmt.Errorf("unknown command: %s (use up, down, version, force, or repair)", command)
 	}
 
 	return nil
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"kuberan/internal/config"
+	"kuberan/internal/database"
+	"kuberan/internal/logger"
+	"kuberan/internal/services"
+)
+
+func main() {
+	logger.Init(logger.OptionsFromEnv())
+	defer logger.Sync()
+
+	if err := run(); err != nil {
+		logger.Get().Fatalf("Seed error: %v", err)
+	}
+}
+
+func run() error {
+	if len(os.Args) < 2 || os.Args[1] != "demo-user" {
+		return fmt.Errorf("usage: seed demo-user [-email EMAIL] [-seed N] [-as-of YYYY-MM-DD]")
+	}
+
+	flags := flag.NewFlagSet("demo-user", flag.ContinueOnError)
+	seed := flags.Int64("seed", time.Now().UnixNano(), "random seed; the same seed and -as-of reproduce the same data")
+	email := flags.String("email", "", "email of the new demo user (default demo-<seed>@demo.kuberan.local)")
+	asOfStr := flags.String("as-of", time.Now().UTC().Format("2006-01-02"), "last day of the generated history")
+	if err := flags.Parse(os.Args[2:]); err != nil {
+		return err
+	}
+	asOf, err := time.Parse("2006-01-02", *asOfStr)
+	if err != nil {
+		return fmt.Errorf("invalid -as-of: %w", err)
+	}
+	if *email == "" {
+		*email = fmt.Sprintf("demo-%d@demo.kuberan.local", *seed)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	logger.Init(cfg.LoggerOptions())
+
+	dbConfig, err := database.NewConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load database configuration: %w", err)
+	}
+
+	manager, err := database.NewManager(dbConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create database manager: %w", err)
+	}
+	db := manager.DB()
+
+	password, err := randomPassword()
+	if err != nil {
+		return fmt.Errorf("failed to generate password: %w", err)
+	}
+	userService := services.NewUserServiceWithOptions(db, services.UserServiceOptions{BcryptCost: cfg.BcryptCost})
+	user, err := userService.CreateUser(*email, password, "Demo", "User")
+	if err != nil {
+		return fmt.Errorf("failed to create demo user: %w", err)
+	}
+
+	summary, err := services.NewSeedService(db).SeedDemoUser(user.ID, *seed, asOf.Add(24*time.Hour-time.Second))
+	if err != nil {
+		return fmt.Errorf("failed to seed demo user: %w", err)
+	}
+
+	logger.Get().Infow("Demo user seeded",
+		"user_id", user.ID,
+		"seed", *seed,
+		"accounts", summary.Accounts,
+		"transactions", summary.Transactions,
+		"investments", summary.Investments,
+		"snapshots", summary.Snapshots,
+	)
+	fmt.Printf("email: %s\npassword: %s\n", user.Email, password)
+	return nil
+}
+
+// randomPassword returns a password for the demo user's login.
+func randomPassword() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -1,13 +1,21 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"kuberan/internal/logger"
+	"kuberan/internal/password"
 
 	"github.com/joho/godotenv"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // Environment represents the application environment.
@@ -25,6 +33,10 @@ type Config struct {
 	// Environment
 	Env Environment
 
+	// Logging; empty values fall back to the Env defaults
+	LogLevel  string // debug, info, warn or error
+	LogFormat string // json or console
+
 	// Server
 	Port string
 
@@ -37,16 +49,81 @@ type Config struct {
 	DBSSLMode  string
 
 	// JWT
-	JWTSecret        string
-	JWTExpirationDur time.Duration
+	JWTSecret               string
+	JWTKeys                 []JWTKey      // Signing keys, oldest first; from JWT_KEYS, JWT_SIGNING_KEY or JWTSecret under DefaultJWTKeyID
+	JWTExpirationDur        time.Duration // Access-token lifetime
+	JWTRefreshExpirationDur time.Duration // Refresh-token lifetime
+	JWTIssuer               string
+	JWTAudience             string // Empty omits the aud claim and skips its check
 
 	// Pipeline
-	PipelineAPIKey string
+	PipelineAPIKey   string // Bootstrap key, accepted alongside the stored keys
+	PipelineAdminKey string // Guards pipeline key management; empty disables it
+
+	// Caching
+	PriceCacheTTL        time.Duration // 0 disables the latest-price cache
+	ExchangeRateCacheTTL time.Duration // How long the exchange-rate snapshot is served; 0 reads rates on every request
+
+	// Prices
+	PriceMaxDeviation float64 // Percent a recorded price may move from the previous one; 0 disables the check
+
+	// Securities
+	SecurityBatchMaxSize int // Most securities one pipeline batch may upsert
+
+	// Trades
+	TradePriceMaxDeviation float64 // Percent a buy/sell price may differ from the stored price near the trade date; 0 disables the check
+	TradePriceWindowDays   int     // How many days from the trade date a stored price may be to serve as reference
+
+	// Reports
+	SummaryDefaultMonths int // Monthly summary length when months is not given
+
+	// Timeouts
+	ServerWriteTimeout time.Duration // Deadline for writing any response
+	AnalyticsTimeout   time.Duration // Deadline of analytics routes; below ServerWriteTimeout
+	SlowQueryThreshold time.Duration // Queries at least this slow are logged; 0 disables it
+
+	// Domain events
+	EventDispatchInterval time.Duration // How often outbox events are delivered; 0 leaves it to the pipeline endpoint
+
+	// Registration
+	RejectPlusAddressDuplicates bool // Treat "ann+tag@x" as taken when "ann@x" (or another +tag) is registered
+	BcryptCost                  int  // Cost of new password hashes; production requires 10–15
+
+	// Email notifications; alerts are skipped when SMTPHost is empty
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
 
 	// CORS
 	CORSOrigin string
 }
 
+// DefaultJWTKeyID is the kid of the key built from JWT_SECRET when JWT_KEYS is
+// not set. Tokens without a kid header are verified against this key.
+const DefaultJWTKeyID = "default"
+
+// JWTKey is a named JWT signing secret. Keys are accepted until RetiresAt,
+// which lets a secret be rotated without invalidating every session at once.
+type JWTKey struct {
+	ID        string     `json:"kid"`
+	Secret    string     `json:"secret"`
+	RetiresAt *time.Time `json:"retires_at,omitempty"`
+}
+
+// ActiveAt reports whether the key is still accepted at t.
+func (k JWTKey) ActiveAt(t time.Time) bool {
+	return k.RetiresAt == nil || t.Before(*k.RetiresAt)
+}
+
+// Range of BCRYPT_COST accepted in production. Elsewhere any cost bcrypt
+// supports is allowed, so that tests and local setups can hash cheaply.
+const (
+	MinBcryptCost = 10
+	MaxBcryptCost = 15
+)
+
 var appConfig *Config
 
 // Load loads configuration from environment variables
@@ -61,6 +138,10 @@ func Load() (*Config, error) {
 		// Environment
 		Env: Environment(getEnv("ENV", string(Development))),
 
+		// Logging
+		LogLevel:  os.Getenv("LOG_LEVEL"),
+		LogFormat: os.Getenv("LOG_FORMAT"),
+
 		// Server
 		Port: getEnv("PORT", "8080"),
 
@@ -76,21 +157,164 @@ func Load() (*Config, error) {
 		JWTSecret: getEnv("JWT_SECRET", "fallback-secret-key-for-dev-only"),
 
 		// Pipeline
-		PipelineAPIKey: os.Getenv("PIPELINE_API_KEY"),
+		PipelineAPIKey:   os.Getenv("PIPELINE_API_KEY"),
+		PipelineAdminKey: os.Getenv("PIPELINE_ADMIN_KEY"),
+
+		// Registration
+		RejectPlusAddressDuplicates: os.Getenv("REJECT_PLUS_ADDRESS_DUPLICATES") == "true",
+
+		// Email notifications
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		SMTPFrom:     os.Getenv("SMTP_FROM"),
 
 		// CORS
 		CORSOrigin: getEnv("CORS_ORIGIN", "*"),
 	}
 
-	// Parse JWT expiration duration
-	expStr := getEnv("JWT_EXPIRES_IN", "24h")
+	// Parse JWT settings
+	expStr := getEnv("JWT_EXPIRES_IN", "15m")
 	expDur, err := time.ParseDuration(expStr)
-	if err != nil {
-		logger.Get().Warnf("Invalid JWT_EXPIRES_IN value '%s', falling back to 24h", expStr)
-		expDur = 24 * time.Hour
+	if err != nil || expDur <= 0 {
+		logger.Get().Warnf("Invalid JWT_EXPIRES_IN value '%s', falling back to 15m", expStr)
+		expDur = 15 * time.Minute
 	}
 	config.JWTExpirationDur = expDur
 
+	refreshStr := getEnv("JWT_REFRESH_EXPIRES_IN", "168h")
+	refreshDur, err := time.ParseDuration(refreshStr)
+	if err != nil || refreshDur <= 0 {
+		logger.Get().Warnf("Invalid JWT_REFRESH_EXPIRES_IN value '%s', falling back to 168h", refreshStr)
+		refreshDur = 7 * 24 * time.Hour
+	}
+	config.JWTRefreshExpirationDur = refreshDur
+
+	config.JWTIssuer = getEnv("JWT_ISSUER", "kuberan-api")
+	config.JWTAudience = os.Getenv("JWT_AUDIENCE")
+
+	keysJSON, keysFile := os.Getenv("JWT_KEYS"), os.Getenv("JWT_KEYS_FILE")
+	signingKey, previousKeys := os.Getenv("JWT_SIGNING_KEY"), os.Getenv("JWT_PREVIOUS_KEYS")
+	if err := checkJWTKeySources(keysJSON, keysFile, signingKey, previousKeys); err != nil {
+		return nil, err
+	}
+	if signingKey != "" {
+		config.JWTKeys = jwtKeysFromSecrets(signingKey, previousKeys, os.Getenv("JWT_SECRET"))
+	} else {
+		keys, err := loadJWTKeys(keysJSON, keysFile, config.JWTSecret)
+		if err != nil {
+			return nil, err
+		}
+		config.JWTKeys = keys
+	}
+
+	// Parse latest-price cache TTL
+	ttlStr := getEnv("PRICE_CACHE_TTL", "60s")
+	ttl, err := time.ParseDuration(ttlStr)
+	if err != nil || ttl < 0 {
+		logger.Get().Warnf("Invalid PRICE_CACHE_TTL value '%s', falling back to 60s", ttlStr)
+		ttl = 60 * time.Second
+	}
+	config.PriceCacheTTL = ttl
+
+	// Parse the price anomaly threshold
+	deviationStr := getEnv("PRICE_MAX_DEVIATION", "50")
+	deviation, err := strconv.ParseFloat(deviationStr, 64)
+	if err != nil || deviation < 0 {
+		logger.Get().Warnf("Invalid PRICE_MAX_DEVIATION value '%s', falling back to 50", deviationStr)
+		deviation = 50
+	}
+	config.PriceMaxDeviation = deviation
+
+	// Parse the security batch size
+	batchSizeStr := getEnv("SECURITY_BATCH_MAX_SIZE", "500")
+	batchSize, err := strconv.Atoi(batchSizeStr)
+	if err != nil || batchSize < 1 {
+		logger.Get().Warnf("Invalid SECURITY_BATCH_MAX_SIZE value '%s', falling back to 500", batchSizeStr)
+		batchSize = 500
+	}
+	config.SecurityBatchMaxSize = batchSize
+
+	// Parse the trade price check
+	tradeDeviationStr := getEnv("TRADE_PRICE_MAX_DEVIATION", "50")
+	tradeDeviation, err := strconv.ParseFloat(tradeDeviationStr, 64)
+	if err != nil || tradeDeviation < 0 {
+		logger.Get().Warnf("Invalid TRADE_PRICE_MAX_DEVIATION value '%s', falling back to 50", tradeDeviationStr)
+		tradeDeviation = 50
+	}
+	config.TradePriceMaxDeviation = tradeDeviation
+
+	tradeWindowStr := getEnv("TRADE_PRICE_WINDOW_DAYS", "7")
+	tradeWindow, err := strconv.Atoi(tradeWindowStr)
+	if err != nil || tradeWindow < 1 {
+		logger.Get().Warnf("Invalid TRADE_PRICE_WINDOW_DAYS value '%s', falling back to 7", tradeWindowStr)
+		tradeWindow = 7
+	}
+	config.TradePriceWindowDays = tradeWindow
+
+	// Parse exchange-rate snapshot TTL
+	rateTTLStr := getEnv("EXCHANGE_RATE_CACHE_TTL", "5m")
+	rateTTL, err := time.ParseDuration(rateTTLStr)
+	if err != nil || rateTTL < 0 {
+		logger.Get().Warnf("Invalid EXCHANGE_RATE_CACHE_TTL value '%s', falling back to 5m", rateTTLStr)
+		rateTTL = 5 * time.Minute
+	}
+	config.ExchangeRateCacheTTL = rateTTL
+
+	// Parse the default monthly summary length
+	monthsStr := getEnv("SUMMARY_DEFAULT_MONTHS", "6")
+	months, err := strconv.Atoi(monthsStr)
+	if err != nil || months < 1 {
+		logger.Get().Warnf("Invalid SUMMARY_DEFAULT_MONTHS value '%s', falling back to 6", monthsStr)
+		months = 6
+	}
+	config.SummaryDefaultMonths = months
+
+	// Parse the server and analytics timeouts
+	writeTimeoutStr := getEnv("SERVER_WRITE_TIMEOUT", "60s")
+	writeTimeout, err := time.ParseDuration(writeTimeoutStr)
+	if err != nil || writeTimeout <= 0 {
+		logger.Get().Warnf("Invalid SERVER_WRITE_TIMEOUT value '%s', falling back to 60s", writeTimeoutStr)
+		writeTimeout = 60 * time.Second
+	}
+	config.ServerWriteTimeout = writeTimeout
+
+	analyticsTimeoutStr := getEnv("ANALYTICS_TIMEOUT", "15s")
+	analyticsTimeout, err := time.ParseDuration(analyticsTimeoutStr)
+	if err != nil || analyticsTimeout <= 0 || analyticsTimeout >= writeTimeout {
+		logger.Get().Warnf("Invalid ANALYTICS_TIMEOUT value '%s', falling back to half of SERVER_WRITE_TIMEOUT", analyticsTimeoutStr)
+		analyticsTimeout = writeTimeout / 2
+	}
+	config.AnalyticsTimeout = analyticsTimeout
+
+	// Parse the slow-query logging threshold
+	slowQueryStr := getEnv("SLOW_QUERY_THRESHOLD", "200ms")
+	slowQuery, err := time.ParseDuration(slowQueryStr)
+	if err != nil || slowQuery < 0 {
+		logger.Get().Warnf("Invalid SLOW_QUERY_THRESHOLD value '%s', falling back to 200ms", slowQueryStr)
+		slowQuery = 200 * time.Millisecond
+	}
+	config.SlowQueryThreshold = slowQuery
+
+	// Parse the outbox dispatch interval
+	dispatchStr := getEnv("EVENT_DISPATCH_INTERVAL", "10s")
+	dispatch, err := time.ParseDuration(dispatchStr)
+	if err != nil || dispatch < 0 {
+		logger.Get().Warnf("Invalid EVENT_DISPATCH_INTERVAL value '%s', falling back to 10s", dispatchStr)
+		dispatch = 10 * time.Second
+	}
+	config.EventDispatchInterval = dispatch
+
+	// Parse the password hashing cost
+	bcryptCostStr := getEnv("BCRYPT_COST", strconv.Itoa(password.DefaultCost))
+	bcryptCost, err := strconv.Atoi(bcryptCostStr)
+	if err != nil || bcryptCost < bcrypt.MinCost || bcryptCost > bcrypt.MaxCost {
+		logger.Get().Warnf("Invalid BCRYPT_COST value '%s', falling back to %d", bcryptCostStr, password.DefaultCost)
+		bcryptCost = password.DefaultCost
+	}
+	config.BcryptCost = bcryptCost
+
 	// Validate production configuration
 	if config.Env == Production {
 		if err := config.validateProduction(); err != nil {
@@ -102,6 +326,119 @@ func Load() (*Config, error) {
 	return config, nil
 }
 
+// redacted stands in for a secret that is set; unset secrets stay empty so
+// their absence is still visible.
+const redacted = "[redacted]"
+
+// RedactedConfig is the effective configuration with every secret replaced by
+// a "[redacted]" marker, safe to show for diagnostics.
+type RedactedConfig struct {
+	Env        Environment `json:"env"`
+	LogLevel   string      `json:"log_level"`
+	LogFormat  string      `json:"log_format"`
+	Port       string      `json:"port"`
+	CORSOrigin string      `json:"cors_origin"`
+
+	DBHost     string `json:"db_host"`
+	DBPort     string `json:"db_port"`
+	DBUser     string `json:"db_user"`
+	DBPassword string `json:"db_password"`
+	DBName     string `json:"db_name"`
+	DBSSLMode  string `json:"db_sslmode"`
+
+	JWTSecret           string   `json:"jwt_secret"`
+	JWTKeyIDs           []string `json:"jwt_key_ids"`
+	JWTExpiresIn        string   `json:"jwt_expires_in"`
+	JWTRefreshExpiresIn string   `json:"jwt_refresh_expires_in"`
+	JWTIssuer           string   `json:"jwt_issuer"`
+	JWTAudience         string   `json:"jwt_audience"`
+
+	PipelineAPIKey   string `json:"pipeline_api_key"`
+	PipelineAdminKey string `json:"pipeline_admin_key"`
+
+	PriceCacheTTL          string  `json:"price_cache_ttl"`
+	ExchangeRateCacheTTL   string  `json:"exchange_rate_cache_ttl"`
+	PriceMaxDeviation      float64 `json:"price_max_deviation"`
+	SecurityBatchMaxSize   int     `json:"security_batch_max_size"`
+	TradePriceMaxDeviation float64 `json:"trade_price_max_deviation"`
+	TradePriceWindowDays   int     `json:"trade_price_window_days"`
+	SummaryDefaultMonths   int     `json:"summary_default_months"`
+	ServerWriteTimeout     string  `json:"server_write_timeout"`
+	AnalyticsTimeout       string  `json:"analytics_timeout"`
+	SlowQueryThreshold     string  `json:"slow_query_threshold"`
+	EventDispatchInterval  string  `json:"event_dispatch_interval"`
+
+	RejectPlusAddressDuplicates bool `json:"reject_plus_address_duplicates"`
+	BcryptCost                  int  `json:"bcrypt_cost"`
+
+	SMTPHost     string `json:"smtp_host"`
+	SMTPPort     string `json:"smtp_port"`
+	SMTPUsername string `json:"smtp_username"`
+	SMTPPassword string `json:"smtp_password"`
+	SMTPFrom     string `json:"smtp_from"`
+}
+
+// Redacted returns c with its secrets (DB and SMTP passwords, JWT secret and
+// keys, pipeline keys) redacted. JWT keys are listed by kid only.
+func (c *Config) Redacted() RedactedConfig {
+	secret := func(s string) string {
+		if s == "" {
+			return ""
+		}
+		return redacted
+	}
+	keyIDs := make([]string, len(c.JWTKeys))
+	for i, k := range c.JWTKeys {
+		keyIDs[i] = k.ID
+	}
+
+	return RedactedConfig{
+		Env:        c.Env,
+		LogLevel:   c.LogLevel,
+		LogFormat:  c.LogFormat,
+		Port:       c.Port,
+		CORSOrigin: c.CORSOrigin,
+
+		DBHost:     c.DBHost,
+		DBPort:     c.DBPort,
+		DBUser:     c.DBUser,
+		DBPassword: secret(c.DBPassword),
+		DBName:     c.DBName,
+		DBSSLMode:  c.DBSSLMode,
+
+		JWTSecret:           secret(c.JWTSecret),
+		JWTKeyIDs:           keyIDs,
+		JWTExpiresIn:        c.JWTExpirationDur.String(),
+		JWTRefreshExpiresIn: c.JWTRefreshExpirationDur.String(),
+		JWTIssuer:           c.JWTIssuer,
+		JWTAudience:         c.JWTAudience,
+
+		PipelineAPIKey:   secret(c.PipelineAPIKey),
+		PipelineAdminKey: secret(c.PipelineAdminKey),
+
+		PriceCacheTTL:          c.PriceCacheTTL.String(),
+		ExchangeRateCacheTTL:   c.ExchangeRateCacheTTL.String(),
+		PriceMaxDeviation:      c.PriceMaxDeviation,
+		SecurityBatchMaxSize:   c.SecurityBatchMaxSize,
+		TradePriceMaxDeviation: c.TradePriceMaxDeviation,
+		TradePriceWindowDays:   c.TradePriceWindowDays,
+		SummaryDefaultMonths:   c.SummaryDefaultMonths,
+		ServerWriteTimeout:     c.ServerWriteTimeout.String(),
+		AnalyticsTimeout:       c.AnalyticsTimeout.String(),
+		SlowQueryThreshold:     c.SlowQueryThreshold.String(),
+		EventDispatchInterval:  c.EventDispatchInterval.String(),
+
+		RejectPlusAddressDuplicates: c.RejectPlusAddressDuplicates,
+		BcryptCost:                  c.BcryptCost,
+
+		SMTPHost:     c.SMTPHost,
+		SMTPPort:     c.SMTPPort,
+		SMTPUsername: c.SMTPUsername,
+		SMTPPassword: secret(c.SMTPPassword),
+		SMTPFrom:     c.SMTPFrom,
+	}
+}
+
 // Get returns the application configuration
 func Get() *Config {
 	if appConfig == nil {
@@ -114,17 +451,147 @@ func Get() *Config {
 	return appConfig
 }
 
+// Set replaces the application configuration, for tests and tools that build a
+// Config directly instead of loading it from the environment.
+func Set(c *Config) {
+	appConfig = c
+}
+
+// LoggerOptions returns the logger settings for this configuration.
+func (c *Config) LoggerOptions() logger.Options {
+	return logger.Options{Env: string(c.Env), Level: c.LogLevel, Format: c.LogFormat}
+}
+
+// SigningKey returns the key new tokens are signed with at t: the last listed
+// key that has not retired.
+func (c *Config) SigningKey(t time.Time) (JWTKey, error) {
+	for i := len(c.JWTKeys) - 1; i >= 0; i-- {
+		if c.JWTKeys[i].ActiveAt(t) {
+			return c.JWTKeys[i], nil
+		}
+	}
+	return JWTKey{}, errors.New("no active JWT signing key")
+}
+
+// VerificationKey returns the key identified by kid if it is still accepted at
+// t. An empty kid selects DefaultJWTKeyID, for tokens issued before kids were used.
+func (c *Config) VerificationKey(kid string, t time.Time) (JWTKey, error) {
+	if kid == "" {
+		kid = DefaultJWTKeyID
+	}
+	for _, k := range c.JWTKeys {
+		if k.ID == kid {
+			if !k.ActiveAt(t) {
+				return JWTKey{}, fmt.Errorf("JWT key %q has retired", kid)
+			}
+			return k, nil
+		}
+	}
+	return JWTKey{}, fmt.Errorf("unknown JWT key %q", kid)
+}
+
+// loadJWTKeys parses the signing keys from JWT_KEYS (a JSON array) or, failing
+// that, the JSON file named by JWT_KEYS_FILE:
+//
+//	[{"kid":"2024-01","secret":"...","retires_at":"2024-08-01T00:00:00Z"},{"kid":"2024-07","secret":"..."}]
+//
+// With neither set, secret becomes the only key under DefaultJWTKeyID.
+func loadJWTKeys(keysJSON, keysFile, secret string) ([]JWTKey, error) {
+	if keysJSON == "" && keysFile != "" {
+		data, err := os.ReadFile(keysFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT_KEYS_FILE: %w", err)
+		}
+		keysJSON = string(data)
+	}
+	if keysJSON == "" {
+		return []JWTKey{{ID: DefaultJWTKeyID, Secret: secret}}, nil
+	}
+
+	var keys []JWTKey
+	if err := json.Unmarshal([]byte(keysJSON), &keys); err != nil {
+		return nil, fmt.Errorf("invalid JWT keys: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("invalid JWT keys: at least one key is required")
+	}
+	seen := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		if k.ID == "" || k.Secret == "" {
+			return nil, errors.New("invalid JWT keys: every key needs a kid and a secret")
+		}
+		if seen[k.ID] {
+			return nil, fmt.Errorf("invalid JWT keys: duplicate kid %q", k.ID)
+		}
+		seen[k.ID] = true
+	}
+	return keys, nil
+}
+
+// checkJWTKeySources rejects setting both JWT_KEYS (or JWT_KEYS_FILE) and
+// JWT_SIGNING_KEY (or JWT_PREVIOUS_KEYS): only one style can supply the keys,
+// and ignoring the other would leave tokens signed or verified with keys the
+// operator did not expect.
+func checkJWTKeySources(keysJSON, keysFile, signingKey, previousKeys string) error {
+	if (keysJSON != "" || keysFile != "") && (signingKey != "" || previousKeys != "") {
+		return errors.New("JWT_KEYS or JWT_KEYS_FILE cannot be combined with JWT_SIGNING_KEY or JWT_PREVIOUS_KEYS; set one style of keys")
+	}
+	if previousKeys != "" && signingKey == "" {
+		return errors.New("JWT_PREVIOUS_KEYS requires JWT_SIGNING_KEY")
+	}
+	return nil
+}
+
+// jwtKeysFromSecrets builds the keys for JWT_SIGNING_KEY and JWT_PREVIOUS_KEYS, a
+// comma-separated list of secrets that still verify tokens but no longer sign.
+// Each key's kid is derived from its secret, so a token signed while a secret
+// was the signing key still finds it once the secret has moved to the previous
+// list. legacySecret, the explicitly set JWT_SECRET if any, stays accepted under
+// DefaultJWTKeyID for tokens issued before the switch.
+func jwtKeysFromSecrets(signingKey, previousKeys, legacySecret string) []JWTKey {
+	var keys []JWTKey
+	seen := map[string]bool{signingKey: true}
+	if legacySecret != "" && legacySecret != signingKey {
+		keys = append(keys, JWTKey{ID: DefaultJWTKeyID, Secret: legacySecret})
+	}
+	for _, secret := range strings.Split(previousKeys, ",") {
+		secret = strings.TrimSpace(secret)
+		if secret == "" || seen[secret] {
+			continue
+		}
+		seen[secret] = true
+		keys = append(keys, JWTKey{ID: jwtKeyID(secret), Secret: secret})
+	}
+	// The signing key is listed last, so SigningKey picks it
+	return append(keys, JWTKey{ID: jwtKeyID(signingKey), Secret: signingKey})
+}
+
+// jwtKeyID derives a kid from a secret: a prefix of its SHA-256. Tokens already
+// allow guessing the secret offline, so the hash discloses nothing new.
+func jwtKeyID(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return "sha256-" + hex.EncodeToString(sum[:8])
+}
+
 // validateProduction checks that production-unsafe defaults are not used.
 func (c *Config) validateProduction() error {
 	unsafeSecrets := []string{"", "fallback-secret-key-for-dev-only", "your-super-secret-key-change-in-production"}
-	for _, s := range unsafeSecrets {
-		if c.JWTSecret == s {
-			return fmt.Errorf("JWT_SECRET must be explicitly set in production")
+	for _, k := range c.JWTKeys {
+		for _, s := range unsafeSecrets {
+			if k.Secret == s {
+				return fmt.Errorf("JWT_SECRET (or every JWT_KEYS or JWT_SIGNING_KEY secret) must be explicitly set in production")
+			}
 		}
 	}
+	if _, err := c.SigningKey(time.Now()); err != nil {
+		return fmt.Errorf("JWT_KEYS must include a key that has not retired")
+	}
 	if c.DBPassword == "kuberan" {
 		return fmt.Errorf("DB_PASSWORD must not be the default in production")
 	}
+	if c.BcryptCost < MinBcryptCost || c.BcryptCost > MaxBcryptCost {
+		return fmt.Errorf("BCRYPT_COST must be between %d and %d in production", MinBcryptCost, MaxBcryptCost)
+	}
 	return nil
 }
 
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadJWTKeys(t *testing.T) {
+	t.Run("defaults_to_secret", func(t *testing.T) {
+		keys, err := loadJWTKeys("", "", "s3cret")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(keys) != 1 || keys[0].ID != DefaultJWTKeyID || keys[0].Secret != "s3cret" {
+			t.Errorf("unexpected keys: %+v", keys)
+		}
+	})
+
+	t.Run("parses_json", func(t *testing.T) {
+		keys, err := loadJWTKeys(`[{"kid":"a","secret":"one","retires_at":"2024-08-01T00:00:00Z"},{"kid":"b","secret":"two"}]`, "", "ignored")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(keys) != 2 || keys[0].RetiresAt == nil || keys[1].RetiresAt != nil {
+			t.Fatalf("unexpected keys: %+v", keys)
+		}
+		if !keys[0].RetiresAt.Equal(time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("unexpected retires_at: %v", keys[0].RetiresAt)
+		}
+	})
+
+	t.Run("reads_file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keys.json")
+		if err := os.WriteFile(path, []byte(`[{"kid":"f","secret":"file-secret"}]`), 0o600); err != nil {
+			t.Fatalf("failed to write keys file: %v", err)
+		}
+		keys, err := loadJWTKeys("", path, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(keys) != 1 || keys[0].ID != "f" {
+			t.Errorf("unexpected keys: %+v", keys)
+		}
+	})
+
+	for _, tt := range []struct{ name, json string }{
+		{"malformed", `{"kid":"a"}`},
+		{"empty", `[]`},
+		{"missing_secret", `[{"kid":"a"}]`},
+		{"missing_kid", `[{"secret":"x"}]`},
+		{"duplicate_kid", `[{"kid":"a","secret":"x"},{"kid":"a","secret":"y"}]`},
+	} {
+		t.Run("rejects_"+tt.name, func(t *testing.T) {
+			if _, err := loadJWTKeys(tt.json, "", ""); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestJWTKeysFromSecrets(t *testing.T) {
+	t.Run("signs_with_signing_key", func(t *testing.T) {
+		c := &Config{JWTKeys: jwtKeysFromSecrets("new", " old , older,,new", "")}
+		if len(c.JWTKeys) != 3 {
+			t.Fatalf("expected signing key and two previous keys, got %+v", c.JWTKeys)
+		}
+		key, err := c.SigningKey(time.Now())
+		if err != nil || key.Secret != "new" {
+			t.Errorf("expected the signing key to sign, got %q (%v)", key.Secret, err)
+		}
+		for _, secret := range []string{"old", "older"} {
+			if _, err := c.VerificationKey(jwtKeyID(secret), time.Now()); err != nil {
+				t.Errorf("expected previous key %q to verify, got %v", secret, err)
+			}
+		}
+	})
+
+	t.Run("kid_follows_the_secret", func(t *testing.T) {
+		before := jwtKeysFromSecrets("one", "", "")
+		after := jwtKeysFromSecrets("two", "one", "")
+		if before[0].ID != after[0].ID || before[0].ID == after[1].ID {
+			t.Errorf("expected a secret to keep its kid across rotations, got %+v and %+v", before, after)
+		}
+	})
+
+	t.Run("keeps_legacy_secret_as_default", func(t *testing.T) {
+		c := &Config{JWTKeys: jwtKeysFromSecrets("new", "", "legacy")}
+		key, err := c.VerificationKey("", time.Now())
+		if err != nil || key.Secret != "legacy" {
+			t.Errorf("expected kid-less tokens checked against JWT_SECRET, got %q (%v)", key.Secret, err)
+		}
+	})
+}
+
+func TestCheckJWTKeySources(t *testing.T) {
+	for _, tt := range []struct {
+		name                                         string
+		keysJSON, keysFile, signingKey, previousKeys string
+		wantErr                                      bool
+	}{
+		{name: "none"},
+		{name: "json_keys", keysJSON: `[{"kid":"a","secret":"x"}]`},
+		{name: "keys_file", keysFile: "keys.json"},
+		{name: "signing_key", signingKey: "new", previousKeys: "old"},
+		{name: "json_keys_and_signing_key", keysJSON: `[{"kid":"a","secret":"x"}]`, signingKey: "new", wantErr: true},
+		{name: "keys_file_and_previous_keys", keysFile: "keys.json", signingKey: "new", previousKeys: "old", wantErr: true},
+		{name: "previous_keys_without_signing_key", previousKeys: "old", wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkJWTKeySources(tt.keysJSON, tt.keysFile, tt.signingKey, tt.previousKeys)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestJWTKeySelection(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	c := &Config{JWTKeys: []JWTKey{
+		{ID: "retired", Secret: "a", RetiresAt: &past},
+		{ID: "retiring", Secret: "b", RetiresAt: &future},
+		{ID: "current", Secret: "c"},
+	}}
+
+	key, err := c.SigningKey(now)
+	if err != nil || key.ID != "current" {
+		t.Errorf("expected signing key current, got %q (%v)", key.ID, err)
+	}
+	if _, err := c.VerificationKey("retiring", now); err != nil {
+		t.Errorf("expected retiring key to verify, got %v", err)
+	}
+	if _, err := c.VerificationKey("retired", now); err == nil {
+		t.Error("expected retired key to be rejected")
+	}
+	if _, err := c.VerificationKey("unknown", now); err == nil {
+		t.Error("expected unknown key to be rejected")
+	}
+	if _, err := c.VerificationKey("", now); err == nil {
+		t.Error("expected kid-less token to be rejected without a default key")
+	}
+
+	onlyRetired := &Config{JWTKeys: []JWTKey{{ID: "retired", Secret: "a", RetiresAt: &past}}}
+	if _, err := onlyRetired.SigningKey(now); err == nil {
+		t.Error("expected an error when every key has retired")
+	}
+}
+
+func TestValidateProductionBcryptCost(t *testing.T) {
+	for _, tt := range []struct {
+		cost    int
+		wantErr bool
+	}{
+		{cost: 4, wantErr: true},
+		{cost: 9, wantErr: true},
+		{cost: 10},
+		{cost: 12},
+		{cost: 15},
+		{cost: 16, wantErr: true},
+	} {
+		c := &Config{JWTKeys: jwtKeysFromSecrets("production-secret", "", ""), DBPassword: "strong", BcryptCost: tt.cost}
+		if err := c.validateProduction(); (err != nil) != tt.wantErr {
+			t.Errorf("cost %d: expected error %v, got %v", tt.cost, tt.wantErr, err)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"fmt"
+	"time"
 
 	"kuberan/internal/config"
 )
@@ -14,6 +15,12 @@ type Config struct {
 	Password string
 	DBName   string
 	SSLMode  string
+
+	// MigrationsDir is the directory holding SQL migrations (default "migrations").
+	MigrationsDir string
+
+	// SlowQueryThreshold is how long a query may take before it is logged; 0 disables it.
+	SlowQueryThreshold time.Duration
 }
 
 // NewConfig creates a new database configuration
@@ -28,6 +35,8 @@ func NewConfig() (*Config, error) {
 		Password: appConfig.DBPassword,
 		DBName:   appConfig.DBName,
 		SSLMode:  appConfig.DBSSLMode,
+
+		SlowQueryThreshold: appConfig.SlowQueryThreshold,
 	}, nil
 }
 
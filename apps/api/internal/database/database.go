@@ -13,10 +13,21 @@ import (
 	"gorm.io/gorm"
 )
 
+// PoolSettings sizes the database connection pool.
+type PoolSettings struct {
+	MaxIdleConns    int
+	MaxOpenConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// Pool is the connection pool every Manager opens its database with.
+var Pool = PoolSettings{MaxIdleConns: 10, MaxOpenConns: 100, ConnMaxLifetime: time.Hour}
+
 // Manager handles database operations
 type Manager struct {
-	db  *gorm.DB
-	dsn string
+	db            *gorm.DB
+	dsn           string
+	migrationsDir string
 }
 
 // NewManager creates a new database manager
@@ -24,7 +35,7 @@ func NewManager(config *Config) (*Manager, error) {
 	db, err := gorm.Open(postgres.New(postgres.Config{
 		DSN:                  config.DSN(),
 		PreferSimpleProtocol: true, // Required for Supabase Supavisor; harmless for direct connections
-	}), &gorm.Config{})
+	}), &gorm.Config{Logger: NewSlowQueryLogger(config.SlowQueryThreshold)})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -33,36 +44,35 @@ func NewManager(config *Config) (*Manager, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get underlying DB: %w", err)
 	}
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
-	sqlDB.SetConnMaxLifetime(time.Hour)
+	sqlDB.SetMaxIdleConns(Pool.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(Pool.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(Pool.ConnMaxLifetime)
 
 	pgURL := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
 		config.User, config.Password, config.Host, config.Port, config.DBName, config.SSLMode)
 
-	return &Manager{db: db, dsn: pgURL}, nil
+	migrationsDir := config.MigrationsDir
+	if migrationsDir == "" {
+		migrationsDir = "migrations"
+	}
+
+	return &Manager{db: db, dsn: pgURL, migrationsDir: migrationsDir}, nil
 }
 
-// RunMigrations applies pending SQL migrations from the migrations/ directory.
+// RunMigrations applies pending SQL migrations from the migrations directory.
+// It holds a Postgres advisory lock while migrating and returns ErrMigrationLocked
+// immediately if another process is already migrating.
 func (m *Manager) RunMigrations() error {
 	logger.Get().Info("Running database migrations...")
 
-	mig, err := migrate.New("file://migrations", m.dsn)
-	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
-	}
-	defer func() {
-		srcErr, dbErr := mig.Close()
-		if srcErr != nil {
-			logger.Get().Warnf("migrate source close error: %v", srcErr)
-		}
-		if dbErr != nil {
-			logger.Get().Warnf("migrate database close error: %v", dbErr)
+	err := m.withMigrator(func(mig *migrate.Migrate) error {
+		if err := mig.Up(); err != nil && err != migrate.ErrNoChange {
+			return fmt.Errorf("migration failed: %w", err)
 		}
-	}()
-
-	if err := mig.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("migration failed: %w", err)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	logger.Get().Info("Database migrations completed successfully")
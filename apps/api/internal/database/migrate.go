@@ -0,0 +1,268 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"kuberan/internal/logger"
+
+	"github.com/golang-migrate/migrate/v4"
+	"gorm.io/gorm"
+)
+
+// MigrationLockKey is the Postgres advisory lock key held while migrating.
+// Any process that runs migrations against the same database shares it.
+const MigrationLockKey int64 = 0x6b75626572616e // "kuberan"
+
+// ErrMigrationLocked is returned when another process holds the migration lock.
+var ErrMigrationLocked = errors.New("another process is running migrations")
+
+// withMigrationLock runs fn while holding the migration advisory lock. The lock
+// is session-scoped, so it is taken on a dedicated connection that stays open
+// until fn returns. Returns ErrMigrationLocked without waiting if the lock is held.
+func withMigrationLock(db *gorm.DB, fn func() error) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get underlying DB: %w", err)
+	}
+
+	ctx := context.Background()
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection for migration lock: %w", err)
+	}
+	defer conn.Close()
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", MigrationLockKey).Scan(&acquired); err != nil {
+		return fmt.Errorf("failed to take migration lock: %w", err)
+	}
+	if !acquired {
+		return ErrMigrationLocked
+	}
+	defer func() {
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", MigrationLockKey); err != nil {
+			logger.Get().Warnf("failed to release migration lock: %v", err)
+		}
+	}()
+
+	return fn()
+}
+
+// withMigrator runs fn against a migrate instance while holding the migration lock.
+func (m *Manager) withMigrator(fn func(mig *migrate.Migrate) error) error {
+	return withMigrationLock(m.db, func() error {
+		mig, err := migrate.New("file://"+m.migrationsDir, m.dsn)
+		if err != nil {
+			return fmt.Errorf("failed to create migrate instance: %w", err)
+		}
+		defer func() {
+			srcErr, dbErr := mig.Close()
+			if srcErr != nil {
+				logger.Get().Warnf("migrate source close error: %v", srcErr)
+			}
+			if dbErr != nil {
+				logger.Get().Warnf("migrate database close error: %v", dbErr)
+			}
+		}()
+		return fn(mig)
+	})
+}
+
+// MigrateDown rolls back the given number of migrations.
+func (m *Manager) MigrateDown(steps int) error {
+	return m.withMigrator(func(mig *migrate.Migrate) error {
+		if err := mig.Steps(-steps); err != nil && err != migrate.ErrNoChange {
+			return fmt.Errorf("migration down failed: %w", err)
+		}
+		return nil
+	})
+}
+
+// MigrationVersion returns the current schema version and whether it is dirty.
+func (m *Manager) MigrationVersion() (version uint, dirty bool, err error) {
+	err = m.withMigrator(func(mig *migrate.Migrate) error {
+		version, dirty, err = mig.Version()
+		return err
+	})
+	return version, dirty, err
+}
+
+// ForceVersion sets the recorded schema version and clears the dirty flag
+// without running any migration. Use -1 to record that no migration is applied.
+func (m *Manager) ForceVersion(version int) error {
+	return m.withMigrator(func(mig *migrate.Migrate) error {
+		if err := mig.Force(version); err != nil {
+			return fmt.Errorf("force version %d failed: %w", version, err)
+		}
+		return nil
+	})
+}
+
+// RepairResult describes what RepairDirty decided.
+type RepairResult struct {
+	DirtyVersion uint
+	Version      int  // version recorded after repair; -1 means none applied
+	Applied      bool // true if the dirty migration was found fully applied
+}
+
+// RepairDirty clears a dirty schema_migrations state after checking which
+// objects from the dirty migration exist. If every table, column, and index it
+// creates is present the version is kept; if none are present the version is
+// rolled back to the previous migration. A partial state is left untouched and
+// reported so it can be fixed by hand and then resolved with ForceVersion.
+func (m *Manager) RepairDirty() (*RepairResult, error) {
+	var result *RepairResult
+	err := m.withMigrator(func(mig *migrate.Migrate) error {
+		version, dirty, err := mig.Version()
+		if err != nil {
+			return fmt.Errorf("failed to get version: %w", err)
+		}
+		if !dirty {
+			return fmt.Errorf("schema version %d is not dirty; nothing to repair", version)
+		}
+
+		upSQL, err := m.readUpMigration(version)
+		if err != nil {
+			return err
+		}
+		objects := parseSchemaObjects(upSQL)
+		if len(objects) == 0 {
+			return fmt.Errorf("migration %d creates no verifiable objects; inspect it manually and use force", version)
+		}
+
+		var present, missing []string
+		for _, obj := range objects {
+			ok, err := m.schemaObjectExists(obj)
+			if err != nil {
+				return err
+			}
+			if ok {
+				present = append(present, obj.String())
+			} else {
+				missing = append(missing, obj.String())
+			}
+		}
+
+		result = &RepairResult{DirtyVersion: version}
+		switch {
+		case len(missing) == 0:
+			result.Version = int(version)
+			result.Applied = true
+		case len(present) == 0:
+			prev, err := m.previousMigrationVersion(version)
+			if err != nil {
+				return err
+			}
+			result.Version = prev
+		default:
+			return fmt.Errorf("migration %d is partially applied (present: %s; missing: %s); fix the schema manually and use force",
+				version, strings.Join(present, ", "), strings.Join(missing, ", "))
+		}
+
+		if err := mig.Force(result.Version); err != nil {
+			return fmt.Errorf("force version %d failed: %w", result.Version, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// readUpMigration returns the contents of the up migration for version.
+func (m *Manager) readUpMigration(version uint) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(m.migrationsDir, fmt.Sprintf("%06d_*.up.sql", version)))
+	if err != nil || len(matches) == 0 {
+		return "", fmt.Errorf("up migration for version %d not found in %s", version, m.migrationsDir)
+	}
+	data, err := os.ReadFile(matches[0])
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", matches[0], err)
+	}
+	return string(data), nil
+}
+
+// previousMigrationVersion returns the version before version, or -1 if it is the first.
+func (m *Manager) previousMigrationVersion(version uint) (int, error) {
+	matches, err := filepath.Glob(filepath.Join(m.migrationsDir, "*.up.sql"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list migrations: %w", err)
+	}
+	prev := -1
+	for _, path := range matches {
+		var v int
+		if _, err := fmt.Sscanf(filepath.Base(path), "%06d_", &v); err != nil {
+			continue
+		}
+		if v < int(version) && v > prev {
+			prev = v
+		}
+	}
+	return prev, nil
+}
+
+// schemaObject is a table, column, or index that a migration creates.
+type schemaObject struct {
+	Kind  string // "table", "column", or "index"
+	Table string
+	Name  string // column or index name; empty for tables
+}
+
+func (o schemaObject) String() string {
+	switch o.Kind {
+	case "column":
+		return fmt.Sprintf("column %s.%s", o.Table, o.Name)
+	case "index":
+		return fmt.Sprintf("index %s", o.Name)
+	}
+	return fmt.Sprintf("table %s", o.Table)
+}
+
+var (
+	createTableRe = regexp.MustCompile(`(?i)CREATE\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?"?(\w+)"?`)
+	addColumnRe   = regexp.MustCompile(`(?i)ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?"?(\w+)"?\s+ADD\s+COLUMN\s+(?:IF\s+NOT\s+EXISTS\s+)?"?(\w+)"?`)
+	createIndexRe = regexp.MustCompile(`(?i)CREATE\s+(?:UNIQUE\s+)?INDEX\s+(?:CONCURRENTLY\s+)?(?:IF\s+NOT\s+EXISTS\s+)?"?(\w+)"?\s+ON\s+"?(\w+)"?`)
+)
+
+// parseSchemaObjects extracts the tables, columns, and indexes created by a migration.
+func parseSchemaObjects(sql string) []schemaObject {
+	var objects []schemaObject
+	for _, match := range createTableRe.FindAllStringSubmatch(sql, -1) {
+		objects = append(objects, schemaObject{Kind: "table", Table: strings.ToLower(match[1])})
+	}
+	for _, match := range addColumnRe.FindAllStringSubmatch(sql, -1) {
+		objects = append(objects, schemaObject{Kind: "column", Table: strings.ToLower(match[1]), Name: strings.ToLower(match[2])})
+	}
+	for _, match := range createIndexRe.FindAllStringSubmatch(sql, -1) {
+		objects = append(objects, schemaObject{Kind: "index", Table: strings.ToLower(match[2]), Name: strings.ToLower(match[1])})
+	}
+	return objects
+}
+
+// schemaObjectExists checks the catalog for obj in the current schema.
+func (m *Manager) schemaObjectExists(obj schemaObject) (bool, error) {
+	var count int64
+	var err error
+	switch obj.Kind {
+	case "table":
+		err = m.db.Raw("SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = ?",
+			obj.Table).Scan(&count).Error
+	case "column":
+		err = m.db.Raw("SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?",
+			obj.Table, obj.Name).Scan(&count).Error
+	case "index":
+		err = m.db.Raw("SELECT COUNT(*) FROM pg_indexes WHERE schemaname = current_schema() AND indexname = ?",
+			obj.Name).Scan(&count).Error
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check %s: %w", obj, err)
+	}
+	return count > 0, nil
+}
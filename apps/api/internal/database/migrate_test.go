@@ -0,0 +1,59 @@
+package database
+
+import "testing"
+
+func TestParseSchemaObjects(t *testing.T) {
+	sql := `
+CREATE TABLE IF NOT EXISTS pending_email_changes (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS idx_pending_email_changes_user_id ON pending_email_changes (user_id);
+CREATE UNIQUE INDEX idx_pending_email_changes_token_hash ON pending_email_changes (token_hash);
+ALTER TABLE users ADD COLUMN display_name VARCHAR(100) DEFAULT '';
+alter table users add column if not exists locale VARCHAR(10);
+`
+	got := parseSchemaObjects(sql)
+	want := []schemaObject{
+		{Kind: "table", Table: "pending_email_changes"},
+		{Kind: "column", Table: "users", Name: "display_name"},
+		{Kind: "column", Table: "users", Name: "locale"},
+		{Kind: "index", Table: "pending_email_changes", Name: "idx_pending_email_changes_user_id"},
+		{Kind: "index", Table: "pending_email_changes", Name: "idx_pending_email_changes_token_hash"},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %d objects, got %d: %+v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("object %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestParseSchemaObjects_NoObjects(t *testing.T) {
+	if got := parseSchemaObjects("UPDATE users SET locale = 'en-US';"); len(got) != 0 {
+		t.Errorf("expected no objects, got %+v", got)
+	}
+}
+
+func TestPreviousMigrationVersion(t *testing.T) {
+	m := &Manager{migrationsDir: "../../migrations"}
+
+	prev, err := m.previousMigrationVersion(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prev != 1 {
+		t.Errorf("expected previous version 1, got %d", prev)
+	}
+
+	prev, err = m.previousMigrationVersion(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prev != -1 {
+		t.Errorf("expected -1 before the first migration, got %d", prev)
+	}
+}
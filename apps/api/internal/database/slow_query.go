@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	gormlogger "gorm.io/gorm/logger"
+
+	"kuberan/internal/logger"
+)
+
+// slowQueryLogger is a GORM logger that reports queries taking at least
+// threshold through Zap, naming the route and user of the request when the
+// query runs with its context. Everything else goes to the wrapped logger.
+type slowQueryLogger struct {
+	gormlogger.Interface
+	threshold time.Duration
+	warn      func(msg string, keysAndValues ...interface{})
+}
+
+// NewSlowQueryLogger returns GORM's default logger with its own slow-query
+// reporting replaced by structured logs of queries at least threshold slow.
+// A threshold of 0 disables slow-query logging.
+func NewSlowQueryLogger(threshold time.Duration) gormlogger.Interface {
+	base := gormlogger.New(log.New(os.Stdout, "\r\n", log.LstdFlags), gormlogger.Config{
+		LogLevel: gormlogger.Warn,
+		Colorful: true,
+	})
+	return &slowQueryLogger{Interface: base, threshold: threshold, warn: logger.Get().Warnw}
+}
+
+// LogMode keeps slow-query reporting when GORM changes the log level.
+func (l *slowQueryLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *l
+	clone.Interface = l.Interface.LogMode(level)
+	return &clone
+}
+
+// Trace logs the query when it was slow, then hands it to the wrapped logger.
+func (l *slowQueryLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	l.Interface.Trace(ctx, begin, fc, err)
+
+	elapsed := time.Since(begin)
+	if l.threshold <= 0 || elapsed < l.threshold {
+		return
+	}
+	sql, rows := fc()
+	fields := []interface{}{"elapsed_ms", elapsed.Milliseconds(), "rows", rows, "sql", sql}
+	if req := logger.RequestFrom(ctx); req != nil {
+		fields = append(fields, "request_id", req.ID, "route", req.Route, "user_id", req.UserID)
+	}
+	l.warn("slow query", fields...)
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"kuberan/internal/logger"
+)
+
+func TestSlowQueryLogger(t *testing.T) {
+	var logged []string
+	l := NewSlowQueryLogger(100 * time.Millisecond).(*slowQueryLogger)
+	l.warn = func(msg string, keysAndValues ...interface{}) {
+		logged = append(logged, fmt.Sprint(append([]interface{}{msg}, keysAndValues...)...))
+	}
+	query := func() (string, int64) { return "SELECT 1", 1 }
+
+	l.Trace(context.Background(), time.Now(), query, nil)
+	if len(logged) != 0 {
+		t.Fatalf("expected a fast query not to be logged, got %v", logged)
+	}
+
+	ctx := logger.WithRequest(context.Background(), &logger.Request{ID: "req-1", Route: "/api/v1/budgets", UserID: "user-1"})
+	l.Trace(ctx, time.Now().Add(-time.Second), query, nil)
+	if len(logged) != 1 {
+		t.Fatalf("expected the slow query to be logged once, got %v", logged)
+	}
+	for _, want := range []string{"slow query", "SELECT 1", "/api/v1/budgets", "user-1", "req-1"} {
+		if !strings.Contains(logged[0], want) {
+			t.Errorf("expected %q in %q", want, logged[0])
+		}
+	}
+
+	// LogMode keeps the threshold; 0 disables logging
+	l.LogMode(0).Trace(ctx, time.Now().Add(-time.Second), query, nil)
+	disabled := NewSlowQueryLogger(0).(*slowQueryLogger)
+	disabled.warn = l.warn
+	disabled.Trace(ctx, time.Now().Add(-time.Hour), query, nil)
+	if len(logged) != 2 {
+		t.Errorf("expected only the LogMode copy to log, got %d entries", len(logged))
+	}
+}
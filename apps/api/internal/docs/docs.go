@@ -16,14 +16,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/accounts": {
+        "/account-groups": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a paginated list of accounts for the authenticated user",
+                "description": "Get the user's account groups in display order",
                 "consumes": [
                     "application/json"
                 ],
@@ -31,28 +31,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "accounts"
-                ],
-                "summary": "Get user accounts",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Page number (default 1)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Items per page (default 20, max 100)",
-                        "name": "page_size",
-                        "in": "query"
-                    }
+                    "account-groups"
                 ],
+                "summary": "Get account groups",
                 "responses": {
                     "200": {
-                        "description": "Paginated accounts",
+                        "description": "Account groups",
                         "schema": {
-                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_models_Account"
+                            "$ref": "#/definitions/internal_handlers.AccountGroupsResponse"
                         }
                     },
                     "401": {
@@ -68,16 +54,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/accounts/cash": {
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new cash account for the authenticated user",
+                "description": "Create a group for organizing accounts; it is ordered after existing groups",
                 "consumes": [
                     "application/json"
                 ],
@@ -85,25 +69,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "accounts"
+                    "account-groups"
                 ],
-                "summary": "Create a cash account",
+                "summary": "Create an account group",
                 "parameters": [
                     {
-                        "description": "Cash account details",
+                        "description": "Group details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.CreateCashAccountRequest"
+                            "$ref": "#/definitions/internal_handlers.AccountGroupRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Account created",
+                        "description": "Group created",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.AccountResponse"
+                            "$ref": "#/definitions/internal_handlers.AccountGroupResponse"
                         }
                     },
                     "400": {
@@ -127,14 +111,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/accounts/credit-card": {
-            "post": {
+        "/account-groups/order": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new credit card account for the authenticated user",
+                "description": "Set the display order of the user's groups; group_ids must list every group exactly once",
                 "consumes": [
                     "application/json"
                 ],
@@ -142,25 +126,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "accounts"
+                    "account-groups"
                 ],
-                "summary": "Create a credit card account",
+                "summary": "Reorder account groups",
                 "parameters": [
                     {
-                        "description": "Credit card account details",
+                        "description": "Group IDs in the new order",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.CreateCreditCardAccountRequest"
+                            "$ref": "#/definitions/internal_handlers.ReorderAccountGroupsRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Account created",
+                    "200": {
+                        "description": "Groups in the new order",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.AccountResponse"
+                            "$ref": "#/definitions/internal_handlers.AccountGroupsResponse"
                         }
                     },
                     "400": {
@@ -175,6 +159,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -184,14 +174,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/accounts/investment": {
-            "post": {
+        "/account-groups/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new investment account for the authenticated user",
+                "description": "Get a specific account group by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -199,29 +189,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "accounts"
+                    "account-groups"
                 ],
-                "summary": "Create an investment account",
+                "summary": "Get account group by ID",
                 "parameters": [
                     {
-                        "description": "Investment account details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handlers.CreateInvestmentAccountRequest"
-                        }
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Account created",
+                    "200": {
+                        "description": "Group details",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.AccountResponse"
+                            "$ref": "#/definitions/internal_handlers.AccountGroupResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid group ID",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -232,6 +220,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -239,16 +233,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/accounts/{id}": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a specific account by ID for the authenticated user",
+                "description": "Rename an account group",
                 "consumes": [
                     "application/json"
                 ],
@@ -256,27 +248,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "accounts"
+                    "account-groups"
                 ],
-                "summary": "Get account by ID",
+                "summary": "Update account group",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Account ID",
+                        "type": "string",
+                        "description": "Group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Updated group details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.AccountGroupRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Account details",
+                        "description": "Updated group",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.AccountResponse"
+                            "$ref": "#/definitions/internal_handlers.AccountGroupResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid account ID",
+                        "description": "Invalid input or group ID",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -288,7 +289,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Account not found",
+                        "description": "Group not found",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -301,13 +302,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing account for the authenticated user. Accepts common fields for all account types and type-specific fields.",
+                "description": "Delete an account group (soft delete); its accounts become ungrouped",
                 "consumes": [
                     "application/json"
                 ],
@@ -315,36 +316,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "accounts"
+                    "account-groups"
                 ],
-                "summary": "Update account",
+                "summary": "Delete account group",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Account ID",
+                        "type": "string",
+                        "description": "Group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated account details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handlers.UpdateAccountRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated account",
+                        "description": "Group deleted",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.AccountResponse"
+                            "$ref": "#/definitions/internal_handlers.MessageResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid input or account ID",
+                        "description": "Invalid group ID",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -356,7 +348,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Account not found",
+                        "description": "Group not found",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -370,14 +362,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/accounts/{id}/investments": {
+        "/accounts": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a paginated list of investments for an account",
+                "description": "Get a paginated list of accounts for the authenticated user",
                 "consumes": [
                     "application/json"
                 ],
@@ -385,16 +377,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "investments"
+                    "accounts"
                 ],
-                "summary": "Get account investments",
+                "summary": "Get user accounts",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Account ID",
-                        "name": "id",
-                        "in": "path"
-                    },
                     {
                         "type": "integer",
                         "description": "Page number (default 1)",
@@ -406,17 +392,35 @@ const docTemplate = `{
                         "description": "Items per page (default 20, max 100)",
                         "name": "page_size",
                         "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Compute total_items and total_pages (default true); when false they are -1 and 0",
+                        "name": "with_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include archived accounts, flagged by is_active false and archived_at (default false)",
+                        "name": "include_inactive",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return every account nested under its group instead of a page (default false)",
+                        "name": "grouped",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Paginated investments",
+                        "description": "Accounts by group, when grouped=true",
                         "schema": {
-                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_models_Investment"
+                            "$ref": "#/definitions/internal_handlers.GroupedAccountsResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid include_inactive or grouped",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -427,12 +431,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Account not found",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -442,14 +440,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/accounts/{id}/transactions": {
+        "/accounts/archived": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a paginated list of transactions for a specific account with optional filters",
+                "description": "Get a paginated list of the user's inactive accounts with their archival time and balance, most recently archived first",
                 "consumes": [
                     "application/json"
                 ],
@@ -457,18 +455,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "accounts",
-                    "transactions"
+                    "accounts"
                 ],
-                "summary": "Get account transactions",
+                "summary": "Get archived accounts",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Account ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "integer",
                         "description": "Page number (default 1)",
@@ -482,53 +472,17 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Filter by start date (RFC3339 e.g. 2024-01-01T00:00:00Z, or YYYY-MM-DD)",
-                        "name": "from_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by end date (RFC3339 or YYYY-MM-DD)",
-                        "name": "to_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by transaction type (income, expense, transfer, investment)",
-                        "name": "type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Filter by category ID",
-                        "name": "category_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Filter by minimum amount (cents)",
-                        "name": "min_amount",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Filter by maximum amount (cents)",
-                        "name": "max_amount",
+                        "type": "boolean",
+                        "description": "Compute total_items and total_pages (default true); when false they are -1 and 0",
+                        "name": "with_total",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Paginated transactions",
-                        "schema": {
-                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_models_Transaction"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid input",
+                        "description": "Paginated archived accounts",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_models_Account"
                         }
                     },
                     "401": {
@@ -537,12 +491,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Account not found",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -552,9 +500,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/login": {
+        "/accounts/cash": {
             "post": {
-                "description": "Authenticate a user and get access and refresh tokens",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new cash account for the authenticated user. With allow_negative set to false, expenses and outgoing transfers that would take the balance below min_balance are rejected with INSUFFICIENT_BALANCE.",
                 "consumes": [
                     "application/json"
                 ],
@@ -562,25 +515,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "accounts"
                 ],
-                "summary": "Login user",
+                "summary": "Create a cash account",
                 "parameters": [
                     {
-                        "description": "User login credentials",
+                        "description": "Cash account details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.LoginRequest"
+                            "$ref": "#/definitions/internal_handlers.CreateCashAccountRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "User authenticated and tokens generated",
+                    "201": {
+                        "description": "Account created",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.AuthResponse"
+                            "$ref": "#/definitions/internal_handlers.AccountResponse"
                         }
                     },
                     "400": {
@@ -590,13 +543,7 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Invalid credentials",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
-                        }
-                    },
-                    "423": {
-                        "description": "Account locked",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -610,9 +557,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/refresh": {
+        "/accounts/credit-card": {
             "post": {
-                "description": "Exchange a valid refresh token for new access and refresh tokens",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new credit card account for the authenticated user",
                 "consumes": [
                     "application/json"
                 ],
@@ -620,25 +572,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "accounts"
                 ],
-                "summary": "Refresh access token",
+                "summary": "Create a credit card account",
                 "parameters": [
                     {
-                        "description": "Refresh token",
+                        "description": "Credit card account details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.RefreshRequest"
+                            "$ref": "#/definitions/internal_handlers.CreateCreditCardAccountRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "New tokens generated",
+                    "201": {
+                        "description": "Account created",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.AuthResponse"
+                            "$ref": "#/definitions/internal_handlers.AccountResponse"
                         }
                     },
                     "400": {
@@ -648,7 +600,7 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Invalid or expired refresh token",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -662,9 +614,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/register": {
+        "/accounts/investment": {
             "post": {
-                "description": "Register a new user with email and password",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new investment account for the authenticated user",
                 "consumes": [
                     "application/json"
                 ],
@@ -672,25 +629,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "accounts"
                 ],
-                "summary": "Register a new user",
+                "summary": "Create an investment account",
                 "parameters": [
                     {
-                        "description": "User registration data",
+                        "description": "Investment account details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.RegisterRequest"
+                            "$ref": "#/definitions/internal_handlers.CreateInvestmentAccountRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "User registered and tokens generated",
+                        "description": "Account created",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.AuthResponse"
+                            "$ref": "#/definitions/internal_handlers.AccountResponse"
                         }
                     },
                     "400": {
@@ -699,6 +656,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -708,14 +671,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/budgets": {
+        "/accounts/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a paginated list of budgets for the authenticated user",
+                "description": "Get a specific account by ID for the authenticated user",
                 "consumes": [
                     "application/json"
                 ],
@@ -723,44 +686,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "budgets"
+                    "accounts"
                 ],
-                "summary": "Get budgets",
+                "summary": "Get account by ID",
                 "parameters": [
-                    {
-                        "type": "boolean",
-                        "description": "Filter by active status",
-                        "name": "is_active",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by period (monthly/yearly)",
-                        "name": "period",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number (default 1)",
-                        "name": "page",
-                        "in": "query"
-                    },
                     {
                         "type": "integer",
-                        "description": "Items per page (default 20, max 100)",
-                        "name": "page_size",
-                        "in": "query"
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Paginated budgets",
+                        "description": "Account details",
                         "schema": {
-                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_models_Budget"
+                            "$ref": "#/definitions/internal_handlers.AccountResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid account ID",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -771,6 +717,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Account not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -779,13 +731,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new budget for a category",
+                "description": "Update an existing account for the authenticated user. Accepts common fields for all account types and type-specific fields.",
                 "consumes": [
                     "application/json"
                 ],
@@ -793,29 +745,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "budgets"
+                    "accounts"
                 ],
-                "summary": "Create a budget",
+                "summary": "Update account",
                 "parameters": [
                     {
-                        "description": "Budget details",
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated account details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.CreateBudgetRequest"
+                            "$ref": "#/definitions/internal_handlers.UpdateAccountRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Budget created",
+                    "200": {
+                        "description": "Updated account",
                         "schema": {
-                            "$ref": "#/definitions/kuberan_internal_models.Budget"
+                            "$ref": "#/definitions/internal_handlers.AccountResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid input or account ID",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -827,7 +786,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Category not found",
+                        "description": "Account not found",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -839,44 +798,45 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/budgets/{id}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a specific budget by ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Delete an account the user owns (soft delete). An account with transactions, transfers into it, or investments is refused with ACCOUNT_HAS_TRANSACTIONS unless force is true; with force, its transactions, investments and investment transactions are deleted with it in one step, and settled transfers to or from other accounts are reversed on those accounts. Archiving keeps the history instead.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "budgets"
+                    "accounts"
                 ],
-                "summary": "Get budget by ID",
+                "summary": "Delete account",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Budget ID",
+                        "type": "string",
+                        "description": "Account ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also delete the account's transactions and investments",
+                        "name": "force",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Budget details",
+                        "description": "Account deleted",
                         "schema": {
-                            "$ref": "#/definitions/kuberan_internal_models.Budget"
+                            "$ref": "#/definitions/internal_handlers.MessageResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid budget ID",
+                        "description": "Invalid account ID or force",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -888,7 +848,13 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Budget not found",
+                        "description": "Account not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Account has transactions or investments",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -900,14 +866,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/accounts/{id}/investments": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing budget",
+                "description": "Get a paginated list of investments for an account",
                 "consumes": [
                     "application/json"
                 ],
@@ -915,36 +883,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "budgets"
+                    "investments"
                 ],
-                "summary": "Update budget",
+                "summary": "Get account investments",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Budget ID",
+                        "description": "Account ID",
                         "name": "id",
-                        "in": "path",
-                        "required": true
+                        "in": "path"
                     },
                     {
-                        "description": "Updated budget details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handlers.UpdateBudgetRequest"
-                        }
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Compute total_items and total_pages (default true); when false they are -1 and 0",
+                        "name": "with_total",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated budget",
+                        "description": "Paginated investments",
                         "schema": {
-                            "$ref": "#/definitions/kuberan_internal_models.Budget"
+                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_models_Investment"
                         }
                     },
                     "400": {
-                        "description": "Invalid input or budget ID",
+                        "description": "Invalid input",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -956,7 +932,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Budget not found",
+                        "description": "Account not found",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -968,42 +944,55 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/accounts/{id}/statement": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a budget by ID (soft delete)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Bank-style statement of an account for a calendar month (UTC): opening balance, settled transactions with running balance, closing balance and totals, rendered as PDF, HTML or CSV. Amounts are in major units of the account's currency; for credit cards the balance is the amount owed.",
                 "produces": [
-                    "application/json"
+                    "application/pdf",
+                    "text/html",
+                    "text/csv"
                 ],
                 "tags": [
-                    "budgets"
+                    "accounts"
                 ],
-                "summary": "Delete budget",
+                "summary": "Get account statement",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Budget ID",
+                        "type": "string",
+                        "description": "Account ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Month in YYYY-MM format (default: current month)",
+                        "name": "month",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pdf (default), html or csv",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Budget deleted",
+                        "description": "Rendered statement",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.MessageResponse"
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Invalid budget ID",
+                        "description": "Invalid month, format or account type",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -1015,7 +1004,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Budget not found",
+                        "description": "Account not found",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -1029,42 +1018,57 @@ const docTemplate = `{
                 }
             }
         },
-        "/budgets/{id}/progress": {
+        "/accounts/{id}/timeline": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get spending progress for a budget in the current period",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get the account's cash transactions and, for investment accounts, the buys, sells, dividends, and splits of its holdings as one feed, newest first. Each entry has a kind (transaction or investment_transaction) and the matching record.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "budgets"
+                    "accounts"
                 ],
-                "summary": "Get budget progress",
+                "summary": "Get account timeline",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Budget ID",
+                        "description": "Account ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Compute total_items and total_pages (default true); when false they are -1 and 0",
+                        "name": "with_total",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Budget progress",
+                        "description": "Paginated timeline entries",
                         "schema": {
-                            "$ref": "#/definitions/kuberan_internal_services.BudgetProgress"
+                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_services_AccountTimelineEntry"
                         }
                     },
                     "400": {
-                        "description": "Invalid budget ID",
+                        "description": "Invalid input",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -1076,7 +1080,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Budget not found",
+                        "description": "Account not found",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -1090,14 +1094,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/categories": {
+        "/accounts/{id}/transactions": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a paginated list of transaction categories for the authenticated user",
+                "description": "Get a paginated list of transactions for a specific account with optional filters",
                 "consumes": [
                     "application/json"
                 ],
@@ -1105,15 +1109,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "accounts",
+                    "transactions"
                 ],
-                "summary": "Get categories",
+                "summary": "Get account transactions",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Filter by category type (income/expense)",
-                        "name": "type",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "integer",
@@ -1126,13 +1132,61 @@ const docTemplate = `{
                         "description": "Items per page (default 20, max 100)",
                         "name": "page_size",
                         "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Compute total_items and total_pages (default true); when false they are -1 and 0",
+                        "name": "with_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by start date (RFC3339 e.g. 2024-01-01T00:00:00Z, YYYY-MM-DD or unix seconds)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by end date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)",
+                        "name": "to_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by transaction type (income, expense, transfer, investment)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by category ID",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by minimum amount (cents)",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by maximum amount (cents)",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by reconciliation status (pending, cleared)",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Paginated categories",
+                        "description": "Paginated transactions",
                         "schema": {
-                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_models_Category"
+                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_models_Transaction"
                         }
                     },
                     "400": {
@@ -1147,6 +1201,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Account not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -1154,50 +1214,46 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/admin/oracle-runs": {
+            "get": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "AdminKeyAuth": []
                     }
                 ],
-                "description": "Create a new transaction category",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List the most recent price oracle runs with their status, counts and per-symbol errors, newest first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "admin"
                 ],
-                "summary": "Create a category",
+                "summary": "List oracle runs",
                 "parameters": [
                     {
-                        "description": "Category details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handlers.CreateCategoryRequest"
-                        }
+                        "type": "integer",
+                        "description": "Maximum runs to list (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Category created",
+                    "200": {
+                        "description": "Runs",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.CategoryResponse"
+                            "$ref": "#/definitions/internal_handlers.OracleRunsResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid limit",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Invalid admin key",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -1207,77 +1263,71 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Admin not configured",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/categories/{id}": {
+        "/admin/pipeline-keys": {
             "get": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "AdminKeyAuth": []
                     }
                 ],
-                "description": "Get a specific transaction category by ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List every stored pipeline API key, including revoked ones. Key values are never returned.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
-                ],
-                "summary": "Get category by ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Category ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin"
                 ],
+                "summary": "List pipeline keys",
                 "responses": {
                     "200": {
-                        "description": "Category details",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handlers.CategoryResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid category ID",
+                        "description": "Keys",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/kuberan_internal_models.PipelineAPIKey"
+                                }
+                            }
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Invalid admin key",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Category not found",
+                    "500": {
+                        "description": "Server error",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Server error",
+                    "503": {
+                        "description": "Admin not configured",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "AdminKeyAuth": []
                     }
                 ],
-                "description": "Update an existing transaction category",
+                "description": "Generate a new pipeline API key. The plaintext key is returned only in this response.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1285,81 +1335,73 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "admin"
                 ],
-                "summary": "Update category",
+                "summary": "Create pipeline key",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Category ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated category details",
+                        "description": "Key name",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.UpdateCategoryRequest"
+                            "$ref": "#/definitions/internal_handlers.CreatePipelineKeyRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Updated category",
+                    "201": {
+                        "description": "Created key",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.CategoryResponse"
+                            "$ref": "#/definitions/internal_handlers.CreatePipelineKeyResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid input or category ID",
+                        "description": "Invalid input",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Invalid admin key",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Category not found",
+                    "500": {
+                        "description": "Server error",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Server error",
+                    "503": {
+                        "description": "Admin not configured",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/pipeline-keys/{id}": {
             "delete": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "AdminKeyAuth": []
                     }
                 ],
-                "description": "Delete a transaction category by ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Revoke a stored pipeline API key so it no longer authenticates",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "admin"
                 ],
-                "summary": "Delete category",
+                "summary": "Revoke pipeline key",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Category ID",
+                        "type": "string",
+                        "description": "Key ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1367,25 +1409,28 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Category deleted",
+                        "description": "Revoked key",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.MessageResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/kuberan_internal_models.PipelineAPIKey"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid category ID or cannot delete category in use",
+                        "description": "Invalid ID",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Invalid admin key",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Category not found",
+                        "description": "Key not found",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -1395,18 +1440,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Admin not configured",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/investments": {
+        "/auth/login": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Add a new investment holding to an investment account",
+                "description": "Authenticate a user by email or username and get access and refresh tokens",
                 "consumes": [
                     "application/json"
                 ],
@@ -1414,25 +1460,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "investments"
+                    "auth"
                 ],
-                "summary": "Add investment",
+                "summary": "Login user",
                 "parameters": [
                     {
-                        "description": "Investment details",
+                        "description": "User login credentials",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.AddInvestmentRequest"
+                            "$ref": "#/definitions/internal_handlers.LoginRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Investment created",
+                    "200": {
+                        "description": "User authenticated and tokens generated",
                         "schema": {
-                            "$ref": "#/definitions/kuberan_internal_models.Investment"
+                            "$ref": "#/definitions/internal_handlers.AuthResponse"
                         }
                     },
                     "400": {
@@ -1442,13 +1488,13 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Invalid credentials",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Account not found",
+                    "423": {
+                        "description": "Account locked",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -1462,14 +1508,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/investments/portfolio": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get an aggregated portfolio summary across all investment accounts",
+        "/auth/refresh": {
+            "post": {
+                "description": "Exchange a valid refresh token for new access and refresh tokens",
                 "consumes": [
                     "application/json"
                 ],
@@ -1477,18 +1518,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "investments"
+                    "auth"
+                ],
+                "summary": "Refresh access token",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.RefreshRequest"
+                        }
+                    }
                 ],
-                "summary": "Get portfolio summary",
                 "responses": {
                     "200": {
-                        "description": "Portfolio summary",
+                        "description": "New tokens generated",
                         "schema": {
-                            "$ref": "#/definitions/kuberan_internal_services.PortfolioSummary"
+                            "$ref": "#/definitions/internal_handlers.AuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Invalid or expired refresh token",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -1502,14 +1560,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/investments/snapshots": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get paginated portfolio snapshots for a date range",
+        "/auth/register": {
+            "post": {
+                "description": "Register a new user with email and password",
                 "consumes": [
                     "application/json"
                 ],
@@ -1517,42 +1570,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "investments"
+                    "auth"
                 ],
-                "summary": "Get portfolio snapshots",
+                "summary": "Register a new user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Start date (RFC3339 or YYYY-MM-DD)",
-                        "name": "from_date",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "End date (RFC3339 or YYYY-MM-DD)",
-                        "name": "to_date",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number (default 1)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Items per page (default 20, max 100)",
-                        "name": "page_size",
-                        "in": "query"
+                        "description": "User registration data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.RegisterRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Paginated snapshots",
+                    "201": {
+                        "description": "User registered and tokens generated",
                         "schema": {
-                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_models_PortfolioSnapshot"
+                            "$ref": "#/definitions/internal_handlers.AuthResponse"
                         }
                     },
                     "400": {
@@ -1561,8 +1597,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "500": {
+                        "description": "Server error",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -1570,14 +1606,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/investments/{id}": {
+        "/auth/verify-email": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get a specific investment by ID",
+                "description": "Confirm a pending login email change using the token sent to the new address",
                 "consumes": [
                     "application/json"
                 ],
@@ -1585,39 +1616,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "investments"
+                    "auth"
                 ],
-                "summary": "Get investment by ID",
+                "summary": "Verify email change",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Investment ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Verification token",
+                        "name": "token",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Investment details",
+                        "description": "Updated profile",
                         "schema": {
-                            "$ref": "#/definitions/kuberan_internal_models.Investment"
+                            "$ref": "#/definitions/internal_handlers.ProfileResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid investment ID",
+                        "description": "Invalid token",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "409": {
+                        "description": "Email already in use",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Investment not found",
+                    "410": {
+                        "description": "Token expired",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -1629,16 +1660,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/investments/{id}/buy": {
+            },
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Record a buy transaction for an investment holding",
+                "description": "Confirm a pending login email change using the token sent to the new address",
                 "consumes": [
                     "application/json"
                 ],
@@ -1646,48 +1670,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "investments"
+                    "auth"
                 ],
-                "summary": "Record buy transaction",
+                "summary": "Verify email change",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Investment ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Verification token",
+                        "name": "token",
+                        "in": "query",
                         "required": true
-                    },
-                    {
-                        "description": "Buy details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handlers.RecordBuyRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Transaction created",
+                    "200": {
+                        "description": "Updated profile",
                         "schema": {
-                            "$ref": "#/definitions/kuberan_internal_models.InvestmentTransaction"
+                            "$ref": "#/definitions/internal_handlers.ProfileResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid token",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "409": {
+                        "description": "Email already in use",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Investment not found",
+                    "410": {
+                        "description": "Token expired",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -1701,14 +1716,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/investments/{id}/dividend": {
-            "post": {
+        "/budgets": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Record a dividend payment for an investment holding",
+                "description": "Get a paginated list of budgets for the authenticated user",
                 "consumes": [
                     "application/json"
                 ],
@@ -1716,32 +1731,58 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "investments"
+                    "budgets"
                 ],
-                "summary": "Record dividend",
+                "summary": "Get budgets",
                 "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Filter by active status",
+                        "name": "is_active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by period (monthly/yearly)",
+                        "name": "period",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Investment ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
                     },
                     {
-                        "description": "Dividend details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handlers.RecordDividendRequest"
-                        }
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Compute total_items and total_pages (default true); when false they are -1 and 0",
+                        "name": "with_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "progress: add each budget's current-period spent, remaining and percentage",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "With include=progress, count pending expenses towards spend (default true)",
+                        "name": "include_pending",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Transaction created",
+                    "200": {
+                        "description": "Paginated budgets; spent, remaining and percentage only with include=progress",
                         "schema": {
-                            "$ref": "#/definitions/kuberan_internal_models.InvestmentTransaction"
+                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_services_BudgetListItem"
                         }
                     },
                     "400": {
@@ -1756,12 +1797,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Investment not found",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -1769,16 +1804,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/investments/{id}/sell": {
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Record a sell transaction for an investment holding",
+                "description": "Create a new budget for a category",
                 "consumes": [
                     "application/json"
                 ],
@@ -1786,36 +1819,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "investments"
+                    "budgets"
                 ],
-                "summary": "Record sell transaction",
+                "summary": "Create a budget",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Investment ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Sell details",
+                        "description": "Budget details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.RecordSellRequest"
+                            "$ref": "#/definitions/internal_handlers.CreateBudgetRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Transaction created",
+                        "description": "Budget created",
                         "schema": {
-                            "$ref": "#/definitions/kuberan_internal_models.InvestmentTransaction"
+                            "$ref": "#/definitions/kuberan_internal_models.Budget"
                         }
                     },
                     "400": {
-                        "description": "Invalid input or insufficient shares",
+                        "description": "Invalid input",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -1827,7 +1853,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Investment not found",
+                        "description": "Category not found",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -1841,53 +1867,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/investments/{id}/split": {
+        "/budgets/clone-last-month": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Record a stock split for an investment holding",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Copy the authenticated user's monthly budgets from last month into the current month, skipping categories that already have one",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "investments"
-                ],
-                "summary": "Record stock split",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Investment ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Split details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handlers.RecordSplitRequest"
-                        }
-                    }
+                    "budgets"
                 ],
+                "summary": "Clone last month's budgets",
                 "responses": {
-                    "201": {
-                        "description": "Transaction created",
-                        "schema": {
-                            "$ref": "#/definitions/kuberan_internal_models.InvestmentTransaction"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid input",
+                    "200": {
+                        "description": "Created budgets and count",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "401": {
@@ -1896,12 +1896,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Investment not found",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -1911,14 +1905,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/investments/{id}/transactions": {
+        "/budgets/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a paginated list of transactions for an investment",
+                "description": "Get a specific budget by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1926,39 +1920,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "investments"
+                    "budgets"
                 ],
-                "summary": "Get investment transactions",
+                "summary": "Get budget by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Investment ID",
+                        "description": "Budget ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number (default 1)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Items per page (default 20, max 100)",
-                        "name": "page_size",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Paginated transactions",
+                        "description": "Budget details",
                         "schema": {
-                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_models_InvestmentTransaction"
+                            "$ref": "#/definitions/kuberan_internal_models.Budget"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid budget ID",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -1970,7 +1952,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Investment not found",
+                        "description": "Budget not found",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -1982,16 +1964,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/pipeline/securities": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
-                        "ApiKeyAuth": []
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Create a new security (pipeline endpoint)",
+                "description": "Update an existing budget",
                 "consumes": [
                     "application/json"
                 ],
@@ -1999,62 +1979,67 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "pipeline"
+                    "budgets"
                 ],
-                "summary": "Create security",
+                "summary": "Update budget",
                 "parameters": [
                     {
-                        "description": "Security details",
+                        "type": "integer",
+                        "description": "Budget ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated budget details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.CreateSecurityRequest"
+                            "$ref": "#/definitions/internal_handlers.UpdateBudgetRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Security created",
+                    "200": {
+                        "description": "Updated budget",
                         "schema": {
-                            "$ref": "#/definitions/kuberan_internal_models.Security"
+                            "$ref": "#/definitions/kuberan_internal_models.Budget"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid input or budget ID",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Invalid API key",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Duplicate security",
+                    "404": {
+                        "description": "Budget not found",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
-                    "503": {
-                        "description": "Pipeline not configured",
+                    "500": {
+                        "description": "Server error",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/pipeline/securities/prices": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
-                        "ApiKeyAuth": []
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Bulk record prices for securities (pipeline endpoint)",
+                "description": "Delete a budget by ID (soft delete)",
                 "consumes": [
                     "application/json"
                 ],
@@ -2062,44 +2047,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "pipeline"
+                    "budgets"
                 ],
-                "summary": "Record prices",
+                "summary": "Delete budget",
                 "parameters": [
                     {
-                        "description": "Price entries",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handlers.RecordPricesRequest"
-                        }
+                        "type": "integer",
+                        "description": "Budget ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Prices recorded count",
+                        "description": "Budget deleted",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "integer"
-                            }
+                            "$ref": "#/definitions/internal_handlers.MessageResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid budget ID",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Invalid API key",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
-                    "503": {
-                        "description": "Pipeline not configured",
+                    "404": {
+                        "description": "Budget not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -2107,59 +2093,57 @@ const docTemplate = `{
                 }
             }
         },
-        "/pipeline/snapshots": {
-            "post": {
+        "/budgets/{id}/burndown": {
+            "get": {
                 "security": [
                     {
-                        "ApiKeyAuth": []
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Compute and record portfolio snapshots for all users (pipeline endpoint)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Cumulative settled spend per day of the budget's current period up to today, against the budgeted amount, with the end-of-period spend projected at the current daily pace",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "pipeline"
+                    "budgets"
                 ],
-                "summary": "Compute portfolio snapshots",
+                "summary": "Get budget burndown",
                 "parameters": [
                     {
-                        "description": "Snapshot parameters",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handlers.ComputeSnapshotsRequest"
-                        }
+                        "type": "string",
+                        "description": "Budget ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Snapshots recorded count",
+                        "description": "Budget burndown",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "integer"
-                            }
+                            "$ref": "#/definitions/kuberan_internal_services.BudgetBurndown"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid budget ID",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Invalid API key",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
-                    "503": {
-                        "description": "Pipeline not configured",
+                    "404": {
+                        "description": "Budget not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -2167,14 +2151,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/profile": {
+        "/budgets/{id}/progress": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get the authenticated user's profile information",
+                "description": "Get spending progress for a budget in the current period, with the pace of spending: days elapsed, the spend projected to the end of the period at the current daily rate, and whether that is on_track, at_risk or over",
                 "consumes": [
                     "application/json"
                 ],
@@ -2182,14 +2166,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "budgets"
+                ],
+                "summary": "Get budget progress",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Budget ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count pending expenses towards spend (default true)",
+                        "name": "include_pending",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get user profile",
                 "responses": {
                     "200": {
-                        "description": "User profile",
+                        "description": "Budget progress",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_services.BudgetProgress"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid budget ID",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.UserResponse"
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
                     "401": {
@@ -2198,6 +2203,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Budget not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -2207,14 +2218,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/securities": {
+        "/categories": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a paginated list of all securities, optionally filtered by search term",
+                "description": "Get a paginated list of transaction categories for the authenticated user",
                 "consumes": [
                     "application/json"
                 ],
@@ -2222,14 +2233,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "securities"
+                    "categories"
                 ],
-                "summary": "List securities",
+                "summary": "Get categories",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Search by symbol or name (case-insensitive)",
-                        "name": "search",
+                        "description": "Filter by category type (income/expense)",
+                        "name": "type",
                         "in": "query"
                     },
                     {
@@ -2243,13 +2254,25 @@ const docTemplate = `{
                         "description": "Items per page (default 20, max 100)",
                         "name": "page_size",
                         "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Compute total_items and total_pages (default true); when false they are -1 and 0",
+                        "name": "with_total",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Paginated securities",
+                        "description": "Paginated categories",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_models_Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
                         "schema": {
-                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_models_Security"
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
                     "401": {
@@ -2265,16 +2288,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/securities/{id}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a specific security by ID",
+                "description": "Create a new transaction category",
                 "consumes": [
                     "application/json"
                 ],
@@ -2282,27 +2303,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "securities"
+                    "categories"
                 ],
-                "summary": "Get security by ID",
+                "summary": "Create a category",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Security ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Category details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.CreateCategoryRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Security details",
+                    "201": {
+                        "description": "Category created",
                         "schema": {
-                            "$ref": "#/definitions/kuberan_internal_models.Security"
+                            "$ref": "#/definitions/internal_handlers.CategoryResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid security ID",
+                        "description": "Invalid input",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -2313,8 +2336,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Security not found",
+                    "500": {
+                        "description": "Server error",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -2322,14 +2345,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/securities/{id}/prices": {
+        "/categories/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get price history for a security (paginated)",
+                "description": "Get a specific transaction category by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -2337,53 +2360,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "securities"
+                    "categories"
                 ],
-                "summary": "Get price history",
+                "summary": "Get category by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Security ID",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Start date (RFC3339 or YYYY-MM-DD)",
-                        "name": "from_date",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "End date (RFC3339 or YYYY-MM-DD)",
-                        "name": "to_date",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number (default 1)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Items per page (default 20, max 100)",
-                        "name": "page_size",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Paginated prices",
+                        "description": "Category details",
                         "schema": {
-                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_models_SecurityPrice"
+                            "$ref": "#/definitions/internal_handlers.CategoryResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid category ID",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -2393,18 +2390,28 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Category not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
                     }
                 }
-            }
-        },
-        "/transactions": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a paginated list of all transactions for the authenticated user with optional filters",
+                "description": "Update an existing transaction category",
                 "consumes": [
                     "application/json"
                 ],
@@ -2412,74 +2419,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "categories"
                 ],
-                "summary": "Get user transactions",
+                "summary": "Update category",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Page number (default 1)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Items per page (default 20, max 100)",
-                        "name": "page_size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Filter by account ID",
-                        "name": "account_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by start date (RFC3339 e.g. 2024-01-01T00:00:00Z, or YYYY-MM-DD)",
-                        "name": "from_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by end date (RFC3339 or YYYY-MM-DD)",
-                        "name": "to_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by transaction type (income, expense, transfer, investment)",
-                        "name": "type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Filter by category ID",
-                        "name": "category_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Filter by minimum amount (cents)",
-                        "name": "min_amount",
-                        "in": "query"
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Filter by maximum amount (cents)",
-                        "name": "max_amount",
-                        "in": "query"
+                        "description": "Updated category details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.UpdateCategoryRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Paginated transactions",
+                        "description": "Updated category",
                         "schema": {
-                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_models_Transaction"
+                            "$ref": "#/definitions/internal_handlers.CategoryResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid input or category ID",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -2490,6 +2459,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Category not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -2498,13 +2473,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new transaction (income or expense) for an account",
+                "description": "Delete a transaction category by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -2512,29 +2487,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "categories"
                 ],
-                "summary": "Create a transaction",
+                "summary": "Delete category",
                 "parameters": [
                     {
-                        "description": "Transaction details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handlers.CreateTransactionRequest"
-                        }
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Transaction created",
+                    "200": {
+                        "description": "Category deleted",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.TransactionResponse"
+                            "$ref": "#/definitions/internal_handlers.MessageResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid category ID or cannot delete category in use",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -2545,6 +2518,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Category not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -2554,14 +2533,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/transactions/daily-spending": {
+        "/exchange-rates": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get daily expense totals for a date range",
+                "description": "Latest exchange rate of every recorded currency pair. With base, the rate from base to every reachable currency instead, resolved like server-side conversions: direct, then inverse, then through USD.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2569,35 +2548,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "exchange-rates"
                 ],
-                "summary": "Get daily spending",
+                "summary": "List exchange rates",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Start date (RFC3339 or YYYY-MM-DD)",
-                        "name": "from_date",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "End date (RFC3339 or YYYY-MM-DD)",
-                        "name": "to_date",
-                        "in": "query",
-                        "required": true
+                        "description": "ISO 4217 currency to express every rate from",
+                        "name": "base",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Daily spending data",
+                        "description": "Exchange rates",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/kuberan_internal_services.ExchangeRateQuote"
+                                }
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid base currency",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -2607,24 +2583,18 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
-                    },
-                    "500": {
-                        "description": "Server error",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
-                        }
                     }
                 }
             }
         },
-        "/transactions/monthly-summary": {
+        "/investments": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get monthly income and expense totals for the last N months",
+                "description": "Get a paginated list of all investments across all active investment accounts",
                 "consumes": [
                     "application/json"
                 ],
@@ -2632,23 +2602,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "investments"
                 ],
-                "summary": "Get monthly income and expense summary",
+                "summary": "Get all investments",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Number of months back (default 6, min 1, max 24)",
-                        "name": "months",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Compute total_items and total_pages (default true); when false they are -1 and 0",
+                        "name": "with_total",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Monthly summary data",
+                        "description": "Paginated investments",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_models_Investment"
                         }
                     },
                     "401": {
@@ -2664,16 +2645,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/transactions/spending-by-category": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get expense totals grouped by category for a date range",
+                "description": "Add a new investment holding to an investment account. Identify the security either by security_id or by symbol, name and asset_type (optionally currency and exchange); a symbol not yet in the catalog is added to it and the holding shows price_pending until the oracle prices it. If the account already holds the security, the purchase is added to that holding as a buy unless force_new is set.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2681,30 +2660,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "investments"
                 ],
-                "summary": "Get spending by category",
+                "summary": "Add investment",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Start date (RFC3339 or YYYY-MM-DD)",
-                        "name": "from_date",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "End date (RFC3339 or YYYY-MM-DD)",
-                        "name": "to_date",
-                        "in": "query",
-                        "required": true
+                        "description": "Investment details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.AddInvestmentRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Spending breakdown by category",
+                        "description": "Purchase added to an existing holding (merged=true)",
                         "schema": {
-                            "$ref": "#/definitions/kuberan_internal_services.SpendingByCategory"
+                            "$ref": "#/definitions/kuberan_internal_models.Investment"
+                        }
+                    },
+                    "201": {
+                        "description": "Investment created",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_models.Investment"
                         }
                     },
                     "400": {
@@ -2719,6 +2699,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Account or security not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -2728,14 +2714,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/transactions/transfer": {
-            "post": {
+        "/investments/benchmark": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Transfer funds from one account to another",
+                "description": "Compare the return on the user's investments, measured between their first and last snapshots in the range, with a benchmark security's price return over the same period",
                 "consumes": [
                     "application/json"
                 ],
@@ -2743,29 +2729,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "investments"
                 ],
-                "summary": "Create a transfer",
+                "summary": "Compare portfolio with a benchmark",
                 "parameters": [
                     {
-                        "description": "Transfer details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handlers.CreateTransferRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Transfer created",
+                        "type": "string",
+                        "description": "Benchmark security ID",
+                        "name": "security_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (RFC3339, YYYY-MM-DD or unix seconds)",
+                        "name": "from_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)",
+                        "name": "to_date",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Portfolio and benchmark returns",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.TransactionResponse"
+                            "$ref": "#/definitions/kuberan_internal_services.BenchmarkComparison"
                         }
                     },
                     "400": {
-                        "description": "Invalid input or insufficient balance",
+                        "description": "Invalid input, or missing snapshots or prices in the range",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -2777,7 +2775,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Account not found",
+                        "description": "Security not found",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -2791,14 +2789,65 @@ const docTemplate = `{
                 }
             }
         },
-        "/transactions/{id}": {
+        "/investments/export": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a specific transaction by ID",
+                "description": "Stream all holdings across active investment accounts as CSV (amounts in major units of each security's currency)",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "investments"
+                ],
+                "summary": "Export investments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export format (only csv is supported)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Unsupported format",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/investments/portfolio": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get an aggregated portfolio summary across all investment accounts. Totals are converted to the user's base currency at the latest exchange rate, values from the currency their price was recorded in (value_currency); each holding lists its native and converted value. Amounts in currencies without a rate are added unconverted and their currencies listed in unconverted_currencies. With include=sparklines, each holding also carries the last 7 daily closes of its security and their percentage change.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2806,27 +2855,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "investments"
                 ],
-                "summary": "Get transaction by ID",
+                "summary": "Get portfolio summary",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Transaction ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "Pass 'sparklines' to embed 7-day price sparklines",
+                        "name": "include",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Transaction details",
+                        "description": "Portfolio summary",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.TransactionResponse"
+                            "$ref": "#/definitions/kuberan_internal_services.PortfolioSummary"
                         }
                     },
                     "400": {
-                        "description": "Invalid transaction ID",
+                        "description": "Invalid include",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -2837,27 +2885,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Transaction not found",
+                    "500": {
+                        "description": "Server error",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Server error",
+                    "503": {
+                        "description": "Request timed out",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/investments/snapshots": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing transaction. Only income/expense transactions can be edited. Transfer and investment transactions cannot be modified.",
+                "description": "Get paginated portfolio snapshots for a date range",
                 "consumes": [
                     "application/json"
                 ],
@@ -2865,36 +2915,52 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "investments"
                 ],
-                "summary": "Update transaction",
+                "summary": "Get portfolio snapshots",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Transaction ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Start date (RFC3339, YYYY-MM-DD or unix seconds)",
+                        "name": "from_date",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "description": "Fields to update",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handlers.UpdateTransactionRequest"
-                        }
+                        "type": "string",
+                        "description": "End date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)",
+                        "name": "to_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Compute total_items and total_pages (default true); when false they are -1 and 0",
+                        "name": "with_total",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated transaction",
+                        "description": "Paginated snapshots",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.TransactionResponse"
+                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_models_PortfolioSnapshot"
                         }
                     },
                     "400": {
-                        "description": "Invalid input or non-editable transaction",
+                        "description": "Invalid input",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -2905,8 +2971,53 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Transaction not found",
+                    "503": {
+                        "description": "Request timed out",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/investments/tax-report": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Summarize sells in a calendar year with FIFO-matched cost basis, split into short-term (held one year or less) and long-term gains",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "investments"
+                ],
+                "summary": "Get realized-gain tax report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Calendar year (default current year)",
+                        "name": "year",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tax report",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_services.TaxReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid year",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -2918,14 +3029,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/investments/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a transaction by ID",
+                "description": "Get a specific investment by ID, with its lifetime fees (total_fees) and dividends received (total_dividends)",
                 "consumes": [
                     "application/json"
                 ],
@@ -2933,13 +3046,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "investments"
                 ],
-                "summary": "Delete transaction",
+                "summary": "Get investment by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Transaction ID",
+                        "description": "Investment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2947,13 +3060,13 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Transaction deleted",
+                        "description": "Investment details",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.MessageResponse"
+                            "$ref": "#/definitions/kuberan_internal_models.Investment"
                         }
                     },
                     "400": {
-                        "description": "Invalid transaction ID",
+                        "description": "Invalid investment ID",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -2965,7 +3078,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Transaction not found",
+                        "description": "Investment not found",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.ErrorResponse"
                         }
@@ -2977,299 +3090,6217 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "gorm.DeletedAt": {
-            "type": "object",
-            "properties": {
-                "time": {
-                    "type": "string"
-                },
-                "valid": {
-                    "description": "Valid is true if Time is not NULL",
-                    "type": "boolean"
-                }
-            }
-        },
-        "internal_handlers.AccountResponse": {
-            "type": "object",
-            "properties": {
-                "balance": {
-                    "type": "integer"
-                },
-                "currency": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "is_active": {
-                    "type": "boolean"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "type": {
-                    "$ref": "#/definitions/kuberan_internal_models.AccountType"
-                },
-                "user_id": {
-                    "type": "integer"
-                }
-            }
-        },
-        "internal_handlers.AddInvestmentRequest": {
-            "type": "object",
-            "required": [
-                "account_id",
-                "purchase_price",
-                "quantity",
-                "security_id"
-            ],
-            "properties": {
-                "account_id": {
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the notes and target price of a holding. Quantity and cost basis change only through transactions. A target_price of 0 clears the target.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "investments"
+                ],
+                "summary": "Update investment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Investment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.UpdateInvestmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated investment",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_models.Investment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Investment not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/investments/{id}/buy": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record a buy transaction for an investment holding, optionally debiting a cash account for the total. A price far from the stored price near the trade date is refused with PRICE_DEVIATION_WARNING unless confirm_price is set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "investments"
+                ],
+                "summary": "Record buy transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Investment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Buy details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.RecordBuyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Transaction created",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_models.InvestmentTransaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Investment not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Price deviates from the stored price (PRICE_DEVIATION_WARNING)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/investments/{id}/dividend": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record a dividend payment for an investment holding",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "investments"
+                ],
+                "summary": "Record dividend",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Investment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dividend details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.RecordDividendRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Transaction created",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_models.InvestmentTransaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Investment not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/investments/{id}/sell": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record a sell transaction for an investment holding. A price far from the stored price near the trade date is refused with PRICE_DEVIATION_WARNING unless confirm_price is set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "investments"
+                ],
+                "summary": "Record sell transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Investment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Sell details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.RecordSellRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Transaction created",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_models.InvestmentTransaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input or insufficient shares",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Investment not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Price deviates from the stored price (PRICE_DEVIATION_WARNING)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/investments/{id}/split": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record a stock split for an investment holding. A split_ratio below 1 is a reverse split; rounding optionally rounds the new quantity to whole units, paying the fraction rounded away as a cash-in-lieu dividend when cash_in_lieu_price is set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "investments"
+                ],
+                "summary": "Record stock split",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Investment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Split details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.RecordSplitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Transaction created",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_models.InvestmentTransaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Investment not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/investments/{id}/transactions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a paginated list of transactions for an investment, newest first. Each includes running_quantity and running_cost_basis: the position after it, replayed from the investment's first transaction.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "investments"
+                ],
+                "summary": "Get investment transactions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Investment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Compute total_items and total_pages (default true); when false they are -1 and 0",
+                        "name": "with_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated transactions",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_models_InvestmentTransaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Investment not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/meta/errors": {
+            "get": {
+                "description": "List every error code with the HTTP status it is answered with, its default message, and a hint, sorted by code. Responses may carry a more specific message than the default.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meta"
+                ],
+                "summary": "List error codes",
+                "responses": {
+                    "200": {
+                        "description": "Error catalog",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorCatalogResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pipeline/budgets/rollover": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Ensure every user's monthly budgets from last month exist for the current month (pipeline endpoint, idempotent)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pipeline"
+                ],
+                "summary": "Roll over monthly budgets",
+                "responses": {
+                    "200": {
+                        "description": "Created budgets count",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Pipeline not configured",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pipeline/email-changes/purge": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Permanently delete pending email changes whose verification token has expired (pipeline endpoint)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pipeline"
+                ],
+                "summary": "Purge expired email changes",
+                "responses": {
+                    "200": {
+                        "description": "Purged count",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Pipeline not configured",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pipeline/events/dispatch": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deliver undelivered domain events to their in-process consumers, oldest first (pipeline endpoint). Failed events are retried on a later dispatch.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pipeline"
+                ],
+                "summary": "Dispatch pending events",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum events to deliver (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delivered and failed counts",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_services.EventDispatchResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Pipeline not configured",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pipeline/exchange-rates": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Bulk record exchange rates (pipeline endpoint). A rate for an already recorded pair and timestamp replaces it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pipeline"
+                ],
+                "summary": "Record exchange rates",
+                "parameters": [
+                    {
+                        "description": "Rate entries",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.RecordExchangeRatesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rates recorded count",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Pipeline not configured",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pipeline/oracle-runs": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Store the final result of a price oracle run (pipeline endpoint), including runs that failed. The run's status is failed when failed is set, partial when errors lists symbols that could not be priced, and ok otherwise.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pipeline"
+                ],
+                "summary": "Record an oracle run",
+                "parameters": [
+                    {
+                        "description": "Run result",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.RecordOracleRunRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Recorded run",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.OracleRunResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Pipeline not configured",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pipeline/securities": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get all active securities without pagination (pipeline endpoint)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pipeline"
+                ],
+                "summary": "List all securities (pipeline)",
+                "responses": {
+                    "200": {
+                        "description": "All securities",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/kuberan_internal_models.Security"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Pipeline not configured",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new security (pipeline endpoint)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pipeline"
+                ],
+                "summary": "Create security",
+                "parameters": [
+                    {
+                        "description": "Security details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.CreateSecurityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Security created",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_models.Security"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Duplicate security",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Pipeline not configured",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pipeline/securities/batch": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create or update up to SECURITY_BATCH_MAX_SIZE securities in one database transaction (pipeline endpoint). Entries are matched to existing securities on symbol and exchange. Each entry is reported as created, updated or failed, with the reason for failures; invalid entries do not stop the others.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pipeline"
+                ],
+                "summary": "Upsert securities in bulk",
+                "parameters": [
+                    {
+                        "description": "Securities",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.CreateSecuritiesBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Per-entry outcome and counts",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_services.SecurityBatchResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Pipeline not configured",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pipeline/securities/prices": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Bulk record prices for securities (pipeline endpoint). A price for an already recorded security and timestamp replaces it. currency names the currency a price is in when it is not the security's own, e.g. after the oracle converted it. An entry that moves further from the security's previous price than PRICE_MAX_DEVIATION percent is not recorded but listed under rejected, unless it sets allow_large_move.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pipeline"
+                ],
+                "summary": "Record prices",
+                "parameters": [
+                    {
+                        "description": "Price entries",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.RecordPricesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Prices recorded count and rejected entries",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_services.RecordPricesResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Pipeline not configured",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pipeline/securities/{id}/prices/{priceId}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replace a recorded price (its source becomes manual) or, with delete, soft-delete it so that price history and valuations skip it (pipeline endpoint). The deviation check does not apply. Snapshots are not revalued; call POST /pipeline/snapshots/recompute afterwards. Corrections are audit-logged with the old and new values.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pipeline"
+                ],
+                "summary": "Correct a recorded price",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Security ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price ID",
+                        "name": "priceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Replacement price or delete",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.CorrectPriceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Price before and after the correction",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_services.PriceCorrectionResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Price not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Pipeline not configured",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pipeline/snapshots": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Compute and record portfolio snapshots for all users (pipeline endpoint). With as_of, snapshots are stamped at that time and investments valued at the latest price on or before it; as_of must not be in the future.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pipeline"
+                ],
+                "summary": "Compute portfolio snapshots",
+                "parameters": [
+                    {
+                        "description": "Snapshot parameters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ComputeSnapshotsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Snapshots recorded count",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Pipeline not configured",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pipeline/snapshots/backfill": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Reconstruct and record portfolio snapshots for all users over a past date range (pipeline endpoint). Existing snapshots are kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pipeline"
+                ],
+                "summary": "Backfill portfolio snapshots",
+                "parameters": [
+                    {
+                        "description": "Backfill parameters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.BackfillSnapshotsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Snapshots recorded count",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Pipeline not configured",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pipeline/snapshots/recompute": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revalue the investments in every snapshot recorded at or after from, at the prices recorded on or before each snapshot (pipeline endpoint). Cash and debt balances keep their recorded values. Use after correcting a price.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pipeline"
+                ],
+                "summary": "Recompute portfolio snapshots",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Earliest snapshot to recompute (RFC3339, YYYY-MM-DD or unix seconds)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Snapshots changed count",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Pipeline not configured",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pipeline/transactions/settle": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Apply pending transactions dated at or before now to account balances (pipeline endpoint)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pipeline"
+                ],
+                "summary": "Settle pending transactions",
+                "responses": {
+                    "200": {
+                        "description": "Settled transactions count",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Pipeline not configured",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the authenticated user's profile information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get user profile",
+                "responses": {
+                    "200": {
+                        "description": "User profile",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ProfileResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update display name, username, base currency, locale, first day of week, and UI preferences. A username can be changed once every 30 days. Email cannot be changed here.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Update user profile",
+                "parameters": [
+                    {
+                        "description": "Profile fields to update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated profile",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ProfileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Username taken",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Username changed too recently",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/backup": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Return the user's categories, accounts, transactions, budgets, investments and investment transactions as a versioned archive with a SHA-256 checksum per section. Unlike the export, the archive can be restored with POST /profile/restore.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Download a backup",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_services.BackupArchive"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/email": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start a login email change. The new address must be confirmed via /auth/verify-email before it takes effect.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Request email change",
+                "parameters": [
+                    {
+                        "description": "New email and current password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.RequestEmailChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Verification sent",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Incorrect password",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Email already in use",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stream the user's profile, accounts, categories, budgets, transactions, investments and investment transactions as a single JSON document. Other users' data and secrets such as password hashes are never included. Unlike the per-entity CSV exports, this is a complete dump.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Export all user data",
+                "responses": {
+                    "200": {
+                        "description": "JSON file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/password": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the password after checking the current one. Other sessions are logged out; the response carries a new token pair for this one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Change password",
+                "parameters": [
+                    {
+                        "description": "Current and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Password changed and tokens generated",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.AuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Incorrect password",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Recreate the archive's records under new IDs, one entity group at a time, replaying transactions so balances come out right. A user who already has accounts or categories is refused with 409 unless merge=false is passed, which replaces their books with the backup. A group that fails is rolled back and reported with completed=false; posting the same archive again resumes at that group.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Restore a backup",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Pass false to replace existing data",
+                        "name": "merge",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Archive from GET /profile/backup",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_services.BackupArchive"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_services.RestoreReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or modified archive",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The user already has data",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/spending-by-account": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get expense totals and counts per account for a date range, largest first. Transfers are not counted; inactive accounts with spending are included.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get spending by account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (RFC3339, YYYY-MM-DD or unix seconds)",
+                        "name": "from_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)",
+                        "name": "to_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include accounts excluded from reports",
+                        "name": "include_excluded",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Spending breakdown by account",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_services.SpendingByAccount"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a paginated list of rules in evaluation order (highest priority first)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rules"
+                ],
+                "summary": "Get categorization rules",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Compute total_items and total_pages (default true); when false they are -1 and 0",
+                        "name": "with_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated rules",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_models_CategorizationRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a rule that auto-categorizes transactions by description",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rules"
+                ],
+                "summary": "Create a categorization rule",
+                "parameters": [
+                    {
+                        "description": "Rule details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.CreateRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Rule created",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_models.CategorizationRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Category not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/apply": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Apply the user's rules to all existing uncategorized income and expense transactions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rules"
+                ],
+                "summary": "Apply categorization rules",
+                "responses": {
+                    "200": {
+                        "description": "Number of transactions categorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ApplyRulesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a specific categorization rule by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rules"
+                ],
+                "summary": "Get rule by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rule details",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_models.CategorizationRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rule ID",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing categorization rule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rules"
+                ],
+                "summary": "Update rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated rule details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.UpdateRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated rule",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_models.CategorizationRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input or rule ID",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Rule or category not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a categorization rule by ID (soft delete)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rules"
+                ],
+                "summary": "Delete rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rule deleted",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rule ID",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/securities": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a paginated list of all securities with the caller's holding in each, optionally filtered by search term, asset type, exchange, currency, or to held securities. Filters combine.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "securities"
+                ],
+                "summary": "List securities",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search by symbol or name (case-insensitive)",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by asset type (stock, etf, bond, crypto, reit)",
+                        "name": "asset_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by exchange (case-insensitive, e.g. NASDAQ)",
+                        "name": "exchange",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by ISO 4217 currency code (e.g. USD)",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only list securities the caller holds",
+                        "name": "owned",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Compute total_items and total_pages (default true); when false they are -1 and 0",
+                        "name": "with_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated securities",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_services_SecurityWithHolding"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/securities/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a specific security by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "securities"
+                ],
+                "summary": "Get security by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Security ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Security details",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_models.Security"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid security ID",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Security not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/securities/{id}/prices": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get price history for a security (paginated)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "securities"
+                ],
+                "summary": "Get price history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Security ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (RFC3339, YYYY-MM-DD or unix seconds)",
+                        "name": "from_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)",
+                        "name": "to_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Compute total_items and total_pages (default true); when false they are -1 and 0",
+                        "name": "with_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated prices",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_models_SecurityPrice"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/shares": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List account shares you have granted or received",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shares"
+                ],
+                "summary": "List shares",
+                "responses": {
+                    "200": {
+                        "description": "Shares",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/kuberan_internal_models.AccountShare"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Invite another registered user by email to view or edit some or all of your accounts. The share is active once accepted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shares"
+                ],
+                "summary": "Share accounts",
+                "parameters": [
+                    {
+                        "description": "Share details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.CreateShareRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Share created",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_models.AccountShare"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User or account not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Share already exists",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/shares/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke a share you granted or leave a share you received",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shares"
+                ],
+                "summary": "Delete share",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Share deleted",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Share not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/shares/{id}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Accept an account share invitation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shares"
+                ],
+                "summary": "Accept share",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Share accepted",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_models.AccountShare"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Share not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/statements/{month}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Opening and closing account balances, income and expense totals, top expense categories, budget standings, investment value change and transaction count for a calendar month (UTC)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "statements"
+                ],
+                "summary": "Get monthly statement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Month in YYYY-MM format",
+                        "name": "month",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Monthly statement",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid month",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/accounts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Transaction count, first and last activity, average transaction size and busiest day of week (UTC) for every account, including accounts with no activity. Transfers count on both accounts.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get account usage statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (RFC3339, YYYY-MM-DD or unix seconds)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)",
+                        "name": "to_date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Account usage statistics",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/categories": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Transaction count, first and last activity, average transaction size and busiest day of week (UTC) for every category, including categories with no activity",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get category usage statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (RFC3339, YYYY-MM-DD or unix seconds)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)",
+                        "name": "to_date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Category usage statistics",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a paginated list of all transactions for the authenticated user with optional filters",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get user transactions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Compute total_items and total_pages (default true); when false they are -1 and 0",
+                        "name": "with_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by account ID",
+                        "name": "account_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by start date (RFC3339 e.g. 2024-01-01T00:00:00Z, YYYY-MM-DD or unix seconds)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by end date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)",
+                        "name": "to_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by transaction type (income, expense, transfer, investment)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by category ID",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by minimum amount (cents)",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by maximum amount (cents)",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by reconciliation status (pending, cleared)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by pending (true) or settled (false) status",
+                        "name": "pending",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated transactions",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_models_Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new transaction (income or expense) for an account. Transactions dated in the future or flagged is_pending do not affect the balance until settled. The amount may be given in minor units (amount) or as a decimal string in the account currency (amount_decimal). The response also carries the account's balance after the transaction (account_balance).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Create a transaction",
+                "parameters": [
+                    {
+                        "description": "Transaction details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.CreateTransactionRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject unknown fields in the body",
+                        "name": "strict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Transaction created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.TransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/bulk-categorize": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set category_id on every income and expense on the user's own accounts matched by the filter (from_date and to_date required; uncategorized true matches transactions without a category). Only transactions of the category's type are changed, and a filter on the other type is rejected with CATEGORY_TYPE_MISMATCH. A null category_id removes the category. Balances are unaffected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Bulk recategorize transactions",
+                "parameters": [
+                    {
+                        "description": "Filter and category",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.BulkCategorizeTransactionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of transactions updated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input or category type mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Category not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/bulk-delete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "With dry_run true (the default), count and total the transactions matched by ids or a filter (from_date and to_date required) and return a confirmation token valid for 15 minutes. With dry_run false and that confirmation_token, delete them and reverse their balances. The delete is refused if the selection changed since the dry run.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Bulk delete transactions",
+                "parameters": [
+                    {
+                        "description": "Bulk delete request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.BulkDeleteTransactionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of transactions deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input or confirmation token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Read-only access to an account",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Transactions changed since the dry run",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Confirmation token expired",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/daily-spending": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get expense totals for a date range per day, week or month; empty buckets are zero and each is labelled with its first day. Weeks begin on the user's week start.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get daily spending",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (RFC3339, YYYY-MM-DD or unix seconds)",
+                        "name": "from_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)",
+                        "name": "to_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bucket size: day (default, range up to 366 days), week or month (range up to 36 months)",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include accounts excluded from reports",
+                        "name": "include_excluded",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Daily spending data",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Request timed out",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/flagged": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a paginated list of income and expenses from the last 90 days at or above the user's large-transaction threshold. Empty when no threshold is set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get flagged transactions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Compute total_items and total_pages (default true); when false they are -1 and 0",
+                        "name": "with_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated flagged transactions",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_models_Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/monthly-summary": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get monthly income and expense totals for the last N months",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get monthly income and expense summary",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of months back (default 6 unless configured, min 1, max 36)",
+                        "name": "months",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include per-category expense breakdown for each month",
+                        "name": "with_categories",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include accounts excluded from reports",
+                        "name": "include_excluded",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Monthly summary data",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid months",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Request timed out",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/spending-by-category": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get expense totals, or income totals with mode=income, grouped by category for a date range",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get spending by category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (RFC3339, YYYY-MM-DD or unix seconds)",
+                        "name": "from_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)",
+                        "name": "to_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "expense (default) or income",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include accounts excluded from reports",
+                        "name": "include_excluded",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Spending breakdown by category",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_services.SpendingByCategory"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Request timed out",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/spending-by-currency": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get expense totals per currency for a date range, largest first. Expenses with an original amount count under their original currency, others under their account's currency. original_total is in that currency; total is as booked in the accounts' currencies.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get spending by currency",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (RFC3339, YYYY-MM-DD or unix seconds)",
+                        "name": "from_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)",
+                        "name": "to_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include accounts excluded from reports",
+                        "name": "include_excluded",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Spending breakdown by currency",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_services.SpendingByCurrency"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Request timed out",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/split-transfer": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Debit the source account once and credit several destination accounts atomically. The total of all legs must not exceed the source balance. The legs share a transfer_group_id and deleting any one of them deletes all.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Create a split transfer",
+                "parameters": [
+                    {
+                        "description": "Split transfer details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.CreateSplitTransferRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject unknown fields in the body",
+                        "name": "strict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Transfer legs created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/kuberan_internal_models.Transaction"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input, unsupported account types or insufficient balance",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Account not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/transfer": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Transfer funds from one account to another. Allowed: cash to cash, cash to credit card (a payment, lowering the amount owed), and cash to or from an investment account's cash; other combinations are rejected with INVALID_TRANSFER. The amount may be given in minor units (amount) or as a decimal string in the source account currency (amount_decimal). The response also carries both accounts' balances after the transfer (from_account_balance, to_account_balance).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Create a transfer",
+                "parameters": [
+                    {
+                        "description": "Transfer details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.CreateTransferRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject unknown fields in the body",
+                        "name": "strict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Transfer created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.TransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input, unsupported account types or insufficient balance",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Account not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/transfers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a paginated list of transfers between the user's accounts, each with both the source (account) and destination (to_account) preloaded",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get transfers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Compute total_items and total_pages (default true); when false they are -1 and 0",
+                        "name": "with_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only transfers into or out of this account",
+                        "name": "account_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by start date (RFC3339 e.g. 2024-01-01T00:00:00Z, YYYY-MM-DD or unix seconds)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by end date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)",
+                        "name": "to_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by minimum amount (cents)",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by maximum amount (cents)",
+                        "name": "max_amount",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated transfers",
+                        "schema": {
+                            "$ref": "#/definitions/kuberan_internal_pagination.PageResponse-kuberan_internal_models_Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a specific transaction by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get transaction by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Transaction details",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.TransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid transaction ID",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Partially update an existing transaction; only the fields sent are changed and at least one is required. Only income/expense transactions can be edited. Transfer and investment transactions cannot be modified, except for a status-only update marking them pending or cleared. Dates accept RFC3339, YYYY-MM-DD or unix seconds.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Update transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.UpdateTransactionRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject unknown fields in the body",
+                        "name": "strict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated transaction",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.TransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input or non-editable transaction",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a transaction by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Delete transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Transaction deleted",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid transaction ID",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Partially update an existing transaction; only the fields sent are changed and at least one is required. Only income/expense transactions can be edited. Transfer and investment transactions cannot be modified, except for a status-only update marking them pending or cleared. Dates accept RFC3339, YYYY-MM-DD or unix seconds.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Update transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.UpdateTransactionRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject unknown fields in the body",
+                        "name": "strict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated transaction",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.TransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input or non-editable transaction",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "gorm.DeletedAt": {
+            "type": "object",
+            "properties": {
+                "time": {
+                    "type": "string"
+                },
+                "valid": {
+                    "description": "Valid is true if Time is not NULL",
+                    "type": "boolean"
+                }
+            }
+        },
+        "internal_handlers.AccountGroupRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                }
+            }
+        },
+        "internal_handlers.AccountGroupResponse": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "$ref": "#/definitions/kuberan_internal_models.AccountGroup"
+                }
+            }
+        },
+        "internal_handlers.AccountGroupsResponse": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_models.AccountGroup"
+                    }
+                }
+            }
+        },
+        "internal_handlers.AccountResponse": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/kuberan_internal_models.AccountType"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handlers.AddInvestmentRequest": {
+            "type": "object",
+            "required": [
+                "account_id",
+                "quantity"
+            ],
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "asset_type": {
+                    "description": "security asset type when given by symbol",
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "security currency when given by symbol, defaults to USD",
+                    "type": "string"
+                },
+                "date": {
+                    "description": "optional, defaults to now",
+                    "type": "string"
+                },
+                "exchange": {
+                    "description": "security exchange when given by symbol",
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "fee": {
+                    "description": "optional, defaults to 0",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "force_new": {
+                    "description": "create a separate holding even if the account already holds the security",
+                    "type": "boolean"
+                },
+                "from_account_id": {
+                    "description": "optional cash account debited for the purchase",
+                    "type": "string"
+                },
+                "holding_notes": {
+                    "description": "optional notes stored on the holding (e.g. thesis)",
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "name": {
+                    "description": "security name when given by symbol",
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "notes": {
+                    "description": "optional buy transaction notes, defaults to \"Initial purchase\"",
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "purchase_price": {
+                    "type": "integer"
+                },
+                "purchase_price_decimal": {
+                    "description": "alternative to purchase_price, in the security's currency",
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "security_id": {
+                    "description": "catalog security; alternative to symbol, name and asset_type",
+                    "type": "string"
+                },
+                "symbol": {
+                    "description": "with name and asset_type, finds or adds a catalog security",
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "target_price": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "wallet_address": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handlers.ApplyRulesResponse": {
+            "type": "object",
+            "properties": {
+                "categorized": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handlers.AuthResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/internal_handlers.UserResponse"
+                }
+            }
+        },
+        "internal_handlers.BackfillSnapshotsRequest": {
+            "type": "object",
+            "required": [
+                "from",
+                "interval",
+                "to"
+            ],
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handlers.BulkCategorizeTransactionsRequest": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "string"
+                },
+                "filter": {
+                    "$ref": "#/definitions/internal_handlers.BulkFilterRequest"
+                }
+            }
+        },
+        "internal_handlers.BulkDeleteTransactionsRequest": {
+            "type": "object",
+            "properties": {
+                "confirmation_token": {
+                    "type": "string"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "filter": {
+                    "$ref": "#/definitions/internal_handlers.BulkFilterRequest"
+                },
+                "ids": {
+                    "type": "array",
+                    "maxItems": 10000,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "internal_handlers.BulkFilterRequest": {
+            "type": "object",
+            "required": [
+                "from_date",
+                "to_date"
+            ],
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "from_date": {
+                    "type": "string"
+                },
+                "max_amount": {
+                    "type": "integer"
+                },
+                "min_amount": {
+                    "type": "integer"
+                },
+                "pending": {
+                    "type": "boolean"
+                },
+                "to_date": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/kuberan_internal_models.TransactionType"
+                },
+                "uncategorized": {
+                    "description": "only transactions without a category",
+                    "type": "boolean"
+                }
+            }
+        },
+        "internal_handlers.CategoryResponse": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/kuberan_internal_models.CategoryType"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handlers.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string",
+                    "maxLength": 128,
+                    "minLength": 8
+                }
+            }
+        },
+        "internal_handlers.ComputeSnapshotsRequest": {
+            "type": "object",
+            "properties": {
+                "as_of": {
+                    "type": "string"
+                },
+                "recorded_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handlers.CorrectPriceRequest": {
+            "type": "object",
+            "properties": {
+                "delete": {
+                    "type": "boolean"
+                },
+                "price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handlers.CreateBudgetRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "category_id",
+                "name",
+                "period",
+                "start_date"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "period": {
+                    "$ref": "#/definitions/kuberan_internal_models.BudgetPeriod"
+                },
+                "start_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handlers.CreateCashAccountRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "allow_negative": {
+                    "description": "defaults to true",
+                    "type": "boolean"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "initial_balance": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "initial_balance_decimal": {
+                    "description": "alternative to initial_balance, e.g. \"12.34\"",
+                    "type": "string"
+                },
+                "min_balance": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "opening_date": {
+                    "description": "backdates the account and its initial balance; not in the future",
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handlers.CreateCategoryRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "type"
+            ],
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "icon": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/kuberan_internal_models.CategoryType"
+                }
+            }
+        },
+        "internal_handlers.CreateCreditCardAccountRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "credit_limit": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "interest_rate": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "opening_date": {
+                    "description": "backdates the account; not in the future",
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handlers.CreateInvestmentAccountRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "account_number": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "broker": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                }
+            }
+        },
+        "internal_handlers.CreatePipelineKeyRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "internal_handlers.CreatePipelineKeyResponse": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "$ref": "#/definitions/kuberan_internal_models.PipelineAPIKey"
+                },
+                "secret": {
+                    "description": "shown only once",
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handlers.CreateRuleRequest": {
+            "type": "object",
+            "required": [
+                "category_id",
+                "match_type",
+                "pattern"
+            ],
+            "properties": {
+                "category_id": {
+                    "type": "string"
+                },
+                "match_type": {
+                    "$ref": "#/definitions/kuberan_internal_models.RuleMatchType"
+                },
+                "pattern": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "priority": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handlers.CreateSecuritiesBatchRequest": {
+            "type": "object",
+            "required": [
+                "securities"
+            ],
+            "properties": {
+                "securities": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.CreateSecurityRequest"
+                    }
+                }
+            }
+        },
+        "internal_handlers.CreateSecurityRequest": {
+            "type": "object",
+            "required": [
+                "asset_type",
+                "name",
+                "symbol"
+            ],
+            "properties": {
+                "asset_type": {
+                    "$ref": "#/definitions/kuberan_internal_models.AssetType"
+                },
+                "coupon_rate": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "exchange": {
+                    "type": "string"
+                },
+                "maturity_date": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "minLength": 1
+                },
+                "network": {
+                    "type": "string"
+                },
+                "preferred_provider": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "property_type": {
+                    "type": "string"
+                },
+                "provider_symbol": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 1
+                },
+                "yield_to_maturity": {
+                    "type": "number"
+                }
+            }
+        },
+        "internal_handlers.CreateShareRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "role"
+            ],
+            "properties": {
+                "account_ids": {
+                    "description": "optional; empty shares all accounts",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "email": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/kuberan_internal_models.ShareRole"
+                }
+            }
+        },
+        "internal_handlers.CreateSplitTransferRequest": {
+            "type": "object",
+            "required": [
+                "from_account_id",
+                "legs"
+            ],
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "from_account_id": {
+                    "type": "string"
+                },
+                "legs": {
+                    "type": "array",
+                    "minItems": 2,
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.SplitTransferLegRequest"
+                    }
+                }
+            }
+        },
+        "internal_handlers.CreateTransactionRequest": {
+            "type": "object",
+            "required": [
+                "account_id",
+                "type"
+            ],
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "integer"
+                },
+                "amount_decimal": {
+                    "description": "alternative to amount, in the account's currency (e.g. \"12.34\")",
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "is_pending": {
+                    "type": "boolean"
+                },
+                "original_amount": {
+                    "description": "OriginalAmount (minor units) and OriginalCurrency record a foreign-currency\npurchase for reference; send both or neither. Amount still moves the balance.",
+                    "type": "integer"
+                },
+                "original_currency": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "pending or cleared (default)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/kuberan_internal_models.TransactionStatus"
+                        }
+                    ]
+                },
+                "type": {
+                    "$ref": "#/definitions/kuberan_internal_models.TransactionType"
+                }
+            }
+        },
+        "internal_handlers.CreateTransferRequest": {
+            "type": "object",
+            "required": [
+                "from_account_id",
+                "to_account_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "amount_decimal": {
+                    "description": "alternative to amount, in the source account's currency",
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "from_account_id": {
+                    "type": "string"
+                },
+                "to_account_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handlers.ErrorCatalogResponse": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_errors.CatalogEntry"
+                    }
+                }
+            }
+        },
+        "internal_handlers.ErrorDetail": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handlers.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/internal_handlers.ErrorDetail"
+                }
+            }
+        },
+        "internal_handlers.GroupedAccountsResponse": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_services.GroupedAccounts"
+                    }
+                }
+            }
+        },
+        "internal_handlers.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handlers.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handlers.OracleRunErrorEntry": {
+            "type": "object",
+            "required": [
+                "error"
+            ],
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "security_id": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handlers.OracleRunResponse": {
+            "type": "object",
+            "properties": {
+                "run": {
+                    "$ref": "#/definitions/kuberan_internal_models.OracleRun"
+                }
+            }
+        },
+        "internal_handlers.OracleRunsResponse": {
+            "type": "object",
+            "properties": {
+                "runs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_models.OracleRun"
+                    }
+                }
+            }
+        },
+        "internal_handlers.ProfileResponse": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string"
+                },
+                "cost_basis_method": {
+                    "$ref": "#/definitions/kuberan_internal_models.CostBasisMethod"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "large_transaction_threshold": {
+                    "type": "integer"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "preferences": {
+                    "type": "object"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "week_start": {
+                    "$ref": "#/definitions/kuberan_internal_models.WeekStart"
+                }
+            }
+        },
+        "internal_handlers.RecordBuyRequest": {
+            "type": "object",
+            "required": [
+                "date",
+                "quantity"
+            ],
+            "properties": {
+                "confirm_price": {
+                    "description": "record the price even when it is far from the stored price near the date",
+                    "type": "boolean"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "fee": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "from_account_id": {
+                    "description": "optional cash account debited for the purchase",
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "price_per_unit": {
+                    "type": "integer"
+                },
+                "price_per_unit_decimal": {
+                    "description": "alternative to price_per_unit, in the security's currency",
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                }
+            }
+        },
+        "internal_handlers.RecordDividendRequest": {
+            "type": "object",
+            "required": [
+                "date"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "amount_decimal": {
+                    "description": "alternative to amount, in the security's currency",
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "dividend_type": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "external_ref": {
+                    "description": "idempotency key; a repeat returns the existing transaction",
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "notes": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "internal_handlers.RecordExchangeRateEntry": {
+            "type": "object",
+            "required": [
+                "from_currency",
+                "rate",
+                "recorded_at",
+                "to_currency"
+            ],
+            "properties": {
+                "from_currency": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "recorded_at": {
+                    "type": "string"
+                },
+                "to_currency": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handlers.RecordExchangeRatesRequest": {
+            "type": "object",
+            "required": [
+                "rates"
+            ],
+            "properties": {
+                "rates": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.RecordExchangeRateEntry"
+                    }
+                }
+            }
+        },
+        "internal_handlers.RecordOracleRunRequest": {
+            "type": "object",
+            "required": [
+                "started_at"
+            ],
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "duration_ms": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "error": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.OracleRunErrorEntry"
+                    }
+                },
+                "failed": {
+                    "type": "boolean"
+                },
+                "prices_recorded": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "securities_fetched": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "snapshots_recorded": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "internal_handlers.RecordPriceEntry": {
+            "type": "object",
+            "required": [
+                "price",
+                "recorded_at",
+                "security_id"
+            ],
+            "properties": {
+                "allow_large_move": {
+                    "description": "AllowLargeMove records the price even if it moves further from the\nprevious price than the anomaly guard allows",
+                    "type": "boolean"
+                },
+                "currency": {
+                    "description": "currency the price is in, when not the security's",
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "recorded_at": {
+                    "type": "string"
+                },
+                "security_id": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "yahoo, coingecko, bursa or manual (default)",
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handlers.RecordPricesRequest": {
+            "type": "object",
+            "required": [
+                "prices"
+            ],
+            "properties": {
+                "prices": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.RecordPriceEntry"
+                    }
+                }
+            }
+        },
+        "internal_handlers.RecordSellRequest": {
+            "type": "object",
+            "required": [
+                "date",
+                "quantity"
+            ],
+            "properties": {
+                "confirm_price": {
+                    "description": "record the price even when it is far from the stored price near the date",
+                    "type": "boolean"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "fee": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "notes": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "price_per_unit": {
+                    "type": "integer"
+                },
+                "price_per_unit_decimal": {
+                    "description": "alternative to price_per_unit, in the security's currency",
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                }
+            }
+        },
+        "internal_handlers.RecordSplitRequest": {
+            "type": "object",
+            "required": [
+                "date",
+                "split_ratio"
+            ],
+            "properties": {
+                "cash_in_lieu_price": {
+                    "description": "CashInLieuPrice pays units rounded away as a cash-in-lieu dividend at this price per post-split unit",
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "external_ref": {
+                    "description": "idempotency key; a repeat returns the existing transaction",
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "notes": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "rounding": {
+                    "description": "Rounding of the resulting quantity: none (default, keep fractions), down, or nearest",
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "down",
+                        "nearest"
+                    ]
+                },
+                "split_ratio": {
+                    "type": "number"
+                }
+            }
+        },
+        "internal_handlers.RefreshRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handlers.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 128,
+                    "minLength": 8
+                }
+            }
+        },
+        "internal_handlers.ReorderAccountGroupsRequest": {
+            "type": "object",
+            "required": [
+                "group_ids"
+            ],
+            "properties": {
+                "group_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "internal_handlers.RequestEmailChangeRequest": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_email"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_email": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "internal_handlers.SplitTransferLegRequest": {
+            "type": "object",
+            "required": [
+                "to_account_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "amount_decimal": {
+                    "description": "alternative to amount, in the source account's currency",
+                    "type": "string"
+                },
+                "to_account_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handlers.TransactionResponse": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/kuberan_internal_models.TransactionType"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handlers.UpdateAccountRequest": {
+            "type": "object",
+            "properties": {
+                "account_number": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "allow_negative": {
+                    "type": "boolean"
+                },
+                "broker": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "credit_limit": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "default_category_id": {
+                    "description": "empty string clears it",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "exclude_from_reports": {
+                    "type": "boolean"
+                },
+                "group_id": {
+                    "description": "empty string ungroups the account",
+                    "type": "string"
+                },
+                "interest_rate": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "min_balance": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                }
+            }
+        },
+        "internal_handlers.UpdateBudgetRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "period": {
+                    "$ref": "#/definitions/kuberan_internal_models.BudgetPeriod"
+                }
+            }
+        },
+        "internal_handlers.UpdateCategoryRequest": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "icon": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "parent_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handlers.UpdateInvestmentRequest": {
+            "type": "object",
+            "properties": {
+                "notes": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "target_price": {
+                    "description": "0 clears the target",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "internal_handlers.UpdateProfileRequest": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string"
+                },
+                "cost_basis_method": {
+                    "description": "CostBasisMethod applies to future sells only; past sells keep the method they used.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/kuberan_internal_models.CostBasisMethod"
+                        }
+                    ]
+                },
+                "display_name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "email": {
+                    "type": "string"
+                },
+                "large_transaction_threshold": {
+                    "description": "LargeTransactionThreshold is the amount in minor units at or above which a new\ntransaction triggers an email alert; 0 turns the alert off.",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "preferences": {
+                    "type": "object"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "week_start": {
+                    "$ref": "#/definitions/kuberan_internal_models.WeekStart"
+                }
+            }
+        },
+        "internal_handlers.UpdateRuleRequest": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "string"
+                },
+                "match_type": {
+                    "$ref": "#/definitions/kuberan_internal_models.RuleMatchType"
+                },
+                "pattern": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "priority": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handlers.UpdateTransactionRequest": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "integer"
+                },
+                "amount_decimal": {
+                    "description": "alternative to amount, in the transaction account's currency",
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "original_amount": {
+                    "description": "OriginalAmount and OriginalCurrency are set together; an empty\noriginal_currency alone clears them.",
+                    "type": "integer"
+                },
+                "original_currency": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/kuberan_internal_models.TransactionStatus"
+                },
+                "type": {
+                    "$ref": "#/definitions/kuberan_internal_models.TransactionType"
+                }
+            }
+        },
+        "internal_handlers.UserResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "kuberan_internal_errors.CatalogEntry": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "hint": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "kuberan_internal_models.Account": {
+            "type": "object",
+            "properties": {
+                "account_number": {
+                    "type": "string"
+                },
+                "allow_negative": {
+                    "description": "For cash accounts: with AllowNegative off, expenses and outgoing transfers\nmay not take the balance below MinBalance",
+                    "type": "boolean"
+                },
+                "archived_at": {
+                    "description": "Set when the account is deactivated: when it happened and its balance then",
+                    "type": "string"
+                },
+                "archived_balance": {
+                    "type": "integer"
+                },
+                "balance": {
+                    "type": "integer"
+                },
+                "broker": {
+                    "description": "For investment accounts",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "credit_limit": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "default_category_id": {
+                    "description": "Category given to new transactions on the account that arrive without one\nand match no categorization rule",
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "exclude_from_reports": {
+                    "description": "Excluded accounts are left out of net worth snapshots and spending reports",
+                    "type": "boolean"
+                },
+                "group_id": {
+                    "description": "Folder the account is filed under; nil for ungrouped accounts",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "interest_rate": {
+                    "description": "For debt and credit card accounts",
+                    "type": "number"
+                },
+                "investments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_models.Investment"
+                    }
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "min_balance": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "opening_date": {
+                    "description": "When the account was opened, if backdated at creation; reports leave the\naccount out before it",
+                    "type": "string"
+                },
+                "transactions": {
+                    "description": "Relationships",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_models.Transaction"
+                    }
+                },
+                "type": {
+                    "$ref": "#/definitions/kuberan_internal_models.AccountType"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "kuberan_internal_models.AccountGroup": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "sort_order": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "kuberan_internal_models.AccountShare": {
+            "type": "object",
+            "properties": {
+                "accepted_at": {
+                    "type": "string"
+                },
+                "account_ids": {
+                    "description": "AccountIDs lists the shared accounts when AllAccounts is false.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "all_accounts": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "owner_id": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/kuberan_internal_models.ShareRole"
+                },
+                "shared_with_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "kuberan_internal_models.AccountType": {
+            "type": "string",
+            "enum": [
+                "cash",
+                "investment",
+                "debt",
+                "credit_card"
+            ],
+            "x-enum-varnames": [
+                "AccountTypeCash",
+                "AccountTypeInvestment",
+                "AccountTypeDebt",
+                "AccountTypeCreditCard"
+            ]
+        },
+        "kuberan_internal_models.AssetType": {
+            "type": "string",
+            "enum": [
+                "stock",
+                "etf",
+                "bond",
+                "crypto",
+                "reit"
+            ],
+            "x-enum-varnames": [
+                "AssetTypeStock",
+                "AssetTypeETF",
+                "AssetTypeBond",
+                "AssetTypeCrypto",
+                "AssetTypeREIT"
+            ]
+        },
+        "kuberan_internal_models.Budget": {
+            "type": "object",
+            "properties": {
+                "amount": {
                     "type": "integer"
                 },
-                "date": {
-                    "description": "optional, defaults to now",
+                "category": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/kuberan_internal_models.Category"
+                        }
+                    ]
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "period": {
+                    "$ref": "#/definitions/kuberan_internal_models.BudgetPeriod"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "kuberan_internal_models.BudgetPeriod": {
+            "type": "string",
+            "enum": [
+                "monthly",
+                "yearly"
+            ],
+            "x-enum-varnames": [
+                "BudgetPeriodMonthly",
+                "BudgetPeriodYearly"
+            ]
+        },
+        "kuberan_internal_models.CategorizationRule": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/kuberan_internal_models.Category"
+                        }
+                    ]
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "match_type": {
+                    "$ref": "#/definitions/kuberan_internal_models.RuleMatchType"
+                },
+                "pattern": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "kuberan_internal_models.Category": {
+            "type": "object",
+            "properties": {
+                "budgets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_models.Budget"
+                    }
+                },
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_models.Category"
+                    }
+                },
+                "color": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/kuberan_internal_models.Category"
+                        }
+                    ]
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_models.Transaction"
+                    }
+                },
+                "type": {
+                    "$ref": "#/definitions/kuberan_internal_models.CategoryType"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "kuberan_internal_models.CategoryType": {
+            "type": "string",
+            "enum": [
+                "income",
+                "expense"
+            ],
+            "x-enum-varnames": [
+                "CategoryTypeIncome",
+                "CategoryTypeExpense"
+            ]
+        },
+        "kuberan_internal_models.CostBasisMethod": {
+            "type": "string",
+            "enum": [
+                "average",
+                "fifo"
+            ],
+            "x-enum-comments": {
+                "CostBasisAverage": "proportional share of the holding's cost basis",
+                "CostBasisFIFO": "cost of the oldest buy lots first"
+            },
+            "x-enum-varnames": [
+                "CostBasisAverage",
+                "CostBasisFIFO"
+            ]
+        },
+        "kuberan_internal_models.Investment": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "$ref": "#/definitions/kuberan_internal_models.Account"
+                },
+                "account_id": {
+                    "type": "string"
+                },
+                "at_target": {
+                    "description": "CurrentPrice has reached TargetPrice",
+                    "type": "boolean"
+                },
+                "cost_basis": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "current_price": {
+                    "description": "Populated at query time from security_prices",
+                    "type": "integer"
+                },
+                "current_price_as_of": {
+                    "description": "recorded_at of the price used for CurrentPrice; nil when unpriced",
+                    "type": "string"
+                },
+                "current_value": {
+                    "description": "Computed from CurrentPrice at query time; zero while the price is pending",
+                    "type": "integer"
+                },
+                "day_change": {
+                    "description": "Value change since the previous price; nil with only one price",
+                    "type": "integer"
+                },
+                "deleted_at": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "gain_loss_pct": {
+                    "description": "0 when CostBasis is 0",
+                    "type": "number"
+                },
+                "id": {
                     "type": "string"
                 },
-                "fee": {
-                    "description": "optional, defaults to 0",
-                    "type": "integer",
-                    "minimum": 0
-                },
                 "notes": {
-                    "description": "optional, defaults to \"Initial purchase\"",
-                    "type": "string",
-                    "maxLength": 500
+                    "type": "string"
                 },
-                "purchase_price": {
-                    "type": "integer"
+                "price_pending": {
+                    "description": "The security has no recorded price yet",
+                    "type": "boolean"
                 },
                 "quantity": {
                     "type": "number"
                 },
+                "realized_gain_loss": {
+                    "type": "integer"
+                },
+                "security": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/kuberan_internal_models.Security"
+                        }
+                    ]
+                },
                 "security_id": {
+                    "type": "string"
+                },
+                "target_price": {
+                    "description": "Optional price the user is watching for",
+                    "type": "integer"
+                },
+                "total_dividends": {
+                    "description": "dividend amounts received",
+                    "type": "integer"
+                },
+                "total_fees": {
+                    "description": "Lifetime totals from the holding's transactions; only set on a single holding",
+                    "type": "integer"
+                },
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_models.InvestmentTransaction"
+                    }
+                },
+                "unrealized_gain_loss": {
+                    "description": "CurrentValue - CostBasis",
                     "type": "integer"
                 },
+                "updated_at": {
+                    "type": "string"
+                },
                 "wallet_address": {
                     "type": "string"
                 }
             }
         },
-        "internal_handlers.AuthResponse": {
+        "kuberan_internal_models.InvestmentTransaction": {
             "type": "object",
             "properties": {
-                "access_token": {
+                "cash_transaction_id": {
+                    "description": "For buys funded from a cash account: the transfer that debited it",
                     "type": "string"
                 },
-                "refresh_token": {
+                "cost_basis_method": {
+                    "description": "For sells: the method used for RealizedGainLoss, kept so later preference changes don't rewrite it",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/kuberan_internal_models.CostBasisMethod"
+                        }
+                    ]
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/internal_handlers.UserResponse"
-                }
-            }
-        },
-        "internal_handlers.CategoryResponse": {
-            "type": "object",
-            "properties": {
-                "color": {
+                "date": {
                     "type": "string"
                 },
-                "description": {
+                "deleted_at": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "dividend_type": {
+                    "description": "For dividends",
                     "type": "string"
                 },
-                "icon": {
+                "external_ref": {
+                    "description": "Source-provided idempotency key, unique per investment; nil for manual entries",
                     "type": "string"
                 },
-                "id": {
+                "fee": {
                     "type": "integer"
                 },
-                "name": {
+                "id": {
                     "type": "string"
                 },
-                "parent_id": {
+                "investment": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/kuberan_internal_models.Investment"
+                        }
+                    ]
+                },
+                "investment_id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "price_per_unit": {
                     "type": "integer"
                 },
-                "type": {
-                    "$ref": "#/definitions/kuberan_internal_models.CategoryType"
+                "quantity": {
+                    "type": "number"
                 },
-                "user_id": {
+                "realized_gain_loss": {
+                    "type": "integer"
+                },
+                "running_cost_basis": {
+                    "type": "integer"
+                },
+                "running_quantity": {
+                    "description": "Position after this transaction, replayed from the first one when listing an investment's transactions",
+                    "type": "number"
+                },
+                "split_ratio": {
+                    "description": "For splits; SplitRemainder is the post-split quantity rounded away (negative when rounded up)",
+                    "type": "number"
+                },
+                "split_remainder": {
+                    "type": "number"
+                },
+                "total_amount": {
                     "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/kuberan_internal_models.InvestmentTransactionType"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "internal_handlers.ComputeSnapshotsRequest": {
-            "type": "object",
-            "required": [
-                "recorded_at"
+        "kuberan_internal_models.InvestmentTransactionType": {
+            "type": "string",
+            "enum": [
+                "buy",
+                "sell",
+                "dividend",
+                "split",
+                "transfer"
             ],
-            "properties": {
-                "recorded_at": {
-                    "type": "string"
-                }
-            }
+            "x-enum-varnames": [
+                "InvestmentTransactionBuy",
+                "InvestmentTransactionSell",
+                "InvestmentTransactionDividend",
+                "InvestmentTransactionSplit",
+                "InvestmentTransactionTransfer"
+            ]
         },
-        "internal_handlers.CreateBudgetRequest": {
+        "kuberan_internal_models.OracleRun": {
             "type": "object",
-            "required": [
-                "amount",
-                "category_id",
-                "name",
-                "period",
-                "start_date"
-            ],
             "properties": {
-                "amount": {
-                    "type": "integer"
+                "created_at": {
+                    "type": "string"
                 },
-                "category_id": {
+                "deleted_at": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "duration_ms": {
                     "type": "integer"
                 },
-                "end_date": {
+                "error": {
+                    "description": "why a failed run stopped",
                     "type": "string"
                 },
-                "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 1
+                "error_count": {
+                    "type": "integer"
                 },
-                "period": {
-                    "$ref": "#/definitions/kuberan_internal_models.BudgetPeriod"
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_models.OracleRunError"
+                    }
                 },
-                "start_date": {
+                "id": {
                     "type": "string"
-                }
-            }
-        },
-        "internal_handlers.CreateCashAccountRequest": {
-            "type": "object",
-            "required": [
-                "name"
-            ],
-            "properties": {
-                "currency": {
+                },
+                "prices_recorded": {
+                    "type": "integer"
+                },
+                "securities_fetched": {
+                    "type": "integer"
+                },
+                "snapshots_recorded": {
+                    "type": "integer"
+                },
+                "started_at": {
                     "type": "string"
                 },
-                "description": {
-                    "type": "string",
-                    "maxLength": 500
+                "status": {
+                    "$ref": "#/definitions/kuberan_internal_models.OracleRunStatus"
                 },
-                "initial_balance": {
-                    "type": "integer",
-                    "minimum": 0
+                "updated_at": {
+                    "type": "string"
                 },
-                "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 1
+                "version": {
+                    "type": "string"
                 }
             }
         },
-        "internal_handlers.CreateCategoryRequest": {
+        "kuberan_internal_models.OracleRunError": {
             "type": "object",
-            "required": [
-                "name",
-                "type"
-            ],
             "properties": {
-                "color": {
+                "created_at": {
                     "type": "string"
                 },
-                "description": {
-                    "type": "string",
-                    "maxLength": 500
+                "deleted_at": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
                 },
-                "icon": {
-                    "type": "string",
-                    "maxLength": 50
+                "error": {
+                    "type": "string"
                 },
-                "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 1
+                "id": {
+                    "type": "string"
                 },
-                "parent_id": {
-                    "type": "integer"
+                "security_id": {
+                    "type": "string"
                 },
-                "type": {
-                    "$ref": "#/definitions/kuberan_internal_models.CategoryType"
+                "symbol": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "internal_handlers.CreateCreditCardAccountRequest": {
-            "type": "object",
-            "required": [
-                "name"
+        "kuberan_internal_models.OracleRunStatus": {
+            "type": "string",
+            "enum": [
+                "ok",
+                "partial",
+                "failed"
             ],
+            "x-enum-comments": {
+                "OracleRunStatusFailed": "stopped before finishing",
+                "OracleRunStatusOK": "finished without errors",
+                "OracleRunStatusPartial": "finished, but some symbols failed"
+            },
+            "x-enum-varnames": [
+                "OracleRunStatusOK",
+                "OracleRunStatusPartial",
+                "OracleRunStatusFailed"
+            ]
+        },
+        "kuberan_internal_models.PipelineAPIKey": {
+            "type": "object",
             "properties": {
-                "credit_limit": {
-                    "type": "integer",
-                    "minimum": 0
+                "created_at": {
+                    "type": "string"
                 },
-                "currency": {
+                "deleted_at": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "id": {
                     "type": "string"
                 },
-                "description": {
-                    "type": "string",
-                    "maxLength": 500
+                "last_used_at": {
+                    "type": "string"
                 },
-                "due_date": {
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
                     "type": "string"
                 },
-                "interest_rate": {
-                    "type": "number",
-                    "maximum": 100,
-                    "minimum": 0
+                "revoked_at": {
+                    "type": "string"
                 },
-                "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 1
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "internal_handlers.CreateInvestmentAccountRequest": {
+        "kuberan_internal_models.PortfolioSnapshot": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "account_number": {
-                    "type": "string",
-                    "maxLength": 50
+                "cash_balance": {
+                    "type": "integer"
                 },
-                "broker": {
-                    "type": "string",
-                    "maxLength": 100
+                "debt_balance": {
+                    "type": "integer"
                 },
-                "currency": {
+                "id": {
                     "type": "string"
                 },
-                "description": {
-                    "type": "string",
-                    "maxLength": 500
+                "investment_value": {
+                    "type": "integer"
                 },
-                "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 1
+                "recorded_at": {
+                    "type": "string"
+                },
+                "total_net_worth": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "internal_handlers.CreateSecurityRequest": {
-            "type": "object",
-            "required": [
-                "asset_type",
-                "name",
-                "symbol"
+        "kuberan_internal_models.PriceSource": {
+            "type": "string",
+            "enum": [
+                "yahoo",
+                "coingecko",
+                "bursa",
+                "manual"
+            ],
+            "x-enum-varnames": [
+                "PriceSourceYahoo",
+                "PriceSourceCoinGecko",
+                "PriceSourceBursa",
+                "PriceSourceManual"
+            ]
+        },
+        "kuberan_internal_models.RuleMatchType": {
+            "type": "string",
+            "enum": [
+                "contains",
+                "regex"
             ],
+            "x-enum-varnames": [
+                "RuleMatchTypeContains",
+                "RuleMatchTypeRegex"
+            ]
+        },
+        "kuberan_internal_models.Security": {
+            "type": "object",
             "properties": {
                 "asset_type": {
                     "$ref": "#/definitions/kuberan_internal_models.AssetType"
@@ -3277,551 +9308,971 @@ const docTemplate = `{
                 "coupon_rate": {
                     "type": "number"
                 },
+                "created_at": {
+                    "type": "string"
+                },
                 "currency": {
                     "type": "string"
                 },
+                "deleted_at": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
                 "exchange": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "string"
+                },
                 "maturity_date": {
                     "type": "string"
                 },
                 "name": {
-                    "type": "string",
-                    "maxLength": 200,
-                    "minLength": 1
+                    "type": "string"
                 },
                 "network": {
                     "type": "string"
                 },
+                "preferred_provider": {
+                    "description": "oracle fetches only from this provider when set",
+                    "type": "string"
+                },
                 "property_type": {
                     "type": "string"
                 },
+                "provider_symbol": {
+                    "type": "string"
+                },
                 "symbol": {
-                    "type": "string",
-                    "maxLength": 20,
-                    "minLength": 1
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 },
                 "yield_to_maturity": {
                     "type": "number"
                 }
             }
         },
-        "internal_handlers.CreateTransactionRequest": {
+        "kuberan_internal_models.SecurityPrice": {
             "type": "object",
-            "required": [
-                "account_id",
-                "amount",
-                "type"
-            ],
             "properties": {
-                "account_id": {
-                    "type": "integer"
+                "currency": {
+                    "description": "empty when in the security's currency",
+                    "type": "string"
                 },
-                "amount": {
-                    "type": "integer"
+                "id": {
+                    "type": "string"
                 },
-                "category_id": {
+                "price": {
                     "type": "integer"
                 },
-                "date": {
+                "recorded_at": {
                     "type": "string"
                 },
-                "description": {
-                    "type": "string",
-                    "maxLength": 500
+                "security": {
+                    "$ref": "#/definitions/kuberan_internal_models.Security"
                 },
-                "type": {
-                    "$ref": "#/definitions/kuberan_internal_models.TransactionType"
+                "security_id": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "empty for prices recorded before sources were tracked",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/kuberan_internal_models.PriceSource"
+                        }
+                    ]
                 }
             }
         },
-        "internal_handlers.CreateTransferRequest": {
-            "type": "object",
-            "required": [
-                "amount",
-                "from_account_id",
-                "to_account_id"
+        "kuberan_internal_models.ShareRole": {
+            "type": "string",
+            "enum": [
+                "viewer",
+                "editor"
             ],
+            "x-enum-varnames": [
+                "ShareRoleViewer",
+                "ShareRoleEditor"
+            ]
+        },
+        "kuberan_internal_models.Transaction": {
+            "type": "object",
             "properties": {
+                "account": {
+                    "description": "Relationships",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/kuberan_internal_models.Account"
+                        }
+                    ]
+                },
+                "account_id": {
+                    "type": "string"
+                },
                 "amount": {
                     "type": "integer"
                 },
+                "category": {
+                    "$ref": "#/definitions/kuberan_internal_models.Category"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
                 "date": {
                     "type": "string"
                 },
+                "deleted_at": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
                 "description": {
-                    "type": "string",
-                    "maxLength": 500
+                    "type": "string"
                 },
-                "from_account_id": {
+                "direction": {
+                    "description": "Direction is set at query time relative to the listed accounts, so a\ntransfer reads \"out\" for its source and \"in\" for its destination. Amount\nis always positive. Empty for transfers between two listed accounts.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/kuberan_internal_models.TransactionDirection"
+                        }
+                    ]
+                },
+                "flagged": {
+                    "description": "Flagged is set at query time when the amount reaches the viewing user's\nlarge-transaction threshold. It is not stored.",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_pending": {
+                    "description": "Pending transactions have not yet been applied to account balances.\nThey settle once their date arrives.",
+                    "type": "boolean"
+                },
+                "original_amount": {
+                    "description": "OriginalAmount and OriginalCurrency record what a foreign-currency\ntransaction cost in the currency it was made in, in that currency's minor\nunits. They are for reference only; Amount in the account's currency is\nwhat moves the balance. Both are set or both are nil.",
                     "type": "integer"
                 },
+                "original_currency": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is the reconciliation status; only cleared transactions count\ntowards cleared balances.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/kuberan_internal_models.TransactionStatus"
+                        }
+                    ]
+                },
+                "to_account": {
+                    "$ref": "#/definitions/kuberan_internal_models.Account"
+                },
                 "to_account_id": {
-                    "type": "integer"
-                }
-            }
-        },
-        "internal_handlers.ErrorDetail": {
-            "type": "object",
-            "properties": {
-                "code": {
+                    "description": "For transfers",
                     "type": "string"
                 },
-                "message": {
+                "transfer_group_id": {
+                    "description": "TransferGroupID links the legs of a split transfer, which are deleted together.",
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/kuberan_internal_models.TransactionType"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "internal_handlers.ErrorResponse": {
+        "kuberan_internal_models.TransactionDirection": {
+            "type": "string",
+            "enum": [
+                "in",
+                "out"
+            ],
+            "x-enum-varnames": [
+                "TransactionDirectionIn",
+                "TransactionDirectionOut"
+            ]
+        },
+        "kuberan_internal_models.TransactionStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "cleared"
+            ],
+            "x-enum-varnames": [
+                "TransactionStatusPending",
+                "TransactionStatusCleared"
+            ]
+        },
+        "kuberan_internal_models.TransactionType": {
+            "type": "string",
+            "enum": [
+                "income",
+                "expense",
+                "transfer",
+                "investment"
+            ],
+            "x-enum-varnames": [
+                "TransactionTypeIncome",
+                "TransactionTypeExpense",
+                "TransactionTypeTransfer",
+                "TransactionTypeInvestment"
+            ]
+        },
+        "kuberan_internal_models.WeekStart": {
+            "type": "string",
+            "enum": [
+                "monday",
+                "sunday",
+                "saturday"
+            ],
+            "x-enum-varnames": [
+                "WeekStartMonday",
+                "WeekStartSunday",
+                "WeekStartSaturday"
+            ]
+        },
+        "kuberan_internal_pagination.PageMeta": {
             "type": "object",
             "properties": {
-                "error": {
-                    "$ref": "#/definitions/internal_handlers.ErrorDetail"
+                "warning": {
+                    "type": "string"
                 }
             }
         },
-        "internal_handlers.LoginRequest": {
+        "kuberan_internal_pagination.PageResponse-kuberan_internal_models_Account": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_models.Account"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/kuberan_internal_pagination.PageMeta"
+                },
+                "page": {
+                    "type": "integer"
                 },
-                "password": {
-                    "type": "string"
+                "page_size": {
+                    "type": "integer"
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
                 }
             }
         },
-        "internal_handlers.MessageResponse": {
+        "kuberan_internal_pagination.PageResponse-kuberan_internal_models_CategorizationRule": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string"
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_models.CategorizationRule"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/kuberan_internal_pagination.PageMeta"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
                 }
             }
         },
-        "internal_handlers.RecordBuyRequest": {
+        "kuberan_internal_pagination.PageResponse-kuberan_internal_models_Category": {
             "type": "object",
-            "required": [
-                "date",
-                "price_per_unit",
-                "quantity"
-            ],
             "properties": {
-                "date": {
-                    "type": "string"
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_models.Category"
+                    }
                 },
-                "fee": {
-                    "type": "integer",
-                    "minimum": 0
+                "meta": {
+                    "$ref": "#/definitions/kuberan_internal_pagination.PageMeta"
                 },
-                "notes": {
-                    "type": "string",
-                    "maxLength": 500
+                "page": {
+                    "type": "integer"
                 },
-                "price_per_unit": {
+                "page_size": {
                     "type": "integer"
                 },
-                "quantity": {
-                    "type": "number"
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
                 }
             }
         },
-        "internal_handlers.RecordDividendRequest": {
+        "kuberan_internal_pagination.PageResponse-kuberan_internal_models_Investment": {
             "type": "object",
-            "required": [
-                "amount",
-                "date"
-            ],
             "properties": {
-                "amount": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_models.Investment"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/kuberan_internal_pagination.PageMeta"
+                },
+                "page": {
                     "type": "integer"
                 },
-                "date": {
-                    "type": "string"
+                "page_size": {
+                    "type": "integer"
                 },
-                "dividend_type": {
-                    "type": "string",
-                    "maxLength": 50
+                "total_items": {
+                    "type": "integer"
                 },
-                "notes": {
-                    "type": "string",
-                    "maxLength": 500
+                "total_pages": {
+                    "type": "integer"
                 }
             }
         },
-        "internal_handlers.RecordPriceEntry": {
+        "kuberan_internal_pagination.PageResponse-kuberan_internal_models_InvestmentTransaction": {
             "type": "object",
-            "required": [
-                "price",
-                "recorded_at",
-                "security_id"
-            ],
             "properties": {
-                "price": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_models.InvestmentTransaction"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/kuberan_internal_pagination.PageMeta"
+                },
+                "page": {
                     "type": "integer"
                 },
-                "recorded_at": {
-                    "type": "string"
+                "page_size": {
+                    "type": "integer"
                 },
-                "security_id": {
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
                     "type": "integer"
                 }
             }
         },
-        "internal_handlers.RecordPricesRequest": {
+        "kuberan_internal_pagination.PageResponse-kuberan_internal_models_PortfolioSnapshot": {
             "type": "object",
-            "required": [
-                "prices"
-            ],
             "properties": {
-                "prices": {
+                "data": {
                     "type": "array",
-                    "minItems": 1,
                     "items": {
-                        "$ref": "#/definitions/internal_handlers.RecordPriceEntry"
+                        "$ref": "#/definitions/kuberan_internal_models.PortfolioSnapshot"
                     }
+                },
+                "meta": {
+                    "$ref": "#/definitions/kuberan_internal_pagination.PageMeta"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
                 }
             }
         },
-        "internal_handlers.RecordSellRequest": {
+        "kuberan_internal_pagination.PageResponse-kuberan_internal_models_SecurityPrice": {
             "type": "object",
-            "required": [
-                "date",
-                "price_per_unit",
-                "quantity"
-            ],
             "properties": {
-                "date": {
-                    "type": "string"
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_models.SecurityPrice"
+                    }
                 },
-                "fee": {
-                    "type": "integer",
-                    "minimum": 0
+                "meta": {
+                    "$ref": "#/definitions/kuberan_internal_pagination.PageMeta"
                 },
-                "notes": {
-                    "type": "string",
-                    "maxLength": 500
+                "page": {
+                    "type": "integer"
                 },
-                "price_per_unit": {
+                "page_size": {
                     "type": "integer"
                 },
-                "quantity": {
-                    "type": "number"
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
                 }
             }
         },
-        "internal_handlers.RecordSplitRequest": {
+        "kuberan_internal_pagination.PageResponse-kuberan_internal_models_Transaction": {
             "type": "object",
-            "required": [
-                "date",
-                "split_ratio"
-            ],
             "properties": {
-                "date": {
-                    "type": "string"
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_models.Transaction"
+                    }
                 },
-                "notes": {
-                    "type": "string",
-                    "maxLength": 500
+                "meta": {
+                    "$ref": "#/definitions/kuberan_internal_pagination.PageMeta"
                 },
-                "split_ratio": {
-                    "type": "number"
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
                 }
             }
         },
-        "internal_handlers.RefreshRequest": {
+        "kuberan_internal_pagination.PageResponse-kuberan_internal_services_AccountTimelineEntry": {
             "type": "object",
-            "required": [
-                "refresh_token"
-            ],
             "properties": {
-                "refresh_token": {
-                    "type": "string"
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_services.AccountTimelineEntry"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/kuberan_internal_pagination.PageMeta"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
                 }
             }
         },
-        "internal_handlers.RegisterRequest": {
+        "kuberan_internal_pagination.PageResponse-kuberan_internal_services_BudgetListItem": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
-                    "type": "string",
-                    "maxLength": 255
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_services.BudgetListItem"
+                    }
                 },
-                "first_name": {
-                    "type": "string",
-                    "maxLength": 100
+                "meta": {
+                    "$ref": "#/definitions/kuberan_internal_pagination.PageMeta"
                 },
-                "last_name": {
-                    "type": "string",
-                    "maxLength": 100
+                "page": {
+                    "type": "integer"
                 },
-                "password": {
-                    "type": "string",
-                    "maxLength": 128,
-                    "minLength": 8
+                "page_size": {
+                    "type": "integer"
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
                 }
             }
         },
-        "internal_handlers.TransactionResponse": {
+        "kuberan_internal_pagination.PageResponse-kuberan_internal_services_SecurityWithHolding": {
             "type": "object",
             "properties": {
-                "account_id": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_services.SecurityWithHolding"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/kuberan_internal_pagination.PageMeta"
+                },
+                "page": {
                     "type": "integer"
                 },
-                "amount": {
+                "page_size": {
                     "type": "integer"
                 },
-                "category_id": {
+                "total_items": {
                     "type": "integer"
                 },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "kuberan_internal_services.AccountTimelineEntry": {
+            "type": "object",
+            "properties": {
                 "date": {
                     "type": "string"
                 },
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
+                "investment_transaction": {
+                    "$ref": "#/definitions/kuberan_internal_models.InvestmentTransaction"
                 },
-                "type": {
-                    "$ref": "#/definitions/kuberan_internal_models.TransactionType"
+                "kind": {
+                    "$ref": "#/definitions/kuberan_internal_services.AccountTimelineEntryKind"
                 },
-                "user_id": {
-                    "type": "integer"
+                "transaction": {
+                    "$ref": "#/definitions/kuberan_internal_models.Transaction"
                 }
             }
         },
-        "internal_handlers.UpdateAccountRequest": {
+        "kuberan_internal_services.AccountTimelineEntryKind": {
+            "type": "string",
+            "enum": [
+                "transaction",
+                "investment_transaction"
+            ],
+            "x-enum-comments": {
+                "TimelineEntryInvestmentTransaction": "buy, sell, dividend, split, or transfer of a holding",
+                "TimelineEntryTransaction": "cash transaction, including transfers"
+            },
+            "x-enum-varnames": [
+                "TimelineEntryTransaction",
+                "TimelineEntryInvestmentTransaction"
+            ]
+        },
+        "kuberan_internal_services.BackupAccount": {
             "type": "object",
             "properties": {
                 "account_number": {
-                    "type": "string",
-                    "maxLength": 50
+                    "type": "string"
+                },
+                "allow_negative": {
+                    "description": "AllowNegative is nil in archives written before overdraft protection,\nwhich restore as unprotected",
+                    "type": "boolean"
+                },
+                "archived_at": {
+                    "type": "string"
+                },
+                "archived_balance": {
+                    "type": "integer"
+                },
+                "balance": {
+                    "type": "integer"
                 },
                 "broker": {
-                    "type": "string",
-                    "maxLength": 100
+                    "type": "string"
                 },
                 "credit_limit": {
-                    "type": "integer",
-                    "minimum": 0
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "default_category_id": {
+                    "type": "string"
                 },
                 "description": {
-                    "type": "string",
-                    "maxLength": 500
+                    "type": "string"
                 },
                 "due_date": {
                     "type": "string"
                 },
+                "exclude_from_reports": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
                 "interest_rate": {
-                    "type": "number",
-                    "maximum": 100,
-                    "minimum": 0
+                    "type": "number"
                 },
                 "is_active": {
                     "type": "boolean"
                 },
+                "min_balance": {
+                    "type": "integer"
+                },
                 "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 1
+                    "type": "string"
+                },
+                "opening_date": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/kuberan_internal_models.AccountType"
                 }
             }
         },
-        "internal_handlers.UpdateBudgetRequest": {
+        "kuberan_internal_services.BackupArchive": {
+            "type": "object",
+            "properties": {
+                "checksums": {
+                    "description": "section name -\u003e SHA-256 of its JSON",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "data": {
+                    "$ref": "#/definitions/kuberan_internal_services.BackupData"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "schema_version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "kuberan_internal_services.BackupBudget": {
             "type": "object",
             "properties": {
                 "amount": {
                     "type": "integer"
                 },
+                "category_id": {
+                    "type": "string"
+                },
                 "end_date": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
                 "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 1
+                    "type": "string"
                 },
                 "period": {
                     "$ref": "#/definitions/kuberan_internal_models.BudgetPeriod"
+                },
+                "start_date": {
+                    "type": "string"
                 }
             }
         },
-        "internal_handlers.UpdateCategoryRequest": {
+        "kuberan_internal_services.BackupCategory": {
             "type": "object",
             "properties": {
                 "color": {
                     "type": "string"
                 },
                 "description": {
-                    "type": "string",
-                    "maxLength": 500
+                    "type": "string"
                 },
                 "icon": {
-                    "type": "string",
-                    "maxLength": 50
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
                 },
                 "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 1
+                    "type": "string"
                 },
                 "parent_id": {
-                    "type": "integer"
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/kuberan_internal_models.CategoryType"
                 }
             }
         },
-        "internal_handlers.UpdateTransactionRequest": {
+        "kuberan_internal_services.BackupData": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_services.BackupAccount"
+                    }
+                },
+                "budgets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_services.BackupBudget"
+                    }
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_services.BackupCategory"
+                    }
+                },
+                "investment_transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_services.BackupInvestmentTransaction"
+                    }
+                },
+                "investments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_services.BackupInvestment"
+                    }
+                },
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_services.BackupTransaction"
+                    }
+                }
+            }
+        },
+        "kuberan_internal_services.BackupInvestment": {
             "type": "object",
             "properties": {
                 "account_id": {
+                    "type": "string"
+                },
+                "cost_basis": {
                     "type": "integer"
                 },
-                "amount": {
+                "id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "realized_gain_loss": {
                     "type": "integer"
                 },
-                "category_id": {
+                "security_exchange": {
+                    "type": "string"
+                },
+                "security_id": {
+                    "type": "string"
+                },
+                "security_symbol": {
+                    "type": "string"
+                },
+                "target_price": {
                     "type": "integer"
                 },
+                "wallet_address": {
+                    "type": "string"
+                }
+            }
+        },
+        "kuberan_internal_services.BackupInvestmentTransaction": {
+            "type": "object",
+            "properties": {
+                "cash_transaction_id": {
+                    "type": "string"
+                },
+                "cost_basis_method": {
+                    "$ref": "#/definitions/kuberan_internal_models.CostBasisMethod"
+                },
                 "date": {
                     "type": "string"
                 },
-                "description": {
-                    "type": "string",
-                    "maxLength": 500
+                "dividend_type": {
+                    "type": "string"
+                },
+                "external_ref": {
+                    "type": "string"
+                },
+                "fee": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "investment_id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "price_per_unit": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "realized_gain_loss": {
+                    "type": "integer"
+                },
+                "split_ratio": {
+                    "type": "number"
+                },
+                "split_remainder": {
+                    "type": "number"
+                },
+                "total_amount": {
+                    "type": "integer"
                 },
                 "type": {
-                    "$ref": "#/definitions/kuberan_internal_models.TransactionType"
+                    "$ref": "#/definitions/kuberan_internal_models.InvestmentTransactionType"
                 }
             }
         },
-        "internal_handlers.UserResponse": {
+        "kuberan_internal_services.BackupTransaction": {
             "type": "object",
             "properties": {
-                "email": {
+                "account_id": {
                     "type": "string"
                 },
-                "first_name": {
+                "amount": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
                     "type": "string"
                 },
                 "id": {
+                    "type": "string"
+                },
+                "is_pending": {
+                    "type": "boolean"
+                },
+                "original_amount": {
                     "type": "integer"
                 },
-                "last_name": {
+                "original_currency": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/kuberan_internal_models.TransactionStatus"
+                },
+                "to_account_id": {
+                    "type": "string"
+                },
+                "transfer_group_id": {
                     "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/kuberan_internal_models.TransactionType"
                 }
             }
         },
-        "kuberan_internal_models.Account": {
+        "kuberan_internal_services.BenchmarkComparison": {
             "type": "object",
             "properties": {
-                "account_number": {
+                "benchmark_end": {
+                    "type": "integer"
+                },
+                "benchmark_return": {
+                    "type": "number"
+                },
+                "benchmark_security_id": {
                     "type": "string"
                 },
-                "balance": {
+                "benchmark_start": {
                     "type": "integer"
                 },
-                "broker": {
-                    "description": "For investment accounts",
+                "benchmark_symbol": {
                     "type": "string"
                 },
-                "created_at": {
+                "difference": {
+                    "type": "number"
+                },
+                "from": {
                     "type": "string"
                 },
-                "credit_limit": {
+                "portfolio_end": {
                     "type": "integer"
                 },
-                "currency": {
-                    "type": "string"
+                "portfolio_return": {
+                    "type": "number"
                 },
-                "deleted_at": {
-                    "$ref": "#/definitions/gorm.DeletedAt"
+                "portfolio_start": {
+                    "type": "integer"
                 },
-                "description": {
+                "to": {
                     "type": "string"
-                },
-                "due_date": {
+                }
+            }
+        },
+        "kuberan_internal_services.BudgetBurndown": {
+            "type": "object",
+            "properties": {
+                "budget_id": {
                     "type": "string"
                 },
-                "id": {
+                "budgeted": {
                     "type": "integer"
                 },
-                "interest_rate": {
-                    "description": "For debt and credit card accounts",
-                    "type": "number"
+                "days_elapsed": {
+                    "description": "including today",
+                    "type": "integer"
                 },
-                "investments": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/kuberan_internal_models.Investment"
-                    }
+                "days_in_period": {
+                    "type": "integer"
                 },
-                "is_active": {
-                    "type": "boolean"
+                "period_end": {
+                    "type": "string"
                 },
-                "name": {
+                "period_start": {
                     "type": "string"
                 },
-                "transactions": {
-                    "description": "Relationships",
+                "projected_spend": {
+                    "description": "spent / days elapsed * days in period",
+                    "type": "integer"
+                },
+                "series": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/kuberan_internal_models.Transaction"
+                        "$ref": "#/definitions/kuberan_internal_services.BudgetBurndownPoint"
                     }
                 },
-                "type": {
-                    "$ref": "#/definitions/kuberan_internal_models.AccountType"
+                "spent": {
+                    "type": "integer"
+                }
+            }
+        },
+        "kuberan_internal_services.BudgetBurndownPoint": {
+            "type": "object",
+            "properties": {
+                "cumulative": {
+                    "description": "spent from the period start through this day",
+                    "type": "integer"
                 },
-                "updated_at": {
+                "date": {
+                    "description": "midnight starting the day",
                     "type": "string"
                 },
-                "user_id": {
+                "spent": {
                     "type": "integer"
                 }
             }
         },
-        "kuberan_internal_models.AccountType": {
-            "type": "string",
-            "enum": [
-                "cash",
-                "investment",
-                "debt",
-                "credit_card"
-            ],
-            "x-enum-varnames": [
-                "AccountTypeCash",
-                "AccountTypeInvestment",
-                "AccountTypeDebt",
-                "AccountTypeCreditCard"
-            ]
-        },
-        "kuberan_internal_models.AssetType": {
-            "type": "string",
-            "enum": [
-                "stock",
-                "etf",
-                "bond",
-                "crypto",
-                "reit"
-            ],
-            "x-enum-varnames": [
-                "AssetTypeStock",
-                "AssetTypeETF",
-                "AssetTypeBond",
-                "AssetTypeCrypto",
-                "AssetTypeREIT"
-            ]
-        },
-        "kuberan_internal_models.Budget": {
+        "kuberan_internal_services.BudgetListItem": {
             "type": "object",
             "properties": {
                 "amount": {
@@ -3836,7 +10287,7 @@ const docTemplate = `{
                     ]
                 },
                 "category_id": {
-                    "type": "integer"
+                    "type": "string"
                 },
                 "created_at": {
                     "type": "string"
@@ -3848,7 +10299,7 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "id": {
-                    "type": "integer"
+                    "type": "string"
                 },
                 "is_active": {
                     "type": "boolean"
@@ -3856,9 +10307,18 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "percentage": {
+                    "type": "number"
+                },
                 "period": {
                     "$ref": "#/definitions/kuberan_internal_models.BudgetPeriod"
                 },
+                "remaining": {
+                    "type": "integer"
+                },
+                "spent": {
+                    "type": "integer"
+                },
                 "start_date": {
                     "type": "string"
                 },
@@ -3866,674 +10326,722 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "kuberan_internal_services.BudgetPace": {
+            "type": "object",
+            "properties": {
+                "days_elapsed": {
+                    "description": "including today; 0 before the period starts",
+                    "type": "integer"
+                },
+                "days_in_period": {
                     "type": "integer"
+                },
+                "projected_spend": {
+                    "description": "spent / days elapsed * days in period",
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/kuberan_internal_services.BudgetPaceStatus"
                 }
             }
         },
-        "kuberan_internal_models.BudgetPeriod": {
+        "kuberan_internal_services.BudgetPaceStatus": {
             "type": "string",
             "enum": [
-                "monthly",
-                "yearly"
+                "on_track",
+                "at_risk",
+                "over"
             ],
+            "x-enum-comments": {
+                "BudgetPaceAtRisk": "projected spend over the budget",
+                "BudgetPaceOnTrack": "projected spend within the budget",
+                "BudgetPaceOver": "already spent more than the budget"
+            },
             "x-enum-varnames": [
-                "BudgetPeriodMonthly",
-                "BudgetPeriodYearly"
+                "BudgetPaceOnTrack",
+                "BudgetPaceAtRisk",
+                "BudgetPaceOver"
             ]
         },
-        "kuberan_internal_models.Category": {
+        "kuberan_internal_services.BudgetProgress": {
             "type": "object",
             "properties": {
-                "budgets": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/kuberan_internal_models.Budget"
-                    }
+                "budget_id": {
+                    "type": "string"
                 },
-                "children": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/kuberan_internal_models.Category"
-                    }
+                "budgeted": {
+                    "type": "integer"
                 },
-                "color": {
+                "pace": {
+                    "description": "spend so far extrapolated to the whole period",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/kuberan_internal_services.BudgetPace"
+                        }
+                    ]
+                },
+                "percentage": {
+                    "type": "number"
+                },
+                "period_end": {
+                    "description": "last instant spending is counted to",
                     "type": "string"
                 },
-                "created_at": {
+                "period_start": {
+                    "description": "first instant spending is counted from",
                     "type": "string"
                 },
-                "deleted_at": {
-                    "$ref": "#/definitions/gorm.DeletedAt"
+                "remaining": {
+                    "type": "integer"
                 },
-                "description": {
+                "spent": {
+                    "type": "integer"
+                }
+            }
+        },
+        "kuberan_internal_services.BulkDeletePreview": {
+            "type": "object",
+            "properties": {
+                "confirmation_token": {
                     "type": "string"
                 },
-                "icon": {
+                "count": {
+                    "type": "integer"
+                },
+                "expires_at": {
                     "type": "string"
                 },
-                "id": {
+                "total_amount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "kuberan_internal_services.EventDispatchResult": {
+            "type": "object",
+            "properties": {
+                "delivered": {
                     "type": "integer"
                 },
-                "name": {
+                "failed": {
+                    "type": "integer"
+                }
+            }
+        },
+        "kuberan_internal_services.ExchangeRateQuote": {
+            "type": "object",
+            "properties": {
+                "as_of": {
                     "type": "string"
                 },
-                "parent": {
-                    "description": "Relationships",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/kuberan_internal_models.Category"
-                        }
-                    ]
+                "from": {
+                    "type": "string"
                 },
-                "parent_id": {
-                    "type": "integer"
+                "rate": {
+                    "type": "number"
                 },
-                "transactions": {
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "kuberan_internal_services.GroupedAccounts": {
+            "type": "object",
+            "properties": {
+                "accounts": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/kuberan_internal_models.Transaction"
+                        "$ref": "#/definitions/kuberan_internal_models.Account"
                     }
                 },
-                "type": {
-                    "$ref": "#/definitions/kuberan_internal_models.CategoryType"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "user_id": {
-                    "type": "integer"
+                "group": {
+                    "$ref": "#/definitions/kuberan_internal_models.AccountGroup"
                 }
             }
         },
-        "kuberan_internal_models.CategoryType": {
+        "kuberan_internal_services.HoldingPeriod": {
             "type": "string",
             "enum": [
-                "income",
-                "expense"
+                "short",
+                "long"
             ],
+            "x-enum-comments": {
+                "HoldingPeriodLong": "held more than one year",
+                "HoldingPeriodShort": "held one year or less"
+            },
             "x-enum-varnames": [
-                "CategoryTypeIncome",
-                "CategoryTypeExpense"
+                "HoldingPeriodShort",
+                "HoldingPeriodLong"
             ]
         },
-        "kuberan_internal_models.Investment": {
+        "kuberan_internal_services.PortfolioHolding": {
             "type": "object",
             "properties": {
-                "account": {
-                    "$ref": "#/definitions/kuberan_internal_models.Account"
+                "asset_type": {
+                    "$ref": "#/definitions/kuberan_internal_models.AssetType"
                 },
-                "account_id": {
+                "converted_value": {
+                    "description": "In the portfolio currency; nil when unconverted",
                     "type": "integer"
                 },
                 "cost_basis": {
+                    "description": "In Currency",
                     "type": "integer"
                 },
-                "created_at": {
+                "currency": {
+                    "description": "The security's currency",
                     "type": "string"
                 },
-                "current_price": {
-                    "description": "Populated at query time from security_prices",
-                    "type": "integer"
-                },
-                "deleted_at": {
-                    "$ref": "#/definitions/gorm.DeletedAt"
+                "exchange_rate": {
+                    "description": "ValueCurrency to the portfolio currency; nil when unknown",
+                    "type": "number"
                 },
-                "id": {
-                    "type": "integer"
+                "investment_id": {
+                    "type": "string"
                 },
                 "quantity": {
                     "type": "number"
                 },
-                "security": {
-                    "description": "Relationships",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/kuberan_internal_models.Security"
-                        }
-                    ]
-                },
                 "security_id": {
-                    "type": "integer"
+                    "type": "string"
                 },
-                "transactions": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/kuberan_internal_models.InvestmentTransaction"
-                    }
+                "sparkline": {
+                    "$ref": "#/definitions/kuberan_internal_services.Sparkline"
                 },
-                "updated_at": {
+                "symbol": {
                     "type": "string"
                 },
-                "wallet_address": {
+                "value": {
+                    "description": "In ValueCurrency",
+                    "type": "integer"
+                },
+                "value_currency": {
+                    "description": "The latest price's currency; Currency unless the price was converted",
                     "type": "string"
                 }
             }
         },
-        "kuberan_internal_models.InvestmentTransaction": {
+        "kuberan_internal_services.PortfolioSummary": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "currency": {
                     "type": "string"
                 },
-                "date": {
-                    "type": "string"
+                "gain_loss_pct": {
+                    "type": "number"
                 },
-                "deleted_at": {
-                    "$ref": "#/definitions/gorm.DeletedAt"
+                "holdings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_services.PortfolioHolding"
+                    }
                 },
-                "dividend_type": {
-                    "description": "For dividends",
-                    "type": "string"
+                "holdings_by_type": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/kuberan_internal_services.TypeSummary"
+                    }
                 },
-                "fee": {
+                "total_cost_basis": {
                     "type": "integer"
                 },
-                "id": {
+                "total_dividends": {
                     "type": "integer"
                 },
-                "investment": {
-                    "description": "Relationships",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/kuberan_internal_models.Investment"
-                        }
-                    ]
-                },
-                "investment_id": {
+                "total_gain_loss": {
                     "type": "integer"
                 },
-                "notes": {
-                    "type": "string"
-                },
-                "price_per_unit": {
+                "total_realized_gain_loss": {
                     "type": "integer"
                 },
-                "quantity": {
-                    "type": "number"
-                },
-                "split_ratio": {
-                    "description": "For splits",
-                    "type": "number"
-                },
-                "total_amount": {
+                "total_return": {
+                    "description": "Realized + unrealized gain/loss + dividends",
                     "type": "integer"
                 },
-                "type": {
-                    "$ref": "#/definitions/kuberan_internal_models.InvestmentTransactionType"
+                "total_value": {
+                    "type": "integer"
                 },
-                "updated_at": {
-                    "type": "string"
+                "unconverted_currencies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "kuberan_internal_models.InvestmentTransactionType": {
-            "type": "string",
-            "enum": [
-                "buy",
-                "sell",
-                "dividend",
-                "split",
-                "transfer"
-            ],
-            "x-enum-varnames": [
-                "InvestmentTransactionBuy",
-                "InvestmentTransactionSell",
-                "InvestmentTransactionDividend",
-                "InvestmentTransactionSplit",
-                "InvestmentTransactionTransfer"
-            ]
+        "kuberan_internal_services.PriceCorrectionResult": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "description": "nil when the price was deleted",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/kuberan_internal_models.SecurityPrice"
+                        }
+                    ]
+                },
+                "previous": {
+                    "$ref": "#/definitions/kuberan_internal_models.SecurityPrice"
+                }
+            }
         },
-        "kuberan_internal_models.PortfolioSnapshot": {
+        "kuberan_internal_services.RecordPricesResult": {
             "type": "object",
             "properties": {
-                "cash_balance": {
+                "prices_recorded": {
                     "type": "integer"
                 },
-                "debt_balance": {
-                    "type": "integer"
+                "rejected": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_services.RejectedPrice"
+                    }
+                }
+            }
+        },
+        "kuberan_internal_services.RejectedPrice": {
+            "type": "object",
+            "properties": {
+                "deviation_pct": {
+                    "type": "number"
                 },
-                "id": {
+                "last_price": {
                     "type": "integer"
                 },
-                "investment_value": {
+                "price": {
                     "type": "integer"
                 },
-                "recorded_at": {
+                "reason": {
                     "type": "string"
                 },
-                "total_net_worth": {
-                    "type": "integer"
+                "recorded_at": {
+                    "type": "string"
                 },
-                "user_id": {
-                    "type": "integer"
+                "security_id": {
+                    "type": "string"
                 }
             }
         },
-        "kuberan_internal_models.Security": {
+        "kuberan_internal_services.RestoreGroupProgress": {
             "type": "object",
             "properties": {
-                "asset_type": {
-                    "$ref": "#/definitions/kuberan_internal_models.AssetType"
-                },
-                "coupon_rate": {
-                    "type": "number"
+                "balances_adjusted": {
+                    "description": "accounts whose replayed balance differed from the archive's",
+                    "type": "integer"
                 },
-                "created_at": {
+                "error": {
                     "type": "string"
                 },
-                "currency": {
+                "group": {
                     "type": "string"
                 },
-                "deleted_at": {
-                    "$ref": "#/definitions/gorm.DeletedAt"
+                "restored": {
+                    "type": "integer"
                 },
-                "exchange": {
+                "status": {
+                    "$ref": "#/definitions/kuberan_internal_services.RestoreStatus"
+                }
+            }
+        },
+        "kuberan_internal_services.RestoreReport": {
+            "type": "object",
+            "properties": {
+                "archive_id": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "completed": {
+                    "type": "boolean"
                 },
-                "maturity_date": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_services.RestoreGroupProgress"
+                    }
+                }
+            }
+        },
+        "kuberan_internal_services.RestoreStatus": {
+            "type": "string",
+            "enum": [
+                "done",
+                "skipped",
+                "failed",
+                "pending"
+            ],
+            "x-enum-comments": {
+                "RestoreStatusDone": "restored by this call",
+                "RestoreStatusFailed": "rolled back; Error says why",
+                "RestoreStatusPending": "not attempted because an earlier group failed",
+                "RestoreStatusSkipped": "restored by an earlier call with the same archive"
+            },
+            "x-enum-varnames": [
+                "RestoreStatusDone",
+                "RestoreStatusSkipped",
+                "RestoreStatusFailed",
+                "RestoreStatusPending"
+            ]
+        },
+        "kuberan_internal_services.SecurityBatchItem": {
+            "type": "object",
+            "properties": {
+                "error": {
                     "type": "string"
                 },
-                "name": {
+                "exchange": {
                     "type": "string"
                 },
-                "network": {
-                    "type": "string"
+                "index": {
+                    "type": "integer"
                 },
-                "property_type": {
+                "security_id": {
                     "type": "string"
                 },
-                "symbol": {
-                    "type": "string"
+                "status": {
+                    "$ref": "#/definitions/kuberan_internal_services.SecurityBatchStatus"
                 },
-                "updated_at": {
+                "symbol": {
                     "type": "string"
-                },
-                "yield_to_maturity": {
-                    "type": "number"
                 }
             }
         },
-        "kuberan_internal_models.SecurityPrice": {
+        "kuberan_internal_services.SecurityBatchResult": {
             "type": "object",
             "properties": {
-                "id": {
+                "created": {
                     "type": "integer"
                 },
-                "price": {
+                "failed": {
                     "type": "integer"
                 },
-                "recorded_at": {
-                    "type": "string"
-                },
-                "security": {
-                    "$ref": "#/definitions/kuberan_internal_models.Security"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_services.SecurityBatchItem"
+                    }
                 },
-                "security_id": {
+                "updated": {
                     "type": "integer"
                 }
             }
         },
-        "kuberan_internal_models.Transaction": {
+        "kuberan_internal_services.SecurityBatchStatus": {
+            "type": "string",
+            "enum": [
+                "created",
+                "updated",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "SecurityBatchCreated",
+                "SecurityBatchUpdated",
+                "SecurityBatchFailed"
+            ]
+        },
+        "kuberan_internal_services.SecurityHolding": {
             "type": "object",
             "properties": {
-                "account": {
-                    "description": "Relationships",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/kuberan_internal_models.Account"
-                        }
-                    ]
+                "cost_basis": {
+                    "type": "integer"
                 },
-                "account_id": {
+                "current_value": {
                     "type": "integer"
                 },
-                "amount": {
+                "gain_loss": {
                     "type": "integer"
                 },
-                "category": {
-                    "$ref": "#/definitions/kuberan_internal_models.Category"
+                "quantity": {
+                    "type": "number"
+                }
+            }
+        },
+        "kuberan_internal_services.SecurityWithHolding": {
+            "type": "object",
+            "properties": {
+                "asset_type": {
+                    "$ref": "#/definitions/kuberan_internal_models.AssetType"
                 },
-                "category_id": {
-                    "type": "integer"
+                "coupon_rate": {
+                    "type": "number"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "date": {
+                "currency": {
                     "type": "string"
                 },
                 "deleted_at": {
                     "$ref": "#/definitions/gorm.DeletedAt"
                 },
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "to_account": {
-                    "$ref": "#/definitions/kuberan_internal_models.Account"
-                },
-                "to_account_id": {
-                    "description": "For transfers",
-                    "type": "integer"
-                },
-                "type": {
-                    "$ref": "#/definitions/kuberan_internal_models.TransactionType"
-                },
-                "updated_at": {
+                "exchange": {
                     "type": "string"
                 },
-                "user_id": {
-                    "type": "integer"
-                }
-            }
-        },
-        "kuberan_internal_models.TransactionType": {
-            "type": "string",
-            "enum": [
-                "income",
-                "expense",
-                "transfer",
-                "investment"
-            ],
-            "x-enum-varnames": [
-                "TransactionTypeIncome",
-                "TransactionTypeExpense",
-                "TransactionTypeTransfer",
-                "TransactionTypeInvestment"
-            ]
-        },
-        "kuberan_internal_pagination.PageResponse-kuberan_internal_models_Account": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/kuberan_internal_models.Account"
-                    }
-                },
-                "page": {
-                    "type": "integer"
+                "holding": {
+                    "$ref": "#/definitions/kuberan_internal_services.SecurityHolding"
                 },
-                "page_size": {
-                    "type": "integer"
+                "id": {
+                    "type": "string"
                 },
-                "total_items": {
-                    "type": "integer"
+                "maturity_date": {
+                    "type": "string"
                 },
-                "total_pages": {
-                    "type": "integer"
-                }
-            }
-        },
-        "kuberan_internal_pagination.PageResponse-kuberan_internal_models_Budget": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/kuberan_internal_models.Budget"
-                    }
+                "name": {
+                    "type": "string"
                 },
-                "page": {
-                    "type": "integer"
+                "network": {
+                    "type": "string"
                 },
-                "page_size": {
-                    "type": "integer"
+                "preferred_provider": {
+                    "description": "oracle fetches only from this provider when set",
+                    "type": "string"
                 },
-                "total_items": {
-                    "type": "integer"
+                "property_type": {
+                    "type": "string"
                 },
-                "total_pages": {
-                    "type": "integer"
+                "provider_symbol": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "yield_to_maturity": {
+                    "type": "number"
                 }
             }
         },
-        "kuberan_internal_pagination.PageResponse-kuberan_internal_models_Category": {
+        "kuberan_internal_services.Sparkline": {
             "type": "object",
             "properties": {
-                "data": {
+                "change_pct": {
+                    "description": "From the first close to the last; nil with fewer than two closes",
+                    "type": "number"
+                },
+                "closes": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/kuberan_internal_models.Category"
+                        "$ref": "#/definitions/kuberan_internal_services.SparklineClose"
                     }
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "page_size": {
-                    "type": "integer"
-                },
-                "total_items": {
-                    "type": "integer"
-                },
-                "total_pages": {
-                    "type": "integer"
                 }
             }
         },
-        "kuberan_internal_pagination.PageResponse-kuberan_internal_models_Investment": {
+        "kuberan_internal_services.SparklineClose": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/kuberan_internal_models.Investment"
-                    }
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "page_size": {
-                    "type": "integer"
-                },
-                "total_items": {
-                    "type": "integer"
+                "date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
                 },
-                "total_pages": {
+                "price": {
                     "type": "integer"
                 }
             }
         },
-        "kuberan_internal_pagination.PageResponse-kuberan_internal_models_InvestmentTransaction": {
+        "kuberan_internal_services.SpendingByAccount": {
             "type": "object",
             "properties": {
-                "data": {
+                "from_date": {
+                    "type": "string"
+                },
+                "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/kuberan_internal_models.InvestmentTransaction"
+                        "$ref": "#/definitions/kuberan_internal_services.SpendingByAccountItem"
                     }
                 },
-                "page": {
-                    "type": "integer"
-                },
-                "page_size": {
-                    "type": "integer"
-                },
-                "total_items": {
-                    "type": "integer"
+                "to_date": {
+                    "type": "string"
                 },
-                "total_pages": {
+                "total_spent": {
                     "type": "integer"
                 }
             }
         },
-        "kuberan_internal_pagination.PageResponse-kuberan_internal_models_PortfolioSnapshot": {
+        "kuberan_internal_services.SpendingByAccountItem": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/kuberan_internal_models.PortfolioSnapshot"
-                    }
+                "account_id": {
+                    "type": "string"
                 },
-                "page": {
-                    "type": "integer"
+                "account_name": {
+                    "type": "string"
                 },
-                "page_size": {
+                "count": {
+                    "description": "number of expenses",
                     "type": "integer"
                 },
-                "total_items": {
-                    "type": "integer"
+                "is_active": {
+                    "type": "boolean"
                 },
-                "total_pages": {
+                "total": {
+                    "description": "cents",
                     "type": "integer"
                 }
             }
         },
-        "kuberan_internal_pagination.PageResponse-kuberan_internal_models_Security": {
+        "kuberan_internal_services.SpendingByCategory": {
             "type": "object",
             "properties": {
-                "data": {
+                "from_date": {
+                    "type": "string"
+                },
+                "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/kuberan_internal_models.Security"
+                        "$ref": "#/definitions/kuberan_internal_services.SpendingByCategoryItem"
                     }
                 },
-                "page": {
-                    "type": "integer"
-                },
-                "page_size": {
-                    "type": "integer"
+                "mode": {
+                    "$ref": "#/definitions/kuberan_internal_services.SpendingMode"
                 },
-                "total_items": {
-                    "type": "integer"
+                "to_date": {
+                    "type": "string"
                 },
-                "total_pages": {
+                "total_spent": {
                     "type": "integer"
                 }
             }
         },
-        "kuberan_internal_pagination.PageResponse-kuberan_internal_models_SecurityPrice": {
+        "kuberan_internal_services.SpendingByCategoryItem": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/kuberan_internal_models.SecurityPrice"
-                    }
+                "category_color": {
+                    "type": "string"
                 },
-                "page": {
-                    "type": "integer"
+                "category_icon": {
+                    "type": "string"
                 },
-                "page_size": {
-                    "type": "integer"
+                "category_id": {
+                    "type": "string"
                 },
-                "total_items": {
-                    "type": "integer"
+                "category_name": {
+                    "type": "string"
                 },
-                "total_pages": {
+                "total": {
                     "type": "integer"
                 }
             }
         },
-        "kuberan_internal_pagination.PageResponse-kuberan_internal_models_Transaction": {
+        "kuberan_internal_services.SpendingByCurrency": {
             "type": "object",
             "properties": {
-                "data": {
+                "from_date": {
+                    "type": "string"
+                },
+                "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/kuberan_internal_models.Transaction"
+                        "$ref": "#/definitions/kuberan_internal_services.SpendingByCurrencyItem"
                     }
                 },
-                "page": {
-                    "type": "integer"
-                },
-                "page_size": {
-                    "type": "integer"
-                },
-                "total_items": {
-                    "type": "integer"
+                "to_date": {
+                    "type": "string"
                 },
-                "total_pages": {
+                "total_spent": {
                     "type": "integer"
                 }
             }
         },
-        "kuberan_internal_services.BudgetProgress": {
+        "kuberan_internal_services.SpendingByCurrencyItem": {
             "type": "object",
             "properties": {
-                "budget_id": {
-                    "type": "integer"
-                },
-                "budgeted": {
+                "count": {
+                    "description": "number of expenses",
                     "type": "integer"
                 },
-                "percentage": {
-                    "type": "number"
+                "currency": {
+                    "type": "string"
                 },
-                "remaining": {
+                "original_total": {
+                    "description": "minor units of Currency",
                     "type": "integer"
                 },
-                "spent": {
+                "total": {
+                    "description": "cents as booked in the accounts' currencies",
                     "type": "integer"
                 }
             }
         },
-        "kuberan_internal_services.PortfolioSummary": {
+        "kuberan_internal_services.SpendingMode": {
+            "type": "string",
+            "enum": [
+                "expense",
+                "income"
+            ],
+            "x-enum-varnames": [
+                "SpendingModeExpense",
+                "SpendingModeIncome"
+            ]
+        },
+        "kuberan_internal_services.TaxReport": {
             "type": "object",
             "properties": {
-                "gain_loss_pct": {
-                    "type": "number"
+                "long_term_gain": {
+                    "type": "integer"
                 },
-                "holdings_by_type": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "$ref": "#/definitions/kuberan_internal_services.TypeSummary"
+                "sales": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kuberan_internal_services.TaxReportSale"
                     }
                 },
+                "short_term_gain": {
+                    "type": "integer"
+                },
                 "total_cost_basis": {
                     "type": "integer"
                 },
-                "total_gain_loss": {
+                "total_gain": {
                     "type": "integer"
                 },
-                "total_value": {
+                "total_proceeds": {
+                    "type": "integer"
+                },
+                "year": {
                     "type": "integer"
                 }
             }
         },
-        "kuberan_internal_services.SpendingByCategory": {
+        "kuberan_internal_services.TaxReportSale": {
             "type": "object",
             "properties": {
-                "from_date": {
+                "acquired_at": {
+                    "description": "earliest matched buy date",
                     "type": "string"
                 },
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/kuberan_internal_services.SpendingByCategoryItem"
-                    }
+                "cost_basis": {
+                    "type": "integer"
                 },
-                "to_date": {
+                "currency": {
                     "type": "string"
                 },
-                "total_spent": {
-                    "type": "integer"
-                }
-            }
-        },
-        "kuberan_internal_services.SpendingByCategoryItem": {
-            "type": "object",
-            "properties": {
-                "category_color": {
-                    "type": "string"
+                "holding_period": {
+                    "$ref": "#/definitions/kuberan_internal_services.HoldingPeriod"
                 },
-                "category_icon": {
+                "investment_id": {
                     "type": "string"
                 },
-                "category_id": {
+                "proceeds": {
                     "type": "integer"
                 },
-                "category_name": {
-                    "type": "string"
+                "quantity": {
+                    "type": "number"
                 },
-                "total": {
+                "realized_gain": {
                     "type": "integer"
+                },
+                "sold_at": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "type": "string"
                 }
             }
         },
@@ -4550,6 +11058,12 @@ const docTemplate = `{
         }
     },
     "securityDefinitions": {
+        "AdminKeyAuth": {
+            "description": "Admin key for managing pipeline API keys.",
+            "type": "apiKey",
+            "name": "X-Admin-Key",
+            "in": "header"
+        },
         "ApiKeyAuth": {
             "description": "Pipeline API key for service-to-service authentication.",
             "type": "apiKey",
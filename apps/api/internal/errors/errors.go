@@ -1,12 +1,64 @@
 // Package errors provides custom error types for the Kuberan API.
 // All service-layer errors should use AppError to ensure consistent,
 // secure error responses that never leak internal details to clients.
+// Every error code is registered in a catalog, which decides the HTTP status
+// of responses and is served to clients so they can enumerate the codes.
 package errors
 
-import "net/http"
+import (
+	"net/http"
+	"sort"
+)
+
+// CatalogEntry describes one registered error code: the HTTP status it is
+// answered with, its default message, and a hint on what a client can do.
+type CatalogEntry struct {
+	Code    string `json:"code"`
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	Hint    string `json:"hint"`
+}
+
+var catalog = map[string]CatalogEntry{}
+
+// register adds an error code to the catalog and returns its sentinel. Codes
+// are registered once, from the package's variable declarations.
+func register(code string, status int, message, hint string) *AppError {
+	if _, ok := catalog[code]; ok {
+		panic("errors: duplicate error code " + code)
+	}
+	catalog[code] = CatalogEntry{Code: code, Status: status, Message: message, Hint: hint}
+	return &AppError{Code: code, Message: message, StatusCode: status}
+}
+
+// Catalog returns every registered error code, sorted by code.
+func Catalog() []CatalogEntry {
+	entries := make([]CatalogEntry, 0, len(catalog))
+	for _, entry := range catalog {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Code < entries[j].Code })
+	return entries
+}
+
+// Lookup returns the catalog entry of code.
+func Lookup(code string) (CatalogEntry, bool) {
+	entry, ok := catalog[code]
+	return entry, ok
+}
+
+// Status returns the HTTP status registered for code, or 500 for a code that
+// is not in the catalog.
+func Status(code string) int {
+	if entry, ok := catalog[code]; ok {
+		return entry.Status
+	}
+	return http.StatusInternalServerError
+}
 
 // AppError represents a structured application error with an error code,
 // human-readable message, HTTP status code, and optional internal error.
+// The status code is the one registered for the code in the catalog.
 type AppError struct {
 	Code       string `json:"code"`
 	Message    string `json:"message"`
@@ -42,61 +94,175 @@ func WithMessage(sentinel *AppError, message string) *AppError {
 
 // Authentication & authorization errors.
 var (
-	ErrUnauthorized       = &AppError{Code: "UNAUTHORIZED", Message: "Authentication required", StatusCode: http.StatusUnauthorized}
-	ErrInvalidCredentials = &AppError{Code: "INVALID_CREDENTIALS", Message: "Invalid email or password", StatusCode: http.StatusUnauthorized}
-	ErrForbidden          = &AppError{Code: "FORBIDDEN", Message: "Access denied", StatusCode: http.StatusForbidden}
-	ErrAccountLocked      = &AppError{Code: "ACCOUNT_LOCKED", Message: "Account is temporarily locked", StatusCode: http.StatusLocked}
+	ErrUnauthorized = register("UNAUTHORIZED", http.StatusUnauthorized, "Authentication required",
+		"Send a valid access token in the Authorization header; refresh it if it expired.")
+	ErrInvalidCredentials = register("INVALID_CREDENTIALS", http.StatusUnauthorized, "Invalid email or password",
+		"Check the email and password; repeated failures lock the account.")
+	ErrForbidden = register("FORBIDDEN", http.StatusForbidden, "Access denied",
+		"The caller is authenticated but may not act on this resource.")
+	ErrAccountLocked = register("ACCOUNT_LOCKED", http.StatusLocked, "Account is temporarily locked",
+		"Too many failed logins; wait for the lockout to expire before retrying.")
 )
 
 // General errors.
 var (
-	ErrInvalidInput   = &AppError{Code: "INVALID_INPUT", Message: "Invalid input", StatusCode: http.StatusBadRequest}
-	ErrNotFound       = &AppError{Code: "NOT_FOUND", Message: "Resource not found", StatusCode: http.StatusNotFound}
-	ErrInternalServer = &AppError{Code: "INTERNAL_ERROR", Message: "An internal error occurred", StatusCode: http.StatusInternalServerError}
+	ErrInvalidInput = register("INVALID_INPUT", http.StatusBadRequest, "Invalid input",
+		"The message names the offending field or parameter; fix the request before retrying.")
+	ErrNotFound = register("NOT_FOUND", http.StatusNotFound, "Resource not found",
+		"The resource does not exist or is not visible to the caller.")
+	ErrInternalServer = register("INTERNAL_ERROR", http.StatusInternalServerError, "An internal error occurred",
+		"Unexpected server failure; retrying may succeed. Details are only in the server logs.")
+	ErrRequestTimeout = register("REQUEST_TIMEOUT", http.StatusServiceUnavailable, "The request took too long to complete",
+		"The request ran past its deadline; narrow the range or retry later.")
 )
 
 // User errors.
 var (
-	ErrUserNotFound   = &AppError{Code: "USER_NOT_FOUND", Message: "User not found", StatusCode: http.StatusNotFound}
-	ErrDuplicateEmail = &AppError{Code: "DUPLICATE_EMAIL", Message: "A user with this email already exists", StatusCode: http.StatusConflict}
+	ErrUserNotFound = register("USER_NOT_FOUND", http.StatusNotFound, "User not found",
+		"The user was deleted or the token refers to an unknown user.")
+	ErrDuplicateEmail = register("DUPLICATE_EMAIL", http.StatusConflict, "A user with this email already exists",
+		"Register with another email or log in to the existing user.")
+	ErrDuplicateUsername = register("DUPLICATE_USERNAME", http.StatusConflict, "This username is already taken",
+		"Usernames are unique regardless of case; choose another one.")
+	ErrUsernameChangeTooSoon = register("USERNAME_CHANGE_TOO_SOON", http.StatusTooManyRequests, "Username was changed too recently",
+		"A username can be changed once every 30 days; the message says when it can next be changed.")
+)
+
+// Backup errors.
+var (
+	ErrInvalidBackup = register("INVALID_BACKUP", http.StatusBadRequest, "Backup archive is invalid",
+		"The archive was modified, truncated or written by a newer version; download a fresh backup.")
+	ErrRestoreTargetNotEmpty = register("RESTORE_TARGET_NOT_EMPTY", http.StatusConflict, "The account already has data",
+		"Restore into a fresh account, or pass merge=false to replace the existing data with the backup.")
+)
+
+// Email change errors.
+var (
+	ErrIncorrectPassword = register("INCORRECT_PASSWORD", http.StatusForbidden, "Current password is incorrect",
+		"Re-enter the current password to confirm the change.")
+	ErrInvalidVerificationToken = register("INVALID_VERIFICATION_TOKEN", http.StatusBadRequest, "Verification token is invalid",
+		"Use the link from the latest verification email.")
+	ErrVerificationTokenExpired = register("VERIFICATION_TOKEN_EXPIRED", http.StatusGone, "Verification token has expired",
+		"Request the email change again to get a new link.")
 )
 
 // Account errors.
 var (
-	ErrAccountNotFound = &AppError{Code: "ACCOUNT_NOT_FOUND", Message: "Account not found", StatusCode: http.StatusNotFound}
+	ErrAccountNotFound = register("ACCOUNT_NOT_FOUND", http.StatusNotFound, "Account not found",
+		"The account does not exist, was deleted, or is not shared with the caller.")
+	ErrAccountHasTransactions = register("ACCOUNT_HAS_TRANSACTIONS", http.StatusConflict, "Account has transactions or investments",
+		"Archive the account instead, or delete it with force=true to delete its history too.")
+)
+
+// Account group errors.
+var (
+	ErrAccountGroupNotFound = register("ACCOUNT_GROUP_NOT_FOUND", http.StatusNotFound, "Account group not found",
+		"The group does not exist or belongs to another user.")
+)
+
+// Account sharing errors.
+var (
+	ErrShareNotFound = register("SHARE_NOT_FOUND", http.StatusNotFound, "Share not found",
+		"The share was revoked or never existed.")
+	ErrDuplicateShare = register("DUPLICATE_SHARE", http.StatusConflict, "This user already has a share from you",
+		"Update the existing share instead of creating another.")
+	ErrShareReadOnly = register("SHARE_READ_ONLY", http.StatusForbidden, "You have read-only access to this account",
+		"Ask the owner for editor access to change this account.")
 )
 
 // Category errors.
 var (
-	ErrCategoryNotFound    = &AppError{Code: "CATEGORY_NOT_FOUND", Message: "Category not found", StatusCode: http.StatusNotFound}
-	ErrCategoryInUse       = &AppError{Code: "CATEGORY_IN_USE", Message: "Category is used by existing transactions", StatusCode: http.StatusConflict}
-	ErrCategoryHasChildren = &AppError{Code: "CATEGORY_HAS_CHILDREN", Message: "Category has child categories", StatusCode: http.StatusConflict}
-	ErrSelfParentCategory  = &AppError{Code: "SELF_PARENT_CATEGORY", Message: "A category cannot be its own parent", StatusCode: http.StatusBadRequest}
+	ErrCategoryNotFound = register("CATEGORY_NOT_FOUND", http.StatusNotFound, "Category not found",
+		"The category does not exist or belongs to another user.")
+	ErrCategoryInUse = register("CATEGORY_IN_USE", http.StatusConflict, "Category is used by existing transactions",
+		"Recategorize or delete its transactions first.")
+	ErrCategoryHasChildren = register("CATEGORY_HAS_CHILDREN", http.StatusConflict, "Category has child categories",
+		"Move or delete the child categories first.")
+	ErrSelfParentCategory = register("SELF_PARENT_CATEGORY", http.StatusBadRequest, "A category cannot be its own parent",
+		"Choose another category as the parent.")
+	ErrCategoryTypeMismatch = register("CATEGORY_TYPE_MISMATCH", http.StatusBadRequest, "Category type does not match transaction type",
+		"Income transactions need income categories and expenses need expense categories.")
 )
 
 // Transaction errors.
 var (
-	ErrTransactionNotFound    = &AppError{Code: "TRANSACTION_NOT_FOUND", Message: "Transaction not found", StatusCode: http.StatusNotFound}
-	ErrInvalidTransactionType = &AppError{Code: "INVALID_TRANSACTION_TYPE", Message: "Unsupported transaction type", StatusCode: http.StatusBadRequest}
-	ErrInsufficientBalance    = &AppError{Code: "INSUFFICIENT_BALANCE", Message: "Insufficient account balance", StatusCode: http.StatusBadRequest}
-	ErrSameAccountTransfer    = &AppError{Code: "SAME_ACCOUNT_TRANSFER", Message: "Cannot transfer to the same account", StatusCode: http.StatusBadRequest}
-	ErrTransactionNotEditable = &AppError{Code: "TRANSACTION_NOT_EDITABLE", Message: "This transaction type cannot be edited", StatusCode: http.StatusBadRequest}
-	ErrInvalidTypeChange      = &AppError{Code: "INVALID_TYPE_CHANGE", Message: "Cannot change transaction type to or from transfer/investment", StatusCode: http.StatusBadRequest}
+	ErrTransactionNotFound = register("TRANSACTION_NOT_FOUND", http.StatusNotFound, "Transaction not found",
+		"The transaction does not exist or is on an account the caller cannot see.")
+	ErrInvalidTransactionType = register("INVALID_TRANSACTION_TYPE", http.StatusBadRequest, "Unsupported transaction type",
+		"Use income, expense, or one of the transfer endpoints.")
+	ErrInsufficientBalance = register("INSUFFICIENT_BALANCE", http.StatusBadRequest, "Insufficient account balance",
+		"The source account cannot cover the amount.")
+	ErrSameAccountTransfer = register("SAME_ACCOUNT_TRANSFER", http.StatusBadRequest, "Cannot transfer to the same account",
+		"Pick different source and destination accounts.")
+	ErrInvalidTransfer = register("INVALID_TRANSFER", http.StatusBadRequest, "Transfers between these account types are not supported",
+		"Cash accounts transfer into cash, credit card, and investment accounts; investment accounts only into cash.")
+	ErrTransactionNotEditable = register("TRANSACTION_NOT_EDITABLE", http.StatusBadRequest, "This transaction type cannot be edited",
+		"Only the status of transfers and investment transactions can change; delete and record them again instead.")
+	ErrInvalidTypeChange = register("INVALID_TYPE_CHANGE", http.StatusBadRequest, "Cannot change transaction type to or from transfer/investment",
+		"Delete the transaction and record it again with the other type.")
+	ErrTransactionFundsInvestment = register("TRANSACTION_FUNDS_INVESTMENT", http.StatusConflict, "This transaction paid for an investment buy",
+		"It moved cash out to buy a holding; record a sell or a correcting transaction instead of deleting it.")
+
+	ErrInvalidConfirmationToken = register("INVALID_CONFIRMATION_TOKEN", http.StatusBadRequest, "Confirmation token is invalid",
+		"Run the dry run again and confirm with the token it returns.")
+	ErrConfirmationTokenExpired = register("CONFIRMATION_TOKEN_EXPIRED", http.StatusGone, "Confirmation token has expired",
+		"Run the dry run again to get a fresh token.")
+	ErrBulkDeleteStale = register("BULK_DELETE_STALE", http.StatusConflict, "The selected transactions changed since the dry run",
+		"Run the dry run again and review the new selection.")
 )
 
 // Budget errors.
 var (
-	ErrBudgetNotFound = &AppError{Code: "BUDGET_NOT_FOUND", Message: "Budget not found", StatusCode: http.StatusNotFound}
+	ErrBudgetNotFound = register("BUDGET_NOT_FOUND", http.StatusNotFound, "Budget not found",
+		"The budget does not exist or belongs to another user.")
+)
+
+// Categorization rule errors.
+var (
+	ErrRuleNotFound = register("RULE_NOT_FOUND", http.StatusNotFound, "Categorization rule not found",
+		"The rule does not exist or belongs to another user.")
 )
 
 // Investment errors.
 var (
-	ErrInvestmentNotFound = &AppError{Code: "INVESTMENT_NOT_FOUND", Message: "Investment not found", StatusCode: http.StatusNotFound}
-	ErrInsufficientShares = &AppError{Code: "INSUFFICIENT_SHARES", Message: "Insufficient shares for this sale", StatusCode: http.StatusBadRequest}
+	ErrInvestmentNotFound = register("INVESTMENT_NOT_FOUND", http.StatusNotFound, "Investment not found",
+		"The holding does not exist or is in an account the caller cannot see.")
+	ErrInsufficientShares = register("INSUFFICIENT_SHARES", http.StatusBadRequest, "Insufficient shares for this sale",
+		"Sell at most the quantity held.")
+	ErrPriceDeviationWarning = register("PRICE_DEVIATION_WARNING", http.StatusUnprocessableEntity, "The price is far from the stored price around the trade date; confirm it to record the trade",
+		"Check the price, then resend with confirm_price to record it anyway.")
 )
 
 // Security errors.
 var (
-	ErrSecurityNotFound  = &AppError{Code: "SECURITY_NOT_FOUND", Message: "Security not found", StatusCode: http.StatusNotFound}
-	ErrDuplicateSecurity = &AppError{Code: "DUPLICATE_SECURITY", Message: "A security with this symbol and exchange already exists", StatusCode: http.StatusConflict}
+	ErrSecurityNotFound = register("SECURITY_NOT_FOUND", http.StatusNotFound, "Security not found",
+		"List securities to find a valid ID.")
+	ErrDuplicateSecurity = register("DUPLICATE_SECURITY", http.StatusConflict, "A security with this symbol and exchange already exists",
+		"Use the existing security with this symbol and exchange.")
+	ErrPriceNotFound = register("PRICE_NOT_FOUND", http.StatusNotFound, "Price not found",
+		"The price does not exist for this security or was already deleted.")
+)
+
+// Exchange rate errors.
+var (
+	ErrExchangeRateNotFound = register("EXCHANGE_RATE_NOT_FOUND", http.StatusNotFound, "No exchange rate is available for this currency pair",
+		"No rate has been recorded for the pair yet; record one through the pipeline.")
+)
+
+// Pipeline and admin access errors.
+var (
+	ErrPipelineNotConfigured = register("PIPELINE_NOT_CONFIGURED", http.StatusServiceUnavailable, "Pipeline endpoints are not configured",
+		"Set PIPELINE_API_KEY or create a stored pipeline key.")
+	ErrInvalidAPIKey = register("INVALID_API_KEY", http.StatusUnauthorized, "Invalid or missing API key",
+		"Send an active pipeline key in the X-API-Key header.")
+	ErrAdminNotConfigured = register("ADMIN_NOT_CONFIGURED", http.StatusServiceUnavailable, "Admin endpoints are not configured",
+		"Set PIPELINE_ADMIN_KEY to enable admin endpoints.")
+	ErrInvalidAdminKey = register("INVALID_ADMIN_KEY", http.StatusUnauthorized, "Invalid or missing admin key",
+		"Send the configured admin key in the X-Admin-Key header.")
+)
+
+// Pipeline key errors.
+var (
+	ErrPipelineKeyNotFound = register("PIPELINE_KEY_NOT_FOUND", http.StatusNotFound, "Pipeline API key not found",
+		"List pipeline keys to find a valid ID.")
 )
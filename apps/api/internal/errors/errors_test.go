@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCatalog(t *testing.T) {
+	entries := Catalog()
+	if len(entries) == 0 {
+		t.Fatal("expected a non-empty catalog")
+	}
+	for i, entry := range entries {
+		if i > 0 && entries[i-1].Code >= entry.Code {
+			t.Errorf("expected codes sorted and unique, got %s before %s", entries[i-1].Code, entry.Code)
+		}
+		if entry.Code != strings.ToUpper(entry.Code) || strings.ContainsAny(entry.Code, " -") {
+			t.Errorf("%s: expected an UPPER_SNAKE_CASE code", entry.Code)
+		}
+		if entry.Status < 400 || entry.Status > 599 {
+			t.Errorf("%s: expected a 4xx or 5xx status, got %d", entry.Code, entry.Status)
+		}
+		if entry.Message == "" || entry.Hint == "" {
+			t.Errorf("%s: expected a message and a hint, got %+v", entry.Code, entry)
+		}
+	}
+
+	if entry, ok := Lookup("ACCOUNT_NOT_FOUND"); !ok || entry.Status != http.StatusNotFound {
+		t.Errorf("expected ACCOUNT_NOT_FOUND registered as 404, got %+v, %v", entry, ok)
+	}
+	if Status(WithMessage(ErrInsufficientShares, "custom").Code) != http.StatusBadRequest {
+		t.Error("expected a WithMessage copy to keep its sentinel's status")
+	}
+	if Status("NOT_A_REGISTERED_CODE") != http.StatusInternalServerError {
+		t.Error("expected 500 for an unregistered code")
+	}
+}
+
+// TestSentinelsAreRegistered fails when an Err variable is declared without
+// register, so it would be missing from the catalog.
+func TestSentinelsAreRegistered(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "errors.go", nil, 0)
+	if err != nil {
+		t.Fatalf("failed to parse errors.go: %v", err)
+	}
+	count := 0
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if !strings.HasPrefix(name.Name, "Err") {
+					continue
+				}
+				count++
+				if call, ok := vs.Values[i].(*ast.CallExpr); ok {
+					if fn, ok := call.Fun.(*ast.Ident); ok && fn.Name == "register" {
+						continue
+					}
+				}
+				t.Errorf("%s is not declared with register", name.Name)
+			}
+		}
+	}
+	if count != len(Catalog()) {
+		t.Errorf("expected one catalog entry per sentinel, got %d sentinels and %d entries", count, len(Catalog()))
+	}
+}
@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+	"kuberan/internal/services"
+)
+
+// AccountGroupHandler handles account group requests.
+type AccountGroupHandler struct {
+	groupService services.AccountGroupServicer
+	auditService services.AuditServicer
+}
+
+// NewAccountGroupHandler creates a new AccountGroupHandler.
+func NewAccountGroupHandler(groupService services.AccountGroupServicer, auditService services.AuditServicer) *AccountGroupHandler {
+	return &AccountGroupHandler{groupService: groupService, auditService: auditService}
+}
+
+// AccountGroupRequest represents the request payload for creating or renaming an account group.
+type AccountGroupRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+}
+
+// ReorderAccountGroupsRequest represents the request payload for reordering account groups.
+type ReorderAccountGroupsRequest struct {
+	GroupIDs []string `json:"group_ids" binding:"required"`
+}
+
+// AccountGroupResponse wraps a single account group.
+type AccountGroupResponse struct {
+	Group models.AccountGroup `json:"group"`
+}
+
+// AccountGroupsResponse lists account groups in display order.
+type AccountGroupsResponse struct {
+	Groups []models.AccountGroup `json:"groups"`
+}
+
+// CreateAccountGroup handles the creation of a new account group.
+// @Summary     Create an account group
+// @Description Create a group for organizing accounts; it is ordered after existing groups
+// @Tags        account-groups
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       request body AccountGroupRequest true "Group details"
+// @Success     201 {object} AccountGroupResponse "Group created"
+// @Failure     400 {object} ErrorResponse "Invalid input"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /account-groups [post]
+func (h *AccountGroupHandler) CreateAccountGroup(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	var req AccountGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+		return
+	}
+
+	group, err := h.groupService.CreateAccountGroup(userID, req.Name)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(userID, "CREATE_ACCOUNT_GROUP", "account_group", group.ID, c.ClientIP(),
+		map[string]interface{}{"name": group.Name})
+
+	c.JSON(http.StatusCreated, gin.H{"group": group})
+}
+
+// GetAccountGroups handles listing the authenticated user's account groups.
+// @Summary     Get account groups
+// @Description Get the user's account groups in display order
+// @Tags        account-groups
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Success     200 {object} AccountGroupsResponse "Account groups"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /account-groups [get]
+func (h *AccountGroupHandler) GetAccountGroups(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	groups, err := h.groupService.GetAccountGroups(userID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"groups": groups})
+}
+
+// GetAccountGroup handles retrieving a specific account group.
+// @Summary     Get account group by ID
+// @Description Get a specific account group by ID
+// @Tags        account-groups
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Group ID"
+// @Success     200 {object} AccountGroupResponse "Group details"
+// @Failure     400 {object} ErrorResponse "Invalid group ID"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     404 {object} ErrorResponse "Group not found"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /account-groups/{id} [get]
+func (h *AccountGroupHandler) GetAccountGroup(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	groupID, err := parsePathID(c, "id")
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	group, err := h.groupService.GetAccountGroupByID(userID, groupID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"group": group})
+}
+
+// UpdateAccountGroup handles renaming an account group.
+// @Summary     Update account group
+// @Description Rename an account group
+// @Tags        account-groups
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id      path string              true "Group ID"
+// @Param       request body AccountGroupRequest true "Updated group details"
+// @Success     200 {object} AccountGroupResponse "Updated group"
+// @Failure     400 {object} ErrorResponse "Invalid input or group ID"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     404 {object} ErrorResponse "Group not found"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /account-groups/{id} [put]
+func (h *AccountGroupHandler) UpdateAccountGroup(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	groupID, err := parsePathID(c, "id")
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	var req AccountGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+		return
+	}
+
+	group, err := h.groupService.UpdateAccountGroup(userID, groupID, req.Name)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(userID, "UPDATE_ACCOUNT_GROUP", "account_group", groupID, c.ClientIP(), nil)
+
+	c.JSON(http.StatusOK, gin.H{"group": group})
+}
+
+// DeleteAccountGroup handles deleting an account group.
+// @Summary     Delete account group
+// @Description Delete an account group (soft delete); its accounts become ungrouped
+// @Tags        account-groups
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Group ID"
+// @Success     200 {object} MessageResponse "Group deleted"
+// @Failure     400 {object} ErrorResponse "Invalid group ID"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     404 {object} ErrorResponse "Group not found"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /account-groups/{id} [delete]
+func (h *AccountGroupHandler) DeleteAccountGroup(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	groupID, err := parsePathID(c, "id")
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	if err := h.groupService.DeleteAccountGroup(userID, groupID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(userID, "DELETE_ACCOUNT_GROUP", "account_group", groupID, c.ClientIP(), nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account group deleted successfully"})
+}
+
+// ReorderAccountGroups handles setting the display order of account groups.
+// @Summary     Reorder account groups
+// @Description Set the display order of the user's groups; group_ids must list every group exactly once
+// @Tags        account-groups
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       request body ReorderAccountGroupsRequest true "Group IDs in the new order"
+// @Success     200 {object} AccountGroupsResponse "Groups in the new order"
+// @Failure     400 {object} ErrorResponse "Invalid input"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     404 {object} ErrorResponse "Group not found"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /account-groups/order [put]
+func (h *AccountGroupHandler) ReorderAccountGroups(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	var req ReorderAccountGroupsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+		return
+	}
+
+	groups, err := h.groupService.ReorderAccountGroups(userID, req.GroupIDs)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(userID, "REORDER_ACCOUNT_GROUPS", "account_group", "", c.ClientIP(), nil)
+
+	c.JSON(http.StatusOK, gin.H{"groups": groups})
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+	"kuberan/internal/services"
+)
+
+// --- mock account group service ---
+
+type mockAccountGroupService struct {
+	createAccountGroupFn   func(userID, name string) (*models.AccountGroup, error)
+	getAccountGroupsFn     func(userID string) ([]models.AccountGroup, error)
+	getAccountGroupByIDFn  func(userID, groupID string) (*models.AccountGroup, error)
+	updateAccountGroupFn   func(userID, groupID, name string) (*models.AccountGroup, error)
+	deleteAccountGroupFn   func(userID, groupID string) error
+	reorderAccountGroupsFn func(userID string, groupIDs []string) ([]models.AccountGroup, error)
+}
+
+func (m *mockAccountGroupService) CreateAccountGroup(userID, name string) (*models.AccountGroup, error) {
+	if m.createAccountGroupFn != nil {
+		return m.createAccountGroupFn(userID, name)
+	}
+	return &models.AccountGroup{}, nil
+}
+
+func (m *mockAccountGroupService) GetAccountGroups(userID string) ([]models.AccountGroup, error) {
+	if m.getAccountGroupsFn != nil {
+		return m.getAccountGroupsFn(userID)
+	}
+	return []models.AccountGroup{}, nil
+}
+
+func (m *mockAccountGroupService) GetAccountGroupByID(userID, groupID string) (*models.AccountGroup, error) {
+	if m.getAccountGroupByIDFn != nil {
+		return m.getAccountGroupByIDFn(userID, groupID)
+	}
+	return &models.AccountGroup{}, nil
+}
+
+func (m *mockAccountGroupService) UpdateAccountGroup(userID, groupID, name string) (*models.AccountGroup, error) {
+	if m.updateAccountGroupFn != nil {
+		return m.updateAccountGroupFn(userID, groupID, name)
+	}
+	return &models.AccountGroup{}, nil
+}
+
+func (m *mockAccountGroupService) DeleteAccountGroup(userID, groupID string) error {
+	if m.deleteAccountGroupFn != nil {
+		return m.deleteAccountGroupFn(userID, groupID)
+	}
+	return nil
+}
+
+func (m *mockAccountGroupService) ReorderAccountGroups(userID string, groupIDs []string) ([]models.AccountGroup, error) {
+	if m.reorderAccountGroupsFn != nil {
+		return m.reorderAccountGroupsFn(userID, groupIDs)
+	}
+	return []models.AccountGroup{}, nil
+}
+
+var _ services.AccountGroupServicer = (*mockAccountGroupService)(nil)
+
+func setupAccountGroupRouter(handler *AccountGroupHandler) *gin.Engine {
+	r := gin.New()
+	auth := r.Group("", injectUserID(testID(1)))
+	auth.POST("/account-groups", handler.CreateAccountGroup)
+	auth.GET("/account-groups", handler.GetAccountGroups)
+	auth.PUT("/account-groups/order", handler.ReorderAccountGroups)
+	auth.GET("/account-groups/:id", handler.GetAccountGroup)
+	auth.PUT("/account-groups/:id", handler.UpdateAccountGroup)
+	auth.DELETE("/account-groups/:id", handler.DeleteAccountGroup)
+	return r
+}
+
+func TestAccountGroupHandler_CreateAccountGroup(t *testing.T) {
+	t.Run("returns 201 on success", func(t *testing.T) {
+		svc := &mockAccountGroupService{
+			createAccountGroupFn: func(userID, name string) (*models.AccountGroup, error) {
+				return &models.AccountGroup{Base: models.Base{ID: testID(2)}, UserID: userID, Name: name}, nil
+			},
+		}
+		handler := NewAccountGroupHandler(svc, &mockAuditService{})
+		r := setupAccountGroupRouter(handler)
+
+		rec := doRequest(r, "POST", "/account-groups", `{"name":"Retirement"}`)
+
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		group := parseJSON(t, rec)["group"].(map[string]interface{})
+		if group["name"] != "Retirement" {
+			t.Errorf("expected name=Retirement, got %v", group["name"])
+		}
+	})
+
+	t.Run("returns 400 without name", func(t *testing.T) {
+		handler := NewAccountGroupHandler(&mockAccountGroupService{}, &mockAuditService{})
+		r := setupAccountGroupRouter(handler)
+
+		rec := doRequest(r, "POST", "/account-groups", `{}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+}
+
+func TestAccountGroupHandler_ReorderAccountGroups(t *testing.T) {
+	t.Run("passes group ids in order", func(t *testing.T) {
+		var captured []string
+		svc := &mockAccountGroupService{
+			reorderAccountGroupsFn: func(_ string, groupIDs []string) ([]models.AccountGroup, error) {
+				captured = groupIDs
+				return []models.AccountGroup{}, nil
+			},
+		}
+		handler := NewAccountGroupHandler(svc, &mockAuditService{})
+		r := setupAccountGroupRouter(handler)
+
+		rec := doRequest(r, "PUT", "/account-groups/order",
+			`{"group_ids":["`+testID(3)+`","`+testID(2)+`"]}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if len(captured) != 2 || captured[0] != testID(3) || captured[1] != testID(2) {
+			t.Errorf("expected ids in request order, got %v", captured)
+		}
+	})
+}
+
+func TestAccountGroupHandler_DeleteAccountGroup(t *testing.T) {
+	t.Run("returns 404 when not found", func(t *testing.T) {
+		svc := &mockAccountGroupService{
+			deleteAccountGroupFn: func(_, _ string) error {
+				return apperrors.ErrAccountGroupNotFound
+			},
+		}
+		handler := NewAccountGroupHandler(svc, &mockAuditService{})
+		r := setupAccountGroupRouter(handler)
+
+		rec := doRequest(r, "DELETE", "/account-groups/"+testID(9), "")
+
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "ACCOUNT_GROUP_NOT_FOUND")
+	})
+}
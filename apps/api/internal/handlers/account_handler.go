@@ -2,13 +2,14 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	apperrors "kuberan/internal/errors"
 	"kuberan/internal/models"
-	"kuberan/internal/pagination"
+	_ "kuberan/internal/pagination" // page types in swagger annotations
 	"kuberan/internal/services"
 )
 
@@ -25,10 +26,14 @@ func NewAccountHandler(accountService services.AccountServicer, auditService ser
 
 // CreateCashAccountRequest represents the request payload for creating a cash account
 type CreateCashAccountRequest struct {
-	Name           string `json:"name" binding:"required,min=1,max=100"`
-	Description    string `json:"description" binding:"max=500"`
-	Currency       string `json:"currency" binding:"omitempty,iso4217"`
-	InitialBalance int64  `json:"initial_balance" binding:"gte=0"`
+	Name                  string  `json:"name" binding:"required,min=1,max=100"`
+	Description           string  `json:"description" binding:"max=500"`
+	Currency              string  `json:"currency" binding:"omitempty,iso4217"`
+	InitialBalance        int64   `json:"initial_balance" binding:"gte=0"`
+	InitialBalanceDecimal *string `json:"initial_balance_decimal"` // alternative to initial_balance, e.g. "12.34"
+	AllowNegative         *bool   `json:"allow_negative"`          // defaults to true
+	MinBalance            int64   `json:"min_balance" binding:"gte=0"`
+	OpeningDate           *string `json:"opening_date"` // backdates the account and its initial balance; not in the future
 }
 
 // CreateInvestmentAccountRequest represents the request payload for creating an investment account.
@@ -48,19 +53,31 @@ type CreateCreditCardAccountRequest struct {
 	CreditLimit  int64   `json:"credit_limit" binding:"gte=0"`
 	InterestRate float64 `json:"interest_rate" binding:"gte=0,lte=100"`
 	DueDate      *string `json:"due_date"`
+	OpeningDate  *string `json:"opening_date"` // backdates the account; not in the future
 }
 
 // UpdateAccountRequest represents the request payload for updating an account.
 // Accepts common fields for all account types and type-specific optional fields.
 type UpdateAccountRequest struct {
-	Name          *string  `json:"name" binding:"omitempty,min=1,max=100"`
-	Description   *string  `json:"description" binding:"omitempty,max=500"`
-	IsActive      *bool    `json:"is_active"`
-	Broker        *string  `json:"broker" binding:"omitempty,max=100"`
-	AccountNumber *string  `json:"account_number" binding:"omitempty,max=50"`
-	InterestRate  *float64 `json:"interest_rate" binding:"omitempty,gte=0,lte=100"`
-	DueDate       *string  `json:"due_date"`
-	CreditLimit   *int64   `json:"credit_limit" binding:"omitempty,gte=0"`
+	Name               *string  `json:"name" binding:"omitempty,min=1,max=100"`
+	Description        *string  `json:"description" binding:"omitempty,max=500"`
+	IsActive           *bool    `json:"is_active"`
+	ExcludeFromReports *bool    `json:"exclude_from_reports"`
+	DefaultCategoryID  *string  `json:"default_category_id"` // empty string clears it
+	GroupID            *string  `json:"group_id"`            // empty string ungroups the account
+	AllowNegative      *bool    `json:"allow_negative"`
+	MinBalance         *int64   `json:"min_balance" binding:"omitempty,gte=0"`
+	Broker             *string  `json:"broker" binding:"omitempty,max=100"`
+	AccountNumber      *string  `json:"account_number" binding:"omitempty,max=50"`
+	InterestRate       *float64 `json:"interest_rate" binding:"omitempty,gte=0,lte=100"`
+	DueDate            *string  `json:"due_date"`
+	CreditLimit        *int64   `json:"credit_limit" binding:"omitempty,gte=0"`
+}
+
+// GroupedAccountsResponse lists accounts nested under their groups, with
+// ungrouped accounts in a final default group.
+type GroupedAccountsResponse struct {
+	Groups []services.GroupedAccounts `json:"groups"`
 }
 
 // AccountResponse represents an account in the response
@@ -77,7 +94,7 @@ type AccountResponse struct {
 
 // CreateCashAccount handles the creation of a new cash account
 // @Summary     Create a cash account
-// @Description Create a new cash account for the authenticated user
+// @Description Create a new cash account for the authenticated user. With allow_negative set to false, expenses and outgoing transfers that would take the balance below min_balance are rejected with INSUFFICIENT_BALANCE.
 // @Tags        accounts
 // @Accept      json
 // @Produce     json
@@ -101,12 +118,34 @@ func (h *AccountHandler) CreateCashAccount(c *gin.Context) {
 		return
 	}
 
+	initialBalance, err := resolveAmount("initial_balance", req.InitialBalance, req.InitialBalanceDecimal, func() (string, error) {
+		return req.Currency, nil
+	})
+	if err == nil && initialBalance < 0 {
+		err = apperrors.WithMessage(apperrors.ErrInvalidInput, "initial_balance must not be negative")
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	allowNegative := req.AllowNegative == nil || *req.AllowNegative
+
+	openingDate, err := parseOpeningDate(req.OpeningDate)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
 	account, err := h.accountService.CreateCashAccount(
-		userID,
+		models.UserID(userID),
 		req.Name,
 		req.Description,
 		req.Currency,
-		req.InitialBalance,
+		models.Cents(initialBalance),
+		allowNegative,
+		models.Cents(req.MinBalance),
+		openingDate,
 	)
 	if err != nil {
 		respondWithError(c, err)
@@ -146,7 +185,7 @@ func (h *AccountHandler) CreateInvestmentAccount(c *gin.Context) {
 	}
 
 	account, err := h.accountService.CreateInvestmentAccount(
-		userID,
+		models.UserID(userID),
 		req.Name,
 		req.Description,
 		req.Currency,
@@ -192,25 +231,28 @@ func (h *AccountHandler) CreateCreditCardAccount(c *gin.Context) {
 
 	var dueDate *time.Time
 	if req.DueDate != nil && *req.DueDate != "" {
-		parsed, parseErr := time.Parse(time.RFC3339, *req.DueDate)
+		parsed, parseErr := parseDateParam("due_date", *req.DueDate, rangeStart, time.UTC)
 		if parseErr != nil {
-			parsed, parseErr = time.Parse("2006-01-02", *req.DueDate)
-			if parseErr != nil {
-				respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "invalid due_date format"))
-				return
-			}
+			respondWithError(c, parseErr)
+			return
 		}
 		dueDate = &parsed
 	}
+	openingDate, err := parseOpeningDate(req.OpeningDate)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
 
 	account, err := h.accountService.CreateCreditCardAccount(
-		userID,
+		models.UserID(userID),
 		req.Name,
 		req.Description,
 		req.Currency,
-		req.CreditLimit,
+		models.Cents(req.CreditLimit),
 		req.InterestRate,
 		dueDate,
+		openingDate,
 	)
 	if err != nil {
 		respondWithError(c, err)
@@ -223,6 +265,19 @@ func (h *AccountHandler) CreateCreditCardAccount(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{"account": account})
 }
 
+// parseOpeningDate parses an optional opening_date; nil or empty means the
+// account opens now.
+func parseOpeningDate(value *string) (*time.Time, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+	parsed, err := parseDateParam("opening_date", *value, rangeStart, time.UTC)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
 // GetUserAccounts handles the retrieval of accounts for a user
 // @Summary     Get user accounts
 // @Description Get a paginated list of accounts for the authenticated user
@@ -232,7 +287,12 @@ func (h *AccountHandler) CreateCreditCardAccount(c *gin.Context) {
 // @Security    BearerAuth
 // @Param       page      query int false "Page number (default 1)"
 // @Param       page_size query int false "Items per page (default 20, max 100)"
+// @Param       with_total query bool false "Compute total_items and total_pages (default true); when false they are -1 and 0"
+// @Param       include_inactive query bool false "Include archived accounts, flagged by is_active false and archived_at (default false)"
+// @Param       grouped   query bool false "Return every account nested under its group instead of a page (default false)"
 // @Success     200 {object} pagination.PageResponse[models.Account] "Paginated accounts"
+// @Success     200 {object} GroupedAccountsResponse "Accounts by group, when grouped=true"
+// @Failure     400 {object} ErrorResponse "Invalid include_inactive or grouped"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
 // @Failure     500 {object} ErrorResponse "Server error"
 // @Router      /accounts [get]
@@ -243,19 +303,82 @@ func (h *AccountHandler) GetUserAccounts(c *gin.Context) {
 		return
 	}
 
-	var page pagination.PageRequest
-	if err := c.ShouldBindQuery(&page); err != nil {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+	page, err := bindPage(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	includeInactive := false
+	if v := c.Query("include_inactive"); v != "" {
+		parsed, parseErr := strconv.ParseBool(v)
+		if parseErr != nil {
+			respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "include_inactive must be true or false"))
+			return
+		}
+		includeInactive = parsed
+	}
+
+	if v := c.Query("grouped"); v != "" {
+		grouped, parseErr := strconv.ParseBool(v)
+		if parseErr != nil {
+			respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "grouped must be true or false"))
+			return
+		}
+		if grouped {
+			groups, groupErr := h.accountService.GetGroupedAccounts(models.UserID(userID), includeInactive)
+			if groupErr != nil {
+				respondWithError(c, groupErr)
+				return
+			}
+			c.JSON(http.StatusOK, GroupedAccountsResponse{Groups: groups})
+			return
+		}
+	}
+
+	result, err := h.accountService.GetUserAccounts(models.UserID(userID), page, includeInactive)
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	result, err := h.accountService.GetUserAccounts(userID, page)
+	respondWithPage(c, page, result)
+}
+
+// GetArchivedAccounts handles the retrieval of a user's archived accounts
+// @Summary     Get archived accounts
+// @Description Get a paginated list of the user's inactive accounts with their archival time and balance, most recently archived first
+// @Tags        accounts
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       page      query int false "Page number (default 1)"
+// @Param       page_size query int false "Items per page (default 20, max 100)"
+// @Param       with_total query bool false "Compute total_items and total_pages (default true); when false they are -1 and 0"
+// @Success     200 {object} pagination.PageResponse[models.Account] "Paginated archived accounts"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /accounts/archived [get]
+func (h *AccountHandler) GetArchivedAccounts(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	page, err := bindPage(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	result, err := h.accountService.GetArchivedAccounts(models.UserID(userID), page)
 	if err != nil {
 		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	respondWithPage(c, page, result)
 }
 
 // GetAccountByID handles the retrieval of a specific account for a user
@@ -285,7 +408,7 @@ func (h *AccountHandler) GetAccountByID(c *gin.Context) {
 		return
 	}
 
-	account, err := h.accountService.GetAccountByID(userID, accountID)
+	account, err := h.accountService.GetAccountByID(models.UserID(userID), models.AccountID(accountID))
 	if err != nil {
 		respondWithError(c, err)
 		return
@@ -294,6 +417,50 @@ func (h *AccountHandler) GetAccountByID(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"account": account})
 }
 
+// GetAccountTimeline handles the retrieval of an account's merged activity feed
+// @Summary     Get account timeline
+// @Description Get the account's cash transactions and, for investment accounts, the buys, sells, dividends, and splits of its holdings as one feed, newest first. Each entry has a kind (transaction or investment_transaction) and the matching record.
+// @Tags        accounts
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id         path  int  true  "Account ID"
+// @Param       page       query int  false "Page number (default 1)"
+// @Param       page_size  query int  false "Items per page (default 20, max 100)"
+// @Param       with_total query bool false "Compute total_items and total_pages (default true); when false they are -1 and 0"
+// @Success     200 {object} pagination.PageResponse[services.AccountTimelineEntry] "Paginated timeline entries"
+// @Failure     400 {object} ErrorResponse "Invalid input"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     404 {object} ErrorResponse "Account not found"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /accounts/{id}/timeline [get]
+func (h *AccountHandler) GetAccountTimeline(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	accountID, err := parsePathID(c, "id")
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	page, err := bindPage(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	result, err := h.accountService.GetAccountTimeline(models.UserID(userID), models.AccountID(accountID), page)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	respondWithPage(c, page, result)
+}
+
 // UpdateAccount handles updating an account of any type.
 // @Summary     Update account
 // @Description Update an existing account for the authenticated user. Accepts common fields for all account types and type-specific fields.
@@ -329,28 +496,48 @@ func (h *AccountHandler) UpdateAccount(c *gin.Context) {
 	}
 
 	updateFields := services.AccountUpdateFields{
-		Name:          req.Name,
-		Description:   req.Description,
-		IsActive:      req.IsActive,
-		Broker:        req.Broker,
-		AccountNumber: req.AccountNumber,
-		InterestRate:  req.InterestRate,
-		CreditLimit:   req.CreditLimit,
+		Name:               req.Name,
+		Description:        req.Description,
+		IsActive:           req.IsActive,
+		ExcludeFromReports: req.ExcludeFromReports,
+		AllowNegative:      req.AllowNegative,
+		MinBalance:         req.MinBalance,
+		Broker:             req.Broker,
+		AccountNumber:      req.AccountNumber,
+		InterestRate:       req.InterestRate,
+		CreditLimit:        req.CreditLimit,
+	}
+
+	// Handle DefaultCategoryID: nil in JSON = don't change; empty string = clear; non-empty = set
+	if req.DefaultCategoryID != nil {
+		if *req.DefaultCategoryID == "" {
+			var nilStr *string
+			updateFields.DefaultCategoryID = &nilStr
+		} else {
+			updateFields.DefaultCategoryID = &req.DefaultCategoryID
+		}
+	}
+
+	// Handle GroupID the same way: empty string = ungroup
+	if req.GroupID != nil {
+		if *req.GroupID == "" {
+			var nilStr *string
+			updateFields.GroupID = &nilStr
+		} else {
+			updateFields.GroupID = &req.GroupID
+		}
 	}
 
 	if req.DueDate != nil && *req.DueDate != "" {
-		parsed, parseErr := time.Parse(time.RFC3339, *req.DueDate)
+		parsed, parseErr := parseDateParam("due_date", *req.DueDate, rangeStart, time.UTC)
 		if parseErr != nil {
-			parsed, parseErr = time.Parse("2006-01-02", *req.DueDate)
-			if parseErr != nil {
-				respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "invalid due_date format"))
-				return
-			}
+			respondWithError(c, parseErr)
+			return
 		}
 		updateFields.DueDate = &parsed
 	}
 
-	account, err := h.accountService.UpdateAccount(userID, accountID, updateFields)
+	account, err := h.accountService.UpdateAccount(models.UserID(userID), models.AccountID(accountID), updateFields)
 	if err != nil {
 		respondWithError(c, err)
 		return
@@ -360,3 +547,50 @@ func (h *AccountHandler) UpdateAccount(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"account": account})
 }
+
+// DeleteAccount handles deleting an account.
+// @Summary     Delete account
+// @Description Delete an account the user owns (soft delete). An account with transactions, transfers into it, or investments is refused with ACCOUNT_HAS_TRANSACTIONS unless force is true; with force, its transactions, investments and investment transactions are deleted with it in one step, and settled transfers to or from other accounts are reversed on those accounts. Archiving keeps the history instead.
+// @Tags        accounts
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id    path  string true  "Account ID"
+// @Param       force query bool   false "Also delete the account's transactions and investments"
+// @Success     200 {object} MessageResponse "Account deleted"
+// @Failure     400 {object} ErrorResponse "Invalid account ID or force"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     404 {object} ErrorResponse "Account not found"
+// @Failure     409 {object} ErrorResponse "Account has transactions or investments"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /accounts/{id} [delete]
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	accountID, err := parsePathID(c, "id")
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	force := false
+	if v := c.Query("force"); v != "" {
+		if force, err = strconv.ParseBool(v); err != nil {
+			respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "force must be true or false"))
+			return
+		}
+	}
+
+	if err := h.accountService.DeleteAccount(models.UserID(userID), models.AccountID(accountID), force); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(userID, "DELETE_ACCOUNT", "account", accountID, c.ClientIP(),
+		map[string]interface{}{"force": force})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account deleted successfully"})
+}
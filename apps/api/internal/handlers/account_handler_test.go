@@ -17,90 +17,144 @@ import (
 // --- mock account service ---
 
 type mockAccountService struct {
-	createCashAccountFn       func(userID uint, name, description, currency string, initialBalance int64) (*models.Account, error)
-	createInvestmentAccountFn func(userID uint, name, description, currency, broker, accountNumber string) (*models.Account, error)
-	createCreditCardAccountFn func(userID uint, name, description, currency string, creditLimit int64, interestRate float64, dueDate *time.Time) (*models.Account, error)
-	getUserAccountsFn         func(userID uint, page pagination.PageRequest) (*pagination.PageResponse[models.Account], error)
-	getAccountByIDFn          func(userID, accountID uint) (*models.Account, error)
-	updateAccountFn           func(userID, accountID uint, updates services.AccountUpdateFields) (*models.Account, error)
-	updateAccountBalanceFn    func(tx *gorm.DB, account *models.Account, transactionType models.TransactionType, amount int64) error
+	createCashAccountFn       func(userID models.UserID, name, description, currency string, initialBalance models.Cents, allowNegative bool, minBalance models.Cents, openingDate *time.Time) (*models.Account, error)
+	createInvestmentAccountFn func(userID models.UserID, name, description, currency, broker, accountNumber string) (*models.Account, error)
+	createCreditCardAccountFn func(userID models.UserID, name, description, currency string, creditLimit models.Cents, interestRate float64, dueDate, openingDate *time.Time) (*models.Account, error)
+	getUserAccountsFn         func(userID models.UserID, page pagination.PageRequest, includeInactive bool) (*pagination.PageResponse[models.Account], error)
+	getArchivedAccountsFn     func(userID models.UserID, page pagination.PageRequest) (*pagination.PageResponse[models.Account], error)
+	getGroupedAccountsFn      func(userID models.UserID, includeInactive bool) ([]services.GroupedAccounts, error)
+	getAccountByIDFn          func(userID models.UserID, accountID models.AccountID) (*models.Account, error)
+	getWritableAccountFn      func(userID models.UserID, accountID models.AccountID) (*models.Account, error)
+	updateAccountFn           func(userID models.UserID, accountID models.AccountID, updates services.AccountUpdateFields) (*models.Account, error)
+	deleteAccountFn           func(userID models.UserID, accountID models.AccountID, force bool) error
+	updateAccountBalanceFn    func(tx *gorm.DB, account *models.Account, transactionType models.TransactionType, amount models.Cents) error
+	getStatementFn            func(userID models.UserID, accountID models.AccountID, month time.Time) (*services.AccountStatement, error)
+	getAccountTimelineFn      func(userID models.UserID, accountID models.AccountID, page pagination.PageRequest) (*pagination.PageResponse[services.AccountTimelineEntry], error)
 }
 
-func (m *mockAccountService) CreateCashAccount(userID uint, name, description, currency string, initialBalance int64) (*models.Account, error) {
+func (m *mockAccountService) CreateCashAccount(userID models.UserID, name, description, currency string, initialBalance models.Cents, allowNegative bool, minBalance models.Cents, openingDate *time.Time) (*models.Account, error) {
 	if m.createCashAccountFn != nil {
-		return m.createCashAccountFn(userID, name, description, currency, initialBalance)
+		return m.createCashAccountFn(userID, name, description, currency, initialBalance, allowNegative, minBalance, openingDate)
 	}
 	return &models.Account{}, nil
 }
 
-func (m *mockAccountService) CreateInvestmentAccount(userID uint, name, description, currency, broker, accountNumber string) (*models.Account, error) {
+func (m *mockAccountService) CreateInvestmentAccount(userID models.UserID, name, description, currency, broker, accountNumber string) (*models.Account, error) {
 	if m.createInvestmentAccountFn != nil {
 		return m.createInvestmentAccountFn(userID, name, description, currency, broker, accountNumber)
 	}
 	return &models.Account{}, nil
 }
 
-func (m *mockAccountService) CreateCreditCardAccount(userID uint, name, description, currency string, creditLimit int64, interestRate float64, dueDate *time.Time) (*models.Account, error) {
+func (m *mockAccountService) CreateCreditCardAccount(userID models.UserID, name, description, currency string, creditLimit models.Cents, interestRate float64, dueDate, openingDate *time.Time) (*models.Account, error) {
 	if m.createCreditCardAccountFn != nil {
-		return m.createCreditCardAccountFn(userID, name, description, currency, creditLimit, interestRate, dueDate)
+		return m.createCreditCardAccountFn(userID, name, description, currency, creditLimit, interestRate, dueDate, openingDate)
 	}
 	return &models.Account{}, nil
 }
 
-func (m *mockAccountService) GetUserAccounts(userID uint, page pagination.PageRequest) (*pagination.PageResponse[models.Account], error) {
+func (m *mockAccountService) GetUserAccounts(userID models.UserID, page pagination.PageRequest, includeInactive bool) (*pagination.PageResponse[models.Account], error) {
 	if m.getUserAccountsFn != nil {
-		return m.getUserAccountsFn(userID, page)
+		return m.getUserAccountsFn(userID, page, includeInactive)
 	}
-	resp := pagination.NewPageResponse([]models.Account{}, 1, 20, 0)
+	resp := pagination.NewPageResponse([]models.Account{}, page.Page, page.PageSize, 0)
 	return &resp, nil
 }
 
-func (m *mockAccountService) GetAccountByID(userID, accountID uint) (*models.Account, error) {
+func (m *mockAccountService) GetArchivedAccounts(userID models.UserID, page pagination.PageRequest) (*pagination.PageResponse[models.Account], error) {
+	if m.getArchivedAccountsFn != nil {
+		return m.getArchivedAccountsFn(userID, page)
+	}
+	resp := pagination.NewPageResponse([]models.Account{}, page.Page, page.PageSize, 0)
+	return &resp, nil
+}
+
+func (m *mockAccountService) GetGroupedAccounts(userID models.UserID, includeInactive bool) ([]services.GroupedAccounts, error) {
+	if m.getGroupedAccountsFn != nil {
+		return m.getGroupedAccountsFn(userID, includeInactive)
+	}
+	return []services.GroupedAccounts{}, nil
+}
+
+func (m *mockAccountService) GetAccountByID(userID models.UserID, accountID models.AccountID) (*models.Account, error) {
 	if m.getAccountByIDFn != nil {
 		return m.getAccountByIDFn(userID, accountID)
 	}
 	return &models.Account{}, nil
 }
 
-func (m *mockAccountService) UpdateAccount(userID, accountID uint, updates services.AccountUpdateFields) (*models.Account, error) {
+func (m *mockAccountService) GetWritableAccount(userID models.UserID, accountID models.AccountID) (*models.Account, error) {
+	if m.getWritableAccountFn != nil {
+		return m.getWritableAccountFn(userID, accountID)
+	}
+	return &models.Account{}, nil
+}
+
+func (m *mockAccountService) UpdateAccount(userID models.UserID, accountID models.AccountID, updates services.AccountUpdateFields) (*models.Account, error) {
 	if m.updateAccountFn != nil {
 		return m.updateAccountFn(userID, accountID, updates)
 	}
 	return &models.Account{}, nil
 }
 
-func (m *mockAccountService) UpdateAccountBalance(tx *gorm.DB, account *models.Account, transactionType models.TransactionType, amount int64) error {
+func (m *mockAccountService) DeleteAccount(userID models.UserID, accountID models.AccountID, force bool) error {
+	if m.deleteAccountFn != nil {
+		return m.deleteAccountFn(userID, accountID, force)
+	}
+	return nil
+}
+
+func (m *mockAccountService) UpdateAccountBalance(tx *gorm.DB, account *models.Account, transactionType models.TransactionType, amount models.Cents) error {
 	if m.updateAccountBalanceFn != nil {
 		return m.updateAccountBalanceFn(tx, account, transactionType, amount)
 	}
 	return nil
 }
 
+func (m *mockAccountService) GetStatement(userID models.UserID, accountID models.AccountID, month time.Time) (*services.AccountStatement, error) {
+	if m.getStatementFn != nil {
+		return m.getStatementFn(userID, accountID, month)
+	}
+	return &services.AccountStatement{}, nil
+}
+
+func (m *mockAccountService) GetAccountTimeline(userID models.UserID, accountID models.AccountID, page pagination.PageRequest) (*pagination.PageResponse[services.AccountTimelineEntry], error) {
+	if m.getAccountTimelineFn != nil {
+		return m.getAccountTimelineFn(userID, accountID, page)
+	}
+	resp := pagination.NewPageResponse([]services.AccountTimelineEntry{}, page.Page, page.PageSize, 0)
+	return &resp, nil
+}
+
 // verify interface compliance
 var _ services.AccountServicer = (*mockAccountService)(nil)
 
 func setupAccountRouter(handler *AccountHandler) *gin.Engine {
 	r := gin.New()
-	auth := r.Group("", injectUserID(1))
+	auth := r.Group("", injectUserID(testID(1)))
 	auth.POST("/accounts/cash", handler.CreateCashAccount)
 	auth.POST("/accounts/investment", handler.CreateInvestmentAccount)
 	auth.POST("/accounts/credit-card", handler.CreateCreditCardAccount)
 	auth.GET("/accounts", handler.GetUserAccounts)
+	auth.GET("/accounts/archived", handler.GetArchivedAccounts)
 	auth.GET("/accounts/:id", handler.GetAccountByID)
 	auth.PUT("/accounts/:id", handler.UpdateAccount)
+	auth.DELETE("/accounts/:id", handler.DeleteAccount)
+	auth.GET("/accounts/:id/statement", handler.GetAccountStatement)
+	auth.GET("/accounts/:id/timeline", handler.GetAccountTimeline)
 	return r
 }
 
 func TestAccountHandler_CreateCashAccount(t *testing.T) {
 	t.Run("returns 201 on success", func(t *testing.T) {
 		acctSvc := &mockAccountService{
-			createCashAccountFn: func(userID uint, name, desc, currency string, balance int64) (*models.Account, error) {
+			createCashAccountFn: func(userID models.UserID, name, desc, currency string, balance models.Cents, _ bool, _ models.Cents, _ *time.Time) (*models.Account, error) {
 				return &models.Account{
-					Base:     models.Base{ID: 1},
-					UserID:   userID,
+					Base:     models.Base{ID: testID(1)},
+					UserID:   string(userID),
 					Name:     name,
 					Type:     models.AccountTypeCash,
-					Balance:  balance,
+					Balance:  int64(balance),
 					Currency: currency,
 					IsActive: true,
 				}, nil
@@ -122,6 +176,129 @@ func TestAccountHandler_CreateCashAccount(t *testing.T) {
 		}
 	})
 
+	t.Run("passes overdraft protection, allowing negative by default", func(t *testing.T) {
+		tests := []struct {
+			body              string
+			wantAllowNegative bool
+			wantMinBalance    models.Cents
+		}{
+			{body: `{"name":"Checking"}`, wantAllowNegative: true},
+			{body: `{"name":"Checking","allow_negative":false,"min_balance":2000}`, wantAllowNegative: false, wantMinBalance: 2000},
+		}
+
+		for _, tt := range tests {
+			var gotAllowNegative bool
+			var gotMinBalance models.Cents
+			acctSvc := &mockAccountService{
+				createCashAccountFn: func(_ models.UserID, _, _, _ string, _ models.Cents, allowNegative bool, minBalance models.Cents, _ *time.Time) (*models.Account, error) {
+					gotAllowNegative, gotMinBalance = allowNegative, minBalance
+					return &models.Account{Base: models.Base{ID: testID(1)}}, nil
+				},
+			}
+			r := setupAccountRouter(NewAccountHandler(acctSvc, &mockAuditService{}))
+
+			rec := doRequest(r, "POST", "/accounts/cash", tt.body)
+
+			if rec.Code != http.StatusCreated {
+				t.Fatalf("%s: expected 201, got %d: %s", tt.body, rec.Code, rec.Body.String())
+			}
+			if gotAllowNegative != tt.wantAllowNegative || gotMinBalance != tt.wantMinBalance {
+				t.Errorf("%s: expected allow_negative=%v min_balance=%d, got %v and %d",
+					tt.body, tt.wantAllowNegative, tt.wantMinBalance, gotAllowNegative, gotMinBalance)
+			}
+		}
+	})
+
+	t.Run("passes opening_date", func(t *testing.T) {
+		tests := []struct {
+			body string
+			want time.Time // zero for none
+		}{
+			{body: `{"name":"Savings"}`},
+			{body: `{"name":"Savings","opening_date":""}`},
+			{body: `{"name":"Savings","opening_date":"2020-01-01"}`, want: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{body: `{"name":"Savings","opening_date":"2020-01-01T09:30:00+08:00"}`, want: time.Date(2020, 1, 1, 1, 30, 0, 0, time.UTC)},
+		}
+
+		for _, tt := range tests {
+			var got *time.Time
+			acctSvc := &mockAccountService{
+				createCashAccountFn: func(_ models.UserID, _, _, _ string, _ models.Cents, _ bool, _ models.Cents, openingDate *time.Time) (*models.Account, error) {
+					got = openingDate
+					return &models.Account{Base: models.Base{ID: testID(1)}}, nil
+				},
+			}
+			r := setupAccountRouter(NewAccountHandler(acctSvc, &mockAuditService{}))
+
+			rec := doRequest(r, "POST", "/accounts/cash", tt.body)
+
+			if rec.Code != http.StatusCreated {
+				t.Fatalf("%s: expected 201, got %d: %s", tt.body, rec.Code, rec.Body.String())
+			}
+			if (got == nil) != tt.want.IsZero() || (got != nil && !got.Equal(tt.want)) {
+				t.Errorf("%s: expected opening date %v, got %v", tt.body, tt.want, got)
+			}
+		}
+	})
+
+	t.Run("rejects invalid opening_date", func(t *testing.T) {
+		r := setupAccountRouter(NewAccountHandler(&mockAccountService{}, &mockAuditService{}))
+
+		rec := doRequest(r, "POST", "/accounts/cash", `{"name":"Savings","opening_date":"01/01/2020"}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("rejects negative min_balance", func(t *testing.T) {
+		r := setupAccountRouter(NewAccountHandler(&mockAccountService{}, &mockAuditService{}))
+
+		rec := doRequest(r, "POST", "/accounts/cash", `{"name":"Checking","allow_negative":false,"min_balance":-100}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("converts initial_balance_decimal using the requested currency", func(t *testing.T) {
+		tests := []struct {
+			body        string
+			wantStatus  int
+			wantBalance int64
+		}{
+			{body: `{"name":"Yen","currency":"JPY","initial_balance_decimal":"120000"}`, wantStatus: http.StatusCreated, wantBalance: 120000},
+			{body: `{"name":"Yen","currency":"JPY","initial_balance_decimal":"0.5"}`, wantStatus: http.StatusBadRequest},
+			{body: `{"name":"Dollars","initial_balance_decimal":"99.99"}`, wantStatus: http.StatusCreated, wantBalance: 9999},
+			{body: `{"name":"Dinar","currency":"BHD","initial_balance_decimal":"10.005"}`, wantStatus: http.StatusCreated, wantBalance: 10005},
+			{body: `{"name":"Dinar","currency":"BHD","initial_balance_decimal":"-1"}`, wantStatus: http.StatusBadRequest},
+			{body: `{"name":"Both","currency":"USD","initial_balance":100,"initial_balance_decimal":"1"}`, wantStatus: http.StatusBadRequest},
+		}
+
+		for _, tt := range tests {
+			var gotBalance int64
+			acctSvc := &mockAccountService{
+				createCashAccountFn: func(_ models.UserID, _, _, _ string, balance models.Cents, _ bool, _ models.Cents, _ *time.Time) (*models.Account, error) {
+					gotBalance = int64(balance)
+					return &models.Account{Base: models.Base{ID: testID(1)}, Balance: int64(balance)}, nil
+				},
+			}
+			handler := NewAccountHandler(acctSvc, &mockAuditService{})
+			r := setupAccountRouter(handler)
+
+			rec := doRequest(r, "POST", "/accounts/cash", tt.body)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("%s: expected %d, got %d: %s", tt.body, tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus == http.StatusCreated && gotBalance != tt.wantBalance {
+				t.Errorf("%s: expected balance %d, got %d", tt.body, tt.wantBalance, gotBalance)
+			}
+		}
+	})
+
 	t.Run("returns 400 on missing name", func(t *testing.T) {
 		handler := NewAccountHandler(&mockAccountService{}, &mockAuditService{})
 		r := setupAccountRouter(handler)
@@ -172,10 +349,10 @@ func TestAccountHandler_CreateCashAccount(t *testing.T) {
 func TestAccountHandler_CreateInvestmentAccount(t *testing.T) {
 	t.Run("returns 201 on success", func(t *testing.T) {
 		acctSvc := &mockAccountService{
-			createInvestmentAccountFn: func(userID uint, name, desc, currency, broker, acctNum string) (*models.Account, error) {
+			createInvestmentAccountFn: func(userID models.UserID, name, desc, currency, broker, acctNum string) (*models.Account, error) {
 				return &models.Account{
-					Base:     models.Base{ID: 2},
-					UserID:   userID,
+					Base:     models.Base{ID: testID(2)},
+					UserID:   string(userID),
 					Name:     name,
 					Type:     models.AccountTypeInvestment,
 					Currency: currency,
@@ -199,10 +376,10 @@ func TestAccountHandler_CreateInvestmentAccount(t *testing.T) {
 func TestAccountHandler_GetUserAccounts(t *testing.T) {
 	t.Run("returns 200 with paginated accounts", func(t *testing.T) {
 		acctSvc := &mockAccountService{
-			getUserAccountsFn: func(_ uint, _ pagination.PageRequest) (*pagination.PageResponse[models.Account], error) {
+			getUserAccountsFn: func(_ models.UserID, _ pagination.PageRequest, _ bool) (*pagination.PageResponse[models.Account], error) {
 				resp := pagination.NewPageResponse([]models.Account{
-					{Base: models.Base{ID: 1}, Name: "Cash"},
-					{Base: models.Base{ID: 2}, Name: "Investment"},
+					{Base: models.Base{ID: testID(1)}, Name: "Cash"},
+					{Base: models.Base{ID: testID(2)}, Name: "Investment"},
 				}, 1, 20, 2)
 				return &resp, nil
 			},
@@ -228,7 +405,7 @@ func TestAccountHandler_GetUserAccounts(t *testing.T) {
 	t.Run("passes pagination params to service", func(t *testing.T) {
 		var capturedPage pagination.PageRequest
 		acctSvc := &mockAccountService{
-			getUserAccountsFn: func(_ uint, page pagination.PageRequest) (*pagination.PageResponse[models.Account], error) {
+			getUserAccountsFn: func(_ models.UserID, page pagination.PageRequest, _ bool) (*pagination.PageResponse[models.Account], error) {
 				capturedPage = page
 				resp := pagination.NewPageResponse([]models.Account{}, 2, 5, 0)
 				return &resp, nil
@@ -246,14 +423,105 @@ func TestAccountHandler_GetUserAccounts(t *testing.T) {
 			t.Errorf("expected page_size=5, got %d", capturedPage.PageSize)
 		}
 	})
+
+	t.Run("passes include_inactive to service", func(t *testing.T) {
+		var captured bool
+		acctSvc := &mockAccountService{
+			getUserAccountsFn: func(_ models.UserID, _ pagination.PageRequest, includeInactive bool) (*pagination.PageResponse[models.Account], error) {
+				captured = includeInactive
+				resp := pagination.NewPageResponse([]models.Account{}, 1, 20, 0)
+				return &resp, nil
+			},
+		}
+		handler := NewAccountHandler(acctSvc, &mockAuditService{})
+		r := setupAccountRouter(handler)
+
+		rec := doRequest(r, "GET", "/accounts?include_inactive=true", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if !captured {
+			t.Error("expected include_inactive=true to be passed to service")
+		}
+	})
+
+	t.Run("returns groups when grouped", func(t *testing.T) {
+		acctSvc := &mockAccountService{
+			getGroupedAccountsFn: func(_ models.UserID, _ bool) ([]services.GroupedAccounts, error) {
+				return []services.GroupedAccounts{
+					{Group: models.AccountGroup{Base: models.Base{ID: testID(5)}, Name: "Retirement"}, Accounts: []models.Account{{Base: models.Base{ID: testID(1)}}}},
+					{Group: models.AccountGroup{Name: services.DefaultAccountGroupName}, Accounts: []models.Account{}},
+				}, nil
+			},
+		}
+		handler := NewAccountHandler(acctSvc, &mockAuditService{})
+		r := setupAccountRouter(handler)
+
+		rec := doRequest(r, "GET", "/accounts?grouped=true", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		groups := parseJSON(t, rec)["groups"].([]interface{})
+		if len(groups) != 2 {
+			t.Fatalf("expected 2 groups, got %d", len(groups))
+		}
+		first := groups[0].(map[string]interface{})
+		if len(first["accounts"].([]interface{})) != 1 {
+			t.Errorf("expected 1 account in first group, got %v", first["accounts"])
+		}
+	})
+
+	t.Run("returns 400 for invalid include_inactive", func(t *testing.T) {
+		handler := NewAccountHandler(&mockAccountService{}, &mockAuditService{})
+		r := setupAccountRouter(handler)
+
+		rec := doRequest(r, "GET", "/accounts?include_inactive=maybe", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+}
+
+func TestAccountHandler_GetArchivedAccounts(t *testing.T) {
+	t.Run("returns 200 with archived accounts", func(t *testing.T) {
+		archivedAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+		balance := int64(12500)
+		acctSvc := &mockAccountService{
+			getArchivedAccountsFn: func(_ models.UserID, _ pagination.PageRequest) (*pagination.PageResponse[models.Account], error) {
+				resp := pagination.NewPageResponse([]models.Account{
+					{Base: models.Base{ID: testID(1)}, Name: "Old Savings", ArchivedAt: &archivedAt, ArchivedBalance: &balance},
+				}, 1, 20, 1)
+				return &resp, nil
+			},
+		}
+		handler := NewAccountHandler(acctSvc, &mockAuditService{})
+		r := setupAccountRouter(handler)
+
+		rec := doRequest(r, "GET", "/accounts/archived", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		data := parseJSON(t, rec)["data"].([]interface{})
+		if len(data) != 1 {
+			t.Fatalf("expected 1 account, got %d", len(data))
+		}
+		if data[0].(map[string]interface{})["archived_balance"].(float64) != 12500 {
+			t.Errorf("expected archived_balance=12500, got %v", data[0])
+		}
+	})
 }
 
 func TestAccountHandler_GetAccountByID(t *testing.T) {
 	t.Run("returns 200 on success", func(t *testing.T) {
 		acctSvc := &mockAccountService{
-			getAccountByIDFn: func(_, accountID uint) (*models.Account, error) {
+			getAccountByIDFn: func(_ models.UserID, accountID models.AccountID) (*models.Account, error) {
 				return &models.Account{
-					Base: models.Base{ID: accountID},
+					Base: models.Base{ID: string(accountID)},
 					Name: "Savings",
 					Type: models.AccountTypeCash,
 				}, nil
@@ -262,7 +530,7 @@ func TestAccountHandler_GetAccountByID(t *testing.T) {
 		handler := NewAccountHandler(acctSvc, &mockAuditService{})
 		r := setupAccountRouter(handler)
 
-		rec := doRequest(r, "GET", "/accounts/1", "")
+		rec := doRequest(r, "GET", "/accounts/00000000-0000-7000-8000-000000000001", "")
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d", rec.Code)
@@ -276,14 +544,14 @@ func TestAccountHandler_GetAccountByID(t *testing.T) {
 
 	t.Run("returns 404 when not found", func(t *testing.T) {
 		acctSvc := &mockAccountService{
-			getAccountByIDFn: func(_, _ uint) (*models.Account, error) {
+			getAccountByIDFn: func(_ models.UserID, _ models.AccountID) (*models.Account, error) {
 				return nil, apperrors.ErrAccountNotFound
 			},
 		}
 		handler := NewAccountHandler(acctSvc, &mockAuditService{})
 		r := setupAccountRouter(handler)
 
-		rec := doRequest(r, "GET", "/accounts/999", "")
+		rec := doRequest(r, "GET", "/accounts/00000000-0000-7000-8000-000000000999", "")
 
 		if rec.Code != http.StatusNotFound {
 			t.Fatalf("expected 404, got %d", rec.Code)
@@ -303,10 +571,84 @@ func TestAccountHandler_GetAccountByID(t *testing.T) {
 	})
 }
 
+func TestAccountHandler_GetAccountTimeline(t *testing.T) {
+	t.Run("returns 200 with kinds", func(t *testing.T) {
+		var capturedPage pagination.PageRequest
+		acctSvc := &mockAccountService{
+			getAccountTimelineFn: func(_ models.UserID, accountID models.AccountID, page pagination.PageRequest) (*pagination.PageResponse[services.AccountTimelineEntry], error) {
+				capturedPage = page
+				entries := []services.AccountTimelineEntry{
+					{
+						Kind:                  services.TimelineEntryInvestmentTransaction,
+						InvestmentTransaction: &models.InvestmentTransaction{Type: models.InvestmentTransactionBuy},
+					},
+					{
+						Kind:        services.TimelineEntryTransaction,
+						Transaction: &models.Transaction{AccountID: string(accountID), Type: models.TransactionTypeExpense},
+					},
+				}
+				resp := pagination.NewPageResponse(entries, page.Page, page.PageSize, 2)
+				return &resp, nil
+			},
+		}
+		handler := NewAccountHandler(acctSvc, &mockAuditService{})
+		r := setupAccountRouter(handler)
+
+		rec := doRequest(r, "GET", "/accounts/"+testID(1)+"/timeline?page=2&page_size=5", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if capturedPage.Page != 2 || capturedPage.PageSize != 5 {
+			t.Errorf("expected page 2 of 5, got %+v", capturedPage)
+		}
+		data := parseJSON(t, rec)["data"].([]interface{})
+		if len(data) != 2 {
+			t.Fatalf("expected 2 entries, got %d", len(data))
+		}
+		first := data[0].(map[string]interface{})
+		if first["kind"] != "investment_transaction" || first["investment_transaction"] == nil || first["transaction"] != nil {
+			t.Errorf("unexpected first entry: %v", first)
+		}
+		second := data[1].(map[string]interface{})
+		if second["kind"] != "transaction" || second["transaction"] == nil || second["investment_transaction"] != nil {
+			t.Errorf("unexpected second entry: %v", second)
+		}
+	})
+
+	t.Run("returns 404 when not found", func(t *testing.T) {
+		acctSvc := &mockAccountService{
+			getAccountTimelineFn: func(_ models.UserID, _ models.AccountID, _ pagination.PageRequest) (*pagination.PageResponse[services.AccountTimelineEntry], error) {
+				return nil, apperrors.ErrAccountNotFound
+			},
+		}
+		handler := NewAccountHandler(acctSvc, &mockAuditService{})
+		r := setupAccountRouter(handler)
+
+		rec := doRequest(r, "GET", "/accounts/"+testID(999)+"/timeline", "")
+
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "ACCOUNT_NOT_FOUND")
+	})
+
+	t.Run("returns 400 on invalid page size", func(t *testing.T) {
+		handler := NewAccountHandler(&mockAccountService{}, &mockAuditService{})
+		r := setupAccountRouter(handler)
+
+		rec := doRequest(r, "GET", "/accounts/"+testID(1)+"/timeline?page_size=-1", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+	})
+}
+
 func TestAccountHandler_UpdateAccount(t *testing.T) {
 	t.Run("returns_200_with_name_update", func(t *testing.T) {
 		acctSvc := &mockAccountService{
-			updateAccountFn: func(_, accountID uint, updates services.AccountUpdateFields) (*models.Account, error) {
+			updateAccountFn: func(_ models.UserID, accountID models.AccountID, updates services.AccountUpdateFields) (*models.Account, error) {
 				name := ""
 				if updates.Name != nil {
 					name = *updates.Name
@@ -316,7 +658,7 @@ func TestAccountHandler_UpdateAccount(t *testing.T) {
 					desc = *updates.Description
 				}
 				return &models.Account{
-					Base:        models.Base{ID: accountID},
+					Base:        models.Base{ID: string(accountID)},
 					Name:        name,
 					Description: desc,
 					Type:        models.AccountTypeCash,
@@ -326,7 +668,7 @@ func TestAccountHandler_UpdateAccount(t *testing.T) {
 		handler := NewAccountHandler(acctSvc, &mockAuditService{})
 		r := setupAccountRouter(handler)
 
-		rec := doRequest(r, "PUT", "/accounts/1", `{"name":"Updated","description":"New desc"}`)
+		rec := doRequest(r, "PUT", "/accounts/00000000-0000-7000-8000-000000000001", `{"name":"Updated","description":"New desc"}`)
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
@@ -341,10 +683,10 @@ func TestAccountHandler_UpdateAccount(t *testing.T) {
 	t.Run("returns_200_with_investment_fields", func(t *testing.T) {
 		var captured services.AccountUpdateFields
 		acctSvc := &mockAccountService{
-			updateAccountFn: func(_, accountID uint, updates services.AccountUpdateFields) (*models.Account, error) {
+			updateAccountFn: func(_ models.UserID, accountID models.AccountID, updates services.AccountUpdateFields) (*models.Account, error) {
 				captured = updates
 				return &models.Account{
-					Base: models.Base{ID: accountID},
+					Base: models.Base{ID: string(accountID)},
 					Type: models.AccountTypeInvestment,
 				}, nil
 			},
@@ -352,7 +694,7 @@ func TestAccountHandler_UpdateAccount(t *testing.T) {
 		handler := NewAccountHandler(acctSvc, &mockAuditService{})
 		r := setupAccountRouter(handler)
 
-		rec := doRequest(r, "PUT", "/accounts/1", `{"broker":"Schwab","account_number":"XYZ"}`)
+		rec := doRequest(r, "PUT", "/accounts/00000000-0000-7000-8000-000000000001", `{"broker":"Schwab","account_number":"XYZ"}`)
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
@@ -368,10 +710,10 @@ func TestAccountHandler_UpdateAccount(t *testing.T) {
 	t.Run("returns_200_with_credit_card_fields", func(t *testing.T) {
 		var captured services.AccountUpdateFields
 		acctSvc := &mockAccountService{
-			updateAccountFn: func(_, accountID uint, updates services.AccountUpdateFields) (*models.Account, error) {
+			updateAccountFn: func(_ models.UserID, accountID models.AccountID, updates services.AccountUpdateFields) (*models.Account, error) {
 				captured = updates
 				return &models.Account{
-					Base: models.Base{ID: accountID},
+					Base: models.Base{ID: string(accountID)},
 					Type: models.AccountTypeCreditCard,
 				}, nil
 			},
@@ -379,7 +721,7 @@ func TestAccountHandler_UpdateAccount(t *testing.T) {
 		handler := NewAccountHandler(acctSvc, &mockAuditService{})
 		r := setupAccountRouter(handler)
 
-		rec := doRequest(r, "PUT", "/accounts/1", `{"interest_rate":22.5,"credit_limit":1000000}`)
+		rec := doRequest(r, "PUT", "/accounts/00000000-0000-7000-8000-000000000001", `{"interest_rate":22.5,"credit_limit":1000000}`)
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
@@ -394,14 +736,14 @@ func TestAccountHandler_UpdateAccount(t *testing.T) {
 
 	t.Run("returns_404_when_not_found", func(t *testing.T) {
 		acctSvc := &mockAccountService{
-			updateAccountFn: func(_, _ uint, _ services.AccountUpdateFields) (*models.Account, error) {
+			updateAccountFn: func(_ models.UserID, _ models.AccountID, _ services.AccountUpdateFields) (*models.Account, error) {
 				return nil, apperrors.ErrAccountNotFound
 			},
 		}
 		handler := NewAccountHandler(acctSvc, &mockAuditService{})
 		r := setupAccountRouter(handler)
 
-		rec := doRequest(r, "PUT", "/accounts/999", `{"name":"Updated"}`)
+		rec := doRequest(r, "PUT", "/accounts/00000000-0000-7000-8000-000000000999", `{"name":"Updated"}`)
 
 		if rec.Code != http.StatusNotFound {
 			t.Fatalf("expected 404, got %d", rec.Code)
@@ -410,17 +752,83 @@ func TestAccountHandler_UpdateAccount(t *testing.T) {
 	})
 }
 
+func TestAccountHandler_DeleteAccount(t *testing.T) {
+	t.Run("returns_200_without_force_by_default", func(t *testing.T) {
+		var gotID models.AccountID
+		gotForce := true
+		acctSvc := &mockAccountService{
+			deleteAccountFn: func(_ models.UserID, accountID models.AccountID, force bool) error {
+				gotID, gotForce = accountID, force
+				return nil
+			},
+		}
+		r := setupAccountRouter(NewAccountHandler(acctSvc, &mockAuditService{}))
+
+		rec := doRequest(r, "DELETE", "/accounts/00000000-0000-7000-8000-000000000001", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotID != "00000000-0000-7000-8000-000000000001" || gotForce {
+			t.Errorf("expected a plain delete of the account, got %s with force=%v", gotID, gotForce)
+		}
+	})
+
+	t.Run("passes_force", func(t *testing.T) {
+		var gotForce bool
+		acctSvc := &mockAccountService{
+			deleteAccountFn: func(_ models.UserID, _ models.AccountID, force bool) error {
+				gotForce = force
+				return nil
+			},
+		}
+		r := setupAccountRouter(NewAccountHandler(acctSvc, &mockAuditService{}))
+
+		rec := doRequest(r, "DELETE", "/accounts/00000000-0000-7000-8000-000000000001?force=true", "")
+
+		if rec.Code != http.StatusOK || !gotForce {
+			t.Fatalf("expected 200 with force, got %d and force=%v", rec.Code, gotForce)
+		}
+	})
+
+	t.Run("returns_409_when_account_has_transactions", func(t *testing.T) {
+		acctSvc := &mockAccountService{
+			deleteAccountFn: func(_ models.UserID, _ models.AccountID, _ bool) error {
+				return apperrors.ErrAccountHasTransactions
+			},
+		}
+		r := setupAccountRouter(NewAccountHandler(acctSvc, &mockAuditService{}))
+
+		rec := doRequest(r, "DELETE", "/accounts/00000000-0000-7000-8000-000000000001", "")
+
+		if rec.Code != http.StatusConflict {
+			t.Fatalf("expected 409, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "ACCOUNT_HAS_TRANSACTIONS")
+	})
+
+	t.Run("returns_400_on_invalid_force", func(t *testing.T) {
+		r := setupAccountRouter(NewAccountHandler(&mockAccountService{}, &mockAuditService{}))
+
+		rec := doRequest(r, "DELETE", "/accounts/00000000-0000-7000-8000-000000000001?force=yes", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+	})
+}
+
 func TestAccountHandler_CreateCreditCardAccount(t *testing.T) {
 	t.Run("returns 201 with valid request", func(t *testing.T) {
 		acctSvc := &mockAccountService{
-			createCreditCardAccountFn: func(userID uint, name, desc, currency string, creditLimit int64, interestRate float64, dueDate *time.Time) (*models.Account, error) {
+			createCreditCardAccountFn: func(userID models.UserID, name, desc, currency string, creditLimit models.Cents, interestRate float64, dueDate, _ *time.Time) (*models.Account, error) {
 				return &models.Account{
-					Base:         models.Base{ID: 3},
-					UserID:       userID,
+					Base:         models.Base{ID: testID(3)},
+					UserID:       string(userID),
 					Name:         name,
 					Type:         models.AccountTypeCreditCard,
 					Currency:     "USD",
-					CreditLimit:  creditLimit,
+					CreditLimit:  int64(creditLimit),
 					InterestRate: interestRate,
 					IsActive:     true,
 				}, nil
@@ -442,6 +850,34 @@ func TestAccountHandler_CreateCreditCardAccount(t *testing.T) {
 		}
 	})
 
+	t.Run("passes opening_date", func(t *testing.T) {
+		var got *time.Time
+		acctSvc := &mockAccountService{
+			createCreditCardAccountFn: func(_ models.UserID, _, _, _ string, _ models.Cents, _ float64, _, openingDate *time.Time) (*models.Account, error) {
+				got = openingDate
+				return &models.Account{Base: models.Base{ID: testID(3)}, OpeningDate: openingDate}, nil
+			},
+		}
+		r := setupAccountRouter(NewAccountHandler(acctSvc, &mockAuditService{}))
+
+		rec := doRequest(r, "POST", "/accounts/credit-card", `{"name":"Visa","opening_date":"2020-01-01"}`)
+
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if want := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC); got == nil || !got.Equal(want) {
+			t.Errorf("expected opening date %v, got %v", want, got)
+		}
+		if acct := parseJSON(t, rec)["account"].(map[string]interface{}); acct["opening_date"] != "2020-01-01T00:00:00Z" {
+			t.Errorf("expected opening_date in the response, got %v", acct["opening_date"])
+		}
+
+		rec = doRequest(r, "POST", "/accounts/credit-card", `{"name":"Visa","opening_date":"soon"}`)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for an invalid opening_date, got %d", rec.Code)
+		}
+	})
+
 	t.Run("returns 400 for missing name", func(t *testing.T) {
 		handler := NewAccountHandler(&mockAccountService{}, &mockAuditService{})
 		r := setupAccountRouter(handler)
@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+	"kuberan/internal/money"
+	"kuberan/internal/pdf"
+	"kuberan/internal/services"
+)
+
+// statementFormats maps each supported statement format to its content type
+// and renderer. All of them render the same AccountStatement.
+var statementFormats = map[string]struct {
+	contentType string
+	render      func(io.Writer, *services.AccountStatement) error
+}{
+	"pdf":  {"application/pdf", renderStatementPDF},
+	"html": {"text/html; charset=utf-8", renderStatementHTML},
+	"csv":  {"text/csv; charset=utf-8", renderStatementCSV},
+}
+
+// GetAccountStatement handles rendering a monthly statement of one account.
+// @Summary     Get account statement
+// @Description Bank-style statement of an account for a calendar month (UTC): opening balance, settled transactions with running balance, closing balance and totals, rendered as PDF, HTML or CSV. Amounts are in major units of the account's currency; for credit cards the balance is the amount owed.
+// @Tags        accounts
+// @Produce     application/pdf
+// @Produce     text/html
+// @Produce     text/csv
+// @Security    BearerAuth
+// @Param       id     path  string true  "Account ID"
+// @Param       month  query string false "Month in YYYY-MM format (default: current month)"
+// @Param       format query string false "pdf (default), html or csv"
+// @Success     200 {file} file "Rendered statement"
+// @Failure     400 {object} ErrorResponse "Invalid month, format or account type"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     404 {object} ErrorResponse "Account not found"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /accounts/{id}/statement [get]
+func (h *AccountHandler) GetAccountStatement(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	accountID, err := parsePathID(c, "id")
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	month := time.Now().UTC()
+	if raw := c.Query("month"); raw != "" {
+		if month, err = time.Parse("2006-01", raw); err != nil {
+			respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "month must be in YYYY-MM format"))
+			return
+		}
+	}
+
+	formatName := c.DefaultQuery("format", "pdf")
+	format, ok := statementFormats[formatName]
+	if !ok {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "format must be one of: pdf, html, csv"))
+		return
+	}
+
+	statement, err := h.accountService.GetStatement(models.UserID(userID), models.AccountID(accountID), month)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	// Render into memory first so a rendering failure can still be reported as JSON
+	var body bytes.Buffer
+	if err := format.render(&body, statement); err != nil {
+		respondWithError(c, apperrors.Wrap(apperrors.ErrInternalServer, err))
+		return
+	}
+
+	filename := fmt.Sprintf("statement-%s-%s.%s", statement.AccountID, statement.Month, formatName)
+	disposition := "attachment"
+	if formatName == "html" {
+		disposition = "inline"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`%s; filename="%s"`, disposition, filename))
+	c.Data(http.StatusOK, format.contentType, body.Bytes())
+}
+
+// statementAccountLabels names account types on rendered statements.
+var statementAccountLabels = map[models.AccountType]string{
+	models.AccountTypeCash:       "Cash account",
+	models.AccountTypeCreditCard: "Credit card",
+	models.AccountTypeDebt:       "Loan account",
+}
+
+// statementLineDetail describes a statement line beyond its description: the
+// counterparty of a transfer, otherwise its category.
+func statementLineDetail(s *services.AccountStatement, line *services.AccountStatementLine) string {
+	if line.Counterparty != "" {
+		// Money arriving raises a balance but lowers what a credit card owes
+		incoming := line.Amount > 0
+		if s.Type == models.AccountTypeCreditCard {
+			incoming = !incoming
+		}
+		if incoming {
+			return "from " + line.Counterparty
+		}
+		return "to " + line.Counterparty
+	}
+	return line.Category
+}
+
+// renderStatementCSV writes one row per line, with opening and closing
+// balance rows around them.
+func renderStatementCSV(w io.Writer, s *services.AccountStatement) error {
+	amount := func(units int64) string { return money.FormatDecimal(units, s.Currency) }
+
+	cw := csv.NewWriter(w)
+	rows := [][]string{
+		{"date", "type", "description", "category", "counterparty", "status", "amount", "balance"},
+		{s.PeriodStart.Format("2006-01-02"), "", "Opening balance", "", "", "", "", amount(s.OpeningBalance)},
+	}
+	for i := range s.Lines {
+		line := &s.Lines[i]
+		rows = append(rows, []string{
+			line.Date.Format("2006-01-02"), string(line.Type), line.Description, line.Category,
+			line.Counterparty, string(line.Status), amount(line.Amount), amount(line.Balance),
+		})
+	}
+	rows = append(rows, []string{s.PeriodEnd.Format("2006-01-02"), "", "Closing balance", "", "", "", "", amount(s.ClosingBalance)})
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+var statementHTMLTemplate = template.Must(template.New("statement").Funcs(template.FuncMap{
+	"date":   func(t time.Time) string { return t.Format("2006-01-02") },
+	"detail": statementLineDetail,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Statement {{.S.AccountName}} {{.S.Month}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { padding: 4px 8px; border-bottom: 1px solid #ddd; text-align: left; }
+.num { text-align: right; font-variant-numeric: tabular-nums; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>{{.S.AccountName}}</h1>
+<p>{{.Kind}} &middot; {{.S.Currency}} &middot; {{date .S.PeriodStart}} to {{date .S.PeriodEnd}}</p>
+<table>
+<thead><tr><th>Date</th><th>Description</th><th>Details</th><th class="num">Amount</th><th class="num">Balance</th></tr></thead>
+<tbody>
+<tr><td>{{date .S.PeriodStart}}</td><td>Opening balance</td><td></td><td></td><td class="num">{{call .Amount .S.OpeningBalance}}</td></tr>
+{{- range .S.Lines}}
+<tr><td>{{date .Date}}</td><td>{{.Description}}</td><td>{{detail $.S .}}</td><td class="num">{{call $.Amount .Amount}}</td><td class="num">{{call $.Amount .Balance}}</td></tr>
+{{- end}}
+<tr><td>{{date .S.PeriodEnd}}</td><td><strong>Closing balance</strong></td><td></td><td></td><td class="num"><strong>{{call .Amount .S.ClosingBalance}}</strong></td></tr>
+</tbody>
+</table>
+<p>Total in: {{call .Amount .S.TotalIn}} &middot; Total out: {{call .Amount .S.TotalOut}}</p>
+</body>
+</html>
+`))
+
+// renderStatementHTML writes a printable HTML page.
+func renderStatementHTML(w io.Writer, s *services.AccountStatement) error {
+	return statementHTMLTemplate.Execute(w, map[string]interface{}{
+		"S":      s,
+		"Kind":   statementAccountLabels[s.Type],
+		"Amount": func(units int64) string { return money.FormatDecimal(units, s.Currency) },
+	})
+}
+
+// Layout of the PDF statement, in points.
+const (
+	statementMargin   = 40.0
+	statementFontSize = 8.0
+	statementRow      = 12.0
+)
+
+// renderStatementPDF writes the statement as an A4 PDF, continuing the
+// transaction table on further pages as needed.
+func renderStatementPDF(w io.Writer, s *services.AccountStatement) error {
+	amount := func(units int64) string { return money.FormatDecimal(units, s.Currency) }
+	doc := pdf.New()
+	right := pdf.PageWidth - statementMargin
+
+	// Column x positions; amount and balance are right-aligned at their x
+	const (
+		colDate        = statementMargin
+		colDescription = statementMargin + 60
+		colDetail      = statementMargin + 250
+		colAmount      = pdf.PageWidth - statementMargin - 85
+	)
+	clip := func(text string, width float64) string {
+		limit := int(width / (pdf.CharWidth * statementFontSize))
+		if runes := []rune(text); len(runes) > limit {
+			return string(runes[:limit-1]) + "~"
+		}
+		return text
+	}
+
+	y := pdf.PageHeight - statementMargin
+	tableHeader := func() {
+		doc.Text(colDate, y, statementFontSize, true, "Date")
+		doc.Text(colDescription, y, statementFontSize, true, "Description")
+		doc.Text(colDetail, y, statementFontSize, true, "Details")
+		doc.TextRight(colAmount, y, statementFontSize, true, "Amount")
+		doc.TextRight(right, y, statementFontSize, true, "Balance")
+		doc.Line(statementMargin, y-4, right, y-4)
+		y -= statementRow + 4
+	}
+	row := func(date, description, detail, amt, balance string, bold bool) {
+		if y < statementMargin+statementRow {
+			doc.AddPage()
+			y = pdf.PageHeight - statementMargin
+			tableHeader()
+		}
+		doc.Text(colDate, y, statementFontSize, bold, date)
+		doc.Text(colDescription, y, statementFontSize, bold, clip(description, colDetail-colDescription-8))
+		doc.Text(colDetail, y, statementFontSize, bold, clip(detail, colAmount-colDetail-80))
+		doc.TextRight(colAmount, y, statementFontSize, bold, amt)
+		doc.TextRight(right, y, statementFontSize, bold, balance)
+		y -= statementRow
+	}
+
+	doc.Text(statementMargin, y, 14, true, s.AccountName)
+	y -= 18
+	doc.Text(statementMargin, y, 9, false, fmt.Sprintf("%s, %s. Statement for %s to %s",
+		statementAccountLabels[s.Type], s.Currency, s.PeriodStart.Format("2006-01-02"), s.PeriodEnd.Format("2006-01-02")))
+	y -= 24
+
+	tableHeader()
+	row(s.PeriodStart.Format("2006-01-02"), "Opening balance", "", "", amount(s.OpeningBalance), false)
+	for i := range s.Lines {
+		line := &s.Lines[i]
+		row(line.Date.Format("2006-01-02"), line.Description, statementLineDetail(s, line),
+			amount(line.Amount), amount(line.Balance), false)
+	}
+	row(s.PeriodEnd.Format("2006-01-02"), "Closing balance", "", "", amount(s.ClosingBalance), true)
+
+	y -= statementRow
+	row("", "Total in", "", amount(s.TotalIn), "", false)
+	row("", "Total out", "", amount(s.TotalOut), "", false)
+
+	_, err := doc.WriteTo(w)
+	return err
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/sfnt"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+	"kuberan/internal/services"
+)
+
+// pdfGlyphs returns s as the hex glyph IDs the PDF content stream draws it with.
+func pdfGlyphs(t *testing.T, s string) []byte {
+	t.Helper()
+	f, err := sfnt.Parse(gomono.TTF)
+	if err != nil {
+		t.Fatalf("parse font: %v", err)
+	}
+	var b bytes.Buffer
+	for _, r := range s {
+		g, err := f.GlyphIndex(nil, r)
+		if err != nil {
+			t.Fatalf("glyph for %q: %v", r, err)
+		}
+		fmt.Fprintf(&b, "%04X", uint16(g))
+	}
+	return b.Bytes()
+}
+
+func testAccountStatement(lines int) *services.AccountStatement {
+	start := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	s := &services.AccountStatement{
+		AccountID:      testID(1),
+		AccountName:    "Everyday (main)",
+		Type:           models.AccountTypeCash,
+		Currency:       "USD",
+		Month:          "2025-10",
+		PeriodStart:    start,
+		PeriodEnd:      start.AddDate(0, 1, 0).Add(-time.Nanosecond),
+		OpeningBalance: 100000,
+	}
+	balance := s.OpeningBalance
+	for i := 0; i < lines; i++ {
+		line := services.AccountStatementLine{
+			TransactionID: testID(100 + i),
+			Date:          start.AddDate(0, 0, i%28),
+			Type:          models.TransactionTypeExpense,
+			Description:   "Groceries",
+			Category:      "Food",
+			Status:        models.TransactionStatusCleared,
+			Amount:        -1250,
+		}
+		if i == 0 {
+			line.Type, line.Description, line.Category = models.TransactionTypeTransfer, "To savings", ""
+			line.Counterparty = "Savings"
+		}
+		balance += line.Amount
+		line.Balance = balance
+		s.TotalOut -= line.Amount
+		s.Lines = append(s.Lines, line)
+	}
+	s.ClosingBalance = balance
+	return s
+}
+
+func TestAccountHandler_GetAccountStatement(t *testing.T) {
+	statementSvc := func(lines int) *mockAccountService {
+		return &mockAccountService{
+			getStatementFn: func(_ models.UserID, _ models.AccountID, _ time.Time) (*services.AccountStatement, error) {
+				return testAccountStatement(lines), nil
+			},
+		}
+	}
+
+	t.Run("renders a pdf by default", func(t *testing.T) {
+		var gotMonth time.Time
+		acctSvc := statementSvc(3)
+		acctSvc.getStatementFn = func(_ models.UserID, _ models.AccountID, month time.Time) (*services.AccountStatement, error) {
+			gotMonth = month
+			return testAccountStatement(3), nil
+		}
+		r := setupAccountRouter(NewAccountHandler(acctSvc, &mockAuditService{}))
+
+		rec := doRequest(r, "GET", "/accounts/"+testID(1)+"/statement?month=2025-10", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if got := rec.Header().Get("Content-Type"); got != "application/pdf" {
+			t.Errorf("expected application/pdf, got %q", got)
+		}
+		if got := rec.Header().Get("Content-Disposition"); !strings.Contains(got, "2025-10.pdf") {
+			t.Errorf("unexpected Content-Disposition %q", got)
+		}
+		if !gotMonth.Equal(time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("expected month 2025-10, got %v", gotMonth)
+		}
+		body := rec.Body.Bytes()
+		if !bytes.HasPrefix(body, []byte("%PDF-")) || !bytes.Contains(body, pdfGlyphs(t, "Everyday (main)")) {
+			t.Errorf("expected a PDF naming the account, got %d bytes", len(body))
+		}
+	})
+
+	t.Run("paginates long pdf statements", func(t *testing.T) {
+		r := setupAccountRouter(NewAccountHandler(statementSvc(150), &mockAuditService{}))
+
+		rec := doRequest(r, "GET", "/accounts/"+testID(1)+"/statement?month=2025-10&format=pdf", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rec.Code)
+		}
+		if !bytes.Contains(rec.Body.Bytes(), []byte("/Count 3")) {
+			t.Error("expected a three-page PDF")
+		}
+	})
+
+	t.Run("renders html", func(t *testing.T) {
+		r := setupAccountRouter(NewAccountHandler(statementSvc(2), &mockAuditService{}))
+
+		rec := doRequest(r, "GET", "/accounts/"+testID(1)+"/statement?month=2025-10&format=html", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rec.Code)
+		}
+		body := rec.Body.String()
+		for _, want := range []string{"<html", "Opening balance", "1000.00", "to Savings", "Closing balance", "987.50"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("expected html to contain %q", want)
+			}
+		}
+	})
+
+	t.Run("renders csv", func(t *testing.T) {
+		r := setupAccountRouter(NewAccountHandler(statementSvc(2), &mockAuditService{}))
+
+		rec := doRequest(r, "GET", "/accounts/"+testID(1)+"/statement?month=2025-10&format=csv", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rec.Code)
+		}
+		rows := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+		if len(rows) != 5 {
+			t.Fatalf("expected header, opening, 2 lines and closing, got %d rows", len(rows))
+		}
+		if want := "2025-10-01,transfer,To savings,,Savings,cleared,-12.50,987.50"; rows[2] != want {
+			t.Errorf("expected %q, got %q", want, rows[2])
+		}
+	})
+
+	t.Run("returns 400 for an unknown format", func(t *testing.T) {
+		r := setupAccountRouter(NewAccountHandler(statementSvc(0), &mockAuditService{}))
+
+		rec := doRequest(r, "GET", "/accounts/"+testID(1)+"/statement?format=xlsx", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("returns 400 for a malformed month", func(t *testing.T) {
+		r := setupAccountRouter(NewAccountHandler(statementSvc(0), &mockAuditService{}))
+
+		rec := doRequest(r, "GET", "/accounts/"+testID(1)+"/statement?month=2025-13", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("returns 404 when the account is not visible", func(t *testing.T) {
+		acctSvc := &mockAccountService{
+			getStatementFn: func(_ models.UserID, _ models.AccountID, _ time.Time) (*services.AccountStatement, error) {
+				return nil, apperrors.ErrAccountNotFound
+			},
+		}
+		r := setupAccountRouter(NewAccountHandler(acctSvc, &mockAuditService{}))
+
+		rec := doRequest(r, "GET", "/accounts/"+testID(1)+"/statement", "")
+
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "ACCOUNT_NOT_FOUND")
+	})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -30,9 +31,10 @@ type RegisterRequest struct {
 	LastName  string `json:"last_name" binding:"max=100"`
 }
 
-// LoginRequest represents the login request payload
+// LoginRequest represents the login request payload.
+// Email takes either the user's email address or their username.
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required,email"`
+	Email    string `json:"email" binding:"required,max=255"`
 	Password string `json:"password" binding:"required"`
 }
 
@@ -41,6 +43,12 @@ type RefreshRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
+// ChangePasswordRequest represents the request payload for changing the password.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=8,max=128"`
+}
+
 // UserResponse represents the user data in the response
 type UserResponse struct {
 	ID        uint   `json:"id"`
@@ -49,6 +57,41 @@ type UserResponse struct {
 	LastName  string `json:"last_name"`
 }
 
+// UpdateProfileRequest represents the request payload for updating the user's profile.
+// Email is accepted only so that attempts to change it can be rejected explicitly.
+type UpdateProfileRequest struct {
+	Email        *string           `json:"email"`
+	DisplayName  *string           `json:"display_name" binding:"omitempty,max=100"`
+	Username     *string           `json:"username" binding:"omitempty,username"`
+	BaseCurrency *string           `json:"base_currency" binding:"omitempty,iso4217"`
+	Locale       *string           `json:"locale" binding:"omitempty,locale"`
+	WeekStart    *models.WeekStart `json:"week_start" binding:"omitempty,week_start"`
+	Timezone     *string           `json:"timezone" binding:"omitempty,timezone"`
+	Preferences  json.RawMessage   `json:"preferences" swaggertype:"object"`
+	// CostBasisMethod applies to future sells only; past sells keep the method they used.
+	CostBasisMethod *models.CostBasisMethod `json:"cost_basis_method" binding:"omitempty,cost_basis_method"`
+	// LargeTransactionThreshold is the amount in minor units at or above which a new
+	// transaction triggers an email alert; 0 turns the alert off.
+	LargeTransactionThreshold *int64 `json:"large_transaction_threshold" binding:"omitempty,min=0"`
+}
+
+// ProfileResponse represents the user's profile and display preferences.
+type ProfileResponse struct {
+	ID                        string                 `json:"id"`
+	Email                     string                 `json:"email"`
+	Username                  *string                `json:"username"`
+	FirstName                 string                 `json:"first_name"`
+	LastName                  string                 `json:"last_name"`
+	DisplayName               string                 `json:"display_name"`
+	BaseCurrency              string                 `json:"base_currency"`
+	Locale                    string                 `json:"locale"`
+	WeekStart                 models.WeekStart       `json:"week_start"`
+	Timezone                  string                 `json:"timezone"`
+	CostBasisMethod           models.CostBasisMethod `json:"cost_basis_method"`
+	Preferences               json.RawMessage        `json:"preferences" swaggertype:"object"`
+	LargeTransactionThreshold int64                  `json:"large_transaction_threshold"`
+}
+
 // AuthResponse represents the authentication response with tokens.
 type AuthResponse struct {
 	AccessToken  string       `json:"access_token"`
@@ -102,7 +145,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // Login handles user login
 // @Summary     Login user
-// @Description Authenticate a user and get access and refresh tokens
+// @Description Authenticate a user by email or username and get access and refresh tokens
 // @Tags        auth
 // @Accept      json
 // @Produce     json
@@ -220,7 +263,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 // @Accept      json
 // @Produce     json
 // @Security    BearerAuth
-// @Success     200 {object} UserResponse "User profile"
+// @Success     200 {object} ProfileResponse "User profile"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
 // @Failure     500 {object} ErrorResponse "Server error"
 // @Router      /profile [get]
@@ -237,7 +280,133 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 		return
 	}
 
+	c.JSON(http.StatusOK, gin.H{"user": newProfileResponse(user)})
+}
+
+// UpdateProfile updates the user's display name and preferences
+// @Summary     Update user profile
+// @Description Update display name, username, base currency, locale, first day of week, and UI preferences. A username can be changed once every 30 days. Email cannot be changed here.
+// @Tags        user
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       request body UpdateProfileRequest true "Profile fields to update"
+// @Success     200 {object} ProfileResponse "Updated profile"
+// @Failure     400 {object} ErrorResponse "Invalid input"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     404 {object} ErrorResponse "User not found"
+// @Failure     409 {object} ErrorResponse "Username taken"
+// @Failure     429 {object} ErrorResponse "Username changed too recently"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /profile [put]
+func (h *AuthHandler) UpdateProfile(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+		return
+	}
+	if req.Email != nil {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "email cannot be changed here; use POST /profile/email"))
+		return
+	}
+
+	current, err := h.userService.GetUserByID(userID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	previousCurrency := current.BaseCurrency
+	previousUsername := current.Username
+
+	fields := services.ProfileUpdateFields{
+		DisplayName:               req.DisplayName,
+		Username:                  req.Username,
+		BaseCurrency:              req.BaseCurrency,
+		Locale:                    req.Locale,
+		WeekStart:                 req.WeekStart,
+		Timezone:                  req.Timezone,
+		CostBasisMethod:           req.CostBasisMethod,
+		LargeTransactionThreshold: req.LargeTransactionThreshold,
+	}
+	if req.Preferences != nil {
+		prefs := string(req.Preferences)
+		fields.Preferences = &prefs
+	}
+
+	user, err := h.userService.UpdateProfile(userID, fields)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(userID, "UPDATE_PROFILE", "user", userID, c.ClientIP(), nil)
+	if user.BaseCurrency != previousCurrency {
+		h.auditService.Log(userID, "CHANGE_BASE_CURRENCY", "user", userID, c.ClientIP(),
+			map[string]interface{}{"from": previousCurrency, "to": user.BaseCurrency})
+	}
+	if user.Username != nil && (previousUsername == nil || *previousUsername != *user.Username) {
+		h.auditService.Log(userID, "CHANGE_USERNAME", "user", userID, c.ClientIP(),
+			map[string]interface{}{"from": previousUsername, "to": *user.Username})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": newProfileResponse(user)})
+}
+
+// ChangePassword replaces the user's password
+// @Summary     Change password
+// @Description Replace the password after checking the current one. Other sessions are logged out; the response carries a new token pair for this one.
+// @Tags        user
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       request body ChangePasswordRequest true "Current and new password"
+// @Success     200 {object} AuthResponse "Password changed and tokens generated"
+// @Failure     400 {object} ErrorResponse "Invalid input"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     403 {object} ErrorResponse "Incorrect password"
+// @Failure     404 {object} ErrorResponse "User not found"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /profile/password [put]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+		return
+	}
+
+	if err := h.userService.ChangePassword(userID, req.CurrentPassword, req.NewPassword); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	user, err := h.userService.GetUserByID(userID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	accessToken, refreshToken, err := h.generateTokenPair(user)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(userID, "CHANGE_PASSWORD", "user", userID, c.ClientIP(), nil)
+
 	c.JSON(http.StatusOK, gin.H{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
 		"user": gin.H{
 			"id":         user.ID,
 			"email":      user.Email,
@@ -247,6 +416,29 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 	})
 }
 
+// newProfileResponse builds the profile payload, defaulting empty preferences to an empty object.
+func newProfileResponse(user *models.User) ProfileResponse {
+	prefs := json.RawMessage(user.Preferences)
+	if len(prefs) == 0 {
+		prefs = json.RawMessage("{}")
+	}
+	return ProfileResponse{
+		ID:                        user.ID,
+		Email:                     user.Email,
+		Username:                  user.Username,
+		FirstName:                 user.FirstName,
+		LastName:                  user.LastName,
+		DisplayName:               user.DisplayName,
+		BaseCurrency:              user.BaseCurrency,
+		Locale:                    user.Locale,
+		WeekStart:                 user.WeekStart,
+		Timezone:                  user.Timezone,
+		CostBasisMethod:           user.CostBasisMethod,
+		Preferences:               prefs,
+		LargeTransactionThreshold: user.LargeTransactionThreshold,
+	}
+}
+
 // generateTokenPair creates a new access/refresh token pair and stores
 // the refresh token hash in the database.
 func (h *AuthHandler) generateTokenPair(user *models.User) (accessToken, refreshToken string, err error) {
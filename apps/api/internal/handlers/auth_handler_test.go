@@ -13,6 +13,8 @@ import (
 
 	apperrors "kuberan/internal/errors"
 	"kuberan/internal/models"
+	"kuberan/internal/pagination"
+	"kuberan/internal/services"
 	"kuberan/internal/validator"
 )
 
@@ -21,11 +23,13 @@ import (
 type mockUserService struct {
 	createUserFn            func(email, password, firstName, lastName string) (*models.User, error)
 	getUserByEmailFn        func(email string) (*models.User, error)
-	getUserByIDFn           func(id uint) (*models.User, error)
+	getUserByIDFn           func(id string) (*models.User, error)
 	verifyPasswordFn        func(user *models.User, password string) bool
-	attemptLoginFn          func(email, password string) (*models.User, error)
-	storeRefreshTokenHashFn func(userID uint, tokenHash string) error
-	getRefreshTokenHashFn   func(userID uint) (string, error)
+	attemptLoginFn          func(login, password string) (*models.User, error)
+	storeRefreshTokenHashFn func(userID string, tokenHash string) error
+	getRefreshTokenHashFn   func(userID string) (string, error)
+	updateProfileFn         func(userID string, updates services.ProfileUpdateFields) (*models.User, error)
+	changePasswordFn        func(userID, currentPassword, newPassword string) error
 }
 
 func (m *mockUserService) CreateUser(email, password, firstName, lastName string) (*models.User, error) {
@@ -42,7 +46,7 @@ func (m *mockUserService) GetUserByEmail(email string) (*models.User, error) {
 	return &models.User{}, nil
 }
 
-func (m *mockUserService) GetUserByID(id uint) (*models.User, error) {
+func (m *mockUserService) GetUserByID(id string) (*models.User, error) {
 	if m.getUserByIDFn != nil {
 		return m.getUserByIDFn(id)
 	}
@@ -56,30 +60,44 @@ func (m *mockUserService) VerifyPassword(user *models.User, password string) boo
 	return true
 }
 
-func (m *mockUserService) AttemptLogin(email, password string) (*models.User, error) {
+func (m *mockUserService) AttemptLogin(login, password string) (*models.User, error) {
 	if m.attemptLoginFn != nil {
-		return m.attemptLoginFn(email, password)
+		return m.attemptLoginFn(login, password)
 	}
 	return &models.User{}, nil
 }
 
-func (m *mockUserService) StoreRefreshTokenHash(userID uint, tokenHash string) error {
+func (m *mockUserService) StoreRefreshTokenHash(userID string, tokenHash string) error {
 	if m.storeRefreshTokenHashFn != nil {
 		return m.storeRefreshTokenHashFn(userID, tokenHash)
 	}
 	return nil
 }
 
-func (m *mockUserService) GetRefreshTokenHash(userID uint) (string, error) {
+func (m *mockUserService) GetRefreshTokenHash(userID string) (string, error) {
 	if m.getRefreshTokenHashFn != nil {
 		return m.getRefreshTokenHashFn(userID)
 	}
 	return "", nil
 }
 
+func (m *mockUserService) UpdateProfile(userID string, updates services.ProfileUpdateFields) (*models.User, error) {
+	if m.updateProfileFn != nil {
+		return m.updateProfileFn(userID, updates)
+	}
+	return &models.User{}, nil
+}
+
+func (m *mockUserService) ChangePassword(userID, currentPassword, newPassword string) error {
+	if m.changePasswordFn != nil {
+		return m.changePasswordFn(userID, currentPassword, newPassword)
+	}
+	return nil
+}
+
 type mockAuditService struct{}
 
-func (m *mockAuditService) Log(_ uint, _, _ string, _ uint, _ string, _ map[string]interface{}) {}
+func (m *mockAuditService) Log(_ string, _, _ string, _ string, _ string, _ map[string]interface{}) {}
 
 // --- test helpers ---
 
@@ -92,11 +110,18 @@ func setupAuthRouter(handler *AuthHandler) *gin.Engine {
 	r := gin.New()
 	r.POST("/auth/register", handler.Register)
 	r.POST("/auth/login", handler.Login)
-	r.GET("/profile", injectUserID(1), handler.GetProfile)
+	r.GET("/profile", injectUserID(testID(1)), handler.GetProfile)
+	r.PUT("/profile", injectUserID(testID(1)), handler.UpdateProfile)
+	r.PUT("/profile/password", injectUserID(testID(1)), handler.ChangePassword)
 	return r
 }
 
-func injectUserID(uid uint) gin.HandlerFunc {
+// testID returns a deterministic, valid UUID for fixture n.
+func testID(n int) string {
+	return fmt.Sprintf("00000000-0000-7000-8000-%012d", n)
+}
+
+func injectUserID(uid string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Set("userID", uid)
 		c.Next()
@@ -131,6 +156,38 @@ func assertErrorCode(t *testing.T, result map[string]interface{}, code string) {
 	}
 }
 
+// assertPageSizeClamped requests path with a page_size over the maximum and
+// checks the route serves the maximum and warns about it in meta, and that a
+// page_size within the limit is served as asked without a warning.
+func assertPageSizeClamped(t *testing.T, r *gin.Engine, path string) {
+	t.Helper()
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+
+	rec := doRequest(r, "GET", path+sep+"page_size=10000", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: expected 200, got %d: %s", path, rec.Code, rec.Body.String())
+	}
+	body := parseJSON(t, rec)
+	if body["page_size"] != float64(pagination.MaxPageSize) {
+		t.Errorf("%s: expected page_size %d, got %v", path, pagination.MaxPageSize, body["page_size"])
+	}
+	if meta, ok := body["meta"].(map[string]interface{}); !ok || meta["warning"] == "" || meta["warning"] == nil {
+		t.Errorf("%s: expected a meta warning, got %v", path, body["meta"])
+	}
+
+	rec = doRequest(r, "GET", path+sep+"page_size=50", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: expected 200, got %d: %s", path, rec.Code, rec.Body.String())
+	}
+	body = parseJSON(t, rec)
+	if body["page_size"] != float64(50) || body["meta"] != nil {
+		t.Errorf("%s: expected page_size 50 without meta, got %v and %v", path, body["page_size"], body["meta"])
+	}
+}
+
 // --- tests ---
 
 func TestAuthHandler_Register(t *testing.T) {
@@ -138,7 +195,7 @@ func TestAuthHandler_Register(t *testing.T) {
 		userSvc := &mockUserService{
 			createUserFn: func(email, _, firstName, lastName string) (*models.User, error) {
 				return &models.User{
-					Base:      models.Base{ID: 1},
+					Base:      models.Base{ID: testID(1)},
 					Email:     email,
 					FirstName: firstName,
 					LastName:  lastName,
@@ -222,9 +279,9 @@ func TestAuthHandler_Register(t *testing.T) {
 		var storedHash string
 		userSvc := &mockUserService{
 			createUserFn: func(email, _, _, _ string) (*models.User, error) {
-				return &models.User{Base: models.Base{ID: 42}, Email: email}, nil
+				return &models.User{Base: models.Base{ID: testID(42)}, Email: email}, nil
 			},
-			storeRefreshTokenHashFn: func(_ uint, hash string) error {
+			storeRefreshTokenHashFn: func(_ string, hash string) error {
 				storedHash = hash
 				return nil
 			},
@@ -248,9 +305,9 @@ func TestAuthHandler_Register(t *testing.T) {
 	t.Run("returns 500 when token storage fails", func(t *testing.T) {
 		userSvc := &mockUserService{
 			createUserFn: func(email, _, _, _ string) (*models.User, error) {
-				return &models.User{Base: models.Base{ID: 1}, Email: email}, nil
+				return &models.User{Base: models.Base{ID: testID(1)}, Email: email}, nil
 			},
-			storeRefreshTokenHashFn: func(_ uint, _ string) error {
+			storeRefreshTokenHashFn: func(_ string, _ string) error {
 				return fmt.Errorf("db connection lost")
 			},
 		}
@@ -269,7 +326,7 @@ func TestAuthHandler_Login(t *testing.T) {
 	t.Run("returns 200 on success", func(t *testing.T) {
 		userSvc := &mockUserService{
 			attemptLoginFn: func(email, _ string) (*models.User, error) {
-				return &models.User{Base: models.Base{ID: 1}, Email: email, FirstName: "Test"}, nil
+				return &models.User{Base: models.Base{ID: testID(1)}, Email: email, FirstName: "Test"}, nil
 			},
 		}
 		handler := NewAuthHandler(userSvc, &mockAuditService{})
@@ -289,6 +346,27 @@ func TestAuthHandler_Login(t *testing.T) {
 		}
 	})
 
+	t.Run("accepts a username in the email field", func(t *testing.T) {
+		var gotLogin string
+		userSvc := &mockUserService{
+			attemptLoginFn: func(login, _ string) (*models.User, error) {
+				gotLogin = login
+				return &models.User{Base: models.Base{ID: testID(1)}, Email: "jane@example.com"}, nil
+			},
+		}
+		handler := NewAuthHandler(userSvc, &mockAuditService{})
+		r := setupAuthRouter(handler)
+
+		rec := doRequest(r, "POST", "/auth/login", `{"email":"jane_doe","password":"password123"}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotLogin != "jane_doe" {
+			t.Errorf("expected login jane_doe, got %q", gotLogin)
+		}
+	})
+
 	t.Run("returns 401 on invalid credentials", func(t *testing.T) {
 		userSvc := &mockUserService{
 			attemptLoginFn: func(_, _ string) (*models.User, error) {
@@ -339,7 +417,7 @@ func TestAuthHandler_GetProfile(t *testing.T) {
 	t.Run("returns 200 with user profile", func(t *testing.T) {
 		now := time.Now()
 		userSvc := &mockUserService{
-			getUserByIDFn: func(id uint) (*models.User, error) {
+			getUserByIDFn: func(id string) (*models.User, error) {
 				return &models.User{
 					Base:        models.Base{ID: id},
 					Email:       "test@example.com",
@@ -381,7 +459,7 @@ func TestAuthHandler_GetProfile(t *testing.T) {
 
 	t.Run("returns 404 when user not found", func(t *testing.T) {
 		userSvc := &mockUserService{
-			getUserByIDFn: func(_ uint) (*models.User, error) {
+			getUserByIDFn: func(_ string) (*models.User, error) {
 				return nil, apperrors.ErrUserNotFound
 			},
 		}
@@ -395,3 +473,386 @@ func TestAuthHandler_GetProfile(t *testing.T) {
 		}
 	})
 }
+
+// recordingAuditService captures audit actions for assertions.
+type recordingAuditService struct {
+	actions []string
+	changes []map[string]interface{}
+}
+
+func (m *recordingAuditService) Log(_ string, action, _ string, _ string, _ string, changes map[string]interface{}) {
+	m.actions = append(m.actions, action)
+	m.changes = append(m.changes, changes)
+}
+
+func TestAuthHandler_UpdateProfile(t *testing.T) {
+	t.Run("passes partial fields to service", func(t *testing.T) {
+		var captured services.ProfileUpdateFields
+		userSvc := &mockUserService{
+			getUserByIDFn: func(id string) (*models.User, error) {
+				return &models.User{Base: models.Base{ID: id}, BaseCurrency: "USD"}, nil
+			},
+			updateProfileFn: func(id string, updates services.ProfileUpdateFields) (*models.User, error) {
+				captured = updates
+				return &models.User{
+					Base:         models.Base{ID: id},
+					DisplayName:  "Johnny",
+					BaseCurrency: "USD",
+					Locale:       "en-GB",
+					WeekStart:    models.WeekStartSunday,
+					Preferences:  `{"theme":"dark"}`,
+				}, nil
+			},
+		}
+		handler := NewAuthHandler(userSvc, &mockAuditService{})
+		r := setupAuthRouter(handler)
+
+		rec := doRequest(r, "PUT", "/profile",
+			`{"display_name":"Johnny","locale":"en-GB","week_start":"sunday","preferences":{"theme":"dark"}}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if captured.DisplayName == nil || *captured.DisplayName != "Johnny" {
+			t.Errorf("expected display_name=Johnny, got %v", captured.DisplayName)
+		}
+		if captured.BaseCurrency != nil {
+			t.Error("expected base_currency to be nil when not provided")
+		}
+		if captured.Preferences == nil || *captured.Preferences != `{"theme":"dark"}` {
+			t.Errorf("expected raw preferences JSON, got %v", captured.Preferences)
+		}
+		user := parseJSON(t, rec)["user"].(map[string]interface{})
+		if user["week_start"] != "sunday" {
+			t.Errorf("expected week_start=sunday, got %v", user["week_start"])
+		}
+		prefs := user["preferences"].(map[string]interface{})
+		if prefs["theme"] != "dark" {
+			t.Errorf("expected preferences.theme=dark, got %v", prefs["theme"])
+		}
+	})
+
+	t.Run("rejects email change", func(t *testing.T) {
+		called := false
+		userSvc := &mockUserService{
+			updateProfileFn: func(_ string, _ services.ProfileUpdateFields) (*models.User, error) {
+				called = true
+				return &models.User{}, nil
+			},
+		}
+		handler := NewAuthHandler(userSvc, &mockAuditService{})
+		r := setupAuthRouter(handler)
+
+		rec := doRequest(r, "PUT", "/profile", `{"email":"new@example.com","display_name":"X"}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+		if called {
+			t.Error("expected service not to be called")
+		}
+	})
+
+	t.Run("returns 400 on invalid currency", func(t *testing.T) {
+		handler := NewAuthHandler(&mockUserService{}, &mockAuditService{})
+		r := setupAuthRouter(handler)
+
+		rec := doRequest(r, "PUT", "/profile", `{"base_currency":"XXX"}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("returns 400 on unknown locale", func(t *testing.T) {
+		handler := NewAuthHandler(&mockUserService{}, &mockAuditService{})
+		r := setupAuthRouter(handler)
+
+		rec := doRequest(r, "PUT", "/profile", `{"locale":"xx-YY"}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("passes username and returns it", func(t *testing.T) {
+		var captured services.ProfileUpdateFields
+		userSvc := &mockUserService{
+			getUserByIDFn: func(id string) (*models.User, error) {
+				return &models.User{Base: models.Base{ID: id}, BaseCurrency: "USD"}, nil
+			},
+			updateProfileFn: func(id string, updates services.ProfileUpdateFields) (*models.User, error) {
+				captured = updates
+				return &models.User{Base: models.Base{ID: id}, BaseCurrency: "USD", Username: updates.Username}, nil
+			},
+		}
+		handler := NewAuthHandler(userSvc, &mockAuditService{})
+		r := setupAuthRouter(handler)
+
+		rec := doRequest(r, "PUT", "/profile", `{"username":"Jane_Doe"}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if captured.Username == nil || *captured.Username != "Jane_Doe" {
+			t.Errorf("expected username=Jane_Doe, got %v", captured.Username)
+		}
+		if user := parseJSON(t, rec)["user"].(map[string]interface{}); user["username"] != "Jane_Doe" {
+			t.Errorf("expected username=Jane_Doe in response, got %v", user["username"])
+		}
+	})
+
+	t.Run("returns 400 on invalid username", func(t *testing.T) {
+		for _, username := range []string{"ab", "jane.doe", "jane@example.com", "", "a_very_long_username_over_thirty"} {
+			handler := NewAuthHandler(&mockUserService{}, &mockAuditService{})
+			r := setupAuthRouter(handler)
+
+			rec := doRequest(r, "PUT", "/profile", `{"username":"`+username+`"}`)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("%q: expected 400, got %d", username, rec.Code)
+			}
+		}
+	})
+
+	t.Run("returns 429 when username changed too recently", func(t *testing.T) {
+		userSvc := &mockUserService{
+			updateProfileFn: func(_ string, _ services.ProfileUpdateFields) (*models.User, error) {
+				return nil, apperrors.ErrUsernameChangeTooSoon
+			},
+		}
+		handler := NewAuthHandler(userSvc, &mockAuditService{})
+		r := setupAuthRouter(handler)
+
+		rec := doRequest(r, "PUT", "/profile", `{"username":"jane_doe"}`)
+
+		if rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("expected 429, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "USERNAME_CHANGE_TOO_SOON")
+	})
+
+	t.Run("returns 400 on invalid week start", func(t *testing.T) {
+		handler := NewAuthHandler(&mockUserService{}, &mockAuditService{})
+		r := setupAuthRouter(handler)
+
+		rec := doRequest(r, "PUT", "/profile", `{"week_start":"wednesday"}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("passes timezone", func(t *testing.T) {
+		var captured services.ProfileUpdateFields
+		userSvc := &mockUserService{
+			getUserByIDFn: func(id string) (*models.User, error) {
+				return &models.User{Base: models.Base{ID: id}, BaseCurrency: "USD"}, nil
+			},
+			updateProfileFn: func(id string, updates services.ProfileUpdateFields) (*models.User, error) {
+				captured = updates
+				return &models.User{Base: models.Base{ID: id}, BaseCurrency: "USD", Timezone: *updates.Timezone}, nil
+			},
+		}
+		r := setupAuthRouter(NewAuthHandler(userSvc, &mockAuditService{}))
+
+		rec := doRequest(r, "PUT", "/profile", `{"timezone":"Asia/Kuala_Lumpur"}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if captured.Timezone == nil || *captured.Timezone != "Asia/Kuala_Lumpur" {
+			t.Errorf("expected timezone=Asia/Kuala_Lumpur, got %v", captured.Timezone)
+		}
+		if user := parseJSON(t, rec)["user"].(map[string]interface{}); user["timezone"] != "Asia/Kuala_Lumpur" {
+			t.Errorf("expected timezone in the profile, got %v", user["timezone"])
+		}
+	})
+
+	t.Run("returns 400 on invalid timezone", func(t *testing.T) {
+		for _, body := range []string{`{"timezone":"Mars/Olympus_Mons"}`, `{"timezone":"Local"}`, `{"timezone":"+08:00"}`} {
+			r := setupAuthRouter(NewAuthHandler(&mockUserService{}, &mockAuditService{}))
+
+			rec := doRequest(r, "PUT", "/profile", body)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("%s: expected 400, got %d", body, rec.Code)
+			}
+			assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+		}
+	})
+
+	t.Run("passes cost basis method", func(t *testing.T) {
+		var captured services.ProfileUpdateFields
+		userSvc := &mockUserService{
+			getUserByIDFn: func(id string) (*models.User, error) {
+				return &models.User{Base: models.Base{ID: id}, BaseCurrency: "USD"}, nil
+			},
+			updateProfileFn: func(id string, updates services.ProfileUpdateFields) (*models.User, error) {
+				captured = updates
+				return &models.User{Base: models.Base{ID: id}, BaseCurrency: "USD", CostBasisMethod: models.CostBasisFIFO}, nil
+			},
+		}
+		handler := NewAuthHandler(userSvc, &mockAuditService{})
+		r := setupAuthRouter(handler)
+
+		rec := doRequest(r, "PUT", "/profile", `{"cost_basis_method":"fifo"}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if captured.CostBasisMethod == nil || *captured.CostBasisMethod != models.CostBasisFIFO {
+			t.Errorf("expected cost_basis_method=fifo, got %v", captured.CostBasisMethod)
+		}
+		user := parseJSON(t, rec)["user"].(map[string]interface{})
+		if user["cost_basis_method"] != "fifo" {
+			t.Errorf("expected cost_basis_method=fifo in response, got %v", user["cost_basis_method"])
+		}
+	})
+
+	t.Run("returns 400 on unknown cost basis method", func(t *testing.T) {
+		handler := NewAuthHandler(&mockUserService{}, &mockAuditService{})
+		r := setupAuthRouter(handler)
+
+		rec := doRequest(r, "PUT", "/profile", `{"cost_basis_method":"lifo"}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("passes large transaction threshold", func(t *testing.T) {
+		var captured services.ProfileUpdateFields
+		userSvc := &mockUserService{
+			getUserByIDFn: func(id string) (*models.User, error) {
+				return &models.User{Base: models.Base{ID: id}, BaseCurrency: "USD"}, nil
+			},
+			updateProfileFn: func(id string, updates services.ProfileUpdateFields) (*models.User, error) {
+				captured = updates
+				return &models.User{Base: models.Base{ID: id}, BaseCurrency: "USD", LargeTransactionThreshold: 100000}, nil
+			},
+		}
+		handler := NewAuthHandler(userSvc, &mockAuditService{})
+		r := setupAuthRouter(handler)
+
+		rec := doRequest(r, "PUT", "/profile", `{"large_transaction_threshold":100000}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if captured.LargeTransactionThreshold == nil || *captured.LargeTransactionThreshold != 100000 {
+			t.Errorf("expected threshold 100000, got %v", captured.LargeTransactionThreshold)
+		}
+		user := parseJSON(t, rec)["user"].(map[string]interface{})
+		if user["large_transaction_threshold"] != float64(100000) {
+			t.Errorf("expected large_transaction_threshold=100000, got %v", user["large_transaction_threshold"])
+		}
+	})
+
+	t.Run("returns 400 on negative large transaction threshold", func(t *testing.T) {
+		handler := NewAuthHandler(&mockUserService{}, &mockAuditService{})
+		r := setupAuthRouter(handler)
+
+		rec := doRequest(r, "PUT", "/profile", `{"large_transaction_threshold":-1}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("audits base currency change", func(t *testing.T) {
+		userSvc := &mockUserService{
+			getUserByIDFn: func(id string) (*models.User, error) {
+				return &models.User{Base: models.Base{ID: id}, BaseCurrency: "USD"}, nil
+			},
+			updateProfileFn: func(id string, updates services.ProfileUpdateFields) (*models.User, error) {
+				return &models.User{Base: models.Base{ID: id}, BaseCurrency: *updates.BaseCurrency}, nil
+			},
+		}
+		audit := &recordingAuditService{}
+		handler := NewAuthHandler(userSvc, audit)
+		r := setupAuthRouter(handler)
+
+		rec := doRequest(r, "PUT", "/profile", `{"base_currency":"SGD"}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		found := false
+		for i, action := range audit.actions {
+			if action == "CHANGE_BASE_CURRENCY" {
+				found = true
+				if audit.changes[i]["from"] != "USD" || audit.changes[i]["to"] != "SGD" {
+					t.Errorf("unexpected audit changes: %v", audit.changes[i])
+				}
+			}
+		}
+		if !found {
+			t.Errorf("expected CHANGE_BASE_CURRENCY audit entry, got %v", audit.actions)
+		}
+	})
+}
+
+func TestAuthHandler_ChangePassword(t *testing.T) {
+	t.Run("returns 200 with a new token pair", func(t *testing.T) {
+		var gotUser, gotCurrent, gotNew, storedHash string
+		userSvc := &mockUserService{
+			changePasswordFn: func(userID, current, newPassword string) error {
+				gotUser, gotCurrent, gotNew = userID, current, newPassword
+				return nil
+			},
+			getUserByIDFn: func(id string) (*models.User, error) {
+				return &models.User{Base: models.Base{ID: id}, Email: "test@example.com"}, nil
+			},
+			storeRefreshTokenHashFn: func(_ string, hash string) error {
+				storedHash = hash
+				return nil
+			},
+		}
+		audit := &recordingAuditService{}
+		r := setupAuthRouter(NewAuthHandler(userSvc, audit))
+
+		rec := doRequest(r, "PUT", "/profile/password", `{"current_password":"password123","new_password":"new-password"}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotUser != testID(1) || gotCurrent != "password123" || gotNew != "new-password" {
+			t.Errorf("unexpected service call: %q %q %q", gotUser, gotCurrent, gotNew)
+		}
+		result := parseJSON(t, rec)
+		if result["access_token"] == "" || result["refresh_token"] == "" || storedHash == "" {
+			t.Error("expected a new token pair to be issued and stored")
+		}
+		if len(audit.actions) != 1 || audit.actions[0] != "CHANGE_PASSWORD" {
+			t.Errorf("expected CHANGE_PASSWORD audit entry, got %v", audit.actions)
+		}
+	})
+
+	t.Run("returns 400 on short new password", func(t *testing.T) {
+		r := setupAuthRouter(NewAuthHandler(&mockUserService{}, &mockAuditService{}))
+
+		rec := doRequest(r, "PUT", "/profile/password", `{"current_password":"password123","new_password":"short"}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("returns 403 on incorrect current password", func(t *testing.T) {
+		userSvc := &mockUserService{
+			changePasswordFn: func(_, _, _ string) error { return apperrors.ErrIncorrectPassword },
+		}
+		r := setupAuthRouter(NewAuthHandler(userSvc, &mockAuditService{}))
+
+		rec := doRequest(r, "PUT", "/profile/password", `{"current_password":"wrong","new_password":"new-password"}`)
+
+		if rec.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INCORRECT_PASSWORD")
+	})
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+	"kuberan/internal/services"
+)
+
+// BackupHandler handles restorable per-user backups.
+type BackupHandler struct {
+	backupService services.BackupServicer
+	auditService  services.AuditServicer
+}
+
+// NewBackupHandler creates a new BackupHandler.
+func NewBackupHandler(backupService services.BackupServicer, auditService services.AuditServicer) *BackupHandler {
+	return &BackupHandler{backupService: backupService, auditService: auditService}
+}
+
+// CreateBackup downloads the user's books as a restorable archive.
+// @Summary     Download a backup
+// @Description Return the user's categories, accounts, transactions, budgets, investments and investment transactions as a versioned archive with a SHA-256 checksum per section. Unlike the export, the archive can be restored with POST /profile/restore.
+// @Tags        user
+// @Produce     json
+// @Security    BearerAuth
+// @Success     200 {object} services.BackupArchive
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     404 {object} ErrorResponse "User not found"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /profile/backup [get]
+func (h *BackupHandler) CreateBackup(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	archive, err := h.backupService.CreateBackup(models.UserID(userID))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(userID, "BACKUP_USER_DATA", "user", userID, c.ClientIP(), nil)
+
+	filename := fmt.Sprintf("kuberan-backup-%s.json", time.Now().UTC().Format("2006-01-02"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.JSON(http.StatusOK, archive)
+}
+
+// RestoreBackup recreates the records of a backup archive for the user.
+// @Summary     Restore a backup
+// @Description Recreate the archive's records under new IDs, one entity group at a time, replaying transactions so balances come out right. A user who already has accounts or categories is refused with 409 unless merge=false is passed, which replaces their books with the backup. A group that fails is rolled back and reported with completed=false; posting the same archive again resumes at that group.
+// @Tags        user
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       merge   query    bool                   false "Pass false to replace existing data"
+// @Param       request body     services.BackupArchive true  "Archive from GET /profile/backup"
+// @Success     200     {object} services.RestoreReport
+// @Failure     400     {object} ErrorResponse "Invalid or modified archive"
+// @Failure     401     {object} ErrorResponse "Unauthorized"
+// @Failure     409     {object} ErrorResponse "The user already has data"
+// @Failure     500     {object} ErrorResponse "Server error"
+// @Router      /profile/restore [post]
+func (h *BackupHandler) RestoreBackup(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	replace := false
+	if v := c.Query("merge"); v != "" {
+		merge, err := strconv.ParseBool(v)
+		if err != nil {
+			respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "merge must be true or false"))
+			return
+		}
+		if merge {
+			respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput,
+				"merging a backup into existing data is not supported; pass merge=false to replace it"))
+			return
+		}
+		replace = true
+	}
+
+	var archive services.BackupArchive
+	if err := bindJSON(c, &archive); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	report, err := h.backupService.RestoreBackup(models.UserID(userID), &archive, replace)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(userID, "RESTORE_USER_DATA", "user", userID, c.ClientIP(), map[string]interface{}{
+		"archive_id": report.ArchiveID,
+		"replace":    replace,
+		"completed":  report.Completed,
+	})
+
+	c.JSON(http.StatusOK, report)
+}
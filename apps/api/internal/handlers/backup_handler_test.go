@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+	"kuberan/internal/services"
+)
+
+// --- mock backup service ---
+
+type mockBackupService struct {
+	createBackupFn  func(userID models.UserID) (*services.BackupArchive, error)
+	restoreBackupFn func(userID models.UserID, archive *services.BackupArchive, replace bool) (*services.RestoreReport, error)
+}
+
+var _ services.BackupServicer = (*mockBackupService)(nil)
+
+func (m *mockBackupService) CreateBackup(userID models.UserID) (*services.BackupArchive, error) {
+	if m.createBackupFn != nil {
+		return m.createBackupFn(userID)
+	}
+	return &services.BackupArchive{Format: "kuberan-backup", SchemaVersion: services.BackupSchemaVersion}, nil
+}
+
+func (m *mockBackupService) RestoreBackup(userID models.UserID, archive *services.BackupArchive, replace bool) (*services.RestoreReport, error) {
+	if m.restoreBackupFn != nil {
+		return m.restoreBackupFn(userID, archive, replace)
+	}
+	return &services.RestoreReport{Completed: true}, nil
+}
+
+// --- router setup ---
+
+func setupBackupRouter(handler *BackupHandler) *gin.Engine {
+	r := gin.New()
+	auth := r.Group("", injectUserID(testID(1)))
+	auth.GET("/profile/backup", handler.CreateBackup)
+	auth.POST("/profile/restore", handler.RestoreBackup)
+	return r
+}
+
+func TestBackupHandler_CreateBackup(t *testing.T) {
+	t.Run("returns the archive as a download", func(t *testing.T) {
+		var gotUserID models.UserID
+		svc := &mockBackupService{
+			createBackupFn: func(userID models.UserID) (*services.BackupArchive, error) {
+				gotUserID = userID
+				return &services.BackupArchive{Format: "kuberan-backup", SchemaVersion: 1, Checksums: map[string]string{"accounts": "abc"}}, nil
+			},
+		}
+		r := setupBackupRouter(NewBackupHandler(svc, &mockAuditService{}))
+
+		rec := doRequest(r, "GET", "/profile/backup", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotUserID != models.UserID(testID(1)) {
+			t.Errorf("expected user %s, got %s", testID(1), gotUserID)
+		}
+		if !strings.HasPrefix(rec.Header().Get("Content-Disposition"), "attachment;") {
+			t.Errorf("expected an attachment, got %q", rec.Header().Get("Content-Disposition"))
+		}
+		if body := parseJSON(t, rec); body["schema_version"] != float64(1) || body["checksums"] == nil {
+			t.Errorf("expected the archive, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("returns service error", func(t *testing.T) {
+		svc := &mockBackupService{
+			createBackupFn: func(_ models.UserID) (*services.BackupArchive, error) {
+				return nil, apperrors.ErrUserNotFound
+			},
+		}
+		r := setupBackupRouter(NewBackupHandler(svc, &mockAuditService{}))
+
+		rec := doRequest(r, "GET", "/profile/backup", "")
+
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "USER_NOT_FOUND")
+	})
+
+	t.Run("returns 401 without auth", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/profile/backup", NewBackupHandler(&mockBackupService{}, &mockAuditService{}).CreateBackup)
+
+		rec := doRequest(r, "GET", "/profile/backup", "")
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rec.Code)
+		}
+	})
+}
+
+func TestBackupHandler_RestoreBackup(t *testing.T) {
+	body := `{"format":"kuberan-backup","schema_version":1,"checksums":{},"data":{"accounts":[{"id":"a1","name":"Cash"}]}}`
+
+	t.Run("restores without replacing by default", func(t *testing.T) {
+		var gotArchive *services.BackupArchive
+		gotReplace := true
+		svc := &mockBackupService{
+			restoreBackupFn: func(_ models.UserID, archive *services.BackupArchive, replace bool) (*services.RestoreReport, error) {
+				gotArchive, gotReplace = archive, replace
+				return &services.RestoreReport{ArchiveID: "abc", Completed: true, Groups: []services.RestoreGroupProgress{
+					{Group: "accounts", Status: services.RestoreStatusDone, Restored: 1},
+				}}, nil
+			},
+		}
+		r := setupBackupRouter(NewBackupHandler(svc, &mockAuditService{}))
+
+		rec := doRequest(r, "POST", "/profile/restore", body)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotReplace {
+			t.Error("expected no replace without merge=false")
+		}
+		if gotArchive == nil || len(gotArchive.Data.Accounts) != 1 || gotArchive.Data.Accounts[0].Name != "Cash" {
+			t.Errorf("expected the posted archive, got %+v", gotArchive)
+		}
+		if resp := parseJSON(t, rec); resp["completed"] != true || resp["archive_id"] != "abc" {
+			t.Errorf("expected the restore report, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("replaces with merge=false", func(t *testing.T) {
+		gotReplace := false
+		svc := &mockBackupService{
+			restoreBackupFn: func(_ models.UserID, _ *services.BackupArchive, replace bool) (*services.RestoreReport, error) {
+				gotReplace = replace
+				return &services.RestoreReport{Completed: true}, nil
+			},
+		}
+		r := setupBackupRouter(NewBackupHandler(svc, &mockAuditService{}))
+
+		rec := doRequest(r, "POST", "/profile/restore?merge=false", body)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if !gotReplace {
+			t.Error("expected replace with merge=false")
+		}
+	})
+
+	t.Run("rejects merge=true", func(t *testing.T) {
+		r := setupBackupRouter(NewBackupHandler(&mockBackupService{}, &mockAuditService{}))
+
+		rec := doRequest(r, "POST", "/profile/restore?merge=true", body)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("rejects invalid merge", func(t *testing.T) {
+		r := setupBackupRouter(NewBackupHandler(&mockBackupService{}, &mockAuditService{}))
+
+		rec := doRequest(r, "POST", "/profile/restore?merge=maybe", body)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("rejects malformed JSON", func(t *testing.T) {
+		r := setupBackupRouter(NewBackupHandler(&mockBackupService{}, &mockAuditService{}))
+
+		rec := doRequest(r, "POST", "/profile/restore", `{"format":`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("returns 409 for an account with data", func(t *testing.T) {
+		svc := &mockBackupService{
+			restoreBackupFn: func(_ models.UserID, _ *services.BackupArchive, _ bool) (*services.RestoreReport, error) {
+				return nil, apperrors.ErrRestoreTargetNotEmpty
+			},
+		}
+		r := setupBackupRouter(NewBackupHandler(svc, &mockAuditService{}))
+
+		rec := doRequest(r, "POST", "/profile/restore", body)
+
+		if rec.Code != http.StatusConflict {
+			t.Fatalf("expected 409, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "RESTORE_TARGET_NOT_EMPTY")
+	})
+
+	t.Run("returns 400 for a modified archive", func(t *testing.T) {
+		svc := &mockBackupService{
+			restoreBackupFn: func(_ models.UserID, _ *services.BackupArchive, _ bool) (*services.RestoreReport, error) {
+				return nil, apperrors.ErrInvalidBackup
+			},
+		}
+		r := setupBackupRouter(NewBackupHandler(svc, &mockAuditService{}))
+
+		rec := doRequest(r, "POST", "/profile/restore", body)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_BACKUP")
+	})
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -93,7 +94,10 @@ func (h *BudgetHandler) CreateBudget(c *gin.Context) {
 // @Param       period    query string false "Filter by period (monthly/yearly)"
 // @Param       page      query int    false "Page number (default 1)"
 // @Param       page_size query int    false "Items per page (default 20, max 100)"
-// @Success     200 {object} pagination.PageResponse[models.Budget] "Paginated budgets"
+// @Param       with_total query bool   false "Compute total_items and total_pages (default true); when false they are -1 and 0"
+// @Param       include    query string false "progress: add each budget's current-period spent, remaining and percentage"
+// @Param       include_pending query bool false "With include=progress, count pending expenses towards spend (default true)"
+// @Success     200 {object} pagination.PageResponse[services.BudgetListItem] "Paginated budgets; spent, remaining and percentage only with include=progress"
 // @Failure     400 {object} ErrorResponse "Invalid input"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
 // @Failure     500 {object} ErrorResponse "Server error"
@@ -105,9 +109,9 @@ func (h *BudgetHandler) GetBudgets(c *gin.Context) {
 		return
 	}
 
-	var page pagination.PageRequest
-	if err := c.ShouldBindQuery(&page); err != nil {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+	page, err := bindPage(c)
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
@@ -137,13 +141,65 @@ func (h *BudgetHandler) GetBudgets(c *gin.Context) {
 		period = &p
 	}
 
+	withProgress := false
+	switch c.Query("include") {
+	case "":
+	case "progress":
+		withProgress = true
+	default:
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "include must be 'progress'"))
+		return
+	}
+	includePending, err := includePendingQuery(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
 	result, err := h.budgetService.GetUserBudgets(userID, page, isActive, period)
 	if err != nil {
 		respondWithError(c, err)
 		return
 	}
+	if !withProgress {
+		respondWithPage(c, page, result)
+		return
+	}
+
+	progress, err := h.budgetService.GetBudgetsProgress(userID, result.Data, includePending)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	items := make([]services.BudgetListItem, len(result.Data))
+	for i, budget := range result.Data {
+		items[i] = services.BudgetListItem{Budget: budget}
+		if p := progress[budget.ID]; p != nil {
+			items[i].Spent, items[i].Remaining, items[i].Percentage = p.Spent, p.Remaining, p.Percentage
+		}
+	}
+	c.JSON(http.StatusOK, pagination.PageResponse[services.BudgetListItem]{
+		Data:       items,
+		Page:       result.Page,
+		PageSize:   result.PageSize,
+		TotalItems: result.TotalItems,
+		TotalPages: result.TotalPages,
+		Meta:       page.Meta(),
+	})
+}
 
-	c.JSON(http.StatusOK, result)
+// includePendingQuery parses the include_pending query parameter, which
+// defaults to true.
+func includePendingQuery(c *gin.Context) (bool, error) {
+	v := c.Query("include_pending")
+	if v == "" {
+		return true, nil
+	}
+	includePending, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, apperrors.WithMessage(apperrors.ErrInvalidInput, "include_pending must be true or false")
+	}
+	return includePending, nil
 }
 
 // GetBudget handles retrieving a specific budget.
@@ -267,12 +323,13 @@ func (h *BudgetHandler) DeleteBudget(c *gin.Context) {
 
 // GetBudgetProgress handles retrieving the spending progress for a budget.
 // @Summary     Get budget progress
-// @Description Get spending progress for a budget in the current period
+// @Description Get spending progress for a budget in the current period, with the pace of spending: days elapsed, the spend projected to the end of the period at the current daily rate, and whether that is on_track, at_risk or over
 // @Tags        budgets
 // @Accept      json
 // @Produce     json
 // @Security    BearerAuth
-// @Param       id path int true "Budget ID"
+// @Param       id              path  int  true  "Budget ID"
+// @Param       include_pending query bool false "Count pending expenses towards spend (default true)"
 // @Success     200 {object} services.BudgetProgress "Budget progress"
 // @Failure     400 {object} ErrorResponse "Invalid budget ID"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
@@ -292,7 +349,13 @@ func (h *BudgetHandler) GetBudgetProgress(c *gin.Context) {
 		return
 	}
 
-	progress, err := h.budgetService.GetBudgetProgress(userID, budgetID)
+	includePending, err := includePendingQuery(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	progress, err := h.budgetService.GetBudgetProgress(userID, budgetID, includePending)
 	if err != nil {
 		respondWithError(c, err)
 		return
@@ -300,3 +363,92 @@ func (h *BudgetHandler) GetBudgetProgress(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"progress": progress})
 }
+
+// GetBudgetBurndown handles retrieving the day-by-day spend of a budget's current period.
+// @Summary     Get budget burndown
+// @Description Cumulative settled spend per day of the budget's current period up to today, against the budgeted amount, with the end-of-period spend projected at the current daily pace
+// @Tags        budgets
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Budget ID"
+// @Success     200 {object} services.BudgetBurndown "Budget burndown"
+// @Failure     400 {object} ErrorResponse "Invalid budget ID"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     404 {object} ErrorResponse "Budget not found"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /budgets/{id}/burndown [get]
+func (h *BudgetHandler) GetBudgetBurndown(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	budgetID, err := parsePathID(c, "id")
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	burndown, err := h.budgetService.GetBudgetBurndown(userID, budgetID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"burndown": burndown})
+}
+
+// CloneLastMonth handles copying last month's monthly budgets into the current month.
+// @Summary     Clone last month's budgets
+// @Description Copy the authenticated user's monthly budgets from last month into the current month, skipping categories that already have one
+// @Tags        budgets
+// @Produce     json
+// @Security    BearerAuth
+// @Success     200 {object} map[string]interface{} "Created budgets and count"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /budgets/clone-last-month [post]
+func (h *BudgetHandler) CloneLastMonth(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	now := time.Now().UTC()
+	lastMonth := time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, time.UTC)
+	budgets, err := h.budgetService.CloneForPeriod(userID, lastMonth, now)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	if len(budgets) > 0 {
+		h.auditService.Log(userID, "CLONE_BUDGETS", "budget", "", c.ClientIP(),
+			map[string]interface{}{"created": len(budgets)})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"budgets": budgets, "created": len(budgets)})
+}
+
+// RolloverBudgets handles creating this month's budgets from last month's for every user.
+// @Summary     Roll over monthly budgets
+// @Description Ensure every user's monthly budgets from last month exist for the current month (pipeline endpoint, idempotent)
+// @Tags        pipeline
+// @Produce     json
+// @Security    ApiKeyAuth
+// @Success     200 {object} map[string]int "Created budgets count"
+// @Failure     401 {object} ErrorResponse "Invalid API key"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Failure     503 {object} ErrorResponse "Pipeline not configured"
+// @Router      /pipeline/budgets/rollover [post]
+func (h *BudgetHandler) RolloverBudgets(c *gin.Context) {
+	count, err := h.budgetService.RolloverMonthlyBudgets(time.Now())
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"created": count})
+}
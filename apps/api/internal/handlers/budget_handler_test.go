@@ -16,78 +16,113 @@ import (
 // --- mock budget service ---
 
 type mockBudgetService struct {
-	createBudgetFn      func(userID, categoryID uint, name string, amount int64, period models.BudgetPeriod, startDate time.Time, endDate *time.Time) (*models.Budget, error)
-	getUserBudgetsFn    func(userID uint, page pagination.PageRequest, isActive *bool, period *models.BudgetPeriod) (*pagination.PageResponse[models.Budget], error)
-	getBudgetByIDFn     func(userID, budgetID uint) (*models.Budget, error)
-	updateBudgetFn      func(userID, budgetID uint, name string, amount *int64, period *models.BudgetPeriod, endDate *time.Time) (*models.Budget, error)
-	deleteBudgetFn      func(userID, budgetID uint) error
-	getBudgetProgressFn func(userID, budgetID uint) (*services.BudgetProgress, error)
+	createBudgetFn       func(userID, categoryID string, name string, amount int64, period models.BudgetPeriod, startDate time.Time, endDate *time.Time) (*models.Budget, error)
+	getUserBudgetsFn     func(userID string, page pagination.PageRequest, isActive *bool, period *models.BudgetPeriod) (*pagination.PageResponse[models.Budget], error)
+	getBudgetByIDFn      func(userID, budgetID string) (*models.Budget, error)
+	updateBudgetFn       func(userID, budgetID string, name string, amount *int64, period *models.BudgetPeriod, endDate *time.Time) (*models.Budget, error)
+	deleteBudgetFn       func(userID, budgetID string) error
+	getBudgetProgressFn  func(userID, budgetID string, includePending bool) (*services.BudgetProgress, error)
+	getBudgetsProgressFn func(userID string, budgets []models.Budget, includePending bool) (map[string]*services.BudgetProgress, error)
+	getBudgetBurndownFn  func(userID, budgetID string) (*services.BudgetBurndown, error)
+	cloneForPeriodFn     func(userID string, sourceMonth, targetMonth time.Time) ([]models.Budget, error)
+	rolloverFn           func(asOf time.Time) (int, error)
 }
 
-func (m *mockBudgetService) CreateBudget(userID, categoryID uint, name string, amount int64, period models.BudgetPeriod, startDate time.Time, endDate *time.Time) (*models.Budget, error) {
+func (m *mockBudgetService) CreateBudget(userID, categoryID string, name string, amount int64, period models.BudgetPeriod, startDate time.Time, endDate *time.Time) (*models.Budget, error) {
 	if m.createBudgetFn != nil {
 		return m.createBudgetFn(userID, categoryID, name, amount, period, startDate, endDate)
 	}
 	return &models.Budget{}, nil
 }
 
-func (m *mockBudgetService) GetUserBudgets(userID uint, page pagination.PageRequest, isActive *bool, period *models.BudgetPeriod) (*pagination.PageResponse[models.Budget], error) {
+func (m *mockBudgetService) GetUserBudgets(userID string, page pagination.PageRequest, isActive *bool, period *models.BudgetPeriod) (*pagination.PageResponse[models.Budget], error) {
 	if m.getUserBudgetsFn != nil {
 		return m.getUserBudgetsFn(userID, page, isActive, period)
 	}
-	resp := pagination.NewPageResponse([]models.Budget{}, 1, 20, 0)
+	resp := pagination.NewPageResponse([]models.Budget{}, page.Page, page.PageSize, 0)
 	return &resp, nil
 }
 
-func (m *mockBudgetService) GetBudgetByID(userID, budgetID uint) (*models.Budget, error) {
+func (m *mockBudgetService) GetBudgetByID(userID, budgetID string) (*models.Budget, error) {
 	if m.getBudgetByIDFn != nil {
 		return m.getBudgetByIDFn(userID, budgetID)
 	}
 	return &models.Budget{}, nil
 }
 
-func (m *mockBudgetService) UpdateBudget(userID, budgetID uint, name string, amount *int64, period *models.BudgetPeriod, endDate *time.Time) (*models.Budget, error) {
+func (m *mockBudgetService) UpdateBudget(userID, budgetID string, name string, amount *int64, period *models.BudgetPeriod, endDate *time.Time) (*models.Budget, error) {
 	if m.updateBudgetFn != nil {
 		return m.updateBudgetFn(userID, budgetID, name, amount, period, endDate)
 	}
 	return &models.Budget{}, nil
 }
 
-func (m *mockBudgetService) DeleteBudget(userID, budgetID uint) error {
+func (m *mockBudgetService) DeleteBudget(userID, budgetID string) error {
 	if m.deleteBudgetFn != nil {
 		return m.deleteBudgetFn(userID, budgetID)
 	}
 	return nil
 }
 
-func (m *mockBudgetService) GetBudgetProgress(userID, budgetID uint) (*services.BudgetProgress, error) {
+func (m *mockBudgetService) GetBudgetProgress(userID, budgetID string, includePending bool) (*services.BudgetProgress, error) {
 	if m.getBudgetProgressFn != nil {
-		return m.getBudgetProgressFn(userID, budgetID)
+		return m.getBudgetProgressFn(userID, budgetID, includePending)
 	}
 	return &services.BudgetProgress{}, nil
 }
 
+func (m *mockBudgetService) GetBudgetsProgress(userID string, budgets []models.Budget, includePending bool) (map[string]*services.BudgetProgress, error) {
+	if m.getBudgetsProgressFn != nil {
+		return m.getBudgetsProgressFn(userID, budgets, includePending)
+	}
+	return map[string]*services.BudgetProgress{}, nil
+}
+
+func (m *mockBudgetService) GetBudgetBurndown(userID, budgetID string) (*services.BudgetBurndown, error) {
+	if m.getBudgetBurndownFn != nil {
+		return m.getBudgetBurndownFn(userID, budgetID)
+	}
+	return &services.BudgetBurndown{}, nil
+}
+
+func (m *mockBudgetService) CloneForPeriod(userID string, sourceMonth, targetMonth time.Time) ([]models.Budget, error) {
+	if m.cloneForPeriodFn != nil {
+		return m.cloneForPeriodFn(userID, sourceMonth, targetMonth)
+	}
+	return []models.Budget{}, nil
+}
+
+func (m *mockBudgetService) RolloverMonthlyBudgets(asOf time.Time) (int, error) {
+	if m.rolloverFn != nil {
+		return m.rolloverFn(asOf)
+	}
+	return 0, nil
+}
+
 var _ services.BudgetServicer = (*mockBudgetService)(nil)
 
 func setupBudgetRouter(handler *BudgetHandler) *gin.Engine {
 	r := gin.New()
-	auth := r.Group("", injectUserID(1))
+	auth := r.Group("", injectUserID(testID(1)))
 	auth.POST("/budgets", handler.CreateBudget)
 	auth.GET("/budgets", handler.GetBudgets)
 	auth.GET("/budgets/:id", handler.GetBudget)
 	auth.PUT("/budgets/:id", handler.UpdateBudget)
 	auth.DELETE("/budgets/:id", handler.DeleteBudget)
 	auth.GET("/budgets/:id/progress", handler.GetBudgetProgress)
+	auth.GET("/budgets/:id/burndown", handler.GetBudgetBurndown)
+	auth.POST("/budgets/clone-last-month", handler.CloneLastMonth)
+	r.POST("/pipeline/budgets/rollover", handler.RolloverBudgets)
 	return r
 }
 
 func TestBudgetHandler_CreateBudget(t *testing.T) {
 	t.Run("returns 201 on success", func(t *testing.T) {
 		svc := &mockBudgetService{
-			createBudgetFn: func(_ uint, categoryID uint, name string, amount int64, period models.BudgetPeriod, _ time.Time, _ *time.Time) (*models.Budget, error) {
+			createBudgetFn: func(_ string, categoryID string, name string, amount int64, period models.BudgetPeriod, _ time.Time, _ *time.Time) (*models.Budget, error) {
 				return &models.Budget{
-					Base:       models.Base{ID: 1},
-					UserID:     1,
+					Base:       models.Base{ID: testID(1)},
+					UserID:     testID(1),
 					CategoryID: categoryID,
 					Name:       name,
 					Amount:     amount,
@@ -100,7 +135,7 @@ func TestBudgetHandler_CreateBudget(t *testing.T) {
 		r := setupBudgetRouter(handler)
 
 		rec := doRequest(r, "POST", "/budgets",
-			`{"category_id":1,"name":"Groceries","amount":50000,"period":"monthly","start_date":"2025-01-01T00:00:00Z"}`)
+			`{"category_id":"00000000-0000-7000-8000-000000000001","name":"Groceries","amount":50000,"period":"monthly","start_date":"2025-01-01T00:00:00Z"}`)
 
 		if rec.Code != http.StatusCreated {
 			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
@@ -120,7 +155,7 @@ func TestBudgetHandler_CreateBudget(t *testing.T) {
 		r := setupBudgetRouter(handler)
 
 		rec := doRequest(r, "POST", "/budgets",
-			`{"category_id":1,"amount":50000,"period":"monthly","start_date":"2025-01-01T00:00:00Z"}`)
+			`{"category_id":"00000000-0000-7000-8000-000000000001","amount":50000,"period":"monthly","start_date":"2025-01-01T00:00:00Z"}`)
 
 		if rec.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d", rec.Code)
@@ -133,7 +168,7 @@ func TestBudgetHandler_CreateBudget(t *testing.T) {
 		r := setupBudgetRouter(handler)
 
 		rec := doRequest(r, "POST", "/budgets",
-			`{"category_id":1,"name":"Groceries","amount":50000,"start_date":"2025-01-01T00:00:00Z"}`)
+			`{"category_id":"00000000-0000-7000-8000-000000000001","name":"Groceries","amount":50000,"start_date":"2025-01-01T00:00:00Z"}`)
 
 		if rec.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d", rec.Code)
@@ -145,7 +180,7 @@ func TestBudgetHandler_CreateBudget(t *testing.T) {
 		r := setupBudgetRouter(handler)
 
 		rec := doRequest(r, "POST", "/budgets",
-			`{"category_id":1,"name":"Groceries","amount":50000,"period":"weekly","start_date":"2025-01-01T00:00:00Z"}`)
+			`{"category_id":"00000000-0000-7000-8000-000000000001","name":"Groceries","amount":50000,"period":"weekly","start_date":"2025-01-01T00:00:00Z"}`)
 
 		if rec.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d", rec.Code)
@@ -157,7 +192,7 @@ func TestBudgetHandler_CreateBudget(t *testing.T) {
 		r := setupBudgetRouter(handler)
 
 		rec := doRequest(r, "POST", "/budgets",
-			`{"category_id":1,"name":"Groceries","amount":0,"period":"monthly","start_date":"2025-01-01T00:00:00Z"}`)
+			`{"category_id":"00000000-0000-7000-8000-000000000001","name":"Groceries","amount":0,"period":"monthly","start_date":"2025-01-01T00:00:00Z"}`)
 
 		if rec.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d", rec.Code)
@@ -166,7 +201,7 @@ func TestBudgetHandler_CreateBudget(t *testing.T) {
 
 	t.Run("returns 404 on invalid category", func(t *testing.T) {
 		svc := &mockBudgetService{
-			createBudgetFn: func(_, _ uint, _ string, _ int64, _ models.BudgetPeriod, _ time.Time, _ *time.Time) (*models.Budget, error) {
+			createBudgetFn: func(_, _ string, _ string, _ int64, _ models.BudgetPeriod, _ time.Time, _ *time.Time) (*models.Budget, error) {
 				return nil, apperrors.ErrCategoryNotFound
 			},
 		}
@@ -174,7 +209,7 @@ func TestBudgetHandler_CreateBudget(t *testing.T) {
 		r := setupBudgetRouter(handler)
 
 		rec := doRequest(r, "POST", "/budgets",
-			`{"category_id":999,"name":"Groceries","amount":50000,"period":"monthly","start_date":"2025-01-01T00:00:00Z"}`)
+			`{"category_id":"00000000-0000-7000-8000-000000000999","name":"Groceries","amount":50000,"period":"monthly","start_date":"2025-01-01T00:00:00Z"}`)
 
 		if rec.Code != http.StatusNotFound {
 			t.Fatalf("expected 404, got %d", rec.Code)
@@ -188,7 +223,7 @@ func TestBudgetHandler_CreateBudget(t *testing.T) {
 		r.POST("/budgets", handler.CreateBudget)
 
 		rec := doRequest(r, "POST", "/budgets",
-			`{"category_id":1,"name":"Groceries","amount":50000,"period":"monthly","start_date":"2025-01-01T00:00:00Z"}`)
+			`{"category_id":"00000000-0000-7000-8000-000000000001","name":"Groceries","amount":50000,"period":"monthly","start_date":"2025-01-01T00:00:00Z"}`)
 
 		if rec.Code != http.StatusUnauthorized {
 			t.Fatalf("expected 401, got %d", rec.Code)
@@ -199,10 +234,10 @@ func TestBudgetHandler_CreateBudget(t *testing.T) {
 func TestBudgetHandler_GetBudgets(t *testing.T) {
 	t.Run("returns 200 with paginated budgets", func(t *testing.T) {
 		svc := &mockBudgetService{
-			getUserBudgetsFn: func(_ uint, _ pagination.PageRequest, _ *bool, _ *models.BudgetPeriod) (*pagination.PageResponse[models.Budget], error) {
+			getUserBudgetsFn: func(_ string, _ pagination.PageRequest, _ *bool, _ *models.BudgetPeriod) (*pagination.PageResponse[models.Budget], error) {
 				resp := pagination.NewPageResponse([]models.Budget{
-					{Base: models.Base{ID: 1}, Name: "Groceries"},
-					{Base: models.Base{ID: 2}, Name: "Entertainment"},
+					{Base: models.Base{ID: testID(1)}, Name: "Groceries"},
+					{Base: models.Base{ID: testID(2)}, Name: "Entertainment"},
 				}, 1, 20, 2)
 				return &resp, nil
 			},
@@ -225,11 +260,92 @@ func TestBudgetHandler_GetBudgets(t *testing.T) {
 		}
 	})
 
+	t.Run("omits progress without include", func(t *testing.T) {
+		svc := &mockBudgetService{
+			getUserBudgetsFn: func(_ string, _ pagination.PageRequest, _ *bool, _ *models.BudgetPeriod) (*pagination.PageResponse[models.Budget], error) {
+				resp := pagination.NewPageResponse([]models.Budget{{Base: models.Base{ID: testID(1)}, Name: "Groceries"}}, 1, 20, 1)
+				return &resp, nil
+			},
+			getBudgetsProgressFn: func(_ string, _ []models.Budget, _ bool) (map[string]*services.BudgetProgress, error) {
+				t.Error("progress should not be computed without include=progress")
+				return nil, nil
+			},
+		}
+		handler := NewBudgetHandler(svc, &mockAuditService{})
+		r := setupBudgetRouter(handler)
+
+		rec := doRequest(r, "GET", "/budgets", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		item := parseJSON(t, rec)["data"].([]interface{})[0].(map[string]interface{})
+		if _, ok := item["spent"]; ok {
+			t.Errorf("expected no spent field, got %v", item)
+		}
+	})
+
+	t.Run("include_progress_embeds_spend_in_each_item", func(t *testing.T) {
+		var capturedBudgets []models.Budget
+		var capturedPending bool
+		svc := &mockBudgetService{
+			getUserBudgetsFn: func(_ string, _ pagination.PageRequest, _ *bool, _ *models.BudgetPeriod) (*pagination.PageResponse[models.Budget], error) {
+				resp := pagination.NewPageResponse([]models.Budget{
+					{Base: models.Base{ID: testID(1)}, Name: "Groceries", Amount: 50000},
+					{Base: models.Base{ID: testID(2)}, Name: "Entertainment", Amount: 10000},
+				}, 1, 20, 2)
+				return &resp, nil
+			},
+			getBudgetsProgressFn: func(_ string, budgets []models.Budget, includePending bool) (map[string]*services.BudgetProgress, error) {
+				capturedBudgets, capturedPending = budgets, includePending
+				return map[string]*services.BudgetProgress{
+					testID(1): {BudgetID: testID(1), Budgeted: 50000, Spent: 12500, Remaining: 37500, Percentage: 25},
+					testID(2): {BudgetID: testID(2), Budgeted: 10000, Spent: 0, Remaining: 10000},
+				}, nil
+			},
+		}
+		handler := NewBudgetHandler(svc, &mockAuditService{})
+		r := setupBudgetRouter(handler)
+
+		rec := doRequest(r, "GET", "/budgets?include=progress&include_pending=false", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if len(capturedBudgets) != 2 || capturedPending {
+			t.Errorf("expected the page's 2 budgets without pending, got %d budgets, includePending=%v", len(capturedBudgets), capturedPending)
+		}
+		result := parseJSON(t, rec)
+		data := result["data"].([]interface{})
+		first := data[0].(map[string]interface{})
+		if first["name"] != "Groceries" || first["spent"].(float64) != 12500 || first["remaining"].(float64) != 37500 || first["percentage"].(float64) != 25 {
+			t.Errorf("unexpected first item: %v", first)
+		}
+		if second := data[1].(map[string]interface{}); second["spent"].(float64) != 0 || second["remaining"].(float64) != 10000 {
+			t.Errorf("unexpected second item: %v", second)
+		}
+		if result["total_items"].(float64) != 2 {
+			t.Errorf("expected total_items=2, got %v", result["total_items"])
+		}
+	})
+
+	t.Run("returns 400 on unknown include", func(t *testing.T) {
+		handler := NewBudgetHandler(&mockBudgetService{}, &mockAuditService{})
+		r := setupBudgetRouter(handler)
+
+		rec := doRequest(r, "GET", "/budgets?include=history", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
 	t.Run("passes filter params to service", func(t *testing.T) {
 		var capturedIsActive *bool
 		var capturedPeriod *models.BudgetPeriod
 		svc := &mockBudgetService{
-			getUserBudgetsFn: func(_ uint, _ pagination.PageRequest, isActive *bool, period *models.BudgetPeriod) (*pagination.PageResponse[models.Budget], error) {
+			getUserBudgetsFn: func(_ string, _ pagination.PageRequest, isActive *bool, period *models.BudgetPeriod) (*pagination.PageResponse[models.Budget], error) {
 				capturedIsActive = isActive
 				capturedPeriod = period
 				resp := pagination.NewPageResponse([]models.Budget{}, 1, 20, 0)
@@ -277,7 +393,7 @@ func TestBudgetHandler_GetBudgets(t *testing.T) {
 func TestBudgetHandler_GetBudget(t *testing.T) {
 	t.Run("returns 200 on success", func(t *testing.T) {
 		svc := &mockBudgetService{
-			getBudgetByIDFn: func(_, budgetID uint) (*models.Budget, error) {
+			getBudgetByIDFn: func(_, budgetID string) (*models.Budget, error) {
 				return &models.Budget{
 					Base:   models.Base{ID: budgetID},
 					Name:   "Groceries",
@@ -288,7 +404,7 @@ func TestBudgetHandler_GetBudget(t *testing.T) {
 		handler := NewBudgetHandler(svc, &mockAuditService{})
 		r := setupBudgetRouter(handler)
 
-		rec := doRequest(r, "GET", "/budgets/1", "")
+		rec := doRequest(r, "GET", "/budgets/00000000-0000-7000-8000-000000000001", "")
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d", rec.Code)
@@ -302,14 +418,14 @@ func TestBudgetHandler_GetBudget(t *testing.T) {
 
 	t.Run("returns 404 when not found", func(t *testing.T) {
 		svc := &mockBudgetService{
-			getBudgetByIDFn: func(_, _ uint) (*models.Budget, error) {
+			getBudgetByIDFn: func(_, _ string) (*models.Budget, error) {
 				return nil, apperrors.ErrBudgetNotFound
 			},
 		}
 		handler := NewBudgetHandler(svc, &mockAuditService{})
 		r := setupBudgetRouter(handler)
 
-		rec := doRequest(r, "GET", "/budgets/999", "")
+		rec := doRequest(r, "GET", "/budgets/00000000-0000-7000-8000-000000000999", "")
 
 		if rec.Code != http.StatusNotFound {
 			t.Fatalf("expected 404, got %d", rec.Code)
@@ -332,7 +448,7 @@ func TestBudgetHandler_GetBudget(t *testing.T) {
 func TestBudgetHandler_UpdateBudget(t *testing.T) {
 	t.Run("returns 200 on success", func(t *testing.T) {
 		svc := &mockBudgetService{
-			updateBudgetFn: func(_, budgetID uint, name string, amount *int64, _ *models.BudgetPeriod, _ *time.Time) (*models.Budget, error) {
+			updateBudgetFn: func(_, budgetID string, name string, amount *int64, _ *models.BudgetPeriod, _ *time.Time) (*models.Budget, error) {
 				b := &models.Budget{
 					Base: models.Base{ID: budgetID},
 					Name: name,
@@ -346,7 +462,7 @@ func TestBudgetHandler_UpdateBudget(t *testing.T) {
 		handler := NewBudgetHandler(svc, &mockAuditService{})
 		r := setupBudgetRouter(handler)
 
-		rec := doRequest(r, "PUT", "/budgets/1", `{"name":"Updated Budget","amount":75000}`)
+		rec := doRequest(r, "PUT", "/budgets/00000000-0000-7000-8000-000000000001", `{"name":"Updated Budget","amount":75000}`)
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
@@ -360,14 +476,14 @@ func TestBudgetHandler_UpdateBudget(t *testing.T) {
 
 	t.Run("returns 404 when not found", func(t *testing.T) {
 		svc := &mockBudgetService{
-			updateBudgetFn: func(_, _ uint, _ string, _ *int64, _ *models.BudgetPeriod, _ *time.Time) (*models.Budget, error) {
+			updateBudgetFn: func(_, _ string, _ string, _ *int64, _ *models.BudgetPeriod, _ *time.Time) (*models.Budget, error) {
 				return nil, apperrors.ErrBudgetNotFound
 			},
 		}
 		handler := NewBudgetHandler(svc, &mockAuditService{})
 		r := setupBudgetRouter(handler)
 
-		rec := doRequest(r, "PUT", "/budgets/999", `{"name":"Updated"}`)
+		rec := doRequest(r, "PUT", "/budgets/00000000-0000-7000-8000-000000000999", `{"name":"Updated"}`)
 
 		if rec.Code != http.StatusNotFound {
 			t.Fatalf("expected 404, got %d", rec.Code)
@@ -381,7 +497,7 @@ func TestBudgetHandler_DeleteBudget(t *testing.T) {
 		handler := NewBudgetHandler(&mockBudgetService{}, &mockAuditService{})
 		r := setupBudgetRouter(handler)
 
-		rec := doRequest(r, "DELETE", "/budgets/1", "")
+		rec := doRequest(r, "DELETE", "/budgets/00000000-0000-7000-8000-000000000001", "")
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
@@ -394,14 +510,14 @@ func TestBudgetHandler_DeleteBudget(t *testing.T) {
 
 	t.Run("returns 404 when not found", func(t *testing.T) {
 		svc := &mockBudgetService{
-			deleteBudgetFn: func(_, _ uint) error {
+			deleteBudgetFn: func(_, _ string) error {
 				return apperrors.ErrBudgetNotFound
 			},
 		}
 		handler := NewBudgetHandler(svc, &mockAuditService{})
 		r := setupBudgetRouter(handler)
 
-		rec := doRequest(r, "DELETE", "/budgets/999", "")
+		rec := doRequest(r, "DELETE", "/budgets/00000000-0000-7000-8000-000000000999", "")
 
 		if rec.Code != http.StatusNotFound {
 			t.Fatalf("expected 404, got %d", rec.Code)
@@ -424,20 +540,26 @@ func TestBudgetHandler_DeleteBudget(t *testing.T) {
 func TestBudgetHandler_GetBudgetProgress(t *testing.T) {
 	t.Run("returns 200 with progress", func(t *testing.T) {
 		svc := &mockBudgetService{
-			getBudgetProgressFn: func(_, budgetID uint) (*services.BudgetProgress, error) {
+			getBudgetProgressFn: func(_, budgetID string, _ bool) (*services.BudgetProgress, error) {
 				return &services.BudgetProgress{
 					BudgetID:   budgetID,
 					Budgeted:   50000,
 					Spent:      25000,
 					Remaining:  25000,
 					Percentage: 50.0,
+					Pace: services.BudgetPace{
+						DaysElapsed:    10,
+						DaysInPeriod:   30,
+						ProjectedSpend: 75000,
+						Status:         services.BudgetPaceAtRisk,
+					},
 				}, nil
 			},
 		}
 		handler := NewBudgetHandler(svc, &mockAuditService{})
 		r := setupBudgetRouter(handler)
 
-		rec := doRequest(r, "GET", "/budgets/1/progress", "")
+		rec := doRequest(r, "GET", "/budgets/00000000-0000-7000-8000-000000000001/progress", "")
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
@@ -453,18 +575,49 @@ func TestBudgetHandler_GetBudgetProgress(t *testing.T) {
 		if progress["percentage"].(float64) != 50.0 {
 			t.Errorf("expected percentage=50, got %v", progress["percentage"])
 		}
+		pace := progress["pace"].(map[string]interface{})
+		if pace["projected_spend"].(float64) != 75000 || pace["status"] != "at_risk" {
+			t.Errorf("expected the pace to be passed through, got %v", pace)
+		}
+	})
+
+	t.Run("passes include_pending flag", func(t *testing.T) {
+		captured := true
+		svc := &mockBudgetService{
+			getBudgetProgressFn: func(_, budgetID string, includePending bool) (*services.BudgetProgress, error) {
+				captured = includePending
+				return &services.BudgetProgress{BudgetID: budgetID}, nil
+			},
+		}
+		handler := NewBudgetHandler(svc, &mockAuditService{})
+		r := setupBudgetRouter(handler)
+
+		rec := doRequest(r, "GET", "/budgets/00000000-0000-7000-8000-000000000001/progress?include_pending=false", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if captured {
+			t.Error("expected includePending=false to be passed to service")
+		}
+
+		rec = doRequest(r, "GET", "/budgets/00000000-0000-7000-8000-000000000001/progress?include_pending=maybe", "")
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for invalid include_pending, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
 	})
 
 	t.Run("returns 404 when budget not found", func(t *testing.T) {
 		svc := &mockBudgetService{
-			getBudgetProgressFn: func(_, _ uint) (*services.BudgetProgress, error) {
+			getBudgetProgressFn: func(_, _ string, _ bool) (*services.BudgetProgress, error) {
 				return nil, apperrors.ErrBudgetNotFound
 			},
 		}
 		handler := NewBudgetHandler(svc, &mockAuditService{})
 		r := setupBudgetRouter(handler)
 
-		rec := doRequest(r, "GET", "/budgets/999/progress", "")
+		rec := doRequest(r, "GET", "/budgets/00000000-0000-7000-8000-000000000999/progress", "")
 
 		if rec.Code != http.StatusNotFound {
 			t.Fatalf("expected 404, got %d", rec.Code)
@@ -483,3 +636,121 @@ func TestBudgetHandler_GetBudgetProgress(t *testing.T) {
 		}
 	})
 }
+
+func TestBudgetHandler_GetBudgetBurndown(t *testing.T) {
+	t.Run("returns 200 with burndown", func(t *testing.T) {
+		day := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+		svc := &mockBudgetService{
+			getBudgetBurndownFn: func(_, budgetID string) (*services.BudgetBurndown, error) {
+				return &services.BudgetBurndown{
+					BudgetID:       budgetID,
+					Budgeted:       50000,
+					Spent:          3000,
+					ProjectedSpend: 46500,
+					Series: []services.BudgetBurndownPoint{
+						{Date: day, Spent: 1000, Cumulative: 1000},
+						{Date: day.AddDate(0, 0, 1), Spent: 2000, Cumulative: 3000},
+					},
+				}, nil
+			},
+		}
+		handler := NewBudgetHandler(svc, &mockAuditService{})
+		r := setupBudgetRouter(handler)
+
+		rec := doRequest(r, "GET", "/budgets/00000000-0000-7000-8000-000000000001/burndown", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		burndown := parseJSON(t, rec)["burndown"].(map[string]interface{})
+		if burndown["projected_spend"].(float64) != 46500 {
+			t.Errorf("expected projected_spend=46500, got %v", burndown["projected_spend"])
+		}
+		series := burndown["series"].([]interface{})
+		if len(series) != 2 || series[1].(map[string]interface{})["cumulative"].(float64) != 3000 {
+			t.Errorf("unexpected series %v", series)
+		}
+	})
+
+	t.Run("returns 404 when budget not found", func(t *testing.T) {
+		svc := &mockBudgetService{
+			getBudgetBurndownFn: func(_, _ string) (*services.BudgetBurndown, error) {
+				return nil, apperrors.ErrBudgetNotFound
+			},
+		}
+		handler := NewBudgetHandler(svc, &mockAuditService{})
+		r := setupBudgetRouter(handler)
+
+		rec := doRequest(r, "GET", "/budgets/00000000-0000-7000-8000-000000000999/burndown", "")
+
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "BUDGET_NOT_FOUND")
+	})
+}
+
+func TestBudgetHandler_CloneLastMonth(t *testing.T) {
+	t.Run("clones from the previous month into the current one", func(t *testing.T) {
+		var gotSource, gotTarget time.Time
+		svc := &mockBudgetService{
+			cloneForPeriodFn: func(userID string, sourceMonth, targetMonth time.Time) ([]models.Budget, error) {
+				gotSource, gotTarget = sourceMonth, targetMonth
+				return []models.Budget{{Base: models.Base{ID: testID(2)}, UserID: userID, Amount: 50000}}, nil
+			},
+		}
+		handler := NewBudgetHandler(svc, &mockAuditService{})
+		r := setupBudgetRouter(handler)
+
+		rec := doRequest(r, "POST", "/budgets/clone-last-month", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		result := parseJSON(t, rec)
+		if result["created"].(float64) != 1 {
+			t.Errorf("expected created=1, got %v", result["created"])
+		}
+		if budgets := result["budgets"].([]interface{}); len(budgets) != 1 {
+			t.Errorf("expected 1 budget, got %d", len(budgets))
+		}
+		if next := gotSource.AddDate(0, 1, 0); gotSource.Day() != 1 || next.Year() != gotTarget.Year() || next.Month() != gotTarget.Month() {
+			t.Errorf("expected source %v to be the month before target %v", gotSource, gotTarget)
+		}
+	})
+
+	t.Run("returns 500 on service error", func(t *testing.T) {
+		svc := &mockBudgetService{
+			cloneForPeriodFn: func(_ string, _, _ time.Time) ([]models.Budget, error) {
+				return nil, apperrors.ErrInternalServer
+			},
+		}
+		handler := NewBudgetHandler(svc, &mockAuditService{})
+		r := setupBudgetRouter(handler)
+
+		rec := doRequest(r, "POST", "/budgets/clone-last-month", "")
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Fatalf("expected 500, got %d", rec.Code)
+		}
+	})
+}
+
+func TestBudgetHandler_RolloverBudgets(t *testing.T) {
+	t.Run("returns created count", func(t *testing.T) {
+		svc := &mockBudgetService{
+			rolloverFn: func(_ time.Time) (int, error) { return 4, nil },
+		}
+		handler := NewBudgetHandler(svc, &mockAuditService{})
+		r := setupBudgetRouter(handler)
+
+		rec := doRequest(r, "POST", "/pipeline/budgets/rollover", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if created := parseJSON(t, rec)["created"].(float64); created != 4 {
+			t.Errorf("expected created=4, got %v", created)
+		}
+	})
+}
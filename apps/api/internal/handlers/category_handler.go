@@ -109,6 +109,7 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 // @Param       type      query string false "Filter by category type (income/expense)"
 // @Param       page      query int    false "Page number (default 1)"
 // @Param       page_size query int    false "Items per page (default 20, max 100)"
+// @Param       with_total query bool   false "Compute total_items and total_pages (default true); when false they are -1 and 0"
 // @Success     200 {object} pagination.PageResponse[models.Category] "Paginated categories"
 // @Failure     400 {object} ErrorResponse "Invalid input"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
@@ -127,9 +128,9 @@ func (h *CategoryHandler) GetUserCategories(c *gin.Context) {
 		return
 	}
 
-	var page pagination.PageRequest
-	if err := c.ShouldBindQuery(&page); err != nil {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+	page, err := bindPage(c)
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
@@ -146,7 +147,7 @@ func (h *CategoryHandler) GetUserCategories(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	respondWithPage(c, page, result)
 }
 
 // GetCategoryByID handles the retrieval of a specific category
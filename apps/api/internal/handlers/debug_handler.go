@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kuberan/internal/config"
+	"kuberan/internal/database"
+)
+
+// DebugHandler serves diagnostics about the running instance.
+type DebugHandler struct {
+	config *config.Config
+	pool   database.PoolSettings
+}
+
+// NewDebugHandler creates a new DebugHandler reporting cfg and the database
+// connection pool settings.
+func NewDebugHandler(cfg *config.Config, pool database.PoolSettings) *DebugHandler {
+	return &DebugHandler{config: cfg, pool: pool}
+}
+
+// GetConfig handles GET /api/debug/config: the effective configuration with
+// secrets redacted, plus the database pool settings. It sits outside /api/v1
+// and the Swagger docs; main.go only serves it outside production or with the
+// admin key.
+func (h *DebugHandler) GetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"config": h.config.Redacted(),
+		"db_pool": gin.H{
+			"max_idle_conns":    h.pool.MaxIdleConns,
+			"max_open_conns":    h.pool.MaxOpenConns,
+			"conn_max_lifetime": h.pool.ConnMaxLifetime.String(),
+		},
+	})
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"kuberan/internal/config"
+	"kuberan/internal/database"
+	"kuberan/internal/middleware"
+)
+
+func TestDebugHandler_GetConfig(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{
+		Env:              config.Development,
+		Port:             "8080",
+		CORSOrigin:       "https://app.example.com",
+		DBHost:           "db.internal",
+		DBName:           "kuberan",
+		DBPassword:       "db-password-secret",
+		JWTSecret:        "jwt-secret-value",
+		JWTKeys:          []config.JWTKey{{ID: "default", Secret: "jwt-secret-value"}, {ID: "2025-01", Secret: "jwt-key-secret"}},
+		PipelineAPIKey:   "pipeline-key-secret",
+		PipelineAdminKey: "admin-key-secret",
+		SMTPPassword:     "smtp-password-secret",
+		PriceCacheTTL:    time.Minute,
+	}
+	pool := database.PoolSettings{MaxIdleConns: 10, MaxOpenConns: 100, ConnMaxLifetime: time.Hour}
+
+	router := func(cfg *config.Config) *gin.Engine {
+		r := gin.New()
+		r.GET("/api/debug/config", middleware.NonProductionOrAdmin(cfg.Env, cfg.PipelineAdminKey), NewDebugHandler(cfg, pool).GetConfig)
+		return r
+	}
+
+	t.Run("redacts_secrets", func(t *testing.T) {
+		rec := doRequest(router(cfg), http.MethodGet, "/api/debug/config", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		for _, secret := range []string{"db-password-secret", "jwt-secret-value", "jwt-key-secret", "pipeline-key-secret", "admin-key-secret", "smtp-password-secret"} {
+			if strings.Contains(rec.Body.String(), secret) {
+				t.Errorf("response leaks %q", secret)
+			}
+		}
+
+		body := parseJSON(t, rec)
+		got := body["config"].(map[string]interface{})
+		want := map[string]interface{}{
+			"env": "development", "port": "8080", "cors_origin": "https://app.example.com",
+			"db_host": "db.internal", "db_name": "kuberan", "price_cache_ttl": "1m0s",
+			"db_password": "[redacted]", "jwt_secret": "[redacted]", "pipeline_api_key": "[redacted]",
+			"pipeline_admin_key": "[redacted]", "smtp_password": "[redacted]",
+		}
+		for key, value := range want {
+			if got[key] != value {
+				t.Errorf("%s = %v, want %v", key, got[key], value)
+			}
+		}
+		if ids := got["jwt_key_ids"].([]interface{}); len(ids) != 2 || ids[1] != "2025-01" {
+			t.Errorf("expected the JWT key ids, got %v", ids)
+		}
+		if dbPool := body["db_pool"].(map[string]interface{}); dbPool["max_open_conns"] != float64(100) || dbPool["conn_max_lifetime"] != "1h0m0s" {
+			t.Errorf("unexpected pool settings %v", dbPool)
+		}
+	})
+
+	t.Run("unset_secrets_stay_empty", func(t *testing.T) {
+		rec := doRequest(router(&config.Config{Env: config.Development}), http.MethodGet, "/api/debug/config", "")
+		got := parseJSON(t, rec)["config"].(map[string]interface{})
+		if got["db_password"] != "" || got["pipeline_api_key"] != "" {
+			t.Errorf("expected unset secrets to be empty, got %v and %v", got["db_password"], got["pipeline_api_key"])
+		}
+	})
+
+	t.Run("disabled_in_production", func(t *testing.T) {
+		prod := *cfg
+		prod.Env = config.Production
+		if rec := doRequest(router(&prod), http.MethodGet, "/api/debug/config", ""); rec.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401 without the admin key, got %d", rec.Code)
+		}
+
+		prod.PipelineAdminKey = ""
+		if rec := doRequest(router(&prod), http.MethodGet, "/api/debug/config", ""); rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected 503 with no admin key configured, got %d", rec.Code)
+		}
+	})
+}
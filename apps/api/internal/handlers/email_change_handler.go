@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/services"
+)
+
+// EmailChangeHandler handles verified login email changes.
+type EmailChangeHandler struct {
+	emailChangeService services.EmailChangeServicer
+	auditService       services.AuditServicer
+}
+
+// NewEmailChangeHandler creates a new EmailChangeHandler.
+func NewEmailChangeHandler(emailChangeService services.EmailChangeServicer, auditService services.AuditServicer) *EmailChangeHandler {
+	return &EmailChangeHandler{emailChangeService: emailChangeService, auditService: auditService}
+}
+
+// RequestEmailChangeRequest represents the request payload for changing the login email.
+type RequestEmailChangeRequest struct {
+	NewEmail        string `json:"new_email" binding:"required,email,max=255"`
+	CurrentPassword string `json:"current_password" binding:"required"`
+}
+
+// RequestEmailChange starts an email change and sends a verification token to the new address.
+// @Summary     Request email change
+// @Description Start a login email change. The new address must be confirmed via /auth/verify-email before it takes effect.
+// @Tags        user
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       request body RequestEmailChangeRequest true "New email and current password"
+// @Success     202 {object} MessageResponse "Verification sent"
+// @Failure     400 {object} ErrorResponse "Invalid input"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     403 {object} ErrorResponse "Incorrect password"
+// @Failure     409 {object} ErrorResponse "Email already in use"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /profile/email [post]
+func (h *EmailChangeHandler) RequestEmailChange(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	var req RequestEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+		return
+	}
+
+	pending, err := h.emailChangeService.RequestEmailChange(userID, req.NewEmail, req.CurrentPassword)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(userID, "REQUEST_EMAIL_CHANGE", "user", userID, c.ClientIP(),
+		map[string]interface{}{"new_email": pending.NewEmail})
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Verification sent to the new email address"})
+}
+
+// VerifyEmail completes a pending email change.
+// @Summary     Verify email change
+// @Description Confirm a pending login email change using the token sent to the new address
+// @Tags        auth
+// @Accept      json
+// @Produce     json
+// @Param       token query string true "Verification token"
+// @Success     200 {object} ProfileResponse "Updated profile"
+// @Failure     400 {object} ErrorResponse "Invalid token"
+// @Failure     409 {object} ErrorResponse "Email already in use"
+// @Failure     410 {object} ErrorResponse "Token expired"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /auth/verify-email [get]
+// @Router      /auth/verify-email [post]
+func (h *EmailChangeHandler) VerifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "token is required"))
+		return
+	}
+
+	user, err := h.emailChangeService.VerifyEmailChange(token)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(user.ID, "CHANGE_EMAIL", "user", user.ID, c.ClientIP(),
+		map[string]interface{}{"email": user.Email})
+
+	c.JSON(http.StatusOK, gin.H{"user": newProfileResponse(user)})
+}
+
+// PurgeExpiredEmailChanges deletes expired pending email changes.
+// @Summary     Purge expired email changes
+// @Description Permanently delete pending email changes whose verification token has expired (pipeline endpoint)
+// @Tags        pipeline
+// @Accept      json
+// @Produce     json
+// @Security    ApiKeyAuth
+// @Success     200 {object} map[string]int "Purged count"
+// @Failure     401 {object} ErrorResponse "Invalid API key"
+// @Failure     503 {object} ErrorResponse "Pipeline not configured"
+// @Router      /pipeline/email-changes/purge [post]
+func (h *EmailChangeHandler) PurgeExpiredEmailChanges(c *gin.Context) {
+	count, err := h.emailChangeService.PurgeExpiredEmailChanges(time.Now())
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"purged": count})
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+	"kuberan/internal/services"
+)
+
+// --- mock email change service ---
+
+type mockEmailChangeService struct {
+	requestEmailChangeFn       func(userID, newEmail, password string) (*models.PendingEmailChange, error)
+	verifyEmailChangeFn        func(token string) (*models.User, error)
+	purgeExpiredEmailChangesFn func(now time.Time) (int, error)
+}
+
+func (m *mockEmailChangeService) RequestEmailChange(userID, newEmail, password string) (*models.PendingEmailChange, error) {
+	if m.requestEmailChangeFn != nil {
+		return m.requestEmailChangeFn(userID, newEmail, password)
+	}
+	return &models.PendingEmailChange{NewEmail: newEmail}, nil
+}
+
+func (m *mockEmailChangeService) VerifyEmailChange(token string) (*models.User, error) {
+	if m.verifyEmailChangeFn != nil {
+		return m.verifyEmailChangeFn(token)
+	}
+	return &models.User{}, nil
+}
+
+func (m *mockEmailChangeService) PurgeExpiredEmailChanges(now time.Time) (int, error) {
+	if m.purgeExpiredEmailChangesFn != nil {
+		return m.purgeExpiredEmailChangesFn(now)
+	}
+	return 0, nil
+}
+
+var _ services.EmailChangeServicer = (*mockEmailChangeService)(nil)
+
+func setupEmailChangeRouter(handler *EmailChangeHandler) *gin.Engine {
+	r := gin.New()
+	r.GET("/auth/verify-email", handler.VerifyEmail)
+	r.POST("/auth/verify-email", handler.VerifyEmail)
+	r.POST("/pipeline/email-changes/purge", handler.PurgeExpiredEmailChanges)
+	auth := r.Group("", injectUserID(testID(1)))
+	auth.POST("/profile/email", handler.RequestEmailChange)
+	return r
+}
+
+func TestEmailChangeHandler_RequestEmailChange(t *testing.T) {
+	t.Run("returns 202 on success", func(t *testing.T) {
+		var gotEmail, gotPassword string
+		svc := &mockEmailChangeService{
+			requestEmailChangeFn: func(_, newEmail, password string) (*models.PendingEmailChange, error) {
+				gotEmail, gotPassword = newEmail, password
+				return &models.PendingEmailChange{NewEmail: newEmail}, nil
+			},
+		}
+		handler := NewEmailChangeHandler(svc, &mockAuditService{})
+		r := setupEmailChangeRouter(handler)
+
+		rec := doRequest(r, "POST", "/profile/email",
+			`{"new_email":"new@example.com","current_password":"password123"}`)
+
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotEmail != "new@example.com" || gotPassword != "password123" {
+			t.Errorf("unexpected service args: %q %q", gotEmail, gotPassword)
+		}
+	})
+
+	t.Run("returns 400 on missing password", func(t *testing.T) {
+		handler := NewEmailChangeHandler(&mockEmailChangeService{}, &mockAuditService{})
+		r := setupEmailChangeRouter(handler)
+
+		rec := doRequest(r, "POST", "/profile/email", `{"new_email":"new@example.com"}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("returns 403 on wrong password", func(t *testing.T) {
+		svc := &mockEmailChangeService{
+			requestEmailChangeFn: func(_, _, _ string) (*models.PendingEmailChange, error) {
+				return nil, apperrors.ErrIncorrectPassword
+			},
+		}
+		handler := NewEmailChangeHandler(svc, &mockAuditService{})
+		r := setupEmailChangeRouter(handler)
+
+		rec := doRequest(r, "POST", "/profile/email",
+			`{"new_email":"new@example.com","current_password":"wrong"}`)
+
+		if rec.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INCORRECT_PASSWORD")
+	})
+}
+
+func TestEmailChangeHandler_VerifyEmail(t *testing.T) {
+	t.Run("returns 200 with updated profile", func(t *testing.T) {
+		var gotToken string
+		svc := &mockEmailChangeService{
+			verifyEmailChangeFn: func(token string) (*models.User, error) {
+				gotToken = token
+				return &models.User{Base: models.Base{ID: testID(1)}, Email: "new@example.com"}, nil
+			},
+		}
+		handler := NewEmailChangeHandler(svc, &mockAuditService{})
+		r := setupEmailChangeRouter(handler)
+
+		for _, method := range []string{"GET", "POST"} {
+			rec := doRequest(r, method, "/auth/verify-email?token=abc123", "")
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("%s: expected 200, got %d: %s", method, rec.Code, rec.Body.String())
+			}
+			if gotToken != "abc123" {
+				t.Errorf("%s: expected token abc123, got %q", method, gotToken)
+			}
+			user := parseJSON(t, rec)["user"].(map[string]interface{})
+			if user["email"] != "new@example.com" {
+				t.Errorf("%s: expected new email, got %v", method, user["email"])
+			}
+		}
+	})
+
+	t.Run("returns 400 without token", func(t *testing.T) {
+		handler := NewEmailChangeHandler(&mockEmailChangeService{}, &mockAuditService{})
+		r := setupEmailChangeRouter(handler)
+
+		rec := doRequest(r, "GET", "/auth/verify-email", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("returns 410 on expired token", func(t *testing.T) {
+		svc := &mockEmailChangeService{
+			verifyEmailChangeFn: func(_ string) (*models.User, error) {
+				return nil, apperrors.ErrVerificationTokenExpired
+			},
+		}
+		handler := NewEmailChangeHandler(svc, &mockAuditService{})
+		r := setupEmailChangeRouter(handler)
+
+		rec := doRequest(r, "GET", "/auth/verify-email?token=old", "")
+
+		if rec.Code != http.StatusGone {
+			t.Fatalf("expected 410, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "VERIFICATION_TOKEN_EXPIRED")
+	})
+}
+
+func TestEmailChangeHandler_PurgeExpiredEmailChanges(t *testing.T) {
+	t.Run("returns purged count", func(t *testing.T) {
+		svc := &mockEmailChangeService{
+			purgeExpiredEmailChangesFn: func(_ time.Time) (int, error) {
+				return 3, nil
+			},
+		}
+		handler := NewEmailChangeHandler(svc, &mockAuditService{})
+		r := setupEmailChangeRouter(handler)
+
+		rec := doRequest(r, "POST", "/pipeline/email-changes/purge", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rec.Code)
+		}
+		if parseJSON(t, rec)["purged"].(float64) != 3 {
+			t.Errorf("expected purged=3")
+		}
+	})
+}
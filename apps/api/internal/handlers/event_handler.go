@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/services"
+)
+
+// EventHandler exposes the domain-event outbox to the pipeline.
+type EventHandler struct {
+	dispatcher services.EventDispatcherServicer
+}
+
+// NewEventHandler creates a new EventHandler.
+func NewEventHandler(dispatcher services.EventDispatcherServicer) *EventHandler {
+	return &EventHandler{dispatcher: dispatcher}
+}
+
+// DispatchEvents drains pending outbox events to their consumers.
+// @Summary     Dispatch pending events
+// @Description Deliver undelivered domain events to their in-process consumers, oldest first (pipeline endpoint). Failed events are retried on a later dispatch.
+// @Tags        pipeline
+// @Accept      json
+// @Produce     json
+// @Security    ApiKeyAuth
+// @Param       limit query int false "Maximum events to deliver (default 100, max 1000)"
+// @Success     200 {object} services.EventDispatchResult "Delivered and failed counts"
+// @Failure     400 {object} ErrorResponse "Invalid limit"
+// @Failure     401 {object} ErrorResponse "Invalid API key"
+// @Failure     503 {object} ErrorResponse "Pipeline not configured"
+// @Router      /pipeline/events/dispatch [post]
+func (h *EventHandler) DispatchEvents(c *gin.Context) {
+	limit := services.DefaultEventBatchSize
+	if v := c.Query("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > services.MaxEventBatchSize {
+			respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput,
+				"limit must be a whole number from 1 to "+strconv.Itoa(services.MaxEventBatchSize)))
+			return
+		}
+		limit = parsed
+	}
+
+	result, err := h.dispatcher.DispatchPending(limit)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"kuberan/internal/services"
+)
+
+// --- mock event dispatcher ---
+
+type mockEventDispatcher struct {
+	dispatchPendingFn func(limit int) (*services.EventDispatchResult, error)
+}
+
+func (m *mockEventDispatcher) Subscribe(string, services.EventConsumer) {}
+
+func (m *mockEventDispatcher) DispatchPending(limit int) (*services.EventDispatchResult, error) {
+	if m.dispatchPendingFn != nil {
+		return m.dispatchPendingFn(limit)
+	}
+	return &services.EventDispatchResult{}, nil
+}
+
+func (m *mockEventDispatcher) Run(context.Context, time.Duration) {}
+
+var _ services.EventDispatcherServicer = (*mockEventDispatcher)(nil)
+
+func setupEventRouter(handler *EventHandler) *gin.Engine {
+	r := gin.New()
+	r.POST("/pipeline/events/dispatch", handler.DispatchEvents)
+	return r
+}
+
+func TestEventHandler_DispatchEvents(t *testing.T) {
+	t.Run("returns counts and passes the limit", func(t *testing.T) {
+		var gotLimit int
+		svc := &mockEventDispatcher{
+			dispatchPendingFn: func(limit int) (*services.EventDispatchResult, error) {
+				gotLimit = limit
+				return &services.EventDispatchResult{Delivered: 3, Failed: 1}, nil
+			},
+		}
+		r := setupEventRouter(NewEventHandler(svc))
+
+		rec := doRequest(r, "POST", "/pipeline/events/dispatch?limit=50", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotLimit != 50 {
+			t.Errorf("expected limit 50, got %d", gotLimit)
+		}
+		body := parseJSON(t, rec)
+		if body["delivered"] != float64(3) || body["failed"] != float64(1) {
+			t.Errorf("expected delivered=3 failed=1, got %v", body)
+		}
+	})
+
+	t.Run("defaults the limit", func(t *testing.T) {
+		var gotLimit int
+		svc := &mockEventDispatcher{
+			dispatchPendingFn: func(limit int) (*services.EventDispatchResult, error) {
+				gotLimit = limit
+				return &services.EventDispatchResult{}, nil
+			},
+		}
+		r := setupEventRouter(NewEventHandler(svc))
+
+		rec := doRequest(r, "POST", "/pipeline/events/dispatch", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotLimit != services.DefaultEventBatchSize {
+			t.Errorf("expected default limit %d, got %d", services.DefaultEventBatchSize, gotLimit)
+		}
+	})
+
+	t.Run("returns 400 on an out-of-range limit", func(t *testing.T) {
+		r := setupEventRouter(NewEventHandler(&mockEventDispatcher{}))
+
+		rec := doRequest(r, "POST", "/pipeline/events/dispatch?limit=0", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/services"
+)
+
+// ExchangeRateHandler handles exchange-rate requests.
+type ExchangeRateHandler struct {
+	rateService services.ExchangeRateServicer
+}
+
+// NewExchangeRateHandler creates a new ExchangeRateHandler.
+func NewExchangeRateHandler(rateService services.ExchangeRateServicer) *ExchangeRateHandler {
+	return &ExchangeRateHandler{rateService: rateService}
+}
+
+// ExchangeRatesQuery holds the query parameters for listing exchange rates.
+type ExchangeRatesQuery struct {
+	Base string `form:"base" binding:"omitempty,iso4217"`
+}
+
+// RecordExchangeRatesRequest represents the request payload for bulk rate recording.
+type RecordExchangeRatesRequest struct {
+	Rates []RecordExchangeRateEntry `json:"rates" binding:"required,min=1,dive"`
+}
+
+// RecordExchangeRateEntry is one rate in a bulk request: one unit of
+// from_currency buys rate units of to_currency.
+type RecordExchangeRateEntry struct {
+	FromCurrency string    `json:"from_currency" binding:"required,iso4217"`
+	ToCurrency   string    `json:"to_currency" binding:"required,iso4217,nefield=FromCurrency"`
+	Rate         float64   `json:"rate" binding:"required,gt=0"`
+	RecordedAt   time.Time `json:"recorded_at" binding:"required"`
+}
+
+// GetExchangeRates handles listing the latest exchange rates.
+// @Summary     List exchange rates
+// @Description Latest exchange rate of every recorded currency pair. With base, the rate from base to every reachable currency instead, resolved like server-side conversions: direct, then inverse, then through USD.
+// @Tags        exchange-rates
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       base query string false "ISO 4217 currency to express every rate from"
+// @Success     200 {object} map[string][]services.ExchangeRateQuote "Exchange rates"
+// @Failure     400 {object} ErrorResponse "Invalid base currency"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Router      /exchange-rates [get]
+func (h *ExchangeRateHandler) GetExchangeRates(c *gin.Context) {
+	var query ExchangeRatesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "base must be an ISO 4217 currency code"))
+		return
+	}
+
+	rates, err := h.rateService.ListRates(query.Base)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rates": rates})
+}
+
+// RecordExchangeRates handles bulk exchange-rate recording.
+// @Summary     Record exchange rates
+// @Description Bulk record exchange rates (pipeline endpoint). A rate for an already recorded pair and timestamp replaces it.
+// @Tags        pipeline
+// @Accept      json
+// @Produce     json
+// @Security    ApiKeyAuth
+// @Param       request body RecordExchangeRatesRequest true "Rate entries"
+// @Success     200 {object} map[string]int "Rates recorded count"
+// @Failure     400 {object} ErrorResponse "Invalid input"
+// @Failure     401 {object} ErrorResponse "Invalid API key"
+// @Failure     503 {object} ErrorResponse "Pipeline not configured"
+// @Router      /pipeline/exchange-rates [post]
+func (h *ExchangeRateHandler) RecordExchangeRates(c *gin.Context) {
+	var req RecordExchangeRatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+		return
+	}
+
+	inputs := make([]services.ExchangeRateInput, len(req.Rates))
+	for i, r := range req.Rates {
+		inputs[i] = services.ExchangeRateInput{
+			FromCurrency: r.FromCurrency,
+			ToCurrency:   r.ToCurrency,
+			Rate:         r.Rate,
+			RecordedAt:   r.RecordedAt,
+		}
+	}
+
+	count, err := h.rateService.RecordRates(inputs)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rates_recorded": count})
+}
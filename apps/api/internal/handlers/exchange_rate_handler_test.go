@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"kuberan/internal/services"
+)
+
+// --- mock exchange rate service ---
+
+type mockExchangeRateService struct {
+	getRateFn     func(from, to string) (float64, time.Time, error)
+	recordRatesFn func(rates []services.ExchangeRateInput) (int, error)
+	listRatesFn   func(base string) ([]services.ExchangeRateQuote, error)
+}
+
+func (m *mockExchangeRateService) GetRate(from, to string) (float64, time.Time, error) {
+	if m.getRateFn != nil {
+		return m.getRateFn(from, to)
+	}
+	return 1, time.Time{}, nil
+}
+
+func (m *mockExchangeRateService) RecordRates(rates []services.ExchangeRateInput) (int, error) {
+	if m.recordRatesFn != nil {
+		return m.recordRatesFn(rates)
+	}
+	return len(rates), nil
+}
+
+func (m *mockExchangeRateService) ListRates(base string) ([]services.ExchangeRateQuote, error) {
+	if m.listRatesFn != nil {
+		return m.listRatesFn(base)
+	}
+	return []services.ExchangeRateQuote{}, nil
+}
+
+var _ services.ExchangeRateServicer = (*mockExchangeRateService)(nil)
+
+func setupExchangeRateRouter(handler *ExchangeRateHandler) *gin.Engine {
+	r := gin.New()
+	auth := r.Group("", injectUserID(testID(1)))
+	auth.GET("/exchange-rates", handler.GetExchangeRates)
+	r.POST("/pipeline/exchange-rates", handler.RecordExchangeRates)
+	return r
+}
+
+func TestExchangeRateHandler_GetExchangeRates(t *testing.T) {
+	t.Run("passes the base currency", func(t *testing.T) {
+		var gotBase string
+		svc := &mockExchangeRateService{
+			listRatesFn: func(base string) ([]services.ExchangeRateQuote, error) {
+				gotBase = base
+				return []services.ExchangeRateQuote{{From: base, To: "USD", Rate: 0.21}}, nil
+			},
+		}
+		r := setupExchangeRateRouter(NewExchangeRateHandler(svc))
+
+		rec := doRequest(r, "GET", "/exchange-rates?base=MYR", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotBase != "MYR" {
+			t.Errorf("expected base MYR, got %q", gotBase)
+		}
+		rates := parseJSON(t, rec)["rates"].([]interface{})
+		if len(rates) != 1 || rates[0].(map[string]interface{})["to"] != "USD" {
+			t.Errorf("unexpected rates %v", rates)
+		}
+	})
+
+	t.Run("returns 400 on an unknown base", func(t *testing.T) {
+		r := setupExchangeRateRouter(NewExchangeRateHandler(&mockExchangeRateService{}))
+
+		rec := doRequest(r, "GET", "/exchange-rates?base=XYZ", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+}
+
+func TestExchangeRateHandler_RecordExchangeRates(t *testing.T) {
+	t.Run("records rates", func(t *testing.T) {
+		var captured []services.ExchangeRateInput
+		svc := &mockExchangeRateService{
+			recordRatesFn: func(rates []services.ExchangeRateInput) (int, error) {
+				captured = rates
+				return len(rates), nil
+			},
+		}
+		r := setupExchangeRateRouter(NewExchangeRateHandler(svc))
+
+		rec := doRequest(r, "POST", "/pipeline/exchange-rates",
+			`{"rates":[{"from_currency":"USD","to_currency":"MYR","rate":4.7,"recorded_at":"2025-03-01T00:00:00Z"}]}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if len(captured) != 1 || captured[0].FromCurrency != "USD" || captured[0].Rate != 4.7 {
+			t.Errorf("unexpected input %+v", captured)
+		}
+		if parseJSON(t, rec)["rates_recorded"] != float64(1) {
+			t.Errorf("expected rates_recorded=1, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("returns 400 on a same-currency pair", func(t *testing.T) {
+		r := setupExchangeRateRouter(NewExchangeRateHandler(&mockExchangeRateService{}))
+
+		rec := doRequest(r, "POST", "/pipeline/exchange-rates",
+			`{"rates":[{"from_currency":"USD","to_currency":"USD","rate":1,"recorded_at":"2025-03-01T00:00:00Z"}]}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+}
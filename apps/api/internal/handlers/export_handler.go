@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"kuberan/internal/logger"
+	"kuberan/internal/models"
+	"kuberan/internal/services"
+)
+
+// ExportHandler handles full per-user data exports.
+type ExportHandler struct {
+	exportService services.ExportServicer
+	auditService  services.AuditServicer
+}
+
+// NewExportHandler creates a new ExportHandler.
+func NewExportHandler(exportService services.ExportServicer, auditService services.AuditServicer) *ExportHandler {
+	return &ExportHandler{exportService: exportService, auditService: auditService}
+}
+
+// ExportUserData streams all of the user's data as one JSON download.
+// @Summary     Export all user data
+// @Description Stream the user's profile, accounts, categories, budgets, transactions, investments and investment transactions as a single JSON document. Other users' data and secrets such as password hashes are never included. Unlike the per-entity CSV exports, this is a complete dump.
+// @Tags        user
+// @Produce     json
+// @Security    BearerAuth
+// @Success     200 {string} string "JSON file"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     404 {object} ErrorResponse "User not found"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /profile/export [get]
+func (h *ExportHandler) ExportUserData(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("kuberan-export-%s.json", time.Now().UTC().Format("2006-01-02"))
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	if err := h.exportService.ExportUserData(models.UserID(userID), c.Writer); err != nil {
+		// Errors before the first byte can still be returned as JSON; after that the stream is truncated
+		if !c.Writer.Written() {
+			c.Header("Content-Disposition", "")
+			respondWithError(c, err)
+			return
+		}
+		logger.Get().Errorw("failed to write user data export", "error", err, "user_id", userID)
+		return
+	}
+
+	h.auditService.Log(userID, "EXPORT_USER_DATA", "user", userID, c.ClientIP(), nil)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+	"kuberan/internal/services"
+)
+
+// --- mock export service ---
+
+type mockExportService struct {
+	exportUserDataFn func(userID models.UserID, w io.Writer) error
+}
+
+var _ services.ExportServicer = (*mockExportService)(nil)
+
+func (m *mockExportService) ExportUserData(userID models.UserID, w io.Writer) error {
+	if m.exportUserDataFn != nil {
+		return m.exportUserDataFn(userID, w)
+	}
+	_, err := io.WriteString(w, "{}")
+	return err
+}
+
+// --- router setup ---
+
+func setupExportRouter(handler *ExportHandler) *gin.Engine {
+	r := gin.New()
+	auth := r.Group("", injectUserID(testID(1)))
+	auth.GET("/profile/export", handler.ExportUserData)
+	return r
+}
+
+func TestExportHandler_ExportUserData(t *testing.T) {
+	t.Run("streams the dump as a download", func(t *testing.T) {
+		var gotUserID models.UserID
+		svc := &mockExportService{
+			exportUserDataFn: func(userID models.UserID, w io.Writer) error {
+				gotUserID = userID
+				_, err := io.WriteString(w, `{"profile":{},"accounts":[]}`)
+				return err
+			},
+		}
+		r := setupExportRouter(NewExportHandler(svc, &mockAuditService{}))
+
+		rec := doRequest(r, "GET", "/profile/export", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotUserID != models.UserID(testID(1)) {
+			t.Errorf("expected user %s, got %s", testID(1), gotUserID)
+		}
+		if !strings.HasPrefix(rec.Header().Get("Content-Disposition"), "attachment;") {
+			t.Errorf("expected an attachment, got %q", rec.Header().Get("Content-Disposition"))
+		}
+		if _, ok := parseJSON(t, rec)["accounts"]; !ok {
+			t.Errorf("expected the service's document, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("returns JSON error before anything is written", func(t *testing.T) {
+		svc := &mockExportService{
+			exportUserDataFn: func(_ models.UserID, _ io.Writer) error {
+				return apperrors.ErrUserNotFound
+			},
+		}
+		r := setupExportRouter(NewExportHandler(svc, &mockAuditService{}))
+
+		rec := doRequest(r, "GET", "/profile/export", "")
+
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rec.Code)
+		}
+		if rec.Header().Get("Content-Disposition") != "" {
+			t.Errorf("expected no attachment on error, got %q", rec.Header().Get("Content-Disposition"))
+		}
+		assertErrorCode(t, parseJSON(t, rec), "USER_NOT_FOUND")
+	})
+
+	t.Run("returns 401 without auth", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/profile/export", NewExportHandler(&mockExportService{}, &mockAuditService{}).ExportUserData)
+
+		rec := doRequest(r, "GET", "/profile/export", "")
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rec.Code)
+		}
+	})
+}
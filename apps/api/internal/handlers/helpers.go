@@ -1,29 +1,167 @@
 package handlers
 
 import (
+	"encoding/json"
 	"errors"
+	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 
 	apperrors "kuberan/internal/errors"
 	"kuberan/internal/logger"
+	"kuberan/internal/money"
+	"kuberan/internal/pagination"
 	"kuberan/internal/uuid"
 )
 
-// parseFlexibleTime parses a date/time string accepting both RFC3339
-// (e.g. "2006-01-02T15:04:05Z07:00") and date-only (e.g. "2006-01-02") formats.
-// Date-only strings are interpreted as midnight UTC.
-func parseFlexibleTime(value string) (time.Time, error) {
-	// Try RFC3339 first (most specific)
-	if t, err := time.Parse(time.RFC3339, value); err == nil {
+// dateBound says which end of a range a date parameter marks.
+type dateBound int
+
+const (
+	rangeStart dateBound = iota
+	rangeEnd
+)
+
+// parseDateParam parses the date parameter name, trying RFC3339 (with optional
+// fractional seconds), then YYYY-MM-DD, then unix seconds. A date-only value
+// names a whole day in loc (UTC when nil): midnight as a rangeStart and the
+// last nanosecond of the day as a rangeEnd, so to_date always includes the day
+// it names. RFC3339 and unix values are exact instants and used as given.
+func parseDateParam(name, value string, bound dateBound, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
 		return t, nil
 	}
-	// Fall back to date-only format
-	if t, err := time.Parse("2006-01-02", value); err == nil {
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		if bound == rangeEnd {
+			return t.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+		}
 		return t, nil
 	}
-	return time.Time{}, errors.New("invalid date format, use RFC3339 (e.g. 2024-01-01T00:00:00Z) or YYYY-MM-DD")
+	if isDigits(value) {
+		if secs, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return time.Unix(secs, 0).UTC(), nil
+		}
+	}
+	return time.Time{}, apperrors.WithMessage(apperrors.ErrInvalidInput,
+		"invalid "+name+" format, use RFC3339 (e.g. 2024-01-01T00:00:00Z), YYYY-MM-DD or unix seconds")
+}
+
+// isDigits reports whether s is a non-empty run of ASCII digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// bindPage binds page, page_size and with_total from the query and normalizes
+// them, so a page_size above the maximum is clamped rather than rejected.
+func bindPage(c *gin.Context) (pagination.PageRequest, error) {
+	var page pagination.PageRequest
+	if err := c.ShouldBindQuery(&page); err != nil {
+		return page, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error())
+	}
+	page.Normalize()
+	return page, nil
+}
+
+// respondWithPage writes a page of results, with a meta warning when the
+// request's page_size was clamped.
+func respondWithPage[T any](c *gin.Context, page pagination.PageRequest, result *pagination.PageResponse[T]) {
+	result.Meta = page.Meta()
+	c.JSON(http.StatusOK, result)
+}
+
+// bindJSON decodes and validates the JSON body like ShouldBindJSON. Requests
+// with ?strict=true additionally reject fields the payload type does not
+// define, so clients can catch misspelled or unsupported fields. Errors are
+// returned as ErrInvalidInput.
+func bindJSON(c *gin.Context, obj interface{}) error {
+	strict := false
+	if v := c.Query("strict"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			return apperrors.WithMessage(apperrors.ErrInvalidInput, "strict must be true or false")
+		}
+		strict = parsed
+	}
+
+	if !strict {
+		if err := c.ShouldBindJSON(obj); err != nil {
+			return apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error())
+		}
+		return nil
+	}
+
+	if c.Request.Body == nil {
+		return apperrors.WithMessage(apperrors.ErrInvalidInput, "invalid request")
+	}
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		return apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error())
+	}
+	if err := binding.Validator.ValidateStruct(obj); err != nil {
+		return apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error())
+	}
+	return nil
+}
+
+// resolveAmount returns an amount in minor units taken from either the integer
+// field or its "<field>_decimal" string counterpart. Decimal strings are parsed
+// with the precision of the currency returned by currency, which is only
+// called when a decimal value was supplied.
+func resolveAmount(field string, units int64, decimal *string, currency func() (string, error)) (int64, error) {
+	if decimal == nil {
+		return units, nil
+	}
+	if units != 0 {
+		return 0, apperrors.WithMessage(apperrors.ErrInvalidInput, "provide either "+field+" or "+field+"_decimal, not both")
+	}
+	code, err := currency()
+	if err != nil {
+		return 0, err
+	}
+	parsed, err := money.ParseDecimal(*decimal, code)
+	if err != nil {
+		return 0, apperrors.WithMessage(apperrors.ErrInvalidInput, field+"_decimal: "+err.Error())
+	}
+	return parsed, nil
+}
+
+// resolveOptionalAmount is resolveAmount for partial updates, where a nil
+// result means the field was not supplied.
+func resolveOptionalAmount(field string, units *int64, decimal *string, currency func() (string, error)) (*int64, error) {
+	if decimal == nil {
+		return units, nil
+	}
+	if units != nil {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "provide either "+field+" or "+field+"_decimal, not both")
+	}
+	parsed, err := resolveAmount(field, 0, decimal, currency)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// requirePositiveAmount returns ErrInvalidInput unless amount is greater than zero.
+func requirePositiveAmount(field string, amount int64) error {
+	if amount <= 0 {
+		return apperrors.WithMessage(apperrors.ErrInvalidInput, field+" must be greater than 0")
+	}
+	return nil
 }
 
 // getUserID extracts the authenticated user ID from the Gin context.
@@ -68,7 +206,7 @@ func respondWithError(c *gin.Context, err error) {
 				"path", c.Request.URL.Path,
 			)
 		}
-		c.JSON(appErr.StatusCode, gin.H{
+		c.JSON(apperrors.Status(appErr.Code), gin.H{
 			"error": gin.H{
 				"code":    appErr.Code,
 				"message": appErr.Message,
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+)
+
+func TestParseDateParam(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+
+	tests := []struct {
+		name    string
+		value   string
+		bound   dateBound
+		loc     *time.Location
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "rfc3339", value: "2026-03-05T10:30:00Z", bound: rangeStart,
+			want: time.Date(2026, 3, 5, 10, 30, 0, 0, time.UTC)},
+		{name: "rfc3339 with offset", value: "2026-03-05T10:30:00+02:00", bound: rangeStart,
+			want: time.Date(2026, 3, 5, 8, 30, 0, 0, time.UTC)},
+		{name: "rfc3339 with fractional seconds", value: "2026-03-05T10:30:00.25Z", bound: rangeStart,
+			want: time.Date(2026, 3, 5, 10, 30, 0, 250000000, time.UTC)},
+		{name: "rfc3339 end is not moved", value: "2026-03-05T10:30:00Z", bound: rangeEnd,
+			want: time.Date(2026, 3, 5, 10, 30, 0, 0, time.UTC)},
+		{name: "date start", value: "2026-03-05", bound: rangeStart,
+			want: time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "date end is inclusive", value: "2026-03-05", bound: rangeEnd,
+			want: time.Date(2026, 3, 5, 23, 59, 59, 999999999, time.UTC)},
+		{name: "date end across a month", value: "2026-02-28", bound: rangeEnd,
+			want: time.Date(2026, 2, 28, 23, 59, 59, 999999999, time.UTC)},
+		{name: "date in location", value: "2026-03-05", bound: rangeStart, loc: tokyo,
+			want: time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC)},
+		{name: "date end in location", value: "2026-03-05", bound: rangeEnd, loc: tokyo,
+			want: time.Date(2026, 3, 5, 14, 59, 59, 999999999, time.UTC)},
+		{name: "unix seconds", value: "1772706600", bound: rangeStart,
+			want: time.Date(2026, 3, 5, 10, 30, 0, 0, time.UTC)},
+		{name: "unix seconds end is not moved", value: "1772706600", bound: rangeEnd,
+			want: time.Date(2026, 3, 5, 10, 30, 0, 0, time.UTC)},
+		{name: "empty", value: "", bound: rangeStart, wantErr: true},
+		{name: "words", value: "yesterday", bound: rangeStart, wantErr: true},
+		{name: "invalid date", value: "2026-13-01", bound: rangeStart, wantErr: true},
+		{name: "negative unix seconds", value: "-5", bound: rangeStart, wantErr: true},
+		{name: "fractional unix seconds", value: "1772706600.5", bound: rangeStart, wantErr: true},
+		{name: "unix seconds out of range", value: "99999999999999999999", bound: rangeStart, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDateParam("to_date", tt.value, tt.bound, tt.loc)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				var appErr *apperrors.AppError
+				if !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+					t.Errorf("expected INVALID_INPUT, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestListRoutes_ClampPageSize(t *testing.T) {
+	audit := &mockAuditService{}
+	accounts := setupAccountRouter(NewAccountHandler(&mockAccountService{}, audit))
+	investments := setupInvestmentRouter(NewInvestmentHandler(&mockInvestmentService{}, &mockSecurityService{}, audit))
+	securities := setupSecurityRouter(NewSecurityHandler(&mockSecurityService{}, audit))
+	transactions := setupTransactionRouter(NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, audit))
+
+	routes := []struct {
+		router *gin.Engine
+		path   string
+	}{
+		{accounts, "/accounts"},
+		{accounts, "/accounts/archived"},
+		{accounts, "/accounts/" + testID(1) + "/timeline"},
+		{setupBudgetRouter(NewBudgetHandler(&mockBudgetService{}, audit)), "/budgets"},
+		{setupBudgetRouter(NewBudgetHandler(&mockBudgetService{}, audit)), "/budgets?include=progress"},
+		{setupCategoryRouter(NewCategoryHandler(&mockCategoryService{}, audit)), "/categories"},
+		{investments, "/investments"},
+		{investments, "/investments/" + testID(1) + "/transactions"},
+		{investments, "/accounts/" + testID(1) + "/investments"},
+		{setupSnapshotRouter(NewPortfolioSnapshotHandler(&mockPortfolioSnapshotService{}, audit)), "/portfolio/snapshots?from_date=2026-01-01&to_date=2026-01-31"},
+		{setupRuleRouter(NewRuleHandler(&mockRuleService{}, audit)), "/rules"},
+		{securities, "/securities"},
+		{securities, "/securities/" + testID(1) + "/prices?from_date=2026-01-01&to_date=2026-01-31"},
+		{transactions, "/transactions"},
+		{transactions, "/transactions/flagged"},
+		{transactions, "/transactions/transfers"},
+		{transactions, "/accounts/" + testID(1) + "/transactions"},
+	}
+
+	for _, tt := range routes {
+		t.Run(tt.path, func(t *testing.T) {
+			assertPageSizeClamped(t, tt.router, tt.path)
+		})
+	}
+}
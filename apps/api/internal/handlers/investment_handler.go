@@ -1,13 +1,20 @@
 package handlers
 
 import (
+	"encoding/csv"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	apperrors "kuberan/internal/errors"
-	_ "kuberan/internal/models" // swagger type references
+	"kuberan/internal/logger"
+	"kuberan/internal/models"
+	"kuberan/internal/money"
 	"kuberan/internal/pagination"
 	"kuberan/internal/services"
 )
@@ -15,57 +22,110 @@ import (
 // InvestmentHandler handles investment-related requests.
 type InvestmentHandler struct {
 	investmentService services.InvestmentServicer
+	securityService   services.SecurityServicer
 	auditService      services.AuditServicer
 }
 
 // NewInvestmentHandler creates a new InvestmentHandler.
-func NewInvestmentHandler(investmentService services.InvestmentServicer, auditService services.AuditServicer) *InvestmentHandler {
-	return &InvestmentHandler{investmentService: investmentService, auditService: auditService}
+func NewInvestmentHandler(investmentService services.InvestmentServicer, securityService services.SecurityServicer, auditService services.AuditServicer) *InvestmentHandler {
+	return &InvestmentHandler{investmentService: investmentService, securityService: securityService, auditService: auditService}
+}
+
+// securityCurrency returns a lookup of the security's currency, used to parse
+// decimal prices with the right precision.
+func (h *InvestmentHandler) securityCurrency(securityID string) func() (string, error) {
+	return func() (string, error) {
+		security, err := h.securityService.GetSecurityByID(securityID)
+		if err != nil {
+			return "", err
+		}
+		return security.Currency, nil
+	}
+}
+
+// investmentCurrency returns a lookup of the currency of the investment's security.
+func (h *InvestmentHandler) investmentCurrency(userID, investmentID string) func() (string, error) {
+	return func() (string, error) {
+		investment, err := h.investmentService.GetInvestmentByID(models.UserID(userID), models.InvestmentID(investmentID))
+		if err != nil {
+			return "", err
+		}
+		return investment.Security.Currency, nil
+	}
 }
 
 // AddInvestmentRequest represents the request payload for adding an investment.
 type AddInvestmentRequest struct {
-	AccountID string       `json:"account_id" binding:"required"`
-	SecurityID string       `json:"security_id" binding:"required"`
-	Quantity      float64    `json:"quantity" binding:"required,gt=0"`
-	PurchasePrice int64      `json:"purchase_price" binding:"required,gt=0"`
-	WalletAddress string     `json:"wallet_address,omitempty"`
-	Date          *time.Time `json:"date"`                    // optional, defaults to now
-	Fee           int64      `json:"fee" binding:"gte=0"`     // optional, defaults to 0
-	Notes         string     `json:"notes" binding:"max=500"` // optional, defaults to "Initial purchase"
+	AccountID            string     `json:"account_id" binding:"required"`
+	SecurityID           string     `json:"security_id"`                               // catalog security; alternative to symbol, name and asset_type
+	Symbol               string     `json:"symbol" binding:"max=50"`                   // with name and asset_type, finds or adds a catalog security
+	Name                 string     `json:"name" binding:"max=200"`                    // security name when given by symbol
+	AssetType            string     `json:"asset_type" binding:"omitempty,asset_type"` // security asset type when given by symbol
+	Currency             string     `json:"currency" binding:"omitempty,iso4217"`      // security currency when given by symbol, defaults to USD
+	Exchange             string     `json:"exchange" binding:"max=50"`                 // security exchange when given by symbol
+	Quantity             float64    `json:"quantity" binding:"required,gt=0"`
+	PurchasePrice        int64      `json:"purchase_price" binding:"omitempty,gt=0"`
+	PurchasePriceDecimal *string    `json:"purchase_price_decimal"` // alternative to purchase_price, in the security's currency
+	WalletAddress        string     `json:"wallet_address,omitempty"`
+	Date                 *time.Time `json:"date"`                             // optional, defaults to now
+	Fee                  int64      `json:"fee" binding:"gte=0"`              // optional, defaults to 0
+	Notes                string     `json:"notes" binding:"max=500"`          // optional buy transaction notes, defaults to "Initial purchase"
+	FromAccountID        string     `json:"from_account_id"`                  // optional cash account debited for the purchase
+	HoldingNotes         string     `json:"holding_notes" binding:"max=2000"` // optional notes stored on the holding (e.g. thesis)
+	TargetPrice          *int64     `json:"target_price" binding:"omitempty,gte=0"`
+	ForceNew             bool       `json:"force_new"` // create a separate holding even if the account already holds the security
+}
+
+// UpdateInvestmentRequest represents the request payload for updating a holding's metadata.
+type UpdateInvestmentRequest struct {
+	Notes       *string `json:"notes" binding:"omitempty,max=2000"`
+	TargetPrice *int64  `json:"target_price" binding:"omitempty,gte=0"` // 0 clears the target
 }
 
 // RecordBuyRequest represents the request payload for recording a buy transaction.
 type RecordBuyRequest struct {
-	Date         time.Time `json:"date" binding:"required"`
-	Quantity     float64   `json:"quantity" binding:"required,gt=0"`
-	PricePerUnit int64     `json:"price_per_unit" binding:"required,gt=0"`
-	Fee          int64     `json:"fee" binding:"gte=0"`
-	Notes        string    `json:"notes" binding:"max=500"`
+	Date                time.Time `json:"date" binding:"required"`
+	Quantity            float64   `json:"quantity" binding:"required,gt=0"`
+	PricePerUnit        int64     `json:"price_per_unit" binding:"omitempty,gt=0"`
+	PricePerUnitDecimal *string   `json:"price_per_unit_decimal"` // alternative to price_per_unit, in the security's currency
+	Fee                 int64     `json:"fee" binding:"gte=0"`
+	Notes               string    `json:"notes" binding:"max=500"`
+	FromAccountID       string    `json:"from_account_id"` // optional cash account debited for the purchase
+	ConfirmPrice        bool      `json:"confirm_price"`   // record the price even when it is far from the stored price near the date
 }
 
 // RecordSellRequest represents the request payload for recording a sell transaction.
 type RecordSellRequest struct {
-	Date         time.Time `json:"date" binding:"required"`
-	Quantity     float64   `json:"quantity" binding:"required,gt=0"`
-	PricePerUnit int64     `json:"price_per_unit" binding:"required,gt=0"`
-	Fee          int64     `json:"fee" binding:"gte=0"`
-	Notes        string    `json:"notes" binding:"max=500"`
+	Date                time.Time `json:"date" binding:"required"`
+	Quantity            float64   `json:"quantity" binding:"required,gt=0"`
+	PricePerUnit        int64     `json:"price_per_unit" binding:"omitempty,gt=0"`
+	PricePerUnitDecimal *string   `json:"price_per_unit_decimal"` // alternative to price_per_unit, in the security's currency
+	Fee                 int64     `json:"fee" binding:"gte=0"`
+	Notes               string    `json:"notes" binding:"max=500"`
+	ConfirmPrice        bool      `json:"confirm_price"` // record the price even when it is far from the stored price near the date
 }
 
 // RecordDividendRequest represents the request payload for recording a dividend.
 type RecordDividendRequest struct {
-	Date         time.Time `json:"date" binding:"required"`
-	Amount       int64     `json:"amount" binding:"required,gt=0"`
-	DividendType string    `json:"dividend_type" binding:"max=50"`
-	Notes        string    `json:"notes" binding:"max=500"`
+	Date          time.Time `json:"date" binding:"required"`
+	Amount        int64     `json:"amount" binding:"omitempty,gt=0"`
+	AmountDecimal *string   `json:"amount_decimal"` // alternative to amount, in the security's currency
+	DividendType  string    `json:"dividend_type" binding:"max=50"`
+	Notes         string    `json:"notes" binding:"max=500"`
+	ExternalRef   string    `json:"external_ref" binding:"max=100"` // idempotency key; a repeat returns the existing transaction
 }
 
 // RecordSplitRequest represents the request payload for recording a stock split.
+// SplitRatio below 1 is a reverse split, e.g. 0.1 for 1-for-10.
 type RecordSplitRequest struct {
-	Date       time.Time `json:"date" binding:"required"`
-	SplitRatio float64   `json:"split_ratio" binding:"required,gt=0"`
-	Notes      string    `json:"notes" binding:"max=500"`
+	Date        time.Time `json:"date" binding:"required"`
+	SplitRatio  float64   `json:"split_ratio" binding:"required,gt=0"`
+	Notes       string    `json:"notes" binding:"max=500"`
+	ExternalRef string    `json:"external_ref" binding:"max=100"` // idempotency key; a repeat returns the existing transaction
+	// Rounding of the resulting quantity: none (default, keep fractions), down, or nearest
+	Rounding string `json:"rounding" binding:"omitempty,oneof=none down nearest"`
+	// CashInLieuPrice pays units rounded away as a cash-in-lieu dividend at this price per post-split unit
+	CashInLieuPrice int64 `json:"cash_in_lieu_price" binding:"omitempty,gt=0"`
 }
 
 // GetAllInvestments handles listing all investments across all investment accounts.
@@ -77,6 +137,7 @@ type RecordSplitRequest struct {
 // @Security    BearerAuth
 // @Param       page      query int false "Page number (default 1)"
 // @Param       page_size query int false "Items per page (default 20, max 100)"
+// @Param       with_total query bool false "Compute total_items and total_pages (default true); when false they are -1 and 0"
 // @Success     200 {object} pagination.PageResponse[models.Investment] "Paginated investments"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
 // @Failure     500 {object} ErrorResponse "Server error"
@@ -88,33 +149,132 @@ func (h *InvestmentHandler) GetAllInvestments(c *gin.Context) {
 		return
 	}
 
-	var page pagination.PageRequest
-	if err := c.ShouldBindQuery(&page); err != nil {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+	page, err := bindPage(c)
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	result, err := h.investmentService.GetAllInvestments(userID, page)
+	result, err := h.investmentService.GetAllInvestments(models.UserID(userID), page)
 	if err != nil {
 		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	respondWithPage(c, page, result)
+}
+
+// investmentExportPageSize is the number of holdings fetched per page while streaming an export.
+const investmentExportPageSize = 100
+
+// investmentExportHeader lists the CSV columns written by ExportInvestments.
+var investmentExportHeader = []string{
+	"symbol", "name", "quantity", "average_cost", "current_price",
+	"market_value", "cost_basis", "unrealized_gain_loss",
+}
+
+// investmentExportRow builds the CSV row for a single holding.
+func investmentExportRow(inv *models.Investment) []string {
+	marketValue := int64(inv.Quantity * float64(inv.CurrentPrice))
+	var averageCost int64
+	if inv.Quantity > 0 {
+		averageCost = int64(math.Round(float64(inv.CostBasis) / inv.Quantity))
+	}
+	currency := inv.Security.Currency
+	return []string{
+		inv.Security.Symbol,
+		inv.Security.Name,
+		strconv.FormatFloat(inv.Quantity, 'f', -1, 64),
+		money.FormatDecimal(averageCost, currency),
+		money.FormatDecimal(inv.CurrentPrice, currency),
+		money.FormatDecimal(marketValue, currency),
+		money.FormatDecimal(inv.CostBasis, currency),
+		money.FormatDecimal(marketValue-inv.CostBasis, currency),
+	}
+}
+
+// ExportInvestments handles exporting all holdings as a CSV file.
+// @Summary     Export investments
+// @Description Stream all holdings across active investment accounts as CSV (amounts in major units of each security's currency)
+// @Tags        investments
+// @Produce     text/csv
+// @Security    BearerAuth
+// @Param       format query string false "Export format (only csv is supported)"
+// @Success     200 {string} string "CSV file"
+// @Failure     400 {object} ErrorResponse "Unsupported format"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /investments/export [get]
+func (h *InvestmentHandler) ExportInvestments(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	if format := c.DefaultQuery("format", "csv"); format != "csv" {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "format must be 'csv'"))
+		return
+	}
+
+	// Fetch the first page before writing anything so errors can still be returned as JSON
+	page := pagination.PageRequest{Page: 1, PageSize: investmentExportPageSize}
+	result, err := h.investmentService.GetAllInvestments(models.UserID(userID), page)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("investments-%s.csv", time.Now().UTC().Format("2006-01-02"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	// Headers are sent from here on, so failures can only be logged and the stream truncated
+	w := csv.NewWriter(c.Writer)
+	if err := w.Write(investmentExportHeader); err != nil {
+		logger.Get().Errorw("failed to write investment export", "error", err, "user_id", userID)
+		return
+	}
+
+	for {
+		for i := range result.Data {
+			if err := w.Write(investmentExportRow(&result.Data[i])); err != nil {
+				logger.Get().Errorw("failed to write investment export", "error", err, "user_id", userID)
+				return
+			}
+		}
+		w.Flush()
+		c.Writer.Flush()
+
+		if page.Page >= result.TotalPages {
+			break
+		}
+		page.Page++
+		result, err = h.investmentService.GetAllInvestments(models.UserID(userID), page)
+		if err != nil {
+			logger.Get().Errorw("failed to fetch investments for export", "error", err, "user_id", userID, "page", page.Page)
+			return
+		}
+	}
+
+	h.auditService.Log(userID, "EXPORT_INVESTMENTS", "investment", "", c.ClientIP(),
+		map[string]interface{}{"format": "csv"})
 }
 
 // AddInvestment handles adding a new investment holding.
 // @Summary     Add investment
-// @Description Add a new investment holding to an investment account
+// @Description Add a new investment holding to an investment account. Identify the security either by security_id or by symbol, name and asset_type (optionally currency and exchange); a symbol not yet in the catalog is added to it and the holding shows price_pending until the oracle prices it. If the account already holds the security, the purchase is added to that holding as a buy unless force_new is set.
 // @Tags        investments
 // @Accept      json
 // @Produce     json
 // @Security    BearerAuth
 // @Param       request body AddInvestmentRequest true "Investment details"
 // @Success     201 {object} models.Investment "Investment created"
+// @Success     200 {object} models.Investment "Purchase added to an existing holding (merged=true)"
 // @Failure     400 {object} ErrorResponse "Invalid input"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
-// @Failure     404 {object} ErrorResponse "Account not found"
+// @Failure     404 {object} ErrorResponse "Account or security not found"
 // @Failure     500 {object} ErrorResponse "Server error"
 // @Router      /investments [post]
 func (h *InvestmentHandler) AddInvestment(c *gin.Context) {
@@ -130,18 +290,57 @@ func (h *InvestmentHandler) AddInvestment(c *gin.Context) {
 		return
 	}
 
-	investment, err := h.investmentService.AddInvestment(
-		userID, req.AccountID, req.SecurityID, req.Quantity, req.PurchasePrice, req.WalletAddress, req.Date, req.Fee, req.Notes,
+	ref := services.SecurityRef{
+		ID:        req.SecurityID,
+		Symbol:    req.Symbol,
+		Name:      req.Name,
+		AssetType: models.AssetType(req.AssetType),
+		Currency:  req.Currency,
+		Exchange:  req.Exchange,
+	}
+	if err := ref.Validate(); err != nil {
+		respondWithError(c, err)
+		return
+	}
+	currency := h.securityCurrency(req.SecurityID)
+	if req.SecurityID == "" {
+		currency = func() (string, error) {
+			if req.Currency == "" {
+				return "USD", nil
+			}
+			return req.Currency, nil
+		}
+	}
+
+	purchasePrice, err := resolveAmount("purchase_price", req.PurchasePrice, req.PurchasePriceDecimal, currency)
+	if err == nil {
+		err = requirePositiveAmount("purchase_price", purchasePrice)
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	metadata := services.InvestmentMetadata{Notes: req.HoldingNotes, TargetPrice: req.TargetPrice}
+	investment, merged, err := h.investmentService.AddInvestment(
+		models.UserID(userID), models.AccountID(req.AccountID), ref, req.Quantity, models.Cents(purchasePrice), req.WalletAddress, req.Date, models.Cents(req.Fee), req.Notes, models.AccountID(req.FromAccountID), metadata, req.ForceNew,
 	)
 	if err != nil {
 		respondWithError(c, err)
 		return
 	}
 
+	if merged {
+		h.auditService.Log(userID, "INVESTMENT_BUY", "investment", investment.ID, c.ClientIP(),
+			map[string]interface{}{"quantity": req.Quantity, "price_per_unit": purchasePrice})
+		c.JSON(http.StatusOK, gin.H{"investment": investment, "merged": true})
+		return
+	}
+
 	h.auditService.Log(userID, "CREATE_INVESTMENT", "investment", investment.ID, c.ClientIP(),
-		map[string]interface{}{"security_id": req.SecurityID, "quantity": req.Quantity})
+		map[string]interface{}{"security_id": investment.SecurityID, "quantity": req.Quantity})
 
-	c.JSON(http.StatusCreated, gin.H{"investment": investment})
+	c.JSON(http.StatusCreated, gin.H{"investment": investment, "merged": false})
 }
 
 // GetAccountInvestments handles listing investments for an account.
@@ -154,6 +353,7 @@ func (h *InvestmentHandler) AddInvestment(c *gin.Context) {
 // @Param       id        path  int false "Account ID"
 // @Param       page      query int false "Page number (default 1)"
 // @Param       page_size query int false "Items per page (default 20, max 100)"
+// @Param       with_total query bool false "Compute total_items and total_pages (default true); when false they are -1 and 0"
 // @Success     200 {object} pagination.PageResponse[models.Investment] "Paginated investments"
 // @Failure     400 {object} ErrorResponse "Invalid input"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
@@ -173,24 +373,24 @@ func (h *InvestmentHandler) GetAccountInvestments(c *gin.Context) {
 		return
 	}
 
-	var page pagination.PageRequest
-	if err := c.ShouldBindQuery(&page); err != nil {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+	page, err := bindPage(c)
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	result, err := h.investmentService.GetAccountInvestments(userID, accountID, page)
+	result, err := h.investmentService.GetAccountInvestments(models.UserID(userID), models.AccountID(accountID), page)
 	if err != nil {
 		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	respondWithPage(c, page, result)
 }
 
 // GetInvestment handles retrieving a specific investment.
 // @Summary     Get investment by ID
-// @Description Get a specific investment by ID
+// @Description Get a specific investment by ID, with its lifetime fees (total_fees) and dividends received (total_dividends)
 // @Tags        investments
 // @Accept      json
 // @Produce     json
@@ -215,7 +415,7 @@ func (h *InvestmentHandler) GetInvestment(c *gin.Context) {
 		return
 	}
 
-	investment, err := h.investmentService.GetInvestmentByID(userID, investmentID)
+	investment, err := h.investmentService.GetInvestmentByID(models.UserID(userID), models.InvestmentID(investmentID))
 	if err != nil {
 		respondWithError(c, err)
 		return
@@ -224,16 +424,74 @@ func (h *InvestmentHandler) GetInvestment(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"investment": investment})
 }
 
-// GetPortfolio handles retrieving the aggregated portfolio summary.
-// @Summary     Get portfolio summary
-// @Description Get an aggregated portfolio summary across all investment accounts
+// UpdateInvestment handles updating a holding's notes and target price.
+// @Summary     Update investment
+// @Description Update the notes and target price of a holding. Quantity and cost basis change only through transactions. A target_price of 0 clears the target.
 // @Tags        investments
 // @Accept      json
 // @Produce     json
 // @Security    BearerAuth
-// @Success     200 {object} services.PortfolioSummary "Portfolio summary"
+// @Param       id      path string                  true "Investment ID"
+// @Param       request body UpdateInvestmentRequest true "Fields to update"
+// @Success     200 {object} models.Investment "Updated investment"
+// @Failure     400 {object} ErrorResponse "Invalid input"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     404 {object} ErrorResponse "Investment not found"
 // @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /investments/{id} [put]
+func (h *InvestmentHandler) UpdateInvestment(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	investmentID, err := parsePathID(c, "id")
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	var req UpdateInvestmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+		return
+	}
+
+	updates := services.InvestmentUpdateFields{Notes: req.Notes}
+	if req.TargetPrice != nil {
+		target := req.TargetPrice
+		if *target == 0 {
+			target = nil
+		}
+		updates.TargetPrice = &target
+	}
+
+	investment, err := h.investmentService.UpdateInvestment(models.UserID(userID), models.InvestmentID(investmentID), updates)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(userID, "UPDATE_INVESTMENT", "investment", investmentID, c.ClientIP(),
+		map[string]interface{}{"target_price": req.TargetPrice})
+
+	c.JSON(http.StatusOK, gin.H{"investment": investment})
+}
+
+// GetPortfolio handles retrieving the aggregated portfolio summary.
+// @Summary     Get portfolio summary
+// @Description Get an aggregated portfolio summary across all investment accounts. Totals are converted to the user's base currency at the latest exchange rate, values from the currency their price was recorded in (value_currency); each holding lists its native and converted value. Amounts in currencies without a rate are added unconverted and their currencies listed in unconverted_currencies. With include=sparklines, each holding also carries the last 7 daily closes of its security and their percentage change.
+// @Tags        investments
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       include query    string false "Pass 'sparklines' to embed 7-day price sparklines"
+// @Success     200     {object} services.PortfolioSummary "Portfolio summary"
+// @Failure     400     {object} ErrorResponse "Invalid include"
+// @Failure     401     {object} ErrorResponse "Unauthorized"
+// @Failure     500     {object} ErrorResponse "Server error"
+// @Failure     503     {object} ErrorResponse "Request timed out"
 // @Router      /investments/portfolio [get]
 func (h *InvestmentHandler) GetPortfolio(c *gin.Context) {
 	userID, err := getUserID(c)
@@ -242,18 +500,77 @@ func (h *InvestmentHandler) GetPortfolio(c *gin.Context) {
 		return
 	}
 
-	summary, err := h.investmentService.GetPortfolio(userID)
+	withSparklines := false
+	switch c.Query("include") {
+	case "":
+	case "sparklines":
+		withSparklines = true
+	default:
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "include must be 'sparklines'"))
+		return
+	}
+
+	summary, err := h.investmentService.GetPortfolio(c.Request.Context(), models.UserID(userID))
 	if err != nil {
 		respondWithError(c, err)
 		return
 	}
 
+	if withSparklines {
+		sparklines, err := h.investmentService.GetHoldingSparklines(summary.Holdings)
+		if err != nil {
+			respondWithError(c, err)
+			return
+		}
+		for i := range summary.Holdings {
+			summary.Holdings[i].Sparkline = sparklines[summary.Holdings[i].SecurityID]
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{"portfolio": summary})
 }
 
+// GetTaxReport handles retrieving realized gains for a tax year.
+// @Summary     Get realized-gain tax report
+// @Description Summarize sells in a calendar year with FIFO-matched cost basis, split into short-term (held one year or less) and long-term gains
+// @Tags        investments
+// @Produce     json
+// @Security    BearerAuth
+// @Param       year query int false "Calendar year (default current year)"
+// @Success     200 {object} services.TaxReport "Tax report"
+// @Failure     400 {object} ErrorResponse "Invalid year"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /investments/tax-report [get]
+func (h *InvestmentHandler) GetTaxReport(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	year := time.Now().UTC().Year()
+	if v := c.Query("year"); v != "" {
+		parsed, parseErr := strconv.Atoi(v)
+		if parseErr != nil || parsed < 1900 || parsed > 9999 {
+			respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "year must be a four-digit year"))
+			return
+		}
+		year = parsed
+	}
+
+	report, err := h.investmentService.GetTaxReport(models.UserID(userID), year)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"report": report})
+}
+
 // RecordBuy handles recording a buy transaction for an investment.
 // @Summary     Record buy transaction
-// @Description Record a buy transaction for an investment holding
+// @Description Record a buy transaction for an investment holding, optionally debiting a cash account for the total. A price far from the stored price near the trade date is refused with PRICE_DEVIATION_WARNING unless confirm_price is set.
 // @Tags        investments
 // @Accept      json
 // @Produce     json
@@ -264,6 +581,7 @@ func (h *InvestmentHandler) GetPortfolio(c *gin.Context) {
 // @Failure     400 {object} ErrorResponse "Invalid input"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
 // @Failure     404 {object} ErrorResponse "Investment not found"
+// @Failure     422 {object} ErrorResponse "Price deviates from the stored price (PRICE_DEVIATION_WARNING)"
 // @Failure     500 {object} ErrorResponse "Server error"
 // @Router      /investments/{id}/buy [post]
 func (h *InvestmentHandler) RecordBuy(c *gin.Context) {
@@ -285,21 +603,30 @@ func (h *InvestmentHandler) RecordBuy(c *gin.Context) {
 		return
 	}
 
-	invTx, err := h.investmentService.RecordBuy(userID, investmentID, req.Date, req.Quantity, req.PricePerUnit, req.Fee, req.Notes)
+	pricePerUnit, err := resolveAmount("price_per_unit", req.PricePerUnit, req.PricePerUnitDecimal, h.investmentCurrency(userID, investmentID))
+	if err == nil {
+		err = requirePositiveAmount("price_per_unit", pricePerUnit)
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	invTx, err := h.investmentService.RecordBuy(models.UserID(userID), models.InvestmentID(investmentID), req.Date, req.Quantity, models.Cents(pricePerUnit), models.Cents(req.Fee), req.Notes, models.AccountID(req.FromAccountID), req.ConfirmPrice)
 	if err != nil {
 		respondWithError(c, err)
 		return
 	}
 
 	h.auditService.Log(userID, "INVESTMENT_BUY", "investment", investmentID, c.ClientIP(),
-		map[string]interface{}{"quantity": req.Quantity, "price_per_unit": req.PricePerUnit})
+		map[string]interface{}{"quantity": req.Quantity, "price_per_unit": pricePerUnit})
 
 	c.JSON(http.StatusCreated, gin.H{"transaction": invTx})
 }
 
 // RecordSell handles recording a sell transaction for an investment.
 // @Summary     Record sell transaction
-// @Description Record a sell transaction for an investment holding
+// @Description Record a sell transaction for an investment holding. A price far from the stored price near the trade date is refused with PRICE_DEVIATION_WARNING unless confirm_price is set.
 // @Tags        investments
 // @Accept      json
 // @Produce     json
@@ -310,6 +637,7 @@ func (h *InvestmentHandler) RecordBuy(c *gin.Context) {
 // @Failure     400 {object} ErrorResponse "Invalid input or insufficient shares"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
 // @Failure     404 {object} ErrorResponse "Investment not found"
+// @Failure     422 {object} ErrorResponse "Price deviates from the stored price (PRICE_DEVIATION_WARNING)"
 // @Failure     500 {object} ErrorResponse "Server error"
 // @Router      /investments/{id}/sell [post]
 func (h *InvestmentHandler) RecordSell(c *gin.Context) {
@@ -331,14 +659,23 @@ func (h *InvestmentHandler) RecordSell(c *gin.Context) {
 		return
 	}
 
-	invTx, err := h.investmentService.RecordSell(userID, investmentID, req.Date, req.Quantity, req.PricePerUnit, req.Fee, req.Notes)
+	pricePerUnit, err := resolveAmount("price_per_unit", req.PricePerUnit, req.PricePerUnitDecimal, h.investmentCurrency(userID, investmentID))
+	if err == nil {
+		err = requirePositiveAmount("price_per_unit", pricePerUnit)
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	invTx, err := h.investmentService.RecordSell(models.UserID(userID), models.InvestmentID(investmentID), req.Date, req.Quantity, models.Cents(pricePerUnit), models.Cents(req.Fee), req.Notes, req.ConfirmPrice)
 	if err != nil {
 		respondWithError(c, err)
 		return
 	}
 
 	h.auditService.Log(userID, "INVESTMENT_SELL", "investment", investmentID, c.ClientIP(),
-		map[string]interface{}{"quantity": req.Quantity, "price_per_unit": req.PricePerUnit})
+		map[string]interface{}{"quantity": req.Quantity, "price_per_unit": pricePerUnit})
 
 	c.JSON(http.StatusCreated, gin.H{"transaction": invTx})
 }
@@ -377,21 +714,46 @@ func (h *InvestmentHandler) RecordDividend(c *gin.Context) {
 		return
 	}
 
-	invTx, err := h.investmentService.RecordDividend(userID, investmentID, req.Date, req.Amount, req.DividendType, req.Notes)
+	dividendType, err := normalizeDividendType(req.DividendType)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	amount, err := resolveAmount("amount", req.Amount, req.AmountDecimal, h.investmentCurrency(userID, investmentID))
+	if err == nil {
+		err = requirePositiveAmount("amount", amount)
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	invTx, err := h.investmentService.RecordDividend(models.UserID(userID), models.InvestmentID(investmentID), req.Date, models.Cents(amount), dividendType, req.Notes, req.ExternalRef)
 	if err != nil {
 		respondWithError(c, err)
 		return
 	}
 
 	h.auditService.Log(userID, "INVESTMENT_DIVIDEND", "investment", investmentID, c.ClientIP(),
-		map[string]interface{}{"amount": req.Amount, "dividend_type": req.DividendType})
+		map[string]interface{}{"amount": amount, "dividend_type": dividendType})
 
 	c.JSON(http.StatusCreated, gin.H{"transaction": invTx})
 }
 
+// normalizeDividendType lowercases dividend_type and rejects anything but cash,
+// stock, special and return_of_capital. An empty type stays empty.
+func normalizeDividendType(value string) (string, error) {
+	switch t := strings.ToLower(strings.TrimSpace(value)); t {
+	case "", models.DividendCash, models.DividendStock, models.DividendSpecial, models.DividendReturnOfCapital:
+		return t, nil
+	}
+	return "", apperrors.WithMessage(apperrors.ErrInvalidInput, "dividend_type must be cash, stock, special or return_of_capital")
+}
+
 // RecordSplit handles recording a stock split for an investment.
 // @Summary     Record stock split
-// @Description Record a stock split for an investment holding
+// @Description Record a stock split for an investment holding. A split_ratio below 1 is a reverse split; rounding optionally rounds the new quantity to whole units, paying the fraction rounded away as a cash-in-lieu dividend when cash_in_lieu_price is set.
 // @Tags        investments
 // @Accept      json
 // @Produce     json
@@ -423,21 +785,25 @@ func (h *InvestmentHandler) RecordSplit(c *gin.Context) {
 		return
 	}
 
-	invTx, err := h.investmentService.RecordSplit(userID, investmentID, req.Date, req.SplitRatio, req.Notes)
+	options := services.SplitOptions{
+		Rounding:        services.SplitRounding(req.Rounding),
+		CashInLieuPrice: models.Cents(req.CashInLieuPrice),
+	}
+	invTx, err := h.investmentService.RecordSplit(models.UserID(userID), models.InvestmentID(investmentID), req.Date, req.SplitRatio, options, req.Notes, req.ExternalRef)
 	if err != nil {
 		respondWithError(c, err)
 		return
 	}
 
 	h.auditService.Log(userID, "INVESTMENT_SPLIT", "investment", investmentID, c.ClientIP(),
-		map[string]interface{}{"split_ratio": req.SplitRatio})
+		map[string]interface{}{"split_ratio": req.SplitRatio, "split_remainder": invTx.SplitRemainder})
 
 	c.JSON(http.StatusCreated, gin.H{"transaction": invTx})
 }
 
 // GetInvestmentTransactions handles listing transactions for an investment.
 // @Summary     Get investment transactions
-// @Description Get a paginated list of transactions for an investment
+// @Description Get a paginated list of transactions for an investment, newest first. Each includes running_quantity and running_cost_basis: the position after it, replayed from the investment's first transaction.
 // @Tags        investments
 // @Accept      json
 // @Produce     json
@@ -445,6 +811,7 @@ func (h *InvestmentHandler) RecordSplit(c *gin.Context) {
 // @Param       id        path  int true "Investment ID"
 // @Param       page      query int false "Page number (default 1)"
 // @Param       page_size query int false "Items per page (default 20, max 100)"
+// @Param       with_total query bool false "Compute total_items and total_pages (default true); when false they are -1 and 0"
 // @Success     200 {object} pagination.PageResponse[models.InvestmentTransaction] "Paginated transactions"
 // @Failure     400 {object} ErrorResponse "Invalid input"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
@@ -464,17 +831,17 @@ func (h *InvestmentHandler) GetInvestmentTransactions(c *gin.Context) {
 		return
 	}
 
-	var page pagination.PageRequest
-	if err := c.ShouldBindQuery(&page); err != nil {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+	page, err := bindPage(c)
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	result, err := h.investmentService.GetInvestmentTransactions(userID, investmentID, page)
+	result, err := h.investmentService.GetInvestmentTransactions(models.UserID(userID), models.InvestmentID(investmentID), page)
 	if err != nil {
 		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	respondWithPage(c, page, result)
 }
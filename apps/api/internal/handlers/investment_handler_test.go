@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"context"
+	"encoding/csv"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,100 +19,127 @@ import (
 // --- mock investment service ---
 
 type mockInvestmentService struct {
-	addInvestmentFn             func(userID, accountID, securityID uint, quantity float64, purchasePrice int64, walletAddress string, date *time.Time, fee int64, notes string) (*models.Investment, error)
-	getAllInvestmentsFn         func(userID uint, page pagination.PageRequest) (*pagination.PageResponse[models.Investment], error)
-	getAccountInvestmentsFn     func(userID, accountID uint, page pagination.PageRequest) (*pagination.PageResponse[models.Investment], error)
-	getInvestmentByIDFn         func(userID, investmentID uint) (*models.Investment, error)
-	getPortfolioFn              func(userID uint) (*services.PortfolioSummary, error)
-	recordBuyFn                 func(userID, investmentID uint, date time.Time, quantity float64, pricePerUnit int64, fee int64, notes string) (*models.InvestmentTransaction, error)
-	recordSellFn                func(userID, investmentID uint, date time.Time, quantity float64, pricePerUnit int64, fee int64, notes string) (*models.InvestmentTransaction, error)
-	recordDividendFn            func(userID, investmentID uint, date time.Time, amount int64, dividendType, notes string) (*models.InvestmentTransaction, error)
-	recordSplitFn               func(userID, investmentID uint, date time.Time, splitRatio float64, notes string) (*models.InvestmentTransaction, error)
-	getInvestmentTransactionsFn func(userID, investmentID uint, page pagination.PageRequest) (*pagination.PageResponse[models.InvestmentTransaction], error)
+	addInvestmentFn             func(userID models.UserID, accountID models.AccountID, security services.SecurityRef, quantity float64, purchasePrice models.Cents, walletAddress string, date *time.Time, fee models.Cents, notes string, fromAccountID models.AccountID, metadata services.InvestmentMetadata, forceNew bool) (*models.Investment, bool, error)
+	getAllInvestmentsFn         func(userID models.UserID, page pagination.PageRequest) (*pagination.PageResponse[models.Investment], error)
+	getAccountInvestmentsFn     func(userID models.UserID, accountID models.AccountID, page pagination.PageRequest) (*pagination.PageResponse[models.Investment], error)
+	getInvestmentByIDFn         func(userID models.UserID, investmentID models.InvestmentID) (*models.Investment, error)
+	updateInvestmentFn          func(userID models.UserID, investmentID models.InvestmentID, updates services.InvestmentUpdateFields) (*models.Investment, error)
+	getPortfolioFn              func(userID models.UserID) (*services.PortfolioSummary, error)
+	getHoldingSparklinesFn      func(holdings []services.PortfolioHolding) (map[string]*services.Sparkline, error)
+	recordBuyFn                 func(userID models.UserID, investmentID models.InvestmentID, date time.Time, quantity float64, pricePerUnit, fee models.Cents, notes string, fromAccountID models.AccountID, confirmPrice bool) (*models.InvestmentTransaction, error)
+	recordSellFn                func(userID models.UserID, investmentID models.InvestmentID, date time.Time, quantity float64, pricePerUnit, fee models.Cents, notes string, confirmPrice bool) (*models.InvestmentTransaction, error)
+	recordDividendFn            func(userID models.UserID, investmentID models.InvestmentID, date time.Time, amount models.Cents, dividendType, notes, externalRef string) (*models.InvestmentTransaction, error)
+	recordSplitFn               func(userID models.UserID, investmentID models.InvestmentID, date time.Time, splitRatio float64, options services.SplitOptions, notes, externalRef string) (*models.InvestmentTransaction, error)
+	getInvestmentTransactionsFn func(userID models.UserID, investmentID models.InvestmentID, page pagination.PageRequest) (*pagination.PageResponse[models.InvestmentTransaction], error)
+	getTaxReportFn              func(userID models.UserID, year int) (*services.TaxReport, error)
 }
 
-func (m *mockInvestmentService) AddInvestment(userID, accountID, securityID uint, quantity float64, purchasePrice int64, walletAddress string, date *time.Time, fee int64, notes string) (*models.Investment, error) {
+func (m *mockInvestmentService) AddInvestment(userID models.UserID, accountID models.AccountID, security services.SecurityRef, quantity float64, purchasePrice models.Cents, walletAddress string, date *time.Time, fee models.Cents, notes string, fromAccountID models.AccountID, metadata services.InvestmentMetadata, forceNew bool) (*models.Investment, bool, error) {
 	if m.addInvestmentFn != nil {
-		return m.addInvestmentFn(userID, accountID, securityID, quantity, purchasePrice, walletAddress, date, fee, notes)
+		return m.addInvestmentFn(userID, accountID, security, quantity, purchasePrice, walletAddress, date, fee, notes, fromAccountID, metadata, forceNew)
 	}
-	return &models.Investment{}, nil
+	return &models.Investment{}, false, nil
 }
 
-func (m *mockInvestmentService) GetAllInvestments(userID uint, page pagination.PageRequest) (*pagination.PageResponse[models.Investment], error) {
+func (m *mockInvestmentService) GetAllInvestments(userID models.UserID, page pagination.PageRequest) (*pagination.PageResponse[models.Investment], error) {
 	if m.getAllInvestmentsFn != nil {
 		return m.getAllInvestmentsFn(userID, page)
 	}
-	resp := pagination.NewPageResponse([]models.Investment{}, 1, 20, 0)
+	resp := pagination.NewPageResponse([]models.Investment{}, page.Page, page.PageSize, 0)
 	return &resp, nil
 }
 
-func (m *mockInvestmentService) GetAccountInvestments(userID, accountID uint, page pagination.PageRequest) (*pagination.PageResponse[models.Investment], error) {
+func (m *mockInvestmentService) GetAccountInvestments(userID models.UserID, accountID models.AccountID, page pagination.PageRequest) (*pagination.PageResponse[models.Investment], error) {
 	if m.getAccountInvestmentsFn != nil {
 		return m.getAccountInvestmentsFn(userID, accountID, page)
 	}
-	resp := pagination.NewPageResponse([]models.Investment{}, 1, 20, 0)
+	resp := pagination.NewPageResponse([]models.Investment{}, page.Page, page.PageSize, 0)
 	return &resp, nil
 }
 
-func (m *mockInvestmentService) GetInvestmentByID(userID, investmentID uint) (*models.Investment, error) {
+func (m *mockInvestmentService) GetInvestmentByID(userID models.UserID, investmentID models.InvestmentID) (*models.Investment, error) {
 	if m.getInvestmentByIDFn != nil {
 		return m.getInvestmentByIDFn(userID, investmentID)
 	}
 	return &models.Investment{}, nil
 }
 
-func (m *mockInvestmentService) GetPortfolio(userID uint) (*services.PortfolioSummary, error) {
+func (m *mockInvestmentService) UpdateInvestment(userID models.UserID, investmentID models.InvestmentID, updates services.InvestmentUpdateFields) (*models.Investment, error) {
+	if m.updateInvestmentFn != nil {
+		return m.updateInvestmentFn(userID, investmentID, updates)
+	}
+	return &models.Investment{}, nil
+}
+
+func (m *mockInvestmentService) GetPortfolio(_ context.Context, userID models.UserID) (*services.PortfolioSummary, error) {
 	if m.getPortfolioFn != nil {
 		return m.getPortfolioFn(userID)
 	}
 	return &services.PortfolioSummary{HoldingsByType: map[models.AssetType]services.TypeSummary{}}, nil
 }
 
-func (m *mockInvestmentService) RecordBuy(userID, investmentID uint, date time.Time, quantity float64, pricePerUnit, fee int64, notes string) (*models.InvestmentTransaction, error) {
+func (m *mockInvestmentService) GetHoldingSparklines(holdings []services.PortfolioHolding) (map[string]*services.Sparkline, error) {
+	if m.getHoldingSparklinesFn != nil {
+		return m.getHoldingSparklinesFn(holdings)
+	}
+	return map[string]*services.Sparkline{}, nil
+}
+
+func (m *mockInvestmentService) RecordBuy(userID models.UserID, investmentID models.InvestmentID, date time.Time, quantity float64, pricePerUnit, fee models.Cents, notes string, fromAccountID models.AccountID, confirmPrice bool) (*models.InvestmentTransaction, error) {
 	if m.recordBuyFn != nil {
-		return m.recordBuyFn(userID, investmentID, date, quantity, pricePerUnit, fee, notes)
+		return m.recordBuyFn(userID, investmentID, date, quantity, pricePerUnit, fee, notes, fromAccountID, confirmPrice)
 	}
 	return &models.InvestmentTransaction{}, nil
 }
 
-func (m *mockInvestmentService) RecordSell(userID, investmentID uint, date time.Time, quantity float64, pricePerUnit, fee int64, notes string) (*models.InvestmentTransaction, error) {
+func (m *mockInvestmentService) RecordSell(userID models.UserID, investmentID models.InvestmentID, date time.Time, quantity float64, pricePerUnit, fee models.Cents, notes string, confirmPrice bool) (*models.InvestmentTransaction, error) {
 	if m.recordSellFn != nil {
-		return m.recordSellFn(userID, investmentID, date, quantity, pricePerUnit, fee, notes)
+		return m.recordSellFn(userID, investmentID, date, quantity, pricePerUnit, fee, notes, confirmPrice)
 	}
 	return &models.InvestmentTransaction{}, nil
 }
 
-func (m *mockInvestmentService) RecordDividend(userID, investmentID uint, date time.Time, amount int64, dividendType, notes string) (*models.InvestmentTransaction, error) {
+func (m *mockInvestmentService) RecordDividend(userID models.UserID, investmentID models.InvestmentID, date time.Time, amount models.Cents, dividendType, notes, externalRef string) (*models.InvestmentTransaction, error) {
 	if m.recordDividendFn != nil {
-		return m.recordDividendFn(userID, investmentID, date, amount, dividendType, notes)
+		return m.recordDividendFn(userID, investmentID, date, amount, dividendType, notes, externalRef)
 	}
 	return &models.InvestmentTransaction{}, nil
 }
 
-func (m *mockInvestmentService) RecordSplit(userID, investmentID uint, date time.Time, splitRatio float64, notes string) (*models.InvestmentTransaction, error) {
+func (m *mockInvestmentService) RecordSplit(userID models.UserID, investmentID models.InvestmentID, date time.Time, splitRatio float64, options services.SplitOptions, notes, externalRef string) (*models.InvestmentTransaction, error) {
 	if m.recordSplitFn != nil {
-		return m.recordSplitFn(userID, investmentID, date, splitRatio, notes)
+		return m.recordSplitFn(userID, investmentID, date, splitRatio, options, notes, externalRef)
 	}
 	return &models.InvestmentTransaction{}, nil
 }
 
-func (m *mockInvestmentService) GetInvestmentTransactions(userID, investmentID uint, page pagination.PageRequest) (*pagination.PageResponse[models.InvestmentTransaction], error) {
+func (m *mockInvestmentService) GetInvestmentTransactions(userID models.UserID, investmentID models.InvestmentID, page pagination.PageRequest) (*pagination.PageResponse[models.InvestmentTransaction], error) {
 	if m.getInvestmentTransactionsFn != nil {
 		return m.getInvestmentTransactionsFn(userID, investmentID, page)
 	}
-	resp := pagination.NewPageResponse([]models.InvestmentTransaction{}, 1, 20, 0)
+	resp := pagination.NewPageResponse([]models.InvestmentTransaction{}, page.Page, page.PageSize, 0)
 	return &resp, nil
 }
 
+func (m *mockInvestmentService) GetTaxReport(userID models.UserID, year int) (*services.TaxReport, error) {
+	if m.getTaxReportFn != nil {
+		return m.getTaxReportFn(userID, year)
+	}
+	return &services.TaxReport{Year: year, Sales: []services.TaxReportSale{}}, nil
+}
+
 var _ services.InvestmentServicer = (*mockInvestmentService)(nil)
 
 func setupInvestmentRouter(handler *InvestmentHandler) *gin.Engine {
 	r := gin.New()
-	auth := r.Group("", injectUserID(1))
+	auth := r.Group("", injectUserID(testID(1)))
 	auth.POST("/investments", handler.AddInvestment)
 	auth.GET("/investments", handler.GetAllInvestments)
 	auth.GET("/investments/portfolio", handler.GetPortfolio)
+	auth.GET("/investments/tax-report", handler.GetTaxReport)
+	auth.GET("/investments/export", handler.ExportInvestments)
 	auth.GET("/investments/:id", handler.GetInvestment)
+	auth.PUT("/investments/:id", handler.UpdateInvestment)
 	auth.POST("/investments/:id/buy", handler.RecordBuy)
 	auth.POST("/investments/:id/sell", handler.RecordSell)
 	auth.POST("/investments/:id/dividend", handler.RecordDividend)
@@ -122,21 +152,21 @@ func setupInvestmentRouter(handler *InvestmentHandler) *gin.Engine {
 func TestInvestmentHandler_AddInvestment(t *testing.T) {
 	t.Run("returns 201 on success", func(t *testing.T) {
 		svc := &mockInvestmentService{
-			addInvestmentFn: func(_ uint, accountID, securityID uint, quantity float64, price int64, _ string, _ *time.Time, _ int64, _ string) (*models.Investment, error) {
+			addInvestmentFn: func(_ models.UserID, accountID models.AccountID, security services.SecurityRef, quantity float64, price models.Cents, _ string, _ *time.Time, _ models.Cents, _ string, _ models.AccountID, _ services.InvestmentMetadata, _ bool) (*models.Investment, bool, error) {
 				return &models.Investment{
-					Base:       models.Base{ID: 1},
-					AccountID:  accountID,
-					SecurityID: securityID,
+					Base:       models.Base{ID: testID(1)},
+					AccountID:  string(accountID),
+					SecurityID: security.ID,
 					Quantity:   quantity,
 					CostBasis:  int64(quantity * float64(price)),
-				}, nil
+				}, false, nil
 			},
 		}
-		handler := NewInvestmentHandler(svc, &mockAuditService{})
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
 		rec := doRequest(r, "POST", "/investments",
-			`{"account_id":1,"security_id":1,"quantity":10,"purchase_price":15000}`)
+			`{"account_id":"00000000-0000-7000-8000-000000000001","security_id":"00000000-0000-7000-8000-000000000001","quantity":10,"purchase_price":15000}`)
 
 		if rec.Code != http.StatusCreated {
 			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
@@ -148,12 +178,147 @@ func TestInvestmentHandler_AddInvestment(t *testing.T) {
 		}
 	})
 
+	t.Run("converts purchase_price_decimal using the security currency", func(t *testing.T) {
+		tests := []struct {
+			currency   string
+			decimal    string
+			wantStatus int
+			wantPrice  int64
+		}{
+			{currency: "JPY", decimal: "18500", wantStatus: http.StatusCreated, wantPrice: 18500},
+			{currency: "JPY", decimal: "185.5", wantStatus: http.StatusBadRequest},
+			{currency: "USD", decimal: "150.25", wantStatus: http.StatusCreated, wantPrice: 15025},
+			{currency: "BHD", decimal: "12.125", wantStatus: http.StatusCreated, wantPrice: 12125},
+			{currency: "BTC", decimal: "0.015", wantStatus: http.StatusCreated, wantPrice: 1500000},
+			{currency: "BTC", decimal: "0.000000005", wantStatus: http.StatusBadRequest},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.currency+"_"+tt.decimal, func(t *testing.T) {
+				var gotPrice int64
+				svc := &mockInvestmentService{
+					addInvestmentFn: func(_ models.UserID, _ models.AccountID, _ services.SecurityRef, _ float64, price models.Cents, _ string, _ *time.Time, _ models.Cents, _ string, _ models.AccountID, _ services.InvestmentMetadata, _ bool) (*models.Investment, bool, error) {
+						gotPrice = int64(price)
+						return &models.Investment{Base: models.Base{ID: testID(1)}}, false, nil
+					},
+				}
+				secSvc := &mockSecurityService{
+					getSecurityByIDFn: func(id string) (*models.Security, error) {
+						return &models.Security{Base: models.Base{ID: id}, Currency: tt.currency}, nil
+					},
+				}
+				handler := NewInvestmentHandler(svc, secSvc, &mockAuditService{})
+				r := setupInvestmentRouter(handler)
+
+				rec := doRequest(r, "POST", "/investments",
+					`{"account_id":"00000000-0000-7000-8000-000000000001","security_id":"00000000-0000-7000-8000-000000000002","quantity":1,"purchase_price_decimal":"`+tt.decimal+`"}`)
+
+				if rec.Code != tt.wantStatus {
+					t.Fatalf("expected %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+				}
+				if tt.wantStatus == http.StatusCreated && gotPrice != tt.wantPrice {
+					t.Errorf("expected purchase price %d, got %d", tt.wantPrice, gotPrice)
+				}
+			})
+		}
+	})
+
+	t.Run("returns 400 on missing purchase price", func(t *testing.T) {
+		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "POST", "/investments",
+			`{"account_id":"00000000-0000-7000-8000-000000000001","security_id":"00000000-0000-7000-8000-000000000002","quantity":1}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("passes holding notes and target price to service", func(t *testing.T) {
+		var got services.InvestmentMetadata
+		svc := &mockInvestmentService{
+			addInvestmentFn: func(_ models.UserID, _ models.AccountID, _ services.SecurityRef, _ float64, _ models.Cents, _ string, _ *time.Time, _ models.Cents, _ string, _ models.AccountID, metadata services.InvestmentMetadata, _ bool) (*models.Investment, bool, error) {
+				got = metadata
+				return &models.Investment{Base: models.Base{ID: testID(1)}}, false, nil
+			},
+		}
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "POST", "/investments",
+			`{"account_id":"00000000-0000-7000-8000-000000000001","security_id":"00000000-0000-7000-8000-000000000002","quantity":1,"purchase_price":15000,"holding_notes":"Dividend grower","target_price":18000}`)
+
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if got.Notes != "Dividend grower" {
+			t.Errorf("expected holding notes to be passed, got %q", got.Notes)
+		}
+		if got.TargetPrice == nil || *got.TargetPrice != 18000 {
+			t.Errorf("expected target price 18000, got %v", got.TargetPrice)
+		}
+	})
+
 	t.Run("returns 400 on missing security_id", func(t *testing.T) {
-		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockAuditService{})
+		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "POST", "/investments",
+			`{"account_id":"00000000-0000-7000-8000-000000000001","quantity":10,"purchase_price":15000}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("passes symbol name and asset type to service", func(t *testing.T) {
+		var got services.SecurityRef
+		svc := &mockInvestmentService{
+			addInvestmentFn: func(_ models.UserID, _ models.AccountID, security services.SecurityRef, _ float64, _ models.Cents, _ string, _ *time.Time, _ models.Cents, _ string, _ models.AccountID, _ services.InvestmentMetadata, _ bool) (*models.Investment, bool, error) {
+				got = security
+				return &models.Investment{Base: models.Base{ID: testID(1)}, SecurityID: testID(5), PricePending: true}, false, nil
+			},
+		}
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "POST", "/investments",
+			`{"account_id":"00000000-0000-7000-8000-000000000001","symbol":"VWRA","name":"Vanguard FTSE All-World","asset_type":"etf","exchange":"LSE","quantity":2,"purchase_price_decimal":"120.50"}`)
+
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if got.ID != "" || got.Symbol != "VWRA" || got.Name != "Vanguard FTSE All-World" || got.AssetType != models.AssetTypeETF || got.Exchange != "LSE" {
+			t.Errorf("unexpected security ref %+v", got)
+		}
+		inv := parseJSON(t, rec)["investment"].(map[string]interface{})
+		if inv["price_pending"] != true {
+			t.Errorf("expected price_pending=true, got %v", inv["price_pending"])
+		}
+	})
+
+	t.Run("returns 400 with both security_id and symbol", func(t *testing.T) {
+		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "POST", "/investments",
+			`{"account_id":"00000000-0000-7000-8000-000000000001","security_id":"00000000-0000-7000-8000-000000000002","symbol":"AAPL","name":"Apple","asset_type":"stock","quantity":1,"purchase_price":15000}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("returns 400 on symbol without name", func(t *testing.T) {
+		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
 		rec := doRequest(r, "POST", "/investments",
-			`{"account_id":1,"quantity":10,"purchase_price":15000}`)
+			`{"account_id":"00000000-0000-7000-8000-000000000001","symbol":"AAPL","asset_type":"stock","quantity":1,"purchase_price":15000}`)
 
 		if rec.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d", rec.Code)
@@ -162,11 +327,11 @@ func TestInvestmentHandler_AddInvestment(t *testing.T) {
 	})
 
 	t.Run("returns 400 on zero quantity", func(t *testing.T) {
-		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockAuditService{})
+		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
 		rec := doRequest(r, "POST", "/investments",
-			`{"account_id":1,"security_id":1,"quantity":0,"purchase_price":15000}`)
+			`{"account_id":"00000000-0000-7000-8000-000000000001","security_id":"00000000-0000-7000-8000-000000000001","quantity":0,"purchase_price":15000}`)
 
 		if rec.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d", rec.Code)
@@ -175,15 +340,15 @@ func TestInvestmentHandler_AddInvestment(t *testing.T) {
 
 	t.Run("returns 404 on invalid account", func(t *testing.T) {
 		svc := &mockInvestmentService{
-			addInvestmentFn: func(_, _, _ uint, _ float64, _ int64, _ string, _ *time.Time, _ int64, _ string) (*models.Investment, error) {
-				return nil, apperrors.ErrAccountNotFound
+			addInvestmentFn: func(_ models.UserID, _ models.AccountID, _ services.SecurityRef, _ float64, _ models.Cents, _ string, _ *time.Time, _ models.Cents, _ string, _ models.AccountID, _ services.InvestmentMetadata, _ bool) (*models.Investment, bool, error) {
+				return nil, false, apperrors.ErrAccountNotFound
 			},
 		}
-		handler := NewInvestmentHandler(svc, &mockAuditService{})
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
 		rec := doRequest(r, "POST", "/investments",
-			`{"account_id":999,"security_id":1,"quantity":10,"purchase_price":15000}`)
+			`{"account_id":"00000000-0000-7000-8000-000000000999","security_id":"00000000-0000-7000-8000-000000000001","quantity":10,"purchase_price":15000}`)
 
 		if rec.Code != http.StatusNotFound {
 			t.Fatalf("expected 404, got %d", rec.Code)
@@ -192,12 +357,12 @@ func TestInvestmentHandler_AddInvestment(t *testing.T) {
 	})
 
 	t.Run("returns 401 without auth", func(t *testing.T) {
-		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockAuditService{})
+		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockSecurityService{}, &mockAuditService{})
 		r := gin.New()
 		r.POST("/investments", handler.AddInvestment)
 
 		rec := doRequest(r, "POST", "/investments",
-			`{"account_id":1,"security_id":1,"quantity":10,"purchase_price":15000}`)
+			`{"account_id":"00000000-0000-7000-8000-000000000001","security_id":"00000000-0000-7000-8000-000000000001","quantity":10,"purchase_price":15000}`)
 
 		if rec.Code != http.StatusUnauthorized {
 			t.Fatalf("expected 401, got %d", rec.Code)
@@ -209,23 +374,23 @@ func TestInvestmentHandler_AddInvestment(t *testing.T) {
 		var capturedFee int64
 		var capturedNotes string
 		svc := &mockInvestmentService{
-			addInvestmentFn: func(_ uint, accountID, securityID uint, quantity float64, _ int64, _ string, date *time.Time, fee int64, notes string) (*models.Investment, error) {
+			addInvestmentFn: func(_ models.UserID, accountID models.AccountID, security services.SecurityRef, quantity float64, _ models.Cents, _ string, date *time.Time, fee models.Cents, notes string, _ models.AccountID, _ services.InvestmentMetadata, _ bool) (*models.Investment, bool, error) {
 				capturedDate = date
-				capturedFee = fee
+				capturedFee = int64(fee)
 				capturedNotes = notes
 				return &models.Investment{
-					Base:       models.Base{ID: 1},
-					AccountID:  accountID,
-					SecurityID: securityID,
+					Base:       models.Base{ID: testID(1)},
+					AccountID:  string(accountID),
+					SecurityID: security.ID,
 					Quantity:   quantity,
-				}, nil
+				}, false, nil
 			},
 		}
-		handler := NewInvestmentHandler(svc, &mockAuditService{})
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
 		rec := doRequest(r, "POST", "/investments",
-			`{"account_id":1,"security_id":1,"quantity":10,"purchase_price":15000,"date":"2025-06-15T00:00:00Z","fee":999,"notes":"Backdated purchase"}`)
+			`{"account_id":"00000000-0000-7000-8000-000000000001","security_id":"00000000-0000-7000-8000-000000000001","quantity":10,"purchase_price":15000,"date":"2025-06-15T00:00:00Z","fee":999,"notes":"Backdated purchase"}`)
 
 		if rec.Code != http.StatusCreated {
 			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
@@ -250,18 +415,18 @@ func TestInvestmentHandler_AddInvestment(t *testing.T) {
 		var capturedFee int64
 		var capturedNotes string
 		svc := &mockInvestmentService{
-			addInvestmentFn: func(_ uint, _, _ uint, _ float64, _ int64, _ string, date *time.Time, fee int64, notes string) (*models.Investment, error) {
+			addInvestmentFn: func(_ models.UserID, _ models.AccountID, _ services.SecurityRef, _ float64, _ models.Cents, _ string, date *time.Time, fee models.Cents, notes string, _ models.AccountID, _ services.InvestmentMetadata, _ bool) (*models.Investment, bool, error) {
 				capturedDate = date
-				capturedFee = fee
+				capturedFee = int64(fee)
 				capturedNotes = notes
-				return &models.Investment{Base: models.Base{ID: 1}}, nil
+				return &models.Investment{Base: models.Base{ID: testID(1)}}, false, nil
 			},
 		}
-		handler := NewInvestmentHandler(svc, &mockAuditService{})
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
 		rec := doRequest(r, "POST", "/investments",
-			`{"account_id":1,"security_id":1,"quantity":10,"purchase_price":15000}`)
+			`{"account_id":"00000000-0000-7000-8000-000000000001","security_id":"00000000-0000-7000-8000-000000000001","quantity":10,"purchase_price":15000}`)
 
 		if rec.Code != http.StatusCreated {
 			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
@@ -276,44 +441,96 @@ func TestInvestmentHandler_AddInvestment(t *testing.T) {
 			t.Errorf("expected notes empty, got %q", capturedNotes)
 		}
 	})
+
+	t.Run("returns 200 when merged into an existing holding", func(t *testing.T) {
+		var gotForceNew bool
+		svc := &mockInvestmentService{
+			addInvestmentFn: func(_ models.UserID, _ models.AccountID, _ services.SecurityRef, _ float64, _ models.Cents, _ string, _ *time.Time, _ models.Cents, _ string, _ models.AccountID, _ services.InvestmentMetadata, forceNew bool) (*models.Investment, bool, error) {
+				gotForceNew = forceNew
+				return &models.Investment{Base: models.Base{ID: testID(1)}, Quantity: 15}, true, nil
+			},
+		}
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "POST", "/investments",
+			`{"account_id":"00000000-0000-7000-8000-000000000001","security_id":"00000000-0000-7000-8000-000000000001","quantity":5,"purchase_price":15000}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotForceNew {
+			t.Error("expected force_new to default to false")
+		}
+		result := parseJSON(t, rec)
+		if result["merged"] != true {
+			t.Errorf("expected merged=true, got %v", result["merged"])
+		}
+	})
+
+	t.Run("passes force_new to service", func(t *testing.T) {
+		var gotForceNew bool
+		svc := &mockInvestmentService{
+			addInvestmentFn: func(_ models.UserID, _ models.AccountID, _ services.SecurityRef, _ float64, _ models.Cents, _ string, _ *time.Time, _ models.Cents, _ string, _ models.AccountID, _ services.InvestmentMetadata, forceNew bool) (*models.Investment, bool, error) {
+				gotForceNew = forceNew
+				return &models.Investment{Base: models.Base{ID: testID(1)}}, false, nil
+			},
+		}
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "POST", "/investments",
+			`{"account_id":"00000000-0000-7000-8000-000000000001","security_id":"00000000-0000-7000-8000-000000000001","quantity":5,"purchase_price":15000,"force_new":true}`)
+
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if !gotForceNew {
+			t.Error("expected force_new=true to be passed to service")
+		}
+		result := parseJSON(t, rec)
+		if result["merged"] != false {
+			t.Errorf("expected merged=false, got %v", result["merged"])
+		}
+	})
 }
 
 func TestInvestmentHandler_GetInvestment(t *testing.T) {
 	t.Run("returns 200 on success", func(t *testing.T) {
 		svc := &mockInvestmentService{
-			getInvestmentByIDFn: func(_, investmentID uint) (*models.Investment, error) {
+			getInvestmentByIDFn: func(_ models.UserID, investmentID models.InvestmentID) (*models.Investment, error) {
 				return &models.Investment{
-					Base:       models.Base{ID: investmentID},
-					SecurityID: 1,
+					Base:       models.Base{ID: string(investmentID)},
+					SecurityID: testID(1),
 					Quantity:   10,
 				}, nil
 			},
 		}
-		handler := NewInvestmentHandler(svc, &mockAuditService{})
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
-		rec := doRequest(r, "GET", "/investments/1", "")
+		rec := doRequest(r, "GET", "/investments/00000000-0000-7000-8000-000000000001", "")
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d", rec.Code)
 		}
 		result := parseJSON(t, rec)
 		inv := result["investment"].(map[string]interface{})
-		if inv["security_id"].(float64) != 1 {
+		if inv["security_id"] != testID(1) {
 			t.Errorf("expected security_id=1, got %v", inv["security_id"])
 		}
 	})
 
 	t.Run("returns 404 when not found", func(t *testing.T) {
 		svc := &mockInvestmentService{
-			getInvestmentByIDFn: func(_, _ uint) (*models.Investment, error) {
+			getInvestmentByIDFn: func(_ models.UserID, _ models.InvestmentID) (*models.Investment, error) {
 				return nil, apperrors.ErrInvestmentNotFound
 			},
 		}
-		handler := NewInvestmentHandler(svc, &mockAuditService{})
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
-		rec := doRequest(r, "GET", "/investments/999", "")
+		rec := doRequest(r, "GET", "/investments/00000000-0000-7000-8000-000000000999", "")
 
 		if rec.Code != http.StatusNotFound {
 			t.Fatalf("expected 404, got %d", rec.Code)
@@ -322,7 +539,7 @@ func TestInvestmentHandler_GetInvestment(t *testing.T) {
 	})
 
 	t.Run("returns 400 on invalid ID", func(t *testing.T) {
-		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockAuditService{})
+		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
 		rec := doRequest(r, "GET", "/investments/abc", "")
@@ -333,10 +550,94 @@ func TestInvestmentHandler_GetInvestment(t *testing.T) {
 	})
 }
 
+func TestInvestmentHandler_UpdateInvestment(t *testing.T) {
+	t.Run("passes_notes_and_target_to_service", func(t *testing.T) {
+		var got services.InvestmentUpdateFields
+		svc := &mockInvestmentService{
+			updateInvestmentFn: func(_ models.UserID, investmentID models.InvestmentID, updates services.InvestmentUpdateFields) (*models.Investment, error) {
+				got = updates
+				target := int64(20000)
+				return &models.Investment{Base: models.Base{ID: string(investmentID)}, Notes: *updates.Notes, TargetPrice: &target, AtTarget: true}, nil
+			},
+		}
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "PUT", "/investments/00000000-0000-7000-8000-000000000001",
+			`{"notes":"Trim above target","target_price":20000}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if got.Notes == nil || *got.Notes != "Trim above target" {
+			t.Errorf("expected notes to be passed, got %v", got.Notes)
+		}
+		if got.TargetPrice == nil || *got.TargetPrice == nil || **got.TargetPrice != 20000 {
+			t.Errorf("expected target price 20000 to be passed, got %v", got.TargetPrice)
+		}
+		inv := parseJSON(t, rec)["investment"].(map[string]interface{})
+		if inv["at_target"] != true {
+			t.Errorf("expected at_target=true in response, got %v", inv["at_target"])
+		}
+	})
+
+	t.Run("zero_target_clears_it", func(t *testing.T) {
+		var got services.InvestmentUpdateFields
+		svc := &mockInvestmentService{
+			updateInvestmentFn: func(_ models.UserID, _ models.InvestmentID, updates services.InvestmentUpdateFields) (*models.Investment, error) {
+				got = updates
+				return &models.Investment{}, nil
+			},
+		}
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "PUT", "/investments/00000000-0000-7000-8000-000000000001", `{"target_price":0}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if got.TargetPrice == nil || *got.TargetPrice != nil {
+			t.Errorf("expected target price to be cleared, got %v", got.TargetPrice)
+		}
+		if got.Notes != nil {
+			t.Errorf("expected notes untouched, got %q", *got.Notes)
+		}
+	})
+
+	t.Run("returns_400_on_negative_target", func(t *testing.T) {
+		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "PUT", "/investments/00000000-0000-7000-8000-000000000001", `{"target_price":-100}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("returns_404_when_not_found", func(t *testing.T) {
+		svc := &mockInvestmentService{
+			updateInvestmentFn: func(_ models.UserID, _ models.InvestmentID, _ services.InvestmentUpdateFields) (*models.Investment, error) {
+				return nil, apperrors.ErrInvestmentNotFound
+			},
+		}
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "PUT", "/investments/00000000-0000-7000-8000-000000000999", `{"notes":"x"}`)
+
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rec.Code)
+		}
+	})
+}
+
 func TestInvestmentHandler_GetPortfolio(t *testing.T) {
 	t.Run("returns 200 with portfolio summary", func(t *testing.T) {
 		svc := &mockInvestmentService{
-			getPortfolioFn: func(_ uint) (*services.PortfolioSummary, error) {
+			getPortfolioFn: func(_ models.UserID) (*services.PortfolioSummary, error) {
 				return &services.PortfolioSummary{
 					TotalValue:     500000,
 					TotalCostBasis: 400000,
@@ -349,7 +650,7 @@ func TestInvestmentHandler_GetPortfolio(t *testing.T) {
 				}, nil
 			},
 		}
-		handler := NewInvestmentHandler(svc, &mockAuditService{})
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
 		rec := doRequest(r, "GET", "/investments/portfolio", "")
@@ -367,8 +668,76 @@ func TestInvestmentHandler_GetPortfolio(t *testing.T) {
 		}
 	})
 
+	t.Run("embeds sparklines with include=sparklines", func(t *testing.T) {
+		change := 5.0
+		sparklinesRequested := false
+		svc := &mockInvestmentService{
+			getPortfolioFn: func(_ models.UserID) (*services.PortfolioSummary, error) {
+				return &services.PortfolioSummary{Holdings: []services.PortfolioHolding{{SecurityID: "sec-1"}, {SecurityID: "sec-2"}}}, nil
+			},
+			getHoldingSparklinesFn: func(holdings []services.PortfolioHolding) (map[string]*services.Sparkline, error) {
+				sparklinesRequested = len(holdings) == 2
+				return map[string]*services.Sparkline{"sec-1": {
+					Closes:    []services.SparklineClose{{Date: "2026-10-14", Price: 10000}, {Date: "2026-10-15", Price: 10500}},
+					ChangePct: &change,
+				}}, nil
+			},
+		}
+		r := setupInvestmentRouter(NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{}))
+
+		rec := doRequest(r, "GET", "/investments/portfolio?include=sparklines", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if !sparklinesRequested {
+			t.Error("expected sparklines for both holdings")
+		}
+		holdings := parseJSON(t, rec)["portfolio"].(map[string]interface{})["holdings"].([]interface{})
+		sparkline, ok := holdings[0].(map[string]interface{})["sparkline"].(map[string]interface{})
+		if !ok || sparkline["change_pct"] != 5.0 || len(sparkline["closes"].([]interface{})) != 2 {
+			t.Errorf("expected the first holding's sparkline, got %v", holdings[0])
+		}
+		if _, ok := holdings[1].(map[string]interface{})["sparkline"]; ok {
+			t.Error("expected no sparkline for a security without prices")
+		}
+	})
+
+	t.Run("omits sparklines by default", func(t *testing.T) {
+		svc := &mockInvestmentService{
+			getPortfolioFn: func(_ models.UserID) (*services.PortfolioSummary, error) {
+				return &services.PortfolioSummary{Holdings: []services.PortfolioHolding{{SecurityID: "sec-1"}}}, nil
+			},
+			getHoldingSparklinesFn: func(_ []services.PortfolioHolding) (map[string]*services.Sparkline, error) {
+				t.Error("expected sparklines not to be loaded")
+				return nil, nil
+			},
+		}
+		r := setupInvestmentRouter(NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{}))
+
+		rec := doRequest(r, "GET", "/investments/portfolio", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if strings.Contains(rec.Body.String(), "sparkline") {
+			t.Errorf("expected no sparkline field, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("rejects an unknown include", func(t *testing.T) {
+		r := setupInvestmentRouter(NewInvestmentHandler(&mockInvestmentService{}, &mockSecurityService{}, &mockAuditService{}))
+
+		rec := doRequest(r, "GET", "/investments/portfolio?include=history", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
 	t.Run("returns 401 without auth", func(t *testing.T) {
-		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockAuditService{})
+		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockSecurityService{}, &mockAuditService{})
 		r := gin.New()
 		r.GET("/investments/portfolio", handler.GetPortfolio)
 
@@ -381,24 +750,64 @@ func TestInvestmentHandler_GetPortfolio(t *testing.T) {
 }
 
 func TestInvestmentHandler_RecordBuy(t *testing.T) {
+	t.Run("passes funding account to service", func(t *testing.T) {
+		var gotFromAccountID string
+		svc := &mockInvestmentService{
+			recordBuyFn: func(_ models.UserID, _ models.InvestmentID, _ time.Time, _ float64, _, _ models.Cents, _ string, fromAccountID models.AccountID, _ bool) (*models.InvestmentTransaction, error) {
+				gotFromAccountID = string(fromAccountID)
+				return &models.InvestmentTransaction{Base: models.Base{ID: testID(1)}}, nil
+			},
+		}
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "POST", "/investments/00000000-0000-7000-8000-000000000001/buy",
+			`{"date":"2025-01-15T00:00:00Z","quantity":5,"price_per_unit":15000,"from_account_id":"00000000-0000-7000-8000-000000000002"}`)
+
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotFromAccountID != testID(2) {
+			t.Errorf("expected from_account_id %s, got %q", testID(2), gotFromAccountID)
+		}
+	})
+
+	t.Run("returns 400 on insufficient cash", func(t *testing.T) {
+		svc := &mockInvestmentService{
+			recordBuyFn: func(_ models.UserID, _ models.InvestmentID, _ time.Time, _ float64, _, _ models.Cents, _ string, _ models.AccountID, _ bool) (*models.InvestmentTransaction, error) {
+				return nil, apperrors.ErrInsufficientBalance
+			},
+		}
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "POST", "/investments/00000000-0000-7000-8000-000000000001/buy",
+			`{"date":"2025-01-15T00:00:00Z","quantity":5,"price_per_unit":15000,"from_account_id":"00000000-0000-7000-8000-000000000002"}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INSUFFICIENT_BALANCE")
+	})
+
 	t.Run("returns 201 on success", func(t *testing.T) {
 		svc := &mockInvestmentService{
-			recordBuyFn: func(_, investmentID uint, _ time.Time, qty float64, price int64, fee int64, notes string) (*models.InvestmentTransaction, error) {
+			recordBuyFn: func(_ models.UserID, investmentID models.InvestmentID, _ time.Time, qty float64, price, fee models.Cents, notes string, _ models.AccountID, _ bool) (*models.InvestmentTransaction, error) {
 				return &models.InvestmentTransaction{
-					Base:         models.Base{ID: 1},
-					InvestmentID: investmentID,
+					Base:         models.Base{ID: testID(1)},
+					InvestmentID: string(investmentID),
 					Type:         models.InvestmentTransactionBuy,
 					Quantity:     qty,
-					PricePerUnit: price,
-					Fee:          fee,
+					PricePerUnit: int64(price),
+					Fee:          int64(fee),
 					Notes:        notes,
 				}, nil
 			},
 		}
-		handler := NewInvestmentHandler(svc, &mockAuditService{})
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
-		rec := doRequest(r, "POST", "/investments/1/buy",
+		rec := doRequest(r, "POST", "/investments/00000000-0000-7000-8000-000000000001/buy",
 			`{"date":"2025-01-15T00:00:00Z","quantity":5,"price_per_unit":15000,"fee":999,"notes":"Buy more"}`)
 
 		if rec.Code != http.StatusCreated {
@@ -414,11 +823,36 @@ func TestInvestmentHandler_RecordBuy(t *testing.T) {
 		}
 	})
 
+	t.Run("converts price_per_unit_decimal using the holding's currency", func(t *testing.T) {
+		var gotPrice int64
+		svc := &mockInvestmentService{
+			getInvestmentByIDFn: func(_ models.UserID, investmentID models.InvestmentID) (*models.Investment, error) {
+				return &models.Investment{Base: models.Base{ID: string(investmentID)}, Security: models.Security{Currency: "BHD"}}, nil
+			},
+			recordBuyFn: func(_ models.UserID, _ models.InvestmentID, _ time.Time, _ float64, price, _ models.Cents, _ string, _ models.AccountID, _ bool) (*models.InvestmentTransaction, error) {
+				gotPrice = int64(price)
+				return &models.InvestmentTransaction{Base: models.Base{ID: testID(1)}}, nil
+			},
+		}
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "POST", "/investments/00000000-0000-7000-8000-000000000001/buy",
+			`{"date":"2025-01-15T00:00:00Z","quantity":5,"price_per_unit_decimal":"3.5"}`)
+
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotPrice != 3500 {
+			t.Errorf("expected price per unit 3500, got %d", gotPrice)
+		}
+	})
+
 	t.Run("returns 400 on missing date", func(t *testing.T) {
-		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockAuditService{})
+		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
-		rec := doRequest(r, "POST", "/investments/1/buy",
+		rec := doRequest(r, "POST", "/investments/00000000-0000-7000-8000-000000000001/buy",
 			`{"quantity":5,"price_per_unit":15000}`)
 
 		if rec.Code != http.StatusBadRequest {
@@ -427,10 +861,10 @@ func TestInvestmentHandler_RecordBuy(t *testing.T) {
 	})
 
 	t.Run("returns 400 on zero quantity", func(t *testing.T) {
-		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockAuditService{})
+		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
-		rec := doRequest(r, "POST", "/investments/1/buy",
+		rec := doRequest(r, "POST", "/investments/00000000-0000-7000-8000-000000000001/buy",
 			`{"date":"2025-01-15T00:00:00Z","quantity":0,"price_per_unit":15000}`)
 
 		if rec.Code != http.StatusBadRequest {
@@ -440,14 +874,14 @@ func TestInvestmentHandler_RecordBuy(t *testing.T) {
 
 	t.Run("returns 404 when investment not found", func(t *testing.T) {
 		svc := &mockInvestmentService{
-			recordBuyFn: func(_, _ uint, _ time.Time, _ float64, _ int64, _ int64, _ string) (*models.InvestmentTransaction, error) {
+			recordBuyFn: func(_ models.UserID, _ models.InvestmentID, _ time.Time, _ float64, _, _ models.Cents, _ string, _ models.AccountID, _ bool) (*models.InvestmentTransaction, error) {
 				return nil, apperrors.ErrInvestmentNotFound
 			},
 		}
-		handler := NewInvestmentHandler(svc, &mockAuditService{})
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
-		rec := doRequest(r, "POST", "/investments/999/buy",
+		rec := doRequest(r, "POST", "/investments/00000000-0000-7000-8000-000000000999/buy",
 			`{"date":"2025-01-15T00:00:00Z","quantity":5,"price_per_unit":15000}`)
 
 		if rec.Code != http.StatusNotFound {
@@ -459,20 +893,20 @@ func TestInvestmentHandler_RecordBuy(t *testing.T) {
 func TestInvestmentHandler_RecordSell(t *testing.T) {
 	t.Run("returns 201 on success", func(t *testing.T) {
 		svc := &mockInvestmentService{
-			recordSellFn: func(_, investmentID uint, _ time.Time, qty float64, price int64, _ int64, _ string) (*models.InvestmentTransaction, error) {
+			recordSellFn: func(_ models.UserID, investmentID models.InvestmentID, _ time.Time, qty float64, price, _ models.Cents, _ string, _ bool) (*models.InvestmentTransaction, error) {
 				return &models.InvestmentTransaction{
-					Base:         models.Base{ID: 2},
-					InvestmentID: investmentID,
+					Base:         models.Base{ID: testID(2)},
+					InvestmentID: string(investmentID),
 					Type:         models.InvestmentTransactionSell,
 					Quantity:     qty,
-					PricePerUnit: price,
+					PricePerUnit: int64(price),
 				}, nil
 			},
 		}
-		handler := NewInvestmentHandler(svc, &mockAuditService{})
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
-		rec := doRequest(r, "POST", "/investments/1/sell",
+		rec := doRequest(r, "POST", "/investments/00000000-0000-7000-8000-000000000001/sell",
 			`{"date":"2025-02-01T00:00:00Z","quantity":3,"price_per_unit":17500}`)
 
 		if rec.Code != http.StatusCreated {
@@ -487,14 +921,14 @@ func TestInvestmentHandler_RecordSell(t *testing.T) {
 
 	t.Run("returns 400 on insufficient shares", func(t *testing.T) {
 		svc := &mockInvestmentService{
-			recordSellFn: func(_, _ uint, _ time.Time, _ float64, _ int64, _ int64, _ string) (*models.InvestmentTransaction, error) {
+			recordSellFn: func(_ models.UserID, _ models.InvestmentID, _ time.Time, _ float64, _, _ models.Cents, _ string, _ bool) (*models.InvestmentTransaction, error) {
 				return nil, apperrors.ErrInsufficientShares
 			},
 		}
-		handler := NewInvestmentHandler(svc, &mockAuditService{})
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
-		rec := doRequest(r, "POST", "/investments/1/sell",
+		rec := doRequest(r, "POST", "/investments/00000000-0000-7000-8000-000000000001/sell",
 			`{"date":"2025-02-01T00:00:00Z","quantity":100,"price_per_unit":17500}`)
 
 		if rec.Code != http.StatusBadRequest {
@@ -502,25 +936,56 @@ func TestInvestmentHandler_RecordSell(t *testing.T) {
 		}
 		assertErrorCode(t, parseJSON(t, rec), "INSUFFICIENT_SHARES")
 	})
+
+	t.Run("passes_confirm_price_and_returns_deviation_warning", func(t *testing.T) {
+		var confirmed []bool
+		svc := &mockInvestmentService{
+			recordSellFn: func(_ models.UserID, _ models.InvestmentID, _ time.Time, _ float64, _, _ models.Cents, _ string, confirmPrice bool) (*models.InvestmentTransaction, error) {
+				confirmed = append(confirmed, confirmPrice)
+				if !confirmPrice {
+					return nil, apperrors.ErrPriceDeviationWarning
+				}
+				return &models.InvestmentTransaction{Base: models.Base{ID: testID(2)}, Type: models.InvestmentTransactionSell}, nil
+			},
+		}
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "POST", "/investments/00000000-0000-7000-8000-000000000001/sell",
+			`{"date":"2025-02-01T00:00:00Z","quantity":3,"price_per_unit":1750000}`)
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("expected 422, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "PRICE_DEVIATION_WARNING")
+
+		rec = doRequest(r, "POST", "/investments/00000000-0000-7000-8000-000000000001/sell",
+			`{"date":"2025-02-01T00:00:00Z","quantity":3,"price_per_unit":1750000,"confirm_price":true}`)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected 201 once confirmed, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if len(confirmed) != 2 || confirmed[0] || !confirmed[1] {
+			t.Errorf("expected confirm_price false then true, got %v", confirmed)
+		}
+	})
 }
 
 func TestInvestmentHandler_RecordDividend(t *testing.T) {
 	t.Run("returns 201 on success", func(t *testing.T) {
 		svc := &mockInvestmentService{
-			recordDividendFn: func(_, investmentID uint, _ time.Time, amount int64, divType, _ string) (*models.InvestmentTransaction, error) {
+			recordDividendFn: func(_ models.UserID, investmentID models.InvestmentID, _ time.Time, amount models.Cents, divType, _, _ string) (*models.InvestmentTransaction, error) {
 				return &models.InvestmentTransaction{
-					Base:         models.Base{ID: 3},
-					InvestmentID: investmentID,
+					Base:         models.Base{ID: testID(3)},
+					InvestmentID: string(investmentID),
 					Type:         models.InvestmentTransactionDividend,
-					TotalAmount:  amount,
+					TotalAmount:  int64(amount),
 					DividendType: divType,
 				}, nil
 			},
 		}
-		handler := NewInvestmentHandler(svc, &mockAuditService{})
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
-		rec := doRequest(r, "POST", "/investments/1/dividend",
+		rec := doRequest(r, "POST", "/investments/00000000-0000-7000-8000-000000000001/dividend",
 			`{"date":"2025-03-15T00:00:00Z","amount":500,"dividend_type":"Cash"}`)
 
 		if rec.Code != http.StatusCreated {
@@ -536,11 +1001,61 @@ func TestInvestmentHandler_RecordDividend(t *testing.T) {
 		}
 	})
 
+	t.Run("normalizes the dividend type", func(t *testing.T) {
+		var got string
+		svc := &mockInvestmentService{
+			recordDividendFn: func(_ models.UserID, _ models.InvestmentID, _ time.Time, _ models.Cents, divType, _, _ string) (*models.InvestmentTransaction, error) {
+				got = divType
+				return &models.InvestmentTransaction{}, nil
+			},
+		}
+		r := setupInvestmentRouter(NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{}))
+
+		for body, want := range map[string]string{
+			`"dividend_type":" Return_Of_Capital "`: models.DividendReturnOfCapital,
+			`"dividend_type":"Cash"`:                models.DividendCash,
+			`"notes":"no type"`:                     "",
+		} {
+			got = "unset"
+			rec := doRequest(r, "POST", "/investments/00000000-0000-7000-8000-000000000001/dividend",
+				`{"date":"2025-03-15T00:00:00Z","amount":500,`+body+`}`)
+
+			if rec.Code != http.StatusCreated {
+				t.Fatalf("%s: expected 201, got %d: %s", body, rec.Code, rec.Body.String())
+			}
+			if got != want {
+				t.Errorf("%s: expected dividend type %q, got %q", body, want, got)
+			}
+		}
+	})
+
+	t.Run("returns 400 on an unknown dividend type", func(t *testing.T) {
+		called := false
+		svc := &mockInvestmentService{
+			recordDividendFn: func(_ models.UserID, _ models.InvestmentID, _ time.Time, _ models.Cents, _, _, _ string) (*models.InvestmentTransaction, error) {
+				called = true
+				return &models.InvestmentTransaction{}, nil
+			},
+		}
+		r := setupInvestmentRouter(NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{}))
+
+		rec := doRequest(r, "POST", "/investments/00000000-0000-7000-8000-000000000001/dividend",
+			`{"date":"2025-03-15T00:00:00Z","amount":500,"dividend_type":"return-of-capital"}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+		if called {
+			t.Error("expected the dividend not to be recorded")
+		}
+	})
+
 	t.Run("returns 400 on zero amount", func(t *testing.T) {
-		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockAuditService{})
+		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
-		rec := doRequest(r, "POST", "/investments/1/dividend",
+		rec := doRequest(r, "POST", "/investments/00000000-0000-7000-8000-000000000001/dividend",
 			`{"date":"2025-03-15T00:00:00Z","amount":0}`)
 
 		if rec.Code != http.StatusBadRequest {
@@ -550,14 +1065,14 @@ func TestInvestmentHandler_RecordDividend(t *testing.T) {
 
 	t.Run("returns 404 when not found", func(t *testing.T) {
 		svc := &mockInvestmentService{
-			recordDividendFn: func(_, _ uint, _ time.Time, _ int64, _, _ string) (*models.InvestmentTransaction, error) {
+			recordDividendFn: func(_ models.UserID, _ models.InvestmentID, _ time.Time, _ models.Cents, _, _, _ string) (*models.InvestmentTransaction, error) {
 				return nil, apperrors.ErrInvestmentNotFound
 			},
 		}
-		handler := NewInvestmentHandler(svc, &mockAuditService{})
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
-		rec := doRequest(r, "POST", "/investments/999/dividend",
+		rec := doRequest(r, "POST", "/investments/00000000-0000-7000-8000-000000000999/dividend",
 			`{"date":"2025-03-15T00:00:00Z","amount":500}`)
 
 		if rec.Code != http.StatusNotFound {
@@ -569,19 +1084,19 @@ func TestInvestmentHandler_RecordDividend(t *testing.T) {
 func TestInvestmentHandler_RecordSplit(t *testing.T) {
 	t.Run("returns 201 on success", func(t *testing.T) {
 		svc := &mockInvestmentService{
-			recordSplitFn: func(_, investmentID uint, _ time.Time, ratio float64, _ string) (*models.InvestmentTransaction, error) {
+			recordSplitFn: func(_ models.UserID, investmentID models.InvestmentID, _ time.Time, ratio float64, _ services.SplitOptions, _, _ string) (*models.InvestmentTransaction, error) {
 				return &models.InvestmentTransaction{
-					Base:         models.Base{ID: 4},
-					InvestmentID: investmentID,
+					Base:         models.Base{ID: testID(4)},
+					InvestmentID: string(investmentID),
 					Type:         models.InvestmentTransactionSplit,
 					SplitRatio:   ratio,
 				}, nil
 			},
 		}
-		handler := NewInvestmentHandler(svc, &mockAuditService{})
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
-		rec := doRequest(r, "POST", "/investments/1/split",
+		rec := doRequest(r, "POST", "/investments/00000000-0000-7000-8000-000000000001/split",
 			`{"date":"2025-06-01T00:00:00Z","split_ratio":2.0,"notes":"2:1 split"}`)
 
 		if rec.Code != http.StatusCreated {
@@ -597,11 +1112,67 @@ func TestInvestmentHandler_RecordSplit(t *testing.T) {
 		}
 	})
 
+	t.Run("passes external_ref to service", func(t *testing.T) {
+		var captured string
+		svc := &mockInvestmentService{
+			recordSplitFn: func(_ models.UserID, investmentID models.InvestmentID, _ time.Time, _ float64, _ services.SplitOptions, _, externalRef string) (*models.InvestmentTransaction, error) {
+				captured = externalRef
+				return &models.InvestmentTransaction{InvestmentID: string(investmentID), Type: models.InvestmentTransactionSplit}, nil
+			},
+		}
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "POST", "/investments/00000000-0000-7000-8000-000000000001/split",
+			`{"date":"2025-06-01T00:00:00Z","split_ratio":2.0,"external_ref":"corp-action-42"}`)
+
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if captured != "corp-action-42" {
+			t.Errorf("expected external_ref corp-action-42, got %q", captured)
+		}
+	})
+
+	t.Run("passes rounding options to service", func(t *testing.T) {
+		var captured services.SplitOptions
+		svc := &mockInvestmentService{
+			recordSplitFn: func(_ models.UserID, investmentID models.InvestmentID, _ time.Time, _ float64, options services.SplitOptions, _, _ string) (*models.InvestmentTransaction, error) {
+				captured = options
+				return &models.InvestmentTransaction{InvestmentID: string(investmentID), Type: models.InvestmentTransactionSplit}, nil
+			},
+		}
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "POST", "/investments/00000000-0000-7000-8000-000000000001/split",
+			`{"date":"2025-06-01T00:00:00Z","split_ratio":0.1,"rounding":"down","cash_in_lieu_price":25000}`)
+
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if captured.Rounding != services.SplitRoundingDown || captured.CashInLieuPrice != 25000 {
+			t.Errorf("expected rounding down with cash in lieu 25000, got %+v", captured)
+		}
+	})
+
+	t.Run("returns 400 on unknown rounding", func(t *testing.T) {
+		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "POST", "/investments/00000000-0000-7000-8000-000000000001/split",
+			`{"date":"2025-06-01T00:00:00Z","split_ratio":0.5,"rounding":"up"}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+	})
+
 	t.Run("returns 400 on zero split ratio", func(t *testing.T) {
-		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockAuditService{})
+		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
-		rec := doRequest(r, "POST", "/investments/1/split",
+		rec := doRequest(r, "POST", "/investments/00000000-0000-7000-8000-000000000001/split",
 			`{"date":"2025-06-01T00:00:00Z","split_ratio":0}`)
 
 		if rec.Code != http.StatusBadRequest {
@@ -611,14 +1182,14 @@ func TestInvestmentHandler_RecordSplit(t *testing.T) {
 
 	t.Run("returns 404 when not found", func(t *testing.T) {
 		svc := &mockInvestmentService{
-			recordSplitFn: func(_, _ uint, _ time.Time, _ float64, _ string) (*models.InvestmentTransaction, error) {
+			recordSplitFn: func(_ models.UserID, _ models.InvestmentID, _ time.Time, _ float64, _ services.SplitOptions, _, _ string) (*models.InvestmentTransaction, error) {
 				return nil, apperrors.ErrInvestmentNotFound
 			},
 		}
-		handler := NewInvestmentHandler(svc, &mockAuditService{})
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
-		rec := doRequest(r, "POST", "/investments/999/split",
+		rec := doRequest(r, "POST", "/investments/00000000-0000-7000-8000-000000000999/split",
 			`{"date":"2025-06-01T00:00:00Z","split_ratio":2.0}`)
 
 		if rec.Code != http.StatusNotFound {
@@ -630,18 +1201,18 @@ func TestInvestmentHandler_RecordSplit(t *testing.T) {
 func TestInvestmentHandler_GetAccountInvestments(t *testing.T) {
 	t.Run("returns 200 with paginated investments", func(t *testing.T) {
 		svc := &mockInvestmentService{
-			getAccountInvestmentsFn: func(_, _ uint, _ pagination.PageRequest) (*pagination.PageResponse[models.Investment], error) {
+			getAccountInvestmentsFn: func(_ models.UserID, _ models.AccountID, _ pagination.PageRequest) (*pagination.PageResponse[models.Investment], error) {
 				resp := pagination.NewPageResponse([]models.Investment{
-					{Base: models.Base{ID: 1}, SecurityID: 1},
-					{Base: models.Base{ID: 2}, SecurityID: 2},
+					{Base: models.Base{ID: testID(1)}, SecurityID: testID(1)},
+					{Base: models.Base{ID: testID(2)}, SecurityID: testID(2)},
 				}, 1, 20, 2)
 				return &resp, nil
 			},
 		}
-		handler := NewInvestmentHandler(svc, &mockAuditService{})
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
-		rec := doRequest(r, "GET", "/accounts/1/investments", "")
+		rec := doRequest(r, "GET", "/accounts/00000000-0000-7000-8000-000000000001/investments", "")
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
@@ -658,14 +1229,14 @@ func TestInvestmentHandler_GetAccountInvestments(t *testing.T) {
 
 	t.Run("returns 404 on invalid account", func(t *testing.T) {
 		svc := &mockInvestmentService{
-			getAccountInvestmentsFn: func(_, _ uint, _ pagination.PageRequest) (*pagination.PageResponse[models.Investment], error) {
+			getAccountInvestmentsFn: func(_ models.UserID, _ models.AccountID, _ pagination.PageRequest) (*pagination.PageResponse[models.Investment], error) {
 				return nil, apperrors.ErrAccountNotFound
 			},
 		}
-		handler := NewInvestmentHandler(svc, &mockAuditService{})
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
-		rec := doRequest(r, "GET", "/accounts/999/investments", "")
+		rec := doRequest(r, "GET", "/accounts/00000000-0000-7000-8000-000000000999/investments", "")
 
 		if rec.Code != http.StatusNotFound {
 			t.Fatalf("expected 404, got %d", rec.Code)
@@ -677,15 +1248,15 @@ func TestInvestmentHandler_GetAccountInvestments(t *testing.T) {
 func TestInvestmentHandler_GetAllInvestments(t *testing.T) {
 	t.Run("returns_200_with_investments", func(t *testing.T) {
 		svc := &mockInvestmentService{
-			getAllInvestmentsFn: func(_ uint, _ pagination.PageRequest) (*pagination.PageResponse[models.Investment], error) {
+			getAllInvestmentsFn: func(_ models.UserID, _ pagination.PageRequest) (*pagination.PageResponse[models.Investment], error) {
 				resp := pagination.NewPageResponse([]models.Investment{
-					{Base: models.Base{ID: 1}, SecurityID: 1, Quantity: 10},
-					{Base: models.Base{ID: 2}, SecurityID: 2, Quantity: 5},
+					{Base: models.Base{ID: testID(1)}, SecurityID: testID(1), Quantity: 10},
+					{Base: models.Base{ID: testID(2)}, SecurityID: testID(2), Quantity: 5},
 				}, 1, 20, 2)
 				return &resp, nil
 			},
 		}
-		handler := NewInvestmentHandler(svc, &mockAuditService{})
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
 		rec := doRequest(r, "GET", "/investments", "")
@@ -705,12 +1276,12 @@ func TestInvestmentHandler_GetAllInvestments(t *testing.T) {
 
 	t.Run("returns_200_empty_list", func(t *testing.T) {
 		svc := &mockInvestmentService{
-			getAllInvestmentsFn: func(_ uint, _ pagination.PageRequest) (*pagination.PageResponse[models.Investment], error) {
+			getAllInvestmentsFn: func(_ models.UserID, _ pagination.PageRequest) (*pagination.PageResponse[models.Investment], error) {
 				resp := pagination.NewPageResponse([]models.Investment{}, 1, 20, 0)
 				return &resp, nil
 			},
 		}
-		handler := NewInvestmentHandler(svc, &mockAuditService{})
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
 		rec := doRequest(r, "GET", "/investments", "")
@@ -727,11 +1298,11 @@ func TestInvestmentHandler_GetAllInvestments(t *testing.T) {
 
 	t.Run("returns_500_on_service_error", func(t *testing.T) {
 		svc := &mockInvestmentService{
-			getAllInvestmentsFn: func(_ uint, _ pagination.PageRequest) (*pagination.PageResponse[models.Investment], error) {
+			getAllInvestmentsFn: func(_ models.UserID, _ pagination.PageRequest) (*pagination.PageResponse[models.Investment], error) {
 				return nil, apperrors.ErrInternalServer
 			},
 		}
-		handler := NewInvestmentHandler(svc, &mockAuditService{})
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
 		rec := doRequest(r, "GET", "/investments", "")
@@ -742,21 +1313,142 @@ func TestInvestmentHandler_GetAllInvestments(t *testing.T) {
 	})
 }
 
+func TestInvestmentHandler_ExportInvestments(t *testing.T) {
+	t.Run("returns_csv_with_computed_market_value", func(t *testing.T) {
+		svc := &mockInvestmentService{
+			getAllInvestmentsFn: func(_ models.UserID, page pagination.PageRequest) (*pagination.PageResponse[models.Investment], error) {
+				resp := pagination.NewPageResponse([]models.Investment{
+					{
+						Base:         models.Base{ID: testID(1)},
+						SecurityID:   testID(1),
+						Quantity:     10,
+						CostBasis:    150000,
+						CurrentPrice: 17550,
+						Security:     models.Security{Symbol: "AAPL", Name: "Apple Inc."},
+					},
+				}, page.Page, page.PageSize, 1)
+				return &resp, nil
+			},
+		}
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "GET", "/investments/export?format=csv", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if cd := rec.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, "attachment; filename=\"investments-") {
+			t.Errorf("unexpected Content-Disposition: %q", cd)
+		}
+		if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+			t.Errorf("unexpected Content-Type: %q", ct)
+		}
+
+		rows, err := csv.NewReader(rec.Body).ReadAll()
+		if err != nil {
+			t.Fatalf("failed to parse CSV: %v", err)
+		}
+		if len(rows) != 2 {
+			t.Fatalf("expected header + 1 row, got %d rows", len(rows))
+		}
+		want := []string{"AAPL", "Apple Inc.", "10", "150.00", "175.50", "1755.00", "1500.00", "255.00"}
+		for i, v := range want {
+			if rows[1][i] != v {
+				t.Errorf("column %s = %q, want %q", rows[0][i], rows[1][i], v)
+			}
+		}
+	})
+
+	t.Run("formats_amounts_with_security_currency_precision", func(t *testing.T) {
+		svc := &mockInvestmentService{
+			getAllInvestmentsFn: func(_ models.UserID, page pagination.PageRequest) (*pagination.PageResponse[models.Investment], error) {
+				resp := pagination.NewPageResponse([]models.Investment{
+					{Quantity: 100, CostBasis: 1850000, CurrentPrice: 19000, Security: models.Security{Symbol: "7203", Currency: "JPY"}},
+					{Quantity: 2, CostBasis: 10000000, CurrentPrice: 6000000, Security: models.Security{Symbol: "BTC", Currency: "BTC"}},
+				}, page.Page, page.PageSize, 2)
+				return &resp, nil
+			},
+		}
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "GET", "/investments/export", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		rows, err := csv.NewReader(rec.Body).ReadAll()
+		if err != nil {
+			t.Fatalf("failed to parse CSV: %v", err)
+		}
+		if len(rows) != 3 {
+			t.Fatalf("expected header + 2 rows, got %d rows", len(rows))
+		}
+		wantJPY := []string{"7203", "", "100", "18500", "19000", "1900000", "1850000", "50000"}
+		wantBTC := []string{"BTC", "", "2", "0.05000000", "0.06000000", "0.12000000", "0.10000000", "0.02000000"}
+		for i := range wantJPY {
+			if rows[1][i] != wantJPY[i] {
+				t.Errorf("JPY column %s = %q, want %q", rows[0][i], rows[1][i], wantJPY[i])
+			}
+			if rows[2][i] != wantBTC[i] {
+				t.Errorf("BTC column %s = %q, want %q", rows[0][i], rows[2][i], wantBTC[i])
+			}
+		}
+	})
+
+	t.Run("streams_all_pages", func(t *testing.T) {
+		var pagesRequested []int
+		svc := &mockInvestmentService{
+			getAllInvestmentsFn: func(_ models.UserID, page pagination.PageRequest) (*pagination.PageResponse[models.Investment], error) {
+				pagesRequested = append(pagesRequested, page.Page)
+				resp := pagination.NewPageResponse([]models.Investment{
+					{Quantity: 1, Security: models.Security{Symbol: "SEC"}},
+				}, page.Page, 1, 2)
+				return &resp, nil
+			},
+		}
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "GET", "/investments/export", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if len(pagesRequested) != 2 {
+			t.Errorf("expected 2 pages requested, got %v", pagesRequested)
+		}
+	})
+
+	t.Run("returns_400_on_unsupported_format", func(t *testing.T) {
+		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "GET", "/investments/export?format=xlsx", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+}
+
 func TestInvestmentHandler_GetInvestmentTransactions(t *testing.T) {
 	t.Run("returns 200 with paginated transactions", func(t *testing.T) {
 		svc := &mockInvestmentService{
-			getInvestmentTransactionsFn: func(_, _ uint, _ pagination.PageRequest) (*pagination.PageResponse[models.InvestmentTransaction], error) {
+			getInvestmentTransactionsFn: func(_ models.UserID, _ models.InvestmentID, _ pagination.PageRequest) (*pagination.PageResponse[models.InvestmentTransaction], error) {
 				resp := pagination.NewPageResponse([]models.InvestmentTransaction{
-					{Base: models.Base{ID: 1}, Type: models.InvestmentTransactionBuy},
-					{Base: models.Base{ID: 2}, Type: models.InvestmentTransactionDividend},
+					{Base: models.Base{ID: testID(1)}, Type: models.InvestmentTransactionBuy},
+					{Base: models.Base{ID: testID(2)}, Type: models.InvestmentTransactionDividend},
 				}, 1, 20, 2)
 				return &resp, nil
 			},
 		}
-		handler := NewInvestmentHandler(svc, &mockAuditService{})
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
-		rec := doRequest(r, "GET", "/investments/1/transactions", "")
+		rec := doRequest(r, "GET", "/investments/00000000-0000-7000-8000-000000000001/transactions", "")
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
@@ -770,17 +1462,78 @@ func TestInvestmentHandler_GetInvestmentTransactions(t *testing.T) {
 
 	t.Run("returns 404 when not found", func(t *testing.T) {
 		svc := &mockInvestmentService{
-			getInvestmentTransactionsFn: func(_, _ uint, _ pagination.PageRequest) (*pagination.PageResponse[models.InvestmentTransaction], error) {
+			getInvestmentTransactionsFn: func(_ models.UserID, _ models.InvestmentID, _ pagination.PageRequest) (*pagination.PageResponse[models.InvestmentTransaction], error) {
 				return nil, apperrors.ErrInvestmentNotFound
 			},
 		}
-		handler := NewInvestmentHandler(svc, &mockAuditService{})
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
 		r := setupInvestmentRouter(handler)
 
-		rec := doRequest(r, "GET", "/investments/999/transactions", "")
+		rec := doRequest(r, "GET", "/investments/00000000-0000-7000-8000-000000000999/transactions", "")
 
 		if rec.Code != http.StatusNotFound {
 			t.Fatalf("expected 404, got %d", rec.Code)
 		}
 	})
 }
+
+func TestInvestmentHandler_GetTaxReport(t *testing.T) {
+	t.Run("returns 200 with report for requested year", func(t *testing.T) {
+		var gotYear int
+		svc := &mockInvestmentService{
+			getTaxReportFn: func(_ models.UserID, year int) (*services.TaxReport, error) {
+				gotYear = year
+				return &services.TaxReport{Year: year, Sales: []services.TaxReportSale{}, ShortTermGain: 500, LongTermGain: 1500, TotalGain: 2000}, nil
+			},
+		}
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "GET", "/investments/tax-report?year=2024", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotYear != 2024 {
+			t.Errorf("expected year 2024 passed to service, got %d", gotYear)
+		}
+		report := parseJSON(t, rec)["report"].(map[string]interface{})
+		if report["total_gain"].(float64) != 2000 {
+			t.Errorf("expected total_gain=2000, got %v", report["total_gain"])
+		}
+	})
+
+	t.Run("defaults to current year", func(t *testing.T) {
+		var gotYear int
+		svc := &mockInvestmentService{
+			getTaxReportFn: func(_ models.UserID, year int) (*services.TaxReport, error) {
+				gotYear = year
+				return &services.TaxReport{Year: year}, nil
+			},
+		}
+		handler := NewInvestmentHandler(svc, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		rec := doRequest(r, "GET", "/investments/tax-report", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotYear != time.Now().UTC().Year() {
+			t.Errorf("expected current year, got %d", gotYear)
+		}
+	})
+
+	t.Run("returns 400 on invalid year", func(t *testing.T) {
+		handler := NewInvestmentHandler(&mockInvestmentService{}, &mockSecurityService{}, &mockAuditService{})
+		r := setupInvestmentRouter(handler)
+
+		for _, year := range []string{"abc", "24", "2024.5"} {
+			rec := doRequest(r, "GET", "/investments/tax-report?year="+year, "")
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("year=%s: expected 400, got %d", year, rec.Code)
+			}
+			assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+		}
+	})
+}
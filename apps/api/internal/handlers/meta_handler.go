@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+)
+
+// MetaHandler serves machine-readable descriptions of the API itself.
+type MetaHandler struct{}
+
+// NewMetaHandler creates a new MetaHandler.
+func NewMetaHandler() *MetaHandler {
+	return &MetaHandler{}
+}
+
+// ErrorCatalogResponse wraps the error catalog.
+type ErrorCatalogResponse struct {
+	Errors []apperrors.CatalogEntry `json:"errors"`
+}
+
+// ListErrors handles listing every error code the API can respond with
+// @Summary     List error codes
+// @Description List every error code with the HTTP status it is answered with, its default message, and a hint, sorted by code. Responses may carry a more specific message than the default.
+// @Tags        meta
+// @Produce     json
+// @Success     200 {object} ErrorCatalogResponse "Error catalog"
+// @Router      /meta/errors [get]
+func (h *MetaHandler) ListErrors(c *gin.Context) {
+	c.JSON(http.StatusOK, ErrorCatalogResponse{Errors: apperrors.Catalog()})
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+)
+
+func TestMetaHandler_ListErrors(t *testing.T) {
+	r := gin.New()
+	r.GET("/meta/errors", NewMetaHandler().ListErrors)
+
+	rec := doRequest(r, "GET", "/meta/errors", "")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	entries := parseJSON(t, rec)["errors"].([]interface{})
+	if len(entries) != len(apperrors.Catalog()) {
+		t.Fatalf("expected %d entries, got %d", len(apperrors.Catalog()), len(entries))
+	}
+	found := false
+	for _, e := range entries {
+		entry := e.(map[string]interface{})
+		if entry["code"] == "TRANSACTION_NOT_EDITABLE" {
+			found = true
+			if entry["status"].(float64) != http.StatusBadRequest || entry["message"] == "" || entry["hint"] == "" {
+				t.Errorf("unexpected entry: %v", entry)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected TRANSACTION_NOT_EDITABLE in the catalog")
+	}
+}
+
+func TestRespondWithError_UsesCatalogStatus(t *testing.T) {
+	r := gin.New()
+	r.GET("/known", func(c *gin.Context) {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrCategoryInUse, "used by 3 transactions"))
+	})
+	r.GET("/unknown", func(c *gin.Context) {
+		respondWithError(c, &apperrors.AppError{Code: "MADE_UP", Message: "made up", StatusCode: http.StatusTeapot})
+	})
+
+	rec := doRequest(r, "GET", "/known", "")
+	if rec.Code != http.StatusConflict {
+		t.Errorf("expected 409 for CATEGORY_IN_USE, got %d", rec.Code)
+	}
+	assertErrorCode(t, parseJSON(t, rec), "CATEGORY_IN_USE")
+
+	if rec := doRequest(r, "GET", "/unknown", ""); rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500 for an unregistered code, got %d", rec.Code)
+	}
+}
+
+// TestErrorCodesAreRegistered fails when handlers, middleware, or services
+// answer with an error code that is missing from the catalog, by building an
+// AppError by hand or writing a "code" into a response body.
+func TestErrorCodesAreRegistered(t *testing.T) {
+	for _, dir := range []string{".", "../middleware", "../services"} {
+		files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, path := range files {
+			if strings.HasSuffix(path, "_test.go") {
+				continue
+			}
+			checkErrorCodesRegistered(t, path)
+		}
+	}
+}
+
+func checkErrorCodesRegistered(t *testing.T, path string) {
+	t.Helper()
+	src, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, 0)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", path, err)
+	}
+
+	checkCode := func(expr ast.Expr) {
+		lit, ok := expr.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			t.Errorf("%s: error code is not a string literal", fset.Position(expr.Pos()))
+			return
+		}
+		code, _ := strconv.Unquote(lit.Value)
+		if _, ok := apperrors.Lookup(code); !ok {
+			t.Errorf("%s: error code %s is not registered in the catalog", fset.Position(lit.Pos()), code)
+		}
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CompositeLit:
+			sel, ok := node.Type.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "AppError" {
+				return true
+			}
+			for _, elt := range node.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "Code" {
+						checkCode(kv.Value)
+					}
+				}
+			}
+		case *ast.KeyValueExpr:
+			// "code": "..." in a gin.H response body
+			if key, ok := node.Key.(*ast.BasicLit); ok && key.Value == `"code"` {
+				if _, isLit := node.Value.(*ast.BasicLit); isLit {
+					checkCode(node.Value)
+				}
+			}
+		}
+		return true
+	})
+}
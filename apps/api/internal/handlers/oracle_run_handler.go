@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+	"kuberan/internal/services"
+)
+
+// OracleRunHandler handles the price oracle's run history.
+type OracleRunHandler struct {
+	runService services.OracleRunServicer
+}
+
+// NewOracleRunHandler creates a new OracleRunHandler.
+func NewOracleRunHandler(runService services.OracleRunServicer) *OracleRunHandler {
+	return &OracleRunHandler{runService: runService}
+}
+
+// RecordOracleRunRequest represents the result the oracle posts at the end of a run.
+type RecordOracleRunRequest struct {
+	Version           string                `json:"version" binding:"max=64"`
+	Failed            bool                  `json:"failed"`
+	Error             string                `json:"error"`
+	DryRun            bool                  `json:"dry_run"`
+	StartedAt         time.Time             `json:"started_at" binding:"required"`
+	DurationMS        int64                 `json:"duration_ms" binding:"gte=0"`
+	SecuritiesFetched int                   `json:"securities_fetched" binding:"gte=0"`
+	PricesRecorded    int                   `json:"prices_recorded" binding:"gte=0"`
+	SnapshotsRecorded int                   `json:"snapshots_recorded" binding:"gte=0"`
+	Errors            []OracleRunErrorEntry `json:"errors" binding:"dive"`
+}
+
+// OracleRunErrorEntry is a symbol the run failed to price.
+type OracleRunErrorEntry struct {
+	SecurityID string `json:"security_id" binding:"max=64"`
+	Symbol     string `json:"symbol"`
+	Error      string `json:"error" binding:"required"`
+}
+
+// OracleRunResponse wraps a single oracle run.
+type OracleRunResponse struct {
+	Run models.OracleRun `json:"run"`
+}
+
+// OracleRunsResponse lists oracle runs.
+type OracleRunsResponse struct {
+	Runs []models.OracleRun `json:"runs"`
+}
+
+// RecordOracleRun handles storing the result of an oracle run.
+// @Summary     Record an oracle run
+// @Description Store the final result of a price oracle run (pipeline endpoint), including runs that failed. The run's status is failed when failed is set, partial when errors lists symbols that could not be priced, and ok otherwise.
+// @Tags        pipeline
+// @Accept      json
+// @Produce     json
+// @Security    ApiKeyAuth
+// @Param       request body RecordOracleRunRequest true "Run result"
+// @Success     201 {object} OracleRunResponse "Recorded run"
+// @Failure     400 {object} ErrorResponse "Invalid input"
+// @Failure     401 {object} ErrorResponse "Invalid API key"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Failure     503 {object} ErrorResponse "Pipeline not configured"
+// @Router      /pipeline/oracle-runs [post]
+func (h *OracleRunHandler) RecordOracleRun(c *gin.Context) {
+	var req RecordOracleRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+		return
+	}
+
+	report := services.OracleRunReport{
+		Version:           req.Version,
+		Failed:            req.Failed,
+		Error:             req.Error,
+		DryRun:            req.DryRun,
+		StartedAt:         req.StartedAt,
+		DurationMS:        req.DurationMS,
+		SecuritiesFetched: req.SecuritiesFetched,
+		PricesRecorded:    req.PricesRecorded,
+		SnapshotsRecorded: req.SnapshotsRecorded,
+		Errors:            make([]services.OracleRunSymbolError, len(req.Errors)),
+	}
+	for i, e := range req.Errors {
+		report.Errors[i] = services.OracleRunSymbolError{SecurityID: e.SecurityID, Symbol: e.Symbol, Error: e.Error}
+	}
+
+	run, err := h.runService.RecordOracleRun(report)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"run": run})
+}
+
+// ListOracleRuns handles listing recent oracle runs.
+// @Summary     List oracle runs
+// @Description List the most recent price oracle runs with their status, counts and per-symbol errors, newest first.
+// @Tags        admin
+// @Produce     json
+// @Security    AdminKeyAuth
+// @Param       limit query int false "Maximum runs to list (default 20, max 100)"
+// @Success     200 {object} OracleRunsResponse "Runs"
+// @Failure     400 {object} ErrorResponse "Invalid limit"
+// @Failure     401 {object} ErrorResponse "Invalid admin key"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Failure     503 {object} ErrorResponse "Admin not configured"
+// @Router      /admin/oracle-runs [get]
+func (h *OracleRunHandler) ListOracleRuns(c *gin.Context) {
+	limit := services.DefaultOracleRunLimit
+	if v := c.Query("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > services.MaxOracleRunLimit {
+			respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput,
+				"limit must be a whole number from 1 to "+strconv.Itoa(services.MaxOracleRunLimit)))
+			return
+		}
+		limit = parsed
+	}
+
+	runs, err := h.runService.ListOracleRuns(limit)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"runs": runs})
+}
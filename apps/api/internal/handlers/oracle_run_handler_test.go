@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"kuberan/internal/models"
+	"kuberan/internal/services"
+)
+
+// --- mock oracle run service ---
+
+type mockOracleRunService struct {
+	recordOracleRunFn func(report services.OracleRunReport) (*models.OracleRun, error)
+	listOracleRunsFn  func(limit int) ([]models.OracleRun, error)
+}
+
+var _ services.OracleRunServicer = (*mockOracleRunService)(nil)
+
+func (m *mockOracleRunService) RecordOracleRun(report services.OracleRunReport) (*models.OracleRun, error) {
+	if m.recordOracleRunFn != nil {
+		return m.recordOracleRunFn(report)
+	}
+	return &models.OracleRun{}, nil
+}
+
+func (m *mockOracleRunService) ListOracleRuns(limit int) ([]models.OracleRun, error) {
+	if m.listOracleRunsFn != nil {
+		return m.listOracleRunsFn(limit)
+	}
+	return []models.OracleRun{}, nil
+}
+
+// --- router setup ---
+
+func setupOracleRunRouter(handler *OracleRunHandler) *gin.Engine {
+	r := gin.New()
+	r.POST("/pipeline/oracle-runs", handler.RecordOracleRun)
+	r.GET("/admin/oracle-runs", handler.ListOracleRuns)
+	return r
+}
+
+func TestOracleRunHandler_RecordOracleRun(t *testing.T) {
+	t.Run("returns 201 with the recorded run", func(t *testing.T) {
+		var got services.OracleRunReport
+		svc := &mockOracleRunService{
+			recordOracleRunFn: func(report services.OracleRunReport) (*models.OracleRun, error) {
+				got = report
+				return &models.OracleRun{Base: models.Base{ID: testID(1)}, Status: models.OracleRunStatusPartial, ErrorCount: 1}, nil
+			},
+		}
+		r := setupOracleRunRouter(NewOracleRunHandler(svc))
+
+		rec := doRequest(r, "POST", "/pipeline/oracle-runs", `{
+			"version": "1.4.0",
+			"started_at": "2026-10-15T06:00:00Z",
+			"duration_ms": 1500,
+			"securities_fetched": 2,
+			"prices_recorded": 1,
+			"errors": [{"security_id": "sec-1", "symbol": "AAPL", "error": "status 429"}]
+		}`)
+
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if got.Version != "1.4.0" || got.DurationMS != 1500 || got.PricesRecorded != 1 || len(got.Errors) != 1 || got.Errors[0].Symbol != "AAPL" {
+			t.Errorf("expected the posted report, got %+v", got)
+		}
+		if run := parseJSON(t, rec)["run"].(map[string]interface{}); run["status"] != "partial" {
+			t.Errorf("expected status partial, got %v", run["status"])
+		}
+	})
+
+	t.Run("returns 400 without started_at", func(t *testing.T) {
+		r := setupOracleRunRouter(NewOracleRunHandler(&mockOracleRunService{}))
+
+		rec := doRequest(r, "POST", "/pipeline/oracle-runs", `{"failed":true}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+}
+
+func TestOracleRunHandler_ListOracleRuns(t *testing.T) {
+	t.Run("passes the limit", func(t *testing.T) {
+		var gotLimit int
+		svc := &mockOracleRunService{
+			listOracleRunsFn: func(limit int) ([]models.OracleRun, error) {
+				gotLimit = limit
+				return []models.OracleRun{{Status: models.OracleRunStatusOK}}, nil
+			},
+		}
+		r := setupOracleRunRouter(NewOracleRunHandler(svc))
+
+		rec := doRequest(r, "GET", "/admin/oracle-runs?limit=5", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotLimit != 5 {
+			t.Errorf("expected limit 5, got %d", gotLimit)
+		}
+		if runs := parseJSON(t, rec)["runs"].([]interface{}); len(runs) != 1 {
+			t.Errorf("expected 1 run, got %d", len(runs))
+		}
+	})
+
+	t.Run("defaults the limit", func(t *testing.T) {
+		var gotLimit int
+		svc := &mockOracleRunService{
+			listOracleRunsFn: func(limit int) ([]models.OracleRun, error) {
+				gotLimit = limit
+				return []models.OracleRun{}, nil
+			},
+		}
+		r := setupOracleRunRouter(NewOracleRunHandler(svc))
+
+		doRequest(r, "GET", "/admin/oracle-runs", "")
+
+		if gotLimit != services.DefaultOracleRunLimit {
+			t.Errorf("expected limit %d, got %d", services.DefaultOracleRunLimit, gotLimit)
+		}
+	})
+
+	t.Run("rejects an invalid limit", func(t *testing.T) {
+		r := setupOracleRunRouter(NewOracleRunHandler(&mockOracleRunService{}))
+
+		for _, limit := range []string{"0", "101", "ten"} {
+			rec := doRequest(r, "GET", "/admin/oracle-runs?limit="+limit, "")
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("limit=%s: expected 400, got %d", limit, rec.Code)
+			}
+			assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+		}
+	})
+}
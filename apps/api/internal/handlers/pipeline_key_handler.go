@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+	"kuberan/internal/services"
+)
+
+// PipelineKeyHandler handles management of the stored pipeline API keys.
+type PipelineKeyHandler struct {
+	keyService services.PipelineKeyServicer
+}
+
+// NewPipelineKeyHandler creates a new PipelineKeyHandler.
+func NewPipelineKeyHandler(keyService services.PipelineKeyServicer) *PipelineKeyHandler {
+	return &PipelineKeyHandler{keyService: keyService}
+}
+
+// CreatePipelineKeyRequest represents the request payload for adding a pipeline key.
+type CreatePipelineKeyRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// CreatePipelineKeyResponse carries a new key together with its plaintext value.
+type CreatePipelineKeyResponse struct {
+	Key    *models.PipelineAPIKey `json:"key"`
+	Secret string                 `json:"secret"` // shown only once
+}
+
+// CreatePipelineKey handles adding a new active pipeline key.
+// @Summary     Create pipeline key
+// @Description Generate a new pipeline API key. The plaintext key is returned only in this response.
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Security    AdminKeyAuth
+// @Param       request body CreatePipelineKeyRequest true "Key name"
+// @Success     201 {object} CreatePipelineKeyResponse "Created key"
+// @Failure     400 {object} ErrorResponse "Invalid input"
+// @Failure     401 {object} ErrorResponse "Invalid admin key"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Failure     503 {object} ErrorResponse "Admin not configured"
+// @Router      /admin/pipeline-keys [post]
+func (h *PipelineKeyHandler) CreatePipelineKey(c *gin.Context) {
+	var req CreatePipelineKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+		return
+	}
+
+	key, secret, err := h.keyService.CreatePipelineKey(req.Name)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreatePipelineKeyResponse{Key: key, Secret: secret})
+}
+
+// ListPipelineKeys handles listing the stored pipeline keys.
+// @Summary     List pipeline keys
+// @Description List every stored pipeline API key, including revoked ones. Key values are never returned.
+// @Tags        admin
+// @Produce     json
+// @Security    AdminKeyAuth
+// @Success     200 {object} map[string][]models.PipelineAPIKey "Keys"
+// @Failure     401 {object} ErrorResponse "Invalid admin key"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Failure     503 {object} ErrorResponse "Admin not configured"
+// @Router      /admin/pipeline-keys [get]
+func (h *PipelineKeyHandler) ListPipelineKeys(c *gin.Context) {
+	keys, err := h.keyService.ListPipelineKeys()
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"keys": keys})
+}
+
+// RevokePipelineKey handles revoking a stored pipeline key.
+// @Summary     Revoke pipeline key
+// @Description Revoke a stored pipeline API key so it no longer authenticates
+// @Tags        admin
+// @Produce     json
+// @Security    AdminKeyAuth
+// @Param       id path string true "Key ID"
+// @Success     200 {object} map[string]models.PipelineAPIKey "Revoked key"
+// @Failure     400 {object} ErrorResponse "Invalid ID"
+// @Failure     401 {object} ErrorResponse "Invalid admin key"
+// @Failure     404 {object} ErrorResponse "Key not found"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Failure     503 {object} ErrorResponse "Admin not configured"
+// @Router      /admin/pipeline-keys/{id} [delete]
+func (h *PipelineKeyHandler) RevokePipelineKey(c *gin.Context) {
+	keyID, err := parsePathID(c, "id")
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	key, err := h.keyService.RevokePipelineKey(keyID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"key": key})
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+	"kuberan/internal/services"
+)
+
+// --- mock pipeline key service ---
+
+type mockPipelineKeyService struct {
+	createPipelineKeyFn       func(name string) (*models.PipelineAPIKey, string, error)
+	listPipelineKeysFn        func() ([]models.PipelineAPIKey, error)
+	revokePipelineKeyFn       func(keyID string) (*models.PipelineAPIKey, error)
+	authenticatePipelineKeyFn func(key string) (*models.PipelineAPIKey, error)
+}
+
+var _ services.PipelineKeyServicer = (*mockPipelineKeyService)(nil)
+
+func (m *mockPipelineKeyService) CreatePipelineKey(name string) (*models.PipelineAPIKey, string, error) {
+	if m.createPipelineKeyFn != nil {
+		return m.createPipelineKeyFn(name)
+	}
+	return &models.PipelineAPIKey{}, "", nil
+}
+
+func (m *mockPipelineKeyService) ListPipelineKeys() ([]models.PipelineAPIKey, error) {
+	if m.listPipelineKeysFn != nil {
+		return m.listPipelineKeysFn()
+	}
+	return []models.PipelineAPIKey{}, nil
+}
+
+func (m *mockPipelineKeyService) RevokePipelineKey(keyID string) (*models.PipelineAPIKey, error) {
+	if m.revokePipelineKeyFn != nil {
+		return m.revokePipelineKeyFn(keyID)
+	}
+	return &models.PipelineAPIKey{}, nil
+}
+
+func (m *mockPipelineKeyService) AuthenticatePipelineKey(key string) (*models.PipelineAPIKey, error) {
+	if m.authenticatePipelineKeyFn != nil {
+		return m.authenticatePipelineKeyFn(key)
+	}
+	return nil, nil
+}
+
+// --- router setup ---
+
+func setupPipelineKeyRouter(handler *PipelineKeyHandler) *gin.Engine {
+	r := gin.New()
+	r.POST("/admin/pipeline-keys", handler.CreatePipelineKey)
+	r.GET("/admin/pipeline-keys", handler.ListPipelineKeys)
+	r.DELETE("/admin/pipeline-keys/:id", handler.RevokePipelineKey)
+	return r
+}
+
+func TestPipelineKeyHandler_CreatePipelineKey(t *testing.T) {
+	t.Run("returns 201 with the secret", func(t *testing.T) {
+		svc := &mockPipelineKeyService{
+			createPipelineKeyFn: func(name string) (*models.PipelineAPIKey, string, error) {
+				return &models.PipelineAPIKey{Base: models.Base{ID: testID(1)}, Name: name, Prefix: "kpk_12345678"}, "kpk_12345678abcdef", nil
+			},
+		}
+		r := setupPipelineKeyRouter(NewPipelineKeyHandler(svc))
+
+		rec := doRequest(r, "POST", "/admin/pipeline-keys", `{"name":"oracle"}`)
+
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		body := parseJSON(t, rec)
+		if body["secret"] != "kpk_12345678abcdef" {
+			t.Errorf("expected secret in response, got %v", body["secret"])
+		}
+		key := body["key"].(map[string]interface{})
+		if key["name"] != "oracle" {
+			t.Errorf("expected name=oracle, got %v", key["name"])
+		}
+	})
+
+	t.Run("returns 400 without name", func(t *testing.T) {
+		r := setupPipelineKeyRouter(NewPipelineKeyHandler(&mockPipelineKeyService{}))
+
+		rec := doRequest(r, "POST", "/admin/pipeline-keys", `{}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+}
+
+func TestPipelineKeyHandler_RevokePipelineKey(t *testing.T) {
+	t.Run("returns 200 with the revoked key", func(t *testing.T) {
+		var gotID string
+		svc := &mockPipelineKeyService{
+			revokePipelineKeyFn: func(keyID string) (*models.PipelineAPIKey, error) {
+				gotID = keyID
+				now := time.Now()
+				return &models.PipelineAPIKey{Base: models.Base{ID: keyID}, RevokedAt: &now}, nil
+			},
+		}
+		r := setupPipelineKeyRouter(NewPipelineKeyHandler(svc))
+
+		rec := doRequest(r, "DELETE", "/admin/pipeline-keys/"+testID(2), "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotID != testID(2) {
+			t.Errorf("expected key %s, got %s", testID(2), gotID)
+		}
+	})
+
+	t.Run("returns 404 when not found", func(t *testing.T) {
+		svc := &mockPipelineKeyService{
+			revokePipelineKeyFn: func(_ string) (*models.PipelineAPIKey, error) {
+				return nil, apperrors.ErrPipelineKeyNotFound
+			},
+		}
+		r := setupPipelineKeyRouter(NewPipelineKeyHandler(svc))
+
+		rec := doRequest(r, "DELETE", "/admin/pipeline-keys/"+testID(9), "")
+
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "PIPELINE_KEY_NOT_FOUND")
+	})
+}
@@ -7,9 +7,10 @@ import (
 	"github.com/gin-gonic/gin"
 
 	apperrors "kuberan/internal/errors"
-	_ "kuberan/internal/models" // swagger type references
-	"kuberan/internal/pagination"
+	"kuberan/internal/models"
+	_ "kuberan/internal/pagination" // page types in swagger annotations
 	"kuberan/internal/services"
+	"kuberan/internal/uuid"
 )
 
 // PortfolioSnapshotHandler handles portfolio snapshot requests.
@@ -24,13 +25,15 @@ func NewPortfolioSnapshotHandler(snapshotService services.PortfolioSnapshotServi
 }
 
 // ComputeSnapshotsRequest represents the request payload for computing snapshots.
+// Setting as_of records the snapshots at that past time, valued at the prices known then.
 type ComputeSnapshotsRequest struct {
-	RecordedAt time.Time `json:"recorded_at" binding:"required"`
+	RecordedAt time.Time  `json:"recorded_at" binding:"required_without=AsOf"`
+	AsOf       *time.Time `json:"as_of"`
 }
 
 // ComputeSnapshots handles computing and recording portfolio snapshots.
 // @Summary     Compute portfolio snapshots
-// @Description Compute and record portfolio snapshots for all users (pipeline endpoint)
+// @Description Compute and record portfolio snapshots for all users (pipeline endpoint). With as_of, snapshots are stamped at that time and investments valued at the latest price on or before it; as_of must not be in the future.
 // @Tags        pipeline
 // @Accept      json
 // @Produce     json
@@ -48,7 +51,53 @@ func (h *PortfolioSnapshotHandler) ComputeSnapshots(c *gin.Context) {
 		return
 	}
 
-	count, err := h.snapshotService.ComputeAndRecordSnapshots(req.RecordedAt)
+	var count int
+	var err error
+	if req.AsOf != nil {
+		if req.AsOf.After(time.Now()) {
+			respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "as_of must not be in the future"))
+			return
+		}
+		count, err = h.snapshotService.ComputeAndRecordSnapshotsAsOf(*req.AsOf)
+	} else {
+		count, err = h.snapshotService.ComputeAndRecordSnapshots(req.RecordedAt)
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"snapshots_recorded": count})
+}
+
+// BackfillSnapshotsRequest represents the request payload for backfilling snapshots.
+type BackfillSnapshotsRequest struct {
+	From     time.Time `json:"from" binding:"required"`
+	To       time.Time `json:"to" binding:"required"`
+	Interval string    `json:"interval" binding:"required,snapshot_interval"`
+}
+
+// BackfillSnapshots handles reconstructing historical portfolio snapshots.
+// @Summary     Backfill portfolio snapshots
+// @Description Reconstruct and record portfolio snapshots for all users over a past date range (pipeline endpoint). Existing snapshots are kept.
+// @Tags        pipeline
+// @Accept      json
+// @Produce     json
+// @Security    ApiKeyAuth
+// @Param       request body BackfillSnapshotsRequest true "Backfill parameters"
+// @Success     200 {object} map[string]int "Snapshots recorded count"
+// @Failure     400 {object} ErrorResponse "Invalid input"
+// @Failure     401 {object} ErrorResponse "Invalid API key"
+// @Failure     503 {object} ErrorResponse "Pipeline not configured"
+// @Router      /pipeline/snapshots/backfill [post]
+func (h *PortfolioSnapshotHandler) BackfillSnapshots(c *gin.Context) {
+	var req BackfillSnapshotsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+		return
+	}
+
+	count, err := h.snapshotService.ComputeSnapshotsForRange(req.From, req.To, models.SnapshotInterval(req.Interval))
 	if err != nil {
 		respondWithError(c, err)
 		return
@@ -57,6 +106,39 @@ func (h *PortfolioSnapshotHandler) ComputeSnapshots(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"snapshots_recorded": count})
 }
 
+// RecomputeSnapshots handles revaluing stored snapshots after a price correction.
+// @Summary     Recompute portfolio snapshots
+// @Description Revalue the investments in every snapshot recorded at or after from, at the prices recorded on or before each snapshot (pipeline endpoint). Cash and debt balances keep their recorded values. Use after correcting a price.
+// @Tags        pipeline
+// @Produce     json
+// @Security    ApiKeyAuth
+// @Param       from query string true "Earliest snapshot to recompute (RFC3339, YYYY-MM-DD or unix seconds)"
+// @Success     200 {object} map[string]int "Snapshots changed count"
+// @Failure     400 {object} ErrorResponse "Invalid input"
+// @Failure     401 {object} ErrorResponse "Invalid API key"
+// @Failure     503 {object} ErrorResponse "Pipeline not configured"
+// @Router      /pipeline/snapshots/recompute [post]
+func (h *PortfolioSnapshotHandler) RecomputeSnapshots(c *gin.Context) {
+	fromStr := c.Query("from")
+	if fromStr == "" {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "from is required"))
+		return
+	}
+	from, err := parseDateParam("from", fromStr, rangeStart, time.UTC)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	count, err := h.snapshotService.RecomputeSnapshots(from)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"snapshots_recomputed": count})
+}
+
 // GetSnapshots handles retrieving portfolio snapshots for the authenticated user.
 // @Summary     Get portfolio snapshots
 // @Description Get paginated portfolio snapshots for a date range
@@ -64,13 +146,15 @@ func (h *PortfolioSnapshotHandler) ComputeSnapshots(c *gin.Context) {
 // @Accept      json
 // @Produce     json
 // @Security    BearerAuth
-// @Param       from_date query string true  "Start date (RFC3339 or YYYY-MM-DD)"
-// @Param       to_date   query string true  "End date (RFC3339 or YYYY-MM-DD)"
+// @Param       from_date query string true  "Start date (RFC3339, YYYY-MM-DD or unix seconds)"
+// @Param       to_date   query string true  "End date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)"
 // @Param       page      query int    false "Page number (default 1)"
 // @Param       page_size query int    false "Items per page (default 20, max 100)"
+// @Param       with_total query bool   false "Compute total_items and total_pages (default true); when false they are -1 and 0"
 // @Success     200 {object} pagination.PageResponse[models.PortfolioSnapshot] "Paginated snapshots"
 // @Failure     400 {object} ErrorResponse "Invalid input"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     503 {object} ErrorResponse "Request timed out"
 // @Router      /investments/snapshots [get]
 func (h *PortfolioSnapshotHandler) GetSnapshots(c *gin.Context) {
 	userID, err := getUserID(c)
@@ -84,9 +168,9 @@ func (h *PortfolioSnapshotHandler) GetSnapshots(c *gin.Context) {
 		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "from_date is required"))
 		return
 	}
-	from, err := parseFlexibleTime(fromStr)
+	from, err := parseDateParam("from_date", fromStr, rangeStart, time.UTC)
 	if err != nil {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+		respondWithError(c, err)
 		return
 	}
 
@@ -95,23 +179,87 @@ func (h *PortfolioSnapshotHandler) GetSnapshots(c *gin.Context) {
 		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "to_date is required"))
 		return
 	}
-	to, err := parseFlexibleTime(toStr)
+	to, err := parseDateParam("to_date", toStr, rangeEnd, time.UTC)
 	if err != nil {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+		respondWithError(c, err)
 		return
 	}
 
-	var page pagination.PageRequest
-	if err := c.ShouldBindQuery(&page); err != nil {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+	page, err := bindPage(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	result, err := h.snapshotService.GetSnapshots(c.Request.Context(), userID, from, to, page)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	respondWithPage(c, page, result)
+}
+
+// GetBenchmark handles comparing the authenticated user's investment return with a benchmark security.
+// @Summary     Compare portfolio with a benchmark
+// @Description Compare the return on the user's investments, measured between their first and last snapshots in the range, with a benchmark security's price return over the same period
+// @Tags        investments
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       security_id query string true "Benchmark security ID"
+// @Param       from_date   query string true "Start date (RFC3339, YYYY-MM-DD or unix seconds)"
+// @Param       to_date     query string true "End date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)"
+// @Success     200 {object} services.BenchmarkComparison "Portfolio and benchmark returns"
+// @Failure     400 {object} ErrorResponse "Invalid input, or missing snapshots or prices in the range"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     404 {object} ErrorResponse "Security not found"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /investments/benchmark [get]
+func (h *PortfolioSnapshotHandler) GetBenchmark(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	securityID := c.Query("security_id")
+	if securityID == "" {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "security_id is required"))
+		return
+	}
+	if !uuid.IsValid(securityID) {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "Invalid security_id format"))
+		return
+	}
+
+	fromStr := c.Query("from_date")
+	if fromStr == "" {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "from_date is required"))
+		return
+	}
+	from, err := parseDateParam("from_date", fromStr, rangeStart, time.UTC)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	toStr := c.Query("to_date")
+	if toStr == "" {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "to_date is required"))
+		return
+	}
+	to, err := parseDateParam("to_date", toStr, rangeEnd, time.UTC)
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	result, err := h.snapshotService.GetSnapshots(userID, from, to, page)
+	comparison, err := h.snapshotService.GetPortfolioVsBenchmark(userID, securityID, from, to)
 	if err != nil {
 		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	c.JSON(http.StatusOK, gin.H{"benchmark": comparison})
 }
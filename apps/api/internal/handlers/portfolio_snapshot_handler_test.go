@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -16,8 +17,12 @@ import (
 // --- mock portfolio snapshot service ---
 
 type mockPortfolioSnapshotService struct {
-	computeAndRecordSnapshotsFn func(recordedAt time.Time) (int, error)
-	getSnapshotsFn              func(userID uint, from, to time.Time, page pagination.PageRequest) (*pagination.PageResponse[models.PortfolioSnapshot], error)
+	computeAndRecordSnapshotsFn     func(recordedAt time.Time) (int, error)
+	computeAndRecordSnapshotsAsOfFn func(asOf time.Time) (int, error)
+	computeSnapshotsForRangeFn      func(from, to time.Time, interval models.SnapshotInterval) (int, error)
+	getSnapshotsFn                  func(userID string, from, to time.Time, page pagination.PageRequest) (*pagination.PageResponse[models.PortfolioSnapshot], error)
+	getPortfolioVsBenchmarkFn       func(userID, benchmarkSecurityID string, from, to time.Time) (*services.BenchmarkComparison, error)
+	recomputeSnapshotsFn            func(from time.Time) (int, error)
 }
 
 var _ services.PortfolioSnapshotServicer = (*mockPortfolioSnapshotService)(nil)
@@ -29,23 +34,58 @@ func (m *mockPortfolioSnapshotService) ComputeAndRecordSnapshots(recordedAt time
 	return 0, nil
 }
 
-func (m *mockPortfolioSnapshotService) GetSnapshots(userID uint, from, to time.Time, page pagination.PageRequest) (*pagination.PageResponse[models.PortfolioSnapshot], error) {
+func (m *mockPortfolioSnapshotService) ComputeAndRecordSnapshotsAsOf(asOf time.Time) (int, error) {
+	if m.computeAndRecordSnapshotsAsOfFn != nil {
+		return m.computeAndRecordSnapshotsAsOfFn(asOf)
+	}
+	return 0, nil
+}
+
+func (m *mockPortfolioSnapshotService) ComputeSnapshotsForRange(from, to time.Time, interval models.SnapshotInterval) (int, error) {
+	if m.computeSnapshotsForRangeFn != nil {
+		return m.computeSnapshotsForRangeFn(from, to, interval)
+	}
+	return 0, nil
+}
+
+func (m *mockPortfolioSnapshotService) ComputeUserSnapshotsForRange(userID string, from, to time.Time, interval models.SnapshotInterval) (int, error) {
+	return 0, nil
+}
+
+func (m *mockPortfolioSnapshotService) GetSnapshots(_ context.Context, userID string, from, to time.Time, page pagination.PageRequest) (*pagination.PageResponse[models.PortfolioSnapshot], error) {
 	if m.getSnapshotsFn != nil {
 		return m.getSnapshotsFn(userID, from, to, page)
 	}
-	resp := pagination.NewPageResponse([]models.PortfolioSnapshot{}, 1, 20, 0)
+	resp := pagination.NewPageResponse([]models.PortfolioSnapshot{}, page.Page, page.PageSize, 0)
 	return &resp, nil
 }
 
+func (m *mockPortfolioSnapshotService) GetPortfolioVsBenchmark(userID, benchmarkSecurityID string, from, to time.Time) (*services.BenchmarkComparison, error) {
+	if m.getPortfolioVsBenchmarkFn != nil {
+		return m.getPortfolioVsBenchmarkFn(userID, benchmarkSecurityID, from, to)
+	}
+	return &services.BenchmarkComparison{}, nil
+}
+
+func (m *mockPortfolioSnapshotService) RecomputeSnapshots(from time.Time) (int, error) {
+	if m.recomputeSnapshotsFn != nil {
+		return m.recomputeSnapshotsFn(from)
+	}
+	return 0, nil
+}
+
 // --- router setup ---
 
 func setupSnapshotRouter(handler *PortfolioSnapshotHandler) *gin.Engine {
 	r := gin.New()
 	// Pipeline route (no user auth)
 	r.POST("/pipeline/snapshots/compute", handler.ComputeSnapshots)
+	r.POST("/pipeline/snapshots/backfill", handler.BackfillSnapshots)
+	r.POST("/pipeline/snapshots/recompute", handler.RecomputeSnapshots)
 	// User route (with auth)
-	auth := r.Group("", injectUserID(1))
+	auth := r.Group("", injectUserID(testID(1)))
 	auth.GET("/portfolio/snapshots", handler.GetSnapshots)
+	auth.GET("/investments/benchmark", handler.GetBenchmark)
 	return r
 }
 
@@ -85,6 +125,45 @@ func TestPortfolioSnapshotHandler_ComputeSnapshots(t *testing.T) {
 		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
 	})
 
+	t.Run("uses_as_of_when_given", func(t *testing.T) {
+		var captured time.Time
+		svc := &mockPortfolioSnapshotService{
+			computeAndRecordSnapshotsFn: func(_ time.Time) (int, error) {
+				t.Error("expected the as-of computation")
+				return 0, nil
+			},
+			computeAndRecordSnapshotsAsOfFn: func(asOf time.Time) (int, error) {
+				captured = asOf
+				return 2, nil
+			},
+		}
+		handler := NewPortfolioSnapshotHandler(svc, &mockAuditService{})
+		r := setupSnapshotRouter(handler)
+
+		rec := doRequest(r, "POST", "/pipeline/snapshots/compute",
+			`{"as_of":"2026-02-06T23:59:59Z"}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if want := time.Date(2026, 2, 6, 23, 59, 59, 0, time.UTC); !captured.Equal(want) {
+			t.Errorf("expected as_of %v, got %v", want, captured)
+		}
+	})
+
+	t.Run("returns_400_future_as_of", func(t *testing.T) {
+		handler := NewPortfolioSnapshotHandler(&mockPortfolioSnapshotService{}, &mockAuditService{})
+		r := setupSnapshotRouter(handler)
+
+		future := time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339)
+		rec := doRequest(r, "POST", "/pipeline/snapshots/compute", `{"as_of":"`+future+`"}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
 	t.Run("returns_500_on_service_error", func(t *testing.T) {
 		svc := &mockPortfolioSnapshotService{
 			computeAndRecordSnapshotsFn: func(_ time.Time) (int, error) {
@@ -103,13 +182,102 @@ func TestPortfolioSnapshotHandler_ComputeSnapshots(t *testing.T) {
 	})
 }
 
+func TestPortfolioSnapshotHandler_BackfillSnapshots(t *testing.T) {
+	t.Run("returns_200_on_success", func(t *testing.T) {
+		var capturedInterval models.SnapshotInterval
+		svc := &mockPortfolioSnapshotService{
+			computeSnapshotsForRangeFn: func(_, _ time.Time, interval models.SnapshotInterval) (int, error) {
+				capturedInterval = interval
+				return 12, nil
+			},
+		}
+		handler := NewPortfolioSnapshotHandler(svc, &mockAuditService{})
+		r := setupSnapshotRouter(handler)
+
+		rec := doRequest(r, "POST", "/pipeline/snapshots/backfill",
+			`{"from":"2025-01-01T00:00:00Z","to":"2025-12-01T00:00:00Z","interval":"monthly"}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		result := parseJSON(t, rec)
+		if result["snapshots_recorded"].(float64) != 12 {
+			t.Errorf("expected snapshots_recorded=12, got %v", result["snapshots_recorded"])
+		}
+		if capturedInterval != models.SnapshotIntervalMonthly {
+			t.Errorf("expected interval=monthly, got %s", capturedInterval)
+		}
+	})
+
+	t.Run("returns_400_invalid_interval", func(t *testing.T) {
+		handler := NewPortfolioSnapshotHandler(&mockPortfolioSnapshotService{}, &mockAuditService{})
+		r := setupSnapshotRouter(handler)
+
+		rec := doRequest(r, "POST", "/pipeline/snapshots/backfill",
+			`{"from":"2025-01-01T00:00:00Z","to":"2025-12-01T00:00:00Z","interval":"hourly"}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("returns_400_missing_range", func(t *testing.T) {
+		handler := NewPortfolioSnapshotHandler(&mockPortfolioSnapshotService{}, &mockAuditService{})
+		r := setupSnapshotRouter(handler)
+
+		rec := doRequest(r, "POST", "/pipeline/snapshots/backfill", `{"interval":"daily"}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+}
+
+func TestPortfolioSnapshotHandler_RecomputeSnapshots(t *testing.T) {
+	t.Run("returns_200_with_count", func(t *testing.T) {
+		var capturedFrom time.Time
+		svc := &mockPortfolioSnapshotService{
+			recomputeSnapshotsFn: func(from time.Time) (int, error) {
+				capturedFrom = from
+				return 3, nil
+			},
+		}
+		r := setupSnapshotRouter(NewPortfolioSnapshotHandler(svc, &mockAuditService{}))
+
+		rec := doRequest(r, "POST", "/pipeline/snapshots/recompute?from=2025-03-01", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if result := parseJSON(t, rec); result["snapshots_recomputed"].(float64) != 3 {
+			t.Errorf("expected snapshots_recomputed=3, got %v", result["snapshots_recomputed"])
+		}
+		if !capturedFrom.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("expected from=2025-03-01, got %v", capturedFrom)
+		}
+	})
+
+	t.Run("returns_400_missing_from", func(t *testing.T) {
+		r := setupSnapshotRouter(NewPortfolioSnapshotHandler(&mockPortfolioSnapshotService{}, &mockAuditService{}))
+
+		rec := doRequest(r, "POST", "/pipeline/snapshots/recompute", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+}
+
 func TestPortfolioSnapshotHandler_GetSnapshots(t *testing.T) {
 	t.Run("returns_200_with_data", func(t *testing.T) {
 		now := time.Now().UTC().Truncate(time.Second)
 		svc := &mockPortfolioSnapshotService{
-			getSnapshotsFn: func(_ uint, _, _ time.Time, _ pagination.PageRequest) (*pagination.PageResponse[models.PortfolioSnapshot], error) {
+			getSnapshotsFn: func(_ string, _, _ time.Time, _ pagination.PageRequest) (*pagination.PageResponse[models.PortfolioSnapshot], error) {
 				resp := pagination.NewPageResponse([]models.PortfolioSnapshot{
-					{ID: 1, UserID: 1, RecordedAt: now, TotalNetWorth: 15500000, CashBalance: 5000000, InvestmentValue: 11000000, DebtBalance: 500000},
+					{ID: testID(1), UserID: testID(1), RecordedAt: now, TotalNetWorth: 15500000, CashBalance: 5000000, InvestmentValue: 11000000, DebtBalance: 500000},
 				}, 1, 20, 1)
 				return &resp, nil
 			},
@@ -159,7 +327,7 @@ func TestPortfolioSnapshotHandler_GetSnapshots(t *testing.T) {
 
 	t.Run("returns_200_empty_data", func(t *testing.T) {
 		svc := &mockPortfolioSnapshotService{
-			getSnapshotsFn: func(_ uint, _, _ time.Time, _ pagination.PageRequest) (*pagination.PageResponse[models.PortfolioSnapshot], error) {
+			getSnapshotsFn: func(_ string, _, _ time.Time, _ pagination.PageRequest) (*pagination.PageResponse[models.PortfolioSnapshot], error) {
 				resp := pagination.NewPageResponse([]models.PortfolioSnapshot{}, 1, 20, 0)
 				return &resp, nil
 			},
@@ -195,10 +363,10 @@ func TestPortfolioSnapshotHandler_GetSnapshots(t *testing.T) {
 	})
 
 	t.Run("passes_user_id_and_pagination_to_service", func(t *testing.T) {
-		var capturedUserID uint
+		var capturedUserID string
 		var capturedPage pagination.PageRequest
 		svc := &mockPortfolioSnapshotService{
-			getSnapshotsFn: func(userID uint, _, _ time.Time, page pagination.PageRequest) (*pagination.PageResponse[models.PortfolioSnapshot], error) {
+			getSnapshotsFn: func(userID string, _, _ time.Time, page pagination.PageRequest) (*pagination.PageResponse[models.PortfolioSnapshot], error) {
 				capturedUserID = userID
 				capturedPage = page
 				resp := pagination.NewPageResponse([]models.PortfolioSnapshot{}, 2, 5, 10)
@@ -213,8 +381,8 @@ func TestPortfolioSnapshotHandler_GetSnapshots(t *testing.T) {
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
 		}
-		if capturedUserID != 1 {
-			t.Errorf("expected userID=1, got %d", capturedUserID)
+		if capturedUserID != testID(1) {
+			t.Errorf("expected userID=1, got %s", capturedUserID)
 		}
 		if capturedPage.Page != 2 {
 			t.Errorf("expected page=2, got %d", capturedPage.Page)
@@ -224,3 +392,56 @@ func TestPortfolioSnapshotHandler_GetSnapshots(t *testing.T) {
 		}
 	})
 }
+
+func TestPortfolioSnapshotHandler_GetBenchmark(t *testing.T) {
+	t.Run("returns_200_with_comparison", func(t *testing.T) {
+		var gotSecurityID string
+		var gotFrom, gotTo time.Time
+		svc := &mockPortfolioSnapshotService{
+			getPortfolioVsBenchmarkFn: func(_, securityID string, from, to time.Time) (*services.BenchmarkComparison, error) {
+				gotSecurityID, gotFrom, gotTo = securityID, from, to
+				return &services.BenchmarkComparison{BenchmarkSecurityID: securityID, PortfolioReturn: 4, BenchmarkReturn: 10, Difference: -6}, nil
+			},
+		}
+		handler := NewPortfolioSnapshotHandler(svc, &mockAuditService{})
+		r := setupSnapshotRouter(handler)
+
+		rec := doRequest(r, "GET", "/investments/benchmark?security_id="+testID(7)+"&from_date=2026-01-01&to_date=2026-03-31", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotSecurityID != testID(7) || !gotFrom.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) ||
+			!gotTo.Equal(time.Date(2026, 3, 31, 23, 59, 59, 999999999, time.UTC)) {
+			t.Errorf("unexpected service args: %s %v %v", gotSecurityID, gotFrom, gotTo)
+		}
+		benchmark := parseJSON(t, rec)["benchmark"].(map[string]interface{})
+		if benchmark["difference"].(float64) != -6 {
+			t.Errorf("expected difference -6, got %v", benchmark["difference"])
+		}
+	})
+
+	t.Run("returns_400_missing_security_id", func(t *testing.T) {
+		handler := NewPortfolioSnapshotHandler(&mockPortfolioSnapshotService{}, &mockAuditService{})
+		r := setupSnapshotRouter(handler)
+
+		rec := doRequest(r, "GET", "/investments/benchmark?from_date=2026-01-01&to_date=2026-03-31", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("returns_400_missing_to_date", func(t *testing.T) {
+		handler := NewPortfolioSnapshotHandler(&mockPortfolioSnapshotService{}, &mockAuditService{})
+		r := setupSnapshotRouter(handler)
+
+		rec := doRequest(r, "GET", "/investments/benchmark?security_id="+testID(7)+"&from_date=2026-01-01", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+}
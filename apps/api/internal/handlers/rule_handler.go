@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+	_ "kuberan/internal/pagination" // page types in swagger annotations
+	"kuberan/internal/services"
+)
+
+// RuleHandler handles categorization rule requests.
+type RuleHandler struct {
+	ruleService  services.RuleServicer
+	auditService services.AuditServicer
+}
+
+// NewRuleHandler creates a new RuleHandler.
+func NewRuleHandler(ruleService services.RuleServicer, auditService services.AuditServicer) *RuleHandler {
+	return &RuleHandler{ruleService: ruleService, auditService: auditService}
+}
+
+// CreateRuleRequest represents the request payload for creating a categorization rule.
+type CreateRuleRequest struct {
+	CategoryID string               `json:"category_id" binding:"required"`
+	Pattern    string               `json:"pattern" binding:"required,min=1,max=255"`
+	MatchType  models.RuleMatchType `json:"match_type" binding:"required,rule_match_type"`
+	Priority   int                  `json:"priority"`
+}
+
+// UpdateRuleRequest represents the request payload for updating a categorization rule.
+type UpdateRuleRequest struct {
+	CategoryID *string               `json:"category_id"`
+	Pattern    *string               `json:"pattern" binding:"omitempty,min=1,max=255"`
+	MatchType  *models.RuleMatchType `json:"match_type" binding:"omitempty,rule_match_type"`
+	Priority   *int                  `json:"priority"`
+}
+
+// ApplyRulesResponse represents the result of retroactively applying rules.
+type ApplyRulesResponse struct {
+	Categorized int `json:"categorized"`
+}
+
+// CreateRule handles the creation of a new categorization rule.
+// @Summary     Create a categorization rule
+// @Description Create a rule that auto-categorizes transactions by description
+// @Tags        rules
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       request body CreateRuleRequest true "Rule details"
+// @Success     201 {object} models.CategorizationRule "Rule created"
+// @Failure     400 {object} ErrorResponse "Invalid input"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     404 {object} ErrorResponse "Category not found"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /rules [post]
+func (h *RuleHandler) CreateRule(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	var req CreateRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+		return
+	}
+
+	rule, err := h.ruleService.CreateRule(userID, req.CategoryID, req.Pattern, req.MatchType, req.Priority)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(userID, "CREATE_RULE", "categorization_rule", rule.ID, c.ClientIP(),
+		map[string]interface{}{"pattern": req.Pattern, "match_type": req.MatchType, "category_id": req.CategoryID})
+
+	c.JSON(http.StatusCreated, gin.H{"rule": rule})
+}
+
+// GetRules handles listing categorization rules for the authenticated user.
+// @Summary     Get categorization rules
+// @Description Get a paginated list of rules in evaluation order (highest priority first)
+// @Tags        rules
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       page      query int false "Page number (default 1)"
+// @Param       page_size query int false "Items per page (default 20, max 100)"
+// @Param       with_total query bool false "Compute total_items and total_pages (default true); when false they are -1 and 0"
+// @Success     200 {object} pagination.PageResponse[models.CategorizationRule] "Paginated rules"
+// @Failure     400 {object} ErrorResponse "Invalid input"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /rules [get]
+func (h *RuleHandler) GetRules(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	page, err := bindPage(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	result, err := h.ruleService.GetUserRules(userID, page)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	respondWithPage(c, page, result)
+}
+
+// GetRule handles retrieving a specific categorization rule.
+// @Summary     Get rule by ID
+// @Description Get a specific categorization rule by ID
+// @Tags        rules
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Rule ID"
+// @Success     200 {object} models.CategorizationRule "Rule details"
+// @Failure     400 {object} ErrorResponse "Invalid rule ID"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     404 {object} ErrorResponse "Rule not found"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /rules/{id} [get]
+func (h *RuleHandler) GetRule(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	ruleID, err := parsePathID(c, "id")
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	rule, err := h.ruleService.GetRuleByID(userID, ruleID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rule": rule})
+}
+
+// UpdateRule handles updating an existing categorization rule.
+// @Summary     Update rule
+// @Description Update an existing categorization rule
+// @Tags        rules
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id      path string            true "Rule ID"
+// @Param       request body UpdateRuleRequest true "Updated rule details"
+// @Success     200 {object} models.CategorizationRule "Updated rule"
+// @Failure     400 {object} ErrorResponse "Invalid input or rule ID"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     404 {object} ErrorResponse "Rule or category not found"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /rules/{id} [put]
+func (h *RuleHandler) UpdateRule(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	ruleID, err := parsePathID(c, "id")
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	var req UpdateRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+		return
+	}
+
+	rule, err := h.ruleService.UpdateRule(userID, ruleID, services.RuleUpdateFields{
+		CategoryID: req.CategoryID,
+		Pattern:    req.Pattern,
+		MatchType:  req.MatchType,
+		Priority:   req.Priority,
+	})
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(userID, "UPDATE_RULE", "categorization_rule", ruleID, c.ClientIP(), nil)
+
+	c.JSON(http.StatusOK, gin.H{"rule": rule})
+}
+
+// DeleteRule handles deleting a categorization rule.
+// @Summary     Delete rule
+// @Description Delete a categorization rule by ID (soft delete)
+// @Tags        rules
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Rule ID"
+// @Success     200 {object} MessageResponse "Rule deleted"
+// @Failure     400 {object} ErrorResponse "Invalid rule ID"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     404 {object} ErrorResponse "Rule not found"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /rules/{id} [delete]
+func (h *RuleHandler) DeleteRule(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	ruleID, err := parsePathID(c, "id")
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	if err := h.ruleService.DeleteRule(userID, ruleID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(userID, "DELETE_RULE", "categorization_rule", ruleID, c.ClientIP(), nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Rule deleted successfully"})
+}
+
+// ApplyRules handles retroactively categorizing existing uncategorized transactions.
+// @Summary     Apply categorization rules
+// @Description Apply the user's rules to all existing uncategorized income and expense transactions
+// @Tags        rules
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Success     200 {object} ApplyRulesResponse "Number of transactions categorized"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /rules/apply [post]
+func (h *RuleHandler) ApplyRules(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	count, err := h.ruleService.ApplyRulesToUncategorized(userID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(userID, "APPLY_RULES", "categorization_rule", "", c.ClientIP(),
+		map[string]interface{}{"categorized": count})
+
+	c.JSON(http.StatusOK, ApplyRulesResponse{Categorized: count})
+}
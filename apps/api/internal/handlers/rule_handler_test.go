@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+	"kuberan/internal/pagination"
+	"kuberan/internal/services"
+)
+
+// --- mock rule service ---
+
+type mockRuleService struct {
+	createRuleFn                func(userID, categoryID, pattern string, matchType models.RuleMatchType, priority int) (*models.CategorizationRule, error)
+	getUserRulesFn              func(userID string, page pagination.PageRequest) (*pagination.PageResponse[models.CategorizationRule], error)
+	getRuleByIDFn               func(userID, ruleID string) (*models.CategorizationRule, error)
+	updateRuleFn                func(userID, ruleID string, updates services.RuleUpdateFields) (*models.CategorizationRule, error)
+	deleteRuleFn                func(userID, ruleID string) error
+	applyRulesFn                func(userID, transactionID string) (*models.Transaction, error)
+	applyRulesToUncategorizedFn func(userID string) (int, error)
+}
+
+func (m *mockRuleService) CreateRule(userID, categoryID, pattern string, matchType models.RuleMatchType, priority int) (*models.CategorizationRule, error) {
+	if m.createRuleFn != nil {
+		return m.createRuleFn(userID, categoryID, pattern, matchType, priority)
+	}
+	return &models.CategorizationRule{}, nil
+}
+
+func (m *mockRuleService) GetUserRules(userID string, page pagination.PageRequest) (*pagination.PageResponse[models.CategorizationRule], error) {
+	if m.getUserRulesFn != nil {
+		return m.getUserRulesFn(userID, page)
+	}
+	resp := pagination.NewPageResponse([]models.CategorizationRule{}, page.Page, page.PageSize, 0)
+	return &resp, nil
+}
+
+func (m *mockRuleService) GetRuleByID(userID, ruleID string) (*models.CategorizationRule, error) {
+	if m.getRuleByIDFn != nil {
+		return m.getRuleByIDFn(userID, ruleID)
+	}
+	return &models.CategorizationRule{}, nil
+}
+
+func (m *mockRuleService) UpdateRule(userID, ruleID string, updates services.RuleUpdateFields) (*models.CategorizationRule, error) {
+	if m.updateRuleFn != nil {
+		return m.updateRuleFn(userID, ruleID, updates)
+	}
+	return &models.CategorizationRule{}, nil
+}
+
+func (m *mockRuleService) DeleteRule(userID, ruleID string) error {
+	if m.deleteRuleFn != nil {
+		return m.deleteRuleFn(userID, ruleID)
+	}
+	return nil
+}
+
+func (m *mockRuleService) ApplyRules(userID, transactionID string) (*models.Transaction, error) {
+	if m.applyRulesFn != nil {
+		return m.applyRulesFn(userID, transactionID)
+	}
+	return &models.Transaction{}, nil
+}
+
+func (m *mockRuleService) ApplyRulesToUncategorized(userID string) (int, error) {
+	if m.applyRulesToUncategorizedFn != nil {
+		return m.applyRulesToUncategorizedFn(userID)
+	}
+	return 0, nil
+}
+
+var _ services.RuleServicer = (*mockRuleService)(nil)
+
+func setupRuleRouter(handler *RuleHandler) *gin.Engine {
+	r := gin.New()
+	auth := r.Group("", injectUserID(testID(1)))
+	auth.POST("/rules", handler.CreateRule)
+	auth.GET("/rules", handler.GetRules)
+	auth.POST("/rules/apply", handler.ApplyRules)
+	auth.GET("/rules/:id", handler.GetRule)
+	auth.PUT("/rules/:id", handler.UpdateRule)
+	auth.DELETE("/rules/:id", handler.DeleteRule)
+	return r
+}
+
+func TestRuleHandler_CreateRule(t *testing.T) {
+	t.Run("returns 201 on success", func(t *testing.T) {
+		svc := &mockRuleService{
+			createRuleFn: func(_, categoryID, pattern string, matchType models.RuleMatchType, priority int) (*models.CategorizationRule, error) {
+				return &models.CategorizationRule{
+					Base:       models.Base{ID: testID(1)},
+					UserID:     testID(1),
+					CategoryID: categoryID,
+					Pattern:    pattern,
+					MatchType:  matchType,
+					Priority:   priority,
+				}, nil
+			},
+		}
+		handler := NewRuleHandler(svc, &mockAuditService{})
+		r := setupRuleRouter(handler)
+
+		rec := doRequest(r, "POST", "/rules",
+			`{"category_id":"00000000-0000-7000-8000-000000000002","pattern":"^UBER","match_type":"regex","priority":5}`)
+
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		result := parseJSON(t, rec)
+		rule := result["rule"].(map[string]interface{})
+		if rule["pattern"] != "^UBER" {
+			t.Errorf("expected pattern=^UBER, got %v", rule["pattern"])
+		}
+		if rule["priority"].(float64) != 5 {
+			t.Errorf("expected priority=5, got %v", rule["priority"])
+		}
+	})
+
+	t.Run("returns 400 on invalid match type", func(t *testing.T) {
+		handler := NewRuleHandler(&mockRuleService{}, &mockAuditService{})
+		r := setupRuleRouter(handler)
+
+		rec := doRequest(r, "POST", "/rules",
+			`{"category_id":"00000000-0000-7000-8000-000000000002","pattern":"x","match_type":"glob"}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("returns 400 on invalid regex", func(t *testing.T) {
+		svc := &mockRuleService{
+			createRuleFn: func(_, _, _ string, _ models.RuleMatchType, _ int) (*models.CategorizationRule, error) {
+				return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "invalid regex pattern")
+			},
+		}
+		handler := NewRuleHandler(svc, &mockAuditService{})
+		r := setupRuleRouter(handler)
+
+		rec := doRequest(r, "POST", "/rules",
+			`{"category_id":"00000000-0000-7000-8000-000000000002","pattern":"([a-z","match_type":"regex"}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+}
+
+func TestRuleHandler_GetRule(t *testing.T) {
+	t.Run("returns 404 when not found", func(t *testing.T) {
+		svc := &mockRuleService{
+			getRuleByIDFn: func(_, _ string) (*models.CategorizationRule, error) {
+				return nil, apperrors.ErrRuleNotFound
+			},
+		}
+		handler := NewRuleHandler(svc, &mockAuditService{})
+		r := setupRuleRouter(handler)
+
+		rec := doRequest(r, "GET", "/rules/00000000-0000-7000-8000-000000000099", "")
+
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "RULE_NOT_FOUND")
+	})
+
+	t.Run("returns 400 on invalid id", func(t *testing.T) {
+		handler := NewRuleHandler(&mockRuleService{}, &mockAuditService{})
+		r := setupRuleRouter(handler)
+
+		rec := doRequest(r, "GET", "/rules/abc", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+	})
+}
+
+func TestRuleHandler_UpdateRule(t *testing.T) {
+	t.Run("passes fields to service", func(t *testing.T) {
+		var captured services.RuleUpdateFields
+		svc := &mockRuleService{
+			updateRuleFn: func(_, ruleID string, updates services.RuleUpdateFields) (*models.CategorizationRule, error) {
+				captured = updates
+				return &models.CategorizationRule{Base: models.Base{ID: ruleID}}, nil
+			},
+		}
+		handler := NewRuleHandler(svc, &mockAuditService{})
+		r := setupRuleRouter(handler)
+
+		rec := doRequest(r, "PUT", "/rules/00000000-0000-7000-8000-000000000001", `{"priority":7}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if captured.Priority == nil || *captured.Priority != 7 {
+			t.Errorf("expected priority=7, got %v", captured.Priority)
+		}
+		if captured.Pattern != nil || captured.MatchType != nil || captured.CategoryID != nil {
+			t.Error("expected unset fields to be nil")
+		}
+	})
+}
+
+func TestRuleHandler_DeleteRule(t *testing.T) {
+	t.Run("returns 200 on success", func(t *testing.T) {
+		handler := NewRuleHandler(&mockRuleService{}, &mockAuditService{})
+		r := setupRuleRouter(handler)
+
+		rec := doRequest(r, "DELETE", "/rules/00000000-0000-7000-8000-000000000001", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rec.Code)
+		}
+	})
+}
+
+func TestRuleHandler_ApplyRules(t *testing.T) {
+	t.Run("returns categorized count", func(t *testing.T) {
+		svc := &mockRuleService{
+			applyRulesToUncategorizedFn: func(_ string) (int, error) {
+				return 4, nil
+			},
+		}
+		handler := NewRuleHandler(svc, &mockAuditService{})
+		r := setupRuleRouter(handler)
+
+		rec := doRequest(r, "POST", "/rules/apply", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		result := parseJSON(t, rec)
+		if result["categorized"].(float64) != 4 {
+			t.Errorf("expected categorized=4, got %v", result["categorized"])
+		}
+	})
+}
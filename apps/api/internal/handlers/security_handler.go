@@ -1,41 +1,68 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	apperrors "kuberan/internal/errors"
 	"kuberan/internal/models"
-	"kuberan/internal/pagination"
+	_ "kuberan/internal/pagination" // page types in swagger annotations
 	"kuberan/internal/services"
+	"kuberan/internal/validator"
 )
 
 // SecurityHandler handles security-related requests.
 type SecurityHandler struct {
 	securityService services.SecurityServicer
 	auditService    services.AuditServicer
+	maxBatchSize    int
 }
 
+// DefaultSecurityBatchSize is the most securities one batch may upsert unless
+// configured otherwise.
+const DefaultSecurityBatchSize = 500
+
 // NewSecurityHandler creates a new SecurityHandler.
 func NewSecurityHandler(securityService services.SecurityServicer, auditService services.AuditServicer) *SecurityHandler {
-	return &SecurityHandler{securityService: securityService, auditService: auditService}
+	return &SecurityHandler{securityService: securityService, auditService: auditService, maxBatchSize: DefaultSecurityBatchSize}
+}
+
+// WithMaxBatchSize sets the most securities one batch may upsert. Values below
+// 1 are ignored.
+func (h *SecurityHandler) WithMaxBatchSize(size int) *SecurityHandler {
+	if size >= 1 {
+		h.maxBatchSize = size
+	}
+	return h
 }
 
 // CreateSecurityRequest represents the request payload for creating a security.
 type CreateSecurityRequest struct {
-	Symbol          string           `json:"symbol" binding:"required,min=1,max=20"`
-	Name            string           `json:"name" binding:"required,min=1,max=200"`
-	AssetType       models.AssetType `json:"asset_type" binding:"required,asset_type"`
-	Currency        string           `json:"currency" binding:"omitempty,iso4217"`
-	Exchange        string           `json:"exchange,omitempty"`
-	ProviderSymbol  string           `json:"provider_symbol,omitempty"`
-	MaturityDate    *time.Time       `json:"maturity_date,omitempty"`
-	YieldToMaturity float64          `json:"yield_to_maturity,omitempty"`
-	CouponRate      float64          `json:"coupon_rate,omitempty"`
-	Network         string           `json:"network,omitempty"`
-	PropertyType    string           `json:"property_type,omitempty"`
+	Symbol            string           `json:"symbol" binding:"required,min=1,max=20"`
+	Name              string           `json:"name" binding:"required,min=1,max=200"`
+	AssetType         models.AssetType `json:"asset_type" binding:"required,asset_type"`
+	Currency          string           `json:"currency" binding:"omitempty,iso4217"`
+	Exchange          string           `json:"exchange,omitempty"`
+	ProviderSymbol    string           `json:"provider_symbol,omitempty"`
+	PreferredProvider string           `json:"preferred_provider,omitempty" binding:"max=50"`
+	MaturityDate      *time.Time       `json:"maturity_date,omitempty"`
+	YieldToMaturity   float64          `json:"yield_to_maturity,omitempty"`
+	CouponRate        float64          `json:"coupon_rate,omitempty"`
+	Network           string           `json:"network,omitempty"`
+	PropertyType      string           `json:"property_type,omitempty"`
+}
+
+// CreateSecuritiesBatchRequest represents the request payload for upserting
+// securities in bulk. Entries are validated one by one by the service, so an
+// invalid entry fails on its own instead of rejecting the batch.
+type CreateSecuritiesBatchRequest struct {
+	Securities []CreateSecurityRequest `json:"securities" binding:"required,min=1"`
 }
 
 // RecordPricesRequest represents the request payload for bulk price recording.
@@ -48,6 +75,11 @@ type RecordPriceEntry struct {
 	SecurityID string    `json:"security_id" binding:"required"`
 	Price      int64     `json:"price" binding:"required,gt=0"`
 	RecordedAt time.Time `json:"recorded_at" binding:"required"`
+	Source     string    `json:"source" binding:"omitempty,price_source"` // yahoo, coingecko, bursa or manual (default)
+	Currency   string    `json:"currency" binding:"omitempty,iso4217"`    // currency the price is in, when not the security's
+	// AllowLargeMove records the price even if it moves further from the
+	// previous price than the anomaly guard allows
+	AllowLargeMove bool `json:"allow_large_move"`
 }
 
 // CreateSecurity handles creating a new security.
@@ -87,6 +119,55 @@ func (h *SecurityHandler) CreateSecurity(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{"security": security})
 }
 
+// CreateSecuritiesBatch handles creating or updating securities in bulk.
+// @Summary     Upsert securities in bulk
+// @Description Create or update up to SECURITY_BATCH_MAX_SIZE securities in one database transaction (pipeline endpoint). Entries are matched to existing securities on symbol and exchange. Each entry is reported as created, updated or failed, with the reason for failures; invalid entries do not stop the others.
+// @Tags        pipeline
+// @Accept      json
+// @Produce     json
+// @Security    ApiKeyAuth
+// @Param       request body CreateSecuritiesBatchRequest true "Securities"
+// @Success     200 {object} services.SecurityBatchResult "Per-entry outcome and counts"
+// @Failure     400 {object} ErrorResponse "Invalid input"
+// @Failure     401 {object} ErrorResponse "Invalid API key"
+// @Failure     503 {object} ErrorResponse "Pipeline not configured"
+// @Router      /pipeline/securities/batch [post]
+func (h *SecurityHandler) CreateSecuritiesBatch(c *gin.Context) {
+	var req CreateSecuritiesBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+		return
+	}
+	if len(req.Securities) > h.maxBatchSize {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput,
+			fmt.Sprintf("a batch holds at most %d securities, got %d", h.maxBatchSize, len(req.Securities))))
+		return
+	}
+
+	inputs := make([]services.SecurityInput, len(req.Securities))
+	for i, s := range req.Securities {
+		inputs[i] = services.SecurityInput{
+			Symbol:      s.Symbol,
+			Name:        s.Name,
+			AssetType:   s.AssetType,
+			Currency:    s.Currency,
+			Exchange:    s.Exchange,
+			ExtraFields: buildSecurityExtraFields(s),
+		}
+	}
+
+	result, err := h.securityService.UpsertSecurities(inputs)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log("", "UPSERT_SECURITIES", "security", "", c.ClientIP(),
+		map[string]interface{}{"created": result.Created, "updated": result.Updated, "failed": result.Failed})
+
+	c.JSON(http.StatusOK, result)
+}
+
 // ListAllSecurities handles listing all securities for the pipeline.
 // @Summary     List all securities (pipeline)
 // @Description Get all active securities without pagination (pipeline endpoint)
@@ -110,34 +191,60 @@ func (h *SecurityHandler) ListAllSecurities(c *gin.Context) {
 
 // ListSecurities handles listing all securities.
 // @Summary     List securities
-// @Description Get a paginated list of all securities, optionally filtered by search term
+// @Description Get a paginated list of all securities with the caller's holding in each, optionally filtered by search term, asset type, exchange, currency, or to held securities. Filters combine.
 // @Tags        securities
 // @Accept      json
 // @Produce     json
 // @Security    BearerAuth
-// @Param       search    query string false "Search by symbol or name (case-insensitive)"
-// @Param       page      query int    false "Page number (default 1)"
-// @Param       page_size query int    false "Items per page (default 20, max 100)"
-// @Success     200 {object} pagination.PageResponse[models.Security] "Paginated securities"
+// @Param       search     query string false "Search by symbol or name (case-insensitive)"
+// @Param       asset_type query string false "Filter by asset type (stock, etf, bond, crypto, reit)"
+// @Param       exchange   query string false "Filter by exchange (case-insensitive, e.g. NASDAQ)"
+// @Param       currency   query string false "Filter by ISO 4217 currency code (e.g. USD)"
+// @Param       owned      query bool   false "Only list securities the caller holds"
+// @Param       page       query int    false "Page number (default 1)"
+// @Param       page_size  query int    false "Items per page (default 20, max 100)"
+// @Param       with_total query bool   false "Compute total_items and total_pages (default true); when false they are -1 and 0"
+// @Success     200 {object} pagination.PageResponse[services.SecurityWithHolding] "Paginated securities"
+// @Failure     400 {object} ErrorResponse "Invalid input"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
 // @Failure     500 {object} ErrorResponse "Server error"
 // @Router      /securities [get]
 func (h *SecurityHandler) ListSecurities(c *gin.Context) {
-	var page pagination.PageRequest
-	if err := c.ShouldBindQuery(&page); err != nil {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	page, err := bindPage(c)
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	search := c.Query("search")
+	filter, err := parseSecurityFilter(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
 
-	result, err := h.securityService.ListSecurities(search, page)
+	ownedOnly := false
+	if v := c.Query("owned"); v != "" {
+		parsed, parseErr := strconv.ParseBool(v)
+		if parseErr != nil {
+			respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "owned must be true or false"))
+			return
+		}
+		ownedOnly = parsed
+	}
+
+	result, err := h.securityService.ListSecuritiesWithHoldings(userID, filter, page, ownedOnly)
 	if err != nil {
 		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	respondWithPage(c, page, result)
 }
 
 // GetSecurity handles retrieving a specific security.
@@ -171,13 +278,13 @@ func (h *SecurityHandler) GetSecurity(c *gin.Context) {
 
 // RecordPrices handles bulk price recording for securities.
 // @Summary     Record prices
-// @Description Bulk record prices for securities (pipeline endpoint)
+// @Description Bulk record prices for securities (pipeline endpoint). A price for an already recorded security and timestamp replaces it. currency names the currency a price is in when it is not the security's own, e.g. after the oracle converted it. An entry that moves further from the security's previous price than PRICE_MAX_DEVIATION percent is not recorded but listed under rejected, unless it sets allow_large_move.
 // @Tags        pipeline
 // @Accept      json
 // @Produce     json
 // @Security    ApiKeyAuth
 // @Param       request body RecordPricesRequest true "Price entries"
-// @Success     200 {object} map[string]int "Prices recorded count"
+// @Success     200 {object} services.RecordPricesResult "Prices recorded count and rejected entries"
 // @Failure     400 {object} ErrorResponse "Invalid input"
 // @Failure     401 {object} ErrorResponse "Invalid API key"
 // @Failure     503 {object} ErrorResponse "Pipeline not configured"
@@ -192,19 +299,22 @@ func (h *SecurityHandler) RecordPrices(c *gin.Context) {
 	inputs := make([]services.SecurityPriceInput, len(req.Prices))
 	for i, p := range req.Prices {
 		inputs[i] = services.SecurityPriceInput{
-			SecurityID: p.SecurityID,
-			Price:      p.Price,
-			RecordedAt: p.RecordedAt,
+			SecurityID:     p.SecurityID,
+			Price:          p.Price,
+			RecordedAt:     p.RecordedAt,
+			Source:         models.PriceSource(p.Source),
+			Currency:       p.Currency,
+			AllowLargeMove: p.AllowLargeMove,
 		}
 	}
 
-	count, err := h.securityService.RecordPrices(inputs)
+	result, err := h.securityService.RecordPrices(inputs)
 	if err != nil {
 		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"prices_recorded": count})
+	c.JSON(http.StatusOK, result)
 }
 
 // GetPriceHistory handles retrieving price history for a security.
@@ -215,10 +325,11 @@ func (h *SecurityHandler) RecordPrices(c *gin.Context) {
 // @Produce     json
 // @Security    BearerAuth
 // @Param       id        path  int    true "Security ID"
-// @Param       from_date query string true "Start date (RFC3339 or YYYY-MM-DD)"
-// @Param       to_date   query string true "End date (RFC3339 or YYYY-MM-DD)"
+// @Param       from_date query string true "Start date (RFC3339, YYYY-MM-DD or unix seconds)"
+// @Param       to_date   query string true "End date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)"
 // @Param       page      query int    false "Page number (default 1)"
 // @Param       page_size query int    false "Items per page (default 20, max 100)"
+// @Param       with_total query bool   false "Compute total_items and total_pages (default true); when false they are -1 and 0"
 // @Success     200 {object} pagination.PageResponse[models.SecurityPrice] "Paginated prices"
 // @Failure     400 {object} ErrorResponse "Invalid input"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
@@ -235,9 +346,9 @@ func (h *SecurityHandler) GetPriceHistory(c *gin.Context) {
 		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "from_date is required"))
 		return
 	}
-	from, err := parseFlexibleTime(fromStr)
+	from, err := parseDateParam("from_date", fromStr, rangeStart, time.UTC)
 	if err != nil {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+		respondWithError(c, err)
 		return
 	}
 
@@ -246,15 +357,15 @@ func (h *SecurityHandler) GetPriceHistory(c *gin.Context) {
 		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "to_date is required"))
 		return
 	}
-	to, err := parseFlexibleTime(toStr)
+	to, err := parseDateParam("to_date", toStr, rangeEnd, time.UTC)
 	if err != nil {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+		respondWithError(c, err)
 		return
 	}
 
-	var page pagination.PageRequest
-	if err := c.ShouldBindQuery(&page); err != nil {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+	page, err := bindPage(c)
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
@@ -264,9 +375,112 @@ func (h *SecurityHandler) GetPriceHistory(c *gin.Context) {
 		return
 	}
 
+	respondWithPage(c, page, result)
+}
+
+// CorrectPriceRequest represents the request payload for correcting a recorded
+// price: either a replacement price or delete.
+type CorrectPriceRequest struct {
+	Price  int64 `json:"price" binding:"omitempty,gt=0"`
+	Delete bool  `json:"delete"`
+}
+
+// CorrectPrice handles correcting or deleting a single recorded price.
+// @Summary     Correct a recorded price
+// @Description Replace a recorded price (its source becomes manual) or, with delete, soft-delete it so that price history and valuations skip it (pipeline endpoint). The deviation check does not apply. Snapshots are not revalued; call POST /pipeline/snapshots/recompute afterwards. Corrections are audit-logged with the old and new values.
+// @Tags        pipeline
+// @Accept      json
+// @Produce     json
+// @Security    ApiKeyAuth
+// @Param       id      path string              true "Security ID"
+// @Param       priceId path string              true "Price ID"
+// @Param       request body CorrectPriceRequest true "Replacement price or delete"
+// @Success     200 {object} services.PriceCorrectionResult "Price before and after the correction"
+// @Failure     400 {object} ErrorResponse "Invalid input"
+// @Failure     401 {object} ErrorResponse "Invalid API key"
+// @Failure     404 {object} ErrorResponse "Price not found"
+// @Failure     503 {object} ErrorResponse "Pipeline not configured"
+// @Router      /pipeline/securities/{id}/prices/{priceId} [put]
+func (h *SecurityHandler) CorrectPrice(c *gin.Context) {
+	securityID, err := parsePathID(c, "id")
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	priceID, err := parsePathID(c, "priceId")
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	var req CorrectPriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+		return
+	}
+	if req.Delete == (req.Price != 0) {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "provide either price or delete"))
+		return
+	}
+
+	result, err := h.securityService.CorrectPrice(securityID, priceID, services.PriceCorrection{Price: req.Price, Delete: req.Delete})
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	changes := map[string]interface{}{
+		"security_id": securityID,
+		"recorded_at": result.Previous.RecordedAt,
+		"old_price":   result.Previous.Price,
+		"old_source":  result.Previous.Source,
+		"deleted":     req.Delete,
+	}
+	if result.Current != nil {
+		changes["new_price"] = result.Current.Price
+	}
+	if keyID := c.GetString("pipelineKeyID"); keyID != "" {
+		changes["pipeline_key_id"] = keyID
+	}
+	h.auditService.Log("", "CORRECT_SECURITY_PRICE", "security_price", priceID, c.ClientIP(), changes)
+
 	c.JSON(http.StatusOK, result)
 }
 
+// parseSecurityFilter reads the securities list filters from the query string,
+// rejecting an asset type or currency that is not known.
+func parseSecurityFilter(c *gin.Context) (services.SecurityFilter, error) {
+	filter := services.SecurityFilter{Search: c.Query("search")}
+
+	if v := c.Query("asset_type"); v != "" {
+		assetType := models.AssetType(strings.ToLower(v))
+		if !slices.Contains(models.AssetTypes, assetType) {
+			allowed := make([]string, len(models.AssetTypes))
+			for i, t := range models.AssetTypes {
+				allowed[i] = string(t)
+			}
+			return filter, apperrors.WithMessage(apperrors.ErrInvalidInput,
+				"invalid asset_type, must be one of "+strings.Join(allowed, ", "))
+		}
+		filter.AssetType = &assetType
+	}
+
+	if v := strings.TrimSpace(c.Query("exchange")); v != "" {
+		filter.Exchange = &v
+	}
+
+	if v := c.Query("currency"); v != "" {
+		currency := strings.ToUpper(v)
+		if !validator.IsISO4217(currency) {
+			return filter, apperrors.WithMessage(apperrors.ErrInvalidInput,
+				"invalid currency, must be an ISO 4217 code such as USD, EUR, or MYR")
+		}
+		filter.Currency = &currency
+	}
+
+	return filter, nil
+}
+
 // buildSecurityExtraFields extracts asset-type-specific fields from the request into a map.
 func buildSecurityExtraFields(req CreateSecurityRequest) map[string]interface{} {
 	fields := make(map[string]interface{})
@@ -288,5 +502,8 @@ func buildSecurityExtraFields(req CreateSecurityRequest) map[string]interface{}
 	if req.ProviderSymbol != "" {
 		fields["provider_symbol"] = req.ProviderSymbol
 	}
+	if req.PreferredProvider != "" {
+		fields["preferred_provider"] = req.PreferredProvider
+	}
 	return fields
 }
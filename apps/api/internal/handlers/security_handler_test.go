@@ -3,6 +3,7 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,12 +18,15 @@ import (
 // --- mock security service ---
 
 type mockSecurityService struct {
-	createSecurityFn    func(symbol, name string, assetType models.AssetType, currency, exchange string, extraFields map[string]interface{}) (*models.Security, error)
-	getSecurityByIDFn   func(id uint) (*models.Security, error)
-	listSecuritiesFn    func(search string, page pagination.PageRequest) (*pagination.PageResponse[models.Security], error)
-	listAllSecuritiesFn func() ([]models.Security, error)
-	recordPricesFn      func(prices []services.SecurityPriceInput) (int, error)
-	getPriceHistoryFn   func(securityID uint, from, to time.Time, page pagination.PageRequest) (*pagination.PageResponse[models.SecurityPrice], error)
+	createSecurityFn             func(symbol, name string, assetType models.AssetType, currency, exchange string, extraFields map[string]interface{}) (*models.Security, error)
+	upsertSecuritiesFn           func(inputs []services.SecurityInput) (*services.SecurityBatchResult, error)
+	getSecurityByIDFn            func(id string) (*models.Security, error)
+	listSecuritiesFn             func(filter services.SecurityFilter, page pagination.PageRequest) (*pagination.PageResponse[models.Security], error)
+	listSecuritiesWithHoldingsFn func(userID string, filter services.SecurityFilter, page pagination.PageRequest, ownedOnly bool) (*pagination.PageResponse[services.SecurityWithHolding], error)
+	listAllSecuritiesFn          func() ([]models.Security, error)
+	recordPricesFn               func(prices []services.SecurityPriceInput) (*services.RecordPricesResult, error)
+	getPriceHistoryFn            func(securityID string, from, to time.Time, page pagination.PageRequest) (*pagination.PageResponse[models.SecurityPrice], error)
+	correctPriceFn               func(securityID, priceID string, correction services.PriceCorrection) (*services.PriceCorrectionResult, error)
 }
 
 var _ services.SecurityServicer = (*mockSecurityService)(nil)
@@ -34,13 +38,28 @@ func (m *mockSecurityService) CreateSecurity(symbol, name string, assetType mode
 	return &models.Security{}, nil
 }
 
-func (m *mockSecurityService) GetSecurityByID(id uint) (*models.Security, error) {
+func (m *mockSecurityService) UpsertSecurities(inputs []services.SecurityInput) (*services.SecurityBatchResult, error) {
+	if m.upsertSecuritiesFn != nil {
+		return m.upsertSecuritiesFn(inputs)
+	}
+	return &services.SecurityBatchResult{Items: []services.SecurityBatchItem{}}, nil
+}
+
+func (m *mockSecurityService) GetSecurityByID(id string) (*models.Security, error) {
 	if m.getSecurityByIDFn != nil {
 		return m.getSecurityByIDFn(id)
 	}
 	return &models.Security{}, nil
 }
 
+func (m *mockSecurityService) ListSecuritiesWithHoldings(userID string, filter services.SecurityFilter, page pagination.PageRequest, ownedOnly bool) (*pagination.PageResponse[services.SecurityWithHolding], error) {
+	if m.listSecuritiesWithHoldingsFn != nil {
+		return m.listSecuritiesWithHoldingsFn(userID, filter, page, ownedOnly)
+	}
+	resp := pagination.NewPageResponse([]services.SecurityWithHolding{}, page.Page, page.PageSize, 0)
+	return &resp, nil
+}
+
 func (m *mockSecurityService) ListAllSecurities() ([]models.Security, error) {
 	if m.listAllSecuritiesFn != nil {
 		return m.listAllSecuritiesFn()
@@ -48,29 +67,36 @@ func (m *mockSecurityService) ListAllSecurities() ([]models.Security, error) {
 	return []models.Security{}, nil
 }
 
-func (m *mockSecurityService) ListSecurities(search string, page pagination.PageRequest) (*pagination.PageResponse[models.Security], error) {
+func (m *mockSecurityService) ListSecurities(filter services.SecurityFilter, page pagination.PageRequest) (*pagination.PageResponse[models.Security], error) {
 	if m.listSecuritiesFn != nil {
-		return m.listSecuritiesFn(search, page)
+		return m.listSecuritiesFn(filter, page)
 	}
-	resp := pagination.NewPageResponse([]models.Security{}, 1, 20, 0)
+	resp := pagination.NewPageResponse([]models.Security{}, page.Page, page.PageSize, 0)
 	return &resp, nil
 }
 
-func (m *mockSecurityService) RecordPrices(prices []services.SecurityPriceInput) (int, error) {
+func (m *mockSecurityService) RecordPrices(prices []services.SecurityPriceInput) (*services.RecordPricesResult, error) {
 	if m.recordPricesFn != nil {
 		return m.recordPricesFn(prices)
 	}
-	return 0, nil
+	return &services.RecordPricesResult{}, nil
 }
 
-func (m *mockSecurityService) GetPriceHistory(securityID uint, from, to time.Time, page pagination.PageRequest) (*pagination.PageResponse[models.SecurityPrice], error) {
+func (m *mockSecurityService) GetPriceHistory(securityID string, from, to time.Time, page pagination.PageRequest) (*pagination.PageResponse[models.SecurityPrice], error) {
 	if m.getPriceHistoryFn != nil {
 		return m.getPriceHistoryFn(securityID, from, to, page)
 	}
-	resp := pagination.NewPageResponse([]models.SecurityPrice{}, 1, 20, 0)
+	resp := pagination.NewPageResponse([]models.SecurityPrice{}, page.Page, page.PageSize, 0)
 	return &resp, nil
 }
 
+func (m *mockSecurityService) CorrectPrice(securityID, priceID string, correction services.PriceCorrection) (*services.PriceCorrectionResult, error) {
+	if m.correctPriceFn != nil {
+		return m.correctPriceFn(securityID, priceID, correction)
+	}
+	return &services.PriceCorrectionResult{}, nil
+}
+
 // --- router setup ---
 
 func setupSecurityRouter(handler *SecurityHandler) *gin.Engine {
@@ -78,9 +104,11 @@ func setupSecurityRouter(handler *SecurityHandler) *gin.Engine {
 	// Pipeline routes (no auth needed for handler tests)
 	r.GET("/pipeline/securities", handler.ListAllSecurities)
 	r.POST("/pipeline/securities", handler.CreateSecurity)
+	r.POST("/pipeline/securities/batch", handler.CreateSecuritiesBatch)
 	r.POST("/pipeline/securities/prices", handler.RecordPrices)
+	r.PUT("/pipeline/securities/:id/prices/:priceId", handler.CorrectPrice)
 	// User routes (with auth)
-	auth := r.Group("", injectUserID(1))
+	auth := r.Group("", injectUserID(testID(1)))
 	auth.GET("/securities", handler.ListSecurities)
 	auth.GET("/securities/:id", handler.GetSecurity)
 	auth.GET("/securities/:id/prices", handler.GetPriceHistory)
@@ -94,7 +122,7 @@ func TestSecurityHandler_CreateSecurity(t *testing.T) {
 		svc := &mockSecurityService{
 			createSecurityFn: func(symbol, name string, assetType models.AssetType, currency, exchange string, _ map[string]interface{}) (*models.Security, error) {
 				return &models.Security{
-					Base:      models.Base{ID: 1},
+					Base:      models.Base{ID: testID(1)},
 					Symbol:    symbol,
 					Name:      name,
 					AssetType: assetType,
@@ -167,7 +195,7 @@ func TestSecurityHandler_CreateSecurity(t *testing.T) {
 			createSecurityFn: func(symbol, name string, assetType models.AssetType, currency, exchange string, extraFields map[string]interface{}) (*models.Security, error) {
 				capturedExtraFields = extraFields
 				return &models.Security{
-					Base:           models.Base{ID: 1},
+					Base:           models.Base{ID: testID(1)},
 					Symbol:         symbol,
 					Name:           name,
 					AssetType:      assetType,
@@ -220,8 +248,8 @@ func TestSecurityHandler_ListAllSecurities(t *testing.T) {
 		svc := &mockSecurityService{
 			listAllSecuritiesFn: func() ([]models.Security, error) {
 				return []models.Security{
-					{Base: models.Base{ID: 1}, Symbol: "AAPL", Name: "Apple Inc.", AssetType: models.AssetTypeStock, Currency: "USD", Exchange: "NASDAQ"},
-					{Base: models.Base{ID: 7}, Symbol: "BTC", Name: "Bitcoin", AssetType: models.AssetTypeCrypto, Currency: "USD", Network: "bitcoin"},
+					{Base: models.Base{ID: testID(1)}, Symbol: "AAPL", Name: "Apple Inc.", AssetType: models.AssetTypeStock, Currency: "USD", Exchange: "NASDAQ"},
+					{Base: models.Base{ID: testID(7)}, Symbol: "BTC", Name: "Bitcoin", AssetType: models.AssetTypeCrypto, Currency: "USD", Network: "bitcoin"},
 				}, nil
 			},
 		}
@@ -289,10 +317,10 @@ func TestSecurityHandler_ListAllSecurities(t *testing.T) {
 func TestSecurityHandler_ListSecurities(t *testing.T) {
 	t.Run("returns_200_with_data", func(t *testing.T) {
 		svc := &mockSecurityService{
-			listSecuritiesFn: func(_ string, _ pagination.PageRequest) (*pagination.PageResponse[models.Security], error) {
-				resp := pagination.NewPageResponse([]models.Security{
-					{Base: models.Base{ID: 1}, Symbol: "AAPL", Name: "Apple Inc.", AssetType: models.AssetTypeStock},
-					{Base: models.Base{ID: 2}, Symbol: "GOOGL", Name: "Alphabet Inc.", AssetType: models.AssetTypeStock},
+			listSecuritiesWithHoldingsFn: func(_ string, _ services.SecurityFilter, _ pagination.PageRequest, _ bool) (*pagination.PageResponse[services.SecurityWithHolding], error) {
+				resp := pagination.NewPageResponse([]services.SecurityWithHolding{
+					{Security: models.Security{Base: models.Base{ID: testID(1)}, Symbol: "AAPL", Name: "Apple Inc.", AssetType: models.AssetTypeStock}},
+					{Security: models.Security{Base: models.Base{ID: testID(2)}, Symbol: "GOOGL", Name: "Alphabet Inc.", AssetType: models.AssetTypeStock}},
 				}, 1, 20, 2)
 				return &resp, nil
 			},
@@ -318,9 +346,9 @@ func TestSecurityHandler_ListSecurities(t *testing.T) {
 	t.Run("returns_200_with_pagination_params", func(t *testing.T) {
 		var capturedPage pagination.PageRequest
 		svc := &mockSecurityService{
-			listSecuritiesFn: func(_ string, page pagination.PageRequest) (*pagination.PageResponse[models.Security], error) {
+			listSecuritiesWithHoldingsFn: func(_ string, _ services.SecurityFilter, page pagination.PageRequest, _ bool) (*pagination.PageResponse[services.SecurityWithHolding], error) {
 				capturedPage = page
-				resp := pagination.NewPageResponse([]models.Security{}, 2, 5, 10)
+				resp := pagination.NewPageResponse([]services.SecurityWithHolding{}, 2, 5, 10)
 				return &resp, nil
 			},
 		}
@@ -343,9 +371,9 @@ func TestSecurityHandler_ListSecurities(t *testing.T) {
 	t.Run("passes_search_to_service", func(t *testing.T) {
 		var capturedSearch string
 		svc := &mockSecurityService{
-			listSecuritiesFn: func(search string, _ pagination.PageRequest) (*pagination.PageResponse[models.Security], error) {
-				capturedSearch = search
-				resp := pagination.NewPageResponse([]models.Security{}, 1, 20, 0)
+			listSecuritiesWithHoldingsFn: func(_ string, filter services.SecurityFilter, _ pagination.PageRequest, _ bool) (*pagination.PageResponse[services.SecurityWithHolding], error) {
+				capturedSearch = filter.Search
+				resp := pagination.NewPageResponse([]services.SecurityWithHolding{}, 1, 20, 0)
 				return &resp, nil
 			},
 		}
@@ -361,12 +389,157 @@ func TestSecurityHandler_ListSecurities(t *testing.T) {
 			t.Errorf("expected search='aapl', got '%s'", capturedSearch)
 		}
 	})
+
+	t.Run("passes_user_and_owned_filter_to_service", func(t *testing.T) {
+		var capturedUser string
+		var capturedOwned bool
+		svc := &mockSecurityService{
+			listSecuritiesWithHoldingsFn: func(userID string, _ services.SecurityFilter, _ pagination.PageRequest, ownedOnly bool) (*pagination.PageResponse[services.SecurityWithHolding], error) {
+				capturedUser, capturedOwned = userID, ownedOnly
+				resp := pagination.NewPageResponse([]services.SecurityWithHolding{
+					{
+						Security: models.Security{Base: models.Base{ID: testID(1)}, Symbol: "AAPL"},
+						Holding:  &services.SecurityHolding{Quantity: 12, CostBasis: 120000, CurrentValue: 180000, GainLoss: 60000},
+					},
+				}, 1, 20, 1)
+				return &resp, nil
+			},
+		}
+		handler := NewSecurityHandler(svc, &mockAuditService{})
+		r := setupSecurityRouter(handler)
+
+		rec := doRequest(r, "GET", "/securities?owned=true", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if capturedUser != testID(1) || !capturedOwned {
+			t.Errorf("expected user %s with owned=true, got %s owned=%v", testID(1), capturedUser, capturedOwned)
+		}
+		item := parseJSON(t, rec)["data"].([]interface{})[0].(map[string]interface{})
+		if item["symbol"] != "AAPL" {
+			t.Errorf("expected security fields inline, got %v", item)
+		}
+		holding := item["holding"].(map[string]interface{})
+		if holding["quantity"].(float64) != 12 || holding["gain_loss"].(float64) != 60000 {
+			t.Errorf("unexpected holding: %v", holding)
+		}
+	})
+
+	t.Run("returns_400_on_invalid_owned", func(t *testing.T) {
+		handler := NewSecurityHandler(&mockSecurityService{}, &mockAuditService{})
+		r := setupSecurityRouter(handler)
+
+		rec := doRequest(r, "GET", "/securities?owned=maybe", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	captureFilter := func(captured *services.SecurityFilter) *mockSecurityService {
+		return &mockSecurityService{
+			listSecuritiesWithHoldingsFn: func(_ string, filter services.SecurityFilter, _ pagination.PageRequest, _ bool) (*pagination.PageResponse[services.SecurityWithHolding], error) {
+				*captured = filter
+				resp := pagination.NewPageResponse([]services.SecurityWithHolding{}, 1, 20, 0)
+				return &resp, nil
+			},
+		}
+	}
+
+	t.Run("passes_asset_type_filter", func(t *testing.T) {
+		var captured services.SecurityFilter
+		r := setupSecurityRouter(NewSecurityHandler(captureFilter(&captured), &mockAuditService{}))
+
+		rec := doRequest(r, "GET", "/securities?asset_type=Crypto", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if captured.AssetType == nil || *captured.AssetType != models.AssetTypeCrypto {
+			t.Errorf("expected asset_type=crypto, got %v", captured.AssetType)
+		}
+		if captured.Exchange != nil || captured.Currency != nil {
+			t.Errorf("expected no other filters, got %+v", captured)
+		}
+	})
+
+	t.Run("passes_exchange_filter", func(t *testing.T) {
+		var captured services.SecurityFilter
+		r := setupSecurityRouter(NewSecurityHandler(captureFilter(&captured), &mockAuditService{}))
+
+		rec := doRequest(r, "GET", "/securities?exchange=NASDAQ", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if captured.Exchange == nil || *captured.Exchange != "NASDAQ" {
+			t.Errorf("expected exchange=NASDAQ, got %v", captured.Exchange)
+		}
+	})
+
+	t.Run("passes_currency_filter_uppercased", func(t *testing.T) {
+		var captured services.SecurityFilter
+		r := setupSecurityRouter(NewSecurityHandler(captureFilter(&captured), &mockAuditService{}))
+
+		rec := doRequest(r, "GET", "/securities?currency=myr", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if captured.Currency == nil || *captured.Currency != "MYR" {
+			t.Errorf("expected currency=MYR, got %v", captured.Currency)
+		}
+	})
+
+	t.Run("combines_filters", func(t *testing.T) {
+		var captured services.SecurityFilter
+		r := setupSecurityRouter(NewSecurityHandler(captureFilter(&captured), &mockAuditService{}))
+
+		rec := doRequest(r, "GET", "/securities?search=bank&asset_type=stock&exchange=MYX&currency=MYR&owned=true", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if captured.Search != "bank" || captured.AssetType == nil || *captured.AssetType != models.AssetTypeStock ||
+			captured.Exchange == nil || *captured.Exchange != "MYX" || captured.Currency == nil || *captured.Currency != "MYR" {
+			t.Errorf("expected every filter passed through, got %+v", captured)
+		}
+	})
+
+	t.Run("returns_400_listing_allowed_asset_types", func(t *testing.T) {
+		r := setupSecurityRouter(NewSecurityHandler(&mockSecurityService{}, &mockAuditService{}))
+
+		rec := doRequest(r, "GET", "/securities?asset_type=option", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		result := parseJSON(t, rec)
+		assertErrorCode(t, result, "INVALID_INPUT")
+		msg := result["error"].(map[string]interface{})["message"].(string)
+		if !strings.Contains(msg, "stock, etf, bond, crypto, reit") {
+			t.Errorf("expected the allowed asset types in the message, got %q", msg)
+		}
+	})
+
+	t.Run("returns_400_on_unknown_currency", func(t *testing.T) {
+		r := setupSecurityRouter(NewSecurityHandler(&mockSecurityService{}, &mockAuditService{}))
+
+		rec := doRequest(r, "GET", "/securities?currency=DOGE", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
 }
 
 func TestSecurityHandler_GetSecurity(t *testing.T) {
 	t.Run("returns_200_on_success", func(t *testing.T) {
 		svc := &mockSecurityService{
-			getSecurityByIDFn: func(id uint) (*models.Security, error) {
+			getSecurityByIDFn: func(id string) (*models.Security, error) {
 				return &models.Security{
 					Base:      models.Base{ID: id},
 					Symbol:    "AAPL",
@@ -380,7 +553,7 @@ func TestSecurityHandler_GetSecurity(t *testing.T) {
 		handler := NewSecurityHandler(svc, &mockAuditService{})
 		r := setupSecurityRouter(handler)
 
-		rec := doRequest(r, "GET", "/securities/1", "")
+		rec := doRequest(r, "GET", "/securities/00000000-0000-7000-8000-000000000001", "")
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
@@ -397,14 +570,14 @@ func TestSecurityHandler_GetSecurity(t *testing.T) {
 
 	t.Run("returns_404_not_found", func(t *testing.T) {
 		svc := &mockSecurityService{
-			getSecurityByIDFn: func(_ uint) (*models.Security, error) {
+			getSecurityByIDFn: func(_ string) (*models.Security, error) {
 				return nil, apperrors.ErrSecurityNotFound
 			},
 		}
 		handler := NewSecurityHandler(svc, &mockAuditService{})
 		r := setupSecurityRouter(handler)
 
-		rec := doRequest(r, "GET", "/securities/999", "")
+		rec := doRequest(r, "GET", "/securities/00000000-0000-7000-8000-000000000999", "")
 
 		if rec.Code != http.StatusNotFound {
 			t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body.String())
@@ -424,18 +597,93 @@ func TestSecurityHandler_GetSecurity(t *testing.T) {
 	})
 }
 
+func TestSecurityHandler_CreateSecuritiesBatch(t *testing.T) {
+	t.Run("passes_every_entry_and_returns_per_item_status", func(t *testing.T) {
+		var got []services.SecurityInput
+		svc := &mockSecurityService{
+			upsertSecuritiesFn: func(inputs []services.SecurityInput) (*services.SecurityBatchResult, error) {
+				got = inputs
+				return &services.SecurityBatchResult{Created: 1, Updated: 1, Failed: 1, Items: []services.SecurityBatchItem{
+					{Index: 0, Symbol: "AAPL", Status: services.SecurityBatchCreated, SecurityID: testID(1)},
+					{Index: 1, Symbol: "AAPL", Status: services.SecurityBatchUpdated, SecurityID: testID(1)},
+					{Index: 2, Symbol: "GOLD", Status: services.SecurityBatchFailed, Error: `invalid asset_type "commodity"`},
+				}}, nil
+			},
+		}
+		r := setupSecurityRouter(NewSecurityHandler(svc, &mockAuditService{}))
+
+		rec := doRequest(r, "POST", "/pipeline/securities/batch", `{"securities":[
+			{"symbol":"AAPL","name":"Apple","asset_type":"stock","exchange":"NASDAQ"},
+			{"symbol":"AAPL","name":"Apple Inc","asset_type":"stock","exchange":"NASDAQ"},
+			{"symbol":"GOLD","name":"Gold","asset_type":"commodity","network":"n/a"}]}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if len(got) != 3 || got[1].Name != "Apple Inc" || got[2].AssetType != "commodity" || got[2].ExtraFields["network"] != "n/a" {
+			t.Errorf("expected all three entries passed through, got %+v", got)
+		}
+		result := parseJSON(t, rec)
+		if result["created"] != float64(1) || result["updated"] != float64(1) || result["failed"] != float64(1) {
+			t.Errorf("expected the counts, got %v", result)
+		}
+		items := result["items"].([]interface{})
+		if failed := items[2].(map[string]interface{}); failed["status"] != "failed" || failed["error"] == "" {
+			t.Errorf("expected the failed entry with its reason, got %v", failed)
+		}
+	})
+
+	t.Run("rejects_an_empty_batch", func(t *testing.T) {
+		r := setupSecurityRouter(NewSecurityHandler(&mockSecurityService{}, &mockAuditService{}))
+
+		rec := doRequest(r, "POST", "/pipeline/securities/batch", `{"securities":[]}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("rejects_a_batch_over_the_configured_size", func(t *testing.T) {
+		called := false
+		svc := &mockSecurityService{
+			upsertSecuritiesFn: func(_ []services.SecurityInput) (*services.SecurityBatchResult, error) {
+				called = true
+				return &services.SecurityBatchResult{}, nil
+			},
+		}
+		r := setupSecurityRouter(NewSecurityHandler(svc, &mockAuditService{}).WithMaxBatchSize(2))
+		entry := `{"symbol":"AAPL","name":"Apple","asset_type":"stock"}`
+
+		rec := doRequest(r, "POST", "/pipeline/securities/batch", `{"securities":[`+entry+`,`+entry+`,`+entry+`]}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+		if called {
+			t.Error("expected the service not to be called")
+		}
+
+		rec = doRequest(r, "POST", "/pipeline/securities/batch", `{"securities":[`+entry+`,`+entry+`]}`)
+		if rec.Code != http.StatusOK || !called {
+			t.Fatalf("expected a batch at the limit to pass, got %d", rec.Code)
+		}
+	})
+}
+
 func TestSecurityHandler_RecordPrices(t *testing.T) {
 	t.Run("returns_200_on_success", func(t *testing.T) {
 		svc := &mockSecurityService{
-			recordPricesFn: func(prices []services.SecurityPriceInput) (int, error) {
-				return len(prices), nil
+			recordPricesFn: func(prices []services.SecurityPriceInput) (*services.RecordPricesResult, error) {
+				return &services.RecordPricesResult{Recorded: len(prices)}, nil
 			},
 		}
 		handler := NewSecurityHandler(svc, &mockAuditService{})
 		r := setupSecurityRouter(handler)
 
 		rec := doRequest(r, "POST", "/pipeline/securities/prices",
-			`{"prices":[{"security_id":1,"price":17500,"recorded_at":"2026-02-09T12:00:00Z"},{"security_id":2,"price":4200,"recorded_at":"2026-02-09T12:00:00Z"}]}`)
+			`{"prices":[{"security_id":"00000000-0000-7000-8000-000000000001","price":17500,"recorded_at":"2026-02-09T12:00:00Z"},{"security_id":"00000000-0000-7000-8000-000000000002","price":4200,"recorded_at":"2026-02-09T12:00:00Z"}]}`)
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
@@ -446,6 +694,100 @@ func TestSecurityHandler_RecordPrices(t *testing.T) {
 		}
 	})
 
+	t.Run("passes_source", func(t *testing.T) {
+		var got []services.SecurityPriceInput
+		svc := &mockSecurityService{
+			recordPricesFn: func(prices []services.SecurityPriceInput) (*services.RecordPricesResult, error) {
+				got = prices
+				return &services.RecordPricesResult{Recorded: len(prices)}, nil
+			},
+		}
+		handler := NewSecurityHandler(svc, &mockAuditService{})
+		r := setupSecurityRouter(handler)
+
+		rec := doRequest(r, "POST", "/pipeline/securities/prices",
+			`{"prices":[{"security_id":"00000000-0000-7000-8000-000000000001","price":17500,"recorded_at":"2026-02-09T12:00:00Z","source":"coingecko"}]}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if len(got) != 1 || got[0].Source != models.PriceSourceCoinGecko {
+			t.Errorf("expected source coingecko, got %+v", got)
+		}
+	})
+
+	t.Run("passes_currency", func(t *testing.T) {
+		var got []services.SecurityPriceInput
+		svc := &mockSecurityService{
+			recordPricesFn: func(prices []services.SecurityPriceInput) (*services.RecordPricesResult, error) {
+				got = prices
+				return &services.RecordPricesResult{Recorded: len(prices)}, nil
+			},
+		}
+		r := setupSecurityRouter(NewSecurityHandler(svc, &mockAuditService{}))
+
+		rec := doRequest(r, "POST", "/pipeline/securities/prices",
+			`{"prices":[{"security_id":"00000000-0000-7000-8000-000000000001","price":78000,"recorded_at":"2026-02-09T12:00:00Z","currency":"MYR"}]}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if len(got) != 1 || got[0].Currency != "MYR" {
+			t.Errorf("expected currency MYR, got %+v", got)
+		}
+
+		rec = doRequest(r, "POST", "/pipeline/securities/prices",
+			`{"prices":[{"security_id":"00000000-0000-7000-8000-000000000001","price":78000,"recorded_at":"2026-02-09T12:00:00Z","currency":"XYZ"}]}`)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for an unknown currency, got %d", rec.Code)
+		}
+	})
+
+	t.Run("passes_allow_large_move_and_lists_rejected", func(t *testing.T) {
+		var got []services.SecurityPriceInput
+		svc := &mockSecurityService{
+			recordPricesFn: func(prices []services.SecurityPriceInput) (*services.RecordPricesResult, error) {
+				got = prices
+				return &services.RecordPricesResult{Recorded: 1, Rejected: []services.RejectedPrice{
+					{SecurityID: prices[1].SecurityID, Price: prices[1].Price, LastPrice: 17500, DeviationPct: 9900, Reason: "too far"},
+				}}, nil
+			},
+		}
+		handler := NewSecurityHandler(svc, &mockAuditService{})
+		r := setupSecurityRouter(handler)
+
+		rec := doRequest(r, "POST", "/pipeline/securities/prices",
+			`{"prices":[{"security_id":"00000000-0000-7000-8000-000000000001","price":35000,"recorded_at":"2026-02-09T12:00:00Z","allow_large_move":true},{"security_id":"00000000-0000-7000-8000-000000000002","price":1750000,"recorded_at":"2026-02-09T12:00:00Z"}]}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if len(got) != 2 || !got[0].AllowLargeMove || got[1].AllowLargeMove {
+			t.Errorf("expected allow_large_move only on the first entry, got %+v", got)
+		}
+		result := parseJSON(t, rec)
+		rejected := result["rejected"].([]interface{})
+		if result["prices_recorded"].(float64) != 1 || len(rejected) != 1 {
+			t.Fatalf("expected 1 recorded and 1 rejected, got %v", result)
+		}
+		if entry := rejected[0].(map[string]interface{}); entry["security_id"] != "00000000-0000-7000-8000-000000000002" || entry["last_price"].(float64) != 17500 {
+			t.Errorf("unexpected rejected entry: %v", entry)
+		}
+	})
+
+	t.Run("returns_400_unknown_source", func(t *testing.T) {
+		handler := NewSecurityHandler(&mockSecurityService{}, &mockAuditService{})
+		r := setupSecurityRouter(handler)
+
+		rec := doRequest(r, "POST", "/pipeline/securities/prices",
+			`{"prices":[{"security_id":"00000000-0000-7000-8000-000000000001","price":17500,"recorded_at":"2026-02-09T12:00:00Z","source":"bloomberg"}]}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
 	t.Run("returns_400_empty_prices", func(t *testing.T) {
 		handler := NewSecurityHandler(&mockSecurityService{}, &mockAuditService{})
 		r := setupSecurityRouter(handler)
@@ -464,7 +806,7 @@ func TestSecurityHandler_RecordPrices(t *testing.T) {
 		r := setupSecurityRouter(handler)
 
 		rec := doRequest(r, "POST", "/pipeline/securities/prices",
-			`{"prices":[{"security_id":1,"price":0,"recorded_at":"2026-02-09T12:00:00Z"}]}`)
+			`{"prices":[{"security_id":"00000000-0000-7000-8000-000000000001","price":0,"recorded_at":"2026-02-09T12:00:00Z"}]}`)
 
 		if rec.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
@@ -486,15 +828,15 @@ func TestSecurityHandler_RecordPrices(t *testing.T) {
 
 	t.Run("returns_500_on_service_error", func(t *testing.T) {
 		svc := &mockSecurityService{
-			recordPricesFn: func(_ []services.SecurityPriceInput) (int, error) {
-				return 0, fmt.Errorf("database error")
+			recordPricesFn: func(_ []services.SecurityPriceInput) (*services.RecordPricesResult, error) {
+				return nil, fmt.Errorf("database error")
 			},
 		}
 		handler := NewSecurityHandler(svc, &mockAuditService{})
 		r := setupSecurityRouter(handler)
 
 		rec := doRequest(r, "POST", "/pipeline/securities/prices",
-			`{"prices":[{"security_id":1,"price":17500,"recorded_at":"2026-02-09T12:00:00Z"}]}`)
+			`{"prices":[{"security_id":"00000000-0000-7000-8000-000000000001","price":17500,"recorded_at":"2026-02-09T12:00:00Z"}]}`)
 
 		if rec.Code != http.StatusInternalServerError {
 			t.Fatalf("expected 500, got %d: %s", rec.Code, rec.Body.String())
@@ -506,10 +848,10 @@ func TestSecurityHandler_GetPriceHistory(t *testing.T) {
 	t.Run("returns_200_with_data", func(t *testing.T) {
 		now := time.Now().UTC().Truncate(time.Second)
 		svc := &mockSecurityService{
-			getPriceHistoryFn: func(_ uint, _, _ time.Time, _ pagination.PageRequest) (*pagination.PageResponse[models.SecurityPrice], error) {
+			getPriceHistoryFn: func(_ string, _, _ time.Time, _ pagination.PageRequest) (*pagination.PageResponse[models.SecurityPrice], error) {
 				resp := pagination.NewPageResponse([]models.SecurityPrice{
-					{ID: 1, SecurityID: 1, Price: 17500, RecordedAt: now},
-					{ID: 2, SecurityID: 1, Price: 17600, RecordedAt: now.Add(-time.Hour)},
+					{ID: testID(1), SecurityID: testID(1), Price: 17500, RecordedAt: now},
+					{ID: testID(2), SecurityID: testID(1), Price: 17600, RecordedAt: now.Add(-time.Hour)},
 				}, 1, 20, 2)
 				return &resp, nil
 			},
@@ -517,7 +859,7 @@ func TestSecurityHandler_GetPriceHistory(t *testing.T) {
 		handler := NewSecurityHandler(svc, &mockAuditService{})
 		r := setupSecurityRouter(handler)
 
-		rec := doRequest(r, "GET", "/securities/1/prices?from_date=2026-01-01&to_date=2026-12-31", "")
+		rec := doRequest(r, "GET", "/securities/00000000-0000-7000-8000-000000000001/prices?from_date=2026-01-01&to_date=2026-12-31", "")
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
@@ -532,11 +874,34 @@ func TestSecurityHandler_GetPriceHistory(t *testing.T) {
 		}
 	})
 
+	t.Run("includes_the_whole_to_date", func(t *testing.T) {
+		var gotFrom, gotTo time.Time
+		svc := &mockSecurityService{
+			getPriceHistoryFn: func(_ string, from, to time.Time, _ pagination.PageRequest) (*pagination.PageResponse[models.SecurityPrice], error) {
+				gotFrom, gotTo = from, to
+				resp := pagination.NewPageResponse([]models.SecurityPrice{}, 1, 20, 0)
+				return &resp, nil
+			},
+		}
+		handler := NewSecurityHandler(svc, &mockAuditService{})
+		r := setupSecurityRouter(handler)
+
+		rec := doRequest(r, "GET", "/securities/00000000-0000-7000-8000-000000000001/prices?from_date=2026-01-01&to_date=2026-01-31", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if !gotFrom.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) ||
+			!gotTo.Equal(time.Date(2026, 1, 31, 23, 59, 59, 999999999, time.UTC)) {
+			t.Errorf("unexpected range %v to %v", gotFrom, gotTo)
+		}
+	})
+
 	t.Run("returns_400_missing_from_date", func(t *testing.T) {
 		handler := NewSecurityHandler(&mockSecurityService{}, &mockAuditService{})
 		r := setupSecurityRouter(handler)
 
-		rec := doRequest(r, "GET", "/securities/1/prices?to_date=2026-12-31", "")
+		rec := doRequest(r, "GET", "/securities/00000000-0000-7000-8000-000000000001/prices?to_date=2026-12-31", "")
 
 		if rec.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
@@ -548,7 +913,7 @@ func TestSecurityHandler_GetPriceHistory(t *testing.T) {
 		handler := NewSecurityHandler(&mockSecurityService{}, &mockAuditService{})
 		r := setupSecurityRouter(handler)
 
-		rec := doRequest(r, "GET", "/securities/1/prices?from_date=2026-01-01", "")
+		rec := doRequest(r, "GET", "/securities/00000000-0000-7000-8000-000000000001/prices?from_date=2026-01-01", "")
 
 		if rec.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
@@ -568,10 +933,10 @@ func TestSecurityHandler_GetPriceHistory(t *testing.T) {
 	})
 
 	t.Run("passes_date_range_and_pagination_to_service", func(t *testing.T) {
-		var capturedSecID uint
+		var capturedSecID string
 		var capturedPage pagination.PageRequest
 		svc := &mockSecurityService{
-			getPriceHistoryFn: func(securityID uint, _, _ time.Time, page pagination.PageRequest) (*pagination.PageResponse[models.SecurityPrice], error) {
+			getPriceHistoryFn: func(securityID string, _, _ time.Time, page pagination.PageRequest) (*pagination.PageResponse[models.SecurityPrice], error) {
 				capturedSecID = securityID
 				capturedPage = page
 				resp := pagination.NewPageResponse([]models.SecurityPrice{}, 3, 10, 25)
@@ -581,13 +946,13 @@ func TestSecurityHandler_GetPriceHistory(t *testing.T) {
 		handler := NewSecurityHandler(svc, &mockAuditService{})
 		r := setupSecurityRouter(handler)
 
-		rec := doRequest(r, "GET", "/securities/5/prices?from_date=2026-01-01&to_date=2026-12-31&page=3&page_size=10", "")
+		rec := doRequest(r, "GET", "/securities/00000000-0000-7000-8000-000000000005/prices?from_date=2026-01-01&to_date=2026-12-31&page=3&page_size=10", "")
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
 		}
-		if capturedSecID != 5 {
-			t.Errorf("expected securityID=5, got %d", capturedSecID)
+		if capturedSecID != testID(5) {
+			t.Errorf("expected securityID=5, got %s", capturedSecID)
 		}
 		if capturedPage.Page != 3 {
 			t.Errorf("expected page=3, got %d", capturedPage.Page)
@@ -597,3 +962,113 @@ func TestSecurityHandler_GetPriceHistory(t *testing.T) {
 		}
 	})
 }
+
+func TestSecurityHandler_CorrectPrice(t *testing.T) {
+	path := fmt.Sprintf("/pipeline/securities/%s/prices/%s", testID(1), testID(2))
+	recordedAt := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	previous := models.SecurityPrice{
+		ID:         testID(2),
+		SecurityID: testID(1),
+		Price:      1000000,
+		Source:     models.PriceSourceYahoo,
+		RecordedAt: recordedAt,
+	}
+
+	t.Run("replaces_price_and_audits_old_and_new", func(t *testing.T) {
+		var captured services.PriceCorrection
+		svc := &mockSecurityService{
+			correctPriceFn: func(securityID, priceID string, correction services.PriceCorrection) (*services.PriceCorrectionResult, error) {
+				captured = correction
+				current := previous
+				current.Price, current.Source = correction.Price, models.PriceSourceManual
+				return &services.PriceCorrectionResult{Previous: previous, Current: &current}, nil
+			},
+		}
+		audit := &recordingAuditService{}
+		r := setupSecurityRouter(NewSecurityHandler(svc, audit))
+
+		rec := doRequest(r, "PUT", path, `{"price":10000}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if captured.Price != 10000 || captured.Delete {
+			t.Errorf("expected price 10000 without delete, got %+v", captured)
+		}
+		result := parseJSON(t, rec)
+		if result["previous"].(map[string]interface{})["price"].(float64) != 1000000 {
+			t.Errorf("expected previous price 1000000, got %v", result["previous"])
+		}
+		if result["current"].(map[string]interface{})["price"].(float64) != 10000 {
+			t.Errorf("expected current price 10000, got %v", result["current"])
+		}
+		if len(audit.actions) != 1 || audit.actions[0] != "CORRECT_SECURITY_PRICE" {
+			t.Fatalf("expected one CORRECT_SECURITY_PRICE audit entry, got %v", audit.actions)
+		}
+		changes := audit.changes[0]
+		if changes["old_price"] != int64(1000000) || changes["new_price"] != int64(10000) || changes["deleted"] != false {
+			t.Errorf("unexpected audit changes: %v", changes)
+		}
+	})
+
+	t.Run("deletes_price", func(t *testing.T) {
+		svc := &mockSecurityService{
+			correctPriceFn: func(_, _ string, correction services.PriceCorrection) (*services.PriceCorrectionResult, error) {
+				if !correction.Delete {
+					t.Errorf("expected delete, got %+v", correction)
+				}
+				return &services.PriceCorrectionResult{Previous: previous}, nil
+			},
+		}
+		audit := &recordingAuditService{}
+		r := setupSecurityRouter(NewSecurityHandler(svc, audit))
+
+		rec := doRequest(r, "PUT", path, `{"delete":true}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if result := parseJSON(t, rec); result["current"] != nil {
+			t.Errorf("expected no current price, got %v", result["current"])
+		}
+		if len(audit.changes) != 1 || audit.changes[0]["deleted"] != true {
+			t.Errorf("expected a deletion audit entry, got %v", audit.changes)
+		}
+		if _, ok := audit.changes[0]["new_price"]; ok {
+			t.Errorf("expected no new_price for a deletion, got %v", audit.changes[0])
+		}
+	})
+
+	t.Run("returns_400_for_price_and_delete_or_neither", func(t *testing.T) {
+		r := setupSecurityRouter(NewSecurityHandler(&mockSecurityService{}, &mockAuditService{}))
+
+		for _, body := range []string{`{"price":10000,"delete":true}`, `{}`, `{"price":-5}`} {
+			rec := doRequest(r, "PUT", path, body)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", body, rec.Code)
+				continue
+			}
+			assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+		}
+	})
+
+	t.Run("returns_404_when_price_not_found", func(t *testing.T) {
+		svc := &mockSecurityService{
+			correctPriceFn: func(_, _ string, _ services.PriceCorrection) (*services.PriceCorrectionResult, error) {
+				return nil, apperrors.ErrPriceNotFound
+			},
+		}
+		audit := &recordingAuditService{}
+		r := setupSecurityRouter(NewSecurityHandler(svc, audit))
+
+		rec := doRequest(r, "PUT", path, `{"price":10000}`)
+
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "PRICE_NOT_FOUND")
+		if len(audit.actions) != 0 {
+			t.Errorf("expected no audit entry, got %v", audit.actions)
+		}
+	})
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+	"kuberan/internal/services"
+)
+
+// ShareHandler handles sharing accounts with other users.
+type ShareHandler struct {
+	shareService services.ShareServicer
+	auditService services.AuditServicer
+}
+
+// NewShareHandler creates a new ShareHandler.
+func NewShareHandler(shareService services.ShareServicer, auditService services.AuditServicer) *ShareHandler {
+	return &ShareHandler{shareService: shareService, auditService: auditService}
+}
+
+// CreateShareRequest represents the request payload for sharing accounts.
+type CreateShareRequest struct {
+	Email      string           `json:"email" binding:"required,email"`
+	Role       models.ShareRole `json:"role" binding:"required,share_role"`
+	AccountIDs []string         `json:"account_ids"` // optional; empty shares all accounts
+}
+
+// CreateShare handles inviting another user to access the caller's accounts.
+// @Summary     Share accounts
+// @Description Invite another registered user by email to view or edit some or all of your accounts. The share is active once accepted.
+// @Tags        shares
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       request body CreateShareRequest true "Share details"
+// @Success     201 {object} models.AccountShare "Share created"
+// @Failure     400 {object} ErrorResponse "Invalid input"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     404 {object} ErrorResponse "User or account not found"
+// @Failure     409 {object} ErrorResponse "Share already exists"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /shares [post]
+func (h *ShareHandler) CreateShare(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	var req CreateShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+		return
+	}
+
+	share, err := h.shareService.CreateShare(userID, req.Email, req.Role, req.AccountIDs)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(userID, "CREATE_SHARE", "account_share", share.ID, c.ClientIP(),
+		map[string]interface{}{"shared_with_id": share.SharedWithID, "role": share.Role, "all_accounts": share.AllAccounts})
+
+	c.JSON(http.StatusCreated, gin.H{"share": share})
+}
+
+// GetShares handles listing shares the caller has granted or received.
+// @Summary     List shares
+// @Description List account shares you have granted or received
+// @Tags        shares
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Success     200 {object} map[string][]models.AccountShare "Shares"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /shares [get]
+func (h *ShareHandler) GetShares(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	shares, err := h.shareService.GetShares(userID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"shares": shares})
+}
+
+// AcceptShare handles accepting a share addressed to the caller.
+// @Summary     Accept share
+// @Description Accept an account share invitation
+// @Tags        shares
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Share ID"
+// @Success     200 {object} models.AccountShare "Share accepted"
+// @Failure     400 {object} ErrorResponse "Invalid input"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     404 {object} ErrorResponse "Share not found"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /shares/{id}/accept [post]
+func (h *ShareHandler) AcceptShare(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	shareID, err := parsePathID(c, "id")
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	share, err := h.shareService.AcceptShare(userID, shareID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(userID, "ACCEPT_SHARE", "account_share", shareID, c.ClientIP(), nil)
+
+	c.JSON(http.StatusOK, gin.H{"share": share})
+}
+
+// DeleteShare handles revoking a share.
+// @Summary     Delete share
+// @Description Revoke a share you granted or leave a share you received
+// @Tags        shares
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Share ID"
+// @Success     200 {object} MessageResponse "Share deleted"
+// @Failure     400 {object} ErrorResponse "Invalid input"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     404 {object} ErrorResponse "Share not found"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /shares/{id} [delete]
+func (h *ShareHandler) DeleteShare(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	shareID, err := parsePathID(c, "id")
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	if err := h.shareService.DeleteShare(userID, shareID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(userID, "DELETE_SHARE", "account_share", shareID, c.ClientIP(), nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Share deleted successfully"})
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+	"kuberan/internal/services"
+)
+
+// --- mock share service ---
+
+type mockShareService struct {
+	createShareFn func(ownerID, email string, role models.ShareRole, accountIDs []string) (*models.AccountShare, error)
+	getSharesFn   func(userID string) ([]models.AccountShare, error)
+	acceptShareFn func(userID, shareID string) (*models.AccountShare, error)
+	deleteShareFn func(userID, shareID string) error
+}
+
+func (m *mockShareService) CreateShare(ownerID, email string, role models.ShareRole, accountIDs []string) (*models.AccountShare, error) {
+	if m.createShareFn != nil {
+		return m.createShareFn(ownerID, email, role, accountIDs)
+	}
+	return &models.AccountShare{}, nil
+}
+
+func (m *mockShareService) GetShares(userID string) ([]models.AccountShare, error) {
+	if m.getSharesFn != nil {
+		return m.getSharesFn(userID)
+	}
+	return []models.AccountShare{}, nil
+}
+
+func (m *mockShareService) AcceptShare(userID, shareID string) (*models.AccountShare, error) {
+	if m.acceptShareFn != nil {
+		return m.acceptShareFn(userID, shareID)
+	}
+	return &models.AccountShare{}, nil
+}
+
+func (m *mockShareService) DeleteShare(userID, shareID string) error {
+	if m.deleteShareFn != nil {
+		return m.deleteShareFn(userID, shareID)
+	}
+	return nil
+}
+
+var _ services.ShareServicer = (*mockShareService)(nil)
+
+func setupShareRouter(handler *ShareHandler) *gin.Engine {
+	r := gin.New()
+	auth := r.Group("", injectUserID(testID(1)))
+	auth.POST("/shares", handler.CreateShare)
+	auth.GET("/shares", handler.GetShares)
+	auth.POST("/shares/:id/accept", handler.AcceptShare)
+	auth.DELETE("/shares/:id", handler.DeleteShare)
+	return r
+}
+
+func TestShareHandler_CreateShare(t *testing.T) {
+	t.Run("returns 201 on success", func(t *testing.T) {
+		var gotRole models.ShareRole
+		var gotAccountIDs []string
+		svc := &mockShareService{
+			createShareFn: func(_, _ string, role models.ShareRole, accountIDs []string) (*models.AccountShare, error) {
+				gotRole, gotAccountIDs = role, accountIDs
+				return &models.AccountShare{Base: models.Base{ID: testID(5)}, Role: role, AccountIDs: accountIDs}, nil
+			},
+		}
+		handler := NewShareHandler(svc, &mockAuditService{})
+		r := setupShareRouter(handler)
+
+		rec := doRequest(r, "POST", "/shares",
+			`{"email":"partner@example.com","role":"viewer","account_ids":["00000000-0000-7000-8000-000000000002"]}`)
+
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotRole != models.ShareRoleViewer {
+			t.Errorf("expected role viewer, got %s", gotRole)
+		}
+		if len(gotAccountIDs) != 1 || gotAccountIDs[0] != testID(2) {
+			t.Errorf("unexpected account IDs: %v", gotAccountIDs)
+		}
+		share := parseJSON(t, rec)["share"].(map[string]interface{})
+		if share["id"] != testID(5) {
+			t.Errorf("expected share id %s, got %v", testID(5), share["id"])
+		}
+	})
+
+	t.Run("returns 400 on invalid role", func(t *testing.T) {
+		handler := NewShareHandler(&mockShareService{}, &mockAuditService{})
+		r := setupShareRouter(handler)
+
+		rec := doRequest(r, "POST", "/shares", `{"email":"partner@example.com","role":"admin"}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("returns 409 on duplicate", func(t *testing.T) {
+		svc := &mockShareService{
+			createShareFn: func(_, _ string, _ models.ShareRole, _ []string) (*models.AccountShare, error) {
+				return nil, apperrors.ErrDuplicateShare
+			},
+		}
+		handler := NewShareHandler(svc, &mockAuditService{})
+		r := setupShareRouter(handler)
+
+		rec := doRequest(r, "POST", "/shares", `{"email":"partner@example.com","role":"editor"}`)
+
+		if rec.Code != http.StatusConflict {
+			t.Fatalf("expected 409, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "DUPLICATE_SHARE")
+	})
+}
+
+func TestShareHandler_GetShares(t *testing.T) {
+	svc := &mockShareService{
+		getSharesFn: func(_ string) ([]models.AccountShare, error) {
+			return []models.AccountShare{{Base: models.Base{ID: testID(5)}, AllAccounts: true}}, nil
+		},
+	}
+	handler := NewShareHandler(svc, &mockAuditService{})
+	r := setupShareRouter(handler)
+
+	rec := doRequest(r, "GET", "/shares", "")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if shares := parseJSON(t, rec)["shares"].([]interface{}); len(shares) != 1 {
+		t.Errorf("expected 1 share, got %d", len(shares))
+	}
+}
+
+func TestShareHandler_AcceptShare(t *testing.T) {
+	t.Run("returns 200 on success", func(t *testing.T) {
+		svc := &mockShareService{
+			acceptShareFn: func(_, shareID string) (*models.AccountShare, error) {
+				now := time.Now()
+				return &models.AccountShare{Base: models.Base{ID: shareID}, AcceptedAt: &now}, nil
+			},
+		}
+		handler := NewShareHandler(svc, &mockAuditService{})
+		r := setupShareRouter(handler)
+
+		rec := doRequest(r, "POST", "/shares/00000000-0000-7000-8000-000000000005/accept", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		share := parseJSON(t, rec)["share"].(map[string]interface{})
+		if share["accepted_at"] == nil {
+			t.Error("expected accepted_at in response")
+		}
+	})
+
+	t.Run("returns 404 when not found", func(t *testing.T) {
+		svc := &mockShareService{
+			acceptShareFn: func(_, _ string) (*models.AccountShare, error) {
+				return nil, apperrors.ErrShareNotFound
+			},
+		}
+		handler := NewShareHandler(svc, &mockAuditService{})
+		r := setupShareRouter(handler)
+
+		rec := doRequest(r, "POST", "/shares/00000000-0000-7000-8000-000000000005/accept", "")
+
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "SHARE_NOT_FOUND")
+	})
+
+	t.Run("returns 400 on invalid id", func(t *testing.T) {
+		handler := NewShareHandler(&mockShareService{}, &mockAuditService{})
+		r := setupShareRouter(handler)
+
+		rec := doRequest(r, "POST", "/shares/abc/accept", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+	})
+}
+
+func TestShareHandler_DeleteShare(t *testing.T) {
+	var gotShareID string
+	svc := &mockShareService{
+		deleteShareFn: func(_, shareID string) error {
+			gotShareID = shareID
+			return nil
+		},
+	}
+	handler := NewShareHandler(svc, &mockAuditService{})
+	r := setupShareRouter(handler)
+
+	rec := doRequest(r, "DELETE", "/shares/00000000-0000-7000-8000-000000000005", "")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if gotShareID != testID(5) {
+		t.Errorf("expected share id %s, got %s", testID(5), gotShareID)
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/services"
+)
+
+// StatementHandler handles monthly statement requests.
+type StatementHandler struct {
+	statementService services.StatementServicer
+}
+
+// NewStatementHandler creates a new StatementHandler.
+func NewStatementHandler(statementService services.StatementServicer) *StatementHandler {
+	return &StatementHandler{statementService: statementService}
+}
+
+// GetMonthlyStatement returns the user's financial statement for one month.
+// @Summary     Get monthly statement
+// @Description Opening and closing account balances, income and expense totals, top expense categories, budget standings, investment value change and transaction count for a calendar month (UTC)
+// @Tags        statements
+// @Produce     json
+// @Security    BearerAuth
+// @Param       month path string true "Month in YYYY-MM format"
+// @Success     200 {object} map[string]interface{} "Monthly statement"
+// @Failure     400 {object} ErrorResponse "Invalid month"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /statements/{month} [get]
+func (h *StatementHandler) GetMonthlyStatement(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	month, err := time.Parse("2006-01", c.Param("month"))
+	if err != nil {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "month must be in YYYY-MM format"))
+		return
+	}
+
+	statement, err := h.statementService.GetMonthlyStatement(c.Request.Context(), userID, month)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"statement": statement})
+}
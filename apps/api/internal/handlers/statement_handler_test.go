@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/services"
+)
+
+// --- mock statement service ---
+
+type mockStatementService struct {
+	getMonthlyStatementFn func(ctx context.Context, userID string, month time.Time) (*services.MonthlyStatement, error)
+}
+
+var _ services.StatementServicer = (*mockStatementService)(nil)
+
+func (m *mockStatementService) GetMonthlyStatement(ctx context.Context, userID string, month time.Time) (*services.MonthlyStatement, error) {
+	if m.getMonthlyStatementFn != nil {
+		return m.getMonthlyStatementFn(ctx, userID, month)
+	}
+	return &services.MonthlyStatement{}, nil
+}
+
+// --- router setup ---
+
+func setupStatementRouter(handler *StatementHandler) *gin.Engine {
+	r := gin.New()
+	auth := r.Group("", injectUserID(testID(1)))
+	auth.GET("/statements/:month", handler.GetMonthlyStatement)
+	return r
+}
+
+func TestStatementHandler_GetMonthlyStatement(t *testing.T) {
+	t.Run("returns 200 with statement", func(t *testing.T) {
+		var gotUserID string
+		var gotMonth time.Time
+		svc := &mockStatementService{
+			getMonthlyStatementFn: func(_ context.Context, userID string, month time.Time) (*services.MonthlyStatement, error) {
+				gotUserID = userID
+				gotMonth = month
+				return &services.MonthlyStatement{Month: "2025-10", Income: 300000, Expenses: 118000, Net: 182000}, nil
+			},
+		}
+		r := setupStatementRouter(NewStatementHandler(svc))
+
+		rec := doRequest(r, "GET", "/statements/2025-10", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotUserID != testID(1) {
+			t.Errorf("expected user %s, got %s", testID(1), gotUserID)
+		}
+		if !gotMonth.Equal(time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("expected 2025-10-01, got %v", gotMonth)
+		}
+		statement := parseJSON(t, rec)["statement"].(map[string]interface{})
+		if statement["net"].(float64) != 182000 {
+			t.Errorf("expected net=182000, got %v", statement["net"])
+		}
+	})
+
+	t.Run("returns 400 on invalid month", func(t *testing.T) {
+		for _, month := range []string{"2025-13", "2025-1", "october", "2025-10-01"} {
+			r := setupStatementRouter(NewStatementHandler(&mockStatementService{}))
+
+			rec := doRequest(r, "GET", "/statements/"+month, "")
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", month, rec.Code)
+				continue
+			}
+			assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+		}
+	})
+
+	t.Run("returns 500 on service error", func(t *testing.T) {
+		svc := &mockStatementService{
+			getMonthlyStatementFn: func(_ context.Context, _ string, _ time.Time) (*services.MonthlyStatement, error) {
+				return nil, apperrors.ErrInternalServer
+			},
+		}
+		r := setupStatementRouter(NewStatementHandler(svc))
+
+		rec := doRequest(r, "GET", "/statements/2025-10", "")
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Fatalf("expected 500, got %d", rec.Code)
+		}
+	})
+
+	t.Run("returns 401 without auth", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/statements/:month", NewStatementHandler(&mockStatementService{}).GetMonthlyStatement)
+
+		rec := doRequest(r, "GET", "/statements/2025-10", "")
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rec.Code)
+		}
+	})
+}
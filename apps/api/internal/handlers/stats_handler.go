@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+	"kuberan/internal/services"
+)
+
+// StatsHandler handles account and category usage statistics requests.
+type StatsHandler struct {
+	statsService services.StatsServicer
+}
+
+// NewStatsHandler creates a new StatsHandler.
+func NewStatsHandler(statsService services.StatsServicer) *StatsHandler {
+	return &StatsHandler{statsService: statsService}
+}
+
+// GetAccountStats returns usage statistics for each of the user's accounts.
+// @Summary     Get account usage statistics
+// @Description Transaction count, first and last activity, average transaction size and busiest day of week (UTC) for every account, including accounts with no activity. Transfers count on both accounts.
+// @Tags        stats
+// @Produce     json
+// @Security    BearerAuth
+// @Param       from_date query string false "Start date (RFC3339, YYYY-MM-DD or unix seconds)"
+// @Param       to_date   query string false "End date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)"
+// @Success     200 {object} map[string]interface{} "Account usage statistics"
+// @Failure     400 {object} ErrorResponse "Invalid date"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /stats/accounts [get]
+func (h *StatsHandler) GetAccountStats(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	loc, err := h.statsService.UserLocation(models.UserID(userID))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	from, to, err := parseStatsRange(c, loc)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	stats, err := h.statsService.GetAccountStats(c.Request.Context(), models.UserID(userID), from, to)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"accounts": stats})
+}
+
+// GetCategoryStats returns usage statistics for each of the user's categories.
+// @Summary     Get category usage statistics
+// @Description Transaction count, first and last activity, average transaction size and busiest day of week (UTC) for every category, including categories with no activity
+// @Tags        stats
+// @Produce     json
+// @Security    BearerAuth
+// @Param       from_date query string false "Start date (RFC3339, YYYY-MM-DD or unix seconds)"
+// @Param       to_date   query string false "End date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)"
+// @Success     200 {object} map[string]interface{} "Category usage statistics"
+// @Failure     400 {object} ErrorResponse "Invalid date"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /stats/categories [get]
+func (h *StatsHandler) GetCategoryStats(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	loc, err := h.statsService.UserLocation(models.UserID(userID))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	from, to, err := parseStatsRange(c, loc)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	stats, err := h.statsService.GetCategoryStats(c.Request.Context(), models.UserID(userID), from, to)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"categories": stats})
+}
+
+// parseStatsRange reads the optional from_date and to_date query parameters,
+// reading date-only values in loc.
+func parseStatsRange(c *gin.Context, loc *time.Location) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+	if v := c.Query("from_date"); v != "" {
+		t, err := parseDateParam("from_date", v, rangeStart, loc)
+		if err != nil {
+			return nil, nil, err
+		}
+		from = &t
+	}
+	if v := c.Query("to_date"); v != "" {
+		t, err := parseDateParam("to_date", v, rangeEnd, loc)
+		if err != nil {
+			return nil, nil, err
+		}
+		to = &t
+	}
+	if from != nil && to != nil && to.Before(*from) {
+		return nil, nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "to_date must not be before from_date")
+	}
+	return from, to, nil
+}
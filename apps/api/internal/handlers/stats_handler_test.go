@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+	"kuberan/internal/services"
+)
+
+// --- mock stats service ---
+
+type mockStatsService struct {
+	getAccountStatsFn  func(userID models.UserID, from, to *time.Time) ([]services.AccountUsageStats, error)
+	getCategoryStatsFn func(userID models.UserID, from, to *time.Time) ([]services.CategoryUsageStats, error)
+	userLocationFn     func(userID models.UserID) (*time.Location, error)
+}
+
+var _ services.StatsServicer = (*mockStatsService)(nil)
+
+func (m *mockStatsService) GetAccountStats(_ context.Context, userID models.UserID, from, to *time.Time) ([]services.AccountUsageStats, error) {
+	if m.getAccountStatsFn != nil {
+		return m.getAccountStatsFn(userID, from, to)
+	}
+	return []services.AccountUsageStats{}, nil
+}
+
+func (m *mockStatsService) GetCategoryStats(_ context.Context, userID models.UserID, from, to *time.Time) ([]services.CategoryUsageStats, error) {
+	if m.getCategoryStatsFn != nil {
+		return m.getCategoryStatsFn(userID, from, to)
+	}
+	return []services.CategoryUsageStats{}, nil
+}
+
+func (m *mockStatsService) UserLocation(userID models.UserID) (*time.Location, error) {
+	if m.userLocationFn != nil {
+		return m.userLocationFn(userID)
+	}
+	return time.UTC, nil
+}
+
+// --- router setup ---
+
+func setupStatsRouter(handler *StatsHandler) *gin.Engine {
+	r := gin.New()
+	auth := r.Group("", injectUserID(testID(1)))
+	auth.GET("/stats/accounts", handler.GetAccountStats)
+	auth.GET("/stats/categories", handler.GetCategoryStats)
+	return r
+}
+
+func TestStatsHandler_GetAccountStats(t *testing.T) {
+	t.Run("returns 200 with stats for the range", func(t *testing.T) {
+		var gotFrom, gotTo *time.Time
+		svc := &mockStatsService{
+			getAccountStatsFn: func(_ models.UserID, from, to *time.Time) ([]services.AccountUsageStats, error) {
+				gotFrom, gotTo = from, to
+				return []services.AccountUsageStats{{AccountID: testID(2), UsageStats: services.UsageStats{TransactionCount: 4}}}, nil
+			},
+		}
+		r := setupStatsRouter(NewStatsHandler(svc))
+
+		rec := doRequest(r, "GET", "/stats/accounts?from_date=2025-06-01&to_date=2025-06-30", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotFrom == nil || !gotFrom.Equal(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)) ||
+			gotTo == nil || !gotTo.Equal(time.Date(2025, 6, 30, 23, 59, 59, 999999999, time.UTC)) {
+			t.Errorf("expected 2025-06-01 through the end of 2025-06-30, got %v to %v", gotFrom, gotTo)
+		}
+		accounts := parseJSON(t, rec)["accounts"].([]interface{})
+		if len(accounts) != 1 || accounts[0].(map[string]interface{})["transaction_count"].(float64) != 4 {
+			t.Errorf("expected one account with 4 transactions, got %v", accounts)
+		}
+	})
+
+	t.Run("reads date-only values in the user's time zone", func(t *testing.T) {
+		loc := time.FixedZone("UTC-5", -5*60*60)
+		var gotFrom, gotTo *time.Time
+		svc := &mockStatsService{
+			userLocationFn: func(models.UserID) (*time.Location, error) { return loc, nil },
+			getAccountStatsFn: func(_ models.UserID, from, to *time.Time) ([]services.AccountUsageStats, error) {
+				gotFrom, gotTo = from, to
+				return []services.AccountUsageStats{}, nil
+			},
+		}
+		r := setupStatsRouter(NewStatsHandler(svc))
+
+		rec := doRequest(r, "GET", "/stats/accounts?from_date=2025-06-01&to_date=2025-06-30", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotFrom == nil || !gotFrom.Equal(time.Date(2025, 6, 1, 0, 0, 0, 0, loc)) ||
+			gotTo == nil || !gotTo.Equal(time.Date(2025, 6, 30, 23, 59, 59, 999999999, loc)) {
+			t.Errorf("expected June 2025 in UTC-5, got %v to %v", gotFrom, gotTo)
+		}
+	})
+
+	t.Run("leaves the range open by default", func(t *testing.T) {
+		called := false
+		svc := &mockStatsService{
+			getAccountStatsFn: func(_ models.UserID, from, to *time.Time) ([]services.AccountUsageStats, error) {
+				called = true
+				if from != nil || to != nil {
+					t.Errorf("expected an open range, got %v to %v", from, to)
+				}
+				return []services.AccountUsageStats{}, nil
+			},
+		}
+		r := setupStatsRouter(NewStatsHandler(svc))
+
+		rec := doRequest(r, "GET", "/stats/accounts", "")
+
+		if rec.Code != http.StatusOK || !called {
+			t.Fatalf("expected 200 from the service, got %d", rec.Code)
+		}
+	})
+
+	t.Run("accepts a single day range", func(t *testing.T) {
+		r := setupStatsRouter(NewStatsHandler(&mockStatsService{}))
+
+		rec := doRequest(r, "GET", "/stats/accounts?from_date=2025-06-01&to_date=2025-06-01", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("returns 400 on invalid range", func(t *testing.T) {
+		for _, query := range []string{"from_date=june", "to_date=2025-13-01", "from_date=2025-06-30&to_date=2025-06-01"} {
+			r := setupStatsRouter(NewStatsHandler(&mockStatsService{}))
+
+			rec := doRequest(r, "GET", "/stats/accounts?"+query, "")
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", query, rec.Code)
+				continue
+			}
+			assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+		}
+	})
+
+	t.Run("returns 500 on service error", func(t *testing.T) {
+		svc := &mockStatsService{
+			getAccountStatsFn: func(_ models.UserID, _, _ *time.Time) ([]services.AccountUsageStats, error) {
+				return nil, apperrors.ErrInternalServer
+			},
+		}
+		r := setupStatsRouter(NewStatsHandler(svc))
+
+		rec := doRequest(r, "GET", "/stats/accounts", "")
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Fatalf("expected 500, got %d", rec.Code)
+		}
+	})
+}
+
+func TestStatsHandler_GetCategoryStats(t *testing.T) {
+	t.Run("returns 200 with stats", func(t *testing.T) {
+		svc := &mockStatsService{
+			getCategoryStatsFn: func(_ models.UserID, _, _ *time.Time) ([]services.CategoryUsageStats, error) {
+				return []services.CategoryUsageStats{{CategoryID: testID(3), Name: "Groceries"}}, nil
+			},
+		}
+		r := setupStatsRouter(NewStatsHandler(svc))
+
+		rec := doRequest(r, "GET", "/stats/categories", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		categories := parseJSON(t, rec)["categories"].([]interface{})
+		if len(categories) != 1 || categories[0].(map[string]interface{})["name"] != "Groceries" {
+			t.Errorf("expected the groceries category, got %v", categories)
+		}
+	})
+
+	t.Run("returns 401 without auth", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/stats/categories", NewStatsHandler(&mockStatsService{}).GetCategoryStats)
+
+		rec := doRequest(r, "GET", "/stats/categories", "")
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rec.Code)
+		}
+	})
+}
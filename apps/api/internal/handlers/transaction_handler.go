@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -9,29 +10,68 @@ import (
 
 	apperrors "kuberan/internal/errors"
 	"kuberan/internal/models"
-	"kuberan/internal/pagination"
+	_ "kuberan/internal/pagination" // page types in swagger annotations
 	"kuberan/internal/services"
+	"kuberan/internal/uuid"
+	"kuberan/internal/validator"
 )
 
+// MaxSummaryMonths bounds the months of the monthly summary, and the range of
+// weekly and monthly spending series.
+const MaxSummaryMonths = 36
+
+// DefaultSummaryMonths is the monthly summary length when months is not given.
+const DefaultSummaryMonths = 6
+
 // TransactionHandler handles transaction-related requests.
 type TransactionHandler struct {
-	transactionService services.TransactionServicer
-	auditService       services.AuditServicer
+	transactionService   services.TransactionServicer
+	accountService       services.AccountServicer
+	auditService         services.AuditServicer
+	defaultSummaryMonths int
 }
 
 // NewTransactionHandler creates a new TransactionHandler.
-func NewTransactionHandler(transactionService services.TransactionServicer, auditService services.AuditServicer) *TransactionHandler {
-	return &TransactionHandler{transactionService: transactionService, auditService: auditService}
+func NewTransactionHandler(transactionService services.TransactionServicer, accountService services.AccountServicer, auditService services.AuditServicer) *TransactionHandler {
+	return &TransactionHandler{transactionService: transactionService, accountService: accountService, auditService: auditService, defaultSummaryMonths: DefaultSummaryMonths}
+}
+
+// WithDefaultSummaryMonths sets the monthly summary length used when months is
+// not given. Values outside 1..MaxSummaryMonths are ignored.
+func (h *TransactionHandler) WithDefaultSummaryMonths(months int) *TransactionHandler {
+	if months >= 1 && months <= MaxSummaryMonths {
+		h.defaultSummaryMonths = months
+	}
+	return h
+}
+
+// accountCurrency returns a lookup of the account's currency, used to parse
+// decimal amounts with the right precision.
+func (h *TransactionHandler) accountCurrency(userID, accountID string) func() (string, error) {
+	return func() (string, error) {
+		account, err := h.accountService.GetAccountByID(models.UserID(userID), models.AccountID(accountID))
+		if err != nil {
+			return "", err
+		}
+		return account.Currency, nil
+	}
 }
 
 // CreateTransactionRequest represents the request payload for creating a transaction
 type CreateTransactionRequest struct {
-	AccountID   string                 `json:"account_id" binding:"required"`
-	CategoryID  *string                `json:"category_id"`
-	Type        models.TransactionType `json:"type" binding:"required,transaction_type"`
-	Amount      int64                  `json:"amount" binding:"required,gt=0"`
-	Description string                 `json:"description" binding:"max=500"`
-	Date        *string                `json:"date"`
+	AccountID     string                 `json:"account_id" binding:"required"`
+	CategoryID    *string                `json:"category_id"`
+	Type          models.TransactionType `json:"type" binding:"required,transaction_type"`
+	Amount        int64                  `json:"amount" binding:"omitempty,gt=0"`
+	AmountDecimal *string                `json:"amount_decimal"` // alternative to amount, in the account's currency (e.g. "12.34")
+	Description   string                 `json:"description" binding:"max=500"`
+	Date          *string                `json:"date"`
+	IsPending     bool                   `json:"is_pending"`
+	Status        models.TransactionStatus `json:"status" binding:"omitempty,transaction_status"` // pending or cleared (default)
+	// OriginalAmount (minor units) and OriginalCurrency record a foreign-currency
+	// purchase for reference; send both or neither. Amount still moves the balance.
+	OriginalAmount   *int64  `json:"original_amount" binding:"omitempty,gt=0"`
+	OriginalCurrency *string `json:"original_currency" binding:"omitempty,iso4217"`
 }
 
 // TransactionResponse represents a transaction in the response
@@ -48,12 +88,13 @@ type TransactionResponse struct {
 
 // CreateTransaction handles the creation of a new transaction
 // @Summary     Create a transaction
-// @Description Create a new transaction (income or expense) for an account
+// @Description Create a new transaction (income or expense) for an account. Transactions dated in the future or flagged is_pending do not affect the balance until settled. The amount may be given in minor units (amount) or as a decimal string in the account currency (amount_decimal). The response also carries the account's balance after the transaction (account_balance).
 // @Tags        transactions
 // @Accept      json
 // @Produce     json
 // @Security    BearerAuth
 // @Param       request body CreateTransactionRequest true "Transaction details"
+// @Param       strict  query bool                     false "Reject unknown fields in the body"
 // @Success     201 {object} TransactionResponse "Transaction created"
 // @Failure     400 {object} ErrorResponse "Invalid input"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
@@ -67,29 +108,52 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 	}
 
 	var req CreateTransactionRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+	if err := bindJSON(c, &req); err != nil {
+		respondWithError(c, err)
 		return
 	}
 
 	transactionDate := time.Now()
 	if req.Date != nil && *req.Date != "" {
-		parsed, parseErr := parseFlexibleTime(*req.Date)
+		loc, locErr := h.transactionService.UserLocation(models.UserID(userID))
+		if locErr != nil {
+			respondWithError(c, locErr)
+			return
+		}
+		parsed, parseErr := parseDateParam("date", *req.Date, rangeStart, loc)
 		if parseErr != nil {
-			respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, parseErr.Error()))
+			respondWithError(c, parseErr)
 			return
 		}
 		transactionDate = parsed
 	}
 
+	amount, err := resolveAmount("amount", req.Amount, req.AmountDecimal, h.accountCurrency(userID, req.AccountID))
+	if err == nil {
+		err = requirePositiveAmount("amount", amount)
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	original, err := parseOriginalAmount(req.OriginalAmount, req.OriginalCurrency)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
 	transaction, err := h.transactionService.CreateTransaction(
-		userID,
-		req.AccountID,
+		models.UserID(userID),
+		models.AccountID(req.AccountID),
 		req.CategoryID,
 		req.Type,
-		req.Amount,
+		models.Cents(amount),
 		req.Description,
 		transactionDate,
+		req.IsPending,
+		req.Status,
+		original,
 	)
 	if err != nil {
 		respondWithError(c, err)
@@ -97,30 +161,35 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 	}
 
 	h.auditService.Log(userID, "CREATE_TRANSACTION", "transaction", transaction.ID, c.ClientIP(),
-		map[string]interface{}{"type": req.Type, "amount": req.Amount, "account_id": req.AccountID})
+		map[string]interface{}{"type": req.Type, "amount": amount, "account_id": req.AccountID})
 
-	c.JSON(http.StatusCreated, gin.H{"transaction": transaction})
+	c.JSON(http.StatusCreated, gin.H{
+		"transaction":     transaction,
+		"account_balance": transaction.Account.Balance,
+	})
 }
 
 // CreateTransferRequest represents the request payload for creating a transfer
 type CreateTransferRequest struct {
 	FromAccountID string  `json:"from_account_id" binding:"required"`
 	ToAccountID   string  `json:"to_account_id" binding:"required"`
-	Amount        int64   `json:"amount" binding:"required,gt=0"`
+	Amount        int64   `json:"amount" binding:"omitempty,gt=0"`
+	AmountDecimal *string `json:"amount_decimal"` // alternative to amount, in the source account's currency
 	Description   string  `json:"description" binding:"max=500"`
 	Date          *string `json:"date"`
 }
 
 // CreateTransfer handles the creation of a transfer between two accounts
 // @Summary     Create a transfer
-// @Description Transfer funds from one account to another
+// @Description Transfer funds from one account to another. Allowed: cash to cash, cash to credit card (a payment, lowering the amount owed), and cash to or from an investment account's cash; other combinations are rejected with INVALID_TRANSFER. The amount may be given in minor units (amount) or as a decimal string in the source account currency (amount_decimal). The response also carries both accounts' balances after the transfer (from_account_balance, to_account_balance).
 // @Tags        transactions
 // @Accept      json
 // @Produce     json
 // @Security    BearerAuth
 // @Param       request body CreateTransferRequest true "Transfer details"
+// @Param       strict  query bool                  false "Reject unknown fields in the body"
 // @Success     201 {object} TransactionResponse "Transfer created"
-// @Failure     400 {object} ErrorResponse "Invalid input or insufficient balance"
+// @Failure     400 {object} ErrorResponse "Invalid input, unsupported account types or insufficient balance"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
 // @Failure     404 {object} ErrorResponse "Account not found"
 // @Failure     500 {object} ErrorResponse "Server error"
@@ -133,26 +202,40 @@ func (h *TransactionHandler) CreateTransfer(c *gin.Context) {
 	}
 
 	var req CreateTransferRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+	if err := bindJSON(c, &req); err != nil {
+		respondWithError(c, err)
 		return
 	}
 
 	transferDate := time.Now()
 	if req.Date != nil && *req.Date != "" {
-		parsed, parseErr := parseFlexibleTime(*req.Date)
+		loc, locErr := h.transactionService.UserLocation(models.UserID(userID))
+		if locErr != nil {
+			respondWithError(c, locErr)
+			return
+		}
+		parsed, parseErr := parseDateParam("date", *req.Date, rangeStart, loc)
 		if parseErr != nil {
-			respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, parseErr.Error()))
+			respondWithError(c, parseErr)
 			return
 		}
 		transferDate = parsed
 	}
 
+	amount, err := resolveAmount("amount", req.Amount, req.AmountDecimal, h.accountCurrency(userID, req.FromAccountID))
+	if err == nil {
+		err = requirePositiveAmount("amount", amount)
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
 	transaction, err := h.transactionService.CreateTransfer(
-		userID,
-		req.FromAccountID,
-		req.ToAccountID,
-		req.Amount,
+		models.UserID(userID),
+		models.AccountID(req.FromAccountID),
+		models.AccountID(req.ToAccountID),
+		models.Cents(amount),
 		req.Description,
 		transferDate,
 	)
@@ -165,10 +248,115 @@ func (h *TransactionHandler) CreateTransfer(c *gin.Context) {
 		map[string]interface{}{
 			"from_account_id": req.FromAccountID,
 			"to_account_id":   req.ToAccountID,
-			"amount":          req.Amount,
+			"amount":          amount,
+		})
+
+	response := gin.H{
+		"transaction":          transaction,
+		"from_account_balance": transaction.Account.Balance,
+	}
+	if transaction.ToAccount != nil {
+		response["to_account_balance"] = transaction.ToAccount.Balance
+	}
+	c.JSON(http.StatusCreated, response)
+}
+
+// SplitTransferLegRequest is one destination of a split transfer
+type SplitTransferLegRequest struct {
+	ToAccountID   string  `json:"to_account_id" binding:"required"`
+	Amount        int64   `json:"amount" binding:"omitempty,gt=0"`
+	AmountDecimal *string `json:"amount_decimal"` // alternative to amount, in the source account's currency
+}
+
+// CreateSplitTransferRequest represents the request body for a one-to-many transfer
+type CreateSplitTransferRequest struct {
+	FromAccountID string                    `json:"from_account_id" binding:"required"`
+	Legs          []SplitTransferLegRequest `json:"legs" binding:"required,min=2,dive"`
+	Description   string                    `json:"description" binding:"max=500"`
+	Date          *string                   `json:"date"`
+}
+
+// CreateSplitTransfer handles a transfer from one account to several
+// @Summary     Create a split transfer
+// @Description Debit the source account once and credit several destination accounts atomically. The total of all legs must not exceed the source balance. The legs share a transfer_group_id and deleting any one of them deletes all.
+// @Tags        transactions
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       request body CreateSplitTransferRequest true "Split transfer details"
+// @Param       strict  query bool                       false "Reject unknown fields in the body"
+// @Success     201 {object} map[string][]models.Transaction "Transfer legs created"
+// @Failure     400 {object} ErrorResponse "Invalid input, unsupported account types or insufficient balance"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     404 {object} ErrorResponse "Account not found"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /transactions/split-transfer [post]
+func (h *TransactionHandler) CreateSplitTransfer(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	var req CreateSplitTransferRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	transferDate := time.Now()
+	if req.Date != nil && *req.Date != "" {
+		loc, locErr := h.transactionService.UserLocation(models.UserID(userID))
+		if locErr != nil {
+			respondWithError(c, locErr)
+			return
+		}
+		parsed, parseErr := parseDateParam("date", *req.Date, rangeStart, loc)
+		if parseErr != nil {
+			respondWithError(c, parseErr)
+			return
+		}
+		transferDate = parsed
+	}
+
+	currency := h.accountCurrency(userID, req.FromAccountID)
+	legs := make([]services.SplitTransferLeg, len(req.Legs))
+	for i, leg := range req.Legs {
+		field := fmt.Sprintf("legs[%d].amount", i)
+		amount, err := resolveAmount(field, leg.Amount, leg.AmountDecimal, currency)
+		if err == nil {
+			err = requirePositiveAmount(field, amount)
+		}
+		if err != nil {
+			respondWithError(c, err)
+			return
+		}
+		legs[i] = services.SplitTransferLeg{
+			ToAccountID: models.AccountID(leg.ToAccountID),
+			Amount:      models.Cents(amount),
+		}
+	}
+
+	transactions, err := h.transactionService.CreateSplitTransfer(
+		models.UserID(userID),
+		models.AccountID(req.FromAccountID),
+		legs,
+		req.Description,
+		transferDate,
+	)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(userID, "CREATE_SPLIT_TRANSFER", "transaction", transactions[0].ID, c.ClientIP(),
+		map[string]interface{}{
+			"from_account_id":   req.FromAccountID,
+			"transfer_group_id": transactions[0].TransferGroupID,
+			"legs":              len(legs),
 		})
 
-	c.JSON(http.StatusCreated, gin.H{"transaction": transaction})
+	c.JSON(http.StatusCreated, gin.H{"transactions": transactions})
 }
 
 // GetAccountTransactions handles the retrieval of transactions for a specific account
@@ -181,12 +369,14 @@ func (h *TransactionHandler) CreateTransfer(c *gin.Context) {
 // @Param       id          path  int    true  "Account ID"
 // @Param       page        query int    false "Page number (default 1)"
 // @Param       page_size   query int    false "Items per page (default 20, max 100)"
-// @Param       from_date   query string false "Filter by start date (RFC3339 e.g. 2024-01-01T00:00:00Z, or YYYY-MM-DD)"
-// @Param       to_date     query string false "Filter by end date (RFC3339 or YYYY-MM-DD)"
+// @Param       with_total  query bool   false "Compute total_items and total_pages (default true); when false they are -1 and 0"
+// @Param       from_date   query string false "Filter by start date (RFC3339 e.g. 2024-01-01T00:00:00Z, YYYY-MM-DD or unix seconds)"
+// @Param       to_date     query string false "Filter by end date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)"
 // @Param       type        query string false "Filter by transaction type (income, expense, transfer, investment)"
 // @Param       category_id query int    false "Filter by category ID"
 // @Param       min_amount  query int    false "Filter by minimum amount (cents)"
 // @Param       max_amount  query int    false "Filter by maximum amount (cents)"
+// @Param       status      query string false "Filter by reconciliation status (pending, cleared)"
 // @Success     200 {object} pagination.PageResponse[models.Transaction] "Paginated transactions"
 // @Failure     400 {object} ErrorResponse "Invalid input"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
@@ -206,25 +396,31 @@ func (h *TransactionHandler) GetAccountTransactions(c *gin.Context) {
 		return
 	}
 
-	var page pagination.PageRequest
-	if err := c.ShouldBindQuery(&page); err != nil {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+	page, err := bindPage(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	loc, err := h.transactionService.UserLocation(models.UserID(userID))
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	filter, err := parseTransactionFilter(c)
+	filter, err := parseTransactionFilter(c, loc)
 	if err != nil {
 		respondWithError(c, err)
 		return
 	}
 
-	result, err := h.transactionService.GetAccountTransactions(userID, accountID, page, filter)
+	result, err := h.transactionService.GetAccountTransactions(models.UserID(userID), models.AccountID(accountID), page, filter)
 	if err != nil {
 		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	respondWithPage(c, page, result)
 }
 
 // GetUserTransactions handles the retrieval of all transactions for the authenticated user
@@ -236,13 +432,16 @@ func (h *TransactionHandler) GetAccountTransactions(c *gin.Context) {
 // @Security    BearerAuth
 // @Param       page        query int    false "Page number (default 1)"
 // @Param       page_size   query int    false "Items per page (default 20, max 100)"
+// @Param       with_total  query bool   false "Compute total_items and total_pages (default true); when false they are -1 and 0"
 // @Param       account_id  query int    false "Filter by account ID"
-// @Param       from_date   query string false "Filter by start date (RFC3339 e.g. 2024-01-01T00:00:00Z, or YYYY-MM-DD)"
-// @Param       to_date     query string false "Filter by end date (RFC3339 or YYYY-MM-DD)"
+// @Param       from_date   query string false "Filter by start date (RFC3339 e.g. 2024-01-01T00:00:00Z, YYYY-MM-DD or unix seconds)"
+// @Param       to_date     query string false "Filter by end date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)"
 // @Param       type        query string false "Filter by transaction type (income, expense, transfer, investment)"
 // @Param       category_id query int    false "Filter by category ID"
 // @Param       min_amount  query int    false "Filter by minimum amount (cents)"
 // @Param       max_amount  query int    false "Filter by maximum amount (cents)"
+// @Param       status      query string false "Filter by reconciliation status (pending, cleared)"
+// @Param       pending     query bool   false "Filter by pending (true) or settled (false) status"
 // @Success     200 {object} pagination.PageResponse[models.Transaction] "Paginated transactions"
 // @Failure     400 {object} ErrorResponse "Invalid input"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
@@ -255,13 +454,19 @@ func (h *TransactionHandler) GetUserTransactions(c *gin.Context) {
 		return
 	}
 
-	var page pagination.PageRequest
-	if err := c.ShouldBindQuery(&page); err != nil {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+	page, err := bindPage(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	loc, err := h.transactionService.UserLocation(models.UserID(userID))
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	filter, err := parseTransactionFilter(c)
+	filter, err := parseTransactionFilter(c, loc)
 	if err != nil {
 		respondWithError(c, err)
 		return
@@ -270,31 +475,140 @@ func (h *TransactionHandler) GetUserTransactions(c *gin.Context) {
 	if v := c.Query("account_id"); v != "" {
 		filter.AccountID = &v
 	}
+	if v := c.Query("pending"); v != "" {
+		pending, parseErr := strconv.ParseBool(v)
+		if parseErr != nil {
+			respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "pending must be true or false"))
+			return
+		}
+		filter.Pending = &pending
+	}
 
-	result, err := h.transactionService.GetUserTransactions(userID, page, filter)
+	result, err := h.transactionService.GetUserTransactions(models.UserID(userID), page, filter)
 	if err != nil {
 		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	respondWithPage(c, page, result)
+}
+
+// GetTransfers handles the retrieval of the authenticated user's transfers
+// @Summary     Get transfers
+// @Description Get a paginated list of transfers between the user's accounts, each with both the source (account) and destination (to_account) preloaded
+// @Tags        transactions
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       page       query int    false "Page number (default 1)"
+// @Param       page_size  query int    false "Items per page (default 20, max 100)"
+// @Param       with_total query bool   false "Compute total_items and total_pages (default true); when false they are -1 and 0"
+// @Param       account_id query string false "Only transfers into or out of this account"
+// @Param       from_date  query string false "Filter by start date (RFC3339 e.g. 2024-01-01T00:00:00Z, YYYY-MM-DD or unix seconds)"
+// @Param       to_date    query string false "Filter by end date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)"
+// @Param       min_amount query int    false "Filter by minimum amount (cents)"
+// @Param       max_amount query int    false "Filter by maximum amount (cents)"
+// @Success     200 {object} pagination.PageResponse[models.Transaction] "Paginated transfers"
+// @Failure     400 {object} ErrorResponse "Invalid input"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /transactions/transfers [get]
+func (h *TransactionHandler) GetTransfers(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	page, err := bindPage(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	loc, err := h.transactionService.UserLocation(models.UserID(userID))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	filter, err := parseTransactionFilter(c, loc)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if filter.Type != nil && *filter.Type != models.TransactionTypeTransfer {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "only transfers are listed here, use /transactions for other types"))
+		return
+	}
+
+	if v := c.Query("account_id"); v != "" {
+		filter.AccountID = &v
+	}
+
+	result, err := h.transactionService.GetTransfers(models.UserID(userID), page, filter)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	respondWithPage(c, page, result)
+}
+
+// GetFlaggedTransactions handles the retrieval of the authenticated user's large transactions
+// @Summary     Get flagged transactions
+// @Description Get a paginated list of income and expenses from the last 90 days at or above the user's large-transaction threshold. Empty when no threshold is set.
+// @Tags        transactions
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       page      query int false "Page number (default 1)"
+// @Param       page_size query int false "Items per page (default 20, max 100)"
+// @Param       with_total query bool false "Compute total_items and total_pages (default true); when false they are -1 and 0"
+// @Success     200 {object} pagination.PageResponse[models.Transaction] "Paginated flagged transactions"
+// @Failure     400 {object} ErrorResponse "Invalid input"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /transactions/flagged [get]
+func (h *TransactionHandler) GetFlaggedTransactions(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	page, err := bindPage(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	result, err := h.transactionService.GetFlaggedTransactions(models.UserID(userID), page)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	respondWithPage(c, page, result)
 }
 
-func parseTransactionFilter(c *gin.Context) (services.TransactionFilter, error) {
+// parseTransactionFilter reads the list filters from the query string. Date-only
+// bounds are read in loc.
+func parseTransactionFilter(c *gin.Context, loc *time.Location) (services.TransactionFilter, error) {
 	var filter services.TransactionFilter
 
 	if v := c.Query("from_date"); v != "" {
-		t, err := parseFlexibleTime(v)
+		t, err := parseDateParam("from_date", v, rangeStart, loc)
 		if err != nil {
-			return filter, apperrors.WithMessage(apperrors.ErrInvalidInput, "invalid from_date format, use RFC3339 or YYYY-MM-DD")
+			return filter, err
 		}
 		filter.FromDate = &t
 	}
 
 	if v := c.Query("to_date"); v != "" {
-		t, err := parseFlexibleTime(v)
+		t, err := parseDateParam("to_date", v, rangeEnd, loc)
 		if err != nil {
-			return filter, apperrors.WithMessage(apperrors.ErrInvalidInput, "invalid to_date format, use RFC3339 or YYYY-MM-DD")
+			return filter, err
 		}
 		filter.ToDate = &t
 	}
@@ -314,6 +628,14 @@ func parseTransactionFilter(c *gin.Context) (services.TransactionFilter, error)
 		filter.CategoryID = &v
 	}
 
+	if v := c.Query("uncategorized"); v != "" {
+		uncategorized, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, apperrors.WithMessage(apperrors.ErrInvalidInput, "invalid uncategorized, must be true or false")
+		}
+		filter.Uncategorized = uncategorized
+	}
+
 	if v := c.Query("min_amount"); v != "" {
 		amt, err := strconv.ParseInt(v, 10, 64)
 		if err != nil {
@@ -330,6 +652,16 @@ func parseTransactionFilter(c *gin.Context) (services.TransactionFilter, error)
 		filter.MaxAmount = &amt
 	}
 
+	if v := c.Query("status"); v != "" {
+		status := models.TransactionStatus(v)
+		switch status {
+		case models.TransactionStatusPending, models.TransactionStatusCleared:
+			filter.Status = &status
+		default:
+			return filter, apperrors.WithMessage(apperrors.ErrInvalidInput, "invalid status, must be pending or cleared")
+		}
+	}
+
 	return filter, nil
 }
 
@@ -360,7 +692,7 @@ func (h *TransactionHandler) GetTransactionByID(c *gin.Context) {
 		return
 	}
 
-	transaction, err := h.transactionService.GetTransactionByID(userID, transactionID)
+	transaction, err := h.transactionService.GetTransactionByID(models.UserID(userID), models.TransactionID(transactionID))
 	if err != nil {
 		respondWithError(c, err)
 		return
@@ -371,29 +703,37 @@ func (h *TransactionHandler) GetTransactionByID(c *gin.Context) {
 
 // UpdateTransactionRequest represents the request payload for updating a transaction.
 type UpdateTransactionRequest struct {
-	AccountID   *string                 `json:"account_id"`
-	CategoryID  *string                 `json:"category_id"`
-	Type        *models.TransactionType `json:"type" binding:"omitempty,transaction_type"`
-	Amount      *int64                  `json:"amount" binding:"omitempty,gt=0"`
-	Description *string                 `json:"description" binding:"omitempty,max=500"`
-	Date        *string                 `json:"date"`
+	AccountID     *string                 `json:"account_id"`
+	CategoryID    *string                 `json:"category_id"`
+	Type          *models.TransactionType `json:"type" binding:"omitempty,transaction_type"`
+	Amount        *int64                  `json:"amount" binding:"omitempty,gt=0"`
+	AmountDecimal *string                 `json:"amount_decimal"` // alternative to amount, in the transaction account's currency
+	Description   *string                 `json:"description" binding:"omitempty,max=500"`
+	Date          *string                 `json:"date"`
+	Status        *models.TransactionStatus `json:"status" binding:"omitempty,transaction_status"`
+	// OriginalAmount and OriginalCurrency are set together; an empty
+	// original_currency alone clears them.
+	OriginalAmount   *int64  `json:"original_amount" binding:"omitempty,gt=0"`
+	OriginalCurrency *string `json:"original_currency"`
 }
 
 // UpdateTransaction handles updating an existing transaction
 // @Summary     Update transaction
-// @Description Update an existing transaction. Only income/expense transactions can be edited. Transfer and investment transactions cannot be modified.
+// @Description Partially update an existing transaction; only the fields sent are changed and at least one is required. Only income/expense transactions can be edited. Transfer and investment transactions cannot be modified, except for a status-only update marking them pending or cleared. Dates accept RFC3339, YYYY-MM-DD or unix seconds.
 // @Tags        transactions
 // @Accept      json
 // @Produce     json
 // @Security    BearerAuth
 // @Param       id      path int                     true "Transaction ID"
 // @Param       request body UpdateTransactionRequest true "Fields to update"
+// @Param       strict  query bool                     false "Reject unknown fields in the body"
 // @Success     200 {object} TransactionResponse "Updated transaction"
 // @Failure     400 {object} ErrorResponse "Invalid input or non-editable transaction"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
 // @Failure     404 {object} ErrorResponse "Transaction not found"
 // @Failure     500 {object} ErrorResponse "Server error"
 // @Router      /transactions/{id} [put]
+// @Router      /transactions/{id} [patch]
 func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
 	userID, err := getUserID(c)
 	if err != nil {
@@ -408,16 +748,45 @@ func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
 	}
 
 	var req UpdateTransactionRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, err.Error()))
+	if err := bindJSON(c, &req); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	if req.AccountID == nil && req.CategoryID == nil && req.Type == nil && req.Amount == nil &&
+		req.AmountDecimal == nil && req.Description == nil && (req.Date == nil || *req.Date == "") && req.Status == nil &&
+		req.OriginalAmount == nil && req.OriginalCurrency == nil {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "no fields provided"))
+		return
+	}
+
+	amount, err := resolveOptionalAmount("amount", req.Amount, req.AmountDecimal, func() (string, error) {
+		accountID := ""
+		if req.AccountID != nil {
+			accountID = *req.AccountID
+		} else {
+			existing, lookupErr := h.transactionService.GetTransactionByID(models.UserID(userID), models.TransactionID(txID))
+			if lookupErr != nil {
+				return "", lookupErr
+			}
+			accountID = existing.AccountID
+		}
+		return h.accountCurrency(userID, accountID)()
+	})
+	if err == nil && amount != nil {
+		err = requirePositiveAmount("amount", *amount)
+	}
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
 	updateFields := services.TransactionUpdateFields{
 		AccountID:   req.AccountID,
 		Type:        req.Type,
-		Amount:      req.Amount,
+		Amount:      amount,
 		Description: req.Description,
+		Status:      req.Status,
 	}
 
 	// Handle CategoryID: nil in JSON = don't change; empty string = clear; non-empty = set
@@ -430,17 +799,39 @@ func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
 		}
 	}
 
+	// Handle the original amount: empty original_currency alone = clear; otherwise both are set
+	if req.OriginalCurrency != nil && *req.OriginalCurrency == "" && req.OriginalAmount == nil {
+		var cleared *services.OriginalAmount
+		updateFields.Original = &cleared
+	} else if req.OriginalAmount != nil || req.OriginalCurrency != nil {
+		if req.OriginalCurrency != nil && !validator.IsISO4217(*req.OriginalCurrency) {
+			respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "original_currency must be an ISO 4217 code"))
+			return
+		}
+		original, parseErr := parseOriginalAmount(req.OriginalAmount, req.OriginalCurrency)
+		if parseErr != nil {
+			respondWithError(c, parseErr)
+			return
+		}
+		updateFields.Original = &original
+	}
+
 	// Parse date if provided
 	if req.Date != nil && *req.Date != "" {
-		parsed, parseErr := parseFlexibleTime(*req.Date)
+		loc, locErr := h.transactionService.UserLocation(models.UserID(userID))
+		if locErr != nil {
+			respondWithError(c, locErr)
+			return
+		}
+		parsed, parseErr := parseDateParam("date", *req.Date, rangeStart, loc)
 		if parseErr != nil {
-			respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, parseErr.Error()))
+			respondWithError(c, parseErr)
 			return
 		}
 		updateFields.Date = &parsed
 	}
 
-	transaction, err := h.transactionService.UpdateTransaction(userID, txID, updateFields)
+	transaction, err := h.transactionService.UpdateTransaction(models.UserID(userID), models.TransactionID(txID), updateFields)
 	if err != nil {
 		respondWithError(c, err)
 		return
@@ -451,6 +842,18 @@ func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"transaction": transaction})
 }
 
+// parseOriginalAmount pairs an optional original amount with its currency,
+// requiring both or neither.
+func parseOriginalAmount(amount *int64, currency *string) (*services.OriginalAmount, error) {
+	if amount == nil && currency == nil {
+		return nil, nil
+	}
+	if amount == nil || currency == nil {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "original_amount and original_currency must be set together")
+	}
+	return &services.OriginalAmount{Amount: models.Cents(*amount), Currency: *currency}, nil
+}
+
 // DeleteTransaction handles the deletion of a transaction
 // @Summary     Delete transaction
 // @Description Delete a transaction by ID
@@ -478,7 +881,7 @@ func (h *TransactionHandler) DeleteTransaction(c *gin.Context) {
 		return
 	}
 
-	if err := h.transactionService.DeleteTransaction(userID, transactionID); err != nil {
+	if err := h.transactionService.DeleteTransaction(models.UserID(userID), models.TransactionID(transactionID)); err != nil {
 		respondWithError(c, err)
 		return
 	}
@@ -488,19 +891,230 @@ func (h *TransactionHandler) DeleteTransaction(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Transaction deleted successfully"})
 }
 
-// GetSpendingByCategory handles the retrieval of expense totals grouped by category
+// BulkFilterRequest selects transactions for a bulk delete or recategorize. The date range is required.
+type BulkFilterRequest struct {
+	FromDate      string                  `json:"from_date" binding:"required"`
+	ToDate        string                  `json:"to_date" binding:"required"`
+	Type          *models.TransactionType `json:"type" binding:"omitempty,transaction_type"`
+	CategoryID    *string                 `json:"category_id"`
+	Uncategorized bool                    `json:"uncategorized"` // only transactions without a category
+	MinAmount     *int64                  `json:"min_amount"`
+	MaxAmount     *int64                  `json:"max_amount"`
+	AccountID     *string                 `json:"account_id"`
+	Pending       *bool                   `json:"pending"`
+}
+
+// transactionFilter parses the date range, reading date-only bounds in loc, and
+// converts the request to a service filter.
+func (r *BulkFilterRequest) transactionFilter(loc *time.Location) (*services.TransactionFilter, error) {
+	from, err := parseDateParam("from_date", r.FromDate, rangeStart, loc)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseDateParam("to_date", r.ToDate, rangeEnd, loc)
+	if err != nil {
+		return nil, err
+	}
+	if r.CategoryID != nil && r.Uncategorized {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "category_id and uncategorized cannot be combined")
+	}
+	return &services.TransactionFilter{
+		FromDate:      &from,
+		ToDate:        &to,
+		Type:          r.Type,
+		CategoryID:    r.CategoryID,
+		Uncategorized: r.Uncategorized,
+		MinAmount:     r.MinAmount,
+		MaxAmount:     r.MaxAmount,
+		AccountID:     r.AccountID,
+		Pending:       r.Pending,
+	}, nil
+}
+
+// BulkDeleteTransactionsRequest represents the request payload for a bulk delete.
+// A dry run (the default) takes ids or filter; executing takes only the
+// confirmation_token returned by the dry run.
+type BulkDeleteTransactionsRequest struct {
+	DryRun            *bool                    `json:"dry_run"`
+	IDs               []string                 `json:"ids" binding:"omitempty,max=10000"`
+	Filter            *BulkFilterRequest `json:"filter"`
+	ConfirmationToken string                   `json:"confirmation_token"`
+}
+
+// bulkDeleteSelection converts the request's ids or filter to a service selection.
+func (r *BulkDeleteTransactionsRequest) bulkDeleteSelection(loc *time.Location) (services.BulkDeleteSelection, error) {
+	var selection services.BulkDeleteSelection
+	for _, id := range r.IDs {
+		if !uuid.IsValid(id) {
+			return selection, apperrors.WithMessage(apperrors.ErrInvalidInput, "Invalid transaction id format: "+id)
+		}
+	}
+	selection.IDs = r.IDs
+
+	if r.Filter != nil {
+		filter, err := r.Filter.transactionFilter(loc)
+		if err != nil {
+			return selection, err
+		}
+		selection.Filter = filter
+	}
+	return selection, nil
+}
+
+// BulkDeleteTransactions handles deleting many transactions in two steps
+// @Summary     Bulk delete transactions
+// @Description With dry_run true (the default), count and total the transactions matched by ids or a filter (from_date and to_date required) and return a confirmation token valid for 15 minutes. With dry_run false and that confirmation_token, delete them and reverse their balances. The delete is refused if the selection changed since the dry run.
+// @Tags        transactions
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       request body BulkDeleteTransactionsRequest true "Bulk delete request"
+// @Success     200 {object} services.BulkDeletePreview "Dry run preview"
+// @Success     200 {object} map[string]interface{} "Number of transactions deleted"
+// @Failure     400 {object} ErrorResponse "Invalid input or confirmation token"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     403 {object} ErrorResponse "Read-only access to an account"
+// @Failure     404 {object} ErrorResponse "Transaction not found"
+// @Failure     409 {object} ErrorResponse "Transactions changed since the dry run"
+// @Failure     410 {object} ErrorResponse "Confirmation token expired"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /transactions/bulk-delete [post]
+func (h *TransactionHandler) BulkDeleteTransactions(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	var req BulkDeleteTransactionsRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	if req.DryRun == nil || *req.DryRun {
+		loc, err := h.transactionService.UserLocation(models.UserID(userID))
+		if err != nil {
+			respondWithError(c, err)
+			return
+		}
+		selection, err := req.bulkDeleteSelection(loc)
+		if err != nil {
+			respondWithError(c, err)
+			return
+		}
+		preview, err := h.transactionService.PreviewBulkDelete(models.UserID(userID), selection)
+		if err != nil {
+			respondWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, preview)
+		return
+	}
+
+	if req.ConfirmationToken == "" {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "confirmation_token from a dry run is required"))
+		return
+	}
+	if len(req.IDs) > 0 || req.Filter != nil {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "ids and filter are taken from the dry run; send only confirmation_token"))
+		return
+	}
+
+	result, err := h.transactionService.ExecuteBulkDelete(models.UserID(userID), req.ConfirmationToken)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	changes := map[string]interface{}{"count": result.Deleted}
+	if result.Selection.Filter != nil {
+		changes["filter"] = result.Selection.Filter
+	} else {
+		changes["ids"] = result.Selection.IDs
+	}
+	h.auditService.Log(userID, "BULK_DELETE_TRANSACTIONS", "transaction", "", c.ClientIP(), changes)
+
+	c.JSON(http.StatusOK, gin.H{"deleted": result.Deleted})
+}
+
+// BulkCategorizeTransactionsRequest represents the request payload for a bulk recategorize.
+// A null or missing category_id removes the category.
+type BulkCategorizeTransactionsRequest struct {
+	Filter     BulkFilterRequest `json:"filter"`
+	CategoryID *string           `json:"category_id"`
+}
+
+// BulkCategorizeTransactions handles setting the category of many transactions at once
+// @Summary     Bulk recategorize transactions
+// @Description Set category_id on every income and expense on the user's own accounts matched by the filter (from_date and to_date required; uncategorized true matches transactions without a category). Only transactions of the category's type are changed, and a filter on the other type is rejected with CATEGORY_TYPE_MISMATCH. A null category_id removes the category. Balances are unaffected.
+// @Tags        transactions
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       request body BulkCategorizeTransactionsRequest true "Filter and category"
+// @Success     200 {object} map[string]interface{} "Number of transactions updated"
+// @Failure     400 {object} ErrorResponse "Invalid input or category type mismatch"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     404 {object} ErrorResponse "Category not found"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /transactions/bulk-categorize [post]
+func (h *TransactionHandler) BulkCategorizeTransactions(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	var req BulkCategorizeTransactionsRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if req.CategoryID != nil && !uuid.IsValid(*req.CategoryID) {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "Invalid category_id format"))
+		return
+	}
+
+	loc, err := h.transactionService.UserLocation(models.UserID(userID))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	filter, err := req.Filter.transactionFilter(loc)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	updated, err := h.transactionService.BulkUpdateCategory(models.UserID(userID), *filter, req.CategoryID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.auditService.Log(userID, "BULK_CATEGORIZE_TRANSACTIONS", "transaction", "", c.ClientIP(),
+		map[string]interface{}{"count": updated, "filter": filter, "category_id": req.CategoryID})
+
+	c.JSON(http.StatusOK, gin.H{"updated": updated})
+}
+
+// GetSpendingByCategory handles the retrieval of expense or income totals grouped by category
 // @Summary     Get spending by category
-// @Description Get expense totals grouped by category for a date range
+// @Description Get expense totals, or income totals with mode=income, grouped by category for a date range
 // @Tags        transactions
 // @Accept      json
 // @Produce     json
 // @Security    BearerAuth
-// @Param       from_date query string true "Start date (RFC3339 or YYYY-MM-DD)"
-// @Param       to_date   query string true "End date (RFC3339 or YYYY-MM-DD)"
+// @Param       from_date query string true "Start date (RFC3339, YYYY-MM-DD or unix seconds)"
+// @Param       to_date   query string true "End date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)"
+// @Param       mode query string false "expense (default) or income"
+// @Param       include_excluded query bool false "Include accounts excluded from reports"
 // @Success     200 {object} services.SpendingByCategory "Spending breakdown by category"
 // @Failure     400 {object} ErrorResponse "Invalid input"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
 // @Failure     500 {object} ErrorResponse "Server error"
+// @Failure     503 {object} ErrorResponse "Request timed out"
 // @Router      /transactions/spending-by-category [get]
 func (h *TransactionHandler) GetSpendingByCategory(c *gin.Context) {
 	userID, err := getUserID(c)
@@ -521,19 +1135,160 @@ func (h *TransactionHandler) GetSpendingByCategory(c *gin.Context) {
 		return
 	}
 
-	fromTime, parseErr := parseFlexibleTime(fromStr)
+	loc, err := h.transactionService.UserLocation(models.UserID(userID))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	fromTime, parseErr := parseDateParam("from_date", fromStr, rangeStart, loc)
 	if parseErr != nil {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, parseErr.Error()))
+		respondWithError(c, parseErr)
 		return
 	}
 
-	toTime, parseErr := parseFlexibleTime(toStr)
+	toTime, parseErr := parseDateParam("to_date", toStr, rangeEnd, loc)
 	if parseErr != nil {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, parseErr.Error()))
+		respondWithError(c, parseErr)
 		return
 	}
 
-	result, err := h.transactionService.GetSpendingByCategory(userID, fromTime, toTime)
+	mode := services.SpendingMode(c.DefaultQuery("mode", string(services.SpendingModeExpense)))
+	if mode != services.SpendingModeExpense && mode != services.SpendingModeIncome {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "mode must be expense or income"))
+		return
+	}
+
+	includeExcluded, _ := strconv.ParseBool(c.Query("include_excluded"))
+
+	result, err := h.transactionService.GetSpendingByCategory(c.Request.Context(), models.UserID(userID), fromTime, toTime, mode, includeExcluded)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetSpendingByAccount handles the retrieval of expense totals grouped by account
+// @Summary     Get spending by account
+// @Description Get expense totals and counts per account for a date range, largest first. Transfers are not counted; inactive accounts with spending are included.
+// @Tags        reports
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       from_date query string true "Start date (RFC3339, YYYY-MM-DD or unix seconds)"
+// @Param       to_date   query string true "End date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)"
+// @Param       include_excluded query bool false "Include accounts excluded from reports"
+// @Success     200 {object} services.SpendingByAccount "Spending breakdown by account"
+// @Failure     400 {object} ErrorResponse "Invalid input"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Router      /reports/spending-by-account [get]
+func (h *TransactionHandler) GetSpendingByAccount(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	fromStr := c.Query("from_date")
+	if fromStr == "" {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "from_date is required"))
+		return
+	}
+
+	toStr := c.Query("to_date")
+	if toStr == "" {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "to_date is required"))
+		return
+	}
+
+	loc, err := h.transactionService.UserLocation(models.UserID(userID))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	fromTime, parseErr := parseDateParam("from_date", fromStr, rangeStart, loc)
+	if parseErr != nil {
+		respondWithError(c, parseErr)
+		return
+	}
+
+	toTime, parseErr := parseDateParam("to_date", toStr, rangeEnd, loc)
+	if parseErr != nil {
+		respondWithError(c, parseErr)
+		return
+	}
+
+	includeExcluded, _ := strconv.ParseBool(c.Query("include_excluded"))
+
+	result, err := h.transactionService.GetSpendingByAccount(models.UserID(userID), fromTime, toTime, includeExcluded)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetSpendingByCurrency handles the retrieval of expense totals grouped by spending currency
+// @Summary     Get spending by currency
+// @Description Get expense totals per currency for a date range, largest first. Expenses with an original amount count under their original currency, others under their account's currency. original_total is in that currency; total is as booked in the accounts' currencies.
+// @Tags        transactions
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       from_date query string true "Start date (RFC3339, YYYY-MM-DD or unix seconds)"
+// @Param       to_date   query string true "End date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)"
+// @Param       include_excluded query bool false "Include accounts excluded from reports"
+// @Success     200 {object} services.SpendingByCurrency "Spending breakdown by currency"
+// @Failure     400 {object} ErrorResponse "Invalid input"
+// @Failure     401 {object} ErrorResponse "Unauthorized"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Failure     503 {object} ErrorResponse "Request timed out"
+// @Router      /transactions/spending-by-currency [get]
+func (h *TransactionHandler) GetSpendingByCurrency(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	fromStr := c.Query("from_date")
+	if fromStr == "" {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "from_date is required"))
+		return
+	}
+
+	toStr := c.Query("to_date")
+	if toStr == "" {
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "to_date is required"))
+		return
+	}
+
+	loc, err := h.transactionService.UserLocation(models.UserID(userID))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	fromTime, parseErr := parseDateParam("from_date", fromStr, rangeStart, loc)
+	if parseErr != nil {
+		respondWithError(c, parseErr)
+		return
+	}
+
+	toTime, parseErr := parseDateParam("to_date", toStr, rangeEnd, loc)
+	if parseErr != nil {
+		respondWithError(c, parseErr)
+		return
+	}
+
+	includeExcluded, _ := strconv.ParseBool(c.Query("include_excluded"))
+
+	result, err := h.transactionService.GetSpendingByCurrency(c.Request.Context(), models.UserID(userID), fromTime, toTime, includeExcluded)
 	if err != nil {
 		respondWithError(c, err)
 		return
@@ -549,10 +1304,14 @@ func (h *TransactionHandler) GetSpendingByCategory(c *gin.Context) {
 // @Accept      json
 // @Produce     json
 // @Security    BearerAuth
-// @Param       months query int false "Number of months back (default 6, min 1, max 24)"
+// @Param       months          query int  false "Number of months back (default 6 unless configured, min 1, max 36)"
+// @Param       with_categories query bool false "Include per-category expense breakdown for each month"
+// @Param       include_excluded query bool false "Include accounts excluded from reports"
 // @Success     200 {object} map[string]interface{} "Monthly summary data"
+// @Failure     400 {object} ErrorResponse "Invalid months"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
 // @Failure     500 {object} ErrorResponse "Server error"
+// @Failure     503 {object} ErrorResponse "Request timed out"
 // @Router      /transactions/monthly-summary [get]
 func (h *TransactionHandler) GetMonthlySummary(c *gin.Context) {
 	userID, err := getUserID(c)
@@ -561,21 +1320,21 @@ func (h *TransactionHandler) GetMonthlySummary(c *gin.Context) {
 		return
 	}
 
-	months := 6
+	months := h.defaultSummaryMonths
 	if v := c.Query("months"); v != "" {
 		parsed, parseErr := strconv.Atoi(v)
-		if parseErr == nil {
-			months = parsed
+		if parseErr != nil || parsed < 1 || parsed > MaxSummaryMonths {
+			respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput,
+				"months must be a whole number from 1 to "+strconv.Itoa(MaxSummaryMonths)))
+			return
 		}
+		months = parsed
 	}
-	if months < 1 {
-		months = 1
-	}
-	if months > 24 {
-		months = 24
-	}
 
-	result, err := h.transactionService.GetMonthlySummary(userID, months)
+	withCategories, _ := strconv.ParseBool(c.Query("with_categories"))
+	includeExcluded, _ := strconv.ParseBool(c.Query("include_excluded"))
+
+	result, err := h.transactionService.GetMonthlySummary(c.Request.Context(), models.UserID(userID), months, withCategories, includeExcluded)
 	if err != nil {
 		respondWithError(c, err)
 		return
@@ -586,17 +1345,20 @@ func (h *TransactionHandler) GetMonthlySummary(c *gin.Context) {
 
 // GetDailySpending handles the retrieval of daily expense totals
 // @Summary     Get daily spending
-// @Description Get daily expense totals for a date range
+// @Description Get expense totals for a date range per day, week or month; empty buckets are zero and each is labelled with its first day. Weeks begin on the user's week start.
 // @Tags        transactions
 // @Accept      json
 // @Produce     json
 // @Security    BearerAuth
-// @Param       from_date query string true "Start date (RFC3339 or YYYY-MM-DD)"
-// @Param       to_date   query string true "End date (RFC3339 or YYYY-MM-DD)"
+// @Param       from_date query string true "Start date (RFC3339, YYYY-MM-DD or unix seconds)"
+// @Param       to_date   query string true "End date, inclusive (RFC3339, YYYY-MM-DD or unix seconds)"
+// @Param       granularity query string false "Bucket size: day (default, range up to 366 days), week or month (range up to 36 months)"
+// @Param       include_excluded query bool false "Include accounts excluded from reports"
 // @Success     200 {object} map[string]interface{} "Daily spending data"
 // @Failure     400 {object} ErrorResponse "Invalid input"
 // @Failure     401 {object} ErrorResponse "Unauthorized"
 // @Failure     500 {object} ErrorResponse "Server error"
+// @Failure     503 {object} ErrorResponse "Request timed out"
 // @Router      /transactions/daily-spending [get]
 func (h *TransactionHandler) GetDailySpending(c *gin.Context) {
 	userID, err := getUserID(c)
@@ -617,24 +1379,45 @@ func (h *TransactionHandler) GetDailySpending(c *gin.Context) {
 		return
 	}
 
-	fromTime, parseErr := parseFlexibleTime(fromStr)
+	loc, err := h.transactionService.UserLocation(models.UserID(userID))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	fromTime, parseErr := parseDateParam("from_date", fromStr, rangeStart, loc)
 	if parseErr != nil {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, parseErr.Error()))
+		respondWithError(c, parseErr)
 		return
 	}
 
-	toTime, parseErr := parseFlexibleTime(toStr)
+	toTime, parseErr := parseDateParam("to_date", toStr, rangeEnd, loc)
 	if parseErr != nil {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, parseErr.Error()))
+		respondWithError(c, parseErr)
 		return
 	}
 
-	if toTime.Sub(fromTime).Hours() > 366*24 {
-		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "date range cannot exceed 366 days"))
+	granularity := services.SpendingGranularity(c.DefaultQuery("granularity", string(services.SpendingGranularityDay)))
+	switch granularity {
+	case services.SpendingGranularityDay:
+		if toTime.Sub(fromTime).Hours() > 366*24 {
+			respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "date range cannot exceed 366 days"))
+			return
+		}
+	case services.SpendingGranularityWeek, services.SpendingGranularityMonth:
+		if toTime.After(fromTime.AddDate(0, MaxSummaryMonths, 0)) {
+			respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput,
+				"date range cannot exceed "+strconv.Itoa(MaxSummaryMonths)+" months"))
+			return
+		}
+	default:
+		respondWithError(c, apperrors.WithMessage(apperrors.ErrInvalidInput, "granularity must be day, week or month"))
 		return
 	}
 
-	result, err := h.transactionService.GetDailySpending(userID, fromTime, toTime)
+	includeExcluded, _ := strconv.ParseBool(c.Query("include_excluded"))
+
+	result, err := h.transactionService.GetDailySpending(c.Request.Context(), models.UserID(userID), fromTime, toTime, granularity, includeExcluded)
 	if err != nil {
 		respondWithError(c, err)
 		return
@@ -647,3 +1430,24 @@ func (h *TransactionHandler) GetDailySpending(c *gin.Context) {
 type MessageResponse struct {
 	Message string `json:"message"`
 }
+
+// SettlePendingTransactions handles settling pending transactions whose date has arrived.
+// @Summary     Settle pending transactions
+// @Description Apply pending transactions dated at or before now to account balances (pipeline endpoint)
+// @Tags        pipeline
+// @Produce     json
+// @Security    ApiKeyAuth
+// @Success     200 {object} map[string]int "Settled transactions count"
+// @Failure     401 {object} ErrorResponse "Invalid API key"
+// @Failure     500 {object} ErrorResponse "Server error"
+// @Failure     503 {object} ErrorResponse "Pipeline not configured"
+// @Router      /pipeline/transactions/settle [post]
+func (h *TransactionHandler) SettlePendingTransactions(c *gin.Context) {
+	count, err := h.transactionService.SettlePendingTransactions(time.Now())
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settled": count})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"testing"
 	"time"
@@ -16,126 +17,218 @@ import (
 // --- mock transaction service ---
 
 type mockTransactionService struct {
-	createTransactionFn      func(userID, accountID uint, categoryID *uint, transactionType models.TransactionType, amount int64, description string, date time.Time) (*models.Transaction, error)
-	createTransferFn         func(userID, fromAccountID, toAccountID uint, amount int64, description string, date time.Time) (*models.Transaction, error)
-	getAccountTransactionsFn func(userID, accountID uint, page pagination.PageRequest, filter services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error)
-	getUserTransactionsFn    func(userID uint, page pagination.PageRequest, filter services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error)
-	getTransactionByIDFn     func(userID, transactionID uint) (*models.Transaction, error)
-	updateTransactionFn      func(userID, transactionID uint, updates services.TransactionUpdateFields) (*models.Transaction, error)
-	deleteTransactionFn      func(userID, transactionID uint) error
-	getSpendingByCategoryFn  func(userID uint, from, to time.Time) (*services.SpendingByCategory, error)
-	getMonthlySummaryFn      func(userID uint, months int) ([]services.MonthlySummaryItem, error)
-	getDailySpendingFn       func(userID uint, from, to time.Time) ([]services.DailySpendingItem, error)
-}
-
-func (m *mockTransactionService) CreateTransaction(userID, accountID uint, categoryID *uint, transactionType models.TransactionType, amount int64, description string, date time.Time) (*models.Transaction, error) {
+	createTransactionFn      func(userID models.UserID, accountID models.AccountID, categoryID *string, transactionType models.TransactionType, amount models.Cents, description string, date time.Time, pending bool, status models.TransactionStatus, original *services.OriginalAmount) (*models.Transaction, error)
+	createTransferFn         func(userID models.UserID, fromAccountID, toAccountID models.AccountID, amount models.Cents, description string, date time.Time) (*models.Transaction, error)
+	createSplitTransferFn    func(userID models.UserID, fromAccountID models.AccountID, legs []services.SplitTransferLeg, description string, date time.Time) ([]models.Transaction, error)
+	getAccountTransactionsFn func(userID models.UserID, accountID models.AccountID, page pagination.PageRequest, filter services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error)
+	getUserTransactionsFn    func(userID models.UserID, page pagination.PageRequest, filter services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error)
+	getTransfersFn           func(userID models.UserID, page pagination.PageRequest, filter services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error)
+	getTransactionByIDFn     func(userID models.UserID, transactionID models.TransactionID) (*models.Transaction, error)
+	getFlaggedFn             func(userID models.UserID, page pagination.PageRequest) (*pagination.PageResponse[models.Transaction], error)
+	previewBulkDeleteFn      func(userID models.UserID, selection services.BulkDeleteSelection) (*services.BulkDeletePreview, error)
+	executeBulkDeleteFn      func(userID models.UserID, confirmationToken string) (*services.BulkDeleteResult, error)
+	bulkUpdateCategoryFn     func(userID models.UserID, filter services.TransactionFilter, categoryID *string) (int64, error)
+	updateTransactionFn      func(userID models.UserID, transactionID models.TransactionID, updates services.TransactionUpdateFields) (*models.Transaction, error)
+	deleteTransactionFn      func(userID models.UserID, transactionID models.TransactionID) error
+	getSpendingByCategoryFn  func(userID models.UserID, from, to time.Time, mode services.SpendingMode, includeExcluded bool) (*services.SpendingByCategory, error)
+	getSpendingByAccountFn   func(userID models.UserID, from, to time.Time, includeExcluded bool) (*services.SpendingByAccount, error)
+	getSpendingByCurrencyFn  func(userID models.UserID, from, to time.Time, includeExcluded bool) (*services.SpendingByCurrency, error)
+	getMonthlySummaryFn      func(userID models.UserID, months int, withCategories, includeExcluded bool) ([]services.MonthlySummaryItem, error)
+	getDailySpendingFn       func(userID models.UserID, from, to time.Time, granularity services.SpendingGranularity, includeExcluded bool) ([]services.DailySpendingItem, error)
+	settlePendingFn          func(asOf time.Time) (int, error)
+	userLocationFn           func(userID models.UserID) (*time.Location, error)
+}
+
+func (m *mockTransactionService) CreateTransaction(userID models.UserID, accountID models.AccountID, categoryID *string, transactionType models.TransactionType, amount models.Cents, description string, date time.Time, pending bool, status models.TransactionStatus, original *services.OriginalAmount) (*models.Transaction, error) {
 	if m.createTransactionFn != nil {
-		return m.createTransactionFn(userID, accountID, categoryID, transactionType, amount, description, date)
+		return m.createTransactionFn(userID, accountID, categoryID, transactionType, amount, description, date, pending, status, original)
 	}
 	return &models.Transaction{}, nil
 }
 
-func (m *mockTransactionService) CreateTransfer(userID, fromAccountID, toAccountID uint, amount int64, description string, date time.Time) (*models.Transaction, error) {
+func (m *mockTransactionService) CreateTransfer(userID models.UserID, fromAccountID, toAccountID models.AccountID, amount models.Cents, description string, date time.Time) (*models.Transaction, error) {
 	if m.createTransferFn != nil {
 		return m.createTransferFn(userID, fromAccountID, toAccountID, amount, description, date)
 	}
 	return &models.Transaction{}, nil
 }
 
-func (m *mockTransactionService) GetAccountTransactions(userID, accountID uint, page pagination.PageRequest, filter services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
+func (m *mockTransactionService) CreateSplitTransfer(userID models.UserID, fromAccountID models.AccountID, legs []services.SplitTransferLeg, description string, date time.Time) ([]models.Transaction, error) {
+	if m.createSplitTransferFn != nil {
+		return m.createSplitTransferFn(userID, fromAccountID, legs, description, date)
+	}
+	return []models.Transaction{{}}, nil
+}
+
+func (m *mockTransactionService) GetAccountTransactions(userID models.UserID, accountID models.AccountID, page pagination.PageRequest, filter services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
 	if m.getAccountTransactionsFn != nil {
 		return m.getAccountTransactionsFn(userID, accountID, page, filter)
 	}
-	resp := pagination.NewPageResponse([]models.Transaction{}, 1, 20, 0)
+	resp := pagination.NewPageResponse([]models.Transaction{}, page.Page, page.PageSize, 0)
 	return &resp, nil
 }
 
-func (m *mockTransactionService) GetUserTransactions(userID uint, page pagination.PageRequest, filter services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
+func (m *mockTransactionService) GetUserTransactions(userID models.UserID, page pagination.PageRequest, filter services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
 	if m.getUserTransactionsFn != nil {
 		return m.getUserTransactionsFn(userID, page, filter)
 	}
-	resp := pagination.NewPageResponse([]models.Transaction{}, 1, 20, 0)
+	resp := pagination.NewPageResponse([]models.Transaction{}, page.Page, page.PageSize, 0)
+	return &resp, nil
+}
+
+func (m *mockTransactionService) GetTransfers(userID models.UserID, page pagination.PageRequest, filter services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
+	if m.getTransfersFn != nil {
+		return m.getTransfersFn(userID, page, filter)
+	}
+	resp := pagination.NewPageResponse([]models.Transaction{}, page.Page, page.PageSize, 0)
+	return &resp, nil
+}
+
+func (m *mockTransactionService) GetFlaggedTransactions(userID models.UserID, page pagination.PageRequest) (*pagination.PageResponse[models.Transaction], error) {
+	if m.getFlaggedFn != nil {
+		return m.getFlaggedFn(userID, page)
+	}
+	resp := pagination.NewPageResponse([]models.Transaction{}, page.Page, page.PageSize, 0)
 	return &resp, nil
 }
 
-func (m *mockTransactionService) GetTransactionByID(userID, transactionID uint) (*models.Transaction, error) {
+func (m *mockTransactionService) GetTransactionByID(userID models.UserID, transactionID models.TransactionID) (*models.Transaction, error) {
 	if m.getTransactionByIDFn != nil {
 		return m.getTransactionByIDFn(userID, transactionID)
 	}
 	return &models.Transaction{}, nil
 }
 
-func (m *mockTransactionService) UpdateTransaction(userID, transactionID uint, updates services.TransactionUpdateFields) (*models.Transaction, error) {
+func (m *mockTransactionService) UpdateTransaction(userID models.UserID, transactionID models.TransactionID, updates services.TransactionUpdateFields) (*models.Transaction, error) {
 	if m.updateTransactionFn != nil {
 		return m.updateTransactionFn(userID, transactionID, updates)
 	}
 	return &models.Transaction{}, nil
 }
 
-func (m *mockTransactionService) DeleteTransaction(userID, transactionID uint) error {
+func (m *mockTransactionService) DeleteTransaction(userID models.UserID, transactionID models.TransactionID) error {
 	if m.deleteTransactionFn != nil {
 		return m.deleteTransactionFn(userID, transactionID)
 	}
 	return nil
 }
 
-func (m *mockTransactionService) GetSpendingByCategory(userID uint, from, to time.Time) (*services.SpendingByCategory, error) {
+func (m *mockTransactionService) GetSpendingByCategory(_ context.Context, userID models.UserID, from, to time.Time, mode services.SpendingMode, includeExcluded bool) (*services.SpendingByCategory, error) {
 	if m.getSpendingByCategoryFn != nil {
-		return m.getSpendingByCategoryFn(userID, from, to)
+		return m.getSpendingByCategoryFn(userID, from, to, mode, includeExcluded)
 	}
 	return &services.SpendingByCategory{Items: []services.SpendingByCategoryItem{}}, nil
 }
 
-func (m *mockTransactionService) GetMonthlySummary(userID uint, months int) ([]services.MonthlySummaryItem, error) {
+func (m *mockTransactionService) GetSpendingByAccount(userID models.UserID, from, to time.Time, includeExcluded bool) (*services.SpendingByAccount, error) {
+	if m.getSpendingByAccountFn != nil {
+		return m.getSpendingByAccountFn(userID, from, to, includeExcluded)
+	}
+	return &services.SpendingByAccount{Items: []services.SpendingByAccountItem{}}, nil
+}
+
+func (m *mockTransactionService) GetSpendingByCurrency(_ context.Context, userID models.UserID, from, to time.Time, includeExcluded bool) (*services.SpendingByCurrency, error) {
+	if m.getSpendingByCurrencyFn != nil {
+		return m.getSpendingByCurrencyFn(userID, from, to, includeExcluded)
+	}
+	return &services.SpendingByCurrency{Items: []services.SpendingByCurrencyItem{}}, nil
+}
+
+func (m *mockTransactionService) GetMonthlySummary(_ context.Context, userID models.UserID, months int, withCategories, includeExcluded bool) ([]services.MonthlySummaryItem, error) {
 	if m.getMonthlySummaryFn != nil {
-		return m.getMonthlySummaryFn(userID, months)
+		return m.getMonthlySummaryFn(userID, months, withCategories, includeExcluded)
 	}
 	return []services.MonthlySummaryItem{}, nil
 }
 
-func (m *mockTransactionService) GetDailySpending(userID uint, from, to time.Time) ([]services.DailySpendingItem, error) {
+func (m *mockTransactionService) GetDailySpending(_ context.Context, userID models.UserID, from, to time.Time, granularity services.SpendingGranularity, includeExcluded bool) ([]services.DailySpendingItem, error) {
 	if m.getDailySpendingFn != nil {
-		return m.getDailySpendingFn(userID, from, to)
+		return m.getDailySpendingFn(userID, from, to, granularity, includeExcluded)
 	}
 	return []services.DailySpendingItem{}, nil
 }
 
+func (m *mockTransactionService) SettlePendingTransactions(asOf time.Time) (int, error) {
+	if m.settlePendingFn != nil {
+		return m.settlePendingFn(asOf)
+	}
+	return 0, nil
+}
+
+func (m *mockTransactionService) PreviewBulkDelete(userID models.UserID, selection services.BulkDeleteSelection) (*services.BulkDeletePreview, error) {
+	if m.previewBulkDeleteFn != nil {
+		return m.previewBulkDeleteFn(userID, selection)
+	}
+	return &services.BulkDeletePreview{}, nil
+}
+
+func (m *mockTransactionService) ExecuteBulkDelete(userID models.UserID, confirmationToken string) (*services.BulkDeleteResult, error) {
+	if m.executeBulkDeleteFn != nil {
+		return m.executeBulkDeleteFn(userID, confirmationToken)
+	}
+	return &services.BulkDeleteResult{}, nil
+}
+
+func (m *mockTransactionService) BulkUpdateCategory(userID models.UserID, filter services.TransactionFilter, categoryID *string) (int64, error) {
+	if m.bulkUpdateCategoryFn != nil {
+		return m.bulkUpdateCategoryFn(userID, filter, categoryID)
+	}
+	return 0, nil
+}
+
+func (m *mockTransactionService) UserLocation(userID models.UserID) (*time.Location, error) {
+	if m.userLocationFn != nil {
+		return m.userLocationFn(userID)
+	}
+	return time.UTC, nil
+}
+
 var _ services.TransactionServicer = (*mockTransactionService)(nil)
 
 func setupTransactionRouter(handler *TransactionHandler) *gin.Engine {
 	r := gin.New()
-	auth := r.Group("", injectUserID(1))
+	auth := r.Group("", injectUserID(testID(1)))
 	auth.GET("/transactions", handler.GetUserTransactions)
 	auth.POST("/transactions", handler.CreateTransaction)
 	auth.POST("/transactions/transfer", handler.CreateTransfer)
+	auth.POST("/transactions/split-transfer", handler.CreateSplitTransfer)
+	auth.POST("/transactions/bulk-delete", handler.BulkDeleteTransactions)
+	auth.POST("/transactions/bulk-categorize", handler.BulkCategorizeTransactions)
 	auth.GET("/transactions/spending-by-category", handler.GetSpendingByCategory)
+	auth.GET("/transactions/spending-by-currency", handler.GetSpendingByCurrency)
+	auth.GET("/reports/spending-by-account", handler.GetSpendingByAccount)
 	auth.GET("/transactions/monthly-summary", handler.GetMonthlySummary)
 	auth.GET("/transactions/daily-spending", handler.GetDailySpending)
+	auth.GET("/transactions/flagged", handler.GetFlaggedTransactions)
+	auth.GET("/transactions/transfers", handler.GetTransfers)
 	auth.GET("/accounts/:id/transactions", handler.GetAccountTransactions)
 	auth.GET("/transactions/:id", handler.GetTransactionByID)
 	auth.PUT("/transactions/:id", handler.UpdateTransaction)
+	auth.PATCH("/transactions/:id", handler.UpdateTransaction)
 	auth.DELETE("/transactions/:id", handler.DeleteTransaction)
+	// Pipeline route (no user auth)
+	r.POST("/pipeline/transactions/settle", handler.SettlePendingTransactions)
 	return r
 }
 
 func TestTransactionHandler_CreateTransaction(t *testing.T) {
 	t.Run("returns 201 on success", func(t *testing.T) {
 		txSvc := &mockTransactionService{
-			createTransactionFn: func(userID, accountID uint, _ *uint, txType models.TransactionType, amount int64, desc string, _ time.Time) (*models.Transaction, error) {
+			createTransactionFn: func(userID models.UserID, accountID models.AccountID, _ *string, txType models.TransactionType, amount models.Cents, desc string, _ time.Time, _ bool, _ models.TransactionStatus, _ *services.OriginalAmount) (*models.Transaction, error) {
 				return &models.Transaction{
-					Base:      models.Base{ID: 1},
-					UserID:    userID,
-					AccountID: accountID,
+					Base:      models.Base{ID: testID(1)},
+					UserID:    string(userID),
+					AccountID: string(accountID),
 					Type:      txType,
-					Amount:    amount,
+					Amount:    int64(amount),
 				}, nil
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "POST", "/transactions",
-			`{"account_id":1,"type":"income","amount":5000,"description":"Salary"}`)
+			`{"account_id":"00000000-0000-7000-8000-000000000001","type":"income","amount":5000,"description":"Salary"}`)
 
 		if rec.Code != http.StatusCreated {
 			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
@@ -147,8 +240,40 @@ func TestTransactionHandler_CreateTransaction(t *testing.T) {
 		}
 	})
 
+	t.Run("returns the account balance after the transaction", func(t *testing.T) {
+		txSvc := &mockTransactionService{
+			createTransactionFn: func(userID models.UserID, accountID models.AccountID, _ *string, txType models.TransactionType, amount models.Cents, _ string, _ time.Time, _ bool, _ models.TransactionStatus, _ *services.OriginalAmount) (*models.Transaction, error) {
+				return &models.Transaction{
+					Base:      models.Base{ID: testID(1)},
+					UserID:    string(userID),
+					AccountID: string(accountID),
+					Type:      txType,
+					Amount:    int64(amount),
+					Account:   models.Account{Base: models.Base{ID: string(accountID)}, Balance: 15000},
+				}, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "POST", "/transactions",
+			`{"account_id":"00000000-0000-7000-8000-000000000001","type":"income","amount":5000}`)
+
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		result := parseJSON(t, rec)
+		if result["account_balance"].(float64) != 15000 {
+			t.Errorf("expected account_balance 15000, got %v", result["account_balance"])
+		}
+		tx := result["transaction"].(map[string]interface{})
+		if tx["id"] != testID(1) || tx["amount"].(float64) != 5000 {
+			t.Errorf("expected the transaction to be returned unchanged, got %v", tx)
+		}
+	})
+
 	t.Run("returns 400 on missing account_id", func(t *testing.T) {
-		handler := NewTransactionHandler(&mockTransactionService{}, &mockAuditService{})
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "POST", "/transactions",
@@ -160,11 +285,11 @@ func TestTransactionHandler_CreateTransaction(t *testing.T) {
 	})
 
 	t.Run("returns 400 on zero amount", func(t *testing.T) {
-		handler := NewTransactionHandler(&mockTransactionService{}, &mockAuditService{})
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "POST", "/transactions",
-			`{"account_id":1,"type":"expense","amount":0}`)
+			`{"account_id":"00000000-0000-7000-8000-000000000001","type":"expense","amount":0}`)
 
 		if rec.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d", rec.Code)
@@ -172,28 +297,191 @@ func TestTransactionHandler_CreateTransaction(t *testing.T) {
 	})
 
 	t.Run("returns 400 on invalid type", func(t *testing.T) {
-		handler := NewTransactionHandler(&mockTransactionService{}, &mockAuditService{})
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "POST", "/transactions",
-			`{"account_id":1,"type":"invalid","amount":1000}`)
+			`{"account_id":"00000000-0000-7000-8000-000000000001","type":"invalid","amount":1000}`)
 
 		if rec.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d", rec.Code)
 		}
 	})
 
+	t.Run("passes original amount and currency", func(t *testing.T) {
+		var got *services.OriginalAmount
+		txSvc := &mockTransactionService{
+			createTransactionFn: func(_ models.UserID, _ models.AccountID, _ *string, _ models.TransactionType, _ models.Cents, _ string, _ time.Time, _ bool, _ models.TransactionStatus, original *services.OriginalAmount) (*models.Transaction, error) {
+				got = original
+				return &models.Transaction{}, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "POST", "/transactions",
+			`{"account_id":"00000000-0000-7000-8000-000000000001","type":"expense","amount":1150,"original_amount":1000,"original_currency":"EUR"}`)
+
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if got == nil || got.Amount != 1000 || got.Currency != "EUR" {
+			t.Errorf("expected original 1000 EUR, got %+v", got)
+		}
+	})
+
+	t.Run("returns 400 on incomplete or invalid original amount", func(t *testing.T) {
+		for _, extra := range []string{`"original_amount":1000`, `"original_currency":"EUR"`, `"original_amount":1000,"original_currency":"XXX"`} {
+			handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
+			r := setupTransactionRouter(handler)
+
+			rec := doRequest(r, "POST", "/transactions",
+				`{"account_id":"00000000-0000-7000-8000-000000000001","type":"expense","amount":1150,`+extra+`}`)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", extra, rec.Code)
+			}
+		}
+	})
+
 	t.Run("returns 404 when account not found", func(t *testing.T) {
 		txSvc := &mockTransactionService{
-			createTransactionFn: func(_, _ uint, _ *uint, _ models.TransactionType, _ int64, _ string, _ time.Time) (*models.Transaction, error) {
+			createTransactionFn: func(_ models.UserID, _ models.AccountID, _ *string, _ models.TransactionType, _ models.Cents, _ string, _ time.Time, _ bool, _ models.TransactionStatus, _ *services.OriginalAmount) (*models.Transaction, error) {
+				return nil, apperrors.ErrAccountNotFound
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "POST", "/transactions",
+			`{"account_id":"00000000-0000-7000-8000-000000000999","type":"income","amount":1000}`)
+
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("accepts date-only date", func(t *testing.T) {
+		var gotDate time.Time
+		txSvc := &mockTransactionService{
+			createTransactionFn: func(_ models.UserID, _ models.AccountID, _ *string, _ models.TransactionType, amount models.Cents, _ string, date time.Time, _ bool, _ models.TransactionStatus, _ *services.OriginalAmount) (*models.Transaction, error) {
+				gotDate = date
+				return &models.Transaction{Base: models.Base{ID: testID(1)}, Amount: int64(amount)}, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "POST", "/transactions",
+			`{"account_id":"00000000-0000-7000-8000-000000000001","type":"expense","amount":1000,"date":"2025-06-15"}`)
+
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if want := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC); !gotDate.Equal(want) {
+			t.Errorf("expected date %v, got %v", want, gotDate)
+		}
+	})
+
+	t.Run("returns 400 on unknown field when strict", func(t *testing.T) {
+		called := false
+		txSvc := &mockTransactionService{
+			createTransactionFn: func(_ models.UserID, _ models.AccountID, _ *string, _ models.TransactionType, _ models.Cents, _ string, _ time.Time, _ bool, _ models.TransactionStatus, _ *services.OriginalAmount) (*models.Transaction, error) {
+				called = true
+				return &models.Transaction{}, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "POST", "/transactions?strict=true",
+			`{"account_id":"00000000-0000-7000-8000-000000000001","type":"expense","amount":1000,"catgory_id":"x"}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+		if called {
+			t.Error("expected service not to be called")
+		}
+	})
+
+	t.Run("converts amount_decimal using the account currency", func(t *testing.T) {
+		tests := []struct {
+			currency   string
+			decimal    string
+			wantStatus int
+			wantAmount int64
+		}{
+			{currency: "JPY", decimal: "1500", wantStatus: http.StatusCreated, wantAmount: 1500},
+			{currency: "JPY", decimal: "1500.5", wantStatus: http.StatusBadRequest},
+			{currency: "USD", decimal: "12.34", wantStatus: http.StatusCreated, wantAmount: 1234},
+			{currency: "USD", decimal: "12.345", wantStatus: http.StatusBadRequest},
+			{currency: "BHD", decimal: "1.234", wantStatus: http.StatusCreated, wantAmount: 1234},
+			{currency: "BHD", decimal: "1.2345", wantStatus: http.StatusBadRequest},
+			{currency: "BTC", decimal: "0.00000001", wantStatus: http.StatusCreated, wantAmount: 1},
+			{currency: "BTC", decimal: "0", wantStatus: http.StatusBadRequest},
+			{currency: "USD", decimal: "abc", wantStatus: http.StatusBadRequest},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.currency+"_"+tt.decimal, func(t *testing.T) {
+				var gotAmount int64
+				txSvc := &mockTransactionService{
+					createTransactionFn: func(_ models.UserID, _ models.AccountID, _ *string, _ models.TransactionType, amount models.Cents, _ string, _ time.Time, _ bool, _ models.TransactionStatus, _ *services.OriginalAmount) (*models.Transaction, error) {
+						gotAmount = int64(amount)
+						return &models.Transaction{Base: models.Base{ID: testID(1)}, Amount: int64(amount)}, nil
+					},
+				}
+				acctSvc := &mockAccountService{
+					getAccountByIDFn: func(_ models.UserID, accountID models.AccountID) (*models.Account, error) {
+						return &models.Account{Base: models.Base{ID: string(accountID)}, Currency: tt.currency}, nil
+					},
+				}
+				handler := NewTransactionHandler(txSvc, acctSvc, &mockAuditService{})
+				r := setupTransactionRouter(handler)
+
+				rec := doRequest(r, "POST", "/transactions",
+					`{"account_id":"00000000-0000-7000-8000-000000000001","type":"expense","amount_decimal":"`+tt.decimal+`"}`)
+
+				if rec.Code != tt.wantStatus {
+					t.Fatalf("expected %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+				}
+				if tt.wantStatus != http.StatusCreated {
+					assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+					return
+				}
+				if gotAmount != tt.wantAmount {
+					t.Errorf("expected amount %d, got %d", tt.wantAmount, gotAmount)
+				}
+			})
+		}
+	})
+
+	t.Run("returns 400 when both amount and amount_decimal are set", func(t *testing.T) {
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "POST", "/transactions",
+			`{"account_id":"00000000-0000-7000-8000-000000000001","type":"expense","amount":100,"amount_decimal":"1.00"}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("returns 404 when amount_decimal account is not found", func(t *testing.T) {
+		acctSvc := &mockAccountService{
+			getAccountByIDFn: func(_ models.UserID, _ models.AccountID) (*models.Account, error) {
 				return nil, apperrors.ErrAccountNotFound
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(&mockTransactionService{}, acctSvc, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "POST", "/transactions",
-			`{"account_id":999,"type":"income","amount":1000}`)
+			`{"account_id":"00000000-0000-7000-8000-000000000999","type":"expense","amount_decimal":"1.00"}`)
 
 		if rec.Code != http.StatusNotFound {
 			t.Fatalf("expected 404, got %d", rec.Code)
@@ -201,12 +489,12 @@ func TestTransactionHandler_CreateTransaction(t *testing.T) {
 	})
 
 	t.Run("returns 401 without auth", func(t *testing.T) {
-		handler := NewTransactionHandler(&mockTransactionService{}, &mockAuditService{})
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
 		r := gin.New()
 		r.POST("/transactions", handler.CreateTransaction)
 
 		rec := doRequest(r, "POST", "/transactions",
-			`{"account_id":1,"type":"income","amount":1000}`)
+			`{"account_id":"00000000-0000-7000-8000-000000000001","type":"income","amount":1000}`)
 
 		if rec.Code != http.StatusUnauthorized {
 			t.Fatalf("expected 401, got %d", rec.Code)
@@ -217,40 +505,77 @@ func TestTransactionHandler_CreateTransaction(t *testing.T) {
 func TestTransactionHandler_CreateTransfer(t *testing.T) {
 	t.Run("returns 201 on success", func(t *testing.T) {
 		txSvc := &mockTransactionService{
-			createTransferFn: func(userID, from, to uint, amount int64, _ string, _ time.Time) (*models.Transaction, error) {
-				toAcct := to
+			createTransferFn: func(userID models.UserID, from, to models.AccountID, amount models.Cents, _ string, _ time.Time) (*models.Transaction, error) {
+				toAcct := string(to)
+				return &models.Transaction{
+					Base:        models.Base{ID: testID(1)},
+					UserID:      string(userID),
+					AccountID:   string(from),
+					ToAccountID: &toAcct,
+					Type:        models.TransactionTypeTransfer,
+					Amount:      int64(amount),
+				}, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "POST", "/transactions/transfer",
+			`{"from_account_id":"00000000-0000-7000-8000-000000000001","to_account_id":"00000000-0000-7000-8000-000000000002","amount":1000,"description":"Transfer"}`)
+
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("returns both account balances after the transfer", func(t *testing.T) {
+		txSvc := &mockTransactionService{
+			createTransferFn: func(userID models.UserID, from, to models.AccountID, amount models.Cents, _ string, _ time.Time) (*models.Transaction, error) {
+				toAcct := string(to)
 				return &models.Transaction{
-					Base:        models.Base{ID: 1},
-					UserID:      userID,
-					AccountID:   from,
+					Base:        models.Base{ID: testID(1)},
+					UserID:      string(userID),
+					AccountID:   string(from),
 					ToAccountID: &toAcct,
 					Type:        models.TransactionTypeTransfer,
-					Amount:      amount,
+					Amount:      int64(amount),
+					Account:     models.Account{Base: models.Base{ID: string(from)}, Balance: 9000},
+					ToAccount:   &models.Account{Base: models.Base{ID: toAcct}, Balance: 3000},
 				}, nil
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "POST", "/transactions/transfer",
-			`{"from_account_id":1,"to_account_id":2,"amount":1000,"description":"Transfer"}`)
+			`{"from_account_id":"00000000-0000-7000-8000-000000000001","to_account_id":"00000000-0000-7000-8000-000000000002","amount":1000}`)
 
 		if rec.Code != http.StatusCreated {
 			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
 		}
+		result := parseJSON(t, rec)
+		if result["from_account_balance"].(float64) != 9000 {
+			t.Errorf("expected from_account_balance 9000, got %v", result["from_account_balance"])
+		}
+		if result["to_account_balance"].(float64) != 3000 {
+			t.Errorf("expected to_account_balance 3000, got %v", result["to_account_balance"])
+		}
+		if _, ok := result["transaction"].(map[string]interface{}); !ok {
+			t.Errorf("expected the transaction to be returned, got %v", result)
+		}
 	})
 
 	t.Run("returns 400 on same account", func(t *testing.T) {
 		txSvc := &mockTransactionService{
-			createTransferFn: func(_, _, _ uint, _ int64, _ string, _ time.Time) (*models.Transaction, error) {
+			createTransferFn: func(_ models.UserID, _, _ models.AccountID, _ models.Cents, _ string, _ time.Time) (*models.Transaction, error) {
 				return nil, apperrors.ErrSameAccountTransfer
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "POST", "/transactions/transfer",
-			`{"from_account_id":1,"to_account_id":1,"amount":1000}`)
+			`{"from_account_id":"00000000-0000-7000-8000-000000000001","to_account_id":"00000000-0000-7000-8000-000000000001","amount":1000}`)
 
 		if rec.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d", rec.Code)
@@ -260,15 +585,15 @@ func TestTransactionHandler_CreateTransfer(t *testing.T) {
 
 	t.Run("returns 400 on insufficient balance", func(t *testing.T) {
 		txSvc := &mockTransactionService{
-			createTransferFn: func(_, _, _ uint, _ int64, _ string, _ time.Time) (*models.Transaction, error) {
+			createTransferFn: func(_ models.UserID, _, _ models.AccountID, _ models.Cents, _ string, _ time.Time) (*models.Transaction, error) {
 				return nil, apperrors.ErrInsufficientBalance
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "POST", "/transactions/transfer",
-			`{"from_account_id":1,"to_account_id":2,"amount":999999}`)
+			`{"from_account_id":"00000000-0000-7000-8000-000000000001","to_account_id":"00000000-0000-7000-8000-000000000002","amount":999999}`)
 
 		if rec.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d", rec.Code)
@@ -276,8 +601,34 @@ func TestTransactionHandler_CreateTransfer(t *testing.T) {
 		assertErrorCode(t, parseJSON(t, rec), "INSUFFICIENT_BALANCE")
 	})
 
+	t.Run("accepts date-only date and rejects unknown fields when strict", func(t *testing.T) {
+		var gotDate time.Time
+		txSvc := &mockTransactionService{
+			createTransferFn: func(_ models.UserID, _, _ models.AccountID, _ models.Cents, _ string, date time.Time) (*models.Transaction, error) {
+				gotDate = date
+				return &models.Transaction{Base: models.Base{ID: testID(1)}}, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+		body := `{"from_account_id":"00000000-0000-7000-8000-000000000001","to_account_id":"00000000-0000-7000-8000-000000000002","amount":1000,"date":"2025-06-15"`
+
+		rec := doRequest(r, "POST", "/transactions/transfer?strict=true", body+`}`)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if want := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC); !gotDate.Equal(want) {
+			t.Errorf("expected date %v, got %v", want, gotDate)
+		}
+
+		rec = doRequest(r, "POST", "/transactions/transfer?strict=true", body+`,"memo":"x"}`)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for unknown field, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
 	t.Run("returns 400 on missing required fields", func(t *testing.T) {
-		handler := NewTransactionHandler(&mockTransactionService{}, &mockAuditService{})
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "POST", "/transactions/transfer", `{"amount":1000}`)
@@ -288,21 +639,84 @@ func TestTransactionHandler_CreateTransfer(t *testing.T) {
 	})
 }
 
+func TestTransactionHandler_CreateSplitTransfer(t *testing.T) {
+	t.Run("returns 201 with every leg", func(t *testing.T) {
+		var captured []services.SplitTransferLeg
+		txSvc := &mockTransactionService{
+			createSplitTransferFn: func(_ models.UserID, from models.AccountID, legs []services.SplitTransferLeg, _ string, _ time.Time) ([]models.Transaction, error) {
+				captured = legs
+				out := make([]models.Transaction, len(legs))
+				for i, leg := range legs {
+					to := string(leg.ToAccountID)
+					out[i] = models.Transaction{AccountID: string(from), ToAccountID: &to, Amount: int64(leg.Amount)}
+				}
+				return out, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "POST", "/transactions/split-transfer",
+			`{"from_account_id":"`+testID(1)+`","legs":[{"to_account_id":"`+testID(2)+`","amount":1000},{"to_account_id":"`+testID(3)+`","amount_decimal":"25.50"}]}`)
+
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if len(captured) != 2 || captured[0].Amount != 1000 || captured[1].Amount != 2550 {
+			t.Errorf("expected legs of 1000 and 2550, got %+v", captured)
+		}
+		if txs := parseJSON(t, rec)["transactions"].([]interface{}); len(txs) != 2 {
+			t.Errorf("expected 2 transactions, got %d", len(txs))
+		}
+	})
+
+	t.Run("returns 400 with a single leg", func(t *testing.T) {
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "POST", "/transactions/split-transfer",
+			`{"from_account_id":"`+testID(1)+`","legs":[{"to_account_id":"`+testID(2)+`","amount":1000}]}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("returns 400 on insufficient balance", func(t *testing.T) {
+		txSvc := &mockTransactionService{
+			createSplitTransferFn: func(_ models.UserID, _ models.AccountID, _ []services.SplitTransferLeg, _ string, _ time.Time) ([]models.Transaction, error) {
+				return nil, apperrors.ErrInsufficientBalance
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "POST", "/transactions/split-transfer",
+			`{"from_account_id":"`+testID(1)+`","legs":[{"to_account_id":"`+testID(2)+`","amount":1000},{"to_account_id":"`+testID(3)+`","amount":1000}]}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INSUFFICIENT_BALANCE")
+	})
+}
+
 func TestTransactionHandler_GetAccountTransactions(t *testing.T) {
 	t.Run("returns 200 with paginated transactions", func(t *testing.T) {
 		now := time.Now()
 		txSvc := &mockTransactionService{
-			getAccountTransactionsFn: func(_, _ uint, _ pagination.PageRequest, _ services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
+			getAccountTransactionsFn: func(_ models.UserID, _ models.AccountID, _ pagination.PageRequest, _ services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
 				resp := pagination.NewPageResponse([]models.Transaction{
-					{Base: models.Base{ID: 1}, Amount: 5000, Type: "income", Date: now},
+					{Base: models.Base{ID: testID(1)}, Amount: 5000, Type: "income", Date: now},
 				}, 1, 20, 1)
 				return &resp, nil
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
-		rec := doRequest(r, "GET", "/accounts/1/transactions", "")
+		rec := doRequest(r, "GET", "/accounts/00000000-0000-7000-8000-000000000001/transactions", "")
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d", rec.Code)
@@ -317,16 +731,16 @@ func TestTransactionHandler_GetAccountTransactions(t *testing.T) {
 	t.Run("passes filter params to service", func(t *testing.T) {
 		var capturedFilter services.TransactionFilter
 		txSvc := &mockTransactionService{
-			getAccountTransactionsFn: func(_, _ uint, _ pagination.PageRequest, filter services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
+			getAccountTransactionsFn: func(_ models.UserID, _ models.AccountID, _ pagination.PageRequest, filter services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
 				capturedFilter = filter
 				resp := pagination.NewPageResponse([]models.Transaction{}, 1, 20, 0)
 				return &resp, nil
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
-		doRequest(r, "GET", "/accounts/1/transactions?type=income&min_amount=100&max_amount=5000", "")
+		doRequest(r, "GET", "/accounts/00000000-0000-7000-8000-000000000001/transactions?type=income&min_amount=100&max_amount=5000", "")
 
 		if capturedFilter.Type == nil || *capturedFilter.Type != models.TransactionTypeIncome {
 			t.Errorf("expected type=income filter, got %v", capturedFilter.Type)
@@ -340,10 +754,10 @@ func TestTransactionHandler_GetAccountTransactions(t *testing.T) {
 	})
 
 	t.Run("returns 400 on invalid type filter", func(t *testing.T) {
-		handler := NewTransactionHandler(&mockTransactionService{}, &mockAuditService{})
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
-		rec := doRequest(r, "GET", "/accounts/1/transactions?type=invalid", "")
+		rec := doRequest(r, "GET", "/accounts/00000000-0000-7000-8000-000000000001/transactions?type=invalid", "")
 
 		if rec.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d", rec.Code)
@@ -351,10 +765,10 @@ func TestTransactionHandler_GetAccountTransactions(t *testing.T) {
 	})
 
 	t.Run("returns 400 on invalid date format", func(t *testing.T) {
-		handler := NewTransactionHandler(&mockTransactionService{}, &mockAuditService{})
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
-		rec := doRequest(r, "GET", "/accounts/1/transactions?from_date=not-a-date", "")
+		rec := doRequest(r, "GET", "/accounts/00000000-0000-7000-8000-000000000001/transactions?from_date=not-a-date", "")
 
 		if rec.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d", rec.Code)
@@ -362,10 +776,10 @@ func TestTransactionHandler_GetAccountTransactions(t *testing.T) {
 	})
 
 	t.Run("returns 400 on invalid min_amount", func(t *testing.T) {
-		handler := NewTransactionHandler(&mockTransactionService{}, &mockAuditService{})
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
-		rec := doRequest(r, "GET", "/accounts/1/transactions?min_amount=abc", "")
+		rec := doRequest(r, "GET", "/accounts/00000000-0000-7000-8000-000000000001/transactions?min_amount=abc", "")
 
 		if rec.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d", rec.Code)
@@ -373,7 +787,7 @@ func TestTransactionHandler_GetAccountTransactions(t *testing.T) {
 	})
 
 	t.Run("returns 400 on invalid account ID", func(t *testing.T) {
-		handler := NewTransactionHandler(&mockTransactionService{}, &mockAuditService{})
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "GET", "/accounts/abc/transactions", "")
@@ -388,15 +802,15 @@ func TestTransactionHandler_GetUserTransactions(t *testing.T) {
 	t.Run("returns_200_with_transactions", func(t *testing.T) {
 		now := time.Now()
 		txSvc := &mockTransactionService{
-			getUserTransactionsFn: func(_ uint, _ pagination.PageRequest, _ services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
+			getUserTransactionsFn: func(_ models.UserID, _ pagination.PageRequest, _ services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
 				resp := pagination.NewPageResponse([]models.Transaction{
-					{Base: models.Base{ID: 1}, Amount: 5000, Type: "income", Date: now},
-					{Base: models.Base{ID: 2}, Amount: 3000, Type: "expense", Date: now},
+					{Base: models.Base{ID: testID(1)}, Amount: 5000, Type: "income", Date: now},
+					{Base: models.Base{ID: testID(2)}, Amount: 3000, Type: "expense", Date: now},
 				}, 1, 20, 2)
 				return &resp, nil
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "GET", "/transactions", "")
@@ -411,14 +825,112 @@ func TestTransactionHandler_GetUserTransactions(t *testing.T) {
 		}
 	})
 
+	t.Run("passes_with_total", func(t *testing.T) {
+		var captured pagination.PageRequest
+		txSvc := &mockTransactionService{
+			getUserTransactionsFn: func(_ models.UserID, page pagination.PageRequest, _ services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
+				captured = page
+				resp := pagination.NewPageResponse([]models.Transaction{}, 1, 20, pagination.UnknownTotal)
+				return &resp, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "GET", "/transactions?with_total=false", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if captured.IncludeTotal() {
+			t.Error("expected with_total=false to be passed to the service")
+		}
+		result := parseJSON(t, rec)
+		if result["total_items"] != float64(-1) || result["total_pages"] != float64(0) {
+			t.Errorf("expected total_items -1 and total_pages 0, got %v and %v", result["total_items"], result["total_pages"])
+		}
+
+		rec = doRequest(r, "GET", "/transactions", "")
+		if rec.Code != http.StatusOK || !captured.IncludeTotal() {
+			t.Errorf("expected total to be included by default, got %d", rec.Code)
+		}
+	})
+
+	t.Run("returns_400_for_invalid_with_total", func(t *testing.T) {
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "GET", "/transactions?with_total=maybe", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("parses_pending_filter", func(t *testing.T) {
+		var captured services.TransactionFilter
+		txSvc := &mockTransactionService{
+			getUserTransactionsFn: func(_ models.UserID, _ pagination.PageRequest, filter services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
+				captured = filter
+				resp := pagination.NewPageResponse([]models.Transaction{}, 1, 20, 0)
+				return &resp, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "GET", "/transactions?pending=true", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if captured.Pending == nil || !*captured.Pending {
+			t.Errorf("expected pending=true filter, got %v", captured.Pending)
+		}
+
+		rec = doRequest(r, "GET", "/transactions?pending=soon", "")
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for invalid pending, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("parses_status_filter", func(t *testing.T) {
+		var captured services.TransactionFilter
+		txSvc := &mockTransactionService{
+			getUserTransactionsFn: func(_ models.UserID, _ pagination.PageRequest, filter services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
+				captured = filter
+				resp := pagination.NewPageResponse([]models.Transaction{}, 1, 20, 0)
+				return &resp, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "GET", "/transactions?status=pending", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if captured.Status == nil || *captured.Status != models.TransactionStatusPending {
+			t.Errorf("expected status=pending filter, got %v", captured.Status)
+		}
+
+		rec = doRequest(r, "GET", "/transactions?status=posted", "")
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for invalid status, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
 	t.Run("returns_200_empty_when_no_transactions", func(t *testing.T) {
 		txSvc := &mockTransactionService{
-			getUserTransactionsFn: func(_ uint, _ pagination.PageRequest, _ services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
+			getUserTransactionsFn: func(_ models.UserID, _ pagination.PageRequest, _ services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
 				resp := pagination.NewPageResponse([]models.Transaction{}, 1, 20, 0)
 				return &resp, nil
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "GET", "/transactions", "")
@@ -436,21 +948,21 @@ func TestTransactionHandler_GetUserTransactions(t *testing.T) {
 	t.Run("passes_filters_to_service", func(t *testing.T) {
 		var capturedFilter services.TransactionFilter
 		txSvc := &mockTransactionService{
-			getUserTransactionsFn: func(_ uint, _ pagination.PageRequest, filter services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
+			getUserTransactionsFn: func(_ models.UserID, _ pagination.PageRequest, filter services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
 				capturedFilter = filter
 				resp := pagination.NewPageResponse([]models.Transaction{}, 1, 20, 0)
 				return &resp, nil
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
-		doRequest(r, "GET", "/transactions?type=income&account_id=5&min_amount=100", "")
+		doRequest(r, "GET", "/transactions?type=income&account_id=00000000-0000-7000-8000-000000000005&min_amount=100", "")
 
 		if capturedFilter.Type == nil || *capturedFilter.Type != models.TransactionTypeIncome {
 			t.Errorf("expected type=income filter, got %v", capturedFilter.Type)
 		}
-		if capturedFilter.AccountID == nil || *capturedFilter.AccountID != 5 {
+		if capturedFilter.AccountID == nil || *capturedFilter.AccountID != testID(5) {
 			t.Errorf("expected account_id=5, got %v", capturedFilter.AccountID)
 		}
 		if capturedFilter.MinAmount == nil || *capturedFilter.MinAmount != 100 {
@@ -459,7 +971,7 @@ func TestTransactionHandler_GetUserTransactions(t *testing.T) {
 	})
 
 	t.Run("returns_400_for_invalid_date", func(t *testing.T) {
-		handler := NewTransactionHandler(&mockTransactionService{}, &mockAuditService{})
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "GET", "/transactions?from_date=not-a-date", "")
@@ -471,7 +983,7 @@ func TestTransactionHandler_GetUserTransactions(t *testing.T) {
 	})
 
 	t.Run("returns_400_for_invalid_type", func(t *testing.T) {
-		handler := NewTransactionHandler(&mockTransactionService{}, &mockAuditService{})
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "GET", "/transactions?type=invalid", "")
@@ -483,7 +995,7 @@ func TestTransactionHandler_GetUserTransactions(t *testing.T) {
 	})
 
 	t.Run("returns_401_without_auth", func(t *testing.T) {
-		handler := NewTransactionHandler(&mockTransactionService{}, &mockAuditService{})
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
 		r := gin.New()
 		r.GET("/transactions", handler.GetUserTransactions)
 
@@ -498,18 +1010,18 @@ func TestTransactionHandler_GetUserTransactions(t *testing.T) {
 func TestTransactionHandler_GetTransactionByID(t *testing.T) {
 	t.Run("returns 200 on success", func(t *testing.T) {
 		txSvc := &mockTransactionService{
-			getTransactionByIDFn: func(_, txID uint) (*models.Transaction, error) {
+			getTransactionByIDFn: func(_ models.UserID, txID models.TransactionID) (*models.Transaction, error) {
 				return &models.Transaction{
-					Base:   models.Base{ID: txID},
+					Base:   models.Base{ID: string(txID)},
 					Amount: 5000,
 					Type:   "income",
 				}, nil
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
-		rec := doRequest(r, "GET", "/transactions/1", "")
+		rec := doRequest(r, "GET", "/transactions/00000000-0000-7000-8000-000000000001", "")
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d", rec.Code)
@@ -518,14 +1030,14 @@ func TestTransactionHandler_GetTransactionByID(t *testing.T) {
 
 	t.Run("returns 404 when not found", func(t *testing.T) {
 		txSvc := &mockTransactionService{
-			getTransactionByIDFn: func(_, _ uint) (*models.Transaction, error) {
+			getTransactionByIDFn: func(_ models.UserID, _ models.TransactionID) (*models.Transaction, error) {
 				return nil, apperrors.ErrTransactionNotFound
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
-		rec := doRequest(r, "GET", "/transactions/999", "")
+		rec := doRequest(r, "GET", "/transactions/00000000-0000-7000-8000-000000000999", "")
 
 		if rec.Code != http.StatusNotFound {
 			t.Fatalf("expected 404, got %d", rec.Code)
@@ -533,12 +1045,106 @@ func TestTransactionHandler_GetTransactionByID(t *testing.T) {
 	})
 }
 
+func TestTransactionHandler_GetFlaggedTransactions(t *testing.T) {
+	t.Run("returns 200 with flagged transactions", func(t *testing.T) {
+		var gotUserID string
+		var gotPage pagination.PageRequest
+		txSvc := &mockTransactionService{
+			getFlaggedFn: func(userID models.UserID, page pagination.PageRequest) (*pagination.PageResponse[models.Transaction], error) {
+				gotUserID, gotPage = string(userID), page
+				resp := pagination.NewPageResponse([]models.Transaction{
+					{Base: models.Base{ID: testID(5)}, Amount: 250000, Type: models.TransactionTypeExpense, Flagged: true},
+				}, 2, 10, 11)
+				return &resp, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "GET", "/transactions/flagged?page=2&page_size=10", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotUserID != testID(1) || gotPage.Page != 2 || gotPage.PageSize != 10 {
+			t.Errorf("unexpected service args: user %s, page %+v", gotUserID, gotPage)
+		}
+		resp := parseJSON(t, rec)
+		data := resp["data"].([]interface{})
+		if len(data) != 1 || data[0].(map[string]interface{})["flagged"] != true {
+			t.Errorf("expected one flagged transaction, got %v", data)
+		}
+	})
+
+	t.Run("returns 400 on invalid page size", func(t *testing.T) {
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "GET", "/transactions/flagged?page_size=-1", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+}
+
+func TestTransactionHandler_GetTransfers(t *testing.T) {
+	t.Run("returns 200 with transfers and passes the filter", func(t *testing.T) {
+		var gotFilter services.TransactionFilter
+		txSvc := &mockTransactionService{
+			getTransfersFn: func(_ models.UserID, _ pagination.PageRequest, filter services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
+				gotFilter = filter
+				toID := testID(3)
+				resp := pagination.NewPageResponse([]models.Transaction{{
+					Base:        models.Base{ID: testID(5)},
+					Type:        models.TransactionTypeTransfer,
+					AccountID:   testID(2),
+					ToAccountID: &toID,
+					Account:     models.Account{Base: models.Base{ID: testID(2)}, Name: "Checking"},
+					ToAccount:   &models.Account{Base: models.Base{ID: toID}, Name: "Savings"},
+				}}, 1, 20, 1)
+				return &resp, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "GET", "/transactions/transfers?account_id="+testID(2)+"&from_date=2026-01-01", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotFilter.AccountID == nil || *gotFilter.AccountID != testID(2) || gotFilter.FromDate == nil {
+			t.Errorf("expected account and date filters to be passed, got %+v", gotFilter)
+		}
+		data := parseJSON(t, rec)["data"].([]interface{})
+		transfer := data[0].(map[string]interface{})
+		if transfer["account"].(map[string]interface{})["name"] != "Checking" ||
+			transfer["to_account"].(map[string]interface{})["name"] != "Savings" {
+			t.Errorf("expected both account names, got %v", transfer)
+		}
+	})
+
+	t.Run("returns 400 for another type", func(t *testing.T) {
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "GET", "/transactions/transfers?type=expense", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+}
+
 func TestTransactionHandler_DeleteTransaction(t *testing.T) {
 	t.Run("returns 200 on success", func(t *testing.T) {
-		handler := NewTransactionHandler(&mockTransactionService{}, &mockAuditService{})
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
-		rec := doRequest(r, "DELETE", "/transactions/1", "")
+		rec := doRequest(r, "DELETE", "/transactions/00000000-0000-7000-8000-000000000001", "")
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d", rec.Code)
@@ -551,76 +1157,573 @@ func TestTransactionHandler_DeleteTransaction(t *testing.T) {
 
 	t.Run("returns 404 when not found", func(t *testing.T) {
 		txSvc := &mockTransactionService{
-			deleteTransactionFn: func(_, _ uint) error {
+			deleteTransactionFn: func(_ models.UserID, _ models.TransactionID) error {
 				return apperrors.ErrTransactionNotFound
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
-		rec := doRequest(r, "DELETE", "/transactions/999", "")
+		rec := doRequest(r, "DELETE", "/transactions/00000000-0000-7000-8000-000000000999", "")
 
 		if rec.Code != http.StatusNotFound {
 			t.Fatalf("expected 404, got %d", rec.Code)
 		}
 	})
 
-	t.Run("returns 400 on invalid ID", func(t *testing.T) {
-		handler := NewTransactionHandler(&mockTransactionService{}, &mockAuditService{})
+	t.Run("returns 400 on invalid ID", func(t *testing.T) {
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "DELETE", "/transactions/abc", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+	})
+}
+
+func TestTransactionHandler_BulkDeleteTransactions(t *testing.T) {
+	t.Run("dry_run_with_filter", func(t *testing.T) {
+		var captured services.BulkDeleteSelection
+		txSvc := &mockTransactionService{
+			previewBulkDeleteFn: func(_ models.UserID, selection services.BulkDeleteSelection) (*services.BulkDeletePreview, error) {
+				captured = selection
+				return &services.BulkDeletePreview{Count: 900, TotalAmount: 123400, ConfirmationToken: "tok"}, nil
+			},
+			executeBulkDeleteFn: func(_ models.UserID, _ string) (*services.BulkDeleteResult, error) {
+				t.Error("execute should not be called on a dry run")
+				return nil, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		body := `{"dry_run":true,"filter":{"from_date":"2025-01-01","to_date":"2025-01-31","type":"expense","account_id":"` + testID(3) + `"}}`
+		rec := doRequest(r, "POST", "/transactions/bulk-delete", body)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		f := captured.Filter
+		if f == nil || f.FromDate.Format("2006-01-02") != "2025-01-01" || f.ToDate.Format("2006-01-02") != "2025-01-31" ||
+			*f.Type != models.TransactionTypeExpense || *f.AccountID != testID(3) {
+			t.Errorf("unexpected selection %+v", captured)
+		}
+		result := parseJSON(t, rec)
+		if result["count"] != float64(900) || result["total_amount"] != float64(123400) || result["confirmation_token"] != "tok" {
+			t.Errorf("unexpected preview %v", result)
+		}
+	})
+
+	t.Run("defaults_to_dry_run_with_ids", func(t *testing.T) {
+		var captured services.BulkDeleteSelection
+		txSvc := &mockTransactionService{
+			previewBulkDeleteFn: func(_ models.UserID, selection services.BulkDeleteSelection) (*services.BulkDeletePreview, error) {
+				captured = selection
+				return &services.BulkDeletePreview{Count: 2}, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "POST", "/transactions/bulk-delete", `{"ids":["`+testID(1)+`","`+testID(2)+`"]}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if len(captured.IDs) != 2 || captured.Filter != nil {
+			t.Errorf("unexpected selection %+v", captured)
+		}
+	})
+
+	t.Run("execute_logs_audit", func(t *testing.T) {
+		var capturedToken string
+		from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		txSvc := &mockTransactionService{
+			executeBulkDeleteFn: func(_ models.UserID, token string) (*services.BulkDeleteResult, error) {
+				capturedToken = token
+				return &services.BulkDeleteResult{
+					Deleted:   900,
+					Selection: services.BulkDeleteSelection{Filter: &services.TransactionFilter{FromDate: &from, ToDate: &from}},
+				}, nil
+			},
+		}
+		audit := &recordingAuditService{}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, audit)
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "POST", "/transactions/bulk-delete", `{"dry_run":false,"confirmation_token":"tok"}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if capturedToken != "tok" {
+			t.Errorf("expected token tok, got %q", capturedToken)
+		}
+		if result := parseJSON(t, rec); result["deleted"] != float64(900) {
+			t.Errorf("expected 900 deleted, got %v", result["deleted"])
+		}
+		if len(audit.actions) != 1 || audit.actions[0] != "BULK_DELETE_TRANSACTIONS" {
+			t.Fatalf("expected BULK_DELETE_TRANSACTIONS audit, got %v", audit.actions)
+		}
+		if audit.changes[0]["count"] != int64(900) || audit.changes[0]["filter"] == nil {
+			t.Errorf("expected count and filter in audit changes, got %v", audit.changes[0])
+		}
+	})
+
+	t.Run("execute_requires_token", func(t *testing.T) {
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "POST", "/transactions/bulk-delete", `{"dry_run":false}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("execute_rejects_selection", func(t *testing.T) {
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "POST", "/transactions/bulk-delete", `{"dry_run":false,"confirmation_token":"tok","ids":["`+testID(1)+`"]}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("rejects_invalid_input", func(t *testing.T) {
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		for _, body := range []string{
+			`{"ids":["not-a-uuid"]}`,
+			`{"filter":{"from_date":"2025-01-01"}}`,
+			`{"filter":{"from_date":"yesterday","to_date":"2025-01-31"}}`,
+			`{"filter":{"from_date":"2025-01-01","to_date":"2025-01-31","type":"bogus"}}`,
+		} {
+			rec := doRequest(r, "POST", "/transactions/bulk-delete", body)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", body, rec.Code)
+			}
+		}
+	})
+
+	t.Run("maps_service_errors", func(t *testing.T) {
+		txSvc := &mockTransactionService{
+			executeBulkDeleteFn: func(_ models.UserID, _ string) (*services.BulkDeleteResult, error) {
+				return nil, apperrors.ErrBulkDeleteStale
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "POST", "/transactions/bulk-delete", `{"dry_run":false,"confirmation_token":"tok"}`)
+
+		if rec.Code != http.StatusConflict {
+			t.Fatalf("expected 409, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "BULK_DELETE_STALE")
+	})
+}
+
+func TestTransactionHandler_BulkCategorizeTransactions(t *testing.T) {
+	t.Run("passes_filter_and_category", func(t *testing.T) {
+		var capturedFilter services.TransactionFilter
+		var capturedCategory *string
+		audit := &recordingAuditService{}
+		txSvc := &mockTransactionService{
+			bulkUpdateCategoryFn: func(_ models.UserID, filter services.TransactionFilter, categoryID *string) (int64, error) {
+				capturedFilter, capturedCategory = filter, categoryID
+				return 12, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, audit)
+		r := setupTransactionRouter(handler)
+
+		body := `{"filter":{"from_date":"2025-01-01","to_date":"2025-01-31","type":"expense","uncategorized":true},"category_id":"` + testID(5) + `"}`
+		rec := doRequest(r, "POST", "/transactions/bulk-categorize", body)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		f := capturedFilter
+		if f.FromDate.Format("2006-01-02") != "2025-01-01" || f.ToDate.Format("2006-01-02") != "2025-01-31" ||
+			*f.Type != models.TransactionTypeExpense || !f.Uncategorized {
+			t.Errorf("unexpected filter %+v", f)
+		}
+		if capturedCategory == nil || *capturedCategory != testID(5) {
+			t.Errorf("expected category %s, got %v", testID(5), capturedCategory)
+		}
+		if result := parseJSON(t, rec); result["updated"] != float64(12) {
+			t.Errorf("expected updated 12, got %v", result)
+		}
+		if len(audit.actions) != 1 || audit.actions[0] != "BULK_CATEGORIZE_TRANSACTIONS" {
+			t.Errorf("expected BULK_CATEGORIZE_TRANSACTIONS audit, got %v", audit.actions)
+		}
+	})
+
+	t.Run("null_category_clears", func(t *testing.T) {
+		called := false
+		txSvc := &mockTransactionService{
+			bulkUpdateCategoryFn: func(_ models.UserID, _ services.TransactionFilter, categoryID *string) (int64, error) {
+				called = true
+				if categoryID != nil {
+					t.Errorf("expected a nil category, got %s", *categoryID)
+				}
+				return 0, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "POST", "/transactions/bulk-categorize",
+			`{"filter":{"from_date":"2025-01-01","to_date":"2025-01-31"},"category_id":null}`)
+
+		if rec.Code != http.StatusOK || !called {
+			t.Fatalf("expected 200 from the service, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("rejects_missing_dates_and_bad_input", func(t *testing.T) {
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		for _, body := range []string{
+			`{"category_id":"` + testID(5) + `"}`,
+			`{"filter":{"from_date":"2025-01-01"},"category_id":"` + testID(5) + `"}`,
+			`{"filter":{"from_date":"2025-01-01","to_date":"2025-01-31"},"category_id":"not-a-uuid"}`,
+			`{"filter":{"from_date":"2025-01-01","to_date":"2025-01-31","category_id":"` + testID(4) + `","uncategorized":true}}`,
+		} {
+			rec := doRequest(r, "POST", "/transactions/bulk-categorize", body)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("expected 400 for %s, got %d", body, rec.Code)
+			}
+		}
+	})
+
+	t.Run("maps_type_mismatch", func(t *testing.T) {
+		txSvc := &mockTransactionService{
+			bulkUpdateCategoryFn: func(_ models.UserID, _ services.TransactionFilter, _ *string) (int64, error) {
+				return 0, apperrors.ErrCategoryTypeMismatch
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "POST", "/transactions/bulk-categorize",
+			`{"filter":{"from_date":"2025-01-01","to_date":"2025-01-31","type":"income"},"category_id":"`+testID(5)+`"}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+		assertErrorCode(t, parseJSON(t, rec), "CATEGORY_TYPE_MISMATCH")
+	})
+}
+
+func TestTransactionHandler_UpdateTransaction(t *testing.T) {
+	t.Run("returns_200_with_updated_transaction", func(t *testing.T) {
+		txSvc := &mockTransactionService{
+			updateTransactionFn: func(_ models.UserID, txID models.TransactionID, _ services.TransactionUpdateFields) (*models.Transaction, error) {
+				return &models.Transaction{
+					Base:      models.Base{ID: string(txID)},
+					UserID:    testID(1),
+					AccountID: testID(1),
+					Type:      models.TransactionTypeExpense,
+					Amount:    3000,
+				}, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "PUT", "/transactions/00000000-0000-7000-8000-000000000001", `{"amount":3000}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		result := parseJSON(t, rec)
+		tx := result["transaction"].(map[string]interface{})
+		if tx["amount"].(float64) != 3000 {
+			t.Errorf("expected amount 3000, got %v", tx["amount"])
+		}
+	})
+
+	t.Run("sets_and_clears_original_amount", func(t *testing.T) {
+		var got services.TransactionUpdateFields
+		txSvc := &mockTransactionService{
+			updateTransactionFn: func(_ models.UserID, txID models.TransactionID, fields services.TransactionUpdateFields) (*models.Transaction, error) {
+				got = fields
+				return &models.Transaction{Base: models.Base{ID: string(txID)}}, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "PATCH", "/transactions/00000000-0000-7000-8000-000000000001", `{"original_amount":500,"original_currency":"JPY"}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if got.Original == nil || *got.Original == nil || (*got.Original).Amount != 500 || (*got.Original).Currency != "JPY" {
+			t.Errorf("expected original 500 JPY, got %+v", got.Original)
+		}
+
+		rec = doRequest(r, "PATCH", "/transactions/00000000-0000-7000-8000-000000000001", `{"original_currency":""}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if got.Original == nil || *got.Original != nil {
+			t.Errorf("expected original to be cleared, got %+v", got.Original)
+		}
+
+		rec = doRequest(r, "PATCH", "/transactions/00000000-0000-7000-8000-000000000001", `{"original_amount":500,"original_currency":"ABC"}`)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected 400 on unknown currency, got %d", rec.Code)
+		}
+	})
+
+	t.Run("passes_status_only_update", func(t *testing.T) {
+		var got services.TransactionUpdateFields
+		txSvc := &mockTransactionService{
+			updateTransactionFn: func(_ models.UserID, txID models.TransactionID, fields services.TransactionUpdateFields) (*models.Transaction, error) {
+				got = fields
+				return &models.Transaction{Base: models.Base{ID: string(txID)}, Status: *fields.Status}, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "PATCH", "/transactions/00000000-0000-7000-8000-000000000001", `{"status":"cleared"}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if got.Status == nil || *got.Status != models.TransactionStatusCleared || got.Amount != nil || got.Date != nil {
+			t.Errorf("expected only status to be passed, got %+v", got)
+		}
+
+		rec = doRequest(r, "PATCH", "/transactions/00000000-0000-7000-8000-000000000001", `{"status":"posted"}`)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for invalid status, got %d", rec.Code)
+		}
+	})
+
+	t.Run("returns_400_for_invalid_amount", func(t *testing.T) {
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "PUT", "/transactions/00000000-0000-7000-8000-000000000001", `{"amount":-1}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("converts_amount_decimal_using_existing_account_currency", func(t *testing.T) {
+		var gotAmount *int64
+		txSvc := &mockTransactionService{
+			getTransactionByIDFn: func(_ models.UserID, txID models.TransactionID) (*models.Transaction, error) {
+				return &models.Transaction{Base: models.Base{ID: string(txID)}, AccountID: testID(7)}, nil
+			},
+			updateTransactionFn: func(_ models.UserID, txID models.TransactionID, updates services.TransactionUpdateFields) (*models.Transaction, error) {
+				gotAmount = updates.Amount
+				return &models.Transaction{Base: models.Base{ID: string(txID)}}, nil
+			},
+		}
+		var gotAccountID string
+		acctSvc := &mockAccountService{
+			getAccountByIDFn: func(_ models.UserID, accountID models.AccountID) (*models.Account, error) {
+				gotAccountID = string(accountID)
+				return &models.Account{Base: models.Base{ID: string(accountID)}, Currency: "JPY"}, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, acctSvc, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "PUT", "/transactions/00000000-0000-7000-8000-000000000001", `{"amount_decimal":"2500"}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotAccountID != testID(7) {
+			t.Errorf("expected currency lookup for account %s, got %s", testID(7), gotAccountID)
+		}
+		if gotAmount == nil || *gotAmount != 2500 {
+			t.Errorf("expected amount 2500, got %v", gotAmount)
+		}
+	})
+
+	t.Run("returns_400_for_amount_decimal_finer_than_currency", func(t *testing.T) {
+		acctSvc := &mockAccountService{
+			getAccountByIDFn: func(_ models.UserID, accountID models.AccountID) (*models.Account, error) {
+				return &models.Account{Base: models.Base{ID: string(accountID)}, Currency: "JPY"}, nil
+			},
+		}
+		handler := NewTransactionHandler(&mockTransactionService{}, acctSvc, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "PUT", "/transactions/00000000-0000-7000-8000-000000000001",
+			`{"account_id":"00000000-0000-7000-8000-000000000002","amount_decimal":"25.50"}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("returns_400_when_no_fields_provided", func(t *testing.T) {
+		for _, body := range []string{`{}`, `{"date":""}`, `{"unknown":1}`} {
+			called := false
+			txSvc := &mockTransactionService{
+				updateTransactionFn: func(_ models.UserID, _ models.TransactionID, _ services.TransactionUpdateFields) (*models.Transaction, error) {
+					called = true
+					return &models.Transaction{}, nil
+				},
+			}
+			handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+			r := setupTransactionRouter(handler)
+
+			rec := doRequest(r, "PUT", "/transactions/00000000-0000-7000-8000-000000000001", body)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("%s: expected 400, got %d: %s", body, rec.Code, rec.Body.String())
+			}
+			result := parseJSON(t, rec)
+			assertErrorCode(t, result, "INVALID_INPUT")
+			if msg := result["error"].(map[string]interface{})["message"]; msg != "no fields provided" {
+				t.Errorf("%s: expected 'no fields provided', got %v", body, msg)
+			}
+			if called {
+				t.Errorf("%s: expected service not to be called", body)
+			}
+		}
+	})
+
+	t.Run("ignores_unknown_fields_unless_strict", func(t *testing.T) {
+		txSvc := &mockTransactionService{
+			updateTransactionFn: func(_ models.UserID, txID models.TransactionID, _ services.TransactionUpdateFields) (*models.Transaction, error) {
+				return &models.Transaction{Base: models.Base{ID: string(txID)}}, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+		body := `{"amount":3000,"descripton":"typo"}`
+
+		rec := doRequest(r, "PUT", "/transactions/00000000-0000-7000-8000-000000000001", body)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200 without strict, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		rec = doRequest(r, "PUT", "/transactions/00000000-0000-7000-8000-000000000001?strict=true", body)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 with strict, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+
+		rec = doRequest(r, "PUT", "/transactions/00000000-0000-7000-8000-000000000001?strict=true", `{"amount":3000}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200 with strict and known fields, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("returns_400_for_strict_validation_failure", func(t *testing.T) {
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "PUT", "/transactions/00000000-0000-7000-8000-000000000001?strict=true", `{"amount":-5}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("returns_400_for_invalid_strict_value", func(t *testing.T) {
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
-		rec := doRequest(r, "DELETE", "/transactions/abc", "")
+		rec := doRequest(r, "PUT", "/transactions/00000000-0000-7000-8000-000000000001?strict=maybe", `{"amount":3000}`)
 
 		if rec.Code != http.StatusBadRequest {
-			t.Fatalf("expected 400, got %d", rec.Code)
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
 		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
 	})
-}
 
-func TestTransactionHandler_UpdateTransaction(t *testing.T) {
-	t.Run("returns_200_with_updated_transaction", func(t *testing.T) {
+	t.Run("accepts_patch_as_alias", func(t *testing.T) {
+		var got services.TransactionUpdateFields
 		txSvc := &mockTransactionService{
-			updateTransactionFn: func(_, txID uint, _ services.TransactionUpdateFields) (*models.Transaction, error) {
-				return &models.Transaction{
-					Base:      models.Base{ID: txID},
-					UserID:    1,
-					AccountID: 1,
-					Type:      models.TransactionTypeExpense,
-					Amount:    3000,
-				}, nil
+			updateTransactionFn: func(_ models.UserID, txID models.TransactionID, updates services.TransactionUpdateFields) (*models.Transaction, error) {
+				got = updates
+				return &models.Transaction{Base: models.Base{ID: string(txID)}}, nil
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
-		rec := doRequest(r, "PUT", "/transactions/1", `{"amount":3000}`)
+		rec := doRequest(r, "PATCH", "/transactions/00000000-0000-7000-8000-000000000001", `{"description":"Groceries"}`)
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
 		}
-		result := parseJSON(t, rec)
-		tx := result["transaction"].(map[string]interface{})
-		if tx["amount"].(float64) != 3000 {
-			t.Errorf("expected amount 3000, got %v", tx["amount"])
+		if got.Description == nil || *got.Description != "Groceries" {
+			t.Errorf("expected description to be passed, got %v", got.Description)
+		}
+		if got.Amount != nil {
+			t.Errorf("expected amount untouched, got %d", *got.Amount)
 		}
 	})
 
-	t.Run("returns_400_for_invalid_amount", func(t *testing.T) {
-		handler := NewTransactionHandler(&mockTransactionService{}, &mockAuditService{})
+	t.Run("accepts_date_only_and_rfc3339_dates", func(t *testing.T) {
+		tests := []struct {
+			date string
+			want time.Time
+		}{
+			{date: "2025-06-15", want: time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)},
+			{date: "2025-06-15T10:30:00Z", want: time.Date(2025, 6, 15, 10, 30, 0, 0, time.UTC)},
+		}
+		for _, tt := range tests {
+			var got services.TransactionUpdateFields
+			txSvc := &mockTransactionService{
+				updateTransactionFn: func(_ models.UserID, txID models.TransactionID, updates services.TransactionUpdateFields) (*models.Transaction, error) {
+					got = updates
+					return &models.Transaction{Base: models.Base{ID: string(txID)}}, nil
+				},
+			}
+			handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+			r := setupTransactionRouter(handler)
+
+			rec := doRequest(r, "PATCH", "/transactions/00000000-0000-7000-8000-000000000001", `{"date":"`+tt.date+`"}`)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("%s: expected 200, got %d: %s", tt.date, rec.Code, rec.Body.String())
+			}
+			if got.Date == nil || !got.Date.Equal(tt.want) {
+				t.Errorf("%s: expected date %v, got %v", tt.date, tt.want, got.Date)
+			}
+		}
+	})
+
+	t.Run("returns_400_for_malformed_date", func(t *testing.T) {
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
-		rec := doRequest(r, "PUT", "/transactions/1", `{"amount":-1}`)
+		rec := doRequest(r, "PUT", "/transactions/00000000-0000-7000-8000-000000000001", `{"date":"15/06/2025"}`)
 
 		if rec.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
 		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
 	})
 
 	t.Run("returns_400_for_invalid_type", func(t *testing.T) {
-		handler := NewTransactionHandler(&mockTransactionService{}, &mockAuditService{})
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
-		rec := doRequest(r, "PUT", "/transactions/1", `{"type":"invalid"}`)
+		rec := doRequest(r, "PUT", "/transactions/00000000-0000-7000-8000-000000000001", `{"type":"invalid"}`)
 
 		if rec.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
@@ -629,14 +1732,14 @@ func TestTransactionHandler_UpdateTransaction(t *testing.T) {
 
 	t.Run("returns_404_for_nonexistent_transaction", func(t *testing.T) {
 		txSvc := &mockTransactionService{
-			updateTransactionFn: func(_, _ uint, _ services.TransactionUpdateFields) (*models.Transaction, error) {
+			updateTransactionFn: func(_ models.UserID, _ models.TransactionID, _ services.TransactionUpdateFields) (*models.Transaction, error) {
 				return nil, apperrors.ErrTransactionNotFound
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
-		rec := doRequest(r, "PUT", "/transactions/999", `{"amount":1000}`)
+		rec := doRequest(r, "PUT", "/transactions/00000000-0000-7000-8000-000000000999", `{"amount":1000}`)
 
 		if rec.Code != http.StatusNotFound {
 			t.Fatalf("expected 404, got %d", rec.Code)
@@ -645,14 +1748,14 @@ func TestTransactionHandler_UpdateTransaction(t *testing.T) {
 
 	t.Run("returns_400_for_non_editable_type", func(t *testing.T) {
 		txSvc := &mockTransactionService{
-			updateTransactionFn: func(_, _ uint, _ services.TransactionUpdateFields) (*models.Transaction, error) {
+			updateTransactionFn: func(_ models.UserID, _ models.TransactionID, _ services.TransactionUpdateFields) (*models.Transaction, error) {
 				return nil, apperrors.ErrTransactionNotEditable
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
-		rec := doRequest(r, "PUT", "/transactions/1", `{"amount":1000}`)
+		rec := doRequest(r, "PUT", "/transactions/00000000-0000-7000-8000-000000000001", `{"amount":1000}`)
 
 		if rec.Code != http.StatusBadRequest {
 			t.Fatalf("expected 400, got %d", rec.Code)
@@ -663,15 +1766,15 @@ func TestTransactionHandler_UpdateTransaction(t *testing.T) {
 	t.Run("passes_update_fields_to_service", func(t *testing.T) {
 		var captured services.TransactionUpdateFields
 		txSvc := &mockTransactionService{
-			updateTransactionFn: func(_, _ uint, updates services.TransactionUpdateFields) (*models.Transaction, error) {
+			updateTransactionFn: func(_ models.UserID, _ models.TransactionID, updates services.TransactionUpdateFields) (*models.Transaction, error) {
 				captured = updates
-				return &models.Transaction{Base: models.Base{ID: 1}}, nil
+				return &models.Transaction{Base: models.Base{ID: testID(1)}}, nil
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
-		doRequest(r, "PUT", "/transactions/1", `{"amount":5000,"type":"income","description":"Updated"}`)
+		doRequest(r, "PUT", "/transactions/00000000-0000-7000-8000-000000000001", `{"amount":5000,"type":"income","description":"Updated"}`)
 
 		if captured.Amount == nil || *captured.Amount != 5000 {
 			t.Errorf("expected amount=5000, got %v", captured.Amount)
@@ -685,11 +1788,100 @@ func TestTransactionHandler_UpdateTransaction(t *testing.T) {
 	})
 }
 
+func TestTransactionHandler_GetSpendingByAccount(t *testing.T) {
+	t.Run("returns_200_with_data", func(t *testing.T) {
+		var gotFrom, gotTo time.Time
+		txSvc := &mockTransactionService{
+			getSpendingByAccountFn: func(_ models.UserID, from, to time.Time, _ bool) (*services.SpendingByAccount, error) {
+				gotFrom, gotTo = from, to
+				return &services.SpendingByAccount{
+					Items: []services.SpendingByAccountItem{
+						{AccountID: testID(2), AccountName: "Credit Card", IsActive: true, Total: 5000, Count: 3},
+						{AccountID: testID(3), AccountName: "Old Wallet", Total: 1500, Count: 1},
+					},
+					TotalSpent: 6500,
+				}, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "GET", "/reports/spending-by-account?from_date=2026-01-01&to_date=2026-01-31", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if gotFrom.Format("2006-01-02") != "2026-01-01" || gotTo.Format("2006-01-02") != "2026-01-31" {
+			t.Errorf("expected range 2026-01-01..2026-01-31, got %s..%s", gotFrom, gotTo)
+		}
+		result := parseJSON(t, rec)
+		items := result["items"].([]interface{})
+		if len(items) != 2 {
+			t.Fatalf("expected 2 items, got %d", len(items))
+		}
+		if items[0].(map[string]interface{})["count"].(float64) != 3 {
+			t.Errorf("expected count 3, got %v", items[0].(map[string]interface{})["count"])
+		}
+	})
+
+	t.Run("returns_400_missing_from_date", func(t *testing.T) {
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "GET", "/reports/spending-by-account?to_date=2026-01-31", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+}
+
+func TestTransactionHandler_GetSpendingByCurrency(t *testing.T) {
+	t.Run("returns_200_with_data", func(t *testing.T) {
+		txSvc := &mockTransactionService{
+			getSpendingByCurrencyFn: func(_ models.UserID, _, _ time.Time, _ bool) (*services.SpendingByCurrency, error) {
+				return &services.SpendingByCurrency{
+					Items: []services.SpendingByCurrencyItem{
+						{Currency: "MYR", OriginalTotal: 8000, Total: 8000, Count: 2},
+						{Currency: "USD", OriginalTotal: 1000, Total: 4700, Count: 1},
+					},
+					TotalSpent: 12700,
+				}, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "GET", "/transactions/spending-by-currency?from_date=2026-01-01&to_date=2026-01-31", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		items := parseJSON(t, rec)["items"].([]interface{})
+		if len(items) != 2 || items[1].(map[string]interface{})["original_total"].(float64) != 1000 {
+			t.Errorf("expected USD original total 1000, got %v", items)
+		}
+	})
+
+	t.Run("returns_400_missing_to_date", func(t *testing.T) {
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "GET", "/transactions/spending-by-currency?from_date=2026-01-01", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+}
+
 func TestTransactionHandler_GetSpendingByCategory(t *testing.T) {
 	t.Run("returns_200_with_data", func(t *testing.T) {
-		catID := uint(3)
+		catID := testID(3)
 		txSvc := &mockTransactionService{
-			getSpendingByCategoryFn: func(_ uint, _, _ time.Time) (*services.SpendingByCategory, error) {
+			getSpendingByCategoryFn: func(_ models.UserID, _, _ time.Time, _ services.SpendingMode, _ bool) (*services.SpendingByCategory, error) {
 				return &services.SpendingByCategory{
 					Items: []services.SpendingByCategoryItem{
 						{CategoryID: &catID, CategoryName: "Groceries", CategoryColor: "#22C55E", Total: 5000},
@@ -699,7 +1891,7 @@ func TestTransactionHandler_GetSpendingByCategory(t *testing.T) {
 				}, nil
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "GET", "/transactions/spending-by-category?from_date=2026-01-01&to_date=2026-01-31", "")
@@ -718,7 +1910,7 @@ func TestTransactionHandler_GetSpendingByCategory(t *testing.T) {
 	})
 
 	t.Run("returns_400_missing_from_date", func(t *testing.T) {
-		handler := NewTransactionHandler(&mockTransactionService{}, &mockAuditService{})
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "GET", "/transactions/spending-by-category?to_date=2026-01-31", "")
@@ -729,7 +1921,7 @@ func TestTransactionHandler_GetSpendingByCategory(t *testing.T) {
 	})
 
 	t.Run("returns_400_missing_to_date", func(t *testing.T) {
-		handler := NewTransactionHandler(&mockTransactionService{}, &mockAuditService{})
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "GET", "/transactions/spending-by-category?from_date=2026-01-01", "")
@@ -739,16 +1931,53 @@ func TestTransactionHandler_GetSpendingByCategory(t *testing.T) {
 		}
 	})
 
+	t.Run("passes_mode_defaulting_to_expense", func(t *testing.T) {
+		var gotMode services.SpendingMode
+		txSvc := &mockTransactionService{
+			getSpendingByCategoryFn: func(_ models.UserID, _, _ time.Time, mode services.SpendingMode, _ bool) (*services.SpendingByCategory, error) {
+				gotMode = mode
+				return &services.SpendingByCategory{Items: []services.SpendingByCategoryItem{}, Mode: mode}, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "GET", "/transactions/spending-by-category?from_date=2026-01-01&to_date=2026-01-31", "")
+		if rec.Code != http.StatusOK || gotMode != services.SpendingModeExpense {
+			t.Fatalf("expected 200 in expense mode, got %d in %q", rec.Code, gotMode)
+		}
+
+		rec = doRequest(r, "GET", "/transactions/spending-by-category?from_date=2026-01-01&to_date=2026-01-31&mode=income", "")
+		if rec.Code != http.StatusOK || gotMode != services.SpendingModeIncome {
+			t.Fatalf("expected 200 in income mode, got %d in %q", rec.Code, gotMode)
+		}
+		if parseJSON(t, rec)["mode"] != "income" {
+			t.Errorf("expected mode income in the response, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("returns_400_invalid_mode", func(t *testing.T) {
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "GET", "/transactions/spending-by-category?from_date=2026-01-01&to_date=2026-01-31&mode=transfer", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
 	t.Run("returns_200_empty_items", func(t *testing.T) {
 		txSvc := &mockTransactionService{
-			getSpendingByCategoryFn: func(_ uint, _, _ time.Time) (*services.SpendingByCategory, error) {
+			getSpendingByCategoryFn: func(_ models.UserID, _, _ time.Time, _ services.SpendingMode, _ bool) (*services.SpendingByCategory, error) {
 				return &services.SpendingByCategory{
 					Items:      []services.SpendingByCategoryItem{},
 					TotalSpent: 0,
 				}, nil
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "GET", "/transactions/spending-by-category?from_date=2026-01-01&to_date=2026-01-31", "")
@@ -768,7 +1997,7 @@ func TestTransactionHandler_GetMonthlySummary(t *testing.T) {
 	t.Run("returns_200_with_default_months", func(t *testing.T) {
 		var capturedMonths int
 		txSvc := &mockTransactionService{
-			getMonthlySummaryFn: func(_ uint, months int) ([]services.MonthlySummaryItem, error) {
+			getMonthlySummaryFn: func(_ models.UserID, months int, _, _ bool) ([]services.MonthlySummaryItem, error) {
 				capturedMonths = months
 				return []services.MonthlySummaryItem{
 					{Month: "2025-09", Income: 500000, Expenses: 320000},
@@ -776,7 +2005,7 @@ func TestTransactionHandler_GetMonthlySummary(t *testing.T) {
 				}, nil
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "GET", "/transactions/monthly-summary", "")
@@ -797,12 +2026,12 @@ func TestTransactionHandler_GetMonthlySummary(t *testing.T) {
 	t.Run("returns_200_with_custom_months", func(t *testing.T) {
 		var capturedMonths int
 		txSvc := &mockTransactionService{
-			getMonthlySummaryFn: func(_ uint, months int) ([]services.MonthlySummaryItem, error) {
+			getMonthlySummaryFn: func(_ models.UserID, months int, _, _ bool) ([]services.MonthlySummaryItem, error) {
 				capturedMonths = months
 				return []services.MonthlySummaryItem{}, nil
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "GET", "/transactions/monthly-summary?months=3", "")
@@ -815,13 +2044,133 @@ func TestTransactionHandler_GetMonthlySummary(t *testing.T) {
 		}
 	})
 
+	t.Run("applies_configured_default_months", func(t *testing.T) {
+		var capturedMonths int
+		txSvc := &mockTransactionService{
+			getMonthlySummaryFn: func(_ models.UserID, months int, _, _ bool) ([]services.MonthlySummaryItem, error) {
+				capturedMonths = months
+				return []services.MonthlySummaryItem{}, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{}).WithDefaultSummaryMonths(12)
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "GET", "/transactions/monthly-summary", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if capturedMonths != 12 {
+			t.Errorf("expected configured default months=12, got %d", capturedMonths)
+		}
+	})
+
+	t.Run("returns_400_for_out_of_range_months", func(t *testing.T) {
+		for _, months := range []string{"0", "37", "1000", "abc"} {
+			handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
+			r := setupTransactionRouter(handler)
+
+			rec := doRequest(r, "GET", "/transactions/monthly-summary?months="+months, "")
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("months=%s: expected 400, got %d: %s", months, rec.Code, rec.Body.String())
+			}
+			assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+		}
+	})
+
+	t.Run("accepts_max_months", func(t *testing.T) {
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "GET", "/transactions/monthly-summary?months=36", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("passes_include_excluded_flag", func(t *testing.T) {
+		var captured bool
+		txSvc := &mockTransactionService{
+			getMonthlySummaryFn: func(_ models.UserID, _ int, _, includeExcluded bool) ([]services.MonthlySummaryItem, error) {
+				captured = includeExcluded
+				return []services.MonthlySummaryItem{}, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "GET", "/transactions/monthly-summary?include_excluded=true", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if !captured {
+			t.Error("expected includeExcluded=true to be passed to service")
+		}
+	})
+
+	t.Run("passes_with_categories_flag", func(t *testing.T) {
+		var captured bool
+		txSvc := &mockTransactionService{
+			getMonthlySummaryFn: func(_ models.UserID, _ int, withCategories, _ bool) ([]services.MonthlySummaryItem, error) {
+				captured = withCategories
+				catID := testID(5)
+				return []services.MonthlySummaryItem{
+					{Month: "2025-10", Expenses: 1000, Categories: []services.MonthlyCategoryExpense{
+						{CategoryID: &catID, CategoryName: "Food", Total: 1000},
+					}},
+				}, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "GET", "/transactions/monthly-summary?with_categories=true", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if !captured {
+			t.Error("expected withCategories=true to be passed to service")
+		}
+		item := parseJSON(t, rec)["data"].([]interface{})[0].(map[string]interface{})
+		cats, ok := item["categories"].([]interface{})
+		if !ok || len(cats) != 1 {
+			t.Fatalf("expected 1 category in response, got %v", item["categories"])
+		}
+	})
+
+	t.Run("omits_categories_by_default", func(t *testing.T) {
+		var captured bool
+		txSvc := &mockTransactionService{
+			getMonthlySummaryFn: func(_ models.UserID, _ int, withCategories, _ bool) ([]services.MonthlySummaryItem, error) {
+				captured = withCategories
+				return []services.MonthlySummaryItem{{Month: "2025-10", Expenses: 1000}}, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "GET", "/transactions/monthly-summary", "")
+
+		if captured {
+			t.Error("expected withCategories=false by default")
+		}
+		item := parseJSON(t, rec)["data"].([]interface{})[0].(map[string]interface{})
+		if _, ok := item["categories"]; ok {
+			t.Error("expected categories key to be absent by default")
+		}
+	})
+
 	t.Run("returns_200_empty_data", func(t *testing.T) {
 		txSvc := &mockTransactionService{
-			getMonthlySummaryFn: func(_ uint, _ int) ([]services.MonthlySummaryItem, error) {
+			getMonthlySummaryFn: func(_ models.UserID, _ int, _, _ bool) ([]services.MonthlySummaryItem, error) {
 				return []services.MonthlySummaryItem{}, nil
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "GET", "/transactions/monthly-summary", "")
@@ -840,7 +2189,10 @@ func TestTransactionHandler_GetMonthlySummary(t *testing.T) {
 func TestTransactionHandler_GetDailySpending(t *testing.T) {
 	t.Run("returns_200_with_data", func(t *testing.T) {
 		txSvc := &mockTransactionService{
-			getDailySpendingFn: func(_ uint, _, _ time.Time) ([]services.DailySpendingItem, error) {
+			getDailySpendingFn: func(_ models.UserID, _, _ time.Time, granularity services.SpendingGranularity, _ bool) ([]services.DailySpendingItem, error) {
+				if granularity != services.SpendingGranularityDay {
+					t.Errorf("expected day granularity by default, got %q", granularity)
+				}
 				return []services.DailySpendingItem{
 					{Date: "2026-02-01", Total: 5000},
 					{Date: "2026-02-02", Total: 0},
@@ -848,7 +2200,7 @@ func TestTransactionHandler_GetDailySpending(t *testing.T) {
 				}, nil
 			},
 		}
-		handler := NewTransactionHandler(txSvc, &mockAuditService{})
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "GET", "/transactions/daily-spending?from_date=2026-02-01&to_date=2026-02-03", "")
@@ -864,7 +2216,7 @@ func TestTransactionHandler_GetDailySpending(t *testing.T) {
 	})
 
 	t.Run("returns_400_missing_from_date", func(t *testing.T) {
-		handler := NewTransactionHandler(&mockTransactionService{}, &mockAuditService{})
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "GET", "/transactions/daily-spending?to_date=2026-02-03", "")
@@ -875,7 +2227,7 @@ func TestTransactionHandler_GetDailySpending(t *testing.T) {
 	})
 
 	t.Run("returns_400_missing_to_date", func(t *testing.T) {
-		handler := NewTransactionHandler(&mockTransactionService{}, &mockAuditService{})
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "GET", "/transactions/daily-spending?from_date=2026-02-01", "")
@@ -886,7 +2238,7 @@ func TestTransactionHandler_GetDailySpending(t *testing.T) {
 	})
 
 	t.Run("returns_400_for_excessive_range", func(t *testing.T) {
-		handler := NewTransactionHandler(&mockTransactionService{}, &mockAuditService{})
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
 		r := setupTransactionRouter(handler)
 
 		rec := doRequest(r, "GET", "/transactions/daily-spending?from_date=2024-01-01&to_date=2026-02-03", "")
@@ -895,4 +2247,257 @@ func TestTransactionHandler_GetDailySpending(t *testing.T) {
 			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
 		}
 	})
+
+	t.Run("passes_granularity", func(t *testing.T) {
+		var got services.SpendingGranularity
+		txSvc := &mockTransactionService{
+			getDailySpendingFn: func(_ models.UserID, _, _ time.Time, granularity services.SpendingGranularity, _ bool) ([]services.DailySpendingItem, error) {
+				got = granularity
+				return []services.DailySpendingItem{}, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "GET", "/transactions/daily-spending?from_date=2026-01-01&to_date=2026-03-31&granularity=week", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if got != services.SpendingGranularityWeek {
+			t.Errorf("expected week granularity, got %q", got)
+		}
+	})
+
+	t.Run("bounds_weekly_and_monthly_ranges_by_months", func(t *testing.T) {
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "GET", "/transactions/daily-spending?from_date=2024-01-01&to_date=2026-12-31&granularity=month", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200 for 36 months, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		rec = doRequest(r, "GET", "/transactions/daily-spending?from_date=2024-01-01&to_date=2027-01-02&granularity=week", "")
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 beyond 36 months, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+
+	t.Run("returns_400_for_unknown_granularity", func(t *testing.T) {
+		handler := NewTransactionHandler(&mockTransactionService{}, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "GET", "/transactions/daily-spending?from_date=2026-01-01&to_date=2026-03-31&granularity=year", "")
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		assertErrorCode(t, parseJSON(t, rec), "INVALID_INPUT")
+	})
+}
+
+func TestTransactionHandler_SettlePendingTransactions(t *testing.T) {
+	t.Run("returns_200_with_count", func(t *testing.T) {
+		txSvc := &mockTransactionService{
+			settlePendingFn: func(_ time.Time) (int, error) {
+				return 4, nil
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "POST", "/pipeline/transactions/settle", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if parseJSON(t, rec)["settled"].(float64) != 4 {
+			t.Errorf("expected settled=4, got %v", parseJSON(t, rec)["settled"])
+		}
+	})
+
+	t.Run("returns_500_on_service_error", func(t *testing.T) {
+		txSvc := &mockTransactionService{
+			settlePendingFn: func(_ time.Time) (int, error) {
+				return 0, apperrors.ErrInternalServer
+			},
+		}
+		handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+		r := setupTransactionRouter(handler)
+
+		rec := doRequest(r, "POST", "/pipeline/transactions/settle", "")
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Fatalf("expected 500, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+}
+
+func TestTransactionHandler_DateRangeIsInclusive(t *testing.T) {
+	wantFrom := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	wantTo := time.Date(2026, 1, 31, 23, 59, 59, 999999999, time.UTC)
+
+	var gotFrom, gotTo time.Time
+	capture := func(from, to time.Time) { gotFrom, gotTo = from, to }
+	txSvc := &mockTransactionService{
+		getUserTransactionsFn: func(_ models.UserID, _ pagination.PageRequest, filter services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
+			capture(*filter.FromDate, *filter.ToDate)
+			resp := pagination.NewPageResponse([]models.Transaction{}, 1, 20, 0)
+			return &resp, nil
+		},
+		bulkUpdateCategoryFn: func(_ models.UserID, filter services.TransactionFilter, _ *string) (int64, error) {
+			capture(*filter.FromDate, *filter.ToDate)
+			return 0, nil
+		},
+		getSpendingByCategoryFn: func(_ models.UserID, from, to time.Time, _ services.SpendingMode, _ bool) (*services.SpendingByCategory, error) {
+			capture(from, to)
+			return &services.SpendingByCategory{}, nil
+		},
+		getSpendingByAccountFn: func(_ models.UserID, from, to time.Time, _ bool) (*services.SpendingByAccount, error) {
+			capture(from, to)
+			return &services.SpendingByAccount{}, nil
+		},
+		getSpendingByCurrencyFn: func(_ models.UserID, from, to time.Time, _ bool) (*services.SpendingByCurrency, error) {
+			capture(from, to)
+			return &services.SpendingByCurrency{}, nil
+		},
+		getDailySpendingFn: func(_ models.UserID, from, to time.Time, _ services.SpendingGranularity, _ bool) ([]services.DailySpendingItem, error) {
+			capture(from, to)
+			return []services.DailySpendingItem{}, nil
+		},
+	}
+	handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+	r := setupTransactionRouter(handler)
+
+	requests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"list", "GET", "/transactions", ""},
+		{"spending_by_category", "GET", "/transactions/spending-by-category", ""},
+		{"spending_by_account", "GET", "/reports/spending-by-account", ""},
+		{"spending_by_currency", "GET", "/transactions/spending-by-currency", ""},
+		{"daily_spending", "GET", "/transactions/daily-spending", ""},
+		{"bulk_categorize", "POST", "/transactions/bulk-categorize", `{"filter":{"from_date":"2026-01-01","to_date":"2026-01-31"},"category_id":null}`},
+	}
+
+	for _, tt := range requests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFrom, gotTo = time.Time{}, time.Time{}
+			path := tt.path
+			if tt.body == "" {
+				path += "?from_date=2026-01-01&to_date=2026-01-31"
+			}
+
+			rec := doRequest(r, tt.method, path, tt.body)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if !gotFrom.Equal(wantFrom) || !gotTo.Equal(wantTo) {
+				t.Errorf("expected %v to %v, got %v to %v", wantFrom, wantTo, gotFrom, gotTo)
+			}
+		})
+	}
+
+	t.Run("unix_seconds_and_rfc3339_are_exact", func(t *testing.T) {
+		rec := doRequest(r, "GET", "/transactions?from_date=1767225600&to_date=2026-01-31T12:00:00Z", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if !gotFrom.Equal(wantFrom) || !gotTo.Equal(time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)) {
+			t.Errorf("unexpected range %v to %v", gotFrom, gotTo)
+		}
+	})
+}
+
+func TestTransactionHandler_DateOnlyUsesUserTimeZone(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*60*60)
+	wantFrom := time.Date(2026, 1, 1, 0, 0, 0, 0, loc)
+	wantTo := time.Date(2026, 1, 31, 23, 59, 59, 999999999, loc)
+
+	var gotFrom, gotTo time.Time
+	capture := func(from, to time.Time) { gotFrom, gotTo = from, to }
+	txSvc := &mockTransactionService{
+		userLocationFn: func(models.UserID) (*time.Location, error) { return loc, nil },
+		getUserTransactionsFn: func(_ models.UserID, _ pagination.PageRequest, filter services.TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
+			capture(*filter.FromDate, *filter.ToDate)
+			resp := pagination.NewPageResponse([]models.Transaction{}, 1, 20, 0)
+			return &resp, nil
+		},
+		bulkUpdateCategoryFn: func(_ models.UserID, filter services.TransactionFilter, _ *string) (int64, error) {
+			capture(*filter.FromDate, *filter.ToDate)
+			return 0, nil
+		},
+		getSpendingByCategoryFn: func(_ models.UserID, from, to time.Time, _ services.SpendingMode, _ bool) (*services.SpendingByCategory, error) {
+			capture(from, to)
+			return &services.SpendingByCategory{}, nil
+		},
+		getDailySpendingFn: func(_ models.UserID, from, to time.Time, _ services.SpendingGranularity, _ bool) ([]services.DailySpendingItem, error) {
+			capture(from, to)
+			return []services.DailySpendingItem{}, nil
+		},
+		updateTransactionFn: func(_ models.UserID, txID models.TransactionID, updates services.TransactionUpdateFields) (*models.Transaction, error) {
+			capture(*updates.Date, *updates.Date)
+			return &models.Transaction{Base: models.Base{ID: string(txID)}}, nil
+		},
+	}
+	handler := NewTransactionHandler(txSvc, &mockAccountService{}, &mockAuditService{})
+	r := setupTransactionRouter(handler)
+
+	requests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"list", "GET", "/transactions?from_date=2026-01-01&to_date=2026-01-31", ""},
+		{"spending_by_category", "GET", "/transactions/spending-by-category?from_date=2026-01-01&to_date=2026-01-31", ""},
+		{"daily_spending", "GET", "/transactions/daily-spending?from_date=2026-01-01&to_date=2026-01-31", ""},
+		{"bulk_categorize", "POST", "/transactions/bulk-categorize", `{"filter":{"from_date":"2026-01-01","to_date":"2026-01-31"},"category_id":null}`},
+	}
+
+	for _, tt := range requests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFrom, gotTo = time.Time{}, time.Time{}
+
+			rec := doRequest(r, tt.method, tt.path, tt.body)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if !gotFrom.Equal(wantFrom) || !gotTo.Equal(wantTo) {
+				t.Errorf("expected %v to %v, got %v to %v", wantFrom, wantTo, gotFrom, gotTo)
+			}
+		})
+	}
+
+	t.Run("transaction_date", func(t *testing.T) {
+		rec := doRequest(r, "PATCH", "/transactions/00000000-0000-7000-8000-000000000001", `{"date":"2026-01-01"}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if !gotFrom.Equal(wantFrom) {
+			t.Errorf("expected %v, got %v", wantFrom, gotFrom)
+		}
+	})
+
+	t.Run("returns_error_when_the_zone_cannot_be_read", func(t *testing.T) {
+		failing := &mockTransactionService{
+			userLocationFn: func(models.UserID) (*time.Location, error) { return nil, apperrors.ErrInternalServer },
+		}
+		r := setupTransactionRouter(NewTransactionHandler(failing, &mockAccountService{}, &mockAuditService{}))
+
+		rec := doRequest(r, "GET", "/transactions?from_date=2026-01-01", "")
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Fatalf("expected 500, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
 }
@@ -2,51 +2,139 @@
 package logger
 
 import (
-	"sync"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
-var (
-	sugar *zap.SugaredLogger
-	once  sync.Once
-)
+var sugar atomic.Pointer[zap.SugaredLogger]
 
-// Init initializes the global logger for the given environment.
-// For "production", it uses a JSON encoder. For all other environments,
-// it uses a human-readable console encoder.
-func Init(env string) {
-	once.Do(func() {
-		var base *zap.Logger
-		var err error
-
-		if env == "production" {
-			base, err = zap.NewProduction()
-		} else {
-			base, err = zap.NewDevelopment()
-		}
+// Options selects the logger's level and encoding. Empty fields fall back to
+// defaults for Env: info-level JSON for "production", debug-level console
+// output for every other environment.
+type Options struct {
+	Env    string
+	Level  string // debug, info, warn or error
+	Format string // json or console
+}
 
-		if err != nil {
+// OptionsFromEnv reads Options from the ENV, LOG_LEVEL and LOG_FORMAT
+// environment variables.
+func OptionsFromEnv() Options {
+	return Options{
+		Env:    os.Getenv("ENV"),
+		Level:  os.Getenv("LOG_LEVEL"),
+		Format: os.Getenv("LOG_FORMAT"),
+	}
+}
+
+// Init initializes the global logger, replacing any logger set up earlier.
+// An invalid level or format is logged and replaced by the Env default.
+func Init(opts Options) {
+	base, err := build(opts, nil)
+	if err != nil {
+		fallback, fallbackErr := build(Options{Env: opts.Env}, nil)
+		if fallbackErr != nil {
 			// Fallback to nop logger if initialization fails.
-			base = zap.NewNop()
+			fallback = zap.NewNop()
+		}
+		fallback.Sugar().Warnf("Invalid logger options, using %s defaults: %v", envOrDefault(opts.Env), err)
+		base = fallback
+	}
+	sugar.Store(base.Sugar())
+}
+
+// build creates a logger for opts. outputPaths overrides the default stderr
+// output; tests use it to capture entries.
+func build(opts Options, outputPaths []string) (*zap.Logger, error) {
+	var cfg zap.Config
+	if opts.Env == "production" {
+		cfg = zap.NewProductionConfig()
+	} else {
+		cfg = zap.NewDevelopmentConfig()
+	}
+
+	if opts.Level != "" {
+		level, err := zapcore.ParseLevel(strings.ToLower(opts.Level))
+		if err != nil || level < zapcore.DebugLevel || level > zapcore.ErrorLevel {
+			return nil, fmt.Errorf("LOG_LEVEL must be debug, info, warn or error, got %q", opts.Level)
 		}
+		cfg.Level = zap.NewAtomicLevelAt(level)
+	}
+
+	switch strings.ToLower(opts.Format) {
+	case "":
+	case "json":
+		cfg.Encoding = "json"
+		cfg.EncoderConfig = zap.NewProductionEncoderConfig()
+	case "console":
+		cfg.Encoding = "console"
+		cfg.EncoderConfig = zap.NewDevelopmentEncoderConfig()
+	default:
+		return nil, fmt.Errorf("LOG_FORMAT must be json or console, got %q", opts.Format)
+	}
 
-		sugar = base.Sugar()
-	})
+	if outputPaths != nil {
+		cfg.OutputPaths = outputPaths
+	}
+	return cfg.Build()
+}
+
+// envOrDefault names the environment whose defaults apply to env.
+func envOrDefault(env string) string {
+	if env == "" {
+		return "development"
+	}
+	return env
 }
 
 // Get returns the global sugared logger.
 // If Init has not been called, it initializes a development logger.
 func Get() *zap.SugaredLogger {
-	if sugar == nil {
-		Init("development")
+	if s := sugar.Load(); s != nil {
+		return s
 	}
-	return sugar
+	Init(Options{Env: "development"})
+	return sugar.Load()
+}
+
+// Replace makes l the global logger and returns a function that restores the
+// previous one. Tests use it to observe what is logged.
+func Replace(l *zap.Logger) (restore func()) {
+	prev := Get()
+	sugar.Store(l.Sugar())
+	return func() { sugar.Store(prev) }
 }
 
 // Sync flushes any buffered log entries. Call this before application exit.
 func Sync() {
-	if sugar != nil {
-		_ = sugar.Sync()
+	if s := sugar.Load(); s != nil {
+		_ = s.Sync()
 	}
 }
+
+// Request identifies the HTTP request work is done for, so log entries written
+// far from the handler (such as slow-query logs) can name it.
+type Request struct {
+	ID     string
+	Route  string // Route pattern, e.g. /api/v1/budgets/:id
+	UserID string // Empty until the request is authenticated
+}
+
+type requestKey struct{}
+
+// WithRequest returns a copy of ctx carrying req.
+func WithRequest(ctx context.Context, req *Request) context.Context {
+	return context.WithValue(ctx, requestKey{}, req)
+}
+
+// RequestFrom returns the request carried by ctx, or nil.
+func RequestFrom(ctx context.Context) *Request {
+	req, _ := ctx.Value(requestKey{}).(*Request)
+	return req
+}
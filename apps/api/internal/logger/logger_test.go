@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// capture builds a logger for opts, runs log against it and returns what was written.
+func capture(t *testing.T, opts Options, log func(l *zap.SugaredLogger)) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "log")
+	base, err := build(opts, []string{path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	log(base.Sugar())
+	_ = base.Sync()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log output: %v", err)
+	}
+	return string(data)
+}
+
+func TestBuild(t *testing.T) {
+	t.Run("info_level_suppresses_debug", func(t *testing.T) {
+		out := capture(t, Options{Env: "development", Level: "info"}, func(l *zap.SugaredLogger) {
+			l.Debug("debug entry")
+			l.Info("info entry")
+		})
+		if strings.Contains(out, "debug entry") {
+			t.Error("expected debug entry to be suppressed")
+		}
+		if !strings.Contains(out, "info entry") {
+			t.Error("expected info entry to be written")
+		}
+	})
+
+	t.Run("debug_level_in_production", func(t *testing.T) {
+		out := capture(t, Options{Env: "production", Level: "DEBUG"}, func(l *zap.SugaredLogger) {
+			l.Debug("debug entry")
+		})
+		if !strings.Contains(out, "debug entry") {
+			t.Error("expected debug entry to be written")
+		}
+	})
+
+	t.Run("env_defaults", func(t *testing.T) {
+		out := capture(t, Options{Env: "production"}, func(l *zap.SugaredLogger) {
+			l.Debug("debug entry")
+			l.Info("info entry")
+		})
+		if strings.Contains(out, "debug entry") {
+			t.Error("expected production to default to info level")
+		}
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(out), &entry); err != nil {
+			t.Errorf("expected production to default to JSON, got %q", out)
+		}
+	})
+
+	t.Run("json_format_outside_production", func(t *testing.T) {
+		out := capture(t, Options{Env: "development", Format: "json"}, func(l *zap.SugaredLogger) {
+			l.Info("info entry")
+		})
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(out), &entry); err != nil || entry["msg"] != "info entry" {
+			t.Errorf("expected a JSON entry, got %q", out)
+		}
+	})
+
+	t.Run("console_format_in_production", func(t *testing.T) {
+		out := capture(t, Options{Env: "production", Format: "console"}, func(l *zap.SugaredLogger) {
+			l.Info("info entry")
+		})
+		if strings.HasPrefix(out, "{") || !strings.Contains(out, "info entry") {
+			t.Errorf("expected a console entry, got %q", out)
+		}
+	})
+
+	for _, opts := range []Options{{Level: "verbose"}, {Level: "fatal"}, {Format: "xml"}} {
+		t.Run("rejects_"+opts.Level+opts.Format, func(t *testing.T) {
+			if _, err := build(opts, nil); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestReplace(t *testing.T) {
+	original := Get()
+	restore := Replace(zap.NewNop())
+	if Get() == original {
+		t.Error("expected the replacement to be the global logger")
+	}
+	restore()
+	if Get() != original {
+		t.Error("expected restore to bring back the previous logger")
+	}
+}
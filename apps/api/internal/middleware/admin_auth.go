@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"crypto/subtle"
+
+	"github.com/gin-gonic/gin"
+
+	"kuberan/internal/config"
+	apperrors "kuberan/internal/errors"
+)
+
+// AdminAuthMiddleware creates a Gin middleware that validates the X-Admin-Key
+// header against the configured admin key. Admin endpoints are disabled while
+// no admin key is configured.
+func AdminAuthMiddleware(adminKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if adminKey == "" {
+			abortWithError(c, apperrors.ErrAdminNotConfigured)
+			return
+		}
+		key := c.GetHeader("X-Admin-Key")
+		if subtle.ConstantTimeCompare([]byte(key), []byte(adminKey)) != 1 {
+			abortWithError(c, apperrors.ErrInvalidAdminKey)
+			return
+		}
+		c.Next()
+	}
+}
+
+// NonProductionOrAdmin lets every request through outside production; in
+// production it requires the admin key as AdminAuthMiddleware does, so the
+// guarded endpoints stay disabled there unless an admin key is configured.
+func NonProductionOrAdmin(env config.Environment, adminKey string) gin.HandlerFunc {
+	if env != config.Production {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return AdminAuthMiddleware(adminKey)
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"kuberan/internal/config"
+)
+
+func TestAdminAuthMiddleware(t *testing.T) {
+	tests := []struct {
+		name          string
+		configuredKey string
+		requestKey    string
+		wantStatus    int
+		wantErrorCode string
+	}{
+		{name: "valid_admin_key", configuredKey: "admin-key", requestKey: "admin-key", wantStatus: http.StatusOK},
+		{name: "invalid_admin_key", configuredKey: "admin-key", requestKey: "wrong", wantStatus: http.StatusUnauthorized, wantErrorCode: "INVALID_ADMIN_KEY"},
+		{name: "missing_admin_key", configuredKey: "admin-key", wantStatus: http.StatusUnauthorized, wantErrorCode: "INVALID_ADMIN_KEY"},
+		{name: "not_configured", requestKey: "admin-key", wantStatus: http.StatusServiceUnavailable, wantErrorCode: "ADMIN_NOT_CONFIGURED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(AdminAuthMiddleware(tt.configuredKey))
+			r.GET("/test", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"status": "ok"})
+			})
+			req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+			if tt.requestKey != "" {
+				req.Header.Set("X-Admin-Key", tt.requestKey)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantErrorCode != "" {
+				errObj, _ := parseBody(t, rec)["error"].(map[string]interface{})
+				if code, _ := errObj["code"].(string); code != tt.wantErrorCode {
+					t.Errorf("error code = %q, want %q", code, tt.wantErrorCode)
+				}
+			}
+		})
+	}
+}
+
+func TestNonProductionOrAdmin(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        config.Environment
+		adminKey   string
+		requestKey string
+		wantStatus int
+	}{
+		{name: "development_is_open", env: config.Development, wantStatus: http.StatusOK},
+		{name: "staging_is_open", env: config.Staging, adminKey: "admin-key", wantStatus: http.StatusOK},
+		{name: "production_disabled_without_admin_key", env: config.Production, requestKey: "anything", wantStatus: http.StatusServiceUnavailable},
+		{name: "production_requires_admin_key", env: config.Production, adminKey: "admin-key", wantStatus: http.StatusUnauthorized},
+		{name: "production_with_admin_key", env: config.Production, adminKey: "admin-key", requestKey: "admin-key", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/test", NonProductionOrAdmin(tt.env, tt.adminKey), func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"status": "ok"})
+			})
+			req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+			if tt.requestKey != "" {
+				req.Header.Set("X-Admin-Key", tt.requestKey)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
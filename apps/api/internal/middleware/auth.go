@@ -12,19 +12,10 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 
 	"kuberan/internal/config"
+	"kuberan/internal/logger"
 	"kuberan/internal/models"
 )
 
-const (
-	accessTokenExpiry  = 15 * time.Minute
-	refreshTokenExpiry = 7 * 24 * time.Hour
-)
-
-// getJWTKey returns the JWT key from configuration
-func getJWTKey() []byte {
-	return []byte(config.Get().JWTSecret)
-}
-
 // JWTClaims represents the claims in the JWT
 type JWTClaims struct {
 	UserID    string `json:"user_id"`
@@ -35,55 +26,80 @@ type JWTClaims struct {
 
 // GenerateAccessToken generates a short-lived JWT access token for a user.
 func GenerateAccessToken(user *models.User) (string, error) {
-	claims := &JWTClaims{
-		UserID:    user.ID,
-		Email:     user.Email,
-		TokenType: "access",
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(accessTokenExpiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    "kuberan-api",
-			Subject:   user.ID,
-		},
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(getJWTKey())
+	return signToken(user, "access", config.Get().JWTExpirationDur)
 }
 
 // GenerateRefreshToken generates a long-lived JWT refresh token for a user.
 func GenerateRefreshToken(user *models.User) (string, error) {
+	return signToken(user, "refresh", config.Get().JWTRefreshExpirationDur)
+}
+
+// signToken issues a token of the given type, signed with the current signing
+// key and carrying its kid so it can still be verified after a rotation.
+func signToken(user *models.User, tokenType string, lifetime time.Duration) (string, error) {
+	cfg := config.Get()
+	now := time.Now()
+	key, err := cfg.SigningKey(now)
+	if err != nil {
+		return "", err
+	}
+
 	claims := &JWTClaims{
 		UserID:    user.ID,
 		Email:     user.Email,
-		TokenType: "refresh",
+		TokenType: tokenType,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(refreshTokenExpiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    "kuberan-api",
+			ExpiresAt: jwt.NewNumericDate(now.Add(lifetime)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    cfg.JWTIssuer,
 			Subject:   user.ID,
 		},
 	}
+	if cfg.JWTAudience != "" {
+		claims.Audience = jwt.ClaimStrings{cfg.JWTAudience}
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(getJWTKey())
+	token.Header["kid"] = key.ID
+	return token.SignedString([]byte(key.Secret))
 }
 
-// ValidateRefreshToken parses and validates a refresh token JWT.
-// Returns the claims if valid, or an error if the token is invalid,
-// expired, or not a refresh token.
-func ValidateRefreshToken(tokenString string) (*JWTClaims, error) {
-	claims := &JWTClaims{}
+// parseToken verifies a token's signature with the key named by its kid header
+// and checks expiry, issuer and (when configured) audience.
+func parseToken(tokenString string, claims *JWTClaims) error {
+	cfg := config.Get()
+	opts := []jwt.ParserOption{jwt.WithIssuer(cfg.JWTIssuer)}
+	if cfg.JWTAudience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.JWTAudience))
+	}
+
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return getJWTKey(), nil
-	})
+		kid, _ := token.Header["kid"].(string)
+		key, err := cfg.VerificationKey(kid, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		return []byte(key.Secret), nil
+	}, opts...)
+	if err != nil {
+		return err
+	}
+	if !token.Valid {
+		return fmt.Errorf("invalid token")
+	}
+	return nil
+}
 
-	if err != nil || !token.Valid {
+// ValidateRefreshToken parses and validates a refresh token JWT.
+// Returns the claims if valid, or an error if the token is invalid,
+// expired, or not a refresh token.
+func ValidateRefreshToken(tokenString string) (*JWTClaims, error) {
+	claims := &JWTClaims{}
+	if err := parseToken(tokenString, claims); err != nil {
 		return nil, fmt.Errorf("invalid refresh token")
 	}
 
@@ -122,14 +138,7 @@ func AuthMiddleware() gin.HandlerFunc {
 		// Parse the token
 		tokenString := parts[1]
 		claims := &JWTClaims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return getJWTKey(), nil
-		})
-
-		if err != nil || !token.Valid {
+		if err := parseToken(tokenString, claims); err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 			c.Abort()
 			return
@@ -145,6 +154,9 @@ func AuthMiddleware() gin.HandlerFunc {
 		// Set user ID and email in the context
 		c.Set("userID", claims.UserID)
 		c.Set("email", claims.Email)
+		if req := logger.RequestFrom(c.Request.Context()); req != nil {
+			req.UserID = claims.UserID
+		}
 		c.Next()
 	}
 }
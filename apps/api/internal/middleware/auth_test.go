@@ -0,0 +1,235 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"kuberan/internal/config"
+	"kuberan/internal/models"
+)
+
+func setJWTConfig(t *testing.T, audience string, keys ...config.JWTKey) {
+	t.Helper()
+	config.Set(&config.Config{
+		JWTKeys:                 keys,
+		JWTExpirationDur:        15 * time.Minute,
+		JWTRefreshExpirationDur: time.Hour,
+		JWTIssuer:               "kuberan-api",
+		JWTAudience:             audience,
+	})
+	t.Cleanup(func() { config.Set(nil) })
+}
+
+// loadEnvConfig loads the configuration from env, with the JWT variables not
+// in env unset.
+func loadEnvConfig(t *testing.T, env map[string]string) {
+	t.Helper()
+	for _, name := range []string{"JWT_SECRET", "JWT_KEYS", "JWT_KEYS_FILE", "JWT_SIGNING_KEY", "JWT_PREVIOUS_KEYS", "JWT_AUDIENCE"} {
+		t.Setenv(name, env[name])
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	config.Set(cfg)
+	t.Cleanup(func() { config.Set(nil) })
+}
+
+func setupAuthRouter() *gin.Engine {
+	r := gin.New()
+	r.Use(AuthMiddleware())
+	r.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetString("userID")})
+	})
+	return r
+}
+
+func doAuthRequest(r *gin.Engine, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+var testUser = &models.User{Base: models.Base{ID: "00000000-0000-7000-8000-000000000001"}, Email: "user@test.com"}
+
+func TestAuthMiddleware_KeyRotation(t *testing.T) {
+	oldKey := config.JWTKey{ID: "2024-01", Secret: "old-secret"}
+	newKey := config.JWTKey{ID: "2024-07", Secret: "new-secret"}
+
+	t.Run("signs_with_newest_active_key", func(t *testing.T) {
+		setJWTConfig(t, "", oldKey, newKey)
+
+		token, err := GenerateAccessToken(testUser)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		parsed, _, err := jwt.NewParser().ParseUnverified(token, &JWTClaims{})
+		if err != nil {
+			t.Fatalf("failed to parse token: %v", err)
+		}
+		if kid := parsed.Header["kid"]; kid != newKey.ID {
+			t.Errorf("expected kid %q, got %v", newKey.ID, kid)
+		}
+	})
+
+	t.Run("accepts_old_key_token_before_retirement", func(t *testing.T) {
+		setJWTConfig(t, "", oldKey)
+		token, err := GenerateAccessToken(testUser)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		retiresAt := time.Now().Add(24 * time.Hour)
+		retiring := oldKey
+		retiring.RetiresAt = &retiresAt
+		setJWTConfig(t, "", retiring, newKey)
+
+		rec := doAuthRequest(setupAuthRouter(), token)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if userID := parseBody(t, rec)["user_id"]; userID != testUser.ID {
+			t.Errorf("expected user_id %s, got %v", testUser.ID, userID)
+		}
+	})
+
+	t.Run("rejects_old_key_token_after_retirement", func(t *testing.T) {
+		setJWTConfig(t, "", oldKey)
+		token, err := GenerateAccessToken(testUser)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		retiredAt := time.Now().Add(-time.Minute)
+		retired := oldKey
+		retired.RetiresAt = &retiredAt
+		setJWTConfig(t, "", retired, newKey)
+
+		rec := doAuthRequest(setupAuthRouter(), token)
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rec.Code)
+		}
+		if _, err := ValidateRefreshToken(token); err == nil {
+			t.Error("expected retired key to be rejected for refresh tokens too")
+		}
+	})
+
+	t.Run("rejects_unknown_kid", func(t *testing.T) {
+		setJWTConfig(t, "", config.JWTKey{ID: "other", Secret: "old-secret"})
+		token, err := GenerateAccessToken(testUser)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		setJWTConfig(t, "", oldKey, newKey)
+		if rec := doAuthRequest(setupAuthRouter(), token); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rec.Code)
+		}
+	})
+
+	t.Run("previous_signing_key_still_verifies", func(t *testing.T) {
+		loadEnvConfig(t, map[string]string{"JWT_SIGNING_KEY": "first-secret"})
+		token, err := GenerateAccessToken(testUser)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		loadEnvConfig(t, map[string]string{"JWT_SIGNING_KEY": "second-secret", "JWT_PREVIOUS_KEYS": "first-secret"})
+		if rec := doAuthRequest(setupAuthRouter(), token); rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("unknown_signing_key_is_rejected", func(t *testing.T) {
+		loadEnvConfig(t, map[string]string{"JWT_SIGNING_KEY": "stranger-secret"})
+		token, err := GenerateAccessToken(testUser)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		loadEnvConfig(t, map[string]string{"JWT_SIGNING_KEY": "second-secret", "JWT_PREVIOUS_KEYS": "first-secret"})
+		if rec := doAuthRequest(setupAuthRouter(), token); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rec.Code)
+		}
+	})
+
+	t.Run("token_without_kid_uses_default_key", func(t *testing.T) {
+		setJWTConfig(t, "", config.JWTKey{ID: config.DefaultJWTKeyID, Secret: "legacy-secret"})
+		claims := &JWTClaims{
+			UserID:    testUser.ID,
+			TokenType: "access",
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+				Issuer:    "kuberan-api",
+			},
+		}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("legacy-secret"))
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+
+		if rec := doAuthRequest(setupAuthRouter(), token); rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+}
+
+func TestAuthMiddleware_IssuerAndAudience(t *testing.T) {
+	key := config.JWTKey{ID: "k1", Secret: "secret"}
+
+	t.Run("accepts_matching_audience", func(t *testing.T) {
+		setJWTConfig(t, "kuberan-web", key)
+		token, err := GenerateAccessToken(testUser)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rec := doAuthRequest(setupAuthRouter(), token); rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("rejects_wrong_audience", func(t *testing.T) {
+		setJWTConfig(t, "other-app", key)
+		token, err := GenerateAccessToken(testUser)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		setJWTConfig(t, "kuberan-web", key)
+		if rec := doAuthRequest(setupAuthRouter(), token); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rec.Code)
+		}
+	})
+
+	t.Run("rejects_wrong_issuer", func(t *testing.T) {
+		setJWTConfig(t, "", key)
+		config.Get().JWTIssuer = "someone-else"
+		token, err := GenerateAccessToken(testUser)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		setJWTConfig(t, "", key)
+		if rec := doAuthRequest(setupAuthRouter(), token); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rec.Code)
+		}
+	})
+
+	t.Run("rejects_refresh_token_as_access_token", func(t *testing.T) {
+		setJWTConfig(t, "", key)
+		token, err := GenerateRefreshToken(testUser)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rec := doAuthRequest(setupAuthRouter(), token); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rec.Code)
+		}
+	})
+}
@@ -11,8 +11,9 @@ import (
 
 // ErrorHandler returns a Gin middleware that converts errors set on the Gin
 // context into consistent JSON error responses. AppErrors are returned with
-// their code and message; unexpected errors are logged and return a generic
-// internal error to avoid leaking details.
+// their code and message and the status the catalog registers for the code;
+// unexpected errors are logged and return a generic internal error to avoid
+// leaking details.
 func ErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
@@ -34,7 +35,7 @@ func ErrorHandler() gin.HandlerFunc {
 					"path", c.Request.URL.Path,
 				)
 			}
-			c.JSON(appErr.StatusCode, gin.H{
+			c.JSON(apperrors.Status(appErr.Code), gin.H{
 				"error": gin.H{
 					"code":    appErr.Code,
 					"message": appErr.Message,
@@ -57,3 +58,14 @@ func ErrorHandler() gin.HandlerFunc {
 		})
 	}
 }
+
+// abortWithError stops the chain with err's code and message and the status
+// registered for its code.
+func abortWithError(c *gin.Context, err *apperrors.AppError) {
+	c.AbortWithStatusJSON(apperrors.Status(err.Code), gin.H{
+		"error": gin.H{
+			"code":    err.Code,
+			"message": err.Message,
+		},
+	})
+}
@@ -12,7 +12,8 @@ import (
 const requestIDKey = "requestID"
 
 // RequestLogging returns a Gin middleware that logs each request with a unique
-// request ID, method, path, status code, latency, and client IP using Zap.
+// request ID, method, path, status code, latency, and client IP using Zap. The
+// request is also attached to the request context (see logger.RequestFrom).
 func RequestLogging() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
@@ -20,6 +21,8 @@ func RequestLogging() gin.HandlerFunc {
 		requestID := uuid.New().String()
 		c.Set(requestIDKey, requestID)
 		c.Writer.Header().Set("X-Request-ID", requestID)
+		c.Request = c.Request.WithContext(logger.WithRequest(c.Request.Context(),
+			&logger.Request{ID: requestID, Route: c.FullPath()}))
 
 		c.Next()
 
@@ -2,26 +2,65 @@ package middleware
 
 import (
 	"crypto/subtle"
-	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/logger"
+	"kuberan/internal/models"
 )
 
+// ConfigPipelineKeyID is the key id logged when a request authenticates with
+// the configured bootstrap key rather than a stored one.
+const ConfigPipelineKeyID = "config"
+
+// PipelineKeyAuthenticator looks up an active stored pipeline key. It returns
+// nil without an error when the key does not match any active key.
+type PipelineKeyAuthenticator interface {
+	AuthenticatePipelineKey(key string) (*models.PipelineAPIKey, error)
+}
+
 // PipelineAuthMiddleware creates a Gin middleware that validates the X-API-Key
-// header against the configured pipeline API key.
-func PipelineAuthMiddleware(apiKey string) gin.HandlerFunc {
+// header against the active stored keys, falling back to the configured
+// pipeline API key so a fresh deployment can be bootstrapped. keys may be nil,
+// in which case only the configured key is accepted. The id of the key that
+// authenticated is set in the context as "pipelineKeyID".
+func PipelineAuthMiddleware(apiKey string, keys PipelineKeyAuthenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if apiKey == "" {
-			c.AbortWithStatusJSON(http.StatusServiceUnavailable,
-				gin.H{"error": gin.H{"code": "PIPELINE_NOT_CONFIGURED", "message": "Pipeline endpoints are not configured"}})
+		if apiKey == "" && keys == nil {
+			abortWithError(c, apperrors.ErrPipelineNotConfigured)
 			return
 		}
 		key := c.GetHeader("X-API-Key")
-		if subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) != 1 {
-			c.AbortWithStatusJSON(http.StatusUnauthorized,
-				gin.H{"error": gin.H{"code": "INVALID_API_KEY", "message": "Invalid or missing API key"}})
+
+		if keys != nil && key != "" {
+			stored, err := keys.AuthenticatePipelineKey(key)
+			if err != nil {
+				// Keep the bootstrap key usable if the key store is unavailable.
+				logger.Get().Warnw("pipeline key lookup failed", "error", err)
+			} else if stored != nil {
+				authenticatePipeline(c, stored.ID)
+				return
+			}
+		}
+
+		if apiKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1 {
+			authenticatePipeline(c, ConfigPipelineKeyID)
 			return
 		}
-		c.Next()
+
+		abortWithError(c, apperrors.ErrInvalidAPIKey)
 	}
 }
+
+// authenticatePipeline records which key authenticated the request and continues.
+func authenticatePipeline(c *gin.Context, keyID string) {
+	c.Set("pipelineKeyID", keyID)
+	logger.Get().Infow("pipeline request authenticated",
+		"request_id", c.GetString(requestIDKey),
+		"key_id", keyID,
+		"method", c.Request.Method,
+		"path", c.Request.URL.Path,
+	)
+	c.Next()
+}
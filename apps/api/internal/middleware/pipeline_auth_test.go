@@ -2,11 +2,15 @@ package middleware
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"kuberan/internal/models"
 )
 
 func init() {
@@ -14,14 +18,35 @@ func init() {
 }
 
 func setupPipelineRouter(apiKey string) *gin.Engine {
+	return setupPipelineRouterWithKeys(apiKey, nil)
+}
+
+func setupPipelineRouterWithKeys(apiKey string, keys PipelineKeyAuthenticator) *gin.Engine {
 	r := gin.New()
-	r.Use(PipelineAuthMiddleware(apiKey))
+	r.Use(PipelineAuthMiddleware(apiKey, keys))
 	r.POST("/test", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+		c.JSON(http.StatusOK, gin.H{"status": "ok", "key_id": c.GetString("pipelineKeyID")})
 	})
 	return r
 }
 
+// fakePipelineKeys is an in-memory PipelineKeyAuthenticator keyed by plaintext key.
+type fakePipelineKeys struct {
+	keys map[string]*models.PipelineAPIKey
+	err  error
+}
+
+func (f *fakePipelineKeys) AuthenticatePipelineKey(key string) (*models.PipelineAPIKey, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	stored, ok := f.keys[key]
+	if !ok || stored.RevokedAt != nil {
+		return nil, nil
+	}
+	return stored, nil
+}
+
 func doRequest(r *gin.Engine, apiKey string) *httptest.ResponseRecorder {
 	req := httptest.NewRequest(http.MethodPost, "/test", http.NoBody)
 	if apiKey != "" {
@@ -121,3 +146,95 @@ func TestPipelineAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestPipelineAuthMiddleware_StoredKeys(t *testing.T) {
+	revokedAt := time.Now()
+	keys := &fakePipelineKeys{keys: map[string]*models.PipelineAPIKey{
+		"stored-key":  {Base: models.Base{ID: "key-1"}},
+		"revoked-key": {Base: models.Base{ID: "key-2"}, RevokedAt: &revokedAt},
+	}}
+
+	tests := []struct {
+		name          string
+		configuredKey string
+		keys          *fakePipelineKeys
+		requestKey    string
+		wantStatus    int
+		wantKeyID     string
+		wantErrorCode string
+	}{
+		{
+			name:          "valid_stored_key",
+			configuredKey: "secret-pipeline-key",
+			keys:          keys,
+			requestKey:    "stored-key",
+			wantStatus:    http.StatusOK,
+			wantKeyID:     "key-1",
+		},
+		{
+			name:       "stored_key_without_config_key",
+			keys:       keys,
+			requestKey: "stored-key",
+			wantStatus: http.StatusOK,
+			wantKeyID:  "key-1",
+		},
+		{
+			name:          "revoked_key_rejected",
+			configuredKey: "secret-pipeline-key",
+			keys:          keys,
+			requestKey:    "revoked-key",
+			wantStatus:    http.StatusUnauthorized,
+			wantErrorCode: "INVALID_API_KEY",
+		},
+		{
+			name:          "config_key_fallback",
+			configuredKey: "secret-pipeline-key",
+			keys:          keys,
+			requestKey:    "secret-pipeline-key",
+			wantStatus:    http.StatusOK,
+			wantKeyID:     ConfigPipelineKeyID,
+		},
+		{
+			name:          "config_key_when_store_fails",
+			configuredKey: "secret-pipeline-key",
+			keys:          &fakePipelineKeys{err: errors.New("database unavailable")},
+			requestKey:    "secret-pipeline-key",
+			wantStatus:    http.StatusOK,
+			wantKeyID:     ConfigPipelineKeyID,
+		},
+		{
+			name:          "unknown_key_without_config_key",
+			keys:          keys,
+			requestKey:    "secret-pipeline-key",
+			wantStatus:    http.StatusUnauthorized,
+			wantErrorCode: "INVALID_API_KEY",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupPipelineRouterWithKeys(tt.configuredKey, tt.keys)
+			rec := doRequest(router, tt.requestKey)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			body := parseBody(t, rec)
+			if tt.wantKeyID != "" {
+				if keyID, _ := body["key_id"].(string); keyID != tt.wantKeyID {
+					t.Errorf("key_id = %q, want %q", keyID, tt.wantKeyID)
+				}
+			}
+			if tt.wantErrorCode != "" {
+				errObj, ok := body["error"].(map[string]interface{})
+				if !ok {
+					t.Fatal("expected error object in response")
+				}
+				if code, _ := errObj["code"].(string); code != tt.wantErrorCode {
+					t.Errorf("error code = %q, want %q", code, tt.wantErrorCode)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "kuberan/internal/errors"
+)
+
+// Timeout returns a Gin middleware that gives the rest of the chain d to
+// respond. The deadline is set on the request context, so work that honours it
+// (such as queries run with db.WithContext) is cancelled when it passes. The
+// client then gets a 503 REQUEST_TIMEOUT straight away and anything the
+// handler writes later is discarded. The middleware itself still waits for the
+// handler to return, because Gin reuses the context once the chain is done.
+func Timeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		w := c.Writer
+		buffered := newTimeoutWriter(w)
+		c.Writer = buffered
+
+		// A panic is handed back to this goroutine so gin.Recovery still sees it
+		done := make(chan interface{}, 1)
+		go func() {
+			defer func() { done <- recover() }()
+			c.Next()
+		}()
+
+		var p interface{}
+		select {
+		case p = <-done:
+			c.Writer = w
+			if p == nil {
+				buffered.copyTo(w)
+			}
+		case <-ctx.Done():
+			buffered.discard()
+			writeTimeoutResponse(w)
+			p = <-done
+			c.Writer = w
+			c.Errors = c.Errors[:0]
+			c.Abort()
+		}
+		if p != nil {
+			panic(p)
+		}
+	}
+}
+
+// writeTimeoutResponse sends the REQUEST_TIMEOUT error and flushes it, with a
+// Content-Length so the client has the whole response before the handler ends.
+func writeTimeoutResponse(w gin.ResponseWriter) {
+	body, _ := json.Marshal(gin.H{
+		"error": gin.H{
+			"code":    apperrors.ErrRequestTimeout.Code,
+			"message": apperrors.ErrRequestTimeout.Message,
+		},
+	})
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(apperrors.ErrRequestTimeout.StatusCode)
+	_, _ = w.Write(body)
+	w.Flush()
+}
+
+// timeoutWriter buffers a handler's response until it is copied to the real
+// writer, or dropped when the deadline passes first. It has its own header
+// map, so the handler never touches the real writer while the timeout
+// response is written.
+type timeoutWriter struct {
+	gin.ResponseWriter
+
+	header http.Header
+
+	mu        sync.Mutex
+	body      bytes.Buffer
+	status    int
+	size      int
+	discarded bool
+}
+
+func newTimeoutWriter(w gin.ResponseWriter) *timeoutWriter {
+	return &timeoutWriter{ResponseWriter: w, header: w.Header().Clone(), status: http.StatusOK, size: -1}
+}
+
+func (w *timeoutWriter) Header() http.Header { return w.header }
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if code > 0 && w.size == -1 {
+		w.status = code
+	}
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.size == -1 {
+		w.size = 0
+	}
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.discarded {
+		return 0, http.ErrHandlerTimeout
+	}
+	if w.size == -1 {
+		w.size = 0
+	}
+	n, err := w.body.Write(data)
+	w.size += n
+	return n, err
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *timeoutWriter) Status() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status
+}
+
+func (w *timeoutWriter) Size() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.size
+}
+
+func (w *timeoutWriter) Written() bool { return w.Size() != -1 }
+
+// Flush is a no-op: nothing reaches the client before the handler is done.
+func (w *timeoutWriter) Flush() {}
+
+func (w *timeoutWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, errors.New("hijacking is not supported by the timeout middleware")
+}
+
+func (w *timeoutWriter) Pusher() http.Pusher { return nil }
+
+// discard drops the buffered response and fails any further writes.
+func (w *timeoutWriter) discard() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.discarded = true
+	w.body.Reset()
+}
+
+// copyTo writes the buffered headers, status and body to dst.
+func (w *timeoutWriter) copyTo(dst gin.ResponseWriter) {
+	h := dst.Header()
+	for key := range h {
+		delete(h, key)
+	}
+	for key, values := range w.header {
+		h[key] = values
+	}
+
+	dst.WriteHeader(w.status)
+	if w.size != -1 {
+		dst.WriteHeaderNow()
+		_, _ = dst.Write(w.body.Bytes())
+	}
+}
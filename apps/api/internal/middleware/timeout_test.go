@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("passes_through_a_fast_response", func(t *testing.T) {
+		r := gin.New()
+		r.Use(func(c *gin.Context) {
+			c.Header("X-Request-ID", "req-1")
+			c.Next()
+		})
+		r.GET("/fast", Timeout(time.Second), func(c *gin.Context) {
+			c.Header("X-Handler", "yes")
+			c.JSON(http.StatusCreated, gin.H{"ok": true})
+		})
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fast", http.NoBody))
+
+		if rec.Code != http.StatusCreated || rec.Body.String() != `{"ok":true}` {
+			t.Errorf("expected the handler's response, got %d %s", rec.Code, rec.Body.String())
+		}
+		if rec.Header().Get("X-Handler") != "yes" || rec.Header().Get("X-Request-ID") != "req-1" {
+			t.Errorf("expected handler and earlier headers, got %v", rec.Header())
+		}
+	})
+
+	t.Run("responds_503_and_releases_the_client", func(t *testing.T) {
+		release := make(chan struct{})
+		type outcome struct {
+			ctxErr   error
+			writeErr error
+		}
+		finished := make(chan outcome, 1)
+
+		r := gin.New()
+		r.Use(ErrorHandler())
+		r.GET("/slow", Timeout(50*time.Millisecond), func(c *gin.Context) {
+			<-release
+			_, err := c.Writer.Write([]byte("late"))
+			_ = c.Error(errors.New("too late"))
+			finished <- outcome{ctxErr: c.Request.Context().Err(), writeErr: err}
+		})
+		srv := httptest.NewServer(r)
+		defer srv.Close()
+		defer func() {
+			select {
+			case <-release:
+			default:
+				close(release)
+			}
+		}()
+
+		// The response arrives in full while the handler is still blocked
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/slow", http.NoBody)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			t.Fatalf("failed to read the body before the handler finished: %v", err)
+		}
+
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("expected 503, got %d", resp.StatusCode)
+		}
+		var payload struct {
+			Error struct {
+				Code string `json:"code"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil || payload.Error.Code != "REQUEST_TIMEOUT" {
+			t.Errorf("expected a REQUEST_TIMEOUT error, got %s", body)
+		}
+
+		close(release)
+		got := <-finished
+		if !errors.Is(got.ctxErr, context.DeadlineExceeded) {
+			t.Errorf("expected the handler's context to be past its deadline, got %v", got.ctxErr)
+		}
+		if !errors.Is(got.writeErr, http.ErrHandlerTimeout) {
+			t.Errorf("expected late writes to fail, got %v", got.writeErr)
+		}
+	})
+
+	t.Run("panics_reach_recovery", func(t *testing.T) {
+		r := gin.New()
+		r.Use(gin.Recovery())
+		r.GET("/panic", Timeout(time.Second), func(c *gin.Context) {
+			panic("boom")
+		})
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", http.NoBody))
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("expected 500 from recovery, got %d", rec.Code)
+		}
+	})
+}
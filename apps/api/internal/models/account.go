@@ -27,6 +27,29 @@ type Account struct {
 	Currency    string      `gorm:"not null;default:'USD'" json:"currency"`
 	IsActive    bool        `gorm:"default:true" json:"is_active"`
 
+	// When the account was opened, if backdated at creation; reports leave the
+	// account out before it
+	OpeningDate *time.Time `json:"opening_date,omitempty"`
+
+	// Set when the account is deactivated: when it happened and its balance then
+	ArchivedAt      *time.Time `json:"archived_at,omitempty"`
+	ArchivedBalance *int64     `gorm:"type:bigint" json:"archived_balance,omitempty"`
+
+	// Excluded accounts are left out of net worth snapshots and spending reports
+	ExcludeFromReports bool `gorm:"not null;default:false" json:"exclude_from_reports"`
+
+	// Folder the account is filed under; nil for ungrouped accounts
+	GroupID *string `gorm:"type:uuid" json:"group_id,omitempty"`
+
+	// Category given to new transactions on the account that arrive without one
+	// and match no categorization rule
+	DefaultCategoryID *string `gorm:"type:uuid" json:"default_category_id,omitempty"`
+
+	// For cash accounts: with AllowNegative off, expenses and outgoing transfers
+	// may not take the balance below MinBalance
+	AllowNegative bool  `gorm:"not null;default:true" json:"allow_negative"`
+	MinBalance    int64 `gorm:"type:bigint;not null;default:0" json:"min_balance"`
+
 	// For investment accounts
 	Broker        string       `json:"broker,omitempty"` // E.g., Robinhood, Fidelity, etc.
 	AccountNumber string       `json:"account_number,omitempty"`
@@ -48,6 +71,12 @@ func (a *Account) BeforeCreate(tx *gorm.DB) error {
 		return err
 	}
 
+	// Overdraft protection applies to cash accounts only
+	if a.Type != AccountTypeCash {
+		a.AllowNegative = true
+		a.MinBalance = 0
+	}
+
 	switch a.Type {
 	case AccountTypeCash:
 		a.Broker = ""
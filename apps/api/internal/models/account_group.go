@@ -0,0 +1,10 @@
+package models
+
+// AccountGroup is a user-defined folder for organizing accounts (e.g.,
+// "Retirement", "Daily"). Groups are listed by SortOrder, lowest first.
+type AccountGroup struct {
+	Base
+	UserID    string `gorm:"type:uuid;not null" json:"user_id"`
+	Name      string `gorm:"not null" json:"name"`
+	SortOrder int    `gorm:"not null;default:0" json:"sort_order"`
+}
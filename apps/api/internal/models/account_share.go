@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// ShareRole represents what a shared user may do with the owner's accounts.
+type ShareRole string
+
+const (
+	ShareRoleViewer ShareRole = "viewer"
+	ShareRoleEditor ShareRole = "editor"
+)
+
+// AccountShare grants another user access to some or all of an owner's accounts.
+// The share takes effect once the invited user accepts it.
+type AccountShare struct {
+	Base
+	OwnerID      string     `gorm:"type:uuid;not null;index" json:"owner_id"`
+	SharedWithID string     `gorm:"type:uuid;not null;index" json:"shared_with_id"`
+	Role         ShareRole  `gorm:"not null" json:"role"`
+	AllAccounts  bool       `gorm:"not null;default:false" json:"all_accounts"`
+	AcceptedAt   *time.Time `json:"accepted_at,omitempty"`
+
+	// AccountIDs lists the shared accounts when AllAccounts is false.
+	AccountIDs []string `gorm:"-" json:"account_ids,omitempty"`
+}
+
+// AccountShareAccount links an AccountShare to one of the accounts it covers.
+type AccountShareAccount struct {
+	AccountShareID string `gorm:"type:uuid;primaryKey" json:"account_share_id"`
+	AccountID      string `gorm:"type:uuid;primaryKey;index" json:"account_id"`
+}
@@ -0,0 +1,23 @@
+package models
+
+// RuleMatchType represents how a categorization rule pattern is matched
+type RuleMatchType string
+
+const (
+	RuleMatchTypeContains RuleMatchType = "contains"
+	RuleMatchTypeRegex    RuleMatchType = "regex"
+)
+
+// CategorizationRule assigns a category to transactions whose description matches a pattern.
+// Rules with a higher priority are evaluated first.
+type CategorizationRule struct {
+	Base
+	UserID     string        `gorm:"type:uuid;not null" json:"user_id"`
+	CategoryID string        `gorm:"type:uuid;not null" json:"category_id"`
+	Pattern    string        `gorm:"not null" json:"pattern"`
+	MatchType  RuleMatchType `gorm:"not null" json:"match_type"`
+	Priority   int           `gorm:"not null;default:0" json:"priority"`
+
+	// Relationships
+	Category Category `gorm:"foreignKey:CategoryID" json:"category"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"kuberan/internal/uuid"
+
+	"gorm.io/gorm"
+)
+
+// ExchangeRate is a recorded conversion rate between two currencies: one unit
+// of FromCurrency buys Rate units of ToCurrency. Like SecurityPrice it is
+// time-series data with at most one rate per pair and timestamp.
+type ExchangeRate struct {
+	ID           string    `gorm:"type:uuid;primaryKey" json:"id"`
+	FromCurrency string    `gorm:"size:3;not null;uniqueIndex:uq_exchange_rates_pair_recorded" json:"from_currency"`
+	ToCurrency   string    `gorm:"size:3;not null;uniqueIndex:uq_exchange_rates_pair_recorded" json:"to_currency"`
+	Rate         float64   `gorm:"not null" json:"rate"`
+	RecordedAt   time.Time `gorm:"not null;uniqueIndex:uq_exchange_rates_pair_recorded" json:"recorded_at"`
+}
+
+// BeforeCreate hook generates a UUIDv7 for new records
+func (r *ExchangeRate) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New()
+	}
+	return nil
+}
@@ -5,13 +5,28 @@ import "time"
 // Investment represents a holding of a specific investment asset.
 type Investment struct {
 	Base
-	AccountID        string  `gorm:"type:uuid;not null" json:"account_id"`
-	SecurityID       string  `gorm:"type:uuid;not null" json:"security_id"`
-	Quantity         float64 `gorm:"not null" json:"quantity"`
-	CostBasis        int64   `gorm:"type:bigint;not null" json:"cost_basis"`
-	RealizedGainLoss int64   `gorm:"type:bigint;not null;default:0" json:"realized_gain_loss"`
-	CurrentPrice     int64   `gorm:"-" json:"current_price"` // Populated at query time from security_prices
-	WalletAddress    string  `json:"wallet_address,omitempty"`
+	AccountID        string     `gorm:"type:uuid;not null" json:"account_id"`
+	SecurityID       string     `gorm:"type:uuid;not null" json:"security_id"`
+	Quantity         float64    `gorm:"not null" json:"quantity"`
+	CostBasis        int64      `gorm:"type:bigint;not null" json:"cost_basis"`
+	RealizedGainLoss int64      `gorm:"type:bigint;not null;default:0" json:"realized_gain_loss"`
+	CurrentPrice     int64      `gorm:"-" json:"current_price"`       // Populated at query time from security_prices
+	CurrentPriceAsOf *time.Time `gorm:"-" json:"current_price_as_of"` // recorded_at of the price used for CurrentPrice; nil when unpriced
+	WalletAddress    string     `json:"wallet_address,omitempty"`
+	Notes            string     `gorm:"type:text;not null;default:''" json:"notes"`
+	TargetPrice      *int64     `gorm:"type:bigint" json:"target_price"` // Optional price the user is watching for
+	AtTarget         bool       `gorm:"-" json:"at_target"`              // CurrentPrice has reached TargetPrice
+	PricePending     bool       `gorm:"-" json:"price_pending"`          // The security has no recorded price yet
+
+	// Computed from CurrentPrice at query time; zero while the price is pending
+	CurrentValue       int64   `gorm:"-" json:"current_value"`        // Quantity * CurrentPrice
+	UnrealizedGainLoss int64   `gorm:"-" json:"unrealized_gain_loss"` // CurrentValue - CostBasis
+	GainLossPct        float64 `gorm:"-" json:"gain_loss_pct"`        // 0 when CostBasis is 0
+	DayChange          *int64  `gorm:"-" json:"day_change"`           // Value change since the previous price; nil with only one price
+
+	// Lifetime totals from the holding's transactions; only set on a single holding
+	TotalFees      int64 `gorm:"-" json:"total_fees"`      // fees paid on buys and sells
+	TotalDividends int64 `gorm:"-" json:"total_dividends"` // dividend amounts received
 
 	// Relationships
 	Security     Security                `gorm:"foreignKey:SecurityID" json:"security"`
@@ -30,10 +45,28 @@ const (
 	InvestmentTransactionTransfer InvestmentTransactionType = "transfer"
 )
 
+// Dividend types a dividend can be recorded with. DividendReturnOfCapital is a
+// distribution that returns part of the investment rather than paying income:
+// it lowers the cost basis instead of counting towards dividends received.
+const (
+	DividendCash            = "cash"
+	DividendStock           = "stock"
+	DividendSpecial         = "special"
+	DividendReturnOfCapital = "return_of_capital"
+)
+
+// CostBasisMethod is how a sell is matched to the cost of the units it disposes of.
+type CostBasisMethod string
+
+const (
+	CostBasisAverage CostBasisMethod = "average" // proportional share of the holding's cost basis
+	CostBasisFIFO    CostBasisMethod = "fifo"    // cost of the oldest buy lots first
+)
+
 // InvestmentTransaction represents a transaction for an investment.
 type InvestmentTransaction struct {
 	Base
-	InvestmentID     string                    `gorm:"type:uuid;not null" json:"investment_id"`
+	InvestmentID     string                    `gorm:"type:uuid;not null;uniqueIndex:uq_investment_transactions_external_ref" json:"investment_id"`
 	Type             InvestmentTransactionType `gorm:"not null" json:"type"`
 	Date             time.Time                 `gorm:"not null" json:"date"`
 	Quantity         float64                   `gorm:"not null" json:"quantity"`
@@ -43,11 +76,25 @@ type InvestmentTransaction struct {
 	Notes            string                    `json:"notes"`
 	RealizedGainLoss int64                     `gorm:"type:bigint;not null;default:0" json:"realized_gain_loss"`
 
-	// For splits
-	SplitRatio float64 `json:"split_ratio,omitempty"`
+	// For sells: the method used for RealizedGainLoss, kept so later preference changes don't rewrite it
+	CostBasisMethod CostBasisMethod `gorm:"type:varchar(10);not null;default:''" json:"cost_basis_method,omitempty"`
+
+	// For splits; SplitRemainder is the post-split quantity rounded away (negative when rounded up)
+	SplitRatio     float64 `json:"split_ratio,omitempty"`
+	SplitRemainder float64 `gorm:"not null;default:0" json:"split_remainder,omitempty"`
 
 	// For dividends
-	DividendType string `json:"dividend_type,omitempty"` // Cash, Stock, Special
+	DividendType string `json:"dividend_type,omitempty"` // cash, stock, special, return_of_capital; CashInLieu for split remainders
+
+	// For buys funded from a cash account: the transfer that debited it
+	CashTransactionID *string `gorm:"type:uuid" json:"cash_transaction_id,omitempty"`
+
+	// Source-provided idempotency key, unique per investment; nil for manual entries
+	ExternalRef *string `gorm:"type:varchar(100);uniqueIndex:uq_investment_transactions_external_ref" json:"external_ref,omitempty"`
+
+	// Position after this transaction, replayed from the first one when listing an investment's transactions
+	RunningQuantity  float64 `gorm:"-" json:"running_quantity"`
+	RunningCostBasis int64   `gorm:"-" json:"running_cost_basis"`
 
 	// Relationships
 	Investment Investment `gorm:"foreignKey:InvestmentID" json:"investment"`
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// OracleRunStatus summarizes how a price oracle run went.
+type OracleRunStatus string
+
+const (
+	OracleRunStatusOK      OracleRunStatus = "ok"      // finished without errors
+	OracleRunStatusPartial OracleRunStatus = "partial" // finished, but some symbols failed
+	OracleRunStatusFailed  OracleRunStatus = "failed"  // stopped before finishing
+)
+
+// OracleRun is the result of one price oracle run as reported by the oracle,
+// kept so that whether prices are flowing can be seen without its logs.
+type OracleRun struct {
+	Base
+	Version           string           `gorm:"size:64;not null;default:''" json:"version"`
+	Status            OracleRunStatus  `gorm:"size:16;not null" json:"status"`
+	Error             string           `gorm:"type:text;not null;default:''" json:"error,omitempty"` // why a failed run stopped
+	DryRun            bool             `gorm:"not null;default:false" json:"dry_run"`
+	StartedAt         time.Time        `gorm:"not null;index" json:"started_at"`
+	DurationMS        int64            `gorm:"not null" json:"duration_ms"`
+	SecuritiesFetched int              `gorm:"not null" json:"securities_fetched"`
+	PricesRecorded    int              `gorm:"not null" json:"prices_recorded"`
+	SnapshotsRecorded int              `gorm:"not null" json:"snapshots_recorded"`
+	ErrorCount        int              `gorm:"not null" json:"error_count"`
+	Errors            []OracleRunError `gorm:"foreignKey:RunID" json:"errors"`
+}
+
+// OracleRunError is a symbol whose price an oracle run failed to fetch or record.
+type OracleRunError struct {
+	Base
+	RunID      string `gorm:"type:uuid;not null;index" json:"-"`
+	SecurityID string `gorm:"size:64;not null;default:''" json:"security_id,omitempty"`
+	Symbol     string `gorm:"not null;default:''" json:"symbol"`
+	Error      string `gorm:"type:text;not null" json:"error"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// OutboxEvent is a domain event written in the same database transaction as
+// the change it describes, so it exists exactly when the change was committed.
+// The event dispatcher delivers it to in-process consumers and sets
+// ProcessedAt once every consumer has accepted it. Payload is JSON whose shape
+// is fixed by Type and Version.
+type OutboxEvent struct {
+	Base
+	Type         string     `gorm:"size:64;not null;index" json:"type"`
+	Version      int        `gorm:"not null;default:1" json:"version"`
+	Payload      string     `gorm:"type:text;not null" json:"payload"`
+	Attempts     int        `gorm:"not null;default:0" json:"attempts"`
+	LastError    string     `gorm:"type:text;not null;default:''" json:"last_error,omitempty"`
+	ClaimedUntil *time.Time `json:"-"` // lease held by a dispatcher while it delivers the event
+	ProcessedAt  *time.Time `gorm:"index" json:"processed_at,omitempty"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// PendingBulkDelete holds a previewed bulk transaction delete until it is
+// confirmed. Only the SHA-256 hash of the confirmation token is stored.
+type PendingBulkDelete struct {
+	Base
+	UserID           string    `gorm:"type:uuid;not null;index" json:"user_id"`
+	Selection        string    `gorm:"type:text;not null" json:"-"` // JSON-encoded services.BulkDeleteSelection
+	TransactionCount int64     `gorm:"not null" json:"transaction_count"`
+	TotalAmount      int64     `gorm:"not null" json:"total_amount"`
+	TokenHash        string    `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	ExpiresAt        time.Time `gorm:"not null;index" json:"expires_at"`
+}
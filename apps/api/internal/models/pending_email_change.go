@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// PendingEmailChange holds a requested login email change until the new
+// address is confirmed. Only the SHA-256 hash of the verification token is stored.
+type PendingEmailChange struct {
+	Base
+	UserID    string    `gorm:"type:uuid;not null;index" json:"user_id"`
+	NewEmail  string    `gorm:"not null;index" json:"new_email"`
+	TokenHash string    `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`
+}
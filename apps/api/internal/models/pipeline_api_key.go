@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// PipelineAPIKey is a stored credential for the pipeline endpoints. Several
+// keys can be active at once so a new key can be rolled out before the old
+// one is revoked. Only the SHA-256 hash of the key is stored; Prefix keeps
+// the first few characters so a key can be recognized in listings and logs.
+type PipelineAPIKey struct {
+	Base
+	Name       string     `gorm:"not null" json:"name"`
+	Prefix     string     `gorm:"size:16;not null" json:"prefix"`
+	KeyHash    string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `gorm:"index" json:"revoked_at,omitempty"`
+}
@@ -8,6 +8,15 @@ import (
 	"gorm.io/gorm"
 )
 
+// SnapshotInterval is the spacing between snapshots produced by a backfill.
+type SnapshotInterval string
+
+const (
+	SnapshotIntervalDaily   SnapshotInterval = "daily"
+	SnapshotIntervalWeekly  SnapshotInterval = "weekly"
+	SnapshotIntervalMonthly SnapshotInterval = "monthly"
+)
+
 // PortfolioSnapshot represents a point-in-time snapshot of a user's net worth.
 // This is immutable time-series data — no Base embed, no soft deletes.
 type PortfolioSnapshot struct {
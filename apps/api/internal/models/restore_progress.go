@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// RestoreProgress records an entity group of a backup archive that has been
+// restored for a user, so an interrupted restore of the same archive resumes
+// after the last completed group.
+type RestoreProgress struct {
+	Base
+	UserID      string    `gorm:"type:uuid;not null;uniqueIndex:uq_restore_progress_group" json:"user_id"`
+	ArchiveID   string    `gorm:"size:64;not null;uniqueIndex:uq_restore_progress_group" json:"archive_id"` // SHA-256 of the archive's section checksums
+	GroupName   string    `gorm:"size:40;not null;uniqueIndex:uq_restore_progress_group" json:"group_name"`
+	Restored    int       `gorm:"not null" json:"restored"`
+	CompletedAt time.Time `gorm:"not null" json:"completed_at"`
+}
+
+// RestoreIDMapping maps the ID a record had in a backup archive to the ID it
+// was restored under, so later groups can point at records restored earlier.
+type RestoreIDMapping struct {
+	Base
+	UserID    string `gorm:"type:uuid;not null;index:idx_restore_id_mappings_archive" json:"user_id"`
+	ArchiveID string `gorm:"size:64;not null;index:idx_restore_id_mappings_archive" json:"archive_id"`
+	Entity    string `gorm:"size:40;not null" json:"entity"`
+	OldID     string `gorm:"type:uuid;not null" json:"old_id"`
+	NewID     string `gorm:"type:uuid;not null" json:"new_id"`
+}
@@ -8,14 +8,29 @@ import (
 	"gorm.io/gorm"
 )
 
+// PriceSource identifies where a recorded price came from.
+type PriceSource string
+
+const (
+	PriceSourceYahoo     PriceSource = "yahoo"
+	PriceSourceCoinGecko PriceSource = "coingecko"
+	PriceSourceBursa     PriceSource = "bursa"
+	PriceSourceManual    PriceSource = "manual"
+)
+
 // SecurityPrice represents a historical price entry for a security.
-// This is immutable time-series data — no Base embed, no soft deletes.
+// This is time-series data — no Base embed. There is at most one price per
+// security and timestamp; re-recording a timestamp replaces it. A bad price
+// can be soft-deleted by a correction, and re-recording its timestamp restores it.
 type SecurityPrice struct {
-	ID         string    `gorm:"type:uuid;primaryKey" json:"id"`
-	SecurityID string    `gorm:"type:uuid;not null" json:"security_id"`
-	Price      int64     `gorm:"type:bigint;not null" json:"price"`
-	RecordedAt time.Time `gorm:"not null" json:"recorded_at"`
-	Security   Security  `gorm:"foreignKey:SecurityID" json:"security,omitempty"`
+	ID         string         `gorm:"type:uuid;primaryKey" json:"id"`
+	SecurityID string         `gorm:"type:uuid;not null;uniqueIndex:uq_security_prices_security_recorded" json:"security_id"`
+	Price      int64          `gorm:"type:bigint;not null" json:"price"`
+	RecordedAt time.Time      `gorm:"not null;uniqueIndex:uq_security_prices_security_recorded" json:"recorded_at"`
+	Source     PriceSource    `gorm:"type:varchar(20);not null;default:''" json:"source,omitempty"`  // empty for prices recorded before sources were tracked
+	Currency   string         `gorm:"type:varchar(3);not null;default:''" json:"currency,omitempty"` // empty when in the security's currency
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+	Security   Security       `gorm:"foreignKey:SecurityID" json:"security,omitempty"`
 }
 
 // BeforeCreate hook generates a UUIDv7 for new records
@@ -12,6 +12,25 @@ const (
 	TransactionTypeInvestment TransactionType = "investment"
 )
 
+// TransactionStatus tracks reconciliation against the bank: a pending
+// transaction is recorded and moves the balance but has not posted yet.
+// This is separate from IsPending, which holds back the balance change itself.
+type TransactionStatus string
+
+const (
+	TransactionStatusPending TransactionStatus = "pending"
+	TransactionStatusCleared TransactionStatus = "cleared"
+)
+
+// TransactionDirection tells whether a transaction moved money into or out of
+// the accounts it is listed for.
+type TransactionDirection string
+
+const (
+	TransactionDirectionIn  TransactionDirection = "in"
+	TransactionDirectionOut TransactionDirection = "out"
+)
+
 // Transaction represents a financial transaction in the system
 type Transaction struct {
 	Base
@@ -23,11 +42,45 @@ type Transaction struct {
 	Description string          `json:"description"`
 	Date        time.Time       `gorm:"not null" json:"date"`
 
+	// Pending transactions have not yet been applied to account balances.
+	// They settle once their date arrives.
+	IsPending bool `gorm:"not null;default:false" json:"is_pending"`
+
+	// Status is the reconciliation status; only cleared transactions count
+	// towards cleared balances.
+	Status TransactionStatus `gorm:"type:varchar(20);not null;default:cleared" json:"status"`
+
+	// OriginalAmount and OriginalCurrency record what a foreign-currency
+	// transaction cost in the currency it was made in, in that currency's minor
+	// units. They are for reference only; Amount in the account's currency is
+	// what moves the balance. Both are set or both are nil.
+	OriginalAmount   *int64  `gorm:"type:bigint" json:"original_amount,omitempty"`
+	OriginalCurrency *string `gorm:"size:3" json:"original_currency,omitempty"`
+
 	// For transfers
 	ToAccountID *string `gorm:"type:uuid" json:"to_account_id,omitempty"`
 
+	// TransferGroupID links the legs of a split transfer, which are deleted together.
+	TransferGroupID *string `gorm:"type:uuid;index" json:"transfer_group_id,omitempty"`
+
+	// Flagged is set at query time when the amount reaches the viewing user's
+	// large-transaction threshold. It is not stored.
+	Flagged bool `gorm:"-" json:"flagged"`
+
+	// Direction is set at query time relative to the listed accounts, so a
+	// transfer reads "out" for its source and "in" for its destination. Amount
+	// is always positive. Empty for transfers between two listed accounts.
+	Direction TransactionDirection `gorm:"-" json:"direction,omitempty"`
+
 	// Relationships
 	Account   Account   `gorm:"foreignKey:AccountID" json:"account"`
 	ToAccount *Account  `gorm:"foreignKey:ToAccountID" json:"to_account,omitempty"`
 	Category  *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
 }
+
+// FundsInvestment reports whether t is the cash leg of an investment buy. It
+// debited AccountID only; ToAccountID names the investment account bought
+// into, whose value comes from its holdings rather than from this transaction.
+func (t *Transaction) FundsInvestment() bool {
+	return t.Type == TransactionTypeInvestment && t.ToAccountID != nil
+}
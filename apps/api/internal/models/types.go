@@ -0,0 +1,22 @@
+package models
+
+// Defined types for the identifiers and amounts passed through the service
+// interfaces. Model fields keep their plain types, so JSON and the database
+// are unaffected; the point is that a user ID cannot be passed where an
+// account ID is expected, or a quantity where an amount is, without an
+// explicit conversion at the call site.
+
+// Cents is a monetary amount in the minor units of its currency.
+type Cents int64
+
+// UserID identifies a User.
+type UserID string
+
+// AccountID identifies an Account.
+type AccountID string
+
+// TransactionID identifies a Transaction.
+type TransactionID string
+
+// InvestmentID identifies an Investment.
+type InvestmentID string
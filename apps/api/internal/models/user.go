@@ -2,20 +2,41 @@ package models
 
 import "time"
 
+// WeekStart represents the first day of the week used for calendar displays.
+type WeekStart string
+
+const (
+	WeekStartMonday   WeekStart = "monday"
+	WeekStartSunday   WeekStart = "sunday"
+	WeekStartSaturday WeekStart = "saturday"
+)
+
 // User represents the user model in the database
 type User struct {
 	Base
-	Email               string        `gorm:"uniqueIndex;not null" json:"email"`
-	Password            string        `gorm:"not null" json:"-"`
-	FirstName           string        `json:"first_name"`
-	LastName            string        `json:"last_name"`
-	IsActive            bool          `gorm:"default:true" json:"is_active"`
-	RefreshTokenHash    string        `gorm:"size:64" json:"-"`
-	FailedLoginAttempts int           `gorm:"default:0" json:"-"`
-	LockedUntil         *time.Time    `json:"-"`
-	LastLoginAt         *time.Time    `json:"last_login_at,omitempty"`
-	Accounts            []Account     `gorm:"foreignKey:UserID" json:"accounts,omitempty"`
-	Budgets             []Budget      `gorm:"foreignKey:UserID" json:"budgets,omitempty"`
-	Categories          []Category    `gorm:"foreignKey:UserID" json:"categories,omitempty"`
-	Transactions        []Transaction `gorm:"foreignKey:UserID" json:"transactions,omitempty"`
+	Email                     string          `gorm:"uniqueIndex;not null" json:"email"`
+	ConflictedEmail           *string         `gorm:"size:255" json:"-"`                 // original address of a case-insensitive duplicate; see migration 000036
+	Username                  *string         `gorm:"size:30" json:"username,omitempty"` // optional login name, unique regardless of case; see migration 000044
+	UsernameChangedAt         *time.Time      `json:"-"`
+	Password                  string          `gorm:"not null" json:"-"`
+	FirstName                 string          `json:"first_name"`
+	LastName                  string          `json:"last_name"`
+	DisplayName               string          `gorm:"default:''" json:"display_name"`
+	BaseCurrency              string          `gorm:"not null;default:'USD'" json:"base_currency"`
+	Locale                    string          `gorm:"not null;default:'en-US'" json:"locale"`
+	WeekStart                 WeekStart       `gorm:"not null;default:'monday'" json:"week_start"`
+	Timezone                  string          `gorm:"size:64;not null;default:''" json:"timezone"` // IANA name; empty uses the server's reporting zone
+	CostBasisMethod           CostBasisMethod `gorm:"not null;default:'average'" json:"cost_basis_method"`
+	Preferences               string          `gorm:"type:text;not null;default:'{}'" json:"-"`
+	LargeTransactionThreshold int64           `gorm:"not null;default:0" json:"large_transaction_threshold"` // minor units; 0 disables alerts
+	IsActive                  bool            `gorm:"default:true" json:"is_active"`
+	IsDemo                    bool            `gorm:"not null;default:false" json:"is_demo"` // seeded demo user, removed by the demo purge
+	RefreshTokenHash          string          `gorm:"size:64" json:"-"`
+	FailedLoginAttempts       int             `gorm:"default:0" json:"-"`
+	LockedUntil               *time.Time      `json:"-"`
+	LastLoginAt               *time.Time      `json:"last_login_at,omitempty"`
+	Accounts                  []Account       `gorm:"foreignKey:UserID" json:"accounts,omitempty"`
+	Budgets                   []Budget        `gorm:"foreignKey:UserID" json:"budgets,omitempty"`
+	Categories                []Category      `gorm:"foreignKey:UserID" json:"categories,omitempty"`
+	Transactions              []Transaction   `gorm:"foreignKey:UserID" json:"transactions,omitempty"`
 }
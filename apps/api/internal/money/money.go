@@ -0,0 +1,121 @@
+// Package money converts between human-readable decimal amounts and the
+// integer minor units amounts are stored in, using each currency's precision.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// DefaultMinorUnits is the number of decimal places assumed for currencies
+// that are not listed in minorUnits.
+const DefaultMinorUnits = 2
+
+// minorUnits lists currencies whose precision differs from DefaultMinorUnits.
+var minorUnits = map[string]int{
+	// Zero-decimal currencies (ISO 4217)
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0,
+	"KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0,
+	// Three-decimal currencies (ISO 4217)
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	// Crypto assets
+	"BTC": 8, "ETH": 8,
+}
+
+var (
+	// ErrInvalidAmount is returned when a decimal string is not a valid number.
+	ErrInvalidAmount = errors.New("invalid decimal amount")
+	// ErrTooPrecise is returned when a decimal string has more fractional
+	// digits than the currency supports.
+	ErrTooPrecise = errors.New("amount has more decimal places than the currency allows")
+	// ErrOutOfRange is returned when an amount does not fit in int64 minor units.
+	ErrOutOfRange = errors.New("amount is out of range")
+)
+
+// MinorUnits returns the number of decimal places used by the given currency
+// code. Unknown currencies default to DefaultMinorUnits.
+func MinorUnits(currency string) int {
+	if n, ok := minorUnits[strings.ToUpper(currency)]; ok {
+		return n
+	}
+	return DefaultMinorUnits
+}
+
+// ParseDecimal converts a decimal string such as "12.34" into integer minor
+// units of the given currency (1234 for USD, an error for JPY). Fewer
+// fractional digits than the currency allows are padded; more are rejected
+// rather than rounded.
+func ParseDecimal(value, currency string) (int64, error) {
+	s := strings.TrimSpace(value)
+	negative := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	whole, frac, hasPoint := strings.Cut(s, ".")
+	if whole == "" && frac == "" || !isDigits(whole) || !isDigits(frac) || hasPoint && frac == "" {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+
+	places := MinorUnits(currency)
+	if len(frac) > places {
+		// Trailing zeros beyond the currency's precision carry no value.
+		if strings.TrimRight(frac[places:], "0") != "" {
+			return 0, fmt.Errorf("%w: %s supports %d", ErrTooPrecise, strings.ToUpper(currency), places)
+		}
+		frac = frac[:places]
+	}
+	frac += strings.Repeat("0", places-len(frac))
+
+	var units int64
+	for _, r := range whole + frac {
+		d := int64(r - '0')
+		if units > (math.MaxInt64-d)/10 {
+			return 0, ErrOutOfRange
+		}
+		units = units*10 + d
+	}
+	if negative {
+		units = -units
+	}
+	return units, nil
+}
+
+// FormatDecimal renders an amount in minor units as a plain decimal string
+// with the currency's precision (e.g. 12345 USD -> "123.45", 12345 JPY -> "12345").
+func FormatDecimal(units int64, currency string) string {
+	places := MinorUnits(currency)
+	sign := ""
+	// Work in uint64 so math.MinInt64 can be negated safely.
+	abs := uint64(units)
+	if units < 0 {
+		sign = "-"
+		abs = uint64(-(units + 1)) + 1
+	}
+	if places == 0 {
+		return fmt.Sprintf("%s%d", sign, abs)
+	}
+	scale := uint64(math.Pow10(places))
+	return fmt.Sprintf("%s%d.%0*d", sign, abs/scale, places, abs%scale)
+}
+
+// Convert converts an amount in minor units of from into minor units of to at
+// rate (units of to per unit of from), rounding to the nearest minor unit of to.
+func Convert(units int64, rate float64, from, to string) int64 {
+	scale := math.Pow10(MinorUnits(to) - MinorUnits(from))
+	return int64(math.Round(float64(units) * rate * scale))
+}
+
+// isDigits reports whether s contains only ASCII digits (true for "").
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
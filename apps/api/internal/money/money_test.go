@@ -0,0 +1,155 @@
+package money
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestMinorUnits(t *testing.T) {
+	tests := []struct {
+		currency string
+		want     int
+	}{
+		{currency: "JPY", want: 0},
+		{currency: "USD", want: 2},
+		{currency: "BHD", want: 3},
+		{currency: "BTC", want: 8},
+		{currency: "jpy", want: 0},
+		{currency: "XYZ", want: DefaultMinorUnits},
+		{currency: "", want: DefaultMinorUnits},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			if got := MinorUnits(tt.currency); got != tt.want {
+				t.Errorf("MinorUnits(%q) = %d, want %d", tt.currency, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		currency string
+		want     int64
+		wantErr  error
+	}{
+		{name: "jpy_whole", value: "1500", currency: "JPY", want: 1500},
+		{name: "jpy_trailing_zero_fraction", value: "1500.00", currency: "JPY", want: 1500},
+		{name: "jpy_fraction_rejected", value: "1500.5", currency: "JPY", wantErr: ErrTooPrecise},
+		{name: "usd_cents", value: "12.34", currency: "USD", want: 1234},
+		{name: "usd_padded", value: "12.3", currency: "USD", want: 1230},
+		{name: "usd_whole", value: "12", currency: "USD", want: 1200},
+		{name: "usd_leading_point", value: ".5", currency: "USD", want: 50},
+		{name: "usd_negative", value: "-0.01", currency: "USD", want: -1},
+		{name: "usd_plus_sign", value: "+3.10", currency: "USD", want: 310},
+		{name: "usd_whitespace", value: " 7.25 ", currency: "USD", want: 725},
+		{name: "usd_too_precise", value: "1.234", currency: "USD", wantErr: ErrTooPrecise},
+		{name: "bhd_fils", value: "1.234", currency: "BHD", want: 1234},
+		{name: "bhd_padded", value: "0.5", currency: "BHD", want: 500},
+		{name: "bhd_too_precise", value: "1.2345", currency: "BHD", wantErr: ErrTooPrecise},
+		{name: "btc_satoshi", value: "0.00000001", currency: "BTC", want: 1},
+		{name: "btc_whole", value: "2.5", currency: "BTC", want: 250000000},
+		{name: "btc_too_precise", value: "0.000000001", currency: "BTC", wantErr: ErrTooPrecise},
+		{name: "empty", value: "", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "sign_only", value: "-", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "point_only", value: ".", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "trailing_point", value: "5.", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "letters", value: "12a.00", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "exponent", value: "1e3", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "thousands_separator", value: "1,000", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "max_int64", value: "92233720368547758.07", currency: "USD", want: math.MaxInt64},
+		{name: "overflow", value: "92233720368547758.08", currency: "USD", wantErr: ErrOutOfRange},
+		{name: "btc_overflow", value: "100000000000", currency: "BTC", wantErr: ErrOutOfRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDecimal(tt.value, tt.currency)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseDecimal(%q, %q) error = %v, want %v", tt.value, tt.currency, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDecimal(%q, %q) unexpected error: %v", tt.value, tt.currency, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDecimal(%q, %q) = %d, want %d", tt.value, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDecimal(t *testing.T) {
+	tests := []struct {
+		name     string
+		units    int64
+		currency string
+		want     string
+	}{
+		{name: "jpy", units: 1500, currency: "JPY", want: "1500"},
+		{name: "jpy_negative", units: -42, currency: "JPY", want: "-42"},
+		{name: "usd", units: 12345, currency: "USD", want: "123.45"},
+		{name: "usd_sub_unit", units: 5, currency: "USD", want: "0.05"},
+		{name: "usd_negative", units: -12345, currency: "USD", want: "-123.45"},
+		{name: "usd_zero", units: 0, currency: "USD", want: "0.00"},
+		{name: "bhd", units: 1234, currency: "BHD", want: "1.234"},
+		{name: "bhd_sub_unit", units: 7, currency: "BHD", want: "0.007"},
+		{name: "btc", units: 250000001, currency: "BTC", want: "2.50000001"},
+		{name: "btc_satoshi", units: 1, currency: "BTC", want: "0.00000001"},
+		{name: "unknown_currency", units: 199, currency: "XYZ", want: "1.99"},
+		{name: "min_int64", units: math.MinInt64, currency: "USD", want: "-92233720368547758.08"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatDecimal(tt.units, tt.currency); got != tt.want {
+				t.Errorf("FormatDecimal(%d, %q) = %q, want %q", tt.units, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		units    int64
+		rate     float64
+		from, to string
+		want     int64
+	}{
+		{name: "same_precision", units: 10000, rate: 4.5, from: "USD", to: "MYR", want: 45000},
+		{name: "rounds_to_nearest", units: 333, rate: 0.5, from: "USD", to: "EUR", want: 167},
+		{name: "to_zero_decimal", units: 1050, rate: 150, from: "USD", to: "JPY", want: 1575},
+		{name: "from_zero_decimal", units: 1500, rate: 0.0067, from: "JPY", to: "USD", want: 1005},
+		{name: "to_three_decimal", units: 100, rate: 0.376, from: "USD", to: "BHD", want: 376},
+		{name: "negative", units: -2000, rate: 4.5, from: "USD", to: "MYR", want: -9000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Convert(tt.units, tt.rate, tt.from, tt.to); got != tt.want {
+				t.Errorf("Convert(%d, %v, %q, %q) = %d, want %d", tt.units, tt.rate, tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, currency := range []string{"JPY", "USD", "BHD", "BTC"} {
+		for _, units := range []int64{0, 1, 99, 1000, 123456789, -1, -987654321} {
+			parsed, err := ParseDecimal(FormatDecimal(units, currency), currency)
+			if err != nil {
+				t.Fatalf("%s %d: unexpected error: %v", currency, units, err)
+			}
+			if parsed != units {
+				t.Errorf("%s: round trip of %d returned %d", currency, units, parsed)
+			}
+		}
+	}
+}
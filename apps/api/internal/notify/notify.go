@@ -0,0 +1,34 @@
+// Package notify delivers user-facing notifications such as verification
+// emails. Delivery is pluggable through the Sender interface so that a real
+// mail provider can be swapped in without touching the services that send.
+package notify
+
+import "kuberan/internal/logger"
+
+// Message is a single notification addressed to one recipient.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers notifications.
+type Sender interface {
+	Send(msg Message) error
+}
+
+// LogSender is a Sender that writes messages to the application log instead
+// of delivering them. It is the default until a mail provider is configured.
+type LogSender struct{}
+
+// NewLogSender creates a new LogSender.
+func NewLogSender() *LogSender {
+	return &LogSender{}
+}
+
+// Send logs the message's recipient and subject. The body is left out because
+// it can carry secrets such as verification tokens.
+func (s *LogSender) Send(msg Message) error {
+	logger.Get().Infow("notification", "to", msg.To, "subject", msg.Subject)
+	return nil
+}
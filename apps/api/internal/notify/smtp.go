@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"fmt"
+	"net/smtp"
+	"strings"
+)
+
+// SMTPConfig holds the settings for delivering mail through an SMTP relay.
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// Enabled reports whether enough is configured to send mail.
+func (c SMTPConfig) Enabled() bool {
+	return c.Host != "" && c.From != ""
+}
+
+// SMTPSender is a Sender that delivers messages as plain-text email.
+type SMTPSender struct {
+	config SMTPConfig
+}
+
+// NewSMTPSender creates a new SMTPSender.
+func NewSMTPSender(config SMTPConfig) *SMTPSender {
+	return &SMTPSender{config: config}
+}
+
+// Send delivers the message over SMTP, authenticating when a username is configured.
+func (s *SMTPSender) Send(msg Message) error {
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.config.From)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(msg.Body)
+
+	addr := s.config.Host + ":" + s.config.Port
+	if err := smtp.SendMail(addr, auth, s.config.From, []string{msg.To}, []byte(b.String())); err != nil {
+		return fmt.Errorf("send mail to %s: %w", msg.To, err)
+	}
+	return nil
+}
@@ -1,44 +1,95 @@
 package pagination
 
 import (
+	"fmt"
 	"math"
 
 	"gorm.io/gorm"
 )
 
+// UnknownTotal is reported as TotalItems when counting was skipped.
+const UnknownTotal int64 = -1
+
+// Page sizes used when page_size is not given and the most served per page.
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
 // PageRequest holds pagination parameters parsed from query strings.
 type PageRequest struct {
-	Page     int `form:"page" binding:"omitempty,min=1"`
-	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Page      int   `form:"page" binding:"omitempty,min=1"`
+	PageSize  int   `form:"page_size" binding:"omitempty,min=1"`
+	WithTotal *bool `form:"with_total"` // nil means true
+
+	requestedPageSize int // page_size asked for, when Normalize clamped it
 }
 
-// Defaults fills in default values when page or page_size are not provided.
+// Defaults fills in default values when page or page_size are not provided
+// and caps page_size at MaxPageSize.
 func (p *PageRequest) Defaults() {
 	if p.Page == 0 {
 		p.Page = 1
 	}
 	if p.PageSize == 0 {
-		p.PageSize = 20
+		p.PageSize = DefaultPageSize
+	}
+	if p.PageSize > MaxPageSize {
+		p.PageSize = MaxPageSize
 	}
 }
 
+// Normalize applies Defaults, remembering a page_size above MaxPageSize so
+// Meta can warn that it was clamped. Handlers call it on every bound request.
+func (p *PageRequest) Normalize() {
+	if p.PageSize > MaxPageSize {
+		p.requestedPageSize = p.PageSize
+	}
+	p.Defaults()
+}
+
+// Meta returns the notes for a response to the request: a warning when
+// Normalize clamped page_size, otherwise nil.
+func (p *PageRequest) Meta() *PageMeta {
+	if p.requestedPageSize == 0 {
+		return nil
+	}
+	return &PageMeta{Warning: fmt.Sprintf("page_size %d exceeds the maximum of %d; %d items are returned per page",
+		p.requestedPageSize, MaxPageSize, MaxPageSize)}
+}
+
 // Offset returns the SQL OFFSET for the current page.
 func (p *PageRequest) Offset() int {
 	return (p.Page - 1) * p.PageSize
 }
 
+// IncludeTotal reports whether the total item count should be computed.
+func (p *PageRequest) IncludeTotal() bool {
+	return p.WithTotal == nil || *p.WithTotal
+}
+
 // PageResponse wraps a paginated list of items with metadata.
 type PageResponse[T any] struct {
-	Data       []T   `json:"data"`
-	Page       int   `json:"page"`
-	PageSize   int   `json:"page_size"`
-	TotalItems int64 `json:"total_items"`
-	TotalPages int   `json:"total_pages"`
+	Data       []T       `json:"data"`
+	Page       int       `json:"page"`
+	PageSize   int       `json:"page_size"`
+	TotalItems int64     `json:"total_items"`
+	TotalPages int       `json:"total_pages"`
+	Meta       *PageMeta `json:"meta,omitempty"`
+}
+
+// PageMeta carries notes on how a list request was served.
+type PageMeta struct {
+	Warning string `json:"warning,omitempty"`
 }
 
 // NewPageResponse creates a PageResponse from the given data and total count.
+// A totalItems of UnknownTotal leaves TotalPages at 0.
 func NewPageResponse[T any](data []T, page, pageSize int, totalItems int64) PageResponse[T] {
-	totalPages := int(math.Ceil(float64(totalItems) / float64(pageSize)))
+	totalPages := 0
+	if totalItems > 0 {
+		totalPages = int(math.Ceil(float64(totalItems) / float64(pageSize)))
+	}
 	if data == nil {
 		data = []T{}
 	}
@@ -57,3 +108,13 @@ func Paginate(req PageRequest) func(db *gorm.DB) *gorm.DB {
 		return db.Offset(req.Offset()).Limit(req.PageSize)
 	}
 }
+
+// Count stores the number of rows matched by db in total, or UnknownTotal
+// without querying when the request opted out with with_total=false.
+func Count(db *gorm.DB, req PageRequest, total *int64) error {
+	if !req.IncludeTotal() {
+		*total = UnknownTotal
+		return nil
+	}
+	return db.Count(total).Error
+}
@@ -0,0 +1,30 @@
+package password
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHash(t *testing.T) {
+	for _, tc := range []struct{ cost, want int }{
+		{bcrypt.MinCost, bcrypt.MinCost},
+		{11, 11},
+		{0, DefaultCost},
+	} {
+		hash, err := Hash("password123", tc.cost)
+		if err != nil {
+			t.Fatalf("cost %d: %v", tc.cost, err)
+		}
+		if got, _ := bcrypt.Cost([]byte(hash)); got != tc.want {
+			t.Errorf("cost %d: expected hash cost %d, got %d", tc.cost, tc.want, got)
+		}
+		if !Matches(hash, "password123") || Matches(hash, "password124") {
+			t.Errorf("cost %d: expected only the original password to match", tc.cost)
+		}
+	}
+
+	if _, err := Hash("password123", bcrypt.MinCost-1); err == nil {
+		t.Error("expected an error below bcrypt's minimum cost")
+	}
+}
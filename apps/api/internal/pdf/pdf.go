@@ -0,0 +1,308 @@
+// Package pdf writes simple text documents as PDF. It supports what printable
+// reports need — monospaced text in two weights and horizontal rules on A4
+// pages — and nothing more: no images and no layout beyond what callers place.
+// Text is set in Go Mono, embedded in the file so that Latin, Greek and
+// Cyrillic text prints the same in every reader; its glyphs all share one
+// advance width, so column widths can be computed from the character count.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf16"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// A4 page size in points.
+const (
+	PageWidth  = 595.0
+	PageHeight = 842.0
+)
+
+// CharWidth is the advance width of a Go Mono glyph, as a fraction of the font size.
+const CharWidth = 0.6
+
+// face is an embedded TrueType font.
+type face struct {
+	name string // PostScript name the PDF refers to it by
+	ttf  []byte
+	font *sfnt.Font
+	// Metrics in PDF glyph space, thousandths of the font size
+	width, ascent, descent, capHeight int
+	bbox                              [4]int
+
+	// The font file as embedded, compressed on first use
+	compressOnce sync.Once
+	compressed   []byte
+	compressErr  error
+}
+
+// fontFile returns the compressed font file.
+func (f *face) fontFile() ([]byte, error) {
+	f.compressOnce.Do(func() {
+		f.compressed, f.compressErr = deflate(f.ttf)
+	})
+	return f.compressed, f.compressErr
+}
+
+// faces holds the regular and bold fonts, indexed by fontIndex.
+var faces = [2]*face{
+	mustLoadFace("GoMono", gomono.TTF),
+	mustLoadFace("GoMono-Bold", gomonobold.TTF),
+}
+
+func mustLoadFace(name string, ttf []byte) *face {
+	f, err := sfnt.Parse(ttf)
+	if err != nil {
+		panic(fmt.Sprintf("pdf: parse %s: %v", name, err))
+	}
+	var buf sfnt.Buffer
+	unitsPerEm := int(f.UnitsPerEm())
+	ppem := fixed.I(unitsPerEm)
+	scale := func(v fixed.Int26_6) int { return v.Round() * 1000 / unitsPerEm }
+
+	glyph, err := f.GlyphIndex(&buf, 'M')
+	if err != nil {
+		panic(fmt.Sprintf("pdf: %s: %v", name, err))
+	}
+	advance, err := f.GlyphAdvance(&buf, glyph, ppem, font.HintingNone)
+	if err != nil {
+		panic(fmt.Sprintf("pdf: %s: %v", name, err))
+	}
+	metrics, err := f.Metrics(&buf, ppem, font.HintingNone)
+	if err != nil {
+		panic(fmt.Sprintf("pdf: %s: %v", name, err))
+	}
+	bounds, err := f.Bounds(&buf, ppem, font.HintingNone)
+	if err != nil {
+		panic(fmt.Sprintf("pdf: %s: %v", name, err))
+	}
+	// sfnt measures y downwards; PDF measures it upwards
+	return &face{
+		name: name, ttf: ttf, font: f,
+		width:     scale(advance),
+		ascent:    scale(metrics.Ascent),
+		descent:   -scale(metrics.Descent),
+		capHeight: scale(metrics.CapHeight),
+		bbox:      [4]int{scale(bounds.Min.X), -scale(bounds.Max.Y), scale(bounds.Max.X), -scale(bounds.Min.Y)},
+	}
+}
+
+// Document is a PDF under construction. Coordinates are in points from the
+// bottom-left corner of the page.
+type Document struct {
+	pages []*bytes.Buffer
+	// used maps each glyph drawn to the character it stands for, per face
+	used [len(faces)]map[sfnt.GlyphIndex]rune
+	buf  sfnt.Buffer
+}
+
+// New creates a document with one empty page.
+func New() *Document {
+	d := &Document{}
+	d.AddPage()
+	return d
+}
+
+// AddPage starts a new page; later drawing goes onto it.
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+// PageCount returns the number of pages.
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// Text draws s with its baseline starting at (x, y). Characters the font has
+// no glyph for, such as CJK ideographs, print as '?'.
+func (d *Document) Text(x, y, size float64, bold bool, s string) {
+	index := fontIndex(bold)
+	fmt.Fprintf(d.current(), "BT /F%d %s Tf %s %s Td <%s> Tj ET\n", index+1, num(size), num(x), num(y), d.glyphs(index, s))
+}
+
+// TextRight draws s so that it ends at x.
+func (d *Document) TextRight(x, y, size float64, bold bool, s string) {
+	d.Text(x-TextWidth(s, size), y, size, bold, s)
+}
+
+// Line draws a thin line from (x1, y1) to (x2, y2).
+func (d *Document) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(d.current(), "0.5 w %s %s m %s %s l S\n", num(x1), num(y1), num(x2), num(y2))
+}
+
+// TextWidth returns the width of s in points at the given font size.
+func TextWidth(s string, size float64) float64 {
+	return float64(len([]rune(s))) * CharWidth * size
+}
+
+// WriteTo writes the document as a PDF file. Each font that was drawn with is
+// embedded whole, compressed.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	// Objects 1 and 2 are the catalog and page tree; each font in use then takes
+	// five objects, and each page a page object and a content stream
+	var fontRefs []string
+	next := 3
+	fontObjects := make([]int, len(faces))
+	for i := range faces {
+		if len(d.used[i]) == 0 {
+			continue
+		}
+		fontObjects[i] = next
+		fontRefs = append(fontRefs, fmt.Sprintf("/F%d %d 0 R", i+1, next))
+		next += 5
+	}
+	firstPage := next
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	stream := func(dict string, data []byte) {
+		object(fmt.Sprintf("<< %s/Length %d >>\nstream\n%s\nendstream", dict, len(data), data))
+	}
+
+	// The comment of high bytes marks the file as binary for transfer tools
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	for i, f := range faces {
+		if fontObjects[i] == 0 {
+			continue
+		}
+		n := fontObjects[i]
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+			f.name, n+1, n+4))
+		object(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /DW %d /CIDToGIDMap /Identity >>",
+			f.name, n+2, f.width))
+		// Flags 33: fixed pitch, non-symbolic
+		object(fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 33 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+			f.name, f.bbox[0], f.bbox[1], f.bbox[2], f.bbox[3], f.ascent, f.descent, f.capHeight, n+3))
+		compressed, err := f.fontFile()
+		if err != nil {
+			return 0, err
+		}
+		stream(fmt.Sprintf("/Filter /FlateDecode /Length1 %d ", len(f.ttf)), compressed)
+		stream("", toUnicode(d.used[i]))
+	}
+	for i, content := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+			num(PageWidth), num(PageHeight), strings.Join(fontRefs, " "), firstPage+2*i+1))
+		stream("", content.Bytes())
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.WriteTo(w)
+}
+
+func (d *Document) current() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// fontIndex returns the index in faces of the regular or bold font.
+func fontIndex(bold bool) int {
+	if bold {
+		return 1
+	}
+	return 0
+}
+
+// glyphs encodes s as the hex glyph IDs of face index, two bytes each as the
+// Identity-H encoding reads them, and records the glyphs as used. Control
+// characters become spaces.
+func (d *Document) glyphs(index int, s string) string {
+	f := faces[index]
+	if d.used[index] == nil {
+		d.used[index] = make(map[sfnt.GlyphIndex]rune)
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if r < 0x20 {
+			r = ' '
+		}
+		g, err := f.font.GlyphIndex(&d.buf, r)
+		if err != nil || g == 0 {
+			r = '?'
+			g, _ = f.font.GlyphIndex(&d.buf, r)
+		}
+		d.used[index][g] = r
+		fmt.Fprintf(&b, "%04X", uint16(g))
+	}
+	return b.String()
+}
+
+// toUnicode builds the CMap that maps glyph IDs back to text, so the PDF can be
+// searched and copied from.
+func toUnicode(used map[sfnt.GlyphIndex]rune) []byte {
+	glyphs := make([]sfnt.GlyphIndex, 0, len(used))
+	for g := range used {
+		glyphs = append(glyphs, g)
+	}
+	sort.Slice(glyphs, func(i, j int) bool { return glyphs[i] < glyphs[j] })
+
+	var b bytes.Buffer
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	// A bfchar block holds at most 100 entries
+	for start := 0; start < len(glyphs); start += 100 {
+		end := min(start+100, len(glyphs))
+		fmt.Fprintf(&b, "%d beginbfchar\n", end-start)
+		for _, g := range glyphs[start:end] {
+			fmt.Fprintf(&b, "<%04X> <", uint16(g))
+			for _, unit := range utf16.Encode([]rune{used[g]}) {
+				fmt.Fprintf(&b, "%04X", unit)
+			}
+			b.WriteString(">\n")
+		}
+		b.WriteString("endbfchar\n")
+	}
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend")
+	return b.Bytes()
+}
+
+// deflate compresses data for a FlateDecode stream.
+func deflate(data []byte) ([]byte, error) {
+	var b bytes.Buffer
+	zw, err := zlib.NewWriterLevel(&b, zlib.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// num formats a coordinate compactly, without trailing zeros.
+func num(f float64) string {
+	s := fmt.Sprintf("%.2f", f)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
@@ -0,0 +1,142 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/sfnt"
+)
+
+// glyphHex returns the hex glyph IDs Text writes for s in the regular font.
+func glyphHex(t *testing.T, s string) string {
+	t.Helper()
+	f, err := sfnt.Parse(gomono.TTF)
+	if err != nil {
+		t.Fatalf("parse font: %v", err)
+	}
+	var b strings.Builder
+	for _, r := range s {
+		g, err := f.GlyphIndex(nil, r)
+		if err != nil || g == 0 {
+			t.Fatalf("no glyph for %q", r)
+		}
+		fmt.Fprintf(&b, "%04X", uint16(g))
+	}
+	return b.String()
+}
+
+func TestDocument_WriteTo(t *testing.T) {
+	d := New()
+	d.Text(40, 800, 12, true, "Statement (March)")
+	d.Line(40, 790, 555, 790)
+	d.AddPage()
+	d.TextRight(555, 800, 9, false, `Café \ 1,000.00`)
+
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	out := buf.String()
+
+	if !strings.HasPrefix(out, "%PDF-1.4\n") || !strings.HasSuffix(out, "%%EOF\n") {
+		t.Fatalf("missing PDF header or trailer")
+	}
+	if !strings.Contains(out, "/Count 2") {
+		t.Errorf("expected 2 pages")
+	}
+	if !strings.Contains(out, "/F1 9 Tf 474 800 Td <"+glyphHex(t, `Café \ 1,000.00`)+"> Tj") {
+		t.Errorf("expected the text as glyph IDs, right-aligned")
+	}
+	if !strings.Contains(out, "/BaseFont /GoMono ") || !strings.Contains(out, "/BaseFont /GoMono-Bold ") {
+		t.Errorf("expected both fonts embedded")
+	}
+
+	// Every xref entry must point at the start of its object: two fonts of
+	// five objects each besides the catalog, page tree and two pages
+	xref := out[strings.Index(out, "xref\n"):]
+	entries := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(xref, -1)
+	if len(entries) != 16 {
+		t.Fatalf("expected 16 objects, got %d", len(entries))
+	}
+	for i, e := range entries {
+		offset, _ := strconv.Atoi(e[1])
+		if want := fmt.Sprintf("%d 0 obj", i+1); !strings.HasPrefix(out[offset:], want) {
+			t.Errorf("xref entry %d does not point at %q", i+1, want)
+		}
+	}
+	start := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(out)
+	if offset, _ := strconv.Atoi(start[1]); !strings.HasPrefix(out[offset:], "xref") {
+		t.Errorf("startxref does not point at the xref table")
+	}
+}
+
+func TestDocument_UnicodeText(t *testing.T) {
+	d := New()
+	d.Text(40, 800, 10, false, "Łódź – Αθήνα – Москва")
+	d.Text(40, 780, 10, false, "東京")
+
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	out := buf.String()
+
+	t.Run("draws_glyphs_beyond_latin_1", func(t *testing.T) {
+		if !strings.Contains(out, "<"+glyphHex(t, "Łódź – Αθήνα – Москва")+"> Tj") {
+			t.Errorf("expected every character drawn with its own glyph")
+		}
+	})
+
+	t.Run("replaces_missing_glyphs_with_question_marks", func(t *testing.T) {
+		if !strings.Contains(out, "<"+glyphHex(t, "??")+"> Tj") {
+			t.Errorf("expected characters without a glyph drawn as '?'")
+		}
+	})
+
+	t.Run("maps_glyphs_back_to_text", func(t *testing.T) {
+		for _, r := range "ŁΑМ" {
+			want := fmt.Sprintf("<%s> <%04X>", glyphHex(t, string(r)), r)
+			if !strings.Contains(out, want) {
+				t.Errorf("expected ToUnicode entry %s", want)
+			}
+		}
+	})
+
+	t.Run("embeds_the_font_file", func(t *testing.T) {
+		if strings.Contains(out, "GoMono-Bold") {
+			t.Errorf("expected only the regular font embedded")
+		}
+		m := regexp.MustCompile(`/Filter /FlateDecode /Length1 (\d+) /Length (\d+) >>\nstream\n`).FindStringSubmatchIndex(out)
+		if m == nil {
+			t.Fatal("expected a compressed font file")
+		}
+		length, _ := strconv.Atoi(out[m[4]:m[5]])
+		zr, err := zlib.NewReader(strings.NewReader(out[m[1] : m[1]+length]))
+		if err != nil {
+			t.Fatalf("font file: %v", err)
+		}
+		ttf, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatalf("font file: %v", err)
+		}
+		if !bytes.Equal(ttf, gomono.TTF) {
+			t.Errorf("expected the embedded font to be Go Mono")
+		}
+	})
+}
+
+func TestTextWidth(t *testing.T) {
+	if got := TextWidth("12.50", 10); got != 30 {
+		t.Errorf("expected 30, got %v", got)
+	}
+	if got := TextWidth("Łódź", 10); got != 24 {
+		t.Errorf("expected 24 for four characters, got %v", got)
+	}
+}
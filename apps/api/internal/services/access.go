@@ -0,0 +1,70 @@
+package services
+
+import (
+	"gorm.io/gorm"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+)
+
+// accessLevel is what a user may do with an account, ordered from least to most.
+type accessLevel int
+
+const (
+	accessNone accessLevel = iota
+	accessViewer
+	accessEditor
+	accessOwner
+)
+
+// accessibleAccountIDs returns a subquery selecting the IDs of every account
+// userID can read: accounts they own plus accounts shared with them through
+// accepted shares. Use it as `Where("account_id IN (?)", accessibleAccountIDs(db, userID))`.
+func accessibleAccountIDs(db *gorm.DB, userID string) *gorm.DB {
+	db = db.Session(&gorm.Session{NewDB: true})
+
+	acceptedShares := db.Model(&models.AccountShare{}).
+		Where("shared_with_id = ? AND accepted_at IS NOT NULL", userID)
+
+	allAccountOwners := acceptedShares.Session(&gorm.Session{}).
+		Select("owner_id").
+		Where("all_accounts = ?", true)
+
+	listedAccounts := db.Model(&models.AccountShareAccount{}).
+		Select("account_id").
+		Where("account_share_id IN (?)", acceptedShares.Session(&gorm.Session{}).Select("id"))
+
+	return db.Model(&models.Account{}).
+		Select("id").
+		Where("user_id = ? OR user_id IN (?) OR id IN (?)", userID, allAccountOwners, listedAccounts)
+}
+
+// accountAccess resolves userID's access level to account. When several
+// accepted shares cover the account, the most permissive role wins.
+func accountAccess(db *gorm.DB, userID string, account *models.Account) (accessLevel, error) {
+	if account.UserID == userID {
+		return accessOwner, nil
+	}
+
+	var roles []models.ShareRole
+	listed := db.Model(&models.AccountShareAccount{}).
+		Select("account_share_id").
+		Where("account_id = ?", account.ID)
+	if err := db.Model(&models.AccountShare{}).
+		Where("owner_id = ? AND shared_with_id = ? AND accepted_at IS NOT NULL", account.UserID, userID).
+		Where("all_accounts = ? OR id IN (?)", true, listed).
+		Pluck("role", &roles).Error; err != nil {
+		return accessNone, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	level := accessNone
+	for _, role := range roles {
+		switch {
+		case role == models.ShareRoleEditor:
+			level = accessEditor
+		case role == models.ShareRoleViewer && level < accessViewer:
+			level = accessViewer
+		}
+	}
+	return level, nil
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+)
+
+// DefaultAccountGroupName names the group ungrouped accounts are listed under.
+const DefaultAccountGroupName = "Ungrouped"
+
+// accountGroupService handles account group business logic.
+type accountGroupService struct {
+	db *gorm.DB
+}
+
+// NewAccountGroupService creates a new AccountGroupServicer.
+func NewAccountGroupService(db *gorm.DB) AccountGroupServicer {
+	return &accountGroupService{db: db}
+}
+
+// CreateAccountGroup creates a group for the user, ordered after their existing groups.
+func (s *accountGroupService) CreateAccountGroup(userID, name string) (*models.AccountGroup, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "name is required")
+	}
+
+	var last struct{ MaxOrder *int }
+	if err := s.db.Model(&models.AccountGroup{}).
+		Select("MAX(sort_order) AS max_order").
+		Where("user_id = ?", userID).
+		Scan(&last).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	sortOrder := 0
+	if last.MaxOrder != nil {
+		sortOrder = *last.MaxOrder + 1
+	}
+
+	group := &models.AccountGroup{UserID: userID, Name: name, SortOrder: sortOrder}
+	if err := s.db.Create(group).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return group, nil
+}
+
+// GetAccountGroups returns the user's groups in display order.
+func (s *accountGroupService) GetAccountGroups(userID string) ([]models.AccountGroup, error) {
+	var groups []models.AccountGroup
+	if err := s.db.Where("user_id = ?", userID).
+		Order("sort_order ASC, created_at ASC").
+		Find(&groups).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return groups, nil
+}
+
+// GetAccountGroupByID returns a group by ID if it belongs to the user.
+func (s *accountGroupService) GetAccountGroupByID(userID, groupID string) (*models.AccountGroup, error) {
+	var group models.AccountGroup
+	if err := s.db.Where("id = ? AND user_id = ?", groupID, userID).First(&group).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrAccountGroupNotFound
+		}
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return &group, nil
+}
+
+// UpdateAccountGroup renames a group.
+func (s *accountGroupService) UpdateAccountGroup(userID, groupID, name string) (*models.AccountGroup, error) {
+	group, err := s.GetAccountGroupByID(userID, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "name is required")
+	}
+
+	if err := s.db.Model(group).Update("name", name).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return s.GetAccountGroupByID(userID, groupID)
+}
+
+// DeleteAccountGroup soft-deletes a group. Its accounts become ungrouped.
+func (s *accountGroupService) DeleteAccountGroup(userID, groupID string) error {
+	group, err := s.GetAccountGroupByID(userID, groupID)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Account{}).
+			Where("group_id = ?", group.ID).
+			Update("group_id", nil).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		if err := tx.Delete(group).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		return nil
+	})
+}
+
+// ReorderAccountGroups sets the display order of the user's groups. groupIDs
+// must list every one of the user's groups exactly once, in the new order.
+func (s *accountGroupService) ReorderAccountGroups(userID string, groupIDs []string) ([]models.AccountGroup, error) {
+	groups, err := s.GetAccountGroups(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		owned[g.ID] = true
+	}
+	if len(groupIDs) != len(groups) {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "group_ids must list each of your groups exactly once")
+	}
+	seen := make(map[string]bool, len(groupIDs))
+	for _, id := range groupIDs {
+		if !owned[id] {
+			return nil, apperrors.ErrAccountGroupNotFound
+		}
+		if seen[id] {
+			return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "group_ids must list each of your groups exactly once")
+		}
+		seen[id] = true
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		for i, id := range groupIDs {
+			if err := tx.Model(&models.AccountGroup{}).
+				Where("id = ? AND user_id = ?", id, userID).
+				Update("sort_order", i).Error; err != nil {
+				return apperrors.Wrap(apperrors.ErrInternalServer, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetAccountGroups(userID)
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"testing"
+
+	"kuberan/internal/models"
+	"kuberan/internal/testutil"
+)
+
+func TestCreateAccountGroup(t *testing.T) {
+	t.Parallel()
+	t.Run("appends_in_order", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountGroupService(db)
+		user := testutil.CreateTestUser(t, db)
+
+		first, err := svc.CreateAccountGroup(user.ID, "Retirement")
+		testutil.AssertNoError(t, err)
+		second, err := svc.CreateAccountGroup(user.ID, "Daily")
+		testutil.AssertNoError(t, err)
+
+		if first.SortOrder != 0 || second.SortOrder != 1 {
+			t.Errorf("expected sort orders 0 and 1, got %d and %d", first.SortOrder, second.SortOrder)
+		}
+	})
+
+	t.Run("rejects_blank_name", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountGroupService(db)
+		user := testutil.CreateTestUser(t, db)
+
+		_, err := svc.CreateAccountGroup(user.ID, "   ")
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+}
+
+func TestAccountGroupOwnership(t *testing.T) {
+	t.Parallel()
+	t.Run("other_users_group_not_found", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountGroupService(db)
+		owner := testutil.CreateTestUser(t, db)
+		other := testutil.CreateTestUser(t, db)
+
+		group, err := svc.CreateAccountGroup(owner.ID, "Retirement")
+		testutil.AssertNoError(t, err)
+
+		_, err = svc.GetAccountGroupByID(other.ID, group.ID)
+		testutil.AssertAppError(t, err, "ACCOUNT_GROUP_NOT_FOUND")
+		_, err = svc.UpdateAccountGroup(other.ID, group.ID, "Mine now")
+		testutil.AssertAppError(t, err, "ACCOUNT_GROUP_NOT_FOUND")
+		err = svc.DeleteAccountGroup(other.ID, group.ID)
+		testutil.AssertAppError(t, err, "ACCOUNT_GROUP_NOT_FOUND")
+
+		groups, err := svc.GetAccountGroups(other.ID)
+		testutil.AssertNoError(t, err)
+		if len(groups) != 0 {
+			t.Errorf("expected no groups for other user, got %d", len(groups))
+		}
+	})
+
+	t.Run("cannot_file_account_under_other_users_group", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		groupSvc := NewAccountGroupService(db)
+		acctSvc := NewAccountService(db, nil)
+		owner := testutil.CreateTestUser(t, db)
+		other := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, other.ID)
+
+		group, err := groupSvc.CreateAccountGroup(owner.ID, "Retirement")
+		testutil.AssertNoError(t, err)
+
+		groupID := &group.ID
+		_, err = acctSvc.UpdateAccount(models.UserID(other.ID), models.AccountID(account.ID), AccountUpdateFields{GroupID: &groupID})
+		testutil.AssertAppError(t, err, "ACCOUNT_GROUP_NOT_FOUND")
+	})
+}
+
+func TestReorderAccountGroups(t *testing.T) {
+	t.Parallel()
+	t.Run("sets_new_order", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountGroupService(db)
+		user := testutil.CreateTestUser(t, db)
+
+		a, _ := svc.CreateAccountGroup(user.ID, "A")
+		b, _ := svc.CreateAccountGroup(user.ID, "B")
+		c, _ := svc.CreateAccountGroup(user.ID, "C")
+
+		groups, err := svc.ReorderAccountGroups(user.ID, []string{c.ID, a.ID, b.ID})
+		testutil.AssertNoError(t, err)
+
+		if len(groups) != 3 || groups[0].ID != c.ID || groups[1].ID != a.ID || groups[2].ID != b.ID {
+			t.Errorf("expected order C, A, B, got %+v", groups)
+		}
+	})
+
+	t.Run("requires_every_group_once", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountGroupService(db)
+		user := testutil.CreateTestUser(t, db)
+
+		a, _ := svc.CreateAccountGroup(user.ID, "A")
+		_, _ = svc.CreateAccountGroup(user.ID, "B")
+
+		_, err := svc.ReorderAccountGroups(user.ID, []string{a.ID})
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+		_, err = svc.ReorderAccountGroups(user.ID, []string{a.ID, a.ID})
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+
+	t.Run("rejects_other_users_group", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountGroupService(db)
+		user := testutil.CreateTestUser(t, db)
+		other := testutil.CreateTestUser(t, db)
+
+		_, _ = svc.CreateAccountGroup(user.ID, "A")
+		foreign, _ := svc.CreateAccountGroup(other.ID, "B")
+
+		_, err := svc.ReorderAccountGroups(user.ID, []string{foreign.ID})
+		testutil.AssertAppError(t, err, "ACCOUNT_GROUP_NOT_FOUND")
+	})
+}
+
+func TestGetGroupedAccounts(t *testing.T) {
+	t.Parallel()
+	t.Run("nests_accounts_under_groups", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		groupSvc := NewAccountGroupService(db)
+		acctSvc := NewAccountService(db, nil)
+		user := testutil.CreateTestUser(t, db)
+
+		retirement, _ := groupSvc.CreateAccountGroup(user.ID, "Retirement")
+		daily, _ := groupSvc.CreateAccountGroup(user.ID, "Daily")
+		pension := testutil.CreateTestCashAccount(t, db, user.ID)
+		wallet := testutil.CreateTestCashAccount(t, db, user.ID)
+		loose := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		for account, group := range map[*models.Account]*models.AccountGroup{pension: retirement, wallet: daily} {
+			groupID := &group.ID
+			_, err := acctSvc.UpdateAccount(models.UserID(user.ID), models.AccountID(account.ID), AccountUpdateFields{GroupID: &groupID})
+			testutil.AssertNoError(t, err)
+		}
+
+		result, err := acctSvc.GetGroupedAccounts(models.UserID(user.ID), false)
+		testutil.AssertNoError(t, err)
+
+		if len(result) != 3 {
+			t.Fatalf("expected 2 groups plus the default, got %d", len(result))
+		}
+		if result[0].Group.ID != retirement.ID || len(result[0].Accounts) != 1 || result[0].Accounts[0].ID != pension.ID {
+			t.Errorf("expected pension under Retirement, got %+v", result[0])
+		}
+		if result[1].Group.ID != daily.ID || len(result[1].Accounts) != 1 || result[1].Accounts[0].ID != wallet.ID {
+			t.Errorf("expected wallet under Daily, got %+v", result[1])
+		}
+		if result[2].Group.Name != DefaultAccountGroupName || len(result[2].Accounts) != 1 || result[2].Accounts[0].ID != loose.ID {
+			t.Errorf("expected the ungrouped account in the default group, got %+v", result[2])
+		}
+	})
+
+	t.Run("deleted_group_falls_back_to_default", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		groupSvc := NewAccountGroupService(db)
+		acctSvc := NewAccountService(db, nil)
+		user := testutil.CreateTestUser(t, db)
+
+		group, _ := groupSvc.CreateAccountGroup(user.ID, "Retirement")
+		account := testutil.CreateTestCashAccount(t, db, user.ID)
+		groupID := &group.ID
+		_, err := acctSvc.UpdateAccount(models.UserID(user.ID), models.AccountID(account.ID), AccountUpdateFields{GroupID: &groupID})
+		testutil.AssertNoError(t, err)
+
+		testutil.AssertNoError(t, groupSvc.DeleteAccountGroup(user.ID, group.ID))
+
+		result, err := acctSvc.GetGroupedAccounts(models.UserID(user.ID), false)
+		testutil.AssertNoError(t, err)
+		if len(result) != 1 || len(result[0].Accounts) != 1 || result[0].Accounts[0].ID != account.ID {
+			t.Fatalf("expected the account in the default group, got %+v", result)
+		}
+		if result[0].Accounts[0].GroupID != nil {
+			t.Errorf("expected group_id to be cleared, got %s", *result[0].Accounts[0].GroupID)
+		}
+	})
+}
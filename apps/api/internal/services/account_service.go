@@ -2,9 +2,11 @@ package services
 
 import (
 	"errors"
+	"sort"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	apperrors "kuberan/internal/errors"
 	"kuberan/internal/models"
@@ -13,20 +15,31 @@ import (
 
 // accountService handles account-related business logic.
 type accountService struct {
-	db *gorm.DB
+	db     *gorm.DB
+	prices PriceCache
 }
 
-// NewAccountService creates a new AccountServicer.
-func NewAccountService(db *gorm.DB) AccountServicer {
-	return &accountService{db: db}
+// NewAccountService creates a new AccountServicer. prices may be nil to always
+// read latest prices from the database.
+func NewAccountService(db *gorm.DB, prices PriceCache) AccountServicer {
+	return &accountService{db: db, prices: prices}
 }
 
-// CreateCashAccount creates a new cash account for a user
-func (s *accountService) CreateCashAccount(userID string, name, description, currency string, initialBalance int64) (*models.Account, error) {
+// CreateCashAccount creates a new cash account for a user. With allowNegative
+// false, expenses and outgoing transfers may not take the balance below
+// minBalance. A non-nil openingDate backdates the account, and its initial
+// balance transaction, to that date; otherwise the initial balance is dated now.
+func (s *accountService) CreateCashAccount(userID models.UserID, name, description, currency string, initialBalance models.Cents, allowNegative bool, minBalance models.Cents, openingDate *time.Time) (*models.Account, error) {
 	// Validate input
 	if name == "" {
 		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "account name is required")
 	}
+	if minBalance < 0 {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "min_balance must not be negative")
+	}
+	if err := validateOpeningDate(openingDate); err != nil {
+		return nil, err
+	}
 
 	if currency == "" {
 		currency = "USD" // Default currency
@@ -34,28 +47,42 @@ func (s *accountService) CreateCashAccount(userID string, name, description, cur
 
 	// Create account
 	account := &models.Account{
-		UserID:      userID,
-		Name:        name,
-		Type:        models.AccountTypeCash,
-		Description: description,
-		Balance:     initialBalance,
-		Currency:    currency,
-		IsActive:    true,
+		UserID:        string(userID),
+		Name:          name,
+		Type:          models.AccountTypeCash,
+		Description:   description,
+		Balance:       int64(initialBalance),
+		Currency:      currency,
+		IsActive:      true,
+		AllowNegative: allowNegative,
+		MinBalance:    int64(minBalance),
+		OpeningDate:   openingDate,
 	}
 
 	err := s.db.Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(account).Error; err != nil {
 			return apperrors.Wrap(apperrors.ErrInternalServer, err)
 		}
+		// Create leaves a false AllowNegative to the column default
+		if !allowNegative {
+			if err := tx.Model(account).Update("allow_negative", false).Error; err != nil {
+				return apperrors.Wrap(apperrors.ErrInternalServer, err)
+			}
+		}
 
 		if initialBalance > 0 {
+			date := time.Now()
+			if openingDate != nil {
+				date = *openingDate
+			}
 			transaction := &models.Transaction{
-				UserID:      userID,
+				UserID:      string(userID),
 				AccountID:   account.ID,
 				Type:        models.TransactionTypeIncome,
-				Amount:      initialBalance,
+				Amount:      int64(initialBalance),
 				Description: "Initial balance",
-				Date:        time.Now(),
+				Date:        date,
+				Status:      models.TransactionStatusCleared,
 			}
 			if err := tx.Create(transaction).Error; err != nil {
 				return apperrors.Wrap(apperrors.ErrInternalServer, err)
@@ -72,7 +99,7 @@ func (s *accountService) CreateCashAccount(userID string, name, description, cur
 }
 
 // CreateInvestmentAccount creates a new investment account for a user.
-func (s *accountService) CreateInvestmentAccount(userID string, name, description, currency, broker, accountNumber string) (*models.Account, error) {
+func (s *accountService) CreateInvestmentAccount(userID models.UserID, name, description, currency, broker, accountNumber string) (*models.Account, error) {
 	if name == "" {
 		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "account name is required")
 	}
@@ -82,7 +109,7 @@ func (s *accountService) CreateInvestmentAccount(userID string, name, descriptio
 	}
 
 	account := &models.Account{
-		UserID:        userID,
+		UserID:        string(userID),
 		Name:          name,
 		Type:          models.AccountTypeInvestment,
 		Description:   description,
@@ -99,26 +126,31 @@ func (s *accountService) CreateInvestmentAccount(userID string, name, descriptio
 	return account, nil
 }
 
-// CreateCreditCardAccount creates a new credit card account for a user.
-func (s *accountService) CreateCreditCardAccount(userID string, name, description, currency string, creditLimit int64, interestRate float64, dueDate *time.Time) (*models.Account, error) {
+// CreateCreditCardAccount creates a new credit card account for a user. A non-nil
+// openingDate backdates the account to that date.
+func (s *accountService) CreateCreditCardAccount(userID models.UserID, name, description, currency string, creditLimit models.Cents, interestRate float64, dueDate, openingDate *time.Time) (*models.Account, error) {
 	if name == "" {
 		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "account name is required")
 	}
+	if err := validateOpeningDate(openingDate); err != nil {
+		return nil, err
+	}
 
 	if currency == "" {
 		currency = "USD"
 	}
 
 	account := &models.Account{
-		UserID:       userID,
+		UserID:       string(userID),
 		Name:         name,
 		Type:         models.AccountTypeCreditCard,
 		Description:  description,
 		Balance:      0,
 		Currency:     currency,
 		IsActive:     true,
-		CreditLimit:  creditLimit,
+		CreditLimit:  int64(creditLimit),
 		InterestRate: interestRate,
+		OpeningDate:  openingDate,
 	}
 
 	if dueDate != nil {
@@ -132,13 +164,134 @@ func (s *accountService) CreateCreditCardAccount(userID string, name, descriptio
 	return account, nil
 }
 
-// GetUserAccounts retrieves a paginated list of accounts for a user.
-func (s *accountService) GetUserAccounts(userID string, page pagination.PageRequest) (*pagination.PageResponse[models.Account], error) {
+// validateOpeningDate rejects opening dates in the future; nil means the account
+// opens now.
+func validateOpeningDate(openingDate *time.Time) error {
+	if openingDate != nil && openingDate.After(time.Now()) {
+		return apperrors.WithMessage(apperrors.ErrInvalidInput, "opening_date cannot be in the future")
+	}
+	return nil
+}
+
+// accountOpenings returns when each account was opened, by ID: its opening date
+// or, without one, the earlier of the day it was created (UTC) and its first
+// transaction, so history imported into a newer account still counts.
+func accountOpenings(db *gorm.DB, accounts []models.Account) (map[string]time.Time, error) {
+	openings := make(map[string]time.Time, len(accounts))
+	var undated []string
+	for i := range accounts {
+		if accounts[i].OpeningDate != nil {
+			openings[accounts[i].ID] = *accounts[i].OpeningDate
+			continue
+		}
+		created := accounts[i].CreatedAt.UTC()
+		openings[accounts[i].ID] = time.Date(created.Year(), created.Month(), created.Day(), 0, 0, 0, 0, time.UTC)
+		undated = append(undated, accounts[i].ID)
+	}
+	if len(undated) == 0 {
+		return openings, nil
+	}
+
+	type firstTransaction struct {
+		AccountID string
+		FirstDate aggregateTime
+	}
+	recorded := db.Model(&models.Transaction{}).
+		Select("account_id, date").
+		Where("account_id IN ?", undated)
+	received := db.Model(&models.Transaction{}).
+		Select("to_account_id AS account_id, date").
+		Where("to_account_id IN ?", undated)
+	var firsts []firstTransaction
+	if err := db.Table("(?) AS activity", db.Raw("? UNION ALL ?", recorded, received)).
+		Select("account_id, MIN(date) AS first_date").
+		Group("account_id").
+		Scan(&firsts).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	for _, f := range firsts {
+		if f.FirstDate.Valid && f.FirstDate.Time.Before(openings[f.AccountID]) {
+			openings[f.AccountID] = f.FirstDate.Time
+		}
+	}
+	return openings, nil
+}
+
+// GetUserAccounts retrieves a paginated list of accounts a user can access:
+// their own plus any shared with them. Inactive accounts are left out unless
+// includeInactive is set.
+func (s *accountService) GetUserAccounts(userID models.UserID, page pagination.PageRequest, includeInactive bool) (*pagination.PageResponse[models.Account], error) {
+	base := s.db.Model(&models.Account{}).
+		Where("id IN (?)", accessibleAccountIDs(s.db, string(userID)))
+	if !includeInactive {
+		base = base.Where("is_active = ?", true)
+	}
+	return s.listAccounts(base, page)
+}
+
+// GetArchivedAccounts retrieves a paginated list of the inactive accounts a
+// user can access, most recently archived first.
+func (s *accountService) GetArchivedAccounts(userID models.UserID, page pagination.PageRequest) (*pagination.PageResponse[models.Account], error) {
+	base := s.db.Model(&models.Account{}).
+		Where("id IN (?) AND is_active = ?", accessibleAccountIDs(s.db, string(userID)), false).
+		Order("archived_at DESC")
+	return s.listAccounts(base, page)
+}
+
+// GetGroupedAccounts returns every account the user can access nested under
+// the user's groups in display order, followed by a default group holding
+// ungrouped accounts. Accounts shared by other users are always ungrouped,
+// since their groups belong to the owner.
+func (s *accountService) GetGroupedAccounts(userID models.UserID, includeInactive bool) ([]GroupedAccounts, error) {
+	var groups []models.AccountGroup
+	if err := s.db.Where("user_id = ?", userID).
+		Order("sort_order ASC, created_at ASC").
+		Find(&groups).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	query := s.db.Where("id IN (?)", accessibleAccountIDs(s.db, string(userID)))
+	if !includeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+	var accounts []models.Account
+	if err := query.Order("name ASC").Find(&accounts).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if err := s.enrichInvestmentBalances(accounts); err != nil {
+		return nil, err
+	}
+
+	result := make([]GroupedAccounts, len(groups)+1)
+	index := make(map[string]int, len(groups))
+	for i, g := range groups {
+		result[i] = GroupedAccounts{Group: g, Accounts: []models.Account{}}
+		index[g.ID] = i
+	}
+	ungrouped := len(groups)
+	result[ungrouped] = GroupedAccounts{
+		Group:    models.AccountGroup{UserID: string(userID), Name: DefaultAccountGroupName, SortOrder: len(groups)},
+		Accounts: []models.Account{},
+	}
+
+	for _, a := range accounts {
+		i := ungrouped
+		if a.GroupID != nil && a.UserID == string(userID) {
+			if gi, ok := index[*a.GroupID]; ok {
+				i = gi
+			}
+		}
+		result[i].Accounts = append(result[i].Accounts, a)
+	}
+	return result, nil
+}
+
+// listAccounts pages through the accounts matched by base.
+func (s *accountService) listAccounts(base *gorm.DB, page pagination.PageRequest) (*pagination.PageResponse[models.Account], error) {
 	page.Defaults()
 
 	var totalItems int64
-	base := s.db.Model(&models.Account{}).Where("user_id = ? AND is_active = ?", userID, true)
-	if err := base.Count(&totalItems).Error; err != nil {
+	if err := pagination.Count(base, page, &totalItems); err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
 
@@ -155,16 +308,40 @@ func (s *accountService) GetUserAccounts(userID string, page pagination.PageRequ
 	return &result, nil
 }
 
-// GetAccountByID retrieves an account by ID for a specific user
-func (s *accountService) GetAccountByID(userID, accountID string) (*models.Account, error) {
+// GetAccountByID retrieves an account the user owns or has been shared.
+func (s *accountService) GetAccountByID(userID models.UserID, accountID models.AccountID) (*models.Account, error) {
+	return s.getAccountWithAccess(string(userID), string(accountID), accessViewer)
+}
+
+// GetWritableAccount retrieves an account the user owns or can edit through a share.
+// Viewers receive ErrShareReadOnly.
+func (s *accountService) GetWritableAccount(userID models.UserID, accountID models.AccountID) (*models.Account, error) {
+	return s.getAccountWithAccess(string(userID), string(accountID), accessEditor)
+}
+
+// getAccountWithAccess loads an active account and checks that userID has at
+// least the required access. Accounts the user cannot see at all are reported
+// as not found; visible accounts without enough access as read-only.
+func (s *accountService) getAccountWithAccess(userID, accountID string, required accessLevel) (*models.Account, error) {
 	var account models.Account
-	if err := s.db.Where("id = ? AND user_id = ? AND is_active = ?", accountID, userID, true).First(&account).Error; err != nil {
+	if err := s.db.Where("id = ? AND is_active = ?", accountID, true).First(&account).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, apperrors.ErrAccountNotFound
 		}
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
 
+	level, err := accountAccess(s.db, userID, &account)
+	if err != nil {
+		return nil, err
+	}
+	if level == accessNone {
+		return nil, apperrors.ErrAccountNotFound
+	}
+	if level < required {
+		return nil, apperrors.ErrShareReadOnly
+	}
+
 	if account.Type == models.AccountTypeInvestment {
 		accounts := []models.Account{account}
 		if err := s.enrichInvestmentBalances(accounts); err != nil {
@@ -177,9 +354,9 @@ func (s *accountService) GetAccountByID(userID, accountID string) (*models.Accou
 }
 
 // UpdateAccount updates an existing account for any account type.
-// Only fields relevant to the account's type are applied.
-func (s *accountService) UpdateAccount(userID, accountID string, fields AccountUpdateFields) (*models.Account, error) {
-	account, err := s.GetAccountByID(userID, accountID)
+// Only fields relevant to the account's type are applied. Only the owner may update.
+func (s *accountService) UpdateAccount(userID models.UserID, accountID models.AccountID, fields AccountUpdateFields) (*models.Account, error) {
+	account, err := s.getAccountWithAccess(string(userID), string(accountID), accessOwner)
 	if err != nil {
 		return nil, err
 	}
@@ -195,6 +372,58 @@ func (s *accountService) UpdateAccount(userID, accountID string, fields AccountU
 	}
 	if fields.IsActive != nil {
 		updates["is_active"] = *fields.IsActive
+		// Only active accounts can be updated, so this deactivates it: record
+		// when, and the balance it is leaving totals with.
+		if !*fields.IsActive {
+			updates["archived_at"] = time.Now()
+			updates["archived_balance"] = account.Balance
+		}
+	}
+	if fields.ExcludeFromReports != nil {
+		updates["exclude_from_reports"] = *fields.ExcludeFromReports
+	}
+	if fields.DefaultCategoryID != nil {
+		categoryID := *fields.DefaultCategoryID
+		if categoryID != nil {
+			var count int64
+			if err := s.db.Model(&models.Category{}).
+				Where("id = ? AND user_id = ?", *categoryID, userID).
+				Count(&count).Error; err != nil {
+				return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+			}
+			if count == 0 {
+				return nil, apperrors.ErrCategoryNotFound
+			}
+		}
+		updates["default_category_id"] = categoryID
+	}
+	if fields.GroupID != nil {
+		groupID := *fields.GroupID
+		if groupID != nil {
+			var count int64
+			if err := s.db.Model(&models.AccountGroup{}).
+				Where("id = ? AND user_id = ?", *groupID, userID).
+				Count(&count).Error; err != nil {
+				return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+			}
+			if count == 0 {
+				return nil, apperrors.ErrAccountGroupNotFound
+			}
+		}
+		updates["group_id"] = groupID
+	}
+
+	// Cash-only fields
+	if account.Type == models.AccountTypeCash {
+		if fields.AllowNegative != nil {
+			updates["allow_negative"] = *fields.AllowNegative
+		}
+		if fields.MinBalance != nil {
+			if *fields.MinBalance < 0 {
+				return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "min_balance must not be negative")
+			}
+			updates["min_balance"] = *fields.MinBalance
+		}
 	}
 
 	// Investment-only fields
@@ -233,23 +462,144 @@ func (s *accountService) UpdateAccount(userID, accountID string, fields AccountU
 	return account, nil
 }
 
+// DeleteAccount soft-deletes an account the user owns, active or archived.
+// An account with transactions (including transfers into it) or investments is
+// refused with ErrAccountHasTransactions unless force is set. With force, the
+// account, its transactions, its investments and their investment transactions
+// are soft-deleted in one DB transaction. Settled transfers to or from other
+// accounts are reversed on those accounts, so their balances read as if the
+// transfers never happened; the deleted account's own balance goes with it.
+// A split transfer the account is part of is reversed and deleted whole.
+func (s *accountService) DeleteAccount(userID models.UserID, accountID models.AccountID, force bool) error {
+	var account models.Account
+	if err := s.db.Where("id = ? AND user_id = ?", string(accountID), string(userID)).First(&account).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrAccountNotFound
+		}
+		return apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	if !force {
+		var transactions, investments int64
+		if err := s.db.Model(&models.Transaction{}).
+			Where("account_id = ? OR to_account_id = ?", account.ID, account.ID).
+			Count(&transactions).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		if err := s.db.Model(&models.Investment{}).Where("account_id = ?", account.ID).Count(&investments).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		if transactions > 0 || investments > 0 {
+			return apperrors.ErrAccountHasTransactions
+		}
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var transfers []models.Transaction
+		if err := tx.Where("(account_id = ? OR to_account_id = ?) AND type = ? AND is_pending = ?",
+			account.ID, account.ID, models.TransactionTypeTransfer, false).
+			Find(&transfers).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		for i := range transfers {
+			if err := s.reverseTransferCounterparty(tx, account.ID, &transfers[i]); err != nil {
+				return err
+			}
+		}
+
+		// Split transfers go whole: the legs between other accounts in a group
+		// this account is part of are reversed on both ends and deleted too
+		groupIDs := tx.Model(&models.Transaction{}).Select("transfer_group_id").
+			Where("(account_id = ? OR to_account_id = ?) AND transfer_group_id IS NOT NULL", account.ID, account.ID)
+		var siblings []models.Transaction
+		if err := tx.Where("transfer_group_id IN (?) AND account_id <> ? AND to_account_id <> ?", groupIDs, account.ID, account.ID).
+			Find(&siblings).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		siblingIDs := make([]string, 0, len(siblings))
+		for i := range siblings {
+			siblingIDs = append(siblingIDs, siblings[i].ID)
+			if siblings[i].IsPending || siblings[i].ToAccountID == nil {
+				continue
+			}
+			if err := s.reverseTransferCounterparty(tx, siblings[i].AccountID, &siblings[i]); err != nil {
+				return err
+			}
+			if err := s.reverseTransferCounterparty(tx, *siblings[i].ToAccountID, &siblings[i]); err != nil {
+				return err
+			}
+		}
+		if len(siblingIDs) > 0 {
+			if err := tx.Where("id IN ?", siblingIDs).Delete(&models.Transaction{}).Error; err != nil {
+				return apperrors.Wrap(apperrors.ErrInternalServer, err)
+			}
+		}
+
+		if err := tx.Where("account_id = ? OR to_account_id = ?", account.ID, account.ID).
+			Delete(&models.Transaction{}).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		investmentIDs := tx.Model(&models.Investment{}).Select("id").Where("account_id = ?", account.ID)
+		if err := tx.Where("investment_id IN (?)", investmentIDs).Delete(&models.InvestmentTransaction{}).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		if err := tx.Where("account_id = ?", account.ID).Delete(&models.Investment{}).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		if err := tx.Where("account_id = ?", account.ID).Delete(&models.AccountShareAccount{}).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		if err := tx.Delete(&account).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		return nil
+	})
+}
+
+// reverseTransferCounterparty undoes a settled transfer's effect on the account
+// at its other end from accountID. Counterparties already deleted are skipped.
+func (s *accountService) reverseTransferCounterparty(tx *gorm.DB, accountID string, transfer *models.Transaction) error {
+	if transfer.ToAccountID == nil {
+		return nil
+	}
+	counterpartyID, reversal := *transfer.ToAccountID, models.TransactionTypeExpense
+	if counterpartyID == accountID {
+		counterpartyID, reversal = transfer.AccountID, models.TransactionTypeIncome
+	}
+
+	var counterparty models.Account
+	if err := tx.Where("id = ?", counterpartyID).First(&counterparty).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return s.UpdateAccountBalance(tx, &counterparty, reversal, models.Cents(transfer.Amount))
+}
+
 // UpdateAccountBalance updates the balance of an account based on transaction
-func (s *accountService) UpdateAccountBalance(tx *gorm.DB, account *models.Account, transactionType models.TransactionType, amount int64) error {
+// type. The balance is re-read under a row lock inside tx, so concurrent updates
+// to the same account serialize instead of overwriting each other.
+func (s *accountService) UpdateAccountBalance(tx *gorm.DB, account *models.Account, transactionType models.TransactionType, amount models.Cents) error {
+	if err := lockAccounts(tx, account); err != nil {
+		return err
+	}
+
 	// Update the balance based on transaction type and account type
 	// Credit cards: positive balance = amount owed (expense increases, income/payment decreases)
 	// All others: income adds, expense subtracts
 	switch transactionType {
 	case models.TransactionTypeIncome:
 		if account.Type == models.AccountTypeCreditCard {
-			account.Balance -= amount
+			account.Balance -= int64(amount)
 		} else {
-			account.Balance += amount
+			account.Balance += int64(amount)
 		}
 	case models.TransactionTypeExpense:
 		if account.Type == models.AccountTypeCreditCard {
-			account.Balance += amount
+			account.Balance += int64(amount)
 		} else {
-			account.Balance -= amount
+			account.Balance -= int64(amount)
 		}
 	}
 
@@ -260,6 +610,220 @@ func (s *accountService) UpdateAccountBalance(tx *gorm.DB, account *models.Accou
 	return nil
 }
 
+// GetStatement assembles a bank-style statement of an account for the calendar
+// month (UTC) containing month: the opening balance, every settled transaction
+// in the month with the running balance after it, and the closing balance.
+// Balances are reconstructed from the current balance with the same rules as
+// the balance history, so the last running balance equals the closing balance.
+// Months before a backdated account's opening date have no statement.
+func (s *accountService) GetStatement(userID models.UserID, accountID models.AccountID, month time.Time) (*AccountStatement, error) {
+	account, err := s.GetAccountByID(userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.Type == models.AccountTypeInvestment {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "statements are not available for investment accounts")
+	}
+
+	start := monthStart(month)
+	end := start.AddDate(0, 1, 0).Add(-time.Nanosecond)
+	opening := start.Add(-time.Nanosecond)
+	openings, err := accountOpenings(s.db, []models.Account{*account})
+	if err != nil {
+		return nil, err
+	}
+	if opened := openings[account.ID]; opened.After(end) {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput,
+			"the account was opened on "+opened.Format("2006-01-02")+" and has no statement before then")
+	}
+
+	// Everything settled after the opening instant is needed to walk the
+	// balance back from today; only the month's own rows become lines.
+	unscoped := func(db *gorm.DB) *gorm.DB { return db.Unscoped() }
+	var transactions []models.Transaction
+	if err := s.db.Where("(account_id = ? OR to_account_id = ?) AND date > ? AND is_pending = ?",
+		account.ID, account.ID, opening, false).
+		Preload("Account", unscoped).
+		Preload("ToAccount", unscoped).
+		Preload("Category", unscoped).
+		Order("date ASC, created_at ASC, id ASC").
+		Find(&transactions).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	statement := &AccountStatement{
+		AccountID:      account.ID,
+		AccountName:    account.Name,
+		Type:           account.Type,
+		Currency:       account.Currency,
+		Month:          start.Format("2006-01"),
+		PeriodStart:    start,
+		PeriodEnd:      end,
+		OpeningBalance: balanceAt(account, transactions, opening),
+		ClosingBalance: balanceAt(account, transactions, end),
+		Lines:          []AccountStatementLine{},
+	}
+
+	balance := statement.OpeningBalance
+	for i := range transactions {
+		t := &transactions[i]
+		if t.Date.After(end) {
+			break
+		}
+		delta := balanceDelta(account, t)
+		balance += delta
+		if delta > 0 {
+			statement.TotalIn += delta
+		} else {
+			statement.TotalOut -= delta
+		}
+
+		line := AccountStatementLine{
+			TransactionID: t.ID,
+			Date:          t.Date,
+			Type:          t.Type,
+			Description:   t.Description,
+			Status:        t.Status,
+			Amount:        delta,
+			Balance:       balance,
+		}
+		if t.Category != nil {
+			line.Category = t.Category.Name
+		}
+		if t.Type == models.TransactionTypeTransfer || t.FundsInvestment() {
+			if t.AccountID == account.ID && t.ToAccount != nil {
+				line.Counterparty = t.ToAccount.Name
+			} else if t.AccountID != account.ID {
+				line.Counterparty = t.Account.Name
+			}
+		}
+		statement.Lines = append(statement.Lines, line)
+	}
+
+	return statement, nil
+}
+
+// GetAccountTimeline returns the account's activity newest first: its cash
+// transactions, including transfers into it, and for investment accounts the
+// transactions of its holdings, merged into one paginated feed. Entries on the
+// same date keep the order they were created in.
+func (s *accountService) GetAccountTimeline(userID models.UserID, accountID models.AccountID, page pagination.PageRequest) (*pagination.PageResponse[AccountTimelineEntry], error) {
+	account, err := s.GetAccountByID(userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	page.Defaults()
+
+	entries := s.db.Table("transactions").
+		Select("'transaction' AS kind, id, date, created_at").
+		Where("(account_id = ? OR to_account_id = ?) AND deleted_at IS NULL", account.ID, account.ID)
+	if account.Type == models.AccountTypeInvestment {
+		investmentEntries := s.db.Table("investment_transactions").
+			Select("'investment_transaction' AS kind, investment_transactions.id, investment_transactions.date, investment_transactions.created_at").
+			Joins("JOIN investments ON investments.id = investment_transactions.investment_id").
+			Where("investments.account_id = ? AND investments.deleted_at IS NULL AND investment_transactions.deleted_at IS NULL", account.ID)
+		entries = s.db.Raw("? UNION ALL ?", entries, investmentEntries)
+	}
+	timeline := s.db.Table("(?) AS timeline", entries)
+
+	var totalItems int64
+	if err := pagination.Count(timeline, page, &totalItems); err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	var refs []struct {
+		Kind AccountTimelineEntryKind
+		ID   string
+	}
+	if err := timeline.Select("kind, id").
+		Order("date DESC, created_at DESC, id DESC").
+		Scopes(pagination.Paginate(page)).
+		Scan(&refs).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	var transactionIDs, investmentTransactionIDs []string
+	for _, ref := range refs {
+		if ref.Kind == TimelineEntryInvestmentTransaction {
+			investmentTransactionIDs = append(investmentTransactionIDs, ref.ID)
+		} else {
+			transactionIDs = append(transactionIDs, ref.ID)
+		}
+	}
+
+	transactions := make(map[string]*models.Transaction, len(transactionIDs))
+	if len(transactionIDs) > 0 {
+		var rows []models.Transaction
+		if err := s.db.Where("id IN ?", transactionIDs).Find(&rows).Error; err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		if err := flagLargeTransactions(s.db, string(userID), rows); err != nil {
+			return nil, err
+		}
+		setTransactionDirections(rows, []string{account.ID})
+		for i := range rows {
+			transactions[rows[i].ID] = &rows[i]
+		}
+	}
+
+	investmentTransactions := make(map[string]*models.InvestmentTransaction, len(investmentTransactionIDs))
+	if len(investmentTransactionIDs) > 0 {
+		var rows []models.InvestmentTransaction
+		if err := s.db.Where("id IN ?", investmentTransactionIDs).
+			Preload("Investment.Security").
+			Find(&rows).Error; err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		for i := range rows {
+			investmentTransactions[rows[i].ID] = &rows[i]
+		}
+	}
+
+	data := make([]AccountTimelineEntry, 0, len(refs))
+	for _, ref := range refs {
+		entry := AccountTimelineEntry{Kind: ref.Kind}
+		if ref.Kind == TimelineEntryInvestmentTransaction {
+			entry.InvestmentTransaction = investmentTransactions[ref.ID]
+			entry.Date = entry.InvestmentTransaction.Date
+		} else {
+			entry.Transaction = transactions[ref.ID]
+			entry.Date = entry.Transaction.Date
+		}
+		data = append(data, entry)
+	}
+
+	result := pagination.NewPageResponse(data, page.Page, page.PageSize, totalItems)
+	return &result, nil
+}
+
+// forUpdate adds a SELECT ... FOR UPDATE row lock to a query. SQLite has no row
+// locks (writers are serialized per database), so the clause is skipped there.
+func forUpdate(tx *gorm.DB) *gorm.DB {
+	if tx.Dialector.Name() == "sqlite" {
+		return tx
+	}
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"})
+}
+
+// lockAccounts locks the given accounts' rows inside tx and refreshes their
+// in-memory balances from the locked rows. Rows are locked in ID order so that
+// flows touching two accounts cannot deadlock against each other.
+func lockAccounts(tx *gorm.DB, accounts ...*models.Account) error {
+	ordered := make([]*models.Account, len(accounts))
+	copy(ordered, accounts)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].ID < ordered[j].ID })
+
+	for _, account := range ordered {
+		var locked models.Account
+		if err := forUpdate(tx).Unscoped().Select("id", "balance").
+			Where("id = ?", account.ID).First(&locked).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		account.Balance = locked.Balance
+	}
+	return nil
+}
+
 // enrichInvestmentBalances computes market-value balances for investment accounts
 // in the given slice. Non-investment accounts are left unchanged.
 func (s *accountService) enrichInvestmentBalances(accounts []models.Account) error {
@@ -303,7 +867,7 @@ func (s *accountService) enrichInvestmentBalances(accounts []models.Account) err
 	}
 
 	// Batch-fetch latest prices
-	prices, err := getLatestPrices(s.db, secIDs)
+	prices, err := getLatestPrices(s.db, s.prices, secIDs)
 	if err != nil {
 		return err
 	}
@@ -7,20 +7,21 @@ import (
 	"kuberan/internal/models"
 	"kuberan/internal/pagination"
 	"kuberan/internal/testutil"
+	"kuberan/internal/uuid"
 )
 
 func TestCreateCashAccount(t *testing.T) {
+	t.Parallel()
 	t.Run("valid", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 
-		account, err := svc.CreateCashAccount(user.ID, "Savings", "My savings", "USD", 0)
+		account, err := svc.CreateCashAccount(models.UserID(user.ID), "Savings", "My savings", "USD", 0, true, 0, nil)
 		testutil.AssertNoError(t, err)
 
-		if account.ID == 0 {
-			t.Fatal("expected non-zero account ID")
+		if account.ID == "" {
+			t.Fatal("expected non-empty account ID")
 		}
 		if account.Name != "Savings" {
 			t.Errorf("expected name Savings, got %s", account.Name)
@@ -37,12 +38,12 @@ func TestCreateCashAccount(t *testing.T) {
 	})
 
 	t.Run("with_initial_balance", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 
-		account, err := svc.CreateCashAccount(user.ID, "Checking", "", "USD", 5000)
+		before := time.Now()
+		account, err := svc.CreateCashAccount(models.UserID(user.ID), "Checking", "", "USD", 5000, true, 0, nil)
 		testutil.AssertNoError(t, err)
 
 		if account.Balance != 5000 {
@@ -64,25 +65,78 @@ func TestCreateCashAccount(t *testing.T) {
 		if tx.Amount != 5000 {
 			t.Errorf("expected initial transaction amount 5000, got %d", tx.Amount)
 		}
+		if tx.Date.Before(before.Add(-time.Second)) || tx.Date.After(time.Now()) {
+			t.Errorf("expected the initial transaction dated now, got %v", tx.Date)
+		}
+		if account.OpeningDate != nil {
+			t.Errorf("expected no opening date, got %v", account.OpeningDate)
+		}
+	})
+
+	t.Run("backdates_the_initial_balance", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		user := testutil.CreateTestUser(t, db)
+
+		opened := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		account, err := svc.CreateCashAccount(models.UserID(user.ID), "Old Savings", "", "USD", 5000, true, 0, &opened)
+		testutil.AssertNoError(t, err)
+
+		var stored models.Account
+		testutil.AssertNoError(t, db.Where("id = ?", account.ID).First(&stored).Error)
+		if stored.OpeningDate == nil || !stored.OpeningDate.Equal(opened) {
+			t.Errorf("expected opening date %v, got %v", opened, stored.OpeningDate)
+		}
+		var tx models.Transaction
+		testutil.AssertNoError(t, db.Where("account_id = ?", account.ID).First(&tx).Error)
+		if !tx.Date.Equal(opened) {
+			t.Errorf("expected the initial transaction dated %v, got %v", opened, tx.Date)
+		}
+	})
+
+	t.Run("rejects_a_future_opening_date", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		user := testutil.CreateTestUser(t, db)
+
+		tomorrow := time.Now().AddDate(0, 0, 1)
+		_, err := svc.CreateCashAccount(models.UserID(user.ID), "Checking", "", "USD", 5000, true, 0, &tomorrow)
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
 	})
 
 	t.Run("empty_name", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 
-		_, err := svc.CreateCashAccount(user.ID, "", "", "USD", 0)
+		_, err := svc.CreateCashAccount(models.UserID(user.ID), "", "", "USD", 0, true, 0, nil)
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+
+	t.Run("overdraft_protection", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		user := testutil.CreateTestUser(t, db)
+
+		account, err := svc.CreateCashAccount(models.UserID(user.ID), "Checking", "", "USD", 10000, false, 2000, nil)
+		testutil.AssertNoError(t, err)
+
+		var stored models.Account
+		db.Where("id = ?", account.ID).First(&stored)
+		if stored.AllowNegative || stored.MinBalance != 2000 {
+			t.Errorf("expected allow_negative=false and min_balance=2000, got %v and %d", stored.AllowNegative, stored.MinBalance)
+		}
+
+		_, err = svc.CreateCashAccount(models.UserID(user.ID), "Checking", "", "USD", 0, false, -1, nil)
 		testutil.AssertAppError(t, err, "INVALID_INPUT")
 	})
 
 	t.Run("default_currency", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 
-		account, err := svc.CreateCashAccount(user.ID, "No Currency", "", "", 0)
+		account, err := svc.CreateCashAccount(models.UserID(user.ID), "No Currency", "", "", 0, true, 0, nil)
 		testutil.AssertNoError(t, err)
 
 		if account.Currency != "USD" {
@@ -92,10 +146,10 @@ func TestCreateCashAccount(t *testing.T) {
 }
 
 func TestGetUserAccounts(t *testing.T) {
+	t.Parallel()
 	t.Run("returns_user_accounts_only", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 
 		user1 := testutil.CreateTestUser(t, db)
 		user2 := testutil.CreateTestUser(t, db)
@@ -105,7 +159,7 @@ func TestGetUserAccounts(t *testing.T) {
 		testutil.CreateTestCashAccount(t, db, user2.ID)
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := svc.GetUserAccounts(user1.ID, page)
+		result, err := svc.GetUserAccounts(models.UserID(user1.ID), page, false)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 2 {
@@ -117,9 +171,8 @@ func TestGetUserAccounts(t *testing.T) {
 	})
 
 	t.Run("excludes_inactive", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 
 		active := testutil.CreateTestCashAccount(t, db, user.ID)
@@ -127,31 +180,49 @@ func TestGetUserAccounts(t *testing.T) {
 		db.Model(inactive).Update("is_active", false)
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := svc.GetUserAccounts(user.ID, page)
+		result, err := svc.GetUserAccounts(models.UserID(user.ID), page, false)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 1 {
 			t.Errorf("expected 1 active account, got %d", result.TotalItems)
 		}
 		if result.Data[0].ID != active.ID {
-			t.Errorf("expected active account ID %d, got %d", active.ID, result.Data[0].ID)
+			t.Errorf("expected active account ID %s, got %s", active.ID, result.Data[0].ID)
+		}
+	})
+
+	t.Run("includes_inactive_when_asked", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		user := testutil.CreateTestUser(t, db)
+
+		testutil.CreateTestCashAccount(t, db, user.ID)
+		inactive := testutil.CreateTestCashAccount(t, db, user.ID)
+		db.Model(inactive).Update("is_active", false)
+
+		page := pagination.PageRequest{Page: 1, PageSize: 20}
+		result, err := svc.GetUserAccounts(models.UserID(user.ID), page, true)
+		testutil.AssertNoError(t, err)
+
+		if result.TotalItems != 2 {
+			t.Errorf("expected 2 accounts, got %d", result.TotalItems)
 		}
 	})
 }
 
 func TestGetAccountByID(t *testing.T) {
+	t.Parallel()
 	t.Run("found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 		created := testutil.CreateTestCashAccount(t, db, user.ID)
 
-		account, err := svc.GetAccountByID(user.ID, created.ID)
+		account, err := svc.GetAccountByID(models.UserID(user.ID), models.AccountID(created.ID))
 		testutil.AssertNoError(t, err)
 
 		if account.ID != created.ID {
-			t.Errorf("expected account ID %d, got %d", created.ID, account.ID)
+			t.Errorf("expected account ID %s, got %s", created.ID, account.ID)
 		}
 		if account.Name != created.Name {
 			t.Errorf("expected name %s, got %s", created.Name, account.Name)
@@ -159,40 +230,38 @@ func TestGetAccountByID(t *testing.T) {
 	})
 
 	t.Run("not_found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 
-		_, err := svc.GetAccountByID(user.ID, 99999)
+		_, err := svc.GetAccountByID(models.UserID(user.ID), models.AccountID(uuid.New()))
 		testutil.AssertAppError(t, err, "ACCOUNT_NOT_FOUND")
 	})
 
 	t.Run("wrong_user", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 
 		user1 := testutil.CreateTestUser(t, db)
 		user2 := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user1.ID)
 
-		_, err := svc.GetAccountByID(user2.ID, account.ID)
+		_, err := svc.GetAccountByID(models.UserID(user2.ID), models.AccountID(account.ID))
 		testutil.AssertAppError(t, err, "ACCOUNT_NOT_FOUND")
 	})
 }
 
 func TestUpdateAccount(t *testing.T) {
+	t.Parallel()
 	t.Run("updates_cash_account_name_and_description", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 
 		name := "New Name"
 		desc := "New Description"
-		updated, err := svc.UpdateAccount(user.ID, account.ID, AccountUpdateFields{
+		updated, err := svc.UpdateAccount(models.UserID(user.ID), models.AccountID(account.ID), AccountUpdateFields{
 			Name:        &name,
 			Description: &desc,
 		})
@@ -206,15 +275,44 @@ func TestUpdateAccount(t *testing.T) {
 		}
 	})
 
+	t.Run("updates_cash_account_overdraft_protection", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		allowNegative := false
+		minBalance := int64(5000)
+		updated, err := svc.UpdateAccount(models.UserID(user.ID), models.AccountID(account.ID), AccountUpdateFields{
+			AllowNegative: &allowNegative,
+			MinBalance:    &minBalance,
+		})
+		testutil.AssertNoError(t, err)
+		if updated.AllowNegative || updated.MinBalance != 5000 {
+			t.Errorf("expected allow_negative=false and min_balance=5000, got %v and %d", updated.AllowNegative, updated.MinBalance)
+		}
+
+		negative := int64(-1)
+		_, err = svc.UpdateAccount(models.UserID(user.ID), models.AccountID(account.ID), AccountUpdateFields{MinBalance: &negative})
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+
+		// Not applicable to other account types
+		card := testutil.CreateTestCreditCardAccount(t, db, user.ID, 0)
+		updated, err = svc.UpdateAccount(models.UserID(user.ID), models.AccountID(card.ID), AccountUpdateFields{AllowNegative: &allowNegative})
+		testutil.AssertNoError(t, err)
+		if !updated.AllowNegative {
+			t.Error("expected a credit card to keep allow_negative=true")
+		}
+	})
+
 	t.Run("updates_investment_account_broker", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 
 		broker := "Schwab"
-		updated, err := svc.UpdateAccount(user.ID, account.ID, AccountUpdateFields{
+		updated, err := svc.UpdateAccount(models.UserID(user.ID), models.AccountID(account.ID), AccountUpdateFields{
 			Broker: &broker,
 		})
 		testutil.AssertNoError(t, err)
@@ -225,14 +323,13 @@ func TestUpdateAccount(t *testing.T) {
 	})
 
 	t.Run("updates_investment_account_number", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 
 		acctNum := "XYZ-789"
-		updated, err := svc.UpdateAccount(user.ID, account.ID, AccountUpdateFields{
+		updated, err := svc.UpdateAccount(models.UserID(user.ID), models.AccountID(account.ID), AccountUpdateFields{
 			AccountNumber: &acctNum,
 		})
 		testutil.AssertNoError(t, err)
@@ -243,14 +340,13 @@ func TestUpdateAccount(t *testing.T) {
 	})
 
 	t.Run("updates_credit_card_interest_rate", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCreditCardAccount(t, db, user.ID, 0)
 
 		rate := 22.5
-		updated, err := svc.UpdateAccount(user.ID, account.ID, AccountUpdateFields{
+		updated, err := svc.UpdateAccount(models.UserID(user.ID), models.AccountID(account.ID), AccountUpdateFields{
 			InterestRate: &rate,
 		})
 		testutil.AssertNoError(t, err)
@@ -261,14 +357,13 @@ func TestUpdateAccount(t *testing.T) {
 	})
 
 	t.Run("updates_credit_card_credit_limit", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCreditCardAccount(t, db, user.ID, 0)
 
 		limit := int64(1000000)
-		updated, err := svc.UpdateAccount(user.ID, account.ID, AccountUpdateFields{
+		updated, err := svc.UpdateAccount(models.UserID(user.ID), models.AccountID(account.ID), AccountUpdateFields{
 			CreditLimit: &limit,
 		})
 		testutil.AssertNoError(t, err)
@@ -279,14 +374,13 @@ func TestUpdateAccount(t *testing.T) {
 	})
 
 	t.Run("updates_credit_card_due_date", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCreditCardAccount(t, db, user.ID, 0)
 
 		dueDate := time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC)
-		updated, err := svc.UpdateAccount(user.ID, account.ID, AccountUpdateFields{
+		updated, err := svc.UpdateAccount(models.UserID(user.ID), models.AccountID(account.ID), AccountUpdateFields{
 			DueDate: &dueDate,
 		})
 		testutil.AssertNoError(t, err)
@@ -297,14 +391,13 @@ func TestUpdateAccount(t *testing.T) {
 	})
 
 	t.Run("ignores_broker_for_cash_account", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 
 		broker := "Fidelity"
-		updated, err := svc.UpdateAccount(user.ID, account.ID, AccountUpdateFields{
+		updated, err := svc.UpdateAccount(models.UserID(user.ID), models.AccountID(account.ID), AccountUpdateFields{
 			Broker: &broker,
 		})
 		testutil.AssertNoError(t, err)
@@ -315,14 +408,13 @@ func TestUpdateAccount(t *testing.T) {
 	})
 
 	t.Run("ignores_credit_limit_for_investment_account", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 
 		limit := int64(100000)
-		updated, err := svc.UpdateAccount(user.ID, account.ID, AccountUpdateFields{
+		updated, err := svc.UpdateAccount(models.UserID(user.ID), models.AccountID(account.ID), AccountUpdateFields{
 			CreditLimit: &limit,
 		})
 		testutil.AssertNoError(t, err)
@@ -333,60 +425,339 @@ func TestUpdateAccount(t *testing.T) {
 	})
 
 	t.Run("toggles_is_active", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 
 		inactive := false
-		_, err := svc.UpdateAccount(user.ID, account.ID, AccountUpdateFields{
+		_, err := svc.UpdateAccount(models.UserID(user.ID), models.AccountID(account.ID), AccountUpdateFields{
 			IsActive: &inactive,
 		})
 		testutil.AssertNoError(t, err)
 
 		// Verify in DB (GetAccountByID filters active=true, so query directly)
 		var dbAccount models.Account
-		db.First(&dbAccount, account.ID)
+		db.Where("id = ?", account.ID).First(&dbAccount)
 		if dbAccount.IsActive {
 			t.Error("expected account to be inactive")
 		}
 	})
 
 	t.Run("returns_error_for_nonexistent_account", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 
 		name := "Test"
-		_, err := svc.UpdateAccount(user.ID, 99999, AccountUpdateFields{
+		_, err := svc.UpdateAccount(models.UserID(user.ID), models.AccountID(uuid.New()), AccountUpdateFields{
 			Name: &name,
 		})
 		testutil.AssertAppError(t, err, "ACCOUNT_NOT_FOUND")
 	})
 
 	t.Run("returns_error_for_wrong_user", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user1 := testutil.CreateTestUser(t, db)
 		user2 := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user1.ID)
 
 		name := "Hacked"
-		_, err := svc.UpdateAccount(user2.ID, account.ID, AccountUpdateFields{
+		_, err := svc.UpdateAccount(models.UserID(user2.ID), models.AccountID(account.ID), AccountUpdateFields{
 			Name: &name,
 		})
 		testutil.AssertAppError(t, err, "ACCOUNT_NOT_FOUND")
 	})
+	t.Run("sets_and_clears_default_category", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, user.ID)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+
+		categoryID := &cat.ID
+		updated, err := svc.UpdateAccount(models.UserID(user.ID), models.AccountID(account.ID), AccountUpdateFields{
+			DefaultCategoryID: &categoryID,
+		})
+		testutil.AssertNoError(t, err)
+		if updated.DefaultCategoryID == nil || *updated.DefaultCategoryID != cat.ID {
+			t.Fatalf("expected default category %s, got %v", cat.ID, updated.DefaultCategoryID)
+		}
+
+		var cleared *string
+		updated, err = svc.UpdateAccount(models.UserID(user.ID), models.AccountID(account.ID), AccountUpdateFields{
+			DefaultCategoryID: &cleared,
+		})
+		testutil.AssertNoError(t, err)
+		if updated.DefaultCategoryID != nil {
+			t.Errorf("expected default category to be cleared, got %s", *updated.DefaultCategoryID)
+		}
+	})
+
+	t.Run("rejects_other_users_default_category", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		user := testutil.CreateTestUser(t, db)
+		other := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, user.ID)
+		cat := testutil.CreateTestCategory(t, db, other.ID, models.CategoryTypeExpense)
+
+		categoryID := &cat.ID
+		_, err := svc.UpdateAccount(models.UserID(user.ID), models.AccountID(account.ID), AccountUpdateFields{
+			DefaultCategoryID: &categoryID,
+		})
+		testutil.AssertAppError(t, err, "CATEGORY_NOT_FOUND")
+	})
+}
+
+func TestArchiveAccount(t *testing.T) {
+	t.Parallel()
+	t.Run("deactivating_captures_balance", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 42000)
+
+		before := time.Now().Add(-time.Second)
+		inactive := false
+		updated, err := svc.UpdateAccount(models.UserID(user.ID), models.AccountID(account.ID), AccountUpdateFields{IsActive: &inactive})
+		testutil.AssertNoError(t, err)
+
+		if updated.IsActive {
+			t.Error("expected account to be inactive")
+		}
+		if updated.ArchivedAt == nil || updated.ArchivedAt.Before(before) {
+			t.Errorf("expected archived_at to be set to now, got %v", updated.ArchivedAt)
+		}
+		if updated.ArchivedBalance == nil || *updated.ArchivedBalance != 42000 {
+			t.Errorf("expected archived balance 42000, got %v", updated.ArchivedBalance)
+		}
+	})
+
+	t.Run("other_updates_leave_archival_unset", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 42000)
+
+		active := true
+		updated, err := svc.UpdateAccount(models.UserID(user.ID), models.AccountID(account.ID), AccountUpdateFields{IsActive: &active})
+		testutil.AssertNoError(t, err)
+
+		if updated.ArchivedAt != nil || updated.ArchivedBalance != nil {
+			t.Errorf("expected no archival data, got %v and %v", updated.ArchivedAt, updated.ArchivedBalance)
+		}
+	})
+
+	t.Run("lists_archived_accounts", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		user := testutil.CreateTestUser(t, db)
+		other := testutil.CreateTestUser(t, db)
+
+		testutil.CreateTestCashAccount(t, db, user.ID)
+		first := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 1000)
+		second := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 2000)
+		othersAccount := testutil.CreateTestCashAccount(t, db, other.ID)
+
+		inactive := false
+		for _, a := range []*models.Account{first, second} {
+			_, err := svc.UpdateAccount(models.UserID(user.ID), models.AccountID(a.ID), AccountUpdateFields{IsActive: &inactive})
+			testutil.AssertNoError(t, err)
+		}
+		db.Model(first).Update("archived_at", time.Now().Add(-time.Hour))
+		_, err := svc.UpdateAccount(models.UserID(other.ID), models.AccountID(othersAccount.ID), AccountUpdateFields{IsActive: &inactive})
+		testutil.AssertNoError(t, err)
+
+		result, err := svc.GetArchivedAccounts(models.UserID(user.ID), pagination.PageRequest{Page: 1, PageSize: 20})
+		testutil.AssertNoError(t, err)
+
+		if len(result.Data) != 2 {
+			t.Fatalf("expected 2 archived accounts, got %d", len(result.Data))
+		}
+		if result.Data[0].ID != second.ID || result.Data[1].ID != first.ID {
+			t.Errorf("expected most recently archived first, got %s then %s", result.Data[0].ID, result.Data[1].ID)
+		}
+		if *result.Data[1].ArchivedBalance != 1000 {
+			t.Errorf("expected archived balance 1000, got %d", *result.Data[1].ArchivedBalance)
+		}
+	})
+}
+
+func TestDeleteAccount(t *testing.T) {
+	t.Parallel()
+	t.Run("deletes_empty_account", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		testutil.AssertNoError(t, svc.DeleteAccount(models.UserID(user.ID), models.AccountID(account.ID), false))
+
+		_, err := svc.GetAccountByID(models.UserID(user.ID), models.AccountID(account.ID))
+		testutil.AssertAppError(t, err, "ACCOUNT_NOT_FOUND")
+	})
+
+	t.Run("refuses_account_with_history_by_default", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 5000)
+		testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeExpense, 1000)
+		invAccount := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		testutil.CreateTestInvestment(t, db, invAccount.ID, testutil.CreateTestSecurity(t, db).ID)
+
+		err := svc.DeleteAccount(models.UserID(user.ID), models.AccountID(account.ID), false)
+		testutil.AssertAppError(t, err, "ACCOUNT_HAS_TRANSACTIONS")
+		err = svc.DeleteAccount(models.UserID(user.ID), models.AccountID(invAccount.ID), false)
+		testutil.AssertAppError(t, err, "ACCOUNT_HAS_TRANSACTIONS")
+
+		_, err = svc.GetAccountByID(models.UserID(user.ID), models.AccountID(account.ID))
+		testutil.AssertNoError(t, err)
+	})
+
+	t.Run("force_cascades_and_reverses_transfers", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, svc, nil)
+		user := testutil.CreateTestUser(t, db)
+		doomed := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+		savings := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+		uid := models.UserID(user.ID)
+
+		expense, err := txSvc.CreateTransaction(uid, models.AccountID(doomed.ID), nil, models.TransactionTypeExpense, 1000, "Coffee", time.Now(), false, models.TransactionStatusCleared, nil)
+		testutil.AssertNoError(t, err)
+		_, err = txSvc.CreateTransfer(uid, models.AccountID(doomed.ID), models.AccountID(savings.ID), 3000, "Out", time.Now())
+		testutil.AssertNoError(t, err)
+		_, err = txSvc.CreateTransfer(uid, models.AccountID(savings.ID), models.AccountID(doomed.ID), 500, "In", time.Now())
+		testutil.AssertNoError(t, err)
+		kept := testutil.CreateTestTransaction(t, db, user.ID, savings.ID, models.TransactionTypeExpense, 100)
+
+		testutil.AssertNoError(t, svc.DeleteAccount(uid, models.AccountID(doomed.ID), true))
+
+		// Savings got 3000 and paid 500; both are undone
+		var dbSavings models.Account
+		db.First(&dbSavings, "id = ?", savings.ID)
+		if dbSavings.Balance != 10000 {
+			t.Errorf("expected savings back at 10000, got %d", dbSavings.Balance)
+		}
+
+		var remaining []models.Transaction
+		db.Where("account_id = ? OR to_account_id = ?", doomed.ID, doomed.ID).Find(&remaining)
+		if len(remaining) != 0 {
+			t.Errorf("expected the account's transactions deleted, got %d", len(remaining))
+		}
+		var deleted models.Transaction
+		if err := db.Unscoped().First(&deleted, "id = ?", expense.ID).Error; err != nil || !deleted.DeletedAt.Valid {
+			t.Errorf("expected the expense soft-deleted, got %v", err)
+		}
+		if err := db.First(&models.Transaction{}, "id = ?", kept.ID).Error; err != nil {
+			t.Errorf("expected savings' own transaction kept, got %v", err)
+		}
+		var count int64
+		db.Model(&models.Account{}).Where("id = ?", doomed.ID).Count(&count)
+		if count != 0 {
+			t.Error("expected the account deleted")
+		}
+	})
+
+	t.Run("force_removes_whole_split_transfers", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, svc, nil)
+		user := testutil.CreateTestUser(t, db)
+		checking := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+		doomed := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 0)
+		savings := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 0)
+		uid := models.UserID(user.ID)
+
+		legs, err := txSvc.CreateSplitTransfer(uid, models.AccountID(checking.ID), []SplitTransferLeg{
+			{ToAccountID: models.AccountID(doomed.ID), Amount: 2000},
+			{ToAccountID: models.AccountID(savings.ID), Amount: 3000},
+		}, "Month end", time.Now())
+		testutil.AssertNoError(t, err)
+
+		testutil.AssertNoError(t, svc.DeleteAccount(uid, models.AccountID(doomed.ID), true))
+
+		// Both legs are undone, not just the one into the deleted account
+		for _, want := range []struct {
+			id      string
+			balance int64
+		}{{checking.ID, 10000}, {savings.ID, 0}} {
+			var got models.Account
+			testutil.AssertNoError(t, db.First(&got, "id = ?", want.id).Error)
+			if got.Balance != want.balance {
+				t.Errorf("expected account %s at %d, got %d", want.id, want.balance, got.Balance)
+			}
+		}
+		var remaining int64
+		db.Model(&models.Transaction{}).Where("transfer_group_id = ?", *legs[0].TransferGroupID).Count(&remaining)
+		if remaining != 0 {
+			t.Errorf("expected the whole split transfer deleted, got %d legs left", remaining)
+		}
+	})
+
+	t.Run("force_leaves_investment_account_of_a_funded_buy", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		invSvc := NewInvestmentService(db, svc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		invAccount := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		cash := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		inv := testutil.CreateTestInvestment(t, db, invAccount.ID, testutil.CreateTestSecurity(t, db).ID)
+		var before models.Account
+		testutil.AssertNoError(t, db.First(&before, "id = ?", invAccount.ID).Error)
+
+		_, err := invSvc.RecordBuy(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 2, 10000, 0, "", models.AccountID(cash.ID), false)
+		testutil.AssertNoError(t, err)
+
+		testutil.AssertNoError(t, svc.DeleteAccount(models.UserID(user.ID), models.AccountID(cash.ID), true))
+
+		var after models.Account
+		testutil.AssertNoError(t, db.First(&after, "id = ?", invAccount.ID).Error)
+		if after.Balance != before.Balance {
+			t.Errorf("expected investment account balance %d untouched, got %d", before.Balance, after.Balance)
+		}
+	})
+
+	t.Run("force_deletes_investments", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		user := testutil.CreateTestUser(t, db)
+		invAccount := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		inv := testutil.CreateTestInvestment(t, db, invAccount.ID, testutil.CreateTestSecurity(t, db).ID)
+		testutil.AssertNoError(t, db.Create(&models.InvestmentTransaction{InvestmentID: inv.ID, Type: models.InvestmentTransactionBuy,
+			Date: time.Now(), Quantity: 10, PricePerUnit: 10000, TotalAmount: 100000}).Error)
+
+		testutil.AssertNoError(t, svc.DeleteAccount(models.UserID(user.ID), models.AccountID(invAccount.ID), true))
+
+		var investments, invTransactions int64
+		db.Model(&models.Investment{}).Where("id = ?", inv.ID).Count(&investments)
+		db.Model(&models.InvestmentTransaction{}).Where("investment_id = ?", inv.ID).Count(&invTransactions)
+		if investments != 0 || invTransactions != 0 {
+			t.Errorf("expected investment and its transactions deleted, got %d and %d", investments, invTransactions)
+		}
+	})
+
+	t.Run("only_owner_can_delete", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		owner := testutil.CreateTestUser(t, db)
+		editor := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, owner.ID)
+		testutil.CreateTestAccountShare(t, db, owner.ID, editor.ID, models.ShareRoleEditor, account.ID)
+
+		err := svc.DeleteAccount(models.UserID(editor.ID), models.AccountID(account.ID), true)
+		testutil.AssertAppError(t, err, "ACCOUNT_NOT_FOUND")
+	})
 }
 
 func TestUpdateAccountBalance(t *testing.T) {
+	t.Parallel()
 	t.Run("income_adds", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 1000)
 
@@ -399,16 +770,15 @@ func TestUpdateAccountBalance(t *testing.T) {
 
 		// Verify persisted to DB
 		var dbAccount models.Account
-		db.First(&dbAccount, account.ID)
+		db.Where("id = ?", account.ID).First(&dbAccount)
 		if dbAccount.Balance != 1500 {
 			t.Errorf("expected DB balance 1500, got %d", dbAccount.Balance)
 		}
 	})
 
 	t.Run("expense_subtracts", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 1000)
 
@@ -421,7 +791,7 @@ func TestUpdateAccountBalance(t *testing.T) {
 
 		// Verify persisted to DB
 		var dbAccount models.Account
-		db.First(&dbAccount, account.ID)
+		db.Where("id = ?", account.ID).First(&dbAccount)
 		if dbAccount.Balance != 700 {
 			t.Errorf("expected DB balance 700, got %d", dbAccount.Balance)
 		}
@@ -429,13 +799,13 @@ func TestUpdateAccountBalance(t *testing.T) {
 }
 
 func TestCreateInvestmentAccount(t *testing.T) {
+	t.Parallel()
 	t.Run("valid", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 
-		account, err := svc.CreateInvestmentAccount(user.ID, "Brokerage", "My investments", "USD", "Fidelity", "123456")
+		account, err := svc.CreateInvestmentAccount(models.UserID(user.ID), "Brokerage", "My investments", "USD", "Fidelity", "123456")
 		testutil.AssertNoError(t, err)
 
 		if account.Type != models.AccountTypeInvestment {
@@ -450,22 +820,20 @@ func TestCreateInvestmentAccount(t *testing.T) {
 	})
 
 	t.Run("empty_name", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 
-		_, err := svc.CreateInvestmentAccount(user.ID, "", "", "USD", "", "")
+		_, err := svc.CreateInvestmentAccount(models.UserID(user.ID), "", "", "USD", "", "")
 		testutil.AssertAppError(t, err, "INVALID_INPUT")
 	})
 
 	t.Run("default_currency", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 
-		account, err := svc.CreateInvestmentAccount(user.ID, "Invest", "", "", "", "")
+		account, err := svc.CreateInvestmentAccount(models.UserID(user.ID), "Invest", "", "", "", "")
 		testutil.AssertNoError(t, err)
 
 		if account.Currency != "USD" {
@@ -475,18 +843,18 @@ func TestCreateInvestmentAccount(t *testing.T) {
 }
 
 func TestCreateCreditCardAccount(t *testing.T) {
+	t.Parallel()
 	t.Run("creates_credit_card_account", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 
 		dueDate := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
-		account, err := svc.CreateCreditCardAccount(user.ID, "Visa", "My credit card", "USD", 500000, 19.99, &dueDate)
+		account, err := svc.CreateCreditCardAccount(models.UserID(user.ID), "Visa", "My credit card", "USD", 500000, 19.99, &dueDate, nil)
 		testutil.AssertNoError(t, err)
 
-		if account.ID == 0 {
-			t.Fatal("expected non-zero account ID")
+		if account.ID == "" {
+			t.Fatal("expected non-empty account ID")
 		}
 		if account.Type != models.AccountTypeCreditCard {
 			t.Errorf("expected type credit_card, got %s", account.Type)
@@ -506,12 +874,11 @@ func TestCreateCreditCardAccount(t *testing.T) {
 	})
 
 	t.Run("defaults_currency_to_usd", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 
-		account, err := svc.CreateCreditCardAccount(user.ID, "Amex", "", "", 0, 0, nil)
+		account, err := svc.CreateCreditCardAccount(models.UserID(user.ID), "Amex", "", "", 0, 0, nil, nil)
 		testutil.AssertNoError(t, err)
 
 		if account.Currency != "USD" {
@@ -520,21 +887,37 @@ func TestCreateCreditCardAccount(t *testing.T) {
 	})
 
 	t.Run("returns_error_for_empty_name", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 
-		_, err := svc.CreateCreditCardAccount(user.ID, "", "", "USD", 0, 0, nil)
+		_, err := svc.CreateCreditCardAccount(models.UserID(user.ID), "", "", "USD", 0, 0, nil, nil)
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+
+	t.Run("stores_the_opening_date", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		user := testutil.CreateTestUser(t, db)
+
+		opened := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		account, err := svc.CreateCreditCardAccount(models.UserID(user.ID), "Visa", "", "USD", 0, 0, nil, &opened)
+		testutil.AssertNoError(t, err)
+		if account.OpeningDate == nil || !account.OpeningDate.Equal(opened) {
+			t.Errorf("expected opening date %v, got %v", opened, account.OpeningDate)
+		}
+
+		tomorrow := time.Now().AddDate(0, 0, 1)
+		_, err = svc.CreateCreditCardAccount(models.UserID(user.ID), "Visa", "", "USD", 0, 0, nil, &tomorrow)
 		testutil.AssertAppError(t, err, "INVALID_INPUT")
 	})
 }
 
 func TestGetUserAccountsInvestmentBalance(t *testing.T) {
+	t.Parallel()
 	t.Run("enriches_investment_account_balance", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 		sec := testutil.CreateTestSecurity(t, db)
@@ -546,7 +929,7 @@ func TestGetUserAccountsInvestmentBalance(t *testing.T) {
 		testutil.CreateTestSecurityPrice(t, db, sec.ID, 15000, time.Now())
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := svc.GetUserAccounts(user.ID, page)
+		result, err := svc.GetUserAccounts(models.UserID(user.ID), page, false)
 		testutil.AssertNoError(t, err)
 
 		if len(result.Data) != 1 {
@@ -559,14 +942,13 @@ func TestGetUserAccountsInvestmentBalance(t *testing.T) {
 	})
 
 	t.Run("leaves_cash_account_balance_unchanged", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 		testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 5000)
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := svc.GetUserAccounts(user.ID, page)
+		result, err := svc.GetUserAccounts(models.UserID(user.ID), page, false)
 		testutil.AssertNoError(t, err)
 
 		if len(result.Data) != 1 {
@@ -578,14 +960,13 @@ func TestGetUserAccountsInvestmentBalance(t *testing.T) {
 	})
 
 	t.Run("handles_investment_account_with_no_investments", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 		testutil.CreateTestInvestmentAccount(t, db, user.ID)
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := svc.GetUserAccounts(user.ID, page)
+		result, err := svc.GetUserAccounts(models.UserID(user.ID), page, false)
 		testutil.AssertNoError(t, err)
 
 		if len(result.Data) != 1 {
@@ -597,9 +978,8 @@ func TestGetUserAccountsInvestmentBalance(t *testing.T) {
 	})
 
 	t.Run("handles_investment_with_no_security_price", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 		sec := testutil.CreateTestSecurity(t, db)
@@ -608,7 +988,7 @@ func TestGetUserAccountsInvestmentBalance(t *testing.T) {
 		testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := svc.GetUserAccounts(user.ID, page)
+		result, err := svc.GetUserAccounts(models.UserID(user.ID), page, false)
 		testutil.AssertNoError(t, err)
 
 		if len(result.Data) != 1 {
@@ -621,10 +1001,10 @@ func TestGetUserAccountsInvestmentBalance(t *testing.T) {
 }
 
 func TestGetAccountByIDInvestmentBalance(t *testing.T) {
+	t.Parallel()
 	t.Run("enriches_investment_account_balance", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 		sec := testutil.CreateTestSecurity(t, db)
@@ -635,7 +1015,7 @@ func TestGetAccountByIDInvestmentBalance(t *testing.T) {
 		// Create security price: $150.00 per share
 		testutil.CreateTestSecurityPrice(t, db, sec.ID, 15000, time.Now())
 
-		result, err := svc.GetAccountByID(user.ID, account.ID)
+		result, err := svc.GetAccountByID(models.UserID(user.ID), models.AccountID(account.ID))
 		testutil.AssertNoError(t, err)
 
 		// Expected balance = 10 shares * $150.00 = $1500.00 = 150000 cents
@@ -645,13 +1025,12 @@ func TestGetAccountByIDInvestmentBalance(t *testing.T) {
 	})
 
 	t.Run("leaves_cash_account_unchanged", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 5000)
 
-		result, err := svc.GetAccountByID(user.ID, account.ID)
+		result, err := svc.GetAccountByID(models.UserID(user.ID), models.AccountID(account.ID))
 		testutil.AssertNoError(t, err)
 
 		if result.Balance != 5000 {
@@ -661,10 +1040,10 @@ func TestGetAccountByIDInvestmentBalance(t *testing.T) {
 }
 
 func TestUpdateAccountBalance_CreditCard(t *testing.T) {
+	t.Parallel()
 	t.Run("expense_increases_credit_card_balance", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCreditCardAccount(t, db, user.ID, 0)
 
@@ -676,16 +1055,15 @@ func TestUpdateAccountBalance_CreditCard(t *testing.T) {
 		}
 
 		var dbAccount models.Account
-		db.First(&dbAccount, account.ID)
+		db.Where("id = ?", account.ID).First(&dbAccount)
 		if dbAccount.Balance != 5000 {
 			t.Errorf("expected DB balance 5000, got %d", dbAccount.Balance)
 		}
 	})
 
 	t.Run("income_decreases_credit_card_balance", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCreditCardAccount(t, db, user.ID, 5000)
 
@@ -697,16 +1075,15 @@ func TestUpdateAccountBalance_CreditCard(t *testing.T) {
 		}
 
 		var dbAccount models.Account
-		db.First(&dbAccount, account.ID)
+		db.Where("id = ?", account.ID).First(&dbAccount)
 		if dbAccount.Balance != 2000 {
 			t.Errorf("expected DB balance 2000, got %d", dbAccount.Balance)
 		}
 	})
 
 	t.Run("cash_account_unchanged_behavior", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewAccountService(db)
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 1000)
 
@@ -718,3 +1095,104 @@ func TestUpdateAccountBalance_CreditCard(t *testing.T) {
 		}
 	})
 }
+
+func TestGetAccountTimeline(t *testing.T) {
+	t.Parallel()
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 12, 0, 0, 0, time.UTC) }
+
+	t.Run("interleaves_transactions_and_investment_events_by_date", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		user := testutil.CreateTestUser(t, db)
+		investAcct := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		cashAcct := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		inv := testutil.CreateTestInvestment(t, db, investAcct.ID, testutil.CreateTestSecurity(t, db).ID)
+
+		buy := &models.InvestmentTransaction{InvestmentID: inv.ID, Type: models.InvestmentTransactionBuy, Date: day(1), Quantity: 10, PricePerUnit: 10000, TotalAmount: 100000}
+		dividend := &models.InvestmentTransaction{InvestmentID: inv.ID, Type: models.InvestmentTransactionDividend, Date: day(3), TotalAmount: 500}
+		db.Create(buy)
+		db.Create(dividend)
+		fee := &models.Transaction{UserID: user.ID, AccountID: investAcct.ID, Type: models.TransactionTypeExpense, Amount: 300, Date: day(2)}
+		funding := &models.Transaction{UserID: user.ID, AccountID: cashAcct.ID, ToAccountID: &investAcct.ID, Type: models.TransactionTypeTransfer, Amount: 20000, Date: day(4)}
+		db.Create(fee)
+		db.Create(funding)
+		// On another account, so not part of the timeline
+		testutil.CreateTestTransaction(t, db, user.ID, cashAcct.ID, models.TransactionTypeExpense, 1000)
+
+		result, err := svc.GetAccountTimeline(models.UserID(user.ID), models.AccountID(investAcct.ID), pagination.PageRequest{Page: 1, PageSize: 3})
+		testutil.AssertNoError(t, err)
+		if result.TotalItems != 4 {
+			t.Errorf("expected 4 entries in total, got %d", result.TotalItems)
+		}
+		want := []struct {
+			kind AccountTimelineEntryKind
+			id   string
+		}{
+			{TimelineEntryTransaction, funding.ID},
+			{TimelineEntryInvestmentTransaction, dividend.ID},
+			{TimelineEntryTransaction, fee.ID},
+		}
+		if len(result.Data) != len(want) {
+			t.Fatalf("expected %d entries on the first page, got %d", len(want), len(result.Data))
+		}
+		for i, w := range want {
+			entry := result.Data[i]
+			if entry.Kind != w.kind {
+				t.Errorf("entry %d: expected kind %s, got %s", i, w.kind, entry.Kind)
+				continue
+			}
+			switch {
+			case entry.Transaction != nil && entry.InvestmentTransaction == nil:
+				if entry.Transaction.ID != w.id || !entry.Date.Equal(entry.Transaction.Date) {
+					t.Errorf("entry %d: expected transaction %s, got %s on %v", i, w.id, entry.Transaction.ID, entry.Date)
+				}
+			case entry.InvestmentTransaction != nil && entry.Transaction == nil:
+				if entry.InvestmentTransaction.ID != w.id || entry.InvestmentTransaction.Investment.Security.ID == "" {
+					t.Errorf("entry %d: expected investment transaction %s with its security, got %+v", i, w.id, entry.InvestmentTransaction)
+				}
+			default:
+				t.Errorf("entry %d: expected exactly one record, got %+v", i, entry)
+			}
+		}
+		if result.Data[0].Transaction.Direction != models.TransactionDirectionIn {
+			t.Errorf("expected the funding transfer to be incoming, got %q", result.Data[0].Transaction.Direction)
+		}
+
+		second, err := svc.GetAccountTimeline(models.UserID(user.ID), models.AccountID(investAcct.ID), pagination.PageRequest{Page: 2, PageSize: 3})
+		testutil.AssertNoError(t, err)
+		if len(second.Data) != 1 || second.Data[0].InvestmentTransaction == nil || second.Data[0].InvestmentTransaction.ID != buy.ID {
+			t.Errorf("expected the buy alone on the second page, got %+v", second.Data)
+		}
+	})
+
+	t.Run("cash_account_lists_transactions_only", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		user := testutil.CreateTestUser(t, db)
+		cashAcct := testutil.CreateTestCashAccount(t, db, user.ID)
+		testutil.CreateTestTransaction(t, db, user.ID, cashAcct.ID, models.TransactionTypeExpense, 1000)
+		testutil.CreateTestTransaction(t, db, user.ID, cashAcct.ID, models.TransactionTypeIncome, 5000)
+
+		result, err := svc.GetAccountTimeline(models.UserID(user.ID), models.AccountID(cashAcct.ID), pagination.PageRequest{})
+		testutil.AssertNoError(t, err)
+		if len(result.Data) != 2 || result.TotalItems != 2 {
+			t.Fatalf("expected 2 entries, got %d of %d", len(result.Data), result.TotalItems)
+		}
+		for _, entry := range result.Data {
+			if entry.Kind != TimelineEntryTransaction || entry.Transaction == nil {
+				t.Errorf("expected a transaction entry, got %+v", entry)
+			}
+		}
+	})
+
+	t.Run("other_users_account_not_found", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		owner := testutil.CreateTestUser(t, db)
+		other := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, owner.ID)
+
+		_, err := svc.GetAccountTimeline(models.UserID(other.ID), models.AccountID(account.ID), pagination.PageRequest{})
+		testutil.AssertAppError(t, err, "ACCOUNT_NOT_FOUND")
+	})
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+	"kuberan/internal/testutil"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// assertGolden compares got, as indented JSON, with testdata/name, or rewrites
+// the file when the tests run with -update.
+func assertGolden(t *testing.T, name string, got interface{}) {
+	t.Helper()
+	data, err := json.MarshalIndent(got, "", "  ")
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	data = append(data, '\n')
+
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatalf("write golden file: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run with -update to create it): %v", err)
+	}
+	if string(want) != string(data) {
+		t.Errorf("%s does not match; run with -update and review the diff\ngot:\n%s", path, data)
+	}
+}
+
+func TestGetStatement(t *testing.T) {
+	t.Parallel()
+	day := func(month time.Month, d int) time.Time { return time.Date(2025, month, d, 12, 0, 0, 0, time.UTC) }
+
+	t.Run("matches_golden_file", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		userID := models.UserID(user.ID)
+
+		checking := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 200000)
+		savings := testutil.CreateTestCashAccount(t, db, user.ID)
+		card := testutil.CreateTestCreditCardAccount(t, db, user.ID, 0)
+		db.Model(checking).Update("name", "Checking")
+		db.Model(savings).Update("name", "Savings")
+		db.Model(card).Update("name", "Visa")
+		groceries := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		db.Model(groceries).Update("name", "Groceries")
+
+		create := func(accountID string, categoryID *string, typ models.TransactionType, amount models.Cents, description string, date time.Time) {
+			t.Helper()
+			_, err := txSvc.CreateTransaction(userID, models.AccountID(accountID), categoryID, typ, amount, description, date, false, "", nil)
+			testutil.AssertNoError(t, err)
+		}
+		transfer := func(from, to string, amount models.Cents, description string, date time.Time) {
+			t.Helper()
+			_, err := txSvc.CreateTransfer(userID, models.AccountID(from), models.AccountID(to), amount, description, date)
+			testutil.AssertNoError(t, err)
+		}
+
+		// Before, during and after October; only October becomes lines
+		create(checking.ID, nil, models.TransactionTypeIncome, 50000, "September salary", day(time.September, 30))
+		create(checking.ID, &groceries.ID, models.TransactionTypeExpense, 4599, "Supermarket", day(time.October, 3))
+		transfer(checking.ID, savings.ID, 25000, "Monthly saving", day(time.October, 5))
+		create(card.ID, &groceries.ID, models.TransactionTypeExpense, 1250, "Bakery", day(time.October, 8))
+		transfer(checking.ID, card.ID, 1250, "Card payment", day(time.October, 20))
+		transfer(savings.ID, checking.ID, 5000, "Back from savings", day(time.October, 25))
+		create(checking.ID, nil, models.TransactionTypeIncome, 50000, "October salary", day(time.October, 31))
+		create(checking.ID, &groceries.ID, models.TransactionTypeExpense, 999, "November groceries", day(time.November, 2))
+
+		statements := map[string]*AccountStatement{}
+		for name, account := range map[string]*models.Account{"checking": checking, "card": card} {
+			statement, err := acctSvc.GetStatement(userID, models.AccountID(account.ID), day(time.October, 15))
+			testutil.AssertNoError(t, err)
+
+			if n := len(statement.Lines); n > 0 && statement.Lines[n-1].Balance != statement.ClosingBalance {
+				t.Errorf("%s: last running balance %d != closing balance %d", name, statement.Lines[n-1].Balance, statement.ClosingBalance)
+			}
+			if statement.OpeningBalance+statement.TotalIn-statement.TotalOut != statement.ClosingBalance {
+				t.Errorf("%s: opening + in - out != closing", name)
+			}
+
+			// IDs differ on every run
+			statement.AccountID = "redacted-" + name
+			for i := range statement.Lines {
+				statement.Lines[i].TransactionID = "redacted"
+			}
+			statements[name] = statement
+		}
+
+		assertGolden(t, "account_statement.golden.json", statements)
+	})
+
+	t.Run("empty_month_carries_balance", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 7500)
+		testutil.AssertNoError(t, db.Model(account).Update("opening_date", day(time.January, 1)).Error)
+
+		statement, err := svc.GetStatement(models.UserID(user.ID), models.AccountID(account.ID), day(time.March, 1))
+		testutil.AssertNoError(t, err)
+
+		if statement.OpeningBalance != 7500 || statement.ClosingBalance != 7500 || len(statement.Lines) != 0 {
+			t.Errorf("expected 7500 carried through an empty month, got %+v", statement)
+		}
+	})
+
+	t.Run("starts_at_the_opening_date", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		user := testutil.CreateTestUser(t, db)
+		opened := day(time.March, 10)
+		account, err := svc.CreateCashAccount(models.UserID(user.ID), "Savings", "", "USD", 5000, true, 0, &opened)
+		testutil.AssertNoError(t, err)
+
+		_, err = svc.GetStatement(models.UserID(user.ID), models.AccountID(account.ID), day(time.February, 1))
+		testutil.AssertAppError(t, err, apperrors.ErrInvalidInput.Code)
+
+		statement, err := svc.GetStatement(models.UserID(user.ID), models.AccountID(account.ID), day(time.March, 1))
+		testutil.AssertNoError(t, err)
+		if statement.OpeningBalance != 0 || statement.ClosingBalance != 5000 || len(statement.Lines) != 1 || !statement.Lines[0].Date.Equal(opened) {
+			t.Errorf("expected the initial balance on the opening date, got %+v", statement)
+		}
+	})
+
+	t.Run("rejects_investment_account", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+
+		_, err := svc.GetStatement(models.UserID(user.ID), models.AccountID(account.ID), day(time.October, 1))
+		testutil.AssertAppError(t, err, apperrors.ErrInvalidInput.Code)
+	})
+
+	t.Run("other_users_account_not_found", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewAccountService(db, nil)
+		owner := testutil.CreateTestUser(t, db)
+		other := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, owner.ID)
+
+		_, err := svc.GetStatement(models.UserID(other.ID), models.AccountID(account.ID), day(time.October, 1))
+		testutil.AssertAppError(t, err, apperrors.ErrAccountNotFound.Code)
+	})
+}
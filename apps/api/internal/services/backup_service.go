@@ -0,0 +1,932 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/logger"
+	"kuberan/internal/models"
+	"kuberan/internal/uuid"
+)
+
+// BackupSchemaVersion is the archive layout CreateBackup writes. Restores accept
+// archives of this version or older.
+const BackupSchemaVersion = 1
+
+// backupFormat identifies a backup archive.
+const backupFormat = "kuberan-backup"
+
+// Restore groups, in the order they are restored. Each group is one section of
+// the archive and is restored in its own database transaction.
+const (
+	restoreGroupCategories             = "categories"
+	restoreGroupAccounts               = "accounts"
+	restoreGroupTransactions           = "transactions"
+	restoreGroupBudgets                = "budgets"
+	restoreGroupInvestments            = "investments"
+	restoreGroupInvestmentTransactions = "investment_transactions"
+)
+
+var restoreGroups = []string{
+	restoreGroupCategories,
+	restoreGroupAccounts,
+	restoreGroupTransactions,
+	restoreGroupBudgets,
+	restoreGroupInvestments,
+	restoreGroupInvestmentTransactions,
+}
+
+// Entities whose archive IDs are mapped to restored IDs.
+const (
+	restoreEntityCategory      = "category"
+	restoreEntityAccount       = "account"
+	restoreEntityTransaction   = "transaction"
+	restoreEntityTransferGroup = "transfer_group"
+	restoreEntityBudget        = "budget"
+	restoreEntityInvestment    = "investment"
+	restoreEntityInvestmentTx  = "investment_transaction"
+)
+
+// BackupArchive is a versioned copy of a user's books that RestoreBackup can
+// recreate in another account. Each section of Data has a checksum, so edited
+// or truncated archives are refused.
+type BackupArchive struct {
+	Format        string            `json:"format"`
+	SchemaVersion int               `json:"schema_version"`
+	CreatedAt     time.Time         `json:"created_at"`
+	Checksums     map[string]string `json:"checksums"` // section name -> SHA-256 of its JSON
+	Data          BackupData        `json:"data"`
+}
+
+// BackupData holds the sections of a backup archive. Records keep the IDs they
+// had when the backup was taken, and refer to each other by those IDs.
+type BackupData struct {
+	Categories             []BackupCategory              `json:"categories"`
+	Accounts               []BackupAccount               `json:"accounts"`
+	Transactions           []BackupTransaction           `json:"transactions"`
+	Budgets                []BackupBudget                `json:"budgets"`
+	Investments            []BackupInvestment            `json:"investments"`
+	InvestmentTransactions []BackupInvestmentTransaction `json:"investment_transactions"`
+}
+
+// BackupCategory is a category in a backup archive.
+type BackupCategory struct {
+	ID          string              `json:"id"`
+	Name        string              `json:"name"`
+	Type        models.CategoryType `json:"type"`
+	Description string              `json:"description"`
+	Icon        string              `json:"icon"`
+	Color       string              `json:"color"`
+	ParentID    *string             `json:"parent_id,omitempty"`
+}
+
+// BackupAccount is an account in a backup archive. Balance is the stored
+// balance when the backup was taken; a restore replays the transactions and
+// reconciles the result to it.
+type BackupAccount struct {
+	ID                 string             `json:"id"`
+	Name               string             `json:"name"`
+	Type               models.AccountType `json:"type"`
+	Description        string             `json:"description"`
+	Balance            int64              `json:"balance"`
+	Currency           string             `json:"currency"`
+	IsActive           bool               `json:"is_active"`
+	OpeningDate        *time.Time         `json:"opening_date,omitempty"`
+	ArchivedAt         *time.Time         `json:"archived_at,omitempty"`
+	ArchivedBalance    *int64             `json:"archived_balance,omitempty"`
+	ExcludeFromReports bool               `json:"exclude_from_reports"`
+	DefaultCategoryID  *string            `json:"default_category_id,omitempty"`
+	Broker             string             `json:"broker,omitempty"`
+	AccountNumber      string             `json:"account_number,omitempty"`
+	InterestRate       float64            `json:"interest_rate,omitempty"`
+	DueDate            time.Time          `json:"due_date"`
+	CreditLimit        int64              `json:"credit_limit,omitempty"`
+	// AllowNegative is nil in archives written before overdraft protection,
+	// which restore as unprotected
+	AllowNegative *bool `json:"allow_negative,omitempty"`
+	MinBalance    int64 `json:"min_balance,omitempty"`
+}
+
+// BackupTransaction is a transaction in a backup archive.
+type BackupTransaction struct {
+	ID               string                   `json:"id"`
+	AccountID        string                   `json:"account_id"`
+	ToAccountID      *string                  `json:"to_account_id,omitempty"`
+	CategoryID       *string                  `json:"category_id,omitempty"`
+	Type             models.TransactionType   `json:"type"`
+	Amount           int64                    `json:"amount"`
+	Description      string                   `json:"description"`
+	Date             time.Time                `json:"date"`
+	IsPending        bool                     `json:"is_pending"`
+	Status           models.TransactionStatus `json:"status"`
+	OriginalAmount   *int64                   `json:"original_amount,omitempty"`
+	OriginalCurrency *string                  `json:"original_currency,omitempty"`
+	TransferGroupID  *string                  `json:"transfer_group_id,omitempty"`
+}
+
+// BackupBudget is a budget in a backup archive.
+type BackupBudget struct {
+	ID         string              `json:"id"`
+	CategoryID string              `json:"category_id"`
+	Name       string              `json:"name"`
+	Amount     int64               `json:"amount"`
+	Period     models.BudgetPeriod `json:"period"`
+	StartDate  time.Time           `json:"start_date"`
+	EndDate    *time.Time          `json:"end_date,omitempty"`
+	IsActive   bool                `json:"is_active"`
+}
+
+// BackupInvestment is a holding in a backup archive. Securities are shared, so
+// only a reference is kept: the ID, with the symbol and exchange to find the
+// security on a server where the ID differs.
+type BackupInvestment struct {
+	ID               string  `json:"id"`
+	AccountID        string  `json:"account_id"`
+	SecurityID       string  `json:"security_id"`
+	SecuritySymbol   string  `json:"security_symbol"`
+	SecurityExchange string  `json:"security_exchange,omitempty"`
+	Quantity         float64 `json:"quantity"`
+	CostBasis        int64   `json:"cost_basis"`
+	RealizedGainLoss int64   `json:"realized_gain_loss"`
+	WalletAddress    string  `json:"wallet_address,omitempty"`
+	Notes            string  `json:"notes"`
+	TargetPrice      *int64  `json:"target_price,omitempty"`
+}
+
+// BackupInvestmentTransaction is an investment transaction in a backup archive.
+type BackupInvestmentTransaction struct {
+	ID                string                           `json:"id"`
+	InvestmentID      string                           `json:"investment_id"`
+	Type              models.InvestmentTransactionType `json:"type"`
+	Date              time.Time                        `json:"date"`
+	Quantity          float64                          `json:"quantity"`
+	PricePerUnit      int64                            `json:"price_per_unit"`
+	TotalAmount       int64                            `json:"total_amount"`
+	Fee               int64                            `json:"fee"`
+	Notes             string                           `json:"notes"`
+	RealizedGainLoss  int64                            `json:"realized_gain_loss"`
+	CostBasisMethod   models.CostBasisMethod           `json:"cost_basis_method,omitempty"`
+	SplitRatio        float64                          `json:"split_ratio,omitempty"`
+	SplitRemainder    float64                          `json:"split_remainder,omitempty"`
+	DividendType      string                           `json:"dividend_type,omitempty"`
+	CashTransactionID *string                          `json:"cash_transaction_id,omitempty"`
+	ExternalRef       *string                          `json:"external_ref,omitempty"`
+}
+
+// RestoreStatus is the state of one group of a restore.
+type RestoreStatus string
+
+// Restore group statuses.
+const (
+	RestoreStatusDone    RestoreStatus = "done"    // restored by this call
+	RestoreStatusSkipped RestoreStatus = "skipped" // restored by an earlier call with the same archive
+	RestoreStatusFailed  RestoreStatus = "failed"  // rolled back; Error says why
+	RestoreStatusPending RestoreStatus = "pending" // not attempted because an earlier group failed
+)
+
+// RestoreGroupProgress reports one group of a restore.
+type RestoreGroupProgress struct {
+	Group            string        `json:"group"`
+	Status           RestoreStatus `json:"status"`
+	Restored         int           `json:"restored"`
+	BalancesAdjusted int           `json:"balances_adjusted,omitempty"` // accounts whose replayed balance differed from the archive's
+	Error            string        `json:"error,omitempty"`
+}
+
+// RestoreReport is the outcome of a restore. When Completed is false a group
+// failed, and restoring the same archive again resumes at that group.
+type RestoreReport struct {
+	ArchiveID string                 `json:"archive_id"`
+	Completed bool                   `json:"completed"`
+	Groups    []RestoreGroupProgress `json:"groups"`
+}
+
+// backupService takes and restores per-user backups.
+type backupService struct {
+	db           *gorm.DB
+	transactions *transactionService
+}
+
+// NewBackupService creates a new BackupServicer.
+func NewBackupService(db *gorm.DB) BackupServicer {
+	return &backupService{
+		db:           db,
+		transactions: &transactionService{db: db, accountService: NewAccountService(db, nil), clock: SystemClock},
+	}
+}
+
+// CreateBackup returns the user's categories, accounts, transactions, budgets,
+// investments and investment transactions as a checksummed archive. Each
+// section is ordered by ID. Transactions are those on the user's own accounts,
+// which are the ones that make up their balances.
+func (s *backupService) CreateBackup(userID models.UserID) (*BackupArchive, error) {
+	if err := s.db.Where("id = ?", string(userID)).First(&models.User{}).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrUserNotFound
+		}
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	accountIDs := s.db.Model(&models.Account{}).Select("id").Where("user_id = ?", string(userID))
+	investmentIDs := s.db.Model(&models.Investment{}).Select("id").Where("account_id IN (?)", accountIDs)
+
+	var (
+		categories     []models.Category
+		accounts       []models.Account
+		transactions   []models.Transaction
+		budgets        []models.Budget
+		investments    []models.Investment
+		investmentTxns []models.InvestmentTransaction
+	)
+	for _, section := range []struct {
+		dest  interface{}
+		query *gorm.DB
+	}{
+		{&categories, s.db.Where("user_id = ?", string(userID))},
+		{&accounts, s.db.Where("user_id = ?", string(userID))},
+		{&transactions, s.db.Where("account_id IN (?)", accountIDs)},
+		{&budgets, s.db.Where("user_id = ?", string(userID))},
+		{&investments, s.db.Preload("Security").Where("account_id IN (?)", accountIDs)},
+		{&investmentTxns, s.db.Where("investment_id IN (?)", investmentIDs)},
+	} {
+		if err := section.query.Order("id").Find(section.dest).Error; err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+	}
+
+	data := BackupData{
+		Categories:             make([]BackupCategory, 0, len(categories)),
+		Accounts:               make([]BackupAccount, 0, len(accounts)),
+		Transactions:           make([]BackupTransaction, 0, len(transactions)),
+		Budgets:                make([]BackupBudget, 0, len(budgets)),
+		Investments:            make([]BackupInvestment, 0, len(investments)),
+		InvestmentTransactions: make([]BackupInvestmentTransaction, 0, len(investmentTxns)),
+	}
+	for _, c := range categories {
+		data.Categories = append(data.Categories, BackupCategory{
+			ID: c.ID, Name: c.Name, Type: c.Type, Description: c.Description,
+			Icon: c.Icon, Color: c.Color, ParentID: c.ParentID,
+		})
+	}
+	for _, a := range accounts {
+		data.Accounts = append(data.Accounts, BackupAccount{
+			ID: a.ID, Name: a.Name, Type: a.Type, Description: a.Description,
+			Balance: a.Balance, Currency: a.Currency, IsActive: a.IsActive,
+			OpeningDate: utcTimePtr(a.OpeningDate), ArchivedAt: utcTimePtr(a.ArchivedAt), ArchivedBalance: a.ArchivedBalance,
+			ExcludeFromReports: a.ExcludeFromReports, DefaultCategoryID: a.DefaultCategoryID,
+			Broker: a.Broker, AccountNumber: a.AccountNumber, InterestRate: a.InterestRate,
+			DueDate: a.DueDate.UTC(), CreditLimit: a.CreditLimit,
+			AllowNegative: &a.AllowNegative, MinBalance: a.MinBalance,
+		})
+	}
+	for _, t := range transactions {
+		data.Transactions = append(data.Transactions, BackupTransaction{
+			ID: t.ID, AccountID: t.AccountID, ToAccountID: t.ToAccountID, CategoryID: t.CategoryID,
+			Type: t.Type, Amount: t.Amount, Description: t.Description, Date: t.Date.UTC(),
+			IsPending: t.IsPending, Status: t.Status,
+			OriginalAmount: t.OriginalAmount, OriginalCurrency: t.OriginalCurrency,
+			TransferGroupID: t.TransferGroupID,
+		})
+	}
+	for _, b := range budgets {
+		data.Budgets = append(data.Budgets, BackupBudget{
+			ID: b.ID, CategoryID: b.CategoryID, Name: b.Name, Amount: b.Amount, Period: b.Period,
+			StartDate: b.StartDate.UTC(), EndDate: utcTimePtr(b.EndDate), IsActive: b.IsActive,
+		})
+	}
+	for _, inv := range investments {
+		data.Investments = append(data.Investments, BackupInvestment{
+			ID: inv.ID, AccountID: inv.AccountID, SecurityID: inv.SecurityID,
+			SecuritySymbol: inv.Security.Symbol, SecurityExchange: inv.Security.Exchange,
+			Quantity: inv.Quantity, CostBasis: inv.CostBasis, RealizedGainLoss: inv.RealizedGainLoss,
+			WalletAddress: inv.WalletAddress, Notes: inv.Notes, TargetPrice: inv.TargetPrice,
+		})
+	}
+	for _, it := range investmentTxns {
+		data.InvestmentTransactions = append(data.InvestmentTransactions, BackupInvestmentTransaction{
+			ID: it.ID, InvestmentID: it.InvestmentID, Type: it.Type, Date: it.Date.UTC(),
+			Quantity: it.Quantity, PricePerUnit: it.PricePerUnit, TotalAmount: it.TotalAmount,
+			Fee: it.Fee, Notes: it.Notes, RealizedGainLoss: it.RealizedGainLoss,
+			CostBasisMethod: it.CostBasisMethod, SplitRatio: it.SplitRatio, SplitRemainder: it.SplitRemainder,
+			DividendType: it.DividendType, CashTransactionID: it.CashTransactionID, ExternalRef: it.ExternalRef,
+		})
+	}
+
+	checksums, err := data.checksums()
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return &BackupArchive{
+		Format:        backupFormat,
+		SchemaVersion: BackupSchemaVersion,
+		CreatedAt:     time.Now().UTC(),
+		Checksums:     checksums,
+		Data:          data,
+	}, nil
+}
+
+// RestoreBackup recreates the archive's records for the user under new IDs,
+// one group at a time in restoreGroups order, each in its own transaction.
+// Transactions are replayed so balances are derived as they would be for new
+// entries; an account whose replayed balance differs from the archive's is then
+// set to the archive's balance and counted in BalancesAdjusted.
+//
+// A user who already has accounts or categories is refused unless replace is
+// set, in which case their existing books are deleted first. A group that fails
+// is rolled back and reported, and later groups are left pending; restoring the
+// same archive again skips the groups already restored and resumes.
+func (s *backupService) RestoreBackup(userID models.UserID, archive *BackupArchive, replace bool) (*RestoreReport, error) {
+	archiveID, err := archive.verify()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.Where("id = ?", string(userID)).First(&models.User{}).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrUserNotFound
+		}
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	var progress []models.RestoreProgress
+	if err := s.db.Where("user_id = ? AND archive_id = ?", string(userID), archiveID).
+		Find(&progress).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	done := make(map[string]models.RestoreProgress, len(progress))
+	for _, p := range progress {
+		done[p.GroupName] = p
+	}
+
+	// A resumed restore finds the data it restored itself, so only a fresh one is checked
+	if len(done) == 0 {
+		hasData, err := s.hasData(string(userID))
+		if err != nil {
+			return nil, err
+		}
+		if hasData {
+			if !replace {
+				return nil, apperrors.ErrRestoreTargetNotEmpty
+			}
+			if err := s.clearUserData(string(userID)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	ids, err := s.loadIDMappings(string(userID), archiveID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RestoreReport{ArchiveID: archiveID, Completed: true, Groups: make([]RestoreGroupProgress, 0, len(restoreGroups))}
+	for _, group := range restoreGroups {
+		entry := RestoreGroupProgress{Group: group, Status: RestoreStatusPending}
+		if p, ok := done[group]; ok {
+			entry.Status, entry.Restored = RestoreStatusSkipped, p.Restored
+		} else if report.Completed {
+			run := &restoreRun{userID: string(userID), archiveID: archiveID, ids: ids, added: restoreIDs{}}
+			if err := s.restoreGroup(run, group, &archive.Data); err != nil {
+				logger.Get().Errorw("restore group failed", "user_id", userID, "archive_id", archiveID, "group", group, "error", err)
+				entry.Status, entry.Error = RestoreStatusFailed, restoreErrorMessage(err)
+				report.Completed = false
+			} else {
+				entry.Status, entry.Restored, entry.BalancesAdjusted = RestoreStatusDone, run.restored, run.adjusted
+				ids.merge(run.added)
+			}
+		}
+		report.Groups = append(report.Groups, entry)
+	}
+	return report, nil
+}
+
+// sections returns the archive's sections by name.
+func (d *BackupData) sections() map[string]interface{} {
+	return map[string]interface{}{
+		restoreGroupCategories:             d.Categories,
+		restoreGroupAccounts:               d.Accounts,
+		restoreGroupTransactions:           d.Transactions,
+		restoreGroupBudgets:                d.Budgets,
+		restoreGroupInvestments:            d.Investments,
+		restoreGroupInvestmentTransactions: d.InvestmentTransactions,
+	}
+}
+
+// checksums returns the SHA-256 of each section's JSON by section name.
+func (d *BackupData) checksums() (map[string]string, error) {
+	sums := make(map[string]string, len(restoreGroups))
+	for name, section := range d.sections() {
+		raw, err := json.Marshal(section)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(raw)
+		sums[name] = hex.EncodeToString(sum[:])
+	}
+	return sums, nil
+}
+
+// verify checks the archive's format, schema version and checksums, and returns
+// its ID: the SHA-256 of its section checksums, which identifies the content
+// regardless of when the backup was taken.
+func (a *BackupArchive) verify() (string, error) {
+	if a.Format != backupFormat {
+		return "", apperrors.WithMessage(apperrors.ErrInvalidBackup, "not a backup archive")
+	}
+	if a.SchemaVersion < 1 || a.SchemaVersion > BackupSchemaVersion {
+		return "", apperrors.WithMessage(apperrors.ErrInvalidBackup,
+			fmt.Sprintf("unsupported schema version %d; this server reads up to version %d", a.SchemaVersion, BackupSchemaVersion))
+	}
+	sums, err := a.Data.checksums()
+	if err != nil {
+		return "", apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	id := sha256.New()
+	for _, name := range restoreGroups {
+		if a.Checksums[name] != sums[name] {
+			return "", apperrors.WithMessage(apperrors.ErrInvalidBackup, fmt.Sprintf("checksum mismatch in section %s", name))
+		}
+		fmt.Fprintf(id, "%s=%s\n", name, sums[name])
+	}
+	return hex.EncodeToString(id.Sum(nil)), nil
+}
+
+// hasData reports whether the user has any accounts or categories.
+func (s *backupService) hasData(userID string) (bool, error) {
+	for _, model := range []interface{}{&models.Account{}, &models.Category{}} {
+		var count int64
+		if err := s.db.Model(model).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+			return false, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// clearUserData deletes the user's books before a replacing restore: their
+// accounts with the transactions, holdings and share links on them, budgets,
+// categorization rules, categories, and the records of earlier restores.
+func (s *backupService) clearUserData(userID string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		accountIDs := tx.Model(&models.Account{}).Select("id").Where("user_id = ?", userID)
+		investmentIDs := tx.Model(&models.Investment{}).Select("id").Where("account_id IN (?)", accountIDs)
+		for _, step := range []struct {
+			query *gorm.DB
+			model interface{}
+		}{
+			{tx.Where("investment_id IN (?)", investmentIDs), &models.InvestmentTransaction{}},
+			{tx.Where("account_id IN (?)", accountIDs), &models.Investment{}},
+			{tx.Where("account_id IN (?)", accountIDs), &models.Transaction{}},
+			{tx.Where("account_id IN (?)", accountIDs), &models.AccountShareAccount{}},
+			{tx.Where("user_id = ?", userID), &models.Account{}},
+			{tx.Where("user_id = ?", userID), &models.Budget{}},
+			{tx.Where("user_id = ?", userID), &models.CategorizationRule{}},
+			{tx.Where("user_id = ?", userID), &models.Category{}},
+			{tx.Unscoped().Where("user_id = ?", userID), &models.RestoreIDMapping{}},
+			{tx.Unscoped().Where("user_id = ?", userID), &models.RestoreProgress{}},
+		} {
+			if err := step.query.Delete(step.model).Error; err != nil {
+				return apperrors.Wrap(apperrors.ErrInternalServer, err)
+			}
+		}
+		return nil
+	})
+}
+
+// loadIDMappings returns the ID mappings recorded by earlier calls restoring
+// the archive.
+func (s *backupService) loadIDMappings(userID, archiveID string) (restoreIDs, error) {
+	var mappings []models.RestoreIDMapping
+	if err := s.db.Where("user_id = ? AND archive_id = ?", userID, archiveID).Find(&mappings).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	ids := restoreIDs{}
+	for _, m := range mappings {
+		ids.set(m.Entity, m.OldID, m.NewID)
+	}
+	return ids, nil
+}
+
+// restoreGroup restores one group in a transaction and records it as done.
+func (s *backupService) restoreGroup(run *restoreRun, group string, data *BackupData) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		run.tx = tx
+		var err error
+		switch group {
+		case restoreGroupCategories:
+			err = s.restoreCategories(run, data.Categories)
+		case restoreGroupAccounts:
+			err = s.restoreAccounts(run, data.Accounts)
+		case restoreGroupTransactions:
+			err = s.restoreTransactions(run, data.Transactions, data.Accounts)
+		case restoreGroupBudgets:
+			err = s.restoreBudgets(run, data.Budgets)
+		case restoreGroupInvestments:
+			err = s.restoreInvestments(run, data.Investments)
+		case restoreGroupInvestmentTransactions:
+			err = s.restoreInvestmentTransactions(run, data.InvestmentTransactions)
+		}
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(&models.RestoreProgress{
+			UserID:      run.userID,
+			ArchiveID:   run.archiveID,
+			GroupName:   group,
+			Restored:    run.restored,
+			CompletedAt: time.Now().UTC(),
+		}).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		return nil
+	})
+}
+
+func (s *backupService) restoreCategories(run *restoreRun, categories []BackupCategory) error {
+	for _, c := range categories {
+		category := models.Category{
+			UserID:      run.userID,
+			Name:        c.Name,
+			Type:        c.Type,
+			Description: c.Description,
+			Icon:        c.Icon,
+			Color:       c.Color,
+		}
+		if err := run.create(&category); err != nil {
+			return err
+		}
+		if err := run.remember(restoreEntityCategory, c.ID, category.ID); err != nil {
+			return err
+		}
+	}
+
+	// Parents are linked once every category exists, as a child may sort before its parent
+	for _, c := range categories {
+		if c.ParentID == nil {
+			continue
+		}
+		id, err := run.resolve(restoreEntityCategory, c.ID)
+		if err != nil {
+			return err
+		}
+		parentID, err := run.resolve(restoreEntityCategory, *c.ParentID)
+		if err != nil {
+			return err
+		}
+		if err := run.tx.Model(&models.Category{}).Where("id = ?", id).Update("parent_id", parentID).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+	}
+	run.restored = len(categories)
+	return nil
+}
+
+func (s *backupService) restoreAccounts(run *restoreRun, accounts []BackupAccount) error {
+	for _, a := range accounts {
+		defaultCategoryID, err := run.resolveOptional(restoreEntityCategory, a.DefaultCategoryID)
+		if err != nil {
+			return err
+		}
+		// The balance starts at zero; the transactions group replays it
+		account := models.Account{
+			UserID:             run.userID,
+			Name:               a.Name,
+			Type:               a.Type,
+			Description:        a.Description,
+			Currency:           a.Currency,
+			IsActive:           a.IsActive,
+			OpeningDate:        a.OpeningDate,
+			ArchivedAt:         a.ArchivedAt,
+			ArchivedBalance:    a.ArchivedBalance,
+			ExcludeFromReports: a.ExcludeFromReports,
+			DefaultCategoryID:  defaultCategoryID,
+			Broker:             a.Broker,
+			AccountNumber:      a.AccountNumber,
+			InterestRate:       a.InterestRate,
+			DueDate:            a.DueDate,
+			CreditLimit:        a.CreditLimit,
+			MinBalance:         a.MinBalance,
+		}
+		if err := run.create(&account); err != nil {
+			return err
+		}
+		// The zero value would be replaced by the column default
+		if !a.IsActive {
+			if err := run.tx.Model(&account).Update("is_active", false).Error; err != nil {
+				return apperrors.Wrap(apperrors.ErrInternalServer, err)
+			}
+		}
+		if a.AllowNegative != nil && !*a.AllowNegative && a.Type == models.AccountTypeCash {
+			if err := run.tx.Model(&account).Update("allow_negative", false).Error; err != nil {
+				return apperrors.Wrap(apperrors.ErrInternalServer, err)
+			}
+		}
+		if err := run.remember(restoreEntityAccount, a.ID, account.ID); err != nil {
+			return err
+		}
+	}
+	run.restored = len(accounts)
+	return nil
+}
+
+func (s *backupService) restoreTransactions(run *restoreRun, transactions []BackupTransaction, accounts []BackupAccount) error {
+	for _, t := range transactions {
+		accountID, err := run.resolve(restoreEntityAccount, t.AccountID)
+		if err != nil {
+			return err
+		}
+		toAccountID, err := run.resolveOptional(restoreEntityAccount, t.ToAccountID)
+		if err != nil {
+			return err
+		}
+		categoryID, err := run.resolveOptional(restoreEntityCategory, t.CategoryID)
+		if err != nil {
+			return err
+		}
+		var transferGroupID *string
+		if t.TransferGroupID != nil {
+			groupID, ok := run.lookup(restoreEntityTransferGroup, *t.TransferGroupID)
+			if !ok {
+				groupID = uuid.New()
+				if err := run.remember(restoreEntityTransferGroup, *t.TransferGroupID, groupID); err != nil {
+					return err
+				}
+			}
+			transferGroupID = &groupID
+		}
+
+		transaction := models.Transaction{
+			UserID:           run.userID,
+			AccountID:        accountID,
+			ToAccountID:      toAccountID,
+			CategoryID:       categoryID,
+			Type:             t.Type,
+			Amount:           t.Amount,
+			Description:      t.Description,
+			Date:             t.Date,
+			IsPending:        t.IsPending,
+			Status:           t.Status,
+			OriginalAmount:   t.OriginalAmount,
+			OriginalCurrency: t.OriginalCurrency,
+			TransferGroupID:  transferGroupID,
+		}
+		if err := run.create(&transaction); err != nil {
+			return err
+		}
+		if err := run.remember(restoreEntityTransaction, t.ID, transaction.ID); err != nil {
+			return err
+		}
+		if !transaction.IsPending {
+			if err := s.transactions.applySettledBalance(run.tx, &transaction, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Balances the history does not explain are taken from the archive
+	for _, a := range accounts {
+		id, err := run.resolve(restoreEntityAccount, a.ID)
+		if err != nil {
+			return err
+		}
+		var account models.Account
+		if err := run.tx.Select("id", "balance").Where("id = ?", id).First(&account).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		if account.Balance == a.Balance {
+			continue
+		}
+		if err := run.tx.Model(&account).Update("balance", a.Balance).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		run.adjusted++
+	}
+	run.restored = len(transactions)
+	return nil
+}
+
+func (s *backupService) restoreBudgets(run *restoreRun, budgets []BackupBudget) error {
+	for _, b := range budgets {
+		categoryID, err := run.resolve(restoreEntityCategory, b.CategoryID)
+		if err != nil {
+			return err
+		}
+		budget := models.Budget{
+			UserID:     run.userID,
+			CategoryID: categoryID,
+			Name:       b.Name,
+			Amount:     b.Amount,
+			Period:     b.Period,
+			StartDate:  b.StartDate,
+			EndDate:    b.EndDate,
+			IsActive:   b.IsActive,
+		}
+		if err := run.create(&budget); err != nil {
+			return err
+		}
+		// The zero value would be replaced by the column default
+		if !b.IsActive {
+			if err := run.tx.Model(&budget).Update("is_active", false).Error; err != nil {
+				return apperrors.Wrap(apperrors.ErrInternalServer, err)
+			}
+		}
+		if err := run.remember(restoreEntityBudget, b.ID, budget.ID); err != nil {
+			return err
+		}
+	}
+	run.restored = len(budgets)
+	return nil
+}
+
+func (s *backupService) restoreInvestments(run *restoreRun, investments []BackupInvestment) error {
+	for _, inv := range investments {
+		accountID, err := run.resolve(restoreEntityAccount, inv.AccountID)
+		if err != nil {
+			return err
+		}
+		securityID, err := s.resolveSecurity(run.tx, inv)
+		if err != nil {
+			return err
+		}
+		investment := models.Investment{
+			AccountID:        accountID,
+			SecurityID:       securityID,
+			Quantity:         inv.Quantity,
+			CostBasis:        inv.CostBasis,
+			RealizedGainLoss: inv.RealizedGainLoss,
+			WalletAddress:    inv.WalletAddress,
+			Notes:            inv.Notes,
+			TargetPrice:      inv.TargetPrice,
+		}
+		if err := run.create(&investment); err != nil {
+			return err
+		}
+		if err := run.remember(restoreEntityInvestment, inv.ID, investment.ID); err != nil {
+			return err
+		}
+	}
+	run.restored = len(investments)
+	return nil
+}
+
+func (s *backupService) restoreInvestmentTransactions(run *restoreRun, investmentTxns []BackupInvestmentTransaction) error {
+	for _, it := range investmentTxns {
+		investmentID, err := run.resolve(restoreEntityInvestment, it.InvestmentID)
+		if err != nil {
+			return err
+		}
+		cashTransactionID, err := run.resolveOptional(restoreEntityTransaction, it.CashTransactionID)
+		if err != nil {
+			return err
+		}
+		investmentTx := models.InvestmentTransaction{
+			InvestmentID:      investmentID,
+			Type:              it.Type,
+			Date:              it.Date,
+			Quantity:          it.Quantity,
+			PricePerUnit:      it.PricePerUnit,
+			TotalAmount:       it.TotalAmount,
+			Fee:               it.Fee,
+			Notes:             it.Notes,
+			RealizedGainLoss:  it.RealizedGainLoss,
+			CostBasisMethod:   it.CostBasisMethod,
+			SplitRatio:        it.SplitRatio,
+			SplitRemainder:    it.SplitRemainder,
+			DividendType:      it.DividendType,
+			CashTransactionID: cashTransactionID,
+			ExternalRef:       it.ExternalRef,
+		}
+		if err := run.create(&investmentTx); err != nil {
+			return err
+		}
+		if err := run.remember(restoreEntityInvestmentTx, it.ID, investmentTx.ID); err != nil {
+			return err
+		}
+	}
+	run.restored = len(investmentTxns)
+	return nil
+}
+
+// resolveSecurity finds the security of a backed-up holding by ID, or by symbol
+// and exchange when the archive comes from a server with other security IDs.
+func (s *backupService) resolveSecurity(tx *gorm.DB, inv BackupInvestment) (string, error) {
+	var security models.Security
+	err := tx.Select("id").Where("id = ?", inv.SecurityID).First(&security).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = tx.Select("id").Where("symbol = ? AND exchange = ?", inv.SecuritySymbol, inv.SecurityExchange).First(&security).Error
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", apperrors.WithMessage(apperrors.ErrInvalidBackup,
+			fmt.Sprintf("security %s is not known to this server", inv.SecuritySymbol))
+	}
+	if err != nil {
+		return "", apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return security.ID, nil
+}
+
+// restoreErrorMessage is the client-safe message of a failed group's error.
+func restoreErrorMessage(err error) string {
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Message
+	}
+	return apperrors.ErrInternalServer.Message
+}
+
+// utcTimePtr returns t in UTC, or nil.
+func utcTimePtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+// restoreIDs maps entity -> archive ID -> restored ID.
+type restoreIDs map[string]map[string]string
+
+func (m restoreIDs) set(entity, oldID, newID string) {
+	if m[entity] == nil {
+		m[entity] = map[string]string{}
+	}
+	m[entity][oldID] = newID
+}
+
+func (m restoreIDs) merge(other restoreIDs) {
+	for entity, ids := range other {
+		for oldID, newID := range ids {
+			m.set(entity, oldID, newID)
+		}
+	}
+}
+
+// restoreRun is the state of restoring one group.
+type restoreRun struct {
+	tx        *gorm.DB
+	userID    string
+	archiveID string
+	ids       restoreIDs // mappings of the groups restored before
+	added     restoreIDs // mappings made by this group, merged into ids once it commits
+	restored  int
+	adjusted  int
+}
+
+func (r *restoreRun) create(record interface{}) error {
+	if err := r.tx.Create(record).Error; err != nil {
+		return apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return nil
+}
+
+// remember records that the archive's oldID of entity was restored as newID.
+func (r *restoreRun) remember(entity, oldID, newID string) error {
+	if err := r.create(&models.RestoreIDMapping{
+		UserID:    r.userID,
+		ArchiveID: r.archiveID,
+		Entity:    entity,
+		OldID:     oldID,
+		NewID:     newID,
+	}); err != nil {
+		return err
+	}
+	r.added.set(entity, oldID, newID)
+	return nil
+}
+
+func (r *restoreRun) lookup(entity, oldID string) (string, bool) {
+	if id, ok := r.added[entity][oldID]; ok {
+		return id, true
+	}
+	id, ok := r.ids[entity][oldID]
+	return id, ok
+}
+
+// resolve returns the restored ID of the archive's oldID of entity. A reference
+// to a record missing from the archive makes the archive invalid.
+func (r *restoreRun) resolve(entity, oldID string) (string, error) {
+	if id, ok := r.lookup(entity, oldID); ok {
+		return id, nil
+	}
+	return "", apperrors.WithMessage(apperrors.ErrInvalidBackup,
+		fmt.Sprintf("%s %s is referenced but not in the archive", entity, oldID))
+}
+
+func (r *restoreRun) resolveOptional(entity string, oldID *string) (*string, error) {
+	if oldID == nil {
+		return nil, nil
+	}
+	id, err := r.resolve(entity, *oldID)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
@@ -0,0 +1,348 @@
+package services
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"kuberan/internal/models"
+	"kuberan/internal/testutil"
+	"kuberan/internal/uuid"
+)
+
+// seedBackupUser gives the user a bit of every section of a backup, with
+// balances kept by the services as they would be for real entries.
+func seedBackupUser(t *testing.T, db *gorm.DB, userID string) {
+	t.Helper()
+	accounts := NewAccountService(db, nil)
+	transactions := NewTransactionService(db, accounts, nil)
+	uid := models.UserID(userID)
+
+	parent := testutil.CreateTestCategory(t, db, userID, models.CategoryTypeExpense)
+	child := &models.Category{UserID: userID, Name: "Groceries", Type: models.CategoryTypeExpense, Icon: "🛒", ParentID: &parent.ID}
+	testutil.AssertNoError(t, db.Create(child).Error)
+	salary := testutil.CreateTestCategory(t, db, userID, models.CategoryTypeIncome)
+	budget := testutil.CreateTestBudget(t, db, userID, child.ID)
+	testutil.AssertNoError(t, db.Model(budget).Update("is_active", false).Error)
+
+	checking := testutil.CreateTestCashAccount(t, db, userID)
+	testutil.AssertNoError(t, db.Model(checking).Update("default_category_id", child.ID).Error)
+	savings := testutil.CreateTestCashAccount(t, db, userID)
+	testutil.AssertNoError(t, db.Model(savings).Updates(map[string]interface{}{"allow_negative": false, "min_balance": 5000}).Error)
+	card := testutil.CreateTestCreditCardAccount(t, db, userID, 0)
+
+	date := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	_, err := transactions.CreateTransaction(uid, models.AccountID(checking.ID), &salary.ID, models.TransactionTypeIncome,
+		500000, "Salary", date, false, "", nil)
+	testutil.AssertNoError(t, err)
+	_, err = transactions.CreateTransaction(uid, models.AccountID(card.ID), &child.ID, models.TransactionTypeExpense,
+		4200, "Market", date.AddDate(0, 0, 1), false, "", &OriginalAmount{Amount: 3900, Currency: "EUR"})
+	testutil.AssertNoError(t, err)
+	_, err = transactions.CreateTransaction(uid, models.AccountID(checking.ID), nil, models.TransactionTypeExpense,
+		1000, "Not yet settled", date.AddDate(0, 0, 2), true, "", nil)
+	testutil.AssertNoError(t, err)
+	_, err = transactions.CreateSplitTransfer(uid, models.AccountID(checking.ID), []SplitTransferLeg{
+		{ToAccountID: models.AccountID(savings.ID), Amount: 20000},
+		{ToAccountID: models.AccountID(card.ID), Amount: 4200},
+	}, "Month end", date.AddDate(0, 0, 3))
+	testutil.AssertNoError(t, err)
+
+	invAccount := testutil.CreateTestInvestmentAccount(t, db, userID)
+	sec := testutil.CreateTestSecurity(t, db)
+	inv := testutil.CreateTestInvestment(t, db, invAccount.ID, sec.ID)
+	cash, err := transactions.CreateTransaction(uid, models.AccountID(checking.ID), nil, models.TransactionTypeExpense,
+		100000, "Buy", date.AddDate(0, 0, 4), false, "", nil)
+	testutil.AssertNoError(t, err)
+	testutil.AssertNoError(t, db.Create(&models.InvestmentTransaction{InvestmentID: inv.ID, Type: models.InvestmentTransactionBuy,
+		Date: date.AddDate(0, 0, 4), Quantity: 10, PricePerUnit: 10000, TotalAmount: 100000, CashTransactionID: &cash.ID}).Error)
+}
+
+// assertSameBackup fails unless restored, with the restored IDs mapped back to
+// the archive's, holds the same records as original.
+func assertSameBackup(t *testing.T, db *gorm.DB, userID, archiveID string, original, restored *BackupArchive) {
+	t.Helper()
+	var mappings []models.RestoreIDMapping
+	testutil.AssertNoError(t, db.Where("user_id = ? AND archive_id = ?", userID, archiveID).Find(&mappings).Error)
+
+	raw, err := json.Marshal(restored.Data)
+	testutil.AssertNoError(t, err)
+	mapped := string(raw)
+	for _, m := range mappings {
+		mapped = strings.ReplaceAll(mapped, m.NewID, m.OldID)
+	}
+	var got BackupData
+	testutil.AssertNoError(t, json.Unmarshal([]byte(mapped), &got))
+	sortBackupData(&got)
+	want := original.Data
+	sortBackupData(&want)
+
+	gotJSON, _ := json.MarshalIndent(got, "", " ")
+	wantJSON, _ := json.MarshalIndent(want, "", " ")
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("restored backup differs from the original\nwant: %s\ngot:  %s", wantJSON, gotJSON)
+	}
+}
+
+// sortBackupData orders every section by ID; restored IDs do not keep the
+// archive's order.
+func sortBackupData(d *BackupData) {
+	sort.Slice(d.Categories, func(i, j int) bool { return d.Categories[i].ID < d.Categories[j].ID })
+	sort.Slice(d.Accounts, func(i, j int) bool { return d.Accounts[i].ID < d.Accounts[j].ID })
+	sort.Slice(d.Transactions, func(i, j int) bool { return d.Transactions[i].ID < d.Transactions[j].ID })
+	sort.Slice(d.Budgets, func(i, j int) bool { return d.Budgets[i].ID < d.Budgets[j].ID })
+	sort.Slice(d.Investments, func(i, j int) bool { return d.Investments[i].ID < d.Investments[j].ID })
+	sort.Slice(d.InvestmentTransactions, func(i, j int) bool {
+		return d.InvestmentTransactions[i].ID < d.InvestmentTransactions[j].ID
+	})
+}
+
+// groupStatuses returns the status of each group of report, in order.
+func groupStatuses(report *RestoreReport) []RestoreStatus {
+	statuses := make([]RestoreStatus, 0, len(report.Groups))
+	for _, g := range report.Groups {
+		statuses = append(statuses, g.Status)
+	}
+	return statuses
+}
+
+func TestCreateBackup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("contains_the_users_books", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewBackupService(db)
+		user := testutil.CreateTestUser(t, db)
+		seedBackupUser(t, db, user.ID)
+		other := testutil.CreateTestUser(t, db)
+		otherAccount := testutil.CreateTestCashAccount(t, db, other.ID)
+
+		archive, err := svc.CreateBackup(models.UserID(user.ID))
+		testutil.AssertNoError(t, err)
+
+		if archive.Format != backupFormat || archive.SchemaVersion != BackupSchemaVersion {
+			t.Errorf("expected a version %d archive, got %q version %d", BackupSchemaVersion, archive.Format, archive.SchemaVersion)
+		}
+		d := archive.Data
+		if len(d.Categories) != 3 || len(d.Accounts) != 4 || len(d.Transactions) != 6 || len(d.Budgets) != 1 ||
+			len(d.Investments) != 1 || len(d.InvestmentTransactions) != 1 {
+			t.Fatalf("expected 3 categories, 4 accounts, 6 transactions and one of the rest; got %d, %d, %d, %d, %d, %d",
+				len(d.Categories), len(d.Accounts), len(d.Transactions), len(d.Budgets), len(d.Investments), len(d.InvestmentTransactions))
+		}
+		for _, a := range d.Accounts {
+			if a.ID == otherAccount.ID {
+				t.Error("backup contains another user's account")
+			}
+		}
+		if len(archive.Checksums) != len(restoreGroups) {
+			t.Errorf("expected a checksum per section, got %v", archive.Checksums)
+		}
+		if _, err := archive.verify(); err != nil {
+			t.Errorf("expected the archive to verify, got %v", err)
+		}
+	})
+
+	t.Run("unknown_user", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		_, err := NewBackupService(db).CreateBackup(models.UserID(uuid.New()))
+		testutil.AssertAppError(t, err, "USER_NOT_FOUND")
+	})
+}
+
+func TestRestoreBackup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round_trips_into_a_fresh_account", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewBackupService(db)
+		user := testutil.CreateTestUser(t, db)
+		seedBackupUser(t, db, user.ID)
+		// A balance no transaction explains is reconciled rather than replayed
+		testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 7500)
+
+		original, err := svc.CreateBackup(models.UserID(user.ID))
+		testutil.AssertNoError(t, err)
+		// Restore what a client would upload
+		raw, err := json.Marshal(original)
+		testutil.AssertNoError(t, err)
+		var uploaded BackupArchive
+		testutil.AssertNoError(t, json.Unmarshal(raw, &uploaded))
+
+		fresh := testutil.CreateTestUser(t, db)
+		report, err := svc.RestoreBackup(models.UserID(fresh.ID), &uploaded, false)
+		testutil.AssertNoError(t, err)
+
+		if !report.Completed {
+			t.Fatalf("expected a completed restore, got %+v", report.Groups)
+		}
+		for _, g := range report.Groups {
+			if g.Status != RestoreStatusDone {
+				t.Errorf("expected group %s done, got %s (%s)", g.Group, g.Status, g.Error)
+			}
+			if g.Group == restoreGroupTransactions && (g.Restored != 6 || g.BalancesAdjusted != 1) {
+				t.Errorf("expected 6 transactions with 1 balance adjusted, got %d and %d", g.Restored, g.BalancesAdjusted)
+			}
+		}
+
+		restored, err := svc.CreateBackup(models.UserID(fresh.ID))
+		testutil.AssertNoError(t, err)
+		assertSameBackup(t, db, fresh.ID, report.ArchiveID, original, restored)
+	})
+
+	t.Run("restores_older_archives_without_overdraft_protection", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewBackupService(db)
+		user := testutil.CreateTestUser(t, db)
+		seedBackupUser(t, db, user.ID)
+		archive, err := svc.CreateBackup(models.UserID(user.ID))
+		testutil.AssertNoError(t, err)
+		for i := range archive.Data.Accounts {
+			archive.Data.Accounts[i].AllowNegative = nil
+			archive.Data.Accounts[i].MinBalance = 0
+		}
+		archive.Checksums, err = archive.Data.checksums()
+		testutil.AssertNoError(t, err)
+
+		fresh := testutil.CreateTestUser(t, db)
+		report, err := svc.RestoreBackup(models.UserID(fresh.ID), archive, false)
+		testutil.AssertNoError(t, err)
+		if !report.Completed {
+			t.Fatalf("expected a completed restore, got %+v", report.Groups)
+		}
+
+		var protected int64
+		testutil.AssertNoError(t, db.Model(&models.Account{}).
+			Where("user_id = ? AND allow_negative = ?", fresh.ID, false).Count(&protected).Error)
+		if protected != 0 {
+			t.Errorf("expected no protected accounts, got %d", protected)
+		}
+	})
+
+	t.Run("refuses_an_account_with_data", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewBackupService(db)
+		user := testutil.CreateTestUser(t, db)
+		seedBackupUser(t, db, user.ID)
+		archive, err := svc.CreateBackup(models.UserID(user.ID))
+		testutil.AssertNoError(t, err)
+
+		target := testutil.CreateTestUser(t, db)
+		testutil.CreateTestCategory(t, db, target.ID, models.CategoryTypeExpense)
+
+		_, err = svc.RestoreBackup(models.UserID(target.ID), archive, false)
+		testutil.AssertAppError(t, err, "RESTORE_TARGET_NOT_EMPTY")
+	})
+
+	t.Run("replaces_existing_data_when_confirmed", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewBackupService(db)
+		user := testutil.CreateTestUser(t, db)
+		seedBackupUser(t, db, user.ID)
+		original, err := svc.CreateBackup(models.UserID(user.ID))
+		testutil.AssertNoError(t, err)
+
+		// Restoring over the user's own books replaces them with the backup
+		report, err := svc.RestoreBackup(models.UserID(user.ID), original, true)
+		testutil.AssertNoError(t, err)
+		if !report.Completed {
+			t.Fatalf("expected a completed restore, got %+v", report.Groups)
+		}
+
+		var accounts int64
+		testutil.AssertNoError(t, db.Model(&models.Account{}).Where("user_id = ?", user.ID).Count(&accounts).Error)
+		if accounts != int64(len(original.Data.Accounts)) {
+			t.Errorf("expected the %d restored accounts only, got %d", len(original.Data.Accounts), accounts)
+		}
+		restored, err := svc.CreateBackup(models.UserID(user.ID))
+		testutil.AssertNoError(t, err)
+		assertSameBackup(t, db, user.ID, report.ArchiveID, original, restored)
+	})
+
+	t.Run("resumes_after_a_failed_group", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewBackupService(db)
+		user := testutil.CreateTestUser(t, db)
+		seedBackupUser(t, db, user.ID)
+		archive, err := svc.CreateBackup(models.UserID(user.ID))
+		testutil.AssertNoError(t, err)
+
+		// A holding of a security this server does not know fails the investments group
+		archive.Data.Investments[0].SecurityID = uuid.New()
+		archive.Data.Investments[0].SecuritySymbol = "MISSING"
+		archive.Checksums, err = archive.Data.checksums()
+		testutil.AssertNoError(t, err)
+
+		fresh := testutil.CreateTestUser(t, db)
+		report, err := svc.RestoreBackup(models.UserID(fresh.ID), archive, false)
+		testutil.AssertNoError(t, err)
+
+		want := []RestoreStatus{RestoreStatusDone, RestoreStatusDone, RestoreStatusDone, RestoreStatusDone, RestoreStatusFailed, RestoreStatusPending}
+		if got := groupStatuses(report); report.Completed || !reflect.DeepEqual(got, want) {
+			t.Fatalf("expected statuses %v of an incomplete restore, got %v", want, got)
+		}
+		if !strings.Contains(report.Groups[4].Error, "MISSING") {
+			t.Errorf("expected the error to name the security, got %q", report.Groups[4].Error)
+		}
+		var investments int64
+		testutil.AssertNoError(t, db.Model(&models.Investment{}).
+			Where("account_id IN (?)", db.Model(&models.Account{}).Select("id").Where("user_id = ?", fresh.ID)).
+			Count(&investments).Error)
+		if investments != 0 {
+			t.Errorf("expected the failed group rolled back, got %d investments", investments)
+		}
+
+		testutil.CreateTestSecurityWithParams(t, db, "MISSING", "Now listed", models.AssetTypeStock, archive.Data.Investments[0].SecurityExchange)
+		resumed, err := svc.RestoreBackup(models.UserID(fresh.ID), archive, false)
+		testutil.AssertNoError(t, err)
+
+		want = []RestoreStatus{RestoreStatusSkipped, RestoreStatusSkipped, RestoreStatusSkipped, RestoreStatusSkipped, RestoreStatusDone, RestoreStatusDone}
+		if got := groupStatuses(resumed); !resumed.Completed || !reflect.DeepEqual(got, want) {
+			t.Fatalf("expected statuses %v of a completed restore, got %v", want, got)
+		}
+		if resumed.Groups[0].Restored != len(archive.Data.Categories) {
+			t.Errorf("expected the skipped group to report %d restored, got %d", len(archive.Data.Categories), resumed.Groups[0].Restored)
+		}
+		var categories int64
+		testutil.AssertNoError(t, db.Model(&models.Category{}).Where("user_id = ?", fresh.ID).Count(&categories).Error)
+		if categories != int64(len(archive.Data.Categories)) {
+			t.Errorf("expected %d categories after resuming, got %d", len(archive.Data.Categories), categories)
+		}
+		var linked models.InvestmentTransaction
+		testutil.AssertNoError(t, db.Joins("JOIN investments ON investments.id = investment_transactions.investment_id").
+			Where("investments.account_id IN (?)", db.Model(&models.Account{}).Select("id").Where("user_id = ?", fresh.ID)).
+			First(&linked).Error)
+		if linked.CashTransactionID == nil || *linked.CashTransactionID == *archive.Data.InvestmentTransactions[0].CashTransactionID {
+			t.Errorf("expected the cash transaction remapped from a group restored earlier, got %v", linked.CashTransactionID)
+		}
+	})
+
+	t.Run("rejects_a_modified_archive", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewBackupService(db)
+		user := testutil.CreateTestUser(t, db)
+		seedBackupUser(t, db, user.ID)
+		archive, err := svc.CreateBackup(models.UserID(user.ID))
+		testutil.AssertNoError(t, err)
+		fresh := testutil.CreateTestUser(t, db)
+
+		archive.Data.Transactions[0].Amount++
+		_, err = svc.RestoreBackup(models.UserID(fresh.ID), archive, false)
+		testutil.AssertAppError(t, err, "INVALID_BACKUP")
+	})
+
+	t.Run("rejects_a_newer_schema_version", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewBackupService(db)
+		user := testutil.CreateTestUser(t, db)
+		archive, err := svc.CreateBackup(models.UserID(user.ID))
+		testutil.AssertNoError(t, err)
+
+		archive.SchemaVersion = BackupSchemaVersion + 1
+		_, err = svc.RestoreBackup(models.UserID(user.ID), archive, false)
+		testutil.AssertAppError(t, err, "INVALID_BACKUP")
+	})
+}
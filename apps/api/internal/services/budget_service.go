@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -13,12 +14,13 @@ import (
 
 // budgetService handles budget-related business logic.
 type budgetService struct {
-	db *gorm.DB
+	db    *gorm.DB
+	clock Clock
 }
 
 // NewBudgetService creates a new BudgetServicer.
 func NewBudgetService(db *gorm.DB) BudgetServicer {
-	return &budgetService{db: db}
+	return &budgetService{db: db, clock: SystemClock}
 }
 
 // CreateBudget creates a new budget for a category.
@@ -75,7 +77,7 @@ func (s *budgetService) GetUserBudgets(
 	}
 
 	var totalItems int64
-	if err := base.Count(&totalItems).Error; err != nil {
+	if err := pagination.Count(base, page, &totalItems); err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
 
@@ -150,48 +152,392 @@ func (s *budgetService) DeleteBudget(userID, budgetID string) error {
 }
 
 // GetBudgetProgress calculates spending vs budget for the current period.
-func (s *budgetService) GetBudgetProgress(userID, budgetID string) (*BudgetProgress, error) {
+// Pending (not yet settled) expenses count towards spend only when includePending is set.
+func (s *budgetService) GetBudgetProgress(userID, budgetID string, includePending bool) (*BudgetProgress, error) {
 	budget, err := s.GetBudgetByID(userID, budgetID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Determine current period window
-	now := time.Now()
-	var periodStart, periodEnd time.Time
+	now := reportingNow(s.clock)
+	periodStart, periodEnd := budgetWindow(budget, now)
+
+	// Sum expense transactions for this category within the period
+	spent, err := categorySpend(s.db, userID, budget.CategoryID, periodStart, periodEnd, includePending)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBudgetProgress(budget, spent, periodStart, periodEnd, now), nil
+}
+
+// budgetPace projects spent, the spend from periodStart up to now, to the
+// whole period at the same average daily rate. Days are calendar days in
+// periodStart's location and today counts as elapsed, so the first day of a
+// period projects from that day's spend alone. Before the period starts no
+// days have elapsed and nothing is projected beyond what is already spent;
+// after it ends the projection is the spend itself.
+func budgetPace(periodStart, periodEnd, now time.Time, budgeted, spent int64) BudgetPace {
+	loc := periodStart.Location()
+	day := func(t time.Time) time.Time {
+		t = t.In(loc)
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	daysBetween := func(from, to time.Time) int {
+		return int(day(to).Sub(day(from)).Hours() / 24)
+	}
+
+	pace := BudgetPace{DaysInPeriod: daysBetween(periodStart, periodEnd) + 1}
+	switch {
+	case now.After(periodEnd):
+		pace.DaysElapsed = pace.DaysInPeriod
+	case !now.Before(periodStart):
+		pace.DaysElapsed = daysBetween(periodStart, now) + 1
+	}
+
+	pace.ProjectedSpend = spent
+	if pace.DaysElapsed > 0 {
+		pace.ProjectedSpend = spent * int64(pace.DaysInPeriod) / int64(pace.DaysElapsed)
+	}
+
+	switch {
+	case spent > budgeted:
+		pace.Status = BudgetPaceOver
+	case pace.ProjectedSpend > budgeted:
+		pace.Status = BudgetPaceAtRisk
+	default:
+		pace.Status = BudgetPaceOnTrack
+	}
+	return pace
+}
+
+// newBudgetProgress compares spent between periodStart and periodEnd against
+// the budget's amount, with the pace of that spend as of now.
+func newBudgetProgress(budget *models.Budget, spent int64, periodStart, periodEnd, now time.Time) *BudgetProgress {
+	var percentage float64
+	if budget.Amount > 0 {
+		percentage = float64(spent) / float64(budget.Amount) * 100
+	}
+	return &BudgetProgress{
+		BudgetID:    budget.ID,
+		Budgeted:    budget.Amount,
+		Spent:       spent,
+		Remaining:   budget.Amount - spent,
+		Percentage:  percentage,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		Pace:        budgetPace(periodStart, periodEnd, now, budget.Amount, spent),
+	}
+}
+
+// GetBudgetsProgress calculates the current-period progress of each of the
+// user's budgets, keyed by budget ID, with the same windows and spend rules
+// as GetBudgetProgress. A single grouped query sums the spend of all of them.
+func (s *budgetService) GetBudgetsProgress(userID string, budgets []models.Budget, includePending bool) (map[string]*BudgetProgress, error) {
+	progress := make(map[string]*BudgetProgress, len(budgets))
+	if len(budgets) == 0 {
+		return progress, nil
+	}
+
+	// Each budget has its own window, so match every transaction against the
+	// windows of the budgets on its category
+	now := reportingNow(s.clock)
+	windows := make([][2]time.Time, len(budgets))
+	matches := make([]string, len(budgets))
+	args := make([]interface{}, 0, 3*len(budgets))
+	for i := range budgets {
+		start, end := budgetWindow(&budgets[i], now)
+		windows[i] = [2]time.Time{start, end}
+		matches[i] = "(b.id = ? AND transactions.date BETWEEN ? AND ?)"
+		args = append(args, budgets[i].ID, start.UTC(), end.UTC())
+	}
+
+	query := s.db.Model(&models.Transaction{}).
+		Select("b.id AS budget_id, COALESCE(SUM(transactions.amount), 0) AS spent").
+		Joins("JOIN budgets b ON b.category_id = transactions.category_id AND b.user_id = transactions.user_id").
+		Where("transactions.user_id = ? AND transactions.type = ?", userID, models.TransactionTypeExpense).
+		Where("("+strings.Join(matches, " OR ")+")", args...).
+		Group("b.id")
+	if !includePending {
+		query = query.Where("transactions.is_pending = ?", false)
+	}
+	var rows []struct {
+		BudgetID string
+		Spent    int64
+	}
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	spent := make(map[string]int64, len(rows))
+	for _, r := range rows {
+		spent[r.BudgetID] = r.Spent
+	}
+	for i := range budgets {
+		progress[budgets[i].ID] = newBudgetProgress(&budgets[i], spent[budgets[i].ID], windows[i][0], windows[i][1], now)
+	}
+	return progress, nil
+}
+
+// GetBudgetBurndown returns the budget's settled spend per day of its current
+// period up to today, cumulated, and projects the spend for the whole period
+// by extending the average daily spend so far to every day of the period.
+func (s *budgetService) GetBudgetBurndown(userID, budgetID string) (*BudgetBurndown, error) {
+	return s.budgetBurndownAt(userID, budgetID, reportingNow(s.clock))
+}
+
+// budgetBurndownAt computes the burndown as of now.
+func (s *budgetService) budgetBurndownAt(userID, budgetID string, now time.Time) (*BudgetBurndown, error) {
+	budget, err := s.GetBudgetByID(userID, budgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	periodStart, periodEnd := budgetWindow(budget, now)
+	var expenses []models.Transaction
+	if err := categoryExpenses(s.db, userID, budget.CategoryID, periodStart, periodEnd, false).
+		Select("date", "amount").Find(&expenses).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	loc := periodStart.Location()
+	day := func(t time.Time) time.Time {
+		t = t.In(loc)
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	}
+	firstDay, lastDay := day(periodStart), day(periodEnd)
+	perDay := make(map[time.Time]int64)
+	for _, e := range expenses {
+		perDay[day(e.Date)] += e.Amount
+	}
+
+	burndown := &BudgetBurndown{
+		BudgetID:    budget.ID,
+		Budgeted:    budget.Amount,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		Series:      []BudgetBurndownPoint{},
+	}
+	for d := firstDay; !d.After(lastDay); d = d.AddDate(0, 0, 1) {
+		burndown.DaysInPeriod++
+		if d.After(now) {
+			continue
+		}
+		burndown.DaysElapsed++
+		burndown.Spent += perDay[d]
+		burndown.Series = append(burndown.Series, BudgetBurndownPoint{Date: d, Spent: perDay[d], Cumulative: burndown.Spent})
+	}
 
-	switch budget.Period {
+	burndown.ProjectedSpend = burndown.Spent
+	if burndown.DaysElapsed > 0 {
+		burndown.ProjectedSpend = burndown.Spent * int64(burndown.DaysInPeriod) / int64(burndown.DaysElapsed)
+	}
+	return burndown, nil
+}
+
+// categorySpend sums the user's expenses in a category dated between from and
+// to inclusive. Pending expenses count only when includePending is set.
+func categorySpend(db *gorm.DB, userID, categoryID string, from, to time.Time, includePending bool) (int64, error) {
+	var spent int64
+	query := categoryExpenses(db, userID, categoryID, from, to, includePending).
+		Select("COALESCE(SUM(amount), 0)")
+	if err := query.Scan(&spent).Error; err != nil {
+		return 0, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return spent, nil
+}
+
+// categoryExpenses scopes a transaction query to the expenses categorySpend sums.
+func categoryExpenses(db *gorm.DB, userID, categoryID string, from, to time.Time, includePending bool) *gorm.DB {
+	query := db.Model(&models.Transaction{}).
+		Where("user_id = ? AND category_id = ? AND type = ? AND date BETWEEN ? AND ?",
+			userID, categoryID, models.TransactionTypeExpense, from.UTC(), to.UTC())
+	if !includePending {
+		query = query.Where("is_pending = ?", false)
+	}
+	return query
+}
+
+// budgetPeriodBounds returns the first and last instants of the budget period containing t.
+func budgetPeriodBounds(period models.BudgetPeriod, t time.Time) (time.Time, time.Time) {
+	var periodStart, periodEnd time.Time
+	switch period {
 	case models.BudgetPeriodMonthly:
-		periodStart = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		periodStart = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
 		periodEnd = periodStart.AddDate(0, 1, -1)
-		periodEnd = time.Date(periodEnd.Year(), periodEnd.Month(), periodEnd.Day(), 23, 59, 59, 999999999, now.Location())
+		periodEnd = time.Date(periodEnd.Year(), periodEnd.Month(), periodEnd.Day(), 23, 59, 59, 999999999, t.Location())
 	case models.BudgetPeriodYearly:
-		periodStart = time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
-		periodEnd = time.Date(now.Year(), 12, 31, 23, 59, 59, 999999999, now.Location())
+		periodStart = time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+		periodEnd = time.Date(t.Year(), 12, 31, 23, 59, 59, 999999999, t.Location())
 	}
+	return periodStart, periodEnd
+}
 
-	// Sum expense transactions for this category within the period
-	var spent int64
-	err = s.db.Model(&models.Transaction{}).
-		Select("COALESCE(SUM(amount), 0)").
-		Where("user_id = ? AND category_id = ? AND type = ? AND date BETWEEN ? AND ?",
-			userID, budget.CategoryID, models.TransactionTypeExpense, periodStart, periodEnd).
-		Scan(&spent).Error
+// budgetWindow returns the part of budget's period containing t that the
+// budget covers: the calendar month or year, narrowed to its start and end
+// dates. A t outside those dates selects the budget's first or last period.
+func budgetWindow(budget *models.Budget, t time.Time) (time.Time, time.Time) {
+	if t.Before(budget.StartDate) {
+		t = budget.StartDate
+	}
+	if budget.EndDate != nil && t.After(*budget.EndDate) {
+		t = *budget.EndDate
+	}
+
+	periodStart, periodEnd := budgetPeriodBounds(budget.Period, t)
+	if budget.StartDate.After(periodStart) {
+		periodStart = budget.StartDate
+	}
+	if budget.EndDate != nil && budget.EndDate.Before(periodEnd) {
+		periodEnd = *budget.EndDate
+	}
+	return periodStart, periodEnd
+}
+
+// budgetsCrossedBy returns alert data for the user's active budgets on the
+// transaction's category that the settled expense pushed over their amount.
+// Budgets that were already over before the expense are skipped, so each
+// overrun is reported once per period.
+func budgetsCrossedBy(db *gorm.DB, transaction *models.Transaction, currency string) ([]BudgetAlertData, error) {
+	if transaction.Type != models.TransactionTypeExpense || transaction.CategoryID == nil || transaction.IsPending {
+		return nil, nil
+	}
+
+	var budgets []models.Budget
+	err := db.Preload("Category").
+		Where("user_id = ? AND category_id = ? AND is_active = ? AND start_date <= ? AND (end_date IS NULL OR end_date >= ?)",
+			transaction.UserID, *transaction.CategoryID, true, transaction.Date, transaction.Date).
+		Find(&budgets).Error
 	if err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
 
-	remaining := budget.Amount - spent
-	var percentage float64
-	if budget.Amount > 0 {
-		percentage = float64(spent) / float64(budget.Amount) * 100
+	var alerts []BudgetAlertData
+	for _, budget := range budgets {
+		periodStart, periodEnd := budgetWindow(&budget, transaction.Date)
+		spent, err := categorySpend(db, transaction.UserID, budget.CategoryID, periodStart, periodEnd, false)
+		if err != nil {
+			return nil, err
+		}
+		if spent <= budget.Amount || spent-transaction.Amount > budget.Amount {
+			continue
+		}
+		alerts = append(alerts, BudgetAlertData{
+			BudgetID:     budget.ID,
+			BudgetName:   budget.Name,
+			CategoryName: budget.Category.Name,
+			Period:       budget.Period,
+			Budgeted:     budget.Amount,
+			Spent:        spent,
+			Currency:     currency,
+		})
 	}
+	return alerts, nil
+}
 
-	return &BudgetProgress{
-		BudgetID:   budget.ID,
-		Budgeted:   budget.Amount,
-		Spent:      spent,
-		Remaining:  remaining,
-		Percentage: percentage,
-	}, nil
+// monthStart returns midnight UTC on the first day of t's month.
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// CloneForPeriod copies the user's active monthly budgets that start in
+// sourceMonth into targetMonth, using each budget's current amount. Categories
+// that already have a monthly budget starting in targetMonth are skipped, so
+// repeated calls never create duplicates. Returns the budgets created.
+func (s *budgetService) CloneForPeriod(userID string, sourceMonth, targetMonth time.Time) ([]models.Budget, error) {
+	sourceStart, targetStart := monthStart(sourceMonth), monthStart(targetMonth)
+	if sourceStart.Equal(targetStart) {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "source and target months must differ")
+	}
+	sourceEnd, targetEnd := sourceStart.AddDate(0, 1, 0), targetStart.AddDate(0, 1, 0)
+
+	var created []models.Budget
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var sources []models.Budget
+		if err := tx.Where("user_id = ? AND period = ? AND is_active = ? AND start_date >= ? AND start_date < ?",
+			userID, models.BudgetPeriodMonthly, true, sourceStart, sourceEnd).
+			Order("start_date ASC, created_at ASC").
+			Find(&sources).Error; err != nil {
+			return err
+		}
+		if len(sources) == 0 {
+			return nil
+		}
+
+		var existing []string
+		if err := tx.Model(&models.Budget{}).
+			Where("user_id = ? AND period = ? AND start_date >= ? AND start_date < ?",
+				userID, models.BudgetPeriodMonthly, targetStart, targetEnd).
+			Pluck("category_id", &existing).Error; err != nil {
+			return err
+		}
+		covered := make(map[string]bool, len(existing))
+		for _, categoryID := range existing {
+			covered[categoryID] = true
+		}
+
+		// A budget with an end date is bounded to the target month; an open-ended one stays open.
+		lastInstant := targetEnd.Add(-time.Nanosecond)
+		for _, source := range sources {
+			if covered[source.CategoryID] {
+				continue
+			}
+			budget := models.Budget{
+				UserID:     userID,
+				CategoryID: source.CategoryID,
+				Name:       source.Name,
+				Amount:     source.Amount,
+				Period:     models.BudgetPeriodMonthly,
+				StartDate:  targetStart,
+				IsActive:   true,
+			}
+			if source.EndDate != nil {
+				budget.EndDate = &lastInstant
+			}
+			if err := tx.Create(&budget).Error; err != nil {
+				return err
+			}
+			covered[source.CategoryID] = true
+			created = append(created, budget)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	if created == nil {
+		created = []models.Budget{}
+	}
+	return created, nil
+}
+
+// RolloverMonthlyBudgets clones every user's monthly budgets from the month
+// before asOf into asOf's month. It is safe to run repeatedly; budgets that
+// already exist for the new month are left untouched. Returns the number of
+// budgets created.
+func (s *budgetService) RolloverMonthlyBudgets(asOf time.Time) (int, error) {
+	targetStart := monthStart(asOf)
+	sourceStart := targetStart.AddDate(0, -1, 0)
+
+	var userIDs []string
+	if err := s.db.Model(&models.Budget{}).
+		Where("period = ? AND is_active = ? AND start_date >= ? AND start_date < ?",
+			models.BudgetPeriodMonthly, true, sourceStart, targetStart).
+		Distinct().
+		Pluck("user_id", &userIDs).Error; err != nil {
+		return 0, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	total := 0
+	for _, userID := range userIDs {
+		created, err := s.CloneForPeriod(userID, sourceStart, targetStart)
+		if err != nil {
+			return total, err
+		}
+		total += len(created)
+	}
+	return total, nil
 }
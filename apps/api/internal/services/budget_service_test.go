@@ -4,15 +4,18 @@ import (
 	"testing"
 	"time"
 
+	"gorm.io/gorm"
+
 	"kuberan/internal/models"
 	"kuberan/internal/pagination"
 	"kuberan/internal/testutil"
+	"kuberan/internal/uuid"
 )
 
 func TestCreateBudget(t *testing.T) {
+	t.Parallel()
 	t.Run("valid", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user := testutil.CreateTestUser(t, db)
 		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
@@ -20,8 +23,8 @@ func TestCreateBudget(t *testing.T) {
 		budget, err := svc.CreateBudget(user.ID, cat.ID, "Groceries", 50000, models.BudgetPeriodMonthly, time.Now(), nil)
 		testutil.AssertNoError(t, err)
 
-		if budget.ID == 0 {
-			t.Fatal("expected non-zero budget ID")
+		if budget.ID == "" {
+			t.Fatal("expected non-empty budget ID")
 		}
 		if budget.Name != "Groceries" {
 			t.Errorf("expected name Groceries, got %s", budget.Name)
@@ -38,8 +41,7 @@ func TestCreateBudget(t *testing.T) {
 	})
 
 	t.Run("with_end_date", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user := testutil.CreateTestUser(t, db)
 		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
@@ -54,18 +56,16 @@ func TestCreateBudget(t *testing.T) {
 	})
 
 	t.Run("invalid_category", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user := testutil.CreateTestUser(t, db)
 
-		_, err := svc.CreateBudget(user.ID, 9999, "Bad", 50000, models.BudgetPeriodMonthly, time.Now(), nil)
+		_, err := svc.CreateBudget(user.ID, uuid.New(), "Bad", 50000, models.BudgetPeriodMonthly, time.Now(), nil)
 		testutil.AssertAppError(t, err, "CATEGORY_NOT_FOUND")
 	})
 
 	t.Run("wrong_user_category", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user1 := testutil.CreateTestUser(t, db)
 		user2 := testutil.CreateTestUser(t, db)
@@ -77,9 +77,9 @@ func TestCreateBudget(t *testing.T) {
 }
 
 func TestGetUserBudgets(t *testing.T) {
+	t.Parallel()
 	t.Run("returns_user_budgets_only", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user1 := testutil.CreateTestUser(t, db)
 		user2 := testutil.CreateTestUser(t, db)
@@ -100,8 +100,7 @@ func TestGetUserBudgets(t *testing.T) {
 	})
 
 	t.Run("filter_by_is_active", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user := testutil.CreateTestUser(t, db)
 		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
@@ -124,8 +123,7 @@ func TestGetUserBudgets(t *testing.T) {
 	})
 
 	t.Run("filter_by_period", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user := testutil.CreateTestUser(t, db)
 		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
@@ -159,8 +157,7 @@ func TestGetUserBudgets(t *testing.T) {
 	})
 
 	t.Run("pagination", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user := testutil.CreateTestUser(t, db)
 		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
@@ -186,9 +183,9 @@ func TestGetUserBudgets(t *testing.T) {
 }
 
 func TestGetBudgetByID(t *testing.T) {
+	t.Parallel()
 	t.Run("found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user := testutil.CreateTestUser(t, db)
 		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
@@ -198,23 +195,21 @@ func TestGetBudgetByID(t *testing.T) {
 		testutil.AssertNoError(t, err)
 
 		if found.ID != budget.ID {
-			t.Errorf("expected budget ID %d, got %d", budget.ID, found.ID)
+			t.Errorf("expected budget ID %s, got %s", budget.ID, found.ID)
 		}
 	})
 
 	t.Run("not_found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user := testutil.CreateTestUser(t, db)
 
-		_, err := svc.GetBudgetByID(user.ID, 9999)
+		_, err := svc.GetBudgetByID(user.ID, uuid.New())
 		testutil.AssertAppError(t, err, "BUDGET_NOT_FOUND")
 	})
 
 	t.Run("wrong_user", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user1 := testutil.CreateTestUser(t, db)
 		user2 := testutil.CreateTestUser(t, db)
@@ -227,9 +222,9 @@ func TestGetBudgetByID(t *testing.T) {
 }
 
 func TestUpdateBudget(t *testing.T) {
+	t.Parallel()
 	t.Run("update_name", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user := testutil.CreateTestUser(t, db)
 		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
@@ -244,8 +239,7 @@ func TestUpdateBudget(t *testing.T) {
 	})
 
 	t.Run("update_amount", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user := testutil.CreateTestUser(t, db)
 		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
@@ -264,8 +258,7 @@ func TestUpdateBudget(t *testing.T) {
 	})
 
 	t.Run("update_period", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user := testutil.CreateTestUser(t, db)
 		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
@@ -283,20 +276,19 @@ func TestUpdateBudget(t *testing.T) {
 	})
 
 	t.Run("not_found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user := testutil.CreateTestUser(t, db)
 
-		_, err := svc.UpdateBudget(user.ID, 9999, "Nope", nil, nil, nil)
+		_, err := svc.UpdateBudget(user.ID, uuid.New(), "Nope", nil, nil, nil)
 		testutil.AssertAppError(t, err, "BUDGET_NOT_FOUND")
 	})
 }
 
 func TestDeleteBudget(t *testing.T) {
+	t.Parallel()
 	t.Run("valid", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user := testutil.CreateTestUser(t, db)
 		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
@@ -318,18 +310,16 @@ func TestDeleteBudget(t *testing.T) {
 	})
 
 	t.Run("not_found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user := testutil.CreateTestUser(t, db)
 
-		err := svc.DeleteBudget(user.ID, 9999)
+		err := svc.DeleteBudget(user.ID, uuid.New())
 		testutil.AssertAppError(t, err, "BUDGET_NOT_FOUND")
 	})
 
 	t.Run("wrong_user", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user1 := testutil.CreateTestUser(t, db)
 		user2 := testutil.CreateTestUser(t, db)
@@ -342,19 +332,88 @@ func TestDeleteBudget(t *testing.T) {
 }
 
 func TestGetBudgetProgress(t *testing.T) {
+	t.Parallel()
+	t.Run("pending_spend_flag", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewBudgetService(db)
+		user := testutil.CreateTestUser(t, db)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		budget := testutil.CreateTestBudget(t, db, user.ID, cat.ID) // $100
+
+		catID := cat.ID
+		for _, tx := range []*models.Transaction{
+			{UserID: user.ID, AccountID: account.ID, CategoryID: &catID, Type: models.TransactionTypeExpense, Amount: 3000, Date: time.Now()},
+			{UserID: user.ID, AccountID: account.ID, CategoryID: &catID, Type: models.TransactionTypeExpense, Amount: 4000, Date: time.Now(), IsPending: true},
+		} {
+			if err := db.Create(tx).Error; err != nil {
+				t.Fatalf("failed to create transaction: %v", err)
+			}
+		}
+
+		progress, err := svc.GetBudgetProgress(user.ID, budget.ID, true)
+		testutil.AssertNoError(t, err)
+		if progress.Spent != 7000 {
+			t.Errorf("expected spent 7000 including pending, got %d", progress.Spent)
+		}
+		if progress.Pace.DaysElapsed == 0 || progress.Pace.ProjectedSpend < progress.Spent {
+			t.Errorf("expected the current period's pace, got %+v", progress.Pace)
+		}
+
+		progress, err = svc.GetBudgetProgress(user.ID, budget.ID, false)
+		testutil.AssertNoError(t, err)
+		if progress.Spent != 3000 {
+			t.Errorf("expected spent 3000 excluding pending, got %d", progress.Spent)
+		}
+	})
+
+	t.Run("period_ignores_the_machine_zone", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewBudgetService(db).(*budgetService)
+		user := testutil.CreateTestUser(t, db)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		budget := testutil.CreateTestBudget(t, db, user.ID, cat.ID)
+		testutil.AssertNoError(t, db.Model(budget).Update("start_date", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)).Error)
+
+		catID := cat.ID
+		for _, tx := range []*models.Transaction{
+			{UserID: user.ID, AccountID: account.ID, CategoryID: &catID, Type: models.TransactionTypeExpense, Amount: 3000, Date: time.Date(2026, 2, 28, 23, 0, 0, 0, time.UTC)},
+			{UserID: user.ID, AccountID: account.ID, CategoryID: &catID, Type: models.TransactionTypeExpense, Amount: 4000, Date: time.Date(2026, 3, 31, 19, 0, 0, 0, time.UTC)},
+		} {
+			testutil.AssertNoError(t, db.Create(tx).Error)
+		}
+
+		wantStart := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+		wantEnd := time.Date(2026, 3, 31, 23, 59, 59, 999999999, time.UTC)
+		instant := time.Date(2026, 3, 31, 20, 0, 0, 0, time.UTC)
+		for _, zone := range []*time.Location{time.UTC, kiritimati, honolulu} {
+			svc.clock = fixedClock{now: instant.In(zone), loc: time.UTC}
+
+			progress, err := svc.GetBudgetProgress(user.ID, budget.ID, false)
+			testutil.AssertNoError(t, err)
+
+			if !progress.PeriodStart.Equal(wantStart) || !progress.PeriodEnd.Equal(wantEnd) {
+				t.Errorf("machine zone %s: expected March, got %v to %v", zone, progress.PeriodStart, progress.PeriodEnd)
+			}
+			if progress.Spent != 4000 || progress.Pace.DaysElapsed != 31 {
+				t.Errorf("machine zone %s: expected 4000 spent over 31 days, got %d over %d", zone, progress.Spent, progress.Pace.DaysElapsed)
+			}
+		}
+	})
+
 	t.Run("no_spending", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user := testutil.CreateTestUser(t, db)
 		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
 		budget := testutil.CreateTestBudget(t, db, user.ID, cat.ID) // $100
 
-		progress, err := svc.GetBudgetProgress(user.ID, budget.ID)
+		progress, err := svc.GetBudgetProgress(user.ID, budget.ID, true)
 		testutil.AssertNoError(t, err)
 
 		if progress.BudgetID != budget.ID {
-			t.Errorf("expected budget ID %d, got %d", budget.ID, progress.BudgetID)
+			t.Errorf("expected budget ID %s, got %s", budget.ID, progress.BudgetID)
 		}
 		if progress.Budgeted != 10000 {
 			t.Errorf("expected budgeted 10000, got %d", progress.Budgeted)
@@ -371,8 +430,7 @@ func TestGetBudgetProgress(t *testing.T) {
 	})
 
 	t.Run("partial_spending", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user := testutil.CreateTestUser(t, db)
 		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
@@ -404,7 +462,7 @@ func TestGetBudgetProgress(t *testing.T) {
 			t.Fatalf("failed to create tx2: %v", err)
 		}
 
-		progress, err := svc.GetBudgetProgress(user.ID, budget.ID)
+		progress, err := svc.GetBudgetProgress(user.ID, budget.ID, true)
 		testutil.AssertNoError(t, err)
 
 		if progress.Spent != 5000 {
@@ -419,8 +477,7 @@ func TestGetBudgetProgress(t *testing.T) {
 	})
 
 	t.Run("over_budget", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user := testutil.CreateTestUser(t, db)
 		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
@@ -440,7 +497,7 @@ func TestGetBudgetProgress(t *testing.T) {
 			t.Fatalf("failed to create tx: %v", err)
 		}
 
-		progress, err := svc.GetBudgetProgress(user.ID, budget.ID)
+		progress, err := svc.GetBudgetProgress(user.ID, budget.ID, true)
 		testutil.AssertNoError(t, err)
 
 		if progress.Spent != 15000 {
@@ -455,8 +512,7 @@ func TestGetBudgetProgress(t *testing.T) {
 	})
 
 	t.Run("ignores_income_transactions", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user := testutil.CreateTestUser(t, db)
 		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
@@ -477,7 +533,7 @@ func TestGetBudgetProgress(t *testing.T) {
 			t.Fatalf("failed to create income tx: %v", err)
 		}
 
-		progress, err := svc.GetBudgetProgress(user.ID, budget.ID)
+		progress, err := svc.GetBudgetProgress(user.ID, budget.ID, true)
 		testutil.AssertNoError(t, err)
 
 		if progress.Spent != 0 {
@@ -486,8 +542,7 @@ func TestGetBudgetProgress(t *testing.T) {
 	})
 
 	t.Run("ignores_other_category_expenses", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user := testutil.CreateTestUser(t, db)
 		cat1 := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
@@ -509,7 +564,7 @@ func TestGetBudgetProgress(t *testing.T) {
 			t.Fatalf("failed to create tx: %v", err)
 		}
 
-		progress, err := svc.GetBudgetProgress(user.ID, budget.ID)
+		progress, err := svc.GetBudgetProgress(user.ID, budget.ID, true)
 		testutil.AssertNoError(t, err)
 
 		if progress.Spent != 0 {
@@ -517,19 +572,52 @@ func TestGetBudgetProgress(t *testing.T) {
 		}
 	})
 
+	t.Run("counts_from_start_date", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewBudgetService(db)
+		user := testutil.CreateTestUser(t, db)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+
+		now := time.Now().UTC()
+		start := now.Truncate(time.Minute)
+		budget, err := svc.CreateBudget(user.ID, cat.ID, "Fresh", 10000, models.BudgetPeriodMonthly, start, nil)
+		testutil.AssertNoError(t, err)
+
+		catID := cat.ID
+		for _, tx := range []*models.Transaction{
+			{UserID: user.ID, AccountID: account.ID, CategoryID: &catID, Type: models.TransactionTypeExpense, Amount: 3000, Date: now},
+			{UserID: user.ID, AccountID: account.ID, CategoryID: &catID, Type: models.TransactionTypeExpense, Amount: 4000, Date: start.Add(-time.Minute)},
+		} {
+			if err := db.Create(tx).Error; err != nil {
+				t.Fatalf("failed to create transaction: %v", err)
+			}
+		}
+
+		progress, err := svc.GetBudgetProgress(user.ID, budget.ID, true)
+		testutil.AssertNoError(t, err)
+		if progress.Spent != 3000 {
+			t.Errorf("expected spent 3000 since the start date, got %d", progress.Spent)
+		}
+		if !progress.PeriodStart.Equal(start) {
+			t.Errorf("expected period_start %v, got %v", start, progress.PeriodStart)
+		}
+		if progress.PeriodEnd.Before(now) {
+			t.Errorf("expected period_end after now, got %v", progress.PeriodEnd)
+		}
+	})
+
 	t.Run("not_found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user := testutil.CreateTestUser(t, db)
 
-		_, err := svc.GetBudgetProgress(user.ID, 9999)
+		_, err := svc.GetBudgetProgress(user.ID, uuid.New(), true)
 		testutil.AssertAppError(t, err, "BUDGET_NOT_FOUND")
 	})
 
 	t.Run("zero_budget_amount", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewBudgetService(db)
 		user := testutil.CreateTestUser(t, db)
 		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
@@ -538,7 +626,7 @@ func TestGetBudgetProgress(t *testing.T) {
 		budget, err := svc.CreateBudget(user.ID, cat.ID, "Zero", 0, models.BudgetPeriodMonthly, time.Now(), nil)
 		testutil.AssertNoError(t, err)
 
-		progress, err := svc.GetBudgetProgress(user.ID, budget.ID)
+		progress, err := svc.GetBudgetProgress(user.ID, budget.ID, true)
 		testutil.AssertNoError(t, err)
 
 		// Should not panic with divide-by-zero
@@ -547,3 +635,589 @@ func TestGetBudgetProgress(t *testing.T) {
 		}
 	})
 }
+
+func TestGetBudgetsProgress(t *testing.T) {
+	t.Parallel()
+	// Counting queries needs a callback, which must not leak into the shared DB
+	db := testutil.SetupTestDB(t)
+	t.Cleanup(func() { testutil.TeardownTestDB(t, db) })
+	svc := NewBudgetService(db)
+	user := testutil.CreateTestUser(t, db)
+	groceries := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+	travel := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+	account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+
+	// Two budgets on groceries with different windows, and one with no spend
+	monthly := testutil.CreateTestBudget(t, db, user.ID, groceries.ID)
+	yearStart := time.Date(time.Now().Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	yearly := &models.Budget{UserID: user.ID, CategoryID: groceries.ID, Name: "Groceries year", Amount: 500000,
+		Period: models.BudgetPeriodYearly, StartDate: yearStart, IsActive: true}
+	if err := db.Create(yearly).Error; err != nil {
+		t.Fatalf("failed to create budget: %v", err)
+	}
+	unused := testutil.CreateTestBudget(t, db, user.ID, travel.ID)
+
+	other := testutil.CreateTestUser(t, db)
+	otherCat := testutil.CreateTestCategory(t, db, other.ID, models.CategoryTypeExpense)
+	otherAccount := testutil.CreateTestCashAccountWithBalance(t, db, other.ID, 100000)
+	otherBudget := testutil.CreateTestBudget(t, db, other.ID, otherCat.ID)
+
+	for _, tx := range []*models.Transaction{
+		{UserID: user.ID, AccountID: account.ID, CategoryID: &groceries.ID, Type: models.TransactionTypeExpense, Amount: 3000, Date: time.Now()},
+		{UserID: user.ID, AccountID: account.ID, CategoryID: &groceries.ID, Type: models.TransactionTypeExpense, Amount: 4000, Date: time.Now(), IsPending: true},
+		{UserID: user.ID, AccountID: account.ID, CategoryID: &groceries.ID, Type: models.TransactionTypeExpense, Amount: 2000, Date: yearStart.Add(12 * time.Hour)},
+		{UserID: other.ID, AccountID: otherAccount.ID, CategoryID: &otherCat.ID, Type: models.TransactionTypeExpense, Amount: 9000, Date: time.Now()},
+	} {
+		if err := db.Create(tx).Error; err != nil {
+			t.Fatalf("failed to create transaction: %v", err)
+		}
+	}
+
+	var budgets []models.Budget
+	if err := db.Where("user_id = ?", user.ID).Find(&budgets).Error; err != nil {
+		t.Fatalf("failed to load budgets: %v", err)
+	}
+
+	var queries int
+	countQuery := func(*gorm.DB) { queries++ }
+	if err := db.Callback().Query().After("gorm:query").Register("test:count_budget_queries", countQuery); err != nil {
+		t.Fatalf("failed to register query callback: %v", err)
+	}
+	if err := db.Callback().Row().After("gorm:row").Register("test:count_budget_rows", countQuery); err != nil {
+		t.Fatalf("failed to register row callback: %v", err)
+	}
+
+	for _, includePending := range []bool{true, false} {
+		queries = 0
+		progress, err := svc.GetBudgetsProgress(user.ID, budgets, includePending)
+		testutil.AssertNoError(t, err)
+
+		if queries != 1 {
+			t.Errorf("expected 1 query for %d budgets, got %d", len(budgets), queries)
+		}
+		if len(progress) != 3 || progress[otherBudget.ID] != nil {
+			t.Fatalf("expected progress for exactly the 3 budgets given, got %d", len(progress))
+		}
+		// The list must agree with the detail endpoint for every budget
+		for _, budget := range []*models.Budget{monthly, yearly, unused} {
+			want, err := svc.GetBudgetProgress(user.ID, budget.ID, includePending)
+			testutil.AssertNoError(t, err)
+			got := progress[budget.ID]
+			if got == nil || *got != *want {
+				t.Errorf("include_pending=%v, budget %s: expected %+v, got %+v", includePending, budget.Name, want, got)
+			}
+		}
+	}
+
+	progress, err := svc.GetBudgetsProgress(user.ID, budgets, false)
+	testutil.AssertNoError(t, err)
+	if got := progress[yearly.ID].Spent; got != 5000 {
+		t.Errorf("expected yearly spend 5000 including the January expense, got %d", got)
+	}
+	if got := progress[unused.ID]; got.Spent != 0 || got.Remaining != 10000 {
+		t.Errorf("expected untouched budget to have 0 spent and 10000 remaining, got %+v", got)
+	}
+
+	empty, err := svc.GetBudgetsProgress(user.ID, nil, true)
+	testutil.AssertNoError(t, err)
+	if len(empty) != 0 {
+		t.Errorf("expected no progress for no budgets, got %d", len(empty))
+	}
+}
+
+func TestGetBudgetBurndown(t *testing.T) {
+	t.Parallel()
+	// A $500 monthly budget for October 2025 with expenses on the 2nd, 5th and
+	// 10th, viewed at noon on the 10th: ten of 31 days have elapsed.
+	setup := func(t *testing.T) (*budgetService, *models.User, *models.Budget, func(amount int64, date time.Time, pending bool)) {
+		db := testutil.WithTx(t)
+		svc := &budgetService{db: db, clock: SystemClock}
+		user := testutil.CreateTestUser(t, db)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		budget := testutil.CreateTestBudget(t, db, user.ID, cat.ID)
+		db.Model(budget).Updates(map[string]interface{}{
+			"amount":     50000,
+			"start_date": time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+		})
+
+		catID := cat.ID
+		expense := func(amount int64, date time.Time, pending bool) {
+			tx := &models.Transaction{UserID: user.ID, AccountID: account.ID, CategoryID: &catID,
+				Type: models.TransactionTypeExpense, Amount: amount, Date: date, IsPending: pending}
+			if err := db.Create(tx).Error; err != nil {
+				t.Fatalf("failed to create transaction: %v", err)
+			}
+		}
+		return svc, user, budget, expense
+	}
+	oct := func(day, hour int) time.Time { return time.Date(2025, 10, day, hour, 0, 0, 0, time.UTC) }
+
+	t.Run("cumulative_series", func(t *testing.T) {
+		svc, user, budget, expense := setup(t)
+		expense(1000, oct(2, 9), false)
+		expense(1500, oct(5, 18), false)
+		expense(500, oct(5, 20), false)
+		expense(1000, oct(10, 8), false)
+		expense(9999, oct(12, 8), true)                                     // future-dated, not yet settled
+		expense(7777, time.Date(2025, 9, 30, 23, 0, 0, 0, time.UTC), false) // previous period
+
+		burndown, err := svc.budgetBurndownAt(user.ID, budget.ID, oct(10, 12))
+		testutil.AssertNoError(t, err)
+
+		if burndown.Budgeted != 50000 || burndown.Spent != 4000 {
+			t.Errorf("expected budgeted 50000 and spent 4000, got %d and %d", burndown.Budgeted, burndown.Spent)
+		}
+		if burndown.DaysElapsed != 10 || burndown.DaysInPeriod != 31 || len(burndown.Series) != 10 {
+			t.Fatalf("expected 10 of 31 days, got %d of %d with %d points", burndown.DaysElapsed, burndown.DaysInPeriod, len(burndown.Series))
+		}
+		wantCumulative := []int64{0, 1000, 1000, 1000, 3000, 3000, 3000, 3000, 3000, 4000}
+		for i, point := range burndown.Series {
+			if !point.Date.Equal(oct(i+1, 0)) {
+				t.Errorf("point %d: expected date %v, got %v", i, oct(i+1, 0), point.Date)
+			}
+			if point.Cumulative != wantCumulative[i] {
+				t.Errorf("point %d: expected cumulative %d, got %d", i, wantCumulative[i], point.Cumulative)
+			}
+		}
+		if burndown.Series[4].Spent != 2000 {
+			t.Errorf("expected both expenses on the 5th in one point, got %d", burndown.Series[4].Spent)
+		}
+	})
+
+	t.Run("linear_projection", func(t *testing.T) {
+		svc, user, budget, expense := setup(t)
+		expense(4000, oct(2, 9), false)
+
+		burndown, err := svc.budgetBurndownAt(user.ID, budget.ID, oct(10, 12))
+		testutil.AssertNoError(t, err)
+
+		// 4000 over 10 days, extended to 31 days
+		if burndown.ProjectedSpend != 12400 {
+			t.Errorf("expected projected spend 12400, got %d", burndown.ProjectedSpend)
+		}
+	})
+
+	t.Run("projection_of_finished_period_is_actual_spend", func(t *testing.T) {
+		svc, user, budget, expense := setup(t)
+		expense(4000, oct(2, 9), false)
+		svc.db.Model(budget).Update("end_date", oct(31, 23))
+
+		burndown, err := svc.budgetBurndownAt(user.ID, budget.ID, time.Date(2025, 11, 15, 0, 0, 0, 0, time.UTC))
+		testutil.AssertNoError(t, err)
+
+		if burndown.DaysElapsed != 31 || burndown.ProjectedSpend != 4000 {
+			t.Errorf("expected all 31 days and projection 4000, got %d days and %d", burndown.DaysElapsed, burndown.ProjectedSpend)
+		}
+	})
+
+	t.Run("budget_not_found", func(t *testing.T) {
+		svc, user, _, _ := setup(t)
+
+		_, err := svc.GetBudgetBurndown(user.ID, "00000000-0000-7000-8000-000000000999")
+		testutil.AssertAppError(t, err, "BUDGET_NOT_FOUND")
+	})
+}
+
+func TestBudgetWindow(t *testing.T) {
+	t.Parallel()
+	at := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	endOf := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 23, 59, 59, 999999999, time.UTC)
+	}
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name      string
+		period    models.BudgetPeriod
+		startDate time.Time
+		endDate   *time.Time
+		now       time.Time
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name:      "whole_month",
+			period:    models.BudgetPeriodMonthly,
+			startDate: at(2025, 1, 1, 0),
+			now:       at(2025, 10, 17, 12),
+			wantStart: at(2025, 10, 1, 0),
+			wantEnd:   endOf(2025, 10, 31),
+		},
+		{
+			name:      "created_mid_month",
+			period:    models.BudgetPeriodMonthly,
+			startDate: at(2025, 10, 20, 0),
+			now:       at(2025, 10, 25, 12),
+			wantStart: at(2025, 10, 20, 0),
+			wantEnd:   endOf(2025, 10, 31),
+		},
+		{
+			name:      "month_after_creation",
+			period:    models.BudgetPeriodMonthly,
+			startDate: at(2025, 10, 20, 0),
+			now:       at(2025, 11, 3, 12),
+			wantStart: at(2025, 11, 1, 0),
+			wantEnd:   endOf(2025, 11, 30),
+		},
+		{
+			name:      "ends_mid_month",
+			period:    models.BudgetPeriodMonthly,
+			startDate: at(2025, 1, 1, 0),
+			endDate:   ptr(at(2025, 10, 15, 0)),
+			now:       at(2025, 10, 25, 12),
+			wantStart: at(2025, 10, 1, 0),
+			wantEnd:   at(2025, 10, 15, 0),
+		},
+		{
+			name:      "ended_in_earlier_month",
+			period:    models.BudgetPeriodMonthly,
+			startDate: at(2025, 1, 1, 0),
+			endDate:   ptr(at(2025, 8, 10, 0)),
+			now:       at(2025, 10, 25, 12),
+			wantStart: at(2025, 8, 1, 0),
+			wantEnd:   at(2025, 8, 10, 0),
+		},
+		{
+			name:      "starts_and_ends_mid_month",
+			period:    models.BudgetPeriodMonthly,
+			startDate: at(2025, 10, 5, 0),
+			endDate:   ptr(at(2025, 10, 20, 0)),
+			now:       at(2025, 10, 12, 12),
+			wantStart: at(2025, 10, 5, 0),
+			wantEnd:   at(2025, 10, 20, 0),
+		},
+		{
+			name:      "not_started_yet",
+			period:    models.BudgetPeriodMonthly,
+			startDate: at(2025, 12, 10, 0),
+			now:       at(2025, 10, 25, 12),
+			wantStart: at(2025, 12, 10, 0),
+			wantEnd:   endOf(2025, 12, 31),
+		},
+		{
+			name:      "yearly_first_year",
+			period:    models.BudgetPeriodYearly,
+			startDate: at(2025, 7, 15, 0),
+			now:       at(2025, 12, 31, 12),
+			wantStart: at(2025, 7, 15, 0),
+			wantEnd:   endOf(2025, 12, 31),
+		},
+		{
+			name:      "yearly_across_year_boundary",
+			period:    models.BudgetPeriodYearly,
+			startDate: at(2025, 7, 15, 0),
+			now:       at(2026, 1, 5, 12),
+			wantStart: at(2026, 1, 1, 0),
+			wantEnd:   endOf(2026, 12, 31),
+		},
+		{
+			name:      "yearly_ends_next_year",
+			period:    models.BudgetPeriodYearly,
+			startDate: at(2025, 7, 15, 0),
+			endDate:   ptr(at(2026, 3, 31, 0)),
+			now:       at(2026, 6, 1, 12),
+			wantStart: at(2026, 1, 1, 0),
+			wantEnd:   at(2026, 3, 31, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := &models.Budget{Period: tt.period, StartDate: tt.startDate, EndDate: tt.endDate}
+			start, end := budgetWindow(budget, tt.now)
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("expected %v - %v, got %v - %v", tt.wantStart, tt.wantEnd, start, end)
+			}
+		})
+	}
+}
+
+func TestBudgetPace(t *testing.T) {
+	t.Parallel()
+	at := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2025, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	endOf := func(month time.Month, day int) time.Time {
+		return time.Date(2025, month, day, 23, 59, 59, 999999999, time.UTC)
+	}
+
+	tests := []struct {
+		name          string
+		start, end    time.Time
+		now           time.Time
+		spent         int64
+		wantElapsed   int
+		wantDays      int
+		wantProjected int64
+		wantStatus    BudgetPaceStatus
+	}{
+		{
+			name:  "on_track_mid_month",
+			start: at(4, 1, 0), end: endOf(4, 30), now: at(4, 10, 12), spent: 3000,
+			wantElapsed: 10, wantDays: 30, wantProjected: 9000, wantStatus: BudgetPaceOnTrack,
+		},
+		{
+			name:  "at_risk_mid_month",
+			start: at(4, 1, 0), end: endOf(4, 30), now: at(4, 10, 12), spent: 6000,
+			wantElapsed: 10, wantDays: 30, wantProjected: 18000, wantStatus: BudgetPaceAtRisk,
+		},
+		{
+			name:  "already_over",
+			start: at(4, 1, 0), end: endOf(4, 30), now: at(4, 20, 12), spent: 10001,
+			wantElapsed: 20, wantDays: 30, wantProjected: 15001, wantStatus: BudgetPaceOver,
+		},
+		{
+			name:  "first_instant_of_period",
+			start: at(4, 1, 0), end: endOf(4, 30), now: at(4, 1, 0), spent: 0,
+			wantElapsed: 1, wantDays: 30, wantProjected: 0, wantStatus: BudgetPaceOnTrack,
+		},
+		{
+			name:  "first_day_projects_that_day",
+			start: at(4, 1, 0), end: endOf(4, 30), now: at(4, 1, 18), spent: 500,
+			wantElapsed: 1, wantDays: 30, wantProjected: 15000, wantStatus: BudgetPaceAtRisk,
+		},
+		{
+			name:  "last_day",
+			start: at(4, 1, 0), end: endOf(4, 30), now: endOf(4, 30), spent: 9000,
+			wantElapsed: 30, wantDays: 30, wantProjected: 9000, wantStatus: BudgetPaceOnTrack,
+		},
+		{
+			name:  "not_started_yet",
+			start: at(12, 10, 0), end: endOf(12, 31), now: at(10, 25, 12), spent: 0,
+			wantElapsed: 0, wantDays: 22, wantProjected: 0, wantStatus: BudgetPaceOnTrack,
+		},
+		{
+			name:  "already_ended",
+			start: at(8, 1, 0), end: at(8, 10, 0), now: at(10, 25, 12), spent: 4000,
+			wantElapsed: 10, wantDays: 10, wantProjected: 4000, wantStatus: BudgetPaceOnTrack,
+		},
+		{
+			name:  "yearly",
+			start: at(1, 1, 0), end: endOf(12, 31), now: at(3, 1, 12), spent: 1000,
+			wantElapsed: 60, wantDays: 365, wantProjected: 6083, wantStatus: BudgetPaceOnTrack,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := budgetPace(tt.start, tt.end, tt.now, 10000, tt.spent)
+			want := BudgetPace{DaysElapsed: tt.wantElapsed, DaysInPeriod: tt.wantDays, ProjectedSpend: tt.wantProjected, Status: tt.wantStatus}
+			if got != want {
+				t.Errorf("expected %+v, got %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestCloneForPeriod(t *testing.T) {
+	t.Parallel()
+	source := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	target := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("copies_monthly_budgets_into_target_month", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewBudgetService(db)
+		user := testutil.CreateTestUser(t, db)
+		groceries := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		dining := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		travel := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+
+		_, err := svc.CreateBudget(user.ID, groceries.ID, "Groceries", 50000, models.BudgetPeriodMonthly, source, nil)
+		testutil.AssertNoError(t, err)
+		endDate := source.AddDate(0, 1, -1)
+		_, err = svc.CreateBudget(user.ID, dining.ID, "Dining", 20000, models.BudgetPeriodMonthly, source.AddDate(0, 0, 14), &endDate)
+		testutil.AssertNoError(t, err)
+		// Yearly budgets and budgets from other months are not cloned
+		_, err = svc.CreateBudget(user.ID, travel.ID, "Travel", 300000, models.BudgetPeriodYearly, source, nil)
+		testutil.AssertNoError(t, err)
+		_, err = svc.CreateBudget(user.ID, travel.ID, "Old", 1000, models.BudgetPeriodMonthly, source.AddDate(0, -1, 0), nil)
+		testutil.AssertNoError(t, err)
+
+		created, err := svc.CloneForPeriod(user.ID, source, target)
+		testutil.AssertNoError(t, err)
+
+		if len(created) != 2 {
+			t.Fatalf("expected 2 budgets created, got %d", len(created))
+		}
+		for _, b := range created {
+			if !b.StartDate.Equal(target) {
+				t.Errorf("expected start date %v, got %v", target, b.StartDate)
+			}
+			if b.Period != models.BudgetPeriodMonthly || !b.IsActive {
+				t.Errorf("expected an active monthly budget, got period=%s active=%v", b.Period, b.IsActive)
+			}
+			switch b.CategoryID {
+			case groceries.ID:
+				if b.Amount != 50000 || b.Name != "Groceries" || b.EndDate != nil {
+					t.Errorf("unexpected groceries clone: %+v", b)
+				}
+			case dining.ID:
+				if b.Amount != 20000 || b.EndDate == nil || b.EndDate.Month() != time.April {
+					t.Errorf("expected dining clone to end in April, got %+v", b)
+				}
+			default:
+				t.Errorf("unexpected category cloned: %s", b.CategoryID)
+			}
+		}
+	})
+
+	t.Run("uses_latest_amount_after_mid_month_edit", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewBudgetService(db)
+		user := testutil.CreateTestUser(t, db)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+
+		budget, err := svc.CreateBudget(user.ID, cat.ID, "Groceries", 50000, models.BudgetPeriodMonthly, source, nil)
+		testutil.AssertNoError(t, err)
+		newAmount := int64(65000)
+		_, err = svc.UpdateBudget(user.ID, budget.ID, "", &newAmount, nil, nil)
+		testutil.AssertNoError(t, err)
+
+		created, err := svc.CloneForPeriod(user.ID, source, target)
+		testutil.AssertNoError(t, err)
+
+		if len(created) != 1 {
+			t.Fatalf("expected 1 budget created, got %d", len(created))
+		}
+		if created[0].Amount != 65000 {
+			t.Errorf("expected cloned amount 65000, got %d", created[0].Amount)
+		}
+	})
+
+	t.Run("idempotent", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewBudgetService(db)
+		user := testutil.CreateTestUser(t, db)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+
+		_, err := svc.CreateBudget(user.ID, cat.ID, "Groceries", 50000, models.BudgetPeriodMonthly, source, nil)
+		testutil.AssertNoError(t, err)
+
+		first, err := svc.CloneForPeriod(user.ID, source, target)
+		testutil.AssertNoError(t, err)
+		second, err := svc.CloneForPeriod(user.ID, source, target)
+		testutil.AssertNoError(t, err)
+
+		if len(first) != 1 || len(second) != 0 {
+			t.Errorf("expected 1 then 0 budgets created, got %d then %d", len(first), len(second))
+		}
+		var count int64
+		db.Model(&models.Budget{}).Where("user_id = ? AND category_id = ?", user.ID, cat.ID).Count(&count)
+		if count != 2 {
+			t.Errorf("expected 2 budgets in total, got %d", count)
+		}
+	})
+
+	t.Run("skips_category_already_budgeted_in_target", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewBudgetService(db)
+		user := testutil.CreateTestUser(t, db)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+
+		_, err := svc.CreateBudget(user.ID, cat.ID, "Groceries", 50000, models.BudgetPeriodMonthly, source, nil)
+		testutil.AssertNoError(t, err)
+		_, err = svc.CreateBudget(user.ID, cat.ID, "Groceries (April)", 70000, models.BudgetPeriodMonthly, target.AddDate(0, 0, 3), nil)
+		testutil.AssertNoError(t, err)
+
+		created, err := svc.CloneForPeriod(user.ID, source, target)
+		testutil.AssertNoError(t, err)
+
+		if len(created) != 0 {
+			t.Errorf("expected no budgets created, got %d", len(created))
+		}
+	})
+
+	t.Run("skips_inactive_budgets", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewBudgetService(db)
+		user := testutil.CreateTestUser(t, db)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+
+		budget, err := svc.CreateBudget(user.ID, cat.ID, "Groceries", 50000, models.BudgetPeriodMonthly, source, nil)
+		testutil.AssertNoError(t, err)
+		db.Model(budget).Update("is_active", false)
+
+		created, err := svc.CloneForPeriod(user.ID, source, target)
+		testutil.AssertNoError(t, err)
+
+		if len(created) != 0 {
+			t.Errorf("expected no budgets created, got %d", len(created))
+		}
+	})
+
+	t.Run("same_month_rejected", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewBudgetService(db)
+		user := testutil.CreateTestUser(t, db)
+
+		_, err := svc.CloneForPeriod(user.ID, source, source.AddDate(0, 0, 10))
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+}
+
+func TestRolloverMonthlyBudgets(t *testing.T) {
+	t.Parallel()
+	asOf := time.Date(2025, time.April, 1, 6, 0, 0, 0, time.UTC)
+	source := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("rolls_over_every_user_and_is_idempotent", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewBudgetService(db)
+		user1 := testutil.CreateTestUser(t, db)
+		user2 := testutil.CreateTestUser(t, db)
+		cat1 := testutil.CreateTestCategory(t, db, user1.ID, models.CategoryTypeExpense)
+		cat2 := testutil.CreateTestCategory(t, db, user1.ID, models.CategoryTypeExpense)
+		cat3 := testutil.CreateTestCategory(t, db, user2.ID, models.CategoryTypeExpense)
+
+		_, err := svc.CreateBudget(user1.ID, cat1.ID, "Groceries", 50000, models.BudgetPeriodMonthly, source, nil)
+		testutil.AssertNoError(t, err)
+		_, err = svc.CreateBudget(user1.ID, cat2.ID, "Dining", 20000, models.BudgetPeriodMonthly, source, nil)
+		testutil.AssertNoError(t, err)
+		edited, err := svc.CreateBudget(user2.ID, cat3.ID, "Fuel", 10000, models.BudgetPeriodMonthly, source, nil)
+		testutil.AssertNoError(t, err)
+		newAmount := int64(12500)
+		_, err = svc.UpdateBudget(user2.ID, edited.ID, "", &newAmount, nil, nil)
+		testutil.AssertNoError(t, err)
+
+		created, err := svc.RolloverMonthlyBudgets(asOf)
+		testutil.AssertNoError(t, err)
+		if created != 3 {
+			t.Fatalf("expected 3 budgets created, got %d", created)
+		}
+
+		created, err = svc.RolloverMonthlyBudgets(asOf)
+		testutil.AssertNoError(t, err)
+		if created != 0 {
+			t.Errorf("expected second run to create nothing, got %d", created)
+		}
+
+		var clone models.Budget
+		if err := db.Where("user_id = ? AND category_id = ? AND start_date >= ?", user2.ID, cat3.ID, asOf.AddDate(0, 0, -1)).
+			First(&clone).Error; err != nil {
+			t.Fatalf("expected a clone for user2: %v", err)
+		}
+		if clone.Amount != 12500 {
+			t.Errorf("expected cloned amount 12500, got %d", clone.Amount)
+		}
+
+		var total int64
+		db.Model(&models.Budget{}).Count(&total)
+		if total != 6 {
+			t.Errorf("expected 6 budgets in total, got %d", total)
+		}
+	})
+
+	t.Run("no_budgets", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewBudgetService(db)
+
+		created, err := svc.RolloverMonthlyBudgets(asOf)
+		testutil.AssertNoError(t, err)
+		if created != 0 {
+			t.Errorf("expected 0 budgets created, got %d", created)
+		}
+	})
+}
@@ -82,7 +82,7 @@ func (s *categoryService) GetUserCategories(userID string, page pagination.PageR
 
 	var totalItems int64
 	base := s.db.Model(&models.Category{}).Where("user_id = ?", userID)
-	if err := base.Count(&totalItems).Error; err != nil {
+	if err := pagination.Count(base, page, &totalItems); err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
 
@@ -101,7 +101,7 @@ func (s *categoryService) GetUserCategoriesByType(userID string, categoryType mo
 
 	var totalItems int64
 	base := s.db.Model(&models.Category{}).Where("user_id = ? AND type = ?", userID, categoryType)
-	if err := base.Count(&totalItems).Error; err != nil {
+	if err := pagination.Count(base, page, &totalItems); err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
 
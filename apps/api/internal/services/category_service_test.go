@@ -6,20 +6,21 @@ import (
 	"kuberan/internal/models"
 	"kuberan/internal/pagination"
 	"kuberan/internal/testutil"
+	"kuberan/internal/uuid"
 )
 
 func TestCreateCategory(t *testing.T) {
+	t.Parallel()
 	t.Run("valid", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewCategoryService(db)
 		user := testutil.CreateTestUser(t, db)
 
 		cat, err := svc.CreateCategory(user.ID, "Groceries", models.CategoryTypeExpense, "Food shopping", "cart", "#FF0000", nil)
 		testutil.AssertNoError(t, err)
 
-		if cat.ID == 0 {
-			t.Fatal("expected non-zero category ID")
+		if cat.ID == "" {
+			t.Fatal("expected non-empty category ID")
 		}
 		if cat.Name != "Groceries" {
 			t.Errorf("expected name Groceries, got %s", cat.Name)
@@ -33,8 +34,7 @@ func TestCreateCategory(t *testing.T) {
 	})
 
 	t.Run("duplicate_name", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewCategoryService(db)
 		user := testutil.CreateTestUser(t, db)
 
@@ -46,8 +46,7 @@ func TestCreateCategory(t *testing.T) {
 	})
 
 	t.Run("with_parent", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewCategoryService(db)
 		user := testutil.CreateTestUser(t, db)
 
@@ -58,24 +57,22 @@ func TestCreateCategory(t *testing.T) {
 		testutil.AssertNoError(t, err)
 
 		if child.ParentID == nil || *child.ParentID != parent.ID {
-			t.Errorf("expected parent ID %d, got %v", parent.ID, child.ParentID)
+			t.Errorf("expected parent ID %s, got %v", parent.ID, child.ParentID)
 		}
 	})
 
 	t.Run("invalid_parent", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewCategoryService(db)
 		user := testutil.CreateTestUser(t, db)
 
-		nonexistent := uint(99999)
+		nonexistent := uuid.New()
 		_, err := svc.CreateCategory(user.ID, "Orphan", models.CategoryTypeExpense, "", "", "", &nonexistent)
 		testutil.AssertAppError(t, err, "CATEGORY_NOT_FOUND")
 	})
 
 	t.Run("empty_name", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewCategoryService(db)
 		user := testutil.CreateTestUser(t, db)
 
@@ -84,8 +81,7 @@ func TestCreateCategory(t *testing.T) {
 	})
 
 	t.Run("duplicate_name_different_users_allowed", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewCategoryService(db)
 		user1 := testutil.CreateTestUser(t, db)
 		user2 := testutil.CreateTestUser(t, db)
@@ -100,9 +96,9 @@ func TestCreateCategory(t *testing.T) {
 }
 
 func TestGetUserCategories(t *testing.T) {
+	t.Parallel()
 	t.Run("returns_user_categories_only", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewCategoryService(db)
 
 		user1 := testutil.CreateTestUser(t, db)
@@ -125,8 +121,7 @@ func TestGetUserCategories(t *testing.T) {
 	})
 
 	t.Run("pagination", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewCategoryService(db)
 		user := testutil.CreateTestUser(t, db)
 
@@ -151,9 +146,9 @@ func TestGetUserCategories(t *testing.T) {
 }
 
 func TestGetUserCategoriesByType(t *testing.T) {
+	t.Parallel()
 	t.Run("filters_correctly", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewCategoryService(db)
 		user := testutil.CreateTestUser(t, db)
 
@@ -178,9 +173,9 @@ func TestGetUserCategoriesByType(t *testing.T) {
 }
 
 func TestGetCategoryByID(t *testing.T) {
+	t.Parallel()
 	t.Run("found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewCategoryService(db)
 		user := testutil.CreateTestUser(t, db)
 		created := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
@@ -189,23 +184,21 @@ func TestGetCategoryByID(t *testing.T) {
 		testutil.AssertNoError(t, err)
 
 		if cat.ID != created.ID {
-			t.Errorf("expected category ID %d, got %d", created.ID, cat.ID)
+			t.Errorf("expected category ID %s, got %s", created.ID, cat.ID)
 		}
 	})
 
 	t.Run("not_found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewCategoryService(db)
 		user := testutil.CreateTestUser(t, db)
 
-		_, err := svc.GetCategoryByID(user.ID, 99999)
+		_, err := svc.GetCategoryByID(user.ID, uuid.New())
 		testutil.AssertAppError(t, err, "CATEGORY_NOT_FOUND")
 	})
 
 	t.Run("wrong_user", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewCategoryService(db)
 
 		user1 := testutil.CreateTestUser(t, db)
@@ -218,9 +211,9 @@ func TestGetCategoryByID(t *testing.T) {
 }
 
 func TestUpdateCategory(t *testing.T) {
+	t.Parallel()
 	t.Run("valid", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewCategoryService(db)
 		user := testutil.CreateTestUser(t, db)
 		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
@@ -243,8 +236,7 @@ func TestUpdateCategory(t *testing.T) {
 	})
 
 	t.Run("self_parent", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewCategoryService(db)
 		user := testutil.CreateTestUser(t, db)
 		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
@@ -254,18 +246,16 @@ func TestUpdateCategory(t *testing.T) {
 	})
 
 	t.Run("not_found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewCategoryService(db)
 		user := testutil.CreateTestUser(t, db)
 
-		_, err := svc.UpdateCategory(user.ID, 99999, "Name", "", "", "", nil)
+		_, err := svc.UpdateCategory(user.ID, uuid.New(), "Name", "", "", "", nil)
 		testutil.AssertAppError(t, err, "CATEGORY_NOT_FOUND")
 	})
 
 	t.Run("with_valid_parent", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewCategoryService(db)
 		user := testutil.CreateTestUser(t, db)
 
@@ -276,15 +266,15 @@ func TestUpdateCategory(t *testing.T) {
 		testutil.AssertNoError(t, err)
 
 		if updated.ParentID == nil || *updated.ParentID != parent.ID {
-			t.Errorf("expected parent ID %d, got %v", parent.ID, updated.ParentID)
+			t.Errorf("expected parent ID %s, got %v", parent.ID, updated.ParentID)
 		}
 	})
 }
 
 func TestDeleteCategory(t *testing.T) {
+	t.Parallel()
 	t.Run("valid", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewCategoryService(db)
 		user := testutil.CreateTestUser(t, db)
 		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
@@ -305,8 +295,7 @@ func TestDeleteCategory(t *testing.T) {
 	})
 
 	t.Run("has_children", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewCategoryService(db)
 		user := testutil.CreateTestUser(t, db)
 
@@ -327,8 +316,7 @@ func TestDeleteCategory(t *testing.T) {
 	})
 
 	t.Run("allows_deletion_when_transactions_reference_category", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewCategoryService(db)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
@@ -352,25 +340,23 @@ func TestDeleteCategory(t *testing.T) {
 
 		// Transaction should still reference the soft-deleted category
 		var storedTx models.Transaction
-		db.First(&storedTx, tx.ID)
+		db.Where("id = ?", tx.ID).First(&storedTx)
 		if storedTx.CategoryID == nil || *storedTx.CategoryID != cat.ID {
 			t.Error("expected transaction to still reference the soft-deleted category")
 		}
 	})
 
 	t.Run("not_found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewCategoryService(db)
 		user := testutil.CreateTestUser(t, db)
 
-		err := svc.DeleteCategory(user.ID, 99999)
+		err := svc.DeleteCategory(user.ID, uuid.New())
 		testutil.AssertAppError(t, err, "CATEGORY_NOT_FOUND")
 	})
 
 	t.Run("wrong_user", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewCategoryService(db)
 
 		user1 := testutil.CreateTestUser(t, db)
@@ -0,0 +1,25 @@
+package services
+
+import "time"
+
+// Clock tells services the current time and the time zone calendar periods,
+// such as summary months and budget periods, are computed in.
+type Clock interface {
+	Now() time.Time
+	Location() *time.Location
+}
+
+// SystemClock reads the machine's clock and reports in UTC, whatever the
+// machine's local time zone.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time           { return time.Now() }
+func (systemClock) Location() *time.Location { return time.UTC }
+
+// reportingNow returns the clock's current time in its reporting location, so
+// its Year, Month and Day name the calendar day reports are computed for.
+func reportingNow(c Clock) time.Time {
+	return c.Now().In(c.Location())
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+// fixedClock is a Clock stopped at now, reporting in loc.
+type fixedClock struct {
+	now time.Time
+	loc *time.Location
+}
+
+func (c fixedClock) Now() time.Time           { return c.now }
+func (c fixedClock) Location() *time.Location { return c.loc }
+
+// Zones at the extremes of UTC offsets, standing in for the machine's local
+// time: on 31 March at 20:00 UTC it is already 1 April in Kiritimati.
+var (
+	kiritimati = time.FixedZone("LINT", 14*60*60)
+	honolulu   = time.FixedZone("HST", -10*60*60)
+)
+
+func TestReportingNow(t *testing.T) {
+	t.Parallel()
+	instant := time.Date(2026, 3, 31, 20, 0, 0, 0, time.UTC)
+
+	t.Run("ignores_the_machine_zone", func(t *testing.T) {
+		for _, zone := range []*time.Location{time.UTC, kiritimati, honolulu} {
+			got := reportingNow(fixedClock{now: instant.In(zone), loc: time.UTC})
+			if got.Location() != time.UTC || got.Day() != 31 || got.Month() != time.March {
+				t.Errorf("machine zone %s: expected 31 March UTC, got %v", zone, got)
+			}
+		}
+	})
+
+	t.Run("uses_the_reporting_location", func(t *testing.T) {
+		got := reportingNow(fixedClock{now: instant, loc: kiritimati})
+		if got.Day() != 1 || got.Month() != time.April {
+			t.Errorf("expected 1 April, got %v", got)
+		}
+	})
+
+	t.Run("system_clock_reports_in_utc", func(t *testing.T) {
+		if SystemClock.Location() != time.UTC {
+			t.Errorf("expected UTC, got %v", SystemClock.Location())
+		}
+		if got := reportingNow(SystemClock); got.Location() != time.UTC {
+			t.Errorf("expected a UTC time, got %v", got)
+		}
+	})
+}
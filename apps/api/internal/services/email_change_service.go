@@ -0,0 +1,206 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/logger"
+	"kuberan/internal/models"
+	"kuberan/internal/notify"
+)
+
+// emailChangeTokenTTL is how long a verification token stays valid.
+const emailChangeTokenTTL = 24 * time.Hour
+
+// emailChangeService handles login email changes that require verification.
+type emailChangeService struct {
+	db     *gorm.DB
+	sender notify.Sender
+}
+
+// NewEmailChangeService creates a new EmailChangeServicer.
+func NewEmailChangeService(db *gorm.DB, sender notify.Sender) EmailChangeServicer {
+	return &emailChangeService{db: db, sender: sender}
+}
+
+// RequestEmailChange verifies the user's current password and records a pending
+// change to newEmail. A verification token is sent to the new address and the
+// current address is notified. Any earlier pending change for the user is replaced.
+func (s *emailChangeService) RequestEmailChange(userID, newEmail, password string) (*models.PendingEmailChange, error) {
+	newEmail = normalizeEmail(newEmail)
+	if newEmail == "" {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "new email is required")
+	}
+
+	var user models.User
+	if err := s.db.Where("id = ?", userID).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrUserNotFound
+		}
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		return nil, apperrors.ErrIncorrectPassword
+	}
+	if newEmail == user.Email {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "new email must differ from the current email")
+	}
+
+	taken, err := emailInUse(s.db, newEmail, userID)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, apperrors.ErrDuplicateEmail
+	}
+
+	token, err := generateVerificationToken()
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	pending := &models.PendingEmailChange{
+		UserID:    userID,
+		NewEmail:  newEmail,
+		TokenHash: hashVerificationToken(token),
+		ExpiresAt: time.Now().Add(emailChangeTokenTTL),
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.PendingEmailChange{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(pending).Error
+	})
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	if err := s.sender.Send(notify.Message{
+		To:      newEmail,
+		Subject: "Confirm your new Kuberan email",
+		Body:    fmt.Sprintf("Confirm this address by visiting /api/v1/auth/verify-email?token=%s. The link expires in 24 hours.", token),
+	}); err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	// The notice to the old address is best-effort; the change can still be verified without it.
+	if err := s.sender.Send(notify.Message{
+		To:      user.Email,
+		Subject: "Your Kuberan email is being changed",
+		Body:    fmt.Sprintf("A request was made to change your login email to %s. If this wasn't you, change your password.", newEmail),
+	}); err != nil {
+		logger.Get().Errorw("failed to notify previous email of change", "user_id", userID, "error", err)
+	}
+
+	return pending, nil
+}
+
+// VerifyEmailChange completes a pending change identified by token and swaps the user's email.
+func (s *emailChangeService) VerifyEmailChange(token string) (*models.User, error) {
+	if token == "" {
+		return nil, apperrors.ErrInvalidVerificationToken
+	}
+
+	var pending models.PendingEmailChange
+	if err := s.db.Where("token_hash = ?", hashVerificationToken(token)).First(&pending).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrInvalidVerificationToken
+		}
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	if time.Now().After(pending.ExpiresAt) {
+		s.db.Unscoped().Delete(&pending)
+		return nil, apperrors.ErrVerificationTokenExpired
+	}
+
+	var user models.User
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Re-check in case another account claimed the address since the request.
+		var count int64
+		if err := tx.Model(&models.User{}).Where("email = ? AND id <> ?", pending.NewEmail, pending.UserID).Count(&count).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		if count > 0 {
+			return apperrors.ErrDuplicateEmail
+		}
+
+		if err := tx.Model(&models.User{}).Where("id = ?", pending.UserID).Update("email", pending.NewEmail).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		if err := tx.Unscoped().Delete(&pending).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		if err := tx.Where("id = ?", pending.UserID).First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperrors.ErrUserNotFound
+			}
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// PurgeExpiredEmailChanges permanently deletes pending changes that expired before now.
+func (s *emailChangeService) PurgeExpiredEmailChanges(now time.Time) (int, error) {
+	result := s.db.Unscoped().Where("expires_at < ?", now).Delete(&models.PendingEmailChange{})
+	if result.Error != nil {
+		return 0, apperrors.Wrap(apperrors.ErrInternalServer, result.Error)
+	}
+	return int(result.RowsAffected), nil
+}
+
+// emailInUse reports whether email belongs to another user or is awaiting
+// verification for one. excludeUserID may be empty.
+func emailInUse(db *gorm.DB, email, excludeUserID string) (bool, error) {
+	var count int64
+	q := db.Model(&models.User{}).Where("email = ?", email)
+	if excludeUserID != "" {
+		q = q.Where("id <> ?", excludeUserID)
+	}
+	if err := q.Count(&count).Error; err != nil {
+		return false, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	q = db.Model(&models.PendingEmailChange{}).Where("new_email = ? AND expires_at > ?", email, time.Now())
+	if excludeUserID != "" {
+		q = q.Where("user_id <> ?", excludeUserID)
+	}
+	if err := q.Count(&count).Error; err != nil {
+		return false, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return count > 0, nil
+}
+
+// generateVerificationToken returns a random 32-byte hex-encoded token.
+func generateVerificationToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashVerificationToken returns the SHA-256 hex digest of a verification token.
+func hashVerificationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"kuberan/internal/models"
+	"kuberan/internal/notify"
+	"kuberan/internal/testutil"
+)
+
+// recordingSender captures sent notifications for assertions.
+type recordingSender struct {
+	messages []notify.Message
+	err      error
+}
+
+func (s *recordingSender) Send(msg notify.Message) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+// tokenFromMessage extracts the verification token from a verification message body.
+func tokenFromMessage(t *testing.T, msg notify.Message) string {
+	t.Helper()
+	_, after, ok := strings.Cut(msg.Body, "token=")
+	if !ok {
+		t.Fatalf("no token in message body: %s", msg.Body)
+	}
+	token, _, _ := strings.Cut(after, ".")
+	return token
+}
+
+func TestRequestEmailChange(t *testing.T) {
+	t.Parallel()
+	t.Run("valid", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		sender := &recordingSender{}
+		svc := NewEmailChangeService(db, sender)
+		user := testutil.CreateTestUser(t, db)
+
+		pending, err := svc.RequestEmailChange(user.ID, "New@Example.com", "password123")
+		testutil.AssertNoError(t, err)
+
+		if pending.NewEmail != "new@example.com" {
+			t.Errorf("expected normalized new email, got %s", pending.NewEmail)
+		}
+		if len(sender.messages) != 2 {
+			t.Fatalf("expected 2 messages, got %d", len(sender.messages))
+		}
+		if sender.messages[0].To != "new@example.com" {
+			t.Errorf("expected verification sent to new email, got %s", sender.messages[0].To)
+		}
+		if sender.messages[1].To != user.Email {
+			t.Errorf("expected notice sent to old email, got %s", sender.messages[1].To)
+		}
+
+		token := tokenFromMessage(t, sender.messages[0])
+		if pending.TokenHash == token || pending.TokenHash != hashVerificationToken(token) {
+			t.Error("expected only the token hash to be stored")
+		}
+
+		var reloaded models.User
+		db.First(&reloaded, "id = ?", user.ID)
+		if reloaded.Email != user.Email {
+			t.Errorf("expected email unchanged until verified, got %s", reloaded.Email)
+		}
+	})
+
+	t.Run("wrong_password", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		sender := &recordingSender{}
+		svc := NewEmailChangeService(db, sender)
+		user := testutil.CreateTestUser(t, db)
+
+		_, err := svc.RequestEmailChange(user.ID, "new@example.com", "wrongpassword")
+		testutil.AssertAppError(t, err, "INCORRECT_PASSWORD")
+
+		if len(sender.messages) != 0 {
+			t.Errorf("expected no messages sent, got %d", len(sender.messages))
+		}
+		var count int64
+		db.Model(&models.PendingEmailChange{}).Count(&count)
+		if count != 0 {
+			t.Errorf("expected no pending change, got %d", count)
+		}
+	})
+
+	t.Run("email_taken_by_user", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewEmailChangeService(db, &recordingSender{})
+		user := testutil.CreateTestUser(t, db)
+		other := testutil.CreateTestUser(t, db)
+
+		_, err := svc.RequestEmailChange(user.ID, other.Email, "password123")
+		testutil.AssertAppError(t, err, "DUPLICATE_EMAIL")
+	})
+
+	t.Run("email_pending_elsewhere", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewEmailChangeService(db, &recordingSender{})
+		user := testutil.CreateTestUser(t, db)
+		other := testutil.CreateTestUser(t, db)
+
+		_, err := svc.RequestEmailChange(other.ID, "claimed@example.com", "password123")
+		testutil.AssertNoError(t, err)
+
+		_, err = svc.RequestEmailChange(user.ID, "claimed@example.com", "password123")
+		testutil.AssertAppError(t, err, "DUPLICATE_EMAIL")
+	})
+
+	t.Run("replaces_previous_request", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewEmailChangeService(db, &recordingSender{})
+		user := testutil.CreateTestUser(t, db)
+
+		_, err := svc.RequestEmailChange(user.ID, "first@example.com", "password123")
+		testutil.AssertNoError(t, err)
+		_, err = svc.RequestEmailChange(user.ID, "second@example.com", "password123")
+		testutil.AssertNoError(t, err)
+
+		var pending []models.PendingEmailChange
+		db.Where("user_id = ?", user.ID).Find(&pending)
+		if len(pending) != 1 || pending[0].NewEmail != "second@example.com" {
+			t.Errorf("expected only the latest pending change, got %+v", pending)
+		}
+	})
+
+	t.Run("same_as_current", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewEmailChangeService(db, &recordingSender{})
+		user := testutil.CreateTestUser(t, db)
+
+		_, err := svc.RequestEmailChange(user.ID, user.Email, "password123")
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+
+	t.Run("verification_send_fails", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewEmailChangeService(db, &recordingSender{err: errors.New("smtp down")})
+		user := testutil.CreateTestUser(t, db)
+
+		_, err := svc.RequestEmailChange(user.ID, "new@example.com", "password123")
+		testutil.AssertAppError(t, err, "INTERNAL_ERROR")
+	})
+}
+
+func TestVerifyEmailChange(t *testing.T) {
+	t.Parallel()
+	t.Run("valid", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		sender := &recordingSender{}
+		svc := NewEmailChangeService(db, sender)
+		user := testutil.CreateTestUser(t, db)
+
+		_, err := svc.RequestEmailChange(user.ID, "new@example.com", "password123")
+		testutil.AssertNoError(t, err)
+
+		updated, err := svc.VerifyEmailChange(tokenFromMessage(t, sender.messages[0]))
+		testutil.AssertNoError(t, err)
+
+		if updated.Email != "new@example.com" {
+			t.Errorf("expected email new@example.com, got %s", updated.Email)
+		}
+		var count int64
+		db.Unscoped().Model(&models.PendingEmailChange{}).Count(&count)
+		if count != 0 {
+			t.Errorf("expected pending change removed, got %d", count)
+		}
+	})
+
+	t.Run("token_is_single_use", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		sender := &recordingSender{}
+		svc := NewEmailChangeService(db, sender)
+		user := testutil.CreateTestUser(t, db)
+
+		_, err := svc.RequestEmailChange(user.ID, "new@example.com", "password123")
+		testutil.AssertNoError(t, err)
+		token := tokenFromMessage(t, sender.messages[0])
+
+		_, err = svc.VerifyEmailChange(token)
+		testutil.AssertNoError(t, err)
+		_, err = svc.VerifyEmailChange(token)
+		testutil.AssertAppError(t, err, "INVALID_VERIFICATION_TOKEN")
+	})
+
+	t.Run("expired_token", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		sender := &recordingSender{}
+		svc := NewEmailChangeService(db, sender)
+		user := testutil.CreateTestUser(t, db)
+
+		pending, err := svc.RequestEmailChange(user.ID, "new@example.com", "password123")
+		testutil.AssertNoError(t, err)
+		db.Model(pending).Update("expires_at", time.Now().Add(-time.Minute))
+
+		_, err = svc.VerifyEmailChange(tokenFromMessage(t, sender.messages[0]))
+		testutil.AssertAppError(t, err, "VERIFICATION_TOKEN_EXPIRED")
+
+		var reloaded models.User
+		db.First(&reloaded, "id = ?", user.ID)
+		if reloaded.Email != user.Email {
+			t.Errorf("expected email unchanged, got %s", reloaded.Email)
+		}
+	})
+
+	t.Run("unknown_token", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewEmailChangeService(db, &recordingSender{})
+
+		_, err := svc.VerifyEmailChange("not-a-real-token")
+		testutil.AssertAppError(t, err, "INVALID_VERIFICATION_TOKEN")
+	})
+
+	t.Run("email_claimed_before_verification", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		sender := &recordingSender{}
+		svc := NewEmailChangeService(db, sender)
+		user := testutil.CreateTestUser(t, db)
+
+		_, err := svc.RequestEmailChange(user.ID, "race@example.com", "password123")
+		testutil.AssertNoError(t, err)
+		testutil.CreateTestUserWithEmail(t, db, "race@example.com")
+
+		_, err = svc.VerifyEmailChange(tokenFromMessage(t, sender.messages[0]))
+		testutil.AssertAppError(t, err, "DUPLICATE_EMAIL")
+	})
+}
+
+func TestPurgeExpiredEmailChanges(t *testing.T) {
+	t.Parallel()
+	db := testutil.WithTx(t)
+	svc := NewEmailChangeService(db, &recordingSender{})
+	expiredUser := testutil.CreateTestUser(t, db)
+	activeUser := testutil.CreateTestUser(t, db)
+
+	expired, err := svc.RequestEmailChange(expiredUser.ID, "old@example.com", "password123")
+	testutil.AssertNoError(t, err)
+	db.Model(expired).Update("expires_at", time.Now().Add(-time.Hour))
+	_, err = svc.RequestEmailChange(activeUser.ID, "fresh@example.com", "password123")
+	testutil.AssertNoError(t, err)
+
+	purged, err := svc.PurgeExpiredEmailChanges(time.Now())
+	testutil.AssertNoError(t, err)
+
+	if purged != 1 {
+		t.Errorf("expected 1 purged, got %d", purged)
+	}
+	var remaining []models.PendingEmailChange
+	db.Unscoped().Find(&remaining)
+	if len(remaining) != 1 || remaining[0].UserID != activeUser.ID {
+		t.Errorf("expected only the active change to remain, got %+v", remaining)
+	}
+}
+
+func TestCreateUser_RejectsPendingEmail(t *testing.T) {
+	t.Parallel()
+	t.Run("pending", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		user := testutil.CreateTestUser(t, db)
+
+		_, err := NewEmailChangeService(db, &recordingSender{}).RequestEmailChange(user.ID, "pending@example.com", "password123")
+		testutil.AssertNoError(t, err)
+
+		_, err = NewUserService(db).CreateUser("Pending@example.com", "password123", "", "")
+		testutil.AssertAppError(t, err, "DUPLICATE_EMAIL")
+	})
+
+	t.Run("expired_pending_does_not_block", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		user := testutil.CreateTestUser(t, db)
+
+		pending, err := NewEmailChangeService(db, &recordingSender{}).RequestEmailChange(user.ID, "stale@example.com", "password123")
+		testutil.AssertNoError(t, err)
+		db.Model(pending).Update("expires_at", time.Now().Add(-time.Hour))
+
+		_, err = NewUserService(db).CreateUser("stale@example.com", "password123", "", "")
+		testutil.AssertNoError(t, err)
+	})
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/logger"
+	"kuberan/internal/models"
+)
+
+// Domain event types written to the outbox.
+const (
+	EventTransactionCreated = "transaction.created"
+	EventBudgetExceeded     = "budget.exceeded"
+	EventInvestmentSold     = "investment.sold"
+	EventPriceRecorded      = "price.recorded"
+)
+
+const (
+	// DefaultEventBatchSize is how many events one dispatch delivers when no limit is given.
+	DefaultEventBatchSize = 100
+	// MaxEventBatchSize bounds the events one dispatch delivers.
+	MaxEventBatchSize = 1000
+	// MaxEventAttempts is how often delivery of an event is tried before it is
+	// left for an operator to inspect.
+	MaxEventAttempts = 10
+	// eventClaimTTL is how long a dispatcher holds an event it is delivering.
+	// An event whose dispatcher died mid-delivery is retried once it lapses.
+	eventClaimTTL = 5 * time.Minute
+)
+
+// DomainEvent is an event payload that can be appended to the outbox.
+// EventVersion is bumped whenever a field is removed or changes meaning, so
+// consumers can tell rows written by an older release apart.
+type DomainEvent interface {
+	EventType() string
+	EventVersion() int
+}
+
+// TransactionCreatedEvent is emitted for every transaction and transfer leg
+// created through the transaction service.
+type TransactionCreatedEvent struct {
+	TransactionID   string                 `json:"transaction_id"`
+	UserID          string                 `json:"user_id"`
+	AccountID       string                 `json:"account_id"`
+	ToAccountID     *string                `json:"to_account_id,omitempty"`
+	TransferGroupID *string                `json:"transfer_group_id,omitempty"`
+	CategoryID      *string                `json:"category_id,omitempty"`
+	Type            models.TransactionType `json:"type"`
+	Amount          int64                  `json:"amount"`
+	Date            time.Time              `json:"date"`
+	IsPending       bool                   `json:"is_pending"`
+}
+
+func (TransactionCreatedEvent) EventType() string { return EventTransactionCreated }
+func (TransactionCreatedEvent) EventVersion() int { return 1 }
+
+// BudgetExceededEvent is emitted when an expense pushes a budget over its amount.
+type BudgetExceededEvent struct {
+	UserID        string              `json:"user_id"`
+	TransactionID string              `json:"transaction_id"`
+	BudgetID      string              `json:"budget_id"`
+	BudgetName    string              `json:"budget_name"`
+	CategoryName  string              `json:"category_name"`
+	Period        models.BudgetPeriod `json:"period"`
+	Budgeted      int64               `json:"budgeted"`
+	Spent         int64               `json:"spent"`
+	Currency      string              `json:"currency"`
+}
+
+func (BudgetExceededEvent) EventType() string { return EventBudgetExceeded }
+func (BudgetExceededEvent) EventVersion() int { return 1 }
+
+// InvestmentSoldEvent is emitted when a sell is recorded on a holding.
+type InvestmentSoldEvent struct {
+	UserID                  string    `json:"user_id"`
+	InvestmentID            string    `json:"investment_id"`
+	InvestmentTransactionID string    `json:"investment_transaction_id"`
+	SecurityID              string    `json:"security_id"`
+	Quantity                float64   `json:"quantity"`
+	PricePerUnit            int64     `json:"price_per_unit"`
+	TotalAmount             int64     `json:"total_amount"`
+	RealizedGainLoss        int64     `json:"realized_gain_loss"`
+	Date                    time.Time `json:"date"`
+}
+
+func (InvestmentSoldEvent) EventType() string { return EventInvestmentSold }
+func (InvestmentSoldEvent) EventVersion() int { return 1 }
+
+// PriceRecordedEvent is emitted when a security price is inserted or changed.
+type PriceRecordedEvent struct {
+	SecurityID string             `json:"security_id"`
+	Price      int64              `json:"price"`
+	RecordedAt time.Time          `json:"recorded_at"`
+	Source     models.PriceSource `json:"source"`
+}
+
+func (PriceRecordedEvent) EventType() string { return EventPriceRecorded }
+func (PriceRecordedEvent) EventVersion() int { return 1 }
+
+// recordEvent appends an event to the outbox. It must be given the database
+// transaction of the change the event describes, so that the event is rolled
+// back with it.
+func recordEvent(tx *gorm.DB, event DomainEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	row := models.OutboxEvent{
+		Type:    event.EventType(),
+		Version: event.EventVersion(),
+		Payload: string(payload),
+	}
+	if err := tx.Create(&row).Error; err != nil {
+		return apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return nil
+}
+
+// DecodeEvent unmarshals an outbox row into the payload type a consumer
+// expects. Rows of another type, or written with a newer payload version than
+// into understands, are rejected rather than half-decoded.
+func DecodeEvent(event *models.OutboxEvent, into DomainEvent) error {
+	if event.Type != into.EventType() {
+		return fmt.Errorf("event %s has type %q, expected %q", event.ID, event.Type, into.EventType())
+	}
+	if event.Version > into.EventVersion() {
+		return fmt.Errorf("event %s has payload version %d, newer than the supported %d", event.ID, event.Version, into.EventVersion())
+	}
+	return json.Unmarshal([]byte(event.Payload), into)
+}
+
+// eventDispatcher delivers outbox events to in-process consumers.
+type eventDispatcher struct {
+	db *gorm.DB
+
+	mu        sync.RWMutex
+	consumers map[string][]EventConsumer
+
+	// dispatching serializes DispatchPending within this process; other
+	// processes are kept apart by the claim lease
+	dispatching sync.Mutex
+}
+
+// NewEventDispatcher creates a new EventDispatcherServicer with no consumers.
+func NewEventDispatcher(db *gorm.DB) EventDispatcherServicer {
+	return &eventDispatcher{db: db, consumers: make(map[string][]EventConsumer)}
+}
+
+// Subscribe registers a consumer for an event type. Delivery is at least
+// once: an event is redelivered to every consumer of its type until all of
+// them accept it in the same round, so consumers must be idempotent.
+func (d *eventDispatcher) Subscribe(eventType string, consumer EventConsumer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.consumers[eventType] = append(d.consumers[eventType], consumer)
+}
+
+// DispatchPending delivers up to limit undelivered events, oldest first. A
+// consumer error is stored on the event and the event is retried on a later
+// dispatch, until MaxEventAttempts is reached. Events with no consumers are
+// marked processed straight away.
+func (d *eventDispatcher) DispatchPending(limit int) (*EventDispatchResult, error) {
+	if limit <= 0 {
+		limit = DefaultEventBatchSize
+	}
+	if limit > MaxEventBatchSize {
+		limit = MaxEventBatchSize
+	}
+
+	d.dispatching.Lock()
+	defer d.dispatching.Unlock()
+
+	events, err := d.claim(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &EventDispatchResult{}
+	for i := range events {
+		event := &events[i]
+		updates := map[string]interface{}{
+			"attempts":      event.Attempts + 1,
+			"claimed_until": nil,
+		}
+		if deliverErr := d.deliver(event); deliverErr != nil {
+			logger.Get().Warnw("event delivery failed",
+				"event_id", event.ID, "type", event.Type, "attempt", event.Attempts+1, "error", deliverErr)
+			updates["last_error"] = deliverErr.Error()
+			result.Failed++
+		} else {
+			updates["last_error"] = ""
+			updates["processed_at"] = time.Now()
+			result.Delivered++
+		}
+		if err := d.db.Model(&models.OutboxEvent{}).Where("id = ?", event.ID).Updates(updates).Error; err != nil {
+			// The event stays claimed and is redelivered once the lease lapses
+			return result, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+	}
+	return result, nil
+}
+
+// Run dispatches pending events every interval until ctx is cancelled.
+func (d *eventDispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := d.DispatchPending(DefaultEventBatchSize); err != nil {
+				logger.Get().Errorw("event dispatch failed", "error", err)
+			}
+		}
+	}
+}
+
+// claim leases up to limit undelivered events to this dispatcher so that
+// another process dispatching at the same time skips them.
+func (d *eventDispatcher) claim(limit int) ([]models.OutboxEvent, error) {
+	now := time.Now()
+	var events []models.OutboxEvent
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		if err := skipLocked(tx).
+			Where("processed_at IS NULL AND attempts < ? AND (claimed_until IS NULL OR claimed_until < ?)", MaxEventAttempts, now).
+			Order("created_at, id").Limit(limit).
+			Find(&events).Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+		ids := make([]string, len(events))
+		for i := range events {
+			ids[i] = events[i].ID
+		}
+		return tx.Model(&models.OutboxEvent{}).Where("id IN ?", ids).
+			Update("claimed_until", now.Add(eventClaimTTL)).Error
+	})
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return events, nil
+}
+
+// deliver hands an event to each of its consumers, stopping at the first error.
+func (d *eventDispatcher) deliver(event *models.OutboxEvent) error {
+	d.mu.RLock()
+	consumers := d.consumers[event.Type]
+	d.mu.RUnlock()
+
+	for _, consume := range consumers {
+		if err := consume(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipLocked locks the selected rows and skips rows another transaction has
+// locked. SQLite has no row locks, so the clause is skipped there.
+func skipLocked(tx *gorm.DB) *gorm.DB {
+	if tx.Dialector.Name() == "sqlite" {
+		return tx
+	}
+	return tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+}
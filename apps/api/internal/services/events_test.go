@@ -0,0 +1,304 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"kuberan/internal/models"
+	"kuberan/internal/testutil"
+)
+
+// outboxEvents returns the outbox rows of the given type, oldest first.
+func outboxEvents(t *testing.T, db *gorm.DB, eventType string) []models.OutboxEvent {
+	t.Helper()
+	var events []models.OutboxEvent
+	if err := db.Where("type = ?", eventType).Order("created_at, id").Find(&events).Error; err != nil {
+		t.Fatalf("failed to load outbox events: %v", err)
+	}
+	return events
+}
+
+var errInjected = errors.New("injected failure")
+
+// failUpdatesOn makes every UPDATE of the given table fail, to abort the
+// surrounding DB transaction after its inserts have run.
+func failUpdatesOn(t *testing.T, db *gorm.DB, table string) {
+	t.Helper()
+	err := db.Callback().Update().Before("gorm:update").Register("test:fail_"+table, func(d *gorm.DB) {
+		if d.Statement.Schema != nil && d.Statement.Schema.Table == table {
+			_ = d.AddError(errInjected)
+		}
+	})
+	if err != nil {
+		t.Fatalf("failed to register callback: %v", err)
+	}
+}
+
+func TestDomainEvents(t *testing.T) {
+	t.Parallel()
+	t.Run("transaction_created", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		txSvc := NewTransactionService(db, NewAccountService(db, nil), nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 2500, "Pay", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		events := outboxEvents(t, db, EventTransactionCreated)
+		if len(events) != 1 {
+			t.Fatalf("expected 1 event, got %d", len(events))
+		}
+		if events[0].Version != 1 || events[0].ProcessedAt != nil {
+			t.Errorf("expected an unprocessed version 1 event, got %+v", events[0])
+		}
+		var payload TransactionCreatedEvent
+		testutil.AssertNoError(t, DecodeEvent(&events[0], &payload))
+		if payload.TransactionID != tx.ID || payload.AccountID != account.ID || payload.Amount != 2500 {
+			t.Errorf("unexpected payload %+v", payload)
+		}
+	})
+
+	t.Run("transaction_events_roll_back", func(t *testing.T) {
+		db := testutil.SetupTestDB(t)
+		t.Cleanup(func() { testutil.TeardownTestDB(t, db) })
+		txSvc := NewTransactionService(db, NewAccountService(db, nil), nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+
+		// The balance update runs after the transaction and its event are inserted
+		failUpdatesOn(t, db, "accounts")
+
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 5000, "Rent", time.Now(), false, "", nil)
+		if !errors.Is(err, errInjected) {
+			t.Fatalf("expected the injected failure, got %v", err)
+		}
+
+		var count int64
+		db.Model(&models.OutboxEvent{}).Count(&count)
+		if count != 0 {
+			t.Errorf("expected no outbox events, got %d", count)
+		}
+		db.Model(&models.Transaction{}).Count(&count)
+		if count != 0 {
+			t.Errorf("expected no transactions, got %d", count)
+		}
+	})
+
+	t.Run("budget_exceeded", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		txSvc := NewTransactionService(db, NewAccountService(db, nil), nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 50000)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		budget := testutil.CreateTestBudget(t, db, user.ID, cat.ID) // 10000
+
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &cat.ID, models.TransactionTypeExpense, 12000, "Shop", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		events := outboxEvents(t, db, EventBudgetExceeded)
+		if len(events) != 1 {
+			t.Fatalf("expected 1 event, got %d", len(events))
+		}
+		var payload BudgetExceededEvent
+		testutil.AssertNoError(t, DecodeEvent(&events[0], &payload))
+		if payload.BudgetID != budget.ID || payload.TransactionID != tx.ID || payload.Spent != 12000 {
+			t.Errorf("unexpected payload %+v", payload)
+		}
+	})
+
+	t.Run("split_transfer_emits_one_event_per_leg", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		txSvc := NewTransactionService(db, NewAccountService(db, nil), nil)
+		user := testutil.CreateTestUser(t, db)
+		from := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+		a := testutil.CreateTestCashAccount(t, db, user.ID)
+		b := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		_, err := txSvc.CreateSplitTransfer(models.UserID(user.ID), models.AccountID(from.ID), []SplitTransferLeg{
+			{ToAccountID: models.AccountID(a.ID), Amount: 1000},
+			{ToAccountID: models.AccountID(b.ID), Amount: 2000},
+		}, "", time.Now())
+		testutil.AssertNoError(t, err)
+
+		if events := outboxEvents(t, db, EventTransactionCreated); len(events) != 2 {
+			t.Errorf("expected 2 events, got %d", len(events))
+		}
+	})
+
+	t.Run("investment_sold", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		invSvc := NewInvestmentService(db, NewAccountService(db, nil), nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID) // 10 units, cost 100000
+
+		sell, err := invSvc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 4, 15000, 0, "", false)
+		testutil.AssertNoError(t, err)
+
+		events := outboxEvents(t, db, EventInvestmentSold)
+		if len(events) != 1 {
+			t.Fatalf("expected 1 event, got %d", len(events))
+		}
+		var payload InvestmentSoldEvent
+		testutil.AssertNoError(t, DecodeEvent(&events[0], &payload))
+		if payload.InvestmentTransactionID != sell.ID || payload.SecurityID != sec.ID || payload.RealizedGainLoss != 20000 {
+			t.Errorf("unexpected payload %+v", payload)
+		}
+	})
+
+	t.Run("investment_events_roll_back", func(t *testing.T) {
+		db := testutil.SetupTestDB(t)
+		t.Cleanup(func() { testutil.TeardownTestDB(t, db) })
+		invSvc := NewInvestmentService(db, NewAccountService(db, nil), nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
+
+		// The holding update runs after the sell and its event are inserted
+		failUpdatesOn(t, db, "investments")
+
+		_, err := invSvc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 4, 15000, 0, "", false)
+		if !errors.Is(err, errInjected) {
+			t.Fatalf("expected the injected failure, got %v", err)
+		}
+
+		var count int64
+		db.Model(&models.OutboxEvent{}).Count(&count)
+		if count != 0 {
+			t.Errorf("expected no outbox events, got %d", count)
+		}
+		db.Model(&models.InvestmentTransaction{}).Count(&count)
+		if count != 0 {
+			t.Errorf("expected no investment transactions, got %d", count)
+		}
+	})
+
+	t.Run("price_recorded_only_when_changed", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		secSvc := NewSecurityService(db, nil, 0)
+		sec := testutil.CreateTestSecurity(t, db)
+		at := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+		price := []SecurityPriceInput{{SecurityID: sec.ID, Price: 12345, RecordedAt: at}}
+		_, err := secSvc.RecordPrices(price)
+		testutil.AssertNoError(t, err)
+		_, err = secSvc.RecordPrices(price)
+		testutil.AssertNoError(t, err)
+
+		if events := outboxEvents(t, db, EventPriceRecorded); len(events) != 1 {
+			t.Errorf("expected 1 event for an unchanged re-record, got %d", len(events))
+		}
+	})
+
+	t.Run("decode_rejects_newer_version", func(t *testing.T) {
+		event := &models.OutboxEvent{Type: EventPriceRecorded, Version: 2, Payload: `{}`}
+		var payload PriceRecordedEvent
+		if err := DecodeEvent(event, &payload); err == nil {
+			t.Error("expected an error for a newer payload version")
+		}
+		if err := DecodeEvent(event, &TransactionCreatedEvent{}); err == nil {
+			t.Error("expected an error for a mismatched type")
+		}
+	})
+}
+
+func TestEventDispatcher(t *testing.T) {
+	t.Parallel()
+	// seed appends one event of each given type directly to the outbox.
+	seed := func(t *testing.T, db *gorm.DB, types ...string) {
+		t.Helper()
+		for _, eventType := range types {
+			testutil.AssertNoError(t, db.Create(&models.OutboxEvent{Type: eventType, Version: 1, Payload: `{}`}).Error)
+		}
+	}
+
+	t.Run("delivers_and_marks_processed", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		d := NewEventDispatcher(db)
+		seed(t, db, EventPriceRecorded, EventPriceRecorded, "unhandled.event")
+
+		var delivered int
+		d.Subscribe(EventPriceRecorded, func(*models.OutboxEvent) error {
+			delivered++
+			return nil
+		})
+
+		result, err := d.DispatchPending(0)
+		testutil.AssertNoError(t, err)
+		if result.Delivered != 3 || result.Failed != 0 {
+			t.Errorf("expected 3 delivered, got %+v", result)
+		}
+		if delivered != 2 {
+			t.Errorf("expected the consumer to see 2 events, got %d", delivered)
+		}
+
+		var pending int64
+		db.Model(&models.OutboxEvent{}).Where("processed_at IS NULL").Count(&pending)
+		if pending != 0 {
+			t.Errorf("expected every event processed, %d pending", pending)
+		}
+
+		// Processed events are not delivered again
+		result, err = d.DispatchPending(0)
+		testutil.AssertNoError(t, err)
+		if result.Delivered != 0 || delivered != 2 {
+			t.Errorf("expected no redelivery, got %+v and %d deliveries", result, delivered)
+		}
+	})
+
+	t.Run("failed_delivery_is_retried", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		d := NewEventDispatcher(db)
+		seed(t, db, EventInvestmentSold)
+
+		var calls int
+		d.Subscribe(EventInvestmentSold, func(*models.OutboxEvent) error {
+			calls++
+			if calls == 1 {
+				return errors.New("consumer down")
+			}
+			return nil
+		})
+
+		result, err := d.DispatchPending(0)
+		testutil.AssertNoError(t, err)
+		if result.Failed != 1 {
+			t.Fatalf("expected 1 failure, got %+v", result)
+		}
+		var event models.OutboxEvent
+		testutil.AssertNoError(t, db.First(&event).Error)
+		if event.ProcessedAt != nil || event.Attempts != 1 || event.LastError != "consumer down" || event.ClaimedUntil != nil {
+			t.Errorf("expected an unclaimed, unprocessed event with the error, got %+v", event)
+		}
+
+		result, err = d.DispatchPending(0)
+		testutil.AssertNoError(t, err)
+		if result.Delivered != 1 {
+			t.Fatalf("expected redelivery, got %+v", result)
+		}
+		testutil.AssertNoError(t, db.First(&event).Error)
+		if event.ProcessedAt == nil || event.Attempts != 2 || event.LastError != "" {
+			t.Errorf("expected a processed event after 2 attempts, got %+v", event)
+		}
+	})
+
+	t.Run("skips_claimed_and_exhausted_events", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		d := NewEventDispatcher(db)
+		claimed := time.Now().Add(time.Minute)
+		testutil.AssertNoError(t, db.Create(&models.OutboxEvent{Type: EventPriceRecorded, Payload: `{}`, ClaimedUntil: &claimed}).Error)
+		testutil.AssertNoError(t, db.Create(&models.OutboxEvent{Type: EventPriceRecorded, Payload: `{}`, Attempts: MaxEventAttempts}).Error)
+
+		result, err := d.DispatchPending(0)
+		testutil.AssertNoError(t, err)
+		if result.Delivered != 0 || result.Failed != 0 {
+			t.Errorf("expected nothing dispatched, got %+v", result)
+		}
+	})
+}
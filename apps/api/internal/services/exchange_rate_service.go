@@ -0,0 +1,213 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+)
+
+// crossCurrency is the currency two others are converted through when
+// neither a direct nor an inverse rate between them is recorded.
+const crossCurrency = "USD"
+
+type currencyPair struct{ from, to string }
+
+// exchangeRateService serves exchange rates from an in-memory snapshot of the
+// latest rate per currency pair. The snapshot is reloaded when it is older
+// than the TTL and after every write through this service; other API
+// instances pick up new rates once their own TTL lapses.
+type exchangeRateService struct {
+	db  *gorm.DB
+	ttl time.Duration
+	now func() time.Time
+
+	mu       sync.RWMutex
+	latest   map[currencyPair]models.ExchangeRate
+	loadedAt time.Time
+	stale    bool
+	writes   uint64 // bumped by every write, so a reload racing one stays stale
+}
+
+// NewExchangeRateService creates a new ExchangeRateServicer. A ttl of 0
+// reloads the snapshot from the database on every read.
+func NewExchangeRateService(db *gorm.DB, ttl time.Duration) ExchangeRateServicer {
+	return &exchangeRateService{db: db, ttl: ttl, now: time.Now, stale: true}
+}
+
+// RecordRates upserts exchange rates in one DB transaction; a rate for an
+// already recorded pair and timestamp replaces it. Returns how many entries
+// were recorded and marks the snapshot for reload.
+func (s *exchangeRateService) RecordRates(rates []ExchangeRateInput) (int, error) {
+	if len(rates) == 0 {
+		return 0, apperrors.WithMessage(apperrors.ErrInvalidInput, "rates array is empty")
+	}
+
+	rows := make([]models.ExchangeRate, len(rates))
+	for i, r := range rates {
+		from, to := strings.ToUpper(r.FromCurrency), strings.ToUpper(r.ToCurrency)
+		if from == to {
+			return 0, apperrors.WithMessage(apperrors.ErrInvalidInput, "from and to currencies must differ")
+		}
+		if r.Rate <= 0 {
+			return 0, apperrors.WithMessage(apperrors.ErrInvalidInput, "rate must be greater than zero")
+		}
+		rows[i] = models.ExchangeRate{FromCurrency: from, ToCurrency: to, Rate: r.Rate, RecordedAt: r.RecordedAt}
+	}
+
+	upsert := clause.OnConflict{
+		Columns:   []clause.Column{{Name: "from_currency"}, {Name: "to_currency"}, {Name: "recorded_at"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate"}),
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for i := range rows {
+			if err := tx.Clauses(upsert).Create(&rows[i]).Error; err != nil {
+				return apperrors.Wrap(apperrors.ErrInternalServer, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	s.stale = true
+	s.writes++
+	s.mu.Unlock()
+	return len(rows), nil
+}
+
+// GetRate returns how many units of to one unit of from buys and when that
+// rate was recorded. A missing pair falls back to the inverse of the opposite
+// pair, then to a cross rate through USD (each leg direct or inverse), whose
+// asOf is the older of its two legs.
+func (s *exchangeRateService) GetRate(from, to string) (float64, time.Time, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return 1, s.now(), nil
+	}
+
+	latest, err := s.snapshot()
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	if rate, asOf, ok := resolveRate(latest, from, to); ok {
+		return rate, asOf, nil
+	}
+	return 0, time.Time{}, apperrors.WithMessage(apperrors.ErrExchangeRateNotFound,
+		"no exchange rate from "+from+" to "+to)
+}
+
+// ListRates returns the latest recorded rate of every pair. With a base
+// currency it instead returns the rate from base to every other currency that
+// has a recorded rate, resolved with the same fallbacks as GetRate; currencies
+// that cannot be reached are left out.
+func (s *exchangeRateService) ListRates(base string) ([]ExchangeRateQuote, error) {
+	latest, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	quotes := make([]ExchangeRateQuote, 0, len(latest))
+	if base == "" {
+		for pair, r := range latest {
+			quotes = append(quotes, ExchangeRateQuote{From: pair.from, To: pair.to, Rate: r.Rate, AsOf: r.RecordedAt})
+		}
+	} else {
+		base = strings.ToUpper(base)
+		currencies := make(map[string]bool)
+		for pair := range latest {
+			currencies[pair.from] = true
+			currencies[pair.to] = true
+		}
+		delete(currencies, base)
+		for currency := range currencies {
+			if rate, asOf, ok := resolveRate(latest, base, currency); ok {
+				quotes = append(quotes, ExchangeRateQuote{From: base, To: currency, Rate: rate, AsOf: asOf})
+			}
+		}
+	}
+
+	sort.Slice(quotes, func(i, j int) bool {
+		if quotes[i].From != quotes[j].From {
+			return quotes[i].From < quotes[j].From
+		}
+		return quotes[i].To < quotes[j].To
+	})
+	return quotes, nil
+}
+
+// snapshot returns the latest rate per pair, reloading it when it is stale.
+func (s *exchangeRateService) snapshot() (map[currencyPair]models.ExchangeRate, error) {
+	now := s.now()
+	s.mu.RLock()
+	if !s.stale && s.ttl > 0 && now.Sub(s.loadedAt) < s.ttl {
+		latest := s.latest
+		s.mu.RUnlock()
+		return latest, nil
+	}
+	writes := s.writes
+	s.mu.RUnlock()
+
+	var rows []models.ExchangeRate
+	err := s.db.Raw(`
+		SELECT r.* FROM exchange_rates r
+		JOIN (
+			SELECT from_currency, to_currency, MAX(recorded_at) AS recorded_at
+			FROM exchange_rates GROUP BY from_currency, to_currency
+		) l ON r.from_currency = l.from_currency AND r.to_currency = l.to_currency AND r.recorded_at = l.recorded_at`).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	latest := make(map[currencyPair]models.ExchangeRate, len(rows))
+	for _, r := range rows {
+		latest[currencyPair{r.FromCurrency, r.ToCurrency}] = r
+	}
+
+	s.mu.Lock()
+	s.latest, s.loadedAt, s.stale = latest, now, s.writes != writes
+	s.mu.Unlock()
+	return latest, nil
+}
+
+// resolveRate converts from→to using a direct rate, the inverse of the
+// opposite pair, or a cross rate through crossCurrency, in that order.
+func resolveRate(latest map[currencyPair]models.ExchangeRate, from, to string) (float64, time.Time, bool) {
+	if rate, asOf, ok := directOrInverse(latest, from, to); ok {
+		return rate, asOf, true
+	}
+	if from == crossCurrency || to == crossCurrency {
+		return 0, time.Time{}, false
+	}
+
+	toCross, asOf1, ok1 := directOrInverse(latest, from, crossCurrency)
+	fromCross, asOf2, ok2 := directOrInverse(latest, crossCurrency, to)
+	if !ok1 || !ok2 {
+		return 0, time.Time{}, false
+	}
+	asOf := asOf1
+	if asOf2.Before(asOf) {
+		asOf = asOf2
+	}
+	return toCross * fromCross, asOf, true
+}
+
+// directOrInverse looks up from→to, falling back to 1 / (to→from).
+func directOrInverse(latest map[currencyPair]models.ExchangeRate, from, to string) (float64, time.Time, bool) {
+	if r, ok := latest[currencyPair{from, to}]; ok {
+		return r.Rate, r.RecordedAt, true
+	}
+	if r, ok := latest[currencyPair{to, from}]; ok && r.Rate > 0 {
+		return 1 / r.Rate, r.RecordedAt, true
+	}
+	return 0, time.Time{}, false
+}
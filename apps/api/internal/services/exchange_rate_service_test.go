@@ -0,0 +1,184 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"kuberan/internal/models"
+	"kuberan/internal/testutil"
+)
+
+func TestExchangeRateService(t *testing.T) {
+	t.Parallel()
+	day1 := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	approx := func(t *testing.T, got, want float64) {
+		t.Helper()
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("expected rate %v, got %v", want, got)
+		}
+	}
+
+	t.Run("direct_rate", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewExchangeRateService(db, time.Minute)
+
+		_, err := svc.RecordRates([]ExchangeRateInput{
+			{FromCurrency: "USD", ToCurrency: "MYR", Rate: 4.5, RecordedAt: day1},
+			{FromCurrency: "usd", ToCurrency: "myr", Rate: 4.7, RecordedAt: day2},
+		})
+		testutil.AssertNoError(t, err)
+
+		rate, asOf, err := svc.GetRate("USD", "MYR")
+		testutil.AssertNoError(t, err)
+		approx(t, rate, 4.7)
+		if !asOf.Equal(day2) {
+			t.Errorf("expected the latest rate, as of %v, got %v", day2, asOf)
+		}
+	})
+
+	t.Run("inverse_fallback", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewExchangeRateService(db, time.Minute)
+
+		_, err := svc.RecordRates([]ExchangeRateInput{{FromCurrency: "USD", ToCurrency: "MYR", Rate: 4, RecordedAt: day1}})
+		testutil.AssertNoError(t, err)
+
+		rate, asOf, err := svc.GetRate("MYR", "USD")
+		testutil.AssertNoError(t, err)
+		approx(t, rate, 0.25)
+		if !asOf.Equal(day1) {
+			t.Errorf("expected as of %v, got %v", day1, asOf)
+		}
+	})
+
+	t.Run("usd_cross_rate", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewExchangeRateService(db, time.Minute)
+
+		// MYR→USD only exists as its inverse; USD→SGD is direct
+		_, err := svc.RecordRates([]ExchangeRateInput{
+			{FromCurrency: "USD", ToCurrency: "MYR", Rate: 4, RecordedAt: day1},
+			{FromCurrency: "USD", ToCurrency: "SGD", Rate: 1.3, RecordedAt: day2},
+		})
+		testutil.AssertNoError(t, err)
+
+		rate, asOf, err := svc.GetRate("MYR", "SGD")
+		testutil.AssertNoError(t, err)
+		approx(t, rate, 0.25*1.3)
+		if !asOf.Equal(day1) {
+			t.Errorf("expected the older leg's date %v, got %v", day1, asOf)
+		}
+
+		rate, _, err = svc.GetRate("SGD", "MYR")
+		testutil.AssertNoError(t, err)
+		approx(t, rate, 4/1.3)
+	})
+
+	t.Run("direct_preferred_over_cross", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewExchangeRateService(db, time.Minute)
+
+		_, err := svc.RecordRates([]ExchangeRateInput{
+			{FromCurrency: "USD", ToCurrency: "MYR", Rate: 4, RecordedAt: day1},
+			{FromCurrency: "USD", ToCurrency: "SGD", Rate: 1.3, RecordedAt: day1},
+			{FromCurrency: "SGD", ToCurrency: "MYR", Rate: 3.5, RecordedAt: day1},
+		})
+		testutil.AssertNoError(t, err)
+
+		rate, _, err := svc.GetRate("SGD", "MYR")
+		testutil.AssertNoError(t, err)
+		approx(t, rate, 3.5)
+	})
+
+	t.Run("missing_pair", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewExchangeRateService(db, time.Minute)
+
+		_, err := svc.RecordRates([]ExchangeRateInput{{FromCurrency: "USD", ToCurrency: "MYR", Rate: 4, RecordedAt: day1}})
+		testutil.AssertNoError(t, err)
+
+		// SGD has no rate at all, so not even a cross rate can be built
+		_, _, err = svc.GetRate("MYR", "SGD")
+		testutil.AssertAppError(t, err, "EXCHANGE_RATE_NOT_FOUND")
+		_, _, err = svc.GetRate("USD", "EUR")
+		testutil.AssertAppError(t, err, "EXCHANGE_RATE_NOT_FOUND")
+	})
+
+	t.Run("same_currency", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewExchangeRateService(db, time.Minute)
+
+		rate, _, err := svc.GetRate("EUR", "eur")
+		testutil.AssertNoError(t, err)
+		approx(t, rate, 1)
+	})
+
+	t.Run("snapshot_refreshed_on_write_and_ttl", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewExchangeRateService(db, time.Minute)
+		now := day2
+		svc.(*exchangeRateService).now = func() time.Time { return now }
+
+		_, err := svc.RecordRates([]ExchangeRateInput{{FromCurrency: "USD", ToCurrency: "MYR", Rate: 4, RecordedAt: day1}})
+		testutil.AssertNoError(t, err)
+		rate, _, err := svc.GetRate("USD", "MYR")
+		testutil.AssertNoError(t, err)
+		approx(t, rate, 4)
+
+		// A write through the service is visible straight away
+		_, err = svc.RecordRates([]ExchangeRateInput{{FromCurrency: "USD", ToCurrency: "MYR", Rate: 4.2, RecordedAt: day2}})
+		testutil.AssertNoError(t, err)
+		rate, _, err = svc.GetRate("USD", "MYR")
+		testutil.AssertNoError(t, err)
+		approx(t, rate, 4.2)
+
+		// A write by another instance is only seen once the TTL lapses
+		later := models.ExchangeRate{FromCurrency: "USD", ToCurrency: "MYR", Rate: 4.4, RecordedAt: day2.Add(time.Hour)}
+		testutil.AssertNoError(t, db.Create(&later).Error)
+		rate, _, _ = svc.GetRate("USD", "MYR")
+		approx(t, rate, 4.2)
+
+		now = now.Add(2 * time.Minute)
+		rate, _, err = svc.GetRate("USD", "MYR")
+		testutil.AssertNoError(t, err)
+		approx(t, rate, 4.4)
+	})
+
+	t.Run("list_rates_from_base", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewExchangeRateService(db, time.Minute)
+
+		_, err := svc.RecordRates([]ExchangeRateInput{
+			{FromCurrency: "USD", ToCurrency: "MYR", Rate: 4, RecordedAt: day1},
+			{FromCurrency: "USD", ToCurrency: "SGD", Rate: 1.25, RecordedAt: day1},
+		})
+		testutil.AssertNoError(t, err)
+
+		all, err := svc.ListRates("")
+		testutil.AssertNoError(t, err)
+		if len(all) != 2 || all[0].To != "MYR" || all[1].To != "SGD" {
+			t.Errorf("expected the 2 recorded pairs, got %+v", all)
+		}
+
+		fromMYR, err := svc.ListRates("myr")
+		testutil.AssertNoError(t, err)
+		if len(fromMYR) != 2 || fromMYR[0].To != "SGD" || fromMYR[1].To != "USD" {
+			t.Fatalf("expected rates to SGD and USD, got %+v", fromMYR)
+		}
+		approx(t, fromMYR[0].Rate, 0.25*1.25)
+		approx(t, fromMYR[1].Rate, 0.25)
+	})
+
+	t.Run("rejects_invalid_rates", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewExchangeRateService(db, time.Minute)
+
+		_, err := svc.RecordRates([]ExchangeRateInput{{FromCurrency: "USD", ToCurrency: "MYR", Rate: 0, RecordedAt: day1}})
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+		_, err = svc.RecordRates([]ExchangeRateInput{{FromCurrency: "USD", ToCurrency: "usd", Rate: 1, RecordedAt: day1}})
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+}
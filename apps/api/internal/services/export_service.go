@@ -0,0 +1,108 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"gorm.io/gorm"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+)
+
+// exportBatchSize is how many rows of a section are loaded at a time.
+const exportBatchSize = 500
+
+// exportService writes full per-user data dumps.
+type exportService struct {
+	db *gorm.DB
+}
+
+// NewExportService creates a new ExportServicer.
+func NewExportService(db *gorm.DB) ExportServicer {
+	return &exportService{db: db}
+}
+
+// ExportUserData writes the user's profile, accounts, categories, budgets,
+// transactions, investments and investment transactions to w as one JSON
+// object. Each section is read in batches and written as it is read, so large
+// histories are never held in memory. Secrets are left out by the models' JSON
+// tags. Nothing is written when the user does not exist; any later error leaves
+// the document truncated.
+func (s *exportService) ExportUserData(userID models.UserID, w io.Writer) error {
+	var user models.User
+	if err := s.db.Where("id = ?", string(userID)).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrUserNotFound
+		}
+		return apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	accountIDs := s.db.Model(&models.Account{}).Select("id").Where("user_id = ?", user.ID)
+	investmentIDs := s.db.Model(&models.Investment{}).Select("id").Where("account_id IN (?)", accountIDs)
+
+	out := &exportWriter{w: w}
+	out.printf(`{"exported_at":%q,"profile":`, time.Now().UTC().Format(time.RFC3339))
+	out.writeJSON(&user)
+	exportSection[models.Account](out, "accounts", s.db.Where("user_id = ?", user.ID))
+	exportSection[models.Category](out, "categories", s.db.Where("user_id = ?", user.ID))
+	exportSection[models.Budget](out, "budgets", s.db.Where("user_id = ?", user.ID))
+	exportSection[models.Transaction](out, "transactions", s.db.Where("user_id = ?", user.ID))
+	exportSection[models.Investment](out, "investments", s.db.Preload("Security").Where("account_id IN (?)", accountIDs))
+	exportSection[models.InvestmentTransaction](out, "investment_transactions", s.db.Where("investment_id IN (?)", investmentIDs))
+	out.printf("}")
+	return out.err
+}
+
+// exportSection writes `,"name":[...]` with the rows of query, loaded in
+// batches ordered by primary key.
+func exportSection[T any](out *exportWriter, name string, query *gorm.DB) {
+	if out.err != nil {
+		return
+	}
+	out.printf(",%q:[", name)
+	written := 0
+	var batch []T
+	result := query.FindInBatches(&batch, exportBatchSize, func(_ *gorm.DB, _ int) error {
+		for i := range batch {
+			if written > 0 {
+				out.printf(",")
+			}
+			out.writeJSON(&batch[i])
+			written++
+		}
+		return out.err
+	})
+	if result.Error != nil && out.err == nil {
+		out.err = apperrors.Wrap(apperrors.ErrInternalServer, result.Error)
+	}
+	out.printf("]")
+}
+
+// exportWriter keeps the first write error so a dump can be written without
+// checking every call.
+type exportWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (e *exportWriter) printf(format string, args ...interface{}) {
+	if e.err == nil {
+		_, e.err = fmt.Fprintf(e.w, format, args...)
+	}
+}
+
+func (e *exportWriter) writeJSON(v interface{}) {
+	if e.err != nil {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		e.err = err
+		return
+	}
+	_, e.err = e.w.Write(data)
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"kuberan/internal/models"
+	"kuberan/internal/testutil"
+	"kuberan/internal/uuid"
+)
+
+func TestExportUserData(t *testing.T) {
+	t.Parallel()
+
+	type dump struct {
+		Profile                models.User                    `json:"profile"`
+		Accounts               []models.Account               `json:"accounts"`
+		Categories             []models.Category              `json:"categories"`
+		Budgets                []models.Budget                `json:"budgets"`
+		Transactions           []models.Transaction           `json:"transactions"`
+		Investments            []models.Investment            `json:"investments"`
+		InvestmentTransactions []models.InvestmentTransaction `json:"investment_transactions"`
+	}
+
+	t.Run("contains_only_the_users_records", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewExportService(db)
+
+		seed := func(userID string) (*models.Account, *models.Transaction, *models.Investment) {
+			account := testutil.CreateTestCashAccount(t, db, userID)
+			category := testutil.CreateTestCategory(t, db, userID, models.CategoryTypeExpense)
+			testutil.CreateTestBudget(t, db, userID, category.ID)
+			tx := testutil.CreateTestTransaction(t, db, userID, account.ID, models.TransactionTypeExpense, 1500)
+			invAccount := testutil.CreateTestInvestmentAccount(t, db, userID)
+			sec := testutil.CreateTestSecurityWithParams(t, db, "SYM"+userID[len(userID)-4:], "Security", models.AssetTypeStock, "NYSE")
+			inv := testutil.CreateTestInvestment(t, db, invAccount.ID, sec.ID)
+			testutil.AssertNoError(t, db.Create(&models.InvestmentTransaction{InvestmentID: inv.ID, Type: models.InvestmentTransactionBuy,
+				Date: time.Now(), Quantity: 1, PricePerUnit: 1000, TotalAmount: 1000}).Error)
+			return account, tx, inv
+		}
+
+		user := testutil.CreateTestUser(t, db)
+		account, tx, inv := seed(user.ID)
+		other := testutil.CreateTestUser(t, db)
+		otherAccount, otherTx, otherInv := seed(other.ID)
+
+		// Paging through more rows than one batch keeps every row
+		for i := 0; i < exportBatchSize; i++ {
+			testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeIncome, 1)
+		}
+
+		var buf bytes.Buffer
+		testutil.AssertNoError(t, svc.ExportUserData(models.UserID(user.ID), &buf))
+
+		var got dump
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("export is not valid JSON: %v", err)
+		}
+		if got.Profile.ID != user.ID {
+			t.Errorf("expected profile of %s, got %s", user.ID, got.Profile.ID)
+		}
+		if len(got.Accounts) != 2 || len(got.Categories) != 1 || len(got.Budgets) != 1 ||
+			len(got.Transactions) != exportBatchSize+1 || len(got.Investments) != 1 || len(got.InvestmentTransactions) != 1 {
+			t.Fatalf("expected 2 accounts, 1 category, 1 budget, %d transactions, 1 investment and 1 investment transaction; got %d, %d, %d, %d, %d, %d",
+				exportBatchSize+1, len(got.Accounts), len(got.Categories), len(got.Budgets), len(got.Transactions), len(got.Investments), len(got.InvestmentTransactions))
+		}
+		if got.Investments[0].ID != inv.ID || got.Investments[0].Security.Symbol == "" {
+			t.Errorf("expected the user's investment with its security, got %+v", got.Investments[0])
+		}
+		if got.InvestmentTransactions[0].InvestmentID != inv.ID {
+			t.Errorf("expected the user's investment transaction, got one for %s", got.InvestmentTransactions[0].InvestmentID)
+		}
+		found := false
+		for _, exported := range got.Transactions {
+			found = found || exported.ID == tx.ID
+		}
+		if !found {
+			t.Errorf("expected transaction %s in the export", tx.ID)
+		}
+
+		raw := buf.String()
+		for _, foreign := range []string{other.ID, otherAccount.ID, otherTx.ID, otherInv.ID} {
+			if strings.Contains(raw, foreign) {
+				t.Errorf("export contains another user's record %s", foreign)
+			}
+		}
+		if strings.Contains(raw, user.Password) {
+			t.Error("export contains the password hash")
+		}
+	})
+
+	t.Run("unknown_user", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewExportService(db)
+
+		var buf bytes.Buffer
+		err := svc.ExportUserData(models.UserID(uuid.New()), &buf)
+		testutil.AssertAppError(t, err, "USER_NOT_FOUND")
+		if buf.Len() != 0 {
+			t.Errorf("expected nothing written, got %q", buf.String())
+		}
+	})
+}
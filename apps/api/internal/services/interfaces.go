@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"io"
 	"time"
 
 	"gorm.io/gorm"
@@ -9,39 +11,159 @@ import (
 	"kuberan/internal/pagination"
 )
 
+// ProfileUpdateFields holds optional fields for updating a user's profile.
+// Nil pointer means "don't change"; non-nil means "set to this value".
+// Preferences holds a raw JSON object that replaces the stored blob.
+type ProfileUpdateFields struct {
+	DisplayName               *string
+	Username                  *string
+	BaseCurrency              *string
+	Locale                    *string
+	WeekStart                 *models.WeekStart
+	Timezone                  *string
+	CostBasisMethod           *models.CostBasisMethod
+	Preferences               *string
+	LargeTransactionThreshold *int64
+}
+
 // UserServicer defines the contract for user-related business logic.
 type UserServicer interface {
 	CreateUser(email, password, firstName, lastName string) (*models.User, error)
 	GetUserByEmail(email string) (*models.User, error)
 	GetUserByID(id string) (*models.User, error)
 	VerifyPassword(user *models.User, password string) bool
-	AttemptLogin(email, password string) (*models.User, error)
+	ChangePassword(userID, currentPassword, newPassword string) error
+	AttemptLogin(login, password string) (*models.User, error)
 	StoreRefreshTokenHash(userID string, tokenHash string) error
 	GetRefreshTokenHash(userID string) (string, error)
+	UpdateProfile(userID string, updates ProfileUpdateFields) (*models.User, error)
+}
+
+// ExportServicer defines the contract for full per-user data exports.
+type ExportServicer interface {
+	ExportUserData(userID models.UserID, w io.Writer) error
+}
+
+// BackupServicer defines the contract for restorable per-user backups.
+type BackupServicer interface {
+	CreateBackup(userID models.UserID) (*BackupArchive, error)
+	RestoreBackup(userID models.UserID, archive *BackupArchive, replace bool) (*RestoreReport, error)
+}
+
+// EmailChangeServicer defines the contract for verified login email changes.
+type EmailChangeServicer interface {
+	RequestEmailChange(userID, newEmail, password string) (*models.PendingEmailChange, error)
+	VerifyEmailChange(token string) (*models.User, error)
+	PurgeExpiredEmailChanges(now time.Time) (int, error)
 }
 
 // AccountUpdateFields holds optional fields for updating an account.
 // Nil pointer means "don't change"; non-nil means "set to this value".
 type AccountUpdateFields struct {
-	Name          *string
-	Description   *string
-	IsActive      *bool
-	Broker        *string    // investment only
-	AccountNumber *string    // investment only
-	InterestRate  *float64   // credit_card only
-	DueDate       *time.Time // credit_card only
-	CreditLimit   *int64     // credit_card only
+	Name               *string
+	Description        *string
+	IsActive           *bool
+	ExcludeFromReports *bool
+	DefaultCategoryID  **string   // nil = unchanged, pointer to nil = clear
+	GroupID            **string   // nil = unchanged, pointer to nil = ungroup
+	AllowNegative      *bool      // cash only
+	MinBalance         *int64     // cash only
+	Broker             *string    // investment only
+	AccountNumber      *string    // investment only
+	InterestRate       *float64   // credit_card only
+	DueDate            *time.Time // credit_card only
+	CreditLimit        *int64     // credit_card only
 }
 
 // AccountServicer defines the contract for account-related business logic.
 type AccountServicer interface {
-	CreateCashAccount(userID string, name, description, currency string, initialBalance int64) (*models.Account, error)
-	CreateInvestmentAccount(userID string, name, description, currency, broker, accountNumber string) (*models.Account, error)
-	CreateCreditCardAccount(userID string, name, description, currency string, creditLimit int64, interestRate float64, dueDate *time.Time) (*models.Account, error)
-	GetUserAccounts(userID string, page pagination.PageRequest) (*pagination.PageResponse[models.Account], error)
-	GetAccountByID(userID, accountID string) (*models.Account, error)
-	UpdateAccount(userID, accountID string, updates AccountUpdateFields) (*models.Account, error)
-	UpdateAccountBalance(tx *gorm.DB, account *models.Account, transactionType models.TransactionType, amount int64) error
+	CreateCashAccount(userID models.UserID, name, description, currency string, initialBalance models.Cents, allowNegative bool, minBalance models.Cents, openingDate *time.Time) (*models.Account, error)
+	CreateInvestmentAccount(userID models.UserID, name, description, currency, broker, accountNumber string) (*models.Account, error)
+	CreateCreditCardAccount(userID models.UserID, name, description, currency string, creditLimit models.Cents, interestRate float64, dueDate, openingDate *time.Time) (*models.Account, error)
+	GetUserAccounts(userID models.UserID, page pagination.PageRequest, includeInactive bool) (*pagination.PageResponse[models.Account], error)
+	GetArchivedAccounts(userID models.UserID, page pagination.PageRequest) (*pagination.PageResponse[models.Account], error)
+	GetGroupedAccounts(userID models.UserID, includeInactive bool) ([]GroupedAccounts, error)
+	GetAccountByID(userID models.UserID, accountID models.AccountID) (*models.Account, error)
+	GetWritableAccount(userID models.UserID, accountID models.AccountID) (*models.Account, error)
+	UpdateAccount(userID models.UserID, accountID models.AccountID, updates AccountUpdateFields) (*models.Account, error)
+	DeleteAccount(userID models.UserID, accountID models.AccountID, force bool) error
+	UpdateAccountBalance(tx *gorm.DB, account *models.Account, transactionType models.TransactionType, amount models.Cents) error
+	GetStatement(userID models.UserID, accountID models.AccountID, month time.Time) (*AccountStatement, error)
+	GetAccountTimeline(userID models.UserID, accountID models.AccountID, page pagination.PageRequest) (*pagination.PageResponse[AccountTimelineEntry], error)
+}
+
+// AccountStatement is one account's bank-style statement for a calendar month
+// (UTC). Amounts are in the account's minor units; for credit cards the
+// balance is the amount owed, as everywhere else.
+type AccountStatement struct {
+	AccountID      string                 `json:"account_id"`
+	AccountName    string                 `json:"account_name"`
+	Type           models.AccountType     `json:"type"`
+	Currency       string                 `json:"currency"`
+	Month          string                 `json:"month"` // YYYY-MM
+	PeriodStart    time.Time              `json:"period_start"`
+	PeriodEnd      time.Time              `json:"period_end"`
+	OpeningBalance int64                  `json:"opening_balance"`
+	ClosingBalance int64                  `json:"closing_balance"`
+	TotalIn        int64                  `json:"total_in"`  // sum of entries that raised the balance
+	TotalOut       int64                  `json:"total_out"` // sum of entries that lowered the balance, as a positive number
+	Lines          []AccountStatementLine `json:"lines"`
+}
+
+// AccountStatementLine is one settled transaction on an account statement.
+type AccountStatementLine struct {
+	TransactionID string                   `json:"transaction_id"`
+	Date          time.Time                `json:"date"`
+	Type          models.TransactionType   `json:"type"`
+	Description   string                   `json:"description"`
+	Category      string                   `json:"category,omitempty"`
+	Counterparty  string                   `json:"counterparty,omitempty"` // other account of a transfer
+	Status        models.TransactionStatus `json:"status"`
+	Amount        int64                    `json:"amount"`  // signed change to the balance
+	Balance       int64                    `json:"balance"` // running balance after this line
+}
+
+// AccountTimelineEntryKind says which kind of record an account timeline entry holds.
+type AccountTimelineEntryKind string
+
+// Account timeline entry kinds.
+const (
+	TimelineEntryTransaction           AccountTimelineEntryKind = "transaction"            // cash transaction, including transfers
+	TimelineEntryInvestmentTransaction AccountTimelineEntryKind = "investment_transaction" // buy, sell, dividend, split, or transfer of a holding
+)
+
+// AccountTimelineEntry is one event in an account's activity timeline. Exactly
+// one of Transaction and InvestmentTransaction is set, as given by Kind.
+type AccountTimelineEntry struct {
+	Kind                  AccountTimelineEntryKind      `json:"kind"`
+	Date                  time.Time                     `json:"date"`
+	Transaction           *models.Transaction           `json:"transaction,omitempty"`
+	InvestmentTransaction *models.InvestmentTransaction `json:"investment_transaction,omitempty"`
+}
+
+// GroupedAccounts is an account group with the accounts filed under it. The
+// default group for ungrouped accounts has an empty ID.
+type GroupedAccounts struct {
+	Group    models.AccountGroup `json:"group"`
+	Accounts []models.Account    `json:"accounts"`
+}
+
+// AccountGroupServicer defines the contract for organizing accounts into groups.
+type AccountGroupServicer interface {
+	CreateAccountGroup(userID, name string) (*models.AccountGroup, error)
+	GetAccountGroups(userID string) ([]models.AccountGroup, error)
+	GetAccountGroupByID(userID, groupID string) (*models.AccountGroup, error)
+	UpdateAccountGroup(userID, groupID, name string) (*models.AccountGroup, error)
+	DeleteAccountGroup(userID, groupID string) error
+	ReorderAccountGroups(userID string, groupIDs []string) ([]models.AccountGroup, error)
+}
+
+// ShareServicer defines the contract for sharing accounts with other users.
+type ShareServicer interface {
+	CreateShare(ownerID, email string, role models.ShareRole, accountIDs []string) (*models.AccountShare, error)
+	GetShares(userID string) ([]models.AccountShare, error)
+	AcceptShare(userID, shareID string) (*models.AccountShare, error)
+	DeleteShare(userID, shareID string) error
 }
 
 // CategoryServicer defines the contract for category-related business logic.
@@ -64,17 +186,51 @@ type TransactionUpdateFields struct {
 	Amount      *int64
 	Description *string
 	Date        *time.Time
+	Status      *models.TransactionStatus
+	Original    **OriginalAmount // nil = unchanged, pointer to nil = clear
+}
+
+// OriginalAmount is what a transaction cost in the currency it was made in,
+// kept for reference next to the amount in the account's currency.
+type OriginalAmount struct {
+	Amount   models.Cents // minor units of Currency
+	Currency string       // ISO 4217
 }
 
 // TransactionFilter holds optional filter parameters for listing transactions.
 type TransactionFilter struct {
-	FromDate   *time.Time
-	ToDate     *time.Time
-	Type       *models.TransactionType
-	CategoryID *string
-	MinAmount  *int64
-	MaxAmount  *int64
-	AccountID  *string
+	FromDate      *time.Time                `json:"from_date,omitempty"`
+	ToDate        *time.Time                `json:"to_date,omitempty"`
+	Type          *models.TransactionType   `json:"type,omitempty"`
+	CategoryID    *string                   `json:"category_id,omitempty"`
+	Uncategorized bool                      `json:"uncategorized,omitempty"` // only transactions without a category
+	MinAmount     *int64                    `json:"min_amount,omitempty"`
+	MaxAmount     *int64                    `json:"max_amount,omitempty"`
+	AccountID     *string                   `json:"account_id,omitempty"`
+	Pending       *bool                     `json:"pending,omitempty"`
+	Status        *models.TransactionStatus `json:"status,omitempty"`
+}
+
+// BulkDeleteSelection picks the transactions for a bulk delete: either explicit
+// IDs or a filter, which must bound the date range with FromDate and ToDate.
+type BulkDeleteSelection struct {
+	IDs    []string           `json:"ids,omitempty"`
+	Filter *TransactionFilter `json:"filter,omitempty"`
+}
+
+// BulkDeletePreview is the dry-run result of a bulk delete. ConfirmationToken
+// must be presented to execute it.
+type BulkDeletePreview struct {
+	Count             int64     `json:"count"`
+	TotalAmount       int64     `json:"total_amount"`
+	ConfirmationToken string    `json:"confirmation_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+// BulkDeleteResult reports an executed bulk delete and the selection it was previewed with.
+type BulkDeleteResult struct {
+	Deleted   int64               `json:"deleted"`
+	Selection BulkDeleteSelection `json:"-"`
 }
 
 // SpendingByCategoryItem represents spending total for a single category.
@@ -86,50 +242,181 @@ type SpendingByCategoryItem struct {
 	Total         int64   `json:"total"`
 }
 
+// SpendingMode selects which transactions a category breakdown totals.
+type SpendingMode string
+
+// Category breakdown modes.
+const (
+	SpendingModeExpense SpendingMode = "expense"
+	SpendingModeIncome  SpendingMode = "income"
+)
+
 // SpendingByCategory represents the full spending breakdown response.
+// In income mode TotalSpent holds the total income.
 type SpendingByCategory struct {
 	Items      []SpendingByCategoryItem `json:"items"`
 	TotalSpent int64                    `json:"total_spent"`
+	Mode       SpendingMode             `json:"mode"`
+	FromDate   time.Time                `json:"from_date"`
+	ToDate     time.Time                `json:"to_date"`
+}
+
+// SpendingByAccountItem represents expense total for a single account.
+type SpendingByAccountItem struct {
+	AccountID   string `json:"account_id"`
+	AccountName string `json:"account_name"`
+	IsActive    bool   `json:"is_active"`
+	Total       int64  `json:"total"` // cents
+	Count       int64  `json:"count"` // number of expenses
+}
+
+// SpendingByAccount represents the full spending breakdown by account.
+type SpendingByAccount struct {
+	Items      []SpendingByAccountItem `json:"items"`
+	TotalSpent int64                   `json:"total_spent"`
+	FromDate   time.Time               `json:"from_date"`
+	ToDate     time.Time               `json:"to_date"`
+}
+
+// SpendingByCurrencyItem represents expense totals for one spending currency:
+// the original currency of foreign-currency purchases, or the account's
+// currency for everything else.
+type SpendingByCurrencyItem struct {
+	Currency      string `json:"currency"`
+	OriginalTotal int64  `json:"original_total"` // minor units of Currency
+	Total         int64  `json:"total"`          // cents as booked in the accounts' currencies
+	Count         int64  `json:"count"`          // number of expenses
+}
+
+// SpendingByCurrency represents the full spending breakdown by currency.
+type SpendingByCurrency struct {
+	Items      []SpendingByCurrencyItem `json:"items"`
+	TotalSpent int64                    `json:"total_spent"`
 	FromDate   time.Time                `json:"from_date"`
 	ToDate     time.Time                `json:"to_date"`
 }
 
-// DailySpendingItem represents expense total for a single day.
+// SpendingGranularity is the bucket size of a spending series.
+type SpendingGranularity string
+
+// Spending series granularities.
+const (
+	SpendingGranularityDay   SpendingGranularity = "day"
+	SpendingGranularityWeek  SpendingGranularity = "week"
+	SpendingGranularityMonth SpendingGranularity = "month"
+)
+
+// DailySpendingItem represents expense total for a single day, week or month.
 type DailySpendingItem struct {
-	Date  string `json:"date"`  // "2026-02-01" format
+	Date  string `json:"date"`  // first day of the bucket, "2026-02-01" format
 	Total int64  `json:"total"` // cents
 }
 
+// MonthlyCategoryExpense represents expense total for a single category within a month.
+type MonthlyCategoryExpense struct {
+	CategoryID   *string `json:"category_id"`
+	CategoryName string  `json:"category_name"`
+	Total        int64   `json:"total"` // cents
+}
+
 // MonthlySummaryItem represents income and expense totals for a single month.
+// Categories is only populated when the breakdown is requested.
 type MonthlySummaryItem struct {
-	Month    string `json:"month"`    // "2026-02" format
-	Income   int64  `json:"income"`   // cents
-	Expenses int64  `json:"expenses"` // cents
+	Month      string                   `json:"month"`    // "2026-02" format
+	Income     int64                    `json:"income"`   // cents
+	Expenses   int64                    `json:"expenses"` // cents
+	Categories []MonthlyCategoryExpense `json:"categories,omitempty"`
+}
+
+// SplitTransferLeg is one destination of a split transfer.
+type SplitTransferLeg struct {
+	ToAccountID models.AccountID
+	Amount      models.Cents
 }
 
 // TransactionServicer defines the contract for transaction-related business logic.
 type TransactionServicer interface {
-	CreateTransaction(userID, accountID string, categoryID *string, transactionType models.TransactionType, amount int64, description string, date time.Time) (*models.Transaction, error)
-	CreateTransfer(userID, fromAccountID, toAccountID string, amount int64, description string, date time.Time) (*models.Transaction, error)
-	GetAccountTransactions(userID, accountID string, page pagination.PageRequest, filter TransactionFilter) (*pagination.PageResponse[models.Transaction], error)
-	GetUserTransactions(userID string, page pagination.PageRequest, filter TransactionFilter) (*pagination.PageResponse[models.Transaction], error)
-	GetTransactionByID(userID, transactionID string) (*models.Transaction, error)
-	UpdateTransaction(userID, transactionID string, updates TransactionUpdateFields) (*models.Transaction, error)
-	DeleteTransaction(userID, transactionID string) error
-	GetSpendingByCategory(userID string, from, to time.Time) (*SpendingByCategory, error)
-	GetMonthlySummary(userID string, months int) ([]MonthlySummaryItem, error)
-	GetDailySpending(userID string, from, to time.Time) ([]DailySpendingItem, error)
+	CreateTransaction(userID models.UserID, accountID models.AccountID, categoryID *string, transactionType models.TransactionType, amount models.Cents, description string, date time.Time, pending bool, status models.TransactionStatus, original *OriginalAmount) (*models.Transaction, error)
+	CreateTransfer(userID models.UserID, fromAccountID, toAccountID models.AccountID, amount models.Cents, description string, date time.Time) (*models.Transaction, error)
+	CreateSplitTransfer(userID models.UserID, fromAccountID models.AccountID, legs []SplitTransferLeg, description string, date time.Time) ([]models.Transaction, error)
+	GetAccountTransactions(userID models.UserID, accountID models.AccountID, page pagination.PageRequest, filter TransactionFilter) (*pagination.PageResponse[models.Transaction], error)
+	GetUserTransactions(userID models.UserID, page pagination.PageRequest, filter TransactionFilter) (*pagination.PageResponse[models.Transaction], error)
+	GetTransfers(userID models.UserID, page pagination.PageRequest, filter TransactionFilter) (*pagination.PageResponse[models.Transaction], error)
+	GetTransactionByID(userID models.UserID, transactionID models.TransactionID) (*models.Transaction, error)
+	UpdateTransaction(userID models.UserID, transactionID models.TransactionID, updates TransactionUpdateFields) (*models.Transaction, error)
+	DeleteTransaction(userID models.UserID, transactionID models.TransactionID) error
+	GetSpendingByCategory(ctx context.Context, userID models.UserID, from, to time.Time, mode SpendingMode, includeExcluded bool) (*SpendingByCategory, error)
+	GetSpendingByAccount(userID models.UserID, from, to time.Time, includeExcluded bool) (*SpendingByAccount, error)
+	GetSpendingByCurrency(ctx context.Context, userID models.UserID, from, to time.Time, includeExcluded bool) (*SpendingByCurrency, error)
+	GetMonthlySummary(ctx context.Context, userID models.UserID, months int, withCategories, includeExcluded bool) ([]MonthlySummaryItem, error)
+	GetDailySpending(ctx context.Context, userID models.UserID, from, to time.Time, granularity SpendingGranularity, includeExcluded bool) ([]DailySpendingItem, error)
+	SettlePendingTransactions(asOf time.Time) (int, error)
+	GetFlaggedTransactions(userID models.UserID, page pagination.PageRequest) (*pagination.PageResponse[models.Transaction], error)
+	PreviewBulkDelete(userID models.UserID, selection BulkDeleteSelection) (*BulkDeletePreview, error)
+	ExecuteBulkDelete(userID models.UserID, confirmationToken string) (*BulkDeleteResult, error)
+	BulkUpdateCategory(userID models.UserID, filter TransactionFilter, categoryID *string) (int64, error)
+	UserLocation(userID models.UserID) (*time.Location, error)
 }
 
 // BudgetProgress contains spending vs budget data for a budget's current period.
 type BudgetProgress struct {
-	BudgetID   string  `json:"budget_id"`
-	Budgeted   int64   `json:"budgeted"`
+	BudgetID    string     `json:"budget_id"`
+	Budgeted    int64      `json:"budgeted"`
+	Spent       int64      `json:"spent"`
+	Remaining   int64      `json:"remaining"`
+	Percentage  float64    `json:"percentage"`
+	PeriodStart time.Time  `json:"period_start"` // first instant spending is counted from
+	PeriodEnd   time.Time  `json:"period_end"`   // last instant spending is counted to
+	Pace        BudgetPace `json:"pace"`         // spend so far extrapolated to the whole period
+}
+
+// BudgetPaceStatus says whether a budget's spend is heading over its amount.
+type BudgetPaceStatus string
+
+// Budget pace statuses.
+const (
+	BudgetPaceOnTrack BudgetPaceStatus = "on_track" // projected spend within the budget
+	BudgetPaceAtRisk  BudgetPaceStatus = "at_risk"  // projected spend over the budget
+	BudgetPaceOver    BudgetPaceStatus = "over"     // already spent more than the budget
+)
+
+// BudgetPace is a budget's spend so far projected linearly to the end of its period.
+type BudgetPace struct {
+	DaysElapsed    int              `json:"days_elapsed"` // including today; 0 before the period starts
+	DaysInPeriod   int              `json:"days_in_period"`
+	ProjectedSpend int64            `json:"projected_spend"` // spent / days elapsed * days in period
+	Status         BudgetPaceStatus `json:"status"`
+}
+
+// BudgetListItem is a budget in a list, with its current-period progress.
+type BudgetListItem struct {
+	models.Budget
 	Spent      int64   `json:"spent"`
 	Remaining  int64   `json:"remaining"`
 	Percentage float64 `json:"percentage"`
 }
 
+// BudgetBurndown is a budget's cumulative spend, day by day, through its current
+// period so far, with the spend projected for the whole period at that pace.
+type BudgetBurndown struct {
+	BudgetID       string                `json:"budget_id"`
+	Budgeted       int64                 `json:"budgeted"`
+	Spent          int64                 `json:"spent"`
+	ProjectedSpend int64                 `json:"projected_spend"` // spent / days elapsed * days in period
+	PeriodStart    time.Time             `json:"period_start"`
+	PeriodEnd      time.Time             `json:"period_end"`
+	DaysElapsed    int                   `json:"days_elapsed"` // including today
+	DaysInPeriod   int                   `json:"days_in_period"`
+	Series         []BudgetBurndownPoint `json:"series"`
+}
+
+// BudgetBurndownPoint is the spend on one day of a budget period.
+type BudgetBurndownPoint struct {
+	Date       time.Time `json:"date"` // midnight starting the day
+	Spent      int64     `json:"spent"`
+	Cumulative int64     `json:"cumulative"` // spent from the period start through this day
+}
+
 // BudgetServicer defines the contract for budget-related business logic.
 type BudgetServicer interface {
 	CreateBudget(userID, categoryID string, name string, amount int64, period models.BudgetPeriod, startDate time.Time, endDate *time.Time) (*models.Budget, error)
@@ -137,17 +424,83 @@ type BudgetServicer interface {
 	GetBudgetByID(userID, budgetID string) (*models.Budget, error)
 	UpdateBudget(userID, budgetID string, name string, amount *int64, period *models.BudgetPeriod, endDate *time.Time) (*models.Budget, error)
 	DeleteBudget(userID, budgetID string) error
-	GetBudgetProgress(userID, budgetID string) (*BudgetProgress, error)
+	GetBudgetProgress(userID, budgetID string, includePending bool) (*BudgetProgress, error)
+	GetBudgetsProgress(userID string, budgets []models.Budget, includePending bool) (map[string]*BudgetProgress, error)
+	GetBudgetBurndown(userID, budgetID string) (*BudgetBurndown, error)
+	CloneForPeriod(userID string, sourceMonth, targetMonth time.Time) ([]models.Budget, error)
+	RolloverMonthlyBudgets(asOf time.Time) (int, error)
+}
+
+// RuleUpdateFields holds optional fields for updating a categorization rule.
+// Nil pointer means "don't change"; non-nil means "set to this value".
+type RuleUpdateFields struct {
+	CategoryID *string
+	Pattern    *string
+	MatchType  *models.RuleMatchType
+	Priority   *int
 }
 
-// PortfolioSummary contains aggregated portfolio data across all investment accounts.
+// RuleServicer defines the contract for categorization rule business logic.
+type RuleServicer interface {
+	CreateRule(userID, categoryID, pattern string, matchType models.RuleMatchType, priority int) (*models.CategorizationRule, error)
+	GetUserRules(userID string, page pagination.PageRequest) (*pagination.PageResponse[models.CategorizationRule], error)
+	GetRuleByID(userID, ruleID string) (*models.CategorizationRule, error)
+	UpdateRule(userID, ruleID string, updates RuleUpdateFields) (*models.CategorizationRule, error)
+	DeleteRule(userID, ruleID string) error
+	ApplyRules(userID, transactionID string) (*models.Transaction, error)
+	ApplyRulesToUncategorized(userID string) (int, error)
+}
+
+// PortfolioSummary contains aggregated portfolio data across all investment
+// accounts. Totals are in Currency, the user's base currency; amounts in
+// currencies without an exchange rate to it are added unconverted and those
+// currencies listed in UnconvertedCurrencies.
 type PortfolioSummary struct {
+	Currency              string                           `json:"currency"`
 	TotalValue            int64                            `json:"total_value"`
 	TotalCostBasis        int64                            `json:"total_cost_basis"`
 	TotalGainLoss         int64                            `json:"total_gain_loss"`
 	GainLossPct           float64                          `json:"gain_loss_pct"`
 	TotalRealizedGainLoss int64                            `json:"total_realized_gain_loss"`
+	TotalDividends        int64                            `json:"total_dividends"`
+	TotalReturn           int64                            `json:"total_return"` // Realized + unrealized gain/loss + dividends
 	HoldingsByType        map[models.AssetType]TypeSummary `json:"holdings_by_type"`
+	Holdings              []PortfolioHolding               `json:"holdings"`
+	UnconvertedCurrencies []string                         `json:"unconverted_currencies"`
+}
+
+// PortfolioHolding is one open position of a portfolio, valued in the
+// currency its price was recorded in and converted to the portfolio's currency.
+type PortfolioHolding struct {
+	InvestmentID   string           `json:"investment_id"`
+	SecurityID     string           `json:"security_id"`
+	Symbol         string           `json:"symbol"`
+	AssetType      models.AssetType `json:"asset_type"`
+	Quantity       float64          `json:"quantity"`
+	Currency       string           `json:"currency"`        // The security's currency
+	ValueCurrency  string           `json:"value_currency"`  // The latest price's currency; Currency unless the price was converted
+	Value          int64            `json:"value"`           // In ValueCurrency
+	CostBasis      int64            `json:"cost_basis"`      // In Currency
+	ExchangeRate   *float64         `json:"exchange_rate"`   // ValueCurrency to the portfolio currency; nil when unknown
+	ConvertedValue *int64           `json:"converted_value"` // In the portfolio currency; nil when unconverted
+	Sparkline      *Sparkline       `json:"sparkline,omitempty"`
+}
+
+// SparklineDays is how many daily closes a holding's sparkline holds.
+const SparklineDays = 7
+
+// Sparkline holds a security's most recent daily closes, oldest first. A
+// close is the last price recorded on a UTC day; days without a price are
+// skipped, so a security with a short history has fewer points.
+type Sparkline struct {
+	Closes    []SparklineClose `json:"closes"`
+	ChangePct *float64         `json:"change_pct"` // From the first close to the last; nil with fewer than two closes
+}
+
+// SparklineClose is one daily close of a sparkline.
+type SparklineClose struct {
+	Date  string `json:"date"` // YYYY-MM-DD
+	Price int64  `json:"price"`
 }
 
 // TypeSummary contains summary data for a single asset type.
@@ -156,44 +509,453 @@ type TypeSummary struct {
 	Count int   `json:"count"`
 }
 
+// HoldingPeriod classifies a sale by how long the sold units were held.
+type HoldingPeriod string
+
+const (
+	HoldingPeriodShort HoldingPeriod = "short" // held one year or less
+	HoldingPeriodLong  HoldingPeriod = "long"  // held more than one year
+)
+
+// TaxReportSale is the part of a sell matched (FIFO) to buy lots with the same
+// holding period. A sell spanning both periods appears as two entries.
+type TaxReportSale struct {
+	TransactionID string        `json:"transaction_id"`
+	InvestmentID  string        `json:"investment_id"`
+	Symbol        string        `json:"symbol"`
+	Currency      string        `json:"currency"`
+	SoldAt        time.Time     `json:"sold_at"`
+	AcquiredAt    time.Time     `json:"acquired_at"` // earliest matched buy date
+	Quantity      float64       `json:"quantity"`
+	Proceeds      int64         `json:"proceeds"`
+	CostBasis     int64         `json:"cost_basis"`
+	RealizedGain  int64         `json:"realized_gain"`
+	HoldingPeriod HoldingPeriod `json:"holding_period"`
+}
+
+// TaxReport summarizes the realized gains from sells in a calendar year.
+type TaxReport struct {
+	Year           int             `json:"year"`
+	Sales          []TaxReportSale `json:"sales"`
+	TotalProceeds  int64           `json:"total_proceeds"`
+	TotalCostBasis int64           `json:"total_cost_basis"`
+	ShortTermGain  int64           `json:"short_term_gain"`
+	LongTermGain   int64           `json:"long_term_gain"`
+	TotalGain      int64           `json:"total_gain"`
+}
+
+// InvestmentMetadata holds user annotations set when a holding is added.
+type InvestmentMetadata struct {
+	Notes       string
+	TargetPrice *int64
+}
+
+// SplitRounding is how a split's resulting quantity is rounded to whole units.
+type SplitRounding string
+
+const (
+	SplitRoundingNone    SplitRounding = "none"    // keep fractional units
+	SplitRoundingDown    SplitRounding = "down"    // drop the fraction, as brokers paying cash in lieu do
+	SplitRoundingNearest SplitRounding = "nearest" // round half up to the nearest whole unit
+)
+
+// SplitOptions controls fractional units produced by a split. The zero value
+// keeps them. With CashInLieuPrice set, units rounded away are paid out as a
+// cash-in-lieu dividend at that price per post-split unit.
+type SplitOptions struct {
+	Rounding        SplitRounding
+	CashInLieuPrice models.Cents
+}
+
+// SecurityRef identifies the security of a new holding: either ID of a catalog
+// entry, or Symbol, Name and AssetType to find or add one. Currency and
+// Exchange only apply to the symbol form; Currency defaults to USD for new
+// securities.
+type SecurityRef struct {
+	ID        string
+	Symbol    string
+	Name      string
+	AssetType models.AssetType
+	Currency  string
+	Exchange  string
+}
+
+// InvestmentUpdateFields holds optional fields for updating a holding's metadata.
+// Quantity and cost basis only change through buy/sell/split transactions.
+type InvestmentUpdateFields struct {
+	Notes       *string
+	TargetPrice **int64 // non-nil pointer to nil clears the target
+}
+
 // InvestmentServicer defines the contract for investment-related business logic.
 type InvestmentServicer interface {
-	AddInvestment(userID, accountID, securityID string, quantity float64, purchasePrice int64, walletAddress string, date *time.Time, fee int64, notes string) (*models.Investment, error)
-	GetAllInvestments(userID string, page pagination.PageRequest) (*pagination.PageResponse[models.Investment], error)
-	GetAccountInvestments(userID, accountID string, page pagination.PageRequest) (*pagination.PageResponse[models.Investment], error)
-	GetInvestmentByID(userID, investmentID string) (*models.Investment, error)
-	GetPortfolio(userID string) (*PortfolioSummary, error)
-	RecordBuy(userID, investmentID string, date time.Time, quantity float64, pricePerUnit int64, fee int64, notes string) (*models.InvestmentTransaction, error)
-	RecordSell(userID, investmentID string, date time.Time, quantity float64, pricePerUnit int64, fee int64, notes string) (*models.InvestmentTransaction, error)
-	RecordDividend(userID, investmentID string, date time.Time, amount int64, dividendType, notes string) (*models.InvestmentTransaction, error)
-	RecordSplit(userID, investmentID string, date time.Time, splitRatio float64, notes string) (*models.InvestmentTransaction, error)
-	GetInvestmentTransactions(userID, investmentID string, page pagination.PageRequest) (*pagination.PageResponse[models.InvestmentTransaction], error)
+	AddInvestment(userID models.UserID, accountID models.AccountID, security SecurityRef, quantity float64, purchasePrice models.Cents, walletAddress string, date *time.Time, fee models.Cents, notes string, fromAccountID models.AccountID, metadata InvestmentMetadata, forceNew bool) (*models.Investment, bool, error)
+	GetAllInvestments(userID models.UserID, page pagination.PageRequest) (*pagination.PageResponse[models.Investment], error)
+	GetAccountInvestments(userID models.UserID, accountID models.AccountID, page pagination.PageRequest) (*pagination.PageResponse[models.Investment], error)
+	GetInvestmentByID(userID models.UserID, investmentID models.InvestmentID) (*models.Investment, error)
+	UpdateInvestment(userID models.UserID, investmentID models.InvestmentID, updates InvestmentUpdateFields) (*models.Investment, error)
+	GetPortfolio(ctx context.Context, userID models.UserID) (*PortfolioSummary, error)
+	GetHoldingSparklines(holdings []PortfolioHolding) (map[string]*Sparkline, error)
+	RecordBuy(userID models.UserID, investmentID models.InvestmentID, date time.Time, quantity float64, pricePerUnit models.Cents, fee models.Cents, notes string, fromAccountID models.AccountID, confirmPrice bool) (*models.InvestmentTransaction, error)
+	RecordSell(userID models.UserID, investmentID models.InvestmentID, date time.Time, quantity float64, pricePerUnit models.Cents, fee models.Cents, notes string, confirmPrice bool) (*models.InvestmentTransaction, error)
+	RecordDividend(userID models.UserID, investmentID models.InvestmentID, date time.Time, amount models.Cents, dividendType, notes, externalRef string) (*models.InvestmentTransaction, error)
+	RecordSplit(userID models.UserID, investmentID models.InvestmentID, date time.Time, splitRatio float64, options SplitOptions, notes, externalRef string) (*models.InvestmentTransaction, error)
+	GetInvestmentTransactions(userID models.UserID, investmentID models.InvestmentID, page pagination.PageRequest) (*pagination.PageResponse[models.InvestmentTransaction], error)
+	GetTaxReport(userID models.UserID, year int) (*TaxReport, error)
 }
 
 // SecurityPriceInput represents a single price entry for bulk recording.
 type SecurityPriceInput struct {
-	SecurityID string    `json:"security_id"`
-	Price      int64     `json:"price"`
-	RecordedAt time.Time `json:"recorded_at"`
+	SecurityID string             `json:"security_id"`
+	Price      int64              `json:"price"`
+	RecordedAt time.Time          `json:"recorded_at"`
+	Source     models.PriceSource `json:"source"`   // defaults to manual
+	Currency   string             `json:"currency"` // currency the price is in; empty for the security's currency
+	// AllowLargeMove records the price even if it deviates from the previous
+	// one by more than the configured maximum, for genuine large moves
+	AllowLargeMove bool `json:"allow_large_move"`
+}
+
+// RecordPricesResult is the outcome of a bulk price recording.
+type RecordPricesResult struct {
+	Recorded int             `json:"prices_recorded"`
+	Rejected []RejectedPrice `json:"rejected"`
+}
+
+// RejectedPrice is a price entry that was not recorded because it moved too
+// far from the security's previous price.
+type RejectedPrice struct {
+	SecurityID   string    `json:"security_id"`
+	Price        int64     `json:"price"`
+	RecordedAt   time.Time `json:"recorded_at"`
+	LastPrice    int64     `json:"last_price"`
+	DeviationPct float64   `json:"deviation_pct"`
+	Reason       string    `json:"reason"`
+}
+
+// SecurityInput is one security in a batch upsert.
+type SecurityInput struct {
+	Symbol      string
+	Name        string
+	AssetType   models.AssetType
+	Currency    string // defaults to USD for new securities; empty keeps an existing one's
+	Exchange    string
+	ExtraFields map[string]interface{}
+}
+
+// SecurityBatchStatus is what a batch upsert did with one entry.
+type SecurityBatchStatus string
+
+const (
+	SecurityBatchCreated SecurityBatchStatus = "created"
+	SecurityBatchUpdated SecurityBatchStatus = "updated"
+	SecurityBatchFailed  SecurityBatchStatus = "failed"
+)
+
+// SecurityBatchItem is the outcome of one entry of a batch upsert, at its
+// position in the request.
+type SecurityBatchItem struct {
+	Index      int                 `json:"index"`
+	Symbol     string              `json:"symbol"`
+	Exchange   string              `json:"exchange,omitempty"`
+	Status     SecurityBatchStatus `json:"status"`
+	SecurityID string              `json:"security_id,omitempty"`
+	Error      string              `json:"error,omitempty"`
+}
+
+// SecurityBatchResult is the outcome of a batch upsert of securities.
+type SecurityBatchResult struct {
+	Created int                 `json:"created"`
+	Updated int                 `json:"updated"`
+	Failed  int                 `json:"failed"`
+	Items   []SecurityBatchItem `json:"items"`
+}
+
+// PriceCorrection replaces a recorded price with Price or, with Delete, removes it.
+type PriceCorrection struct {
+	Price  int64
+	Delete bool
+}
+
+// PriceCorrectionResult is a recorded price before and after a correction.
+type PriceCorrectionResult struct {
+	Previous models.SecurityPrice  `json:"previous"`
+	Current  *models.SecurityPrice `json:"current"` // nil when the price was deleted
+}
+
+// SecurityHolding summarizes a user's open position in a security across their
+// active investment accounts.
+type SecurityHolding struct {
+	Quantity     float64 `json:"quantity"`
+	CostBasis    int64   `json:"cost_basis"`
+	CurrentValue int64   `json:"current_value"`
+	GainLoss     int64   `json:"gain_loss"`
+}
+
+// SecurityFilter narrows a securities listing. Search matches symbol or name
+// case-insensitively; Exchange matches case-insensitively as well.
+type SecurityFilter struct {
+	Search    string            `json:"search,omitempty"`
+	AssetType *models.AssetType `json:"asset_type,omitempty"`
+	Exchange  *string           `json:"exchange,omitempty"`
+	Currency  *string           `json:"currency,omitempty"`
+}
+
+// SecurityWithHolding is a catalog security with the caller's holding in it.
+// Holding is nil when the user holds none.
+type SecurityWithHolding struct {
+	models.Security
+	Holding *SecurityHolding `json:"holding"`
 }
 
 // SecurityServicer defines the interface for security-related operations.
 type SecurityServicer interface {
 	CreateSecurity(symbol, name string, assetType models.AssetType, currency, exchange string, extraFields map[string]interface{}) (*models.Security, error)
+	UpsertSecurities(inputs []SecurityInput) (*SecurityBatchResult, error)
 	GetSecurityByID(id string) (*models.Security, error)
-	ListSecurities(search string, page pagination.PageRequest) (*pagination.PageResponse[models.Security], error)
+	ListSecurities(filter SecurityFilter, page pagination.PageRequest) (*pagination.PageResponse[models.Security], error)
+	ListSecuritiesWithHoldings(userID string, filter SecurityFilter, page pagination.PageRequest, ownedOnly bool) (*pagination.PageResponse[SecurityWithHolding], error)
 	ListAllSecurities() ([]models.Security, error)
-	RecordPrices(prices []SecurityPriceInput) (int, error)
+	RecordPrices(prices []SecurityPriceInput) (*RecordPricesResult, error)
 	GetPriceHistory(securityID string, from, to time.Time, page pagination.PageRequest) (*pagination.PageResponse[models.SecurityPrice], error)
+	CorrectPrice(securityID, priceID string, correction PriceCorrection) (*PriceCorrectionResult, error)
+}
+
+// ExchangeRateInput is one rate in a bulk exchange-rate recording.
+type ExchangeRateInput struct {
+	FromCurrency string
+	ToCurrency   string
+	Rate         float64
+	RecordedAt   time.Time
+}
+
+// ExchangeRateQuote is a resolved rate: one unit of From buys Rate units of To.
+type ExchangeRateQuote struct {
+	From string    `json:"from"`
+	To   string    `json:"to"`
+	Rate float64   `json:"rate"`
+	AsOf time.Time `json:"as_of"`
+}
+
+// ExchangeRateProvider is the narrow view of exchange rates that other
+// services convert amounts with.
+type ExchangeRateProvider interface {
+	GetRate(from, to string) (rate float64, asOf time.Time, err error)
+}
+
+// ExchangeRateServicer defines the contract for recording and serving exchange rates.
+type ExchangeRateServicer interface {
+	ExchangeRateProvider
+	RecordRates(rates []ExchangeRateInput) (int, error)
+	ListRates(base string) ([]ExchangeRateQuote, error)
 }
 
 // PortfolioSnapshotServicer defines the interface for portfolio snapshot operations.
 type PortfolioSnapshotServicer interface {
 	ComputeAndRecordSnapshots(recordedAt time.Time) (int, error)
-	GetSnapshots(userID string, from, to time.Time, page pagination.PageRequest) (*pagination.PageResponse[models.PortfolioSnapshot], error)
+	ComputeAndRecordSnapshotsAsOf(asOf time.Time) (int, error)
+	ComputeSnapshotsForRange(from, to time.Time, interval models.SnapshotInterval) (int, error)
+	ComputeUserSnapshotsForRange(userID string, from, to time.Time, interval models.SnapshotInterval) (int, error)
+	RecomputeSnapshots(from time.Time) (int, error)
+	GetSnapshots(ctx context.Context, userID string, from, to time.Time, page pagination.PageRequest) (*pagination.PageResponse[models.PortfolioSnapshot], error)
+	GetPortfolioVsBenchmark(userID, benchmarkSecurityID string, from, to time.Time) (*BenchmarkComparison, error)
+}
+
+// BenchmarkComparison compares the return on a user's investments with a
+// benchmark security's over the same period. The period runs between the
+// user's first and last snapshots in the requested range; the benchmark is
+// priced at the latest price on or before each of them. Returns are
+// percentages and Difference is portfolio minus benchmark, in points.
+type BenchmarkComparison struct {
+	BenchmarkSecurityID string    `json:"benchmark_security_id"`
+	BenchmarkSymbol     string    `json:"benchmark_symbol"`
+	From                time.Time `json:"from"`
+	To                  time.Time `json:"to"`
+	PortfolioStart      int64     `json:"portfolio_start"`
+	PortfolioEnd        int64     `json:"portfolio_end"`
+	PortfolioReturn     float64   `json:"portfolio_return"`
+	BenchmarkStart      int64     `json:"benchmark_start"`
+	BenchmarkEnd        int64     `json:"benchmark_end"`
+	BenchmarkReturn     float64   `json:"benchmark_return"`
+	Difference          float64   `json:"difference"`
+}
+
+// SeedServicer defines the contract for generating demo data.
+type SeedServicer interface {
+	SeedDemoUser(userID string, seed int64, asOf time.Time) (*SeedSummary, error)
+}
+
+// PipelineKeyServicer defines the contract for managing stored pipeline API keys.
+type PipelineKeyServicer interface {
+	CreatePipelineKey(name string) (*models.PipelineAPIKey, string, error)
+	ListPipelineKeys() ([]models.PipelineAPIKey, error)
+	RevokePipelineKey(keyID string) (*models.PipelineAPIKey, error)
+	AuthenticatePipelineKey(key string) (*models.PipelineAPIKey, error)
+}
+
+// OracleRunReport is the result of a price oracle run as the oracle reports it.
+type OracleRunReport struct {
+	Version           string
+	Failed            bool
+	Error             string // why a failed run stopped
+	DryRun            bool
+	StartedAt         time.Time
+	DurationMS        int64
+	SecuritiesFetched int
+	PricesRecorded    int
+	SnapshotsRecorded int
+	Errors            []OracleRunSymbolError
+}
+
+// OracleRunSymbolError is a symbol an oracle run failed to price.
+type OracleRunSymbolError struct {
+	SecurityID string
+	Symbol     string
+	Error      string
+}
+
+// OracleRunServicer defines the contract for keeping the price oracle's run history.
+type OracleRunServicer interface {
+	RecordOracleRun(report OracleRunReport) (*models.OracleRun, error)
+	ListOracleRuns(limit int) ([]models.OracleRun, error)
+}
+
+// EventConsumer handles one outbox event. Returning an error leaves the event
+// undelivered, so it is offered again on a later dispatch.
+type EventConsumer func(event *models.OutboxEvent) error
+
+// EventDispatchResult reports the outcome of one dispatch of outbox events.
+type EventDispatchResult struct {
+	Delivered int `json:"delivered"`
+	Failed    int `json:"failed"`
+}
+
+// EventDispatcherServicer defines the contract for delivering domain events
+// from the outbox to in-process consumers.
+type EventDispatcherServicer interface {
+	Subscribe(eventType string, consumer EventConsumer)
+	DispatchPending(limit int) (*EventDispatchResult, error)
+	Run(ctx context.Context, interval time.Duration)
 }
 
 // AuditServicer defines the contract for audit logging.
 type AuditServicer interface {
 	Log(userID string, action, resourceType string, resourceID string, ipAddress string, changes map[string]interface{})
 }
+
+// BudgetAlertData is the template data for an email sent when spending goes over a budget.
+type BudgetAlertData struct {
+	BudgetID     string
+	BudgetName   string
+	CategoryName string
+	Period       models.BudgetPeriod
+	Budgeted     int64
+	Spent        int64
+	Currency     string
+}
+
+// LargeTransactionAlertData is the template data for an email sent when a
+// transaction at or above the user's threshold is recorded.
+type LargeTransactionAlertData struct {
+	TransactionID string
+	AccountName   string
+	Type          models.TransactionType
+	Description   string
+	Amount        int64
+	Threshold     int64
+	Currency      string
+	Date          time.Time
+}
+
+// NotificationServicer defines the contract for user-facing email alerts.
+// Sends are queued in the background and never block or fail the caller.
+type NotificationServicer interface {
+	SendBudgetAlert(userID string, alert BudgetAlertData)
+	SendLargeTransactionAlert(userID string, alert LargeTransactionAlertData)
+	// Wait blocks until every queued notification has been attempted.
+	Wait()
+}
+
+// StatementAccount is an account's balance at the start and end of a statement month.
+type StatementAccount struct {
+	AccountID      string             `json:"account_id"`
+	Name           string             `json:"name"`
+	Type           models.AccountType `json:"type"`
+	Currency       string             `json:"currency"`
+	OpeningBalance int64              `json:"opening_balance"` // minor units
+	ClosingBalance int64              `json:"closing_balance"` // minor units
+	ClearedBalance int64              `json:"cleared_balance"` // closing balance without transactions still pending with the bank
+}
+
+// StatementBudget is a budget's standing at the end of a statement month. Yearly
+// budgets count spending from the start of their year.
+type StatementBudget struct {
+	BudgetProgress
+	Name         string              `json:"name"`
+	CategoryName string              `json:"category_name"`
+	Period       models.BudgetPeriod `json:"period"`
+	Exceeded     bool                `json:"exceeded"`
+}
+
+// StatementInvestments is the market value of the user's holdings at the start and end of a month.
+type StatementInvestments struct {
+	OpeningValue int64 `json:"opening_value"` // cents
+	ClosingValue int64 `json:"closing_value"` // cents
+	Change       int64 `json:"change"`        // cents
+}
+
+// MonthlyStatement is a single month's financial summary for a user.
+type MonthlyStatement struct {
+	Month            string                   `json:"month"` // "2025-10"
+	Accounts         []StatementAccount       `json:"accounts"`
+	Income           int64                    `json:"income"`   // cents
+	Expenses         int64                    `json:"expenses"` // cents
+	Net              int64                    `json:"net"`      // cents
+	TopCategories    []SpendingByCategoryItem `json:"top_categories"`
+	Budgets          []StatementBudget        `json:"budgets"`
+	Investments      StatementInvestments     `json:"investments"`
+	TransactionCount int64                    `json:"transaction_count"`
+}
+
+// StatementServicer defines the contract for monthly statements.
+type StatementServicer interface {
+	GetMonthlyStatement(ctx context.Context, userID string, month time.Time) (*MonthlyStatement, error)
+}
+
+// UsageStats is the activity of an account or category over a date range.
+// FirstActivity, LastActivity and BusiestWeekday are nil without activity.
+type UsageStats struct {
+	TransactionCount int64      `json:"transaction_count"`
+	FirstActivity    *time.Time `json:"first_activity"`
+	LastActivity     *time.Time `json:"last_activity"`
+	AverageAmount    int64      `json:"average_amount"`  // minor units
+	BusiestWeekday   *string    `json:"busiest_weekday"` // e.g. "Monday"; ties go to the earlier day from Sunday (UTC)
+}
+
+// AccountUsageStats is an account's activity, counting transfers in as well as
+// transactions recorded against it.
+type AccountUsageStats struct {
+	AccountID string             `json:"account_id"`
+	Name      string             `json:"name"`
+	Type      models.AccountType `json:"type"`
+	Currency  string             `json:"currency"`
+	IsActive  bool               `json:"is_active"`
+	UsageStats
+}
+
+// CategoryUsageStats is a category's activity.
+type CategoryUsageStats struct {
+	CategoryID string              `json:"category_id"`
+	Name       string              `json:"name"`
+	Type       models.CategoryType `json:"type"`
+	UsageStats
+}
+
+// StatsServicer defines the contract for account and category usage statistics.
+// Every account or category of the user is listed, with zero stats when it had
+// no activity. A nil from or to leaves that end of the range open.
+type StatsServicer interface {
+	GetAccountStats(ctx context.Context, userID models.UserID, from, to *time.Time) ([]AccountUsageStats, error)
+	GetCategoryStats(ctx context.Context, userID models.UserID, from, to *time.Time) ([]CategoryUsageStats, error)
+	UserLocation(userID models.UserID) (*time.Location, error)
+}
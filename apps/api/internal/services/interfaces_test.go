@@ -0,0 +1,62 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"kuberan/internal/models"
+)
+
+// The services are returned as interfaces from their constructors, so a
+// signature drifting away from its interface would otherwise only surface
+// at the first call site that happens to use it.
+var (
+	_ AccountServicer     = (*accountService)(nil)
+	_ TransactionServicer = (*transactionService)(nil)
+	_ InvestmentServicer  = (*investmentService)(nil)
+)
+
+func TestServiceInterfacesUseDefinedTypes(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		iface  reflect.Type
+		method string
+		want   []reflect.Type
+	}{
+		{
+			iface:  reflect.TypeOf((*TransactionServicer)(nil)).Elem(),
+			method: "CreateTransfer",
+			want: []reflect.Type{
+				reflect.TypeOf(models.UserID("")),
+				reflect.TypeOf(models.AccountID("")),
+				reflect.TypeOf(models.AccountID("")),
+				reflect.TypeOf(models.Cents(0)),
+			},
+		},
+		{
+			iface:  reflect.TypeOf((*InvestmentServicer)(nil)).Elem(),
+			method: "RecordBuy",
+			want: []reflect.Type{
+				reflect.TypeOf(models.UserID("")),
+				reflect.TypeOf(models.InvestmentID("")),
+			},
+		},
+		{
+			iface:  reflect.TypeOf((*AccountServicer)(nil)).Elem(),
+			method: "CreateCreditCardAccount",
+			want:   []reflect.Type{reflect.TypeOf(models.UserID(""))},
+		},
+	}
+
+	for _, tc := range cases {
+		m, ok := tc.iface.MethodByName(tc.method)
+		if !ok {
+			t.Fatalf("%s has no method %s", tc.iface.Name(), tc.method)
+		}
+		for i, want := range tc.want {
+			if got := m.Type.In(i); got != want {
+				t.Errorf("%s.%s argument %d: expected %s, got %s", tc.iface.Name(), tc.method, i, want, got)
+			}
+		}
+	}
+}
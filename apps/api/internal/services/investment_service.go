@@ -1,106 +1,256 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 
 	apperrors "kuberan/internal/errors"
 	"kuberan/internal/models"
+	"kuberan/internal/money"
 	"kuberan/internal/pagination"
 )
 
-// getLatestPrices fetches the most recent price for each security ID from security_prices.
-// Returns a map of security_id -> price (int64 cents). Securities with no price entries
-// are not included in the map.
-func getLatestPrices(db *gorm.DB, securityIDs []string) (map[string]int64, error) {
+// getLatestPrices fetches the most recent price for each security ID, serving from
+// cache when one is given. Returns a map of security_id -> price (int64 cents).
+// Securities with no price entries are not included in the map.
+func getLatestPrices(db *gorm.DB, cache PriceCache, securityIDs []string) (map[string]int64, error) {
+	quotes, err := getLatestPriceQuotes(db, cache, securityIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int64, len(quotes))
+	for id, q := range quotes {
+		result[id] = q.Price
+	}
+	return result, nil
+}
+
+// getLatestPriceQuotes is like getLatestPrices but also returns when each price was
+// recorded. Cache misses are loaded in a single query and written back to the cache.
+func getLatestPriceQuotes(db *gorm.DB, cache PriceCache, securityIDs []string) (map[string]PriceQuote, error) {
+	if cache == nil {
+		return queryLatestPriceQuotes(db, securityIDs)
+	}
+
+	result, misses := cache.Get(uniqueStrings(securityIDs))
+	if len(misses) == 0 {
+		return result, nil
+	}
+
+	loaded, err := queryLatestPriceQuotes(db, misses)
+	if err != nil {
+		return nil, err
+	}
+	cache.Set(loaded)
+	for id, q := range loaded {
+		result[id] = q
+	}
+	return result, nil
+}
+
+// queryLatestPriceQuotes loads the latest security_prices row for every given
+// security in one query, ranking each security's rows by recorded_at.
+func queryLatestPriceQuotes(db *gorm.DB, securityIDs []string) (map[string]PriceQuote, error) {
+	return queryPriceQuotesAsOf(db, securityIDs, time.Time{})
+}
+
+// queryPriceQuotesAsOf is like queryLatestPriceQuotes but ignores prices recorded
+// after asOf. A zero asOf applies no cutoff.
+func queryPriceQuotesAsOf(db *gorm.DB, securityIDs []string, asOf time.Time) (map[string]PriceQuote, error) {
 	if len(securityIDs) == 0 {
-		return map[string]int64{}, nil
+		return map[string]PriceQuote{}, nil
 	}
 
 	type priceRow struct {
 		SecurityID string
 		Price      int64
+		RecordedAt time.Time
+		Currency   string
+		Rn         int
 	}
 	var rows []priceRow
 
-	subq := db.Table("security_prices").
-		Select("security_id, MAX(recorded_at) AS max_recorded").
-		Where("security_id IN ?", securityIDs).
-		Group("security_id")
+	ranked := db.Table("security_prices").
+		Select("security_id, price, recorded_at, currency, ROW_NUMBER() OVER (PARTITION BY security_id ORDER BY recorded_at DESC) AS rn").
+		Where("security_id IN ? AND deleted_at IS NULL", securityIDs)
+	if !asOf.IsZero() {
+		ranked = ranked.Where("recorded_at <= ?", asOf)
+	}
 
-	if err := db.Table("security_prices sp").
-		Select("sp.security_id, sp.price").
-		Joins("INNER JOIN (?) latest ON sp.security_id = latest.security_id AND sp.recorded_at = latest.max_recorded", subq).
+	// The second-ranked row supplies PreviousPrice.
+	if err := db.Table("(?) AS ranked", ranked).
+		Select("security_id, price, recorded_at, currency, rn").
+		Where("rn <= 2").
+		Order("rn").
 		Scan(&rows).Error; err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
 
-	result := make(map[string]int64, len(rows))
+	result := make(map[string]PriceQuote, len(rows))
 	for _, r := range rows {
-		result[r.SecurityID] = r.Price
+		if r.Rn == 1 {
+			result[r.SecurityID] = PriceQuote{Price: r.Price, RecordedAt: r.RecordedAt, Currency: r.Currency}
+			continue
+		}
+		q := result[r.SecurityID]
+		previous := r.Price
+		q.PreviousPrice = &previous
+		result[r.SecurityID] = q
 	}
 	return result, nil
 }
 
+// applyLatestPrice sets CurrentPrice and CurrentPriceAsOf from quotes, leaving
+// CurrentPriceAsOf nil and PricePending set when the security has no recorded
+// price. AtTarget is set once the price reaches the holding's target price.
+// The holding's value and gain/loss follow from the price, using the same math
+// as GetPortfolio; DayChange is left nil unless there is an earlier price.
+func applyLatestPrice(investment *models.Investment, quotes map[string]PriceQuote) {
+	q, ok := quotes[investment.SecurityID]
+	if !ok {
+		investment.PricePending = true
+		return
+	}
+	recordedAt := q.RecordedAt
+	investment.CurrentPrice = q.Price
+	investment.CurrentPriceAsOf = &recordedAt
+	investment.AtTarget = investment.TargetPrice != nil && q.Price >= *investment.TargetPrice
+
+	investment.CurrentValue = holdingValue(investment.Quantity, q.Price)
+	investment.UnrealizedGainLoss = investment.CurrentValue - investment.CostBasis
+	if investment.CostBasis > 0 {
+		investment.GainLossPct = float64(investment.UnrealizedGainLoss) / float64(investment.CostBasis) * 100
+	}
+	if q.PreviousPrice != nil {
+		change := investment.CurrentValue - holdingValue(investment.Quantity, *q.PreviousPrice)
+		investment.DayChange = &change
+	}
+}
+
+// holdingValue is the market value in cents of quantity units at price.
+func holdingValue(quantity float64, price int64) int64 {
+	return int64(quantity * float64(price))
+}
+
 // investmentService handles investment-related business logic.
 type investmentService struct {
 	db             *gorm.DB
 	accountService AccountServicer
+	prices         PriceCache
+	rates          ExchangeRateProvider
+	priceCheck     TradePriceCheck
+}
+
+// TradePriceCheck configures the sanity check of buy and sell prices against
+// stored security prices. The zero value disables it.
+type TradePriceCheck struct {
+	MaxDeviation float64 // Percent a trade price may differ from the reference price
+	WindowDays   int     // How far from the trade date a stored price may be to serve as reference
 }
 
-// NewInvestmentService creates a new InvestmentServicer.
-func NewInvestmentService(db *gorm.DB, accountService AccountServicer) InvestmentServicer {
-	return &investmentService{db: db, accountService: accountService}
+// NewInvestmentService creates a new InvestmentServicer. prices may be nil to
+// always read latest prices from the database. rates converts the portfolio
+// into the user's base currency; when nil, holdings in other currencies are
+// left out of portfolio totals.
+func NewInvestmentService(db *gorm.DB, accountService AccountServicer, prices PriceCache, rates ExchangeRateProvider, priceCheck TradePriceCheck) InvestmentServicer {
+	return &investmentService{db: db, accountService: accountService, prices: prices, rates: rates, priceCheck: priceCheck}
 }
 
-// AddInvestment adds a new investment holding to an investment account.
+// withContext returns a copy of s whose queries run with ctx.
+func (s *investmentService) withContext(ctx context.Context) *investmentService {
+	scoped := *s
+	scoped.db = s.db.WithContext(ctx)
+	return &scoped
+}
+
+// AddInvestment adds a new investment holding to an investment account. The
+// security is resolved from ref, adding it to the catalog when it is given by
+// symbol and not listed yet. When the account already has an open holding of the
+// security (with the same wallet address), the purchase is recorded as a buy on
+// that holding instead, unless forceNew is set. The returned bool reports
+// whether an existing holding was used.
 func (s *investmentService) AddInvestment(
-	userID, accountID, securityID string,
+	userID models.UserID,
+	accountID models.AccountID,
+	ref SecurityRef,
 	quantity float64,
-	purchasePrice int64,
+	purchasePrice models.Cents,
 	walletAddress string,
 	date *time.Time,
-	fee int64,
+	fee models.Cents,
 	notes string,
-) (*models.Investment, error) {
-	// Verify account exists, belongs to user, and is an investment account
-	account, err := s.accountService.GetAccountByID(userID, accountID)
+	fromAccountID models.AccountID,
+	metadata InvestmentMetadata,
+	forceNew bool,
+) (*models.Investment, bool, error) {
+	if metadata.TargetPrice != nil && *metadata.TargetPrice < 0 {
+		return nil, false, apperrors.WithMessage(apperrors.ErrInvalidInput, "Target price must not be negative")
+	}
+
+	// Verify account exists, is writable by the user, and is an investment account
+	account, err := s.accountService.GetWritableAccount(userID, accountID)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	if account.Type != models.AccountTypeInvestment {
-		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "Account is not an investment account")
+		return nil, false, apperrors.WithMessage(apperrors.ErrInvalidInput, "Account is not an investment account")
 	}
 
-	// Verify security exists
-	var security models.Security
-	if err := s.db.Where("id = ?", securityID).First(&security).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, apperrors.ErrSecurityNotFound
-		}
-		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	security, err := s.resolveSecurity(ref)
+	if err != nil {
+		return nil, false, err
 	}
+	securityID := security.ID
 
 	// Apply defaults for optional fields
 	txDate := time.Now()
 	if date != nil {
 		txDate = *date
 	}
+
+	if !forceNew {
+		var existing models.Investment
+		err := s.db.Where("account_id = ? AND security_id = ? AND wallet_address = ? AND quantity > 0",
+			accountID, securityID, walletAddress).
+			Order("created_at ASC").
+			First(&existing).Error
+		if err == nil {
+			investment, err := s.addToExistingInvestment(string(userID), &existing, txDate, quantity, int64(purchasePrice), int64(fee), notes, string(fromAccountID), metadata)
+			return investment, true, err
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+	}
 	txNotes := "Initial purchase"
 	if notes != "" {
 		txNotes = notes
 	}
 
-	costBasis := int64(quantity*float64(purchasePrice)) + fee
+	costBasis := int64(quantity*float64(purchasePrice)) + int64(fee)
+
+	cashAccount, err := s.getFundingAccount(string(userID), string(fromAccountID), string(accountID), costBasis)
+	if err != nil {
+		return nil, false, err
+	}
 
 	investment := &models.Investment{
-		AccountID:     accountID,
+		AccountID:     string(accountID),
 		SecurityID:    securityID,
 		Quantity:      quantity,
 		CostBasis:     costBasis,
 		WalletAddress: walletAddress,
+		Notes:         metadata.Notes,
+		TargetPrice:   metadata.TargetPrice,
 	}
 
 	err = s.db.Transaction(func(tx *gorm.DB) error {
@@ -114,11 +264,18 @@ func (s *investmentService) AddInvestment(
 			Type:         models.InvestmentTransactionBuy,
 			Date:         txDate,
 			Quantity:     quantity,
-			PricePerUnit: purchasePrice,
+			PricePerUnit: int64(purchasePrice),
 			TotalAmount:  costBasis,
-			Fee:          fee,
+			Fee:          int64(fee),
 			Notes:        txNotes,
 		}
+		if cashAccount != nil {
+			cashTx, txErr := s.debitFundingAccount(tx, string(userID), cashAccount, string(accountID), costBasis, txDate, "Buy "+security.Symbol)
+			if txErr != nil {
+				return txErr
+			}
+			invTx.CashTransactionID = &cashTx.ID
+		}
 		if txErr := tx.Create(invTx).Error; txErr != nil {
 			return apperrors.Wrap(apperrors.ErrInternalServer, txErr)
 		}
@@ -126,23 +283,125 @@ func (s *investmentService) AddInvestment(
 		return nil
 	})
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	// Populate current price from security_prices for the response
-	prices, err := getLatestPrices(s.db, []string{securityID})
+	quotes, err := getLatestPriceQuotes(s.db, s.prices, []string{securityID})
 	if err != nil {
+		return nil, false, err
+	}
+	applyLatestPrice(investment, quotes)
+
+	investment.Security = *security
+	return investment, false, nil
+}
+
+// Validate checks that exactly one way of identifying the security is used.
+func (r SecurityRef) Validate() error {
+	bySymbol := r.Symbol != "" || r.Name != "" || r.AssetType != ""
+	switch {
+	case r.ID != "" && bySymbol:
+		return apperrors.WithMessage(apperrors.ErrInvalidInput, "provide either security_id or symbol, name and asset_type, not both")
+	case r.ID != "":
+		return nil
+	case !bySymbol:
+		return apperrors.WithMessage(apperrors.ErrInvalidInput, "security_id or symbol, name and asset_type is required")
+	case strings.TrimSpace(r.Symbol) == "" || strings.TrimSpace(r.Name) == "" || r.AssetType == "":
+		return apperrors.WithMessage(apperrors.ErrInvalidInput, "symbol, name and asset_type are all required when security_id is not given")
+	}
+	return nil
+}
+
+// resolveSecurity returns the security ref points at. A ref given by symbol is
+// matched case-insensitively against the catalog on symbol and exchange, and a
+// new security is created when none matches. Such a security has no price until
+// the oracle picks it up.
+func (s *investmentService) resolveSecurity(ref SecurityRef) (*models.Security, error) {
+	if err := ref.Validate(); err != nil {
 		return nil, err
 	}
-	investment.CurrentPrice = prices[securityID]
 
-	investment.Security = security
-	return investment, nil
+	var security models.Security
+	if ref.ID != "" {
+		if err := s.db.Where("id = ?", ref.ID).First(&security).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, apperrors.ErrSecurityNotFound
+			}
+			return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		return &security, nil
+	}
+
+	symbol := strings.TrimSpace(ref.Symbol)
+	find := func() error {
+		return s.db.Where("UPPER(symbol) = UPPER(?) AND exchange = ?", symbol, ref.Exchange).First(&security).Error
+	}
+	err := find()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		currency := ref.Currency
+		if currency == "" {
+			currency = "USD"
+		}
+		security = models.Security{
+			Symbol:    symbol,
+			Name:      strings.TrimSpace(ref.Name),
+			AssetType: ref.AssetType,
+			Currency:  currency,
+			Exchange:  ref.Exchange,
+		}
+		err = s.db.Create(&security).Error
+		if err != nil && isUniqueConstraintError(err) {
+			// Created concurrently; use that one.
+			err = find()
+		}
+		if err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		return &security, nil
+	}
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if ref.Currency != "" && ref.Currency != security.Currency {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput,
+			"currency does not match the listed security "+security.Symbol+" ("+security.Currency+")")
+	}
+	return &security, nil
+}
+
+// addToExistingInvestment records a purchase as a buy on an existing holding and
+// applies any metadata given with it. Metadata left empty keeps the holding's own.
+// Like a new holding, the purchase skips the trade price check.
+func (s *investmentService) addToExistingInvestment(
+	userID string,
+	investment *models.Investment,
+	date time.Time,
+	quantity float64,
+	pricePerUnit, fee int64,
+	notes, fromAccountID string,
+	metadata InvestmentMetadata,
+) (*models.Investment, error) {
+	if _, err := s.RecordBuy(models.UserID(userID), models.InvestmentID(investment.ID), date, quantity, models.Cents(pricePerUnit), models.Cents(fee), notes, models.AccountID(fromAccountID), true); err != nil {
+		return nil, err
+	}
+
+	var updates InvestmentUpdateFields
+	if metadata.Notes != "" {
+		updates.Notes = &metadata.Notes
+	}
+	if metadata.TargetPrice != nil {
+		updates.TargetPrice = &metadata.TargetPrice
+	}
+	if updates.Notes != nil || updates.TargetPrice != nil {
+		return s.UpdateInvestment(models.UserID(userID), models.InvestmentID(investment.ID), updates)
+	}
+	return s.GetInvestmentByID(models.UserID(userID), models.InvestmentID(investment.ID))
 }
 
 // GetAccountInvestments returns a paginated list of investments for an account.
-func (s *investmentService) GetAccountInvestments(userID, accountID string, page pagination.PageRequest) (*pagination.PageResponse[models.Investment], error) {
-	// Verify account exists and belongs to user
+func (s *investmentService) GetAccountInvestments(userID models.UserID, accountID models.AccountID, page pagination.PageRequest) (*pagination.PageResponse[models.Investment], error) {
+	// Verify account exists and the user can see it
 	if _, err := s.accountService.GetAccountByID(userID, accountID); err != nil {
 		return nil, err
 	}
@@ -151,7 +410,7 @@ func (s *investmentService) GetAccountInvestments(userID, accountID string, page
 
 	var totalItems int64
 	base := s.db.Model(&models.Investment{}).Where("account_id = ? AND quantity > 0", accountID)
-	if err := base.Count(&totalItems).Error; err != nil {
+	if err := pagination.Count(base, page, &totalItems); err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
 
@@ -166,12 +425,12 @@ func (s *investmentService) GetAccountInvestments(userID, accountID string, page
 	for i := range investments {
 		secIDs = append(secIDs, investments[i].SecurityID)
 	}
-	prices, err := getLatestPrices(s.db, secIDs)
+	quotes, err := getLatestPriceQuotes(s.db, s.prices, secIDs)
 	if err != nil {
 		return nil, err
 	}
 	for i := range investments {
-		investments[i].CurrentPrice = prices[investments[i].SecurityID]
+		applyLatestPrice(&investments[i], quotes)
 	}
 
 	result := pagination.NewPageResponse(investments, page.Page, page.PageSize, totalItems)
@@ -179,14 +438,14 @@ func (s *investmentService) GetAccountInvestments(userID, accountID string, page
 }
 
 // GetAllInvestments returns a paginated list of all investments across all active
-// investment accounts for the given user.
-func (s *investmentService) GetAllInvestments(userID string, page pagination.PageRequest) (*pagination.PageResponse[models.Investment], error) {
+// investment accounts the given user can access.
+func (s *investmentService) GetAllInvestments(userID models.UserID, page pagination.PageRequest) (*pagination.PageResponse[models.Investment], error) {
 	page.Defaults()
 
-	// Find all active investment account IDs for the user
+	// Find all accessible active investment account IDs
 	var accountIDs []string
 	if err := s.db.Model(&models.Account{}).
-		Where("user_id = ? AND type = ? AND is_active = ?", userID, models.AccountTypeInvestment, true).
+		Where("id IN (?) AND type = ? AND is_active = ?", accessibleAccountIDs(s.db, string(userID)), models.AccountTypeInvestment, true).
 		Pluck("id", &accountIDs).Error; err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
@@ -198,7 +457,7 @@ func (s *investmentService) GetAllInvestments(userID string, page pagination.Pag
 
 	var totalItems int64
 	base := s.db.Model(&models.Investment{}).Where("account_id IN ? AND quantity > 0", accountIDs)
-	if err := base.Count(&totalItems).Error; err != nil {
+	if err := pagination.Count(base, page, &totalItems); err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
 
@@ -214,20 +473,20 @@ func (s *investmentService) GetAllInvestments(userID string, page pagination.Pag
 	for i := range investments {
 		secIDs = append(secIDs, investments[i].SecurityID)
 	}
-	prices, err := getLatestPrices(s.db, secIDs)
+	quotes, err := getLatestPriceQuotes(s.db, s.prices, secIDs)
 	if err != nil {
 		return nil, err
 	}
 	for i := range investments {
-		investments[i].CurrentPrice = prices[investments[i].SecurityID]
+		applyLatestPrice(&investments[i], quotes)
 	}
 
 	result := pagination.NewPageResponse(investments, page.Page, page.PageSize, totalItems)
 	return &result, nil
 }
 
-// GetInvestmentByID returns an investment if the parent account belongs to the user.
-func (s *investmentService) GetInvestmentByID(userID, investmentID string) (*models.Investment, error) {
+// GetInvestmentByID returns an investment if the user can access the parent account.
+func (s *investmentService) GetInvestmentByID(userID models.UserID, investmentID models.InvestmentID) (*models.Investment, error) {
 	var investment models.Investment
 	if err := s.db.Preload("Account").Preload("Security").Where("id = ?", investmentID).First(&investment).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -236,26 +495,110 @@ func (s *investmentService) GetInvestmentByID(userID, investmentID string) (*mod
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
 
-	// Verify account belongs to user
-	if investment.Account.UserID != userID {
+	// Verify the user can access the account
+	level, err := accountAccess(s.db, string(userID), &investment.Account)
+	if err != nil {
+		return nil, err
+	}
+	if level == accessNone {
 		return nil, apperrors.ErrInvestmentNotFound
 	}
 
 	// Populate current price from security_prices
-	prices, err := getLatestPrices(s.db, []string{investment.SecurityID})
+	quotes, err := getLatestPriceQuotes(s.db, s.prices, []string{investment.SecurityID})
 	if err != nil {
 		return nil, err
 	}
-	investment.CurrentPrice = prices[investment.SecurityID]
+	applyLatestPrice(&investment, quotes)
+
+	if err := applyTransactionTotals(s.db, &investment); err != nil {
+		return nil, err
+	}
 
 	return &investment, nil
 }
 
-// GetPortfolio returns an aggregated portfolio summary across all investment accounts.
-func (s *investmentService) GetPortfolio(userID string) (*PortfolioSummary, error) {
-	// Get all investment accounts for the user
+// applyTransactionTotals sets the holding's lifetime fees, paid on its buys
+// and sells, and the dividends it has received. Returns of capital are not
+// dividend income.
+func applyTransactionTotals(db *gorm.DB, investment *models.Investment) error {
+	var totals struct {
+		TotalFees      int64
+		TotalDividends int64
+	}
+	err := db.Model(&models.InvestmentTransaction{}).
+		Select("COALESCE(SUM(CASE WHEN type IN ? THEN COALESCE(fee, 0) ELSE 0 END), 0) AS total_fees, "+
+			"COALESCE(SUM(CASE WHEN type = ? AND COALESCE(dividend_type, '') <> ? THEN total_amount ELSE 0 END), 0) AS total_dividends",
+			[]models.InvestmentTransactionType{models.InvestmentTransactionBuy, models.InvestmentTransactionSell},
+			models.InvestmentTransactionDividend, models.DividendReturnOfCapital).
+		Where("investment_id = ?", investment.ID).
+		Scan(&totals).Error
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	investment.TotalFees = totals.TotalFees
+	investment.TotalDividends = totals.TotalDividends
+	return nil
+}
+
+// UpdateInvestment updates a holding's notes and target price. Quantity and
+// cost basis are left untouched.
+func (s *investmentService) UpdateInvestment(userID models.UserID, investmentID models.InvestmentID, updates InvestmentUpdateFields) (*models.Investment, error) {
+	investment, err := s.getWritableInvestment(string(userID), string(investmentID))
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{}
+	if updates.Notes != nil {
+		fields["notes"] = *updates.Notes
+	}
+	if updates.TargetPrice != nil {
+		target := *updates.TargetPrice
+		if target != nil && *target < 0 {
+			return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "Target price must not be negative")
+		}
+		fields["target_price"] = target
+	}
+
+	if len(fields) > 0 {
+		if err := s.db.Model(&models.Investment{}).Where("id = ?", investment.ID).Updates(fields).Error; err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+	}
+
+	return s.GetInvestmentByID(userID, investmentID)
+}
+
+// getWritableInvestment returns an investment if the user can write to its parent account.
+func (s *investmentService) getWritableInvestment(userID, investmentID string) (*models.Investment, error) {
+	investment, err := s.GetInvestmentByID(models.UserID(userID), models.InvestmentID(investmentID))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.accountService.GetWritableAccount(models.UserID(userID), models.AccountID(investment.AccountID)); err != nil {
+		return nil, err
+	}
+	return investment, nil
+}
+
+// GetPortfolio returns an aggregated portfolio summary across all investment accounts
+// the user can access, including accounts shared with them. Amounts are
+// converted to the user's base currency at the latest exchange rate: values
+// from the currency their price was recorded in, cost basis, gains and
+// dividends from the security's currency. Amounts already in the base currency
+// are not converted, and amounts in a currency without a rate are added as they
+// are.
+func (s *investmentService) GetPortfolio(ctx context.Context, userID models.UserID) (*PortfolioSummary, error) {
+	s = s.withContext(ctx)
+	var user models.User
+	if err := s.db.Select("base_currency").Where("id = ?", userID).First(&user).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	// Get all accessible investment accounts
 	var accounts []models.Account
-	if err := s.db.Where("user_id = ? AND type = ? AND is_active = ?", userID, models.AccountTypeInvestment, true).
+	if err := s.db.Where("id IN (?) AND type = ? AND is_active = ?", accessibleAccountIDs(s.db, string(userID)), models.AccountTypeInvestment, true).
 		Find(&accounts).Error; err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
@@ -266,7 +609,10 @@ func (s *investmentService) GetPortfolio(userID string) (*PortfolioSummary, erro
 	}
 
 	summary := &PortfolioSummary{
-		HoldingsByType: make(map[models.AssetType]TypeSummary),
+		Currency:              user.BaseCurrency,
+		HoldingsByType:        make(map[models.AssetType]TypeSummary),
+		Holdings:              []PortfolioHolding{},
+		UnconvertedCurrencies: []string{},
 	}
 
 	if len(accountIDs) == 0 {
@@ -284,66 +630,270 @@ func (s *investmentService) GetPortfolio(userID string) (*PortfolioSummary, erro
 	for i := range investments {
 		secIDs = append(secIDs, investments[i].SecurityID)
 	}
-	prices, err := getLatestPrices(s.db, secIDs)
+	quotes, err := getLatestPriceQuotes(s.db, s.prices, secIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	currencies := make([]string, 0, len(investments))
+	for i := range investments {
+		currencies = append(currencies, investments[i].Security.Currency, priceCurrency(&investments[i], quotes))
+	}
+	rates, err := s.portfolioRates(summary, currencies)
+	if err != nil {
+		return nil, err
+	}
+	toBase := func(units int64, from string) (int64, *float64) {
+		rate, ok := rates[from]
+		if !ok {
+			return units, nil
+		}
+		return convertAmount(units, rate, from, summary.Currency), &rate
+	}
+
+	dividends, err := dividendTotals(s.db, investments)
 	if err != nil {
 		return nil, err
 	}
 
 	for i := range investments {
 		inv := &investments[i]
+		currency := inv.Security.Currency
 
-		// Always include realized G/L from all positions (open + closed)
-		summary.TotalRealizedGainLoss += inv.RealizedGainLoss
+		// Always include realized G/L and dividends from all positions (open + closed)
+		realized, _ := toBase(inv.RealizedGainLoss, currency)
+		summary.TotalRealizedGainLoss += realized
+		dividend, _ := toBase(dividends[inv.ID], currency)
+		summary.TotalDividends += dividend
 
 		// Only include open positions in holdings counts, values, and cost basis
-		if inv.Quantity > 0 {
-			value := int64(inv.Quantity * float64(prices[inv.SecurityID]))
-			summary.TotalValue += value
-			summary.TotalCostBasis += inv.CostBasis
-
-			ts := summary.HoldingsByType[inv.Security.AssetType]
-			ts.Value += value
-			ts.Count++
-			summary.HoldingsByType[inv.Security.AssetType] = ts
+		if inv.Quantity <= 0 {
+			continue
+		}
+		valueCurrency := priceCurrency(inv, quotes)
+		value := holdingValue(inv.Quantity, quotes[inv.SecurityID].Price)
+		holding := PortfolioHolding{
+			InvestmentID:  inv.ID,
+			SecurityID:    inv.SecurityID,
+			Symbol:        inv.Security.Symbol,
+			AssetType:     inv.Security.AssetType,
+			Quantity:      inv.Quantity,
+			Currency:      currency,
+			ValueCurrency: valueCurrency,
+			Value:         value,
+			CostBasis:     inv.CostBasis,
+		}
+		converted, rate := toBase(value, valueCurrency)
+		if rate != nil {
+			holding.ExchangeRate, holding.ConvertedValue = rate, &converted
 		}
+		costBasis, _ := toBase(inv.CostBasis, currency)
+
+		summary.TotalValue += converted
+		summary.TotalCostBasis += costBasis
+
+		ts := summary.HoldingsByType[inv.Security.AssetType]
+		ts.Value += converted
+		ts.Count++
+		summary.HoldingsByType[inv.Security.AssetType] = ts
+		summary.Holdings = append(summary.Holdings, holding)
 	}
 
 	summary.TotalGainLoss = summary.TotalValue - summary.TotalCostBasis
 	if summary.TotalCostBasis > 0 {
 		summary.GainLossPct = float64(summary.TotalGainLoss) / float64(summary.TotalCostBasis) * 100
 	}
+	summary.TotalReturn = summary.TotalRealizedGainLoss + summary.TotalGainLoss + summary.TotalDividends
 
 	return summary, nil
 }
 
+// dividendTotals returns the dividends each investment has received, in the
+// security's currency, keyed by investment ID. Returns of capital are left out.
+func dividendTotals(db *gorm.DB, investments []models.Investment) (map[string]int64, error) {
+	totals := make(map[string]int64)
+	if len(investments) == 0 {
+		return totals, nil
+	}
+	ids := make([]string, len(investments))
+	for i := range investments {
+		ids[i] = investments[i].ID
+	}
+
+	var rows []struct {
+		InvestmentID string
+		Total        int64
+	}
+	if err := db.Model(&models.InvestmentTransaction{}).
+		Select("investment_id, COALESCE(SUM(total_amount), 0) AS total").
+		Where("investment_id IN ? AND type = ? AND COALESCE(dividend_type, '') <> ?", ids, models.InvestmentTransactionDividend, models.DividendReturnOfCapital).
+		Group("investment_id").
+		Scan(&rows).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	for _, row := range rows {
+		totals[row.InvestmentID] = row.Total
+	}
+	return totals, nil
+}
+
+// GetHoldingSparklines returns the sparkline of each holding's security, keyed
+// by security ID, loading the closes of all securities in one query. Securities
+// with no recorded price are left out.
+func (s *investmentService) GetHoldingSparklines(holdings []PortfolioHolding) (map[string]*Sparkline, error) {
+	result := make(map[string]*Sparkline)
+	if len(holdings) == 0 {
+		return result, nil
+	}
+	secIDs := make([]string, len(holdings))
+	for i := range holdings {
+		secIDs[i] = holdings[i].SecurityID
+	}
+
+	type closeRow struct {
+		SecurityID string
+		Price      int64
+		RecordedAt time.Time
+	}
+	var rows []closeRow
+
+	// Rank each day's prices to find its close, then rank the closes by day.
+	day := utcDayExpr(s.db, "recorded_at")
+	daily := s.db.Table("security_prices").
+		Select("security_id, price, recorded_at, "+day+" AS day, ROW_NUMBER() OVER (PARTITION BY security_id, "+day+" ORDER BY recorded_at DESC) AS day_rn").
+		Where("security_id IN ? AND deleted_at IS NULL", secIDs)
+	closes := s.db.Table("(?) AS daily", daily).
+		Select("security_id, price, recorded_at, ROW_NUMBER() OVER (PARTITION BY security_id ORDER BY day DESC) AS rn").
+		Where("day_rn = 1")
+	if err := s.db.Table("(?) AS closes", closes).
+		Select("security_id, price, recorded_at").
+		Where("rn <= ?", SparklineDays).
+		Order("security_id, recorded_at").
+		Scan(&rows).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	for _, r := range rows {
+		sparkline := result[r.SecurityID]
+		if sparkline == nil {
+			sparkline = &Sparkline{}
+			result[r.SecurityID] = sparkline
+		}
+		sparkline.Closes = append(sparkline.Closes, SparklineClose{
+			Date:  r.RecordedAt.UTC().Format("2006-01-02"),
+			Price: r.Price,
+		})
+	}
+	for _, sparkline := range result {
+		first, last := sparkline.Closes[0].Price, sparkline.Closes[len(sparkline.Closes)-1].Price
+		if len(sparkline.Closes) > 1 && first != 0 {
+			change := math.Round(float64(last-first)/float64(first)*10000) / 100
+			sparkline.ChangePct = &change
+		}
+	}
+	return result, nil
+}
+
+// utcDayExpr is the SQL for the UTC date of a timestamp column.
+func utcDayExpr(db *gorm.DB, column string) string {
+	if db.Dialector.Name() == "sqlite" {
+		return "date(" + column + ")"
+	}
+	return "DATE(" + column + " AT TIME ZONE 'UTC')"
+}
+
+// priceCurrency returns the currency the investment's latest price was
+// recorded in, which is the security's currency unless the price says otherwise.
+func priceCurrency(investment *models.Investment, quotes map[string]PriceQuote) string {
+	if currency := quotes[investment.SecurityID].Currency; currency != "" {
+		return currency
+	}
+	return investment.Security.Currency
+}
+
+// portfolioRates returns the rate from each of currencies to the summary's
+// currency. Currencies with no rate (or no exchange rate provider) are left out
+// of the map and listed in summary.UnconvertedCurrencies.
+func (s *investmentService) portfolioRates(summary *PortfolioSummary, currencies []string) (map[string]float64, error) {
+	rates := map[string]float64{summary.Currency: 1}
+	for _, currency := range currencies {
+		if _, ok := rates[currency]; ok || slices.Contains(summary.UnconvertedCurrencies, currency) {
+			continue
+		}
+		if s.rates != nil {
+			rate, _, err := s.rates.GetRate(currency, summary.Currency)
+			if err == nil {
+				rates[currency] = rate
+				continue
+			}
+			var appErr *apperrors.AppError
+			if !errors.As(err, &appErr) || appErr.Code != apperrors.ErrExchangeRateNotFound.Code {
+				return nil, err
+			}
+		}
+		summary.UnconvertedCurrencies = append(summary.UnconvertedCurrencies, currency)
+	}
+	sort.Strings(summary.UnconvertedCurrencies)
+	return rates, nil
+}
+
+// convertAmount converts minor units of from into minor units of to, leaving
+// amounts already in to untouched.
+func convertAmount(units int64, rate float64, from, to string) int64 {
+	if from == to {
+		return units
+	}
+	return money.Convert(units, rate, from, to)
+}
+
 // RecordBuy records a buy transaction and updates the investment holding.
 func (s *investmentService) RecordBuy(
-	userID, investmentID string,
+	userID models.UserID,
+	investmentID models.InvestmentID,
 	date time.Time,
 	quantity float64,
-	pricePerUnit int64,
-	fee int64,
+	pricePerUnit models.Cents,
+	fee models.Cents,
 	notes string,
+	fromAccountID models.AccountID,
+	confirmPrice bool,
 ) (*models.InvestmentTransaction, error) {
-	investment, err := s.GetInvestmentByID(userID, investmentID)
+	investment, err := s.getWritableInvestment(string(userID), string(investmentID))
 	if err != nil {
 		return nil, err
 	}
+	if !confirmPrice {
+		if err := s.checkTradePrice(&investment.Security, date, pricePerUnit); err != nil {
+			return nil, err
+		}
+	}
 
-	totalAmount := int64(quantity*float64(pricePerUnit)) + fee
+	totalAmount := int64(quantity*float64(pricePerUnit)) + int64(fee)
+
+	cashAccount, err := s.getFundingAccount(string(userID), string(fromAccountID), investment.AccountID, totalAmount)
+	if err != nil {
+		return nil, err
+	}
 
 	var invTx models.InvestmentTransaction
 	err = s.db.Transaction(func(tx *gorm.DB) error {
 		invTx = models.InvestmentTransaction{
-			InvestmentID: investmentID,
+			InvestmentID: string(investmentID),
 			Type:         models.InvestmentTransactionBuy,
 			Date:         date,
 			Quantity:     quantity,
-			PricePerUnit: pricePerUnit,
+			PricePerUnit: int64(pricePerUnit),
 			TotalAmount:  totalAmount,
-			Fee:          fee,
+			Fee:          int64(fee),
 			Notes:        notes,
 		}
+		if cashAccount != nil {
+			cashTx, txErr := s.debitFundingAccount(tx, string(userID), cashAccount, investment.AccountID, totalAmount, date, "Buy "+investment.Security.Symbol)
+			if txErr != nil {
+				return txErr
+			}
+			invTx.CashTransactionID = &cashTx.ID
+		}
 		if txErr := tx.Create(&invTx).Error; txErr != nil {
 			return apperrors.Wrap(apperrors.ErrInternalServer, txErr)
 		}
@@ -367,16 +917,129 @@ func (s *investmentService) RecordBuy(
 	return &invTx, nil
 }
 
+// checkTradePrice returns ErrPriceDeviationWarning when pricePerUnit differs
+// from the reference price by more than the configured percentage. The
+// reference is the stored price of the security nearest to date within the
+// configured window; without one there is nothing to compare with and the
+// trade passes.
+func (s *investmentService) checkTradePrice(security *models.Security, date time.Time, pricePerUnit models.Cents) error {
+	if s.priceCheck.MaxDeviation <= 0 || s.priceCheck.WindowDays <= 0 {
+		return nil
+	}
+	window := time.Duration(s.priceCheck.WindowDays) * 24 * time.Hour
+
+	var before, after []models.SecurityPrice
+	if err := s.db.Where("security_id = ? AND recorded_at <= ? AND recorded_at >= ?", security.ID, date, date.Add(-window)).
+		Order("recorded_at DESC").Limit(1).Find(&before).Error; err != nil {
+		return apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if err := s.db.Where("security_id = ? AND recorded_at > ? AND recorded_at <= ?", security.ID, date, date.Add(window)).
+		Order("recorded_at ASC").Limit(1).Find(&after).Error; err != nil {
+		return apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	var ref *models.SecurityPrice
+	switch {
+	case len(before) == 1 && len(after) == 1:
+		ref = &before[0]
+		if after[0].RecordedAt.Sub(date) < date.Sub(before[0].RecordedAt) {
+			ref = &after[0]
+		}
+	case len(before) == 1:
+		ref = &before[0]
+	case len(after) == 1:
+		ref = &after[0]
+	}
+	if ref == nil || ref.Price <= 0 {
+		return nil
+	}
+
+	deviation := math.Abs(float64(int64(pricePerUnit)-ref.Price)) / float64(ref.Price) * 100
+	if deviation <= s.priceCheck.MaxDeviation {
+		return nil
+	}
+	return apperrors.WithMessage(apperrors.ErrPriceDeviationWarning, fmt.Sprintf(
+		"price_per_unit %s is %.2f%% away from the stored price %s on %s (more than %.2f%%); resend with confirm_price=true to record it anyway",
+		money.FormatDecimal(int64(pricePerUnit), security.Currency), deviation,
+		money.FormatDecimal(ref.Price, security.Currency), ref.RecordedAt.Format("2006-01-02"), s.priceCheck.MaxDeviation))
+}
+
+// getFundingAccount loads the cash account a buy is paid from and checks it can
+// cover amount. Returns nil when fromAccountID is empty.
+func (s *investmentService) getFundingAccount(userID, fromAccountID, investmentAccountID string, amount int64) (*models.Account, error) {
+	if fromAccountID == "" {
+		return nil, nil
+	}
+	if fromAccountID == investmentAccountID {
+		return nil, apperrors.ErrSameAccountTransfer
+	}
+
+	account, err := s.accountService.GetWritableAccount(models.UserID(userID), models.AccountID(fromAccountID))
+	if err != nil {
+		return nil, err
+	}
+	if account.Type == models.AccountTypeInvestment || account.Type == models.AccountTypeDebt {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "Funding account must be a cash or credit card account")
+	}
+	if account.Type != models.AccountTypeCreditCard && account.Balance < amount {
+		return nil, apperrors.ErrInsufficientBalance
+	}
+	return account, nil
+}
+
+// debitFundingAccount records the cash leg of a buy as an investment transaction
+// from the funding account to the investment account. Only the funding account
+// balance changes; investment account balances are derived from holdings, so
+// the row is not a transfer and nothing credits its destination.
+func (s *investmentService) debitFundingAccount(
+	tx *gorm.DB,
+	userID string,
+	account *models.Account,
+	investmentAccountID string,
+	amount int64,
+	date time.Time,
+	description string,
+) (*models.Transaction, error) {
+	// Re-check the funding balance under lock; getFundingAccount ran outside tx
+	if err := lockAccounts(tx, account); err != nil {
+		return nil, err
+	}
+	if account.Type != models.AccountTypeCreditCard && account.Balance < amount {
+		return nil, apperrors.ErrInsufficientBalance
+	}
+
+	transaction := &models.Transaction{
+		UserID:      userID,
+		AccountID:   account.ID,
+		ToAccountID: &investmentAccountID,
+		Type:        models.TransactionTypeInvestment,
+		Amount:      amount,
+		Description: description,
+		Date:        date,
+		Status:      models.TransactionStatusCleared,
+	}
+	if err := tx.Create(transaction).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	if err := s.accountService.UpdateAccountBalance(tx, account, models.TransactionTypeExpense, models.Cents(amount)); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
 // RecordSell records a sell transaction and adjusts the investment holding proportionally.
 func (s *investmentService) RecordSell(
-	userID, investmentID string,
+	userID models.UserID,
+	investmentID models.InvestmentID,
 	date time.Time,
 	quantity float64,
-	pricePerUnit int64,
-	fee int64,
+	pricePerUnit models.Cents,
+	fee models.Cents,
 	notes string,
+	confirmPrice bool,
 ) (*models.InvestmentTransaction, error) {
-	investment, err := s.GetInvestmentByID(userID, investmentID)
+	investment, err := s.getWritableInvestment(string(userID), string(investmentID))
 	if err != nil {
 		return nil, err
 	}
@@ -384,27 +1047,35 @@ func (s *investmentService) RecordSell(
 	if quantity > investment.Quantity {
 		return nil, apperrors.ErrInsufficientShares
 	}
+	if !confirmPrice {
+		if err := s.checkTradePrice(&investment.Security, date, pricePerUnit); err != nil {
+			return nil, err
+		}
+	}
 
-	totalAmount := int64(quantity*float64(pricePerUnit)) - fee
+	totalAmount := int64(quantity*float64(pricePerUnit)) - int64(fee)
 
-	// Proportional cost basis reduction
-	costBasisReduction := int64(float64(investment.CostBasis) * (quantity / investment.Quantity))
+	costBasisReduction, method, err := s.sellCostBasis(investment, date, quantity)
+	if err != nil {
+		return nil, err
+	}
 
-	// Realized gain/loss = sell proceeds - proportional cost basis
+	// Realized gain/loss = sell proceeds - cost basis of the units sold
 	realizedGainLoss := totalAmount - costBasisReduction
 
 	var invTx models.InvestmentTransaction
 	err = s.db.Transaction(func(tx *gorm.DB) error {
 		invTx = models.InvestmentTransaction{
-			InvestmentID:     investmentID,
+			InvestmentID:     string(investmentID),
 			Type:             models.InvestmentTransactionSell,
 			Date:             date,
 			Quantity:         quantity,
-			PricePerUnit:     pricePerUnit,
+			PricePerUnit:     int64(pricePerUnit),
 			TotalAmount:      totalAmount,
-			Fee:              fee,
+			Fee:              int64(fee),
 			Notes:            notes,
 			RealizedGainLoss: realizedGainLoss,
+			CostBasisMethod:  method,
 		}
 		if txErr := tx.Create(&invTx).Error; txErr != nil {
 			return apperrors.Wrap(apperrors.ErrInternalServer, txErr)
@@ -421,7 +1092,17 @@ func (s *investmentService) RecordSell(
 			return apperrors.Wrap(apperrors.ErrInternalServer, txErr)
 		}
 
-		return nil
+		return recordEvent(tx, InvestmentSoldEvent{
+			UserID:                  string(userID),
+			InvestmentID:            investment.ID,
+			InvestmentTransactionID: invTx.ID,
+			SecurityID:              investment.SecurityID,
+			Quantity:                quantity,
+			PricePerUnit:            invTx.PricePerUnit,
+			TotalAmount:             invTx.TotalAmount,
+			RealizedGainLoss:        realizedGainLoss,
+			Date:                    date,
+		})
 	})
 	if err != nil {
 		return nil, err
@@ -430,61 +1111,230 @@ func (s *investmentService) RecordSell(
 	return &invTx, nil
 }
 
-// RecordDividend records a dividend transaction without changing quantity or cost basis.
+// sellCostBasis returns the cost basis disposed of by selling quantity units
+// of investment, using the account owner's cost basis method, and the method
+// actually used. FIFO falls back to average when the recorded buys, splits and
+// sells don't add up to the holding's quantity (for example a holding entered
+// without its purchase history). Selling the whole holding always disposes of
+// all of its remaining cost basis.
+func (s *investmentService) sellCostBasis(investment *models.Investment, date time.Time, quantity float64) (int64, models.CostBasisMethod, error) {
+	method, err := s.ownerCostBasisMethod(investment.AccountID)
+	if err != nil {
+		return 0, "", err
+	}
+	if quantity >= investment.Quantity-lotQuantityEpsilon {
+		return investment.CostBasis, method, nil
+	}
+
+	average := int64(float64(investment.CostBasis) * (quantity / investment.Quantity))
+	if method != models.CostBasisFIFO {
+		return average, models.CostBasisAverage, nil
+	}
+
+	lots, err := s.openLots(investment)
+	if err != nil {
+		return 0, "", err
+	}
+	if lots == nil {
+		return average, models.CostBasisAverage, nil
+	}
+
+	sales, _ := matchSale(investment, models.InvestmentTransaction{Date: date, Quantity: quantity}, lots)
+	var cost int64
+	for _, sale := range sales {
+		cost += sale.CostBasis
+	}
+	// Earlier average-cost sells can leave the lots costing more than the holding
+	return min(cost, investment.CostBasis), models.CostBasisFIFO, nil
+}
+
+// ownerCostBasisMethod returns the cost basis method chosen by the owner of the
+// account, so sells by someone it is shared with follow the owner's method.
+func (s *investmentService) ownerCostBasisMethod(accountID string) (models.CostBasisMethod, error) {
+	var owner models.User
+	if err := s.db.Select("cost_basis_method").
+		Where("id = (?)", s.db.Model(&models.Account{}).Select("user_id").Where("id = ?", accountID)).
+		First(&owner).Error; err != nil {
+		return "", apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if owner.CostBasisMethod == models.CostBasisFIFO {
+		return models.CostBasisFIFO, nil
+	}
+	return models.CostBasisAverage, nil
+}
+
+// openLots replays the investment's buys, splits and sells into its unsold
+// lots, with earlier sells consuming lots first-in-first-out whatever method
+// they used. It returns nil when the lots don't account for the holding's
+// current quantity.
+func (s *investmentService) openLots(investment *models.Investment) ([]taxLot, error) {
+	var history []models.InvestmentTransaction
+	if err := s.db.Where("investment_id = ?", investment.ID).
+		Order("date ASC, created_at ASC").
+		Find(&history).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	lots := []taxLot{}
+	for _, t := range history {
+		switch t.Type {
+		case models.InvestmentTransactionBuy:
+			lots = append(lots, taxLot{acquiredAt: t.Date, quantity: t.Quantity, cost: t.TotalAmount})
+		case models.InvestmentTransactionSplit:
+			lots = applySplit(lots, t)
+		case models.InvestmentTransactionDividend:
+			applyReturnOfCapital(lots, t)
+		case models.InvestmentTransactionSell:
+			_, lots = matchSale(investment, t, lots)
+		}
+	}
+
+	total := 0.0
+	for _, lot := range lots {
+		total += lot.quantity
+	}
+	if math.Abs(total-investment.Quantity) > lotQuantityEpsilon*math.Max(1, investment.Quantity) {
+		return nil, nil
+	}
+	return lots, nil
+}
+
+// RecordDividend records a dividend transaction without changing quantity or cost basis,
+// except that a return of capital lowers the cost basis by amount, down to zero.
+// A non-empty externalRef makes the call idempotent: if the investment already
+// has a transaction with that reference, it is returned instead.
 func (s *investmentService) RecordDividend(
-	userID, investmentID string,
+	userID models.UserID,
+	investmentID models.InvestmentID,
 	date time.Time,
-	amount int64,
-	dividendType, notes string,
+	amount models.Cents,
+	dividendType, notes, externalRef string,
 ) (*models.InvestmentTransaction, error) {
-	if _, err := s.GetInvestmentByID(userID, investmentID); err != nil {
+	investment, err := s.getWritableInvestment(string(userID), string(investmentID))
+	if err != nil {
 		return nil, err
 	}
 
-	invTx := &models.InvestmentTransaction{
-		InvestmentID: investmentID,
-		Type:         models.InvestmentTransactionDividend,
-		Date:         date,
-		TotalAmount:  amount,
-		DividendType: dividendType,
-		Notes:        notes,
-	}
+	var invTx models.InvestmentTransaction
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		found, txErr := findExternalTransaction(tx, string(investmentID), externalRef, &invTx)
+		if txErr != nil || found {
+			return txErr
+		}
 
-	if err := s.db.Create(invTx).Error; err != nil {
-		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		invTx = models.InvestmentTransaction{
+			InvestmentID: string(investmentID),
+			Type:         models.InvestmentTransactionDividend,
+			Date:         date,
+			TotalAmount:  int64(amount),
+			DividendType: dividendType,
+			Notes:        notes,
+			ExternalRef:  optionalRef(externalRef),
+		}
+		if txErr := tx.Create(&invTx).Error; txErr != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, txErr)
+		}
+
+		if dividendType == models.DividendReturnOfCapital {
+			newCostBasis := max(investment.CostBasis-int64(amount), 0)
+			if txErr := tx.Model(investment).Update("cost_basis", newCostBasis).Error; txErr != nil {
+				return apperrors.Wrap(apperrors.ErrInternalServer, txErr)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return invTx, nil
+	return &invTx, nil
 }
 
-// RecordSplit records a stock split and multiplies the investment quantity.
+// RecordSplit records a stock split and multiplies the investment quantity by
+// splitRatio, so a ratio below 1 is a reverse split. options.Rounding rounds the
+// result to whole units; the fraction rounded away is kept on the split and,
+// when options.CashInLieuPrice is set, paid out as a CashInLieu dividend. Cost
+// basis is unchanged either way.
+// A non-empty externalRef makes the call idempotent: if the investment already
+// has a transaction with that reference, it is returned and the quantity is
+// left unchanged.
 func (s *investmentService) RecordSplit(
-	userID, investmentID string,
+	userID models.UserID,
+	investmentID models.InvestmentID,
 	date time.Time,
 	splitRatio float64,
-	notes string,
+	options SplitOptions,
+	notes, externalRef string,
 ) (*models.InvestmentTransaction, error) {
-	investment, err := s.GetInvestmentByID(userID, investmentID)
+	if !(splitRatio > 0) || math.IsInf(splitRatio, 0) {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "split ratio must be greater than 0")
+	}
+	if options.CashInLieuPrice < 0 {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "cash in lieu price cannot be negative")
+	}
+
+	investment, err := s.getWritableInvestment(string(userID), string(investmentID))
 	if err != nil {
 		return nil, err
 	}
 
+	split := investment.Quantity * splitRatio
+	var newQuantity float64
+	switch options.Rounding {
+	case SplitRoundingDown:
+		newQuantity = math.Floor(split + lotQuantityEpsilon)
+	case SplitRoundingNearest:
+		newQuantity = math.Floor(split + 0.5 + lotQuantityEpsilon)
+	case SplitRoundingNone, "":
+		if options.CashInLieuPrice > 0 {
+			return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "cash in lieu needs rounding down or nearest")
+		}
+		newQuantity = split
+	default:
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "rounding must be one of none, down, nearest")
+	}
+	remainder := split - newQuantity
+	if math.Abs(remainder) <= lotQuantityEpsilon {
+		remainder = 0
+	}
+
 	var invTx models.InvestmentTransaction
 	err = s.db.Transaction(func(tx *gorm.DB) error {
+		found, txErr := findExternalTransaction(tx, string(investmentID), externalRef, &invTx)
+		if txErr != nil || found {
+			return txErr
+		}
+
 		invTx = models.InvestmentTransaction{
-			InvestmentID: investmentID,
-			Type:         models.InvestmentTransactionSplit,
-			Date:         date,
-			Quantity:     investment.Quantity,
-			SplitRatio:   splitRatio,
-			Notes:        notes,
+			InvestmentID:   string(investmentID),
+			Type:           models.InvestmentTransactionSplit,
+			Date:           date,
+			Quantity:       investment.Quantity,
+			SplitRatio:     splitRatio,
+			SplitRemainder: remainder,
+			Notes:          notes,
+			ExternalRef:    optionalRef(externalRef),
 		}
 		if txErr := tx.Create(&invTx).Error; txErr != nil {
 			return apperrors.Wrap(apperrors.ErrInternalServer, txErr)
 		}
 
-		// Multiply quantity by split ratio; cost basis stays the same
-		newQuantity := investment.Quantity * splitRatio
+		if remainder > 0 && options.CashInLieuPrice > 0 {
+			cashInLieu := models.InvestmentTransaction{
+				InvestmentID: string(investmentID),
+				Type:         models.InvestmentTransactionDividend,
+				Date:         date,
+				Quantity:     remainder,
+				PricePerUnit: int64(options.CashInLieuPrice),
+				TotalAmount:  int64(math.Round(remainder * float64(options.CashInLieuPrice))),
+				DividendType: "CashInLieu",
+				Notes:        "Cash in lieu of fractional units",
+			}
+			if txErr := tx.Create(&cashInLieu).Error; txErr != nil {
+				return apperrors.Wrap(apperrors.ErrInternalServer, txErr)
+			}
+		}
+
 		if txErr := tx.Model(investment).Update("quantity", newQuantity).Error; txErr != nil {
 			return apperrors.Wrap(apperrors.ErrInternalServer, txErr)
 		}
@@ -498,8 +1348,80 @@ func (s *investmentService) RecordSplit(
 	return &invTx, nil
 }
 
-// GetInvestmentTransactions returns a paginated list of transactions for an investment.
-func (s *investmentService) GetInvestmentTransactions(userID, investmentID string, page pagination.PageRequest) (*pagination.PageResponse[models.InvestmentTransaction], error) {
+// applySplit scales lots by a split's ratio, then takes the units it rounded
+// away off the newest lots (or adds units rounded up to the newest). Lot costs
+// are unchanged, matching the holding's cost basis.
+func applySplit(lots []taxLot, split models.InvestmentTransaction) []taxLot {
+	for j := range lots {
+		lots[j].quantity *= split.SplitRatio
+	}
+	if len(lots) == 0 {
+		return lots
+	}
+	if split.SplitRemainder < 0 {
+		lots[len(lots)-1].quantity -= split.SplitRemainder
+		return lots
+	}
+	remaining := split.SplitRemainder
+	for j := len(lots) - 1; j >= 0 && remaining > lotQuantityEpsilon; j-- {
+		take := math.Min(remaining, lots[j].quantity)
+		lots[j].quantity -= take
+		remaining -= take
+	}
+	return lots
+}
+
+// applyReturnOfCapital lowers the lots' costs by a return of capital, shared
+// by quantity and floored at zero, matching the holding's cost basis. Other
+// dividends leave the lots unchanged.
+func applyReturnOfCapital(lots []taxLot, dividend models.InvestmentTransaction) {
+	if dividend.DividendType != models.DividendReturnOfCapital {
+		return
+	}
+	total := 0.0
+	for _, lot := range lots {
+		total += lot.quantity
+	}
+	if total <= lotQuantityEpsilon {
+		return
+	}
+	for j := range lots {
+		share := int64(math.Round(float64(dividend.TotalAmount) * lots[j].quantity / total))
+		lots[j].cost = max(lots[j].cost-share, 0)
+	}
+}
+
+// findExternalTransaction loads the investment's transaction recorded under
+// externalRef into dest, reporting whether one exists. An empty reference
+// never matches.
+func findExternalTransaction(tx *gorm.DB, investmentID, externalRef string, dest *models.InvestmentTransaction) (bool, error) {
+	if externalRef == "" {
+		return false, nil
+	}
+	err := tx.Where("investment_id = ? AND external_ref = ?", investmentID, externalRef).First(dest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return true, nil
+}
+
+// optionalRef stores an empty external reference as NULL so manual entries
+// never collide on the unique index.
+func optionalRef(externalRef string) *string {
+	if externalRef == "" {
+		return nil
+	}
+	return &externalRef
+}
+
+// GetInvestmentTransactions returns a paginated list of transactions for an
+// investment, newest first. Each carries the quantity and cost basis held after
+// it, replayed from the investment's first transaction like a brokerage
+// statement.
+func (s *investmentService) GetInvestmentTransactions(userID models.UserID, investmentID models.InvestmentID, page pagination.PageRequest) (*pagination.PageResponse[models.InvestmentTransaction], error) {
 	// Verify investment exists and user owns it
 	if _, err := s.GetInvestmentByID(userID, investmentID); err != nil {
 		return nil, err
@@ -509,15 +1431,218 @@ func (s *investmentService) GetInvestmentTransactions(userID, investmentID strin
 
 	var totalItems int64
 	base := s.db.Model(&models.InvestmentTransaction{}).Where("investment_id = ?", investmentID)
-	if err := base.Count(&totalItems).Error; err != nil {
+	if err := pagination.Count(base, page, &totalItems); err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
 
 	var transactions []models.InvestmentTransaction
-	if err := base.Order("date DESC").Scopes(pagination.Paginate(page)).Find(&transactions).Error; err != nil {
+	if err := base.Order("date DESC, created_at DESC").Scopes(pagination.Paginate(page)).Find(&transactions).Error; err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
 
+	if len(transactions) > 0 {
+		// The position after a transaction depends on every earlier one, so replay the full history
+		var history []models.InvestmentTransaction
+		if err := s.db.Select("id", "type", "quantity", "total_amount", "realized_gain_loss", "split_ratio", "split_remainder", "dividend_type").
+			Where("investment_id = ?", investmentID).
+			Order("date ASC, created_at ASC").
+			Find(&history).Error; err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		positions := replayPositions(history)
+		for i := range transactions {
+			position := positions[transactions[i].ID]
+			transactions[i].RunningQuantity = position.quantity
+			transactions[i].RunningCostBasis = position.costBasis
+		}
+	}
+
 	result := pagination.NewPageResponse(transactions, page.Page, page.PageSize, totalItems)
 	return &result, nil
 }
+
+// runningPosition is the quantity and cost basis held after a transaction.
+type runningPosition struct {
+	quantity  float64
+	costBasis int64
+}
+
+// replayPositions applies history, ordered oldest first, the way RecordBuy,
+// RecordSell, RecordSplit and RecordDividend update the holding, and returns the position after
+// each transaction by ID. A sell reduces the cost basis by its proceeds less its
+// realized gain, which is the cost of the units it disposed of.
+func replayPositions(history []models.InvestmentTransaction) map[string]runningPosition {
+	positions := make(map[string]runningPosition, len(history))
+	var position runningPosition
+	for _, t := range history {
+		switch t.Type {
+		case models.InvestmentTransactionBuy:
+			position.quantity += t.Quantity
+			position.costBasis += t.TotalAmount
+		case models.InvestmentTransactionSell:
+			position.quantity -= t.Quantity
+			position.costBasis -= t.TotalAmount - t.RealizedGainLoss
+		case models.InvestmentTransactionSplit:
+			position.quantity = position.quantity*t.SplitRatio - t.SplitRemainder
+		case models.InvestmentTransactionDividend:
+			if t.DividendType == models.DividendReturnOfCapital {
+				position.costBasis = max(position.costBasis-t.TotalAmount, 0)
+			}
+		}
+		if math.Abs(position.quantity) <= lotQuantityEpsilon {
+			position.quantity = 0
+		}
+		positions[t.ID] = position
+	}
+	return positions
+}
+
+// taxLot is the unsold remainder of a buy, used for FIFO matching.
+type taxLot struct {
+	acquiredAt time.Time
+	quantity   float64
+	cost       int64
+}
+
+// lotQuantityEpsilon absorbs float rounding when a sell exactly consumes a lot.
+const lotQuantityEpsilon = 1e-9
+
+// GetTaxReport returns the realized gains from sells dated in the given calendar
+// year (UTC) across the user's own investment accounts. Cost basis is matched to
+// buys first-in-first-out, so it can differ from the RealizedGainLoss stored on
+// sells recorded with the average method; units held more than one year are
+// long-term.
+func (s *investmentService) GetTaxReport(userID models.UserID, year int) (*TaxReport, error) {
+	yearStart := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	yearEnd := yearStart.AddDate(1, 0, 0)
+
+	var investments []models.Investment
+	if err := s.db.Preload("Security").
+		Where("account_id IN (?)", s.db.Model(&models.Account{}).Select("id").
+			Where("user_id = ? AND type = ?", userID, models.AccountTypeInvestment)).
+		Find(&investments).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	report := &TaxReport{Year: year, Sales: []TaxReportSale{}}
+	for i := range investments {
+		inv := &investments[i]
+
+		// Lots depend on every earlier buy, sell and split, so replay the full history
+		var history []models.InvestmentTransaction
+		if err := s.db.Where("investment_id = ? AND date < ?", inv.ID, yearEnd).
+			Order("date ASC, created_at ASC").
+			Find(&history).Error; err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+
+		var lots []taxLot
+		for _, t := range history {
+			switch t.Type {
+			case models.InvestmentTransactionBuy:
+				lots = append(lots, taxLot{acquiredAt: t.Date, quantity: t.Quantity, cost: t.TotalAmount})
+			case models.InvestmentTransactionSplit:
+				lots = applySplit(lots, t)
+			case models.InvestmentTransactionDividend:
+				applyReturnOfCapital(lots, t)
+			case models.InvestmentTransactionSell:
+				var sales []TaxReportSale
+				sales, lots = matchSale(inv, t, lots)
+				if t.Date.Before(yearStart) {
+					continue
+				}
+				for _, sale := range sales {
+					report.Sales = append(report.Sales, sale)
+					report.TotalProceeds += sale.Proceeds
+					report.TotalCostBasis += sale.CostBasis
+					if sale.HoldingPeriod == HoldingPeriodLong {
+						report.LongTermGain += sale.RealizedGain
+					} else {
+						report.ShortTermGain += sale.RealizedGain
+					}
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(report.Sales, func(a, b int) bool {
+		return report.Sales[a].SoldAt.Before(report.Sales[b].SoldAt)
+	})
+	report.TotalGain = report.ShortTermGain + report.LongTermGain
+	return report, nil
+}
+
+// matchSale consumes lots first-in-first-out to cover sell, returning one sale
+// entry per holding period and the lots left afterwards. Proceeds are split
+// across entries by quantity; units sold beyond the recorded lots carry no
+// cost basis and count as short-term.
+func matchSale(inv *models.Investment, sell models.InvestmentTransaction, lots []taxLot) ([]TaxReportSale, []taxLot) {
+	longTermFrom := sell.Date.AddDate(-1, 0, 0)
+	var byPeriod [2]*TaxReportSale // long, short: FIFO consumes the oldest lots first
+	entry := func(period HoldingPeriod, acquiredAt time.Time) *TaxReportSale {
+		idx := 0
+		if period == HoldingPeriodShort {
+			idx = 1
+		}
+		if byPeriod[idx] == nil {
+			byPeriod[idx] = &TaxReportSale{
+				TransactionID: sell.ID,
+				InvestmentID:  inv.ID,
+				Symbol:        inv.Security.Symbol,
+				Currency:      inv.Security.Currency,
+				SoldAt:        sell.Date,
+				AcquiredAt:    acquiredAt,
+				HoldingPeriod: period,
+			}
+		}
+		return byPeriod[idx]
+	}
+
+	remaining := sell.Quantity
+	for remaining > lotQuantityEpsilon && len(lots) > 0 {
+		lot := &lots[0]
+		take := math.Min(remaining, lot.quantity)
+		cost := lot.cost
+		if lot.quantity-take > lotQuantityEpsilon {
+			cost = int64(float64(lot.cost) * (take / lot.quantity))
+		}
+
+		period := HoldingPeriodShort
+		if lot.acquiredAt.Before(longTermFrom) {
+			period = HoldingPeriodLong
+		}
+		e := entry(period, lot.acquiredAt)
+		e.Quantity += take
+		e.CostBasis += cost
+
+		lot.quantity -= take
+		lot.cost -= cost
+		remaining -= take
+		if lot.quantity <= lotQuantityEpsilon {
+			lots = lots[1:]
+		}
+	}
+	if remaining > lotQuantityEpsilon {
+		entry(HoldingPeriodShort, sell.Date).Quantity += remaining
+	}
+
+	var sales []TaxReportSale
+	allocated := int64(0)
+	for _, e := range byPeriod {
+		if e == nil {
+			continue
+		}
+		sales = append(sales, *e)
+	}
+	for i := range sales {
+		// The last entry takes the remainder so proceeds add up exactly
+		if i == len(sales)-1 {
+			sales[i].Proceeds = sell.TotalAmount - allocated
+		} else {
+			sales[i].Proceeds = int64(float64(sell.TotalAmount) * (sales[i].Quantity / sell.Quantity))
+		}
+		allocated += sales[i].Proceeds
+		sales[i].RealizedGain = sales[i].Proceeds - sales[i].CostBasis
+	}
+	return sales, lots
+}
@@ -1,32 +1,37 @@
 package services
 
 import (
+	"context"
+	"math"
 	"testing"
 	"time"
 
+	"gorm.io/gorm"
+
 	"kuberan/internal/models"
 	"kuberan/internal/pagination"
 	"kuberan/internal/testutil"
+	"kuberan/internal/uuid"
 )
 
 func TestAddInvestment(t *testing.T) {
+	t.Parallel()
 	t.Run("valid", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 		sec := testutil.CreateTestSecurityWithParams(t, db, "AAPL", "Apple Inc", models.AssetTypeStock, "NASDAQ")
 
-		inv, err := svc.AddInvestment(user.ID, account.ID, sec.ID, 10.0, 15000, "", nil, 0, "")
+		inv, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 10.0, 15000, "", nil, 0, "", "", InvestmentMetadata{}, false)
 		testutil.AssertNoError(t, err)
 
-		if inv.ID == 0 {
-			t.Fatal("expected non-zero investment ID")
+		if inv.ID == "" {
+			t.Fatal("expected non-empty investment ID")
 		}
 		if inv.SecurityID != sec.ID {
-			t.Errorf("expected security ID %d, got %d", sec.ID, inv.SecurityID)
+			t.Errorf("expected security ID %s, got %s", sec.ID, inv.SecurityID)
 		}
 		if inv.Quantity != 10.0 {
 			t.Errorf("expected quantity 10.0, got %f", inv.Quantity)
@@ -61,53 +66,126 @@ func TestAddInvestment(t *testing.T) {
 	})
 
 	t.Run("not_investment_account", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		cashAcct := testutil.CreateTestCashAccount(t, db, user.ID)
 		sec := testutil.CreateTestSecurity(t, db)
 
-		_, err := svc.AddInvestment(user.ID, cashAcct.ID, sec.ID, 10.0, 15000, "", nil, 0, "")
+		_, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(cashAcct.ID), SecurityRef{ID: sec.ID}, 10.0, 15000, "", nil, 0, "", "", InvestmentMetadata{}, false)
 		testutil.AssertAppError(t, err, "INVALID_INPUT")
 	})
 
 	t.Run("invalid_account", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		sec := testutil.CreateTestSecurity(t, db)
 
-		_, err := svc.AddInvestment(user.ID, 9999, sec.ID, 10.0, 15000, "", nil, 0, "")
+		_, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(uuid.New()), SecurityRef{ID: sec.ID}, 10.0, 15000, "", nil, 0, "", "", InvestmentMetadata{}, false)
 		testutil.AssertAppError(t, err, "ACCOUNT_NOT_FOUND")
 	})
 
 	t.Run("invalid_security", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 
-		_, err := svc.AddInvestment(user.ID, account.ID, 9999, 10.0, 15000, "", nil, 0, "")
+		_, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: uuid.New()}, 10.0, 15000, "", nil, 0, "", "", InvestmentMetadata{}, false)
 		testutil.AssertAppError(t, err, "SECURITY_NOT_FOUND")
 	})
 
+	t.Run("by_symbol_creates_pending_security", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+
+		ref := SecurityRef{Symbol: "VWRA", Name: "Vanguard FTSE All-World", AssetType: models.AssetTypeETF, Exchange: "LSE"}
+		inv, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), ref, 2, 12050, "", nil, 0, "", "", InvestmentMetadata{}, false)
+		testutil.AssertNoError(t, err)
+
+		if inv.Security.Symbol != "VWRA" || inv.Security.Currency != "USD" || inv.Security.Exchange != "LSE" {
+			t.Errorf("unexpected security %+v", inv.Security)
+		}
+		if !inv.PricePending {
+			t.Error("expected a new security to be pending a price")
+		}
+		var count int64
+		db.Model(&models.Security{}).Where("symbol = ?", "VWRA").Count(&count)
+		if count != 1 {
+			t.Errorf("expected the security to be added to the catalog, got %d rows", count)
+		}
+	})
+
+	t.Run("by_symbol_reuses_listed_security", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurityWithParams(t, db, "AAPL", "Apple Inc", models.AssetTypeStock, "NASDAQ")
+		testutil.CreateTestSecurityPrice(t, db, sec.ID, 18000, time.Now())
+
+		ref := SecurityRef{Symbol: "aapl", Name: "Apple", AssetType: models.AssetTypeStock, Exchange: "NASDAQ"}
+		inv, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), ref, 1, 15000, "", nil, 0, "", "", InvestmentMetadata{}, false)
+		testutil.AssertNoError(t, err)
+
+		if inv.SecurityID != sec.ID {
+			t.Errorf("expected listed security %s, got %s", sec.ID, inv.SecurityID)
+		}
+		if inv.PricePending || inv.CurrentPrice != 18000 {
+			t.Errorf("expected listed price 18000, got %d (pending=%v)", inv.CurrentPrice, inv.PricePending)
+		}
+	})
+
+	t.Run("by_symbol_rejects_currency_mismatch", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		testutil.CreateTestSecurityWithParams(t, db, "AAPL", "Apple Inc", models.AssetTypeStock, "NASDAQ")
+
+		ref := SecurityRef{Symbol: "AAPL", Name: "Apple", AssetType: models.AssetTypeStock, Exchange: "NASDAQ", Currency: "EUR"}
+		_, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), ref, 1, 15000, "", nil, 0, "", "", InvestmentMetadata{}, false)
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+
+	t.Run("requires_exactly_one_security_reference", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+
+		refs := []SecurityRef{
+			{},
+			{ID: sec.ID, Symbol: "AAPL", Name: "Apple", AssetType: models.AssetTypeStock},
+			{Symbol: "AAPL", AssetType: models.AssetTypeStock},
+		}
+		for _, ref := range refs {
+			_, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), ref, 1, 15000, "", nil, 0, "", "", InvestmentMetadata{}, false)
+			testutil.AssertAppError(t, err, "INVALID_INPUT")
+		}
+	})
+
 	t.Run("custom_date", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 		sec := testutil.CreateTestSecurity(t, db)
 
 		customDate := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
-		inv, err := svc.AddInvestment(user.ID, account.ID, sec.ID, 5.0, 20000, "", &customDate, 0, "")
+		inv, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 5.0, 20000, "", &customDate, 0, "", "", InvestmentMetadata{}, false)
 		testutil.AssertNoError(t, err)
 
 		// Verify initial buy transaction uses the custom date
@@ -122,15 +200,14 @@ func TestAddInvestment(t *testing.T) {
 	})
 
 	t.Run("custom_fee_and_notes", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 		sec := testutil.CreateTestSecurity(t, db)
 
-		inv, err := svc.AddInvestment(user.ID, account.ID, sec.ID, 10.0, 15000, "", nil, 500, "Bought via broker")
+		inv, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 10.0, 15000, "", nil, 500, "Bought via broker", "", InvestmentMetadata{}, false)
 		testutil.AssertNoError(t, err)
 
 		// CostBasis should include fee: 10 * 15000 + 500 = 150500
@@ -154,16 +231,15 @@ func TestAddInvestment(t *testing.T) {
 	})
 
 	t.Run("defaults_when_omitted", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 		sec := testutil.CreateTestSecurity(t, db)
 
 		beforeCreate := time.Now().Add(-time.Second)
-		inv, err := svc.AddInvestment(user.ID, account.ID, sec.ID, 10.0, 15000, "", nil, 0, "")
+		inv, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 10.0, 15000, "", nil, 0, "", "", InvestmentMetadata{}, false)
 		testutil.AssertNoError(t, err)
 		afterCreate := time.Now().Add(time.Second)
 
@@ -185,14 +261,173 @@ func TestAddInvestment(t *testing.T) {
 			t.Errorf("expected date near now, got %v", buyTx.Date)
 		}
 	})
+
+	t.Run("debits_cash_account", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		cash := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 200000)
+		sec := testutil.CreateTestSecurity(t, db)
+
+		inv, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 10.0, 15000, "", nil, 500, "", models.AccountID(cash.ID), InvestmentMetadata{}, false)
+		testutil.AssertNoError(t, err)
+
+		// 200000 - (10 * 15000 + 500) = 49500
+		var dbCash models.Account
+		db.Where("id = ?", cash.ID).First(&dbCash)
+		if dbCash.Balance != 49500 {
+			t.Errorf("expected cash balance 49500, got %d", dbCash.Balance)
+		}
+
+		var buyTx models.InvestmentTransaction
+		db.Where("investment_id = ?", inv.ID).First(&buyTx)
+		if buyTx.CashTransactionID == nil {
+			t.Fatal("expected initial buy linked to a cash transaction")
+		}
+		var cashTx models.Transaction
+		db.Where("id = ?", *buyTx.CashTransactionID).First(&cashTx)
+		if cashTx.Amount != 150500 {
+			t.Errorf("expected cash transaction amount 150500, got %d", cashTx.Amount)
+		}
+	})
+
+	t.Run("insufficient_cash", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		cash := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 1000)
+		sec := testutil.CreateTestSecurity(t, db)
+
+		_, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 10.0, 15000, "", nil, 0, "", models.AccountID(cash.ID), InvestmentMetadata{}, false)
+		testutil.AssertAppError(t, err, "INSUFFICIENT_BALANCE")
+
+		var count int64
+		db.Model(&models.Investment{}).Where("account_id = ?", account.ID).Count(&count)
+		if count != 0 {
+			t.Errorf("expected no investment created, got %d", count)
+		}
+	})
+
+	t.Run("merges_into_existing_holding", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+
+		first, merged, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 10.0, 15000, "", nil, 0, "", "", InvestmentMetadata{}, false)
+		testutil.AssertNoError(t, err)
+		if merged {
+			t.Error("expected first purchase to create a new holding")
+		}
+
+		second, merged, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 5.0, 20000, "", nil, 100, "", "", InvestmentMetadata{}, false)
+		testutil.AssertNoError(t, err)
+		if !merged {
+			t.Error("expected second purchase to merge into the existing holding")
+		}
+		if second.ID != first.ID {
+			t.Errorf("expected holding %s, got %s", first.ID, second.ID)
+		}
+		if second.Quantity != 15.0 {
+			t.Errorf("expected quantity 15.0, got %f", second.Quantity)
+		}
+		// 10 * 15000 + (5 * 20000 + 100) = 250100
+		if second.CostBasis != 250100 {
+			t.Errorf("expected cost basis 250100, got %d", second.CostBasis)
+		}
+
+		var count int64
+		db.Model(&models.Investment{}).Where("account_id = ?", account.ID).Count(&count)
+		if count != 1 {
+			t.Errorf("expected 1 holding, got %d", count)
+		}
+		var txCount int64
+		db.Model(&models.InvestmentTransaction{}).Where("investment_id = ? AND type = ?", first.ID, models.InvestmentTransactionBuy).Count(&txCount)
+		if txCount != 2 {
+			t.Errorf("expected 2 buy transactions, got %d", txCount)
+		}
+	})
+
+	t.Run("force_new_creates_separate_holding", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+
+		first, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 10.0, 15000, "", nil, 0, "", "", InvestmentMetadata{}, false)
+		testutil.AssertNoError(t, err)
+
+		second, merged, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 5.0, 20000, "", nil, 0, "", "", InvestmentMetadata{}, true)
+		testutil.AssertNoError(t, err)
+		if merged {
+			t.Error("expected force_new to skip the existing holding")
+		}
+		if second.ID == first.ID {
+			t.Error("expected a separate holding")
+		}
+		if second.Quantity != 5.0 || second.CostBasis != 100000 {
+			t.Errorf("expected 5 units at cost 100000, got %f at %d", second.Quantity, second.CostBasis)
+		}
+
+		var dbFirst models.Investment
+		db.Where("id = ?", first.ID).First(&dbFirst)
+		if dbFirst.Quantity != 10.0 || dbFirst.CostBasis != 150000 {
+			t.Errorf("expected first holding unchanged, got %f at %d", dbFirst.Quantity, dbFirst.CostBasis)
+		}
+	})
+
+	t.Run("closed_holding_not_reused", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+
+		first, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 10.0, 15000, "", nil, 0, "", "", InvestmentMetadata{}, false)
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordSell(models.UserID(user.ID), models.InvestmentID(first.ID), time.Now(), 10.0, 16000, 0, "", false)
+		testutil.AssertNoError(t, err)
+
+		second, merged, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 2.0, 17000, "", nil, 0, "", "", InvestmentMetadata{}, false)
+		testutil.AssertNoError(t, err)
+		if merged || second.ID == first.ID {
+			t.Error("expected a new holding after the previous one was closed")
+		}
+	})
+
+	t.Run("different_wallet_not_merged", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurityWithParams(t, db, "BTC", "Bitcoin", models.AssetTypeCrypto, "")
+
+		first, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 1.0, 5000000, "wallet-a", nil, 0, "", "", InvestmentMetadata{}, false)
+		testutil.AssertNoError(t, err)
+		second, merged, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 1.0, 5000000, "wallet-b", nil, 0, "", "", InvestmentMetadata{}, false)
+		testutil.AssertNoError(t, err)
+		if merged || second.ID == first.ID {
+			t.Error("expected holdings in different wallets to stay separate")
+		}
+	})
 }
 
 func TestGetInvestmentByID(t *testing.T) {
+	t.Parallel()
 	t.Run("found_with_live_price", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 		sec := testutil.CreateTestSecurity(t, db)
@@ -201,43 +436,82 @@ func TestGetInvestmentByID(t *testing.T) {
 		// Record a security price
 		testutil.CreateTestSecurityPrice(t, db, sec.ID, 15000, time.Now())
 
-		result, err := svc.GetInvestmentByID(user.ID, inv.ID)
+		result, err := svc.GetInvestmentByID(models.UserID(user.ID), models.InvestmentID(inv.ID))
 		testutil.AssertNoError(t, err)
 
 		if result.ID != inv.ID {
-			t.Errorf("expected ID %d, got %d", inv.ID, result.ID)
+			t.Errorf("expected ID %s, got %s", inv.ID, result.ID)
 		}
 		if result.SecurityID != sec.ID {
-			t.Errorf("expected security ID %d, got %d", sec.ID, result.SecurityID)
+			t.Errorf("expected security ID %s, got %s", sec.ID, result.SecurityID)
 		}
 		if result.CurrentPrice != 15000 {
 			t.Errorf("expected current price 15000 from security_prices, got %d", result.CurrentPrice)
 		}
 	})
 
+	t.Run("fee_and_dividend_totals", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
+		uid, iid := models.UserID(user.ID), models.InvestmentID(inv.ID)
+
+		// Nothing recorded yet
+		result, err := svc.GetInvestmentByID(uid, iid)
+		testutil.AssertNoError(t, err)
+		if result.TotalFees != 0 || result.TotalDividends != 0 {
+			t.Errorf("expected zero totals without transactions, got fees %d, dividends %d", result.TotalFees, result.TotalDividends)
+		}
+
+		_, err = svc.RecordBuy(uid, iid, time.Now(), 5.0, 10000, 500, "", "", false)
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordBuy(uid, iid, time.Now(), 2.0, 10000, 250, "", "", false)
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordSell(uid, iid, time.Now(), 1.0, 10000, 100, "", false)
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordDividend(uid, iid, time.Now(), 1200, "Cash", "", "")
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordDividend(uid, iid, time.Now(), 800, "Special", "", "")
+		testutil.AssertNoError(t, err)
+
+		result, err = svc.GetInvestmentByID(uid, iid)
+		testutil.AssertNoError(t, err)
+		if result.TotalFees != 850 {
+			t.Errorf("expected total fees 850, got %d", result.TotalFees)
+		}
+		if result.TotalDividends != 2000 {
+			t.Errorf("expected total dividends 2000, got %d", result.TotalDividends)
+		}
+	})
+
 	t.Run("no_price_returns_zero", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 		sec := testutil.CreateTestSecurity(t, db)
 		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
 
-		result, err := svc.GetInvestmentByID(user.ID, inv.ID)
+		result, err := svc.GetInvestmentByID(models.UserID(user.ID), models.InvestmentID(inv.ID))
 		testutil.AssertNoError(t, err)
 
 		if result.CurrentPrice != 0 {
 			t.Errorf("expected current price 0 when no security price exists, got %d", result.CurrentPrice)
 		}
+		if result.CurrentPriceAsOf != nil {
+			t.Errorf("expected nil CurrentPriceAsOf when no security price exists, got %v", result.CurrentPriceAsOf)
+		}
 	})
 
 	t.Run("returns_latest_price", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 		sec := testutil.CreateTestSecurity(t, db)
@@ -249,47 +523,198 @@ func TestGetInvestmentByID(t *testing.T) {
 		testutil.CreateTestSecurityPrice(t, db, sec.ID, 12000, base.Add(time.Hour))
 		testutil.CreateTestSecurityPrice(t, db, sec.ID, 15000, base.Add(2*time.Hour))
 
-		result, err := svc.GetInvestmentByID(user.ID, inv.ID)
+		result, err := svc.GetInvestmentByID(models.UserID(user.ID), models.InvestmentID(inv.ID))
 		testutil.AssertNoError(t, err)
 
 		if result.CurrentPrice != 15000 {
 			t.Errorf("expected latest price 15000, got %d", result.CurrentPrice)
 		}
+		if result.CurrentPriceAsOf == nil || !result.CurrentPriceAsOf.Equal(base.Add(2*time.Hour)) {
+			t.Errorf("expected CurrentPriceAsOf %v, got %v", base.Add(2*time.Hour), result.CurrentPriceAsOf)
+		}
 	})
 
 	t.Run("not_found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 
-		_, err := svc.GetInvestmentByID(user.ID, 9999)
+		_, err := svc.GetInvestmentByID(models.UserID(user.ID), models.InvestmentID(uuid.New()))
 		testutil.AssertAppError(t, err, "INVESTMENT_NOT_FOUND")
 	})
 
 	t.Run("wrong_user", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user1 := testutil.CreateTestUser(t, db)
 		user2 := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user1.ID)
 		sec := testutil.CreateTestSecurity(t, db)
 		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
 
-		_, err := svc.GetInvestmentByID(user2.ID, inv.ID)
+		_, err := svc.GetInvestmentByID(models.UserID(user2.ID), models.InvestmentID(inv.ID))
 		testutil.AssertAppError(t, err, "INVESTMENT_NOT_FOUND")
 	})
 }
 
+func TestUpdateInvestment(t *testing.T) {
+	t.Parallel()
+	int64Ptr := func(v int64) *int64 { return &v }
+
+	t.Run("sets_notes_and_target_without_touching_position", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
+
+		notes := "Long-term hold, revisit after earnings"
+		target := int64Ptr(20000)
+		result, err := svc.UpdateInvestment(models.UserID(user.ID), models.InvestmentID(inv.ID), InvestmentUpdateFields{Notes: &notes, TargetPrice: &target})
+		testutil.AssertNoError(t, err)
+
+		if result.Notes != notes {
+			t.Errorf("expected notes %q, got %q", notes, result.Notes)
+		}
+		if result.TargetPrice == nil || *result.TargetPrice != 20000 {
+			t.Errorf("expected target price 20000, got %v", result.TargetPrice)
+		}
+		if result.Quantity != inv.Quantity || result.CostBasis != inv.CostBasis {
+			t.Errorf("expected quantity/cost basis unchanged (%v/%d), got %v/%d",
+				inv.Quantity, inv.CostBasis, result.Quantity, result.CostBasis)
+		}
+	})
+
+	t.Run("clears_target_and_keeps_notes", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
+
+		notes := "Thesis"
+		target := int64Ptr(20000)
+		_, err := svc.UpdateInvestment(models.UserID(user.ID), models.InvestmentID(inv.ID), InvestmentUpdateFields{Notes: &notes, TargetPrice: &target})
+		testutil.AssertNoError(t, err)
+
+		var cleared *int64
+		result, err := svc.UpdateInvestment(models.UserID(user.ID), models.InvestmentID(inv.ID), InvestmentUpdateFields{TargetPrice: &cleared})
+		testutil.AssertNoError(t, err)
+
+		if result.TargetPrice != nil {
+			t.Errorf("expected target price cleared, got %d", *result.TargetPrice)
+		}
+		if result.Notes != notes {
+			t.Errorf("expected notes %q to be kept, got %q", notes, result.Notes)
+		}
+	})
+
+	t.Run("negative_target_rejected", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
+
+		target := int64Ptr(-1)
+		_, err := svc.UpdateInvestment(models.UserID(user.ID), models.InvestmentID(inv.ID), InvestmentUpdateFields{TargetPrice: &target})
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+
+		_, _, err = svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 1, 100, "", nil, 0, "", "", InvestmentMetadata{TargetPrice: int64Ptr(-5)}, false)
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+
+	t.Run("viewer_cannot_update", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		owner := testutil.CreateTestUser(t, db)
+		viewer := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, owner.ID)
+		testutil.CreateTestAccountShare(t, db, owner.ID, viewer.ID, models.ShareRoleViewer, account.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
+
+		notes := "not mine"
+		_, err := svc.UpdateInvestment(models.UserID(viewer.ID), models.InvestmentID(inv.ID), InvestmentUpdateFields{Notes: &notes})
+		testutil.AssertAppError(t, err, "SHARE_READ_ONLY")
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+
+		_, err := svc.UpdateInvestment(models.UserID(user.ID), models.InvestmentID(uuid.New()), InvestmentUpdateFields{})
+		testutil.AssertAppError(t, err, "INVESTMENT_NOT_FOUND")
+	})
+}
+
+func TestInvestmentAtTarget(t *testing.T) {
+	t.Parallel()
+	int64Ptr := func(v int64) *int64 { return &v }
+	tests := []struct {
+		name   string
+		target *int64
+		price  int64 // 0 means no recorded price
+		want   bool
+	}{
+		{name: "no_target", target: nil, price: 25000, want: false},
+		{name: "below_target", target: int64Ptr(20000), price: 19999, want: false},
+		{name: "at_target", target: int64Ptr(20000), price: 20000, want: true},
+		{name: "above_target", target: int64Ptr(20000), price: 25000, want: true},
+		{name: "no_price", target: int64Ptr(20000), price: 0, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testutil.WithTx(t)
+			acctSvc := NewAccountService(db, nil)
+			svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+			user := testutil.CreateTestUser(t, db)
+			account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+			sec := testutil.CreateTestSecurity(t, db)
+
+			inv, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 1, 15000, "", nil, 0, "", "",
+				InvestmentMetadata{Notes: "watch", TargetPrice: tt.target}, false)
+			testutil.AssertNoError(t, err)
+			if inv.Notes != "watch" {
+				t.Errorf("expected notes %q, got %q", "watch", inv.Notes)
+			}
+			if tt.price > 0 {
+				testutil.CreateTestSecurityPrice(t, db, sec.ID, tt.price, time.Now())
+			}
+
+			result, err := svc.GetInvestmentByID(models.UserID(user.ID), models.InvestmentID(inv.ID))
+			testutil.AssertNoError(t, err)
+			if result.AtTarget != tt.want {
+				t.Errorf("expected at_target=%v at price %d, got %v", tt.want, tt.price, result.AtTarget)
+			}
+
+			page, err := svc.GetAccountInvestments(models.UserID(user.ID), models.AccountID(account.ID), pagination.PageRequest{Page: 1, PageSize: 10})
+			testutil.AssertNoError(t, err)
+			if len(page.Data) != 1 || page.Data[0].AtTarget != tt.want {
+				t.Errorf("expected listed holding at_target=%v, got %+v", tt.want, page.Data)
+			}
+		})
+	}
+}
+
 func TestGetAccountInvestments(t *testing.T) {
+	t.Parallel()
 	t.Run("returns_investments", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 		sec1 := testutil.CreateTestSecurity(t, db)
@@ -298,7 +723,7 @@ func TestGetAccountInvestments(t *testing.T) {
 		testutil.CreateTestInvestment(t, db, account.ID, sec2.ID)
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := svc.GetAccountInvestments(user.ID, account.ID, page)
+		result, err := svc.GetAccountInvestments(models.UserID(user.ID), models.AccountID(account.ID), page)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 2 {
@@ -310,10 +735,9 @@ func TestGetAccountInvestments(t *testing.T) {
 	})
 
 	t.Run("pagination", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 		for i := 0; i < 5; i++ {
@@ -322,7 +746,7 @@ func TestGetAccountInvestments(t *testing.T) {
 		}
 
 		page := pagination.PageRequest{Page: 1, PageSize: 2}
-		result, err := svc.GetAccountInvestments(user.ID, account.ID, page)
+		result, err := svc.GetAccountInvestments(models.UserID(user.ID), models.AccountID(account.ID), page)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 5 {
@@ -337,22 +761,20 @@ func TestGetAccountInvestments(t *testing.T) {
 	})
 
 	t.Run("invalid_account", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		_, err := svc.GetAccountInvestments(user.ID, 9999, page)
+		_, err := svc.GetAccountInvestments(models.UserID(user.ID), models.AccountID(uuid.New()), page)
 		testutil.AssertAppError(t, err, "ACCOUNT_NOT_FOUND")
 	})
 
 	t.Run("excludes_closed_positions", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 
@@ -374,7 +796,7 @@ func TestGetAccountInvestments(t *testing.T) {
 		}
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := svc.GetAccountInvestments(user.ID, account.ID, page)
+		result, err := svc.GetAccountInvestments(models.UserID(user.ID), models.AccountID(account.ID), page)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 1 {
@@ -387,17 +809,17 @@ func TestGetAccountInvestments(t *testing.T) {
 }
 
 func TestRecordBuy(t *testing.T) {
+	t.Parallel()
 	t.Run("valid", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 		sec := testutil.CreateTestSecurity(t, db)
 		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID) // 10 shares @ $100, cost basis $1000
 
-		buyTx, err := svc.RecordBuy(user.ID, inv.ID, time.Now(), 5.0, 10000, 500, "Buy more")
+		buyTx, err := svc.RecordBuy(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 5.0, 10000, 500, "Buy more", "", false)
 		testutil.AssertNoError(t, err)
 
 		if buyTx.Type != models.InvestmentTransactionBuy {
@@ -416,7 +838,7 @@ func TestRecordBuy(t *testing.T) {
 
 		// Verify investment updated in DB
 		var dbInv models.Investment
-		db.First(&dbInv, inv.ID)
+		db.Where("id = ?", inv.ID).First(&dbInv)
 		// 10 + 5 = 15 shares
 		if dbInv.Quantity != 15.0 {
 			t.Errorf("expected quantity 15.0, got %f", dbInv.Quantity)
@@ -428,29 +850,208 @@ func TestRecordBuy(t *testing.T) {
 	})
 
 	t.Run("not_found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 
-		_, err := svc.RecordBuy(user.ID, 9999, time.Now(), 5.0, 10000, 0, "")
+		_, err := svc.RecordBuy(models.UserID(user.ID), models.InvestmentID(uuid.New()), time.Now(), 5.0, 10000, 0, "", "", false)
 		testutil.AssertAppError(t, err, "INVESTMENT_NOT_FOUND")
 	})
+
+	t.Run("debits_cash_account", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		cash := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
+
+		buyTx, err := svc.RecordBuy(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 5.0, 10000, 500, "", models.AccountID(cash.ID), false)
+		testutil.AssertNoError(t, err)
+
+		// 100000 - (5 * 10000 + 500) = 49500
+		var dbCash models.Account
+		db.Where("id = ?", cash.ID).First(&dbCash)
+		if dbCash.Balance != 49500 {
+			t.Errorf("expected cash balance 49500, got %d", dbCash.Balance)
+		}
+
+		if buyTx.CashTransactionID == nil {
+			t.Fatal("expected buy linked to a cash transaction")
+		}
+		var cashTx models.Transaction
+		db.Where("id = ?", *buyTx.CashTransactionID).First(&cashTx)
+		if cashTx.AccountID != cash.ID || cashTx.Amount != 50500 || cashTx.Type != models.TransactionTypeInvestment {
+			t.Errorf("unexpected cash transaction: %+v", cashTx)
+		}
+		if cashTx.ToAccountID == nil || *cashTx.ToAccountID != account.ID {
+			t.Errorf("expected funding of investment account %s, got %v", account.ID, cashTx.ToAccountID)
+		}
+	})
+
+	t.Run("insufficient_cash", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		cash := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 50000)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
+
+		_, err := svc.RecordBuy(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 5.0, 10000, 500, "", models.AccountID(cash.ID), false)
+		testutil.AssertAppError(t, err, "INSUFFICIENT_BALANCE")
+
+		var dbInv models.Investment
+		db.Where("id = ?", inv.ID).First(&dbInv)
+		if dbInv.Quantity != 10.0 {
+			t.Errorf("expected quantity unchanged at 10.0, got %f", dbInv.Quantity)
+		}
+	})
+
+	t.Run("rejects_investment_funding_account", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		other := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
+
+		_, err := svc.RecordBuy(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 1.0, 10000, 0, "", models.AccountID(other.ID), false)
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+
+		_, err = svc.RecordBuy(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 1.0, 10000, 0, "", models.AccountID(account.ID), false)
+		testutil.AssertAppError(t, err, "SAME_ACCOUNT_TRANSFER")
+	})
+
+	t.Run("rolls_back_cash_debit_on_failure", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		cash := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
+
+		// Force the investment transaction insert to fail after the cash debit
+		if err := db.Migrator().DropTable(&models.InvestmentTransaction{}); err != nil {
+			t.Fatalf("failed to drop table: %v", err)
+		}
+
+		_, err := svc.RecordBuy(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 5.0, 10000, 500, "", models.AccountID(cash.ID), false)
+		testutil.AssertAppError(t, err, "INTERNAL_ERROR")
+
+		var dbCash models.Account
+		db.Where("id = ?", cash.ID).First(&dbCash)
+		if dbCash.Balance != 100000 {
+			t.Errorf("expected cash balance unchanged at 100000, got %d", dbCash.Balance)
+		}
+		var count int64
+		db.Model(&models.Transaction{}).Where("account_id = ?", cash.ID).Count(&count)
+		if count != 0 {
+			t.Errorf("expected no cash transactions, got %d", count)
+		}
+	})
+}
+
+func TestTradePriceCheck(t *testing.T) {
+	t.Parallel()
+	tradeDate := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	// setup creates a holding whose security was priced at $100 two days
+	// before tradeDate, and a service allowing 50% within 7 days.
+	setup := func(t *testing.T) (*gorm.DB, InvestmentServicer, string, string, string) {
+		t.Helper()
+		db := testutil.WithTx(t)
+		svc := NewInvestmentService(db, NewAccountService(db, nil), nil, nil, TradePriceCheck{MaxDeviation: 50, WindowDays: 7})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
+		testutil.CreateTestSecurityPrice(t, db, sec.ID, 10000, tradeDate.AddDate(0, 0, -2))
+		return db, svc, user.ID, inv.ID, sec.ID
+	}
+
+	t.Run("warns_on_a_far_off_buy_price", func(t *testing.T) {
+		_, svc, userID, invID, _ := setup(t)
+
+		_, err := svc.RecordBuy(models.UserID(userID), models.InvestmentID(invID), tradeDate, 1, 1000000, 0, "", "", false)
+		testutil.AssertAppError(t, err, "PRICE_DEVIATION_WARNING")
+
+		// Within the threshold passes
+		_, err = svc.RecordBuy(models.UserID(userID), models.InvestmentID(invID), tradeDate, 1, 14000, 0, "", "", false)
+		testutil.AssertNoError(t, err)
+	})
+
+	t.Run("warns_on_a_far_off_sell_price", func(t *testing.T) {
+		_, svc, userID, invID, _ := setup(t)
+
+		_, err := svc.RecordSell(models.UserID(userID), models.InvestmentID(invID), tradeDate, 1, 100, 0, "", false)
+		testutil.AssertAppError(t, err, "PRICE_DEVIATION_WARNING")
+	})
+
+	t.Run("confirm_overrides_the_warning", func(t *testing.T) {
+		_, svc, userID, invID, _ := setup(t)
+
+		buy, err := svc.RecordBuy(models.UserID(userID), models.InvestmentID(invID), tradeDate, 1, 1000000, 0, "", "", true)
+		testutil.AssertNoError(t, err)
+		if buy.PricePerUnit != 1000000 {
+			t.Errorf("expected the confirmed price recorded, got %d", buy.PricePerUnit)
+		}
+		_, err = svc.RecordSell(models.UserID(userID), models.InvestmentID(invID), tradeDate, 1, 100, 0, "", true)
+		testutil.AssertNoError(t, err)
+	})
+
+	t.Run("no_reference_price_within_the_window", func(t *testing.T) {
+		_, svc, userID, invID, _ := setup(t)
+
+		// The only stored price is 12 days before this trade
+		_, err := svc.RecordBuy(models.UserID(userID), models.InvestmentID(invID), tradeDate.AddDate(0, 0, 10), 1, 1000000, 0, "", "", false)
+		testutil.AssertNoError(t, err)
+	})
+
+	t.Run("no_stored_price_at_all", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewInvestmentService(db, NewAccountService(db, nil), nil, nil, TradePriceCheck{MaxDeviation: 50, WindowDays: 7})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, testutil.CreateTestSecurity(t, db).ID)
+
+		_, err := svc.RecordBuy(models.UserID(user.ID), models.InvestmentID(inv.ID), tradeDate, 1, 1000000, 0, "", "", false)
+		testutil.AssertNoError(t, err)
+	})
+
+	t.Run("uses_the_nearest_stored_price", func(t *testing.T) {
+		db, svc, userID, invID, secID := setup(t)
+
+		// A price a day after the trade is nearer than the one two days before
+		testutil.CreateTestSecurityPrice(t, db, secID, 100000, tradeDate.AddDate(0, 0, 1))
+
+		_, err := svc.RecordBuy(models.UserID(userID), models.InvestmentID(invID), tradeDate, 1, 100000, 0, "", "", false)
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordBuy(models.UserID(userID), models.InvestmentID(invID), tradeDate, 1, 10000, 0, "", "", false)
+		testutil.AssertAppError(t, err, "PRICE_DEVIATION_WARNING")
+	})
 }
 
 func TestRecordSell(t *testing.T) {
+	t.Parallel()
 	t.Run("valid", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 		sec := testutil.CreateTestSecurity(t, db)
 		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID) // 10 shares, cost basis 100000
 
-		sellTx, err := svc.RecordSell(user.ID, inv.ID, time.Now(), 4.0, 12000, 300, "Sell some")
+		sellTx, err := svc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 4.0, 12000, 300, "Sell some", false)
 		testutil.AssertNoError(t, err)
 
 		if sellTx.Type != models.InvestmentTransactionSell {
@@ -466,7 +1067,7 @@ func TestRecordSell(t *testing.T) {
 
 		// Verify investment updated in DB
 		var dbInv models.Investment
-		db.First(&dbInv, inv.ID)
+		db.Where("id = ?", inv.ID).First(&dbInv)
 		// 10 - 4 = 6 shares remaining
 		if dbInv.Quantity != 6.0 {
 			t.Errorf("expected quantity 6.0, got %f", dbInv.Quantity)
@@ -478,10 +1079,9 @@ func TestRecordSell(t *testing.T) {
 	})
 
 	t.Run("computes_realized_gain_loss_on_sell", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 		sec := testutil.CreateTestSecurity(t, db)
@@ -491,7 +1091,7 @@ func TestRecordSell(t *testing.T) {
 		// totalAmount = 5 * 15000 - 0 = 75000
 		// costBasisReduction = 100000 * (5/10) = 50000
 		// realizedGainLoss = 75000 - 50000 = 25000
-		sellTx, err := svc.RecordSell(user.ID, inv.ID, time.Now(), 5.0, 15000, 0, "Sell half at profit")
+		sellTx, err := svc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 5.0, 15000, 0, "Sell half at profit", false)
 		testutil.AssertNoError(t, err)
 
 		if sellTx.RealizedGainLoss != 25000 {
@@ -499,17 +1099,16 @@ func TestRecordSell(t *testing.T) {
 		}
 
 		var dbInv models.Investment
-		db.First(&dbInv, inv.ID)
+		db.Where("id = ?", inv.ID).First(&dbInv)
 		if dbInv.RealizedGainLoss != 25000 {
 			t.Errorf("expected investment realized gain/loss 25000, got %d", dbInv.RealizedGainLoss)
 		}
 	})
 
 	t.Run("accumulates_realized_gain_loss_on_investment", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 		sec := testutil.CreateTestSecurity(t, db)
@@ -519,7 +1118,7 @@ func TestRecordSell(t *testing.T) {
 		// totalAmount = 3 * 12000 = 36000
 		// costBasisReduction = 100000 * (3/10) = 30000
 		// realizedGL1 = 36000 - 30000 = 6000
-		sell1, err := svc.RecordSell(user.ID, inv.ID, time.Now(), 3.0, 12000, 0, "Sell 1")
+		sell1, err := svc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 3.0, 12000, 0, "Sell 1", false)
 		testutil.AssertNoError(t, err)
 		if sell1.RealizedGainLoss != 6000 {
 			t.Errorf("expected sell1 realized gain/loss 6000, got %d", sell1.RealizedGainLoss)
@@ -531,7 +1130,7 @@ func TestRecordSell(t *testing.T) {
 		// totalAmount = 2 * 8000 = 16000
 		// costBasisReduction = 70000 * (2/7) = 20000
 		// realizedGL2 = 16000 - 20000 = -4000
-		sell2, err := svc.RecordSell(user.ID, inv.ID, time.Now(), 2.0, 8000, 0, "Sell 2")
+		sell2, err := svc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 2.0, 8000, 0, "Sell 2", false)
 		testutil.AssertNoError(t, err)
 		if sell2.RealizedGainLoss != -4000 {
 			t.Errorf("expected sell2 realized gain/loss -4000, got %d", sell2.RealizedGainLoss)
@@ -539,17 +1138,16 @@ func TestRecordSell(t *testing.T) {
 
 		// Investment should have accumulated: 6000 + (-4000) = 2000
 		var dbInv models.Investment
-		db.First(&dbInv, inv.ID)
+		db.Where("id = ?", inv.ID).First(&dbInv)
 		if dbInv.RealizedGainLoss != 2000 {
 			t.Errorf("expected accumulated realized gain/loss 2000, got %d", dbInv.RealizedGainLoss)
 		}
 	})
 
 	t.Run("realized_gain_loss_for_losing_trade", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 		sec := testutil.CreateTestSecurity(t, db)
@@ -559,7 +1157,7 @@ func TestRecordSell(t *testing.T) {
 		// totalAmount = 10 * 5000 = 50000
 		// costBasisReduction = 100000 * (10/10) = 100000
 		// realizedGainLoss = 50000 - 100000 = -50000
-		sellTx, err := svc.RecordSell(user.ID, inv.ID, time.Now(), 10.0, 5000, 0, "Sell all at loss")
+		sellTx, err := svc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 10.0, 5000, 0, "Sell all at loss", false)
 		testutil.AssertNoError(t, err)
 
 		if sellTx.RealizedGainLoss != -50000 {
@@ -567,7 +1165,7 @@ func TestRecordSell(t *testing.T) {
 		}
 
 		var dbInv models.Investment
-		db.First(&dbInv, inv.ID)
+		db.Where("id = ?", inv.ID).First(&dbInv)
 		if dbInv.RealizedGainLoss != -50000 {
 			t.Errorf("expected investment realized gain/loss -50000, got %d", dbInv.RealizedGainLoss)
 		}
@@ -580,31 +1178,29 @@ func TestRecordSell(t *testing.T) {
 	})
 
 	t.Run("insufficient_shares", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 		sec := testutil.CreateTestSecurity(t, db)
 		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID) // 10 shares
 
-		_, err := svc.RecordSell(user.ID, inv.ID, time.Now(), 15.0, 12000, 0, "Too many")
+		_, err := svc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 15.0, 12000, 0, "Too many", false)
 		testutil.AssertAppError(t, err, "INSUFFICIENT_SHARES")
 
 		// Verify quantity unchanged
 		var dbInv models.Investment
-		db.First(&dbInv, inv.ID)
+		db.Where("id = ?", inv.ID).First(&dbInv)
 		if dbInv.Quantity != 10.0 {
 			t.Errorf("expected quantity unchanged at 10.0, got %f", dbInv.Quantity)
 		}
 	})
 
 	t.Run("sell_all_shares", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 		sec := testutil.CreateTestSecurity(t, db)
@@ -614,7 +1210,7 @@ func TestRecordSell(t *testing.T) {
 		// totalAmount = 10 * 12000 = 120000
 		// costBasisReduction = 100000 * (10/10) = 100000
 		// realizedGainLoss = 120000 - 100000 = 20000
-		sellTx, err := svc.RecordSell(user.ID, inv.ID, time.Now(), 10.0, 12000, 0, "Sell all")
+		sellTx, err := svc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 10.0, 12000, 0, "Sell all", false)
 		testutil.AssertNoError(t, err)
 
 		if sellTx.RealizedGainLoss != 20000 {
@@ -622,7 +1218,7 @@ func TestRecordSell(t *testing.T) {
 		}
 
 		var dbInv models.Investment
-		db.First(&dbInv, inv.ID)
+		db.Where("id = ?", inv.ID).First(&dbInv)
 		if dbInv.Quantity != 0.0 {
 			t.Errorf("expected quantity 0.0, got %f", dbInv.Quantity)
 		}
@@ -633,105 +1229,493 @@ func TestRecordSell(t *testing.T) {
 			t.Errorf("expected realized gain/loss 20000, got %d", dbInv.RealizedGainLoss)
 		}
 	})
-}
 
-func TestRecordDividend(t *testing.T) {
-	t.Run("valid", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+	t.Run("cost_basis_method_average_vs_fifo", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		day := func(d int) time.Time { return time.Date(2024, time.March, d, 12, 0, 0, 0, time.UTC) }
+
+		// Same history for both methods: buy 10 @ $10, buy 10 @ $20, sell 15 @ $30, sell 5 @ $30
+		type want struct{ firstGain, remainingCost, secondGain int64 }
+		cases := map[models.CostBasisMethod]want{
+			// 30000 * 15/20 = 22500 disposed of; the closing sell takes the remaining 7500
+			models.CostBasisAverage: {firstGain: 45000 - 22500, remainingCost: 7500, secondGain: 15000 - 7500},
+			// All of the $10 lot (10000) plus 5 units of the $20 lot (10000)
+			models.CostBasisFIFO: {firstGain: 45000 - 20000, remainingCost: 10000, secondGain: 15000 - 10000},
+		}
+		for method, want := range cases {
+			user := testutil.CreateTestUser(t, db)
+			testutil.AssertNoError(t, db.Model(user).Update("cost_basis_method", method).Error)
+			account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+			sec := testutil.CreateTestSecurity(t, db)
+			buyDate := day(1)
+			inv, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 10, 1000, "", &buyDate, 0, "", "", InvestmentMetadata{}, false)
+			testutil.AssertNoError(t, err)
+			_, err = svc.RecordBuy(models.UserID(user.ID), models.InvestmentID(inv.ID), day(2), 10, 2000, 0, "", "", false)
+			testutil.AssertNoError(t, err)
+
+			first, err := svc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv.ID), day(3), 15, 3000, 0, "", false)
+			testutil.AssertNoError(t, err)
+			if first.RealizedGainLoss != want.firstGain || first.CostBasisMethod != method {
+				t.Errorf("%s: expected first sell gain %d, got %d (%s)", method, want.firstGain, first.RealizedGainLoss, first.CostBasisMethod)
+			}
+			var dbInv models.Investment
+			db.Where("id = ?", inv.ID).First(&dbInv)
+			if dbInv.CostBasis != want.remainingCost {
+				t.Errorf("%s: expected remaining cost basis %d, got %d", method, want.remainingCost, dbInv.CostBasis)
+			}
+
+			second, err := svc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv.ID), day(4), 5, 3000, 0, "", false)
+			testutil.AssertNoError(t, err)
+			if second.RealizedGainLoss != want.secondGain {
+				t.Errorf("%s: expected closing sell gain %d, got %d", method, want.secondGain, second.RealizedGainLoss)
+			}
+			// Both methods realize the same total once the position is closed
+			db.Where("id = ?", inv.ID).First(&dbInv)
+			if dbInv.CostBasis != 0 || dbInv.RealizedGainLoss != 30000 {
+				t.Errorf("%s: expected closed position with 30000 realized, got cost %d, realized %d", method, dbInv.CostBasis, dbInv.RealizedGainLoss)
+			}
+		}
+	})
+
+	t.Run("fifo_falls_back_to_average_without_lots", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		testutil.AssertNoError(t, db.Model(user).Update("cost_basis_method", models.CostBasisFIFO).Error)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID) // 10 shares, cost basis 100000, no buy transaction
+
+		sellTx, err := svc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 4.0, 12000, 0, "", false)
+		testutil.AssertNoError(t, err)
+
+		if sellTx.CostBasisMethod != models.CostBasisAverage {
+			t.Errorf("expected the sell stamped average, got %q", sellTx.CostBasisMethod)
+		}
+		// 48000 - 100000 * (4/10)
+		if sellTx.RealizedGainLoss != 8000 {
+			t.Errorf("expected realized gain 8000, got %d", sellTx.RealizedGainLoss)
+		}
+	})
+
+	t.Run("switching_method_keeps_earlier_sells", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 10, 1000, "", nil, 0, "", "", InvestmentMetadata{}, false)
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordBuy(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 10, 2000, 0, "", "", false)
+		testutil.AssertNoError(t, err)
+
+		earlier, err := svc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 4, 3000, 0, "", false)
+		testutil.AssertNoError(t, err)
+		testutil.AssertNoError(t, db.Model(user).Update("cost_basis_method", models.CostBasisFIFO).Error)
+		later, err := svc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 2, 3000, 0, "", false)
+		testutil.AssertNoError(t, err)
+
+		var stored models.InvestmentTransaction
+		db.Where("id = ?", earlier.ID).First(&stored)
+		if stored.CostBasisMethod != models.CostBasisAverage || stored.RealizedGainLoss != earlier.RealizedGainLoss {
+			t.Errorf("expected the earlier sell to keep its average result, got %+v", stored)
+		}
+		// The earlier sell consumed 4 units of the $10 lot; 2 more of it cost 2000
+		if later.CostBasisMethod != models.CostBasisFIFO || later.RealizedGainLoss != 6000-2000 {
+			t.Errorf("expected a fifo sell with gain 4000, got %d (%s)", later.RealizedGainLoss, later.CostBasisMethod)
+		}
+	})
+}
+
+func TestRecordDividend(t *testing.T) {
+	t.Parallel()
+	t.Run("valid", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID) // 10 shares, cost basis 100000
+
+		divTx, err := svc.RecordDividend(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 5000, "Cash", "Q4 dividend", "")
+		testutil.AssertNoError(t, err)
+
+		if divTx.Type != models.InvestmentTransactionDividend {
+			t.Errorf("expected type dividend, got %s", divTx.Type)
+		}
+		if divTx.TotalAmount != 5000 {
+			t.Errorf("expected total 5000, got %d", divTx.TotalAmount)
+		}
+		if divTx.DividendType != "Cash" {
+			t.Errorf("expected dividend type Cash, got %s", divTx.DividendType)
+		}
+
+		// Verify investment quantity and cost basis unchanged
+		var dbInv models.Investment
+		db.Where("id = ?", inv.ID).First(&dbInv)
+		if dbInv.Quantity != 10.0 {
+			t.Errorf("expected quantity unchanged at 10.0, got %f", dbInv.Quantity)
+		}
+		if dbInv.CostBasis != 100000 {
+			t.Errorf("expected cost basis unchanged at 100000, got %d", dbInv.CostBasis)
+		}
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+
+		_, err := svc.RecordDividend(models.UserID(user.ID), models.InvestmentID(uuid.New()), time.Now(), 5000, "Cash", "", "")
+		testutil.AssertAppError(t, err, "INVESTMENT_NOT_FOUND")
+	})
+
+	t.Run("return_of_capital_lowers_cost_basis", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewInvestmentService(db, NewAccountService(db, nil), nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID) // 10 shares, cost basis 100000
+		uid, iid := models.UserID(user.ID), models.InvestmentID(inv.ID)
+
+		_, err := svc.RecordDividend(uid, iid, time.Now(), 1200, "Cash", "", "")
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordDividend(uid, iid, time.Now(), 5000, models.DividendReturnOfCapital, "", "")
+		testutil.AssertNoError(t, err)
+
+		result, err := svc.GetInvestmentByID(uid, iid)
+		testutil.AssertNoError(t, err)
+		if result.CostBasis != 95000 || result.Quantity != 10.0 {
+			t.Errorf("expected 10 shares at cost basis 95000, got %v at %d", result.Quantity, result.CostBasis)
+		}
+		if result.TotalDividends != 1200 {
+			t.Errorf("expected total dividends 1200 without the return of capital, got %d", result.TotalDividends)
+		}
+		portfolio, err := svc.GetPortfolio(context.Background(), uid)
+		testutil.AssertNoError(t, err)
+		if portfolio.TotalDividends != 1200 {
+			t.Errorf("expected portfolio dividends 1200 without the return of capital, got %d", portfolio.TotalDividends)
+		}
+
+		// Returning more than the remaining cost basis stops at zero
+		_, err = svc.RecordDividend(uid, iid, time.Now(), 200000, models.DividendReturnOfCapital, "", "")
+		testutil.AssertNoError(t, err)
+		result, err = svc.GetInvestmentByID(uid, iid)
+		testutil.AssertNoError(t, err)
+		if result.CostBasis != 0 {
+			t.Errorf("expected cost basis floored at 0, got %d", result.CostBasis)
+		}
+	})
+
+	t.Run("return_of_capital_lowers_fifo_lots_and_running_cost_basis", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewInvestmentService(db, NewAccountService(db, nil), nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		testutil.AssertNoError(t, db.Model(user).Update("cost_basis_method", models.CostBasisFIFO).Error)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
+		testutil.AssertNoError(t, db.Model(inv).Updates(map[string]interface{}{"quantity": 0, "cost_basis": 0}).Error)
+		uid, iid := models.UserID(user.ID), models.InvestmentID(inv.ID)
+		day := func(d int) time.Time { return time.Date(2026, time.January, d, 12, 0, 0, 0, time.UTC) }
+
+		_, err := svc.RecordBuy(uid, iid, day(1), 10, 10000, 0, "", "", false)
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordBuy(uid, iid, day(2), 10, 20000, 0, "", "", false)
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordDividend(uid, iid, day(3), 4000, models.DividendReturnOfCapital, "", "")
+		testutil.AssertNoError(t, err)
+
+		// 2000 comes off each lot, so the first ten units cost 98000
+		sellTx, err := svc.RecordSell(uid, iid, day(4), 10, 15000, 0, "", false)
+		testutil.AssertNoError(t, err)
+		if sellTx.CostBasisMethod != models.CostBasisFIFO || sellTx.RealizedGainLoss != 150000-98000 {
+			t.Errorf("expected a fifo gain of 52000, got %s %d", sellTx.CostBasisMethod, sellTx.RealizedGainLoss)
+		}
+
+		result, err := svc.GetInvestmentTransactions(uid, iid, pagination.PageRequest{Page: 1, PageSize: 20})
+		testutil.AssertNoError(t, err)
+		if len(result.Data) != 4 || result.Data[1].RunningCostBasis != 296000 || result.Data[0].RunningCostBasis != 198000 {
+			t.Errorf("expected running cost basis 296000 after the return of capital and 198000 after the sell, got %+v", result.Data)
+		}
+	})
+
+	t.Run("return_of_capital_is_not_applied_twice_for_a_repeat", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewInvestmentService(db, NewAccountService(db, nil), nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
+		uid, iid := models.UserID(user.ID), models.InvestmentID(inv.ID)
+
+		for range 2 {
+			_, err := svc.RecordDividend(uid, iid, time.Now(), 5000, models.DividendReturnOfCapital, "", "roc-2026")
+			testutil.AssertNoError(t, err)
+		}
+
+		var dbInv models.Investment
+		testutil.AssertNoError(t, db.Where("id = ?", inv.ID).First(&dbInv).Error)
+		if dbInv.CostBasis != 95000 {
+			t.Errorf("expected cost basis 95000, got %d", dbInv.CostBasis)
+		}
+	})
+
+	t.Run("idempotent_with_external_ref", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
+
+		first, err := svc.RecordDividend(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 5000, "Cash", "", "div-2026-q1")
+		testutil.AssertNoError(t, err)
+		second, err := svc.RecordDividend(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 5000, "Cash", "", "div-2026-q1")
+		testutil.AssertNoError(t, err)
+
+		if second.ID != first.ID {
+			t.Errorf("expected repeat to return transaction %s, got %s", first.ID, second.ID)
+		}
+		var count int64
+		db.Model(&models.InvestmentTransaction{}).Where("investment_id = ?", inv.ID).Count(&count)
+		if count != 1 {
+			t.Errorf("expected 1 dividend transaction, got %d", count)
+		}
+	})
+}
+
+func TestRecordSplit(t *testing.T) {
+	t.Parallel()
+	t.Run("valid", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID) // 10 shares, cost basis 100000
+
+		splitTx, err := svc.RecordSplit(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 2.0, SplitOptions{}, "2-for-1 split", "")
+		testutil.AssertNoError(t, err)
+
+		if splitTx.Type != models.InvestmentTransactionSplit {
+			t.Errorf("expected type split, got %s", splitTx.Type)
+		}
+		if splitTx.SplitRatio != 2.0 {
+			t.Errorf("expected split ratio 2.0, got %f", splitTx.SplitRatio)
+		}
+
+		// Verify quantity doubled, cost basis unchanged
+		var dbInv models.Investment
+		db.Where("id = ?", inv.ID).First(&dbInv)
+		if dbInv.Quantity != 20.0 {
+			t.Errorf("expected quantity 20.0 after 2:1 split, got %f", dbInv.Quantity)
+		}
+		if dbInv.CostBasis != 100000 {
+			t.Errorf("expected cost basis unchanged at 100000, got %d", dbInv.CostBasis)
+		}
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+
+		_, err := svc.RecordSplit(models.UserID(user.ID), models.InvestmentID(uuid.New()), time.Now(), 2.0, SplitOptions{}, "", "")
+		testutil.AssertAppError(t, err, "INVESTMENT_NOT_FOUND")
+	})
+
+	t.Run("idempotent_with_external_ref", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID) // 10 shares
+
+		first, err := svc.RecordSplit(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 2.0, SplitOptions{}, "", "split-2026-01")
+		testutil.AssertNoError(t, err)
+		second, err := svc.RecordSplit(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 2.0, SplitOptions{}, "", "split-2026-01")
+		testutil.AssertNoError(t, err)
+
+		if second.ID != first.ID {
+			t.Errorf("expected repeat to return transaction %s, got %s", first.ID, second.ID)
+		}
+		var count int64
+		db.Model(&models.InvestmentTransaction{}).Where("investment_id = ?", inv.ID).Count(&count)
+		if count != 1 {
+			t.Errorf("expected 1 split transaction, got %d", count)
+		}
+		var dbInv models.Investment
+		db.Where("id = ?", inv.ID).First(&dbInv)
+		if dbInv.Quantity != 20.0 {
+			t.Errorf("expected quantity 20.0 after a single 2:1 split, got %f", dbInv.Quantity)
+		}
+	})
+
+	t.Run("manual_entries_without_ref", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
+
+		_, err := svc.RecordSplit(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 2.0, SplitOptions{}, "", "")
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordSplit(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 2.0, SplitOptions{}, "", "")
+		testutil.AssertNoError(t, err)
+
+		var dbInv models.Investment
+		db.Where("id = ?", inv.ID).First(&dbInv)
+		if dbInv.Quantity != 40.0 {
+			t.Errorf("expected quantity 40.0 after two splits, got %f", dbInv.Quantity)
+		}
+	})
+
+	t.Run("reverse_split_1_for_10", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 		sec := testutil.CreateTestSecurity(t, db)
 		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID) // 10 shares, cost basis 100000
 
-		divTx, err := svc.RecordDividend(user.ID, inv.ID, time.Now(), 5000, "Cash", "Q4 dividend")
+		splitTx, err := svc.RecordSplit(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 0.1, SplitOptions{}, "1-for-10", "")
 		testutil.AssertNoError(t, err)
 
-		if divTx.Type != models.InvestmentTransactionDividend {
-			t.Errorf("expected type dividend, got %s", divTx.Type)
-		}
-		if divTx.TotalAmount != 5000 {
-			t.Errorf("expected total 5000, got %d", divTx.TotalAmount)
-		}
-		if divTx.DividendType != "Cash" {
-			t.Errorf("expected dividend type Cash, got %s", divTx.DividendType)
-		}
-
-		// Verify investment quantity and cost basis unchanged
 		var dbInv models.Investment
-		db.First(&dbInv, inv.ID)
-		if dbInv.Quantity != 10.0 {
-			t.Errorf("expected quantity unchanged at 10.0, got %f", dbInv.Quantity)
+		db.Where("id = ?", inv.ID).First(&dbInv)
+		if dbInv.Quantity != 1.0 {
+			t.Errorf("expected quantity 1.0 after a 1:10 reverse split, got %f", dbInv.Quantity)
 		}
 		if dbInv.CostBasis != 100000 {
 			t.Errorf("expected cost basis unchanged at 100000, got %d", dbInv.CostBasis)
 		}
+		if splitTx.SplitRemainder != 0 {
+			t.Errorf("expected no remainder, got %f", splitTx.SplitRemainder)
+		}
 	})
 
-	t.Run("not_found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+	t.Run("rounds_fractional_units_with_cash_in_lieu", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
+		db.Model(inv).Update("quantity", 25)
 
-		_, err := svc.RecordDividend(user.ID, 9999, time.Now(), 5000, "Cash", "")
-		testutil.AssertAppError(t, err, "INVESTMENT_NOT_FOUND")
+		// 25 shares 1-for-10 is 2.5 shares: keep 2, pay 0.5 at $40 in lieu
+		splitTx, err := svc.RecordSplit(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 0.1,
+			SplitOptions{Rounding: SplitRoundingDown, CashInLieuPrice: 4000}, "", "")
+		testutil.AssertNoError(t, err)
+
+		var dbInv models.Investment
+		db.Where("id = ?", inv.ID).First(&dbInv)
+		if dbInv.Quantity != 2.0 {
+			t.Errorf("expected quantity rounded down to 2.0, got %f", dbInv.Quantity)
+		}
+		if math.Abs(splitTx.SplitRemainder-0.5) > 1e-9 {
+			t.Errorf("expected remainder 0.5, got %f", splitTx.SplitRemainder)
+		}
+		var dividend models.InvestmentTransaction
+		testutil.AssertNoError(t, db.Where("investment_id = ? AND type = ?", inv.ID, models.InvestmentTransactionDividend).First(&dividend).Error)
+		if dividend.DividendType != "CashInLieu" || dividend.TotalAmount != 2000 {
+			t.Errorf("expected a 2000 cash-in-lieu dividend, got %s %d", dividend.DividendType, dividend.TotalAmount)
+		}
 	})
-}
 
-func TestRecordSplit(t *testing.T) {
-	t.Run("valid", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+	t.Run("rounds_to_nearest_without_cash", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 		sec := testutil.CreateTestSecurity(t, db)
-		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID) // 10 shares, cost basis 100000
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
+		db.Model(inv).Update("quantity", 17)
 
-		splitTx, err := svc.RecordSplit(user.ID, inv.ID, time.Now(), 2.0, "2-for-1 split")
+		// 17 shares 1-for-3 is 5.67 shares, rounded up to 6
+		splitTx, err := svc.RecordSplit(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 1.0/3,
+			SplitOptions{Rounding: SplitRoundingNearest}, "", "")
 		testutil.AssertNoError(t, err)
 
-		if splitTx.Type != models.InvestmentTransactionSplit {
-			t.Errorf("expected type split, got %s", splitTx.Type)
+		var dbInv models.Investment
+		db.Where("id = ?", inv.ID).First(&dbInv)
+		if dbInv.Quantity != 6.0 {
+			t.Errorf("expected quantity rounded to 6.0, got %f", dbInv.Quantity)
 		}
-		if splitTx.SplitRatio != 2.0 {
-			t.Errorf("expected split ratio 2.0, got %f", splitTx.SplitRatio)
+		if splitTx.SplitRemainder >= 0 {
+			t.Errorf("expected a negative remainder when rounding up, got %f", splitTx.SplitRemainder)
 		}
-
-		// Verify quantity doubled, cost basis unchanged
-		var dbInv models.Investment
-		db.First(&dbInv, inv.ID)
-		if dbInv.Quantity != 20.0 {
-			t.Errorf("expected quantity 20.0 after 2:1 split, got %f", dbInv.Quantity)
+		var count int64
+		db.Model(&models.InvestmentTransaction{}).Where("investment_id = ? AND type = ?", inv.ID, models.InvestmentTransactionDividend).Count(&count)
+		if count != 0 {
+			t.Errorf("expected no cash-in-lieu dividend, got %d", count)
 		}
-		if dbInv.CostBasis != 100000 {
-			t.Errorf("expected cost basis unchanged at 100000, got %d", dbInv.CostBasis)
+	})
+
+	t.Run("fifo_lots_follow_rounded_split", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		testutil.AssertNoError(t, db.Model(user).Update("cost_basis_method", models.CostBasisFIFO).Error)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 15, 1000, "", nil, 0, "", "", InvestmentMetadata{}, false)
+		testutil.AssertNoError(t, err)
+
+		_, err = svc.RecordSplit(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 0.1, SplitOptions{Rounding: SplitRoundingDown}, "", "")
+		testutil.AssertNoError(t, err)
+		sellTx, err := svc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 0.5, 10000, 0, "", false)
+		testutil.AssertNoError(t, err)
+
+		// The lot still accounts for the rounded quantity, so the sell is matched FIFO
+		if sellTx.CostBasisMethod != models.CostBasisFIFO {
+			t.Errorf("expected a fifo sell after the rounded split, got %q", sellTx.CostBasisMethod)
 		}
 	})
 
-	t.Run("not_found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+	t.Run("rejects_invalid_ratio", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
 
-		_, err := svc.RecordSplit(user.ID, 9999, time.Now(), 2.0, "")
-		testutil.AssertAppError(t, err, "INVESTMENT_NOT_FOUND")
+		for _, ratio := range []float64{0, -2, math.NaN(), math.Inf(1)} {
+			_, err := svc.RecordSplit(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), ratio, SplitOptions{}, "", "")
+			testutil.AssertAppError(t, err, "INVALID_INPUT")
+		}
+		_, err := svc.RecordSplit(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 0.5, SplitOptions{CashInLieuPrice: 100}, "", "")
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
 	})
 }
 
 func TestGetPortfolio(t *testing.T) {
+	t.Parallel()
 	t.Run("aggregation", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 
 		// Create two investment accounts
@@ -756,7 +1740,7 @@ func TestGetPortfolio(t *testing.T) {
 		}
 		testutil.CreateTestSecurityPrice(t, db, secETF.ID, 12000, time.Now()) // $120/share
 
-		portfolio, err := svc.GetPortfolio(user.ID)
+		portfolio, err := svc.GetPortfolio(context.Background(), models.UserID(user.ID))
 		testutil.AssertNoError(t, err)
 
 		// Stock value: 10 * 10000 = 100000
@@ -815,10 +1799,9 @@ func TestGetPortfolio(t *testing.T) {
 	})
 
 	t.Run("includes_realized_gain_loss", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		acct := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 
@@ -833,15 +1816,15 @@ func TestGetPortfolio(t *testing.T) {
 
 		// Sell 5 shares of AAPL at $150 (profit)
 		// totalAmount = 5 * 15000 = 75000, costBasisReduction = 50000, realized = 25000
-		_, err := svc.RecordSell(user.ID, inv1.ID, time.Now(), 5.0, 15000, 0, "")
+		_, err := svc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv1.ID), time.Now(), 5.0, 15000, 0, "", false)
 		testutil.AssertNoError(t, err)
 
 		// Sell 3 shares of GOOG at $80 (loss)
 		// totalAmount = 3 * 8000 = 24000, costBasisReduction = 30000, realized = -6000
-		_, err = svc.RecordSell(user.ID, inv2.ID, time.Now(), 3.0, 8000, 0, "")
+		_, err = svc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv2.ID), time.Now(), 3.0, 8000, 0, "", false)
 		testutil.AssertNoError(t, err)
 
-		portfolio, err := svc.GetPortfolio(user.ID)
+		portfolio, err := svc.GetPortfolio(context.Background(), models.UserID(user.ID))
 		testutil.AssertNoError(t, err)
 
 		// Total realized: 25000 + (-6000) = 19000
@@ -850,14 +1833,188 @@ func TestGetPortfolio(t *testing.T) {
 		}
 	})
 
+	t.Run("includes_dividends_in_total_return", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewInvestmentService(db, NewAccountService(db, nil), nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		acct := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+
+		aapl := testutil.CreateTestSecurityWithParams(t, db, "AAPL", "Apple Inc", models.AssetTypeStock, "NASDAQ")
+		goog := testutil.CreateTestSecurityWithParams(t, db, "GOOG", "Alphabet Inc", models.AssetTypeStock, "NASDAQ")
+		open := testutil.CreateTestInvestment(t, db, acct.ID, aapl.ID)   // 10 shares, cost basis 100000
+		closed := testutil.CreateTestInvestment(t, db, acct.ID, goog.ID) // 10 shares, cost basis 100000
+		testutil.CreateTestSecurityPrice(t, db, aapl.ID, 12000, time.Now())
+		testutil.CreateTestSecurityPrice(t, db, goog.ID, 10000, time.Now())
+
+		// Sell 4 AAPL at $150: proceeds 60000, cost 40000, realized 20000.
+		// The 6 left are worth 72000 on a cost of 60000, unrealized 12000.
+		_, err := svc.RecordSell(models.UserID(user.ID), models.InvestmentID(open.ID), time.Now(), 4.0, 15000, 0, "", false)
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordDividend(models.UserID(user.ID), models.InvestmentID(open.ID), time.Now(), 1500, "Cash", "", "")
+		testutil.AssertNoError(t, err)
+
+		// Close GOOG at cost; its dividend still counts.
+		_, err = svc.RecordSell(models.UserID(user.ID), models.InvestmentID(closed.ID), time.Now(), 10.0, 10000, 0, "", false)
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordDividend(models.UserID(user.ID), models.InvestmentID(closed.ID), time.Now(), 500, "Cash", "", "")
+		testutil.AssertNoError(t, err)
+
+		portfolio, err := svc.GetPortfolio(context.Background(), models.UserID(user.ID))
+		testutil.AssertNoError(t, err)
+
+		if portfolio.TotalRealizedGainLoss != 20000 {
+			t.Errorf("expected total realized gain/loss 20000, got %d", portfolio.TotalRealizedGainLoss)
+		}
+		if portfolio.TotalGainLoss != 12000 {
+			t.Errorf("expected unrealized gain/loss 12000, got %d", portfolio.TotalGainLoss)
+		}
+		if portfolio.TotalDividends != 2000 {
+			t.Errorf("expected total dividends 2000, got %d", portfolio.TotalDividends)
+		}
+		if portfolio.TotalReturn != 34000 {
+			t.Errorf("expected total return 34000, got %d", portfolio.TotalReturn)
+		}
+	})
+
+	// mixedCurrencies creates a USD holding worth $1000 (cost $1000) and a MYR
+	// holding worth RM900 (cost RM800), with USD→MYR recorded at 4.5.
+	mixedCurrencies := func(t *testing.T, db *gorm.DB, userID string) (usd, myr *models.Investment) {
+		t.Helper()
+		acct := testutil.CreateTestInvestmentAccount(t, db, userID)
+
+		aapl := testutil.CreateTestSecurity(t, db)
+		usd = testutil.CreateTestInvestment(t, db, acct.ID, aapl.ID) // 10 units, cost 100000
+		testutil.CreateTestSecurityPrice(t, db, aapl.ID, 10000, time.Now())
+
+		mbb := testutil.CreateTestSecurityWithParams(t, db, "1155", "Maybank", models.AssetTypeStock, "MYX")
+		testutil.AssertNoError(t, db.Model(mbb).Update("currency", "MYR").Error)
+		myr = &models.Investment{AccountID: acct.ID, SecurityID: mbb.ID, Quantity: 100, CostBasis: 80000}
+		testutil.AssertNoError(t, db.Create(myr).Error)
+		testutil.CreateTestSecurityPrice(t, db, mbb.ID, 900, time.Now())
+
+		_, err := NewExchangeRateService(db, 0).RecordRates([]ExchangeRateInput{
+			{FromCurrency: "USD", ToCurrency: "MYR", Rate: 4.5, RecordedAt: time.Now()},
+		})
+		testutil.AssertNoError(t, err)
+		return usd, myr
+	}
+
+	t.Run("converts_to_base_currency", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewInvestmentService(db, NewAccountService(db, nil), nil, NewExchangeRateService(db, 0), TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		usd, myr := mixedCurrencies(t, db, user.ID)
+
+		portfolio, err := svc.GetPortfolio(context.Background(), models.UserID(user.ID))
+		testutil.AssertNoError(t, err)
+
+		// RM900 / 4.5 = $200 and RM800 / 4.5 = $177.78
+		if portfolio.Currency != "USD" || portfolio.TotalValue != 120000 || portfolio.TotalCostBasis != 117778 {
+			t.Errorf("expected USD 120000 valued, 117778 cost, got %s %d, %d",
+				portfolio.Currency, portfolio.TotalValue, portfolio.TotalCostBasis)
+		}
+		if stock := portfolio.HoldingsByType[models.AssetTypeStock]; stock.Value != 120000 || stock.Count != 2 {
+			t.Errorf("expected 2 stocks worth 120000, got %+v", stock)
+		}
+		if len(portfolio.UnconvertedCurrencies) != 0 {
+			t.Errorf("expected every currency converted, got %v", portfolio.UnconvertedCurrencies)
+		}
+
+		holdings := make(map[string]PortfolioHolding)
+		for _, h := range portfolio.Holdings {
+			holdings[h.InvestmentID] = h
+		}
+		if h := holdings[usd.ID]; h.Currency != "USD" || h.Value != 100000 || h.ConvertedValue == nil || *h.ConvertedValue != 100000 || *h.ExchangeRate != 1 {
+			t.Errorf("expected the USD holding unconverted, got %+v", h)
+		}
+		if h := holdings[myr.ID]; h.Currency != "MYR" || h.Value != 90000 || h.CostBasis != 80000 || h.ConvertedValue == nil || *h.ConvertedValue != 20000 {
+			t.Errorf("expected RM900 native and $200 converted, got %+v", h)
+		}
+	})
+
+	t.Run("base_currency_other_than_usd", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewInvestmentService(db, NewAccountService(db, nil), nil, NewExchangeRateService(db, 0), TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		testutil.AssertNoError(t, db.Model(user).Update("base_currency", "MYR").Error)
+		mixedCurrencies(t, db, user.ID)
+
+		portfolio, err := svc.GetPortfolio(context.Background(), models.UserID(user.ID))
+		testutil.AssertNoError(t, err)
+
+		// $1000 * 4.5 = RM4500, plus RM900
+		if portfolio.Currency != "MYR" || portfolio.TotalValue != 540000 || portfolio.TotalCostBasis != 530000 {
+			t.Errorf("expected MYR 540000 valued, 530000 cost, got %s %d, %d",
+				portfolio.Currency, portfolio.TotalValue, portfolio.TotalCostBasis)
+		}
+	})
+
+	t.Run("converts_from_the_price_currency", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewInvestmentService(db, NewAccountService(db, nil), nil, NewExchangeRateService(db, 0), TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		acct := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		aapl := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, acct.ID, aapl.ID) // 10 units, cost $1000
+		// The oracle converted the USD quote to MYR before recording it
+		price := testutil.CreateTestSecurityPrice(t, db, aapl.ID, 45000, time.Now())
+		testutil.AssertNoError(t, db.Model(price).Update("currency", "MYR").Error)
+		_, err := NewExchangeRateService(db, 0).RecordRates([]ExchangeRateInput{
+			{FromCurrency: "USD", ToCurrency: "MYR", Rate: 4.5, RecordedAt: time.Now()},
+		})
+		testutil.AssertNoError(t, err)
+
+		portfolio, err := svc.GetPortfolio(context.Background(), models.UserID(user.ID))
+		testutil.AssertNoError(t, err)
+
+		// 10 * RM450 = RM4500 = $1000
+		if portfolio.TotalValue != 100000 || portfolio.TotalCostBasis != 100000 {
+			t.Errorf("expected $1000 valued at $1000 cost, got %d, %d", portfolio.TotalValue, portfolio.TotalCostBasis)
+		}
+		if len(portfolio.Holdings) != 1 {
+			t.Fatalf("expected one holding, got %d", len(portfolio.Holdings))
+		}
+		if h := portfolio.Holdings[0]; h.InvestmentID != inv.ID || h.Currency != "USD" || h.ValueCurrency != "MYR" || h.Value != 450000 || h.ConvertedValue == nil || *h.ConvertedValue != 100000 {
+			t.Errorf("expected RM4500 native and $1000 converted, got %+v", h)
+		}
+	})
+
+	t.Run("missing_rate_adds_holding_unconverted", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewInvestmentService(db, NewAccountService(db, nil), nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		_, myr := mixedCurrencies(t, db, user.ID)
+
+		portfolio, err := svc.GetPortfolio(context.Background(), models.UserID(user.ID))
+		testutil.AssertNoError(t, err)
+
+		// $1000 + RM900 and $1000 + RM800, as summed without conversion
+		if portfolio.TotalValue != 190000 || portfolio.TotalCostBasis != 180000 {
+			t.Errorf("expected unconverted sums 190000, 180000, got %d, %d", portfolio.TotalValue, portfolio.TotalCostBasis)
+		}
+		if stock := portfolio.HoldingsByType[models.AssetTypeStock]; stock.Value != 190000 || stock.Count != 2 {
+			t.Errorf("expected 2 stocks worth 190000, got %+v", stock)
+		}
+		if len(portfolio.UnconvertedCurrencies) != 1 || portfolio.UnconvertedCurrencies[0] != "MYR" {
+			t.Errorf("expected MYR unconverted, got %v", portfolio.UnconvertedCurrencies)
+		}
+		if len(portfolio.Holdings) != 2 {
+			t.Fatalf("expected both holdings listed, got %d", len(portfolio.Holdings))
+		}
+		for _, h := range portfolio.Holdings {
+			if h.InvestmentID == myr.ID && (h.ConvertedValue != nil || h.ExchangeRate != nil || h.Value != 90000) {
+				t.Errorf("expected the MYR holding valued natively only, got %+v", h)
+			}
+		}
+	})
+
 	t.Run("no_investments", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 
-		portfolio, err := svc.GetPortfolio(user.ID)
+		portfolio, err := svc.GetPortfolio(context.Background(), models.UserID(user.ID))
 		testutil.AssertNoError(t, err)
 
 		if portfolio.TotalValue != 0 {
@@ -872,10 +2029,9 @@ func TestGetPortfolio(t *testing.T) {
 	})
 
 	t.Run("user_isolation", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user1 := testutil.CreateTestUser(t, db)
 		user2 := testutil.CreateTestUser(t, db)
 
@@ -889,7 +2045,7 @@ func TestGetPortfolio(t *testing.T) {
 		testutil.CreateTestInvestment(t, db, acct2.ID, sec2.ID)
 		testutil.CreateTestSecurityPrice(t, db, sec2.ID, 10000, time.Now())
 
-		portfolio1, err := svc.GetPortfolio(user1.ID)
+		portfolio1, err := svc.GetPortfolio(context.Background(), models.UserID(user1.ID))
 		testutil.AssertNoError(t, err)
 
 		// Each user has 1 investment with 10 shares @ $100 = $1000 value
@@ -897,7 +2053,7 @@ func TestGetPortfolio(t *testing.T) {
 			t.Errorf("expected user1 total value 100000, got %d", portfolio1.TotalValue)
 		}
 
-		portfolio2, err := svc.GetPortfolio(user2.ID)
+		portfolio2, err := svc.GetPortfolio(context.Background(), models.UserID(user2.ID))
 		testutil.AssertNoError(t, err)
 		if portfolio2.TotalValue != 100000 {
 			t.Errorf("expected user2 total value 100000, got %d", portfolio2.TotalValue)
@@ -905,10 +2061,9 @@ func TestGetPortfolio(t *testing.T) {
 	})
 
 	t.Run("excludes_closed_from_count_but_includes_realized_gl", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		acct := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 
@@ -931,7 +2086,7 @@ func TestGetPortfolio(t *testing.T) {
 		}
 		testutil.CreateTestSecurityPrice(t, db, secClosed.ID, 20000, time.Now())
 
-		portfolio, err := svc.GetPortfolio(user.ID)
+		portfolio, err := svc.GetPortfolio(context.Background(), models.UserID(user.ID))
 		testutil.AssertNoError(t, err)
 
 		// TotalValue should only include open position: 10 * 15000 = 150000
@@ -963,12 +2118,86 @@ func TestGetPortfolio(t *testing.T) {
 	})
 }
 
+func TestGetHoldingSparklines(t *testing.T) {
+	t.Parallel()
+	db := testutil.WithTx(t)
+	svc := NewInvestmentService(db, NewAccountService(db, nil), nil, nil, TradePriceCheck{})
+	user := testutil.CreateTestUser(t, db)
+	acct := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	day := func(daysAgo int) time.Time { return today.AddDate(0, 0, -daysAgo).Add(12 * time.Hour) }
+
+	// Ten days of history, with an earlier intraday price on the latest day.
+	full := testutil.CreateTestSecurity(t, db)
+	testutil.CreateTestInvestment(t, db, acct.ID, full.ID)
+	for i := 9; i >= 0; i-- {
+		testutil.CreateTestSecurityPrice(t, db, full.ID, int64(10000+100*(9-i)), day(i))
+	}
+	testutil.CreateTestSecurityPrice(t, db, full.ID, 99999, day(0).Add(-time.Hour))
+
+	// Only three days of history, with gaps.
+	short := testutil.CreateTestSecurity(t, db)
+	testutil.CreateTestInvestment(t, db, acct.ID, short.ID)
+	testutil.CreateTestSecurityPrice(t, db, short.ID, 20000, day(6))
+	testutil.CreateTestSecurityPrice(t, db, short.ID, 19000, day(3))
+	testutil.CreateTestSecurityPrice(t, db, short.ID, 18000, day(1))
+
+	// Held but never priced.
+	unpriced := testutil.CreateTestSecurity(t, db)
+	testutil.CreateTestInvestment(t, db, acct.ID, unpriced.ID)
+
+	// Priced but not held by the user.
+	other := testutil.CreateTestSecurity(t, db)
+	testutil.CreateTestSecurityPrice(t, db, other.ID, 5000, day(0))
+
+	portfolio, err := svc.GetPortfolio(context.Background(), models.UserID(user.ID))
+	testutil.AssertNoError(t, err)
+	sparklines, err := svc.GetHoldingSparklines(portfolio.Holdings)
+	testutil.AssertNoError(t, err)
+
+	if len(sparklines) != 2 {
+		t.Fatalf("expected sparklines for the 2 priced holdings, got %d", len(sparklines))
+	}
+	if _, ok := sparklines[unpriced.ID]; ok {
+		t.Error("expected no sparkline for an unpriced security")
+	}
+	if _, ok := sparklines[other.ID]; ok {
+		t.Error("expected no sparkline for a security the user does not hold")
+	}
+
+	fullLine := sparklines[full.ID]
+	if len(fullLine.Closes) != SparklineDays {
+		t.Fatalf("expected %d closes, got %d", SparklineDays, len(fullLine.Closes))
+	}
+	first, last := fullLine.Closes[0], fullLine.Closes[SparklineDays-1]
+	if first.Date != day(6).Format("2006-01-02") || first.Price != 10300 {
+		t.Errorf("expected the first close 10300 on %s, got %+v", day(6).Format("2006-01-02"), first)
+	}
+	if last.Date != day(0).Format("2006-01-02") || last.Price != 10900 {
+		t.Errorf("expected the day's last price 10900 as the latest close, got %+v", last)
+	}
+	if fullLine.ChangePct == nil || *fullLine.ChangePct != 5.83 {
+		t.Errorf("expected a change of 5.83%%, got %v", fullLine.ChangePct)
+	}
+
+	shortLine := sparklines[short.ID]
+	if len(shortLine.Closes) != 3 {
+		t.Fatalf("expected 3 closes, got %d", len(shortLine.Closes))
+	}
+	if shortLine.Closes[0].Price != 20000 || shortLine.Closes[2].Price != 18000 {
+		t.Errorf("expected closes oldest first, got %+v", shortLine.Closes)
+	}
+	if shortLine.ChangePct == nil || *shortLine.ChangePct != -10 {
+		t.Errorf("expected a change of -10%%, got %v", shortLine.ChangePct)
+	}
+}
+
 func TestGetAllInvestments(t *testing.T) {
+	t.Parallel()
 	t.Run("returns_investments_across_accounts", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 
 		acct1 := testutil.CreateTestInvestmentAccount(t, db, user.ID)
@@ -983,7 +2212,7 @@ func TestGetAllInvestments(t *testing.T) {
 		testutil.CreateTestSecurityPrice(t, db, sec2.ID, 12000, time.Now())
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := svc.GetAllInvestments(user.ID, page)
+		result, err := svc.GetAllInvestments(models.UserID(user.ID), page)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 2 {
@@ -996,26 +2225,119 @@ func TestGetAllInvestments(t *testing.T) {
 		// Verify CurrentPrice, Security, and Account are populated
 		for _, inv := range result.Data {
 			if inv.CurrentPrice == 0 {
-				t.Errorf("expected non-zero CurrentPrice for investment %d", inv.ID)
+				t.Errorf("expected non-zero CurrentPrice for investment %s", inv.ID)
 			}
 			if inv.Security.Symbol == "" {
-				t.Errorf("expected Security preloaded for investment %d", inv.ID)
+				t.Errorf("expected Security preloaded for investment %s", inv.ID)
 			}
 			if inv.Account.Name == "" {
-				t.Errorf("expected Account preloaded for investment %d", inv.ID)
+				t.Errorf("expected Account preloaded for investment %s", inv.ID)
+			}
+		}
+	})
+
+	t.Run("populates_price_timestamp_from_latest_record", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+
+		priced := testutil.CreateTestSecurityWithParams(t, db, "AAPL", "Apple Inc", models.AssetTypeStock, "NASDAQ")
+		unpriced := testutil.CreateTestSecurityWithParams(t, db, "VTI", "Vanguard Total", models.AssetTypeETF, "NYSE")
+		testutil.CreateTestInvestment(t, db, account.ID, priced.ID)
+		testutil.CreateTestInvestment(t, db, account.ID, unpriced.ID)
+
+		base := time.Date(2025, 3, 1, 16, 0, 0, 0, time.UTC)
+		testutil.CreateTestSecurityPrice(t, db, priced.ID, 14000, base)
+		testutil.CreateTestSecurityPrice(t, db, priced.ID, 15000, base.Add(24*time.Hour))
+
+		page := pagination.PageRequest{Page: 1, PageSize: 20}
+		result, err := svc.GetAllInvestments(models.UserID(user.ID), page)
+		testutil.AssertNoError(t, err)
+
+		for _, inv := range result.Data {
+			switch inv.SecurityID {
+			case priced.ID:
+				if inv.CurrentPriceAsOf == nil || !inv.CurrentPriceAsOf.Equal(base.Add(24*time.Hour)) {
+					t.Errorf("expected CurrentPriceAsOf %v, got %v", base.Add(24*time.Hour), inv.CurrentPriceAsOf)
+				}
+			case unpriced.ID:
+				if inv.CurrentPriceAsOf != nil {
+					t.Errorf("expected nil CurrentPriceAsOf for unpriced security, got %v", inv.CurrentPriceAsOf)
+				}
+			}
+		}
+	})
+
+	t.Run("computes_performance_per_holding", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+
+		// Each holding is 10 units with a $1000 cost basis unless changed below.
+		gainer := testutil.CreateTestSecurityWithParams(t, db, "AAPL", "Apple Inc", models.AssetTypeStock, "NASDAQ")
+		loser := testutil.CreateTestSecurityWithParams(t, db, "VTI", "Vanguard Total", models.AssetTypeETF, "NYSE")
+		gifted := testutil.CreateTestSecurityWithParams(t, db, "GIFT", "Gifted Shares", models.AssetTypeStock, "NYSE")
+		testutil.CreateTestInvestment(t, db, account.ID, gainer.ID)
+		testutil.CreateTestInvestment(t, db, account.ID, loser.ID)
+		free := testutil.CreateTestInvestment(t, db, account.ID, gifted.ID)
+		db.Model(free).Update("cost_basis", 0)
+
+		base := time.Date(2025, 3, 1, 16, 0, 0, 0, time.UTC)
+		testutil.CreateTestSecurityPrice(t, db, gainer.ID, 9000, base)
+		testutil.CreateTestSecurityPrice(t, db, gainer.ID, 12000, base.Add(24*time.Hour))
+		testutil.CreateTestSecurityPrice(t, db, loser.ID, 11000, base)
+		testutil.CreateTestSecurityPrice(t, db, loser.ID, 9000, base.Add(24*time.Hour))
+		testutil.CreateTestSecurityPrice(t, db, gifted.ID, 5000, base)
+
+		page := pagination.PageRequest{Page: 1, PageSize: 20}
+		result, err := svc.GetAllInvestments(models.UserID(user.ID), page)
+		testutil.AssertNoError(t, err)
+
+		int64Ptr := func(v int64) *int64 { return &v }
+		want := map[string]struct {
+			value, gain int64
+			pct         float64
+			dayChange   *int64
+		}{
+			gainer.ID: {value: 120000, gain: 20000, pct: 20, dayChange: int64Ptr(30000)},
+			loser.ID:  {value: 90000, gain: -10000, pct: -10, dayChange: int64Ptr(-20000)},
+			gifted.ID: {value: 50000, gain: 50000, pct: 0, dayChange: nil},
+		}
+		if len(result.Data) != len(want) {
+			t.Fatalf("expected %d investments, got %d", len(want), len(result.Data))
+		}
+		for _, inv := range result.Data {
+			w := want[inv.SecurityID]
+			if inv.CurrentValue != w.value {
+				t.Errorf("%s: expected current_value %d, got %d", inv.Security.Symbol, w.value, inv.CurrentValue)
+			}
+			if inv.UnrealizedGainLoss != w.gain {
+				t.Errorf("%s: expected unrealized_gain_loss %d, got %d", inv.Security.Symbol, w.gain, inv.UnrealizedGainLoss)
+			}
+			if math.Abs(inv.GainLossPct-w.pct) > 0.0001 {
+				t.Errorf("%s: expected gain_loss_pct %.2f, got %.2f", inv.Security.Symbol, w.pct, inv.GainLossPct)
+			}
+			switch {
+			case w.dayChange == nil && inv.DayChange != nil:
+				t.Errorf("%s: expected nil day_change, got %d", inv.Security.Symbol, *inv.DayChange)
+			case w.dayChange != nil && (inv.DayChange == nil || *inv.DayChange != *w.dayChange):
+				t.Errorf("%s: expected day_change %d, got %v", inv.Security.Symbol, *w.dayChange, inv.DayChange)
 			}
 		}
 	})
 
 	t.Run("returns_empty_for_no_investments", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := svc.GetAllInvestments(user.ID, page)
+		result, err := svc.GetAllInvestments(models.UserID(user.ID), page)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 0 {
@@ -1027,10 +2349,9 @@ func TestGetAllInvestments(t *testing.T) {
 	})
 
 	t.Run("paginates_results", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		acct := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 
@@ -1040,7 +2361,7 @@ func TestGetAllInvestments(t *testing.T) {
 		}
 
 		page := pagination.PageRequest{Page: 1, PageSize: 2}
-		result, err := svc.GetAllInvestments(user.ID, page)
+		result, err := svc.GetAllInvestments(models.UserID(user.ID), page)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 5 {
@@ -1054,7 +2375,7 @@ func TestGetAllInvestments(t *testing.T) {
 		}
 
 		page2 := pagination.PageRequest{Page: 3, PageSize: 2}
-		result2, err := svc.GetAllInvestments(user.ID, page2)
+		result2, err := svc.GetAllInvestments(models.UserID(user.ID), page2)
 		testutil.AssertNoError(t, err)
 		if len(result2.Data) != 1 {
 			t.Errorf("expected 1 item on page 3, got %d", len(result2.Data))
@@ -1062,10 +2383,9 @@ func TestGetAllInvestments(t *testing.T) {
 	})
 
 	t.Run("excludes_inactive_accounts", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 
 		activeAcct := testutil.CreateTestInvestmentAccount(t, db, user.ID)
@@ -1080,7 +2400,7 @@ func TestGetAllInvestments(t *testing.T) {
 		testutil.CreateTestInvestment(t, db, inactiveAcct.ID, sec2.ID)
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := svc.GetAllInvestments(user.ID, page)
+		result, err := svc.GetAllInvestments(models.UserID(user.ID), page)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 1 {
@@ -1089,10 +2409,9 @@ func TestGetAllInvestments(t *testing.T) {
 	})
 
 	t.Run("excludes_closed_positions", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		acct := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 
@@ -1114,7 +2433,7 @@ func TestGetAllInvestments(t *testing.T) {
 		}
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := svc.GetAllInvestments(user.ID, page)
+		result, err := svc.GetAllInvestments(models.UserID(user.ID), page)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 1 {
@@ -1127,24 +2446,24 @@ func TestGetAllInvestments(t *testing.T) {
 }
 
 func TestGetInvestmentTransactions(t *testing.T) {
+	t.Parallel()
 	t.Run("returns_transactions", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
 		sec := testutil.CreateTestSecurity(t, db)
 		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
 
 		// Record some transactions
-		_, err := svc.RecordBuy(user.ID, inv.ID, time.Now(), 5.0, 10000, 0, "Buy 1")
+		_, err := svc.RecordBuy(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 5.0, 10000, 0, "Buy 1", "", false)
 		testutil.AssertNoError(t, err)
-		_, err = svc.RecordDividend(user.ID, inv.ID, time.Now(), 2000, "Cash", "Div")
+		_, err = svc.RecordDividend(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 2000, "Cash", "Div", "")
 		testutil.AssertNoError(t, err)
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := svc.GetInvestmentTransactions(user.ID, inv.ID, page)
+		result, err := svc.GetInvestmentTransactions(models.UserID(user.ID), models.InvestmentID(inv.ID), page)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 2 {
@@ -1152,15 +2471,235 @@ func TestGetInvestmentTransactions(t *testing.T) {
 		}
 	})
 
+	t.Run("running_position_after_buys_sell_and_split", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
+		testutil.AssertNoError(t, db.Model(inv).Updates(map[string]interface{}{"quantity": 0, "cost_basis": 0}).Error)
+		uid, iid := models.UserID(user.ID), models.InvestmentID(inv.ID)
+		day := func(d int) time.Time { return time.Date(2026, time.January, d, 12, 0, 0, 0, time.UTC) }
+
+		_, err := svc.RecordBuy(uid, iid, day(1), 10, 10000, 0, "", "", false)
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordBuy(uid, iid, day(2), 10, 20000, 0, "", "", false)
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordSell(uid, iid, day(3), 5, 30000, 0, "", false)
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordDividend(uid, iid, day(4), 1500, "Cash", "", "")
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordSplit(uid, iid, day(5), 2, SplitOptions{}, "", "")
+		testutil.AssertNoError(t, err)
+
+		result, err := svc.GetInvestmentTransactions(uid, iid, pagination.PageRequest{Page: 1, PageSize: 20})
+		testutil.AssertNoError(t, err)
+
+		// Newest first: split, dividend, sell, buy, buy
+		want := []struct {
+			txType    models.InvestmentTransactionType
+			quantity  float64
+			costBasis int64
+		}{
+			{models.InvestmentTransactionSplit, 30, 225000},
+			{models.InvestmentTransactionDividend, 15, 225000},
+			{models.InvestmentTransactionSell, 15, 225000},
+			{models.InvestmentTransactionBuy, 20, 300000},
+			{models.InvestmentTransactionBuy, 10, 100000},
+		}
+		if len(result.Data) != len(want) {
+			t.Fatalf("expected %d transactions, got %d", len(want), len(result.Data))
+		}
+		for i, w := range want {
+			got := result.Data[i]
+			if got.Type != w.txType || got.RunningQuantity != w.quantity || got.RunningCostBasis != w.costBasis {
+				t.Errorf("transaction %d: expected %s holding %v at cost %d, got %s holding %v at cost %d",
+					i, w.txType, w.quantity, w.costBasis, got.Type, got.RunningQuantity, got.RunningCostBasis)
+			}
+		}
+
+		// A later page keeps the positions replayed from the start
+		second, err := svc.GetInvestmentTransactions(uid, iid, pagination.PageRequest{Page: 2, PageSize: 2})
+		testutil.AssertNoError(t, err)
+		if len(second.Data) != 2 || second.Data[0].RunningQuantity != 15 || second.Data[1].RunningQuantity != 20 {
+			t.Errorf("expected the sell and second buy at 15 and 20 units, got %+v", second.Data)
+		}
+
+		var holding models.Investment
+		testutil.AssertNoError(t, db.First(&holding, "id = ?", inv.ID).Error)
+		if holding.Quantity != want[0].quantity || holding.CostBasis != want[0].costBasis {
+			t.Errorf("expected the latest position to match the holding, got %v at cost %d", holding.Quantity, holding.CostBasis)
+		}
+	})
+
 	t.Run("not_found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		svc := NewInvestmentService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
 		user := testutil.CreateTestUser(t, db)
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		_, err := svc.GetInvestmentTransactions(user.ID, 9999, page)
+		_, err := svc.GetInvestmentTransactions(models.UserID(user.ID), models.InvestmentID(uuid.New()), page)
 		testutil.AssertAppError(t, err, "INVESTMENT_NOT_FOUND")
 	})
 }
+
+func TestGetTaxReport(t *testing.T) {
+	t.Parallel()
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 12, 0, 0, 0, time.UTC)
+	}
+
+	t.Run("classifies_short_and_long_term_with_fifo_lots", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+
+		firstBuy := day(2022, time.March, 1)
+		inv, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 10, 1000, "", &firstBuy, 0, "", "", InvestmentMetadata{}, false)
+		testutil.AssertNoError(t, err)
+		// A prior-year sale consumes 2 units of the first lot but is not reported
+		_, err = svc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv.ID), day(2023, time.May, 1), 2, 1500, 0, "", false)
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordBuy(models.UserID(user.ID), models.InvestmentID(inv.ID), day(2024, time.January, 10), 10, 2000, 0, "", "", false)
+		testutil.AssertNoError(t, err)
+		// Long-term: 5 units from the 2022 lot
+		_, err = svc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv.ID), day(2024, time.June, 1), 5, 3000, 0, "", false)
+		testutil.AssertNoError(t, err)
+		// Mixed: the last 3 units of the 2022 lot, then 7 units of the 2024 lot
+		mixed, err := svc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv.ID), day(2024, time.September, 1), 10, 2500, 0, "", false)
+		testutil.AssertNoError(t, err)
+
+		report, err := svc.GetTaxReport(models.UserID(user.ID), 2024)
+		testutil.AssertNoError(t, err)
+
+		if len(report.Sales) != 3 {
+			t.Fatalf("expected 3 sale entries, got %d", len(report.Sales))
+		}
+		first := report.Sales[0]
+		if first.HoldingPeriod != HoldingPeriodLong || first.Proceeds != 15000 || first.CostBasis != 5000 || first.RealizedGain != 10000 {
+			t.Errorf("unexpected first sale: %+v", first)
+		}
+		if !first.AcquiredAt.Equal(firstBuy) {
+			t.Errorf("expected acquired at %v, got %v", firstBuy, first.AcquiredAt)
+		}
+
+		var long, short *TaxReportSale
+		for i := range report.Sales[1:] {
+			sale := &report.Sales[1+i]
+			if sale.TransactionID != mixed.ID {
+				t.Fatalf("expected mixed sale entries to share transaction %s, got %s", mixed.ID, sale.TransactionID)
+			}
+			if sale.HoldingPeriod == HoldingPeriodLong {
+				long = sale
+			} else {
+				short = sale
+			}
+		}
+		if long == nil || short == nil {
+			t.Fatal("expected the mixed sale to be split into long and short entries")
+		}
+		if long.Quantity != 3 || long.Proceeds != 7500 || long.CostBasis != 3000 || long.RealizedGain != 4500 {
+			t.Errorf("unexpected long-term portion: %+v", *long)
+		}
+		if short.Quantity != 7 || short.Proceeds != 17500 || short.CostBasis != 14000 || short.RealizedGain != 3500 {
+			t.Errorf("unexpected short-term portion: %+v", *short)
+		}
+
+		if report.LongTermGain != 14500 {
+			t.Errorf("expected long-term gain 14500, got %d", report.LongTermGain)
+		}
+		if report.ShortTermGain != 3500 {
+			t.Errorf("expected short-term gain 3500, got %d", report.ShortTermGain)
+		}
+		if report.TotalGain != 18000 || report.TotalProceeds != 40000 || report.TotalCostBasis != 22000 {
+			t.Errorf("unexpected totals: gain=%d proceeds=%d cost=%d", report.TotalGain, report.TotalProceeds, report.TotalCostBasis)
+		}
+	})
+
+	t.Run("exactly_one_year_is_short_term", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+
+		bought := day(2023, time.March, 1)
+		inv, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 4, 1000, "", &bought, 0, "", "", InvestmentMetadata{}, false)
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv.ID), day(2024, time.March, 1), 4, 900, 0, "", false)
+		testutil.AssertNoError(t, err)
+
+		report, err := svc.GetTaxReport(models.UserID(user.ID), 2024)
+		testutil.AssertNoError(t, err)
+
+		if len(report.Sales) != 1 || report.Sales[0].HoldingPeriod != HoldingPeriodShort {
+			t.Fatalf("expected one short-term sale, got %+v", report.Sales)
+		}
+		if report.ShortTermGain != -400 || report.LongTermGain != 0 {
+			t.Errorf("expected short-term loss 400, got short=%d long=%d", report.ShortTermGain, report.LongTermGain)
+		}
+	})
+
+	t.Run("splits_scale_lot_quantities", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+
+		bought := day(2023, time.January, 5)
+		inv, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 10, 1000, "", &bought, 0, "", "", InvestmentMetadata{}, false)
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordSplit(models.UserID(user.ID), models.InvestmentID(inv.ID), day(2023, time.June, 1), 2, SplitOptions{}, "", "")
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv.ID), day(2024, time.February, 1), 20, 600, 0, "", false)
+		testutil.AssertNoError(t, err)
+
+		report, err := svc.GetTaxReport(models.UserID(user.ID), 2024)
+		testutil.AssertNoError(t, err)
+
+		if len(report.Sales) != 1 {
+			t.Fatalf("expected 1 sale entry, got %d", len(report.Sales))
+		}
+		sale := report.Sales[0]
+		if sale.HoldingPeriod != HoldingPeriodLong || sale.CostBasis != 10000 || sale.RealizedGain != 2000 {
+			t.Errorf("unexpected sale after split: %+v", sale)
+		}
+	})
+
+	t.Run("excludes_other_years_and_users", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		svc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		other := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		otherAccount := testutil.CreateTestInvestmentAccount(t, db, other.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+
+		bought := day(2023, time.January, 5)
+		inv, _, err := svc.AddInvestment(models.UserID(user.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 10, 1000, "", &bought, 0, "", "", InvestmentMetadata{}, false)
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordSell(models.UserID(user.ID), models.InvestmentID(inv.ID), day(2023, time.December, 31), 5, 1200, 0, "", false)
+		testutil.AssertNoError(t, err)
+		otherInv, _, err := svc.AddInvestment(models.UserID(other.ID), models.AccountID(otherAccount.ID), SecurityRef{ID: sec.ID}, 10, 1000, "", &bought, 0, "", "", InvestmentMetadata{}, false)
+		testutil.AssertNoError(t, err)
+		_, err = svc.RecordSell(models.UserID(other.ID), models.InvestmentID(otherInv.ID), day(2024, time.March, 1), 5, 1200, 0, "", false)
+		testutil.AssertNoError(t, err)
+
+		report, err := svc.GetTaxReport(models.UserID(user.ID), 2024)
+		testutil.AssertNoError(t, err)
+
+		if report.Year != 2024 || len(report.Sales) != 0 || report.TotalGain != 0 {
+			t.Errorf("expected an empty 2024 report, got %+v", report)
+		}
+	})
+}
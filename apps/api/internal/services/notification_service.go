@@ -0,0 +1,112 @@
+package services
+
+import (
+	"strings"
+	"sync"
+	"text/template"
+
+	"gorm.io/gorm"
+
+	"kuberan/internal/logger"
+	"kuberan/internal/models"
+	"kuberan/internal/money"
+	"kuberan/internal/notify"
+)
+
+var notificationFuncs = template.FuncMap{"money": money.FormatDecimal}
+
+var budgetAlertSubject = template.Must(template.New("budget_alert_subject").Funcs(notificationFuncs).Parse(
+	`Budget exceeded: {{.Alert.BudgetName}}`))
+
+var budgetAlertBody = template.Must(template.New("budget_alert_body").Funcs(notificationFuncs).Parse(
+	`Hi {{.Name}},
+
+You have spent {{money .Alert.Spent .Alert.Currency}} {{.Alert.Currency}} on {{.Alert.CategoryName}} this {{if eq .Alert.Period "yearly"}}year{{else}}month{{end}}, over your "{{.Alert.BudgetName}}" budget of {{money .Alert.Budgeted .Alert.Currency}} {{.Alert.Currency}}.
+`))
+
+var largeTransactionAlertSubject = template.Must(template.New("large_transaction_alert_subject").Funcs(notificationFuncs).Parse(
+	`Large {{.Alert.Type}} of {{money .Alert.Amount .Alert.Currency}} {{.Alert.Currency}} on {{.Alert.AccountName}}`))
+
+var largeTransactionAlertBody = template.Must(template.New("large_transaction_alert_body").Funcs(notificationFuncs).Parse(
+	`Hi {{.Name}},
+
+A {{.Alert.Type}} of {{money .Alert.Amount .Alert.Currency}} {{.Alert.Currency}} was recorded on {{.Alert.AccountName}} on {{.Alert.Date.Format "2006-01-02"}}{{if .Alert.Description}} ({{.Alert.Description}}){{end}}.
+You are alerted about transactions of {{money .Alert.Threshold .Alert.Currency}} {{.Alert.Currency}} or more; change this in your profile settings.
+`))
+
+// notificationService renders alert emails and hands them to a notify.Sender.
+type notificationService struct {
+	db      *gorm.DB
+	sender  notify.Sender
+	pending sync.WaitGroup
+}
+
+// NewNotificationService creates a new NotificationServicer. A nil sender
+// disables delivery, so alerts are dropped when SMTP is not configured.
+func NewNotificationService(db *gorm.DB, sender notify.Sender) NotificationServicer {
+	return &notificationService{db: db, sender: sender}
+}
+
+// SendBudgetAlert emails the user that spending has gone over a budget.
+func (s *notificationService) SendBudgetAlert(userID string, alert BudgetAlertData) {
+	s.enqueue(userID, budgetAlertSubject, budgetAlertBody, alert)
+}
+
+// SendLargeTransactionAlert emails the user about a transaction at or above their threshold.
+func (s *notificationService) SendLargeTransactionAlert(userID string, alert LargeTransactionAlertData) {
+	s.enqueue(userID, largeTransactionAlertSubject, largeTransactionAlertBody, alert)
+}
+
+// Wait blocks until every queued notification has been attempted.
+func (s *notificationService) Wait() {
+	s.pending.Wait()
+}
+
+// enqueue renders and sends a notification in the background. Failures are
+// logged rather than returned because the triggering operation has already succeeded.
+func (s *notificationService) enqueue(userID string, subject, body *template.Template, alert any) {
+	if s.sender == nil {
+		return
+	}
+
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+
+		var user models.User
+		if err := s.db.Select("email", "first_name", "display_name").Where("id = ?", userID).First(&user).Error; err != nil {
+			logger.Get().Errorw("failed to load notification recipient", "user_id", userID, "error", err)
+			return
+		}
+
+		data := struct {
+			Name  string
+			Alert any
+		}{Name: recipientName(&user), Alert: alert}
+
+		var subj, text strings.Builder
+		if err := subject.Execute(&subj, data); err != nil {
+			logger.Get().Errorw("failed to render notification", "template", subject.Name(), "error", err)
+			return
+		}
+		if err := body.Execute(&text, data); err != nil {
+			logger.Get().Errorw("failed to render notification", "template", body.Name(), "error", err)
+			return
+		}
+
+		if err := s.sender.Send(notify.Message{To: user.Email, Subject: subj.String(), Body: text.String()}); err != nil {
+			logger.Get().Errorw("failed to send notification", "user_id", userID, "template", body.Name(), "error", err)
+		}
+	}()
+}
+
+// recipientName picks the friendliest name available for a greeting.
+func recipientName(user *models.User) string {
+	if user.DisplayName != "" {
+		return user.DisplayName
+	}
+	if user.FirstName != "" {
+		return user.FirstName
+	}
+	return user.Email
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"kuberan/internal/models"
+	"kuberan/internal/testutil"
+)
+
+// recordingNotifier captures alert data passed to a NotificationServicer.
+type recordingNotifier struct {
+	budgetAlerts           []BudgetAlertData
+	largeTransactionAlerts []LargeTransactionAlertData
+}
+
+func (n *recordingNotifier) SendBudgetAlert(_ string, alert BudgetAlertData) {
+	n.budgetAlerts = append(n.budgetAlerts, alert)
+}
+
+func (n *recordingNotifier) SendLargeTransactionAlert(_ string, alert LargeTransactionAlertData) {
+	n.largeTransactionAlerts = append(n.largeTransactionAlerts, alert)
+}
+
+func (n *recordingNotifier) Wait() {}
+
+func TestSendBudgetAlert(t *testing.T) {
+	t.Parallel()
+	t.Run("renders_budget_details", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		user := testutil.CreateTestUser(t, db)
+		db.Model(user).Update("first_name", "Ada")
+		sender := &recordingSender{}
+		svc := NewNotificationService(db, sender)
+
+		svc.SendBudgetAlert(user.ID, BudgetAlertData{
+			BudgetName:   "Groceries",
+			CategoryName: "Food",
+			Period:       models.BudgetPeriodMonthly,
+			Budgeted:     50000,
+			Spent:        52550,
+			Currency:     "USD",
+		})
+		svc.Wait()
+
+		if len(sender.messages) != 1 {
+			t.Fatalf("expected 1 message, got %d", len(sender.messages))
+		}
+		msg := sender.messages[0]
+		if msg.To != user.Email {
+			t.Errorf("expected recipient %s, got %s", user.Email, msg.To)
+		}
+		if msg.Subject != "Budget exceeded: Groceries" {
+			t.Errorf("unexpected subject %q", msg.Subject)
+		}
+		for _, want := range []string{"Hi Ada", "525.50 USD on Food this month", "budget of 500.00 USD"} {
+			if !strings.Contains(msg.Body, want) {
+				t.Errorf("expected body to contain %q, got:\n%s", want, msg.Body)
+			}
+		}
+	})
+
+	t.Run("nil_sender_is_noop", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		user := testutil.CreateTestUser(t, db)
+		svc := NewNotificationService(db, nil)
+
+		svc.SendBudgetAlert(user.ID, BudgetAlertData{BudgetName: "Groceries"})
+		svc.Wait()
+	})
+
+	t.Run("send_failure_is_swallowed", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		user := testutil.CreateTestUser(t, db)
+		sender := &recordingSender{err: errors.New("smtp down")}
+		svc := NewNotificationService(db, sender)
+
+		svc.SendBudgetAlert(user.ID, BudgetAlertData{BudgetName: "Groceries", Currency: "USD"})
+		svc.Wait()
+
+		if len(sender.messages) != 0 {
+			t.Errorf("expected no delivered messages, got %d", len(sender.messages))
+		}
+	})
+}
+
+func TestSendLargeTransactionAlert(t *testing.T) {
+	t.Parallel()
+	db := testutil.WithTx(t)
+	user := testutil.CreateTestUser(t, db)
+	sender := &recordingSender{}
+	svc := NewNotificationService(db, sender)
+
+	svc.SendLargeTransactionAlert(user.ID, LargeTransactionAlertData{
+		AccountName: "Checking",
+		Type:        models.TransactionTypeExpense,
+		Description: "New laptop",
+		Amount:      250000,
+		Threshold:   100000,
+		Currency:    "USD",
+		Date:        time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC),
+	})
+	svc.Wait()
+
+	if len(sender.messages) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(sender.messages))
+	}
+	msg := sender.messages[0]
+	if msg.Subject != "Large expense of 2500.00 USD on Checking" {
+		t.Errorf("unexpected subject %q", msg.Subject)
+	}
+	// No display or first name, so the greeting falls back to the email.
+	for _, want := range []string{"Hi " + user.Email, "on 2026-03-14 (New laptop)", "1000.00 USD or more"} {
+		if !strings.Contains(msg.Body, want) {
+			t.Errorf("expected body to contain %q, got:\n%s", want, msg.Body)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"gorm.io/gorm"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+)
+
+const (
+	// DefaultOracleRunLimit is how many runs are listed when no limit is given.
+	DefaultOracleRunLimit = 20
+	// MaxOracleRunLimit bounds the runs listed at once.
+	MaxOracleRunLimit = 100
+)
+
+// oracleRunService keeps the run history of the price oracle.
+type oracleRunService struct {
+	db *gorm.DB
+}
+
+// NewOracleRunService creates a new OracleRunServicer.
+func NewOracleRunService(db *gorm.DB) OracleRunServicer {
+	return &oracleRunService{db: db}
+}
+
+// RecordOracleRun stores a run the oracle reported. Its status is failed when
+// the run stopped early, partial when some symbols could not be priced and ok
+// otherwise.
+func (s *oracleRunService) RecordOracleRun(report OracleRunReport) (*models.OracleRun, error) {
+	if report.StartedAt.IsZero() {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "started_at is required")
+	}
+	if report.DurationMS < 0 {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "duration_ms must not be negative")
+	}
+
+	run := &models.OracleRun{
+		Version:           report.Version,
+		Status:            models.OracleRunStatusOK,
+		Error:             report.Error,
+		DryRun:            report.DryRun,
+		StartedAt:         report.StartedAt.UTC(),
+		DurationMS:        report.DurationMS,
+		SecuritiesFetched: report.SecuritiesFetched,
+		PricesRecorded:    report.PricesRecorded,
+		SnapshotsRecorded: report.SnapshotsRecorded,
+		ErrorCount:        len(report.Errors),
+		Errors:            make([]models.OracleRunError, len(report.Errors)),
+	}
+	switch {
+	case report.Failed:
+		run.Status = models.OracleRunStatusFailed
+	case len(report.Errors) > 0:
+		run.Status = models.OracleRunStatusPartial
+	}
+	for i, e := range report.Errors {
+		run.Errors[i] = models.OracleRunError{SecurityID: e.SecurityID, Symbol: e.Symbol, Error: e.Error}
+	}
+
+	// Creating the run creates its errors with it
+	if err := s.db.Create(run).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return run, nil
+}
+
+// ListOracleRuns returns up to limit runs with their errors, most recent first.
+func (s *oracleRunService) ListOracleRuns(limit int) ([]models.OracleRun, error) {
+	if limit <= 0 {
+		limit = DefaultOracleRunLimit
+	}
+	if limit > MaxOracleRunLimit {
+		limit = MaxOracleRunLimit
+	}
+
+	runs := []models.OracleRun{}
+	if err := s.db.Preload("Errors", func(db *gorm.DB) *gorm.DB {
+		return db.Order("symbol ASC")
+	}).Order("started_at DESC, created_at DESC").Limit(limit).Find(&runs).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return runs, nil
+}
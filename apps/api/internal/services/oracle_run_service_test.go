@@ -0,0 +1,87 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"kuberan/internal/models"
+	"kuberan/internal/testutil"
+)
+
+func TestRecordOracleRun(t *testing.T) {
+	t.Parallel()
+	started := time.Date(2026, 10, 15, 6, 0, 0, 0, time.UTC)
+
+	t.Run("derives_status", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewOracleRunService(db)
+
+		ok, err := svc.RecordOracleRun(OracleRunReport{Version: "1.4.0", StartedAt: started, DurationMS: 1200, SecuritiesFetched: 3, PricesRecorded: 3})
+		testutil.AssertNoError(t, err)
+		if ok.Status != models.OracleRunStatusOK {
+			t.Errorf("expected ok, got %s", ok.Status)
+		}
+
+		partial, err := svc.RecordOracleRun(OracleRunReport{
+			StartedAt:         started,
+			SecuritiesFetched: 3,
+			PricesRecorded:    2,
+			Errors:            []OracleRunSymbolError{{SecurityID: "sec-1", Symbol: "AAPL", Error: "status 429"}},
+		})
+		testutil.AssertNoError(t, err)
+		if partial.Status != models.OracleRunStatusPartial || partial.ErrorCount != 1 {
+			t.Errorf("expected partial with 1 error, got %s with %d", partial.Status, partial.ErrorCount)
+		}
+		var stored []models.OracleRunError
+		db.Where("run_id = ?", partial.ID).Find(&stored)
+		if len(stored) != 1 || stored[0].Symbol != "AAPL" || stored[0].Error != "status 429" {
+			t.Errorf("expected the AAPL error to be stored, got %+v", stored)
+		}
+
+		failed, err := svc.RecordOracleRun(OracleRunReport{StartedAt: started, Failed: true, Error: "fetching securities: unexpected status 503"})
+		testutil.AssertNoError(t, err)
+		if failed.Status != models.OracleRunStatusFailed || failed.Error == "" {
+			t.Errorf("expected failed with its error, got %s and %q", failed.Status, failed.Error)
+		}
+	})
+
+	t.Run("rejects_missing_start", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewOracleRunService(db)
+
+		_, err := svc.RecordOracleRun(OracleRunReport{Version: "1.4.0"})
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+}
+
+func TestListOracleRuns(t *testing.T) {
+	t.Parallel()
+	db := testutil.WithTx(t)
+	svc := NewOracleRunService(db)
+	start := time.Date(2026, 10, 10, 6, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		_, err := svc.RecordOracleRun(OracleRunReport{
+			StartedAt: start.AddDate(0, 0, i),
+			Errors:    []OracleRunSymbolError{{Symbol: "MSFT", Error: "timeout"}, {Symbol: "AAPL", Error: "timeout"}},
+		})
+		testutil.AssertNoError(t, err)
+	}
+
+	runs, err := svc.ListOracleRuns(2)
+	testutil.AssertNoError(t, err)
+	if len(runs) != 2 {
+		t.Fatalf("expected 2 runs, got %d", len(runs))
+	}
+	if !runs[0].StartedAt.Equal(start.AddDate(0, 0, 2)) || !runs[1].StartedAt.Equal(start.AddDate(0, 0, 1)) {
+		t.Errorf("expected the latest runs first, got %v and %v", runs[0].StartedAt, runs[1].StartedAt)
+	}
+	if len(runs[0].Errors) != 2 || runs[0].Errors[0].Symbol != "AAPL" {
+		t.Errorf("expected the run's errors sorted by symbol, got %+v", runs[0].Errors)
+	}
+
+	all, err := svc.ListOracleRuns(0)
+	testutil.AssertNoError(t, err)
+	if len(all) != 3 {
+		t.Errorf("expected the default limit to list all 3 runs, got %d", len(all))
+	}
+}
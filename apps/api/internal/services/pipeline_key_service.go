@@ -0,0 +1,117 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+)
+
+// pipelineKeyPrefix marks generated keys so they are recognizable when leaked.
+const pipelineKeyPrefix = "kpk_"
+
+// pipelineKeyPrefixLen is how much of a key is kept in clear for identification.
+const pipelineKeyPrefixLen = 12
+
+// pipelineKeyService manages the stored pipeline API keys.
+type pipelineKeyService struct {
+	db *gorm.DB
+}
+
+// NewPipelineKeyService creates a new PipelineKeyServicer.
+func NewPipelineKeyService(db *gorm.DB) PipelineKeyServicer {
+	return &pipelineKeyService{db: db}
+}
+
+// CreatePipelineKey generates and stores a new active key. The plaintext key
+// is returned only here; afterwards only its hash is kept.
+func (s *pipelineKeyService) CreatePipelineKey(name string) (*models.PipelineAPIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", apperrors.WithMessage(apperrors.ErrInvalidInput, "name is required")
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	secret := pipelineKeyPrefix + hex.EncodeToString(b)
+
+	key := &models.PipelineAPIKey{
+		Name:    name,
+		Prefix:  secret[:pipelineKeyPrefixLen],
+		KeyHash: hashPipelineKey(secret),
+	}
+	if err := s.db.Create(key).Error; err != nil {
+		return nil, "", apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return key, secret, nil
+}
+
+// ListPipelineKeys returns every stored key, revoked ones included, oldest first.
+func (s *pipelineKeyService) ListPipelineKeys() ([]models.PipelineAPIKey, error) {
+	var keys []models.PipelineAPIKey
+	if err := s.db.Order("created_at ASC").Find(&keys).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return keys, nil
+}
+
+// RevokePipelineKey stops a key from authenticating. Revoking an already
+// revoked key leaves its original revocation time in place.
+func (s *pipelineKeyService) RevokePipelineKey(keyID string) (*models.PipelineAPIKey, error) {
+	var key models.PipelineAPIKey
+	if err := s.db.Where("id = ?", keyID).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrPipelineKeyNotFound
+		}
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if key.RevokedAt != nil {
+		return &key, nil
+	}
+
+	now := time.Now()
+	if err := s.db.Model(&key).Update("revoked_at", now).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	key.RevokedAt = &now
+	return &key, nil
+}
+
+// AuthenticatePipelineKey returns the active stored key matching key, or nil
+// when there is none. A match records its use in LastUsedAt.
+func (s *pipelineKeyService) AuthenticatePipelineKey(key string) (*models.PipelineAPIKey, error) {
+	if key == "" {
+		return nil, nil
+	}
+
+	var stored models.PipelineAPIKey
+	err := s.db.Where("key_hash = ? AND revoked_at IS NULL", hashPipelineKey(key)).First(&stored).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	now := time.Now()
+	if err := s.db.Model(&stored).UpdateColumn("last_used_at", now).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	stored.LastUsedAt = &now
+	return &stored, nil
+}
+
+// hashPipelineKey returns the SHA-256 hex digest of a pipeline API key.
+func hashPipelineKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
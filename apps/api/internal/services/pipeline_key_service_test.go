@@ -0,0 +1,107 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"kuberan/internal/models"
+	"kuberan/internal/testutil"
+)
+
+func TestCreatePipelineKey(t *testing.T) {
+	t.Parallel()
+	t.Run("stores_only_the_hash", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewPipelineKeyService(db)
+
+		key, secret, err := svc.CreatePipelineKey("oracle")
+		testutil.AssertNoError(t, err)
+
+		if !strings.HasPrefix(secret, pipelineKeyPrefix) || !strings.HasPrefix(secret, key.Prefix) {
+			t.Errorf("expected secret %q to start with prefix %q", secret, key.Prefix)
+		}
+		var stored models.PipelineAPIKey
+		db.First(&stored, "id = ?", key.ID)
+		if stored.KeyHash == secret || stored.KeyHash != hashPipelineKey(secret) {
+			t.Errorf("expected the SHA-256 hash to be stored, got %q", stored.KeyHash)
+		}
+	})
+
+	t.Run("rejects_blank_name", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewPipelineKeyService(db)
+
+		_, _, err := svc.CreatePipelineKey("  ")
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+}
+
+func TestAuthenticatePipelineKey(t *testing.T) {
+	t.Parallel()
+	t.Run("accepts_any_active_key", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewPipelineKeyService(db)
+
+		first, firstSecret, _ := svc.CreatePipelineKey("old")
+		second, secondSecret, _ := svc.CreatePipelineKey("new")
+
+		got, err := svc.AuthenticatePipelineKey(firstSecret)
+		testutil.AssertNoError(t, err)
+		if got == nil || got.ID != first.ID || got.LastUsedAt == nil {
+			t.Errorf("expected %s with last_used_at set, got %+v", first.ID, got)
+		}
+		got, err = svc.AuthenticatePipelineKey(secondSecret)
+		testutil.AssertNoError(t, err)
+		if got == nil || got.ID != second.ID {
+			t.Errorf("expected %s, got %+v", second.ID, got)
+		}
+	})
+
+	t.Run("rejects_revoked_and_unknown_keys", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewPipelineKeyService(db)
+
+		key, secret, _ := svc.CreatePipelineKey("old")
+		revoked, err := svc.RevokePipelineKey(key.ID)
+		testutil.AssertNoError(t, err)
+		if revoked.RevokedAt == nil {
+			t.Fatal("expected revoked_at to be set")
+		}
+
+		got, err := svc.AuthenticatePipelineKey(secret)
+		testutil.AssertNoError(t, err)
+		if got != nil {
+			t.Errorf("expected revoked key to be rejected, got %+v", got)
+		}
+		got, err = svc.AuthenticatePipelineKey("kpk_unknown")
+		testutil.AssertNoError(t, err)
+		if got != nil {
+			t.Errorf("expected unknown key to be rejected, got %+v", got)
+		}
+	})
+}
+
+func TestRevokePipelineKey(t *testing.T) {
+	t.Parallel()
+	t.Run("unknown_key", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewPipelineKeyService(db)
+
+		_, err := svc.RevokePipelineKey("00000000-0000-7000-8000-000000000999")
+		testutil.AssertAppError(t, err, "PIPELINE_KEY_NOT_FOUND")
+	})
+
+	t.Run("keeps_first_revocation_time", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewPipelineKeyService(db)
+
+		key, _, _ := svc.CreatePipelineKey("old")
+		first, err := svc.RevokePipelineKey(key.ID)
+		testutil.AssertNoError(t, err)
+		second, err := svc.RevokePipelineKey(key.ID)
+		testutil.AssertNoError(t, err)
+		if !second.RevokedAt.Equal(*first.RevokedAt) {
+			t.Errorf("expected revoked_at %v to be kept, got %v", first.RevokedAt, second.RevokedAt)
+		}
+	})
+}
@@ -1,15 +1,23 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
 
 	apperrors "kuberan/internal/errors"
+	"kuberan/internal/logger"
 	"kuberan/internal/models"
 	"kuberan/internal/pagination"
 )
 
+// maxBackfillSnapshotsPerUser bounds how many snapshots a single backfill may
+// compute for each user.
+const maxBackfillSnapshotsPerUser = 400
+
 // portfolioSnapshotService handles portfolio snapshot operations.
 type portfolioSnapshotService struct {
 	db *gorm.DB
@@ -22,18 +30,27 @@ func NewPortfolioSnapshotService(db *gorm.DB) PortfolioSnapshotServicer {
 
 // ComputeAndRecordSnapshots computes and stores a net worth snapshot for all active users.
 func (s *portfolioSnapshotService) ComputeAndRecordSnapshots(recordedAt time.Time) (int, error) {
-	// Find all distinct active user IDs
-	var userIDs []string
-	if err := s.db.Model(&models.Account{}).
-		Where("is_active = ?", true).
-		Distinct("user_id").
-		Pluck("user_id", &userIDs).Error; err != nil {
-		return 0, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	return s.recordSnapshots(recordedAt, time.Time{})
+}
+
+// ComputeAndRecordSnapshotsAsOf is like ComputeAndRecordSnapshots, but stamps the
+// snapshots at asOf and values investments at the latest price recorded on or
+// before it, so a missed run can be recorded once prices have caught up.
+func (s *portfolioSnapshotService) ComputeAndRecordSnapshotsAsOf(asOf time.Time) (int, error) {
+	return s.recordSnapshots(asOf, asOf)
+}
+
+// recordSnapshots upserts every active user's snapshot at recordedAt, valuing
+// investments at prices recorded on or before pricesAsOf (the latest if zero).
+func (s *portfolioSnapshotService) recordSnapshots(recordedAt, pricesAsOf time.Time) (int, error) {
+	userIDs, err := s.activeUserIDs()
+	if err != nil {
+		return 0, err
 	}
 
 	count := 0
 	for _, userID := range userIDs {
-		snapshot, err := s.computeSnapshot(userID, recordedAt)
+		snapshot, err := s.computeSnapshot(userID, recordedAt, pricesAsOf)
 		if err != nil {
 			return count, err
 		}
@@ -62,12 +79,358 @@ func (s *portfolioSnapshotService) ComputeAndRecordSnapshots(recordedAt time.Tim
 	return count, nil
 }
 
-// computeSnapshot calculates a user's net worth breakdown.
-func (s *portfolioSnapshotService) computeSnapshot(userID string, recordedAt time.Time) (*models.PortfolioSnapshot, error) {
+// activeUserIDs returns the distinct IDs of users with at least one active account.
+func (s *portfolioSnapshotService) activeUserIDs() ([]string, error) {
+	var userIDs []string
+	if err := s.db.Model(&models.Account{}).
+		Where("is_active = ?", true).
+		Distinct("user_id").
+		Pluck("user_id", &userIDs).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return userIDs, nil
+}
+
+// ComputeSnapshotsForRange backfills snapshots for all active users at every
+// interval step from from to to inclusive. Balances and holdings are rebuilt by
+// reversing the transactions dated after each step, and investments are valued at
+// the latest price recorded on or before it. Steps that already have a snapshot
+// are skipped. Returns the number of snapshots created.
+func (s *portfolioSnapshotService) ComputeSnapshotsForRange(from, to time.Time, interval models.SnapshotInterval) (int, error) {
+	dates, err := backfillDates(from, to, interval)
+	if err != nil {
+		return 0, err
+	}
+
+	userIDs, err := s.activeUserIDs()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i, userID := range userIDs {
+		created, err := s.backfillUser(userID, dates)
+		if err != nil {
+			return count, err
+		}
+		count += created
+		logger.Get().Infow("snapshot backfill progress",
+			"user_id", userID,
+			"users_done", i+1,
+			"users_total", len(userIDs),
+			"snapshots_created", created,
+		)
+	}
+
+	return count, nil
+}
+
+// ComputeUserSnapshotsForRange backfills the user's snapshots like
+// ComputeSnapshotsForRange does for every user. Returns the number created.
+func (s *portfolioSnapshotService) ComputeUserSnapshotsForRange(userID string, from, to time.Time, interval models.SnapshotInterval) (int, error) {
+	dates, err := backfillDates(from, to, interval)
+	if err != nil {
+		return 0, err
+	}
+	return s.backfillUser(userID, dates)
+}
+
+// backfillDates returns the times from from to to inclusive, stepping by interval.
+// The range is rejected if it exceeds maxBackfillSnapshotsPerUser steps.
+func backfillDates(from, to time.Time, interval models.SnapshotInterval) ([]time.Time, error) {
+	if to.Before(from) {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "to must not be before from")
+	}
+
+	var dates []time.Time
+	for i := 0; ; i++ {
+		var at time.Time
+		switch interval {
+		case models.SnapshotIntervalDaily:
+			at = from.AddDate(0, 0, i)
+		case models.SnapshotIntervalWeekly:
+			at = from.AddDate(0, 0, 7*i)
+		case models.SnapshotIntervalMonthly:
+			at = from.AddDate(0, i, 0)
+		default:
+			return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "interval must be daily, weekly, or monthly")
+		}
+		if at.After(to) {
+			return dates, nil
+		}
+		if len(dates) == maxBackfillSnapshotsPerUser {
+			return nil, apperrors.WithMessage(apperrors.ErrInvalidInput,
+				fmt.Sprintf("range exceeds %d snapshots per user", maxBackfillSnapshotsPerUser))
+		}
+		dates = append(dates, at)
+	}
+}
+
+// backfillUser computes and stores the user's missing snapshots at the given dates.
+func (s *portfolioSnapshotService) backfillUser(userID string, dates []time.Time) (int, error) {
+	var existing []time.Time
+	if err := s.db.Model(&models.PortfolioSnapshot{}).
+		Where("user_id = ? AND recorded_at >= ? AND recorded_at <= ?", userID, dates[0], dates[len(dates)-1]).
+		Pluck("recorded_at", &existing).Error; err != nil {
+		return 0, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	recorded := make(map[int64]bool, len(existing))
+	for _, t := range existing {
+		recorded[t.UnixMicro()] = true
+	}
+	var pending []time.Time
+	for _, at := range dates {
+		if !recorded[at.UnixMicro()] {
+			pending = append(pending, at)
+		}
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+	earliest := pending[0]
+
+	// Cash and debt accounts, with every settled transaction that moved them since the earliest date
+	var accounts []models.Account
+	if err := s.db.Where("user_id = ? AND is_active = ? AND exclude_from_reports = ? AND type IN ?", userID, true, false,
+		[]models.AccountType{models.AccountTypeCash, models.AccountTypeDebt, models.AccountTypeCreditCard}).
+		Find(&accounts).Error; err != nil {
+		return 0, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	accountIDs := make([]string, 0, len(accounts))
+	for i := range accounts {
+		accountIDs = append(accountIDs, accounts[i].ID)
+	}
+	var transactions []models.Transaction
+	if len(accountIDs) > 0 {
+		if err := s.db.Where("(account_id IN ? OR to_account_id IN ?) AND date > ? AND is_pending = ?", accountIDs, accountIDs, earliest, false).
+			Find(&transactions).Error; err != nil {
+			return 0, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+	}
+
+	openings, err := accountOpenings(s.db, accounts)
+	if err != nil {
+		return 0, err
+	}
+
+	holdings, err := s.holdingsSince(userID, earliest)
+	if err != nil {
+		return 0, err
+	}
+
+	snapshots := make([]models.PortfolioSnapshot, 0, len(pending))
+	for _, at := range pending {
+		var cashBalance, debtBalance int64
+		for i := range accounts {
+			if openings[accounts[i].ID].After(at) {
+				continue
+			}
+			balance := balanceAt(&accounts[i], transactions, at)
+			if accounts[i].Type == models.AccountTypeCash {
+				cashBalance += balance
+			} else {
+				debtBalance += balance
+			}
+		}
+
+		investmentValue, err := holdings.valueAt(s.db, at)
+		if err != nil {
+			return 0, err
+		}
+
+		snapshots = append(snapshots, models.PortfolioSnapshot{
+			UserID:          userID,
+			RecordedAt:      at,
+			TotalNetWorth:   cashBalance + investmentValue - debtBalance,
+			CashBalance:     cashBalance,
+			InvestmentValue: investmentValue,
+			DebtBalance:     debtBalance,
+		})
+	}
+
+	if err := s.db.CreateInBatches(&snapshots, 100).Error; err != nil {
+		return 0, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return len(snapshots), nil
+}
+
+// RecomputeSnapshots revalues the investments in every snapshot recorded at or
+// after from, for use after a price correction. Holdings are rebuilt as
+// backfills do and valued at the latest price recorded on or before each
+// snapshot; cash and debt balances keep their recorded values. Returns the
+// number of snapshots whose value changed.
+func (s *portfolioSnapshotService) RecomputeSnapshots(from time.Time) (int, error) {
+	var userIDs []string
+	if err := s.db.Model(&models.PortfolioSnapshot{}).
+		Where("recorded_at >= ?", from).
+		Distinct("user_id").
+		Pluck("user_id", &userIDs).Error; err != nil {
+		return 0, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	count := 0
+	for _, userID := range userIDs {
+		var snapshots []models.PortfolioSnapshot
+		if err := s.db.Where("user_id = ? AND recorded_at >= ?", userID, from).
+			Order("recorded_at ASC").
+			Find(&snapshots).Error; err != nil {
+			return count, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		holdings, err := s.holdingsSince(userID, snapshots[0].RecordedAt)
+		if err != nil {
+			return count, err
+		}
+
+		for i := range snapshots {
+			snapshot := &snapshots[i]
+			investmentValue, err := holdings.valueAt(s.db, snapshot.RecordedAt)
+			if err != nil {
+				return count, err
+			}
+			if investmentValue == snapshot.InvestmentValue {
+				continue
+			}
+			if err := s.db.Model(snapshot).Updates(map[string]interface{}{
+				"investment_value": investmentValue,
+				"total_net_worth":  snapshot.CashBalance + investmentValue - snapshot.DebtBalance,
+			}).Error; err != nil {
+				return count, apperrors.Wrap(apperrors.ErrInternalServer, err)
+			}
+			count++
+		}
+		logger.Get().Infow("snapshots recomputed", "user_id", userID, "from", from, "snapshots_checked", len(snapshots))
+	}
+	return count, nil
+}
+
+// snapshotHoldings are a user's investments with the quantity-changing
+// transactions needed to rebuild their quantities back to some date.
+type snapshotHoldings struct {
+	investments  []models.Investment
+	securityIDs  []string
+	transactions []models.InvestmentTransaction // newest first
+}
+
+// holdingsSince loads the investments in the user's active, reported investment
+// accounts with their buys, sells, and splits dated after since.
+func (s *portfolioSnapshotService) holdingsSince(userID string, since time.Time) (*snapshotHoldings, error) {
+	h := &snapshotHoldings{}
+	if err := s.db.Joins("JOIN accounts ON accounts.id = investments.account_id").
+		Where("accounts.user_id = ? AND accounts.type = ? AND accounts.is_active = ? AND accounts.exclude_from_reports = ? AND accounts.deleted_at IS NULL",
+			userID, models.AccountTypeInvestment, true, false).
+		Find(&h.investments).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	investmentIDs := make([]string, 0, len(h.investments))
+	h.securityIDs = make([]string, 0, len(h.investments))
+	for i := range h.investments {
+		investmentIDs = append(investmentIDs, h.investments[i].ID)
+		h.securityIDs = append(h.securityIDs, h.investments[i].SecurityID)
+	}
+	if len(investmentIDs) > 0 {
+		if err := s.db.Where("investment_id IN ? AND date > ? AND type IN ?", investmentIDs, since,
+			[]models.InvestmentTransactionType{models.InvestmentTransactionBuy, models.InvestmentTransactionSell, models.InvestmentTransactionSplit}).
+			Order("date DESC").
+			Find(&h.transactions).Error; err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+	}
+	return h, nil
+}
+
+// valueAt values the holdings as they were at the given time, at the latest
+// prices recorded on or before it.
+func (h *snapshotHoldings) valueAt(db *gorm.DB, at time.Time) (int64, error) {
+	if len(h.investments) == 0 {
+		return 0, nil
+	}
+	prices, err := queryPriceQuotesAsOf(db, h.securityIDs, at)
+	if err != nil {
+		return 0, err
+	}
+	var value int64
+	for i := range h.investments {
+		quantity := quantityAt(&h.investments[i], h.transactions, at)
+		value += int64(quantity * float64(prices[h.investments[i].SecurityID].Price))
+	}
+	return value, nil
+}
+
+// balanceAt reconstructs account's balance at the given time by undoing the
+// effect of every transaction dated after it, mirroring UpdateAccountBalance.
+func balanceAt(account *models.Account, transactions []models.Transaction, at time.Time) int64 {
+	balance := account.Balance
+	for i := range transactions {
+		if transactions[i].Date.After(at) {
+			balance -= balanceDelta(account, &transactions[i])
+		}
+	}
+	return balance
+}
+
+// balanceDelta returns how much t changed account's balance, mirroring
+// UpdateAccountBalance. Transactions on other accounts change nothing.
+func balanceDelta(account *models.Account, t *models.Transaction) int64 {
+	var effect models.TransactionType
+	switch {
+	case t.Type == models.TransactionTypeTransfer && t.ToAccountID != nil && *t.ToAccountID == account.ID:
+		effect = models.TransactionTypeIncome
+	case (t.Type == models.TransactionTypeTransfer || t.FundsInvestment()) && t.AccountID == account.ID:
+		effect = models.TransactionTypeExpense
+	case t.AccountID == account.ID:
+		effect = t.Type
+	default:
+		return 0
+	}
+
+	var delta int64
+	switch effect {
+	case models.TransactionTypeIncome:
+		delta = t.Amount
+	case models.TransactionTypeExpense:
+		delta = -t.Amount
+	}
+	// Credit cards: positive balance = amount owed
+	if account.Type == models.AccountTypeCreditCard {
+		delta = -delta
+	}
+	return delta
+}
+
+// quantityAt reconstructs investment's quantity at the given time by undoing, newest
+// first, the buys, sells, and splits dated after it. transactions must be sorted by
+// date descending.
+func quantityAt(investment *models.Investment, transactions []models.InvestmentTransaction, at time.Time) float64 {
+	quantity := investment.Quantity
+	for i := range transactions {
+		t := &transactions[i]
+		if t.InvestmentID != investment.ID || !t.Date.After(at) {
+			continue
+		}
+		switch t.Type {
+		case models.InvestmentTransactionBuy:
+			quantity -= t.Quantity
+		case models.InvestmentTransactionSell:
+			quantity += t.Quantity
+		case models.InvestmentTransactionSplit:
+			if t.SplitRatio > 0 {
+				quantity = (quantity + t.SplitRemainder) / t.SplitRatio
+			}
+		}
+	}
+	if quantity < 0 {
+		return 0
+	}
+	return quantity
+}
+
+// computeSnapshot calculates a user's net worth breakdown, valuing investments at
+// the latest prices recorded on or before pricesAsOf, or the latest at all if zero.
+func (s *portfolioSnapshotService) computeSnapshot(userID string, recordedAt, pricesAsOf time.Time) (*models.PortfolioSnapshot, error) {
 	// Cash balance: sum of cash account balances
 	var cashBalance int64
 	if err := s.db.Model(&models.Account{}).
-		Where("user_id = ? AND type = ? AND is_active = ?", userID, models.AccountTypeCash, true).
+		Where("user_id = ? AND type = ? AND is_active = ? AND exclude_from_reports = ?", userID, models.AccountTypeCash, true, false).
 		Select("COALESCE(SUM(balance), 0)").
 		Scan(&cashBalance).Error; err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
@@ -77,8 +440,8 @@ func (s *portfolioSnapshotService) computeSnapshot(userID string, recordedAt tim
 	var investmentValue int64
 	var investments []models.Investment
 	if err := s.db.Joins("JOIN accounts ON accounts.id = investments.account_id").
-		Where("accounts.user_id = ? AND accounts.type = ? AND accounts.is_active = ? AND accounts.deleted_at IS NULL",
-			userID, models.AccountTypeInvestment, true).
+		Where("accounts.user_id = ? AND accounts.type = ? AND accounts.is_active = ? AND accounts.exclude_from_reports = ? AND accounts.deleted_at IS NULL",
+			userID, models.AccountTypeInvestment, true, false).
 		Find(&investments).Error; err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
@@ -86,18 +449,19 @@ func (s *portfolioSnapshotService) computeSnapshot(userID string, recordedAt tim
 	for i := range investments {
 		secIDs = append(secIDs, investments[i].SecurityID)
 	}
-	prices, err := getLatestPrices(s.db, secIDs)
+	prices, err := queryPriceQuotesAsOf(s.db, secIDs, pricesAsOf)
 	if err != nil {
 		return nil, err
 	}
 	for i := range investments {
-		investmentValue += int64(investments[i].Quantity * float64(prices[investments[i].SecurityID]))
+		investmentValue += int64(investments[i].Quantity * float64(prices[investments[i].SecurityID].Price))
 	}
 
 	// Debt balance: sum of debt + credit_card account balances
 	var debtBalance int64
 	if err := s.db.Model(&models.Account{}).
-		Where("user_id = ? AND type IN ? AND is_active = ?", userID, []models.AccountType{models.AccountTypeDebt, models.AccountTypeCreditCard}, true).
+		Where("user_id = ? AND type IN ? AND is_active = ? AND exclude_from_reports = ?",
+			userID, []models.AccountType{models.AccountTypeDebt, models.AccountTypeCreditCard}, true, false).
 		Select("COALESCE(SUM(balance), 0)").
 		Scan(&debtBalance).Error; err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
@@ -117,6 +481,7 @@ func (s *portfolioSnapshotService) computeSnapshot(userID string, recordedAt tim
 
 // GetSnapshots returns paginated snapshots for a user within a date range.
 func (s *portfolioSnapshotService) GetSnapshots(
+	ctx context.Context,
 	userID string,
 	from, to time.Time,
 	page pagination.PageRequest,
@@ -124,9 +489,9 @@ func (s *portfolioSnapshotService) GetSnapshots(
 	page.Defaults()
 
 	var totalItems int64
-	base := s.db.Model(&models.PortfolioSnapshot{}).
+	base := s.db.WithContext(ctx).Model(&models.PortfolioSnapshot{}).
 		Where("user_id = ? AND recorded_at >= ? AND recorded_at <= ?", userID, from, to)
-	if err := base.Count(&totalItems).Error; err != nil {
+	if err := pagination.Count(base, page, &totalItems); err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
 
@@ -138,3 +503,75 @@ func (s *portfolioSnapshotService) GetSnapshots(
 	result := pagination.NewPageResponse(snapshots, page.Page, page.PageSize, totalItems)
 	return &result, nil
 }
+
+// GetPortfolioVsBenchmark compares the return on the user's investments between
+// their first and last snapshots in [from, to] with the benchmark security's
+// return over the same period. Both endpoints need a snapshot and a benchmark
+// price, and the portfolio must hold investments at the start.
+func (s *portfolioSnapshotService) GetPortfolioVsBenchmark(userID, benchmarkSecurityID string, from, to time.Time) (*BenchmarkComparison, error) {
+	if !from.Before(to) {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "from must be before to")
+	}
+
+	var security models.Security
+	if err := s.db.Where("id = ?", benchmarkSecurityID).First(&security).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrSecurityNotFound
+		}
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	inRange := func() *gorm.DB {
+		return s.db.Where("user_id = ? AND recorded_at >= ? AND recorded_at <= ?", userID, from, to)
+	}
+	var first, last models.PortfolioSnapshot
+	if err := inRange().Order("recorded_at ASC").Limit(1).Find(&first).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if err := inRange().Order("recorded_at DESC").Limit(1).Find(&last).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if first.ID == "" || first.ID == last.ID {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput,
+			"at least two portfolio snapshots are needed between from and to")
+	}
+	if first.InvestmentValue <= 0 {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput,
+			"the portfolio held no investments at the first snapshot in range")
+	}
+
+	startPrices, err := queryPriceQuotesAsOf(s.db, []string{security.ID}, first.RecordedAt)
+	if err != nil {
+		return nil, err
+	}
+	endPrices, err := queryPriceQuotesAsOf(s.db, []string{security.ID}, last.RecordedAt)
+	if err != nil {
+		return nil, err
+	}
+	start, ok := startPrices[security.ID]
+	if !ok || start.Price <= 0 {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput,
+			fmt.Sprintf("%s has no price on or before %s", security.Symbol, first.RecordedAt.Format(time.RFC3339)))
+	}
+	end := endPrices[security.ID]
+
+	comparison := &BenchmarkComparison{
+		BenchmarkSecurityID: security.ID,
+		BenchmarkSymbol:     security.Symbol,
+		From:                first.RecordedAt,
+		To:                  last.RecordedAt,
+		PortfolioStart:      first.InvestmentValue,
+		PortfolioEnd:        last.InvestmentValue,
+		PortfolioReturn:     percentChange(first.InvestmentValue, last.InvestmentValue),
+		BenchmarkStart:      start.Price,
+		BenchmarkEnd:        end.Price,
+		BenchmarkReturn:     percentChange(start.Price, end.Price),
+	}
+	comparison.Difference = comparison.PortfolioReturn - comparison.BenchmarkReturn
+	return comparison, nil
+}
+
+// percentChange returns the change from start to end as a percentage of start.
+func percentChange(start, end int64) float64 {
+	return float64(end-start) / float64(start) * 100
+}
@@ -1,18 +1,21 @@
 package services
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"gorm.io/gorm"
+
 	"kuberan/internal/models"
 	"kuberan/internal/pagination"
 	"kuberan/internal/testutil"
 )
 
 func TestComputeAndRecordSnapshots(t *testing.T) {
+	t.Parallel()
 	t.Run("creates_snapshots_for_all_users", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewPortfolioSnapshotService(db)
 
 		user1 := testutil.CreateTestUser(t, db)
@@ -37,8 +40,7 @@ func TestComputeAndRecordSnapshots(t *testing.T) {
 	})
 
 	t.Run("cash_balance_computed_correctly", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewPortfolioSnapshotService(db)
 
 		user := testutil.CreateTestUser(t, db)
@@ -62,8 +64,7 @@ func TestComputeAndRecordSnapshots(t *testing.T) {
 	})
 
 	t.Run("investment_value_computed_correctly", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewPortfolioSnapshotService(db)
 
 		user := testutil.CreateTestUser(t, db)
@@ -109,8 +110,7 @@ func TestComputeAndRecordSnapshots(t *testing.T) {
 	})
 
 	t.Run("debt_balance_computed_correctly", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewPortfolioSnapshotService(db)
 
 		user := testutil.CreateTestUser(t, db)
@@ -135,8 +135,7 @@ func TestComputeAndRecordSnapshots(t *testing.T) {
 	})
 
 	t.Run("net_worth_computed_correctly", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewPortfolioSnapshotService(db)
 
 		user := testutil.CreateTestUser(t, db)
@@ -193,8 +192,7 @@ func TestComputeAndRecordSnapshots(t *testing.T) {
 	})
 
 	t.Run("excludes_inactive_accounts", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewPortfolioSnapshotService(db)
 
 		user := testutil.CreateTestUser(t, db)
@@ -217,8 +215,7 @@ func TestComputeAndRecordSnapshots(t *testing.T) {
 	})
 
 	t.Run("idempotent_retry", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewPortfolioSnapshotService(db)
 
 		user := testutil.CreateTestUser(t, db)
@@ -248,10 +245,134 @@ func TestComputeAndRecordSnapshots(t *testing.T) {
 	})
 }
 
+func TestComputeAndRecordSnapshotsAsOf(t *testing.T) {
+	t.Parallel()
+	t.Run("values_investments_at_the_as_of_price", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewPortfolioSnapshotService(db)
+
+		user := testutil.CreateTestUser(t, db)
+		investAcct := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		db.Create(&models.Investment{AccountID: investAcct.ID, SecurityID: sec.ID, Quantity: 10, CostBasis: 100000})
+
+		day := func(d int) time.Time { return time.Date(2026, 2, d, 16, 0, 0, 0, time.UTC) }
+		testutil.CreateTestSecurityPrice(t, db, sec.ID, 10000, day(2))
+		testutil.CreateTestSecurityPrice(t, db, sec.ID, 11000, day(3))
+		testutil.CreateTestSecurityPrice(t, db, sec.ID, 12500, day(5))
+
+		asOf := time.Date(2026, 2, 4, 23, 59, 59, 0, time.UTC)
+		count, err := svc.ComputeAndRecordSnapshotsAsOf(asOf)
+		testutil.AssertNoError(t, err)
+		if count != 1 {
+			t.Fatalf("expected 1 snapshot, got %d", count)
+		}
+
+		var snap models.PortfolioSnapshot
+		db.Where("user_id = ?", user.ID).First(&snap)
+		if !snap.RecordedAt.Equal(asOf) {
+			t.Errorf("expected recorded_at %v, got %v", asOf, snap.RecordedAt)
+		}
+		// 10 shares at the 3 Feb price; the 5 Feb price is after as_of
+		if snap.InvestmentValue != 110000 {
+			t.Errorf("expected investment_value 110000, got %d", snap.InvestmentValue)
+		}
+
+		_, err = svc.ComputeAndRecordSnapshotsAsOf(day(5))
+		testutil.AssertNoError(t, err)
+		var later models.PortfolioSnapshot
+		db.Where("user_id = ? AND recorded_at = ?", user.ID, day(5)).First(&later)
+		if later.InvestmentValue != 125000 {
+			t.Errorf("expected a price recorded exactly at as_of to count, got %d", later.InvestmentValue)
+		}
+	})
+
+	t.Run("unpriced_before_as_of_values_at_zero", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewPortfolioSnapshotService(db)
+
+		user := testutil.CreateTestUser(t, db)
+		investAcct := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		db.Create(&models.Investment{AccountID: investAcct.ID, SecurityID: sec.ID, Quantity: 10, CostBasis: 100000})
+		testutil.CreateTestSecurityPrice(t, db, sec.ID, 10000, time.Date(2026, 2, 10, 16, 0, 0, 0, time.UTC))
+
+		_, err := svc.ComputeAndRecordSnapshotsAsOf(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+		testutil.AssertNoError(t, err)
+
+		var snap models.PortfolioSnapshot
+		db.Where("user_id = ?", user.ID).First(&snap)
+		if snap.InvestmentValue != 0 {
+			t.Errorf("expected investment_value 0, got %d", snap.InvestmentValue)
+		}
+	})
+}
+
+func TestRecomputeSnapshots(t *testing.T) {
+	t.Parallel()
+	t.Run("revalues_snapshots_after_a_price_correction", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewPortfolioSnapshotService(db)
+		securitySvc := NewSecurityService(db, nil, 0)
+
+		user := testutil.CreateTestUser(t, db)
+		testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 50000)
+		investAcct := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		db.Create(&models.Investment{AccountID: investAcct.ID, SecurityID: sec.ID, Quantity: 10, CostBasis: 100000})
+
+		day := func(d int) time.Time { return time.Date(2026, 2, d, 16, 0, 0, 0, time.UTC) }
+		testutil.CreateTestSecurityPrice(t, db, sec.ID, 10000, day(2))
+		bad := testutil.CreateTestSecurityPrice(t, db, sec.ID, 1000000, day(4))
+
+		for _, d := range []int{3, 4, 5} {
+			_, err := svc.ComputeAndRecordSnapshotsAsOf(day(d))
+			testutil.AssertNoError(t, err)
+		}
+
+		_, err := securitySvc.CorrectPrice(sec.ID, bad.ID, PriceCorrection{Price: 11000})
+		testutil.AssertNoError(t, err)
+
+		count, err := svc.RecomputeSnapshots(day(3))
+		testutil.AssertNoError(t, err)
+		// The 3 Feb snapshot predates the corrected price and keeps its value
+		if count != 2 {
+			t.Errorf("expected 2 snapshots recomputed, got %d", count)
+		}
+
+		var snapshots []models.PortfolioSnapshot
+		db.Where("user_id = ?", user.ID).Order("recorded_at ASC").Find(&snapshots)
+		if len(snapshots) != 3 {
+			t.Fatalf("expected 3 snapshots, got %d", len(snapshots))
+		}
+		for i, want := range []int64{100000, 110000, 110000} {
+			if snapshots[i].InvestmentValue != want {
+				t.Errorf("snapshot %d: expected investment_value %d, got %d", i, want, snapshots[i].InvestmentValue)
+			}
+			if snapshots[i].TotalNetWorth != 50000+want {
+				t.Errorf("snapshot %d: expected total_net_worth %d, got %d", i, 50000+want, snapshots[i].TotalNetWorth)
+			}
+		}
+
+		// Deleting the price falls back to the one before it
+		_, err = securitySvc.CorrectPrice(sec.ID, bad.ID, PriceCorrection{Delete: true})
+		testutil.AssertNoError(t, err)
+		count, err = svc.RecomputeSnapshots(day(1))
+		testutil.AssertNoError(t, err)
+		if count != 2 {
+			t.Errorf("expected 2 snapshots recomputed after delete, got %d", count)
+		}
+		db.Where("user_id = ?", user.ID).Order("recorded_at ASC").Find(&snapshots)
+		if snapshots[2].InvestmentValue != 100000 {
+			t.Errorf("expected investment_value 100000 after delete, got %d", snapshots[2].InvestmentValue)
+		}
+	})
+}
+
 func TestGetSnapshots(t *testing.T) {
+	t.Parallel()
 	t.Run("returns_paginated", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewPortfolioSnapshotService(db)
 
 		user := testutil.CreateTestUser(t, db)
@@ -272,7 +393,7 @@ func TestGetSnapshots(t *testing.T) {
 		to := base.Add(10 * time.Hour)
 		page := pagination.PageRequest{Page: 1, PageSize: 2}
 
-		result, err := svc.GetSnapshots(user.ID, from, to, page)
+		result, err := svc.GetSnapshots(context.Background(), user.ID, from, to, page)
 		testutil.AssertNoError(t, err)
 
 		if len(result.Data) != 2 {
@@ -287,8 +408,7 @@ func TestGetSnapshots(t *testing.T) {
 	})
 
 	t.Run("filters_by_date_range", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewPortfolioSnapshotService(db)
 
 		user := testutil.CreateTestUser(t, db)
@@ -307,7 +427,7 @@ func TestGetSnapshots(t *testing.T) {
 		to := base.Add(60 * time.Hour)
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
 
-		result, err := svc.GetSnapshots(user.ID, from, to, page)
+		result, err := svc.GetSnapshots(context.Background(), user.ID, from, to, page)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 2 {
@@ -316,8 +436,7 @@ func TestGetSnapshots(t *testing.T) {
 	})
 
 	t.Run("user_isolation", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewPortfolioSnapshotService(db)
 
 		user1 := testutil.CreateTestUser(t, db)
@@ -335,7 +454,7 @@ func TestGetSnapshots(t *testing.T) {
 		to := recordedAt.Add(time.Hour)
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
 
-		result, err := svc.GetSnapshots(user1.ID, from, to, page)
+		result, err := svc.GetSnapshots(context.Background(), user1.ID, from, to, page)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 1 {
@@ -350,8 +469,7 @@ func TestGetSnapshots(t *testing.T) {
 	})
 
 	t.Run("ordered_by_recorded_at_desc", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewPortfolioSnapshotService(db)
 
 		user := testutil.CreateTestUser(t, db)
@@ -369,7 +487,7 @@ func TestGetSnapshots(t *testing.T) {
 		to := base.Add(3 * time.Hour)
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
 
-		result, err := svc.GetSnapshots(user.ID, from, to, page)
+		result, err := svc.GetSnapshots(context.Background(), user.ID, from, to, page)
 		testutil.AssertNoError(t, err)
 
 		if len(result.Data) != 3 {
@@ -385,3 +503,300 @@ func TestGetSnapshots(t *testing.T) {
 		}
 	})
 }
+
+func TestSnapshotsSkipExcludedAccounts(t *testing.T) {
+	t.Parallel()
+	db := testutil.WithTx(t)
+	svc := NewPortfolioSnapshotService(db)
+
+	user := testutil.CreateTestUser(t, db)
+	personal := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+	testutil.AssertNoError(t, db.Model(personal).Update("opening_date", time.Now().AddDate(0, -1, 0)).Error)
+	joint := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 400000)
+	card := testutil.CreateTestCreditCardAccount(t, db, user.ID, 30000)
+	investAcct := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+	sec := testutil.CreateTestSecurity(t, db)
+	testutil.CreateTestInvestment(t, db, investAcct.ID, sec.ID)
+	testutil.CreateTestSecurityPrice(t, db, sec.ID, 10000, time.Now().Add(-time.Hour))
+	db.Model(&models.Account{}).Where("id IN ?", []string{joint.ID, card.ID, investAcct.ID}).
+		Update("exclude_from_reports", true)
+
+	recordedAt := time.Now().Truncate(time.Second)
+	_, err := svc.ComputeAndRecordSnapshots(recordedAt)
+	testutil.AssertNoError(t, err)
+	_, err = svc.ComputeSnapshotsForRange(recordedAt.AddDate(0, 0, -1), recordedAt.AddDate(0, 0, -1), models.SnapshotIntervalDaily)
+	testutil.AssertNoError(t, err)
+
+	var snaps []models.PortfolioSnapshot
+	db.Where("user_id = ?", user.ID).Find(&snaps)
+	if len(snaps) != 2 {
+		t.Fatalf("expected 2 snapshots, got %d", len(snaps))
+	}
+	for _, snap := range snaps {
+		if snap.CashBalance != 100000 || snap.DebtBalance != 0 || snap.InvestmentValue != 0 {
+			t.Errorf("expected only the personal account, got cash=%d debt=%d investments=%d",
+				snap.CashBalance, snap.DebtBalance, snap.InvestmentValue)
+		}
+		if snap.TotalNetWorth != 100000 {
+			t.Errorf("expected total_net_worth 100000, got %d", snap.TotalNetWorth)
+		}
+	}
+}
+
+func TestComputeSnapshotsForRange(t *testing.T) {
+	t.Parallel()
+	jan1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	jan10 := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	jan15 := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	feb1 := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	feb10 := time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("reconstructs_history_around_a_buy", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		invSvc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		svc := NewPortfolioSnapshotService(db)
+
+		user := testutil.CreateTestUser(t, db)
+		cash := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 150000)
+		testutil.AssertNoError(t, db.Model(cash).Update("opening_date", jan1).Error)
+		investAcct := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		testutil.CreateTestSecurityPrice(t, db, sec.ID, 10000, jan1)
+		testutil.CreateTestSecurityPrice(t, db, sec.ID, 12000, feb1)
+
+		// 5 shares on Jan 1, then 5 more on Jan 15 paid from cash
+		inv, _, err := invSvc.AddInvestment(models.UserID(user.ID), models.AccountID(investAcct.ID), SecurityRef{ID: sec.ID}, 5, 10000, "", &jan1, 0, "", "", InvestmentMetadata{}, false)
+		testutil.AssertNoError(t, err)
+		_, err = invSvc.RecordBuy(models.UserID(user.ID), models.InvestmentID(inv.ID), jan15, 5, 10000, 0, "", models.AccountID(cash.ID), false)
+		testutil.AssertNoError(t, err)
+
+		count, err := svc.ComputeSnapshotsForRange(jan10, feb10, models.SnapshotIntervalMonthly)
+		testutil.AssertNoError(t, err)
+		if count != 2 {
+			t.Fatalf("expected 2 snapshots, got %d", count)
+		}
+
+		var snaps []models.PortfolioSnapshot
+		db.Where("user_id = ?", user.ID).Order("recorded_at ASC").Find(&snaps)
+		if len(snaps) != 2 {
+			t.Fatalf("expected 2 snapshots in DB, got %d", len(snaps))
+		}
+
+		// Jan 10: before the second buy, valued at the Jan 1 price
+		if snaps[0].CashBalance != 150000 {
+			t.Errorf("jan 10: expected cash_balance 150000, got %d", snaps[0].CashBalance)
+		}
+		if snaps[0].InvestmentValue != 50000 {
+			t.Errorf("jan 10: expected investment_value 50000, got %d", snaps[0].InvestmentValue)
+		}
+		if snaps[0].TotalNetWorth != 200000 {
+			t.Errorf("jan 10: expected total_net_worth 200000, got %d", snaps[0].TotalNetWorth)
+		}
+
+		// Feb 10: after the buy, valued at the Feb 1 price
+		if snaps[1].CashBalance != 100000 {
+			t.Errorf("feb 10: expected cash_balance 100000, got %d", snaps[1].CashBalance)
+		}
+		if snaps[1].InvestmentValue != 120000 {
+			t.Errorf("feb 10: expected investment_value 120000, got %d", snaps[1].InvestmentValue)
+		}
+		if snaps[1].TotalNetWorth != 220000 {
+			t.Errorf("feb 10: expected total_net_worth 220000, got %d", snaps[1].TotalNetWorth)
+		}
+	})
+
+	t.Run("reverses_splits_and_credit_card_spending", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewPortfolioSnapshotService(db)
+
+		user := testutil.CreateTestUser(t, db)
+		card := testutil.CreateTestCreditCardAccount(t, db, user.ID, 30000)
+		testutil.AssertNoError(t, db.Model(card).Update("opening_date", jan1).Error)
+		spend := testutil.CreateTestTransaction(t, db, user.ID, card.ID, models.TransactionTypeExpense, 20000)
+		db.Model(spend).Update("date", jan15)
+
+		investAcct := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, investAcct.ID, sec.ID) // 10 shares after a 2:1 split
+		db.Create(&models.InvestmentTransaction{InvestmentID: inv.ID, Type: models.InvestmentTransactionSplit, Date: jan15, SplitRatio: 2})
+		testutil.CreateTestSecurityPrice(t, db, sec.ID, 1000, jan1)
+
+		_, err := svc.ComputeSnapshotsForRange(jan10, jan10, models.SnapshotIntervalDaily)
+		testutil.AssertNoError(t, err)
+
+		var snap models.PortfolioSnapshot
+		db.Where("user_id = ?", user.ID).First(&snap)
+		if snap.DebtBalance != 10000 {
+			t.Errorf("expected debt_balance 10000, got %d", snap.DebtBalance)
+		}
+		if snap.InvestmentValue != 5000 {
+			t.Errorf("expected investment_value 5000 (5 pre-split shares), got %d", snap.InvestmentValue)
+		}
+	})
+
+	t.Run("leaves_accounts_out_before_their_opening_date", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewPortfolioSnapshotService(db)
+
+		user := testutil.CreateTestUser(t, db)
+		checking := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		testutil.AssertNoError(t, db.Model(checking).Update("opening_date", jan1).Error)
+		savings, err := NewAccountService(db, nil).CreateCashAccount(models.UserID(user.ID), "Savings", "", "USD", 50000, true, 0, &jan15)
+		testutil.AssertNoError(t, err)
+		// Recorded as spent before the account was opened, which reconstructing
+		// the balance alone would turn into a negative January 10 balance
+		early := &models.Transaction{UserID: user.ID, AccountID: savings.ID, Type: models.TransactionTypeExpense, Amount: 1000, Date: jan1}
+		testutil.AssertNoError(t, db.Create(early).Error)
+		db.Model(savings).Update("balance", 49000)
+
+		_, err = svc.ComputeSnapshotsForRange(jan10, feb10, models.SnapshotIntervalMonthly)
+		testutil.AssertNoError(t, err)
+
+		var snaps []models.PortfolioSnapshot
+		db.Where("user_id = ?", user.ID).Order("recorded_at ASC").Find(&snaps)
+		if len(snaps) != 2 {
+			t.Fatalf("expected 2 snapshots, got %d", len(snaps))
+		}
+		if snaps[0].CashBalance != 100000 {
+			t.Errorf("jan 10: expected only the open account's 100000, got %d", snaps[0].CashBalance)
+		}
+		if snaps[1].CashBalance != 149000 {
+			t.Errorf("feb 10: expected 149000 with savings, got %d", snaps[1].CashBalance)
+		}
+	})
+
+	t.Run("counts_accounts_from_their_first_transaction", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewPortfolioSnapshotService(db)
+
+		// Created today with history imported from January 1; a newer account
+		// without transactions stays out
+		user := testutil.CreateTestUser(t, db)
+		imported := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 70000)
+		income := &models.Transaction{UserID: user.ID, AccountID: imported.ID, Type: models.TransactionTypeIncome, Amount: 100000, Date: jan1}
+		testutil.AssertNoError(t, db.Create(income).Error)
+
+		_, err := svc.ComputeSnapshotsForRange(jan10, jan10, models.SnapshotIntervalDaily)
+		testutil.AssertNoError(t, err)
+
+		var snap models.PortfolioSnapshot
+		testutil.AssertNoError(t, db.Where("user_id = ?", user.ID).First(&snap).Error)
+		if snap.CashBalance != 100000 {
+			t.Errorf("expected only the imported account's 100000, got %d", snap.CashBalance)
+		}
+	})
+
+	t.Run("skips_existing_snapshots", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewPortfolioSnapshotService(db)
+
+		user := testutil.CreateTestUser(t, db)
+		testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		db.Create(&models.PortfolioSnapshot{UserID: user.ID, RecordedAt: jan10, TotalNetWorth: 1, CashBalance: 1})
+
+		count, err := svc.ComputeSnapshotsForRange(jan10, feb10, models.SnapshotIntervalMonthly)
+		testutil.AssertNoError(t, err)
+		if count != 1 {
+			t.Errorf("expected 1 new snapshot, got %d", count)
+		}
+
+		var existing models.PortfolioSnapshot
+		db.Where("user_id = ? AND recorded_at = ?", user.ID, jan10).First(&existing)
+		if existing.TotalNetWorth != 1 {
+			t.Errorf("expected existing snapshot untouched, got total_net_worth %d", existing.TotalNetWorth)
+		}
+
+		count, err = svc.ComputeSnapshotsForRange(jan10, feb10, models.SnapshotIntervalMonthly)
+		testutil.AssertNoError(t, err)
+		if count != 0 {
+			t.Errorf("expected rerun to create 0 snapshots, got %d", count)
+		}
+	})
+
+	t.Run("rejects_invalid_ranges", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewPortfolioSnapshotService(db)
+
+		_, err := svc.ComputeSnapshotsForRange(feb10, jan10, models.SnapshotIntervalDaily)
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+
+		_, err = svc.ComputeSnapshotsForRange(jan1, jan1.AddDate(2, 0, 0), models.SnapshotIntervalDaily)
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+
+		_, err = svc.ComputeSnapshotsForRange(jan1, feb1, models.SnapshotInterval("hourly"))
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+}
+
+func TestGetPortfolioVsBenchmark(t *testing.T) {
+	t.Parallel()
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	setup := func(t *testing.T) (*gorm.DB, PortfolioSnapshotServicer, *models.User, *models.Security) {
+		t.Helper()
+		db := testutil.WithTx(t)
+		svc := NewPortfolioSnapshotService(db)
+		user := testutil.CreateTestUser(t, db)
+		benchmark := testutil.CreateTestSecurity(t, db)
+		return db, svc, user, benchmark
+	}
+	snapshot := func(db *gorm.DB, userID string, at time.Time, investmentValue int64) {
+		db.Create(&models.PortfolioSnapshot{UserID: userID, RecordedAt: at, InvestmentValue: investmentValue, TotalNetWorth: investmentValue})
+	}
+
+	t.Run("flat_portfolio_trails_rising_benchmark", func(t *testing.T) {
+		db, svc, user, benchmark := setup(t)
+		snapshot(db, user.ID, day(2), 500000)
+		snapshot(db, user.ID, day(16), 500000)
+		snapshot(db, user.ID, day(30), 500000)
+		testutil.CreateTestSecurityPrice(t, db, benchmark.ID, 40000, day(1))
+		testutil.CreateTestSecurityPrice(t, db, benchmark.ID, 42000, day(15))
+		testutil.CreateTestSecurityPrice(t, db, benchmark.ID, 44000, day(29))
+		testutil.CreateTestSecurityPrice(t, db, benchmark.ID, 50000, day(31))
+
+		result, err := svc.GetPortfolioVsBenchmark(user.ID, benchmark.ID, day(1), day(30))
+		testutil.AssertNoError(t, err)
+
+		if !result.From.Equal(day(2)) || !result.To.Equal(day(30)) {
+			t.Errorf("expected the period to run between the snapshots, got %v to %v", result.From, result.To)
+		}
+		if result.PortfolioReturn != 0 {
+			t.Errorf("expected a flat portfolio, got %v%%", result.PortfolioReturn)
+		}
+		// 40000 → 44000; the price after the last snapshot is ignored
+		if result.BenchmarkStart != 40000 || result.BenchmarkEnd != 44000 || result.BenchmarkReturn != 10 {
+			t.Errorf("expected the benchmark up 10%% from 40000 to 44000, got %+v", result)
+		}
+		if result.Difference != -10 {
+			t.Errorf("expected the portfolio to trail by 10 points, got %v", result.Difference)
+		}
+	})
+
+	t.Run("requires_two_snapshots_in_range", func(t *testing.T) {
+		db, svc, user, benchmark := setup(t)
+		snapshot(db, user.ID, day(10), 500000)
+		snapshot(db, user.ID, day(25), 510000)
+		testutil.CreateTestSecurityPrice(t, db, benchmark.ID, 40000, day(1))
+
+		_, err := svc.GetPortfolioVsBenchmark(user.ID, benchmark.ID, day(1), day(20))
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+
+	t.Run("requires_a_benchmark_price_at_the_start", func(t *testing.T) {
+		db, svc, user, benchmark := setup(t)
+		snapshot(db, user.ID, day(2), 500000)
+		snapshot(db, user.ID, day(30), 510000)
+		testutil.CreateTestSecurityPrice(t, db, benchmark.ID, 44000, day(20))
+
+		_, err := svc.GetPortfolioVsBenchmark(user.ID, benchmark.ID, day(1), day(30))
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+
+	t.Run("unknown_benchmark", func(t *testing.T) {
+		_, svc, user, _ := setup(t)
+
+		_, err := svc.GetPortfolioVsBenchmark(user.ID, "00000000-0000-7000-8000-000000000999", day(1), day(30))
+		testutil.AssertAppError(t, err, "SECURITY_NOT_FOUND")
+	})
+}
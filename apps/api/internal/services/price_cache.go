@@ -0,0 +1,78 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// PriceQuote is the most recent security_prices row for a security.
+type PriceQuote struct {
+	Price         int64
+	RecordedAt    time.Time
+	PreviousPrice *int64 // price recorded before this one; nil when there is none
+	Currency      string // currency the price was recorded in; empty for the security's currency
+}
+
+// PriceCache caches the latest price per security ID.
+type PriceCache interface {
+	// Get returns cached quotes for the IDs it holds and the IDs it does not.
+	Get(securityIDs []string) (hits map[string]PriceQuote, misses []string)
+	// Set stores quotes, replacing any cached entries for the same IDs.
+	Set(quotes map[string]PriceQuote)
+	// Invalidate drops the cached entries for the given IDs.
+	Invalidate(securityIDs ...string)
+}
+
+type cachedQuote struct {
+	quote     PriceQuote
+	expiresAt time.Time
+}
+
+// memoryPriceCache is an in-process PriceCache with a fixed TTL per entry.
+type memoryPriceCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]cachedQuote
+	now     func() time.Time
+}
+
+// NewPriceCache creates an in-process PriceCache whose entries expire after ttl.
+func NewPriceCache(ttl time.Duration) PriceCache {
+	return &memoryPriceCache{ttl: ttl, entries: make(map[string]cachedQuote), now: time.Now}
+}
+
+func (c *memoryPriceCache) Get(securityIDs []string) (map[string]PriceQuote, []string) {
+	now := c.now()
+	hits := make(map[string]PriceQuote, len(securityIDs))
+	var misses []string
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, id := range securityIDs {
+		entry, ok := c.entries[id]
+		if ok && now.Before(entry.expiresAt) {
+			hits[id] = entry.quote
+		} else {
+			misses = append(misses, id)
+		}
+	}
+	return hits, misses
+}
+
+func (c *memoryPriceCache) Set(quotes map[string]PriceQuote) {
+	expiresAt := c.now().Add(c.ttl)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, q := range quotes {
+		c.entries[id] = cachedQuote{quote: q, expiresAt: expiresAt}
+	}
+}
+
+func (c *memoryPriceCache) Invalidate(securityIDs ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range securityIDs {
+		delete(c.entries, id)
+	}
+}
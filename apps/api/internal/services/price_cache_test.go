@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"kuberan/internal/models"
+	"kuberan/internal/pagination"
+	"kuberan/internal/testutil"
+)
+
+// countPriceQueries counts the SQL statements that read security_prices on db.
+// Dry-run statements GORM builds for subqueries are not executed and are skipped.
+func countPriceQueries(t *testing.T, db *gorm.DB) *int64 {
+	t.Helper()
+	var count int64
+	hook := func(tx *gorm.DB) {
+		if !tx.DryRun && strings.Contains(tx.Statement.SQL.String(), "security_prices") {
+			atomic.AddInt64(&count, 1)
+		}
+	}
+	if err := db.Callback().Query().After("gorm:query").Register("test:count_price_queries", hook); err != nil {
+		t.Fatalf("failed to register query callback: %v", err)
+	}
+	if err := db.Callback().Row().After("gorm:row").Register("test:count_price_rows", hook); err != nil {
+		t.Fatalf("failed to register row callback: %v", err)
+	}
+	return &count
+}
+
+func TestMemoryPriceCache(t *testing.T) {
+	t.Parallel()
+	t.Run("get_set_invalidate", func(t *testing.T) {
+		cache := NewPriceCache(time.Minute)
+		q := PriceQuote{Price: 100, RecordedAt: time.Now()}
+		cache.Set(map[string]PriceQuote{"a": q})
+
+		hits, misses := cache.Get([]string{"a", "b"})
+		if hits["a"] != q {
+			t.Errorf("expected hit for a, got %+v", hits)
+		}
+		if len(misses) != 1 || misses[0] != "b" {
+			t.Errorf("expected miss for b, got %v", misses)
+		}
+
+		cache.Invalidate("a")
+		if _, misses := cache.Get([]string{"a"}); len(misses) != 1 {
+			t.Error("expected a to be invalidated")
+		}
+	})
+
+	t.Run("expires_after_ttl", func(t *testing.T) {
+		now := time.Now()
+		cache := &memoryPriceCache{ttl: time.Minute, entries: make(map[string]cachedQuote), now: func() time.Time { return now }}
+		cache.Set(map[string]PriceQuote{"a": {Price: 100}})
+
+		now = now.Add(59 * time.Second)
+		if hits, _ := cache.Get([]string{"a"}); len(hits) != 1 {
+			t.Error("expected entry before ttl")
+		}
+		now = now.Add(2 * time.Second)
+		if hits, _ := cache.Get([]string{"a"}); len(hits) != 0 {
+			t.Error("expected entry expired after ttl")
+		}
+	})
+}
+
+func TestQueryLatestPriceQuotes_SingleQuery(t *testing.T) {
+	t.Parallel()
+	db := testutil.SetupTestDB(t)
+	t.Cleanup(func() { testutil.TeardownTestDB(t, db) })
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var ids []string
+	for i, symbol := range []string{"AAA", "BBB", "CCC", "DDD", "EEE"} {
+		sec := testutil.CreateTestSecurityWithParams(t, db, symbol, symbol, models.AssetTypeStock, "NYSE")
+		ids = append(ids, sec.ID)
+		for day := 0; day < 3; day++ {
+			testutil.CreateTestSecurityPrice(t, db, sec.ID, int64((i+1)*1000+day), base.AddDate(0, 0, day))
+		}
+	}
+	queries := countPriceQueries(t, db)
+
+	quotes, err := queryLatestPriceQuotes(db, ids)
+	testutil.AssertNoError(t, err)
+
+	if *queries != 1 {
+		t.Errorf("expected 1 price query for %d securities, got %d", len(ids), *queries)
+	}
+	for i, id := range ids {
+		want := int64((i+1)*1000 + 2)
+		if quotes[id].Price != want {
+			t.Errorf("security %d: expected latest price %d, got %d", i, want, quotes[id].Price)
+		}
+		if !quotes[id].RecordedAt.Equal(base.AddDate(0, 0, 2)) {
+			t.Errorf("security %d: expected latest recorded_at, got %v", i, quotes[id].RecordedAt)
+		}
+	}
+}
+
+func TestPriceCache_EnrichmentPaths(t *testing.T) {
+	t.Parallel()
+	t.Run("portfolio_served_from_cache_until_prices_recorded", func(t *testing.T) {
+		db := testutil.SetupTestDB(t)
+		t.Cleanup(func() { testutil.TeardownTestDB(t, db) })
+		cache := NewPriceCache(time.Minute)
+		acctSvc := NewAccountService(db, cache)
+		invSvc := NewInvestmentService(db, acctSvc, cache, nil, TradePriceCheck{})
+		secSvc := NewSecurityService(db, cache, 0)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		testutil.CreateTestInvestment(t, db, account.ID, sec.ID) // 10 shares
+		testutil.CreateTestSecurityPrice(t, db, sec.ID, 10000, time.Now().Add(-time.Hour))
+		queries := countPriceQueries(t, db)
+
+		portfolio, err := invSvc.GetPortfolio(context.Background(), models.UserID(user.ID))
+		testutil.AssertNoError(t, err)
+		if *queries != 1 || portfolio.TotalValue != 100000 {
+			t.Fatalf("cold: expected 1 query and value 100000, got %d queries and %d", *queries, portfolio.TotalValue)
+		}
+
+		_, err = invSvc.GetPortfolio(context.Background(), models.UserID(user.ID))
+		testutil.AssertNoError(t, err)
+		_, err = invSvc.GetAllInvestments(models.UserID(user.ID), pagination.PageRequest{Page: 1, PageSize: 20})
+		testutil.AssertNoError(t, err)
+		_, err = acctSvc.GetUserAccounts(models.UserID(user.ID), pagination.PageRequest{Page: 1, PageSize: 20}, false)
+		testutil.AssertNoError(t, err)
+		if *queries != 1 {
+			t.Errorf("warm: expected no further price queries, got %d total", *queries)
+		}
+
+		_, err = secSvc.RecordPrices([]SecurityPriceInput{{SecurityID: sec.ID, Price: 12000, RecordedAt: time.Now()}})
+		testutil.AssertNoError(t, err)
+		before := atomic.LoadInt64(queries)
+
+		portfolio, err = invSvc.GetPortfolio(context.Background(), models.UserID(user.ID))
+		testutil.AssertNoError(t, err)
+		if portfolio.TotalValue != 120000 {
+			t.Errorf("expected refreshed value 120000 after RecordPrices, got %d", portfolio.TotalValue)
+		}
+		if *queries != before+1 {
+			t.Errorf("expected 1 price query after invalidation, got %d", *queries-before)
+		}
+	})
+
+	t.Run("nil_cache_always_queries", func(t *testing.T) {
+		db := testutil.SetupTestDB(t)
+		t.Cleanup(func() { testutil.TeardownTestDB(t, db) })
+		acctSvc := NewAccountService(db, nil)
+		invSvc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
+		testutil.CreateTestSecurityPrice(t, db, sec.ID, 10000, time.Now())
+		queries := countPriceQueries(t, db)
+
+		for i := 0; i < 2; i++ {
+			_, err := invSvc.GetPortfolio(context.Background(), models.UserID(user.ID))
+			testutil.AssertNoError(t, err)
+		}
+		if *queries != 2 {
+			t.Errorf("expected 2 price queries without cache, got %d", *queries)
+		}
+	})
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+	"kuberan/internal/pagination"
+)
+
+// compiledRule is a categorization rule prepared for matching.
+type compiledRule struct {
+	rule         models.CategorizationRule
+	categoryType models.CategoryType
+	re           *regexp.Regexp
+}
+
+// matches reports whether the rule applies to a transaction of the given type and description.
+func (r *compiledRule) matches(transactionType models.TransactionType, description string) bool {
+	if string(r.categoryType) != string(transactionType) || description == "" {
+		return false
+	}
+	if r.re != nil {
+		return r.re.MatchString(description)
+	}
+	return strings.Contains(strings.ToLower(description), strings.ToLower(r.rule.Pattern))
+}
+
+// validateRulePattern checks that a pattern is usable for the given match type.
+func validateRulePattern(pattern string, matchType models.RuleMatchType) error {
+	if strings.TrimSpace(pattern) == "" {
+		return apperrors.WithMessage(apperrors.ErrInvalidInput, "pattern is required")
+	}
+	switch matchType {
+	case models.RuleMatchTypeContains:
+		return nil
+	case models.RuleMatchTypeRegex:
+		if _, err := regexp.Compile(pattern); err != nil {
+			return apperrors.WithMessage(apperrors.ErrInvalidInput, "invalid regex pattern: "+err.Error())
+		}
+		return nil
+	default:
+		return apperrors.WithMessage(apperrors.ErrInvalidInput, "match type must be 'contains' or 'regex'")
+	}
+}
+
+// loadRules fetches a user's categorization rules in evaluation order (highest priority first).
+func loadRules(db *gorm.DB, userID string) ([]compiledRule, error) {
+	var rules []models.CategorizationRule
+	if err := db.Preload("Category").Where("user_id = ?", userID).
+		Order("priority DESC, created_at ASC").Find(&rules).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	compiled := make([]compiledRule, 0, len(rules))
+	for i := range rules {
+		cr := compiledRule{rule: rules[i], categoryType: rules[i].Category.Type}
+		if rules[i].MatchType == models.RuleMatchTypeRegex {
+			re, err := regexp.Compile(rules[i].Pattern)
+			if err != nil {
+				// Patterns are validated on write; skip anything that no longer compiles.
+				continue
+			}
+			cr.re = re
+		}
+		compiled = append(compiled, cr)
+	}
+	return compiled, nil
+}
+
+// matchRules returns the category ID of the first matching rule, or nil if none match.
+func matchRules(rules []compiledRule, transactionType models.TransactionType, description string) *string {
+	for i := range rules {
+		if rules[i].matches(transactionType, description) {
+			categoryID := rules[i].rule.CategoryID
+			return &categoryID
+		}
+	}
+	return nil
+}
+
+// categorizeByRules returns the category ID assigned by the user's rules for a new
+// transaction, or nil if no rule matches.
+func categorizeByRules(db *gorm.DB, userID string, transactionType models.TransactionType, description string) (*string, error) {
+	if transactionType != models.TransactionTypeIncome && transactionType != models.TransactionTypeExpense {
+		return nil, nil
+	}
+	rules, err := loadRules(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	return matchRules(rules, transactionType, description), nil
+}
+
+// ruleService handles categorization rule business logic.
+type ruleService struct {
+	db *gorm.DB
+}
+
+// NewRuleService creates a new RuleServicer.
+func NewRuleService(db *gorm.DB) RuleServicer {
+	return &ruleService{db: db}
+}
+
+// verifyCategory ensures the category exists and belongs to the user.
+func (s *ruleService) verifyCategory(userID, categoryID string) error {
+	var category models.Category
+	if err := s.db.Where("id = ? AND user_id = ?", categoryID, userID).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrCategoryNotFound
+		}
+		return apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return nil
+}
+
+// CreateRule creates a new categorization rule.
+func (s *ruleService) CreateRule(
+	userID, categoryID, pattern string,
+	matchType models.RuleMatchType,
+	priority int,
+) (*models.CategorizationRule, error) {
+	if err := validateRulePattern(pattern, matchType); err != nil {
+		return nil, err
+	}
+	if err := s.verifyCategory(userID, categoryID); err != nil {
+		return nil, err
+	}
+
+	rule := &models.CategorizationRule{
+		UserID:     userID,
+		CategoryID: categoryID,
+		Pattern:    pattern,
+		MatchType:  matchType,
+		Priority:   priority,
+	}
+	if err := s.db.Create(rule).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	return s.GetRuleByID(userID, rule.ID)
+}
+
+// GetUserRules returns a paginated list of the user's rules in evaluation order.
+func (s *ruleService) GetUserRules(userID string, page pagination.PageRequest) (*pagination.PageResponse[models.CategorizationRule], error) {
+	page.Defaults()
+
+	base := s.db.Model(&models.CategorizationRule{}).Where("user_id = ?", userID)
+
+	var totalItems int64
+	if err := pagination.Count(base, page, &totalItems); err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	var rules []models.CategorizationRule
+	if err := base.Preload("Category").Order("priority DESC, created_at ASC").
+		Scopes(pagination.Paginate(page)).Find(&rules).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	result := pagination.NewPageResponse(rules, page.Page, page.PageSize, totalItems)
+	return &result, nil
+}
+
+// GetRuleByID returns a rule by ID if it belongs to the user.
+func (s *ruleService) GetRuleByID(userID, ruleID string) (*models.CategorizationRule, error) {
+	var rule models.CategorizationRule
+	if err := s.db.Preload("Category").Where("id = ? AND user_id = ?", ruleID, userID).First(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrRuleNotFound
+		}
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return &rule, nil
+}
+
+// UpdateRule updates an existing rule's fields.
+func (s *ruleService) UpdateRule(userID, ruleID string, updates RuleUpdateFields) (*models.CategorizationRule, error) {
+	rule, err := s.GetRuleByID(userID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the resulting pattern/match type combination
+	pattern := rule.Pattern
+	if updates.Pattern != nil {
+		pattern = *updates.Pattern
+	}
+	matchType := rule.MatchType
+	if updates.MatchType != nil {
+		matchType = *updates.MatchType
+	}
+	if err := validateRulePattern(pattern, matchType); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]interface{})
+	if updates.CategoryID != nil {
+		if err := s.verifyCategory(userID, *updates.CategoryID); err != nil {
+			return nil, err
+		}
+		fields["category_id"] = *updates.CategoryID
+	}
+	if updates.Pattern != nil {
+		fields["pattern"] = pattern
+	}
+	if updates.MatchType != nil {
+		fields["match_type"] = matchType
+	}
+	if updates.Priority != nil {
+		fields["priority"] = *updates.Priority
+	}
+
+	if len(fields) > 0 {
+		if err := s.db.Model(rule).Updates(fields).Error; err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+	}
+
+	return s.GetRuleByID(userID, ruleID)
+}
+
+// DeleteRule soft-deletes a rule.
+func (s *ruleService) DeleteRule(userID, ruleID string) error {
+	rule, err := s.GetRuleByID(userID, ruleID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.Delete(rule).Error; err != nil {
+		return apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return nil
+}
+
+// ApplyRules categorizes a single uncategorized transaction using the user's rules.
+// Transactions that already have a category are returned unchanged.
+func (s *ruleService) ApplyRules(userID, transactionID string) (*models.Transaction, error) {
+	var transaction models.Transaction
+	if err := s.db.Where("id = ? AND user_id = ?", transactionID, userID).First(&transaction).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrTransactionNotFound
+		}
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	if transaction.CategoryID != nil {
+		return &transaction, nil
+	}
+
+	categoryID, err := categorizeByRules(s.db, userID, transaction.Type, transaction.Description)
+	if err != nil {
+		return nil, err
+	}
+	if categoryID == nil {
+		return &transaction, nil
+	}
+
+	if err := s.db.Model(&transaction).Update("category_id", *categoryID).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	transaction.CategoryID = categoryID
+	return &transaction, nil
+}
+
+// ApplyRulesToUncategorized retroactively categorizes all of the user's uncategorized
+// income and expense transactions. Returns the number of transactions updated.
+func (s *ruleService) ApplyRulesToUncategorized(userID string) (int, error) {
+	rules, err := loadRules(s.db, userID)
+	if err != nil {
+		return 0, err
+	}
+	if len(rules) == 0 {
+		return 0, nil
+	}
+
+	var transactions []models.Transaction
+	if err := s.db.Where("user_id = ? AND category_id IS NULL AND type IN ?", userID,
+		[]models.TransactionType{models.TransactionTypeIncome, models.TransactionTypeExpense}).
+		Find(&transactions).Error; err != nil {
+		return 0, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	updated := 0
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		for i := range transactions {
+			categoryID := matchRules(rules, transactions[i].Type, transactions[i].Description)
+			if categoryID == nil {
+				continue
+			}
+			if txErr := tx.Model(&transactions[i]).Update("category_id", *categoryID).Error; txErr != nil {
+				return apperrors.Wrap(apperrors.ErrInternalServer, txErr)
+			}
+			updated++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return updated, nil
+}
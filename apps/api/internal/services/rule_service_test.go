@@ -0,0 +1,343 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"kuberan/internal/models"
+	"kuberan/internal/pagination"
+	"kuberan/internal/testutil"
+	"kuberan/internal/uuid"
+)
+
+func TestCreateRule(t *testing.T) {
+	t.Parallel()
+	t.Run("valid_contains", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewRuleService(db)
+		user := testutil.CreateTestUser(t, db)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+
+		rule, err := svc.CreateRule(user.ID, cat.ID, "grocer", models.RuleMatchTypeContains, 5)
+		testutil.AssertNoError(t, err)
+
+		if rule.ID == "" {
+			t.Fatal("expected non-empty rule ID")
+		}
+		if rule.Pattern != "grocer" || rule.MatchType != models.RuleMatchTypeContains || rule.Priority != 5 {
+			t.Errorf("unexpected rule fields: %+v", rule)
+		}
+		if rule.Category.ID != cat.ID {
+			t.Errorf("expected category to be preloaded, got %q", rule.Category.ID)
+		}
+	})
+
+	t.Run("invalid_regex", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewRuleService(db)
+		user := testutil.CreateTestUser(t, db)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+
+		_, err := svc.CreateRule(user.ID, cat.ID, "([a-z", models.RuleMatchTypeRegex, 0)
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+
+	t.Run("invalid_match_type", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewRuleService(db)
+		user := testutil.CreateTestUser(t, db)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+
+		_, err := svc.CreateRule(user.ID, cat.ID, "x", models.RuleMatchType("glob"), 0)
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+
+	t.Run("wrong_user_category", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewRuleService(db)
+		user1 := testutil.CreateTestUser(t, db)
+		user2 := testutil.CreateTestUser(t, db)
+		cat := testutil.CreateTestCategory(t, db, user2.ID, models.CategoryTypeExpense)
+
+		_, err := svc.CreateRule(user1.ID, cat.ID, "x", models.RuleMatchTypeContains, 0)
+		testutil.AssertAppError(t, err, "CATEGORY_NOT_FOUND")
+	})
+}
+
+func TestGetUserRules(t *testing.T) {
+	t.Parallel()
+	t.Run("ordered_by_priority", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewRuleService(db)
+		user := testutil.CreateTestUser(t, db)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+
+		_, err := svc.CreateRule(user.ID, cat.ID, "low", models.RuleMatchTypeContains, 1)
+		testutil.AssertNoError(t, err)
+		_, err = svc.CreateRule(user.ID, cat.ID, "high", models.RuleMatchTypeContains, 10)
+		testutil.AssertNoError(t, err)
+
+		result, err := svc.GetUserRules(user.ID, pagination.PageRequest{})
+		testutil.AssertNoError(t, err)
+
+		if result.TotalItems != 2 {
+			t.Fatalf("expected 2 rules, got %d", result.TotalItems)
+		}
+		if result.Data[0].Pattern != "high" {
+			t.Errorf("expected highest priority rule first, got %s", result.Data[0].Pattern)
+		}
+	})
+}
+
+func TestUpdateRule(t *testing.T) {
+	t.Parallel()
+	t.Run("valid", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewRuleService(db)
+		user := testutil.CreateTestUser(t, db)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		rule, err := svc.CreateRule(user.ID, cat.ID, "coffee", models.RuleMatchTypeContains, 0)
+		testutil.AssertNoError(t, err)
+
+		pattern := "^STARBUCKS"
+		matchType := models.RuleMatchTypeRegex
+		priority := 3
+		updated, err := svc.UpdateRule(user.ID, rule.ID, RuleUpdateFields{Pattern: &pattern, MatchType: &matchType, Priority: &priority})
+		testutil.AssertNoError(t, err)
+
+		if updated.Pattern != pattern || updated.MatchType != matchType || updated.Priority != priority {
+			t.Errorf("unexpected rule fields after update: %+v", updated)
+		}
+	})
+
+	t.Run("switch_to_regex_revalidates_existing_pattern", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewRuleService(db)
+		user := testutil.CreateTestUser(t, db)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		rule, err := svc.CreateRule(user.ID, cat.ID, "a(b", models.RuleMatchTypeContains, 0)
+		testutil.AssertNoError(t, err)
+
+		matchType := models.RuleMatchTypeRegex
+		_, err = svc.UpdateRule(user.ID, rule.ID, RuleUpdateFields{MatchType: &matchType})
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewRuleService(db)
+		user := testutil.CreateTestUser(t, db)
+
+		_, err := svc.UpdateRule(user.ID, uuid.New(), RuleUpdateFields{})
+		testutil.AssertAppError(t, err, "RULE_NOT_FOUND")
+	})
+}
+
+func TestDeleteRule(t *testing.T) {
+	t.Parallel()
+	t.Run("valid", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewRuleService(db)
+		user := testutil.CreateTestUser(t, db)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		rule, err := svc.CreateRule(user.ID, cat.ID, "rent", models.RuleMatchTypeContains, 0)
+		testutil.AssertNoError(t, err)
+
+		testutil.AssertNoError(t, svc.DeleteRule(user.ID, rule.ID))
+
+		_, err = svc.GetRuleByID(user.ID, rule.ID)
+		testutil.AssertAppError(t, err, "RULE_NOT_FOUND")
+	})
+}
+
+func TestApplyRules(t *testing.T) {
+	t.Parallel()
+	t.Run("contains_is_case_insensitive", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewRuleService(db)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, user.ID)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		_, err := svc.CreateRule(user.ID, cat.ID, "grocer", models.RuleMatchTypeContains, 0)
+		testutil.AssertNoError(t, err)
+
+		tx := testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeExpense, 2500)
+		db.Model(tx).Update("description", "Local GROCERY store")
+
+		result, err := svc.ApplyRules(user.ID, tx.ID)
+		testutil.AssertNoError(t, err)
+
+		if result.CategoryID == nil || *result.CategoryID != cat.ID {
+			t.Errorf("expected category %s, got %v", cat.ID, result.CategoryID)
+		}
+	})
+
+	t.Run("regex_match", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewRuleService(db)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, user.ID)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		_, err := svc.CreateRule(user.ID, cat.ID, `^UBER\s*\*\s*TRIP`, models.RuleMatchTypeRegex, 0)
+		testutil.AssertNoError(t, err)
+
+		matching := testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeExpense, 1500)
+		db.Model(matching).Update("description", "UBER *TRIP 1234")
+		other := testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeExpense, 1500)
+		db.Model(other).Update("description", "UBER EATS")
+
+		result, err := svc.ApplyRules(user.ID, matching.ID)
+		testutil.AssertNoError(t, err)
+		if result.CategoryID == nil || *result.CategoryID != cat.ID {
+			t.Errorf("expected category %s, got %v", cat.ID, result.CategoryID)
+		}
+
+		result, err = svc.ApplyRules(user.ID, other.ID)
+		testutil.AssertNoError(t, err)
+		if result.CategoryID != nil {
+			t.Errorf("expected no category, got %s", *result.CategoryID)
+		}
+	})
+
+	t.Run("highest_priority_wins", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewRuleService(db)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, user.ID)
+		general := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		specific := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		_, err := svc.CreateRule(user.ID, general.ID, "amazon", models.RuleMatchTypeContains, 1)
+		testutil.AssertNoError(t, err)
+		_, err = svc.CreateRule(user.ID, specific.ID, "amazon prime", models.RuleMatchTypeContains, 10)
+		testutil.AssertNoError(t, err)
+
+		tx := testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeExpense, 1499)
+		db.Model(tx).Update("description", "Amazon Prime membership")
+
+		result, err := svc.ApplyRules(user.ID, tx.ID)
+		testutil.AssertNoError(t, err)
+		if result.CategoryID == nil || *result.CategoryID != specific.ID {
+			t.Errorf("expected higher priority category %s, got %v", specific.ID, result.CategoryID)
+		}
+	})
+
+	t.Run("skips_category_of_other_type", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewRuleService(db)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, user.ID)
+		incomeCat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeIncome)
+		_, err := svc.CreateRule(user.ID, incomeCat.ID, "refund", models.RuleMatchTypeContains, 0)
+		testutil.AssertNoError(t, err)
+
+		tx := testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeExpense, 1000)
+		db.Model(tx).Update("description", "refund processing fee")
+
+		result, err := svc.ApplyRules(user.ID, tx.ID)
+		testutil.AssertNoError(t, err)
+		if result.CategoryID != nil {
+			t.Errorf("expected no category, got %s", *result.CategoryID)
+		}
+	})
+
+	t.Run("transaction_not_found", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewRuleService(db)
+		user := testutil.CreateTestUser(t, db)
+
+		_, err := svc.ApplyRules(user.ID, uuid.New())
+		testutil.AssertAppError(t, err, "TRANSACTION_NOT_FOUND")
+	})
+}
+
+func TestApplyRulesToUncategorized(t *testing.T) {
+	t.Parallel()
+	t.Run("categorizes_only_uncategorized_matches", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewRuleService(db)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, user.ID)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		existing := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		_, err := svc.CreateRule(user.ID, cat.ID, "netflix", models.RuleMatchTypeContains, 0)
+		testutil.AssertNoError(t, err)
+
+		match := testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeExpense, 1599)
+		db.Model(match).Update("description", "NETFLIX.COM")
+		noMatch := testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeExpense, 500)
+		db.Model(noMatch).Update("description", "Bakery")
+		categorized := testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeExpense, 1599)
+		db.Model(categorized).Updates(map[string]interface{}{"description": "Netflix", "category_id": existing.ID})
+
+		count, err := svc.ApplyRulesToUncategorized(user.ID)
+		testutil.AssertNoError(t, err)
+		if count != 1 {
+			t.Fatalf("expected 1 categorized, got %d", count)
+		}
+
+		var kept models.Transaction
+		db.Where("id = ?", categorized.ID).First(&kept)
+		if kept.CategoryID == nil || *kept.CategoryID != existing.ID {
+			t.Errorf("expected existing category to be kept, got %v", kept.CategoryID)
+		}
+		var matched models.Transaction
+		db.Where("id = ?", match.ID).First(&matched)
+		if matched.CategoryID == nil || *matched.CategoryID != cat.ID {
+			t.Errorf("expected category %s, got %v", cat.ID, matched.CategoryID)
+		}
+	})
+
+	t.Run("no_rules", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewRuleService(db)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, user.ID)
+		testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeExpense, 100)
+
+		count, err := svc.ApplyRulesToUncategorized(user.ID)
+		testutil.AssertNoError(t, err)
+		if count != 0 {
+			t.Errorf("expected 0 categorized, got %d", count)
+		}
+	})
+}
+
+func TestCreateTransaction_AppliesRules(t *testing.T) {
+	t.Parallel()
+	t.Run("sets_category_when_none_given", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		ruleSvc := NewRuleService(db)
+		txSvc := NewTransactionService(db, NewAccountService(db, nil), nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		_, err := ruleSvc.CreateRule(user.ID, cat.ID, "shell", models.RuleMatchTypeContains, 0)
+		testutil.AssertNoError(t, err)
+
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 4000, "Shell Station #42", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		if tx.CategoryID == nil || *tx.CategoryID != cat.ID {
+			t.Errorf("expected category %s, got %v", cat.ID, tx.CategoryID)
+		}
+	})
+
+	t.Run("explicit_category_wins", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		ruleSvc := NewRuleService(db)
+		txSvc := NewTransactionService(db, NewAccountService(db, nil), nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		ruleCat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		chosen := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		_, err := ruleSvc.CreateRule(user.ID, ruleCat.ID, "shell", models.RuleMatchTypeContains, 0)
+		testutil.AssertNoError(t, err)
+
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &chosen.ID, models.TransactionTypeExpense, 4000, "Shell Station", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		if tx.CategoryID == nil || *tx.CategoryID != chosen.ID {
+			t.Errorf("expected category %s, got %v", chosen.ID, tx.CategoryID)
+		}
+	})
+}
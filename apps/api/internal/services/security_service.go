@@ -2,24 +2,35 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"math"
+	"slices"
 	"strings"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	apperrors "kuberan/internal/errors"
 	"kuberan/internal/models"
 	"kuberan/internal/pagination"
+	"kuberan/internal/validator"
 )
 
 // securityService handles security-related business logic.
 type securityService struct {
-	db *gorm.DB
+	db           *gorm.DB
+	prices       PriceCache
+	maxDeviation float64 // percent; 0 accepts any price
 }
 
-// NewSecurityService creates a new SecurityServicer.
-func NewSecurityService(db *gorm.DB) SecurityServicer {
-	return &securityService{db: db}
+// NewSecurityService creates a new SecurityServicer. prices, when not nil, is
+// invalidated for every security that receives a new price. A new price that
+// differs from the security's previous price by more than maxDeviation
+// percent is rejected unless the entry allows a large move; 0 disables the
+// check.
+func NewSecurityService(db *gorm.DB, prices PriceCache, maxDeviation float64) SecurityServicer {
+	return &securityService{db: db, prices: prices, maxDeviation: maxDeviation}
 }
 
 // CreateSecurity creates a new security record.
@@ -59,6 +70,89 @@ func (s *securityService) CreateSecurity(
 	return security, nil
 }
 
+// UpsertSecurities creates or updates a batch of securities in one database
+// transaction, matching existing securities on symbol and exchange. An entry
+// repeating an earlier one's symbol and exchange updates the security that
+// entry created. Entries that fail validation are reported as failed without
+// affecting the others; a database error rolls back the whole batch.
+func (s *securityService) UpsertSecurities(inputs []SecurityInput) (*SecurityBatchResult, error) {
+	if len(inputs) == 0 {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "Securities array is empty")
+	}
+
+	result := &SecurityBatchResult{Items: make([]SecurityBatchItem, 0, len(inputs))}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for i, in := range inputs {
+			item := SecurityBatchItem{Index: i, Symbol: in.Symbol, Exchange: in.Exchange}
+			if msg := validateSecurityInput(in); msg != "" {
+				item.Status, item.Error = SecurityBatchFailed, msg
+				result.Items = append(result.Items, item)
+				continue
+			}
+
+			var security models.Security
+			err := tx.Where("symbol = ? AND exchange = ?", in.Symbol, in.Exchange).First(&security).Error
+			switch {
+			case err == nil:
+				item.Status = SecurityBatchUpdated
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				item.Status = SecurityBatchCreated
+				security = models.Security{Symbol: in.Symbol, Exchange: in.Exchange, Currency: "USD"}
+			default:
+				return apperrors.Wrap(apperrors.ErrInternalServer, err)
+			}
+
+			security.Name = in.Name
+			security.AssetType = in.AssetType
+			if in.Currency != "" {
+				security.Currency = in.Currency
+			}
+			applySecurityExtraFields(&security, in.ExtraFields)
+			if err := tx.Save(&security).Error; err != nil {
+				return apperrors.Wrap(apperrors.ErrInternalServer, err)
+			}
+			item.SecurityID = security.ID
+			result.Items = append(result.Items, item)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range result.Items {
+		switch item.Status {
+		case SecurityBatchCreated:
+			result.Created++
+		case SecurityBatchUpdated:
+			result.Updated++
+		default:
+			result.Failed++
+		}
+	}
+	return result, nil
+}
+
+// validateSecurityInput returns why in cannot be stored, or "" if it can. The
+// rules match those CreateSecurity's request is bound with.
+func validateSecurityInput(in SecurityInput) string {
+	switch {
+	case strings.TrimSpace(in.Symbol) == "":
+		return "symbol is required"
+	case len(in.Symbol) > 20:
+		return "symbol must be at most 20 characters"
+	case strings.TrimSpace(in.Name) == "":
+		return "name is required"
+	case len(in.Name) > 200:
+		return "name must be at most 200 characters"
+	case !slices.Contains(models.AssetTypes, in.AssetType):
+		return fmt.Sprintf("invalid asset_type %q", in.AssetType)
+	case in.Currency != "" && !validator.IsISO4217(in.Currency):
+		return fmt.Sprintf("invalid currency %q", in.Currency)
+	}
+	return ""
+}
+
 // GetSecurityByID returns a security by its ID.
 func (s *securityService) GetSecurityByID(id string) (*models.Security, error) {
 	var security models.Security
@@ -71,20 +165,75 @@ func (s *securityService) GetSecurityByID(id string) (*models.Security, error) {
 	return &security, nil
 }
 
-// ListSecurities returns a paginated list of securities ordered by symbol.
-// When search is non-empty, results are filtered by case-insensitive match on symbol or name.
-func (s *securityService) ListSecurities(search string, page pagination.PageRequest) (*pagination.PageResponse[models.Security], error) {
+// ListSecurities returns a paginated list of securities ordered by symbol,
+// narrowed by filter.
+func (s *securityService) ListSecurities(filter SecurityFilter, page pagination.PageRequest) (*pagination.PageResponse[models.Security], error) {
 	page.Defaults()
 
 	var totalItems int64
-	base := s.db.Model(&models.Security{})
+	base := applySecurityFilters(s.db.Model(&models.Security{}), filter)
+
+	if err := pagination.Count(base, page, &totalItems); err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
 
-	if search = strings.TrimSpace(search); search != "" {
+	var securities []models.Security
+	if err := base.Order("symbol ASC").Scopes(pagination.Paginate(page)).Find(&securities).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	result := pagination.NewPageResponse(securities, page.Page, page.PageSize, totalItems)
+	return &result, nil
+}
+
+// applySecurityFilters narrows a securities query by f.
+func applySecurityFilters(q *gorm.DB, f SecurityFilter) *gorm.DB {
+	if search := strings.TrimSpace(f.Search); search != "" {
 		pattern := "%" + strings.ToLower(search) + "%"
-		base = base.Where("LOWER(symbol) LIKE ? OR LOWER(name) LIKE ?", pattern, pattern)
+		q = q.Where("LOWER(symbol) LIKE ? OR LOWER(name) LIKE ?", pattern, pattern)
+	}
+	if f.AssetType != nil {
+		q = q.Where("asset_type = ?", *f.AssetType)
+	}
+	if f.Exchange != nil {
+		q = q.Where("UPPER(exchange) = ?", strings.ToUpper(*f.Exchange))
+	}
+	if f.Currency != nil {
+		q = q.Where("currency = ?", *f.Currency)
+	}
+	return q
+}
+
+// heldSecurities returns a query over the user's open positions (quantity > 0) in
+// active investment accounts they can access, one row per security_id.
+func (s *securityService) heldSecurities(userID string) *gorm.DB {
+	return s.db.Model(&models.Investment{}).
+		Joins("JOIN accounts ON accounts.id = investments.account_id AND accounts.deleted_at IS NULL").
+		Where("accounts.id IN (?) AND accounts.type = ? AND accounts.is_active = ?",
+			accessibleAccountIDs(s.db, userID), models.AccountTypeInvestment, true).
+		Where("investments.quantity > 0").
+		Group("investments.security_id")
+}
+
+// ListSecuritiesWithHoldings returns a paginated list of securities ordered by
+// symbol and narrowed by filter, each with the user's total holding across
+// accounts. When ownedOnly is set, only securities the user currently holds
+// are listed.
+func (s *securityService) ListSecuritiesWithHoldings(
+	userID string,
+	filter SecurityFilter,
+	page pagination.PageRequest,
+	ownedOnly bool,
+) (*pagination.PageResponse[SecurityWithHolding], error) {
+	page.Defaults()
+
+	var totalItems int64
+	base := applySecurityFilters(s.db.Model(&models.Security{}), filter)
+	if ownedOnly {
+		base = base.Where("id IN (?)", s.heldSecurities(userID).Select("investments.security_id"))
 	}
 
-	if err := base.Count(&totalItems).Error; err != nil {
+	if err := pagination.Count(base, page, &totalItems); err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
 
@@ -93,34 +242,159 @@ func (s *securityService) ListSecurities(search string, page pagination.PageRequ
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
 
-	result := pagination.NewPageResponse(securities, page.Page, page.PageSize, totalItems)
+	secIDs := make([]string, len(securities))
+	for i := range securities {
+		secIDs[i] = securities[i].ID
+	}
+
+	var rows []struct {
+		SecurityID string
+		Quantity   float64
+		CostBasis  int64
+	}
+	if len(secIDs) > 0 {
+		if err := s.heldSecurities(userID).
+			Select("investments.security_id, SUM(investments.quantity) AS quantity, SUM(investments.cost_basis) AS cost_basis").
+			Where("investments.security_id IN ?", secIDs).
+			Scan(&rows).Error; err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+	}
+
+	heldIDs := make([]string, len(rows))
+	for i := range rows {
+		heldIDs[i] = rows[i].SecurityID
+	}
+	prices, err := getLatestPrices(s.db, s.prices, heldIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	holdings := make(map[string]*SecurityHolding, len(rows))
+	for _, row := range rows {
+		value := int64(row.Quantity * float64(prices[row.SecurityID]))
+		holdings[row.SecurityID] = &SecurityHolding{
+			Quantity:     row.Quantity,
+			CostBasis:    row.CostBasis,
+			CurrentValue: value,
+			GainLoss:     value - row.CostBasis,
+		}
+	}
+
+	items := make([]SecurityWithHolding, len(securities))
+	for i := range securities {
+		items[i] = SecurityWithHolding{Security: securities[i], Holding: holdings[securities[i].ID]}
+	}
+
+	result := pagination.NewPageResponse(items, page.Page, page.PageSize, totalItems)
 	return &result, nil
 }
 
-// RecordPrices bulk-inserts price entries, skipping duplicates.
-func (s *securityService) RecordPrices(prices []SecurityPriceInput) (int, error) {
+// RecordPrices bulk-upserts price entries. An entry for a security and
+// timestamp that already has a price replaces that price, its source and its
+// currency, and
+// restores it if a correction deleted it; re-recording an identical entry is a
+// no-op. Only inserted or changed entries are counted, and cached latest prices
+// are invalidated for their securities.
+// Entries that fail the deviation check are listed as rejected, not recorded.
+func (s *securityService) RecordPrices(prices []SecurityPriceInput) (*RecordPricesResult, error) {
 	if len(prices) == 0 {
-		return 0, apperrors.WithMessage(apperrors.ErrInvalidInput, "Prices array is empty")
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "Prices array is empty")
+	}
+
+	var updated []string
+	defer func() {
+		if s.prices != nil && len(updated) > 0 {
+			s.prices.Invalidate(updated...)
+		}
+	}()
+
+	upsert := clause.OnConflict{
+		Columns:   []clause.Column{{Name: "security_id"}, {Name: "recorded_at"}},
+		DoUpdates: clause.AssignmentColumns([]string{"price", "source", "currency", "deleted_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "security_prices.price <> excluded.price OR security_prices.source <> excluded.source OR security_prices.currency <> excluded.currency OR security_prices.deleted_at IS NOT NULL"},
+		}},
 	}
 
-	count := 0
+	result := &RecordPricesResult{Rejected: []RejectedPrice{}}
 	for _, p := range prices {
+		if !p.AllowLargeMove && s.maxDeviation > 0 {
+			rejected, err := s.checkPriceDeviation(p)
+			if err != nil {
+				return result, err
+			}
+			if rejected != nil {
+				result.Rejected = append(result.Rejected, *rejected)
+				continue
+			}
+		}
+
+		source := p.Source
+		if source == "" {
+			source = models.PriceSourceManual
+		}
 		sp := models.SecurityPrice{
 			SecurityID: p.SecurityID,
 			Price:      p.Price,
 			RecordedAt: p.RecordedAt,
+			Source:     source,
+			Currency:   p.Currency,
 		}
-		result := s.db.Where("security_id = ? AND recorded_at = ?", sp.SecurityID, sp.RecordedAt).
-			FirstOrCreate(&sp)
-		if result.Error != nil {
-			return count, apperrors.Wrap(apperrors.ErrInternalServer, result.Error)
+		var changed bool
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			result := tx.Clauses(upsert).Create(&sp)
+			if result.Error != nil {
+				return apperrors.Wrap(apperrors.ErrInternalServer, result.Error)
+			}
+			if changed = result.RowsAffected > 0; !changed {
+				return nil
+			}
+			return recordEvent(tx, PriceRecordedEvent{
+				SecurityID: sp.SecurityID,
+				Price:      sp.Price,
+				RecordedAt: sp.RecordedAt,
+				Source:     sp.Source,
+			})
+		})
+		if err != nil {
+			return result, err
 		}
-		if result.RowsAffected > 0 {
-			count++
+		if changed {
+			result.Recorded++
+			updated = append(updated, sp.SecurityID)
 		}
 	}
 
-	return count, nil
+	return result, nil
+}
+
+// checkPriceDeviation compares p against the security's latest price recorded
+// before it and returns a rejection if p moves further than maxDeviation
+// percent from it. The first price of a security is always accepted.
+func (s *securityService) checkPriceDeviation(p SecurityPriceInput) (*RejectedPrice, error) {
+	var last models.SecurityPrice
+	err := s.db.Where("security_id = ? AND recorded_at < ?", p.SecurityID, p.RecordedAt).
+		Order("recorded_at DESC").First(&last).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && last.Price <= 0) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	deviation := math.Abs(float64(p.Price-last.Price)) / float64(last.Price) * 100
+	if deviation <= s.maxDeviation {
+		return nil, nil
+	}
+	return &RejectedPrice{
+		SecurityID:   p.SecurityID,
+		Price:        p.Price,
+		RecordedAt:   p.RecordedAt,
+		LastPrice:    last.Price,
+		DeviationPct: math.Round(deviation*100) / 100,
+		Reason:       fmt.Sprintf("price moved %.2f%% from the last recorded price, more than the allowed %.2f%%", deviation, s.maxDeviation),
+	}, nil
 }
 
 // GetPriceHistory returns paginated price history for a security within a date range.
@@ -134,7 +408,7 @@ func (s *securityService) GetPriceHistory(
 	var totalItems int64
 	base := s.db.Model(&models.SecurityPrice{}).
 		Where("security_id = ? AND recorded_at >= ? AND recorded_at <= ?", securityID, from, to)
-	if err := base.Count(&totalItems).Error; err != nil {
+	if err := pagination.Count(base, page, &totalItems); err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
 
@@ -147,6 +421,46 @@ func (s *securityService) GetPriceHistory(
 	return &result, nil
 }
 
+// CorrectPrice replaces the price of one recorded entry of a security, which
+// then counts as entered manually, or soft-deletes the entry so that price
+// history and valuations skip it. The deviation check does not apply. Cached
+// latest prices are invalidated, but snapshots valued with the old price keep
+// it until they are recomputed.
+func (s *securityService) CorrectPrice(securityID, priceID string, correction PriceCorrection) (*PriceCorrectionResult, error) {
+	if !correction.Delete && correction.Price <= 0 {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "price must be greater than 0")
+	}
+
+	var price models.SecurityPrice
+	if err := s.db.Where("id = ? AND security_id = ?", priceID, securityID).First(&price).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrPriceNotFound
+		}
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	result := &PriceCorrectionResult{Previous: price}
+
+	if correction.Delete {
+		if err := s.db.Delete(&price).Error; err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+	} else {
+		if err := s.db.Model(&price).Updates(map[string]interface{}{
+			"price":  correction.Price,
+			"source": models.PriceSourceManual,
+		}).Error; err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		price.Price, price.Source = correction.Price, models.PriceSourceManual
+		result.Current = &price
+	}
+
+	if s.prices != nil {
+		s.prices.Invalidate(securityID)
+	}
+	return result, nil
+}
+
 // ListAllSecurities returns all active securities ordered by symbol.
 // Intended for machine clients (e.g., the price oracle) that need the full list.
 func (s *securityService) ListAllSecurities() ([]models.Security, error) {
@@ -180,6 +494,9 @@ func applySecurityExtraFields(sec *models.Security, fields map[string]interface{
 	if v, ok := fields["provider_symbol"].(string); ok {
 		sec.ProviderSymbol = v
 	}
+	if v, ok := fields["preferred_provider"].(string); ok {
+		sec.PreferredProvider = v
+	}
 }
 
 // isUniqueConstraintError checks if a GORM error is a unique constraint violation.
@@ -7,19 +7,20 @@ import (
 	"kuberan/internal/models"
 	"kuberan/internal/pagination"
 	"kuberan/internal/testutil"
+	"kuberan/internal/uuid"
 )
 
 func TestCreateSecurity(t *testing.T) {
+	t.Parallel()
 	t.Run("valid", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		sec, err := svc.CreateSecurity("AAPL", "Apple Inc", models.AssetTypeStock, "USD", "NASDAQ", nil)
 		testutil.AssertNoError(t, err)
 
-		if sec.ID == 0 {
-			t.Fatal("expected non-zero security ID")
+		if sec.ID == "" {
+			t.Fatal("expected non-empty security ID")
 		}
 		if sec.Symbol != "AAPL" {
 			t.Errorf("expected symbol AAPL, got %s", sec.Symbol)
@@ -39,9 +40,8 @@ func TestCreateSecurity(t *testing.T) {
 	})
 
 	t.Run("with_extra_fields", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		maturity := time.Date(2030, 6, 15, 0, 0, 0, 0, time.UTC)
 		extra := map[string]interface{}{
@@ -68,9 +68,8 @@ func TestCreateSecurity(t *testing.T) {
 	})
 
 	t.Run("with_provider_symbol", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		extra := map[string]interface{}{
 			"provider_symbol": "1023.KL",
@@ -91,10 +90,27 @@ func TestCreateSecurity(t *testing.T) {
 		}
 	})
 
+	t.Run("with_preferred_provider", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
+
+		extra := map[string]interface{}{
+			"preferred_provider": "CoinGecko",
+		}
+
+		sec, err := svc.CreateSecurity("PAXG", "PAX Gold", models.AssetTypeCrypto, "USD", "", extra)
+		testutil.AssertNoError(t, err)
+
+		fetched, err := svc.GetSecurityByID(sec.ID)
+		testutil.AssertNoError(t, err)
+		if fetched.PreferredProvider != "CoinGecko" {
+			t.Errorf("expected persisted preferred_provider CoinGecko, got %s", fetched.PreferredProvider)
+		}
+	})
+
 	t.Run("duplicate_symbol_exchange", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		_, err := svc.CreateSecurity("AAPL", "Apple Inc", models.AssetTypeStock, "USD", "NASDAQ", nil)
 		testutil.AssertNoError(t, err)
@@ -104,9 +120,8 @@ func TestCreateSecurity(t *testing.T) {
 	})
 
 	t.Run("same_symbol_different_exchange", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		_, err := svc.CreateSecurity("AAPL", "Apple NYSE", models.AssetTypeStock, "USD", "NYSE", nil)
 		testutil.AssertNoError(t, err)
@@ -114,33 +129,30 @@ func TestCreateSecurity(t *testing.T) {
 		sec2, err := svc.CreateSecurity("AAPL", "Apple NASDAQ", models.AssetTypeStock, "USD", "NASDAQ", nil)
 		testutil.AssertNoError(t, err)
 
-		if sec2.ID == 0 {
+		if sec2.ID == "" {
 			t.Fatal("expected second security to be created successfully")
 		}
 	})
 
 	t.Run("empty_symbol", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		_, err := svc.CreateSecurity("", "Some Name", models.AssetTypeStock, "USD", "NYSE", nil)
 		testutil.AssertAppError(t, err, "INVALID_INPUT")
 	})
 
 	t.Run("empty_name", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		_, err := svc.CreateSecurity("SYM", "", models.AssetTypeStock, "USD", "NYSE", nil)
 		testutil.AssertAppError(t, err, "INVALID_INPUT")
 	})
 
 	t.Run("defaults_currency_to_usd", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		sec, err := svc.CreateSecurity("VTI", "Vanguard Total", models.AssetTypeETF, "", "NYSE", nil)
 		testutil.AssertNoError(t, err)
@@ -151,11 +163,96 @@ func TestCreateSecurity(t *testing.T) {
 	})
 }
 
+func TestUpsertSecurities(t *testing.T) {
+	t.Parallel()
+	t.Run("mixed_batch", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
+		existing, err := svc.CreateSecurity("AAPL", "Apple", models.AssetTypeStock, "USD", "NASDAQ", nil)
+		testutil.AssertNoError(t, err)
+
+		result, err := svc.UpsertSecurities([]SecurityInput{
+			{Symbol: "AAPL", Name: "Apple Inc", AssetType: models.AssetTypeStock, Exchange: "NASDAQ"},
+			{Symbol: "MSFT", Name: "Microsoft", AssetType: models.AssetTypeStock, Currency: "USD", Exchange: "NASDAQ"},
+			{Symbol: "GOLD", Name: "Gold", AssetType: "commodity"},
+			{Symbol: "MSFT", Name: "Microsoft Corp", AssetType: models.AssetTypeStock, Exchange: "NASDAQ"},
+			{Symbol: "AAPL", Name: "Apple CDR", AssetType: models.AssetTypeStock, Currency: "CAD", Exchange: "NEO"},
+		})
+		testutil.AssertNoError(t, err)
+
+		want := []SecurityBatchStatus{SecurityBatchUpdated, SecurityBatchCreated, SecurityBatchFailed, SecurityBatchUpdated, SecurityBatchCreated}
+		if len(result.Items) != len(want) {
+			t.Fatalf("expected %d items, got %+v", len(want), result.Items)
+		}
+		for i, status := range want {
+			if result.Items[i].Index != i || result.Items[i].Status != status {
+				t.Errorf("item %d: expected %s, got %+v", i, status, result.Items[i])
+			}
+		}
+		if result.Created != 2 || result.Updated != 2 || result.Failed != 1 {
+			t.Errorf("expected 2 created, 2 updated and 1 failed, got %+v", result)
+		}
+		if result.Items[0].SecurityID != existing.ID {
+			t.Errorf("expected AAPL on NASDAQ to update %s, got %s", existing.ID, result.Items[0].SecurityID)
+		}
+		if result.Items[3].SecurityID != result.Items[1].SecurityID {
+			t.Error("expected the repeated MSFT to update the security created earlier in the batch")
+		}
+		if result.Items[2].Error == "" || result.Items[2].SecurityID != "" {
+			t.Errorf("expected the invalid asset type to fail with a reason, got %+v", result.Items[2])
+		}
+
+		var aapl, msft models.Security
+		testutil.AssertNoError(t, db.Where("id = ?", existing.ID).First(&aapl).Error)
+		if aapl.Name != "Apple Inc" || aapl.Currency != "USD" {
+			t.Errorf("expected the name updated and the currency kept, got %+v", aapl)
+		}
+		testutil.AssertNoError(t, db.Where("id = ?", result.Items[1].SecurityID).First(&msft).Error)
+		if msft.Name != "Microsoft Corp" {
+			t.Errorf("expected the later MSFT entry to win, got %s", msft.Name)
+		}
+		var count int64
+		db.Model(&models.Security{}).Count(&count)
+		if count != 3 {
+			t.Errorf("expected 3 securities, got %d", count)
+		}
+	})
+
+	t.Run("validates_each_entry", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
+
+		result, err := svc.UpsertSecurities([]SecurityInput{
+			{Symbol: " ", Name: "Blank", AssetType: models.AssetTypeStock},
+			{Symbol: "TOOLONGSYMBOLFORTHEFIELD", Name: "Long", AssetType: models.AssetTypeStock},
+			{Symbol: "NONAME", AssetType: models.AssetTypeStock},
+			{Symbol: "XYZ", Name: "Bad currency", AssetType: models.AssetTypeStock, Currency: "ZZZ"},
+			{Symbol: "BTC", Name: "Bitcoin", AssetType: models.AssetTypeCrypto, ExtraFields: map[string]interface{}{"network": "bitcoin"}},
+		})
+		testutil.AssertNoError(t, err)
+
+		if result.Failed != 4 || result.Created != 1 {
+			t.Fatalf("expected 4 failed and 1 created, got %+v", result)
+		}
+		var btc models.Security
+		testutil.AssertNoError(t, db.Where("id = ?", result.Items[4].SecurityID).First(&btc).Error)
+		if btc.Network != "bitcoin" || btc.Currency != "USD" {
+			t.Errorf("expected network bitcoin and default currency USD, got %+v", btc)
+		}
+	})
+
+	t.Run("rejects_an_empty_batch", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		_, err := NewSecurityService(db, nil, 0).UpsertSecurities(nil)
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+}
+
 func TestGetSecurityByID(t *testing.T) {
+	t.Parallel()
 	t.Run("found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		created := testutil.CreateTestSecurityWithParams(t, db, "AAPL", "Apple Inc", models.AssetTypeStock, "NASDAQ")
 
@@ -163,7 +260,7 @@ func TestGetSecurityByID(t *testing.T) {
 		testutil.AssertNoError(t, err)
 
 		if sec.ID != created.ID {
-			t.Errorf("expected ID %d, got %d", created.ID, sec.ID)
+			t.Errorf("expected ID %s, got %s", created.ID, sec.ID)
 		}
 		if sec.Symbol != "AAPL" {
 			t.Errorf("expected symbol AAPL, got %s", sec.Symbol)
@@ -171,27 +268,26 @@ func TestGetSecurityByID(t *testing.T) {
 	})
 
 	t.Run("not_found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
-		_, err := svc.GetSecurityByID(9999)
+		_, err := svc.GetSecurityByID(uuid.New())
 		testutil.AssertAppError(t, err, "SECURITY_NOT_FOUND")
 	})
 }
 
 func TestListSecurities(t *testing.T) {
+	t.Parallel()
 	t.Run("returns_paginated", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		for i := 0; i < 5; i++ {
 			testutil.CreateTestSecurity(t, db)
 		}
 
 		page := pagination.PageRequest{Page: 1, PageSize: 2}
-		result, err := svc.ListSecurities("", page)
+		result, err := svc.ListSecurities(SecurityFilter{}, page)
 		testutil.AssertNoError(t, err)
 
 		if len(result.Data) != 2 {
@@ -206,16 +302,15 @@ func TestListSecurities(t *testing.T) {
 	})
 
 	t.Run("ordered_by_symbol", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		testutil.CreateTestSecurityWithParams(t, db, "ZZZ", "Zzz Corp", models.AssetTypeStock, "NYSE")
 		testutil.CreateTestSecurityWithParams(t, db, "AAA", "Aaa Corp", models.AssetTypeStock, "NYSE")
 		testutil.CreateTestSecurityWithParams(t, db, "MMM", "Mmm Corp", models.AssetTypeStock, "NYSE")
 
 		page := pagination.PageRequest{Page: 1, PageSize: 10}
-		result, err := svc.ListSecurities("", page)
+		result, err := svc.ListSecurities(SecurityFilter{}, page)
 		testutil.AssertNoError(t, err)
 
 		if len(result.Data) != 3 {
@@ -233,16 +328,15 @@ func TestListSecurities(t *testing.T) {
 	})
 
 	t.Run("search_by_symbol", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		testutil.CreateTestSecurityWithParams(t, db, "AAPL", "Apple Inc", models.AssetTypeStock, "NASDAQ")
 		testutil.CreateTestSecurityWithParams(t, db, "GOOGL", "Alphabet Inc", models.AssetTypeStock, "NASDAQ")
 		testutil.CreateTestSecurityWithParams(t, db, "MSFT", "Microsoft Corp", models.AssetTypeStock, "NASDAQ")
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := svc.ListSecurities("aapl", page)
+		result, err := svc.ListSecurities(SecurityFilter{Search: "aapl"}, page)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 1 {
@@ -254,15 +348,14 @@ func TestListSecurities(t *testing.T) {
 	})
 
 	t.Run("search_by_name", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		testutil.CreateTestSecurityWithParams(t, db, "AAPL", "Apple Inc", models.AssetTypeStock, "NASDAQ")
 		testutil.CreateTestSecurityWithParams(t, db, "GOOGL", "Alphabet Inc", models.AssetTypeStock, "NASDAQ")
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := svc.ListSecurities("apple", page)
+		result, err := svc.ListSecurities(SecurityFilter{Search: "apple"}, page)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 1 {
@@ -274,19 +367,18 @@ func TestListSecurities(t *testing.T) {
 	})
 
 	t.Run("search_case_insensitive", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		testutil.CreateTestSecurityWithParams(t, db, "AAPL", "Apple Inc", models.AssetTypeStock, "NASDAQ")
 		testutil.CreateTestSecurityWithParams(t, db, "GOOGL", "Alphabet Inc", models.AssetTypeStock, "NASDAQ")
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
 
-		upper, err := svc.ListSecurities("AAPL", page)
+		upper, err := svc.ListSecurities(SecurityFilter{Search: "AAPL"}, page)
 		testutil.AssertNoError(t, err)
 
-		lower, err := svc.ListSecurities("aapl", page)
+		lower, err := svc.ListSecurities(SecurityFilter{Search: "aapl"}, page)
 		testutil.AssertNoError(t, err)
 
 		if upper.TotalItems != lower.TotalItems {
@@ -298,29 +390,69 @@ func TestListSecurities(t *testing.T) {
 	})
 
 	t.Run("search_empty_returns_all", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		testutil.CreateTestSecurityWithParams(t, db, "AAPL", "Apple Inc", models.AssetTypeStock, "NASDAQ")
 		testutil.CreateTestSecurityWithParams(t, db, "GOOGL", "Alphabet Inc", models.AssetTypeStock, "NASDAQ")
 		testutil.CreateTestSecurityWithParams(t, db, "MSFT", "Microsoft Corp", models.AssetTypeStock, "NASDAQ")
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := svc.ListSecurities("", page)
+		result, err := svc.ListSecurities(SecurityFilter{}, page)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 3 {
 			t.Errorf("expected 3 results for empty search, got %d", result.TotalItems)
 		}
 	})
+
+	t.Run("filters_by_asset_type_exchange_and_currency", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
+
+		testutil.CreateTestSecurityWithParams(t, db, "AAPL", "Apple Inc", models.AssetTypeStock, "NASDAQ")
+		testutil.CreateTestSecurityWithParams(t, db, "VOO", "Vanguard S&P 500", models.AssetTypeETF, "NYSE")
+		testutil.CreateTestSecurityWithParams(t, db, "BTC", "Bitcoin", models.AssetTypeCrypto, "")
+		maybank := testutil.CreateTestSecurityWithParams(t, db, "1155", "Malayan Banking", models.AssetTypeStock, "MYX")
+		testutil.AssertNoError(t, db.Model(maybank).Update("currency", "MYR").Error)
+		page := pagination.PageRequest{Page: 1, PageSize: 20}
+
+		etf := models.AssetTypeETF
+		result, err := svc.ListSecurities(SecurityFilter{AssetType: &etf}, page)
+		testutil.AssertNoError(t, err)
+		if result.TotalItems != 1 || result.Data[0].Symbol != "VOO" {
+			t.Errorf("expected only VOO for asset_type=etf, got %v", result.Data)
+		}
+
+		exchange := "nasdaq"
+		result, err = svc.ListSecurities(SecurityFilter{Exchange: &exchange}, page)
+		testutil.AssertNoError(t, err)
+		if result.TotalItems != 1 || result.Data[0].Symbol != "AAPL" {
+			t.Errorf("expected exchange to match case-insensitively, got %v", result.Data)
+		}
+
+		myr := "MYR"
+		result, err = svc.ListSecurities(SecurityFilter{Currency: &myr}, page)
+		testutil.AssertNoError(t, err)
+		if result.TotalItems != 1 || result.Data[0].Symbol != "1155" {
+			t.Errorf("expected only 1155 for currency=MYR, got %v", result.Data)
+		}
+
+		stock := models.AssetTypeStock
+		usd := "USD"
+		result, err = svc.ListSecurities(SecurityFilter{Search: "a", AssetType: &stock, Currency: &usd}, page)
+		testutil.AssertNoError(t, err)
+		if result.TotalItems != 1 || result.Data[0].Symbol != "AAPL" {
+			t.Errorf("expected filters to combine with search, got %v", result.Data)
+		}
+	})
 }
 
 func TestListAllSecurities(t *testing.T) {
+	t.Parallel()
 	t.Run("returns_all_securities", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		testutil.CreateTestSecurityWithParams(t, db, "MSFT", "Microsoft Corp", models.AssetTypeStock, "NASDAQ")
 		testutil.CreateTestSecurityWithParams(t, db, "AAPL", "Apple Inc", models.AssetTypeStock, "NASDAQ")
@@ -345,9 +477,8 @@ func TestListAllSecurities(t *testing.T) {
 	})
 
 	t.Run("returns_empty_when_none", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		securities, err := svc.ListAllSecurities()
 		testutil.AssertNoError(t, err)
@@ -361,9 +492,8 @@ func TestListAllSecurities(t *testing.T) {
 	})
 
 	t.Run("excludes_soft_deleted", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		active := testutil.CreateTestSecurityWithParams(t, db, "AAPL", "Apple Inc", models.AssetTypeStock, "NASDAQ")
 		deleted := testutil.CreateTestSecurityWithParams(t, db, "GONE", "Deleted Corp", models.AssetTypeStock, "NYSE")
@@ -378,16 +508,16 @@ func TestListAllSecurities(t *testing.T) {
 			t.Fatalf("expected 1 security, got %d", len(securities))
 		}
 		if securities[0].ID != active.ID {
-			t.Errorf("expected security ID %d, got %d", active.ID, securities[0].ID)
+			t.Errorf("expected security ID %s, got %s", active.ID, securities[0].ID)
 		}
 	})
 }
 
 func TestRecordPrices(t *testing.T) {
+	t.Parallel()
 	t.Run("valid_bulk_insert", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		sec1 := testutil.CreateTestSecurity(t, db)
 		sec2 := testutil.CreateTestSecurity(t, db)
@@ -399,11 +529,11 @@ func TestRecordPrices(t *testing.T) {
 			{SecurityID: sec2.ID, Price: 4200, RecordedAt: now},
 		}
 
-		count, err := svc.RecordPrices(prices)
+		result, err := svc.RecordPrices(prices)
 		testutil.AssertNoError(t, err)
 
-		if count != 3 {
-			t.Errorf("expected 3 prices recorded, got %d", count)
+		if result.Recorded != 3 {
+			t.Errorf("expected 3 prices recorded, got %d", result.Recorded)
 		}
 
 		// Verify in DB
@@ -414,10 +544,31 @@ func TestRecordPrices(t *testing.T) {
 		}
 	})
 
+	t.Run("stores_and_replaces_currency", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
+		sec := testutil.CreateTestSecurity(t, db)
+		now := time.Now().Truncate(time.Second)
+
+		_, err := svc.RecordPrices([]SecurityPriceInput{{SecurityID: sec.ID, Price: 15000, RecordedAt: now}})
+		testutil.AssertNoError(t, err)
+		// The same price re-recorded in another currency is a change
+		result, err := svc.RecordPrices([]SecurityPriceInput{{SecurityID: sec.ID, Price: 15000, RecordedAt: now, Currency: "MYR"}})
+		testutil.AssertNoError(t, err)
+		if result.Recorded != 1 {
+			t.Errorf("expected the currency change recorded, got %d", result.Recorded)
+		}
+
+		var stored models.SecurityPrice
+		testutil.AssertNoError(t, db.Where("security_id = ?", sec.ID).First(&stored).Error)
+		if stored.Currency != "MYR" {
+			t.Errorf("expected currency MYR, got %q", stored.Currency)
+		}
+	})
+
 	t.Run("idempotent_retry", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		sec := testutil.CreateTestSecurity(t, db)
 		now := time.Now().Truncate(time.Second)
@@ -426,17 +577,17 @@ func TestRecordPrices(t *testing.T) {
 			{SecurityID: sec.ID, Price: 15000, RecordedAt: now},
 		}
 
-		count1, err := svc.RecordPrices(prices)
+		result1, err := svc.RecordPrices(prices)
 		testutil.AssertNoError(t, err)
-		if count1 != 1 {
-			t.Errorf("expected 1 on first insert, got %d", count1)
+		if result1.Recorded != 1 {
+			t.Errorf("expected 1 on first insert, got %d", result1.Recorded)
 		}
 
 		// Insert same price again — should not create duplicate
-		count2, err := svc.RecordPrices(prices)
+		result2, err := svc.RecordPrices(prices)
 		testutil.AssertNoError(t, err)
-		if count2 != 0 {
-			t.Errorf("expected 0 on duplicate insert, got %d", count2)
+		if result2.Recorded != 0 {
+			t.Errorf("expected 0 on duplicate insert, got %d", result2.Recorded)
 		}
 
 		// Verify only 1 row exists
@@ -447,21 +598,212 @@ func TestRecordPrices(t *testing.T) {
 		}
 	})
 
+	t.Run("upserts_changed_price", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
+
+		sec := testutil.CreateTestSecurity(t, db)
+		now := time.Now().Truncate(time.Second)
+
+		_, err := svc.RecordPrices([]SecurityPriceInput{{SecurityID: sec.ID, Price: 15000, RecordedAt: now}})
+		testutil.AssertNoError(t, err)
+
+		result, err := svc.RecordPrices([]SecurityPriceInput{
+			{SecurityID: sec.ID, Price: 15500, RecordedAt: now, Source: models.PriceSourceYahoo},
+		})
+		testutil.AssertNoError(t, err)
+		if result.Recorded != 1 {
+			t.Errorf("expected 1 price recorded, got %d", result.Recorded)
+		}
+
+		var stored []models.SecurityPrice
+		db.Where("security_id = ?", sec.ID).Find(&stored)
+		if len(stored) != 1 {
+			t.Fatalf("expected 1 row in DB, got %d", len(stored))
+		}
+		if stored[0].Price != 15500 {
+			t.Errorf("expected price 15500, got %d", stored[0].Price)
+		}
+		if stored[0].Source != models.PriceSourceYahoo {
+			t.Errorf("expected source yahoo, got %q", stored[0].Source)
+		}
+	})
+
+	t.Run("deviation_guard", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 50)
+
+		sec := testutil.CreateTestSecurity(t, db)
+		base := time.Now().Truncate(time.Second).Add(-4 * time.Hour)
+		_, err := svc.RecordPrices([]SecurityPriceInput{{SecurityID: sec.ID, Price: 10000, RecordedAt: base}})
+		testutil.AssertNoError(t, err)
+
+		// Within tolerance: +40% is recorded
+		result, err := svc.RecordPrices([]SecurityPriceInput{{SecurityID: sec.ID, Price: 14000, RecordedAt: base.Add(time.Hour)}})
+		testutil.AssertNoError(t, err)
+		if result.Recorded != 1 || len(result.Rejected) != 0 {
+			t.Fatalf("expected the +40%% price to be recorded, got %+v", result)
+		}
+
+		// Out of tolerance: 100x the last price is rejected, while another
+		// entry in the same batch is still recorded
+		glitch := SecurityPriceInput{SecurityID: sec.ID, Price: 1400000, RecordedAt: base.Add(2 * time.Hour)}
+		other := testutil.CreateTestSecurityWithParams(t, db, "OTHER", "Other", models.AssetTypeStock, "NYSE")
+		result, err = svc.RecordPrices([]SecurityPriceInput{glitch, {SecurityID: other.ID, Price: 5000, RecordedAt: base}})
+		testutil.AssertNoError(t, err)
+		if result.Recorded != 1 || len(result.Rejected) != 1 {
+			t.Fatalf("expected 1 recorded and 1 rejected, got %+v", result)
+		}
+		rejected := result.Rejected[0]
+		if rejected.SecurityID != sec.ID || rejected.Price != 1400000 || rejected.LastPrice != 14000 || rejected.DeviationPct != 9900 {
+			t.Errorf("unexpected rejection: %+v", rejected)
+		}
+		var stored int64
+		db.Model(&models.SecurityPrice{}).Where("security_id = ? AND price = ?", sec.ID, 1400000).Count(&stored)
+		if stored != 0 {
+			t.Error("expected the rejected price not to be stored")
+		}
+
+		// Override: the same move is recorded when flagged as legitimate
+		glitch.AllowLargeMove = true
+		result, err = svc.RecordPrices([]SecurityPriceInput{glitch})
+		testutil.AssertNoError(t, err)
+		if result.Recorded != 1 || len(result.Rejected) != 0 {
+			t.Errorf("expected the overridden price to be recorded, got %+v", result)
+		}
+	})
+
+	t.Run("deviation_guard_disabled", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
+
+		sec := testutil.CreateTestSecurity(t, db)
+		now := time.Now().Truncate(time.Second)
+		result, err := svc.RecordPrices([]SecurityPriceInput{
+			{SecurityID: sec.ID, Price: 10000, RecordedAt: now.Add(-time.Hour)},
+			{SecurityID: sec.ID, Price: 1000000, RecordedAt: now},
+		})
+		testutil.AssertNoError(t, err)
+		if result.Recorded != 2 || len(result.Rejected) != 0 {
+			t.Errorf("expected both prices recorded without a threshold, got %+v", result)
+		}
+	})
+
+	t.Run("source_defaults_to_manual", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
+
+		sec := testutil.CreateTestSecurity(t, db)
+		_, err := svc.RecordPrices([]SecurityPriceInput{{SecurityID: sec.ID, Price: 15000, RecordedAt: time.Now()}})
+		testutil.AssertNoError(t, err)
+
+		var stored models.SecurityPrice
+		db.Where("security_id = ?", sec.ID).First(&stored)
+		if stored.Source != models.PriceSourceManual {
+			t.Errorf("expected source manual, got %q", stored.Source)
+		}
+	})
+
 	t.Run("empty_input", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		_, err := svc.RecordPrices([]SecurityPriceInput{})
 		testutil.AssertAppError(t, err, "INVALID_INPUT")
 	})
 }
 
+func TestCorrectPrice(t *testing.T) {
+	t.Parallel()
+	t.Run("replaces_price_as_manual", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 50)
+
+		sec := testutil.CreateTestSecurity(t, db)
+		recorded := testutil.CreateTestSecurityPrice(t, db, sec.ID, 15000, time.Now().Truncate(time.Second))
+
+		// A hundredfold change would fail the deviation check when recorded
+		result, err := svc.CorrectPrice(sec.ID, recorded.ID, PriceCorrection{Price: 150})
+		testutil.AssertNoError(t, err)
+		if result.Previous.Price != 15000 {
+			t.Errorf("expected previous price 15000, got %d", result.Previous.Price)
+		}
+		if result.Current == nil || result.Current.Price != 150 || result.Current.Source != models.PriceSourceManual {
+			t.Errorf("expected current price 150 from manual, got %+v", result.Current)
+		}
+
+		var stored models.SecurityPrice
+		db.First(&stored, "id = ?", recorded.ID)
+		if stored.Price != 150 || stored.Source != models.PriceSourceManual {
+			t.Errorf("expected stored price 150 from manual, got %d from %q", stored.Price, stored.Source)
+		}
+	})
+
+	t.Run("delete_hides_price_until_rerecorded", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
+
+		sec := testutil.CreateTestSecurity(t, db)
+		now := time.Now().Truncate(time.Second)
+		testutil.CreateTestSecurityPrice(t, db, sec.ID, 15000, now.Add(-time.Hour))
+		bad := testutil.CreateTestSecurityPrice(t, db, sec.ID, 1, now)
+
+		result, err := svc.CorrectPrice(sec.ID, bad.ID, PriceCorrection{Delete: true})
+		testutil.AssertNoError(t, err)
+		if result.Current != nil {
+			t.Errorf("expected no current price after delete, got %+v", result.Current)
+		}
+
+		page := pagination.PageRequest{Page: 1, PageSize: 20}
+		history, err := svc.GetPriceHistory(sec.ID, now.Add(-2*time.Hour), now.Add(time.Hour), page)
+		testutil.AssertNoError(t, err)
+		if len(history.Data) != 1 || history.Data[0].Price != 15000 {
+			t.Errorf("expected only the 15000 price in history, got %+v", history.Data)
+		}
+
+		recordResult, err := svc.RecordPrices([]SecurityPriceInput{{SecurityID: sec.ID, Price: 15100, RecordedAt: now}})
+		testutil.AssertNoError(t, err)
+		if recordResult.Recorded != 1 {
+			t.Errorf("expected re-recording the deleted timestamp to count, got %d", recordResult.Recorded)
+		}
+		history, err = svc.GetPriceHistory(sec.ID, now.Add(-2*time.Hour), now.Add(time.Hour), page)
+		testutil.AssertNoError(t, err)
+		if len(history.Data) != 2 || history.Data[0].Price != 15100 {
+			t.Errorf("expected the re-recorded price to be restored, got %+v", history.Data)
+		}
+	})
+
+	t.Run("not_found_for_other_security", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
+
+		sec := testutil.CreateTestSecurity(t, db)
+		other := testutil.CreateTestSecurity(t, db)
+		recorded := testutil.CreateTestSecurityPrice(t, db, sec.ID, 15000, time.Now())
+
+		_, err := svc.CorrectPrice(other.ID, recorded.ID, PriceCorrection{Price: 100})
+		testutil.AssertAppError(t, err, "PRICE_NOT_FOUND")
+		_, err = svc.CorrectPrice(sec.ID, uuid.New(), PriceCorrection{Delete: true})
+		testutil.AssertAppError(t, err, "PRICE_NOT_FOUND")
+	})
+
+	t.Run("rejects_non_positive_price", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
+
+		sec := testutil.CreateTestSecurity(t, db)
+		recorded := testutil.CreateTestSecurityPrice(t, db, sec.ID, 15000, time.Now())
+
+		_, err := svc.CorrectPrice(sec.ID, recorded.ID, PriceCorrection{})
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+}
+
 func TestGetPriceHistory(t *testing.T) {
+	t.Parallel()
 	t.Run("returns_paginated", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		sec := testutil.CreateTestSecurity(t, db)
 		base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
@@ -496,9 +838,8 @@ func TestGetPriceHistory(t *testing.T) {
 	})
 
 	t.Run("filters_by_date_range", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		sec := testutil.CreateTestSecurity(t, db)
 		base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
@@ -526,9 +867,8 @@ func TestGetPriceHistory(t *testing.T) {
 	})
 
 	t.Run("filters_by_security", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		sec1 := testutil.CreateTestSecurity(t, db)
 		sec2 := testutil.CreateTestSecurity(t, db)
@@ -555,9 +895,8 @@ func TestGetPriceHistory(t *testing.T) {
 	})
 
 	t.Run("ordered_by_recorded_at_desc", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		svc := NewSecurityService(db)
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
 
 		sec := testutil.CreateTestSecurity(t, db)
 		base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
@@ -589,3 +928,128 @@ func TestGetPriceHistory(t *testing.T) {
 		}
 	})
 }
+
+func TestListSecuritiesWithHoldings(t *testing.T) {
+	t.Parallel()
+	t.Run("sums_holdings_across_accounts", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
+		user := testutil.CreateTestUser(t, db)
+		acct1 := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		acct2 := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		held := testutil.CreateTestSecurityWithParams(t, db, "AAA", "Held Co", models.AssetTypeStock, "NYSE")
+		notHeld := testutil.CreateTestSecurityWithParams(t, db, "BBB", "Other Co", models.AssetTypeStock, "NYSE")
+		testutil.CreateTestInvestment(t, db, acct1.ID, held.ID) // 10 shares, cost basis 100000
+		testutil.CreateTestInvestment(t, db, acct2.ID, held.ID)
+		testutil.CreateTestSecurityPrice(t, db, held.ID, 15000, time.Now())
+
+		result, err := svc.ListSecuritiesWithHoldings(user.ID, SecurityFilter{}, pagination.PageRequest{Page: 1, PageSize: 20}, false)
+		testutil.AssertNoError(t, err)
+
+		if result.TotalItems != 2 || len(result.Data) != 2 {
+			t.Fatalf("expected 2 securities, got %d (total %d)", len(result.Data), result.TotalItems)
+		}
+		if result.Data[0].ID != held.ID || result.Data[1].ID != notHeld.ID {
+			t.Fatalf("expected securities ordered by symbol")
+		}
+		h := result.Data[0].Holding
+		if h == nil {
+			t.Fatal("expected a holding for the held security")
+		}
+		if h.Quantity != 20 || h.CostBasis != 200000 || h.CurrentValue != 300000 || h.GainLoss != 100000 {
+			t.Errorf("unexpected holding: %+v", *h)
+		}
+		if result.Data[1].Holding != nil {
+			t.Errorf("expected no holding for unheld security, got %+v", *result.Data[1].Holding)
+		}
+	})
+
+	t.Run("owned_only_filters_to_open_positions", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
+		user := testutil.CreateTestUser(t, db)
+		acct := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		held := testutil.CreateTestSecurityWithParams(t, db, "AAA", "Held Co", models.AssetTypeStock, "NYSE")
+		closed := testutil.CreateTestSecurityWithParams(t, db, "CCC", "Closed Co", models.AssetTypeStock, "NYSE")
+		testutil.CreateTestSecurityWithParams(t, db, "DDD", "Never Co", models.AssetTypeStock, "NYSE")
+		testutil.CreateTestInvestment(t, db, acct.ID, held.ID)
+		closedInv := testutil.CreateTestInvestment(t, db, acct.ID, closed.ID)
+		db.Model(closedInv).Updates(map[string]interface{}{"quantity": 0, "cost_basis": 0})
+
+		result, err := svc.ListSecuritiesWithHoldings(user.ID, SecurityFilter{}, pagination.PageRequest{Page: 1, PageSize: 20}, true)
+		testutil.AssertNoError(t, err)
+
+		if result.TotalItems != 1 || len(result.Data) != 1 || result.Data[0].ID != held.ID {
+			t.Fatalf("expected only the held security, got %+v", result.Data)
+		}
+		if result.Data[0].Holding == nil || result.Data[0].Holding.Quantity != 10 {
+			t.Errorf("expected holding of 10, got %+v", result.Data[0].Holding)
+		}
+	})
+
+	t.Run("isolates_users_and_inactive_accounts", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
+		user := testutil.CreateTestUser(t, db)
+		other := testutil.CreateTestUser(t, db)
+		otherAcct := testutil.CreateTestInvestmentAccount(t, db, other.ID)
+		inactive := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		db.Model(inactive).Update("is_active", false)
+		sec := testutil.CreateTestSecurity(t, db)
+		testutil.CreateTestInvestment(t, db, otherAcct.ID, sec.ID)
+		testutil.CreateTestInvestment(t, db, inactive.ID, sec.ID)
+
+		owned, err := svc.ListSecuritiesWithHoldings(user.ID, SecurityFilter{}, pagination.PageRequest{Page: 1, PageSize: 20}, true)
+		testutil.AssertNoError(t, err)
+		if owned.TotalItems != 0 {
+			t.Errorf("expected no owned securities, got %d", owned.TotalItems)
+		}
+
+		all, err := svc.ListSecuritiesWithHoldings(user.ID, SecurityFilter{}, pagination.PageRequest{Page: 1, PageSize: 20}, false)
+		testutil.AssertNoError(t, err)
+		if len(all.Data) != 1 || all.Data[0].Holding != nil {
+			t.Errorf("expected the security without a holding, got %+v", all.Data)
+		}
+
+		// The other user sees only their own position
+		theirs, err := svc.ListSecuritiesWithHoldings(other.ID, SecurityFilter{}, pagination.PageRequest{Page: 1, PageSize: 20}, true)
+		testutil.AssertNoError(t, err)
+		if len(theirs.Data) != 1 || theirs.Data[0].Holding == nil || theirs.Data[0].Holding.Quantity != 10 {
+			t.Errorf("expected other user's holding of 10, got %+v", theirs.Data)
+		}
+	})
+
+	t.Run("includes_accounts_shared_with_user", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
+		owner := testutil.CreateTestUser(t, db)
+		viewer := testutil.CreateTestUser(t, db)
+		acct := testutil.CreateTestInvestmentAccount(t, db, owner.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		testutil.CreateTestInvestment(t, db, acct.ID, sec.ID)
+		testutil.CreateTestAccountShare(t, db, owner.ID, viewer.ID, models.ShareRoleViewer, acct.ID)
+
+		result, err := svc.ListSecuritiesWithHoldings(viewer.ID, SecurityFilter{}, pagination.PageRequest{Page: 1, PageSize: 20}, true)
+		testutil.AssertNoError(t, err)
+		if len(result.Data) != 1 || result.Data[0].Holding == nil {
+			t.Errorf("expected shared holding to be listed, got %+v", result.Data)
+		}
+	})
+
+	t.Run("search_combines_with_owned", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewSecurityService(db, nil, 0)
+		user := testutil.CreateTestUser(t, db)
+		acct := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		apple := testutil.CreateTestSecurityWithParams(t, db, "AAPL", "Apple Inc.", models.AssetTypeStock, "NASDAQ")
+		msft := testutil.CreateTestSecurityWithParams(t, db, "MSFT", "Microsoft", models.AssetTypeStock, "NASDAQ")
+		testutil.CreateTestInvestment(t, db, acct.ID, apple.ID)
+		testutil.CreateTestInvestment(t, db, acct.ID, msft.ID)
+
+		result, err := svc.ListSecuritiesWithHoldings(user.ID, SecurityFilter{Search: "apple"}, pagination.PageRequest{Page: 1, PageSize: 20}, true)
+		testutil.AssertNoError(t, err)
+		if len(result.Data) != 1 || result.Data[0].ID != apple.ID {
+			t.Errorf("expected only AAPL, got %+v", result.Data)
+		}
+	})
+}
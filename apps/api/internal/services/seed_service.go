@@ -0,0 +1,318 @@
+package services
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	"gorm.io/gorm"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+)
+
+// demoMonths is how many months of history a demo user is seeded with.
+const demoMonths = 6
+
+// DemoSecurityExchange is the exchange of the securities demo holdings are in.
+// The oracle does not price them; their history is generated on seeding.
+const DemoSecurityExchange = "DEMO"
+
+// demoSecurity describes a security held by demo users. Its price history is a
+// random walk drawn from its own seed, so every demo user sees the same prices.
+type demoSecurity struct {
+	Symbol     string
+	Name       string
+	AssetType  models.AssetType
+	StartPrice int64
+	Seed       int64
+}
+
+var demoSecurities = []demoSecurity{
+	{Symbol: "WRLD", Name: "Demo World Equity ETF", AssetType: models.AssetTypeETF, StartPrice: 10450, Seed: 1},
+	{Symbol: "TECH", Name: "Demo Technology Corp", AssetType: models.AssetTypeStock, StartPrice: 18720, Seed: 2},
+}
+
+// demoCategory describes a category created for demo users.
+type demoCategory struct {
+	Name  string
+	Type  models.CategoryType
+	Icon  string
+	Color string
+}
+
+var demoCategories = []demoCategory{
+	{Name: "Salary", Type: models.CategoryTypeIncome, Icon: "💼", Color: "#22C55E"},
+	{Name: "Rent", Type: models.CategoryTypeExpense, Icon: "🏠", Color: "#8B5CF6"},
+	{Name: "Groceries", Type: models.CategoryTypeExpense, Icon: "🛒", Color: "#F59E0B"},
+	{Name: "Dining Out", Type: models.CategoryTypeExpense, Icon: "🍽️", Color: "#EF4444"},
+	{Name: "Transport", Type: models.CategoryTypeExpense, Icon: "🚌", Color: "#3B82F6"},
+	{Name: "Utilities", Type: models.CategoryTypeExpense, Icon: "💡", Color: "#14B8A6"},
+	{Name: "Entertainment", Type: models.CategoryTypeExpense, Icon: "🎬", Color: "#EC4899"},
+}
+
+// SeedSummary counts what was created for a demo user.
+type SeedSummary struct {
+	Accounts     int `json:"accounts"`
+	Categories   int `json:"categories"`
+	Transactions int `json:"transactions"`
+	Budgets      int `json:"budgets"`
+	Investments  int `json:"investments"`
+	Prices       int `json:"prices"`
+	Snapshots    int `json:"snapshots"`
+}
+
+// seedService generates demo data through the other services, so balances,
+// holdings and snapshots are as consistent as for a real user.
+type seedService struct {
+	db           *gorm.DB
+	accounts     AccountServicer
+	categories   CategoryServicer
+	transactions TransactionServicer
+	budgets      BudgetServicer
+	investments  InvestmentServicer
+	securities   SecurityServicer
+	snapshots    PortfolioSnapshotServicer
+}
+
+// NewSeedService creates a new SeedServicer.
+func NewSeedService(db *gorm.DB) SeedServicer {
+	accounts := NewAccountService(db, nil)
+	return &seedService{
+		db:           db,
+		accounts:     accounts,
+		categories:   NewCategoryService(db),
+		transactions: NewTransactionService(db, accounts, nil),
+		budgets:      NewBudgetService(db),
+		investments:  NewInvestmentService(db, accounts, nil, nil, TradePriceCheck{}),
+		securities:   NewSecurityService(db, nil, 0),
+		snapshots:    NewPortfolioSnapshotService(db),
+	}
+}
+
+// SeedDemoUser fills the user's books with demoMonths of history ending at asOf
+// and marks them as a demo user. The same seed and asOf produce the same data.
+func (s *seedService) SeedDemoUser(userID string, seed int64, asOf time.Time) (*SeedSummary, error) {
+	var user models.User
+	if err := s.db.Where("id = ?", userID).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrUserNotFound
+		}
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if err := s.db.Model(&user).Update("is_demo", true).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	asOf = asOf.UTC()
+	start := time.Date(asOf.Year(), asOf.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -(demoMonths - 1), 0)
+	summary := &SeedSummary{}
+
+	categories := make(map[string]*models.Category, len(demoCategories))
+	for _, c := range demoCategories {
+		category, err := s.categories.CreateCategory(userID, c.Name, c.Type, "", c.Icon, c.Color, nil)
+		if err != nil {
+			return nil, err
+		}
+		categories[c.Name] = category
+		summary.Categories++
+	}
+
+	checking, err := s.openCashAccount(userID, "Everyday Checking", 250000, start, summary)
+	if err != nil {
+		return nil, err
+	}
+	savings, err := s.openCashAccount(userID, "Savings", 1500000, start, summary)
+	if err != nil {
+		return nil, err
+	}
+	brokerage, err := s.accounts.CreateInvestmentAccount(models.UserID(userID), "Brokerage", "", "USD", "Demo Broker", "")
+	if err != nil {
+		return nil, err
+	}
+	summary.Accounts++
+
+	if err := s.seedMonthlyActivity(userID, rng, start, asOf, checking.ID, savings.ID, categories); err != nil {
+		return nil, err
+	}
+
+	for _, b := range []struct {
+		Category string
+		Amount   int64
+	}{{"Groceries", 60000}, {"Dining Out", 25000}} {
+		if _, err := s.budgets.CreateBudget(userID, categories[b.Category].ID, b.Category, b.Amount, models.BudgetPeriodMonthly, start, nil); err != nil {
+			return nil, err
+		}
+		summary.Budgets++
+	}
+
+	if err := s.seedHoldings(userID, rng, start, asOf, brokerage.ID, savings.ID, summary); err != nil {
+		return nil, err
+	}
+
+	created, err := s.snapshots.ComputeUserSnapshotsForRange(userID, start, asOf, models.SnapshotIntervalWeekly)
+	if err != nil {
+		return nil, err
+	}
+	summary.Snapshots = created
+
+	var transactions int64
+	if err := s.db.Model(&models.Transaction{}).Where("user_id = ?", userID).Count(&transactions).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	summary.Transactions = int(transactions)
+
+	return summary, nil
+}
+
+// openCashAccount creates a cash account whose opening balance is dated at
+// start rather than now, so history before today adds up.
+func (s *seedService) openCashAccount(userID, name string, balance int64, start time.Time, summary *SeedSummary) (*models.Account, error) {
+	account, err := s.accounts.CreateCashAccount(models.UserID(userID), name, "", "USD", models.Cents(balance), true, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	summary.Accounts++
+
+	var opening models.Transaction
+	if err := s.db.Where("account_id = ? AND description = ?", account.ID, "Initial balance").
+		First(&opening).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if _, err := s.transactions.UpdateTransaction(models.UserID(userID), models.TransactionID(opening.ID), TransactionUpdateFields{Date: &start}); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// seedMonthlyActivity records salary, rent, bills, everyday spending and a
+// savings transfer for every month from start up to asOf.
+func (s *seedService) seedMonthlyActivity(
+	userID string,
+	rng *rand.Rand,
+	start, asOf time.Time,
+	checkingID, savingsID string,
+	categories map[string]*models.Category,
+) error {
+	record := func(categoryName string, txType models.TransactionType, amount int64, description string, date time.Time) error {
+		if date.After(asOf) {
+			return nil
+		}
+		categoryID := categories[categoryName].ID
+		_, err := s.transactions.CreateTransaction(models.UserID(userID), models.AccountID(checkingID), &categoryID, txType, models.Cents(amount), description, date, false, "", nil)
+		return err
+	}
+	// between returns a random amount of cents in [low, high].
+	between := func(low, high int64) int64 {
+		return low + rng.Int63n(high-low+1)
+	}
+	// at returns a time on the given day of month, during the day.
+	at := func(month time.Time, day int) time.Time {
+		return month.AddDate(0, 0, day-1).Add(time.Duration(9+rng.Intn(11)) * time.Hour)
+	}
+
+	for month := start; !month.After(asOf); month = month.AddDate(0, 1, 0) {
+		days := month.AddDate(0, 1, -1).Day()
+
+		if err := record("Salary", models.TransactionTypeIncome, between(520000, 540000), "Salary", at(month, 1)); err != nil {
+			return err
+		}
+		if err := record("Rent", models.TransactionTypeExpense, 180000, "Rent", at(month, 2)); err != nil {
+			return err
+		}
+		if err := record("Utilities", models.TransactionTypeExpense, between(9000, 16000), "Electricity and water", at(month, 8)); err != nil {
+			return err
+		}
+		if err := record("Utilities", models.TransactionTypeExpense, 4999, "Internet", at(month, 12)); err != nil {
+			return err
+		}
+
+		for day := 3; day <= days; day += 7 {
+			if err := record("Groceries", models.TransactionTypeExpense, between(8000, 16000), "Supermarket", at(month, day)); err != nil {
+				return err
+			}
+		}
+		for i, n := 0, 3+rng.Intn(4); i < n; i++ {
+			if err := record("Dining Out", models.TransactionTypeExpense, between(1800, 6500), "Restaurant", at(month, 1+rng.Intn(days))); err != nil {
+				return err
+			}
+		}
+		for i, n := 0, 6+rng.Intn(6); i < n; i++ {
+			if err := record("Transport", models.TransactionTypeExpense, between(250, 1800), "Transit", at(month, 1+rng.Intn(days))); err != nil {
+				return err
+			}
+		}
+		for i, n := 0, 1+rng.Intn(3); i < n; i++ {
+			if err := record("Entertainment", models.TransactionTypeExpense, between(1200, 4500), "Cinema", at(month, 1+rng.Intn(days))); err != nil {
+				return err
+			}
+		}
+
+		if transferDate := at(month, 25); !transferDate.After(asOf) {
+			if _, err := s.transactions.CreateTransfer(models.UserID(userID), models.AccountID(checkingID), models.AccountID(savingsID), 50000, "Monthly savings", transferDate); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// seedHoldings buys the demo securities from savings at the start, tops each
+// up halfway through, and records their weekly price history.
+func (s *seedService) seedHoldings(
+	userID string,
+	rng *rand.Rand,
+	start, asOf time.Time,
+	brokerageID, savingsID string,
+	summary *SeedSummary,
+) error {
+	for _, demo := range demoSecurities {
+		prices := demoPriceHistory(demo, start, asOf)
+
+		ref := SecurityRef{Symbol: demo.Symbol, Name: demo.Name, AssetType: demo.AssetType, Exchange: DemoSecurityExchange}
+		investment, _, err := s.investments.AddInvestment(models.UserID(userID), models.AccountID(brokerageID), ref, float64(10+rng.Intn(30)), models.Cents(prices[0].Price),
+			"", &start, 495, "", models.AccountID(savingsID), InvestmentMetadata{}, false)
+		if err != nil {
+			return err
+		}
+		summary.Investments++
+
+		mid := prices[len(prices)/2]
+		if _, err := s.investments.RecordBuy(models.UserID(userID), models.InvestmentID(investment.ID), mid.RecordedAt, float64(5+rng.Intn(5)), models.Cents(mid.Price),
+			495, "", models.AccountID(savingsID), true); err != nil {
+			return err
+		}
+
+		for i := range prices {
+			prices[i].SecurityID = investment.SecurityID
+		}
+		recorded, err := s.securities.RecordPrices(prices)
+		if err != nil {
+			return err
+		}
+		summary.Prices += recorded.Recorded
+	}
+	return nil
+}
+
+// demoPriceHistory returns weekly prices for the security from start to asOf,
+// as a random walk of up to ±3% a week from its start price.
+func demoPriceHistory(demo demoSecurity, start, asOf time.Time) []SecurityPriceInput {
+	rng := rand.New(rand.NewSource(demo.Seed))
+	price := demo.StartPrice
+	var prices []SecurityPriceInput
+	for at := start; !at.After(asOf); at = at.AddDate(0, 0, 7) {
+		prices = append(prices, SecurityPriceInput{
+			Price:      price,
+			RecordedAt: at,
+			Source:     models.PriceSourceManual,
+		})
+		change := price * int64(rng.Intn(601)-280) / 10000
+		price += change
+		if price < 100 {
+			price = 100
+		}
+	}
+	return prices
+}
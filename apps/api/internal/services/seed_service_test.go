@@ -0,0 +1,105 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"kuberan/internal/models"
+	"kuberan/internal/testutil"
+)
+
+func TestSeedDemoUser(t *testing.T) {
+	t.Parallel()
+	asOf := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	t.Run("seeds_consistent_books", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewSeedService(db)
+		user := testutil.CreateTestUser(t, db)
+
+		summary, err := svc.SeedDemoUser(user.ID, 42, asOf)
+		testutil.AssertNoError(t, err)
+
+		if summary.Accounts != 3 || summary.Budgets != 2 || summary.Investments != 2 {
+			t.Errorf("unexpected summary %+v", summary)
+		}
+		if summary.Transactions < 100 || summary.Prices == 0 || summary.Snapshots == 0 {
+			t.Errorf("expected six months of activity, got %+v", summary)
+		}
+
+		var seeded models.User
+		db.First(&seeded, "id = ?", user.ID)
+		if !seeded.IsDemo {
+			t.Error("expected the user to be marked as demo")
+		}
+
+		// Every cash balance must equal the sum of the transactions applied to it.
+		var accounts []models.Account
+		db.Where("user_id = ? AND type = ?", user.ID, models.AccountTypeCash).Find(&accounts)
+		for _, account := range accounts {
+			var txs []models.Transaction
+			db.Where("account_id = ? OR to_account_id = ?", account.ID, account.ID).Find(&txs)
+			var want int64
+			for i := range txs {
+				want += balanceDelta(&account, &txs[i])
+			}
+			if account.Balance != want || account.Balance < 0 {
+				t.Errorf("%s: balance %d does not match its transactions (%d)", account.Name, account.Balance, want)
+			}
+		}
+
+		var early int64
+		db.Model(&models.Transaction{}).
+			Where("user_id = ? AND date < ?", user.ID, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)).
+			Count(&early)
+		if early != 0 {
+			t.Errorf("expected no history before January, got %d transactions", early)
+		}
+	})
+
+	t.Run("same_seed_is_reproducible", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewSeedService(db)
+		first := testutil.CreateTestUser(t, db)
+		second := testutil.CreateTestUser(t, db)
+		third := testutil.CreateTestUser(t, db)
+
+		_, err := svc.SeedDemoUser(first.ID, 7, asOf)
+		testutil.AssertNoError(t, err)
+		_, err = svc.SeedDemoUser(second.ID, 7, asOf)
+		testutil.AssertNoError(t, err)
+		_, err = svc.SeedDemoUser(third.ID, 8, asOf)
+		testutil.AssertNoError(t, err)
+
+		amounts := func(userID string) []int64 {
+			var result []int64
+			db.Model(&models.Transaction{}).Where("user_id = ?", userID).
+				Order("date ASC, amount ASC").Pluck("amount", &result)
+			return result
+		}
+		a, b, c := amounts(first.ID), amounts(second.ID), amounts(third.ID)
+		if len(a) != len(b) {
+			t.Fatalf("expected the same number of transactions, got %d and %d", len(a), len(b))
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				t.Fatalf("transaction %d differs: %d vs %d", i, a[i], b[i])
+			}
+		}
+		same := len(a) == len(c)
+		for i := 0; same && i < len(a); i++ {
+			same = a[i] == c[i]
+		}
+		if same {
+			t.Error("expected a different seed to produce different data")
+		}
+	})
+
+	t.Run("unknown_user", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewSeedService(db)
+
+		_, err := svc.SeedDemoUser("00000000-0000-7000-8000-000000000999", 1, asOf)
+		testutil.AssertAppError(t, err, "USER_NOT_FOUND")
+	})
+}
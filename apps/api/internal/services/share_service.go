@@ -0,0 +1,194 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+)
+
+// shareService handles sharing accounts with other registered users.
+type shareService struct {
+	db *gorm.DB
+}
+
+// NewShareService creates a new ShareServicer.
+func NewShareService(db *gorm.DB) ShareServicer {
+	return &shareService{db: db}
+}
+
+// CreateShare invites the user registered under email to access ownerID's
+// accounts with the given role. An empty accountIDs shares every account,
+// including ones created later. The share is inactive until accepted.
+func (s *shareService) CreateShare(ownerID, email string, role models.ShareRole, accountIDs []string) (*models.AccountShare, error) {
+	if role != models.ShareRoleViewer && role != models.ShareRoleEditor {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "role must be viewer or editor")
+	}
+
+	var invitee models.User
+	if err := s.db.Where("email = ? AND is_active = ?", strings.ToLower(strings.TrimSpace(email)), true).
+		First(&invitee).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrUserNotFound
+		}
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if invitee.ID == ownerID {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "cannot share accounts with yourself")
+	}
+
+	var existing int64
+	if err := s.db.Model(&models.AccountShare{}).
+		Where("owner_id = ? AND shared_with_id = ?", ownerID, invitee.ID).
+		Count(&existing).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if existing > 0 {
+		return nil, apperrors.ErrDuplicateShare
+	}
+
+	// Only the owner's own accounts can be shared
+	accountIDs = uniqueStrings(accountIDs)
+	if len(accountIDs) > 0 {
+		var owned int64
+		if err := s.db.Model(&models.Account{}).
+			Where("id IN ? AND user_id = ?", accountIDs, ownerID).
+			Count(&owned).Error; err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		if int(owned) != len(accountIDs) {
+			return nil, apperrors.ErrAccountNotFound
+		}
+	}
+
+	share := &models.AccountShare{
+		OwnerID:      ownerID,
+		SharedWithID: invitee.ID,
+		Role:         role,
+		AllAccounts:  len(accountIDs) == 0,
+		AccountIDs:   accountIDs,
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if txErr := tx.Create(share).Error; txErr != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, txErr)
+		}
+		for _, accountID := range accountIDs {
+			link := &models.AccountShareAccount{AccountShareID: share.ID, AccountID: accountID}
+			if txErr := tx.Create(link).Error; txErr != nil {
+				return apperrors.Wrap(apperrors.ErrInternalServer, txErr)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return share, nil
+}
+
+// GetShares returns the shares a user has granted or received, newest first.
+func (s *shareService) GetShares(userID string) ([]models.AccountShare, error) {
+	var shares []models.AccountShare
+	if err := s.db.Where("owner_id = ? OR shared_with_id = ?", userID, userID).
+		Order("created_at DESC").
+		Find(&shares).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if err := s.loadAccountIDs(shares); err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// AcceptShare activates a share addressed to userID. Accepting an already
+// accepted share is a no-op.
+func (s *shareService) AcceptShare(userID, shareID string) (*models.AccountShare, error) {
+	var share models.AccountShare
+	if err := s.db.Where("id = ? AND shared_with_id = ?", shareID, userID).First(&share).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrShareNotFound
+		}
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	if share.AcceptedAt == nil {
+		now := time.Now()
+		if err := s.db.Model(&share).Update("accepted_at", now).Error; err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		share.AcceptedAt = &now
+	}
+
+	shares := []models.AccountShare{share}
+	if err := s.loadAccountIDs(shares); err != nil {
+		return nil, err
+	}
+	return &shares[0], nil
+}
+
+// DeleteShare revokes a share. Either the owner or the invited user may delete it.
+func (s *shareService) DeleteShare(userID, shareID string) error {
+	var share models.AccountShare
+	if err := s.db.Where("id = ? AND (owner_id = ? OR shared_with_id = ?)", shareID, userID, userID).
+		First(&share).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrShareNotFound
+		}
+		return apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("account_share_id = ?", share.ID).Delete(&models.AccountShareAccount{}).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		if err := tx.Unscoped().Delete(&share).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		return nil
+	})
+}
+
+// loadAccountIDs fills AccountIDs for shares scoped to specific accounts.
+func (s *shareService) loadAccountIDs(shares []models.AccountShare) error {
+	if len(shares) == 0 {
+		return nil
+	}
+	shareIDs := make([]string, len(shares))
+	for i := range shares {
+		shareIDs[i] = shares[i].ID
+	}
+
+	var links []models.AccountShareAccount
+	if err := s.db.Where("account_share_id IN ?", shareIDs).Find(&links).Error; err != nil {
+		return apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	byShare := make(map[string][]string)
+	for _, link := range links {
+		byShare[link.AccountShareID] = append(byShare[link.AccountShareID], link.AccountID)
+	}
+	for i := range shares {
+		shares[i].AccountIDs = byShare[shares[i].ID]
+	}
+	return nil
+}
+
+// uniqueStrings returns values with duplicates removed, preserving order.
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		result = append(result, v)
+	}
+	return result
+}
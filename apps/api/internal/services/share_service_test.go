@@ -0,0 +1,401 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kuberan/internal/models"
+	"kuberan/internal/pagination"
+	"kuberan/internal/testutil"
+	"kuberan/internal/uuid"
+)
+
+func TestCreateShare(t *testing.T) {
+	t.Parallel()
+	t.Run("all_accounts", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewShareService(db)
+		owner := testutil.CreateTestUser(t, db)
+		partner := testutil.CreateTestUser(t, db)
+
+		share, err := svc.CreateShare(owner.ID, partner.Email, models.ShareRoleViewer, nil)
+		testutil.AssertNoError(t, err)
+
+		if !share.AllAccounts {
+			t.Error("expected share to cover all accounts")
+		}
+		if share.SharedWithID != partner.ID {
+			t.Errorf("expected shared with %s, got %s", partner.ID, share.SharedWithID)
+		}
+		if share.AcceptedAt != nil {
+			t.Error("expected share to be pending")
+		}
+	})
+
+	t.Run("specific_accounts", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewShareService(db)
+		owner := testutil.CreateTestUser(t, db)
+		partner := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, owner.ID)
+
+		share, err := svc.CreateShare(owner.ID, partner.Email, models.ShareRoleEditor, []string{account.ID, account.ID})
+		testutil.AssertNoError(t, err)
+
+		if share.AllAccounts {
+			t.Error("expected share scoped to listed accounts")
+		}
+		var links []models.AccountShareAccount
+		db.Where("account_share_id = ?", share.ID).Find(&links)
+		if len(links) != 1 || links[0].AccountID != account.ID {
+			t.Errorf("expected one link to %s, got %+v", account.ID, links)
+		}
+	})
+
+	t.Run("unknown_email", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewShareService(db)
+		owner := testutil.CreateTestUser(t, db)
+
+		_, err := svc.CreateShare(owner.ID, "nobody@example.com", models.ShareRoleViewer, nil)
+		testutil.AssertAppError(t, err, "USER_NOT_FOUND")
+	})
+
+	t.Run("self", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewShareService(db)
+		owner := testutil.CreateTestUser(t, db)
+
+		_, err := svc.CreateShare(owner.ID, owner.Email, models.ShareRoleViewer, nil)
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+
+	t.Run("invalid_role", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewShareService(db)
+		owner := testutil.CreateTestUser(t, db)
+		partner := testutil.CreateTestUser(t, db)
+
+		_, err := svc.CreateShare(owner.ID, partner.Email, models.ShareRole("admin"), nil)
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewShareService(db)
+		owner := testutil.CreateTestUser(t, db)
+		partner := testutil.CreateTestUser(t, db)
+
+		_, err := svc.CreateShare(owner.ID, partner.Email, models.ShareRoleViewer, nil)
+		testutil.AssertNoError(t, err)
+		_, err = svc.CreateShare(owner.ID, partner.Email, models.ShareRoleEditor, nil)
+		testutil.AssertAppError(t, err, "DUPLICATE_SHARE")
+	})
+
+	t.Run("account_not_owned", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewShareService(db)
+		owner := testutil.CreateTestUser(t, db)
+		partner := testutil.CreateTestUser(t, db)
+		partnerAccount := testutil.CreateTestCashAccount(t, db, partner.ID)
+
+		_, err := svc.CreateShare(owner.ID, partner.Email, models.ShareRoleViewer, []string{partnerAccount.ID})
+		testutil.AssertAppError(t, err, "ACCOUNT_NOT_FOUND")
+	})
+}
+
+func TestAcceptShare(t *testing.T) {
+	t.Parallel()
+	t.Run("valid", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewShareService(db)
+		owner := testutil.CreateTestUser(t, db)
+		partner := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, owner.ID)
+
+		share, err := svc.CreateShare(owner.ID, partner.Email, models.ShareRoleViewer, []string{account.ID})
+		testutil.AssertNoError(t, err)
+
+		accepted, err := svc.AcceptShare(partner.ID, share.ID)
+		testutil.AssertNoError(t, err)
+
+		if accepted.AcceptedAt == nil {
+			t.Error("expected accepted_at to be set")
+		}
+		if len(accepted.AccountIDs) != 1 || accepted.AccountIDs[0] != account.ID {
+			t.Errorf("expected account IDs [%s], got %v", account.ID, accepted.AccountIDs)
+		}
+	})
+
+	t.Run("owner_cannot_accept", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewShareService(db)
+		owner := testutil.CreateTestUser(t, db)
+		partner := testutil.CreateTestUser(t, db)
+
+		share, err := svc.CreateShare(owner.ID, partner.Email, models.ShareRoleViewer, nil)
+		testutil.AssertNoError(t, err)
+
+		_, err = svc.AcceptShare(owner.ID, share.ID)
+		testutil.AssertAppError(t, err, "SHARE_NOT_FOUND")
+	})
+}
+
+func TestDeleteShare(t *testing.T) {
+	t.Parallel()
+	t.Run("by_invitee", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewShareService(db)
+		owner := testutil.CreateTestUser(t, db)
+		partner := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, owner.ID)
+		share := testutil.CreateTestAccountShare(t, db, owner.ID, partner.ID, models.ShareRoleViewer, account.ID)
+
+		testutil.AssertNoError(t, svc.DeleteShare(partner.ID, share.ID))
+
+		var links int64
+		db.Model(&models.AccountShareAccount{}).Count(&links)
+		if links != 0 {
+			t.Errorf("expected links removed, got %d", links)
+		}
+	})
+
+	t.Run("stranger", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewShareService(db)
+		owner := testutil.CreateTestUser(t, db)
+		partner := testutil.CreateTestUser(t, db)
+		stranger := testutil.CreateTestUser(t, db)
+		share := testutil.CreateTestAccountShare(t, db, owner.ID, partner.ID, models.ShareRoleViewer)
+
+		err := svc.DeleteShare(stranger.ID, share.ID)
+		testutil.AssertAppError(t, err, "SHARE_NOT_FOUND")
+	})
+}
+
+func TestGetShares(t *testing.T) {
+	t.Parallel()
+	db := testutil.WithTx(t)
+	svc := NewShareService(db)
+	owner := testutil.CreateTestUser(t, db)
+	partner := testutil.CreateTestUser(t, db)
+	stranger := testutil.CreateTestUser(t, db)
+	testutil.CreateTestAccountShare(t, db, owner.ID, partner.ID, models.ShareRoleViewer)
+
+	for _, userID := range []string{owner.ID, partner.ID} {
+		shares, err := svc.GetShares(userID)
+		testutil.AssertNoError(t, err)
+		if len(shares) != 1 {
+			t.Errorf("expected 1 share for %s, got %d", userID, len(shares))
+		}
+	}
+
+	shares, err := svc.GetShares(stranger.ID)
+	testutil.AssertNoError(t, err)
+	if len(shares) != 0 {
+		t.Errorf("expected no shares for stranger, got %d", len(shares))
+	}
+}
+
+func TestSharedAccountAccess(t *testing.T) {
+	t.Parallel()
+	page := pagination.PageRequest{Page: 1, PageSize: 20}
+
+	t.Run("viewer_reads_shared_accounts", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		owner := testutil.CreateTestUser(t, db)
+		viewer := testutil.CreateTestUser(t, db)
+		shared := testutil.CreateTestCashAccountWithBalance(t, db, owner.ID, 10000)
+		private := testutil.CreateTestCashAccountWithBalance(t, db, owner.ID, 5000)
+		own := testutil.CreateTestCashAccount(t, db, viewer.ID)
+		testutil.CreateTestTransaction(t, db, owner.ID, shared.ID, models.TransactionTypeExpense, 100)
+		testutil.CreateTestTransaction(t, db, owner.ID, private.ID, models.TransactionTypeExpense, 200)
+		testutil.CreateTestTransaction(t, db, viewer.ID, own.ID, models.TransactionTypeExpense, 300)
+		testutil.CreateTestAccountShare(t, db, owner.ID, viewer.ID, models.ShareRoleViewer, shared.ID)
+
+		accounts, err := acctSvc.GetUserAccounts(models.UserID(viewer.ID), page, false)
+		testutil.AssertNoError(t, err)
+		if accounts.TotalItems != 2 {
+			t.Errorf("expected own + shared account, got %d", accounts.TotalItems)
+		}
+
+		txs, err := txSvc.GetUserTransactions(models.UserID(viewer.ID), page, TransactionFilter{})
+		testutil.AssertNoError(t, err)
+		if txs.TotalItems != 2 {
+			t.Errorf("expected 2 visible transactions, got %d", txs.TotalItems)
+		}
+
+		_, err = acctSvc.GetAccountByID(models.UserID(viewer.ID), models.AccountID(shared.ID))
+		testutil.AssertNoError(t, err)
+		_, err = acctSvc.GetAccountByID(models.UserID(viewer.ID), models.AccountID(private.ID))
+		testutil.AssertAppError(t, err, "ACCOUNT_NOT_FOUND")
+	})
+
+	t.Run("owner_view_unchanged_by_sharing", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		owner := testutil.CreateTestUser(t, db)
+		viewer := testutil.CreateTestUser(t, db)
+		testutil.CreateTestCashAccount(t, db, owner.ID)
+		testutil.CreateTestCashAccount(t, db, viewer.ID)
+		testutil.CreateTestAccountShare(t, db, owner.ID, viewer.ID, models.ShareRoleViewer)
+
+		accounts, err := acctSvc.GetUserAccounts(models.UserID(owner.ID), page, false)
+		testutil.AssertNoError(t, err)
+		if accounts.TotalItems != 1 {
+			t.Errorf("expected owner to see only their account, got %d", accounts.TotalItems)
+		}
+	})
+
+	t.Run("pending_share_grants_nothing", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		owner := testutil.CreateTestUser(t, db)
+		invitee := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, owner.ID)
+
+		_, err := NewShareService(db).CreateShare(owner.ID, invitee.Email, models.ShareRoleEditor, nil)
+		testutil.AssertNoError(t, err)
+
+		_, err = acctSvc.GetAccountByID(models.UserID(invitee.ID), models.AccountID(account.ID))
+		testutil.AssertAppError(t, err, "ACCOUNT_NOT_FOUND")
+		accounts, err := acctSvc.GetUserAccounts(models.UserID(invitee.ID), page, false)
+		testutil.AssertNoError(t, err)
+		if accounts.TotalItems != 0 {
+			t.Errorf("expected no accounts, got %d", accounts.TotalItems)
+		}
+	})
+
+	t.Run("viewer_cannot_write", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		owner := testutil.CreateTestUser(t, db)
+		viewer := testutil.CreateTestUser(t, db)
+		shared := testutil.CreateTestCashAccountWithBalance(t, db, owner.ID, 10000)
+		other := testutil.CreateTestCashAccountWithBalance(t, db, owner.ID, 10000)
+		own := testutil.CreateTestCashAccountWithBalance(t, db, viewer.ID, 10000)
+		existing := testutil.CreateTestTransaction(t, db, owner.ID, shared.ID, models.TransactionTypeExpense, 100)
+		testutil.CreateTestAccountShare(t, db, owner.ID, viewer.ID, models.ShareRoleViewer)
+
+		_, err := txSvc.CreateTransaction(models.UserID(viewer.ID), models.AccountID(shared.ID), nil, models.TransactionTypeExpense, 1000, "Sneaky", time.Now(), false, "", nil)
+		testutil.AssertAppError(t, err, "SHARE_READ_ONLY")
+
+		_, err = txSvc.CreateTransfer(models.UserID(viewer.ID), models.AccountID(shared.ID), models.AccountID(own.ID), 1000, "Out", time.Now())
+		testutil.AssertAppError(t, err, "SHARE_READ_ONLY")
+		_, err = txSvc.CreateTransfer(models.UserID(viewer.ID), models.AccountID(own.ID), models.AccountID(other.ID), 1000, "In", time.Now())
+		testutil.AssertAppError(t, err, "SHARE_READ_ONLY")
+
+		amount := int64(5)
+		_, err = txSvc.UpdateTransaction(models.UserID(viewer.ID), models.TransactionID(existing.ID), TransactionUpdateFields{Amount: &amount})
+		testutil.AssertAppError(t, err, "SHARE_READ_ONLY")
+
+		err = txSvc.DeleteTransaction(models.UserID(viewer.ID), models.TransactionID(existing.ID))
+		testutil.AssertAppError(t, err, "SHARE_READ_ONLY")
+
+		name := "Mine"
+		_, err = acctSvc.UpdateAccount(models.UserID(viewer.ID), models.AccountID(shared.ID), AccountUpdateFields{Name: &name})
+		testutil.AssertAppError(t, err, "SHARE_READ_ONLY")
+
+		var dbShared models.Account
+		db.Where("id = ?", shared.ID).First(&dbShared)
+		if dbShared.Balance != 10000 || dbShared.Name != shared.Name {
+			t.Errorf("expected shared account untouched, got balance %d name %s", dbShared.Balance, dbShared.Name)
+		}
+	})
+
+	t.Run("editor_can_write_transactions", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		owner := testutil.CreateTestUser(t, db)
+		editor := testutil.CreateTestUser(t, db)
+		shared := testutil.CreateTestCashAccountWithBalance(t, db, owner.ID, 10000)
+		testutil.CreateTestAccountShare(t, db, owner.ID, editor.ID, models.ShareRoleEditor, shared.ID)
+
+		created, err := txSvc.CreateTransaction(models.UserID(editor.ID), models.AccountID(shared.ID), nil, models.TransactionTypeExpense, 2500, "Groceries", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		// Owner can see and delete the editor's transaction
+		_, err = txSvc.GetTransactionByID(models.UserID(owner.ID), models.TransactionID(created.ID))
+		testutil.AssertNoError(t, err)
+		testutil.AssertNoError(t, txSvc.DeleteTransaction(models.UserID(owner.ID), models.TransactionID(created.ID)))
+
+		var dbShared models.Account
+		db.Where("id = ?", shared.ID).First(&dbShared)
+		if dbShared.Balance != 10000 {
+			t.Errorf("expected balance restored to 10000, got %d", dbShared.Balance)
+		}
+
+		name := "Mine"
+		_, err = acctSvc.UpdateAccount(models.UserID(editor.ID), models.AccountID(shared.ID), AccountUpdateFields{Name: &name})
+		testutil.AssertAppError(t, err, "SHARE_READ_ONLY")
+	})
+
+	t.Run("editor_role_wins_over_viewer", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		owner := testutil.CreateTestUser(t, db)
+		partner := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, owner.ID)
+		testutil.CreateTestAccountShare(t, db, owner.ID, partner.ID, models.ShareRoleViewer)
+		testutil.CreateTestAccountShare(t, db, owner.ID, partner.ID, models.ShareRoleEditor, account.ID)
+
+		_, err := acctSvc.GetWritableAccount(models.UserID(partner.ID), models.AccountID(account.ID))
+		testutil.AssertNoError(t, err)
+	})
+
+	t.Run("stranger_sees_nothing", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		owner := testutil.CreateTestUser(t, db)
+		partner := testutil.CreateTestUser(t, db)
+		stranger := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, owner.ID)
+		tx := testutil.CreateTestTransaction(t, db, owner.ID, account.ID, models.TransactionTypeExpense, 100)
+		testutil.CreateTestAccountShare(t, db, owner.ID, partner.ID, models.ShareRoleEditor)
+
+		_, err := acctSvc.GetAccountByID(models.UserID(stranger.ID), models.AccountID(account.ID))
+		testutil.AssertAppError(t, err, "ACCOUNT_NOT_FOUND")
+		_, err = txSvc.GetTransactionByID(models.UserID(stranger.ID), models.TransactionID(tx.ID))
+		testutil.AssertAppError(t, err, "TRANSACTION_NOT_FOUND")
+		_, err = txSvc.CreateTransaction(models.UserID(stranger.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 100, "", time.Now(), false, "", nil)
+		testutil.AssertAppError(t, err, "ACCOUNT_NOT_FOUND")
+	})
+
+	t.Run("portfolio_includes_shared_investments", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		invSvc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		owner := testutil.CreateTestUser(t, db)
+		viewer := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestInvestmentAccount(t, db, owner.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, account.ID, sec.ID)
+		testutil.CreateTestSecurityPrice(t, db, sec.ID, 12000, time.Now())
+		testutil.CreateTestAccountShare(t, db, owner.ID, viewer.ID, models.ShareRoleViewer, account.ID)
+
+		portfolio, err := invSvc.GetPortfolio(context.Background(), models.UserID(viewer.ID))
+		testutil.AssertNoError(t, err)
+		if portfolio.TotalValue != 120000 {
+			t.Errorf("expected total value 120000, got %d", portfolio.TotalValue)
+		}
+
+		_, err = invSvc.GetInvestmentByID(models.UserID(viewer.ID), models.InvestmentID(inv.ID))
+		testutil.AssertNoError(t, err)
+		_, err = invSvc.RecordBuy(models.UserID(viewer.ID), models.InvestmentID(inv.ID), time.Now(), 1, 12000, 0, "", "", false)
+		testutil.AssertAppError(t, err, "SHARE_READ_ONLY")
+		_, _, err = invSvc.AddInvestment(models.UserID(viewer.ID), models.AccountID(account.ID), SecurityRef{ID: sec.ID}, 1, 12000, "", nil, 0, "", "", InvestmentMetadata{}, false)
+		testutil.AssertAppError(t, err, "SHARE_READ_ONLY")
+
+		stranger := testutil.CreateTestUser(t, db)
+		_, err = invSvc.GetInvestmentByID(models.UserID(stranger.ID), models.InvestmentID(uuid.New()))
+		testutil.AssertAppError(t, err, "INVESTMENT_NOT_FOUND")
+		_, err = invSvc.GetInvestmentByID(models.UserID(stranger.ID), models.InvestmentID(inv.ID))
+		testutil.AssertAppError(t, err, "INVESTMENT_NOT_FOUND")
+	})
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"gorm.io/gorm"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+)
+
+// statementTimeout bounds how long all sections of a statement may take together.
+const statementTimeout = 10 * time.Second
+
+// statementTopCategories is how many expense categories a statement lists.
+const statementTopCategories = 5
+
+// statementService composes monthly statements from the report queries.
+type statementService struct {
+	db *gorm.DB
+}
+
+// NewStatementService creates a new StatementServicer.
+func NewStatementService(db *gorm.DB) StatementServicer {
+	return &statementService{db: db}
+}
+
+// GetMonthlyStatement builds the user's statement for the calendar month (UTC)
+// containing month. Each section is computed concurrently under a shared
+// deadline; the first failure cancels the rest. Accounts excluded from reports
+// are left out, as in the other reports.
+func (s *statementService) GetMonthlyStatement(ctx context.Context, userID string, month time.Time) (*MonthlyStatement, error) {
+	start := monthStart(month)
+	end := start.AddDate(0, 1, 0).Add(-time.Nanosecond)
+
+	ctx, cancel := context.WithTimeout(ctx, statementTimeout)
+	defer cancel()
+	g, gctx := errgroup.WithContext(ctx)
+	db := s.db.WithContext(gctx)
+
+	statement := &MonthlyStatement{Month: start.Format("2006-01")}
+
+	g.Go(func() error {
+		accounts, err := statementAccounts(db, userID, start, end)
+		statement.Accounts = accounts
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		statement.Income, statement.Expenses, err = incomeAndExpenses(db, userID, start, end, false)
+		return err
+	})
+	g.Go(func() error {
+		reports := &transactionService{db: db, clock: SystemClock}
+		spending, err := reports.GetSpendingByCategory(gctx, models.UserID(userID), start, end, SpendingModeExpense, false)
+		if err != nil {
+			return err
+		}
+		statement.TopCategories = spending.Items
+		if len(statement.TopCategories) > statementTopCategories {
+			statement.TopCategories = statement.TopCategories[:statementTopCategories]
+		}
+		return nil
+	})
+	g.Go(func() error {
+		budgets, err := statementBudgets(db, userID, start, end)
+		statement.Budgets = budgets
+		return err
+	})
+	g.Go(func() error {
+		investments, err := statementInvestments(db, userID, start.Add(-time.Nanosecond), end)
+		statement.Investments = investments
+		return err
+	})
+	g.Go(func() error {
+		if err := db.Model(&models.Transaction{}).
+			Where("user_id = ? AND deleted_at IS NULL AND date BETWEEN ? AND ?", userID, start, end).
+			Scopes(reportableTransactions(false)).
+			Count(&statement.TransactionCount).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	statement.Net = statement.Income - statement.Expenses
+	return statement, nil
+}
+
+// statementAccounts returns the opening and closing balances of the user's active
+// non-investment accounts, rebuilt from the settled transactions dated after start.
+// Accounts opened after the month are left out.
+// The cleared balance also leaves out transactions up to end that have not
+// cleared with the bank.
+func statementAccounts(db *gorm.DB, userID string, start, end time.Time) ([]StatementAccount, error) {
+	var accounts []models.Account
+	if err := db.Where("user_id = ? AND is_active = ? AND exclude_from_reports = ? AND type <> ?",
+		userID, true, false, models.AccountTypeInvestment).
+		Order("name ASC").
+		Find(&accounts).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if len(accounts) == 0 {
+		return []StatementAccount{}, nil
+	}
+
+	accountIDs := make([]string, 0, len(accounts))
+	for i := range accounts {
+		accountIDs = append(accountIDs, accounts[i].ID)
+	}
+	opening := start.Add(-time.Nanosecond)
+	var transactions []models.Transaction
+	if err := db.Where("(account_id IN ? OR to_account_id IN ?) AND date > ? AND is_pending = ?", accountIDs, accountIDs, opening, false).
+		Find(&transactions).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	var uncleared []models.Transaction
+	if err := db.Where("(account_id IN ? OR to_account_id IN ?) AND date <= ? AND is_pending = ? AND status = ?",
+		accountIDs, accountIDs, end, false, models.TransactionStatusPending).
+		Find(&uncleared).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	openings, err := accountOpenings(db, accounts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]StatementAccount, 0, len(accounts))
+	for i := range accounts {
+		if openings[accounts[i].ID].After(end) {
+			continue
+		}
+		closing := balanceAt(&accounts[i], transactions, end)
+		cleared := closing
+		for j := range uncleared {
+			cleared -= balanceDelta(&accounts[i], &uncleared[j])
+		}
+		result = append(result, StatementAccount{
+			AccountID:      accounts[i].ID,
+			Name:           accounts[i].Name,
+			Type:           accounts[i].Type,
+			Currency:       accounts[i].Currency,
+			OpeningBalance: balanceAt(&accounts[i], transactions, opening),
+			ClosingBalance: closing,
+			ClearedBalance: cleared,
+		})
+	}
+	return result, nil
+}
+
+// statementBudgets returns the standing at end of every active budget that
+// overlaps the month, counting settled spending from the start of its period
+// or, if later, the budget's start date.
+func statementBudgets(db *gorm.DB, userID string, start, end time.Time) ([]StatementBudget, error) {
+	var budgets []models.Budget
+	if err := db.Preload("Category").
+		Where("user_id = ? AND is_active = ? AND start_date <= ? AND (end_date IS NULL OR end_date >= ?)", userID, true, end, start).
+		Order("name ASC").
+		Find(&budgets).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	result := make([]StatementBudget, 0, len(budgets))
+	for i := range budgets {
+		periodStart, periodEnd := budgetWindow(&budgets[i], end)
+		if periodEnd.After(end) {
+			periodEnd = end
+		}
+		spent, err := categorySpend(db, userID, budgets[i].CategoryID, periodStart, periodEnd, false)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, StatementBudget{
+			BudgetProgress: *newBudgetProgress(&budgets[i], spent, periodStart, periodEnd, end),
+			Name:           budgets[i].Name,
+			CategoryName:   budgets[i].Category.Name,
+			Period:         budgets[i].Period,
+			Exceeded:       spent > budgets[i].Amount,
+		})
+	}
+	return result, nil
+}
+
+// statementInvestments values the user's holdings at opening and closing, using the
+// quantity held and the latest price recorded on or before each instant.
+func statementInvestments(db *gorm.DB, userID string, opening, closing time.Time) (StatementInvestments, error) {
+	var result StatementInvestments
+
+	var investments []models.Investment
+	if err := db.Joins("JOIN accounts ON accounts.id = investments.account_id").
+		Where("accounts.user_id = ? AND accounts.type = ? AND accounts.is_active = ? AND accounts.exclude_from_reports = ? AND accounts.deleted_at IS NULL",
+			userID, models.AccountTypeInvestment, true, false).
+		Find(&investments).Error; err != nil {
+		return result, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if len(investments) == 0 {
+		return result, nil
+	}
+
+	investmentIDs := make([]string, 0, len(investments))
+	secIDs := make([]string, 0, len(investments))
+	for i := range investments {
+		investmentIDs = append(investmentIDs, investments[i].ID)
+		secIDs = append(secIDs, investments[i].SecurityID)
+	}
+	var transactions []models.InvestmentTransaction
+	if err := db.Where("investment_id IN ? AND date > ? AND type IN ?", investmentIDs, opening,
+		[]models.InvestmentTransactionType{models.InvestmentTransactionBuy, models.InvestmentTransactionSell, models.InvestmentTransactionSplit}).
+		Order("date DESC").
+		Find(&transactions).Error; err != nil {
+		return result, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	valueAt := func(at time.Time) (int64, error) {
+		prices, err := queryPriceQuotesAsOf(db, secIDs, at)
+		if err != nil {
+			return 0, err
+		}
+		var value int64
+		for i := range investments {
+			value += int64(quantityAt(&investments[i], transactions, at) * float64(prices[investments[i].SecurityID].Price))
+		}
+		return value, nil
+	}
+
+	var err error
+	if result.OpeningValue, err = valueAt(opening); err != nil {
+		return result, err
+	}
+	if result.ClosingValue, err = valueAt(closing); err != nil {
+		return result, err
+	}
+	result.Change = result.ClosingValue - result.OpeningValue
+	return result, nil
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"kuberan/internal/models"
+	"kuberan/internal/testutil"
+)
+
+func TestGetMonthlyStatement(t *testing.T) {
+	t.Parallel()
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 12, 0, 0, 0, time.UTC)
+	}
+	addTx := func(t *testing.T, db *gorm.DB, userID, accountID string, txType models.TransactionType, amount int64, date time.Time, categoryID *string) {
+		t.Helper()
+		tx := &models.Transaction{
+			UserID:     userID,
+			AccountID:  accountID,
+			CategoryID: categoryID,
+			Type:       txType,
+			Amount:     amount,
+			Date:       date,
+		}
+		if err := db.Create(tx).Error; err != nil {
+			t.Fatalf("failed to create transaction: %v", err)
+		}
+	}
+	namedCategory := func(t *testing.T, db *gorm.DB, userID, name string) *models.Category {
+		t.Helper()
+		cat := testutil.CreateTestCategory(t, db, userID, models.CategoryTypeExpense)
+		db.Model(cat).Update("name", name)
+		cat.Name = name
+		return cat
+	}
+	addBudget := func(t *testing.T, db *gorm.DB, userID, categoryID, name string, amount int64, period models.BudgetPeriod, start time.Time) {
+		t.Helper()
+		budget := &models.Budget{
+			UserID:     userID,
+			CategoryID: categoryID,
+			Name:       name,
+			Amount:     amount,
+			Period:     period,
+			StartDate:  start,
+			IsActive:   true,
+		}
+		if err := db.Create(budget).Error; err != nil {
+			t.Fatalf("failed to create budget: %v", err)
+		}
+	}
+
+	t.Run("fixture_month", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewStatementService(db)
+		user := testutil.CreateTestUser(t, db)
+
+		// Checking ends October at 280000: 98000 opening + 300000 income - 118000 expenses.
+		// A 30000 November expense brings it to today's 250000.
+		checking := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 250000)
+		groceries := namedCategory(t, db, user.ID, "Groceries")
+		dining := namedCategory(t, db, user.ID, "Dining")
+		transport := namedCategory(t, db, user.ID, "Transport")
+		utilities := namedCategory(t, db, user.ID, "Utilities")
+		fun := namedCategory(t, db, user.ID, "Fun")
+		misc := namedCategory(t, db, user.ID, "Misc")
+
+		addTx(t, db, user.ID, checking.ID, models.TransactionTypeExpense, 40000, day(2025, 3, 10), &transport.ID)
+		addTx(t, db, user.ID, checking.ID, models.TransactionTypeIncome, 300000, day(2025, 10, 1), nil)
+		addTx(t, db, user.ID, checking.ID, models.TransactionTypeExpense, 50000, day(2025, 10, 5), &groceries.ID)
+		addTx(t, db, user.ID, checking.ID, models.TransactionTypeExpense, 20000, day(2025, 10, 12), &dining.ID)
+		addTx(t, db, user.ID, checking.ID, models.TransactionTypeExpense, 10000, day(2025, 10, 15), &groceries.ID)
+		addTx(t, db, user.ID, checking.ID, models.TransactionTypeExpense, 15000, day(2025, 10, 18), &transport.ID)
+		addTx(t, db, user.ID, checking.ID, models.TransactionTypeExpense, 12000, day(2025, 10, 22), &utilities.ID)
+		addTx(t, db, user.ID, checking.ID, models.TransactionTypeExpense, 8000, day(2025, 10, 25), &fun.ID)
+		addTx(t, db, user.ID, checking.ID, models.TransactionTypeExpense, 3000, day(2025, 10, 31), &misc.ID)
+		addTx(t, db, user.ID, checking.ID, models.TransactionTypeExpense, 30000, day(2025, 11, 3), &groceries.ID)
+
+		// Excluded accounts stay out of balances, totals, categories and the count
+		hidden := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 0)
+		db.Model(hidden).Update("exclude_from_reports", true)
+		hiddenCat := namedCategory(t, db, user.ID, "Hidden")
+		addTx(t, db, user.ID, hidden.ID, models.TransactionTypeExpense, 77777, day(2025, 10, 9), &hiddenCat.ID)
+
+		addBudget(t, db, user.ID, groceries.ID, "Food", 50000, models.BudgetPeriodMonthly, day(2025, 10, 1))
+		addBudget(t, db, user.ID, dining.ID, "Eating out", 30000, models.BudgetPeriodMonthly, day(2025, 10, 1))
+		addBudget(t, db, user.ID, transport.ID, "Getting around", 100000, models.BudgetPeriodYearly, day(2025, 1, 1))
+		addBudget(t, db, user.ID, fun.ID, "Next year", 1000, models.BudgetPeriodMonthly, day(2025, 12, 1))
+
+		// 10 shares today, 4 bought mid-October; priced 1000 before the month and 1500 at its end
+		invAccount := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		sec := testutil.CreateTestSecurity(t, db)
+		inv := testutil.CreateTestInvestment(t, db, invAccount.ID, sec.ID)
+		db.Create(&models.InvestmentTransaction{InvestmentID: inv.ID, Type: models.InvestmentTransactionBuy,
+			Date: day(2025, 10, 10), Quantity: 4, PricePerUnit: 1200, TotalAmount: 4800})
+		testutil.CreateTestSecurityPrice(t, db, sec.ID, 1000, day(2025, 9, 25))
+		testutil.CreateTestSecurityPrice(t, db, sec.ID, 1500, day(2025, 10, 28))
+		testutil.CreateTestSecurityPrice(t, db, sec.ID, 2000, day(2025, 11, 5))
+
+		// Another user's activity in the same month
+		other := testutil.CreateTestUser(t, db)
+		otherAccount := testutil.CreateTestCashAccountWithBalance(t, db, other.ID, 1000)
+		otherCat := namedCategory(t, db, other.ID, "Elsewhere")
+		addTx(t, db, other.ID, otherAccount.ID, models.TransactionTypeExpense, 99999, day(2025, 10, 14), &otherCat.ID)
+		otherInvAccount := testutil.CreateTestInvestmentAccount(t, db, other.ID)
+		testutil.CreateTestInvestment(t, db, otherInvAccount.ID, sec.ID)
+
+		statement, err := svc.GetMonthlyStatement(context.Background(), user.ID, day(2025, 10, 17))
+		testutil.AssertNoError(t, err)
+
+		if statement.Month != "2025-10" {
+			t.Errorf("expected month 2025-10, got %s", statement.Month)
+		}
+
+		if len(statement.Accounts) != 1 {
+			t.Fatalf("expected 1 account, got %d", len(statement.Accounts))
+		}
+		acct := statement.Accounts[0]
+		if acct.AccountID != checking.ID || acct.OpeningBalance != 98000 || acct.ClosingBalance != 280000 {
+			t.Errorf("expected checking 98000 -> 280000, got %+v", acct)
+		}
+
+		if statement.Income != 300000 || statement.Expenses != 118000 || statement.Net != 182000 {
+			t.Errorf("expected income 300000, expenses 118000, net 182000; got %d, %d, %d",
+				statement.Income, statement.Expenses, statement.Net)
+		}
+
+		wantTop := []struct {
+			name  string
+			total int64
+		}{{"Groceries", 60000}, {"Dining", 20000}, {"Transport", 15000}, {"Utilities", 12000}, {"Fun", 8000}}
+		if len(statement.TopCategories) != len(wantTop) {
+			t.Fatalf("expected %d top categories, got %d", len(wantTop), len(statement.TopCategories))
+		}
+		for i, want := range wantTop {
+			got := statement.TopCategories[i]
+			if got.CategoryName != want.name || got.Total != want.total {
+				t.Errorf("top category %d: expected %s %d, got %s %d", i, want.name, want.total, got.CategoryName, got.Total)
+			}
+		}
+
+		if len(statement.Budgets) != 3 {
+			t.Fatalf("expected 3 budgets, got %d", len(statement.Budgets))
+		}
+		budgets := make(map[string]StatementBudget)
+		for _, b := range statement.Budgets {
+			budgets[b.Name] = b
+		}
+		if b := budgets["Food"]; b.Spent != 60000 || b.Remaining != -10000 || !b.Exceeded || b.CategoryName != "Groceries" {
+			t.Errorf("unexpected Food budget %+v", b)
+		}
+		if b := budgets["Eating out"]; b.Spent != 20000 || b.Exceeded {
+			t.Errorf("unexpected Eating out budget %+v", b)
+		}
+		if b := budgets["Getting around"]; b.Spent != 55000 || b.Period != models.BudgetPeriodYearly {
+			t.Errorf("expected yearly budget to count spending since January, got %+v", b)
+		}
+
+		if statement.Investments.OpeningValue != 6000 || statement.Investments.ClosingValue != 15000 || statement.Investments.Change != 9000 {
+			t.Errorf("expected investments 6000 -> 15000 (+9000), got %+v", statement.Investments)
+		}
+
+		if statement.TransactionCount != 8 {
+			t.Errorf("expected 8 transactions, got %d", statement.TransactionCount)
+		}
+	})
+
+	t.Run("cleared_balance", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewStatementService(db)
+		user := testutil.CreateTestUser(t, db)
+
+		// 50000 today after a 10000 uncleared October expense and a 5000
+		// uncleared November deposit
+		checking := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 50000)
+		for _, tx := range []*models.Transaction{
+			{UserID: user.ID, AccountID: checking.ID, Type: models.TransactionTypeExpense, Amount: 10000, Date: day(2025, 10, 20), Status: models.TransactionStatusPending},
+			{UserID: user.ID, AccountID: checking.ID, Type: models.TransactionTypeIncome, Amount: 5000, Date: day(2025, 11, 2), Status: models.TransactionStatusPending},
+		} {
+			if err := db.Create(tx).Error; err != nil {
+				t.Fatalf("failed to create transaction: %v", err)
+			}
+		}
+
+		statement, err := svc.GetMonthlyStatement(context.Background(), user.ID, day(2025, 10, 1))
+		testutil.AssertNoError(t, err)
+		acct := statement.Accounts[0]
+		if acct.ClosingBalance != 45000 || acct.ClearedBalance != 55000 {
+			t.Errorf("expected closing 45000 and cleared 55000, got %d and %d", acct.ClosingBalance, acct.ClearedBalance)
+		}
+	})
+
+	t.Run("leaves_out_accounts_created_later", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewStatementService(db)
+		user := testutil.CreateTestUser(t, db)
+		testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 50000)
+
+		// Without an opening date the account exists from the day it was created
+		statement, err := svc.GetMonthlyStatement(context.Background(), user.ID, day(2025, 10, 1))
+		testutil.AssertNoError(t, err)
+		if len(statement.Accounts) != 0 {
+			t.Errorf("expected no accounts before the account was created, got %+v", statement.Accounts)
+		}
+	})
+
+	t.Run("leaves_out_accounts_opened_later", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewStatementService(db)
+		user := testutil.CreateTestUser(t, db)
+
+		checking := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 50000)
+		testutil.AssertNoError(t, db.Model(checking).Update("opening_date", day(2025, 1, 1)).Error)
+		opened := day(2025, 11, 5)
+		_, err := NewAccountService(db, nil).CreateCashAccount(models.UserID(user.ID), "New Savings", "", "USD", 20000, true, 0, &opened)
+		testutil.AssertNoError(t, err)
+
+		statement, err := svc.GetMonthlyStatement(context.Background(), user.ID, day(2025, 10, 1))
+		testutil.AssertNoError(t, err)
+		if len(statement.Accounts) != 1 || statement.Accounts[0].AccountID != checking.ID {
+			t.Errorf("expected only the account open in October, got %+v", statement.Accounts)
+		}
+
+		statement, err = svc.GetMonthlyStatement(context.Background(), user.ID, day(2025, 11, 1))
+		testutil.AssertNoError(t, err)
+		if len(statement.Accounts) != 2 {
+			t.Errorf("expected both accounts in November, got %+v", statement.Accounts)
+		}
+	})
+
+	t.Run("empty_month", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewStatementService(db)
+		user := testutil.CreateTestUser(t, db)
+
+		statement, err := svc.GetMonthlyStatement(context.Background(), user.ID, day(2025, 10, 1))
+		testutil.AssertNoError(t, err)
+
+		if statement.Accounts == nil || statement.Budgets == nil || statement.TopCategories == nil {
+			t.Error("expected empty sections to be non-nil slices")
+		}
+		if statement.Income != 0 || statement.Expenses != 0 || statement.TransactionCount != 0 || statement.Investments.Change != 0 {
+			t.Errorf("expected zero totals, got %+v", statement)
+		}
+	})
+
+	t.Run("cancelled_context", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewStatementService(db)
+		user := testutil.CreateTestUser(t, db)
+		testutil.CreateTestCashAccount(t, db, user.ID)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := svc.GetMonthlyStatement(ctx, user.ID, day(2025, 10, 1))
+		testutil.AssertAppError(t, err, "INTERNAL_ERROR")
+	})
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+
+	apperrors "kuberan/internal/errors"
+	"kuberan/internal/models"
+)
+
+// statsService computes usage statistics with grouped queries over transactions.
+type statsService struct {
+	db *gorm.DB
+}
+
+// NewStatsService creates a new StatsServicer.
+func NewStatsService(db *gorm.DB) StatsServicer {
+	return &statsService{db: db}
+}
+
+// withContext returns a copy of s whose queries run with ctx.
+func (s *statsService) withContext(ctx context.Context) *statsService {
+	return &statsService{db: s.db.WithContext(ctx)}
+}
+
+// UserLocation returns the time zone the user's date-only ranges are read in.
+func (s *statsService) UserLocation(userID models.UserID) (*time.Location, error) {
+	return userLocation(s.db, SystemClock, string(userID))
+}
+
+// GetAccountStats returns usage stats for each of the user's accounts, ordered by
+// name. A transfer counts as activity on both of its accounts; the funding of
+// an investment buy only on the account that paid.
+func (s *statsService) GetAccountStats(ctx context.Context, userID models.UserID, from, to *time.Time) ([]AccountUsageStats, error) {
+	s = s.withContext(ctx)
+	var accounts []models.Account
+	if err := s.db.Where("user_id = ?", string(userID)).Order("name ASC").Find(&accounts).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	result := make([]AccountUsageStats, 0, len(accounts))
+	if len(accounts) == 0 {
+		return result, nil
+	}
+
+	accountIDs := make([]string, 0, len(accounts))
+	for i := range accounts {
+		accountIDs = append(accountIDs, accounts[i].ID)
+	}
+	recorded := s.db.Model(&models.Transaction{}).
+		Select("account_id AS group_id, amount, date").
+		Where("account_id IN ?", accountIDs).
+		Scopes(datedBetween(from, to))
+	received := s.db.Model(&models.Transaction{}).
+		Select("to_account_id AS group_id, amount, date").
+		Where("to_account_id IN ? AND type = ?", accountIDs, models.TransactionTypeTransfer).
+		Scopes(datedBetween(from, to))
+
+	stats, err := s.usageStats(s.db.Raw("? UNION ALL ?", recorded, received))
+	if err != nil {
+		return nil, err
+	}
+	for i := range accounts {
+		result = append(result, AccountUsageStats{
+			AccountID:  accounts[i].ID,
+			Name:       accounts[i].Name,
+			Type:       accounts[i].Type,
+			Currency:   accounts[i].Currency,
+			IsActive:   accounts[i].IsActive,
+			UsageStats: stats[accounts[i].ID],
+		})
+	}
+	return result, nil
+}
+
+// GetCategoryStats returns usage stats for each of the user's categories,
+// ordered by name. Uncategorized transactions are not counted.
+func (s *statsService) GetCategoryStats(ctx context.Context, userID models.UserID, from, to *time.Time) ([]CategoryUsageStats, error) {
+	s = s.withContext(ctx)
+	var categories []models.Category
+	if err := s.db.Where("user_id = ?", string(userID)).Order("name ASC").Find(&categories).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	result := make([]CategoryUsageStats, 0, len(categories))
+	if len(categories) == 0 {
+		return result, nil
+	}
+
+	categorized := s.db.Model(&models.Transaction{}).
+		Select("category_id AS group_id, amount, date").
+		Where("user_id = ? AND category_id IS NOT NULL", string(userID)).
+		Scopes(datedBetween(from, to))
+
+	stats, err := s.usageStats(categorized)
+	if err != nil {
+		return nil, err
+	}
+	for i := range categories {
+		result = append(result, CategoryUsageStats{
+			CategoryID: categories[i].ID,
+			Name:       categories[i].Name,
+			Type:       categories[i].Type,
+			UsageStats: stats[categories[i].ID],
+		})
+	}
+	return result, nil
+}
+
+// usageStats aggregates activity, a query of group_id, amount and date rows,
+// into stats per group_id. Groups without rows are absent from the map.
+func (s *statsService) usageStats(activity *gorm.DB) (map[string]UsageStats, error) {
+	type groupTotals struct {
+		GroupID   string
+		Count     int64
+		Total     int64
+		FirstDate aggregateTime
+		LastDate  aggregateTime
+	}
+	var totals []groupTotals
+	if err := s.db.Table("(?) AS activity", activity).
+		Select("group_id, COUNT(*) AS count, COALESCE(SUM(amount), 0) AS total, MIN(date) AS first_date, MAX(date) AS last_date").
+		Group("group_id").
+		Scan(&totals).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	type groupWeekday struct {
+		GroupID string
+		Weekday int
+		Count   int64
+	}
+	var weekdays []groupWeekday
+	if err := s.db.Table("(?) AS activity", activity).
+		Select(fmt.Sprintf("group_id, %s AS weekday, COUNT(*) AS count", weekdayExpr(s.db))).
+		Group("group_id, weekday").
+		Scan(&weekdays).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	busiest := make(map[string]groupWeekday, len(totals))
+	for _, w := range weekdays {
+		best, ok := busiest[w.GroupID]
+		if !ok || w.Count > best.Count || (w.Count == best.Count && w.Weekday < best.Weekday) {
+			busiest[w.GroupID] = w
+		}
+	}
+
+	stats := make(map[string]UsageStats, len(totals))
+	for _, t := range totals {
+		usage := UsageStats{
+			TransactionCount: t.Count,
+			FirstActivity:    t.FirstDate.ptr(),
+			LastActivity:     t.LastDate.ptr(),
+		}
+		if t.Count > 0 {
+			usage.AverageAmount = int64(math.Round(float64(t.Total) / float64(t.Count)))
+		}
+		if w, ok := busiest[t.GroupID]; ok {
+			day := time.Weekday(w.Weekday).String()
+			usage.BusiestWeekday = &day
+		}
+		stats[t.GroupID] = usage
+	}
+	return stats, nil
+}
+
+// datedBetween scopes a transaction query to dates between from and to inclusive,
+// leaving a nil end open.
+func datedBetween(from, to *time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if from != nil {
+			db = db.Where("date >= ?", *from)
+		}
+		if to != nil {
+			db = db.Where("date <= ?", *to)
+		}
+		return db
+	}
+}
+
+// weekdayExpr is the SQL for the UTC day of the week of the date column,
+// 0 for Sunday as in time.Weekday.
+func weekdayExpr(db *gorm.DB) string {
+	if db.Dialector.Name() == "sqlite" {
+		return "CAST(strftime('%w', date) AS INTEGER)"
+	}
+	return "CAST(EXTRACT(DOW FROM date AT TIME ZONE 'UTC') AS INTEGER)"
+}
+
+// aggregateTime scans a timestamp produced by MIN or MAX. The aggregate loses
+// the column type, so SQLite returns it as text rather than a time.
+type aggregateTime struct {
+	Time  time.Time
+	Valid bool
+}
+
+// sqliteTimeFormat is how the SQLite driver stores time values.
+const sqliteTimeFormat = "2006-01-02 15:04:05.999999999-07:00"
+
+// Scan implements sql.Scanner.
+func (t *aggregateTime) Scan(value interface{}) error {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		t.Time, t.Valid = time.Time{}, false
+		return nil
+	case time.Time:
+		t.Time, t.Valid = v, true
+		return nil
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into a time", value)
+	}
+	for _, layout := range []string{sqliteTimeFormat, time.RFC3339Nano} {
+		if parsed, err := time.Parse(layout, text); err == nil {
+			t.Time, t.Valid = parsed.UTC(), true
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot parse %q as a time", text)
+}
+
+// Value implements driver.Valuer, which GORM requires alongside Scan.
+func (t aggregateTime) Value() (driver.Value, error) {
+	if !t.Valid {
+		return nil, nil
+	}
+	return t.Time, nil
+}
+
+// ptr returns the time, or nil when it was NULL.
+func (t aggregateTime) ptr() *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"kuberan/internal/models"
+	"kuberan/internal/testutil"
+)
+
+func TestGetAccountStats(t *testing.T) {
+	t.Parallel()
+	day := func(d int) time.Time {
+		// June 2025 starts on a Sunday
+		return time.Date(2025, time.June, d, 12, 0, 0, 0, time.UTC)
+	}
+	addTx := func(t *testing.T, db *gorm.DB, tx models.Transaction) {
+		t.Helper()
+		if err := db.Create(&tx).Error; err != nil {
+			t.Fatalf("failed to create transaction: %v", err)
+		}
+	}
+
+	t.Run("busiest_day_over_a_known_week", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewStatsService(db)
+		user := testutil.CreateTestUser(t, db)
+		checking := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		// Sunday 1, Tuesday 3 (x3), Wednesday 4, Friday 6 (x3), Saturday 7
+		for _, d := range []struct {
+			day    int
+			amount int64
+		}{{1, 1000}, {3, 2000}, {3, 3000}, {3, 1000}, {4, 500}, {6, 700}, {6, 800}, {6, 900}, {7, 100}} {
+			addTx(t, db, models.Transaction{UserID: user.ID, AccountID: checking.ID, Type: models.TransactionTypeExpense, Amount: d.amount, Date: day(d.day)})
+		}
+
+		stats, err := svc.GetAccountStats(context.Background(), models.UserID(user.ID), nil, nil)
+		testutil.AssertNoError(t, err)
+		if len(stats) != 1 {
+			t.Fatalf("expected 1 account, got %d", len(stats))
+		}
+		got := stats[0]
+		if got.TransactionCount != 9 {
+			t.Errorf("expected 9 transactions, got %d", got.TransactionCount)
+		}
+		// Tuesday and Friday tie at three; the earlier day wins
+		if got.BusiestWeekday == nil || *got.BusiestWeekday != "Tuesday" {
+			t.Errorf("expected busiest day Tuesday, got %v", got.BusiestWeekday)
+		}
+		if got.AverageAmount != 1111 {
+			t.Errorf("expected average 1111, got %d", got.AverageAmount)
+		}
+		if got.FirstActivity == nil || !got.FirstActivity.Equal(day(1)) {
+			t.Errorf("expected first activity %v, got %v", day(1), got.FirstActivity)
+		}
+		if got.LastActivity == nil || !got.LastActivity.Equal(day(7)) {
+			t.Errorf("expected last activity %v, got %v", day(7), got.LastActivity)
+		}
+
+		// Without Tuesday the range leaves Friday the busiest
+		from, to := day(4), day(7)
+		stats, err = svc.GetAccountStats(context.Background(), models.UserID(user.ID), &from, &to)
+		testutil.AssertNoError(t, err)
+		if stats[0].TransactionCount != 5 || stats[0].BusiestWeekday == nil || *stats[0].BusiestWeekday != "Friday" {
+			t.Errorf("expected 5 transactions busiest on Friday, got %d on %v", stats[0].TransactionCount, stats[0].BusiestWeekday)
+		}
+	})
+
+	t.Run("transfers_count_on_both_accounts", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewStatsService(db)
+		user := testutil.CreateTestUser(t, db)
+		checking := testutil.CreateTestCashAccount(t, db, user.ID)
+		savings := testutil.CreateTestCashAccount(t, db, user.ID)
+		db.Model(savings).Update("name", "Savings")
+		addTx(t, db, models.Transaction{UserID: user.ID, AccountID: checking.ID, ToAccountID: &savings.ID, Type: models.TransactionTypeTransfer, Amount: 5000, Date: day(2)})
+
+		stats, err := svc.GetAccountStats(context.Background(), models.UserID(user.ID), nil, nil)
+		testutil.AssertNoError(t, err)
+		for _, s := range stats {
+			if s.TransactionCount != 1 || s.AverageAmount != 5000 {
+				t.Errorf("expected %s to count the transfer, got %+v", s.Name, s.UsageStats)
+			}
+		}
+	})
+
+	t.Run("lists_idle_accounts_with_zero_stats", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewStatsService(db)
+		user := testutil.CreateTestUser(t, db)
+		idle := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		stats, err := svc.GetAccountStats(context.Background(), models.UserID(user.ID), nil, nil)
+		testutil.AssertNoError(t, err)
+		if len(stats) != 1 || stats[0].AccountID != idle.ID {
+			t.Fatalf("expected the idle account, got %+v", stats)
+		}
+		if stats[0].TransactionCount != 0 || stats[0].FirstActivity != nil || stats[0].BusiestWeekday != nil {
+			t.Errorf("expected zero stats, got %+v", stats[0].UsageStats)
+		}
+	})
+
+	t.Run("isolates_users", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewStatsService(db)
+		user := testutil.CreateTestUser(t, db)
+		testutil.CreateTestCashAccount(t, db, user.ID)
+		other := testutil.CreateTestUser(t, db)
+		otherAccount := testutil.CreateTestCashAccount(t, db, other.ID)
+		addTx(t, db, models.Transaction{UserID: other.ID, AccountID: otherAccount.ID, Type: models.TransactionTypeExpense, Amount: 9999, Date: day(2)})
+
+		stats, err := svc.GetAccountStats(context.Background(), models.UserID(user.ID), nil, nil)
+		testutil.AssertNoError(t, err)
+		if len(stats) != 1 || stats[0].AccountID == otherAccount.ID || stats[0].TransactionCount != 0 {
+			t.Errorf("expected only the user's idle account, got %+v", stats)
+		}
+	})
+}
+
+func TestGetCategoryStats(t *testing.T) {
+	t.Parallel()
+	day := func(d int) time.Time {
+		return time.Date(2025, time.June, d, 12, 0, 0, 0, time.UTC)
+	}
+
+	t.Run("groups_by_category_and_lists_idle_ones", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewStatsService(db)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, user.ID)
+		groceries := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		db.Model(groceries).Update("name", "Groceries")
+		salary := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeIncome)
+		db.Model(salary).Update("name", "Salary")
+
+		for _, tx := range []models.Transaction{
+			{UserID: user.ID, AccountID: account.ID, CategoryID: &groceries.ID, Type: models.TransactionTypeExpense, Amount: 3000, Date: day(2)},
+			{UserID: user.ID, AccountID: account.ID, CategoryID: &groceries.ID, Type: models.TransactionTypeExpense, Amount: 5000, Date: day(9)},
+			{UserID: user.ID, AccountID: account.ID, Type: models.TransactionTypeExpense, Amount: 700, Date: day(9)},
+		} {
+			testutil.AssertNoError(t, db.Create(&tx).Error)
+		}
+
+		other := testutil.CreateTestUser(t, db)
+		otherAccount := testutil.CreateTestCashAccount(t, db, other.ID)
+		otherCategory := testutil.CreateTestCategory(t, db, other.ID, models.CategoryTypeExpense)
+		testutil.AssertNoError(t, db.Create(&models.Transaction{UserID: other.ID, AccountID: otherAccount.ID, CategoryID: &otherCategory.ID,
+			Type: models.TransactionTypeExpense, Amount: 9999, Date: day(2)}).Error)
+
+		stats, err := svc.GetCategoryStats(context.Background(), models.UserID(user.ID), nil, nil)
+		testutil.AssertNoError(t, err)
+		if len(stats) != 2 {
+			t.Fatalf("expected the user's 2 categories, got %d", len(stats))
+		}
+		if stats[0].CategoryID != groceries.ID || stats[0].TransactionCount != 2 || stats[0].AverageAmount != 4000 {
+			t.Errorf("expected groceries with 2 transactions averaging 4000, got %+v", stats[0])
+		}
+		if stats[0].BusiestWeekday == nil || *stats[0].BusiestWeekday != "Monday" {
+			t.Errorf("expected groceries busiest on Monday, got %v", stats[0].BusiestWeekday)
+		}
+		if stats[1].CategoryID != salary.ID || stats[1].TransactionCount != 0 || stats[1].LastActivity != nil {
+			t.Errorf("expected salary with zero stats, got %+v", stats[1])
+		}
+	})
+}
@@ -1,7 +1,11 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"slices"
 	"sort"
 	"time"
 
@@ -9,6 +13,7 @@ import (
 	"gorm.io/gorm"
 
 	apperrors "kuberan/internal/errors"
+	"kuberan/internal/logger"
 	"kuberan/internal/models"
 	"kuberan/internal/pagination"
 )
@@ -17,30 +22,57 @@ import (
 type transactionService struct {
 	db             *gorm.DB
 	accountService AccountServicer
+	notifier       NotificationServicer
+	clock          Clock
 }
 
-// NewTransactionService creates a new TransactionServicer.
-func NewTransactionService(db *gorm.DB, accountService AccountServicer) TransactionServicer {
+// NewTransactionService creates a new TransactionServicer. A nil notifier
+// disables budget and large-transaction alerts.
+func NewTransactionService(db *gorm.DB, accountService AccountServicer, notifier NotificationServicer) TransactionServicer {
 	return &transactionService{
 		db:             db,
 		accountService: accountService,
+		notifier:       notifier,
+		clock:          SystemClock,
 	}
 }
 
-// CreateTransaction creates a new transaction for a user's account
+// withContext returns a copy of s whose queries run with ctx, so a report is
+// cancelled along with its request and slow queries are logged against it.
+func (s *transactionService) withContext(ctx context.Context) *transactionService {
+	scoped := *s
+	scoped.db = s.db.WithContext(ctx)
+	return &scoped
+}
+
+// CreateTransaction creates a new transaction for a user's account. Transactions
+// flagged pending or dated in the future are stored as pending and leave the
+// account balance untouched until SettlePendingTransactions applies them.
 func (s *transactionService) CreateTransaction(
-	userID string,
-	accountID string,
+	userID models.UserID,
+	accountID models.AccountID,
 	categoryID *string,
 	transactionType models.TransactionType,
-	amount int64,
+	amount models.Cents,
 	description string,
 	date time.Time,
+	pending bool,
+	status models.TransactionStatus,
+	original *OriginalAmount,
 ) (*models.Transaction, error) {
 	// Validate input
 	if amount <= 0 {
 		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "amount must be greater than zero")
 	}
+	if status == "" {
+		status = models.TransactionStatusCleared
+	}
+	if err := validateTransactionStatus(status); err != nil {
+		return nil, err
+	}
+	if err := validateOriginalAmount(original); err != nil {
+		return nil, err
+	}
 
 	if accountID == "" {
 		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "account ID is required")
@@ -48,27 +80,110 @@ func (s *transactionService) CreateTransaction(
 
 	// Default date to now if not provided
 	if date.IsZero() {
-		date = time.Now()
+		date = s.clock.Now()
 	}
 
-	// Get the account to ensure it exists and belongs to the user
-	account, err := s.accountService.GetAccountByID(userID, accountID)
+	// Get the account to ensure it exists and the user can write to it
+	account, err := s.accountService.GetWritableAccount(userID, accountID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := validateCategoryForType(s.db, string(userID), categoryID, transactionType); err != nil {
+		return nil, err
+	}
+
+	// Auto-categorize from the user's rules when no category is provided,
+	// then fall back to the account's default category
+	if categoryID == nil {
+		categoryID, err = categorizeByRules(s.db, string(userID), transactionType, description)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if categoryID == nil {
+		categoryID, err = accountDefaultCategory(s.db, string(userID), account, transactionType)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var result *models.Transaction
+	var budgetAlerts []BudgetAlertData
 	err = s.db.Transaction(func(tx *gorm.DB) error {
 		var txErr error
-		result, txErr = s.createTransactionWithDB(tx, userID, account, categoryID, transactionType, amount, description, date)
-		return txErr
+		result, txErr = s.createTransactionWithDB(tx, string(userID), account, categoryID, transactionType, int64(amount), description, date,
+			pending || date.After(s.clock.Now()), status, original)
+		if txErr != nil {
+			return txErr
+		}
+
+		// Check budgets inside the transaction so that the budget.exceeded
+		// events commit together with the expense that caused them
+		if budgetAlerts, txErr = budgetsCrossedBy(tx, result, account.Currency); txErr != nil {
+			return txErr
+		}
+		for _, alert := range budgetAlerts {
+			if txErr = recordEvent(tx, BudgetExceededEvent{
+				UserID:        string(userID),
+				TransactionID: result.ID,
+				BudgetID:      alert.BudgetID,
+				BudgetName:    alert.BudgetName,
+				CategoryName:  alert.CategoryName,
+				Period:        alert.Period,
+				Budgeted:      alert.Budgeted,
+				Spent:         alert.Spent,
+				Currency:      alert.Currency,
+			}); txErr != nil {
+				return txErr
+			}
+		}
+		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
+
+	// The transaction is already saved, so a failed lookup only skips flagging.
+	threshold, err := largeTransactionThreshold(s.db, string(userID))
+	if err != nil {
+		logger.Get().Errorw("failed to load large-transaction threshold", "user_id", userID, "error", err)
+	}
+	result.Flagged = isLargeTransaction(result, threshold)
+
+	s.sendTransactionAlerts(string(userID), account, result, threshold, budgetAlerts)
+
+	// The account was updated in place, so it carries the post-transaction balance
+	result.Account = *account
 	return result, nil
 }
 
+// sendTransactionAlerts queues email alerts for a newly posted transaction: one
+// when it is flagged as large and one for each budget it pushed over. Pending
+// transactions are skipped.
+func (s *transactionService) sendTransactionAlerts(userID string, account *models.Account, transaction *models.Transaction, threshold int64, budgetAlerts []BudgetAlertData) {
+	if s.notifier == nil || transaction.IsPending {
+		return
+	}
+
+	if transaction.Flagged {
+		s.notifier.SendLargeTransactionAlert(userID, LargeTransactionAlertData{
+			TransactionID: transaction.ID,
+			AccountName:   account.Name,
+			Type:          transaction.Type,
+			Description:   transaction.Description,
+			Amount:        transaction.Amount,
+			Threshold:     threshold,
+			Currency:      account.Currency,
+			Date:          transaction.Date,
+		})
+	}
+
+	for _, alert := range budgetAlerts {
+		s.notifier.SendBudgetAlert(userID, alert)
+	}
+}
+
 // createTransactionWithDB creates a transaction with a given database connection (useful for transactions)
 func (s *transactionService) createTransactionWithDB(
 	tx *gorm.DB,
@@ -79,6 +194,9 @@ func (s *transactionService) createTransactionWithDB(
 	amount int64,
 	description string,
 	date time.Time,
+	pending bool,
+	status models.TransactionStatus,
+	original *OriginalAmount,
 ) (*models.Transaction, error) {
 	// Create transaction record
 	transaction := &models.Transaction{
@@ -89,13 +207,32 @@ func (s *transactionService) createTransactionWithDB(
 		Amount:      amount,
 		Description: description,
 		Date:        date,
+		IsPending:   pending,
+		Status:      status,
 	}
+	setOriginalAmount(transaction, original)
 
 	if err := tx.Create(transaction).Error; err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
+	if err := recordEvent(tx, transactionCreatedEvent(transaction)); err != nil {
+		return nil, err
+	}
 
-	if err := s.accountService.UpdateAccountBalance(tx, account, transactionType, amount); err != nil {
+	if pending {
+		return transaction, nil
+	}
+	// Re-read the balance under lock before letting a protected account go
+	// below its floor
+	if transactionType == models.TransactionTypeExpense && account.Type == models.AccountTypeCash && !account.AllowNegative {
+		if err := lockAccounts(tx, account); err != nil {
+			return nil, err
+		}
+		if account.Balance-amount < balanceFloor(account) {
+			return nil, apperrors.ErrInsufficientBalance
+		}
+	}
+	if err := s.accountService.UpdateAccountBalance(tx, account, transactionType, models.Cents(amount)); err != nil {
 		return nil, err
 	}
 
@@ -103,9 +240,13 @@ func (s *transactionService) createTransactionWithDB(
 }
 
 // CreateTransfer creates an account-to-account transfer within a single DB transaction.
+// Transfers dated in the future are stored as pending and move no money until settled.
+// Only the combinations in allowedTransfers are accepted; a transfer into a
+// credit card is a payment and lowers the amount owed.
 func (s *transactionService) CreateTransfer(
-	userID, fromAccountID, toAccountID string,
-	amount int64,
+	userID models.UserID,
+	fromAccountID, toAccountID models.AccountID,
+	amount models.Cents,
 	description string,
 	date time.Time,
 ) (*models.Transaction, error) {
@@ -118,46 +259,204 @@ func (s *transactionService) CreateTransfer(
 	}
 
 	if date.IsZero() {
-		date = time.Now()
+		date = s.clock.Now()
 	}
 
-	fromAccount, err := s.accountService.GetAccountByID(userID, fromAccountID)
+	fromAccount, err := s.accountService.GetWritableAccount(userID, fromAccountID)
 	if err != nil {
 		return nil, err
 	}
 
-	toAccount, err := s.accountService.GetAccountByID(userID, toAccountID)
+	toAccount, err := s.accountService.GetWritableAccount(userID, toAccountID)
 	if err != nil {
 		return nil, err
 	}
 
-	if fromAccount.Type != models.AccountTypeCreditCard && fromAccount.Balance < amount {
+	if err := checkTransferTypes(fromAccount, toAccount); err != nil {
+		return nil, err
+	}
+	if fromAccount.Type == models.AccountTypeCash && fromAccount.Balance-int64(amount) < balanceFloor(fromAccount) {
 		return nil, apperrors.ErrInsufficientBalance
 	}
 
 	var result *models.Transaction
 	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// Re-check the balance under lock; the check above may be stale by now
+		if txErr := lockAccounts(tx, fromAccount, toAccount); txErr != nil {
+			return txErr
+		}
+		if fromAccount.Balance-int64(amount) < balanceFloor(fromAccount) {
+			return apperrors.ErrInsufficientBalance
+		}
+
+		toID := string(toAccountID)
 		transaction := &models.Transaction{
-			UserID:      userID,
-			AccountID:   fromAccountID,
-			ToAccountID: &toAccountID,
+			UserID:      string(userID),
+			AccountID:   string(fromAccountID),
+			ToAccountID: &toID,
 			Type:        models.TransactionTypeTransfer,
-			Amount:      amount,
+			Amount:      int64(amount),
 			Description: description,
 			Date:        date,
+			IsPending:   date.After(s.clock.Now()),
+			Status:      models.TransactionStatusCleared,
 		}
 		if txErr := tx.Create(transaction).Error; txErr != nil {
 			return apperrors.Wrap(apperrors.ErrInternalServer, txErr)
 		}
+		if txErr := recordEvent(tx, transactionCreatedEvent(transaction)); txErr != nil {
+			return txErr
+		}
 
+		result = transaction
+		if transaction.IsPending {
+			return nil
+		}
 		if txErr := s.accountService.UpdateAccountBalance(tx, fromAccount, models.TransactionTypeExpense, amount); txErr != nil {
 			return txErr
 		}
-		if txErr := s.accountService.UpdateAccountBalance(tx, toAccount, models.TransactionTypeIncome, amount); txErr != nil {
+		return s.accountService.UpdateAccountBalance(tx, toAccount, models.TransactionTypeIncome, amount)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Both accounts were updated in place and carry their post-transfer balances
+	result.Account = *fromAccount
+	result.ToAccount = toAccount
+	return result, nil
+}
+
+// allowedTransfers lists, per source account type, the account types it may
+// transfer into. Investment accounts take part through their cash balance;
+// credit cards only receive payments and debt accounts are not transferable.
+var allowedTransfers = map[models.AccountType][]models.AccountType{
+	models.AccountTypeCash:       {models.AccountTypeCash, models.AccountTypeCreditCard, models.AccountTypeInvestment},
+	models.AccountTypeInvestment: {models.AccountTypeCash},
+}
+
+// checkTransferTypes rejects a transfer between account types that
+// allowedTransfers does not list.
+func checkTransferTypes(from, to *models.Account) error {
+	if slices.Contains(allowedTransfers[from.Type], to.Type) {
+		return nil
+	}
+	return apperrors.WithMessage(apperrors.ErrInvalidTransfer,
+		"cannot transfer from a "+string(from.Type)+" account to a "+string(to.Type)+" account")
+}
+
+// balanceFloor is the lowest balance an expense or outgoing transfer may leave
+// on an account. Transfers never overdraw an account; expenses may, unless it
+// is a cash account with AllowNegative off, whose floor is its MinBalance.
+func balanceFloor(account *models.Account) int64 {
+	if account.Type == models.AccountTypeCash && !account.AllowNegative {
+		return account.MinBalance
+	}
+	return 0
+}
+
+// CreateSplitTransfer moves money from one account into several in a single DB
+// transaction: the source is debited once for the total and each leg is
+// recorded as its own transfer. The legs share a TransferGroupID so that
+// deleting any one of them deletes them all. Like CreateTransfer, a future date
+// stores every leg as pending.
+func (s *transactionService) CreateSplitTransfer(
+	userID models.UserID,
+	fromAccountID models.AccountID,
+	legs []SplitTransferLeg,
+	description string,
+	date time.Time,
+) ([]models.Transaction, error) {
+	if len(legs) < 2 {
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "a split transfer needs at least two destinations")
+	}
+
+	var total models.Cents
+	seen := make(map[models.AccountID]bool, len(legs))
+	for _, leg := range legs {
+		if leg.ToAccountID == fromAccountID {
+			return nil, apperrors.ErrSameAccountTransfer
+		}
+		if seen[leg.ToAccountID] {
+			return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "each destination account may appear only once")
+		}
+		seen[leg.ToAccountID] = true
+		if leg.Amount <= 0 {
+			return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "amount must be greater than zero")
+		}
+		total += leg.Amount
+	}
+
+	if date.IsZero() {
+		date = s.clock.Now()
+	}
+
+	fromAccount, err := s.accountService.GetWritableAccount(userID, fromAccountID)
+	if err != nil {
+		return nil, err
+	}
+	toAccounts := make([]*models.Account, len(legs))
+	for i, leg := range legs {
+		if toAccounts[i], err = s.accountService.GetWritableAccount(userID, leg.ToAccountID); err != nil {
+			return nil, err
+		}
+		if err := checkTransferTypes(fromAccount, toAccounts[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if fromAccount.Type == models.AccountTypeCash && fromAccount.Balance-int64(total) < balanceFloor(fromAccount) {
+		return nil, apperrors.ErrInsufficientBalance
+	}
+
+	groupID := uuid.Must(uuid.NewV7()).String()
+	pending := date.After(s.clock.Now())
+	var result []models.Transaction
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// Re-check the balance under lock; the check above may be stale by now
+		if txErr := lockAccounts(tx, append([]*models.Account{fromAccount}, toAccounts...)...); txErr != nil {
 			return txErr
 		}
+		if fromAccount.Balance-int64(total) < balanceFloor(fromAccount) {
+			return apperrors.ErrInsufficientBalance
+		}
 
-		result = transaction
+		result = make([]models.Transaction, len(legs))
+		for i, leg := range legs {
+			toID := string(leg.ToAccountID)
+			result[i] = models.Transaction{
+				UserID:          string(userID),
+				AccountID:       string(fromAccountID),
+				ToAccountID:     &toID,
+				TransferGroupID: &groupID,
+				Type:            models.TransactionTypeTransfer,
+				Amount:          int64(leg.Amount),
+				Description:     description,
+				Date:            date,
+				IsPending:       pending,
+				Status:          models.TransactionStatusCleared,
+			}
+		}
+		if txErr := tx.Create(&result).Error; txErr != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, txErr)
+		}
+		for i := range result {
+			if txErr := recordEvent(tx, transactionCreatedEvent(&result[i])); txErr != nil {
+				return txErr
+			}
+		}
+
+		if pending {
+			return nil
+		}
+		if txErr := s.accountService.UpdateAccountBalance(tx, fromAccount, models.TransactionTypeExpense, total); txErr != nil {
+			return txErr
+		}
+		for i, leg := range legs {
+			if txErr := s.accountService.UpdateAccountBalance(tx, toAccounts[i], models.TransactionTypeIncome, leg.Amount); txErr != nil {
+				return txErr
+			}
+		}
 		return nil
 	})
 	if err != nil {
@@ -166,6 +465,22 @@ func (s *transactionService) CreateTransfer(
 	return result, nil
 }
 
+// transactionCreatedEvent describes a newly created transaction for the outbox.
+func transactionCreatedEvent(t *models.Transaction) TransactionCreatedEvent {
+	return TransactionCreatedEvent{
+		TransactionID:   t.ID,
+		UserID:          t.UserID,
+		AccountID:       t.AccountID,
+		ToAccountID:     t.ToAccountID,
+		TransferGroupID: t.TransferGroupID,
+		CategoryID:      t.CategoryID,
+		Type:            t.Type,
+		Amount:          t.Amount,
+		Date:            t.Date,
+		IsPending:       t.IsPending,
+	}
+}
+
 // reverseType flips income↔expense for balance reversal.
 func reverseType(t models.TransactionType) models.TransactionType {
 	if t == models.TransactionTypeIncome {
@@ -174,14 +489,82 @@ func reverseType(t models.TransactionType) models.TransactionType {
 	return models.TransactionTypeIncome
 }
 
+// validateCategoryForType checks that categoryID, when set, refers to one of the user's
+// categories whose type matches an income or expense transaction. Other transaction
+// types accept any category.
+func validateCategoryForType(db *gorm.DB, userID string, categoryID *string, transactionType models.TransactionType) error {
+	if categoryID == nil {
+		return nil
+	}
+
+	var category models.Category
+	if err := db.Where("id = ? AND user_id = ?", *categoryID, userID).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrCategoryNotFound
+		}
+		return apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	switch transactionType {
+	case models.TransactionTypeIncome, models.TransactionTypeExpense:
+		if string(category.Type) != string(transactionType) {
+			return apperrors.ErrCategoryTypeMismatch
+		}
+	}
+	return nil
+}
+
+// accountDefaultCategory returns account's default category if it suits a
+// transaction of transactionType created by userID. A default owned by someone
+// else, such as the owner of a shared account, or of the other category type
+// is skipped.
+func accountDefaultCategory(db *gorm.DB, userID string, account *models.Account, transactionType models.TransactionType) (*string, error) {
+	if account.DefaultCategoryID == nil {
+		return nil, nil
+	}
+	if transactionType != models.TransactionTypeIncome && transactionType != models.TransactionTypeExpense {
+		return nil, nil
+	}
+
+	var category models.Category
+	err := db.Where("id = ? AND user_id = ? AND type = ?", *account.DefaultCategoryID, userID, string(transactionType)).
+		First(&category).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return &category.ID, nil
+}
+
 // UpdateTransaction updates an existing income/expense transaction.
-// Transfer and investment transactions cannot be edited.
-func (s *transactionService) UpdateTransaction(userID, transactionID string, updates TransactionUpdateFields) (*models.Transaction, error) {
+// Transfer and investment transactions cannot be edited. Pending transactions
+// have no balance impact, so editing one leaves balances unchanged.
+func (s *transactionService) UpdateTransaction(userID models.UserID, transactionID models.TransactionID, updates TransactionUpdateFields) (*models.Transaction, error) {
 	transaction, err := s.GetTransactionByID(userID, transactionID)
 	if err != nil {
 		return nil, err
 	}
 
+	if updates.Status != nil {
+		if err := validateTransactionStatus(*updates.Status); err != nil {
+			return nil, err
+		}
+	}
+	if updates.Original != nil {
+		if err := validateOriginalAmount(*updates.Original); err != nil {
+			return nil, err
+		}
+	}
+
+	// Any transaction can be marked cleared or pending; only the status
+	// changes, so balances are untouched
+	if updates.Status != nil && updates.AccountID == nil && updates.CategoryID == nil && updates.Type == nil &&
+		updates.Amount == nil && updates.Description == nil && updates.Date == nil && updates.Original == nil {
+		return s.updateTransactionStatus(string(userID), transaction, *updates.Status)
+	}
+
 	// Reject transfers and investment transactions
 	if transaction.Type == models.TransactionTypeTransfer || transaction.Type == models.TransactionTypeInvestment {
 		return nil, apperrors.ErrTransactionNotEditable
@@ -214,8 +597,19 @@ func (s *transactionService) UpdateTransaction(userID, transactionID string, upd
 		newAmount = *updates.Amount
 	}
 
+	// Re-check category compatibility when either side of the pairing changes
+	if updates.CategoryID != nil || updates.Type != nil {
+		newCategoryID := transaction.CategoryID
+		if updates.CategoryID != nil {
+			newCategoryID = *updates.CategoryID
+		}
+		if err := validateCategoryForType(s.db, string(userID), newCategoryID, newType); err != nil {
+			return nil, err
+		}
+	}
+
 	// Fetch old account
-	oldAccount, err := s.accountService.GetAccountByID(userID, oldAccountID)
+	oldAccount, err := s.accountService.GetWritableAccount(userID, models.AccountID(oldAccountID))
 	if err != nil {
 		return nil, err
 	}
@@ -223,7 +617,7 @@ func (s *transactionService) UpdateTransaction(userID, transactionID string, upd
 	// If account is changing, fetch the new account
 	var targetAccount *models.Account
 	if newAccountID != oldAccountID {
-		targetAccount, err = s.accountService.GetAccountByID(userID, newAccountID)
+		targetAccount, err = s.accountService.GetWritableAccount(userID, models.AccountID(newAccountID))
 		if err != nil {
 			return nil, err
 		}
@@ -231,10 +625,18 @@ func (s *transactionService) UpdateTransaction(userID, transactionID string, upd
 		targetAccount = oldAccount
 	}
 
+	pending := transaction.IsPending
 	err = s.db.Transaction(func(tx *gorm.DB) error {
-		// Reverse old impact on old account
-		if txErr := s.accountService.UpdateAccountBalance(tx, oldAccount, reverseType(oldType), oldAmount); txErr != nil {
-			return txErr
+		// Reverse old impact on old account, locking both accounts up front
+		var targetBefore int64
+		if !pending {
+			if txErr := lockAccounts(tx, oldAccount, targetAccount); txErr != nil {
+				return txErr
+			}
+			targetBefore = targetAccount.Balance
+			if txErr := s.accountService.UpdateAccountBalance(tx, oldAccount, reverseType(oldType), models.Cents(oldAmount)); txErr != nil {
+				return txErr
+			}
 		}
 
 		// Apply field updates
@@ -256,28 +658,94 @@ func (s *transactionService) UpdateTransaction(userID, transactionID string, upd
 		if updates.CategoryID != nil {
 			transaction.CategoryID = *updates.CategoryID
 		}
+		if updates.Status != nil {
+			transaction.Status = *updates.Status
+		}
+		if updates.Original != nil {
+			setOriginalAmount(transaction, *updates.Original)
+		}
 
 		if txErr := tx.Save(transaction).Error; txErr != nil {
 			return apperrors.Wrap(apperrors.ErrInternalServer, txErr)
 		}
 
 		// Apply new impact on target account
-		if txErr := s.accountService.UpdateAccountBalance(tx, targetAccount, newType, newAmount); txErr != nil {
-			return txErr
+		if pending {
+			return nil
 		}
-
-		return nil
+		// The edit may not take a protected account below its floor, or further
+		// below it than it already was
+		if newType == models.TransactionTypeExpense && targetAccount.Type == models.AccountTypeCash && !targetAccount.AllowNegative {
+			after := targetAccount.Balance - newAmount
+			if after < balanceFloor(targetAccount) && after < targetBefore {
+				return apperrors.ErrInsufficientBalance
+			}
+		}
+		return s.accountService.UpdateAccountBalance(tx, targetAccount, newType, models.Cents(newAmount))
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	threshold, err := largeTransactionThreshold(s.db, string(userID))
+	if err != nil {
+		return nil, err
+	}
+	transaction.Flagged = isLargeTransaction(transaction, threshold)
+	transaction.Direction = transactionDirection(transaction, nil)
+	return transaction, nil
+}
+
+// validateOriginalAmount checks an optional original amount. The currency is
+// expected to be a checked ISO 4217 code already; only its shape is verified.
+func validateOriginalAmount(original *OriginalAmount) error {
+	if original == nil {
+		return nil
+	}
+	if original.Amount <= 0 {
+		return apperrors.WithMessage(apperrors.ErrInvalidInput, "original amount must be greater than zero")
+	}
+	if len(original.Currency) != 3 {
+		return apperrors.WithMessage(apperrors.ErrInvalidInput, "original currency must be an ISO 4217 code")
+	}
+	return nil
+}
+
+// setOriginalAmount stores original on transaction, or clears it when nil.
+func setOriginalAmount(transaction *models.Transaction, original *OriginalAmount) {
+	if original == nil {
+		transaction.OriginalAmount = nil
+		transaction.OriginalCurrency = nil
+		return
+	}
+	amount, currency := int64(original.Amount), original.Currency
+	transaction.OriginalAmount = &amount
+	transaction.OriginalCurrency = &currency
+}
+
+// updateTransactionStatus sets the reconciliation status of a transaction on
+// an account the user can write to.
+func (s *transactionService) updateTransactionStatus(userID string, transaction *models.Transaction, status models.TransactionStatus) (*models.Transaction, error) {
+	if _, err := s.accountService.GetWritableAccount(models.UserID(userID), models.AccountID(transaction.AccountID)); err != nil {
+		return nil, err
+	}
+	if err := s.db.Model(transaction).Update("status", status).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
 	return transaction, nil
 }
 
+// validateTransactionStatus rejects anything but pending and cleared.
+func validateTransactionStatus(status models.TransactionStatus) error {
+	if status != models.TransactionStatusPending && status != models.TransactionStatusCleared {
+		return apperrors.WithMessage(apperrors.ErrInvalidInput, "status must be pending or cleared")
+	}
+	return nil
+}
+
 // GetAccountTransactions retrieves a paginated, filtered list of transactions for a specific account.
-func (s *transactionService) GetAccountTransactions(userID, accountID string, page pagination.PageRequest, filter TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
-	// First verify the account belongs to the user
+func (s *transactionService) GetAccountTransactions(userID models.UserID, accountID models.AccountID, page pagination.PageRequest, filter TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
+	// First verify the user can see the account
 	_, err := s.accountService.GetAccountByID(userID, accountID)
 	if err != nil {
 		return nil, err
@@ -285,11 +753,11 @@ func (s *transactionService) GetAccountTransactions(userID, accountID string, pa
 
 	page.Defaults()
 
-	base := s.db.Model(&models.Transaction{}).Where("user_id = ? AND account_id = ?", userID, accountID)
+	base := s.db.Model(&models.Transaction{}).Where("(account_id = ? OR to_account_id = ?)", accountID, accountID)
 	base = applyTransactionFilters(base, filter)
 
 	var totalItems int64
-	if err := base.Count(&totalItems).Error; err != nil {
+	if err := pagination.Count(base, page, &totalItems); err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
 
@@ -299,11 +767,17 @@ func (s *transactionService) GetAccountTransactions(userID, accountID string, pa
 		Find(&transactions).Error; err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
+	if err := flagLargeTransactions(s.db, string(userID), transactions); err != nil {
+		return nil, err
+	}
+	setTransactionDirections(transactions, []string{string(accountID)})
 
 	result := pagination.NewPageResponse(transactions, page.Page, page.PageSize, totalItems)
 	return &result, nil
 }
 
+// applyTransactionFilters narrows q by f. An account filter matches
+// transfers into the account as well as transactions recorded against it.
 func applyTransactionFilters(q *gorm.DB, f TransactionFilter) *gorm.DB {
 	if f.FromDate != nil {
 		q = q.Where("date >= ?", *f.FromDate)
@@ -317,6 +791,9 @@ func applyTransactionFilters(q *gorm.DB, f TransactionFilter) *gorm.DB {
 	if f.CategoryID != nil {
 		q = q.Where("category_id = ?", *f.CategoryID)
 	}
+	if f.Uncategorized {
+		q = q.Where("category_id IS NULL")
+	}
 	if f.MinAmount != nil {
 		q = q.Where("amount >= ?", *f.MinAmount)
 	}
@@ -324,20 +801,34 @@ func applyTransactionFilters(q *gorm.DB, f TransactionFilter) *gorm.DB {
 		q = q.Where("amount <= ?", *f.MaxAmount)
 	}
 	if f.AccountID != nil {
-		q = q.Where("account_id = ?", *f.AccountID)
+		q = q.Where("(account_id = ? OR to_account_id = ?)", *f.AccountID, *f.AccountID)
+	}
+	if f.Pending != nil {
+		q = q.Where("is_pending = ?", *f.Pending)
+	}
+	if f.Status != nil {
+		q = q.Where("status = ?", *f.Status)
 	}
 	return q
 }
 
-// GetUserTransactions retrieves a paginated, filtered list of all transactions for a user across all accounts.
-func (s *transactionService) GetUserTransactions(userID string, page pagination.PageRequest, filter TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
+// GetUserTransactions retrieves a paginated, filtered list of all transactions across
+// the accounts a user can access, including accounts shared with them.
+func (s *transactionService) GetUserTransactions(userID models.UserID, page pagination.PageRequest, filter TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
 	page.Defaults()
 
-	base := s.db.Model(&models.Transaction{}).Where("user_id = ?", userID)
+	var accountIDs []string
+	if err := accessibleAccountIDs(s.db, string(userID)).Pluck("id", &accountIDs).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	// A transfer between two accessible accounts is a single row, so it is
+	// listed and counted once
+	base := s.db.Model(&models.Transaction{}).Where("(account_id IN ? OR to_account_id IN ?)", accountIDs, accountIDs)
 	base = applyTransactionFilters(base, filter)
 
 	var totalItems int64
-	if err := base.Count(&totalItems).Error; err != nil {
+	if err := pagination.Count(base, page, &totalItems); err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
 
@@ -348,68 +839,680 @@ func (s *transactionService) GetUserTransactions(userID string, page pagination.
 		Find(&transactions).Error; err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
+	if err := flagLargeTransactions(s.db, string(userID), transactions); err != nil {
+		return nil, err
+	}
+	if filter.AccountID != nil {
+		accountIDs = []string{*filter.AccountID}
+	}
+	setTransactionDirections(transactions, accountIDs)
+
+	result := pagination.NewPageResponse(transactions, page.Page, page.PageSize, totalItems)
+	return &result, nil
+}
+
+// GetTransfers retrieves a paginated, filtered list of the transfers touching
+// the accounts a user can access, with both the source and destination account
+// preloaded so each can be shown as a from→to pair. Deleted accounts are still
+// loaded so older transfers keep their names. The filter's Type is ignored.
+func (s *transactionService) GetTransfers(userID models.UserID, page pagination.PageRequest, filter TransactionFilter) (*pagination.PageResponse[models.Transaction], error) {
+	page.Defaults()
+
+	var accountIDs []string
+	if err := accessibleAccountIDs(s.db, string(userID)).Pluck("id", &accountIDs).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	transferType := models.TransactionTypeTransfer
+	filter.Type = &transferType
+	base := s.db.Model(&models.Transaction{}).Where("(account_id IN ? OR to_account_id IN ?)", accountIDs, accountIDs)
+	base = applyTransactionFilters(base, filter)
+
+	var totalItems int64
+	if err := pagination.Count(base, page, &totalItems); err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	unscoped := func(db *gorm.DB) *gorm.DB { return db.Unscoped() }
+	var transactions []models.Transaction
+	if err := base.Preload("Account", unscoped).
+		Preload("ToAccount", unscoped).
+		Scopes(pagination.Paginate(page)).
+		Order("date DESC").
+		Find(&transactions).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if filter.AccountID != nil {
+		accountIDs = []string{*filter.AccountID}
+	}
+	setTransactionDirections(transactions, accountIDs)
 
 	result := pagination.NewPageResponse(transactions, page.Page, page.PageSize, totalItems)
 	return &result, nil
 }
 
-// GetTransactionByID retrieves a transaction by ID for a specific user
-func (s *transactionService) GetTransactionByID(userID, transactionID string) (*models.Transaction, error) {
+// setTransactionDirections sets Direction on each transaction relative to the
+// listed accountIDs.
+func setTransactionDirections(transactions []models.Transaction, accountIDs []string) {
+	for i := range transactions {
+		transactions[i].Direction = transactionDirection(&transactions[i], accountIDs)
+	}
+}
+
+// transactionDirection reports whether t moved money into or out of
+// accountIDs. Income and expenses only touch their own account, so their
+// direction follows from the type alone.
+func transactionDirection(t *models.Transaction, accountIDs []string) models.TransactionDirection {
+	switch t.Type {
+	case models.TransactionTypeIncome:
+		return models.TransactionDirectionIn
+	case models.TransactionTypeExpense:
+		return models.TransactionDirectionOut
+	}
+	if t.ToAccountID == nil {
+		return ""
+	}
+	from, to := slices.Contains(accountIDs, t.AccountID), slices.Contains(accountIDs, *t.ToAccountID)
+	switch {
+	case from && !to:
+		return models.TransactionDirectionOut
+	case to && !from:
+		return models.TransactionDirectionIn
+	}
+	return ""
+}
+
+// GetTransactionByID retrieves a transaction by ID from an account the user can
+// access, including transfers into one
+func (s *transactionService) GetTransactionByID(userID models.UserID, transactionID models.TransactionID) (*models.Transaction, error) {
+	var accountIDs []string
+	if err := accessibleAccountIDs(s.db, string(userID)).Pluck("id", &accountIDs).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
 	var transaction models.Transaction
-	if err := s.db.Where("id = ? AND user_id = ?", transactionID, userID).First(&transaction).Error; err != nil {
+	if err := s.db.Where("id = ? AND (account_id IN ? OR to_account_id IN ?)", transactionID, accountIDs, accountIDs).
+		First(&transaction).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, apperrors.ErrTransactionNotFound
 		}
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
-	return &transaction, nil
-}
 
-// DeleteTransaction deletes a transaction and updates the account balance
-func (s *transactionService) DeleteTransaction(userID, transactionID string) error {
-	transaction, err := s.GetTransactionByID(userID, transactionID)
+	threshold, err := largeTransactionThreshold(s.db, string(userID))
 	if err != nil {
-		return err
+		return nil, err
 	}
+	transaction.Flagged = isLargeTransaction(&transaction, threshold)
+	transaction.Direction = transactionDirection(&transaction, accountIDs)
+	return &transaction, nil
+}
+
+// flaggedTransactionWindow is how far back GetFlaggedTransactions looks.
+const flaggedTransactionWindow = 90 * 24 * time.Hour
 
-	account, err := s.accountService.GetAccountByID(userID, transaction.AccountID)
+// GetFlaggedTransactions returns the income and expenses from the last 90 days,
+// newest first, whose amount reaches the user's large-transaction threshold.
+// Transfers are never flagged. The page is empty while no threshold is set.
+func (s *transactionService) GetFlaggedTransactions(userID models.UserID, page pagination.PageRequest) (*pagination.PageResponse[models.Transaction], error) {
+	page.Defaults()
+
+	threshold, err := largeTransactionThreshold(s.db, string(userID))
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if threshold <= 0 {
+		result := pagination.NewPageResponse([]models.Transaction{}, page.Page, page.PageSize, 0)
+		return &result, nil
 	}
 
-	return s.db.Transaction(func(tx *gorm.DB) error {
-		if txErr := tx.Delete(transaction).Error; txErr != nil {
-			return apperrors.Wrap(apperrors.ErrInternalServer, txErr)
-		}
+	base := s.db.Model(&models.Transaction{}).
+		Where("account_id IN (?) AND type IN ? AND amount >= ? AND date >= ?",
+			accessibleAccountIDs(s.db, string(userID)), flaggableTransactionTypes, threshold, s.clock.Now().Add(-flaggedTransactionWindow))
 
-		switch transaction.Type {
-		case models.TransactionTypeIncome:
-			return s.accountService.UpdateAccountBalance(tx, account, models.TransactionTypeExpense, transaction.Amount)
-		case models.TransactionTypeExpense:
-			return s.accountService.UpdateAccountBalance(tx, account, models.TransactionTypeIncome, transaction.Amount)
-		case models.TransactionTypeTransfer:
-			if transaction.ToAccountID == nil {
-				return apperrors.ErrInvalidTransactionType
-			}
-			toAccount, toErr := s.accountService.GetAccountByID(userID, *transaction.ToAccountID)
-			if toErr != nil {
-				return toErr
-			}
-			// Reverse: add back to from-account, subtract from to-account
-			if txErr := s.accountService.UpdateAccountBalance(tx, account, models.TransactionTypeIncome, transaction.Amount); txErr != nil {
-				return txErr
-			}
-			return s.accountService.UpdateAccountBalance(tx, toAccount, models.TransactionTypeExpense, transaction.Amount)
-		default:
-			return apperrors.ErrInvalidTransactionType
+	var totalItems int64
+	if err := pagination.Count(base, page, &totalItems); err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	var transactions []models.Transaction
+	if err := base.Preload("Category").
+		Scopes(pagination.Paginate(page)).
+		Order("date DESC").
+		Find(&transactions).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	for i := range transactions {
+		transactions[i].Flagged = true
+	}
+
+	result := pagination.NewPageResponse(transactions, page.Page, page.PageSize, totalItems)
+	return &result, nil
+}
+
+// flaggableTransactionTypes are the types that can be flagged as large. Transfers
+// only move money between the user's own accounts, so they are left out.
+var flaggableTransactionTypes = []models.TransactionType{models.TransactionTypeIncome, models.TransactionTypeExpense}
+
+// largeTransactionThreshold returns the user's large-transaction threshold; 0 means flagging is off.
+func largeTransactionThreshold(db *gorm.DB, userID string) (int64, error) {
+	var user models.User
+	if err := db.Select("large_transaction_threshold").Where("id = ?", userID).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, apperrors.ErrUserNotFound
+		}
+		return 0, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return user.LargeTransactionThreshold, nil
+}
+
+// isLargeTransaction reports whether transaction reaches threshold and is of a flaggable type.
+func isLargeTransaction(transaction *models.Transaction, threshold int64) bool {
+	if threshold <= 0 || transaction.Amount < threshold {
+		return false
+	}
+	for _, t := range flaggableTransactionTypes {
+		if transaction.Type == t {
+			return true
 		}
+	}
+	return false
+}
+
+// flagLargeTransactions sets Flagged on each transaction that reaches the user's threshold.
+func flagLargeTransactions(db *gorm.DB, userID string, transactions []models.Transaction) error {
+	threshold, err := largeTransactionThreshold(db, userID)
+	if err != nil {
+		return err
+	}
+	for i := range transactions {
+		transactions[i].Flagged = isLargeTransaction(&transactions[i], threshold)
+	}
+	return nil
+}
+
+// DeleteTransaction deletes a transaction and, unless it is still pending, reverses
+// its effect on the account balance
+func (s *transactionService) DeleteTransaction(userID models.UserID, transactionID models.TransactionID) error {
+	transaction, err := s.GetTransactionByID(userID, transactionID)
+	if err != nil {
+		return err
+	}
+	if transaction.TransferGroupID != nil {
+		return s.deleteTransferGroup(string(userID), *transaction.TransferGroupID)
+	}
+
+	account, err := s.accountService.GetWritableAccount(userID, models.AccountID(transaction.AccountID))
+	if err != nil {
+		return err
+	}
+	toAccount, err := s.transferDestination(string(userID), transaction)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return s.deleteTransactionWithDB(tx, account, toAccount, transaction)
+	})
+}
+
+// deleteTransferGroup deletes every leg of a split transfer in one DB
+// transaction, reversing each settled leg's effect on its accounts.
+func (s *transactionService) deleteTransferGroup(userID, groupID string) error {
+	var legs []models.Transaction
+	if err := s.db.Where("transfer_group_id = ?", groupID).Find(&legs).Error; err != nil {
+		return apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if len(legs) == 0 {
+		return apperrors.ErrTransactionNotFound
+	}
+
+	// Every leg debits the same source account
+	account, err := s.accountService.GetWritableAccount(models.UserID(userID), models.AccountID(legs[0].AccountID))
+	if err != nil {
+		return err
+	}
+	toAccounts := make([]*models.Account, len(legs))
+	for i := range legs {
+		if toAccounts[i], err = s.transferDestination(userID, &legs[i]); err != nil {
+			return err
+		}
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		for i := range legs {
+			if err := s.deleteTransactionWithDB(tx, account, toAccounts[i], &legs[i]); err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 }
 
-// GetMonthlySummary returns monthly income and expense totals for the last N months.
-func (s *transactionService) GetMonthlySummary(userID string, months int) ([]MonthlySummaryItem, error) {
-	now := time.Now()
-	startMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -(months - 1), 0)
+// transferDestination returns the writable destination account of a settled
+// transfer, or nil for any other transaction.
+func (s *transactionService) transferDestination(userID string, transaction *models.Transaction) (*models.Account, error) {
+	if transaction.IsPending || transaction.Type != models.TransactionTypeTransfer || transaction.ToAccountID == nil {
+		return nil, nil
+	}
+	return s.accountService.GetWritableAccount(models.UserID(userID), models.AccountID(*transaction.ToAccountID))
+}
+
+// deleteTransactionWithDB soft-deletes transaction inside tx and reverses its
+// effect on account, and on toAccount for transfers, unless it is still pending.
+// Accounts are resolved by the caller because reads outside tx can block on
+// SQLite once tx has written to them.
+func (s *transactionService) deleteTransactionWithDB(tx *gorm.DB, account, toAccount *models.Account, transaction *models.Transaction) error {
+	if transaction.FundsInvestment() {
+		return apperrors.ErrTransactionFundsInvestment
+	}
+	if err := tx.Delete(transaction).Error; err != nil {
+		return apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if transaction.IsPending {
+		return nil
+	}
+
+	switch transaction.Type {
+	case models.TransactionTypeIncome:
+		return s.accountService.UpdateAccountBalance(tx, account, models.TransactionTypeExpense, models.Cents(transaction.Amount))
+	case models.TransactionTypeExpense:
+		return s.accountService.UpdateAccountBalance(tx, account, models.TransactionTypeIncome, models.Cents(transaction.Amount))
+	case models.TransactionTypeTransfer:
+		if toAccount == nil {
+			return apperrors.ErrInvalidTransactionType
+		}
+		if err := lockAccounts(tx, account, toAccount); err != nil {
+			return err
+		}
+		// Reverse: add back to from-account, subtract from to-account
+		if err := s.accountService.UpdateAccountBalance(tx, account, models.TransactionTypeIncome, models.Cents(transaction.Amount)); err != nil {
+			return err
+		}
+		return s.accountService.UpdateAccountBalance(tx, toAccount, models.TransactionTypeExpense, models.Cents(transaction.Amount))
+	default:
+		return apperrors.ErrInvalidTransactionType
+	}
+}
+
+const (
+	// bulkDeleteTokenTTL is how long a bulk delete dry run can be confirmed.
+	bulkDeleteTokenTTL = 15 * time.Minute
+	// bulkDeleteChunkSize is how many transactions each database transaction of a bulk delete removes.
+	bulkDeleteChunkSize = 500
+)
+
+// bulkDeletableTypes are the transaction types a bulk delete can reverse.
+var bulkDeletableTypes = []models.TransactionType{
+	models.TransactionTypeIncome, models.TransactionTypeExpense, models.TransactionTypeTransfer,
+}
+
+// PreviewBulkDelete counts and totals the transactions matched by selection and
+// records a pending delete that ExecuteBulkDelete runs once given the returned
+// confirmation token. Explicit IDs must all exist and be deletable; a filter
+// must bound the date range and only matches income, expenses and transfers.
+// Every account touched, including transfer destinations, must be writable.
+func (s *transactionService) PreviewBulkDelete(userID models.UserID, selection BulkDeleteSelection) (*BulkDeletePreview, error) {
+	selection.IDs = uniqueStrings(selection.IDs)
+	count, total, err := s.bulkDeleteTotals(string(userID), selection)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(selection)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	token, err := generateVerificationToken()
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	pending := &models.PendingBulkDelete{
+		UserID:           string(userID),
+		Selection:        string(encoded),
+		TransactionCount: count,
+		TotalAmount:      total,
+		TokenHash:        hashVerificationToken(token),
+		ExpiresAt:        s.clock.Now().Add(bulkDeleteTokenTTL),
+	}
+	if err := s.db.Create(pending).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	return &BulkDeletePreview{
+		Count:             count,
+		TotalAmount:       total,
+		ConfirmationToken: token,
+		ExpiresAt:         pending.ExpiresAt,
+	}, nil
+}
+
+// ExecuteBulkDelete deletes the transactions previewed under confirmationToken,
+// reversing balances as DeleteTransaction does. Tokens are single-use. If the
+// selection no longer has the previewed count and total the delete is refused
+// and a new dry run is needed. Deletion runs in chunks of bulkDeleteChunkSize,
+// each in its own database transaction, so a failure part way leaves earlier
+// chunks deleted.
+func (s *transactionService) ExecuteBulkDelete(userID models.UserID, confirmationToken string) (*BulkDeleteResult, error) {
+	if confirmationToken == "" {
+		return nil, apperrors.ErrInvalidConfirmationToken
+	}
+
+	var pending models.PendingBulkDelete
+	if err := s.db.Where("token_hash = ? AND user_id = ?", hashVerificationToken(confirmationToken), userID).
+		First(&pending).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrInvalidConfirmationToken
+		}
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if err := s.db.Unscoped().Delete(&pending).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if s.clock.Now().After(pending.ExpiresAt) {
+		return nil, apperrors.ErrConfirmationTokenExpired
+	}
+
+	var selection BulkDeleteSelection
+	if err := json.Unmarshal([]byte(pending.Selection), &selection); err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	count, total, err := s.bulkDeleteTotals(string(userID), selection)
+	if err != nil {
+		return nil, err
+	}
+	if count != pending.TransactionCount || total != pending.TotalAmount {
+		return nil, apperrors.ErrBulkDeleteStale
+	}
+
+	var ids []string
+	if err := s.bulkDeleteQuery(string(userID), selection).Order("id").Pluck("id", &ids).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	result := &BulkDeleteResult{Selection: selection}
+	accounts := make(map[string]*models.Account)
+	writableAccount := func(accountID string) (*models.Account, error) {
+		if account, ok := accounts[accountID]; ok {
+			return account, nil
+		}
+		account, err := s.accountService.GetWritableAccount(userID, models.AccountID(accountID))
+		if err != nil {
+			return nil, err
+		}
+		accounts[accountID] = account
+		return account, nil
+	}
+
+	for start := 0; start < len(ids); start += bulkDeleteChunkSize {
+		chunk := ids[start:min(start+bulkDeleteChunkSize, len(ids))]
+
+		var transactions []models.Transaction
+		if err := s.db.Where("id IN ?", chunk).Find(&transactions).Error; err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		fromAccounts := make([]*models.Account, len(transactions))
+		toAccounts := make([]*models.Account, len(transactions))
+		for i := range transactions {
+			if fromAccounts[i], err = writableAccount(transactions[i].AccountID); err != nil {
+				return nil, err
+			}
+			if transactions[i].ToAccountID != nil && !transactions[i].IsPending {
+				if toAccounts[i], err = writableAccount(*transactions[i].ToAccountID); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			for i := range transactions {
+				if err := s.deleteTransactionWithDB(tx, fromAccounts[i], toAccounts[i], &transactions[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			logger.Get().Errorw("bulk delete stopped part way", "user_id", userID, "deleted", result.Deleted, "error", err)
+			return nil, err
+		}
+		result.Deleted += int64(len(transactions))
+	}
+	return result, nil
+}
+
+// bulkDeleteQuery returns the transactions matched by an already validated selection.
+func (s *transactionService) bulkDeleteQuery(userID string, selection BulkDeleteSelection) *gorm.DB {
+	q := s.db.Model(&models.Transaction{}).Where("account_id IN (?)", accessibleAccountIDs(s.db, userID))
+	if selection.Filter != nil {
+		return applyTransactionFilters(q, *selection.Filter).Where("type IN ?", bulkDeletableTypes)
+	}
+	return q.Where("id IN ?", selection.IDs)
+}
+
+// bulkDeleteTotals validates selection and returns the number and summed amount
+// of the transactions it matches, checking that each can be deleted by userID.
+func (s *transactionService) bulkDeleteTotals(userID string, selection BulkDeleteSelection) (int64, int64, error) {
+	switch {
+	case len(selection.IDs) > 0 && selection.Filter != nil:
+		return 0, 0, apperrors.WithMessage(apperrors.ErrInvalidInput, "provide either ids or filter, not both")
+	case selection.Filter != nil:
+		f := selection.Filter
+		if f.FromDate == nil || f.ToDate == nil {
+			return 0, 0, apperrors.WithMessage(apperrors.ErrInvalidInput, "filter requires from_date and to_date")
+		}
+		if f.FromDate.After(*f.ToDate) {
+			return 0, 0, apperrors.WithMessage(apperrors.ErrInvalidInput, "from_date must not be after to_date")
+		}
+	case len(selection.IDs) == 0:
+		return 0, 0, apperrors.WithMessage(apperrors.ErrInvalidInput, "ids or filter is required")
+	}
+
+	var groups []struct {
+		AccountID   string
+		ToAccountID *string
+		Type        models.TransactionType
+		Count       int64
+		Total       int64
+	}
+	if err := s.bulkDeleteQuery(userID, selection).
+		Select("account_id, to_account_id, type, COUNT(*) AS count, COALESCE(SUM(amount), 0) AS total").
+		Group("account_id, to_account_id, type").
+		Scan(&groups).Error; err != nil {
+		return 0, 0, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	var count, total int64
+	checked := make(map[string]bool)
+	for _, g := range groups {
+		if !slices.Contains(bulkDeletableTypes, g.Type) {
+			return 0, 0, apperrors.WithMessage(apperrors.ErrInvalidTransactionType, "investment transactions cannot be bulk deleted")
+		}
+		accountIDs := []string{g.AccountID}
+		if g.ToAccountID != nil {
+			accountIDs = append(accountIDs, *g.ToAccountID)
+		}
+		for _, id := range accountIDs {
+			if checked[id] {
+				continue
+			}
+			if _, err := s.accountService.GetWritableAccount(models.UserID(userID), models.AccountID(id)); err != nil {
+				return 0, 0, err
+			}
+			checked[id] = true
+		}
+		count += g.Count
+		total += g.Total
+	}
+
+	if len(selection.IDs) > 0 && count != int64(len(selection.IDs)) {
+		return 0, 0, apperrors.WithMessage(apperrors.ErrTransactionNotFound, "one or more transactions were not found")
+	}
+	return count, total, nil
+}
+
+// BulkUpdateCategory sets the category of every income and expense on the
+// user's own accounts matched by filter, in a single update, and returns how
+// many were changed. The filter must bound the date range. A category only
+// applies to transactions of its own type, so a filter on the other type is
+// rejected; a nil categoryID uncategorizes both types. Balances are unaffected
+// and no budget alerts are sent.
+func (s *transactionService) BulkUpdateCategory(userID models.UserID, filter TransactionFilter, categoryID *string) (int64, error) {
+	if filter.FromDate == nil || filter.ToDate == nil {
+		return 0, apperrors.WithMessage(apperrors.ErrInvalidInput, "filter requires from_date and to_date")
+	}
+	if filter.FromDate.After(*filter.ToDate) {
+		return 0, apperrors.WithMessage(apperrors.ErrInvalidInput, "from_date must not be after to_date")
+	}
+
+	types := []models.TransactionType{models.TransactionTypeIncome, models.TransactionTypeExpense}
+	if categoryID != nil {
+		var category models.Category
+		if err := s.db.Where("id = ? AND user_id = ?", *categoryID, userID).First(&category).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return 0, apperrors.ErrCategoryNotFound
+			}
+			return 0, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		types = []models.TransactionType{models.TransactionType(category.Type)}
+	}
+	if filter.Type != nil && !slices.Contains(types, *filter.Type) {
+		return 0, apperrors.ErrCategoryTypeMismatch
+	}
+
+	ownAccounts := s.db.Model(&models.Account{}).Select("id").Where("user_id = ?", userID)
+	result := applyTransactionFilters(s.db.Model(&models.Transaction{}), filter).
+		Where("account_id IN (?) AND type IN ?", ownAccounts, types).
+		Update("category_id", categoryID)
+	if result.Error != nil {
+		return 0, apperrors.Wrap(apperrors.ErrInternalServer, result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// SettlePendingTransactions applies every pending transaction dated at or before
+// asOf to its account balances and marks it settled. Each transaction settles in
+// its own DB transaction, and one already settled by a concurrent run is skipped.
+// One that would take its account below its balance floor stays pending until a
+// later run can settle it. Returns the number of transactions settled.
+func (s *transactionService) SettlePendingTransactions(asOf time.Time) (int, error) {
+	var pending []models.Transaction
+	if err := s.db.Where("is_pending = ? AND date <= ?", true, asOf).
+		Order("date ASC").
+		Find(&pending).Error; err != nil {
+		return 0, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	settled := 0
+	for i := range pending {
+		transaction := &pending[i]
+		applied := false
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			result := tx.Model(&models.Transaction{}).
+				Where("id = ? AND is_pending = ?", transaction.ID, true).
+				Update("is_pending", false)
+			if result.Error != nil {
+				return apperrors.Wrap(apperrors.ErrInternalServer, result.Error)
+			}
+			if result.RowsAffected == 0 {
+				return nil
+			}
+			applied = true
+			return s.applySettledBalance(tx, transaction, true)
+		})
+		if errors.Is(err, apperrors.ErrInsufficientBalance) {
+			logger.Get().Warnw("pending transaction left pending below its account's balance floor",
+				"transaction_id", transaction.ID, "account_id", transaction.AccountID)
+			continue
+		}
+		if err != nil {
+			return settled, err
+		}
+		if applied {
+			settled++
+		}
+	}
+
+	return settled, nil
+}
+
+// applySettledBalance applies a newly settled transaction to its account balances.
+// With checkFloor, an expense or outgoing transfer that would leave its account
+// below balanceFloor fails with ErrInsufficientBalance, as when it is created.
+func (s *transactionService) applySettledBalance(tx *gorm.DB, transaction *models.Transaction, checkFloor bool) error {
+	var account models.Account
+	if err := tx.Unscoped().Where("id = ?", transaction.AccountID).First(&account).Error; err != nil {
+		return apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	switch transaction.Type {
+	case models.TransactionTypeIncome, models.TransactionTypeExpense:
+		if checkFloor && transaction.Type == models.TransactionTypeExpense && account.Type == models.AccountTypeCash && !account.AllowNegative {
+			if err := lockAccounts(tx, &account); err != nil {
+				return err
+			}
+			if account.Balance-transaction.Amount < balanceFloor(&account) {
+				return apperrors.ErrInsufficientBalance
+			}
+		}
+		return s.accountService.UpdateAccountBalance(tx, &account, transaction.Type, models.Cents(transaction.Amount))
+	case models.TransactionTypeTransfer:
+		if transaction.ToAccountID == nil {
+			return apperrors.ErrInvalidTransactionType
+		}
+		var toAccount models.Account
+		if err := tx.Unscoped().Where("id = ?", *transaction.ToAccountID).First(&toAccount).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		if err := lockAccounts(tx, &account, &toAccount); err != nil {
+			return err
+		}
+		if checkFloor && account.Type == models.AccountTypeCash && account.Balance-transaction.Amount < balanceFloor(&account) {
+			return apperrors.ErrInsufficientBalance
+		}
+		if err := s.accountService.UpdateAccountBalance(tx, &account, models.TransactionTypeExpense, models.Cents(transaction.Amount)); err != nil {
+			return err
+		}
+		return s.accountService.UpdateAccountBalance(tx, &toAccount, models.TransactionTypeIncome, models.Cents(transaction.Amount))
+	case models.TransactionTypeInvestment:
+		// Only a buy's funding leg moved a balance, and only its cash side
+		if !transaction.FundsInvestment() {
+			return nil
+		}
+		return s.accountService.UpdateAccountBalance(tx, &account, models.TransactionTypeExpense, models.Cents(transaction.Amount))
+	default:
+		return nil
+	}
+}
+
+// reportableTransactions scopes a report query to transactions on accounts not
+// flagged ExcludeFromReports, unless includeExcluded is set.
+func reportableTransactions(includeExcluded bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if includeExcluded {
+			return db
+		}
+		excluded := db.Session(&gorm.Session{NewDB: true}).Unscoped().
+			Model(&models.Account{}).
+			Select("id").
+			Where("exclude_from_reports = ?", true)
+		return db.Where("account_id NOT IN (?)", excluded)
+	}
+}
+
+// GetMonthlySummary returns monthly income and expense totals for the last N months,
+// which are calendar months in the user's time zone.
+// When withCategories is true, each month also carries its expense breakdown by category.
+// Accounts excluded from reports are skipped unless includeExcluded is set.
+func (s *transactionService) GetMonthlySummary(ctx context.Context, userID models.UserID, months int, withCategories, includeExcluded bool) ([]MonthlySummaryItem, error) {
+	s = s.withContext(ctx)
+	loc, err := userLocation(s.db, s.clock, string(userID))
+	if err != nil {
+		return nil, err
+	}
+	now := s.clock.Now().In(loc)
+	startMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, -(months - 1), 0)
 
 	items := make([]MonthlySummaryItem, 0, months)
 
@@ -418,22 +1521,9 @@ func (s *transactionService) GetMonthlySummary(userID string, months int) ([]Mon
 		monthStart := current
 		monthEnd := current.AddDate(0, 1, 0).Add(-time.Nanosecond)
 
-		var income int64
-		if err := s.db.Model(&models.Transaction{}).
-			Select("COALESCE(SUM(amount), 0)").
-			Where("user_id = ? AND type = ? AND deleted_at IS NULL AND date BETWEEN ? AND ? AND description != ?",
-				userID, models.TransactionTypeIncome, monthStart, monthEnd, "Initial balance").
-			Scan(&income).Error; err != nil {
-			return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
-		}
-
-		var expenses int64
-		if err := s.db.Model(&models.Transaction{}).
-			Select("COALESCE(SUM(amount), 0)").
-			Where("user_id = ? AND type = ? AND deleted_at IS NULL AND date BETWEEN ? AND ?",
-				userID, models.TransactionTypeExpense, monthStart, monthEnd).
-			Scan(&expenses).Error; err != nil {
-			return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		income, expenses, err := incomeAndExpenses(s.db, string(userID), monthStart.UTC(), monthEnd.UTC(), includeExcluded)
+		if err != nil {
+			return nil, err
 		}
 
 		items = append(items, MonthlySummaryItem{
@@ -445,43 +1535,370 @@ func (s *transactionService) GetMonthlySummary(userID string, months int) ([]Mon
 		current = current.AddDate(0, 1, 0)
 	}
 
+	if withCategories {
+		if err := s.attachMonthlyCategoryBreakdown(string(userID), items, startMonth, current.Add(-time.Nanosecond), includeExcluded); err != nil {
+			return nil, err
+		}
+	}
+
 	return items, nil
 }
 
-// GetDailySpending returns daily expense totals for a date range.
-func (s *transactionService) GetDailySpending(userID string, from, to time.Time) ([]DailySpendingItem, error) {
+// incomeAndExpenses sums the user's income and expenses dated between from and to
+// inclusive. Opening-balance income is left out since it is not earnings.
+func incomeAndExpenses(db *gorm.DB, userID string, from, to time.Time, includeExcluded bool) (int64, int64, error) {
+	var income int64
+	if err := db.Model(&models.Transaction{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("user_id = ? AND type = ? AND deleted_at IS NULL AND date BETWEEN ? AND ? AND description != ?",
+			userID, models.TransactionTypeIncome, from, to, "Initial balance").
+		Scopes(reportableTransactions(includeExcluded)).
+		Scan(&income).Error; err != nil {
+		return 0, 0, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	var expenses int64
+	if err := db.Model(&models.Transaction{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("user_id = ? AND type = ? AND deleted_at IS NULL AND date BETWEEN ? AND ?",
+			userID, models.TransactionTypeExpense, from, to).
+		Scopes(reportableTransactions(includeExcluded)).
+		Scan(&expenses).Error; err != nil {
+		return 0, 0, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return income, expenses, nil
+}
+
+// attachMonthlyCategoryBreakdown fills each item's Categories with expense totals per
+// category. The whole range is aggregated in one query grouped by category and date,
+// then bucketed into months of from's location here to stay portable across SQL dialects.
+func (s *transactionService) attachMonthlyCategoryBreakdown(userID string, items []MonthlySummaryItem, from, to time.Time, includeExcluded bool) error {
+	type dayCategorySpend struct {
+		CategoryID *string
+		Date       time.Time
+		Total      int64
+	}
+
+	var rows []dayCategorySpend
+	if err := s.db.Model(&models.Transaction{}).
+		Select("category_id, date, COALESCE(SUM(amount), 0) AS total").
+		Where("user_id = ? AND type = ? AND deleted_at IS NULL AND date BETWEEN ? AND ?",
+			userID, models.TransactionTypeExpense, from.UTC(), to.UTC()).
+		Scopes(reportableTransactions(includeExcluded)).
+		Group("category_id, date").
+		Scan(&rows).Error; err != nil {
+		return apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	// Resolve category names in one lookup
+	categoryIDs := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, r := range rows {
+		if r.CategoryID != nil && !seen[*r.CategoryID] {
+			seen[*r.CategoryID] = true
+			categoryIDs = append(categoryIDs, *r.CategoryID)
+		}
+	}
+	names := make(map[string]string, len(categoryIDs))
+	if len(categoryIDs) > 0 {
+		var categories []models.Category
+		if err := s.db.Unscoped().Where("id IN ?", categoryIDs).Find(&categories).Error; err != nil {
+			return apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		for _, cat := range categories {
+			names[cat.ID] = cat.Name
+		}
+	}
+
+	// month -> category key ("" for uncategorized) -> total
+	totals := make(map[string]map[string]int64, len(items))
+	for _, r := range rows {
+		month := r.Date.In(from.Location()).Format("2006-01")
+		if totals[month] == nil {
+			totals[month] = make(map[string]int64)
+		}
+		key := ""
+		if r.CategoryID != nil {
+			key = *r.CategoryID
+		}
+		totals[month][key] += r.Total
+	}
+
+	for i := range items {
+		breakdown := make([]MonthlyCategoryExpense, 0, len(totals[items[i].Month]))
+		for key, total := range totals[items[i].Month] {
+			entry := MonthlyCategoryExpense{Total: total, CategoryName: "Uncategorized"}
+			if key != "" {
+				id := key
+				entry.CategoryID = &id
+				entry.CategoryName = names[key]
+				if entry.CategoryName == "" {
+					entry.CategoryName = "Unknown Category"
+				}
+			}
+			breakdown = append(breakdown, entry)
+		}
+		sort.Slice(breakdown, func(a, b int) bool {
+			if breakdown[a].Total != breakdown[b].Total {
+				return breakdown[a].Total > breakdown[b].Total
+			}
+			return breakdown[a].CategoryName < breakdown[b].CategoryName
+		})
+		items[i].Categories = breakdown
+	}
+
+	return nil
+}
+
+// GetDailySpending returns expense totals for a date range in day, week or month
+// buckets, skipping accounts excluded from reports unless includeExcluded is set.
+// Buckets without spending are included with a zero total. Weeks begin on the
+// user's configured week start. Each bucket is labelled with its first day, and
+// the first and last buckets only count spending within the range. The calendar
+// days of from and to are taken as days in the user's time zone, and buckets
+// start at local midnight.
+func (s *transactionService) GetDailySpending(ctx context.Context, userID models.UserID, from, to time.Time, granularity SpendingGranularity, includeExcluded bool) ([]DailySpendingItem, error) {
+	s = s.withContext(ctx)
+	loc, err := userLocation(s.db, s.clock, string(userID))
+	if err != nil {
+		return nil, err
+	}
 	// Normalize to start/end of day
-	current := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
-	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1).Add(-time.Nanosecond)
+
+	var current time.Time
+	var next func(time.Time) time.Time
+	switch granularity {
+	case SpendingGranularityDay, "":
+		current = start
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+	case SpendingGranularityWeek:
+		weekStart, err := s.userWeekStart(string(userID))
+		if err != nil {
+			return nil, err
+		}
+		offset := (int(start.Weekday()) - int(weekStart) + 7) % 7
+		current = start.AddDate(0, 0, -offset)
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+	case SpendingGranularityMonth:
+		current = time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, loc)
+		next = func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+	default:
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "granularity must be day, week or month")
+	}
 
-	var items []DailySpendingItem
+	// One query totals the range per local day; the buckets are summed from it
+	type daySpend struct {
+		Day   string
+		Total int64
+	}
+	dayExpr, dayArgs := localDayExpr(s.db, loc, start)
+	var rows []daySpend
+	if err := s.db.Model(&models.Transaction{}).
+		Select(dayExpr+" AS day, COALESCE(SUM(amount), 0) AS total", dayArgs...).
+		Where("user_id = ? AND type = ? AND deleted_at IS NULL AND date BETWEEN ? AND ?",
+			userID, models.TransactionTypeExpense, start.UTC(), end.UTC()).
+		Scopes(reportableTransactions(includeExcluded)).
+		Group("day").
+		Scan(&rows).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	byDay := make(map[string]int64, len(rows))
+	for _, r := range rows {
+		byDay[r.Day] = r.Total
+	}
 
+	items := []DailySpendingItem{}
 	for !current.After(end) {
-		dayStart := current
-		dayEnd := current.Add(24*time.Hour - time.Nanosecond)
+		bucketStart := current
+		bucketEnd := next(current).Add(-time.Nanosecond)
+		if bucketStart.Before(start) {
+			bucketStart = start
+		}
+		if bucketEnd.After(end) {
+			bucketEnd = end
+		}
 
 		var total int64
-		if err := s.db.Model(&models.Transaction{}).
-			Select("COALESCE(SUM(amount), 0)").
-			Where("user_id = ? AND type = ? AND deleted_at IS NULL AND date BETWEEN ? AND ?",
-				userID, models.TransactionTypeExpense, dayStart, dayEnd).
-			Scan(&total).Error; err != nil {
-			return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		for day := bucketStart; !day.After(bucketEnd); day = day.AddDate(0, 0, 1) {
+			total += byDay[day.Format("2006-01-02")]
 		}
 
 		items = append(items, DailySpendingItem{
-			Date:  dayStart.Format("2006-01-02"),
+			Date:  current.Format("2006-01-02"),
 			Total: total,
 		})
 
-		current = current.AddDate(0, 0, 1)
+		current = next(current)
 	}
 
-	if items == nil {
-		items = []DailySpendingItem{}
+	return items, nil
+}
+
+// localDayExpr returns the SQL, and its arguments, for the calendar day of the
+// date column in loc as YYYY-MM-DD. SQLite has no time zone database, so there
+// every row is shifted by the offset loc has at the instant at.
+func localDayExpr(db *gorm.DB, loc *time.Location, at time.Time) (string, []interface{}) {
+	if db.Dialector.Name() == "sqlite" {
+		_, offset := at.In(loc).Zone()
+		return "strftime('%Y-%m-%d', date, ?)", []interface{}{fmt.Sprintf("%+d seconds", offset)}
 	}
+	return "TO_CHAR(date AT TIME ZONE ?, 'YYYY-MM-DD')", []interface{}{loc.String()}
+}
 
-	return items, nil
+// userWeekStart returns the weekday the user's weeks begin on, Monday by default.
+func (s *transactionService) userWeekStart(userID string) (time.Weekday, error) {
+	var weekStart models.WeekStart
+	if err := s.db.Model(&models.User{}).
+		Select("week_start").
+		Where("id = ?", userID).
+		Scan(&weekStart).Error; err != nil {
+		return 0, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	switch weekStart {
+	case models.WeekStartSunday:
+		return time.Sunday, nil
+	case models.WeekStartSaturday:
+		return time.Saturday, nil
+	default:
+		return time.Monday, nil
+	}
+}
+
+// UserLocation returns the time zone the user's dates and reports are read in.
+func (s *transactionService) UserLocation(userID models.UserID) (*time.Location, error) {
+	return userLocation(s.db, s.clock, string(userID))
+}
+
+// userLocation returns the time zone the user's daily and monthly reports are
+// bucketed in, falling back to the clock's reporting location when none is set.
+func userLocation(db *gorm.DB, clock Clock, userID string) (*time.Location, error) {
+	var timezone string
+	if err := db.Model(&models.User{}).
+		Select("timezone").
+		Where("id = ?", userID).
+		Scan(&timezone).Error; err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if timezone == "" {
+		return clock.Location(), nil
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return loc, nil
+}
+
+// GetSpendingByAccount returns expense totals and counts per account for a date
+// range, largest first. Transfers are not expenses and are left out. Inactive
+// accounts are listed when they had spending in the range; accounts excluded
+// from reports are skipped unless includeExcluded is set.
+func (s *transactionService) GetSpendingByAccount(userID models.UserID, from, to time.Time, includeExcluded bool) (*SpendingByAccount, error) {
+	type accountSpend struct {
+		AccountID string
+		Total     int64
+		Count     int64
+	}
+
+	var results []accountSpend
+	err := s.db.Model(&models.Transaction{}).
+		Select("account_id, COALESCE(SUM(amount), 0) as total, COUNT(*) as count").
+		Where("user_id = ? AND type = ? AND deleted_at IS NULL AND date BETWEEN ? AND ?",
+			userID, models.TransactionTypeExpense, from, to).
+		Scopes(reportableTransactions(includeExcluded)).
+		Group("account_id").
+		Scan(&results).Error
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	accountIDs := make([]string, 0, len(results))
+	for _, r := range results {
+		accountIDs = append(accountIDs, r.AccountID)
+	}
+	accounts := make(map[string]models.Account, len(accountIDs))
+	if len(accountIDs) > 0 {
+		var found []models.Account
+		if err := s.db.Unscoped().Where("id IN ?", accountIDs).Find(&found).Error; err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		for _, a := range found {
+			accounts[a.ID] = a
+		}
+	}
+
+	items := make([]SpendingByAccountItem, 0, len(results))
+	var totalSpent int64
+	for _, r := range results {
+		account := accounts[r.AccountID]
+		items = append(items, SpendingByAccountItem{
+			AccountID:   r.AccountID,
+			AccountName: account.Name,
+			IsActive:    account.IsActive,
+			Total:       r.Total,
+			Count:       r.Count,
+		})
+		totalSpent += r.Total
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Total != items[j].Total {
+			return items[i].Total > items[j].Total
+		}
+		return items[i].AccountName < items[j].AccountName
+	})
+
+	return &SpendingByAccount{
+		Items:      items,
+		TotalSpent: totalSpent,
+		FromDate:   from,
+		ToDate:     to,
+	}, nil
+}
+
+// GetSpendingByCurrency returns expense totals grouped by the currency the money
+// was spent in for a date range, largest first. A transaction with an original
+// amount counts under its original currency; any other counts under its
+// account's currency. Accounts excluded from reports are skipped unless
+// includeExcluded is set.
+func (s *transactionService) GetSpendingByCurrency(ctx context.Context, userID models.UserID, from, to time.Time, includeExcluded bool) (*SpendingByCurrency, error) {
+	s = s.withContext(ctx)
+	const currencyExpr = "COALESCE(transactions.original_currency, accounts.currency)"
+
+	items := []SpendingByCurrencyItem{}
+	err := s.db.Model(&models.Transaction{}).
+		Select(currencyExpr+" AS currency, "+
+			"COALESCE(SUM(COALESCE(transactions.original_amount, transactions.amount)), 0) AS original_total, "+
+			"COALESCE(SUM(transactions.amount), 0) AS total, COUNT(*) AS count").
+		Joins("JOIN accounts ON accounts.id = transactions.account_id").
+		Where("transactions.user_id = ? AND transactions.type = ? AND transactions.date BETWEEN ? AND ?",
+			userID, models.TransactionTypeExpense, from, to).
+		Scopes(reportableTransactions(includeExcluded)).
+		Group(currencyExpr).
+		Scan(&items).Error
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+
+	var totalSpent int64
+	for _, item := range items {
+		totalSpent += item.Total
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Total != items[j].Total {
+			return items[i].Total > items[j].Total
+		}
+		return items[i].Currency < items[j].Currency
+	})
+
+	return &SpendingByCurrency{
+		Items:      items,
+		TotalSpent: totalSpent,
+		FromDate:   from,
+		ToDate:     to,
+	}, nil
 }
 
 // categoryColorPalette provides fallback colors for categories that don't have a color set.
@@ -522,8 +1939,22 @@ func getCategoryColorFromID(id string) string {
 	return categoryColorPalette[hash%uint64(len(categoryColorPalette))]
 }
 
-// GetSpendingByCategory returns expense totals grouped by category for a date range.
-func (s *transactionService) GetSpendingByCategory(userID string, from, to time.Time) (*SpendingByCategory, error) {
+// GetSpendingByCategory returns expense totals, or income totals in income mode,
+// grouped by category for a date range, skipping accounts excluded from reports
+// unless includeExcluded is set. An empty mode means expense.
+func (s *transactionService) GetSpendingByCategory(ctx context.Context, userID models.UserID, from, to time.Time, mode SpendingMode, includeExcluded bool) (*SpendingByCategory, error) {
+	s = s.withContext(ctx)
+	var txType models.TransactionType
+	switch mode {
+	case SpendingModeExpense, "":
+		mode = SpendingModeExpense
+		txType = models.TransactionTypeExpense
+	case SpendingModeIncome:
+		txType = models.TransactionTypeIncome
+	default:
+		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "mode must be expense or income")
+	}
+
 	type categorySpend struct {
 		CategoryID *string
 		Total      int64
@@ -533,7 +1964,8 @@ func (s *transactionService) GetSpendingByCategory(userID string, from, to time.
 	err := s.db.Model(&models.Transaction{}).
 		Select("category_id, COALESCE(SUM(amount), 0) as total").
 		Where("user_id = ? AND type = ? AND deleted_at IS NULL AND date BETWEEN ? AND ?",
-			userID, models.TransactionTypeExpense, from, to).
+			userID, txType, from, to).
+		Scopes(reportableTransactions(includeExcluded)).
 		Group("category_id").
 		Scan(&results).Error
 	if err != nil {
@@ -583,6 +2015,7 @@ func (s *transactionService) GetSpendingByCategory(userID string, from, to time.
 	return &SpendingByCategory{
 		Items:      items,
 		TotalSpent: totalSpent,
+		Mode:       mode,
 		FromDate:   from,
 		ToDate:     to,
 	}, nil
@@ -1,53 +1,121 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	apperrors "kuberan/internal/errors"
 	"kuberan/internal/models"
 	"kuberan/internal/pagination"
 	"kuberan/internal/testutil"
+	"kuberan/internal/uuid"
+
+	"gorm.io/gorm"
 )
 
 func TestCreateTransaction(t *testing.T) {
+	t.Parallel()
 	t.Run("income_increases_balance", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 
-		tx, err := txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeIncome, 5000, "Salary", time.Now())
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 5000, "Salary", time.Now(), false, "", nil)
 		testutil.AssertNoError(t, err)
 
-		if tx.ID == 0 {
-			t.Fatal("expected non-zero transaction ID")
+		if tx.ID == "" {
+			t.Fatal("expected non-empty transaction ID")
 		}
 		if tx.Amount != 5000 {
 			t.Errorf("expected amount 5000, got %d", tx.Amount)
 		}
 
 		// Verify balance increased
-		updated, err := acctSvc.GetAccountByID(user.ID, account.ID)
+		updated, err := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(account.ID))
 		testutil.AssertNoError(t, err)
 		if updated.Balance != 5000 {
 			t.Errorf("expected balance 5000, got %d", updated.Balance)
 		}
+		if tx.Account.Balance != updated.Balance {
+			t.Errorf("expected the returned account balance %d, got %d", updated.Balance, tx.Account.Balance)
+		}
+	})
+
+	t.Run("original_amount_persists_without_affecting_balance", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 1150, "Paris cafe", time.Now(), false, "",
+			&OriginalAmount{Amount: 1000, Currency: "EUR"})
+		testutil.AssertNoError(t, err)
+
+		stored, err := txSvc.GetTransactionByID(models.UserID(user.ID), models.TransactionID(tx.ID))
+		testutil.AssertNoError(t, err)
+		if stored.Amount != 1150 || stored.OriginalAmount == nil || *stored.OriginalAmount != 1000 ||
+			stored.OriginalCurrency == nil || *stored.OriginalCurrency != "EUR" {
+			t.Errorf("expected 1150 in the account currency from 1000 EUR, got %d from %v %v", stored.Amount, stored.OriginalAmount, stored.OriginalCurrency)
+		}
+
+		acct, _ := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(account.ID))
+		if acct.Balance != 8850 {
+			t.Errorf("expected balance 8850, got %d", acct.Balance)
+		}
+
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 1150, "", time.Now(), false, "",
+			&OriginalAmount{Amount: 0, Currency: "EUR"})
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+
+	t.Run("status_defaults_to_cleared", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 5000, "Salary", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+		if tx.Status != models.TransactionStatusCleared {
+			t.Errorf("expected status cleared, got %q", tx.Status)
+		}
+
+		// Uncleared transactions still move the balance
+		tx, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 1000, "Refund", time.Now(), false, models.TransactionStatusPending, nil)
+		testutil.AssertNoError(t, err)
+		if tx.Status != models.TransactionStatusPending {
+			t.Errorf("expected status pending, got %q", tx.Status)
+		}
+		updated, err := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(account.ID))
+		testutil.AssertNoError(t, err)
+		if updated.Balance != 6000 {
+			t.Errorf("expected balance 6000, got %d", updated.Balance)
+		}
+
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 1000, "", time.Now(), false, "posted", nil)
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
 	})
 
 	t.Run("expense_decreases_balance", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
 
-		_, err := txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeExpense, 3000, "Lunch", time.Now())
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 3000, "Lunch", time.Now(), false, "", nil)
 		testutil.AssertNoError(t, err)
 
-		updated, err := acctSvc.GetAccountByID(user.ID, account.ID)
+		updated, err := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(account.ID))
 		testutil.AssertNoError(t, err)
 		if updated.Balance != 7000 {
 			t.Errorf("expected balance 7000, got %d", updated.Balance)
@@ -55,73 +123,67 @@ func TestCreateTransaction(t *testing.T) {
 	})
 
 	t.Run("zero_amount", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 
-		_, err := txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeIncome, 0, "", time.Now())
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 0, "", time.Now(), false, "", nil)
 		testutil.AssertAppError(t, err, "INVALID_INPUT")
 	})
 
 	t.Run("negative_amount", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 
-		_, err := txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeIncome, -100, "", time.Now())
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, -100, "", time.Now(), false, "", nil)
 		testutil.AssertAppError(t, err, "INVALID_INPUT")
 	})
 
-	t.Run("zero_account_id", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+	t.Run("empty_account_id", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 
-		_, err := txSvc.CreateTransaction(1, 0, nil, models.TransactionTypeIncome, 1000, "", time.Now())
+		_, err := txSvc.CreateTransaction(models.UserID(uuid.New()), "", nil, models.TransactionTypeIncome, 1000, "", time.Now(), false, "", nil)
 		testutil.AssertAppError(t, err, "INVALID_INPUT")
 	})
 
 	t.Run("invalid_account", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 
-		_, err := txSvc.CreateTransaction(user.ID, 99999, nil, models.TransactionTypeIncome, 1000, "", time.Now())
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(uuid.New()), nil, models.TransactionTypeIncome, 1000, "", time.Now(), false, "", nil)
 		testutil.AssertAppError(t, err, "ACCOUNT_NOT_FOUND")
 	})
 
 	t.Run("wrong_user_account", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user1 := testutil.CreateTestUser(t, db)
 		user2 := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user1.ID)
 
-		_, err := txSvc.CreateTransaction(user2.ID, account.ID, nil, models.TransactionTypeIncome, 1000, "", time.Now())
+		_, err := txSvc.CreateTransaction(models.UserID(user2.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 1000, "", time.Now(), false, "", nil)
 		testutil.AssertAppError(t, err, "ACCOUNT_NOT_FOUND")
 	})
 
 	t.Run("with_category", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
 
-		tx, err := txSvc.CreateTransaction(user.ID, account.ID, &cat.ID, models.TransactionTypeExpense, 500, "Coffee", time.Now())
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &cat.ID, models.TransactionTypeExpense, 500, "Coffee", time.Now(), false, "", nil)
 		testutil.AssertNoError(t, err)
 
 		if tx.CategoryID == nil || *tx.CategoryID != cat.ID {
@@ -130,33 +192,408 @@ func TestCreateTransaction(t *testing.T) {
 	})
 
 	t.Run("default_date_when_zero", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 
-		tx, err := txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeIncome, 1000, "", time.Time{})
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 1000, "", time.Time{}, false, "", nil)
 		testutil.AssertNoError(t, err)
 
 		if tx.Date.IsZero() {
 			t.Error("expected date to be defaulted to now, got zero")
 		}
 	})
+
+	t.Run("matching_category_type", func(t *testing.T) {
+		for _, txType := range []models.TransactionType{models.TransactionTypeIncome, models.TransactionTypeExpense} {
+			t.Run(string(txType), func(t *testing.T) {
+				db := testutil.WithTx(t)
+				acctSvc := NewAccountService(db, nil)
+				txSvc := NewTransactionService(db, acctSvc, nil)
+				user := testutil.CreateTestUser(t, db)
+				account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+				cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryType(txType))
+
+				tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &cat.ID, txType, 500, "", time.Now(), false, "", nil)
+				testutil.AssertNoError(t, err)
+				if tx.CategoryID == nil || *tx.CategoryID != cat.ID {
+					t.Error("expected category ID to be set")
+				}
+			})
+		}
+	})
+
+	t.Run("category_type_mismatch", func(t *testing.T) {
+		for _, tc := range []struct {
+			txType  models.TransactionType
+			catType models.CategoryType
+		}{
+			{models.TransactionTypeIncome, models.CategoryTypeExpense},
+			{models.TransactionTypeExpense, models.CategoryTypeIncome},
+		} {
+			t.Run(string(tc.txType), func(t *testing.T) {
+				db := testutil.WithTx(t)
+				acctSvc := NewAccountService(db, nil)
+				txSvc := NewTransactionService(db, acctSvc, nil)
+				user := testutil.CreateTestUser(t, db)
+				account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+				cat := testutil.CreateTestCategory(t, db, user.ID, tc.catType)
+
+				_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &cat.ID, tc.txType, 500, "", time.Now(), false, "", nil)
+				testutil.AssertAppError(t, err, "CATEGORY_TYPE_MISMATCH")
+
+				acct, _ := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(account.ID))
+				if acct.Balance != 10000 {
+					t.Errorf("expected balance unchanged, got %d", acct.Balance)
+				}
+			})
+		}
+	})
+
+	t.Run("other_users_category", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		other := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, user.ID)
+		cat := testutil.CreateTestCategory(t, db, other.ID, models.CategoryTypeIncome)
+
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &cat.ID, models.TransactionTypeIncome, 500, "", time.Now(), false, "", nil)
+		testutil.AssertAppError(t, err, "CATEGORY_NOT_FOUND")
+	})
+
+	t.Run("inherits_account_default_category", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		db.Model(account).Update("default_category_id", cat.ID)
+
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 500, "", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+		if tx.CategoryID == nil || *tx.CategoryID != cat.ID {
+			t.Errorf("expected default category %s, got %v", cat.ID, tx.CategoryID)
+		}
+
+		// A default of the wrong type is ignored.
+		income, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 500, "", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+		if income.CategoryID != nil {
+			t.Errorf("expected no category on income, got %v", *income.CategoryID)
+		}
+	})
+
+	t.Run("explicit_category_overrides_default", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+		defaultCat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		db.Model(account).Update("default_category_id", defaultCat.ID)
+
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &cat.ID, models.TransactionTypeExpense, 500, "", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+		if tx.CategoryID == nil || *tx.CategoryID != cat.ID {
+			t.Errorf("expected explicit category %s, got %v", cat.ID, tx.CategoryID)
+		}
+	})
+}
+
+func TestCreateTransactionOverdraftProtection(t *testing.T) {
+	t.Parallel()
+	setup := func(t *testing.T) (*gorm.DB, TransactionServicer, AccountServicer, *models.User) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		return db, NewTransactionService(db, acctSvc, nil), acctSvc, testutil.CreateTestUser(t, db)
+	}
+
+	t.Run("blocks_expense_below_min_balance", func(t *testing.T) {
+		db, txSvc, acctSvc, user := setup(t)
+		account, err := acctSvc.CreateCashAccount(models.UserID(user.ID), "Checking", "", "USD", 10000, false, 2000, nil)
+		testutil.AssertNoError(t, err)
+
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 8001, "", time.Now(), false, "", nil)
+		testutil.AssertAppError(t, err, "INSUFFICIENT_BALANCE")
+
+		var stored models.Account
+		db.Where("id = ?", account.ID).First(&stored)
+		if stored.Balance != 10000 {
+			t.Errorf("expected balance unchanged at 10000, got %d", stored.Balance)
+		}
+		var count int64
+		db.Model(&models.Transaction{}).Where("account_id = ? AND type = ?", account.ID, models.TransactionTypeExpense).Count(&count)
+		if count != 0 {
+			t.Errorf("expected no expense recorded, got %d", count)
+		}
+	})
+
+	t.Run("allows_expense_down_to_min_balance", func(t *testing.T) {
+		_, txSvc, acctSvc, user := setup(t)
+		account, err := acctSvc.CreateCashAccount(models.UserID(user.ID), "Checking", "", "USD", 10000, false, 2000, nil)
+		testutil.AssertNoError(t, err)
+
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 8000, "", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+		if tx.Account.Balance != 2000 {
+			t.Errorf("expected balance 2000, got %d", tx.Account.Balance)
+		}
+	})
+
+	t.Run("zero_floor_without_min_balance", func(t *testing.T) {
+		_, txSvc, acctSvc, user := setup(t)
+		account, err := acctSvc.CreateCashAccount(models.UserID(user.ID), "Checking", "", "USD", 1000, false, 0, nil)
+		testutil.AssertNoError(t, err)
+
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 1001, "", time.Now(), false, "", nil)
+		testutil.AssertAppError(t, err, "INSUFFICIENT_BALANCE")
+	})
+
+	t.Run("allow_negative_by_default", func(t *testing.T) {
+		db, txSvc, _, user := setup(t)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 1000)
+
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 5000, "", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+		if tx.Account.Balance != -4000 {
+			t.Errorf("expected balance -4000, got %d", tx.Account.Balance)
+		}
+	})
+
+	t.Run("credit_cards_unaffected", func(t *testing.T) {
+		db, txSvc, _, user := setup(t)
+		card := testutil.CreateTestCreditCardAccount(t, db, user.ID, 0)
+		// Even a stray setting on a card row does not limit its spending
+		db.Model(card).Updates(map[string]interface{}{"allow_negative": false, "min_balance": 5000})
+
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(card.ID), nil, models.TransactionTypeExpense, 3000, "", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+		if tx.Account.Balance != 3000 {
+			t.Errorf("expected amount owed 3000, got %d", tx.Account.Balance)
+		}
+	})
+
+	t.Run("blocks_transfer_below_min_balance", func(t *testing.T) {
+		db, txSvc, acctSvc, user := setup(t)
+		from, err := acctSvc.CreateCashAccount(models.UserID(user.ID), "Checking", "", "USD", 10000, false, 2000, nil)
+		testutil.AssertNoError(t, err)
+		a := testutil.CreateTestCashAccount(t, db, user.ID)
+		b := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		_, err = txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(from.ID), models.AccountID(a.ID), 8001, "", time.Now())
+		testutil.AssertAppError(t, err, "INSUFFICIENT_BALANCE")
+
+		legs := []SplitTransferLeg{
+			{ToAccountID: models.AccountID(a.ID), Amount: 4000},
+			{ToAccountID: models.AccountID(b.ID), Amount: 4001},
+		}
+		_, err = txSvc.CreateSplitTransfer(models.UserID(user.ID), models.AccountID(from.ID), legs, "", time.Now())
+		testutil.AssertAppError(t, err, "INSUFFICIENT_BALANCE")
+
+		transfer, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(from.ID), models.AccountID(a.ID), 8000, "", time.Now())
+		testutil.AssertNoError(t, err)
+		if transfer.Account.Balance != 2000 {
+			t.Errorf("expected balance 2000, got %d", transfer.Account.Balance)
+		}
+	})
+
+	t.Run("blocks_update_below_min_balance", func(t *testing.T) {
+		db, txSvc, acctSvc, user := setup(t)
+		account, err := acctSvc.CreateCashAccount(models.UserID(user.ID), "Checking", "", "USD", 10000, false, 5000, nil)
+		testutil.AssertNoError(t, err)
+		expense, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 1000, "", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		raised := int64(50000)
+		_, err = txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(expense.ID), TransactionUpdateFields{Amount: &raised})
+		testutil.AssertAppError(t, err, "INSUFFICIENT_BALANCE")
+
+		var stored models.Account
+		db.Where("id = ?", account.ID).First(&stored)
+		if stored.Balance != 9000 {
+			t.Errorf("expected balance unchanged at 9000, got %d", stored.Balance)
+		}
+
+		// Down to the floor is still allowed
+		raised = 5000
+		updated, err := txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(expense.ID), TransactionUpdateFields{Amount: &raised})
+		testutil.AssertNoError(t, err)
+		if updated.Amount != 5000 {
+			t.Errorf("expected amount 5000, got %d", updated.Amount)
+		}
+	})
+
+	t.Run("allows_editing_an_expense_already_below_a_raised_min_balance", func(t *testing.T) {
+		db, txSvc, acctSvc, user := setup(t)
+		account, err := acctSvc.CreateCashAccount(models.UserID(user.ID), "Checking", "", "USD", 10000, false, 0, nil)
+		testutil.AssertNoError(t, err)
+		expense, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 8000, "", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+		db.Model(&models.Account{}).Where("id = ?", account.ID).Update("min_balance", 5000)
+
+		description := "Rent"
+		_, err = txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(expense.ID), TransactionUpdateFields{Description: &description})
+		testutil.AssertNoError(t, err)
+	})
+
+	t.Run("leaves_pending_debits_pending_below_min_balance", func(t *testing.T) {
+		db, txSvc, acctSvc, user := setup(t)
+		account, err := acctSvc.CreateCashAccount(models.UserID(user.ID), "Checking", "", "USD", 10000, false, 5000, nil)
+		testutil.AssertNoError(t, err)
+		other := testutil.CreateTestCashAccount(t, db, user.ID)
+		due := time.Now().Add(time.Hour)
+
+		transfer, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(account.ID), models.AccountID(other.ID), 4000, "", due)
+		testutil.AssertNoError(t, err)
+		expense, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 6000, "", due.Add(time.Minute), false, "", nil)
+		testutil.AssertNoError(t, err)
+		if !expense.IsPending || !transfer.IsPending {
+			t.Fatal("expected post-dated debits to be pending")
+		}
+		// Spending in the meantime leaves room for neither
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 3000, "", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		count, err := txSvc.SettlePendingTransactions(due.Add(time.Minute))
+		testutil.AssertNoError(t, err)
+		if count != 0 {
+			t.Errorf("expected nothing settled, got %d", count)
+		}
+		var stored models.Account
+		db.Where("id = ?", account.ID).First(&stored)
+		if stored.Balance != 7000 {
+			t.Errorf("expected balance unchanged at 7000, got %d", stored.Balance)
+		}
+		var pending int64
+		db.Model(&models.Transaction{}).Where("id IN ? AND is_pending = ?", []string{expense.ID, transfer.ID}, true).Count(&pending)
+		if pending != 2 {
+			t.Errorf("expected both debits still pending, got %d", pending)
+		}
+
+		// Once income covers them, the next run settles both
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 10000, "", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+		count, err = txSvc.SettlePendingTransactions(due.Add(time.Minute))
+		testutil.AssertNoError(t, err)
+		if count != 2 {
+			t.Errorf("expected both debits settled, got %d", count)
+		}
+		db.Where("id = ?", account.ID).First(&stored)
+		if stored.Balance != 7000 {
+			t.Errorf("expected balance 7000 after settling, got %d", stored.Balance)
+		}
+	})
+}
+
+func TestCreateTransactionAlerts(t *testing.T) {
+	t.Parallel()
+	setup := func(t *testing.T) (*gorm.DB, TransactionServicer, *recordingNotifier, *models.User, *models.Account) {
+		t.Helper()
+		db := testutil.WithTx(t)
+		notifier := &recordingNotifier{}
+		txSvc := NewTransactionService(db, NewAccountService(db, nil), notifier)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 1000000)
+		return db, txSvc, notifier, user, account
+	}
+
+	t.Run("large_transaction_at_threshold", func(t *testing.T) {
+		db, txSvc, notifier, user, account := setup(t)
+		db.Model(user).Update("large_transaction_threshold", 50000)
+
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 49999, "Small", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 50000, "Rent", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		if len(notifier.largeTransactionAlerts) != 1 {
+			t.Fatalf("expected 1 large transaction alert, got %d", len(notifier.largeTransactionAlerts))
+		}
+		alert := notifier.largeTransactionAlerts[0]
+		if alert.TransactionID != tx.ID || alert.Amount != 50000 || alert.Threshold != 50000 {
+			t.Errorf("unexpected alert data %+v", alert)
+		}
+		if alert.AccountName != account.Name || alert.Currency != "USD" || alert.Description != "Rent" {
+			t.Errorf("unexpected alert data %+v", alert)
+		}
+	})
+
+	t.Run("zero_threshold_disables_large_alerts", func(t *testing.T) {
+		_, txSvc, notifier, user, account := setup(t)
+
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 900000, "Bonus", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		if len(notifier.largeTransactionAlerts) != 0 {
+			t.Errorf("expected no alerts, got %d", len(notifier.largeTransactionAlerts))
+		}
+	})
+
+	t.Run("budget_alert_when_crossed_once", func(t *testing.T) {
+		db, txSvc, notifier, user, account := setup(t)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		budget := testutil.CreateTestBudget(t, db, user.ID, cat.ID) // 10000
+
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &cat.ID, models.TransactionTypeExpense, 6000, "Shop", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+		if len(notifier.budgetAlerts) != 0 {
+			t.Fatalf("expected no alert under budget, got %d", len(notifier.budgetAlerts))
+		}
+
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &cat.ID, models.TransactionTypeExpense, 4500, "Shop", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+		if len(notifier.budgetAlerts) != 1 {
+			t.Fatalf("expected 1 budget alert, got %d", len(notifier.budgetAlerts))
+		}
+		alert := notifier.budgetAlerts[0]
+		if alert.BudgetID != budget.ID || alert.BudgetName != budget.Name || alert.CategoryName != cat.Name {
+			t.Errorf("unexpected alert data %+v", alert)
+		}
+		if alert.Budgeted != 10000 || alert.Spent != 10500 || alert.Currency != "USD" || alert.Period != models.BudgetPeriodMonthly {
+			t.Errorf("unexpected alert data %+v", alert)
+		}
+
+		// Already over budget: further spending does not alert again.
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &cat.ID, models.TransactionTypeExpense, 1000, "Shop", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+		if len(notifier.budgetAlerts) != 1 {
+			t.Errorf("expected no repeat alert, got %d", len(notifier.budgetAlerts))
+		}
+	})
+
+	t.Run("pending_transactions_do_not_alert", func(t *testing.T) {
+		db, txSvc, notifier, user, account := setup(t)
+		db.Model(user).Update("large_transaction_threshold", 100)
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		testutil.CreateTestBudget(t, db, user.ID, cat.ID)
+
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &cat.ID, models.TransactionTypeExpense, 20000, "Hold", time.Now(), true, "", nil)
+		testutil.AssertNoError(t, err)
+
+		if len(notifier.budgetAlerts) != 0 || len(notifier.largeTransactionAlerts) != 0 {
+			t.Errorf("expected no alerts for pending transaction, got %d budget and %d large",
+				len(notifier.budgetAlerts), len(notifier.largeTransactionAlerts))
+		}
+	})
 }
 
 func TestCreateTransfer(t *testing.T) {
+	t.Parallel()
 	t.Run("valid", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		from := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
 		to := testutil.CreateTestCashAccount(t, db, user.ID)
 
-		tx, err := txSvc.CreateTransfer(user.ID, from.ID, to.ID, 3000, "Transfer", time.Now())
+		tx, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(from.ID), models.AccountID(to.ID), 3000, "Transfer", time.Now())
 		testutil.AssertNoError(t, err)
 
 		if tx.Type != models.TransactionTypeTransfer {
@@ -167,46 +604,47 @@ func TestCreateTransfer(t *testing.T) {
 		}
 
 		// Verify from-account balance decreased
-		fromUpdated, err := acctSvc.GetAccountByID(user.ID, from.ID)
+		fromUpdated, err := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(from.ID))
 		testutil.AssertNoError(t, err)
 		if fromUpdated.Balance != 7000 {
 			t.Errorf("expected from-balance 7000, got %d", fromUpdated.Balance)
 		}
 
 		// Verify to-account balance increased
-		toUpdated, err := acctSvc.GetAccountByID(user.ID, to.ID)
+		toUpdated, err := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(to.ID))
 		testutil.AssertNoError(t, err)
 		if toUpdated.Balance != 3000 {
 			t.Errorf("expected to-balance 3000, got %d", toUpdated.Balance)
 		}
+		if tx.Account.Balance != 7000 || tx.ToAccount == nil || tx.ToAccount.Balance != 3000 {
+			t.Errorf("expected the returned accounts to carry the new balances, got %+v / %+v", tx.Account, tx.ToAccount)
+		}
 	})
 
 	t.Run("same_account", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
 
-		_, err := txSvc.CreateTransfer(user.ID, account.ID, account.ID, 1000, "", time.Now())
+		_, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(account.ID), models.AccountID(account.ID), 1000, "", time.Now())
 		testutil.AssertAppError(t, err, "SAME_ACCOUNT_TRANSFER")
 	})
 
 	t.Run("insufficient_balance", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		from := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 1000)
 		to := testutil.CreateTestCashAccount(t, db, user.ID)
 
-		_, err := txSvc.CreateTransfer(user.ID, from.ID, to.ID, 5000, "", time.Now())
+		_, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(from.ID), models.AccountID(to.ID), 5000, "", time.Now())
 		testutil.AssertAppError(t, err, "INSUFFICIENT_BALANCE")
 
 		// Verify balances unchanged
-		fromUpdated, err := acctSvc.GetAccountByID(user.ID, from.ID)
+		fromUpdated, err := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(from.ID))
 		testutil.AssertNoError(t, err)
 		if fromUpdated.Balance != 1000 {
 			t.Errorf("expected from-balance unchanged at 1000, got %d", fromUpdated.Balance)
@@ -214,58 +652,143 @@ func TestCreateTransfer(t *testing.T) {
 	})
 
 	t.Run("zero_amount", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		from := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
 		to := testutil.CreateTestCashAccount(t, db, user.ID)
 
-		_, err := txSvc.CreateTransfer(user.ID, from.ID, to.ID, 0, "", time.Now())
+		_, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(from.ID), models.AccountID(to.ID), 0, "", time.Now())
 		testutil.AssertAppError(t, err, "INVALID_INPUT")
 	})
 
 	t.Run("invalid_from_account", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		to := testutil.CreateTestCashAccount(t, db, user.ID)
 
-		_, err := txSvc.CreateTransfer(user.ID, 99999, to.ID, 1000, "", time.Now())
+		_, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(uuid.New()), models.AccountID(to.ID), 1000, "", time.Now())
 		testutil.AssertAppError(t, err, "ACCOUNT_NOT_FOUND")
 	})
 
 	t.Run("invalid_to_account", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		from := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
 
-		_, err := txSvc.CreateTransfer(user.ID, from.ID, 99999, 1000, "", time.Now())
+		_, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(from.ID), models.AccountID(uuid.New()), 1000, "", time.Now())
 		testutil.AssertAppError(t, err, "ACCOUNT_NOT_FOUND")
 	})
+
+	t.Run("credit_card_payment", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		checking := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+		card := testutil.CreateTestCreditCardAccount(t, db, user.ID, 4500) // $45 owed
+
+		_, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(checking.ID), models.AccountID(card.ID), 3000, "Card payment", time.Now())
+		testutil.AssertNoError(t, err)
+
+		checkingUpdated, err := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(checking.ID))
+		testutil.AssertNoError(t, err)
+		if checkingUpdated.Balance != 7000 {
+			t.Errorf("expected checking balance 7000, got %d", checkingUpdated.Balance)
+		}
+		cardUpdated, err := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(card.ID))
+		testutil.AssertNoError(t, err)
+		if cardUpdated.Balance != 1500 {
+			t.Errorf("expected card to owe 1500 after the payment, got %d", cardUpdated.Balance)
+		}
+	})
+
+	t.Run("investment_cash_both_ways", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		checking := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+		brokerage := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+
+		_, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(checking.ID), models.AccountID(brokerage.ID), 4000, "Fund brokerage", time.Now())
+		testutil.AssertNoError(t, err)
+		_, err = txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(brokerage.ID), models.AccountID(checking.ID), 1000, "Withdraw", time.Now())
+		testutil.AssertNoError(t, err)
+
+		// Withdrawing more cash than the brokerage holds is refused
+		_, err = txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(brokerage.ID), models.AccountID(checking.ID), 5000, "Withdraw", time.Now())
+		testutil.AssertAppError(t, err, "INSUFFICIENT_BALANCE")
+
+		var stored models.Account
+		db.Where("id = ?", brokerage.ID).First(&stored)
+		if stored.Balance != 3000 {
+			t.Errorf("expected brokerage cash 3000, got %d", stored.Balance)
+		}
+	})
+
+	t.Run("rejects_unsupported_combinations", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		checking := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+		card := testutil.CreateTestCreditCardAccount(t, db, user.ID, 0)
+		card2 := testutil.CreateTestCreditCardAccount(t, db, user.ID, 0)
+		loan := testutil.CreateTestDebtAccount(t, db, user.ID, 100000)
+		brokerage := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		brokerage2 := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		db.Model(brokerage).Update("balance", 10000)
+
+		for name, pair := range map[string][2]*models.Account{
+			"card_to_cash":             {card, checking},
+			"card_to_card":             {card, card2},
+			"cash_to_debt":             {checking, loan},
+			"debt_to_cash":             {loan, checking},
+			"investment_to_card":       {brokerage, card},
+			"investment_to_investment": {brokerage, brokerage2},
+		} {
+			_, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(pair[0].ID), models.AccountID(pair[1].ID), 1000, "", time.Now())
+			if err == nil {
+				t.Errorf("%s: expected INVALID_TRANSFER, got success", name)
+				continue
+			}
+			testutil.AssertAppError(t, err, "INVALID_TRANSFER")
+		}
+
+		var count int64
+		db.Model(&models.Transaction{}).Where("user_id = ?", user.ID).Count(&count)
+		if count != 0 {
+			t.Errorf("expected no transfers to be recorded, got %d", count)
+		}
+		cardUpdated, err := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(card.ID))
+		testutil.AssertNoError(t, err)
+		if cardUpdated.Balance != 0 {
+			t.Errorf("expected card balance unchanged at 0, got %d", cardUpdated.Balance)
+		}
+	})
 }
 
 func TestGetTransactionByID(t *testing.T) {
+	t.Parallel()
 	t.Run("found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 		created := testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeIncome, 1000)
 
-		tx, err := txSvc.GetTransactionByID(user.ID, created.ID)
+		tx, err := txSvc.GetTransactionByID(models.UserID(user.ID), models.TransactionID(created.ID))
 		testutil.AssertNoError(t, err)
 
 		if tx.ID != created.ID {
-			t.Errorf("expected transaction ID %d, got %d", created.ID, tx.ID)
+			t.Errorf("expected transaction ID %s, got %s", created.ID, tx.ID)
 		}
 		if tx.Amount != 1000 {
 			t.Errorf("expected amount 1000, got %d", tx.Amount)
@@ -273,95 +796,254 @@ func TestGetTransactionByID(t *testing.T) {
 	})
 
 	t.Run("not_found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 
-		_, err := txSvc.GetTransactionByID(user.ID, 99999)
+		_, err := txSvc.GetTransactionByID(models.UserID(user.ID), models.TransactionID(uuid.New()))
 		testutil.AssertAppError(t, err, "TRANSACTION_NOT_FOUND")
 	})
 
 	t.Run("wrong_user", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user1 := testutil.CreateTestUser(t, db)
 		user2 := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user1.ID)
 		created := testutil.CreateTestTransaction(t, db, user1.ID, account.ID, models.TransactionTypeIncome, 1000)
 
-		_, err := txSvc.GetTransactionByID(user2.ID, created.ID)
+		_, err := txSvc.GetTransactionByID(models.UserID(user2.ID), models.TransactionID(created.ID))
 		testutil.AssertAppError(t, err, "TRANSACTION_NOT_FOUND")
 	})
 }
 
-func TestGetAccountTransactions(t *testing.T) {
-	t.Run("returns_account_transactions", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+func TestGetFlaggedTransactions(t *testing.T) {
+	t.Parallel()
+	setup := func(t *testing.T) (*gorm.DB, TransactionServicer, *models.User, *models.Account) {
+		t.Helper()
+		db := testutil.WithTx(t)
+		txSvc := NewTransactionService(db, NewAccountService(db, nil), nil)
 		user := testutil.CreateTestUser(t, db)
-		account := testutil.CreateTestCashAccount(t, db, user.ID)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 1000000)
+		db.Model(user).Update("large_transaction_threshold", 50000)
+		return db, txSvc, user, account
+	}
 
-		testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeIncome, 1000)
-		testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeExpense, 500)
+	t.Run("above_and_below_threshold", func(t *testing.T) {
+		db, txSvc, user, account := setup(t)
+		expense := testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeExpense, 50000)
+		income := testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeIncome, 120000)
+		testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeExpense, 49999)
+		db.Create(&models.Transaction{UserID: user.ID, AccountID: account.ID, Type: models.TransactionTypeExpense,
+			Amount: 90000, Date: time.Now().AddDate(0, 0, -120)})
 
-		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := txSvc.GetAccountTransactions(user.ID, account.ID, page, TransactionFilter{})
+		result, err := txSvc.GetFlaggedTransactions(models.UserID(user.ID), pagination.PageRequest{})
 		testutil.AssertNoError(t, err)
 
-		if result.TotalItems != 2 {
-			t.Errorf("expected 2 transactions, got %d", result.TotalItems)
+		if result.TotalItems != 2 || len(result.Data) != 2 {
+			t.Fatalf("expected 2 flagged transactions, got %d", result.TotalItems)
+		}
+		ids := map[string]bool{result.Data[0].ID: true, result.Data[1].ID: true}
+		if !ids[expense.ID] || !ids[income.ID] {
+			t.Errorf("expected expense and income at or over threshold, got %v", ids)
+		}
+		for _, tx := range result.Data {
+			if !tx.Flagged {
+				t.Errorf("expected transaction %s to be flagged", tx.ID)
+			}
 		}
 	})
 
-	t.Run("pagination", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
-		user := testutil.CreateTestUser(t, db)
-		account := testutil.CreateTestCashAccount(t, db, user.ID)
+	t.Run("transfers_excluded", func(t *testing.T) {
+		db, txSvc, user, account := setup(t)
+		savings := testutil.CreateTestCashAccount(t, db, user.ID)
 
-		for i := 0; i < 5; i++ {
-			testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeIncome, int64((i+1)*1000))
+		transfer, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(account.ID), models.AccountID(savings.ID), 500000, "Move to savings", time.Now())
+		testutil.AssertNoError(t, err)
+		if transfer.Flagged {
+			t.Error("expected transfer not to be flagged")
 		}
 
-		page := pagination.PageRequest{Page: 1, PageSize: 2}
-		result, err := txSvc.GetAccountTransactions(user.ID, account.ID, page, TransactionFilter{})
+		result, err := txSvc.GetFlaggedTransactions(models.UserID(user.ID), pagination.PageRequest{})
 		testutil.AssertNoError(t, err)
-
-		if result.TotalItems != 5 {
-			t.Errorf("expected total 5, got %d", result.TotalItems)
-		}
-		if len(result.Data) != 2 {
-			t.Errorf("expected 2 items on page, got %d", len(result.Data))
+		if result.TotalItems != 0 {
+			t.Errorf("expected no flagged transactions, got %d", result.TotalItems)
 		}
-		if result.TotalPages != 3 {
-			t.Errorf("expected 3 total pages, got %d", result.TotalPages)
+	})
+
+	t.Run("zero_threshold_flags_nothing", func(t *testing.T) {
+		db, txSvc, user, account := setup(t)
+		db.Model(user).Update("large_transaction_threshold", 0)
+		testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeExpense, 900000)
+
+		result, err := txSvc.GetFlaggedTransactions(models.UserID(user.ID), pagination.PageRequest{})
+		testutil.AssertNoError(t, err)
+		if result.TotalItems != 0 || result.Data == nil || len(result.Data) != 0 {
+			t.Errorf("expected an empty page, got %+v", result)
 		}
 	})
 
-	t.Run("filter_by_type", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
-		user := testutil.CreateTestUser(t, db)
-		account := testutil.CreateTestCashAccount(t, db, user.ID)
+	t.Run("other_users_not_included", func(t *testing.T) {
+		db, txSvc, user, _ := setup(t)
+		other := testutil.CreateTestUser(t, db)
+		otherAccount := testutil.CreateTestCashAccount(t, db, other.ID)
+		testutil.CreateTestTransaction(t, db, other.ID, otherAccount.ID, models.TransactionTypeExpense, 900000)
 
-		testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeIncome, 1000)
+		result, err := txSvc.GetFlaggedTransactions(models.UserID(user.ID), pagination.PageRequest{})
+		testutil.AssertNoError(t, err)
+		if result.TotalItems != 0 {
+			t.Errorf("expected no flagged transactions, got %d", result.TotalItems)
+		}
+	})
+
+	t.Run("flagged_on_reads", func(t *testing.T) {
+		db, txSvc, user, account := setup(t)
+
+		created, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 60000, "Laptop", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+		if !created.Flagged {
+			t.Error("expected created transaction to be flagged")
+		}
+		testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeExpense, 100)
+
+		got, err := txSvc.GetTransactionByID(models.UserID(user.ID), models.TransactionID(created.ID))
+		testutil.AssertNoError(t, err)
+		if !got.Flagged {
+			t.Error("expected fetched transaction to be flagged")
+		}
+
+		list, err := txSvc.GetUserTransactions(models.UserID(user.ID), pagination.PageRequest{}, TransactionFilter{})
+		testutil.AssertNoError(t, err)
+		for _, tx := range list.Data {
+			if tx.Flagged != (tx.ID == created.ID) {
+				t.Errorf("transaction %s: unexpected flagged=%v", tx.ID, tx.Flagged)
+			}
+		}
+
+		amount := int64(200)
+		updated, err := txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(created.ID), TransactionUpdateFields{Amount: &amount})
+		testutil.AssertNoError(t, err)
+		if updated.Flagged {
+			t.Error("expected transaction under threshold after update not to be flagged")
+		}
+		accountList, err := txSvc.GetAccountTransactions(models.UserID(user.ID), models.AccountID(account.ID), pagination.PageRequest{}, TransactionFilter{})
+		testutil.AssertNoError(t, err)
+		for _, tx := range accountList.Data {
+			if tx.Flagged {
+				t.Errorf("transaction %s should not be flagged", tx.ID)
+			}
+		}
+	})
+}
+
+func TestGetAccountTransactions(t *testing.T) {
+	t.Parallel()
+	t.Run("returns_account_transactions", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeIncome, 1000)
+		testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeExpense, 500)
+
+		page := pagination.PageRequest{Page: 1, PageSize: 20}
+		result, err := txSvc.GetAccountTransactions(models.UserID(user.ID), models.AccountID(account.ID), page, TransactionFilter{})
+		testutil.AssertNoError(t, err)
+
+		if result.TotalItems != 2 {
+			t.Errorf("expected 2 transactions, got %d", result.TotalItems)
+		}
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		for i := 0; i < 5; i++ {
+			testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeIncome, int64((i+1)*1000))
+		}
+
+		page := pagination.PageRequest{Page: 1, PageSize: 2}
+		result, err := txSvc.GetAccountTransactions(models.UserID(user.ID), models.AccountID(account.ID), page, TransactionFilter{})
+		testutil.AssertNoError(t, err)
+
+		if result.TotalItems != 5 {
+			t.Errorf("expected total 5, got %d", result.TotalItems)
+		}
+		if len(result.Data) != 2 {
+			t.Errorf("expected 2 items on page, got %d", len(result.Data))
+		}
+		if result.TotalPages != 3 {
+			t.Errorf("expected 3 total pages, got %d", result.TotalPages)
+		}
+	})
+
+	t.Run("pagination_without_total", func(t *testing.T) {
+		db := testutil.SetupTestDB(t)
+		t.Cleanup(func() { testutil.TeardownTestDB(t, db) })
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		for i := 0; i < 5; i++ {
+			testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeIncome, int64((i+1)*1000))
+		}
+
+		var countQueries int
+		if err := db.Callback().Query().After("gorm:query").Register("test:count_queries", func(tx *gorm.DB) {
+			if strings.Contains(strings.ToLower(tx.Statement.SQL.String()), "count(") {
+				countQueries++
+			}
+		}); err != nil {
+			t.Fatalf("failed to register callback: %v", err)
+		}
+
+		withTotal := false
+		page := pagination.PageRequest{Page: 1, PageSize: 2, WithTotal: &withTotal}
+		result, err := txSvc.GetAccountTransactions(models.UserID(user.ID), models.AccountID(account.ID), page, TransactionFilter{})
+		testutil.AssertNoError(t, err)
+
+		if countQueries != 0 {
+			t.Errorf("expected no count queries, got %d", countQueries)
+		}
+		if result.TotalItems != pagination.UnknownTotal || result.TotalPages != 0 {
+			t.Errorf("expected unknown total (-1, 0 pages), got %d, %d", result.TotalItems, result.TotalPages)
+		}
+		if len(result.Data) != 2 {
+			t.Errorf("expected 2 items on page, got %d", len(result.Data))
+		}
+
+		// The default still counts
+		result, err = txSvc.GetAccountTransactions(models.UserID(user.ID), models.AccountID(account.ID), pagination.PageRequest{Page: 1, PageSize: 2}, TransactionFilter{})
+		testutil.AssertNoError(t, err)
+		if countQueries != 1 || result.TotalItems != 5 {
+			t.Errorf("expected one count query and total 5, got %d queries and total %d", countQueries, result.TotalItems)
+		}
+	})
+
+	t.Run("filter_by_type", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeIncome, 1000)
 		testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeExpense, 500)
 		testutil.CreateTestTransaction(t, db, user.ID, account.ID, models.TransactionTypeIncome, 2000)
 
 		incomeType := models.TransactionTypeIncome
 		filter := TransactionFilter{Type: &incomeType}
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := txSvc.GetAccountTransactions(user.ID, account.ID, page, filter)
+		result, err := txSvc.GetAccountTransactions(models.UserID(user.ID), models.AccountID(account.ID), page, filter)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 2 {
@@ -370,10 +1052,9 @@ func TestGetAccountTransactions(t *testing.T) {
 	})
 
 	t.Run("filter_by_amount_range", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 
@@ -385,7 +1066,7 @@ func TestGetAccountTransactions(t *testing.T) {
 		maxAmt := int64(2000)
 		filter := TransactionFilter{MinAmount: &minAmt, MaxAmount: &maxAmt}
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := txSvc.GetAccountTransactions(user.ID, account.ID, page, filter)
+		result, err := txSvc.GetAccountTransactions(models.UserID(user.ID), models.AccountID(account.ID), page, filter)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 1 {
@@ -394,10 +1075,9 @@ func TestGetAccountTransactions(t *testing.T) {
 	})
 
 	t.Run("filter_by_date_range", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 
@@ -418,7 +1098,7 @@ func TestGetAccountTransactions(t *testing.T) {
 		fromDate := now.AddDate(0, -1, 0)
 		filter := TransactionFilter{FromDate: &fromDate}
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := txSvc.GetAccountTransactions(user.ID, account.ID, page, filter)
+		result, err := txSvc.GetAccountTransactions(models.UserID(user.ID), models.AccountID(account.ID), page, filter)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 1 {
@@ -426,25 +1106,255 @@ func TestGetAccountTransactions(t *testing.T) {
 		}
 	})
 
+	t.Run("transfers_listed_on_both_accounts", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		a := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+		b := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		transfer, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(a.ID), models.AccountID(b.ID), 3000, "Transfer", time.Now())
+		testutil.AssertNoError(t, err)
+		testutil.CreateTestTransaction(t, db, user.ID, b.ID, models.TransactionTypeExpense, 500)
+
+		page := pagination.PageRequest{Page: 1, PageSize: 20}
+		for _, tt := range []struct {
+			account   *models.Account
+			total     int64
+			direction models.TransactionDirection
+		}{
+			{a, 1, models.TransactionDirectionOut},
+			{b, 2, models.TransactionDirectionIn},
+		} {
+			result, err := txSvc.GetAccountTransactions(models.UserID(user.ID), models.AccountID(tt.account.ID), page, TransactionFilter{})
+			testutil.AssertNoError(t, err)
+			if result.TotalItems != tt.total {
+				t.Errorf("expected %d transactions, got %d", tt.total, result.TotalItems)
+			}
+			for _, txn := range result.Data {
+				if txn.ID == transfer.ID && (txn.Direction != tt.direction || txn.Amount != 3000) {
+					t.Errorf("expected transfer %s 3000, got %s %d", tt.direction, txn.Direction, txn.Amount)
+				}
+				if txn.Type == models.TransactionTypeExpense && txn.Direction != models.TransactionDirectionOut {
+					t.Errorf("expected expense direction out, got %q", txn.Direction)
+				}
+			}
+		}
+	})
+
 	t.Run("invalid_account", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		_, err := txSvc.GetAccountTransactions(user.ID, 99999, page, TransactionFilter{})
+		_, err := txSvc.GetAccountTransactions(models.UserID(user.ID), models.AccountID(uuid.New()), page, TransactionFilter{})
 		testutil.AssertAppError(t, err, "ACCOUNT_NOT_FOUND")
 	})
 }
 
+func TestCreateSplitTransfer(t *testing.T) {
+	t.Parallel()
+	t.Run("three_way_split", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		from := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+		a := testutil.CreateTestCashAccount(t, db, user.ID)
+		b := testutil.CreateTestCashAccount(t, db, user.ID)
+		c := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		legs := []SplitTransferLeg{
+			{ToAccountID: models.AccountID(a.ID), Amount: 1000},
+			{ToAccountID: models.AccountID(b.ID), Amount: 2000},
+			{ToAccountID: models.AccountID(c.ID), Amount: 3000},
+		}
+		txs, err := txSvc.CreateSplitTransfer(models.UserID(user.ID), models.AccountID(from.ID), legs, "Allowances", time.Now())
+		testutil.AssertNoError(t, err)
+
+		if len(txs) != 3 {
+			t.Fatalf("expected 3 legs, got %d", len(txs))
+		}
+		for _, tx := range txs {
+			if tx.TransferGroupID == nil || *tx.TransferGroupID != *txs[0].TransferGroupID {
+				t.Errorf("expected every leg to share a transfer group, got %v", tx.TransferGroupID)
+			}
+		}
+
+		want := map[string]int64{from.ID: 4000, a.ID: 1000, b.ID: 2000, c.ID: 3000}
+		for id, balance := range want {
+			acct, err := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(id))
+			testutil.AssertNoError(t, err)
+			if acct.Balance != balance {
+				t.Errorf("expected balance %d for %s, got %d", balance, acct.Name, acct.Balance)
+			}
+		}
+	})
+
+	t.Run("insufficient_balance_rolls_back", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		from := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 5000)
+		a := testutil.CreateTestCashAccount(t, db, user.ID)
+		b := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		// Each leg alone fits the balance; together they do not
+		legs := []SplitTransferLeg{
+			{ToAccountID: models.AccountID(a.ID), Amount: 3000},
+			{ToAccountID: models.AccountID(b.ID), Amount: 3000},
+		}
+		_, err := txSvc.CreateSplitTransfer(models.UserID(user.ID), models.AccountID(from.ID), legs, "", time.Now())
+		testutil.AssertAppError(t, err, "INSUFFICIENT_BALANCE")
+
+		var count int64
+		db.Model(&models.Transaction{}).Where("account_id = ?", from.ID).Count(&count)
+		if count != 0 {
+			t.Errorf("expected no transfer legs, got %d", count)
+		}
+		want := map[string]int64{from.ID: 5000, a.ID: 0, b.ID: 0}
+		for id, balance := range want {
+			acct, err := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(id))
+			testutil.AssertNoError(t, err)
+			if acct.Balance != balance {
+				t.Errorf("expected balance %d for %s, got %d", balance, acct.Name, acct.Balance)
+			}
+		}
+	})
+
+	t.Run("rejects_unsupported_leg", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		from := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+		savings := testutil.CreateTestCashAccount(t, db, user.ID)
+		loan := testutil.CreateTestDebtAccount(t, db, user.ID, 50000)
+
+		legs := []SplitTransferLeg{
+			{ToAccountID: models.AccountID(savings.ID), Amount: 1000},
+			{ToAccountID: models.AccountID(loan.ID), Amount: 1000},
+		}
+		_, err := txSvc.CreateSplitTransfer(models.UserID(user.ID), models.AccountID(from.ID), legs, "", time.Now())
+		testutil.AssertAppError(t, err, "INVALID_TRANSFER")
+	})
+
+	t.Run("duplicate_destination", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		from := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 5000)
+		a := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		legs := []SplitTransferLeg{
+			{ToAccountID: models.AccountID(a.ID), Amount: 1000},
+			{ToAccountID: models.AccountID(a.ID), Amount: 1000},
+		}
+		_, err := txSvc.CreateSplitTransfer(models.UserID(user.ID), models.AccountID(from.ID), legs, "", time.Now())
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+
+	t.Run("deleting_one_leg_deletes_all", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		from := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+		a := testutil.CreateTestCashAccount(t, db, user.ID)
+		b := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		legs := []SplitTransferLeg{
+			{ToAccountID: models.AccountID(a.ID), Amount: 1500},
+			{ToAccountID: models.AccountID(b.ID), Amount: 2500},
+		}
+		txs, err := txSvc.CreateSplitTransfer(models.UserID(user.ID), models.AccountID(from.ID), legs, "", time.Now())
+		testutil.AssertNoError(t, err)
+
+		err = txSvc.DeleteTransaction(models.UserID(user.ID), models.TransactionID(txs[1].ID))
+		testutil.AssertNoError(t, err)
+
+		var count int64
+		db.Model(&models.Transaction{}).Where("transfer_group_id = ?", *txs[0].TransferGroupID).Count(&count)
+		if count != 0 {
+			t.Errorf("expected every leg deleted, %d remain", count)
+		}
+		want := map[string]int64{from.ID: 10000, a.ID: 0, b.ID: 0}
+		for id, balance := range want {
+			acct, err := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(id))
+			testutil.AssertNoError(t, err)
+			if acct.Balance != balance {
+				t.Errorf("expected balance %d for %s, got %d", balance, acct.Name, acct.Balance)
+			}
+		}
+	})
+}
+
+func TestGetTransfers(t *testing.T) {
+	t.Parallel()
+	t.Run("pairs_both_account_names", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		checking := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+		savings := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		transfer, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(checking.ID), models.AccountID(savings.ID), 3000, "To savings", time.Now())
+		testutil.AssertNoError(t, err)
+		testutil.CreateTestTransaction(t, db, user.ID, checking.ID, models.TransactionTypeIncome, 1000)
+		testutil.CreateTestTransaction(t, db, user.ID, checking.ID, models.TransactionTypeExpense, 500)
+
+		result, err := txSvc.GetTransfers(models.UserID(user.ID), pagination.PageRequest{}, TransactionFilter{})
+		testutil.AssertNoError(t, err)
+
+		if result.TotalItems != 1 || len(result.Data) != 1 {
+			t.Fatalf("expected only the transfer, got %d", result.TotalItems)
+		}
+		got := result.Data[0]
+		if got.ID != transfer.ID || got.Type != models.TransactionTypeTransfer {
+			t.Errorf("expected the transfer, got %+v", got)
+		}
+		if got.Account.Name != checking.Name || got.ToAccount == nil || got.ToAccount.Name != savings.Name {
+			t.Errorf("expected %s → %s, got %q → %v", checking.Name, savings.Name, got.Account.Name, got.ToAccount)
+		}
+	})
+
+	t.Run("ignores_type_filter_and_keeps_deleted_account_names", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		checking := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+		old := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		_, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(checking.ID), models.AccountID(old.ID), 2500, "Closing out", time.Now())
+		testutil.AssertNoError(t, err)
+		db.Delete(old)
+
+		expense := models.TransactionTypeExpense
+		result, err := txSvc.GetTransfers(models.UserID(user.ID), pagination.PageRequest{}, TransactionFilter{Type: &expense})
+		testutil.AssertNoError(t, err)
+
+		if len(result.Data) != 1 {
+			t.Fatalf("expected 1 transfer, got %d", len(result.Data))
+		}
+		if result.Data[0].ToAccount == nil || result.Data[0].ToAccount.Name != old.Name {
+			t.Errorf("expected the deleted account's name, got %v", result.Data[0].ToAccount)
+		}
+	})
+}
+
 func TestGetUserTransactions(t *testing.T) {
+	t.Parallel()
 	t.Run("lists_all_transactions_across_accounts", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		acct1 := testutil.CreateTestCashAccount(t, db, user.ID)
 		acct2 := testutil.CreateTestCashAccount(t, db, user.ID)
@@ -453,7 +1363,7 @@ func TestGetUserTransactions(t *testing.T) {
 		testutil.CreateTestTransaction(t, db, user.ID, acct2.ID, models.TransactionTypeIncome, 2000)
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := txSvc.GetUserTransactions(user.ID, page, TransactionFilter{})
+		result, err := txSvc.GetUserTransactions(models.UserID(user.ID), page, TransactionFilter{})
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 2 {
@@ -461,11 +1371,79 @@ func TestGetUserTransactions(t *testing.T) {
 		}
 	})
 
+	t.Run("transfers_counted_once", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		a := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+		b := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		_, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(a.ID), models.AccountID(b.ID), 3000, "Transfer", time.Now())
+		testutil.AssertNoError(t, err)
+		testutil.CreateTestTransaction(t, db, user.ID, a.ID, models.TransactionTypeIncome, 1000)
+
+		page := pagination.PageRequest{Page: 1, PageSize: 1}
+		result, err := txSvc.GetUserTransactions(models.UserID(user.ID), page, TransactionFilter{})
+		testutil.AssertNoError(t, err)
+		if result.TotalItems != 2 || result.TotalPages != 2 {
+			t.Errorf("expected 2 transactions on 2 pages, got %d on %d", result.TotalItems, result.TotalPages)
+		}
+
+		// Between the user's own accounts a transfer has no direction,
+		// unless the listing is narrowed to one side
+		transferType := models.TransactionTypeTransfer
+		page.PageSize = 20
+		result, err = txSvc.GetUserTransactions(models.UserID(user.ID), page, TransactionFilter{Type: &transferType})
+		testutil.AssertNoError(t, err)
+		if len(result.Data) != 1 || result.Data[0].Direction != "" {
+			t.Fatalf("expected one transfer without a direction, got %+v", result.Data)
+		}
+		result, err = txSvc.GetUserTransactions(models.UserID(user.ID), page, TransactionFilter{Type: &transferType, AccountID: &b.ID})
+		testutil.AssertNoError(t, err)
+		if len(result.Data) != 1 || result.Data[0].Direction != models.TransactionDirectionIn {
+			t.Fatalf("expected the transfer inbound to b, got %+v", result.Data)
+		}
+	})
+
+	t.Run("filters_by_status", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 1000, "", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+		uncleared, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 2000, "", time.Now(), false, models.TransactionStatusPending, nil)
+		testutil.AssertNoError(t, err)
+
+		page := pagination.PageRequest{Page: 1, PageSize: 20}
+		for status, want := range map[models.TransactionStatus]int64{
+			models.TransactionStatusPending: 1,
+			models.TransactionStatusCleared: 1,
+		} {
+			result, err := txSvc.GetUserTransactions(models.UserID(user.ID), page, TransactionFilter{Status: &status})
+			testutil.AssertNoError(t, err)
+			if result.TotalItems != want {
+				t.Errorf("%s: expected %d transactions, got %d", status, want, result.TotalItems)
+			}
+			if status == models.TransactionStatusPending && len(result.Data) == 1 && result.Data[0].ID != uncleared.ID {
+				t.Errorf("expected the uncleared transaction, got %s", result.Data[0].ID)
+			}
+		}
+
+		result, err := txSvc.GetUserTransactions(models.UserID(user.ID), page, TransactionFilter{})
+		testutil.AssertNoError(t, err)
+		if result.TotalItems != 2 {
+			t.Errorf("expected both transactions without a status filter, got %d", result.TotalItems)
+		}
+	})
+
 	t.Run("filters_by_type", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 
@@ -476,7 +1454,7 @@ func TestGetUserTransactions(t *testing.T) {
 		expenseType := models.TransactionTypeExpense
 		filter := TransactionFilter{Type: &expenseType}
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := txSvc.GetUserTransactions(user.ID, page, filter)
+		result, err := txSvc.GetUserTransactions(models.UserID(user.ID), page, filter)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 1 {
@@ -485,10 +1463,9 @@ func TestGetUserTransactions(t *testing.T) {
 	})
 
 	t.Run("filters_by_date_range", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 
@@ -508,7 +1485,7 @@ func TestGetUserTransactions(t *testing.T) {
 		toDate := now
 		filter := TransactionFilter{FromDate: &fromDate, ToDate: &toDate}
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := txSvc.GetUserTransactions(user.ID, page, filter)
+		result, err := txSvc.GetUserTransactions(models.UserID(user.ID), page, filter)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 1 {
@@ -517,10 +1494,9 @@ func TestGetUserTransactions(t *testing.T) {
 	})
 
 	t.Run("filters_by_category", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
@@ -534,7 +1510,7 @@ func TestGetUserTransactions(t *testing.T) {
 
 		filter := TransactionFilter{CategoryID: &cat.ID}
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := txSvc.GetUserTransactions(user.ID, page, filter)
+		result, err := txSvc.GetUserTransactions(models.UserID(user.ID), page, filter)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 1 {
@@ -543,10 +1519,9 @@ func TestGetUserTransactions(t *testing.T) {
 	})
 
 	t.Run("filters_by_amount_range", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 
@@ -558,7 +1533,7 @@ func TestGetUserTransactions(t *testing.T) {
 		maxAmt := int64(2000)
 		filter := TransactionFilter{MinAmount: &minAmt, MaxAmount: &maxAmt}
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := txSvc.GetUserTransactions(user.ID, page, filter)
+		result, err := txSvc.GetUserTransactions(models.UserID(user.ID), page, filter)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 1 {
@@ -567,10 +1542,9 @@ func TestGetUserTransactions(t *testing.T) {
 	})
 
 	t.Run("filters_by_account_id", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		acct1 := testutil.CreateTestCashAccount(t, db, user.ID)
 		acct2 := testutil.CreateTestCashAccount(t, db, user.ID)
@@ -581,7 +1555,7 @@ func TestGetUserTransactions(t *testing.T) {
 
 		filter := TransactionFilter{AccountID: &acct1.ID}
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := txSvc.GetUserTransactions(user.ID, page, filter)
+		result, err := txSvc.GetUserTransactions(models.UserID(user.ID), page, filter)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 2 {
@@ -590,10 +1564,9 @@ func TestGetUserTransactions(t *testing.T) {
 	})
 
 	t.Run("paginates_correctly", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 
@@ -602,7 +1575,7 @@ func TestGetUserTransactions(t *testing.T) {
 		}
 
 		page := pagination.PageRequest{Page: 1, PageSize: 2}
-		result, err := txSvc.GetUserTransactions(user.ID, page, TransactionFilter{})
+		result, err := txSvc.GetUserTransactions(models.UserID(user.ID), page, TransactionFilter{})
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 5 {
@@ -620,10 +1593,9 @@ func TestGetUserTransactions(t *testing.T) {
 	})
 
 	t.Run("user_isolation", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user1 := testutil.CreateTestUser(t, db)
 		user2 := testutil.CreateTestUser(t, db)
 		acct1 := testutil.CreateTestCashAccount(t, db, user1.ID)
@@ -633,7 +1605,7 @@ func TestGetUserTransactions(t *testing.T) {
 		testutil.CreateTestTransaction(t, db, user2.ID, acct2.ID, models.TransactionTypeIncome, 2000)
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := txSvc.GetUserTransactions(user1.ID, page, TransactionFilter{})
+		result, err := txSvc.GetUserTransactions(models.UserID(user1.ID), page, TransactionFilter{})
 		testutil.AssertNoError(t, err)
 
 		if result.TotalItems != 1 {
@@ -642,10 +1614,9 @@ func TestGetUserTransactions(t *testing.T) {
 	})
 
 	t.Run("orders_by_date_desc", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 
@@ -667,7 +1638,7 @@ func TestGetUserTransactions(t *testing.T) {
 		})
 
 		page := pagination.PageRequest{Page: 1, PageSize: 20}
-		result, err := txSvc.GetUserTransactions(user.ID, page, TransactionFilter{})
+		result, err := txSvc.GetUserTransactions(models.UserID(user.ID), page, TransactionFilter{})
 		testutil.AssertNoError(t, err)
 
 		if len(result.Data) != 3 {
@@ -683,77 +1654,104 @@ func TestGetUserTransactions(t *testing.T) {
 }
 
 func TestDeleteTransaction(t *testing.T) {
+	t.Parallel()
+	t.Run("refuses_an_investment_buy_funding_transfer", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		invSvc := NewInvestmentService(db, acctSvc, nil, nil, TradePriceCheck{})
+		user := testutil.CreateTestUser(t, db)
+		investmentAccount := testutil.CreateTestInvestmentAccount(t, db, user.ID)
+		cash := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		inv := testutil.CreateTestInvestment(t, db, investmentAccount.ID, testutil.CreateTestSecurity(t, db).ID)
+
+		buyTx, err := invSvc.RecordBuy(models.UserID(user.ID), models.InvestmentID(inv.ID), time.Now(), 1.0, 10000, 0, "", models.AccountID(cash.ID), false)
+		testutil.AssertNoError(t, err)
+
+		err = txSvc.DeleteTransaction(models.UserID(user.ID), models.TransactionID(*buyTx.CashTransactionID))
+		testutil.AssertAppError(t, err, "TRANSACTION_FUNDS_INVESTMENT")
+
+		var dbCash, dbInvestmentAccount models.Account
+		testutil.AssertNoError(t, db.First(&dbCash, "id = ?", cash.ID).Error)
+		testutil.AssertNoError(t, db.First(&dbInvestmentAccount, "id = ?", investmentAccount.ID).Error)
+		if dbCash.Balance != 90000 || dbInvestmentAccount.Balance != investmentAccount.Balance {
+			t.Errorf("expected balances untouched, got cash %d, investment account %d", dbCash.Balance, dbInvestmentAccount.Balance)
+		}
+		var count int64
+		db.Model(&models.Transaction{}).Where("id = ?", *buyTx.CashTransactionID).Count(&count)
+		if count != 1 {
+			t.Error("expected the funding transfer kept")
+		}
+	})
+
 	t.Run("income_reversal", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 
-		tx, err := txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeIncome, 5000, "Income", time.Now())
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 5000, "Income", time.Now(), false, "", nil)
 		testutil.AssertNoError(t, err)
 
 		// Verify balance increased
-		updated, _ := acctSvc.GetAccountByID(user.ID, account.ID)
+		updated, _ := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(account.ID))
 		if updated.Balance != 5000 {
 			t.Fatalf("expected balance 5000 after income, got %d", updated.Balance)
 		}
 
 		// Delete the income transaction
-		err = txSvc.DeleteTransaction(user.ID, tx.ID)
+		err = txSvc.DeleteTransaction(models.UserID(user.ID), models.TransactionID(tx.ID))
 		testutil.AssertNoError(t, err)
 
 		// Balance should be back to 0
-		updated, _ = acctSvc.GetAccountByID(user.ID, account.ID)
+		updated, _ = acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(account.ID))
 		if updated.Balance != 0 {
 			t.Errorf("expected balance 0 after deleting income, got %d", updated.Balance)
 		}
 	})
 
 	t.Run("expense_reversal", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
 
-		tx, err := txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeExpense, 3000, "Expense", time.Now())
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 3000, "Expense", time.Now(), false, "", nil)
 		testutil.AssertNoError(t, err)
 
 		// Verify balance decreased
-		updated, _ := acctSvc.GetAccountByID(user.ID, account.ID)
+		updated, _ := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(account.ID))
 		if updated.Balance != 7000 {
 			t.Fatalf("expected balance 7000 after expense, got %d", updated.Balance)
 		}
 
 		// Delete the expense
-		err = txSvc.DeleteTransaction(user.ID, tx.ID)
+		err = txSvc.DeleteTransaction(models.UserID(user.ID), models.TransactionID(tx.ID))
 		testutil.AssertNoError(t, err)
 
 		// Balance should be restored
-		updated, _ = acctSvc.GetAccountByID(user.ID, account.ID)
+		updated, _ = acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(account.ID))
 		if updated.Balance != 10000 {
 			t.Errorf("expected balance 10000 after deleting expense, got %d", updated.Balance)
 		}
 	})
 
 	t.Run("transfer_reversal", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		from := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
 		to := testutil.CreateTestCashAccount(t, db, user.ID)
 
-		tx, err := txSvc.CreateTransfer(user.ID, from.ID, to.ID, 4000, "Transfer", time.Now())
+		tx, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(from.ID), models.AccountID(to.ID), 4000, "Transfer", time.Now())
 		testutil.AssertNoError(t, err)
 
 		// Verify balances after transfer
-		fromUpdated, _ := acctSvc.GetAccountByID(user.ID, from.ID)
-		toUpdated, _ := acctSvc.GetAccountByID(user.ID, to.ID)
+		fromUpdated, _ := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(from.ID))
+		toUpdated, _ := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(to.ID))
 		if fromUpdated.Balance != 6000 {
 			t.Fatalf("expected from-balance 6000, got %d", fromUpdated.Balance)
 		}
@@ -762,12 +1760,12 @@ func TestDeleteTransaction(t *testing.T) {
 		}
 
 		// Delete the transfer
-		err = txSvc.DeleteTransaction(user.ID, tx.ID)
+		err = txSvc.DeleteTransaction(models.UserID(user.ID), models.TransactionID(tx.ID))
 		testutil.AssertNoError(t, err)
 
 		// Balances should be restored
-		fromUpdated, _ = acctSvc.GetAccountByID(user.ID, from.ID)
-		toUpdated, _ = acctSvc.GetAccountByID(user.ID, to.ID)
+		fromUpdated, _ = acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(from.ID))
+		toUpdated, _ = acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(to.ID))
 		if fromUpdated.Balance != 10000 {
 			t.Errorf("expected from-balance 10000 after delete, got %d", fromUpdated.Balance)
 		}
@@ -777,151 +1775,545 @@ func TestDeleteTransaction(t *testing.T) {
 	})
 
 	t.Run("not_found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 
-		err := txSvc.DeleteTransaction(user.ID, 99999)
+		err := txSvc.DeleteTransaction(models.UserID(user.ID), models.TransactionID(uuid.New()))
 		testutil.AssertAppError(t, err, "TRANSACTION_NOT_FOUND")
 	})
 
 	t.Run("wrong_user", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user1 := testutil.CreateTestUser(t, db)
 		user2 := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user1.ID)
 
-		tx, err := txSvc.CreateTransaction(user1.ID, account.ID, nil, models.TransactionTypeIncome, 1000, "", time.Now())
+		tx, err := txSvc.CreateTransaction(models.UserID(user1.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 1000, "", time.Now(), false, "", nil)
 		testutil.AssertNoError(t, err)
 
-		err = txSvc.DeleteTransaction(user2.ID, tx.ID)
+		err = txSvc.DeleteTransaction(models.UserID(user2.ID), models.TransactionID(tx.ID))
 		testutil.AssertAppError(t, err, "TRANSACTION_NOT_FOUND")
 	})
 }
 
-func TestUpdateTransaction(t *testing.T) {
-	t.Run("updates_amount_adjusts_balance", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+func TestBulkDeleteTransactions(t *testing.T) {
+	t.Parallel()
+	type fixture struct {
+		db       *gorm.DB
+		txSvc    TransactionServicer
+		user     *models.User
+		checking *models.Account
+		savings  *models.Account
+	}
+	setup := func(t *testing.T) *fixture {
+		t.Helper()
+		db := testutil.WithTx(t)
 		user := testutil.CreateTestUser(t, db)
-		account := testutil.CreateTestCashAccount(t, db, user.ID)
+		return &fixture{
+			db:       db,
+			txSvc:    NewTransactionService(db, NewAccountService(db, nil), nil),
+			user:     user,
+			checking: testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000),
+			savings:  testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 0),
+		}
+	}
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 12, 0, 0, 0, time.UTC) }
+	dateRange := func(from, to int) *TransactionFilter {
+		fromDate, toDate := day(from), day(to)
+		return &TransactionFilter{FromDate: &fromDate, ToDate: &toDate}
+	}
+	balance := func(t *testing.T, db *gorm.DB, account *models.Account) int64 {
+		t.Helper()
+		var a models.Account
+		if err := db.First(&a, "id = ?", account.ID).Error; err != nil {
+			t.Fatalf("failed to reload account: %v", err)
+		}
+		return a.Balance
+	}
 
-		tx, err := txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeIncome, 5000, "Salary", time.Now())
+	t.Run("filter_preview_and_execute_reverse_balances", func(t *testing.T) {
+		f := setup(t)
+
+		_, err := f.txSvc.CreateTransaction(models.UserID(f.user.ID), models.AccountID(f.checking.ID), nil, models.TransactionTypeExpense, 3000, "Import", day(5), false, "", nil)
+		testutil.AssertNoError(t, err)
+		_, err = f.txSvc.CreateTransaction(models.UserID(f.user.ID), models.AccountID(f.checking.ID), nil, models.TransactionTypeIncome, 5000, "Import", day(6), false, "", nil)
+		testutil.AssertNoError(t, err)
+		_, err = f.txSvc.CreateTransfer(models.UserID(f.user.ID), models.AccountID(f.checking.ID), models.AccountID(f.savings.ID), 2000, "Import", day(7))
+		testutil.AssertNoError(t, err)
+		_, err = f.txSvc.CreateTransaction(models.UserID(f.user.ID), models.AccountID(f.checking.ID), nil, models.TransactionTypeExpense, 700, "Hold", day(8), true, "", nil)
+		testutil.AssertNoError(t, err)
+		kept, err := f.txSvc.CreateTransaction(models.UserID(f.user.ID), models.AccountID(f.checking.ID), nil, models.TransactionTypeExpense, 999, "Outside range", day(20), false, "", nil)
 		testutil.AssertNoError(t, err)
 
-		// Balance should be 5000
-		acct, _ := acctSvc.GetAccountByID(user.ID, account.ID)
-		if acct.Balance != 5000 {
-			t.Fatalf("expected balance 5000, got %d", acct.Balance)
+		preview, err := f.txSvc.PreviewBulkDelete(models.UserID(f.user.ID), BulkDeleteSelection{Filter: dateRange(1, 10)})
+		testutil.AssertNoError(t, err)
+		if preview.Count != 4 || preview.TotalAmount != 10700 {
+			t.Errorf("expected 4 transactions totalling 10700, got %d and %d", preview.Count, preview.TotalAmount)
+		}
+		if preview.ConfirmationToken == "" || !preview.ExpiresAt.After(time.Now()) {
+			t.Errorf("expected a confirmation token with a future expiry, got %+v", preview)
+		}
+		// A dry run changes nothing
+		if got := balance(t, f.db, f.checking); got != 100000+5000-3000-2000-999 {
+			t.Errorf("dry run changed the balance to %d", got)
 		}
 
-		// Update amount from 5000 to 3000
-		newAmount := int64(3000)
-		updated, err := txSvc.UpdateTransaction(user.ID, tx.ID, TransactionUpdateFields{Amount: &newAmount})
+		result, err := f.txSvc.ExecuteBulkDelete(models.UserID(f.user.ID), preview.ConfirmationToken)
 		testutil.AssertNoError(t, err)
-
-		if updated.Amount != 3000 {
-			t.Errorf("expected updated amount 3000, got %d", updated.Amount)
+		if result.Deleted != 4 || result.Selection.Filter == nil {
+			t.Errorf("expected 4 deleted with the filter selection, got %+v", result)
+		}
+		if got := balance(t, f.db, f.checking); got != 100000-999 {
+			t.Errorf("expected checking balance %d, got %d", 100000-999, got)
+		}
+		if got := balance(t, f.db, f.savings); got != 0 {
+			t.Errorf("expected savings balance 0, got %d", got)
 		}
 
-		// Balance should be 3000 (reversed 5000 income, then applied 3000 income)
-		acct, _ = acctSvc.GetAccountByID(user.ID, account.ID)
-		if acct.Balance != 3000 {
-			t.Errorf("expected balance 3000, got %d", acct.Balance)
+		var remaining []models.Transaction
+		f.db.Find(&remaining)
+		if len(remaining) != 1 || remaining[0].ID != kept.ID {
+			t.Errorf("expected only the out-of-range transaction to remain, got %d", len(remaining))
 		}
 	})
 
-	t.Run("updates_type_income_to_expense", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
-		user := testutil.CreateTestUser(t, db)
+	t.Run("ids_selection", func(t *testing.T) {
+		f := setup(t)
+		a := testutil.CreateTestTransaction(t, f.db, f.user.ID, f.checking.ID, models.TransactionTypeIncome, 1000)
+		b := testutil.CreateTestTransaction(t, f.db, f.user.ID, f.checking.ID, models.TransactionTypeIncome, 2000)
+
+		preview, err := f.txSvc.PreviewBulkDelete(models.UserID(f.user.ID), BulkDeleteSelection{IDs: []string{a.ID, b.ID, a.ID}})
+		testutil.AssertNoError(t, err)
+		if preview.Count != 2 || preview.TotalAmount != 3000 {
+			t.Errorf("expected 2 transactions totalling 3000, got %d and %d", preview.Count, preview.TotalAmount)
+		}
+
+		result, err := f.txSvc.ExecuteBulkDelete(models.UserID(f.user.ID), preview.ConfirmationToken)
+		testutil.AssertNoError(t, err)
+		if result.Deleted != 2 || len(result.Selection.IDs) != 2 {
+			t.Errorf("unexpected result %+v", result)
+		}
+		if got := balance(t, f.db, f.checking); got != 97000 {
+			t.Errorf("expected balance 97000, got %d", got)
+		}
+	})
+
+	t.Run("invalid_selection", func(t *testing.T) {
+		f := setup(t)
+		tx := testutil.CreateTestTransaction(t, f.db, f.user.ID, f.checking.ID, models.TransactionTypeIncome, 1000)
+		other := testutil.CreateTestUser(t, f.db)
+		otherTx := testutil.CreateTestTransaction(t, f.db, other.ID, testutil.CreateTestCashAccount(t, f.db, other.ID).ID, models.TransactionTypeIncome, 1000)
+		from := day(1)
+
+		for name, selection := range map[string]BulkDeleteSelection{
+			"empty":      {},
+			"both":       {IDs: []string{tx.ID}, Filter: dateRange(1, 2)},
+			"open_range": {Filter: &TransactionFilter{FromDate: &from}},
+			"reversed":   {Filter: dateRange(10, 1)},
+			"unknown_id": {IDs: []string{tx.ID, uuid.New()}},
+			"other_user": {IDs: []string{tx.ID, otherTx.ID}},
+		} {
+			if _, err := f.txSvc.PreviewBulkDelete(models.UserID(f.user.ID), selection); err == nil {
+				t.Errorf("%s: expected an error", name)
+			}
+		}
+	})
+
+	t.Run("investment_transactions_rejected", func(t *testing.T) {
+		f := setup(t)
+		tx := testutil.CreateTestTransaction(t, f.db, f.user.ID, f.checking.ID, models.TransactionTypeInvestment, 1000)
+
+		_, err := f.txSvc.PreviewBulkDelete(models.UserID(f.user.ID), BulkDeleteSelection{IDs: []string{tx.ID}})
+		testutil.AssertAppError(t, err, "INVALID_TRANSACTION_TYPE")
+	})
+
+	t.Run("read_only_share_rejected", func(t *testing.T) {
+		f := setup(t)
+		viewer := testutil.CreateTestUser(t, f.db)
+		testutil.CreateTestAccountShare(t, f.db, f.user.ID, viewer.ID, models.ShareRoleViewer, f.checking.ID)
+		tx := testutil.CreateTestTransaction(t, f.db, f.user.ID, f.checking.ID, models.TransactionTypeIncome, 1000)
+
+		_, err := f.txSvc.PreviewBulkDelete(models.UserID(viewer.ID), BulkDeleteSelection{IDs: []string{tx.ID}})
+		testutil.AssertAppError(t, err, "SHARE_READ_ONLY")
+	})
+
+	t.Run("token_is_single_use_and_user_bound", func(t *testing.T) {
+		f := setup(t)
+		tx := testutil.CreateTestTransaction(t, f.db, f.user.ID, f.checking.ID, models.TransactionTypeIncome, 1000)
+		other := testutil.CreateTestUser(t, f.db)
+
+		preview, err := f.txSvc.PreviewBulkDelete(models.UserID(f.user.ID), BulkDeleteSelection{IDs: []string{tx.ID}})
+		testutil.AssertNoError(t, err)
+
+		_, err = f.txSvc.ExecuteBulkDelete(models.UserID(other.ID), preview.ConfirmationToken)
+		testutil.AssertAppError(t, err, "INVALID_CONFIRMATION_TOKEN")
+		_, err = f.txSvc.ExecuteBulkDelete(models.UserID(f.user.ID), "")
+		testutil.AssertAppError(t, err, "INVALID_CONFIRMATION_TOKEN")
+
+		_, err = f.txSvc.ExecuteBulkDelete(models.UserID(f.user.ID), preview.ConfirmationToken)
+		testutil.AssertNoError(t, err)
+		_, err = f.txSvc.ExecuteBulkDelete(models.UserID(f.user.ID), preview.ConfirmationToken)
+		testutil.AssertAppError(t, err, "INVALID_CONFIRMATION_TOKEN")
+	})
+
+	t.Run("expired_token", func(t *testing.T) {
+		f := setup(t)
+		tx := testutil.CreateTestTransaction(t, f.db, f.user.ID, f.checking.ID, models.TransactionTypeIncome, 1000)
+
+		preview, err := f.txSvc.PreviewBulkDelete(models.UserID(f.user.ID), BulkDeleteSelection{IDs: []string{tx.ID}})
+		testutil.AssertNoError(t, err)
+		f.db.Model(&models.PendingBulkDelete{}).Where("user_id = ?", f.user.ID).Update("expires_at", time.Now().Add(-time.Minute))
+
+		_, err = f.txSvc.ExecuteBulkDelete(models.UserID(f.user.ID), preview.ConfirmationToken)
+		testutil.AssertAppError(t, err, "CONFIRMATION_TOKEN_EXPIRED")
+
+		var count int64
+		f.db.Model(&models.Transaction{}).Count(&count)
+		if count != 1 {
+			t.Errorf("expected the transaction to survive, got %d", count)
+		}
+	})
+
+	t.Run("stale_selection_refused", func(t *testing.T) {
+		f := setup(t)
+		_, err := f.txSvc.CreateTransaction(models.UserID(f.user.ID), models.AccountID(f.checking.ID), nil, models.TransactionTypeExpense, 3000, "Import", day(5), false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		preview, err := f.txSvc.PreviewBulkDelete(models.UserID(f.user.ID), BulkDeleteSelection{Filter: dateRange(1, 10)})
+		testutil.AssertNoError(t, err)
+
+		// A new transaction lands in the range after the dry run
+		_, err = f.txSvc.CreateTransaction(models.UserID(f.user.ID), models.AccountID(f.checking.ID), nil, models.TransactionTypeExpense, 500, "Late", day(6), false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		_, err = f.txSvc.ExecuteBulkDelete(models.UserID(f.user.ID), preview.ConfirmationToken)
+		testutil.AssertAppError(t, err, "BULK_DELETE_STALE")
+		if got := balance(t, f.db, f.checking); got != 96500 {
+			t.Errorf("expected balance untouched at 96500, got %d", got)
+		}
+	})
+
+	t.Run("deletes_in_chunks", func(t *testing.T) {
+		f := setup(t)
+
+		n := bulkDeleteChunkSize*2 + 1
+		transactions := make([]models.Transaction, n)
+		for i := range transactions {
+			transactions[i] = models.Transaction{UserID: f.user.ID, AccountID: f.savings.ID, Type: models.TransactionTypeIncome, Amount: 100, Date: day(3)}
+		}
+		if err := f.db.CreateInBatches(transactions, 200).Error; err != nil {
+			t.Fatalf("failed to create transactions: %v", err)
+		}
+		f.db.Model(f.savings).Update("balance", int64(n*100))
+
+		preview, err := f.txSvc.PreviewBulkDelete(models.UserID(f.user.ID), BulkDeleteSelection{Filter: dateRange(1, 10)})
+		testutil.AssertNoError(t, err)
+		result, err := f.txSvc.ExecuteBulkDelete(models.UserID(f.user.ID), preview.ConfirmationToken)
+		testutil.AssertNoError(t, err)
+
+		if result.Deleted != int64(n) {
+			t.Errorf("expected %d deleted, got %d", n, result.Deleted)
+		}
+		if got := balance(t, f.db, f.savings); got != 0 {
+			t.Errorf("expected savings balance 0, got %d", got)
+		}
+	})
+}
+
+func TestBulkUpdateCategory(t *testing.T) {
+	t.Parallel()
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 12, 0, 0, 0, time.UTC) }
+	dateRange := func(from, to int) TransactionFilter {
+		fromDate, toDate := day(from), day(to)
+		return TransactionFilter{FromDate: &fromDate, ToDate: &toDate}
+	}
+
+	t.Run("uncategorized_expenses_in_range", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		txSvc := NewTransactionService(db, NewAccountService(db, nil), nil)
+		user := testutil.CreateTestUser(t, db)
+		other := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		otherAccount := testutil.CreateTestCashAccountWithBalance(t, db, other.ID, 100000)
+		groceries := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		dining := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		uid := models.UserID(user.ID)
+
+		create := func(userID string, accountID string, categoryID *string, txType models.TransactionType, date time.Time) *models.Transaction {
+			t.Helper()
+			tx, err := txSvc.CreateTransaction(models.UserID(userID), models.AccountID(accountID), categoryID, txType, 1000, "", date, false, "", nil)
+			testutil.AssertNoError(t, err)
+			return tx
+		}
+		inRange := []*models.Transaction{
+			create(user.ID, account.ID, nil, models.TransactionTypeExpense, day(5)),
+			create(user.ID, account.ID, nil, models.TransactionTypeExpense, day(20)),
+		}
+		untouched := []*models.Transaction{
+			create(user.ID, account.ID, &dining.ID, models.TransactionTypeExpense, day(10)), // already categorized
+			create(user.ID, account.ID, nil, models.TransactionTypeIncome, day(10)),         // other type
+			create(user.ID, account.ID, nil, models.TransactionTypeExpense, day(25)),        // outside the range
+			create(other.ID, otherAccount.ID, nil, models.TransactionTypeExpense, day(10)),  // someone else's
+		}
+
+		filter := dateRange(1, 21)
+		filter.Uncategorized = true
+		updated, err := txSvc.BulkUpdateCategory(uid, filter, &groceries.ID)
+		testutil.AssertNoError(t, err)
+		if updated != 2 {
+			t.Errorf("expected 2 updated, got %d", updated)
+		}
+
+		categoryOf := func(tx *models.Transaction) *string {
+			t.Helper()
+			var reloaded models.Transaction
+			if err := db.First(&reloaded, "id = ?", tx.ID).Error; err != nil {
+				t.Fatalf("failed to reload transaction: %v", err)
+			}
+			return reloaded.CategoryID
+		}
+		for _, tx := range inRange {
+			if got := categoryOf(tx); got == nil || *got != groceries.ID {
+				t.Errorf("expected transaction on %s to be in groceries, got %v", tx.Date.Format("2006-01-02"), got)
+			}
+		}
+		if got := categoryOf(untouched[0]); got == nil || *got != dining.ID {
+			t.Errorf("expected the categorized expense to keep its category, got %v", got)
+		}
+		for _, tx := range untouched[1:] {
+			if got := categoryOf(tx); got != nil {
+				t.Errorf("expected transaction %s to stay uncategorized, got %s", tx.ID, *got)
+			}
+		}
+
+		// A nil category clears it again
+		updated, err = txSvc.BulkUpdateCategory(uid, dateRange(1, 21), nil)
+		testutil.AssertNoError(t, err)
+		if updated != 4 {
+			t.Errorf("expected 4 income and expenses cleared, got %d", updated)
+		}
+		if got := categoryOf(inRange[0]); got != nil {
+			t.Errorf("expected the category to be cleared, got %s", *got)
+		}
+	})
+
+	t.Run("validates_category_and_filter", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		txSvc := NewTransactionService(db, NewAccountService(db, nil), nil)
+		user := testutil.CreateTestUser(t, db)
+		other := testutil.CreateTestUser(t, db)
+		expense := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		foreign := testutil.CreateTestCategory(t, db, other.ID, models.CategoryTypeExpense)
+		uid := models.UserID(user.ID)
+
+		_, err := txSvc.BulkUpdateCategory(uid, dateRange(1, 31), &foreign.ID)
+		testutil.AssertAppError(t, err, "CATEGORY_NOT_FOUND")
+
+		income := models.TransactionTypeIncome
+		filter := dateRange(1, 31)
+		filter.Type = &income
+		_, err = txSvc.BulkUpdateCategory(uid, filter, &expense.ID)
+		testutil.AssertAppError(t, err, "CATEGORY_TYPE_MISMATCH")
+
+		_, err = txSvc.BulkUpdateCategory(uid, TransactionFilter{}, &expense.ID)
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+
+		_, err = txSvc.BulkUpdateCategory(uid, dateRange(20, 10), &expense.ID)
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+}
+
+func TestUpdateTransaction(t *testing.T) {
+	t.Parallel()
+	t.Run("updates_amount_adjusts_balance", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 5000, "Salary", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		// Balance should be 5000
+		acct, _ := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(account.ID))
+		if acct.Balance != 5000 {
+			t.Fatalf("expected balance 5000, got %d", acct.Balance)
+		}
+
+		// Update amount from 5000 to 3000
+		newAmount := int64(3000)
+		updated, err := txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(tx.ID), TransactionUpdateFields{Amount: &newAmount})
+		testutil.AssertNoError(t, err)
+
+		if updated.Amount != 3000 {
+			t.Errorf("expected updated amount 3000, got %d", updated.Amount)
+		}
+
+		// Balance should be 3000 (reversed 5000 income, then applied 3000 income)
+		acct, _ = acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(account.ID))
+		if acct.Balance != 3000 {
+			t.Errorf("expected balance 3000, got %d", acct.Balance)
+		}
+	})
+
+	t.Run("sets_and_clears_original_amount_without_affecting_balance", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 2000, "Hotel", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		original := &OriginalAmount{Amount: 300000, Currency: "JPY"}
+		updated, err := txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(tx.ID), TransactionUpdateFields{Original: &original})
+		testutil.AssertNoError(t, err)
+		if updated.OriginalAmount == nil || *updated.OriginalAmount != 300000 || *updated.OriginalCurrency != "JPY" {
+			t.Errorf("expected original 300000 JPY, got %v %v", updated.OriginalAmount, updated.OriginalCurrency)
+		}
+		acct, _ := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(account.ID))
+		if acct.Balance != 8000 {
+			t.Errorf("expected balance to stay 8000, got %d", acct.Balance)
+		}
+
+		var cleared *OriginalAmount
+		updated, err = txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(tx.ID), TransactionUpdateFields{Original: &cleared})
+		testutil.AssertNoError(t, err)
+		if updated.OriginalAmount != nil || updated.OriginalCurrency != nil {
+			t.Errorf("expected original to be cleared, got %v %v", updated.OriginalAmount, updated.OriginalCurrency)
+		}
+		stored, _ := txSvc.GetTransactionByID(models.UserID(user.ID), models.TransactionID(tx.ID))
+		if stored.OriginalAmount != nil || stored.Amount != 2000 {
+			t.Errorf("expected stored original cleared and amount 2000, got %v and %d", stored.OriginalAmount, stored.Amount)
+		}
+	})
+
+	t.Run("marks_pending_transactions_cleared", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		from := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+		to := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		expense, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(from.ID), nil, models.TransactionTypeExpense, 2000, "Groceries", time.Now(), false, models.TransactionStatusPending, nil)
+		testutil.AssertNoError(t, err)
+		transfer, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(from.ID), models.AccountID(to.ID), 3000, "Savings", time.Now())
+		testutil.AssertNoError(t, err)
+		db.Model(transfer).Update("status", models.TransactionStatusPending)
+
+		cleared := models.TransactionStatusCleared
+		for _, id := range []string{expense.ID, transfer.ID} {
+			updated, err := txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(id), TransactionUpdateFields{Status: &cleared})
+			testutil.AssertNoError(t, err)
+			if updated.Status != models.TransactionStatusCleared {
+				t.Errorf("expected status cleared, got %q", updated.Status)
+			}
+		}
+
+		// Clearing leaves balances alone
+		acct, _ := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(from.ID))
+		if acct.Balance != 5000 {
+			t.Errorf("expected balance 5000, got %d", acct.Balance)
+		}
+
+		// Transfers still reject any other change
+		amount := int64(100)
+		_, err = txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(transfer.ID), TransactionUpdateFields{Status: &cleared, Amount: &amount})
+		testutil.AssertAppError(t, err, "TRANSACTION_NOT_EDITABLE")
+
+		invalid := models.TransactionStatus("posted")
+		_, err = txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(expense.ID), TransactionUpdateFields{Status: &invalid})
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+
+	t.Run("updates_type_income_to_expense", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
 
-		tx, err := txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeIncome, 5000, "Income", time.Now())
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 5000, "Income", time.Now(), false, "", nil)
 		testutil.AssertNoError(t, err)
 
 		// Verify balance is now 15000 (10000 initial + 5000 income)
-		acct, _ := acctSvc.GetAccountByID(user.ID, account.ID)
+		acct, _ := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(account.ID))
 		if acct.Balance != 15000 {
 			t.Fatalf("expected balance 15000, got %d", acct.Balance)
 		}
 
 		// Change type to expense
 		expenseType := models.TransactionTypeExpense
-		_, err = txSvc.UpdateTransaction(user.ID, tx.ID, TransactionUpdateFields{Type: &expenseType})
+		_, err = txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(tx.ID), TransactionUpdateFields{Type: &expenseType})
 		testutil.AssertNoError(t, err)
 
 		// Balance: reverse income(5000) → 15000 - 5000 = 10000, then apply expense(5000) → 10000 - 5000 = 5000
-		acct, _ = acctSvc.GetAccountByID(user.ID, account.ID)
+		acct, _ = acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(account.ID))
 		if acct.Balance != 5000 {
 			t.Errorf("expected balance 5000, got %d", acct.Balance)
 		}
 	})
 
 	t.Run("updates_type_expense_to_income", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
 
-		tx, err := txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeExpense, 3000, "Expense", time.Now())
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 3000, "Expense", time.Now(), false, "", nil)
 		testutil.AssertNoError(t, err)
 
 		// Verify balance is now 7000 (10000 initial - 3000 expense)
-		acct, _ := acctSvc.GetAccountByID(user.ID, account.ID)
+		acct, _ := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(account.ID))
 		if acct.Balance != 7000 {
 			t.Fatalf("expected balance 7000, got %d", acct.Balance)
 		}
 
 		// Change type to income
 		incomeType := models.TransactionTypeIncome
-		_, err = txSvc.UpdateTransaction(user.ID, tx.ID, TransactionUpdateFields{Type: &incomeType})
+		_, err = txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(tx.ID), TransactionUpdateFields{Type: &incomeType})
 		testutil.AssertNoError(t, err)
 
 		// Balance: reverse expense(3000) → 7000 + 3000 = 10000, then apply income(3000) → 10000 + 3000 = 13000
-		acct, _ = acctSvc.GetAccountByID(user.ID, account.ID)
+		acct, _ = acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(account.ID))
 		if acct.Balance != 13000 {
 			t.Errorf("expected balance 13000, got %d", acct.Balance)
 		}
 	})
 
 	t.Run("updates_account_id", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		acctA := testutil.CreateTestCashAccount(t, db, user.ID)
 		acctB := testutil.CreateTestCashAccount(t, db, user.ID)
 
-		tx, err := txSvc.CreateTransaction(user.ID, acctA.ID, nil, models.TransactionTypeIncome, 5000, "Income", time.Now())
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(acctA.ID), nil, models.TransactionTypeIncome, 5000, "Income", time.Now(), false, "", nil)
 		testutil.AssertNoError(t, err)
 
 		// A: 5000, B: 0
-		a, _ := acctSvc.GetAccountByID(user.ID, acctA.ID)
-		b, _ := acctSvc.GetAccountByID(user.ID, acctB.ID)
+		a, _ := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(acctA.ID))
+		b, _ := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(acctB.ID))
 		if a.Balance != 5000 || b.Balance != 0 {
 			t.Fatalf("expected A=5000 B=0, got A=%d B=%d", a.Balance, b.Balance)
 		}
 
 		// Move transaction to account B
-		_, err = txSvc.UpdateTransaction(user.ID, tx.ID, TransactionUpdateFields{AccountID: &acctB.ID})
+		_, err = txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(tx.ID), TransactionUpdateFields{AccountID: &acctB.ID})
 		testutil.AssertNoError(t, err)
 
 		// A: 0 (reversed), B: 5000 (applied)
-		a, _ = acctSvc.GetAccountByID(user.ID, acctA.ID)
-		b, _ = acctSvc.GetAccountByID(user.ID, acctB.ID)
+		a, _ = acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(acctA.ID))
+		b, _ = acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(acctB.ID))
 		if a.Balance != 0 {
 			t.Errorf("expected A balance 0, got %d", a.Balance)
 		}
@@ -931,43 +2323,41 @@ func TestUpdateTransaction(t *testing.T) {
 	})
 
 	t.Run("updates_category", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 		cat1 := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
 		cat2 := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
 
-		tx, err := txSvc.CreateTransaction(user.ID, account.ID, &cat1.ID, models.TransactionTypeExpense, 1000, "Expense", time.Now())
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &cat1.ID, models.TransactionTypeExpense, 1000, "Expense", time.Now(), false, "", nil)
 		testutil.AssertNoError(t, err)
 
 		// Update to cat2
 		cat2IDPtr := &cat2.ID
-		updated, err := txSvc.UpdateTransaction(user.ID, tx.ID, TransactionUpdateFields{CategoryID: &cat2IDPtr})
+		updated, err := txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(tx.ID), TransactionUpdateFields{CategoryID: &cat2IDPtr})
 		testutil.AssertNoError(t, err)
 
 		if updated.CategoryID == nil || *updated.CategoryID != cat2.ID {
-			t.Errorf("expected category_id %d, got %v", cat2.ID, updated.CategoryID)
+			t.Errorf("expected category_id %s, got %v", cat2.ID, updated.CategoryID)
 		}
 	})
 
 	t.Run("clears_category", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
 
-		tx, err := txSvc.CreateTransaction(user.ID, account.ID, &cat.ID, models.TransactionTypeExpense, 1000, "Expense", time.Now())
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &cat.ID, models.TransactionTypeExpense, 1000, "Expense", time.Now(), false, "", nil)
 		testutil.AssertNoError(t, err)
 
 		// Clear category: double pointer with nil inner
-		var nilUint *uint
-		updated, err := txSvc.UpdateTransaction(user.ID, tx.ID, TransactionUpdateFields{CategoryID: &nilUint})
+		var nilStr *string
+		updated, err := txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(tx.ID), TransactionUpdateFields{CategoryID: &nilStr})
 		testutil.AssertNoError(t, err)
 
 		if updated.CategoryID != nil {
@@ -976,19 +2366,18 @@ func TestUpdateTransaction(t *testing.T) {
 	})
 
 	t.Run("updates_description_and_date", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 
-		tx, err := txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeIncome, 1000, "Old desc", time.Now())
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 1000, "Old desc", time.Now(), false, "", nil)
 		testutil.AssertNoError(t, err)
 
 		newDesc := "New description"
 		newDate := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
-		updated, err := txSvc.UpdateTransaction(user.ID, tx.ID, TransactionUpdateFields{
+		updated, err := txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(tx.ID), TransactionUpdateFields{
 			Description: &newDesc,
 			Date:        &newDate,
 		})
@@ -1003,27 +2392,25 @@ func TestUpdateTransaction(t *testing.T) {
 	})
 
 	t.Run("rejects_transfer_transaction", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		from := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
 		to := testutil.CreateTestCashAccount(t, db, user.ID)
 
-		tx, err := txSvc.CreateTransfer(user.ID, from.ID, to.ID, 3000, "Transfer", time.Now())
+		tx, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(from.ID), models.AccountID(to.ID), 3000, "Transfer", time.Now())
 		testutil.AssertNoError(t, err)
 
 		newAmount := int64(5000)
-		_, err = txSvc.UpdateTransaction(user.ID, tx.ID, TransactionUpdateFields{Amount: &newAmount})
+		_, err = txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(tx.ID), TransactionUpdateFields{Amount: &newAmount})
 		testutil.AssertAppError(t, err, "TRANSACTION_NOT_EDITABLE")
 	})
 
 	t.Run("rejects_investment_transaction", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 
@@ -1038,200 +2425,468 @@ func TestUpdateTransaction(t *testing.T) {
 		db.Create(investTx)
 
 		newAmount := int64(5000)
-		_, err := txSvc.UpdateTransaction(user.ID, investTx.ID, TransactionUpdateFields{Amount: &newAmount})
+		_, err := txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(investTx.ID), TransactionUpdateFields{Amount: &newAmount})
 		testutil.AssertAppError(t, err, "TRANSACTION_NOT_EDITABLE")
 	})
 
 	t.Run("rejects_type_change_to_transfer", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 
-		tx, err := txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeIncome, 1000, "", time.Now())
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 1000, "", time.Now(), false, "", nil)
 		testutil.AssertNoError(t, err)
 
 		transferType := models.TransactionTypeTransfer
-		_, err = txSvc.UpdateTransaction(user.ID, tx.ID, TransactionUpdateFields{Type: &transferType})
+		_, err = txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(tx.ID), TransactionUpdateFields{Type: &transferType})
 		testutil.AssertAppError(t, err, "INVALID_TYPE_CHANGE")
 	})
 
 	t.Run("rejects_type_change_to_investment", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user.ID)
 
-		tx, err := txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeIncome, 1000, "", time.Now())
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 1000, "", time.Now(), false, "", nil)
 		testutil.AssertNoError(t, err)
 
 		investType := models.TransactionTypeInvestment
-		_, err = txSvc.UpdateTransaction(user.ID, tx.ID, TransactionUpdateFields{Type: &investType})
+		_, err = txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(tx.ID), TransactionUpdateFields{Type: &investType})
 		testutil.AssertAppError(t, err, "INVALID_TYPE_CHANGE")
 	})
 
 	t.Run("user_isolation", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user1 := testutil.CreateTestUser(t, db)
 		user2 := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccount(t, db, user1.ID)
 
-		tx, err := txSvc.CreateTransaction(user1.ID, account.ID, nil, models.TransactionTypeIncome, 1000, "", time.Now())
+		tx, err := txSvc.CreateTransaction(models.UserID(user1.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 1000, "", time.Now(), false, "", nil)
 		testutil.AssertNoError(t, err)
 
 		newAmount := int64(2000)
-		_, err = txSvc.UpdateTransaction(user2.ID, tx.ID, TransactionUpdateFields{Amount: &newAmount})
+		_, err = txSvc.UpdateTransaction(models.UserID(user2.ID), models.TransactionID(tx.ID), TransactionUpdateFields{Amount: &newAmount})
 		testutil.AssertAppError(t, err, "TRANSACTION_NOT_FOUND")
 	})
-}
 
-func TestGetSpendingByCategory(t *testing.T) {
-	now := time.Now()
-	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
-	to := from.AddDate(0, 1, -1).Add(23*time.Hour + 59*time.Minute + 59*time.Second)
-
-	t.Run("groups_by_category", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+	t.Run("category_type_mismatch", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
-		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
-
-		catA := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
-		catB := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+		incomeCat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeIncome)
 
-		// Two expenses for catA
-		_, err := txSvc.CreateTransaction(user.ID, account.ID, &catA.ID, models.TransactionTypeExpense, 3000, "", from.Add(time.Hour))
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 1000, "", time.Now(), false, "", nil)
 		testutil.AssertNoError(t, err)
-		_, err = txSvc.CreateTransaction(user.ID, account.ID, &catA.ID, models.TransactionTypeExpense, 2000, "", from.Add(2*time.Hour))
+
+		catID := &incomeCat.ID
+		_, err = txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(tx.ID), TransactionUpdateFields{CategoryID: &catID})
+		testutil.AssertAppError(t, err, "CATEGORY_TYPE_MISMATCH")
+	})
+
+	t.Run("other_users_category", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		other := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+		ownCat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		foreignCat := testutil.CreateTestCategory(t, db, other.ID, models.CategoryTypeExpense)
+
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &ownCat.ID, models.TransactionTypeExpense, 1000, "", time.Now(), false, "", nil)
 		testutil.AssertNoError(t, err)
 
-		// One expense for catB
-		_, err = txSvc.CreateTransaction(user.ID, account.ID, &catB.ID, models.TransactionTypeExpense, 1500, "", from.Add(3*time.Hour))
+		catID := &foreignCat.ID
+		_, err = txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(tx.ID), TransactionUpdateFields{CategoryID: &catID})
+		testutil.AssertAppError(t, err, "CATEGORY_NOT_FOUND")
+
+		got, err := txSvc.GetTransactionByID(models.UserID(user.ID), models.TransactionID(tx.ID))
 		testutil.AssertNoError(t, err)
+		if got.CategoryID == nil || *got.CategoryID != ownCat.ID {
+			t.Errorf("expected the category to stay %s, got %v", ownCat.ID, got.CategoryID)
+		}
+	})
+
+	t.Run("type_change_conflicts_with_existing_category", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+		expenseCat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
 
-		result, err := txSvc.GetSpendingByCategory(user.ID, from, to)
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &expenseCat.ID, models.TransactionTypeExpense, 1000, "", time.Now(), false, "", nil)
 		testutil.AssertNoError(t, err)
 
-		if len(result.Items) != 2 {
-			t.Fatalf("expected 2 items, got %d", len(result.Items))
-		}
-		if result.TotalSpent != 6500 {
-			t.Errorf("expected total_spent 6500, got %d", result.TotalSpent)
-		}
-		// First item should be catA (5000 > 1500)
-		if result.Items[0].Total != 5000 {
-			t.Errorf("expected first item total 5000, got %d", result.Items[0].Total)
-		}
-		if result.Items[1].Total != 1500 {
-			t.Errorf("expected second item total 1500, got %d", result.Items[1].Total)
-		}
+		newType := models.TransactionTypeIncome
+		_, err = txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(tx.ID), TransactionUpdateFields{Type: &newType})
+		testutil.AssertAppError(t, err, "CATEGORY_TYPE_MISMATCH")
 	})
 
-	t.Run("handles_uncategorized", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+	t.Run("type_and_category_change_together", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
-		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+		expenseCat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		incomeCat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeIncome)
 
-		_, err := txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeExpense, 2500, "", from.Add(time.Hour))
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &expenseCat.ID, models.TransactionTypeExpense, 1000, "", time.Now(), false, "", nil)
 		testutil.AssertNoError(t, err)
 
-		result, err := txSvc.GetSpendingByCategory(user.ID, from, to)
+		newType := models.TransactionTypeIncome
+		catID := &incomeCat.ID
+		updated, err := txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(tx.ID), TransactionUpdateFields{Type: &newType, CategoryID: &catID})
 		testutil.AssertNoError(t, err)
 
-		if len(result.Items) != 1 {
-			t.Fatalf("expected 1 item, got %d", len(result.Items))
-		}
-		if result.Items[0].CategoryName != "Uncategorized" {
-			t.Errorf("expected category_name 'Uncategorized', got %q", result.Items[0].CategoryName)
-		}
-		if result.Items[0].CategoryColor != "#9CA3AF" {
-			t.Errorf("expected color '#9CA3AF', got %q", result.Items[0].CategoryColor)
-		}
-		if result.Items[0].CategoryID != nil {
-			t.Errorf("expected nil category_id, got %v", result.Items[0].CategoryID)
+		if updated.CategoryID == nil || *updated.CategoryID != incomeCat.ID {
+			t.Errorf("expected category %s, got %v", incomeCat.ID, updated.CategoryID)
 		}
 	})
 
-	t.Run("filters_by_date_range", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+	t.Run("clearing_category_allowed", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
-		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
-
-		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
-
-		// January expense (out of range for February query)
-		jan := time.Date(now.Year(), 1, 15, 12, 0, 0, 0, time.UTC)
-		_, err := txSvc.CreateTransaction(user.ID, account.ID, &cat.ID, models.TransactionTypeExpense, 1000, "", jan)
-		testutil.AssertNoError(t, err)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 10000)
+		expenseCat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
 
-		// February expense (in range)
-		feb := time.Date(now.Year(), 2, 15, 12, 0, 0, 0, time.UTC)
-		_, err = txSvc.CreateTransaction(user.ID, account.ID, &cat.ID, models.TransactionTypeExpense, 2000, "", feb)
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &expenseCat.ID, models.TransactionTypeExpense, 1000, "", time.Now(), false, "", nil)
 		testutil.AssertNoError(t, err)
 
-		febFrom := time.Date(now.Year(), 2, 1, 0, 0, 0, 0, time.UTC)
-		febTo := time.Date(now.Year(), 2, 28, 23, 59, 59, 0, time.UTC)
-		result, err := txSvc.GetSpendingByCategory(user.ID, febFrom, febTo)
+		var cleared *string
+		updated, err := txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(tx.ID), TransactionUpdateFields{CategoryID: &cleared})
 		testutil.AssertNoError(t, err)
 
-		if result.TotalSpent != 2000 {
-			t.Errorf("expected total_spent 2000, got %d", result.TotalSpent)
+		if updated.CategoryID != nil {
+			t.Errorf("expected category cleared, got %v", *updated.CategoryID)
 		}
 	})
+}
 
-	t.Run("excludes_non_expense_types", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
-		user := testutil.CreateTestUser(t, db)
-		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
-		account2 := testutil.CreateTestCashAccount(t, db, user.ID)
+func TestGetSpendingByCurrency(t *testing.T) {
+	t.Parallel()
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 1, 31, 23, 59, 59, 0, time.UTC)
 
-		// Income transaction
-		_, err := txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeIncome, 5000, "", from.Add(time.Hour))
-		testutil.AssertNoError(t, err)
+	t.Run("groups_by_original_or_account_currency", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		other := testutil.CreateTestUser(t, db)
+		card := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		otherCard := testutil.CreateTestCashAccountWithBalance(t, db, other.ID, 100000)
+		jan := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
 
-		// Transfer transaction
-		_, err = txSvc.CreateTransfer(user.ID, account.ID, account2.ID, 1000, "", from.Add(2*time.Hour))
-		testutil.AssertNoError(t, err)
+		create := func(userID string, accountID string, txType models.TransactionType, amount models.Cents, original *OriginalAmount) {
+			t.Helper()
+			_, err := txSvc.CreateTransaction(models.UserID(userID), models.AccountID(accountID), nil, txType, amount, "", jan, false, "", original)
+			testutil.AssertNoError(t, err)
+		}
+		create(user.ID, card.ID, models.TransactionTypeExpense, 3000, nil)
+		create(user.ID, card.ID, models.TransactionTypeExpense, 2000, nil)
+		create(user.ID, card.ID, models.TransactionTypeExpense, 1150, &OriginalAmount{Amount: 1000, Currency: "EUR"})
+		create(user.ID, card.ID, models.TransactionTypeExpense, 600, &OriginalAmount{Amount: 500, Currency: "EUR"})
+		create(user.ID, card.ID, models.TransactionTypeIncome, 9000, &OriginalAmount{Amount: 8000, Currency: "GBP"})
+		create(other.ID, otherCard.ID, models.TransactionTypeExpense, 700, &OriginalAmount{Amount: 100000, Currency: "JPY"})
 
-		result, err := txSvc.GetSpendingByCategory(user.ID, from, to)
+		result, err := txSvc.GetSpendingByCurrency(context.Background(), models.UserID(user.ID), from, to, false)
 		testutil.AssertNoError(t, err)
 
-		if result.TotalSpent != 0 {
-			t.Errorf("expected total_spent 0, got %d", result.TotalSpent)
+		if len(result.Items) != 2 {
+			t.Fatalf("expected 2 currencies, got %+v", result.Items)
 		}
-		if len(result.Items) != 0 {
-			t.Errorf("expected 0 items, got %d", len(result.Items))
+		want := []SpendingByCurrencyItem{
+			{Currency: card.Currency, OriginalTotal: 5000, Total: 5000, Count: 2},
+			{Currency: "EUR", OriginalTotal: 1500, Total: 1750, Count: 2},
+		}
+		for i, w := range want {
+			if result.Items[i] != w {
+				t.Errorf("item %d: expected %+v, got %+v", i, w, result.Items[i])
+			}
+		}
+		if result.TotalSpent != 6750 {
+			t.Errorf("expected total spent 6750, got %d", result.TotalSpent)
+		}
+
+		// Balances only ever move by the booked amount
+		acct, _ := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(card.ID))
+		if acct.Balance != 100000-6750+9000 {
+			t.Errorf("expected balance %d, got %d", 100000-6750+9000, acct.Balance)
 		}
 	})
 
-	t.Run("returns_empty_for_no_expenses", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+	t.Run("empty_range", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		txSvc := NewTransactionService(db, NewAccountService(db, nil), nil)
 		user := testutil.CreateTestUser(t, db)
 
-		result, err := txSvc.GetSpendingByCategory(user.ID, from, to)
+		result, err := txSvc.GetSpendingByCurrency(context.Background(), models.UserID(user.ID), from, to, false)
 		testutil.AssertNoError(t, err)
-
+		if result.Items == nil || len(result.Items) != 0 || result.TotalSpent != 0 {
+			t.Errorf("expected an empty breakdown, got %+v", result)
+		}
+	})
+}
+
+func TestGetSpendingByAccount(t *testing.T) {
+	t.Parallel()
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 1, 31, 23, 59, 59, 0, time.UTC)
+
+	t.Run("totals_per_account_excluding_transfers", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		card := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		wallet := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		jan := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(card.ID), nil, models.TransactionTypeExpense, 3000, "", jan, false, "", nil)
+		testutil.AssertNoError(t, err)
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(card.ID), nil, models.TransactionTypeExpense, 2000, "", jan, false, "", nil)
+		testutil.AssertNoError(t, err)
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(wallet.ID), nil, models.TransactionTypeExpense, 1500, "", jan, false, "", nil)
+		testutil.AssertNoError(t, err)
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(wallet.ID), nil, models.TransactionTypeExpense, 900, "", time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC), false, "", nil)
+		testutil.AssertNoError(t, err)
+		_, err = txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(wallet.ID), models.AccountID(card.ID), 50000, "Top up", jan)
+		testutil.AssertNoError(t, err)
+
+		result, err := txSvc.GetSpendingByAccount(models.UserID(user.ID), from, to, false)
+		testutil.AssertNoError(t, err)
+
+		if len(result.Items) != 2 {
+			t.Fatalf("expected 2 accounts, got %+v", result.Items)
+		}
+		if result.Items[0].AccountID != card.ID || result.Items[0].Total != 5000 || result.Items[0].Count != 2 {
+			t.Errorf("expected card first with 5000 over 2 expenses, got %+v", result.Items[0])
+		}
+		if result.Items[1].AccountID != wallet.ID || result.Items[1].Total != 1500 || result.Items[1].Count != 1 {
+			t.Errorf("expected wallet with 1500 over 1 expense, got %+v", result.Items[1])
+		}
+		if result.TotalSpent != 6500 {
+			t.Errorf("expected total_spent 6500, got %d", result.TotalSpent)
+		}
+	})
+
+	t.Run("includes_inactive_accounts", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 1200, "", time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC), false, "", nil)
+		testutil.AssertNoError(t, err)
+		inactive := false
+		_, err = acctSvc.UpdateAccount(models.UserID(user.ID), models.AccountID(account.ID), AccountUpdateFields{IsActive: &inactive})
+		testutil.AssertNoError(t, err)
+
+		result, err := txSvc.GetSpendingByAccount(models.UserID(user.ID), from, to, false)
+		testutil.AssertNoError(t, err)
+
+		if len(result.Items) != 1 || result.Items[0].AccountID != account.ID || result.Items[0].IsActive {
+			t.Errorf("expected the inactive account to be listed, got %+v", result.Items)
+		}
+	})
+}
+
+func TestGetSpendingByCategory(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, -1).Add(23*time.Hour + 59*time.Minute + 59*time.Second)
+
+	t.Run("groups_by_category", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+
+		catA := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		catB := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+
+		// Two expenses for catA
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &catA.ID, models.TransactionTypeExpense, 3000, "", from.Add(time.Hour), false, "", nil)
+		testutil.AssertNoError(t, err)
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &catA.ID, models.TransactionTypeExpense, 2000, "", from.Add(2*time.Hour), false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		// One expense for catB
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &catB.ID, models.TransactionTypeExpense, 1500, "", from.Add(3*time.Hour), false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		result, err := txSvc.GetSpendingByCategory(context.Background(), models.UserID(user.ID), from, to, SpendingModeExpense, false)
+		testutil.AssertNoError(t, err)
+
+		if len(result.Items) != 2 {
+			t.Fatalf("expected 2 items, got %d", len(result.Items))
+		}
+		if result.TotalSpent != 6500 {
+			t.Errorf("expected total_spent 6500, got %d", result.TotalSpent)
+		}
+		// First item should be catA (5000 > 1500)
+		if result.Items[0].Total != 5000 {
+			t.Errorf("expected first item total 5000, got %d", result.Items[0].Total)
+		}
+		if result.Items[1].Total != 1500 {
+			t.Errorf("expected second item total 1500, got %d", result.Items[1].Total)
+		}
+	})
+
+	t.Run("handles_uncategorized", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 2500, "", from.Add(time.Hour), false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		result, err := txSvc.GetSpendingByCategory(context.Background(), models.UserID(user.ID), from, to, SpendingModeExpense, false)
+		testutil.AssertNoError(t, err)
+
+		if len(result.Items) != 1 {
+			t.Fatalf("expected 1 item, got %d", len(result.Items))
+		}
+		if result.Items[0].CategoryName != "Uncategorized" {
+			t.Errorf("expected category_name 'Uncategorized', got %q", result.Items[0].CategoryName)
+		}
+		if result.Items[0].CategoryColor != "#9CA3AF" {
+			t.Errorf("expected color '#9CA3AF', got %q", result.Items[0].CategoryColor)
+		}
+		if result.Items[0].CategoryID != nil {
+			t.Errorf("expected nil category_id, got %v", result.Items[0].CategoryID)
+		}
+	})
+
+	t.Run("filters_by_date_range", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+
+		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+
+		// January expense (out of range for February query)
+		jan := time.Date(now.Year(), 1, 15, 12, 0, 0, 0, time.UTC)
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &cat.ID, models.TransactionTypeExpense, 1000, "", jan, false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		// February expense (in range)
+		feb := time.Date(now.Year(), 2, 15, 12, 0, 0, 0, time.UTC)
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &cat.ID, models.TransactionTypeExpense, 2000, "", feb, false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		febFrom := time.Date(now.Year(), 2, 1, 0, 0, 0, 0, time.UTC)
+		febTo := time.Date(now.Year(), 2, 28, 23, 59, 59, 0, time.UTC)
+		result, err := txSvc.GetSpendingByCategory(context.Background(), models.UserID(user.ID), febFrom, febTo, SpendingModeExpense, false)
+		testutil.AssertNoError(t, err)
+
+		if result.TotalSpent != 2000 {
+			t.Errorf("expected total_spent 2000, got %d", result.TotalSpent)
+		}
+	})
+
+	t.Run("excludes_non_expense_types", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		account2 := testutil.CreateTestCashAccount(t, db, user.ID)
+
+		// Income transaction
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 5000, "", from.Add(time.Hour), false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		// Transfer transaction
+		_, err = txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(account.ID), models.AccountID(account2.ID), 1000, "", from.Add(2*time.Hour))
+		testutil.AssertNoError(t, err)
+
+		result, err := txSvc.GetSpendingByCategory(context.Background(), models.UserID(user.ID), from, to, SpendingModeExpense, false)
+		testutil.AssertNoError(t, err)
+
+		if result.TotalSpent != 0 {
+			t.Errorf("expected total_spent 0, got %d", result.TotalSpent)
+		}
+		if len(result.Items) != 0 {
+			t.Errorf("expected 0 items, got %d", len(result.Items))
+		}
+	})
+
+	t.Run("income_mode_groups_income_and_ignores_expenses", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		salary := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeIncome)
+		groceries := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &salary.ID, models.TransactionTypeIncome, 300000, "", from.Add(time.Hour), false, "", nil)
+		testutil.AssertNoError(t, err)
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &salary.ID, models.TransactionTypeIncome, 20000, "", from.Add(2*time.Hour), false, "", nil)
+		testutil.AssertNoError(t, err)
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 5000, "", from.Add(3*time.Hour), false, "", nil)
+		testutil.AssertNoError(t, err)
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &groceries.ID, models.TransactionTypeExpense, 7000, "", from.Add(4*time.Hour), false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		result, err := txSvc.GetSpendingByCategory(context.Background(), models.UserID(user.ID), from, to, SpendingModeIncome, false)
+		testutil.AssertNoError(t, err)
+
+		if result.Mode != SpendingModeIncome {
+			t.Errorf("expected mode income, got %q", result.Mode)
+		}
+		if result.TotalSpent != 325000 {
+			t.Errorf("expected total 325000, got %d", result.TotalSpent)
+		}
+		if len(result.Items) != 2 {
+			t.Fatalf("expected 2 items, got %d", len(result.Items))
+		}
+		if result.Items[0].CategoryID == nil || *result.Items[0].CategoryID != salary.ID || result.Items[0].Total != 320000 {
+			t.Errorf("expected salary first with 320000, got %+v", result.Items[0])
+		}
+		if result.Items[1].CategoryID != nil || result.Items[1].CategoryName != "Uncategorized" || result.Items[1].Total != 5000 {
+			t.Errorf("expected uncategorized income of 5000, got %+v", result.Items[1])
+		}
+	})
+
+	t.Run("rejects_unknown_mode", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		txSvc := NewTransactionService(db, NewAccountService(db, nil), nil)
+		user := testutil.CreateTestUser(t, db)
+
+		_, err := txSvc.GetSpendingByCategory(context.Background(), models.UserID(user.ID), from, to, SpendingMode("transfer"), false)
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+
+	t.Run("returns_empty_for_no_expenses", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+
+		result, err := txSvc.GetSpendingByCategory(context.Background(), models.UserID(user.ID), from, to, SpendingModeExpense, false)
+		testutil.AssertNoError(t, err)
+
 		if result.TotalSpent != 0 {
 			t.Errorf("expected total_spent 0, got %d", result.TotalSpent)
 		}
@@ -1241,21 +2896,20 @@ func TestGetSpendingByCategory(t *testing.T) {
 	})
 
 	t.Run("user_isolation", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		userA := testutil.CreateTestUser(t, db)
 		userB := testutil.CreateTestUser(t, db)
 		accountA := testutil.CreateTestCashAccountWithBalance(t, db, userA.ID, 100000)
 		accountB := testutil.CreateTestCashAccountWithBalance(t, db, userB.ID, 100000)
 
-		_, err := txSvc.CreateTransaction(userA.ID, accountA.ID, nil, models.TransactionTypeExpense, 3000, "", from.Add(time.Hour))
+		_, err := txSvc.CreateTransaction(models.UserID(userA.ID), models.AccountID(accountA.ID), nil, models.TransactionTypeExpense, 3000, "", from.Add(time.Hour), false, "", nil)
 		testutil.AssertNoError(t, err)
-		_, err = txSvc.CreateTransaction(userB.ID, accountB.ID, nil, models.TransactionTypeExpense, 5000, "", from.Add(time.Hour))
+		_, err = txSvc.CreateTransaction(models.UserID(userB.ID), models.AccountID(accountB.ID), nil, models.TransactionTypeExpense, 5000, "", from.Add(time.Hour), false, "", nil)
 		testutil.AssertNoError(t, err)
 
-		result, err := txSvc.GetSpendingByCategory(userA.ID, from, to)
+		result, err := txSvc.GetSpendingByCategory(context.Background(), models.UserID(userA.ID), from, to, SpendingModeExpense, false)
 		testutil.AssertNoError(t, err)
 
 		if result.TotalSpent != 3000 {
@@ -1264,20 +2918,19 @@ func TestGetSpendingByCategory(t *testing.T) {
 	})
 
 	t.Run("generates_fallback_color_for_colorless_categories", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
 
 		// CreateTestCategory creates categories without a color set
 		cat := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
 
-		_, err := txSvc.CreateTransaction(user.ID, account.ID, &cat.ID, models.TransactionTypeExpense, 1000, "", from.Add(time.Hour))
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &cat.ID, models.TransactionTypeExpense, 1000, "", from.Add(time.Hour), false, "", nil)
 		testutil.AssertNoError(t, err)
 
-		result, err := txSvc.GetSpendingByCategory(user.ID, from, to)
+		result, err := txSvc.GetSpendingByCategory(context.Background(), models.UserID(user.ID), from, to, SpendingModeExpense, false)
 		testutil.AssertNoError(t, err)
 
 		if len(result.Items) != 1 {
@@ -1288,17 +2941,16 @@ func TestGetSpendingByCategory(t *testing.T) {
 			t.Error("expected non-empty fallback color for colorless category")
 		}
 		// Should be a valid hex color from the palette
-		expectedColor := categoryColorPalette[cat.ID%uint(len(categoryColorPalette))]
+		expectedColor := getCategoryColorFromID(cat.ID)
 		if result.Items[0].CategoryColor != expectedColor {
 			t.Errorf("expected fallback color %q, got %q", expectedColor, result.Items[0].CategoryColor)
 		}
 	})
 
 	t.Run("uses_category_color_when_set", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
 
@@ -1313,10 +2965,10 @@ func TestGetSpendingByCategory(t *testing.T) {
 			t.Fatalf("failed to create category: %v", err)
 		}
 
-		_, err := txSvc.CreateTransaction(user.ID, account.ID, &cat.ID, models.TransactionTypeExpense, 1000, "", from.Add(time.Hour))
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &cat.ID, models.TransactionTypeExpense, 1000, "", from.Add(time.Hour), false, "", nil)
 		testutil.AssertNoError(t, err)
 
-		result, err := txSvc.GetSpendingByCategory(user.ID, from, to)
+		result, err := txSvc.GetSpendingByCategory(context.Background(), models.UserID(user.ID), from, to, SpendingModeExpense, false)
 		testutil.AssertNoError(t, err)
 
 		if len(result.Items) != 1 {
@@ -1328,10 +2980,9 @@ func TestGetSpendingByCategory(t *testing.T) {
 	})
 
 	t.Run("sorts_by_total_descending", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
 
@@ -1339,14 +2990,14 @@ func TestGetSpendingByCategory(t *testing.T) {
 		catMedium := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
 		catLarge := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
 
-		_, err := txSvc.CreateTransaction(user.ID, account.ID, &catSmall.ID, models.TransactionTypeExpense, 1000, "", from.Add(time.Hour))
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &catSmall.ID, models.TransactionTypeExpense, 1000, "", from.Add(time.Hour), false, "", nil)
 		testutil.AssertNoError(t, err)
-		_, err = txSvc.CreateTransaction(user.ID, account.ID, &catMedium.ID, models.TransactionTypeExpense, 3000, "", from.Add(2*time.Hour))
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &catMedium.ID, models.TransactionTypeExpense, 3000, "", from.Add(2*time.Hour), false, "", nil)
 		testutil.AssertNoError(t, err)
-		_, err = txSvc.CreateTransaction(user.ID, account.ID, &catLarge.ID, models.TransactionTypeExpense, 5000, "", from.Add(3*time.Hour))
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &catLarge.ID, models.TransactionTypeExpense, 5000, "", from.Add(3*time.Hour), false, "", nil)
 		testutil.AssertNoError(t, err)
 
-		result, err := txSvc.GetSpendingByCategory(user.ID, from, to)
+		result, err := txSvc.GetSpendingByCategory(context.Background(), models.UserID(user.ID), from, to, SpendingModeExpense, false)
 		testutil.AssertNoError(t, err)
 
 		if len(result.Items) != 3 {
@@ -1365,31 +3016,31 @@ func TestGetSpendingByCategory(t *testing.T) {
 }
 
 func TestGetMonthlySummary(t *testing.T) {
+	t.Parallel()
 	now := time.Now()
 
 	t.Run("returns_monthly_totals", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
 
 		// Current month: income 10000, expense 5000
 		curMonth := time.Date(now.Year(), now.Month(), 10, 12, 0, 0, 0, time.UTC)
-		_, err := txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeIncome, 10000, "", curMonth)
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 10000, "", curMonth, false, "", nil)
 		testutil.AssertNoError(t, err)
-		_, err = txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeExpense, 5000, "", curMonth)
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 5000, "", curMonth, false, "", nil)
 		testutil.AssertNoError(t, err)
 
 		// Previous month: income 8000, expense 3000
 		prevMonth := curMonth.AddDate(0, -1, 0)
-		_, err = txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeIncome, 8000, "", prevMonth)
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 8000, "", prevMonth, false, "", nil)
 		testutil.AssertNoError(t, err)
-		_, err = txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeExpense, 3000, "", prevMonth)
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 3000, "", prevMonth, false, "", nil)
 		testutil.AssertNoError(t, err)
 
-		result, err := txSvc.GetMonthlySummary(user.ID, 2)
+		result, err := txSvc.GetMonthlySummary(context.Background(), models.UserID(user.ID), 2, false, false)
 		testutil.AssertNoError(t, err)
 
 		if len(result) != 2 {
@@ -1412,13 +3063,12 @@ func TestGetMonthlySummary(t *testing.T) {
 	})
 
 	t.Run("returns_zero_for_empty_months", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 
-		result, err := txSvc.GetMonthlySummary(user.ID, 3)
+		result, err := txSvc.GetMonthlySummary(context.Background(), models.UserID(user.ID), 3, false, false)
 		testutil.AssertNoError(t, err)
 
 		if len(result) != 3 {
@@ -1435,10 +3085,9 @@ func TestGetMonthlySummary(t *testing.T) {
 	})
 
 	t.Run("excludes_transfers_and_investments", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
 		account2 := testutil.CreateTestCashAccount(t, db, user.ID)
@@ -1446,10 +3095,10 @@ func TestGetMonthlySummary(t *testing.T) {
 		curMonth := time.Date(now.Year(), now.Month(), 10, 12, 0, 0, 0, time.UTC)
 
 		// Transfer
-		_, err := txSvc.CreateTransfer(user.ID, account.ID, account2.ID, 2000, "", curMonth)
+		_, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(account.ID), models.AccountID(account2.ID), 2000, "", curMonth)
 		testutil.AssertNoError(t, err)
 
-		result, err := txSvc.GetMonthlySummary(user.ID, 1)
+		result, err := txSvc.GetMonthlySummary(context.Background(), models.UserID(user.ID), 1, false, false)
 		testutil.AssertNoError(t, err)
 
 		if len(result) != 1 {
@@ -1464,22 +3113,21 @@ func TestGetMonthlySummary(t *testing.T) {
 	})
 
 	t.Run("excludes_initial_balance_from_income", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 
 		// CreateCashAccount with initial balance creates an income transaction with description "Initial balance"
-		account, err := acctSvc.CreateCashAccount(user.ID, "Savings", "", "USD", 50000)
+		account, err := acctSvc.CreateCashAccount(models.UserID(user.ID), "Savings", "", "USD", 50000, true, 0, nil)
 		testutil.AssertNoError(t, err)
 
 		// Add a regular income transaction in the current month
 		curMonth := time.Date(now.Year(), now.Month(), 10, 12, 0, 0, 0, time.UTC)
-		_, err = txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeIncome, 7000, "Salary", curMonth)
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 7000, "Salary", curMonth, false, "", nil)
 		testutil.AssertNoError(t, err)
 
-		result, err := txSvc.GetMonthlySummary(user.ID, 1)
+		result, err := txSvc.GetMonthlySummary(context.Background(), models.UserID(user.ID), 1, false, false)
 		testutil.AssertNoError(t, err)
 
 		if len(result) != 1 {
@@ -1492,10 +3140,9 @@ func TestGetMonthlySummary(t *testing.T) {
 	})
 
 	t.Run("user_isolation", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		userA := testutil.CreateTestUser(t, db)
 		userB := testutil.CreateTestUser(t, db)
 		accountA := testutil.CreateTestCashAccountWithBalance(t, db, userA.ID, 100000)
@@ -1503,12 +3150,12 @@ func TestGetMonthlySummary(t *testing.T) {
 
 		curMonth := time.Date(now.Year(), now.Month(), 10, 12, 0, 0, 0, time.UTC)
 
-		_, err := txSvc.CreateTransaction(userA.ID, accountA.ID, nil, models.TransactionTypeIncome, 5000, "", curMonth)
+		_, err := txSvc.CreateTransaction(models.UserID(userA.ID), models.AccountID(accountA.ID), nil, models.TransactionTypeIncome, 5000, "", curMonth, false, "", nil)
 		testutil.AssertNoError(t, err)
-		_, err = txSvc.CreateTransaction(userB.ID, accountB.ID, nil, models.TransactionTypeIncome, 9000, "", curMonth)
+		_, err = txSvc.CreateTransaction(models.UserID(userB.ID), models.AccountID(accountB.ID), nil, models.TransactionTypeIncome, 9000, "", curMonth, false, "", nil)
 		testutil.AssertNoError(t, err)
 
-		result, err := txSvc.GetMonthlySummary(userA.ID, 1)
+		result, err := txSvc.GetMonthlySummary(context.Background(), models.UserID(userA.ID), 1, false, false)
 		testutil.AssertNoError(t, err)
 
 		if len(result) != 1 {
@@ -1518,31 +3165,270 @@ func TestGetMonthlySummary(t *testing.T) {
 			t.Errorf("expected income 5000, got %d", result[0].Income)
 		}
 	})
+
+	// 31 March 20:00 UTC, with expenses late on the last day of February and
+	// of March in UTC.
+	instant := time.Date(2026, 3, 31, 20, 0, 0, 0, time.UTC)
+	seedMonthEnds := func(t *testing.T) (*transactionService, string) {
+		t.Helper()
+		db := testutil.WithTx(t)
+		txSvc := NewTransactionService(db, NewAccountService(db, nil), nil).(*transactionService)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		for _, tx := range []struct {
+			amount int64
+			date   time.Time
+		}{
+			{3000, time.Date(2026, 2, 28, 23, 0, 0, 0, time.UTC)},
+			{5000, time.Date(2026, 3, 31, 19, 0, 0, 0, time.UTC)},
+		} {
+			_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, models.Cents(tx.amount), "", tx.date, false, "", nil)
+			testutil.AssertNoError(t, err)
+		}
+		return txSvc, user.ID
+	}
+
+	t.Run("month_boundaries_ignore_the_machine_zone", func(t *testing.T) {
+		txSvc, userID := seedMonthEnds(t)
+
+		for _, zone := range []*time.Location{time.UTC, kiritimati, honolulu} {
+			txSvc.clock = fixedClock{now: instant.In(zone), loc: time.UTC}
+
+			result, err := txSvc.GetMonthlySummary(context.Background(), models.UserID(userID), 2, false, false)
+			testutil.AssertNoError(t, err)
+
+			if len(result) != 2 || result[0].Month != "2026-02" || result[1].Month != "2026-03" {
+				t.Fatalf("machine zone %s: expected February and March, got %+v", zone, result)
+			}
+			if result[0].Expenses != 3000 || result[1].Expenses != 5000 {
+				t.Errorf("machine zone %s: expected expenses 3000 and 5000, got %+v", zone, result)
+			}
+		}
+	})
+
+	t.Run("months_follow_the_reporting_location_without_a_timezone", func(t *testing.T) {
+		txSvc, userID := seedMonthEnds(t)
+		testutil.AssertNoError(t, txSvc.db.Model(&models.User{}).Where("id = ?", userID).Update("timezone", "").Error)
+		txSvc.clock = fixedClock{now: instant, loc: kiritimati}
+
+		result, err := txSvc.GetMonthlySummary(context.Background(), models.UserID(userID), 2, false, false)
+		testutil.AssertNoError(t, err)
+
+		// In Kiritimati both expenses fall on the first day of the next month.
+		if len(result) != 2 || result[0].Month != "2026-03" || result[1].Month != "2026-04" {
+			t.Fatalf("expected March and April, got %+v", result)
+		}
+		if result[0].Expenses != 3000 || result[1].Expenses != 5000 {
+			t.Errorf("expected expenses 3000 and 5000, got %+v", result)
+		}
+	})
+
+	t.Run("months_follow_the_user_timezone", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		txSvc := NewTransactionService(db, NewAccountService(db, nil), nil).(*transactionService)
+		txSvc.clock = fixedClock{now: time.Date(2026, 4, 15, 12, 0, 0, 0, time.UTC), loc: time.UTC}
+		local := testutil.CreateTestUser(t, db)
+		testutil.AssertNoError(t, db.Model(local).Update("timezone", "Asia/Kuala_Lumpur").Error)
+		utc := testutil.CreateTestUser(t, db)
+		kualaLumpur, err := time.LoadLocation("Asia/Kuala_Lumpur")
+		testutil.AssertNoError(t, err)
+
+		for _, user := range []*models.User{local, utc} {
+			account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+			for _, tx := range []struct {
+				amount int64
+				date   time.Time
+			}{
+				{1000, time.Date(2026, 3, 31, 23, 0, 0, 0, kualaLumpur)}, // 15:00 UTC, same day
+				{2000, time.Date(2026, 4, 1, 7, 0, 0, 0, kualaLumpur)},   // 23:00 UTC on 31 March
+			} {
+				_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, models.Cents(tx.amount), "", tx.date.UTC(), false, "", nil)
+				testutil.AssertNoError(t, err)
+			}
+		}
+
+		result, err := txSvc.GetMonthlySummary(context.Background(), models.UserID(local.ID), 2, true, false)
+		testutil.AssertNoError(t, err)
+		if len(result) != 2 || result[0].Month != "2026-03" || result[1].Month != "2026-04" {
+			t.Fatalf("expected March and April, got %+v", result)
+		}
+		if result[0].Expenses != 1000 || result[1].Expenses != 2000 {
+			t.Errorf("expected local months 1000 and 2000, got %+v", result)
+		}
+		if len(result[1].Categories) != 1 || result[1].Categories[0].Total != 2000 {
+			t.Errorf("expected the April breakdown to hold 2000, got %+v", result[1].Categories)
+		}
+
+		result, err = txSvc.GetMonthlySummary(context.Background(), models.UserID(utc.ID), 2, false, false)
+		testutil.AssertNoError(t, err)
+		if result[0].Expenses != 3000 || result[1].Expenses != 0 {
+			t.Errorf("expected both expenses in March UTC, got %+v", result)
+		}
+	})
+
+	t.Run("defaults_the_date_to_the_clock", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		txSvc := NewTransactionService(db, NewAccountService(db, nil), nil).(*transactionService)
+		txSvc.clock = fixedClock{now: instant, loc: time.UTC}
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 1000, "", time.Time{}, false, "", nil)
+		testutil.AssertNoError(t, err)
+		if !tx.Date.Equal(instant) || tx.IsPending {
+			t.Errorf("expected a settled expense dated %v, got %v (pending %v)", instant, tx.Date, tx.IsPending)
+		}
+
+		tx, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 1000, "", instant.Add(time.Hour), false, "", nil)
+		testutil.AssertNoError(t, err)
+		if !tx.IsPending {
+			t.Error("expected an expense dated after the clock to be pending")
+		}
+	})
+}
+
+func TestGetMonthlySummary_WithCategories(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+
+	t.Run("breaks_down_expenses_per_month", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		groceries := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+		transport := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+
+		curMonth := time.Date(now.Year(), now.Month(), 10, 12, 0, 0, 0, time.UTC)
+		prevMonth := curMonth.AddDate(0, -1, 0)
+
+		// Previous month: groceries 2000 + 1000, transport 500
+		for _, tx := range []struct {
+			categoryID string
+			amount     int64
+			date       time.Time
+		}{
+			{groceries.ID, 2000, prevMonth},
+			{groceries.ID, 1000, prevMonth.AddDate(0, 0, 3)},
+			{transport.ID, 500, prevMonth},
+			// Current month: transport 4000, groceries 1500
+			{transport.ID, 4000, curMonth},
+			{groceries.ID, 1500, curMonth},
+		} {
+			catID := tx.categoryID
+			_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &catID, models.TransactionTypeExpense, models.Cents(tx.amount), "", tx.date, false, "", nil)
+			testutil.AssertNoError(t, err)
+		}
+
+		result, err := txSvc.GetMonthlySummary(context.Background(), models.UserID(user.ID), 2, true, false)
+		testutil.AssertNoError(t, err)
+
+		if len(result) != 2 {
+			t.Fatalf("expected 2 items, got %d", len(result))
+		}
+
+		prev := result[0].Categories
+		if len(prev) != 2 {
+			t.Fatalf("expected 2 categories in previous month, got %d", len(prev))
+		}
+		if *prev[0].CategoryID != groceries.ID || prev[0].Total != 3000 {
+			t.Errorf("expected groceries 3000 first, got %+v", prev[0])
+		}
+		if prev[0].CategoryName != groceries.Name {
+			t.Errorf("expected category name %s, got %s", groceries.Name, prev[0].CategoryName)
+		}
+		if *prev[1].CategoryID != transport.ID || prev[1].Total != 500 {
+			t.Errorf("expected transport 500 second, got %+v", prev[1])
+		}
+
+		cur := result[1].Categories
+		if len(cur) != 2 {
+			t.Fatalf("expected 2 categories in current month, got %d", len(cur))
+		}
+		if *cur[0].CategoryID != transport.ID || cur[0].Total != 4000 {
+			t.Errorf("expected transport 4000 first, got %+v", cur[0])
+		}
+		if *cur[1].CategoryID != groceries.ID || cur[1].Total != 1500 {
+			t.Errorf("expected groceries 1500 second, got %+v", cur[1])
+		}
+
+		// Breakdown sums to the month's expense total
+		for _, item := range result {
+			var sum int64
+			for _, c := range item.Categories {
+				sum += c.Total
+			}
+			if sum != item.Expenses {
+				t.Errorf("month %s: breakdown sum %d != expenses %d", item.Month, sum, item.Expenses)
+			}
+		}
+	})
+
+	t.Run("omitted_by_default", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		category := testutil.CreateTestCategory(t, db, user.ID, models.CategoryTypeExpense)
+
+		curMonth := time.Date(now.Year(), now.Month(), 10, 12, 0, 0, 0, time.UTC)
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), &category.ID, models.TransactionTypeExpense, 1000, "", curMonth, false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		result, err := txSvc.GetMonthlySummary(context.Background(), models.UserID(user.ID), 1, false, false)
+		testutil.AssertNoError(t, err)
+
+		if result[0].Categories != nil {
+			t.Errorf("expected no breakdown by default, got %+v", result[0].Categories)
+		}
+	})
+
+	t.Run("uncategorized_expenses", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+
+		curMonth := time.Date(now.Year(), now.Month(), 10, 12, 0, 0, 0, time.UTC)
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 700, "", curMonth, false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		result, err := txSvc.GetMonthlySummary(context.Background(), models.UserID(user.ID), 1, true, false)
+		testutil.AssertNoError(t, err)
+
+		cats := result[0].Categories
+		if len(cats) != 1 || cats[0].CategoryID != nil || cats[0].CategoryName != "Uncategorized" || cats[0].Total != 700 {
+			t.Errorf("expected single uncategorized entry of 700, got %+v", cats)
+		}
+	})
 }
 
 func TestGetDailySpending(t *testing.T) {
+	t.Parallel()
 	from := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2026, 2, 3, 23, 59, 59, 0, time.UTC)
 
 	t.Run("returns_daily_totals", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
 
 		// Day 1: two expenses (3000 + 2000 = 5000)
-		_, err := txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeExpense, 3000, "", time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC))
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 3000, "", time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC), false, "", nil)
 		testutil.AssertNoError(t, err)
-		_, err = txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeExpense, 2000, "", time.Date(2026, 2, 1, 14, 0, 0, 0, time.UTC))
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 2000, "", time.Date(2026, 2, 1, 14, 0, 0, 0, time.UTC), false, "", nil)
 		testutil.AssertNoError(t, err)
 
 		// Day 3: one expense (1500)
-		_, err = txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeExpense, 1500, "", time.Date(2026, 2, 3, 12, 0, 0, 0, time.UTC))
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 1500, "", time.Date(2026, 2, 3, 12, 0, 0, 0, time.UTC), false, "", nil)
 		testutil.AssertNoError(t, err)
 
-		result, err := txSvc.GetDailySpending(user.ID, from, to)
+		result, err := txSvc.GetDailySpending(context.Background(), models.UserID(user.ID), from, to, SpendingGranularityDay, false)
 		testutil.AssertNoError(t, err)
 
 		if len(result) != 3 {
@@ -1560,19 +3446,18 @@ func TestGetDailySpending(t *testing.T) {
 	})
 
 	t.Run("includes_zero_days", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
 
 		fiveDay := time.Date(2026, 2, 5, 23, 59, 59, 0, time.UTC)
 
-		_, err := txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeExpense, 1000, "", time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC))
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 1000, "", time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC), false, "", nil)
 		testutil.AssertNoError(t, err)
 
-		result, err := txSvc.GetDailySpending(user.ID, from, fiveDay)
+		result, err := txSvc.GetDailySpending(context.Background(), models.UserID(user.ID), from, fiveDay, SpendingGranularityDay, false)
 		testutil.AssertNoError(t, err)
 
 		if len(result) != 5 {
@@ -1586,10 +3471,9 @@ func TestGetDailySpending(t *testing.T) {
 	})
 
 	t.Run("excludes_non_expense_types", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
 		account2 := testutil.CreateTestCashAccount(t, db, user.ID)
@@ -1597,14 +3481,14 @@ func TestGetDailySpending(t *testing.T) {
 		day1 := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
 
 		// Income
-		_, err := txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeIncome, 5000, "", day1)
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 5000, "", day1, false, "", nil)
 		testutil.AssertNoError(t, err)
 
 		// Transfer
-		_, err = txSvc.CreateTransfer(user.ID, account.ID, account2.ID, 1000, "", day1)
+		_, err = txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(account.ID), models.AccountID(account2.ID), 1000, "", day1)
 		testutil.AssertNoError(t, err)
 
-		result, err := txSvc.GetDailySpending(user.ID, from, to)
+		result, err := txSvc.GetDailySpending(context.Background(), models.UserID(user.ID), from, to, SpendingGranularityDay, false)
 		testutil.AssertNoError(t, err)
 
 		for _, item := range result {
@@ -1615,22 +3499,21 @@ func TestGetDailySpending(t *testing.T) {
 	})
 
 	t.Run("filters_by_date_range", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		user := testutil.CreateTestUser(t, db)
 		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
 
 		// Expense before range
-		_, err := txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeExpense, 1000, "", time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC))
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 1000, "", time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC), false, "", nil)
 		testutil.AssertNoError(t, err)
 
 		// Expense after range
-		_, err = txSvc.CreateTransaction(user.ID, account.ID, nil, models.TransactionTypeExpense, 2000, "", time.Date(2026, 2, 4, 12, 0, 0, 0, time.UTC))
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 2000, "", time.Date(2026, 2, 4, 12, 0, 0, 0, time.UTC), false, "", nil)
 		testutil.AssertNoError(t, err)
 
-		result, err := txSvc.GetDailySpending(user.ID, from, to)
+		result, err := txSvc.GetDailySpending(context.Background(), models.UserID(user.ID), from, to, SpendingGranularityDay, false)
 		testutil.AssertNoError(t, err)
 
 		for _, item := range result {
@@ -1641,10 +3524,9 @@ func TestGetDailySpending(t *testing.T) {
 	})
 
 	t.Run("user_isolation", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
-		acctSvc := NewAccountService(db)
-		txSvc := NewTransactionService(db, acctSvc)
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
 		userA := testutil.CreateTestUser(t, db)
 		userB := testutil.CreateTestUser(t, db)
 		accountA := testutil.CreateTestCashAccountWithBalance(t, db, userA.ID, 100000)
@@ -1652,16 +3534,555 @@ func TestGetDailySpending(t *testing.T) {
 
 		day1 := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
 
-		_, err := txSvc.CreateTransaction(userA.ID, accountA.ID, nil, models.TransactionTypeExpense, 3000, "", day1)
+		_, err := txSvc.CreateTransaction(models.UserID(userA.ID), models.AccountID(accountA.ID), nil, models.TransactionTypeExpense, 3000, "", day1, false, "", nil)
 		testutil.AssertNoError(t, err)
-		_, err = txSvc.CreateTransaction(userB.ID, accountB.ID, nil, models.TransactionTypeExpense, 7000, "", day1)
+		_, err = txSvc.CreateTransaction(models.UserID(userB.ID), models.AccountID(accountB.ID), nil, models.TransactionTypeExpense, 7000, "", day1, false, "", nil)
 		testutil.AssertNoError(t, err)
 
-		result, err := txSvc.GetDailySpending(userA.ID, from, to)
+		result, err := txSvc.GetDailySpending(context.Background(), models.UserID(userA.ID), from, to, SpendingGranularityDay, false)
 		testutil.AssertNoError(t, err)
 
 		if result[0].Total != 3000 {
 			t.Errorf("expected day 1 total 3000 for userA, got %d", result[0].Total)
 		}
 	})
+
+	t.Run("totals_the_range_in_one_query", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		txSvc := NewTransactionService(db, NewAccountService(db, nil), nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 1500, "", time.Date(2026, 1, 20, 9, 0, 0, 0, time.UTC), false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		var spendingQueries int
+		if err := db.Callback().Row().After("gorm:row").Register("test:count_daily_spending_queries", func(tx *gorm.DB) {
+			if tx.Statement.Table == "transactions" && slices.Contains(tx.Statement.Vars, interface{}(models.UserID(user.ID))) {
+				spendingQueries++
+			}
+		}); err != nil {
+			t.Fatalf("failed to register callback: %v", err)
+		}
+
+		result, err := txSvc.GetDailySpending(context.Background(), models.UserID(user.ID), time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC), SpendingGranularityDay, false)
+		testutil.AssertNoError(t, err)
+
+		if spendingQueries != 1 {
+			t.Errorf("expected 1 spending query for 31 days, got %d", spendingQueries)
+		}
+		if len(result) != 31 || result[19] != (DailySpendingItem{"2026-01-20", 1500}) || result[18].Total != 0 || result[20].Total != 0 {
+			t.Errorf("expected 31 zero-filled days with 1500 on 20 January, got %+v", result)
+		}
+	})
+
+	t.Run("days_follow_the_user_timezone", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		txSvc := NewTransactionService(db, NewAccountService(db, nil), nil)
+		local := testutil.CreateTestUser(t, db)
+		testutil.AssertNoError(t, db.Model(local).Update("timezone", "Asia/Kuala_Lumpur").Error)
+		utc := testutil.CreateTestUser(t, db)
+		kualaLumpur, err := time.LoadLocation("Asia/Kuala_Lumpur")
+		testutil.AssertNoError(t, err)
+
+		for _, user := range []*models.User{local, utc} {
+			account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+			for _, tx := range []struct {
+				amount int64
+				date   time.Time
+			}{
+				{1000, time.Date(2026, 2, 1, 23, 0, 0, 0, kualaLumpur)}, // 15:00 UTC, same day
+				{2000, time.Date(2026, 2, 3, 7, 0, 0, 0, kualaLumpur)},  // 23:00 UTC on 2 February
+			} {
+				_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, models.Cents(tx.amount), "", tx.date.UTC(), false, "", nil)
+				testutil.AssertNoError(t, err)
+			}
+		}
+
+		result, err := txSvc.GetDailySpending(context.Background(), models.UserID(local.ID), from, to, SpendingGranularityDay, false)
+		testutil.AssertNoError(t, err)
+		want := []DailySpendingItem{{"2026-02-01", 1000}, {"2026-02-02", 0}, {"2026-02-03", 2000}}
+		if len(result) != 3 || result[0] != want[0] || result[1] != want[1] || result[2] != want[2] {
+			t.Errorf("expected local days %+v, got %+v", want, result)
+		}
+
+		result, err = txSvc.GetDailySpending(context.Background(), models.UserID(utc.ID), from, to, SpendingGranularityDay, false)
+		testutil.AssertNoError(t, err)
+		want = []DailySpendingItem{{"2026-02-01", 1000}, {"2026-02-02", 2000}, {"2026-02-03", 0}}
+		if len(result) != 3 || result[0] != want[0] || result[1] != want[1] || result[2] != want[2] {
+			t.Errorf("expected UTC days %+v, got %+v", want, result)
+		}
+	})
+
+	t.Run("months_follow_the_user_timezone", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		txSvc := NewTransactionService(db, NewAccountService(db, nil), nil)
+		user := testutil.CreateTestUser(t, db)
+		testutil.AssertNoError(t, db.Model(user).Update("timezone", "Asia/Kuala_Lumpur").Error)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		kualaLumpur, err := time.LoadLocation("Asia/Kuala_Lumpur")
+		testutil.AssertNoError(t, err)
+
+		// 1 March 01:00 local is still February in UTC.
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 1000, "", time.Date(2026, 3, 1, 1, 0, 0, 0, kualaLumpur).UTC(), false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		result, err := txSvc.GetDailySpending(context.Background(), models.UserID(user.ID), from, time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC), SpendingGranularityMonth, false)
+		testutil.AssertNoError(t, err)
+		if len(result) != 2 || result[0] != (DailySpendingItem{"2026-02-01", 0}) || result[1] != (DailySpendingItem{"2026-03-01", 1000}) {
+			t.Errorf("expected the expense in March, got %+v", result)
+		}
+	})
+}
+
+func TestGetDailySpendingGranularity(t *testing.T) {
+	t.Parallel()
+	from := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC) // Thursday
+	to := time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)   // Friday
+
+	setup := func(t *testing.T) (*gorm.DB, TransactionServicer, *models.User) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+
+		for _, e := range []struct {
+			amount int64
+			date   time.Time
+		}{
+			{9999, time.Date(2026, 1, 14, 12, 0, 0, 0, time.UTC)}, // before the range
+			{1000, time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)},
+			{2000, time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)}, // Saturday
+			{500, time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)},   // Sunday
+			{300, time.Date(2026, 3, 20, 23, 0, 0, 0, time.UTC)},
+		} {
+			_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, models.Cents(e.amount), "", e.date, false, "", nil)
+			testutil.AssertNoError(t, err)
+		}
+		return db, txSvc, user
+	}
+
+	t.Run("month_buckets", func(t *testing.T) {
+		_, txSvc, user := setup(t)
+
+		result, err := txSvc.GetDailySpending(context.Background(), models.UserID(user.ID), from, to, SpendingGranularityMonth, false)
+		testutil.AssertNoError(t, err)
+
+		want := []DailySpendingItem{
+			{Date: "2026-01-01", Total: 3000},
+			{Date: "2026-02-01", Total: 500},
+			{Date: "2026-03-01", Total: 300},
+		}
+		if len(result) != len(want) {
+			t.Fatalf("expected %d buckets, got %+v", len(want), result)
+		}
+		for i := range want {
+			if result[i] != want[i] {
+				t.Errorf("bucket %d: expected %+v, got %+v", i, want[i], result[i])
+			}
+		}
+	})
+
+	t.Run("week_buckets_start_monday", func(t *testing.T) {
+		_, txSvc, user := setup(t)
+
+		result, err := txSvc.GetDailySpending(context.Background(), models.UserID(user.ID), from, to, SpendingGranularityWeek, false)
+		testutil.AssertNoError(t, err)
+
+		if len(result) != 10 {
+			t.Fatalf("expected 10 weekly buckets, got %d", len(result))
+		}
+		if result[0] != (DailySpendingItem{Date: "2026-01-12", Total: 1000}) {
+			t.Errorf("expected first week 2026-01-12 with 1000, got %+v", result[0])
+		}
+		if result[2] != (DailySpendingItem{Date: "2026-01-26", Total: 2500}) {
+			t.Errorf("expected Saturday and Sunday in the week of 2026-01-26, got %+v", result[2])
+		}
+		if result[3] != (DailySpendingItem{Date: "2026-02-02", Total: 0}) {
+			t.Errorf("expected empty week 2026-02-02 to be zero-filled, got %+v", result[3])
+		}
+		if result[9] != (DailySpendingItem{Date: "2026-03-16", Total: 300}) {
+			t.Errorf("expected last week 2026-03-16 with 300, got %+v", result[9])
+		}
+	})
+
+	t.Run("week_buckets_follow_user_week_start", func(t *testing.T) {
+		db, txSvc, user := setup(t)
+		testutil.AssertNoError(t, db.Model(user).Update("week_start", models.WeekStartSunday).Error)
+
+		result, err := txSvc.GetDailySpending(context.Background(), models.UserID(user.ID), from, to, SpendingGranularityWeek, false)
+		testutil.AssertNoError(t, err)
+
+		if len(result) != 10 {
+			t.Fatalf("expected 10 weekly buckets, got %d", len(result))
+		}
+		if result[0].Date != "2026-01-11" {
+			t.Errorf("expected first week to start Sunday 2026-01-11, got %s", result[0].Date)
+		}
+		if result[2] != (DailySpendingItem{Date: "2026-01-25", Total: 2000}) {
+			t.Errorf("expected Saturday in the week of 2026-01-25, got %+v", result[2])
+		}
+		if result[3] != (DailySpendingItem{Date: "2026-02-01", Total: 500}) {
+			t.Errorf("expected Sunday to start the week of 2026-02-01, got %+v", result[3])
+		}
+	})
+
+	t.Run("rejects_unknown_granularity", func(t *testing.T) {
+		_, txSvc, user := setup(t)
+
+		_, err := txSvc.GetDailySpending(context.Background(), models.UserID(user.ID), from, to, SpendingGranularity("year"), false)
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+}
+
+func TestReportsSkipExcludedAccounts(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	curMonth := time.Date(now.Year(), now.Month(), 1, 12, 0, 0, 0, time.UTC)
+	from := curMonth.AddDate(0, 0, -1)
+	to := curMonth.AddDate(0, 0, 1)
+
+	setup := func(t *testing.T) (TransactionServicer, string) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		personal := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		joint := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(personal.ID), nil, models.TransactionTypeIncome, 10000, "", curMonth, false, "", nil)
+		testutil.AssertNoError(t, err)
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(personal.ID), nil, models.TransactionTypeExpense, 2000, "", curMonth, false, "", nil)
+		testutil.AssertNoError(t, err)
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(joint.ID), nil, models.TransactionTypeIncome, 50000, "", curMonth, false, "", nil)
+		testutil.AssertNoError(t, err)
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(joint.ID), nil, models.TransactionTypeExpense, 7000, "", curMonth, false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		excluded := true
+		_, err = acctSvc.UpdateAccount(models.UserID(user.ID), models.AccountID(joint.ID), AccountUpdateFields{ExcludeFromReports: &excluded})
+		testutil.AssertNoError(t, err)
+		return txSvc, user.ID
+	}
+
+	t.Run("monthly_summary_skips_excluded_by_default", func(t *testing.T) {
+		txSvc, userID := setup(t)
+
+		result, err := txSvc.GetMonthlySummary(context.Background(), models.UserID(userID), 1, true, false)
+		testutil.AssertNoError(t, err)
+		if result[0].Income != 10000 || result[0].Expenses != 2000 {
+			t.Errorf("expected income 10000 and expenses 2000, got %d and %d", result[0].Income, result[0].Expenses)
+		}
+		if len(result[0].Categories) != 1 || result[0].Categories[0].Total != 2000 {
+			t.Errorf("expected breakdown to only count 2000, got %+v", result[0].Categories)
+		}
+
+		result, err = txSvc.GetMonthlySummary(context.Background(), models.UserID(userID), 1, false, true)
+		testutil.AssertNoError(t, err)
+		if result[0].Income != 60000 || result[0].Expenses != 9000 {
+			t.Errorf("expected income 60000 and expenses 9000 with override, got %d and %d", result[0].Income, result[0].Expenses)
+		}
+	})
+
+	t.Run("spending_reports_skip_excluded_by_default", func(t *testing.T) {
+		txSvc, userID := setup(t)
+
+		spending, err := txSvc.GetSpendingByCategory(context.Background(), models.UserID(userID), from, to, SpendingModeExpense, false)
+		testutil.AssertNoError(t, err)
+		if spending.TotalSpent != 2000 {
+			t.Errorf("expected total_spent 2000, got %d", spending.TotalSpent)
+		}
+		spending, err = txSvc.GetSpendingByCategory(context.Background(), models.UserID(userID), from, to, SpendingModeExpense, true)
+		testutil.AssertNoError(t, err)
+		if spending.TotalSpent != 9000 {
+			t.Errorf("expected total_spent 9000 with override, got %d", spending.TotalSpent)
+		}
+
+		daily, err := txSvc.GetDailySpending(context.Background(), models.UserID(userID), curMonth, curMonth, SpendingGranularityDay, false)
+		testutil.AssertNoError(t, err)
+		if len(daily) != 1 || daily[0].Total != 2000 {
+			t.Errorf("expected daily total 2000, got %+v", daily)
+		}
+		daily, err = txSvc.GetDailySpending(context.Background(), models.UserID(userID), curMonth, curMonth, SpendingGranularityDay, true)
+		testutil.AssertNoError(t, err)
+		if len(daily) != 1 || daily[0].Total != 9000 {
+			t.Errorf("expected daily total 9000 with override, got %+v", daily)
+		}
+	})
+}
+
+func TestPendingTransactions(t *testing.T) {
+	t.Parallel()
+	setup := func(t *testing.T, balance int64) (AccountServicer, TransactionServicer, *models.User, *models.Account) {
+		db := testutil.WithTx(t)
+		acctSvc := NewAccountService(db, nil)
+		txSvc := NewTransactionService(db, acctSvc, nil)
+		user := testutil.CreateTestUser(t, db)
+		account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, balance)
+		return acctSvc, txSvc, user, account
+	}
+	balanceOf := func(t *testing.T, acctSvc AccountServicer, userID, accountID string) int64 {
+		t.Helper()
+		account, err := acctSvc.GetAccountByID(models.UserID(userID), models.AccountID(accountID))
+		testutil.AssertNoError(t, err)
+		return account.Balance
+	}
+
+	t.Run("future_dated_is_pending_and_skips_balance", func(t *testing.T) {
+		acctSvc, txSvc, user, account := setup(t, 100000)
+
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 150000, "Rent", time.Now().AddDate(0, 0, 5), false, "", nil)
+		testutil.AssertNoError(t, err)
+		if !tx.IsPending {
+			t.Error("expected future-dated transaction to be pending")
+		}
+		if got := balanceOf(t, acctSvc, user.ID, account.ID); got != 100000 {
+			t.Errorf("expected balance 100000, got %d", got)
+		}
+	})
+
+	t.Run("explicit_pending_skips_balance", func(t *testing.T) {
+		acctSvc, txSvc, user, account := setup(t, 100000)
+
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeIncome, 5000, "", time.Now().Add(-time.Hour), true, "", nil)
+		testutil.AssertNoError(t, err)
+		if !tx.IsPending {
+			t.Error("expected flagged transaction to be pending")
+		}
+		if got := balanceOf(t, acctSvc, user.ID, account.ID); got != 100000 {
+			t.Errorf("expected balance 100000, got %d", got)
+		}
+	})
+
+	t.Run("settles_only_once_date_arrives", func(t *testing.T) {
+		acctSvc, txSvc, user, account := setup(t, 100000)
+		due := time.Now().AddDate(0, 0, 3)
+
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 40000, "Rent", due, false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		count, err := txSvc.SettlePendingTransactions(due.Add(-time.Second))
+		testutil.AssertNoError(t, err)
+		if count != 0 {
+			t.Errorf("expected 0 settled before due date, got %d", count)
+		}
+		if got := balanceOf(t, acctSvc, user.ID, account.ID); got != 100000 {
+			t.Errorf("expected balance 100000 before due date, got %d", got)
+		}
+
+		count, err = txSvc.SettlePendingTransactions(due)
+		testutil.AssertNoError(t, err)
+		if count != 1 {
+			t.Errorf("expected 1 settled on due date, got %d", count)
+		}
+		if got := balanceOf(t, acctSvc, user.ID, account.ID); got != 60000 {
+			t.Errorf("expected balance 60000 after settle, got %d", got)
+		}
+		settled, err := txSvc.GetTransactionByID(models.UserID(user.ID), models.TransactionID(tx.ID))
+		testutil.AssertNoError(t, err)
+		if settled.IsPending {
+			t.Error("expected transaction to be settled")
+		}
+
+		// A second run applies nothing again
+		count, err = txSvc.SettlePendingTransactions(due.AddDate(0, 0, 1))
+		testutil.AssertNoError(t, err)
+		if count != 0 {
+			t.Errorf("expected 0 settled on rerun, got %d", count)
+		}
+		if got := balanceOf(t, acctSvc, user.ID, account.ID); got != 60000 {
+			t.Errorf("expected balance 60000 after rerun, got %d", got)
+		}
+	})
+
+	t.Run("update_of_pending_leaves_balance_and_settles_new_values", func(t *testing.T) {
+		acctSvc, txSvc, user, account := setup(t, 100000)
+		due := time.Now().AddDate(0, 0, 3)
+
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 40000, "Rent", due, false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		amount := int64(45000)
+		updated, err := txSvc.UpdateTransaction(models.UserID(user.ID), models.TransactionID(tx.ID), TransactionUpdateFields{Amount: &amount})
+		testutil.AssertNoError(t, err)
+		if !updated.IsPending {
+			t.Error("expected transaction to stay pending after update")
+		}
+		if got := balanceOf(t, acctSvc, user.ID, account.ID); got != 100000 {
+			t.Errorf("expected balance 100000 after updating pending, got %d", got)
+		}
+
+		_, err = txSvc.SettlePendingTransactions(due)
+		testutil.AssertNoError(t, err)
+		if got := balanceOf(t, acctSvc, user.ID, account.ID); got != 55000 {
+			t.Errorf("expected balance 55000 after settling updated amount, got %d", got)
+		}
+	})
+
+	t.Run("delete_of_pending_leaves_balance", func(t *testing.T) {
+		acctSvc, txSvc, user, account := setup(t, 100000)
+		due := time.Now().AddDate(0, 0, 3)
+
+		tx, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 40000, "Rent", due, false, "", nil)
+		testutil.AssertNoError(t, err)
+		testutil.AssertNoError(t, txSvc.DeleteTransaction(models.UserID(user.ID), models.TransactionID(tx.ID)))
+
+		if got := balanceOf(t, acctSvc, user.ID, account.ID); got != 100000 {
+			t.Errorf("expected balance 100000 after deleting pending, got %d", got)
+		}
+		count, err := txSvc.SettlePendingTransactions(due)
+		testutil.AssertNoError(t, err)
+		if count != 0 {
+			t.Errorf("expected deleted transaction not to settle, got %d", count)
+		}
+	})
+
+	t.Run("pending_transfer_moves_both_sides_on_settle", func(t *testing.T) {
+		acctSvc, txSvc, user, from := setup(t, 100000)
+		to, err := acctSvc.CreateCashAccount(models.UserID(user.ID), "Savings", "", "USD", 0, true, 0, nil)
+		testutil.AssertNoError(t, err)
+		due := time.Now().AddDate(0, 0, 1)
+
+		tx, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(from.ID), models.AccountID(to.ID), 30000, "Savings", due)
+		testutil.AssertNoError(t, err)
+		if !tx.IsPending {
+			t.Error("expected future-dated transfer to be pending")
+		}
+		if got := balanceOf(t, acctSvc, user.ID, from.ID); got != 100000 {
+			t.Errorf("expected from balance 100000 before settle, got %d", got)
+		}
+
+		_, err = txSvc.SettlePendingTransactions(due)
+		testutil.AssertNoError(t, err)
+		if got := balanceOf(t, acctSvc, user.ID, from.ID); got != 70000 {
+			t.Errorf("expected from balance 70000 after settle, got %d", got)
+		}
+		if got := balanceOf(t, acctSvc, user.ID, to.ID); got != 30000 {
+			t.Errorf("expected to balance 30000 after settle, got %d", got)
+		}
+	})
+
+	t.Run("filters_user_transactions_by_pending", func(t *testing.T) {
+		_, txSvc, user, account := setup(t, 100000)
+
+		_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 1000, "", time.Now(), false, "", nil)
+		testutil.AssertNoError(t, err)
+		_, err = txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(account.ID), nil, models.TransactionTypeExpense, 2000, "", time.Now().AddDate(0, 0, 2), false, "", nil)
+		testutil.AssertNoError(t, err)
+
+		pending := true
+		result, err := txSvc.GetUserTransactions(models.UserID(user.ID), pagination.PageRequest{}, TransactionFilter{Pending: &pending})
+		testutil.AssertNoError(t, err)
+		if len(result.Data) != 1 || result.Data[0].Amount != 2000 {
+			t.Errorf("expected only the pending transaction, got %+v", result.Data)
+		}
+
+		settled := false
+		result, err = txSvc.GetUserTransactions(models.UserID(user.ID), pagination.PageRequest{}, TransactionFilter{Pending: &settled})
+		testutil.AssertNoError(t, err)
+		if len(result.Data) != 1 || result.Data[0].Amount != 1000 {
+			t.Errorf("expected only the settled transaction, got %+v", result.Data)
+		}
+	})
+}
+
+func TestConcurrentBalanceUpdates(t *testing.T) {
+	t.Parallel()
+	// setup opens the test DB with a single connection: SQLite's shared in-memory
+	// cache rejects concurrent writers, and one connection serializes transactions
+	// much like the row locks do on Postgres.
+	setup := func(t *testing.T) (*gorm.DB, TransactionServicer, AccountServicer, *models.User) {
+		t.Helper()
+		db := testutil.SetupTestDB(t)
+		t.Cleanup(func() { testutil.TeardownTestDB(t, db) })
+		sqlDB, err := db.DB()
+		testutil.AssertNoError(t, err)
+		sqlDB.SetMaxOpenConns(1)
+
+		acctSvc := NewAccountService(db, nil)
+		return db, NewTransactionService(db, acctSvc, nil), acctSvc, testutil.CreateTestUser(t, db)
+	}
+
+	t.Run("many_transfers_and_incomes_keep_exact_balances", func(t *testing.T) {
+		db, txSvc, acctSvc, user := setup(t)
+		from := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 100000)
+		to := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 0)
+
+		const transfers, incomes = 50, 20
+		var wg sync.WaitGroup
+		errs := make(chan error, transfers+incomes)
+		for i := 0; i < transfers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(from.ID), models.AccountID(to.ID), 1000, "move", time.Now())
+				errs <- err
+			}()
+		}
+		for i := 0; i < incomes; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := txSvc.CreateTransaction(models.UserID(user.ID), models.AccountID(from.ID), nil, models.TransactionTypeIncome, 500, "pay", time.Now(), false, "", nil)
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			testutil.AssertNoError(t, err)
+		}
+
+		gotFrom, err := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(from.ID))
+		testutil.AssertNoError(t, err)
+		gotTo, err := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(to.ID))
+		testutil.AssertNoError(t, err)
+
+		if want := int64(100000 - transfers*1000 + incomes*500); gotFrom.Balance != want {
+			t.Errorf("expected source balance %d, got %d", want, gotFrom.Balance)
+		}
+		if want := int64(transfers * 1000); gotTo.Balance != want {
+			t.Errorf("expected destination balance %d, got %d", want, gotTo.Balance)
+		}
+	})
+
+	t.Run("concurrent_transfers_cannot_overdraw", func(t *testing.T) {
+		db, txSvc, acctSvc, user := setup(t)
+		from := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 5000)
+		to := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 0)
+
+		const attempts = 10
+		var wg sync.WaitGroup
+		errs := make(chan error, attempts)
+		for i := 0; i < attempts; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := txSvc.CreateTransfer(models.UserID(user.ID), models.AccountID(from.ID), models.AccountID(to.ID), 1000, "move", time.Now())
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		succeeded := 0
+		for err := range errs {
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, apperrors.ErrInsufficientBalance):
+			default:
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if succeeded != 5 {
+			t.Errorf("expected exactly 5 transfers to succeed, got %d", succeeded)
+		}
+
+		gotFrom, err := acctSvc.GetAccountByID(models.UserID(user.ID), models.AccountID(from.ID))
+		testutil.AssertNoError(t, err)
+		if gotFrom.Balance != 0 {
+			t.Errorf("expected source balance 0, got %d", gotFrom.Balance)
+		}
+	})
 }
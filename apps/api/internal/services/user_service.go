@@ -1,56 +1,94 @@
 package services
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 
 	apperrors "kuberan/internal/errors"
 	"kuberan/internal/models"
+	"kuberan/internal/password"
 )
 
 const (
 	maxFailedAttempts = 5
 	lockoutDuration   = 15 * time.Minute
+
+	// usernameChangeCooldown is how long a username must be kept before it can
+	// be changed again. Setting the first username is not limited.
+	usernameChangeCooldown = 30 * 24 * time.Hour
 )
 
+// UserServiceOptions tunes registration rules.
+type UserServiceOptions struct {
+	// RejectPlusAddressDuplicates refuses a registration whose address differs
+	// from an existing user's only by a "+tag" in the local part.
+	RejectPlusAddressDuplicates bool
+
+	// BcryptCost is the cost of new password hashes; 0 uses password.DefaultCost.
+	// Hashes already stored keep the cost they were made with.
+	BcryptCost int
+}
+
 // userService handles user-related business logic.
 type userService struct {
-	db *gorm.DB
+	db   *gorm.DB
+	opts UserServiceOptions
 }
 
-// NewUserService creates a new UserServicer.
+// NewUserService creates a new UserServicer with the default options.
 func NewUserService(db *gorm.DB) UserServicer {
-	return &userService{db: db}
+	return NewUserServiceWithOptions(db, UserServiceOptions{})
+}
+
+// NewUserServiceWithOptions creates a new UserServicer with the given options.
+func NewUserServiceWithOptions(db *gorm.DB, opts UserServiceOptions) UserServicer {
+	return &userService{db: db, opts: opts}
+}
+
+// normalizeEmail trims surrounding whitespace and lowercases an address, which
+// is the form every email is stored and looked up in.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
 }
 
 // CreateUser registers a new user
-func (s *userService) CreateUser(email, password, firstName, lastName string) (*models.User, error) {
+func (s *userService) CreateUser(email, plaintext, firstName, lastName string) (*models.User, error) {
 	// Validate input
-	if email == "" || password == "" {
+	email = normalizeEmail(email)
+	if email == "" || plaintext == "" {
 		return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "email and password are required")
 	}
 
-	// Check if user with email exists
-	var count int64
-	s.db.Model(&models.User{}).Where("email = ?", email).Count(&count)
-	if count > 0 {
+	// Check if the email belongs to a user or is pending verification for one
+	taken, err := emailInUse(s.db, email, "")
+	if err != nil {
+		return nil, err
+	}
+	if !taken && s.opts.RejectPlusAddressDuplicates {
+		taken, err = plusAddressInUse(s.db, email)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if taken {
 		return nil, apperrors.ErrDuplicateEmail
 	}
 
 	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashedPassword, err := password.Hash(plaintext, s.opts.BcryptCost)
 	if err != nil {
 		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
 	}
 
 	// Create user
 	user := &models.User{
-		Email:     strings.ToLower(email),
-		Password:  string(hashedPassword),
+		Email:     email,
+		Password:  hashedPassword,
 		FirstName: firstName,
 		LastName:  lastName,
 		IsActive:  true,
@@ -66,7 +104,20 @@ func (s *userService) CreateUser(email, password, firstName, lastName string) (*
 // GetUserByEmail retrieves a user by email
 func (s *userService) GetUserByEmail(email string) (*models.User, error) {
 	var user models.User
-	if err := s.db.Where("email = ? AND is_active = ?", strings.ToLower(email), true).First(&user).Error; err != nil {
+	if err := s.db.Where("email = ? AND is_active = ?", normalizeEmail(email), true).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrUserNotFound
+		}
+		return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return &user, nil
+}
+
+// getUserByUsername retrieves an active user by username, ignoring case.
+func (s *userService) getUserByUsername(username string) (*models.User, error) {
+	var user models.User
+	if err := s.db.Where("LOWER(username) = ? AND is_active = ?", strings.ToLower(strings.TrimSpace(username)), true).
+		First(&user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, apperrors.ErrUserNotFound
 		}
@@ -88,15 +139,50 @@ func (s *userService) GetUserByID(id string) (*models.User, error) {
 }
 
 // VerifyPassword checks if the provided password matches the stored hash
-func (s *userService) VerifyPassword(user *models.User, password string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
-	return err == nil
+func (s *userService) VerifyPassword(user *models.User, plaintext string) bool {
+	return password.Matches(user.Password, plaintext)
+}
+
+// ChangePassword replaces the user's password after checking the current one.
+// The new hash uses the configured cost, and the stored refresh token is
+// cleared so that other sessions must log in again.
+func (s *userService) ChangePassword(userID, currentPassword, newPassword string) error {
+	if newPassword == "" {
+		return apperrors.WithMessage(apperrors.ErrInvalidInput, "new password is required")
+	}
+	user, err := s.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	if !s.VerifyPassword(user, currentPassword) {
+		return apperrors.ErrIncorrectPassword
+	}
+
+	hashedPassword, err := password.Hash(newPassword, s.opts.BcryptCost)
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if err := s.db.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"password":           hashedPassword,
+		"refresh_token_hash": "",
+	}).Error; err != nil {
+		return apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return nil
 }
 
-// AttemptLogin authenticates a user by email and password with lockout protection.
+// AttemptLogin authenticates a user by email or username and password with
+// lockout protection. A login containing "@" is an email; anything else is a
+// username, since usernames cannot contain "@".
 // Returns the user on success, or an appropriate AppError on failure.
-func (s *userService) AttemptLogin(email, password string) (*models.User, error) {
-	user, err := s.GetUserByEmail(email)
+func (s *userService) AttemptLogin(login, password string) (*models.User, error) {
+	var user *models.User
+	var err error
+	if strings.Contains(login, "@") {
+		user, err = s.GetUserByEmail(login)
+	} else {
+		user, err = s.getUserByUsername(login)
+	}
 	if err != nil {
 		return nil, apperrors.ErrInvalidCredentials
 	}
@@ -143,3 +229,126 @@ func (s *userService) GetRefreshTokenHash(userID string) (string, error) {
 	}
 	return user.RefreshTokenHash, nil
 }
+
+// UpdateProfile applies partial updates to the user's display name, username and
+// preferences. A username can be changed once per usernameChangeCooldown; setting
+// it to its current value is a no-op. Email and password are deliberately not
+// updatable here.
+func (s *userService) UpdateProfile(userID string, fields ProfileUpdateFields) (*models.User, error) {
+	user, err := s.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if fields.DisplayName != nil {
+		updates["display_name"] = strings.TrimSpace(*fields.DisplayName)
+	}
+	if fields.Username != nil {
+		username := strings.TrimSpace(*fields.Username)
+		if user.Username == nil || *user.Username != username {
+			if err := s.checkUsernameChange(user, username); err != nil {
+				return nil, err
+			}
+			updates["username"] = username
+			updates["username_changed_at"] = time.Now()
+		}
+	}
+	if fields.BaseCurrency != nil {
+		updates["base_currency"] = *fields.BaseCurrency
+	}
+	if fields.Locale != nil {
+		updates["locale"] = *fields.Locale
+	}
+	if fields.WeekStart != nil {
+		updates["week_start"] = *fields.WeekStart
+	}
+	if fields.Timezone != nil {
+		updates["timezone"] = *fields.Timezone
+	}
+	if fields.CostBasisMethod != nil {
+		updates["cost_basis_method"] = *fields.CostBasisMethod
+	}
+	if fields.Preferences != nil {
+		var prefs map[string]interface{}
+		if err := json.Unmarshal([]byte(*fields.Preferences), &prefs); err != nil || prefs == nil {
+			return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "preferences must be a JSON object")
+		}
+		updates["preferences"] = *fields.Preferences
+	}
+	if fields.LargeTransactionThreshold != nil {
+		if *fields.LargeTransactionThreshold < 0 {
+			return nil, apperrors.WithMessage(apperrors.ErrInvalidInput, "large transaction threshold cannot be negative")
+		}
+		updates["large_transaction_threshold"] = *fields.LargeTransactionThreshold
+	}
+
+	if len(updates) > 0 {
+		if err := s.db.Model(user).Updates(updates).Error; err != nil {
+			if _, ok := updates["username"]; ok && isUniqueConstraintError(err) {
+				return nil, apperrors.ErrDuplicateUsername
+			}
+			return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+		// Reload to get fresh data
+		if err := s.db.Where("id = ?", user.ID).First(user).Error; err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInternalServer, err)
+		}
+	}
+
+	return user, nil
+}
+
+// checkUsernameChange refuses a new username for user while the previous change
+// is within the cooldown, or when another user holds it in any case. A change
+// that only alters case is still a change.
+func (s *userService) checkUsernameChange(user *models.User, username string) error {
+	if username == "" {
+		return apperrors.WithMessage(apperrors.ErrInvalidInput, "username cannot be empty")
+	}
+	if user.UsernameChangedAt != nil {
+		if next := user.UsernameChangedAt.Add(usernameChangeCooldown); time.Now().Before(next) {
+			return apperrors.WithMessage(apperrors.ErrUsernameChangeTooSoon,
+				fmt.Sprintf("username can be changed again after %s", next.UTC().Format(time.RFC3339)))
+		}
+	}
+
+	var count int64
+	if err := s.db.Model(&models.User{}).
+		Where("LOWER(username) = ? AND id <> ?", strings.ToLower(username), user.ID).
+		Count(&count).Error; err != nil {
+		return apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	if count > 0 {
+		return apperrors.ErrDuplicateUsername
+	}
+	return nil
+}
+
+// plusAddressInUse reports whether a user is registered under the same mailbox
+// as email once any "+tag" is dropped from the local part, e.g. whether
+// "ann+bank@example.com" collides with "ann@example.com" or "ann+shop@example.com".
+// email must already be normalized.
+func plusAddressInUse(db *gorm.DB, email string) (bool, error) {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false, nil
+	}
+	local, domain := email[:at], email[at+1:]
+	if plus := strings.Index(local, "+"); plus >= 0 {
+		local = local[:plus]
+	}
+
+	var count int64
+	if err := db.Model(&models.User{}).
+		Where("email = ? OR email LIKE ? ESCAPE '\\'", local+"@"+domain, escapeLike(local)+"+%@"+escapeLike(domain)).
+		Count(&count).Error; err != nil {
+		return false, apperrors.Wrap(apperrors.ErrInternalServer, err)
+	}
+	return count > 0, nil
+}
+
+// escapeLike escapes the LIKE wildcards in s so it matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
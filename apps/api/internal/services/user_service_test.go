@@ -4,22 +4,25 @@ import (
 	"testing"
 	"time"
 
+	"kuberan/internal/models"
+	"kuberan/internal/password"
 	"kuberan/internal/testutil"
+	"kuberan/internal/uuid"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 func TestCreateUser(t *testing.T) {
+	t.Parallel()
 	t.Run("valid", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewUserService(db)
 
 		user, err := svc.CreateUser("alice@example.com", "password123", "Alice", "Smith")
 		testutil.AssertNoError(t, err)
 
-		if user.ID == 0 {
-			t.Fatal("expected non-zero user ID")
+		if user.ID == "" {
+			t.Fatal("expected non-empty user ID")
 		}
 		if user.Email != "alice@example.com" {
 			t.Errorf("expected email alice@example.com, got %s", user.Email)
@@ -33,8 +36,7 @@ func TestCreateUser(t *testing.T) {
 	})
 
 	t.Run("duplicate_email", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewUserService(db)
 
 		_, err := svc.CreateUser("dup@example.com", "password123", "", "")
@@ -45,8 +47,7 @@ func TestCreateUser(t *testing.T) {
 	})
 
 	t.Run("empty_email", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewUserService(db)
 
 		_, err := svc.CreateUser("", "password123", "", "")
@@ -54,8 +55,7 @@ func TestCreateUser(t *testing.T) {
 	})
 
 	t.Run("empty_password", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewUserService(db)
 
 		_, err := svc.CreateUser("test@example.com", "", "", "")
@@ -63,8 +63,7 @@ func TestCreateUser(t *testing.T) {
 	})
 
 	t.Run("email_normalized_to_lowercase", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewUserService(db)
 
 		user, err := svc.CreateUser("Alice@EXAMPLE.COM", "password123", "", "")
@@ -74,12 +73,92 @@ func TestCreateUser(t *testing.T) {
 			t.Errorf("expected lowercased email, got %s", user.Email)
 		}
 	})
+
+	t.Run("mixed_case_and_whitespace_duplicate", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewUserService(db)
+
+		user, err := svc.CreateUser("  Foo@Bar.com ", "password123", "", "")
+		testutil.AssertNoError(t, err)
+		if user.Email != "foo@bar.com" {
+			t.Errorf("expected normalized email foo@bar.com, got %q", user.Email)
+		}
+
+		_, err = svc.CreateUser("foo@bar.com", "password456", "", "")
+		testutil.AssertAppError(t, err, "DUPLICATE_EMAIL")
+		_, err = svc.CreateUser("FOO@BAR.COM", "password456", "", "")
+		testutil.AssertAppError(t, err, "DUPLICATE_EMAIL")
+	})
+
+	t.Run("whitespace_only_email", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewUserService(db)
+
+		_, err := svc.CreateUser("   ", "password123", "", "")
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+
+	t.Run("plus_address_allowed_by_default", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewUserService(db)
+
+		_, err := svc.CreateUser("ann@example.com", "password123", "", "")
+		testutil.AssertNoError(t, err)
+		_, err = svc.CreateUser("ann+bank@example.com", "password123", "", "")
+		testutil.AssertNoError(t, err)
+	})
+
+	t.Run("plus_address_duplicates_rejected_when_enabled", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewUserServiceWithOptions(db, UserServiceOptions{RejectPlusAddressDuplicates: true})
+
+		_, err := svc.CreateUser("ann+bank@example.com", "password123", "", "")
+		testutil.AssertNoError(t, err)
+
+		for _, email := range []string{"ann@example.com", "Ann+Shop@Example.com"} {
+			_, err = svc.CreateUser(email, "password123", "", "")
+			testutil.AssertAppError(t, err, "DUPLICATE_EMAIL")
+		}
+
+		// Only the tag is ignored; other mailboxes on the domain, including
+		// ones that would match if "_" were treated as a LIKE wildcard, are free.
+		for _, email := range []string{"anne@example.com", "a_n@example.com"} {
+			_, err = svc.CreateUser(email, "password123", "", "")
+			testutil.AssertNoError(t, err)
+		}
+	})
+
+	t.Run("hashes_with_configured_cost", func(t *testing.T) {
+		db := testutil.WithTx(t)
+
+		for _, tc := range []struct {
+			opts UserServiceOptions
+			want int
+		}{
+			{UserServiceOptions{BcryptCost: bcrypt.MinCost}, bcrypt.MinCost},
+			{UserServiceOptions{BcryptCost: 11}, 11},
+			{UserServiceOptions{}, password.DefaultCost},
+		} {
+			svc := NewUserServiceWithOptions(db, tc.opts)
+			user, err := svc.CreateUser(uuid.New()+"@example.com", "password123", "", "")
+			testutil.AssertNoError(t, err)
+
+			cost, err := bcrypt.Cost([]byte(user.Password))
+			testutil.AssertNoError(t, err)
+			if cost != tc.want {
+				t.Errorf("expected cost %d, got %d", tc.want, cost)
+			}
+			if !svc.VerifyPassword(user, "password123") {
+				t.Errorf("expected the password to verify at cost %d", tc.want)
+			}
+		}
+	})
 }
 
 func TestGetUserByEmail(t *testing.T) {
+	t.Parallel()
 	t.Run("found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewUserService(db)
 
 		created := testutil.CreateTestUserWithEmail(t, db, "found@example.com")
@@ -87,13 +166,12 @@ func TestGetUserByEmail(t *testing.T) {
 		testutil.AssertNoError(t, err)
 
 		if user.ID != created.ID {
-			t.Errorf("expected user ID %d, got %d", created.ID, user.ID)
+			t.Errorf("expected user ID %s, got %s", created.ID, user.ID)
 		}
 	})
 
 	t.Run("not_found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewUserService(db)
 
 		_, err := svc.GetUserByEmail("nonexistent@example.com")
@@ -101,8 +179,7 @@ func TestGetUserByEmail(t *testing.T) {
 	})
 
 	t.Run("inactive_user", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewUserService(db)
 
 		user := testutil.CreateTestUserWithEmail(t, db, "inactive@example.com")
@@ -114,9 +191,9 @@ func TestGetUserByEmail(t *testing.T) {
 }
 
 func TestGetUserByID(t *testing.T) {
+	t.Parallel()
 	t.Run("found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewUserService(db)
 
 		created := testutil.CreateTestUser(t, db)
@@ -129,22 +206,21 @@ func TestGetUserByID(t *testing.T) {
 	})
 
 	t.Run("not_found", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewUserService(db)
 
-		_, err := svc.GetUserByID(99999)
+		_, err := svc.GetUserByID(uuid.New())
 		testutil.AssertAppError(t, err, "USER_NOT_FOUND")
 	})
 }
 
 func TestVerifyPassword(t *testing.T) {
+	t.Parallel()
 	t.Run("correct", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewUserService(db)
 
-		// Fixture uses "password123" with bcrypt.MinCost
+		// Fixture uses "password123" with testutil.BcryptCost
 		user := testutil.CreateTestUser(t, db)
 		if !svc.VerifyPassword(user, "password123") {
 			t.Error("expected password verification to succeed")
@@ -152,8 +228,7 @@ func TestVerifyPassword(t *testing.T) {
 	})
 
 	t.Run("incorrect", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewUserService(db)
 
 		user := testutil.CreateTestUser(t, db)
@@ -163,10 +238,99 @@ func TestVerifyPassword(t *testing.T) {
 	})
 }
 
+func TestChangePassword(t *testing.T) {
+	t.Parallel()
+	t.Run("replaces_the_password_at_configured_cost", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewUserServiceWithOptions(db, UserServiceOptions{BcryptCost: 5})
+		user := testutil.CreateTestUser(t, db)
+		testutil.AssertNoError(t, svc.StoreRefreshTokenHash(user.ID, "old-refresh-token-hash"))
+
+		testutil.AssertNoError(t, svc.ChangePassword(user.ID, "password123", "new-password"))
+
+		updated, err := svc.GetUserByID(user.ID)
+		testutil.AssertNoError(t, err)
+		if !svc.VerifyPassword(updated, "new-password") || svc.VerifyPassword(updated, "password123") {
+			t.Error("expected only the new password to verify")
+		}
+		cost, err := bcrypt.Cost([]byte(updated.Password))
+		testutil.AssertNoError(t, err)
+		if cost != 5 {
+			t.Errorf("expected cost 5, got %d", cost)
+		}
+		if updated.RefreshTokenHash != "" {
+			t.Error("expected the refresh token to be revoked")
+		}
+	})
+
+	t.Run("rejects_wrong_current_password", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewUserService(db)
+		user := testutil.CreateTestUser(t, db)
+
+		err := svc.ChangePassword(user.ID, "wrongpassword", "new-password")
+		testutil.AssertAppError(t, err, "INCORRECT_PASSWORD")
+
+		unchanged, err := svc.GetUserByID(user.ID)
+		testutil.AssertNoError(t, err)
+		if !svc.VerifyPassword(unchanged, "password123") {
+			t.Error("expected the old password to remain")
+		}
+	})
+
+	t.Run("user_not_found", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewUserService(db)
+
+		err := svc.ChangePassword(uuid.New(), "password123", "new-password")
+		testutil.AssertAppError(t, err, "USER_NOT_FOUND")
+	})
+}
+
 func TestAttemptLogin(t *testing.T) {
+	t.Parallel()
+	t.Run("mixed_case_email", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewUserService(db)
+
+		created, err := svc.CreateUser("Mixed.Case@Example.com", "password123", "", "")
+		testutil.AssertNoError(t, err)
+
+		for _, email := range []string{"mixed.case@example.com", "  MIXED.CASE@EXAMPLE.COM "} {
+			user, err := svc.AttemptLogin(email, "password123")
+			testutil.AssertNoError(t, err)
+			if user.ID != created.ID {
+				t.Errorf("%q: expected user %s, got %s", email, created.ID, user.ID)
+			}
+		}
+	})
+
+	t.Run("by_username_or_email", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewUserService(db)
+
+		created, err := svc.CreateUser("jane@example.com", "password123", "", "")
+		testutil.AssertNoError(t, err)
+		username := "Jane_Doe"
+		_, err = svc.UpdateProfile(created.ID, ProfileUpdateFields{Username: &username})
+		testutil.AssertNoError(t, err)
+
+		for _, login := range []string{"jane@example.com", "Jane_Doe", "jane_doe", " JANE_DOE "} {
+			user, err := svc.AttemptLogin(login, "password123")
+			testutil.AssertNoError(t, err)
+			if user.ID != created.ID {
+				t.Errorf("%q: expected user %s, got %s", login, created.ID, user.ID)
+			}
+		}
+
+		_, err = svc.AttemptLogin("jane_doe", "wrongpassword")
+		testutil.AssertAppError(t, err, "INVALID_CREDENTIALS")
+		_, err = svc.AttemptLogin("john_doe", "password123")
+		testutil.AssertAppError(t, err, "INVALID_CREDENTIALS")
+	})
+
 	t.Run("success_resets_attempts", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewUserService(db)
 
 		// Create user via service so password is hashed with DefaultCost
@@ -188,8 +352,7 @@ func TestAttemptLogin(t *testing.T) {
 	})
 
 	t.Run("wrong_password_increments_attempts", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewUserService(db)
 
 		_, err := svc.CreateUser("fail@example.com", "password123", "", "")
@@ -206,8 +369,7 @@ func TestAttemptLogin(t *testing.T) {
 	})
 
 	t.Run("lockout_after_5_failures", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewUserService(db)
 
 		_, err := svc.CreateUser("lockout@example.com", "password123", "", "")
@@ -230,8 +392,7 @@ func TestAttemptLogin(t *testing.T) {
 	})
 
 	t.Run("locked_account_returns_error", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewUserService(db)
 
 		_, err := svc.CreateUser("locked@example.com", "password123", "", "")
@@ -246,8 +407,7 @@ func TestAttemptLogin(t *testing.T) {
 	})
 
 	t.Run("nonexistent_user", func(t *testing.T) {
-		db := testutil.SetupTestDB(t)
-		defer testutil.TeardownTestDB(t, db)
+		db := testutil.WithTx(t)
 		svc := NewUserService(db)
 
 		_, err := svc.AttemptLogin("nobody@example.com", "password123")
@@ -256,8 +416,8 @@ func TestAttemptLogin(t *testing.T) {
 }
 
 func TestStoreAndGetRefreshTokenHash(t *testing.T) {
-	db := testutil.SetupTestDB(t)
-	defer testutil.TeardownTestDB(t, db)
+	t.Parallel()
+	db := testutil.WithTx(t)
 	svc := NewUserService(db)
 
 	user := testutil.CreateTestUser(t, db)
@@ -275,8 +435,8 @@ func TestStoreAndGetRefreshTokenHash(t *testing.T) {
 }
 
 func TestCreateUser_password_is_hashed(t *testing.T) {
-	db := testutil.SetupTestDB(t)
-	defer testutil.TeardownTestDB(t, db)
+	t.Parallel()
+	db := testutil.WithTx(t)
 	svc := NewUserService(db)
 
 	user, err := svc.CreateUser("hash@example.com", "mypassword", "", "")
@@ -290,3 +450,152 @@ func TestCreateUser_password_is_hashed(t *testing.T) {
 		t.Error("password hash should be valid bcrypt")
 	}
 }
+
+func TestUpdateProfile(t *testing.T) {
+	t.Parallel()
+	t.Run("partial_update", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewUserService(db)
+		user := testutil.CreateTestUser(t, db)
+
+		locale := "en-GB"
+		updated, err := svc.UpdateProfile(user.ID, ProfileUpdateFields{Locale: &locale})
+		testutil.AssertNoError(t, err)
+
+		if updated.Locale != "en-GB" {
+			t.Errorf("expected locale en-GB, got %s", updated.Locale)
+		}
+		if updated.BaseCurrency != "USD" {
+			t.Errorf("expected base currency to stay USD, got %s", updated.BaseCurrency)
+		}
+		if updated.WeekStart != models.WeekStartMonday {
+			t.Errorf("expected week start to stay monday, got %s", updated.WeekStart)
+		}
+		if updated.Timezone != "" {
+			t.Errorf("expected timezone to stay unset, got %s", updated.Timezone)
+		}
+	})
+
+	t.Run("all_fields", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewUserService(db)
+		user := testutil.CreateTestUser(t, db)
+
+		name := "  Ali  "
+		currency := "MYR"
+		weekStart := models.WeekStartSunday
+		timezone := "Asia/Kuala_Lumpur"
+		prefs := `{"theme":"dark","compact":true}`
+		updated, err := svc.UpdateProfile(user.ID, ProfileUpdateFields{
+			DisplayName:  &name,
+			BaseCurrency: &currency,
+			WeekStart:    &weekStart,
+			Timezone:     &timezone,
+			Preferences:  &prefs,
+		})
+		testutil.AssertNoError(t, err)
+
+		if updated.DisplayName != "Ali" {
+			t.Errorf("expected trimmed display name Ali, got %q", updated.DisplayName)
+		}
+		if updated.BaseCurrency != "MYR" {
+			t.Errorf("expected base currency MYR, got %s", updated.BaseCurrency)
+		}
+		if updated.WeekStart != models.WeekStartSunday {
+			t.Errorf("expected week start sunday, got %s", updated.WeekStart)
+		}
+		if updated.Timezone != "Asia/Kuala_Lumpur" {
+			t.Errorf("expected timezone Asia/Kuala_Lumpur, got %s", updated.Timezone)
+		}
+		if updated.Preferences != prefs {
+			t.Errorf("expected preferences %s, got %s", prefs, updated.Preferences)
+		}
+		if updated.Email != user.Email {
+			t.Errorf("expected email unchanged, got %s", updated.Email)
+		}
+	})
+
+	t.Run("invalid_preferences", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewUserService(db)
+		user := testutil.CreateTestUser(t, db)
+
+		prefs := `["not","an","object"]`
+		_, err := svc.UpdateProfile(user.ID, ProfileUpdateFields{Preferences: &prefs})
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+
+	t.Run("large_transaction_threshold", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewUserService(db)
+		user := testutil.CreateTestUser(t, db)
+
+		threshold := int64(100000)
+		updated, err := svc.UpdateProfile(user.ID, ProfileUpdateFields{LargeTransactionThreshold: &threshold})
+		testutil.AssertNoError(t, err)
+		if updated.LargeTransactionThreshold != 100000 {
+			t.Errorf("expected threshold 100000, got %d", updated.LargeTransactionThreshold)
+		}
+
+		negative := int64(-1)
+		_, err = svc.UpdateProfile(user.ID, ProfileUpdateFields{LargeTransactionThreshold: &negative})
+		testutil.AssertAppError(t, err, "INVALID_INPUT")
+	})
+
+	t.Run("username_unique_regardless_of_case", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewUserService(db)
+		first := testutil.CreateTestUser(t, db)
+		second := testutil.CreateTestUser(t, db)
+
+		username := "jane_doe"
+		updated, err := svc.UpdateProfile(first.ID, ProfileUpdateFields{Username: &username})
+		testutil.AssertNoError(t, err)
+		if updated.Username == nil || *updated.Username != "jane_doe" {
+			t.Fatalf("expected username jane_doe, got %v", updated.Username)
+		}
+
+		taken := "JANE_DOE"
+		_, err = svc.UpdateProfile(second.ID, ProfileUpdateFields{Username: &taken})
+		testutil.AssertAppError(t, err, "DUPLICATE_USERNAME")
+	})
+
+	t.Run("username_change_cooldown", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewUserService(db)
+		user := testutil.CreateTestUser(t, db)
+
+		first := "first_name"
+		_, err := svc.UpdateProfile(user.ID, ProfileUpdateFields{Username: &first})
+		testutil.AssertNoError(t, err)
+
+		// Resubmitting the current username is not a change
+		_, err = svc.UpdateProfile(user.ID, ProfileUpdateFields{Username: &first})
+		testutil.AssertNoError(t, err)
+
+		second := "second_name"
+		_, err = svc.UpdateProfile(user.ID, ProfileUpdateFields{Username: &second})
+		testutil.AssertAppError(t, err, "USERNAME_CHANGE_TOO_SOON")
+
+		recased := "First_Name"
+		_, err = svc.UpdateProfile(user.ID, ProfileUpdateFields{Username: &recased})
+		testutil.AssertAppError(t, err, "USERNAME_CHANGE_TOO_SOON")
+
+		testutil.AssertNoError(t, db.Model(&models.User{}).Where("id = ?", user.ID).
+			Update("username_changed_at", time.Now().Add(-usernameChangeCooldown-time.Hour)).Error)
+		updated, err := svc.UpdateProfile(user.ID, ProfileUpdateFields{Username: &second})
+		testutil.AssertNoError(t, err)
+		if updated.Username == nil || *updated.Username != "second_name" {
+			t.Errorf("expected username second_name after the cooldown, got %v", updated.Username)
+		}
+	})
+
+	t.Run("user_not_found", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		svc := NewUserService(db)
+
+		name := "x"
+		_, err := svc.UpdateProfile(uuid.New(), ProfileUpdateFields{DisplayName: &name})
+		testutil.AssertAppError(t, err, "USER_NOT_FOUND")
+	})
+}
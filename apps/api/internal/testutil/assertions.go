@@ -8,7 +8,7 @@ import (
 )
 
 // AssertAppError checks that err is an *AppError with the expected error code.
-func AssertAppError(t *testing.T, err error, expectedCode string) {
+func AssertAppError(t testing.TB, err error, expectedCode string) {
 	t.Helper()
 
 	if err == nil {
@@ -26,7 +26,7 @@ func AssertAppError(t *testing.T, err error, expectedCode string) {
 }
 
 // AssertNoError fails the test if err is not nil.
-func AssertNoError(t *testing.T, err error) {
+func AssertNoError(t testing.TB, err error) {
 	t.Helper()
 
 	if err != nil {
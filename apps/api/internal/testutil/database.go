@@ -3,6 +3,9 @@
 package testutil
 
 import (
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 
 	"kuberan/internal/models"
@@ -14,36 +17,142 @@ import (
 // allModels is the list of all GORM models to auto-migrate in tests.
 var allModels = []interface{}{
 	&models.User{},
+	&models.PendingEmailChange{},
+	&models.PendingBulkDelete{},
+	&models.RestoreProgress{},
+	&models.RestoreIDMapping{},
+	&models.AccountGroup{},
 	&models.Account{},
+	&models.AccountShare{},
+	&models.AccountShareAccount{},
 	&models.Category{},
 	&models.Transaction{},
 	&models.Budget{},
+	&models.CategorizationRule{},
 	&models.Security{},
 	&models.Investment{},
 	&models.InvestmentTransaction{},
 	&models.SecurityPrice{},
 	&models.PortfolioSnapshot{},
 	&models.AuditLog{},
+	&models.PipelineAPIKey{},
+	&models.OracleRun{},
+	&models.OracleRunError{},
+	&models.OutboxEvent{},
+	&models.ExchangeRate{},
 }
 
-// SetupTestDB creates an in-memory SQLite database with all models migrated.
-func SetupTestDB(t *testing.T) *gorm.DB {
+// The shared database is opened and migrated once per test binary and then
+// handed out one transaction at a time. SQLite allows a single writer, so a
+// single connection and a semaphore serialise the tests that use it; parallel
+// tests simply queue for their turn.
+var shared struct {
+	once sync.Once
+	db   *gorm.DB
+	err  error
+
+	mu     sync.Mutex
+	holder string // name of the test holding the transaction
+	sem    chan struct{}
+}
+
+// sharedDB returns the shared database, opening and migrating it on first use.
+func sharedDB(t testing.TB) *gorm.DB {
 	t.Helper()
 
-	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
-	if err != nil {
-		t.Fatalf("failed to open test database: %v", err)
+	shared.once.Do(func() {
+		shared.sem = make(chan struct{}, 1)
+		shared.db, shared.err = openDB("file:testutil_shared?mode=memory&cache=shared")
+		if shared.err != nil {
+			return
+		}
+		sqlDB, err := shared.db.DB()
+		if err != nil {
+			shared.err = err
+			return
+		}
+		// The in-memory database lives as long as its last connection
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+	})
+	if shared.err != nil {
+		t.Fatalf("failed to set up shared test database: %v", shared.err)
 	}
+	return shared.db
+}
 
-	if err := db.AutoMigrate(allModels...); err != nil {
-		t.Fatalf("failed to migrate test database: %v", err)
+// WithTx returns a transaction on the shared test database that is rolled
+// back when t finishes, so nothing a test writes is seen by any other test.
+// Transactions the code under test starts itself become savepoints inside it.
+//
+// Only one test holds the transaction at a time and the others wait for it,
+// so tests using WithTx may call t.Parallel. A test must not call WithTx
+// while it or one of its parents already holds one; subtests share their
+// parent's transaction instead.
+func WithTx(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	db := sharedDB(t)
+
+	shared.mu.Lock()
+	holder := shared.holder
+	shared.mu.Unlock()
+	if holder != "" && (t.Name() == holder || strings.HasPrefix(t.Name(), holder+"/")) {
+		t.Fatalf("WithTx called while %s already holds the test transaction", holder)
 	}
 
+	shared.sem <- struct{}{}
+	tx := db.Begin()
+	if tx.Error != nil {
+		<-shared.sem
+		t.Fatalf("failed to begin test transaction: %v", tx.Error)
+	}
+
+	shared.mu.Lock()
+	shared.holder = t.Name()
+	shared.mu.Unlock()
+
+	t.Cleanup(func() {
+		if err := tx.Rollback().Error; err != nil {
+			t.Errorf("failed to roll back test transaction: %v", err)
+		}
+		shared.mu.Lock()
+		shared.holder = ""
+		shared.mu.Unlock()
+		<-shared.sem
+	})
+	return tx
+}
+
+// SetupTestDB creates a private in-memory SQLite database with all models
+// migrated. Prefer WithTx; this is for tests that need what a transaction
+// cannot give them, such as registering GORM callbacks, running concurrent
+// transactions or dropping tables. Close it with TeardownTestDB.
+func SetupTestDB(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	db, err := openDB(fmt.Sprintf("file:testutil_%d?mode=memory&cache=shared", nextID()))
+	if err != nil {
+		t.Fatalf("failed to set up test database: %v", err)
+	}
 	return db
 }
 
-// TeardownTestDB closes the underlying database connection.
-func TeardownTestDB(t *testing.T, db *gorm.DB) {
+// openDB opens the SQLite database at dsn and migrates all models.
+func openDB(dsn string) (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+	if err := db.AutoMigrate(allModels...); err != nil {
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+	return db, nil
+}
+
+// TeardownTestDB closes a database from SetupTestDB.
+func TeardownTestDB(t testing.TB, db *gorm.DB) {
 	t.Helper()
 
 	sqlDB, err := db.DB()
@@ -7,11 +7,16 @@ import (
 	"time"
 
 	"kuberan/internal/models"
+	"kuberan/internal/password"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
+// BcryptCost is the cost of password hashes made in tests. The cheapest cost
+// keeps the many users tests create fast; production requires at least 10.
+const BcryptCost = bcrypt.MinCost
+
 // counter provides unique values across fixtures within a test run.
 var counter atomic.Int64
 
@@ -20,24 +25,24 @@ func nextID() int64 {
 }
 
 // CreateTestUser creates a user with a hashed password and unique email.
-func CreateTestUser(t *testing.T, db *gorm.DB) *models.User {
+func CreateTestUser(t testing.TB, db *gorm.DB) *models.User {
 	t.Helper()
 	email := fmt.Sprintf("user%d@test.com", nextID())
 	return CreateTestUserWithEmail(t, db, email)
 }
 
 // CreateTestUserWithEmail creates a user with the given email.
-func CreateTestUserWithEmail(t *testing.T, db *gorm.DB, email string) *models.User {
+func CreateTestUserWithEmail(t testing.TB, db *gorm.DB, email string) *models.User {
 	t.Helper()
 
-	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	hash, err := password.Hash("password123", BcryptCost)
 	if err != nil {
 		t.Fatalf("failed to hash password: %v", err)
 	}
 
 	user := &models.User{
 		Email:    email,
-		Password: string(hash),
+		Password: hash,
 		IsActive: true,
 	}
 	if err := db.Create(user).Error; err != nil {
@@ -47,13 +52,13 @@ func CreateTestUserWithEmail(t *testing.T, db *gorm.DB, email string) *models.Us
 }
 
 // CreateTestCashAccount creates a cash account with zero balance.
-func CreateTestCashAccount(t *testing.T, db *gorm.DB, userID string) *models.Account {
+func CreateTestCashAccount(t testing.TB, db *gorm.DB, userID string) *models.Account {
 	t.Helper()
 	return CreateTestCashAccountWithBalance(t, db, userID, 0)
 }
 
 // CreateTestCashAccountWithBalance creates a cash account with the given balance (in cents).
-func CreateTestCashAccountWithBalance(t *testing.T, db *gorm.DB, userID string, balance int64) *models.Account {
+func CreateTestCashAccountWithBalance(t testing.TB, db *gorm.DB, userID string, balance int64) *models.Account {
 	t.Helper()
 
 	account := &models.Account{
@@ -71,7 +76,7 @@ func CreateTestCashAccountWithBalance(t *testing.T, db *gorm.DB, userID string,
 }
 
 // CreateTestInvestmentAccount creates an investment account.
-func CreateTestInvestmentAccount(t *testing.T, db *gorm.DB, userID string) *models.Account {
+func CreateTestInvestmentAccount(t testing.TB, db *gorm.DB, userID string) *models.Account {
 	t.Helper()
 
 	account := &models.Account{
@@ -88,7 +93,7 @@ func CreateTestInvestmentAccount(t *testing.T, db *gorm.DB, userID string) *mode
 }
 
 // CreateTestCreditCardAccount creates a credit card account with the given balance.
-func CreateTestCreditCardAccount(t *testing.T, db *gorm.DB, userID string, balance int64) *models.Account {
+func CreateTestCreditCardAccount(t testing.TB, db *gorm.DB, userID string, balance int64) *models.Account {
 	t.Helper()
 
 	account := &models.Account{
@@ -107,7 +112,7 @@ func CreateTestCreditCardAccount(t *testing.T, db *gorm.DB, userID string, balan
 }
 
 // CreateTestDebtAccount creates a debt account with the given balance.
-func CreateTestDebtAccount(t *testing.T, db *gorm.DB, userID string, balance int64) *models.Account {
+func CreateTestDebtAccount(t testing.TB, db *gorm.DB, userID string, balance int64) *models.Account {
 	t.Helper()
 
 	account := &models.Account{
@@ -126,7 +131,7 @@ func CreateTestDebtAccount(t *testing.T, db *gorm.DB, userID string, balance int
 }
 
 // CreateTestCategory creates a category of the given type.
-func CreateTestCategory(t *testing.T, db *gorm.DB, userID string, categoryType models.CategoryType) *models.Category {
+func CreateTestCategory(t testing.TB, db *gorm.DB, userID string, categoryType models.CategoryType) *models.Category {
 	t.Helper()
 
 	category := &models.Category{
@@ -141,7 +146,7 @@ func CreateTestCategory(t *testing.T, db *gorm.DB, userID string, categoryType m
 }
 
 // CreateTestTransaction creates a transaction of the given type and amount (in cents).
-func CreateTestTransaction(t *testing.T, db *gorm.DB, userID, accountID string, txType models.TransactionType, amount int64) *models.Transaction {
+func CreateTestTransaction(t testing.TB, db *gorm.DB, userID, accountID string, txType models.TransactionType, amount int64) *models.Transaction {
 	t.Helper()
 
 	tx := &models.Transaction{
@@ -158,7 +163,7 @@ func CreateTestTransaction(t *testing.T, db *gorm.DB, userID, accountID string,
 }
 
 // CreateTestBudget creates a monthly budget for the given category.
-func CreateTestBudget(t *testing.T, db *gorm.DB, userID, categoryID string) *models.Budget {
+func CreateTestBudget(t testing.TB, db *gorm.DB, userID, categoryID string) *models.Budget {
 	t.Helper()
 
 	budget := &models.Budget{
@@ -177,14 +182,14 @@ func CreateTestBudget(t *testing.T, db *gorm.DB, userID, categoryID string) *mod
 }
 
 // CreateTestSecurity creates a security with default values.
-func CreateTestSecurity(t *testing.T, db *gorm.DB) *models.Security {
+func CreateTestSecurity(t testing.TB, db *gorm.DB) *models.Security {
 	t.Helper()
 	n := nextID()
 	return CreateTestSecurityWithParams(t, db, fmt.Sprintf("SEC%d", n), fmt.Sprintf("Test Security %d", n), models.AssetTypeStock, "NYSE")
 }
 
 // CreateTestSecurityWithParams creates a security with the specified parameters.
-func CreateTestSecurityWithParams(t *testing.T, db *gorm.DB, symbol, name string, assetType models.AssetType, exchange string) *models.Security {
+func CreateTestSecurityWithParams(t testing.TB, db *gorm.DB, symbol, name string, assetType models.AssetType, exchange string) *models.Security {
 	t.Helper()
 	sec := &models.Security{
 		Symbol:    symbol,
@@ -200,7 +205,7 @@ func CreateTestSecurityWithParams(t *testing.T, db *gorm.DB, symbol, name string
 }
 
 // CreateTestInvestment creates an investment holding in the given account.
-func CreateTestInvestment(t *testing.T, db *gorm.DB, accountID, securityID string) *models.Investment {
+func CreateTestInvestment(t testing.TB, db *gorm.DB, accountID, securityID string) *models.Investment {
 	t.Helper()
 
 	inv := &models.Investment{
@@ -216,7 +221,7 @@ func CreateTestInvestment(t *testing.T, db *gorm.DB, accountID, securityID strin
 }
 
 // CreateTestSecurityPrice creates a security price record for testing.
-func CreateTestSecurityPrice(t *testing.T, db *gorm.DB, securityID string, price int64, recordedAt time.Time) *models.SecurityPrice {
+func CreateTestSecurityPrice(t testing.TB, db *gorm.DB, securityID string, price int64, recordedAt time.Time) *models.SecurityPrice {
 	t.Helper()
 
 	sp := &models.SecurityPrice{
@@ -229,3 +234,29 @@ func CreateTestSecurityPrice(t *testing.T, db *gorm.DB, securityID string, price
 	}
 	return sp
 }
+
+// CreateTestAccountShare creates an accepted share from owner to sharedWith.
+// With no accountIDs the share covers all of the owner's accounts.
+func CreateTestAccountShare(t testing.TB, db *gorm.DB, ownerID, sharedWithID string, role models.ShareRole, accountIDs ...string) *models.AccountShare {
+	t.Helper()
+
+	now := time.Now()
+	share := &models.AccountShare{
+		OwnerID:      ownerID,
+		SharedWithID: sharedWithID,
+		Role:         role,
+		AllAccounts:  len(accountIDs) == 0,
+		AcceptedAt:   &now,
+		AccountIDs:   accountIDs,
+	}
+	if err := db.Create(share).Error; err != nil {
+		t.Fatalf("failed to create test account share: %v", err)
+	}
+	for _, accountID := range accountIDs {
+		link := &models.AccountShareAccount{AccountShareID: share.ID, AccountID: accountID}
+		if err := db.Create(link).Error; err != nil {
+			t.Fatalf("failed to create test account share link: %v", err)
+		}
+	}
+	return share
+}
@@ -6,6 +6,8 @@ import (
 	"kuberan/internal/errors"
 	"kuberan/internal/models"
 	"kuberan/internal/testutil"
+
+	"gorm.io/gorm"
 )
 
 func TestSetupTestDB(t *testing.T) {
@@ -21,13 +23,59 @@ func TestSetupTestDB(t *testing.T) {
 	}
 }
 
+func TestWithTx(t *testing.T) {
+	email := "withtx@test.com"
+	countUsers := func(db *gorm.DB) int64 {
+		var count int64
+		if err := db.Model(&models.User{}).Where("email = ?", email).Count(&count).Error; err != nil {
+			t.Fatalf("failed to count users: %v", err)
+		}
+		return count
+	}
+
+	t.Run("writes", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		testutil.CreateTestUserWithEmail(t, db, email)
+		if got := countUsers(db); got != 1 {
+			t.Errorf("expected the user inside the transaction, got %d", got)
+		}
+	})
+
+	t.Run("rolled_back_after_previous_test", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		if got := countUsers(db); got != 0 {
+			t.Errorf("expected the previous test's user to be rolled back, got %d", got)
+		}
+	})
+
+	t.Run("nested_transaction_is_a_savepoint", func(t *testing.T) {
+		db := testutil.WithTx(t)
+		testutil.CreateTestUserWithEmail(t, db, email)
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			testutil.CreateTestUser(t, tx)
+			return errors.ErrInvalidInput
+		})
+		if err == nil {
+			t.Fatal("expected the nested transaction to fail")
+		}
+
+		var total int64
+		if err := db.Model(&models.User{}).Count(&total).Error; err != nil {
+			t.Fatalf("failed to count users: %v", err)
+		}
+		if total != 1 || countUsers(db) != 1 {
+			t.Errorf("expected only the outer user to remain, got %d users", total)
+		}
+	})
+}
+
 func TestFixtures(t *testing.T) {
-	db := testutil.SetupTestDB(t)
-	defer testutil.TeardownTestDB(t, db)
+	db := testutil.WithTx(t)
 
 	user := testutil.CreateTestUser(t, db)
-	if user.ID == 0 {
-		t.Fatal("user should have a non-zero ID")
+	if user.ID == "" {
+		t.Fatal("user should have a non-empty ID")
 	}
 
 	account := testutil.CreateTestCashAccountWithBalance(t, db, user.ID, 5000)
@@ -56,8 +104,8 @@ func TestFixtures(t *testing.T) {
 	}
 
 	sec := testutil.CreateTestSecurity(t, db)
-	if sec.ID == 0 {
-		t.Fatal("security should have a non-zero ID")
+	if sec.ID == "" {
+		t.Fatal("security should have a non-empty ID")
 	}
 
 	inv := testutil.CreateTestInvestment(t, db, invAccount.ID, sec.ID)
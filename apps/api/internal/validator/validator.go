@@ -10,6 +10,10 @@ import (
 
 var hexColorRegex = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
 
+// usernameRegex allows 3-30 letters, digits and underscores. Excluding "@" is
+// what lets a login field tell usernames and emails apart.
+var usernameRegex = regexp.MustCompile(`^[A-Za-z0-9_]{3,30}$`)
+
 // validCurrencies contains ISO 4217 currency codes.
 var validCurrencies = map[string]bool{
 	"AED": true, "AFN": true, "ALL": true, "AMD": true, "ANG": true,
@@ -46,27 +50,55 @@ var validCurrencies = map[string]bool{
 	"ZWL": true,
 }
 
+// validLocales contains the BCP 47 locale tags supported for number and date formatting.
+var validLocales = map[string]bool{
+	"en-US": true, "en-GB": true, "en-AU": true, "en-CA": true, "en-IN": true,
+	"en-NZ": true, "en-SG": true, "de-DE": true, "es-ES": true, "es-MX": true,
+	"fr-FR": true, "fr-CA": true, "id-ID": true, "it-IT": true, "ja-JP": true,
+	"ko-KR": true, "ms-MY": true, "nl-NL": true, "pt-BR": true, "pt-PT": true,
+	"th-TH": true, "vi-VN": true, "zh-CN": true, "zh-HK": true, "zh-TW": true,
+}
+
 // Register registers all custom validators with the Gin binding engine.
 func Register() {
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		_ = v.RegisterValidation("iso4217", validateISO4217)
 		_ = v.RegisterValidation("hex_color", validateHexColor)
 		_ = v.RegisterValidation("transaction_type", validateTransactionType)
+		_ = v.RegisterValidation("transaction_status", validateTransactionStatus)
 		_ = v.RegisterValidation("category_type", validateCategoryType)
 		_ = v.RegisterValidation("account_type", validateAccountType)
 		_ = v.RegisterValidation("budget_period", validateBudgetPeriod)
 		_ = v.RegisterValidation("asset_type", validateAssetType)
+		_ = v.RegisterValidation("price_source", validatePriceSource)
+		_ = v.RegisterValidation("rule_match_type", validateRuleMatchType)
+		_ = v.RegisterValidation("locale", validateLocale)
+		_ = v.RegisterValidation("week_start", validateWeekStart)
+		_ = v.RegisterValidation("cost_basis_method", validateCostBasisMethod)
+		_ = v.RegisterValidation("share_role", validateShareRole)
+		_ = v.RegisterValidation("snapshot_interval", validateSnapshotInterval)
+		_ = v.RegisterValidation("username", validateUsername)
 	}
 }
 
 func validateISO4217(fl validator.FieldLevel) bool {
-	return validCurrencies[fl.Field().String()]
+	return IsISO4217(fl.Field().String())
+}
+
+// IsISO4217 reports whether code is a known ISO 4217 currency code, for
+// values checked outside binding such as query filters.
+func IsISO4217(code string) bool {
+	return validCurrencies[code]
 }
 
 func validateHexColor(fl validator.FieldLevel) bool {
 	return hexColorRegex.MatchString(fl.Field().String())
 }
 
+func validateUsername(fl validator.FieldLevel) bool {
+	return usernameRegex.MatchString(fl.Field().String())
+}
+
 func validateTransactionType(fl validator.FieldLevel) bool {
 	switch fl.Field().String() {
 	case "income", "expense", "transfer", "investment":
@@ -75,6 +107,14 @@ func validateTransactionType(fl validator.FieldLevel) bool {
 	return false
 }
 
+func validateTransactionStatus(fl validator.FieldLevel) bool {
+	switch fl.Field().String() {
+	case "pending", "cleared":
+		return true
+	}
+	return false
+}
+
 func validateCategoryType(fl validator.FieldLevel) bool {
 	switch fl.Field().String() {
 	case "income", "expense":
@@ -106,3 +146,55 @@ func validateAssetType(fl validator.FieldLevel) bool {
 	}
 	return false
 }
+
+func validatePriceSource(fl validator.FieldLevel) bool {
+	switch fl.Field().String() {
+	case "yahoo", "coingecko", "bursa", "manual":
+		return true
+	}
+	return false
+}
+
+func validateRuleMatchType(fl validator.FieldLevel) bool {
+	switch fl.Field().String() {
+	case "contains", "regex":
+		return true
+	}
+	return false
+}
+
+func validateLocale(fl validator.FieldLevel) bool {
+	return validLocales[fl.Field().String()]
+}
+
+func validateWeekStart(fl validator.FieldLevel) bool {
+	switch fl.Field().String() {
+	case "monday", "sunday", "saturday":
+		return true
+	}
+	return false
+}
+
+func validateCostBasisMethod(fl validator.FieldLevel) bool {
+	switch fl.Field().String() {
+	case "average", "fifo":
+		return true
+	}
+	return false
+}
+
+func validateShareRole(fl validator.FieldLevel) bool {
+	switch fl.Field().String() {
+	case "viewer", "editor":
+		return true
+	}
+	return false
+}
+
+func validateSnapshotInterval(fl validator.FieldLevel) bool {
+	switch fl.Field().String() {
+	case "daily", "weekly", "monthly":
+		return true
+	}
+	return false
+}
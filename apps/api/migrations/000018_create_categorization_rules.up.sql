@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS categorization_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    user_id UUID NOT NULL REFERENCES users(id),
+    category_id UUID NOT NULL REFERENCES categories(id),
+    pattern VARCHAR(255) NOT NULL,
+    match_type VARCHAR(20) NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_categorization_rules_deleted_at ON categorization_rules (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_categorization_rules_user_priority ON categorization_rules (user_id, priority DESC);
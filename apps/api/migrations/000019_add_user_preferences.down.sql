@@ -0,0 +1,5 @@
+ALTER TABLE users DROP COLUMN preferences;
+ALTER TABLE users DROP COLUMN week_start;
+ALTER TABLE users DROP COLUMN locale;
+ALTER TABLE users DROP COLUMN base_currency;
+ALTER TABLE users DROP COLUMN display_name;
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN display_name VARCHAR(100) DEFAULT '';
+ALTER TABLE users ADD COLUMN base_currency VARCHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE users ADD COLUMN locale VARCHAR(10) NOT NULL DEFAULT 'en-US';
+ALTER TABLE users ADD COLUMN week_start VARCHAR(10) NOT NULL DEFAULT 'monday';
+ALTER TABLE users ADD COLUMN preferences TEXT NOT NULL DEFAULT '{}';
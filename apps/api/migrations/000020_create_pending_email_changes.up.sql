@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS pending_email_changes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    user_id UUID NOT NULL REFERENCES users(id),
+    new_email VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_pending_email_changes_deleted_at ON pending_email_changes (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_pending_email_changes_user_id ON pending_email_changes (user_id);
+CREATE INDEX IF NOT EXISTS idx_pending_email_changes_new_email ON pending_email_changes (new_email);
+CREATE INDEX IF NOT EXISTS idx_pending_email_changes_expires_at ON pending_email_changes (expires_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pending_email_changes_token_hash ON pending_email_changes (token_hash);
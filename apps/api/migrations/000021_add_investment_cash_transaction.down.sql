@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_investment_transactions_cash_transaction_id;
+ALTER TABLE investment_transactions DROP COLUMN cash_transaction_id;
@@ -0,0 +1,2 @@
+ALTER TABLE investment_transactions ADD COLUMN cash_transaction_id UUID REFERENCES transactions(id);
+CREATE INDEX IF NOT EXISTS idx_investment_transactions_cash_transaction_id ON investment_transactions (cash_transaction_id);
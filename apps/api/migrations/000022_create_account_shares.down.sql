@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS account_share_accounts;
+DROP TABLE IF EXISTS account_shares;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS account_shares (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    owner_id UUID NOT NULL REFERENCES users(id),
+    shared_with_id UUID NOT NULL REFERENCES users(id),
+    role VARCHAR(20) NOT NULL,
+    all_accounts BOOLEAN NOT NULL DEFAULT FALSE,
+    accepted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_shares_deleted_at ON account_shares (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_account_shares_owner_id ON account_shares (owner_id);
+CREATE INDEX IF NOT EXISTS idx_account_shares_shared_with_id ON account_shares (shared_with_id);
+
+CREATE TABLE IF NOT EXISTS account_share_accounts (
+    account_share_id UUID NOT NULL REFERENCES account_shares(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    PRIMARY KEY (account_share_id, account_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_share_accounts_account_id ON account_share_accounts (account_id);
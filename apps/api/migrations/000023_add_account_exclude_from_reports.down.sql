@@ -0,0 +1 @@
+ALTER TABLE accounts DROP COLUMN exclude_from_reports;
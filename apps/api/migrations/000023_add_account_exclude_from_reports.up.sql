@@ -0,0 +1 @@
+ALTER TABLE accounts ADD COLUMN exclude_from_reports BOOLEAN NOT NULL DEFAULT FALSE;
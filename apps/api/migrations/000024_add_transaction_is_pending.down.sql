@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_transactions_pending_date;
+ALTER TABLE transactions DROP COLUMN is_pending;
@@ -0,0 +1,2 @@
+ALTER TABLE transactions ADD COLUMN is_pending BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX IF NOT EXISTS idx_transactions_pending_date ON transactions (date) WHERE is_pending = TRUE;
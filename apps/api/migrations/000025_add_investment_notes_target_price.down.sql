@@ -0,0 +1,2 @@
+ALTER TABLE investments DROP COLUMN target_price;
+ALTER TABLE investments DROP COLUMN notes;
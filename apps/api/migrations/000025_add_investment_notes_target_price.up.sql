@@ -0,0 +1,2 @@
+ALTER TABLE investments ADD COLUMN notes TEXT NOT NULL DEFAULT '';
+ALTER TABLE investments ADD COLUMN target_price BIGINT;
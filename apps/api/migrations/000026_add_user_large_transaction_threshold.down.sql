@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN large_transaction_threshold;
@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN large_transaction_threshold BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS pending_bulk_deletes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    user_id UUID NOT NULL REFERENCES users(id),
+    selection TEXT NOT NULL,
+    transaction_count BIGINT NOT NULL,
+    total_amount BIGINT NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_pending_bulk_deletes_deleted_at ON pending_bulk_deletes (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_pending_bulk_deletes_user_id ON pending_bulk_deletes (user_id);
+CREATE INDEX IF NOT EXISTS idx_pending_bulk_deletes_expires_at ON pending_bulk_deletes (expires_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pending_bulk_deletes_token_hash ON pending_bulk_deletes (token_hash);
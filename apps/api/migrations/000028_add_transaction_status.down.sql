@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_transactions_uncleared;
+ALTER TABLE transactions DROP COLUMN status;
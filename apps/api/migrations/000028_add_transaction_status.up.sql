@@ -0,0 +1,2 @@
+ALTER TABLE transactions ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'cleared';
+CREATE INDEX IF NOT EXISTS idx_transactions_uncleared ON transactions (account_id) WHERE status = 'pending';
@@ -0,0 +1 @@
+ALTER TABLE accounts DROP COLUMN default_category_id;
@@ -0,0 +1 @@
+ALTER TABLE accounts ADD COLUMN default_category_id UUID REFERENCES categories(id);
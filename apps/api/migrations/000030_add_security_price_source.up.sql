@@ -0,0 +1 @@
+ALTER TABLE security_prices ADD COLUMN source VARCHAR(20) NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE accounts DROP COLUMN archived_balance;
+ALTER TABLE accounts DROP COLUMN archived_at;
@@ -0,0 +1,6 @@
+ALTER TABLE accounts ADD COLUMN archived_at TIMESTAMPTZ;
+ALTER TABLE accounts ADD COLUMN archived_balance BIGINT;
+
+-- Accounts deactivated before archival was tracked: their last update is the
+-- best available archival time, and their balance has not moved since.
+UPDATE accounts SET archived_at = updated_at, archived_balance = balance WHERE is_active = false;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS uq_investment_transactions_external_ref;
+ALTER TABLE investment_transactions DROP COLUMN external_ref;
@@ -0,0 +1,4 @@
+ALTER TABLE investment_transactions ADD COLUMN external_ref VARCHAR(100);
+
+-- NULL references (manual entries) never conflict.
+CREATE UNIQUE INDEX uq_investment_transactions_external_ref ON investment_transactions(investment_id, external_ref);
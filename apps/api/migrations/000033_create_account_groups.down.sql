@@ -0,0 +1,2 @@
+ALTER TABLE accounts DROP COLUMN group_id;
+DROP TABLE IF EXISTS account_groups;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS account_groups (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    user_id UUID NOT NULL REFERENCES users(id),
+    name VARCHAR(100) NOT NULL,
+    sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_groups_deleted_at ON account_groups (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_account_groups_user_order ON account_groups (user_id, sort_order);
+
+ALTER TABLE accounts ADD COLUMN group_id UUID REFERENCES account_groups(id);
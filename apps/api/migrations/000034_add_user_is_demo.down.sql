@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_users_is_demo;
+ALTER TABLE users DROP COLUMN is_demo;
@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN is_demo BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Demo users are few; the purge job only ever looks them up.
+CREATE INDEX IF NOT EXISTS idx_users_is_demo ON users (is_demo) WHERE is_demo;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS pipeline_api_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_pipeline_api_keys_deleted_at ON pipeline_api_keys (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pipeline_api_keys_key_hash ON pipeline_api_keys (key_hash);
+CREATE INDEX IF NOT EXISTS idx_pipeline_api_keys_revoked_at ON pipeline_api_keys (revoked_at);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_users_email_lower;
+
+-- Give conflicted users their original address back where it is still free.
+-- Lowercasing the remaining emails is not reversed.
+UPDATE users u
+SET email = u.conflicted_email
+WHERE u.conflicted_email IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM users o WHERE o.email = u.conflicted_email);
+
+ALTER TABLE users DROP COLUMN IF EXISTS conflicted_email;
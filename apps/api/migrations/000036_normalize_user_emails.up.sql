@@ -0,0 +1,30 @@
+-- Store every email trimmed and lowercased, and enforce uniqueness regardless
+-- of case so "Foo@Bar.com" and "foo@bar.com" cannot both register.
+--
+-- Existing duplicates are resolved first. Users are grouped by
+-- LOWER(TRIM(email)); the oldest user in each group (by created_at, then id)
+-- keeps the address. Every newer user in the group is marked conflicted: its
+-- original address moves to conflicted_email and email becomes
+-- "<id>@conflicted.invalid", which is unique and cannot be logged into, so
+-- nothing is deleted and support can reconcile the accounts by hand.
+--
+-- Each step is idempotent. Users already marked conflicted are left out of
+-- the grouping, so a re-run finds no duplicates to resolve, the lowercasing
+-- only touches rows that still need it, and the index is created IF NOT EXISTS.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS conflicted_email VARCHAR(255);
+
+WITH ranked AS (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY LOWER(TRIM(email)) ORDER BY created_at, id) AS rn
+    FROM users
+    WHERE conflicted_email IS NULL
+)
+UPDATE users
+SET conflicted_email = users.email,
+    email = users.id::text || '@conflicted.invalid',
+    updated_at = NOW()
+FROM ranked
+WHERE users.id = ranked.id AND ranked.rn > 1;
+
+UPDATE users SET email = LOWER(TRIM(email)) WHERE email <> LOWER(TRIM(email));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower ON users (LOWER(email));
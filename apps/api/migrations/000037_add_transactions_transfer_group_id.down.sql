@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_transactions_transfer_group_id;
+ALTER TABLE transactions DROP COLUMN transfer_group_id;
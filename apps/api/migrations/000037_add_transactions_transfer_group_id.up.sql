@@ -0,0 +1,4 @@
+ALTER TABLE transactions ADD COLUMN transfer_group_id UUID;
+
+-- Only split-transfer legs carry a group; deleting one leg looks up the rest.
+CREATE INDEX IF NOT EXISTS idx_transactions_transfer_group_id ON transactions (transfer_group_id) WHERE transfer_group_id IS NOT NULL;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    from_currency VARCHAR(3) NOT NULL,
+    to_currency VARCHAR(3) NOT NULL,
+    rate DOUBLE PRECISION NOT NULL CHECK (rate > 0),
+    recorded_at TIMESTAMPTZ NOT NULL,
+
+    CONSTRAINT uq_exchange_rates_pair_recorded UNIQUE (from_currency, to_currency, recorded_at)
+);
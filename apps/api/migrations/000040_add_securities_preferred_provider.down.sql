@@ -0,0 +1 @@
+ALTER TABLE securities DROP COLUMN preferred_provider;
@@ -0,0 +1 @@
+ALTER TABLE securities ADD COLUMN preferred_provider VARCHAR(50) DEFAULT '';
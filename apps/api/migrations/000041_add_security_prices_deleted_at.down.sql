@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_security_prices_deleted_at;
+ALTER TABLE security_prices DROP COLUMN deleted_at;
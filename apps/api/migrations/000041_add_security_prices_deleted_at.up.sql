@@ -0,0 +1,2 @@
+ALTER TABLE security_prices ADD COLUMN deleted_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_security_prices_deleted_at ON security_prices (deleted_at);
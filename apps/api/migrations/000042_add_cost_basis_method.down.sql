@@ -0,0 +1,2 @@
+ALTER TABLE investment_transactions DROP COLUMN cost_basis_method;
+ALTER TABLE users DROP COLUMN cost_basis_method;
@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN cost_basis_method VARCHAR(10) NOT NULL DEFAULT 'average';
+ALTER TABLE investment_transactions ADD COLUMN cost_basis_method VARCHAR(10) NOT NULL DEFAULT '';
+UPDATE investment_transactions SET cost_basis_method = 'average' WHERE type = 'sell';
@@ -0,0 +1 @@
+ALTER TABLE investment_transactions DROP COLUMN split_remainder;
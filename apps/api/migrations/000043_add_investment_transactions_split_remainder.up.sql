@@ -0,0 +1 @@
+ALTER TABLE investment_transactions ADD COLUMN split_remainder DOUBLE PRECISION NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_username_lower;
+ALTER TABLE users DROP COLUMN IF EXISTS username_changed_at;
+ALTER TABLE users DROP COLUMN IF EXISTS username;
@@ -0,0 +1,7 @@
+-- Optional login name. Usernames are unique regardless of case, so the index
+-- is on LOWER(username); NULLs do not collide, so users without one are fine.
+-- username_changed_at drives the 30-day cooldown between changes.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS username VARCHAR(30);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS username_changed_at TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username_lower ON users (LOWER(username));
@@ -0,0 +1,4 @@
+-- What a foreign-currency transaction cost in the currency it was made in, for
+-- reference only; amount in the account's currency still drives balances.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS original_amount BIGINT;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS original_currency VARCHAR(3);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS restore_id_mappings;
+DROP TABLE IF EXISTS restore_progresses;
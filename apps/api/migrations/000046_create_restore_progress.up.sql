@@ -0,0 +1,31 @@
+-- Bookkeeping for resumable backup restores: which entity groups of an
+-- archive are done, and what each restored record's archive ID became.
+CREATE TABLE IF NOT EXISTS restore_progresses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    user_id UUID NOT NULL REFERENCES users(id),
+    archive_id VARCHAR(64) NOT NULL,
+    group_name VARCHAR(40) NOT NULL,
+    restored INTEGER NOT NULL,
+    completed_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_restore_progresses_deleted_at ON restore_progresses (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_restore_progress_group ON restore_progresses (user_id, archive_id, group_name);
+
+CREATE TABLE IF NOT EXISTS restore_id_mappings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    user_id UUID NOT NULL REFERENCES users(id),
+    archive_id VARCHAR(64) NOT NULL,
+    entity VARCHAR(40) NOT NULL,
+    old_id UUID NOT NULL,
+    new_id UUID NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_restore_id_mappings_deleted_at ON restore_id_mappings (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_restore_id_mappings_archive ON restore_id_mappings (user_id, archive_id);
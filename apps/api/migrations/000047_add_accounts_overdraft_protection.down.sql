@@ -0,0 +1,2 @@
+ALTER TABLE accounts DROP COLUMN IF EXISTS min_balance;
+ALTER TABLE accounts DROP COLUMN IF EXISTS allow_negative;
@@ -0,0 +1,4 @@
+-- Overdraft protection for cash accounts: with allow_negative off, expenses and
+-- outgoing transfers may not take the balance below min_balance.
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS allow_negative BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS min_balance BIGINT NOT NULL DEFAULT 0;
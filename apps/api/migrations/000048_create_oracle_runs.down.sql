@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS oracle_run_errors;
+DROP TABLE IF EXISTS oracle_runs;
@@ -0,0 +1,34 @@
+-- Results the price oracle reports at the end of every run, failed runs included.
+CREATE TABLE IF NOT EXISTS oracle_runs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    version VARCHAR(64) NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    started_at TIMESTAMPTZ NOT NULL,
+    duration_ms BIGINT NOT NULL,
+    securities_fetched INTEGER NOT NULL,
+    prices_recorded INTEGER NOT NULL,
+    snapshots_recorded INTEGER NOT NULL,
+    error_count INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_oracle_runs_deleted_at ON oracle_runs (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_oracle_runs_started_at ON oracle_runs (started_at);
+
+CREATE TABLE IF NOT EXISTS oracle_run_errors (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    run_id UUID NOT NULL REFERENCES oracle_runs(id) ON DELETE CASCADE,
+    security_id VARCHAR(64) NOT NULL DEFAULT '',
+    symbol VARCHAR(255) NOT NULL DEFAULT '',
+    error TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_oracle_run_errors_deleted_at ON oracle_run_errors (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_oracle_run_errors_run_id ON oracle_run_errors (run_id);
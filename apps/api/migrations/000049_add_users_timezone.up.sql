@@ -0,0 +1,2 @@
+-- IANA time zone daily and monthly spending reports are bucketed in.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
@@ -0,0 +1,3 @@
+-- Backdated opening date given at creation; reports leave the account out
+-- before it. NULL for accounts that were not backdated.
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS opening_date TIMESTAMPTZ;
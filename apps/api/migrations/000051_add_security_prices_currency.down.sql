@@ -0,0 +1 @@
+ALTER TABLE security_prices DROP COLUMN IF EXISTS currency;
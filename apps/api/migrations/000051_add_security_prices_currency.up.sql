@@ -0,0 +1,3 @@
+-- Currency the price was recorded in, which the oracle may have converted it
+-- to; empty for prices recorded in the security's own currency.
+ALTER TABLE security_prices ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+UPDATE users SET timezone = 'UTC' WHERE timezone = '';
+ALTER TABLE users ALTER COLUMN timezone SET DEFAULT 'UTC';
@@ -0,0 +1,4 @@
+-- An empty timezone means the user never chose one, so reports and date-only
+-- filters fall back to the server's reporting zone.
+ALTER TABLE users ALTER COLUMN timezone SET DEFAULT '';
+UPDATE users SET timezone = '' WHERE timezone = 'UTC';
@@ -0,0 +1,4 @@
+UPDATE transactions SET type = 'transfer'
+WHERE type = 'investment'
+  AND to_account_id IS NOT NULL
+  AND id IN (SELECT cash_transaction_id FROM investment_transactions WHERE cash_transaction_id IS NOT NULL);
@@ -0,0 +1,7 @@
+-- The cash leg of an investment buy only debits the paying account, so it is
+-- stored as an investment transaction rather than a transfer that would be
+-- read as crediting the investment account.
+UPDATE transactions SET type = 'investment'
+WHERE type = 'transfer'
+  AND to_account_id IS NOT NULL
+  AND id IN (SELECT cash_transaction_id FROM investment_transactions WHERE cash_transaction_id IS NOT NULL);
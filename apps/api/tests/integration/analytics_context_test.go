@@ -0,0 +1,119 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/gorm"
+
+	"kuberan/internal/database"
+	"kuberan/internal/handlers"
+	"kuberan/internal/logger"
+	"kuberan/internal/middleware"
+	"kuberan/internal/services"
+)
+
+const spendingByCategoryRoute = "/api/v1/transactions/spending-by-category"
+
+// slowTransactionQueries makes every query on the transactions table take
+// delay once enabled, unless its context ends first. It returns the flag that
+// enables the delay and one that records whether a query was cancelled.
+func slowTransactionQueries(t *testing.T, db *gorm.DB, delay time.Duration) (enabled, cancelled *atomic.Bool) {
+	t.Helper()
+	enabled, cancelled = &atomic.Bool{}, &atomic.Bool{}
+	wait := func(tx *gorm.DB) {
+		if !enabled.Load() || tx.Statement.Table != "transactions" {
+			return
+		}
+		select {
+		case <-time.After(delay):
+		case <-tx.Statement.Context.Done():
+			cancelled.Store(errors.Is(tx.Statement.Context.Err(), context.DeadlineExceeded))
+			_ = tx.AddError(tx.Statement.Context.Err())
+		}
+	}
+	if err := db.Callback().Query().Before("gorm:query").Register("test:slow_query", wait); err != nil {
+		t.Fatalf("failed to register callback: %v", err)
+	}
+	if err := db.Callback().Row().Before("gorm:row").Register("test:slow_row", wait); err != nil {
+		t.Fatalf("failed to register callback: %v", err)
+	}
+	return enabled, cancelled
+}
+
+// analyticsRouter serves spending by category from db behind the same
+// middleware as the API: request logging, auth and an analytics timeout.
+func analyticsRouter(db *gorm.DB, timeout time.Duration) *gin.Engine {
+	accountService := services.NewAccountService(db, nil)
+	transactionHandler := handlers.NewTransactionHandler(
+		services.NewTransactionService(db, accountService, nil), accountService, services.NewAuditService(db))
+
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(middleware.RequestLogging())
+	router.Use(middleware.ErrorHandler())
+	router.GET(spendingByCategoryRoute, middleware.AuthMiddleware(), middleware.Timeout(timeout), transactionHandler.GetSpendingByCategory)
+	return router
+}
+
+func TestAnalyticsQueriesRunWithRequestContext(t *testing.T) {
+	app := setupApp(t)
+	token, _, userID := app.registerUser(t, "analytics@test.com", "password123")
+	enabled, cancelled := slowTransactionQueries(t, app.DB, 200*time.Millisecond)
+	enabled.Store(true)
+
+	get := func(router *gin.Engine) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", spendingByCategoryRoute+"?from_date=2025-01-01&to_date=2025-01-31", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("slow query is logged with route and user", func(t *testing.T) {
+		core, logs := observer.New(zapcore.WarnLevel)
+		restore := logger.Replace(zap.New(core))
+		slowLogger := database.NewSlowQueryLogger(100 * time.Millisecond)
+		restore()
+		db := app.DB.Session(&gorm.Session{Logger: slowLogger})
+
+		rec := get(analyticsRouter(db, 5*time.Second))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		entries := logs.FilterMessage("slow query").All()
+		if len(entries) == 0 {
+			t.Fatal("expected the slow query to be logged")
+		}
+		fields := entries[0].ContextMap()
+		if fields["route"] != spendingByCategoryRoute {
+			t.Errorf("expected route %s, got %v", spendingByCategoryRoute, fields["route"])
+		}
+		if fields["user_id"] != userID {
+			t.Errorf("expected user_id %s, got %v", userID, fields["user_id"])
+		}
+	})
+
+	t.Run("query is cancelled at the deadline", func(t *testing.T) {
+		start := time.Now()
+		rec := get(analyticsRouter(app.DB, 20*time.Millisecond))
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("expected 503, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if !cancelled.Load() {
+			t.Error("expected the query to be cancelled by the request deadline")
+		}
+		if elapsed := time.Since(start); elapsed >= 200*time.Millisecond {
+			t.Errorf("expected the request to end at the deadline, took %v", elapsed)
+		}
+	})
+}
@@ -14,7 +14,7 @@ func TestAuthFlow_RegisterLoginProfileRefresh(t *testing.T) {
 	if accessToken == "" || refreshToken == "" {
 		t.Fatal("expected non-empty tokens from registration")
 	}
-	if userID == 0 {
+	if userID == "" {
 		t.Fatal("expected non-zero user ID")
 	}
 
@@ -77,6 +77,30 @@ func TestAuthFlow_RegisterDuplicateEmail(t *testing.T) {
 	}
 }
 
+func TestAuthFlow_MixedCaseEmail(t *testing.T) {
+	app := setupApp(t)
+
+	_, _, userID := app.registerUser(t, "Mixed@Test.com", "password123")
+
+	// A differently-cased registration is a duplicate
+	rec := app.request("POST", "/api/v1/auth/register",
+		`{"email":"mixed@TEST.com","password":"password123"}`, "")
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for differently-cased duplicate, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// Login works with any casing and resolves to the same user
+	access, _ := app.loginUser(t, "MIXED@test.COM", "password123")
+	rec = app.request("GET", "/api/v1/profile", "", access)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	user := parseJSON(t, rec)["user"].(map[string]interface{})
+	if user["id"] != userID || user["email"] != "mixed@test.com" {
+		t.Errorf("expected user %s with email mixed@test.com, got %v %v", userID, user["id"], user["email"])
+	}
+}
+
 func TestAuthFlow_LoginWrongPassword(t *testing.T) {
 	app := setupApp(t)
 
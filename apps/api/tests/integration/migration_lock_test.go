@@ -0,0 +1,145 @@
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"kuberan/internal/database"
+)
+
+// postgresTestConfig returns a database config for a throwaway Postgres database,
+// skipping the test when TEST_DB_HOST is not set. Migration locking relies on
+// Postgres advisory locks and cannot be exercised against SQLite.
+func postgresTestConfig(t *testing.T) *database.Config {
+	t.Helper()
+
+	host := os.Getenv("TEST_DB_HOST")
+	if host == "" {
+		t.Skip("TEST_DB_HOST not set; skipping Postgres migration test")
+	}
+
+	getenv := func(key, fallback string) string {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+		return fallback
+	}
+
+	return &database.Config{
+		Host:          host,
+		Port:          getenv("TEST_DB_PORT", "5432"),
+		User:          getenv("TEST_DB_USER", "kuberan"),
+		Password:      getenv("TEST_DB_PASSWORD", "kuberan"),
+		DBName:        getenv("TEST_DB_NAME", "kuberan_test"),
+		SSLMode:       getenv("TEST_DB_SSLMODE", "disable"),
+		MigrationsDir: "../../migrations",
+	}
+}
+
+// latestMigrationVersion returns the highest version in the migrations directory.
+func latestMigrationVersion(t *testing.T, dir string) uint {
+	t.Helper()
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+	if err != nil || len(matches) == 0 {
+		t.Fatalf("failed to list migrations: %v", err)
+	}
+	var latest uint
+	for _, path := range matches {
+		var v uint
+		if _, err := fmt.Sscanf(filepath.Base(path), "%06d_", &v); err == nil && v > latest {
+			latest = v
+		}
+	}
+	return latest
+}
+
+func TestMigrations_ConcurrentRunsLeaveCleanSchema(t *testing.T) {
+	cfg := postgresTestConfig(t)
+
+	const replicas = 2
+	managers := make([]*database.Manager, replicas)
+	for i := range managers {
+		m, err := database.NewManager(cfg)
+		if err != nil {
+			t.Fatalf("failed to create manager: %v", err)
+		}
+		managers[i] = m
+	}
+
+	start := make(chan struct{})
+	errs := make([]error, replicas)
+	var wg sync.WaitGroup
+	for i, m := range managers {
+		wg.Add(1)
+		go func(i int, m *database.Manager) {
+			defer wg.Done()
+			<-start
+			errs[i] = m.RunMigrations()
+		}(i, m)
+	}
+	close(start)
+	wg.Wait()
+
+	succeeded := 0
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, database.ErrMigrationLocked):
+			// Expected for the replica that lost the race
+		default:
+			t.Errorf("replica %d: unexpected migration error: %v", i, err)
+		}
+	}
+	if succeeded == 0 {
+		t.Fatal("expected at least one replica to migrate")
+	}
+
+	version, dirty, err := managers[0].MigrationVersion()
+	if err != nil {
+		t.Fatalf("failed to read version: %v", err)
+	}
+	if dirty {
+		t.Errorf("expected clean schema, got dirty at version %d", version)
+	}
+	if want := latestMigrationVersion(t, cfg.MigrationsDir); version != want {
+		t.Errorf("expected version %d, got %d", want, version)
+	}
+}
+
+func TestMigrations_SkipWhenLockHeld(t *testing.T) {
+	cfg := postgresTestConfig(t)
+
+	holder, err := database.NewManager(cfg)
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	sqlDB, err := holder.DB().DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	conn, err := sqlDB.Conn(t.Context())
+	if err != nil {
+		t.Fatalf("failed to get connection: %v", err)
+	}
+	defer conn.Close()
+
+	var acquired bool
+	if err := conn.QueryRowContext(t.Context(), "SELECT pg_try_advisory_lock($1)", database.MigrationLockKey).Scan(&acquired); err != nil || !acquired {
+		t.Fatalf("failed to take lock: acquired=%v err=%v", acquired, err)
+	}
+	defer conn.ExecContext(t.Context(), "SELECT pg_advisory_unlock($1)", database.MigrationLockKey)
+
+	other, err := database.NewManager(cfg)
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	if err := other.RunMigrations(); !errors.Is(err, database.ErrMigrationLocked) {
+		t.Errorf("expected ErrMigrationLocked, got %v", err)
+	}
+}
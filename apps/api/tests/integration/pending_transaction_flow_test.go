@@ -0,0 +1,73 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPendingTransactionFlow_SettleViaPipeline(t *testing.T) {
+	app := setupApp(t)
+	token, _, _ := app.registerUser(t, "pending@test.com", "password123")
+
+	rec := app.request("POST", "/api/v1/accounts/cash",
+		`{"name":"Checking","initial_balance":200000}`, token)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201 creating account, got %d: %s", rec.Code, rec.Body.String())
+	}
+	accountID := parseJSON(t, rec)["account"].(map[string]interface{})["id"].(string)
+
+	balance := func() float64 {
+		t.Helper()
+		rec := app.request("GET", fmt.Sprintf("/api/v1/accounts/%s", accountID), "", token)
+		return parseJSON(t, rec)["account"].(map[string]interface{})["balance"].(float64)
+	}
+
+	// Post-dated rent stays pending and does not touch the balance
+	rent := time.Now().UTC().AddDate(0, 1, 0).Format(time.RFC3339)
+	rec = app.request("POST", "/api/v1/transactions",
+		fmt.Sprintf(`{"account_id":%q,"type":"expense","amount":150000,"description":"Rent","date":%q}`, accountID, rent), token)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201 creating post-dated transaction, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !parseJSON(t, rec)["transaction"].(map[string]interface{})["is_pending"].(bool) {
+		t.Error("expected post-dated transaction to be pending")
+	}
+
+	// A flagged pending expense dated today is due for settlement
+	rec = app.request("POST", "/api/v1/transactions",
+		fmt.Sprintf(`{"account_id":%q,"type":"expense","amount":2500,"description":"Card hold","is_pending":true}`, accountID), token)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201 creating pending transaction, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := balance(); got != 200000 {
+		t.Errorf("expected balance 200000 with only pending transactions, got %.0f", got)
+	}
+
+	rec = app.request("GET", "/api/v1/transactions?pending=true", "", token)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 listing pending, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if n := len(parseJSON(t, rec)["data"].([]interface{})); n != 2 {
+		t.Errorf("expected 2 pending transactions, got %d", n)
+	}
+
+	// Settling applies only the due transaction
+	rec = app.pipelineRequest("POST", "/api/v1/pipeline/transactions/settle", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 settling, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if settled := parseJSON(t, rec)["settled"].(float64); settled != 1 {
+		t.Errorf("expected 1 settled, got %.0f", settled)
+	}
+	if got := balance(); got != 197500 {
+		t.Errorf("expected balance 197500 after settle, got %.0f", got)
+	}
+
+	rec = app.request("GET", "/api/v1/transactions?pending=true", "", token)
+	data := parseJSON(t, rec)["data"].([]interface{})
+	if len(data) != 1 || data[0].(map[string]interface{})["description"] != "Rent" {
+		t.Errorf("expected only the rent to remain pending, got %v", data)
+	}
+}
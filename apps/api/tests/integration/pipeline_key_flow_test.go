@@ -0,0 +1,64 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestPipelineKeyFlow_RotateKey(t *testing.T) {
+	app := setupApp(t)
+
+	// Step 1: Create a stored key via the admin endpoint
+	rec := app.adminRequest("POST", "/api/v1/admin/pipeline-keys", `{"name":"oracle-2026"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201 creating key, got %d: %s", rec.Code, rec.Body.String())
+	}
+	result := parseJSON(t, rec)
+	secret := result["secret"].(string)
+	keyID := result["key"].(map[string]interface{})["id"].(string)
+
+	// Step 2: The stored key authenticates pipeline requests
+	rec = app.keyRequest("GET", "/api/v1/pipeline/securities", "", "X-API-Key", secret)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 with stored key, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// Step 3: A pipeline key cannot manage keys
+	rec = app.keyRequest("GET", "/api/v1/admin/pipeline-keys", "", "X-Admin-Key", secret)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 using pipeline key as admin key, got %d", rec.Code)
+	}
+
+	// Step 4: Revoke the key; it no longer authenticates
+	rec = app.adminRequest("DELETE", "/api/v1/admin/pipeline-keys/"+keyID, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 revoking key, got %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = app.keyRequest("GET", "/api/v1/pipeline/securities", "", "X-API-Key", secret)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 with revoked key, got %d", rec.Code)
+	}
+
+	// Step 5: The configured bootstrap key keeps working
+	rec = app.pipelineRequest("GET", "/api/v1/pipeline/securities", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 with config key, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// Step 6: The listing shows the revoked key without its hash
+	rec = app.adminRequest("GET", "/api/v1/admin/pipeline-keys", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 listing keys, got %d: %s", rec.Code, rec.Body.String())
+	}
+	keys := parseJSON(t, rec)["keys"].([]interface{})
+	if len(keys) != 1 {
+		t.Fatalf("expected 1 key, got %d", len(keys))
+	}
+	key := keys[0].(map[string]interface{})
+	if key["revoked_at"] == nil {
+		t.Error("expected revoked_at to be set")
+	}
+	if _, ok := key["key_hash"]; ok {
+		t.Error("expected key hash to be omitted")
+	}
+}
@@ -24,12 +24,12 @@ func TestPortfolioSnapshotFlow_ComputeAndQuery(t *testing.T) {
 	if rec.Code != http.StatusCreated {
 		t.Fatalf("expected 201 creating investment account, got %d: %s", rec.Code, rec.Body.String())
 	}
-	accountID := parseJSON(t, rec)["account"].(map[string]interface{})["id"].(float64)
+	accountID := parseJSON(t, rec)["account"].(map[string]interface{})["id"].(string)
 
 	// Step 3: Create security and add investment (10 shares @ $150 = $1500)
 	securityID := app.createSecurity(t, "AAPL", "Apple Inc.", "stock")
 	rec = app.request("POST", "/api/v1/investments",
-		fmt.Sprintf(`{"account_id":%.0f,"security_id":%.0f,"quantity":10,"purchase_price":15000}`,
+		fmt.Sprintf(`{"account_id":%q,"security_id":%q,"quantity":10,"purchase_price":15000}`,
 			accountID, securityID), token)
 	if rec.Code != http.StatusCreated {
 		t.Fatalf("expected 201 adding investment, got %d: %s", rec.Code, rec.Body.String())
@@ -38,7 +38,7 @@ func TestPortfolioSnapshotFlow_ComputeAndQuery(t *testing.T) {
 	// Step 4: Record live price via pipeline ($150/share)
 	priceTime := time.Now().UTC().Format(time.RFC3339)
 	rec = app.pipelineRequest("POST", "/api/v1/pipeline/securities/prices",
-		fmt.Sprintf(`{"prices":[{"security_id":%.0f,"price":15000,"recorded_at":%q}]}`, securityID, priceTime))
+		fmt.Sprintf(`{"prices":[{"security_id":%q,"price":15000,"recorded_at":%q}]}`, securityID, priceTime))
 	if rec.Code != http.StatusOK {
 		t.Fatalf("expected 200 for pipeline price, got %d: %s", rec.Code, rec.Body.String())
 	}
@@ -171,3 +171,65 @@ func TestPortfolioSnapshotFlow_MultipleUsersComputed(t *testing.T) {
 		t.Errorf("expected user2 total_net_worth 700000, got %.0f", s2["total_net_worth"].(float64))
 	}
 }
+
+func TestPortfolioSnapshotFlow_Backfill(t *testing.T) {
+	app := setupApp(t)
+	token, _, _ := app.registerUser(t, "backfill@test.com", "password123")
+
+	rec := app.request("POST", "/api/v1/accounts/investment", `{"name":"Brokerage"}`, token)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201 creating investment account, got %d: %s", rec.Code, rec.Body.String())
+	}
+	accountID := parseJSON(t, rec)["account"].(map[string]interface{})["id"].(string)
+
+	// 10 shares bought on Jan 5, priced at $100 on Jan 1 and $110 on Jan 11
+	securityID := app.createSecurity(t, "VTI", "Vanguard Total Stock Market", "etf")
+	rec = app.request("POST", "/api/v1/investments",
+		fmt.Sprintf(`{"account_id":%q,"security_id":%q,"quantity":10,"purchase_price":10000,"date":"2025-01-05T00:00:00Z"}`,
+			accountID, securityID), token)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201 adding investment, got %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = app.pipelineRequest("POST", "/api/v1/pipeline/securities/prices",
+		fmt.Sprintf(`{"prices":[{"security_id":%q,"price":10000,"recorded_at":"2025-01-01T00:00:00Z"},{"security_id":%q,"price":11000,"recorded_at":"2025-01-11T00:00:00Z"}]}`,
+			securityID, securityID))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for pipeline prices, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	body := `{"from":"2025-01-01T00:00:00Z","to":"2025-01-15T00:00:00Z","interval":"weekly"}`
+	rec = app.pipelineRequest("POST", "/api/v1/pipeline/snapshots/backfill", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 backfilling snapshots, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if parseJSON(t, rec)["snapshots_recorded"].(float64) != 3 {
+		t.Errorf("expected 3 snapshots, got %.0f", parseJSON(t, rec)["snapshots_recorded"].(float64))
+	}
+
+	// Rerunning the same range records nothing new
+	rec = app.pipelineRequest("POST", "/api/v1/pipeline/snapshots/backfill", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 rerunning backfill, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if parseJSON(t, rec)["snapshots_recorded"].(float64) != 0 {
+		t.Errorf("expected 0 snapshots on rerun, got %.0f", parseJSON(t, rec)["snapshots_recorded"].(float64))
+	}
+
+	rec = app.request("GET", "/api/v1/investments/snapshots?from_date=2025-01-01&to_date=2025-01-31", "", token)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 getting snapshots, got %d: %s", rec.Code, rec.Body.String())
+	}
+	data := parseJSON(t, rec)["data"].([]interface{})
+	if len(data) != 3 {
+		t.Fatalf("expected 3 snapshots, got %d", len(data))
+	}
+
+	// Newest first: Jan 15 ($110), Jan 8 ($100), Jan 1 (not yet bought)
+	want := []float64{110000, 100000, 0}
+	for i, item := range data {
+		value := item.(map[string]interface{})["investment_value"].(float64)
+		if value != want[i] {
+			t.Errorf("snapshot %d: expected investment_value %.0f, got %.0f", i, want[i], value)
+		}
+	}
+}
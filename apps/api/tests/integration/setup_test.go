@@ -8,6 +8,7 @@ import (
 	"strings"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/driver/sqlite"
@@ -18,6 +19,7 @@ import (
 	"kuberan/internal/middleware"
 	"kuberan/internal/models"
 	"kuberan/internal/services"
+	"kuberan/internal/testutil"
 	"kuberan/internal/validator"
 )
 
@@ -32,7 +34,7 @@ var dbCounter atomic.Int64
 
 func init() {
 	gin.SetMode(gin.TestMode)
-	logger.Init("test")
+	logger.Init(logger.Options{Env: "test"})
 	validator.Register()
 }
 
@@ -49,16 +51,27 @@ func setupIsolatedDB(t *testing.T) *gorm.DB {
 
 	allModels := []interface{}{
 		&models.User{},
+		&models.PendingEmailChange{},
+		&models.PendingBulkDelete{},
+		&models.AccountGroup{},
 		&models.Account{},
+		&models.AccountShare{},
+		&models.AccountShareAccount{},
 		&models.Category{},
 		&models.Transaction{},
 		&models.Budget{},
+		&models.CategorizationRule{},
 		&models.Security{},
 		&models.SecurityPrice{},
 		&models.PortfolioSnapshot{},
 		&models.Investment{},
 		&models.InvestmentTransaction{},
 		&models.AuditLog{},
+		&models.PipelineAPIKey{},
+		&models.OracleRun{},
+		&models.OracleRunError{},
+		&models.OutboxEvent{},
+		&models.ExchangeRate{},
 	}
 	if err := db.AutoMigrate(allModels...); err != nil {
 		t.Fatalf("failed to migrate test database: %v", err)
@@ -74,25 +87,40 @@ func setupApp(t *testing.T) *testApp {
 	db := setupIsolatedDB(t)
 
 	// Services
-	userService := services.NewUserService(db)
-	accountService := services.NewAccountService(db)
+	userService := services.NewUserServiceWithOptions(db, services.UserServiceOptions{BcryptCost: testutil.BcryptCost})
+	priceCache := services.NewPriceCache(time.Minute)
+	accountService := services.NewAccountService(db, priceCache)
+	shareService := services.NewShareService(db)
 	categoryService := services.NewCategoryService(db)
-	transactionService := services.NewTransactionService(db, accountService)
+	transactionService := services.NewTransactionService(db, accountService, nil)
 	budgetService := services.NewBudgetService(db)
-	investmentService := services.NewInvestmentService(db, accountService)
-	securityService := services.NewSecurityService(db)
+	investmentService := services.NewInvestmentService(db, accountService, priceCache, nil, services.TradePriceCheck{})
+	securityService := services.NewSecurityService(db, priceCache, 0)
 	snapshotService := services.NewPortfolioSnapshotService(db)
+	statementService := services.NewStatementService(db)
+	statsService := services.NewStatsService(db)
+	exportService := services.NewExportService(db)
+	backupService := services.NewBackupService(db)
+	pipelineKeyService := services.NewPipelineKeyService(db)
 	auditService := services.NewAuditService(db)
 
 	// Handlers
 	authHandler := handlers.NewAuthHandler(userService, auditService)
 	accountHandler := handlers.NewAccountHandler(accountService, auditService)
+	shareHandler := handlers.NewShareHandler(shareService, auditService)
 	categoryHandler := handlers.NewCategoryHandler(categoryService, auditService)
-	transactionHandler := handlers.NewTransactionHandler(transactionService, auditService)
+	transactionHandler := handlers.NewTransactionHandler(transactionService, accountService, auditService)
 	budgetHandler := handlers.NewBudgetHandler(budgetService, auditService)
-	investmentHandler := handlers.NewInvestmentHandler(investmentService, auditService)
+	investmentHandler := handlers.NewInvestmentHandler(investmentService, securityService, auditService)
 	securityHandler := handlers.NewSecurityHandler(securityService, auditService)
 	snapshotHandler := handlers.NewPortfolioSnapshotHandler(snapshotService, auditService)
+	statementHandler := handlers.NewStatementHandler(statementService)
+	statsHandler := handlers.NewStatsHandler(statsService)
+	exportHandler := handlers.NewExportHandler(exportService, auditService)
+	backupHandler := handlers.NewBackupHandler(backupService, auditService)
+	pipelineKeyHandler := handlers.NewPipelineKeyHandler(pipelineKeyService)
+	oracleRunHandler := handlers.NewOracleRunHandler(services.NewOracleRunService(db))
+	metaHandler := handlers.NewMetaHandler()
 
 	// Router
 	router := gin.New()
@@ -107,45 +135,72 @@ func setupApp(t *testing.T) *testApp {
 	auth.POST("/login", authHandler.Login)
 	auth.POST("/refresh", authHandler.RefreshToken)
 
+	v1.GET("/meta/errors", metaHandler.ListErrors)
+
+	analyticsTimeout := middleware.Timeout(30 * time.Second)
+
 	// Protected routes
 	protected := v1.Group("/")
 	protected.Use(middleware.AuthMiddleware())
 
 	protected.GET("/profile", authHandler.GetProfile)
+	protected.PUT("/profile", authHandler.UpdateProfile)
+	protected.GET("/profile/export", exportHandler.ExportUserData)
+	protected.GET("/profile/backup", backupHandler.CreateBackup)
+	protected.POST("/profile/restore", backupHandler.RestoreBackup)
 
 	accounts := protected.Group("/accounts")
 	accounts.POST("/cash", accountHandler.CreateCashAccount)
 	accounts.POST("/investment", accountHandler.CreateInvestmentAccount)
 	accounts.GET("", accountHandler.GetUserAccounts)
+	accounts.GET("/archived", accountHandler.GetArchivedAccounts)
 	accounts.GET("/:id", accountHandler.GetAccountByID)
 	accounts.PUT("/:id", accountHandler.UpdateAccount)
+	accounts.DELETE("/:id", accountHandler.DeleteAccount)
 	accounts.GET("/:id/transactions", transactionHandler.GetAccountTransactions)
 	accounts.GET("/:id/investments", investmentHandler.GetAccountInvestments)
+	accounts.GET("/:id/statement", accountHandler.GetAccountStatement)
+	accounts.GET("/:id/timeline", accountHandler.GetAccountTimeline)
 
 	transactions := protected.Group("/transactions")
 	transactions.POST("", transactionHandler.CreateTransaction)
+	transactions.GET("", transactionHandler.GetUserTransactions)
 	transactions.POST("/transfer", transactionHandler.CreateTransfer)
+	transactions.POST("/split-transfer", transactionHandler.CreateSplitTransfer)
+	transactions.POST("/bulk-delete", transactionHandler.BulkDeleteTransactions)
+	transactions.POST("/bulk-categorize", transactionHandler.BulkCategorizeTransactions)
+	transactions.GET("/flagged", transactionHandler.GetFlaggedTransactions)
 	transactions.GET("/:id", transactionHandler.GetTransactionByID)
 	transactions.DELETE("/:id", transactionHandler.DeleteTransaction)
 
+	shares := protected.Group("/shares")
+	shares.POST("", shareHandler.CreateShare)
+	shares.GET("", shareHandler.GetShares)
+	shares.POST("/:id/accept", shareHandler.AcceptShare)
+	shares.DELETE("/:id", shareHandler.DeleteShare)
+
 	categories := protected.Group("/categories")
 	categories.POST("", categoryHandler.CreateCategory)
 	categories.GET("", categoryHandler.GetUserCategories)
 
 	budgets := protected.Group("/budgets")
 	budgets.POST("", budgetHandler.CreateBudget)
+	budgets.POST("/clone-last-month", budgetHandler.CloneLastMonth)
 	budgets.GET("", budgetHandler.GetBudgets)
 	budgets.GET("/:id", budgetHandler.GetBudget)
 	budgets.PUT("/:id", budgetHandler.UpdateBudget)
 	budgets.DELETE("/:id", budgetHandler.DeleteBudget)
 	budgets.GET("/:id/progress", budgetHandler.GetBudgetProgress)
+	budgets.GET("/:id/burndown", budgetHandler.GetBudgetBurndown)
 
 	investments := protected.Group("/investments")
 	investments.POST("", investmentHandler.AddInvestment)
 	investments.GET("", investmentHandler.GetAllInvestments)
-	investments.GET("/portfolio", investmentHandler.GetPortfolio)
-	investments.GET("/snapshots", snapshotHandler.GetSnapshots)
+	investments.GET("/portfolio", analyticsTimeout, investmentHandler.GetPortfolio)
+	investments.GET("/tax-report", investmentHandler.GetTaxReport)
+	investments.GET("/snapshots", analyticsTimeout, snapshotHandler.GetSnapshots)
 	investments.GET("/:id", investmentHandler.GetInvestment)
+	investments.PUT("/:id", investmentHandler.UpdateInvestment)
 	investments.POST("/:id/buy", investmentHandler.RecordBuy)
 	investments.POST("/:id/sell", investmentHandler.RecordSell)
 	investments.POST("/:id/dividend", investmentHandler.RecordDividend)
@@ -157,13 +212,30 @@ func setupApp(t *testing.T) *testApp {
 	securities.GET("/:id", securityHandler.GetSecurity)
 	securities.GET("/:id/prices", securityHandler.GetPriceHistory)
 
+	protected.GET("/statements/:month", statementHandler.GetMonthlyStatement)
+	protected.GET("/stats/accounts", analyticsTimeout, statsHandler.GetAccountStats)
+	protected.GET("/stats/categories", analyticsTimeout, statsHandler.GetCategoryStats)
+
 	// Pipeline routes (use test API key for integration tests)
 	pipeline := v1.Group("/pipeline")
-	pipeline.Use(middleware.PipelineAuthMiddleware("test-pipeline-key"))
+	pipeline.Use(middleware.PipelineAuthMiddleware("test-pipeline-key", pipelineKeyService))
 	pipeline.GET("/securities", securityHandler.ListAllSecurities)
 	pipeline.POST("/securities", securityHandler.CreateSecurity)
 	pipeline.POST("/securities/prices", securityHandler.RecordPrices)
+	pipeline.PUT("/securities/:id/prices/:priceId", securityHandler.CorrectPrice)
 	pipeline.POST("/snapshots", snapshotHandler.ComputeSnapshots)
+	pipeline.POST("/snapshots/backfill", snapshotHandler.BackfillSnapshots)
+	pipeline.POST("/snapshots/recompute", snapshotHandler.RecomputeSnapshots)
+	pipeline.POST("/transactions/settle", transactionHandler.SettlePendingTransactions)
+	pipeline.POST("/budgets/rollover", budgetHandler.RolloverBudgets)
+	pipeline.POST("/oracle-runs", oracleRunHandler.RecordOracleRun)
+
+	admin := v1.Group("/admin")
+	admin.Use(middleware.AdminAuthMiddleware("test-admin-key"))
+	admin.POST("/pipeline-keys", pipelineKeyHandler.CreatePipelineKey)
+	admin.GET("/pipeline-keys", pipelineKeyHandler.ListPipelineKeys)
+	admin.DELETE("/pipeline-keys/:id", pipelineKeyHandler.RevokePipelineKey)
+	admin.GET("/oracle-runs", oracleRunHandler.ListOracleRuns)
 
 	return &testApp{DB: db, Router: router}
 }
@@ -182,16 +254,26 @@ func (app *testApp) request(method, path, body, token string) *httptest.Response
 
 // pipelineRequest makes an HTTP request to a pipeline endpoint with the test API key.
 func (app *testApp) pipelineRequest(method, path, body string) *httptest.ResponseRecorder {
+	return app.keyRequest(method, path, body, "X-API-Key", "test-pipeline-key")
+}
+
+// adminRequest makes a request authenticated with the test admin key.
+func (app *testApp) adminRequest(method, path, body string) *httptest.ResponseRecorder {
+	return app.keyRequest(method, path, body, "X-Admin-Key", "test-admin-key")
+}
+
+// keyRequest makes a request that sends key in the given header.
+func (app *testApp) keyRequest(method, path, body, header, key string) *httptest.ResponseRecorder {
 	req := httptest.NewRequest(method, path, strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-API-Key", "test-pipeline-key")
+	req.Header.Set(header, key)
 	rec := httptest.NewRecorder()
 	app.Router.ServeHTTP(rec, req)
 	return rec
 }
 
 // createSecurity creates a security via the pipeline API and returns the security ID.
-func (app *testApp) createSecurity(t *testing.T, symbol, name, assetType string) float64 {
+func (app *testApp) createSecurity(t *testing.T, symbol, name, assetType string) string {
 	t.Helper()
 	body := fmt.Sprintf(`{"symbol":%q,"name":%q,"asset_type":%q}`, symbol, name, assetType)
 	rec := app.pipelineRequest("POST", "/api/v1/pipeline/securities", body)
@@ -200,7 +282,7 @@ func (app *testApp) createSecurity(t *testing.T, symbol, name, assetType string)
 	}
 	result := parseJSON(t, rec)
 	sec := result["security"].(map[string]interface{})
-	return sec["id"].(float64)
+	return sec["id"].(string)
 }
 
 // parseJSON parses the response body into a map.
@@ -214,7 +296,7 @@ func parseJSON(t *testing.T, rec *httptest.ResponseRecorder) map[string]interfac
 }
 
 // registerUser registers a new user and returns the access token, refresh token, and user ID.
-func (app *testApp) registerUser(t *testing.T, email, password string) (accessToken, refreshToken string, userID float64) {
+func (app *testApp) registerUser(t *testing.T, email, password string) (accessToken, refreshToken string, userID string) {
 	t.Helper()
 	body := fmt.Sprintf(`{"email":%q,"password":%q,"first_name":"Test","last_name":"User"}`, email, password)
 	rec := app.request("POST", "/api/v1/auth/register", body, "")
@@ -223,7 +305,7 @@ func (app *testApp) registerUser(t *testing.T, email, password string) (accessTo
 	}
 	result := parseJSON(t, rec)
 	user := result["user"].(map[string]interface{})
-	return result["access_token"].(string), result["refresh_token"].(string), user["id"].(float64)
+	return result["access_token"].(string), result["refresh_token"].(string), user["id"].(string)
 }
 
 // loginUser logs in and returns the access and refresh tokens.
@@ -0,0 +1,157 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// createCashAccount creates a cash account with an initial balance and returns its ID.
+func createCashAccount(t *testing.T, app *testApp, token, name string, balance int64) string {
+	t.Helper()
+	rec := app.request("POST", "/api/v1/accounts/cash",
+		fmt.Sprintf(`{"name":%q,"currency":"USD","initial_balance":%d}`, name, balance), token)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create account failed: %d %s", rec.Code, rec.Body.String())
+	}
+	return parseJSON(t, rec)["account"].(map[string]interface{})["id"].(string)
+}
+
+// shareAccounts invites email with role and accepts as the invitee, returning the share ID.
+func shareAccounts(t *testing.T, app *testApp, ownerToken, inviteeToken, email, role, accountIDsJSON string) string {
+	t.Helper()
+	rec := app.request("POST", "/api/v1/shares",
+		fmt.Sprintf(`{"email":%q,"role":%q,"account_ids":%s}`, email, role, accountIDsJSON), ownerToken)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create share failed: %d %s", rec.Code, rec.Body.String())
+	}
+	shareID := parseJSON(t, rec)["share"].(map[string]interface{})["id"].(string)
+
+	rec = app.request("POST", fmt.Sprintf("/api/v1/shares/%s/accept", shareID), "", inviteeToken)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("accept share failed: %d %s", rec.Code, rec.Body.String())
+	}
+	return shareID
+}
+
+func TestShareFlow_ViewerCanReadButNotWrite(t *testing.T) {
+	app := setupApp(t)
+	ownerToken, _, _ := app.registerUser(t, "owner@test.com", "password123")
+	viewerToken, _, _ := app.registerUser(t, "viewer@test.com", "password123")
+
+	sharedID := createCashAccount(t, app, ownerToken, "Joint", 10000)
+	privateID := createCashAccount(t, app, ownerToken, "Private", 5000)
+	shareAccounts(t, app, ownerToken, viewerToken, "viewer@test.com", "viewer", fmt.Sprintf("[%q]", sharedID))
+
+	// Viewer sees the shared account but not the private one
+	rec := app.request("GET", "/api/v1/accounts", "", viewerToken)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if total := parseJSON(t, rec)["total_items"].(float64); total != 1 {
+		t.Errorf("expected 1 accessible account, got %.0f", total)
+	}
+	rec = app.request("GET", fmt.Sprintf("/api/v1/accounts/%s", privateID), "", viewerToken)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unshared account, got %d", rec.Code)
+	}
+
+	// Viewer sees the shared account's transactions
+	rec = app.request("GET", "/api/v1/transactions", "", viewerToken)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if total := parseJSON(t, rec)["total_items"].(float64); total != 1 {
+		t.Errorf("expected 1 visible transaction, got %.0f", total)
+	}
+
+	// Viewer cannot create a transaction on the shared account
+	rec = app.request("POST", "/api/v1/transactions",
+		fmt.Sprintf(`{"account_id":%q,"type":"expense","amount":1000,"description":"Sneaky"}`, sharedID), viewerToken)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d: %s", rec.Code, rec.Body.String())
+	}
+	errObj := parseJSON(t, rec)["error"].(map[string]interface{})
+	if errObj["code"] != "SHARE_READ_ONLY" {
+		t.Errorf("expected SHARE_READ_ONLY, got %v", errObj["code"])
+	}
+
+	// Viewer cannot rename the shared account
+	rec = app.request("PUT", fmt.Sprintf("/api/v1/accounts/%s", sharedID), `{"name":"Mine"}`, viewerToken)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 on account update, got %d", rec.Code)
+	}
+
+	// Balance is untouched
+	rec = app.request("GET", fmt.Sprintf("/api/v1/accounts/%s", sharedID), "", ownerToken)
+	if balance := parseJSON(t, rec)["account"].(map[string]interface{})["balance"].(float64); balance != 10000 {
+		t.Errorf("expected balance 10000, got %.0f", balance)
+	}
+}
+
+func TestShareFlow_EditorCanCreateTransactions(t *testing.T) {
+	app := setupApp(t)
+	ownerToken, _, _ := app.registerUser(t, "owner@test.com", "password123")
+	editorToken, _, _ := app.registerUser(t, "editor@test.com", "password123")
+
+	sharedID := createCashAccount(t, app, ownerToken, "Joint", 10000)
+	shareAccounts(t, app, ownerToken, editorToken, "editor@test.com", "editor", "[]")
+
+	rec := app.request("POST", "/api/v1/transactions",
+		fmt.Sprintf(`{"account_id":%q,"type":"expense","amount":2500,"description":"Groceries"}`, sharedID), editorToken)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// Owner sees the editor's transaction and the new balance
+	rec = app.request("GET", fmt.Sprintf("/api/v1/accounts/%s/transactions", sharedID), "", ownerToken)
+	if total := parseJSON(t, rec)["total_items"].(float64); total != 2 {
+		t.Errorf("expected 2 transactions, got %.0f", total)
+	}
+	rec = app.request("GET", fmt.Sprintf("/api/v1/accounts/%s", sharedID), "", ownerToken)
+	if balance := parseJSON(t, rec)["account"].(map[string]interface{})["balance"].(float64); balance != 7500 {
+		t.Errorf("expected balance 7500, got %.0f", balance)
+	}
+
+	// Editors still cannot change account settings
+	rec = app.request("PUT", fmt.Sprintf("/api/v1/accounts/%s", sharedID), `{"name":"Mine"}`, editorToken)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 on account update, got %d", rec.Code)
+	}
+}
+
+func TestShareFlow_PendingShareGrantsNothing(t *testing.T) {
+	app := setupApp(t)
+	ownerToken, _, _ := app.registerUser(t, "owner@test.com", "password123")
+	inviteeToken, _, _ := app.registerUser(t, "invitee@test.com", "password123")
+
+	accountID := createCashAccount(t, app, ownerToken, "Joint", 10000)
+	rec := app.request("POST", "/api/v1/shares", `{"email":"invitee@test.com","role":"editor"}`, ownerToken)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = app.request("GET", fmt.Sprintf("/api/v1/accounts/%s", accountID), "", inviteeToken)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 before acceptance, got %d", rec.Code)
+	}
+}
+
+func TestShareFlow_RevokedShareRemovesAccess(t *testing.T) {
+	app := setupApp(t)
+	ownerToken, _, _ := app.registerUser(t, "owner@test.com", "password123")
+	viewerToken, _, _ := app.registerUser(t, "viewer@test.com", "password123")
+
+	accountID := createCashAccount(t, app, ownerToken, "Joint", 10000)
+	shareID := shareAccounts(t, app, ownerToken, viewerToken, "viewer@test.com", "viewer", "[]")
+
+	rec := app.request("DELETE", fmt.Sprintf("/api/v1/shares/%s", shareID), "", ownerToken)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = app.request("GET", fmt.Sprintf("/api/v1/accounts/%s", accountID), "", viewerToken)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 after revoke, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,71 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestStatementFlow_CurrentMonth(t *testing.T) {
+	app := setupApp(t)
+	token, _, _ := app.registerUser(t, "statement@test.com", "password123")
+	otherToken, _, _ := app.registerUser(t, "statement-other@test.com", "password123")
+
+	rec := app.request("POST", "/api/v1/categories", `{"name":"Groceries","type":"expense"}`, token)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201 creating category, got %d: %s", rec.Code, rec.Body.String())
+	}
+	categoryID := parseJSON(t, rec)["category"].(map[string]interface{})["id"].(string)
+
+	rec = app.request("POST", "/api/v1/accounts/cash", `{"name":"Checking","initial_balance":50000}`, token)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201 creating account, got %d: %s", rec.Code, rec.Body.String())
+	}
+	accountID := parseJSON(t, rec)["account"].(map[string]interface{})["id"].(string)
+
+	rec = app.request("POST", "/api/v1/transactions",
+		fmt.Sprintf(`{"account_id":%q,"category_id":%q,"type":"expense","amount":12000,"description":"Market"}`, accountID, categoryID), token)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201 creating transaction, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	month := time.Now().UTC().Format("2006-01")
+	rec = app.request("GET", "/api/v1/statements/"+month, "", token)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	statement := parseJSON(t, rec)["statement"].(map[string]interface{})
+	if statement["month"] != month {
+		t.Errorf("expected month %s, got %v", month, statement["month"])
+	}
+	if statement["expenses"].(float64) != 12000 {
+		t.Errorf("expected expenses 12000, got %v", statement["expenses"])
+	}
+	accounts := statement["accounts"].([]interface{})
+	if len(accounts) != 1 {
+		t.Fatalf("expected 1 account, got %d", len(accounts))
+	}
+	if closing := accounts[0].(map[string]interface{})["closing_balance"].(float64); closing != 38000 {
+		t.Errorf("expected closing balance 38000, got %.0f", closing)
+	}
+	top := statement["top_categories"].([]interface{})
+	if len(top) != 1 || top[0].(map[string]interface{})["category_name"] != "Groceries" {
+		t.Errorf("expected Groceries as the only top category, got %v", top)
+	}
+
+	// Another user's statement for the same month is empty
+	rec = app.request("GET", "/api/v1/statements/"+month, "", otherToken)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	other := parseJSON(t, rec)["statement"].(map[string]interface{})
+	if other["expenses"].(float64) != 0 || len(other["accounts"].([]interface{})) != 0 {
+		t.Errorf("expected empty statement for other user, got %v", other)
+	}
+
+	rec = app.request("GET", "/api/v1/statements/2025-13", "", token)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid month, got %d", rec.Code)
+	}
+}
@@ -14,54 +14,54 @@ func TestTransferFlow_SuccessfulTransfer(t *testing.T) {
 	rec := app.request("POST", "/api/v1/accounts/cash",
 		`{"name":"Account A","initial_balance":20000}`, token)
 	acctA := parseJSON(t, rec)["account"].(map[string]interface{})
-	acctAID := acctA["id"].(float64)
+	acctAID := acctA["id"].(string)
 
 	// Create account B with $50
 	rec = app.request("POST", "/api/v1/accounts/cash",
 		`{"name":"Account B","initial_balance":5000}`, token)
 	acctB := parseJSON(t, rec)["account"].(map[string]interface{})
-	acctBID := acctB["id"].(float64)
+	acctBID := acctB["id"].(string)
 
 	// Transfer $75 from A to B
 	rec = app.request("POST", "/api/v1/transactions/transfer",
-		fmt.Sprintf(`{"from_account_id":%.0f,"to_account_id":%.0f,"amount":7500,"description":"Rent money"}`,
+		fmt.Sprintf(`{"from_account_id":%q,"to_account_id":%q,"amount":7500,"description":"Rent money"}`,
 			acctAID, acctBID), token)
 	if rec.Code != http.StatusCreated {
 		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
 	}
 	xferResult := parseJSON(t, rec)
 	xferTx := xferResult["transaction"].(map[string]interface{})
-	xferID := xferTx["id"].(float64)
+	xferID := xferTx["id"].(string)
 
 	// Verify A balance: 20000 - 7500 = 12500
-	rec = app.request("GET", fmt.Sprintf("/api/v1/accounts/%.0f", acctAID), "", token)
+	rec = app.request("GET", fmt.Sprintf("/api/v1/accounts/%s", acctAID), "", token)
 	acctAResult := parseJSON(t, rec)["account"].(map[string]interface{})
 	if acctAResult["balance"].(float64) != 12500 {
 		t.Errorf("expected account A balance 12500, got %.0f", acctAResult["balance"].(float64))
 	}
 
 	// Verify B balance: 5000 + 7500 = 12500
-	rec = app.request("GET", fmt.Sprintf("/api/v1/accounts/%.0f", acctBID), "", token)
+	rec = app.request("GET", fmt.Sprintf("/api/v1/accounts/%s", acctBID), "", token)
 	acctBResult := parseJSON(t, rec)["account"].(map[string]interface{})
 	if acctBResult["balance"].(float64) != 12500 {
 		t.Errorf("expected account B balance 12500, got %.0f", acctBResult["balance"].(float64))
 	}
 
 	// Delete the transfer
-	rec = app.request("DELETE", fmt.Sprintf("/api/v1/transactions/%.0f", xferID), "", token)
+	rec = app.request("DELETE", fmt.Sprintf("/api/v1/transactions/%s", xferID), "", token)
 	if rec.Code != http.StatusOK {
 		t.Fatalf("expected 200 on delete, got %d: %s", rec.Code, rec.Body.String())
 	}
 
 	// Verify A balance restored to 20000
-	rec = app.request("GET", fmt.Sprintf("/api/v1/accounts/%.0f", acctAID), "", token)
+	rec = app.request("GET", fmt.Sprintf("/api/v1/accounts/%s", acctAID), "", token)
 	acctAResult = parseJSON(t, rec)["account"].(map[string]interface{})
 	if acctAResult["balance"].(float64) != 20000 {
 		t.Errorf("expected account A balance 20000 after delete, got %.0f", acctAResult["balance"].(float64))
 	}
 
 	// Verify B balance restored to 5000
-	rec = app.request("GET", fmt.Sprintf("/api/v1/accounts/%.0f", acctBID), "", token)
+	rec = app.request("GET", fmt.Sprintf("/api/v1/accounts/%s", acctBID), "", token)
 	acctBResult = parseJSON(t, rec)["account"].(map[string]interface{})
 	if acctBResult["balance"].(float64) != 5000 {
 		t.Errorf("expected account B balance 5000 after delete, got %.0f", acctBResult["balance"].(float64))
@@ -75,10 +75,10 @@ func TestTransferFlow_SameAccountRejected(t *testing.T) {
 	rec := app.request("POST", "/api/v1/accounts/cash",
 		`{"name":"Only Account","initial_balance":10000}`, token)
 	acct := parseJSON(t, rec)["account"].(map[string]interface{})
-	acctID := acct["id"].(float64)
+	acctID := acct["id"].(string)
 
 	rec = app.request("POST", "/api/v1/transactions/transfer",
-		fmt.Sprintf(`{"from_account_id":%.0f,"to_account_id":%.0f,"amount":1000}`,
+		fmt.Sprintf(`{"from_account_id":%q,"to_account_id":%q,"amount":1000}`,
 			acctID, acctID), token)
 	if rec.Code != http.StatusBadRequest {
 		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
@@ -98,17 +98,17 @@ func TestTransferFlow_InsufficientBalance(t *testing.T) {
 	rec := app.request("POST", "/api/v1/accounts/cash",
 		`{"name":"Poor Account","initial_balance":1000}`, token)
 	acctA := parseJSON(t, rec)["account"].(map[string]interface{})
-	acctAID := acctA["id"].(float64)
+	acctAID := acctA["id"].(string)
 
 	// Account B
 	rec = app.request("POST", "/api/v1/accounts/cash",
 		`{"name":"Rich Account","initial_balance":0}`, token)
 	acctB := parseJSON(t, rec)["account"].(map[string]interface{})
-	acctBID := acctB["id"].(float64)
+	acctBID := acctB["id"].(string)
 
 	// Try to transfer $50 from A ($10)
 	rec = app.request("POST", "/api/v1/transactions/transfer",
-		fmt.Sprintf(`{"from_account_id":%.0f,"to_account_id":%.0f,"amount":5000}`,
+		fmt.Sprintf(`{"from_account_id":%q,"to_account_id":%q,"amount":5000}`,
 			acctAID, acctBID), token)
 	if rec.Code != http.StatusBadRequest {
 		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
@@ -120,7 +120,7 @@ func TestTransferFlow_InsufficientBalance(t *testing.T) {
 	}
 
 	// Verify A balance unchanged
-	rec = app.request("GET", fmt.Sprintf("/api/v1/accounts/%.0f", acctAID), "", token)
+	rec = app.request("GET", fmt.Sprintf("/api/v1/accounts/%s", acctAID), "", token)
 	acctAResult := parseJSON(t, rec)["account"].(map[string]interface{})
 	if acctAResult["balance"].(float64) != 1000 {
 		t.Errorf("expected balance 1000 unchanged, got %.0f", acctAResult["balance"].(float64))
@@ -135,38 +135,38 @@ func TestTransferFlow_MultipleTransfers(t *testing.T) {
 	rec := app.request("POST", "/api/v1/accounts/cash",
 		`{"name":"A","initial_balance":10000}`, token)
 	acctA := parseJSON(t, rec)["account"].(map[string]interface{})
-	acctAID := acctA["id"].(float64)
+	acctAID := acctA["id"].(string)
 
 	rec = app.request("POST", "/api/v1/accounts/cash",
 		`{"name":"B","initial_balance":5000}`, token)
 	acctB := parseJSON(t, rec)["account"].(map[string]interface{})
-	acctBID := acctB["id"].(float64)
+	acctBID := acctB["id"].(string)
 
 	rec = app.request("POST", "/api/v1/accounts/cash",
 		`{"name":"C","initial_balance":0}`, token)
 	acctC := parseJSON(t, rec)["account"].(map[string]interface{})
-	acctCID := acctC["id"].(float64)
+	acctCID := acctC["id"].(string)
 
 	// A -> B: $30
 	app.request("POST", "/api/v1/transactions/transfer",
-		fmt.Sprintf(`{"from_account_id":%.0f,"to_account_id":%.0f,"amount":3000}`, acctAID, acctBID), token)
+		fmt.Sprintf(`{"from_account_id":%q,"to_account_id":%q,"amount":3000}`, acctAID, acctBID), token)
 
 	// B -> C: $60
 	app.request("POST", "/api/v1/transactions/transfer",
-		fmt.Sprintf(`{"from_account_id":%.0f,"to_account_id":%.0f,"amount":6000}`, acctBID, acctCID), token)
+		fmt.Sprintf(`{"from_account_id":%q,"to_account_id":%q,"amount":6000}`, acctBID, acctCID), token)
 
 	// Verify: A=7000, B=2000 (5000+3000-6000), C=6000
-	rec = app.request("GET", fmt.Sprintf("/api/v1/accounts/%.0f", acctAID), "", token)
+	rec = app.request("GET", fmt.Sprintf("/api/v1/accounts/%s", acctAID), "", token)
 	if parseJSON(t, rec)["account"].(map[string]interface{})["balance"].(float64) != 7000 {
 		t.Error("expected A=7000")
 	}
 
-	rec = app.request("GET", fmt.Sprintf("/api/v1/accounts/%.0f", acctBID), "", token)
+	rec = app.request("GET", fmt.Sprintf("/api/v1/accounts/%s", acctBID), "", token)
 	if parseJSON(t, rec)["account"].(map[string]interface{})["balance"].(float64) != 2000 {
 		t.Error("expected B=2000")
 	}
 
-	rec = app.request("GET", fmt.Sprintf("/api/v1/accounts/%.0f", acctCID), "", token)
+	rec = app.request("GET", fmt.Sprintf("/api/v1/accounts/%s", acctCID), "", token)
 	if parseJSON(t, rec)["account"].(map[string]interface{})["balance"].(float64) != 6000 {
 		t.Error("expected C=6000")
 	}
@@ -12,14 +12,15 @@ import (
 
 // Security represents a security returned by the Kuberan pipeline API.
 type Security struct {
-	ID             string `json:"id"`
-	Symbol         string `json:"symbol"`
-	Name           string `json:"name"`
-	AssetType      string `json:"asset_type"`
-	Currency       string `json:"currency"`
-	Exchange       string `json:"exchange"`
-	ProviderSymbol string `json:"provider_symbol"`
-	Network        string `json:"network"`
+	ID                string `json:"id"`
+	Symbol            string `json:"symbol"`
+	Name              string `json:"name"`
+	AssetType         string `json:"asset_type"`
+	Currency          string `json:"currency"`
+	Exchange          string `json:"exchange"`
+	ProviderSymbol    string `json:"provider_symbol"`
+	PreferredProvider string `json:"preferred_provider"`
+	Network           string `json:"network"`
 }
 
 // RecordPriceEntry represents a single price entry to submit to the pipeline API.
@@ -27,6 +28,29 @@ type RecordPriceEntry struct {
 	SecurityID string `json:"security_id"`
 	Price      int64  `json:"price"`
 	RecordedAt string `json:"recorded_at"` // RFC3339
+	Source     string `json:"source,omitempty"`
+	Currency   string `json:"currency,omitempty"` // currency Price is in, after any conversion
+}
+
+// RunRecord is the result of an oracle run as posted to the pipeline API's run history.
+type RunRecord struct {
+	Version           string           `json:"version"`
+	Failed            bool             `json:"failed"`
+	Error             string           `json:"error,omitempty"` // why a failed run stopped
+	DryRun            bool             `json:"dry_run"`
+	StartedAt         time.Time        `json:"started_at"`
+	DurationMS        int64            `json:"duration_ms"`
+	SecuritiesFetched int              `json:"securities_fetched"`
+	PricesRecorded    int              `json:"prices_recorded"`
+	SnapshotsRecorded int              `json:"snapshots_recorded"`
+	Errors            []RunRecordError `json:"errors"`
+}
+
+// RunRecordError is a symbol whose price a run failed to fetch or record.
+type RunRecordError struct {
+	SecurityID string `json:"security_id,omitempty"`
+	Symbol     string `json:"symbol"`
+	Error      string `json:"error"`
 }
 
 // KuberanClient communicates with the Kuberan pipeline API.
@@ -72,6 +96,41 @@ func (c *KuberanClient) GetSecurities(ctx context.Context) ([]Security, error) {
 	return result.Securities, nil
 }
 
+// CheckAccess verifies the API is reachable and accepts the API key by listing
+// at most one security. A rejected key and a pipeline the API has not been
+// configured for are reported as such rather than as a bare status code.
+func (c *KuberanClient) CheckAccess(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/pipeline/securities?limit=1", nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("X-API-Key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("reaching API: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("API key rejected (status %d)", resp.StatusCode)
+	case http.StatusServiceUnavailable:
+		return fmt.Errorf("pipeline API not configured on the server (status %d)", resp.StatusCode)
+	default:
+		return fmt.Errorf("listing securities: unexpected status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Securities []Security `json:"securities"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("decoding securities response: %w", err)
+	}
+	return nil
+}
+
 // RecordPrices submits price entries to the pipeline API and returns the count recorded.
 func (c *KuberanClient) RecordPrices(ctx context.Context, prices []RecordPriceEntry) (int, error) {
 	body := struct {
@@ -110,10 +169,17 @@ func (c *KuberanClient) RecordPrices(ctx context.Context, prices []RecordPriceEn
 }
 
 // ComputeSnapshots triggers portfolio snapshot computation and returns the count recorded.
-func (c *KuberanClient) ComputeSnapshots(ctx context.Context) (int, error) {
+// Snapshots are recorded now, or at asOf with the prices known then when it is set.
+func (c *KuberanClient) ComputeSnapshots(ctx context.Context, asOf *time.Time) (int, error) {
 	body := struct {
-		RecordedAt string `json:"recorded_at"`
-	}{RecordedAt: time.Now().UTC().Format(time.RFC3339)}
+		RecordedAt string `json:"recorded_at,omitempty"`
+		AsOf       string `json:"as_of,omitempty"`
+	}{}
+	if asOf != nil {
+		body.AsOf = asOf.UTC().Format(time.RFC3339)
+	} else {
+		body.RecordedAt = time.Now().UTC().Format(time.RFC3339)
+	}
 
 	jsonBody, err := json.Marshal(body)
 	if err != nil {
@@ -145,3 +211,32 @@ func (c *KuberanClient) ComputeSnapshots(ctx context.Context) (int, error) {
 	}
 	return result.SnapshotsRecorded, nil
 }
+
+// RecordRun posts the result of a run to the pipeline API's run history.
+func (c *KuberanClient) RecordRun(ctx context.Context, run RunRecord) error {
+	if run.Errors == nil {
+		run.Errors = []RunRecordError{}
+	}
+	jsonBody, err := json.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("marshaling run record: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/pipeline/oracle-runs", strings.NewReader(string(jsonBody)))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("recording run: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("recording run: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
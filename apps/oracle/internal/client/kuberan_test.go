@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestGetSecurities_Success(t *testing.T) {
@@ -25,7 +26,7 @@ func TestGetSecurities_Success(t *testing.T) {
 		_ = json.NewEncoder(w).Encode(map[string]any{
 			"securities": []map[string]any{
 				{"id": "sec-1", "symbol": "AAPL", "name": "Apple Inc.", "asset_type": "stock", "currency": "USD", "exchange": "NASDAQ", "network": "", "provider_symbol": ""},
-				{"id": "sec-2", "symbol": "BTC", "name": "Bitcoin", "asset_type": "crypto", "currency": "USD", "exchange": "", "network": "bitcoin", "provider_symbol": ""},
+				{"id": "sec-2", "symbol": "BTC", "name": "Bitcoin", "asset_type": "crypto", "currency": "USD", "exchange": "", "network": "bitcoin", "provider_symbol": "", "preferred_provider": "CoinGecko"},
 				{"id": "sec-3", "symbol": "CIMB", "name": "CIMB Group", "asset_type": "stock", "currency": "MYR", "exchange": "BURSA", "network": "", "provider_symbol": "1023.KL"},
 			},
 		})
@@ -50,6 +51,9 @@ func TestGetSecurities_Success(t *testing.T) {
 	if securities[1].ID != "sec-2" || securities[1].Symbol != "BTC" || securities[1].Network != "bitcoin" {
 		t.Errorf("second security mismatch: %+v", securities[1])
 	}
+	if securities[1].PreferredProvider != "CoinGecko" {
+		t.Errorf("second security: expected preferred_provider 'CoinGecko', got %q", securities[1].PreferredProvider)
+	}
 	if securities[2].ID != "sec-3" || securities[2].Symbol != "CIMB" || securities[2].Exchange != "BURSA" {
 		t.Errorf("third security mismatch: %+v", securities[2])
 	}
@@ -91,6 +95,46 @@ func TestGetSecurities_ServerError(t *testing.T) {
 	}
 }
 
+func TestCheckAccess(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr string
+	}{
+		{"ok", http.StatusOK, ""},
+		{"rejected_key", http.StatusUnauthorized, "API key rejected"},
+		{"pipeline_not_configured", http.StatusServiceUnavailable, "not configured"},
+		{"server_error", http.StatusInternalServerError, "unexpected status 500"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v1/pipeline/securities" || r.URL.Query().Get("limit") != "1" {
+					t.Errorf("unexpected request: %s", r.URL)
+				}
+				if r.Header.Get("X-API-Key") != "test-key" {
+					t.Errorf("missing or wrong API key header")
+				}
+				w.WriteHeader(tt.status)
+				_ = json.NewEncoder(w).Encode(map[string]any{"securities": []any{}})
+			}))
+			defer server.Close()
+
+			c := NewKuberanClient(server.URL, "test-key", server.Client())
+			err := c.CheckAccess(context.Background())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestRecordPrices_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
@@ -146,8 +190,8 @@ func TestRecordPrices_ValidatesRequestBody(t *testing.T) {
 
 	c := NewKuberanClient(server.URL, "my-secret-key", server.Client())
 	prices := []RecordPriceEntry{
-		{SecurityID: "sec-1", Price: 17872, RecordedAt: "2025-01-15T10:00:00Z"},
-		{SecurityID: "sec-2", Price: 6723456, RecordedAt: "2025-01-15T10:00:00Z"},
+		{SecurityID: "sec-1", Price: 17872, RecordedAt: "2025-01-15T10:00:00Z", Source: "yahoo"},
+		{SecurityID: "sec-2", Price: 6723456, RecordedAt: "2025-01-15T10:00:00Z", Source: "coingecko"},
 	}
 
 	_, err := c.RecordPrices(context.Background(), prices)
@@ -166,6 +210,7 @@ func TestRecordPrices_ValidatesRequestBody(t *testing.T) {
 			SecurityID string `json:"security_id"`
 			Price      int64  `json:"price"`
 			RecordedAt string `json:"recorded_at"`
+			Source     string `json:"source"`
 		} `json:"prices"`
 	}
 	if err := json.Unmarshal(capturedBody, &parsed); err != nil {
@@ -174,10 +219,10 @@ func TestRecordPrices_ValidatesRequestBody(t *testing.T) {
 	if len(parsed.Prices) != 2 {
 		t.Fatalf("expected 2 prices in body, got %d", len(parsed.Prices))
 	}
-	if parsed.Prices[0].SecurityID != "sec-1" || parsed.Prices[0].Price != 17872 {
+	if parsed.Prices[0].SecurityID != "sec-1" || parsed.Prices[0].Price != 17872 || parsed.Prices[0].Source != "yahoo" {
 		t.Errorf("first price mismatch: %+v", parsed.Prices[0])
 	}
-	if parsed.Prices[1].SecurityID != "sec-2" || parsed.Prices[1].Price != 6723456 {
+	if parsed.Prices[1].SecurityID != "sec-2" || parsed.Prices[1].Price != 6723456 || parsed.Prices[1].Source != "coingecko" {
 		t.Errorf("second price mismatch: %+v", parsed.Prices[1])
 	}
 }
@@ -200,6 +245,7 @@ func TestComputeSnapshots_Success(t *testing.T) {
 		// Verify body has recorded_at field.
 		var body struct {
 			RecordedAt string `json:"recorded_at"`
+			AsOf       string `json:"as_of"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 			t.Fatalf("decoding body: %v", err)
@@ -207,6 +253,9 @@ func TestComputeSnapshots_Success(t *testing.T) {
 		if body.RecordedAt == "" {
 			t.Error("expected recorded_at in body")
 		}
+		if body.AsOf != "" {
+			t.Errorf("expected no as_of in body, got %q", body.AsOf)
+		}
 
 		w.Header().Set("Content-Type", "application/json")
 		_ = json.NewEncoder(w).Encode(map[string]int{"snapshots_recorded": 3})
@@ -214,7 +263,7 @@ func TestComputeSnapshots_Success(t *testing.T) {
 	defer server.Close()
 
 	c := NewKuberanClient(server.URL, "test-key", server.Client())
-	n, err := c.ComputeSnapshots(context.Background())
+	n, err := c.ComputeSnapshots(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -223,7 +272,96 @@ func TestComputeSnapshots_Success(t *testing.T) {
 	}
 }
 
+func TestComputeSnapshots_AsOf(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decoding body: %v", err)
+		}
+		if body["as_of"] != "2026-02-06T23:59:59Z" {
+			t.Errorf("expected as_of 2026-02-06T23:59:59Z, got %q", body["as_of"])
+		}
+		if _, ok := body["recorded_at"]; ok {
+			t.Error("expected no recorded_at alongside as_of")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]int{"snapshots_recorded": 1})
+	}))
+	defer server.Close()
+
+	c := NewKuberanClient(server.URL, "test-key", server.Client())
+	asOf := time.Date(2026, 2, 6, 23, 59, 59, 0, time.UTC)
+	n, err := c.ComputeSnapshots(context.Background(), &asOf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 snapshot recorded, got %d", n)
+	}
+}
+
 // contains checks if s contains substr.
+func TestRecordRun_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if r.URL.Path != "/api/v1/pipeline/oracle-runs" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.Header.Get("X-API-Key") != "test-key" {
+			t.Errorf("missing or wrong API key header")
+		}
+
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decoding body: %v", err)
+		}
+		if body["version"] != "1.4.0" || body["failed"] != true || body["error"] != "fetching securities: boom" {
+			t.Errorf("unexpected run fields: %v", body)
+		}
+		if body["started_at"] != "2026-10-15T06:00:00Z" || body["duration_ms"] != float64(1500) {
+			t.Errorf("unexpected timing: %v", body)
+		}
+		if errs, ok := body["errors"].([]any); !ok || len(errs) != 0 {
+			t.Errorf("expected an empty errors list, got %v", body["errors"])
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{"run": map[string]any{"status": "failed"}})
+	}))
+	defer server.Close()
+
+	c := NewKuberanClient(server.URL, "test-key", server.Client())
+	err := c.RecordRun(context.Background(), RunRecord{
+		Version:    "1.4.0",
+		Failed:     true,
+		Error:      "fetching securities: boom",
+		StartedAt:  time.Date(2026, 10, 15, 6, 0, 0, 0, time.UTC),
+		DurationMS: 1500,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRecordRun_UnexpectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	c := NewKuberanClient(server.URL, "test-key", server.Client())
+	err := c.RecordRun(context.Background(), RunRecord{StartedAt: time.Now()})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if want := "unexpected status 400"; !contains(err.Error(), want) {
+		t.Errorf("error %q should contain %q", err.Error(), want)
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && searchString(s, substr)
 }
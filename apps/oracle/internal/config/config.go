@@ -4,11 +4,19 @@ package config
 import (
 	"fmt"
 	"log/slog"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
 
+// Bounds on tunables, so a typo fails at startup rather than stalling a run.
+const (
+	maxRequestTimeout = 5 * time.Minute
+	maxConcurrency    = 50
+)
+
 // Config holds all oracle configuration values.
 type Config struct {
 	KuberanAPIURL    string
@@ -17,18 +25,26 @@ type Config struct {
 	RequestTimeout   time.Duration
 	ComputeSnapshots bool
 	TargetCurrency   string // Target currency for all prices (default: "MYR")
+	MaxConcurrency   int    // Requests each provider makes at once; 0 keeps each provider's default
+
+	// Set from command-line flags rather than the environment.
+	DryRun       bool       // Fetch and convert prices without recording anything
+	ReportFile   string     // Optional path for the JSON run report
+	SnapshotAsOf *time.Time // Record snapshots at this past time, with the prices known then
+	Check        bool       // Check connectivity to the API and providers instead of running
 }
 
 // Load reads configuration from environment variables and validates required fields.
 func Load() (*Config, error) {
 	cfg := &Config{}
 
-	cfg.KuberanAPIURL = os.Getenv("KUBERAN_API_URL")
-	if cfg.KuberanAPIURL == "" {
-		return nil, fmt.Errorf("KUBERAN_API_URL is required")
+	apiURL, err := parseAPIURL(os.Getenv("KUBERAN_API_URL"))
+	if err != nil {
+		return nil, err
 	}
+	cfg.KuberanAPIURL = apiURL
 
-	cfg.PipelineAPIKey = os.Getenv("PIPELINE_API_KEY")
+	cfg.PipelineAPIKey = strings.TrimSpace(os.Getenv("PIPELINE_API_KEY"))
 	if cfg.PipelineAPIKey == "" {
 		return nil, fmt.Errorf("PIPELINE_API_KEY is required")
 	}
@@ -56,9 +72,51 @@ func Load() (*Config, error) {
 		cfg.TargetCurrency = "MYR"
 	}
 
+	concurrency, err := parseConcurrency(os.Getenv("MAX_CONCURRENCY"))
+	if err != nil {
+		return nil, err
+	}
+	cfg.MaxConcurrency = concurrency
+
 	return cfg, nil
 }
 
+// parseAPIURL requires an absolute http(s) URL with a host, such as
+// "https://kuberan.example.com".
+func parseAPIURL(s string) (string, error) {
+	if s == "" {
+		return "", fmt.Errorf("KUBERAN_API_URL is required")
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid KUBERAN_API_URL %q: %w", s, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid KUBERAN_API_URL %q: scheme must be http or https", s)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid KUBERAN_API_URL %q: missing host", s)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("invalid KUBERAN_API_URL %q: must not have a query or fragment", s)
+	}
+	return s, nil
+}
+
+func parseConcurrency(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid MAX_CONCURRENCY %q: must be an integer", s)
+	}
+	if n < 1 || n > maxConcurrency {
+		return 0, fmt.Errorf("MAX_CONCURRENCY must be between 1 and %d, got %d", maxConcurrency, n)
+	}
+	return n, nil
+}
+
 func parseLogLevel(s string) (slog.Level, error) {
 	if s == "" {
 		return slog.LevelInfo, nil
@@ -85,8 +143,8 @@ func parseTimeout(s string) (time.Duration, error) {
 	if err != nil {
 		return 0, fmt.Errorf("invalid REQUEST_TIMEOUT %q: %w", s, err)
 	}
-	if d <= 0 {
-		return 0, fmt.Errorf("REQUEST_TIMEOUT must be positive, got %v", d)
+	if d <= 0 || d > maxRequestTimeout {
+		return 0, fmt.Errorf("REQUEST_TIMEOUT must be positive and at most %v, got %v", maxRequestTimeout, d)
 	}
 	return d, nil
 }
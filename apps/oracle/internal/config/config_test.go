@@ -0,0 +1,75 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	valid := map[string]string{
+		"KUBERAN_API_URL":  "https://kuberan.example.com",
+		"PIPELINE_API_KEY": "secret",
+		"LOG_LEVEL":        "",
+		"REQUEST_TIMEOUT":  "",
+		"MAX_CONCURRENCY":  "",
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{"valid", nil, ""},
+		{"missing_url", map[string]string{"KUBERAN_API_URL": ""}, "KUBERAN_API_URL is required"},
+		{"url_without_scheme", map[string]string{"KUBERAN_API_URL": "kuberan.example.com"}, "scheme must be http or https"},
+		{"url_without_host", map[string]string{"KUBERAN_API_URL": "http://"}, "missing host"},
+		{"url_with_query", map[string]string{"KUBERAN_API_URL": "http://api:8080?x=1"}, "query or fragment"},
+		{"malformed_url", map[string]string{"KUBERAN_API_URL": "http://api:port"}, "invalid KUBERAN_API_URL"},
+		{"missing_key", map[string]string{"PIPELINE_API_KEY": ""}, "PIPELINE_API_KEY is required"},
+		{"blank_key", map[string]string{"PIPELINE_API_KEY": "  "}, "PIPELINE_API_KEY is required"},
+		{"timeout_too_long", map[string]string{"REQUEST_TIMEOUT": "1h"}, "at most 5m0s"},
+		{"negative_timeout", map[string]string{"REQUEST_TIMEOUT": "-1s"}, "must be positive"},
+		{"concurrency_zero", map[string]string{"MAX_CONCURRENCY": "0"}, "between 1 and 50"},
+		{"concurrency_too_high", map[string]string{"MAX_CONCURRENCY": "100"}, "between 1 and 50"},
+		{"concurrency_not_a_number", map[string]string{"MAX_CONCURRENCY": "many"}, "must be an integer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range valid {
+				t.Setenv(k, v)
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			cfg, err := Load()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.RequestTimeout != 30*time.Second || cfg.MaxConcurrency != 0 || cfg.TargetCurrency != "MYR" {
+				t.Errorf("unexpected defaults: %+v", cfg)
+			}
+		})
+	}
+
+	t.Run("concurrency_set", func(t *testing.T) {
+		for k, v := range valid {
+			t.Setenv(k, v)
+		}
+		t.Setenv("MAX_CONCURRENCY", "8")
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.MaxConcurrency != 8 {
+			t.Errorf("expected max concurrency 8, got %d", cfg.MaxConcurrency)
+		}
+	})
+}
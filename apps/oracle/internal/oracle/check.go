@@ -0,0 +1,54 @@
+package oracle
+
+import (
+	"context"
+	"time"
+
+	"github.com/kuberan/oracle/internal/provider"
+)
+
+// CheckResult is the outcome of one preflight check.
+type CheckResult struct {
+	Name       string `json:"name"`
+	OK         bool   `json:"ok"`
+	Skipped    bool   `json:"skipped,omitempty"` // the provider cannot be probed
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// CheckReport is the outcome of a preflight: OK only if every check passed.
+type CheckReport struct {
+	OK     bool          `json:"ok"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// Check runs the connectivity preflight with the same client and providers a
+// run would use: it lists securities from the Kuberan API, which verifies the
+// API key, and probes every provider that supports it. Nothing is recorded.
+func (o *Oracle) Check(ctx context.Context) *CheckReport {
+	report := &CheckReport{OK: true}
+	add := func(name string, check func(context.Context) error) {
+		start := time.Now()
+		result := CheckResult{Name: name, OK: true}
+		if check == nil {
+			result.Skipped = true
+		} else if err := check(ctx); err != nil {
+			result.OK = false
+			result.Error = err.Error()
+			report.OK = false
+		}
+		result.DurationMS = time.Since(start).Milliseconds()
+		o.logger.Debug("preflight check", "name", name, "ok", result.OK, "skipped", result.Skipped, "error", result.Error)
+		report.Checks = append(report.Checks, result)
+	}
+
+	add("kuberan_api", o.client.CheckAccess)
+	for _, p := range o.providers {
+		var probe func(context.Context) error
+		if prober, ok := p.(provider.Prober); ok {
+			probe = prober.Probe
+		}
+		add("provider:"+p.Source(), probe)
+	}
+	return report
+}
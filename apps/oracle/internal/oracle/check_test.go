@@ -0,0 +1,115 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kuberan/oracle/internal/client"
+	"github.com/kuberan/oracle/internal/provider"
+)
+
+// mockProbingProvider is a mockProvider that also implements provider.Prober.
+type mockProbingProvider struct {
+	mockProvider
+	probe func(ctx context.Context) error
+}
+
+func (m *mockProbingProvider) Probe(ctx context.Context) error {
+	return m.probe(ctx)
+}
+
+// newPipelineServer serves the securities listing to requests carrying key.
+func newPipelineServer(t *testing.T, key string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != key {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"securities": []any{}})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOracle_Check(t *testing.T) {
+	probing := func(source string, err error) *mockProbingProvider {
+		return &mockProbingProvider{
+			mockProvider: mockProvider{name: source, source: source},
+			probe:        func(context.Context) error { return err },
+		}
+	}
+	unprobed := &mockProvider{name: "Manual", source: "manual"}
+
+	t.Run("all_pass", func(t *testing.T) {
+		server := newPipelineServer(t, "good-key")
+		kc := client.NewKuberanClient(server.URL, "good-key", server.Client())
+		orc := NewOracle(kc, []provider.Provider{probing("yahoo", nil), unprobed}, newMYRConverter(), defaultConfig(false), newTestLogger())
+
+		report := orc.Check(context.Background())
+
+		if !report.OK {
+			t.Fatalf("expected the preflight to pass, got %+v", report.Checks)
+		}
+		if len(report.Checks) != 3 {
+			t.Fatalf("expected 3 checks, got %d", len(report.Checks))
+		}
+		names := []string{report.Checks[0].Name, report.Checks[1].Name, report.Checks[2].Name}
+		if strings.Join(names, ",") != "kuberan_api,provider:yahoo,provider:manual" {
+			t.Errorf("unexpected checks: %v", names)
+		}
+		if !report.Checks[2].Skipped || !report.Checks[2].OK {
+			t.Errorf("expected a provider without a probe to be skipped, got %+v", report.Checks[2])
+		}
+	})
+
+	t.Run("rejected_key_and_unreachable_provider", func(t *testing.T) {
+		server := newPipelineServer(t, "good-key")
+		kc := client.NewKuberanClient(server.URL, "stale-key", server.Client())
+		orc := NewOracle(kc, []provider.Provider{probing("yahoo", nil), probing("coingecko", errors.New("connection refused"))},
+			newMYRConverter(), defaultConfig(false), newTestLogger())
+
+		report := orc.Check(context.Background())
+
+		if report.OK {
+			t.Fatal("expected the preflight to fail")
+		}
+		api, yahoo, coingecko := report.Checks[0], report.Checks[1], report.Checks[2]
+		if api.OK || !strings.Contains(api.Error, "API key rejected") {
+			t.Errorf("expected the API check to report the rejected key, got %+v", api)
+		}
+		if !yahoo.OK {
+			t.Errorf("expected yahoo to pass, got %+v", yahoo)
+		}
+		if coingecko.OK || coingecko.Error != "connection refused" {
+			t.Errorf("expected coingecko to fail, got %+v", coingecko)
+		}
+
+		data, err := json.Marshal(report)
+		if err != nil {
+			t.Fatalf("marshal report: %v", err)
+		}
+		if !strings.Contains(string(data), `"ok":false`) || !strings.Contains(string(data), `"name":"provider:coingecko"`) {
+			t.Errorf("unexpected report JSON: %s", data)
+		}
+	})
+
+	t.Run("unreachable_api", func(t *testing.T) {
+		server := newPipelineServer(t, "good-key")
+		url := server.URL
+		server.Close()
+		kc := client.NewKuberanClient(url, "good-key", http.DefaultClient)
+		orc := NewOracle(kc, nil, newMYRConverter(), defaultConfig(false), newTestLogger())
+
+		report := orc.Check(context.Background())
+
+		if report.OK || len(report.Checks) != 1 || !strings.Contains(report.Checks[0].Error, "reaching API") {
+			t.Errorf("expected the API check to fail to connect, got %+v", report)
+		}
+	})
+}
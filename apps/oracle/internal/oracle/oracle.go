@@ -18,7 +18,8 @@ import (
 type SecurityClient interface {
 	GetSecurities(ctx context.Context) ([]client.Security, error)
 	RecordPrices(ctx context.Context, prices []client.RecordPriceEntry) (int, error)
-	ComputeSnapshots(ctx context.Context) (int, error)
+	ComputeSnapshots(ctx context.Context, asOf *time.Time) (int, error)
+	CheckAccess(ctx context.Context) error
 }
 
 // CurrencyConverter converts prices from one currency to the target currency.
@@ -31,11 +32,29 @@ type CurrencyConverter interface {
 	TargetCurrency() string
 }
 
+// SecurityReport records what happened to a single security during a run.
+type SecurityReport struct {
+	SecurityID        string     `json:"security_id"`
+	Symbol            string     `json:"symbol"`
+	AssetType         string     `json:"asset_type"`
+	Provider          string     `json:"provider,omitempty"`
+	SourcePrice       int64      `json:"source_price,omitempty"`    // cents as reported by the provider
+	SourceCurrency    string     `json:"source_currency,omitempty"` // currency reported by the provider
+	Price             int64      `json:"price,omitempty"`           // cents sent (or, in dry-run, to be sent) to Kuberan
+	Currency          string     `json:"currency,omitempty"`
+	ConversionApplied bool       `json:"conversion_applied"`
+	RecordedAt        *time.Time `json:"recorded_at,omitempty"`
+	Error             string     `json:"error,omitempty"`
+}
+
 // RunResult contains the outcome of an oracle run.
 type RunResult struct {
+	DryRun            bool
+	StartedAt         time.Time
 	SecuritiesFetched int
 	PricesRecorded    int
 	SnapshotsRecorded int
+	Securities        []SecurityReport
 	Errors            []provider.FetchError
 	Duration          time.Duration
 }
@@ -70,10 +89,52 @@ func normalizeAssetType(assetType string) string {
 	return t
 }
 
+// selectProvider returns the index of the provider to fetch sec from, or -1 if
+// none supports it. A provider that claims the security itself (e.g. by exchange)
+// takes priority; otherwise the first provider supporting its asset type wins.
+// Providers implementing provider.SecuritySupporter are only used for securities
+// they claim.
+func (o *Oracle) selectProvider(sec provider.Security) int {
+	fallback := -1
+	for i, p := range o.providers {
+		if sp, ok := p.(provider.SecuritySupporter); ok {
+			if sp.SupportsSecurity(sec) {
+				return i
+			}
+			continue
+		}
+		if fallback < 0 && p.Supports(sec.AssetType) {
+			fallback = i
+		}
+	}
+	return fallback
+}
+
+// selectPreferredProvider returns the index of the provider sec.PreferredProvider
+// names. Unlike selectProvider there is no fallback: if that provider is not
+// configured or cannot fetch the security, the security is not fetched at all.
+func (o *Oracle) selectPreferredProvider(sec provider.Security) (int, error) {
+	for i, p := range o.providers {
+		if !strings.EqualFold(p.Name(), sec.PreferredProvider) && !strings.EqualFold(p.Source(), sec.PreferredProvider) {
+			continue
+		}
+		supported := p.Supports(sec.AssetType)
+		if sp, ok := p.(provider.SecuritySupporter); ok {
+			supported = sp.SupportsSecurity(sec)
+		}
+		if !supported {
+			return -1, fmt.Errorf("preferred provider %s does not support %s %q", p.Name(), sec.AssetType, sec.Symbol)
+		}
+		return i, nil
+	}
+	return -1, fmt.Errorf("preferred provider %q is not configured", sec.PreferredProvider)
+}
+
 // Run executes a single oracle cycle: fetch securities, get prices, record results.
+// In dry-run mode prices are fetched and converted but nothing is written to Kuberan.
 func (o *Oracle) Run(ctx context.Context) (*RunResult, error) {
 	start := time.Now()
-	result := &RunResult{}
+	result := &RunResult{DryRun: o.config.DryRun, StartedAt: start.UTC()}
 
 	// 1. Fetch securities from Kuberan API.
 	securities, err := o.client.GetSecurities(ctx)
@@ -88,34 +149,53 @@ func (o *Oracle) Run(ctx context.Context) (*RunResult, error) {
 		return result, nil
 	}
 
-	// 2. Convert to provider types.
+	// 2. Convert to provider types and start a report entry for each security.
 	providerSecurities := make([]provider.Security, len(securities))
+	result.Securities = make([]SecurityReport, len(securities))
+	reports := make(map[string]*SecurityReport, len(securities)) // security ID -> report entry
 	for i, s := range securities {
 		providerSecurities[i] = provider.Security{
-			ID:             s.ID,
-			Symbol:         s.Symbol,
-			AssetType:      normalizeAssetType(s.AssetType),
-			Exchange:       s.Exchange,
-			ProviderSymbol: s.ProviderSymbol,
-			Network:        s.Network,
-			Currency:       s.Currency,
+			ID:                s.ID,
+			Symbol:            s.Symbol,
+			AssetType:         normalizeAssetType(s.AssetType),
+			Exchange:          s.Exchange,
+			ProviderSymbol:    s.ProviderSymbol,
+			PreferredProvider: s.PreferredProvider,
+			Network:           s.Network,
+			Currency:          s.Currency,
 		}
+		result.Securities[i] = SecurityReport{
+			SecurityID: s.ID,
+			Symbol:     s.Symbol,
+			AssetType:  providerSecurities[i].AssetType,
+		}
+		reports[s.ID] = &result.Securities[i]
 	}
 
-	// 3. Group by provider.
+	// 3. Group by provider. A preferred provider replaces the default routing.
 	groups := make(map[int][]provider.Security) // provider index -> securities
 	for _, sec := range providerSecurities {
-		matched := false
-		for i, p := range o.providers {
-			if p.Supports(sec.AssetType) {
-				groups[i] = append(groups[i], sec)
-				matched = true
-				break
+		if sec.PreferredProvider != "" {
+			i, err := o.selectPreferredProvider(sec)
+			if err != nil {
+				o.logger.Warn("preferred provider unavailable", "symbol", sec.Symbol, "preferred_provider", sec.PreferredProvider, "error", err)
+				reports[sec.ID].Error = err.Error()
+				result.Errors = append(result.Errors, provider.FetchError{SecurityID: sec.ID, Symbol: sec.Symbol, Err: err})
+				continue
 			}
+			groups[i] = append(groups[i], sec)
+			reports[sec.ID].Provider = o.providers[i].Name()
+			continue
 		}
-		if !matched {
+
+		i := o.selectProvider(sec)
+		if i < 0 {
 			o.logger.Warn("no provider supports asset type", "symbol", sec.Symbol, "asset_type", sec.AssetType)
+			reports[sec.ID].Error = fmt.Sprintf("no provider supports asset type %q", sec.AssetType)
+			continue
 		}
+		groups[i] = append(groups[i], sec)
+		reports[sec.ID].Provider = o.providers[i].Name()
 	}
 
 	// 4. Fetch prices from each provider concurrently.
@@ -130,6 +210,9 @@ func (o *Oracle) Run(ctx context.Context) (*RunResult, error) {
 			defer wg.Done()
 			o.logger.Info("fetching prices", "provider", p.Name(), "count", len(securities))
 			prices, fetchErrors := p.FetchPrices(ctx, securities)
+			for j := range prices {
+				prices[j].Source = p.Source()
+			}
 			mu.Lock()
 			allResults = append(allResults, prices...)
 			allErrors = append(allErrors, fetchErrors...)
@@ -138,7 +221,12 @@ func (o *Oracle) Run(ctx context.Context) (*RunResult, error) {
 	}
 	wg.Wait()
 
-	result.Errors = allErrors
+	result.Errors = append(result.Errors, allErrors...)
+	for _, fe := range allErrors {
+		if rep, ok := reports[fe.SecurityID]; ok && fe.Err != nil {
+			rep.Error = fe.Err.Error()
+		}
+	}
 
 	// 5. If no prices fetched, return early.
 	if len(allResults) == 0 {
@@ -151,6 +239,14 @@ func (o *Oracle) Run(ctx context.Context) (*RunResult, error) {
 	// reported by each data source (e.g. Yahoo returns "USD" for NASDAQ stocks).
 	var convertedResults []provider.PriceResult
 	for _, r := range allResults {
+		rep := reports[r.SecurityID]
+		if rep != nil {
+			recordedAt := r.RecordedAt
+			rep.SourcePrice = r.Price
+			rep.SourceCurrency = r.Currency
+			rep.RecordedAt = &recordedAt
+		}
+
 		if o.converter != nil && o.converter.NeedsConversion(r.Currency) {
 			converted, err := o.converter.Convert(ctx, r.Price, r.Currency)
 			if err != nil {
@@ -160,11 +256,15 @@ func (o *Oracle) Run(ctx context.Context) (*RunResult, error) {
 					"target", o.converter.TargetCurrency(),
 					"error", err,
 				)
-				result.Errors = append(result.Errors, provider.FetchError{
+				convErr := provider.FetchError{
 					SecurityID: r.SecurityID,
 					Symbol:     fmt.Sprintf("id:%s", r.SecurityID),
 					Err:        fmt.Errorf("currency conversion from %s to %s: %w", r.Currency, o.converter.TargetCurrency(), err),
-				})
+				}
+				if rep != nil {
+					rep.Error = convErr.Err.Error()
+				}
+				result.Errors = append(result.Errors, convErr)
 				continue
 			}
 			o.logger.Debug("converted price",
@@ -174,6 +274,16 @@ func (o *Oracle) Run(ctx context.Context) (*RunResult, error) {
 				"converted_cents", converted,
 			)
 			r.Price = converted
+			r.Currency = o.converter.TargetCurrency()
+			if rep != nil {
+				rep.ConversionApplied = true
+				rep.Currency = o.converter.TargetCurrency()
+			}
+		} else if rep != nil {
+			rep.Currency = r.Currency
+		}
+		if rep != nil {
+			rep.Price = r.Price
 		}
 		convertedResults = append(convertedResults, r)
 	}
@@ -191,9 +301,24 @@ func (o *Oracle) Run(ctx context.Context) (*RunResult, error) {
 			SecurityID: r.SecurityID,
 			Price:      r.Price,
 			RecordedAt: r.RecordedAt.Format(time.RFC3339),
+			Source:     r.Source,
+			Currency:   strings.ToUpper(r.Currency),
 		}
 	}
 
+	if o.config.DryRun {
+		for _, e := range entries {
+			o.logger.Info("dry run: would record price",
+				"security_id", e.SecurityID,
+				"price", e.Price,
+				"recorded_at", e.RecordedAt,
+			)
+		}
+		o.logger.Info("dry run: skipping price recording and snapshots", "prices", len(entries))
+		result.Duration = time.Since(start)
+		return result, nil
+	}
+
 	recorded, err := o.client.RecordPrices(ctx, entries)
 	if err != nil {
 		return nil, err
@@ -202,7 +327,7 @@ func (o *Oracle) Run(ctx context.Context) (*RunResult, error) {
 
 	// 7. Trigger snapshots if configured.
 	if o.config.ComputeSnapshots {
-		snapshots, err := o.client.ComputeSnapshots(ctx)
+		snapshots, err := o.client.ComputeSnapshots(ctx, o.config.SnapshotAsOf)
 		if err != nil {
 			o.logger.Warn("failed to compute snapshots", "error", err)
 		} else {
@@ -2,11 +2,15 @@ package oracle
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -19,7 +23,8 @@ import (
 type mockClient struct {
 	getSecuritiesFn    func(ctx context.Context) ([]client.Security, error)
 	recordPricesFn     func(ctx context.Context, prices []client.RecordPriceEntry) (int, error)
-	computeSnapshotsFn func(ctx context.Context) (int, error)
+	computeSnapshotsFn func(ctx context.Context, asOf *time.Time) (int, error)
+	checkAccessFn      func(ctx context.Context) error
 }
 
 func (m *mockClient) GetSecurities(ctx context.Context) ([]client.Security, error) {
@@ -30,25 +35,45 @@ func (m *mockClient) RecordPrices(ctx context.Context, prices []client.RecordPri
 	return m.recordPricesFn(ctx, prices)
 }
 
-func (m *mockClient) ComputeSnapshots(ctx context.Context) (int, error) {
-	return m.computeSnapshotsFn(ctx)
+func (m *mockClient) ComputeSnapshots(ctx context.Context, asOf *time.Time) (int, error) {
+	return m.computeSnapshotsFn(ctx, asOf)
+}
+
+func (m *mockClient) CheckAccess(ctx context.Context) error {
+	if m.checkAccessFn == nil {
+		return nil
+	}
+	return m.checkAccessFn(ctx)
 }
 
 // mockProvider implements provider.Provider for testing.
 type mockProvider struct {
 	name        string
+	source      string
 	supports    func(assetType string) bool
 	fetchPrices func(ctx context.Context, securities []provider.Security) ([]provider.PriceResult, []provider.FetchError)
 }
 
 func (m *mockProvider) Name() string { return m.name }
 
+func (m *mockProvider) Source() string { return m.source }
+
 func (m *mockProvider) Supports(assetType string) bool { return m.supports(assetType) }
 
 func (m *mockProvider) FetchPrices(ctx context.Context, securities []provider.Security) ([]provider.PriceResult, []provider.FetchError) {
 	return m.fetchPrices(ctx, securities)
 }
 
+// mockExchangeProvider is a mockProvider that also implements provider.SecuritySupporter.
+type mockExchangeProvider struct {
+	mockProvider
+	supportsSecurity func(sec provider.Security) bool
+}
+
+func (m *mockExchangeProvider) SupportsSecurity(sec provider.Security) bool {
+	return m.supportsSecurity(sec)
+}
+
 // mockConverter implements CurrencyConverter for testing.
 type mockConverter struct {
 	target          string
@@ -119,7 +144,7 @@ func TestOracle_Run_FullFlow(t *testing.T) {
 			recordedPrices = prices
 			return len(prices), nil
 		},
-		computeSnapshotsFn: func(_ context.Context) (int, error) {
+		computeSnapshotsFn: func(_ context.Context, _ *time.Time) (int, error) {
 			snapshotsCalled = true
 			return 3, nil
 		},
@@ -129,6 +154,7 @@ func TestOracle_Run_FullFlow(t *testing.T) {
 	var providerSymbolSeen bool
 	yahooProvider := &mockProvider{
 		name:     "Yahoo Finance",
+		source:   "yahoo",
 		supports: func(at string) bool { return at == "stock" || at == "etf" || at == "reit" || at == "bond" },
 		fetchPrices: func(_ context.Context, secs []provider.Security) ([]provider.PriceResult, []provider.FetchError) {
 			results := make([]provider.PriceResult, len(secs))
@@ -150,6 +176,7 @@ func TestOracle_Run_FullFlow(t *testing.T) {
 	// CoinGecko returns prices directly in MYR (target currency).
 	geckoProvider := &mockProvider{
 		name:     "CoinGecko",
+		source:   "coingecko",
 		supports: func(at string) bool { return at == "crypto" },
 		fetchPrices: func(_ context.Context, secs []provider.Security) ([]provider.PriceResult, []provider.FetchError) {
 			results := make([]provider.PriceResult, len(secs))
@@ -220,6 +247,24 @@ func TestOracle_Run_FullFlow(t *testing.T) {
 			}
 		}
 	}
+
+	// Each price carries the currency it ended up in, so Kuberan converts from MYR.
+	for _, p := range recordedPrices {
+		if p.Currency != "MYR" {
+			t.Errorf("%s currency = %q, want MYR", p.SecurityID, p.Currency)
+		}
+	}
+
+	// Each price carries the source of the provider that fetched it.
+	for _, p := range recordedPrices {
+		want := "yahoo"
+		if p.SecurityID == "sec-4" || p.SecurityID == "sec-5" {
+			want = "coingecko"
+		}
+		if p.Source != want {
+			t.Errorf("%s source = %q, want %q", p.SecurityID, p.Source, want)
+		}
+	}
 }
 
 func TestOracle_Run_PartialProviderFailure(t *testing.T) {
@@ -238,7 +283,7 @@ func TestOracle_Run_PartialProviderFailure(t *testing.T) {
 		recordPricesFn: func(_ context.Context, prices []client.RecordPriceEntry) (int, error) {
 			return len(prices), nil
 		},
-		computeSnapshotsFn: func(_ context.Context) (int, error) {
+		computeSnapshotsFn: func(_ context.Context, _ *time.Time) (int, error) {
 			return 2, nil
 		},
 	}
@@ -300,7 +345,7 @@ func TestOracle_Run_NoSecurities(t *testing.T) {
 			t.Error("RecordPrices should not be called")
 			return 0, nil
 		},
-		computeSnapshotsFn: func(_ context.Context) (int, error) {
+		computeSnapshotsFn: func(_ context.Context, _ *time.Time) (int, error) {
 			t.Error("ComputeSnapshots should not be called")
 			return 0, nil
 		},
@@ -377,7 +422,7 @@ func TestOracle_Run_MixedCaseAssetTypes(t *testing.T) {
 			recordedPrices = prices
 			return len(prices), nil
 		},
-		computeSnapshotsFn: func(_ context.Context) (int, error) {
+		computeSnapshotsFn: func(_ context.Context, _ *time.Time) (int, error) {
 			return 1, nil
 		},
 	}
@@ -437,7 +482,7 @@ func TestOracle_Run_UnsupportedAssetType(t *testing.T) {
 			t.Error("RecordPrices should not be called when no prices fetched")
 			return 0, nil
 		},
-		computeSnapshotsFn: func(_ context.Context) (int, error) {
+		computeSnapshotsFn: func(_ context.Context, _ *time.Time) (int, error) {
 			t.Error("ComputeSnapshots should not be called when no prices fetched")
 			return 0, nil
 		},
@@ -467,6 +512,247 @@ func TestOracle_Run_UnsupportedAssetType(t *testing.T) {
 	}
 }
 
+func TestOracle_Run_ExchangeProviderPriority(t *testing.T) {
+	now := time.Now().UTC()
+
+	mc := &mockClient{
+		getSecuritiesFn: func(_ context.Context) ([]client.Security, error) {
+			return []client.Security{
+				{ID: "sec-1", Symbol: "CIMB", AssetType: "stock", Exchange: "BURSA", ProviderSymbol: "1023.KL"},
+				{ID: "sec-2", Symbol: "AAPL", AssetType: "stock", Exchange: "NASDAQ"},
+				{ID: "sec-3", Symbol: "MYETF", AssetType: "etf", Exchange: "BURSA"},
+			}, nil
+		},
+		recordPricesFn: func(_ context.Context, prices []client.RecordPriceEntry) (int, error) {
+			return len(prices), nil
+		},
+		computeSnapshotsFn: func(_ context.Context, _ *time.Time) (int, error) { return 0, nil },
+	}
+
+	var mu sync.Mutex
+	fetched := make(map[string][]string) // provider name -> security IDs
+	fetchAll := func(name string) func(context.Context, []provider.Security) ([]provider.PriceResult, []provider.FetchError) {
+		return func(_ context.Context, secs []provider.Security) ([]provider.PriceResult, []provider.FetchError) {
+			mu.Lock()
+			defer mu.Unlock()
+			results := make([]provider.PriceResult, len(secs))
+			for i, s := range secs {
+				fetched[name] = append(fetched[name], s.ID)
+				results[i] = provider.PriceResult{SecurityID: s.ID, Price: 100, Currency: "MYR", RecordedAt: now}
+			}
+			return results, nil
+		}
+	}
+
+	// Yahoo is listed first and supports every stock, but the Bursa provider claims BURSA stocks.
+	yahooProvider := &mockProvider{
+		name:        "Yahoo Finance",
+		supports:    func(at string) bool { return at == "stock" || at == "etf" },
+		fetchPrices: fetchAll("Yahoo Finance"),
+	}
+	bursaProvider := &mockExchangeProvider{
+		mockProvider: mockProvider{
+			name:        "KLSE Screener",
+			supports:    func(at string) bool { return at == "stock" },
+			fetchPrices: fetchAll("KLSE Screener"),
+		},
+		supportsSecurity: func(sec provider.Security) bool {
+			return sec.AssetType == "stock" && sec.Exchange == "BURSA"
+		},
+	}
+
+	orc := NewOracle(mc, []provider.Provider{yahooProvider, bursaProvider}, nil, defaultConfig(false), newTestLogger())
+	result, err := orc.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := fetched["KLSE Screener"]; len(got) != 1 || got[0] != "sec-1" {
+		t.Errorf("KLSE Screener fetched %v, want [sec-1]", got)
+	}
+	yahoo := strings.Join(fetched["Yahoo Finance"], ",")
+	if strings.Contains(yahoo, "sec-1") {
+		t.Errorf("Yahoo Finance should skip BURSA stocks, fetched %s", yahoo)
+	}
+	if !strings.Contains(yahoo, "sec-2") || !strings.Contains(yahoo, "sec-3") {
+		t.Errorf("Yahoo Finance should fetch the NASDAQ stock and BURSA ETF, fetched %s", yahoo)
+	}
+
+	wantProvider := map[string]string{"sec-1": "KLSE Screener", "sec-2": "Yahoo Finance", "sec-3": "Yahoo Finance"}
+	for _, rep := range result.Securities {
+		if rep.Provider != wantProvider[rep.SecurityID] {
+			t.Errorf("%s: report provider = %q, want %q", rep.SecurityID, rep.Provider, wantProvider[rep.SecurityID])
+		}
+	}
+}
+
+func TestOracle_Run_ExchangeProviderDeclines(t *testing.T) {
+	mc := &mockClient{
+		getSecuritiesFn: func(_ context.Context) ([]client.Security, error) {
+			return []client.Security{
+				{ID: "sec-1", Symbol: "AAPL", AssetType: "stock", Exchange: "NASDAQ"},
+			}, nil
+		},
+		recordPricesFn: func(_ context.Context, _ []client.RecordPriceEntry) (int, error) {
+			t.Error("RecordPrices should not be called when no prices fetched")
+			return 0, nil
+		},
+		computeSnapshotsFn: func(_ context.Context, _ *time.Time) (int, error) { return 0, nil },
+	}
+
+	// The only stock provider is exchange-specific, so a NASDAQ stock is left unrouted.
+	bursaProvider := &mockExchangeProvider{
+		mockProvider: mockProvider{
+			name:     "KLSE Screener",
+			supports: func(at string) bool { return at == "stock" },
+			fetchPrices: func(_ context.Context, _ []provider.Security) ([]provider.PriceResult, []provider.FetchError) {
+				t.Error("FetchPrices should not be called for a declined security")
+				return nil, nil
+			},
+		},
+		supportsSecurity: func(sec provider.Security) bool { return sec.Exchange == "BURSA" },
+	}
+
+	orc := NewOracle(mc, []provider.Provider{bursaProvider}, nil, defaultConfig(true), newTestLogger())
+	result, err := orc.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Securities[0].Error == "" {
+		t.Error("expected the unrouted security to report an error")
+	}
+}
+
+func TestOracle_Run_PreferredProvider(t *testing.T) {
+	now := time.Now().UTC()
+
+	mc := &mockClient{
+		getSecuritiesFn: func(_ context.Context) ([]client.Security, error) {
+			return []client.Security{
+				{ID: "sec-1", Symbol: "AAPL", AssetType: "stock", Exchange: "NASDAQ", PreferredProvider: "CoinGecko"},
+				{ID: "sec-2", Symbol: "BTC", AssetType: "crypto", PreferredProvider: "CoinGecko"},
+				{ID: "sec-3", Symbol: "MSFT", AssetType: "stock", Exchange: "NASDAQ"},
+				{ID: "sec-4", Symbol: "ETH", AssetType: "crypto", PreferredProvider: "coingecko"},
+				{ID: "sec-5", Symbol: "GOOG", AssetType: "stock", PreferredProvider: "Alpha Vantage"},
+			}, nil
+		},
+		recordPricesFn: func(_ context.Context, prices []client.RecordPriceEntry) (int, error) {
+			return len(prices), nil
+		},
+		computeSnapshotsFn: func(_ context.Context, _ *time.Time) (int, error) { return 0, nil },
+	}
+
+	var mu sync.Mutex
+	fetched := make(map[string][]string) // provider name -> security IDs
+	fetchAll := func(name string) func(context.Context, []provider.Security) ([]provider.PriceResult, []provider.FetchError) {
+		return func(_ context.Context, secs []provider.Security) ([]provider.PriceResult, []provider.FetchError) {
+			mu.Lock()
+			defer mu.Unlock()
+			results := make([]provider.PriceResult, len(secs))
+			for i, s := range secs {
+				fetched[name] = append(fetched[name], s.ID)
+				results[i] = provider.PriceResult{SecurityID: s.ID, Price: 100, Currency: "USD", RecordedAt: now}
+			}
+			return results, nil
+		}
+	}
+
+	// Yahoo is listed first and claims to support every asset type here
+	yahooProvider := &mockProvider{
+		name:        "Yahoo Finance",
+		source:      "yahoo",
+		supports:    func(at string) bool { return at == "stock" || at == "crypto" },
+		fetchPrices: fetchAll("Yahoo Finance"),
+	}
+	coingeckoProvider := &mockProvider{
+		name:        "CoinGecko",
+		source:      "coingecko",
+		supports:    func(at string) bool { return at == "crypto" },
+		fetchPrices: fetchAll("CoinGecko"),
+	}
+
+	orc := NewOracle(mc, []provider.Provider{yahooProvider, coingeckoProvider}, nil, defaultConfig(false), newTestLogger())
+	result, err := orc.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	yahoo := strings.Join(fetched["Yahoo Finance"], ",")
+	if yahoo != "sec-3" {
+		t.Errorf("Yahoo Finance fetched %s, want only sec-3", yahoo)
+	}
+	coingecko := strings.Join(fetched["CoinGecko"], ",")
+	if !strings.Contains(coingecko, "sec-2") || !strings.Contains(coingecko, "sec-4") || strings.Contains(coingecko, "sec-1") {
+		t.Errorf("CoinGecko fetched %s, want sec-2 and sec-4", coingecko)
+	}
+	if result.PricesRecorded != 3 {
+		t.Errorf("PricesRecorded = %d, want 3", result.PricesRecorded)
+	}
+
+	failed := make(map[string]bool)
+	for _, fe := range result.Errors {
+		failed[fe.SecurityID] = true
+	}
+	if len(result.Errors) != 2 || !failed["sec-1"] || !failed["sec-5"] {
+		t.Errorf("expected fetch errors for sec-1 and sec-5, got %+v", result.Errors)
+	}
+	for _, rep := range result.Securities {
+		switch rep.SecurityID {
+		case "sec-1", "sec-5":
+			if rep.Error == "" || rep.Provider != "" {
+				t.Errorf("%s: expected an error and no provider, got %+v", rep.SecurityID, rep)
+			}
+		case "sec-2", "sec-4":
+			if rep.Provider != "CoinGecko" {
+				t.Errorf("%s: report provider = %q, want CoinGecko", rep.SecurityID, rep.Provider)
+			}
+		}
+	}
+}
+
+func TestOracle_Run_PreferredProviderOverridesExchangeClaim(t *testing.T) {
+	mc := &mockClient{
+		getSecuritiesFn: func(_ context.Context) ([]client.Security, error) {
+			return []client.Security{
+				{ID: "sec-1", Symbol: "CIMB", AssetType: "stock", Exchange: "BURSA", ProviderSymbol: "1023.KL", PreferredProvider: "Yahoo Finance"},
+			}, nil
+		},
+		recordPricesFn:     func(_ context.Context, prices []client.RecordPriceEntry) (int, error) { return len(prices), nil },
+		computeSnapshotsFn: func(_ context.Context, _ *time.Time) (int, error) { return 0, nil },
+	}
+
+	var yahooFetched []string
+	yahooProvider := &mockProvider{
+		name:     "Yahoo Finance",
+		supports: func(at string) bool { return at == "stock" },
+		fetchPrices: func(_ context.Context, secs []provider.Security) ([]provider.PriceResult, []provider.FetchError) {
+			for _, s := range secs {
+				yahooFetched = append(yahooFetched, s.ID)
+			}
+			return nil, nil
+		},
+	}
+	bursaProvider := &mockExchangeProvider{
+		mockProvider: mockProvider{
+			name:     "KLSE Screener",
+			supports: func(at string) bool { return at == "stock" },
+			fetchPrices: func(_ context.Context, _ []provider.Security) ([]provider.PriceResult, []provider.FetchError) {
+				t.Error("KLSE Screener should not fetch a security preferring Yahoo Finance")
+				return nil, nil
+			},
+		},
+		supportsSecurity: func(sec provider.Security) bool { return sec.Exchange == "BURSA" },
+	}
+
+	orc := NewOracle(mc, []provider.Provider{bursaProvider, yahooProvider}, nil, defaultConfig(false), newTestLogger())
+	if _, err := orc.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(yahooFetched) != 1 || yahooFetched[0] != "sec-1" {
+		t.Errorf("Yahoo Finance fetched %v, want [sec-1]", yahooFetched)
+	}
+}
+
 func TestOracle_Run_GetSecuritiesFails(t *testing.T) {
 	mc := &mockClient{
 		getSecuritiesFn: func(_ context.Context) ([]client.Security, error) {
@@ -475,7 +761,7 @@ func TestOracle_Run_GetSecuritiesFails(t *testing.T) {
 		recordPricesFn: func(_ context.Context, _ []client.RecordPriceEntry) (int, error) {
 			return 0, nil
 		},
-		computeSnapshotsFn: func(_ context.Context) (int, error) {
+		computeSnapshotsFn: func(_ context.Context, _ *time.Time) (int, error) {
 			return 0, nil
 		},
 	}
@@ -505,7 +791,7 @@ func TestOracle_Run_RecordPricesFails(t *testing.T) {
 		recordPricesFn: func(_ context.Context, _ []client.RecordPriceEntry) (int, error) {
 			return 0, errors.New("server error")
 		},
-		computeSnapshotsFn: func(_ context.Context) (int, error) {
+		computeSnapshotsFn: func(_ context.Context, _ *time.Time) (int, error) {
 			t.Error("ComputeSnapshots should not be called when RecordPrices fails")
 			return 0, nil
 		},
@@ -546,7 +832,7 @@ func TestOracle_Run_SnapshotFailureNonFatal(t *testing.T) {
 		recordPricesFn: func(_ context.Context, prices []client.RecordPriceEntry) (int, error) {
 			return len(prices), nil
 		},
-		computeSnapshotsFn: func(_ context.Context) (int, error) {
+		computeSnapshotsFn: func(_ context.Context, _ *time.Time) (int, error) {
 			return 0, errors.New("snapshot service unavailable")
 		},
 	}
@@ -588,7 +874,7 @@ func TestOracle_Run_SnapshotsDisabled(t *testing.T) {
 		recordPricesFn: func(_ context.Context, prices []client.RecordPriceEntry) (int, error) {
 			return len(prices), nil
 		},
-		computeSnapshotsFn: func(_ context.Context) (int, error) {
+		computeSnapshotsFn: func(_ context.Context, _ *time.Time) (int, error) {
 			snapshotsCalled = true
 			return 1, nil
 		},
@@ -620,3 +906,209 @@ func TestOracle_Run_SnapshotsDisabled(t *testing.T) {
 		t.Errorf("SnapshotsRecorded = %d, want 0", result.SnapshotsRecorded)
 	}
 }
+
+func TestOracle_Run_DryRunSkipsWrites(t *testing.T) {
+	now := time.Now().UTC()
+
+	mc := &mockClient{
+		getSecuritiesFn: func(_ context.Context) ([]client.Security, error) {
+			return []client.Security{
+				{ID: "sec-1", Symbol: "AAPL", AssetType: "stock", Currency: "USD"},
+				{ID: "sec-2", Symbol: "BTC", AssetType: "crypto", Currency: "MYR"},
+			}, nil
+		},
+		recordPricesFn: func(_ context.Context, _ []client.RecordPriceEntry) (int, error) {
+			t.Error("RecordPrices should not be called in dry-run mode")
+			return 0, nil
+		},
+		computeSnapshotsFn: func(_ context.Context, _ *time.Time) (int, error) {
+			t.Error("ComputeSnapshots should not be called in dry-run mode")
+			return 0, nil
+		},
+	}
+
+	mp := &mockProvider{
+		name:     "Test",
+		supports: func(_ string) bool { return true },
+		fetchPrices: func(_ context.Context, secs []provider.Security) ([]provider.PriceResult, []provider.FetchError) {
+			results := make([]provider.PriceResult, len(secs))
+			for i, s := range secs {
+				results[i] = provider.PriceResult{SecurityID: s.ID, Price: 10000, Currency: s.Currency, RecordedAt: now}
+			}
+			return results, nil
+		},
+	}
+
+	cfg := defaultConfig(true)
+	cfg.DryRun = true
+	orc := NewOracle(mc, []provider.Provider{mp}, newMYRConverter(), cfg, newTestLogger())
+	result, err := orc.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.DryRun {
+		t.Error("DryRun should be set on the result")
+	}
+	if result.PricesRecorded != 0 {
+		t.Errorf("PricesRecorded = %d, want 0", result.PricesRecorded)
+	}
+	if result.SnapshotsRecorded != 0 {
+		t.Errorf("SnapshotsRecorded = %d, want 0", result.SnapshotsRecorded)
+	}
+	if len(result.Securities) != 2 {
+		t.Fatalf("Securities = %d, want 2", len(result.Securities))
+	}
+	if result.Securities[0].Price != 44700 {
+		t.Errorf("AAPL would-be price = %d, want 44700", result.Securities[0].Price)
+	}
+}
+
+func TestOracle_Run_SecurityReports(t *testing.T) {
+	now := time.Now().UTC()
+
+	mc := &mockClient{
+		getSecuritiesFn: func(_ context.Context) ([]client.Security, error) {
+			return []client.Security{
+				{ID: "sec-1", Symbol: "AAPL", AssetType: "stock", Currency: "USD"},
+				{ID: "sec-2", Symbol: "CIMB", AssetType: "stock", Currency: "MYR"},
+				{ID: "sec-3", Symbol: "FAIL", AssetType: "stock", Currency: "USD"},
+				{ID: "sec-4", Symbol: "GOLD", AssetType: "commodity", Currency: "USD"},
+			}, nil
+		},
+		recordPricesFn: func(_ context.Context, prices []client.RecordPriceEntry) (int, error) {
+			return len(prices), nil
+		},
+		computeSnapshotsFn: func(_ context.Context, _ *time.Time) (int, error) {
+			return 0, nil
+		},
+	}
+
+	mp := &mockProvider{
+		name:     "Yahoo Finance",
+		supports: func(at string) bool { return at == "stock" },
+		fetchPrices: func(_ context.Context, secs []provider.Security) ([]provider.PriceResult, []provider.FetchError) {
+			var results []provider.PriceResult
+			var errs []provider.FetchError
+			for _, s := range secs {
+				if s.Symbol == "FAIL" {
+					errs = append(errs, provider.FetchError{SecurityID: s.ID, Symbol: s.Symbol, Err: errors.New("not found")})
+					continue
+				}
+				results = append(results, provider.PriceResult{SecurityID: s.ID, Price: 10000, Currency: s.Currency, RecordedAt: now})
+			}
+			return results, errs
+		},
+	}
+
+	orc := NewOracle(mc, []provider.Provider{mp}, newMYRConverter(), defaultConfig(false), newTestLogger())
+	result, err := orc.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	byID := make(map[string]SecurityReport)
+	for _, r := range result.Securities {
+		byID[r.SecurityID] = r
+	}
+
+	aapl := byID["sec-1"]
+	if !aapl.ConversionApplied || aapl.SourcePrice != 10000 || aapl.SourceCurrency != "USD" || aapl.Price != 44700 || aapl.Currency != "MYR" {
+		t.Errorf("unexpected AAPL report: %+v", aapl)
+	}
+	if aapl.Provider != "Yahoo Finance" || aapl.RecordedAt == nil {
+		t.Errorf("expected provider and recorded_at on AAPL report: %+v", aapl)
+	}
+
+	cimb := byID["sec-2"]
+	if cimb.ConversionApplied || cimb.Price != 10000 || cimb.Currency != "MYR" {
+		t.Errorf("unexpected CIMB report: %+v", cimb)
+	}
+
+	if byID["sec-3"].Error != "not found" {
+		t.Errorf("FAIL error = %q, want %q", byID["sec-3"].Error, "not found")
+	}
+	if byID["sec-4"].Error == "" {
+		t.Error("expected an error on the unsupported GOLD security")
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	result := &RunResult{
+		DryRun:            true,
+		StartedAt:         now,
+		SecuritiesFetched: 1,
+		Securities: []SecurityReport{
+			{SecurityID: "sec-1", Symbol: "AAPL", AssetType: "stock", Price: 44700, Currency: "MYR", ConversionApplied: true, RecordedAt: &now},
+		},
+		Errors:   []provider.FetchError{{SecurityID: "sec-2", Symbol: "FAIL", Err: errors.New("not found")}},
+		Duration: 1500 * time.Millisecond,
+	}
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := WriteReport(path, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading report: %v", err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("report is not valid JSON: %v", err)
+	}
+
+	if got["dry_run"] != true {
+		t.Errorf("dry_run = %v, want true", got["dry_run"])
+	}
+	if got["duration_ms"].(float64) != 1500 {
+		t.Errorf("duration_ms = %v, want 1500", got["duration_ms"])
+	}
+	secs := got["securities"].([]interface{})
+	if len(secs) != 1 || secs[0].(map[string]interface{})["conversion_applied"] != true {
+		t.Errorf("unexpected securities: %v", secs)
+	}
+	errs := got["errors"].([]interface{})
+	if len(errs) != 1 || !strings.Contains(errs[0].(string), "not found") {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestNewRunRecord(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("completed run", func(t *testing.T) {
+		result := &RunResult{
+			StartedAt:         start,
+			SecuritiesFetched: 2,
+			PricesRecorded:    1,
+			Errors:            []provider.FetchError{{SecurityID: "sec-2", Symbol: "FAIL", Err: errors.New("not found")}},
+			Duration:          1500 * time.Millisecond,
+		}
+
+		record := NewRunRecord(result, nil, start, "1.4.0")
+
+		if record.Failed || record.Error != "" {
+			t.Errorf("expected a completed run, got failed=%v error=%q", record.Failed, record.Error)
+		}
+		if record.Version != "1.4.0" || record.DurationMS != 1500 || record.SecuritiesFetched != 2 || record.PricesRecorded != 1 {
+			t.Errorf("unexpected record: %+v", record)
+		}
+		if len(record.Errors) != 1 || record.Errors[0].Symbol != "FAIL" || record.Errors[0].Error != "not found" {
+			t.Errorf("unexpected errors: %+v", record.Errors)
+		}
+	})
+
+	t.Run("failed run", func(t *testing.T) {
+		record := NewRunRecord(nil, errors.New("fetching securities: unexpected status 503"), start, "1.4.0")
+
+		if !record.Failed || record.Error != "fetching securities: unexpected status 503" {
+			t.Errorf("expected a failed run with its error, got failed=%v error=%q", record.Failed, record.Error)
+		}
+		if !record.StartedAt.Equal(start) || record.Errors == nil {
+			t.Errorf("expected the start time and an empty errors list, got %+v", record)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package oracle
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kuberan/oracle/internal/client"
+)
+
+// runReport is the JSON representation of a RunResult written by WriteReport.
+type runReport struct {
+	DryRun            bool             `json:"dry_run"`
+	StartedAt         time.Time        `json:"started_at"`
+	DurationMS        int64            `json:"duration_ms"`
+	SecuritiesFetched int              `json:"securities_fetched"`
+	PricesRecorded    int              `json:"prices_recorded"`
+	SnapshotsRecorded int              `json:"snapshots_recorded"`
+	Securities        []SecurityReport `json:"securities"`
+	Errors            []string         `json:"errors"`
+}
+
+// MarshalJSON renders the run result as a self-contained report suitable for archiving.
+func (r *RunResult) MarshalJSON() ([]byte, error) {
+	report := runReport{
+		DryRun:            r.DryRun,
+		StartedAt:         r.StartedAt,
+		DurationMS:        r.Duration.Milliseconds(),
+		SecuritiesFetched: r.SecuritiesFetched,
+		PricesRecorded:    r.PricesRecorded,
+		SnapshotsRecorded: r.SnapshotsRecorded,
+		Securities:        r.Securities,
+		Errors:            make([]string, len(r.Errors)),
+	}
+	if report.Securities == nil {
+		report.Securities = []SecurityReport{}
+	}
+	for i := range r.Errors {
+		report.Errors[i] = r.Errors[i].Error()
+	}
+	return json.Marshal(report)
+}
+
+// WriteReport writes the run result as indented JSON to path.
+func WriteReport(path string, result *RunResult) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding run report: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing run report: %w", err)
+	}
+	return nil
+}
+
+// NewRunRecord builds the record of a run for Kuberan's run history. result is
+// nil when the run stopped before producing one; a non-nil runErr marks the
+// run failed, with its counts as far as they are known.
+func NewRunRecord(result *RunResult, runErr error, start time.Time, version string) client.RunRecord {
+	record := client.RunRecord{
+		Version:    version,
+		StartedAt:  start.UTC(),
+		DurationMS: time.Since(start).Milliseconds(),
+		Errors:     []client.RunRecordError{},
+	}
+	if runErr != nil {
+		record.Failed = true
+		record.Error = runErr.Error()
+	} else if result == nil {
+		record.Failed = true
+		record.Error = "run did not finish"
+	}
+	if result == nil {
+		return record
+	}
+
+	record.DryRun = result.DryRun
+	record.StartedAt = result.StartedAt
+	record.DurationMS = result.Duration.Milliseconds()
+	record.SecuritiesFetched = result.SecuritiesFetched
+	record.PricesRecorded = result.PricesRecorded
+	record.SnapshotsRecorded = result.SnapshotsRecorded
+	for _, e := range result.Errors {
+		entry := client.RunRecordError{SecurityID: e.SecurityID, Symbol: e.Symbol}
+		if e.Err != nil {
+			entry.Error = e.Err.Error()
+		}
+		record.Errors = append(record.Errors, entry)
+	}
+	return record
+}
@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	bursaBaseURL       = "https://www.klsescreener.com/v2/stocks/view"
+	bursaMaxConcurrent = 4
+	bursaExchange      = "BURSA"
+)
+
+// bursaPricePattern matches the last-done price element on a KLSE Screener stock page.
+var bursaPricePattern = regexp.MustCompile(`<span[^>]*\bid="price"[^>]*>\s*([0-9][0-9,]*(?:\.[0-9]+)?)\s*<`)
+
+// BursaProvider fetches Bursa Malaysia stock prices by scraping KLSE Screener,
+// which tracks the exchange more closely than Yahoo Finance. Prices are in MYR.
+type BursaProvider struct {
+	httpClient    *http.Client
+	baseURL       string // overridable for tests
+	maxConcurrent int    // 0 uses bursaMaxConcurrent
+}
+
+// NewBursaProvider creates a new Bursa Malaysia price provider.
+func NewBursaProvider(httpClient *http.Client) *BursaProvider {
+	return &BursaProvider{httpClient: httpClient, baseURL: bursaBaseURL}
+}
+
+// SetMaxConcurrent overrides how many requests FetchPrices makes at once; 0
+// restores the default of 4.
+func (p *BursaProvider) SetMaxConcurrent(n int) {
+	p.maxConcurrent = n
+}
+
+// Name returns the provider's display name.
+func (p *BursaProvider) Name() string { return "KLSE Screener" }
+
+// Source returns the price source identifier.
+func (p *BursaProvider) Source() string { return "bursa" }
+
+// Probe checks the provider is reachable by fetching the price of Maybank.
+func (p *BursaProvider) Probe(ctx context.Context) error {
+	return probeFetch(ctx, p, Security{ID: "probe", Symbol: "1155", AssetType: "stock", Exchange: bursaExchange})
+}
+
+// Supports returns true for the stock asset type only.
+func (p *BursaProvider) Supports(assetType string) bool {
+	return assetType == "stock"
+}
+
+// SupportsSecurity returns true for stocks listed on Bursa Malaysia.
+func (p *BursaProvider) SupportsSecurity(sec Security) bool {
+	return p.Supports(sec.AssetType) && strings.EqualFold(sec.Exchange, bursaExchange)
+}
+
+// buildBursaCode returns the numeric Bursa stock code for a security. A Yahoo
+// style ProviderSymbol such as "1023.KL" is accepted and its suffix dropped;
+// otherwise the symbol is used as is.
+func buildBursaCode(sec Security) string {
+	if sec.ProviderSymbol != "" {
+		return strings.TrimSuffix(strings.ToUpper(sec.ProviderSymbol), ".KL")
+	}
+	return sec.Symbol
+}
+
+// FetchPrices fetches the last-done price of each security from its KLSE Screener
+// page, with concurrent requests limited by a semaphore.
+func (p *BursaProvider) FetchPrices(ctx context.Context, securities []Security) ([]PriceResult, []FetchError) {
+	if len(securities) == 0 {
+		return nil, nil
+	}
+
+	now := time.Now().UTC()
+	limit := p.maxConcurrent
+	if limit <= 0 {
+		limit = bursaMaxConcurrent
+	}
+	sem := make(chan struct{}, limit)
+
+	var mu sync.Mutex
+	var results []PriceResult
+	var fetchErrors []FetchError
+
+	var wg sync.WaitGroup
+	for _, sec := range securities {
+		wg.Add(1)
+		go func(sec Security) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result, err := p.fetchOne(ctx, buildBursaCode(sec), sec.ID, now)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fetchErrors = append(fetchErrors, FetchError{
+					SecurityID: sec.ID,
+					Symbol:     sec.Symbol,
+					Err:        err,
+				})
+				return
+			}
+			results = append(results, *result)
+		}(sec)
+	}
+	wg.Wait()
+
+	return results, fetchErrors
+}
+
+// fetchOne fetches and parses the stock page for a single Bursa stock code.
+func (p *BursaProvider) fetchOne(ctx context.Context, code string, secID string, now time.Time) (*PriceResult, error) {
+	url := p.baseURL + "/" + code
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("User-Agent", yahooUA)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	match := bursaPricePattern.FindSubmatch(body)
+	if match == nil {
+		return nil, fmt.Errorf("no price found for %s", code)
+	}
+	price, err := strconv.ParseFloat(strings.ReplaceAll(string(match[1]), ",", ""), 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing price %q: %w", match[1], err)
+	}
+	if price == 0 {
+		return nil, fmt.Errorf("zero price for %s", code)
+	}
+
+	return &PriceResult{
+		SecurityID: secID,
+		Price:      int64(math.Round(price * 100)),
+		Currency:   "MYR",
+		RecordedAt: now,
+	}, nil
+}
@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// klseScreenerPage returns a trimmed KLSE Screener stock page with the given last-done price.
+func klseScreenerPage(code, price string) string {
+	return fmt.Sprintf(`<html><body>
+<div class="stock-header"><h1>Stock: [%s]</h1>
+<span id="priceChange" class="green">+0.020</span>
+<span id="price" class="price" data-value="%s">%s</span>
+</div></body></html>`, code, price, price)
+}
+
+// newKLSEMockServer serves canned stock pages keyed by stock code. Unknown codes return 404.
+func newKLSEMockServer(pages map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		code := strings.TrimPrefix(r.URL.Path, "/")
+		page, ok := pages[code]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(page))
+	}))
+}
+
+func TestBursaProvider_Supports(t *testing.T) {
+	p := NewBursaProvider(http.DefaultClient)
+
+	if !p.Supports("stock") {
+		t.Error("expected Supports(\"stock\") = true")
+	}
+	for _, at := range []string{"etf", "crypto", ""} {
+		if p.Supports(at) {
+			t.Errorf("expected Supports(%q) = false", at)
+		}
+	}
+
+	tests := []struct {
+		sec  Security
+		want bool
+	}{
+		{Security{AssetType: "stock", Exchange: "BURSA"}, true},
+		{Security{AssetType: "stock", Exchange: "bursa"}, true},
+		{Security{AssetType: "stock", Exchange: "NASDAQ"}, false},
+		{Security{AssetType: "stock"}, false},
+		{Security{AssetType: "etf", Exchange: "BURSA"}, false},
+	}
+	for _, tt := range tests {
+		if got := p.SupportsSecurity(tt.sec); got != tt.want {
+			t.Errorf("SupportsSecurity(%+v) = %v, want %v", tt.sec, got, tt.want)
+		}
+	}
+}
+
+func TestBuildBursaCode(t *testing.T) {
+	tests := []struct {
+		sec  Security
+		want string
+	}{
+		{Security{Symbol: "CIMB", ProviderSymbol: "1023.KL"}, "1023"},
+		{Security{Symbol: "MAYBANK", ProviderSymbol: "1155"}, "1155"},
+		{Security{Symbol: "5347"}, "5347"},
+	}
+	for _, tt := range tests {
+		if got := buildBursaCode(tt.sec); got != tt.want {
+			t.Errorf("buildBursaCode(%+v) = %q, want %q", tt.sec, got, tt.want)
+		}
+	}
+}
+
+func TestBursaProvider_FetchPrices_Success(t *testing.T) {
+	server := newKLSEMockServer(map[string]string{
+		"1023": klseScreenerPage("1023", "6.500"),
+		"1155": klseScreenerPage("1155", "10.12"),
+		"5347": klseScreenerPage("5347", "1,234.505"),
+	})
+	defer server.Close()
+
+	p := &BursaProvider{httpClient: server.Client(), baseURL: server.URL}
+	securities := []Security{
+		{ID: "sec-1", Symbol: "CIMB", AssetType: "stock", Exchange: "BURSA", ProviderSymbol: "1023.KL"},
+		{ID: "sec-2", Symbol: "MAYBANK", AssetType: "stock", Exchange: "BURSA", ProviderSymbol: "1155"},
+		{ID: "sec-3", Symbol: "5347", AssetType: "stock", Exchange: "BURSA"},
+	}
+
+	results, fetchErrors := p.FetchPrices(context.Background(), securities)
+	if len(fetchErrors) != 0 {
+		t.Fatalf("expected 0 errors, got %d: %v", len(fetchErrors), fetchErrors)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	expected := map[string]int64{
+		"sec-1": 650,
+		"sec-2": 1012,
+		"sec-3": 123451,
+	}
+	for _, r := range results {
+		if want := expected[r.SecurityID]; r.Price != want {
+			t.Errorf("security %s: got price %d, want %d", r.SecurityID, r.Price, want)
+		}
+		if r.Currency != "MYR" {
+			t.Errorf("security %s: got currency %q, want %q", r.SecurityID, r.Currency, "MYR")
+		}
+	}
+}
+
+func TestBursaProvider_FetchPrices_PartialFailure(t *testing.T) {
+	server := newKLSEMockServer(map[string]string{
+		"1023": klseScreenerPage("1023", "6.500"),
+		"9999": `<html><body>Stock not found</body></html>`,
+	})
+	defer server.Close()
+
+	p := &BursaProvider{httpClient: server.Client(), baseURL: server.URL}
+	securities := []Security{
+		{ID: "sec-1", Symbol: "1023", AssetType: "stock", Exchange: "BURSA"},
+		{ID: "sec-2", Symbol: "9999", AssetType: "stock", Exchange: "BURSA"},
+		{ID: "sec-3", Symbol: "0000", AssetType: "stock", Exchange: "BURSA"},
+	}
+
+	results, fetchErrors := p.FetchPrices(context.Background(), securities)
+	if len(results) != 1 || results[0].SecurityID != "sec-1" {
+		t.Errorf("expected only sec-1 to succeed, got %+v", results)
+	}
+	if len(fetchErrors) != 2 {
+		t.Fatalf("expected 2 errors, got %d", len(fetchErrors))
+	}
+	for _, fe := range fetchErrors {
+		switch fe.SecurityID {
+		case "sec-2":
+			if !strings.Contains(fe.Err.Error(), "no price found") {
+				t.Errorf("sec-2: unexpected error %v", fe.Err)
+			}
+		case "sec-3":
+			if !strings.Contains(fe.Err.Error(), "unexpected status 404") {
+				t.Errorf("sec-3: unexpected error %v", fe.Err)
+			}
+		default:
+			t.Errorf("unexpected error for %s", fe.SecurityID)
+		}
+	}
+}
+
+func TestBursaProvider_FetchPrices_Empty(t *testing.T) {
+	p := NewBursaProvider(http.DefaultClient)
+	results, fetchErrors := p.FetchPrices(context.Background(), nil)
+	if results != nil || fetchErrors != nil {
+		t.Errorf("expected nil results and errors, got %v, %v", results, fetchErrors)
+	}
+}
+
+func TestBursaProvider_Probe(t *testing.T) {
+	server := newKLSEMockServer(map[string]string{"1155": klseScreenerPage("1155", "10.12")})
+	defer server.Close()
+
+	p := &BursaProvider{httpClient: server.Client(), baseURL: server.URL}
+	if err := p.Probe(context.Background()); err != nil {
+		t.Errorf("expected probe to pass, got %v", err)
+	}
+
+	down := &BursaProvider{httpClient: server.Client(), baseURL: server.URL + "/missing"}
+	if err := down.Probe(context.Background()); err == nil {
+		t.Error("expected probe to fail when the page is missing")
+	}
+}
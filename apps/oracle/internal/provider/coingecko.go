@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -31,6 +32,14 @@ func NewCoinGeckoProvider(httpClient *http.Client, targetCurrency string) *CoinG
 // Name returns the provider's display name.
 func (p *CoinGeckoProvider) Name() string { return "CoinGecko" }
 
+// Source returns the price source identifier.
+func (p *CoinGeckoProvider) Source() string { return "coingecko" }
+
+// Probe checks the provider is reachable by fetching the price of Bitcoin.
+func (p *CoinGeckoProvider) Probe(ctx context.Context) error {
+	return probeFetch(ctx, p, Security{ID: "probe", Symbol: "BTC", AssetType: "crypto"})
+}
+
 // Supports returns true for crypto asset type only.
 func (p *CoinGeckoProvider) Supports(assetType string) bool {
 	return assetType == "crypto"
@@ -70,10 +79,12 @@ func (p *CoinGeckoProvider) FetchPrices(ctx context.Context, securities []Securi
 	// Use the configured target currency for all price requests.
 	currency := p.targetCurrency
 
-	// Call CoinGecko simple price API.
-	url := p.baseURL + "?ids=" + strings.Join(ids, ",") + "&vs_currencies=" + currency
+	// Call CoinGecko simple price API once for every mapped coin.
+	query := url.Values{}
+	query.Set("ids", strings.Join(ids, ","))
+	query.Set("vs_currencies", currency)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"?"+query.Encode(), nil)
 	if err != nil {
 		return nil, appendAllErrors(fetchErrors, idToSecs, fmt.Errorf("building request: %w", err))
 	}
@@ -133,6 +133,52 @@ func TestCoinGeckoProvider_FetchPrices_PartialResponse(t *testing.T) {
 	}
 }
 
+func TestCoinGeckoProvider_FetchPrices_BatchesWithUnknownSymbol(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if got := r.URL.Query().Get("ids"); got != "bitcoin,ethereum,solana" {
+			t.Errorf("expected ids=bitcoin,ethereum,solana, got %q", got)
+		}
+		if got := r.URL.Query().Get("vs_currencies"); got != "usd" {
+			t.Errorf("expected vs_currencies=usd, got %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"bitcoin":{"usd":67234.56},"ethereum":{"usd":3456.78},"solana":{"usd":142.5}}`))
+	}))
+	defer server.Close()
+
+	p := NewCoinGeckoProvider(server.Client(), "USD")
+	p.baseURL = server.URL
+	securities := []Security{
+		{ID: "sec-1", Symbol: "BTC", AssetType: "crypto"},
+		{ID: "sec-2", Symbol: "ETH", AssetType: "crypto"},
+		{ID: "sec-3", Symbol: "OBSCURECOIN", AssetType: "crypto"},
+		{ID: "sec-4", Symbol: "SOL", AssetType: "crypto"},
+	}
+
+	results, fetchErrors := p.FetchPrices(context.Background(), securities)
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+	if len(fetchErrors) != 1 || fetchErrors[0].SecurityID != "sec-3" {
+		t.Fatalf("expected a single error for sec-3, got %v", fetchErrors)
+	}
+
+	expected := map[string]int64{"sec-1": 6723456, "sec-2": 345678, "sec-4": 14250}
+	if len(results) != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), len(results))
+	}
+	for _, r := range results {
+		if r.Price != expected[r.SecurityID] {
+			t.Errorf("security %s: got price %d, want %d", r.SecurityID, r.Price, expected[r.SecurityID])
+		}
+		if r.Currency != "USD" {
+			t.Errorf("security %s: got currency %q, want USD", r.SecurityID, r.Currency)
+		}
+	}
+}
+
 func TestCoinGeckoProvider_FetchPrices_HTTPError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusTooManyRequests)
@@ -10,13 +10,14 @@ import (
 // Security represents a security from the Kuberan API, containing
 // the fields needed by price providers to fetch quotes.
 type Security struct {
-	ID             string
-	Symbol         string
-	AssetType      string
-	Exchange       string
-	ProviderSymbol string
-	Network        string
-	Currency       string
+	ID                string
+	Symbol            string
+	AssetType         string
+	Exchange          string
+	ProviderSymbol    string
+	PreferredProvider string // Name or Source of the only provider to fetch from; empty routes by asset type
+	Network           string
+	Currency          string
 }
 
 // PriceResult represents a successfully fetched price for a security.
@@ -25,6 +26,7 @@ type PriceResult struct {
 	Price      int64  // cents in the native currency reported by the data source
 	Currency   string // ISO 4217 currency code from the data source (e.g. "USD", "MYR", "GBP")
 	RecordedAt time.Time
+	Source     string // Source of the provider that fetched the price
 }
 
 // FetchError represents a failed price fetch for a specific security.
@@ -44,6 +46,10 @@ type Provider interface {
 	// Name returns the provider's display name (e.g., "Yahoo Finance", "CoinGecko").
 	Name() string
 
+	// Source returns the identifier the Kuberan API records with each price
+	// (e.g., "yahoo", "coingecko").
+	Source() string
+
 	// Supports returns true if this provider can fetch prices for the given asset type.
 	Supports(assetType string) bool
 
@@ -52,3 +58,30 @@ type Provider interface {
 	// A provider should return as many prices as possible, even if some fail.
 	FetchPrices(ctx context.Context, securities []Security) ([]PriceResult, []FetchError)
 }
+
+// SecuritySupporter is implemented by providers that route by more than asset
+// type, such as a single exchange. The oracle prefers a provider whose
+// SupportsSecurity returns true over one that only matches the asset type, and
+// never routes a security to a SecuritySupporter that declines it.
+type SecuritySupporter interface {
+	SupportsSecurity(sec Security) bool
+}
+
+// Prober is implemented by providers that can check their data source is
+// reachable before a run. Probe fetches the price of one well-known security
+// and returns why that failed, if it did.
+type Prober interface {
+	Probe(ctx context.Context) error
+}
+
+// probeFetch fetches the price of sec from p as a reachability probe.
+func probeFetch(ctx context.Context, p Provider, sec Security) error {
+	results, errs := p.FetchPrices(ctx, []Security{sec})
+	if len(errs) > 0 {
+		return errs[0].Err
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("no price returned for %s", sec.Symbol)
+	}
+	return nil
+}
@@ -56,8 +56,9 @@ type yahooChartResponse struct {
 
 // YahooProvider fetches prices from Yahoo Finance for stocks, ETFs, bonds, and REITs.
 type YahooProvider struct {
-	httpClient *http.Client
-	baseURL    string // overridable for tests
+	httpClient    *http.Client
+	baseURL       string // overridable for tests
+	maxConcurrent int    // 0 uses yahooMaxConcurrent
 }
 
 // NewYahooProvider creates a new Yahoo Finance price provider.
@@ -65,9 +66,23 @@ func NewYahooProvider(httpClient *http.Client) *YahooProvider {
 	return &YahooProvider{httpClient: httpClient, baseURL: yahooBaseURL}
 }
 
+// SetMaxConcurrent overrides how many requests FetchPrices makes at once; 0
+// restores the default of 10.
+func (p *YahooProvider) SetMaxConcurrent(n int) {
+	p.maxConcurrent = n
+}
+
 // Name returns the provider's display name.
 func (p *YahooProvider) Name() string { return "Yahoo Finance" }
 
+// Source returns the price source identifier.
+func (p *YahooProvider) Source() string { return "yahoo" }
+
+// Probe checks the provider is reachable by fetching the price of Apple on NASDAQ.
+func (p *YahooProvider) Probe(ctx context.Context) error {
+	return probeFetch(ctx, p, Security{ID: "probe", Symbol: "AAPL", AssetType: "stock"})
+}
+
 // Supports returns true for stock, etf, bond, and reit asset types.
 func (p *YahooProvider) Supports(assetType string) bool {
 	switch assetType {
@@ -99,7 +114,11 @@ func (p *YahooProvider) FetchPrices(ctx context.Context, securities []Security)
 	}
 
 	now := time.Now().UTC()
-	sem := make(chan struct{}, yahooMaxConcurrent)
+	limit := p.maxConcurrent
+	if limit <= 0 {
+		limit = yahooMaxConcurrent
+	}
+	sem := make(chan struct{}, limit)
 
 	var mu sync.Mutex
 	var results []PriceResult
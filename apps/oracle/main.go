@@ -2,10 +2,13 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/kuberan/oracle/internal/client"
 	"github.com/kuberan/oracle/internal/config"
@@ -13,14 +16,39 @@ import (
 	"github.com/kuberan/oracle/internal/provider"
 )
 
+// version identifies the build in run records; set with -ldflags "-X main.version=...".
+var version = "dev"
+
 func main() {
+	dryRun := flag.Bool("dry-run", false, "fetch and convert prices without recording prices or computing snapshots")
+	reportFile := flag.String("report-file", "", "write the full run result as JSON to this path")
+	snapshotDate := flag.String("snapshot-date", "", "record snapshots as of the end of this past date (YYYY-MM-DD) instead of now")
+	check := flag.Bool("check", false, "check connectivity to the Kuberan API and every provider, print a JSON report and exit 0 if all passed, 1 otherwise")
+	flag.Parse()
+
 	cfg, err := config.Load()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "configuration error: %v\n", err)
 		os.Exit(1)
 	}
+	cfg.DryRun = *dryRun
+	cfg.ReportFile = *reportFile
+	cfg.Check = *check
+	if *snapshotDate != "" {
+		asOf, err := snapshotAsOf(*snapshotDate, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid -snapshot-date: %v\n", err)
+			os.Exit(1)
+		}
+		cfg.SnapshotAsOf = &asOf
+	}
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+	// With -check, stdout carries only the report
+	logOutput := os.Stdout
+	if cfg.Check {
+		logOutput = os.Stderr
+	}
+	logger := slog.New(slog.NewJSONHandler(logOutput, &slog.HandlerOptions{
 		Level: cfg.LogLevel,
 	}))
 
@@ -30,25 +58,93 @@ func main() {
 
 	forexConverter := provider.NewForexConverter(httpClient, cfg.TargetCurrency)
 
+	yahoo := provider.NewYahooProvider(httpClient)
+	bursa := provider.NewBursaProvider(httpClient)
+	if cfg.MaxConcurrency > 0 {
+		yahoo.SetMaxConcurrent(cfg.MaxConcurrency)
+		bursa.SetMaxConcurrent(cfg.MaxConcurrency)
+	}
 	providers := []provider.Provider{
-		provider.NewYahooProvider(httpClient),
+		yahoo,
+		bursa,
 		provider.NewCoinGeckoProvider(httpClient, cfg.TargetCurrency),
 	}
 
+	providerNames := make([]string, len(providers))
+	for i, p := range providers {
+		providerNames[i] = p.Source()
+	}
 	logger.Info("oracle starting",
+		"version", version,
+		"api_url", cfg.KuberanAPIURL,
+		"providers", providerNames,
 		"target_currency", cfg.TargetCurrency,
+		"request_timeout", cfg.RequestTimeout.String(),
+		"max_concurrency", cfg.MaxConcurrency,
 		"compute_snapshots", cfg.ComputeSnapshots,
+		"dry_run", cfg.DryRun,
+		"check", cfg.Check,
 	)
 
 	orc := oracle.NewOracle(kuberanClient, providers, forexConverter, cfg, logger)
 	ctx := context.Background()
-	result, err := orc.Run(ctx)
+
+	if cfg.Check {
+		report := orc.Check(ctx)
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			logger.Error("failed to write check report", "error", err)
+			os.Exit(1)
+		}
+		if !report.OK {
+			os.Exit(1)
+		}
+		return
+	}
+
+	os.Exit(run(ctx, orc, kuberanClient, cfg, logger))
+}
+
+// run executes one oracle cycle and returns the process exit code. Unless it is
+// a dry run, the result is posted to Kuberan's run history on the way out, so
+// runs that fail or panic are recorded too.
+func run(ctx context.Context, orc *oracle.Oracle, kuberanClient *client.KuberanClient, cfg *config.Config, logger *slog.Logger) int {
+	start := time.Now()
+	var result *oracle.RunResult
+	var err error
+	if !cfg.DryRun {
+		defer func() {
+			p := recover()
+			if p != nil {
+				err = fmt.Errorf("panic: %v", p)
+			}
+			record := oracle.NewRunRecord(result, err, start, version)
+			if recordErr := kuberanClient.RecordRun(context.Background(), record); recordErr != nil {
+				logger.Error("failed to record run", "error", recordErr)
+			}
+			if p != nil {
+				panic(p)
+			}
+		}()
+	}
+
+	result, err = orc.Run(ctx)
 	if err != nil {
 		logger.Error("oracle run failed", "error", err)
-		os.Exit(1)
+		return 1
+	}
+
+	if cfg.ReportFile != "" {
+		if err := oracle.WriteReport(cfg.ReportFile, result); err != nil {
+			logger.Error("failed to write run report", "path", cfg.ReportFile, "error", err)
+		} else {
+			logger.Info("run report written", "path", cfg.ReportFile)
+		}
 	}
 
 	logger.Info("oracle run completed",
+		"dry_run", result.DryRun,
 		"securities_fetched", result.SecuritiesFetched,
 		"prices_recorded", result.PricesRecorded,
 		"snapshots_recorded", result.SnapshotsRecorded,
@@ -65,6 +161,23 @@ func main() {
 	}
 
 	if len(result.Errors) > 0 {
-		os.Exit(2)
+		return 2
+	}
+	return 0
+}
+
+// snapshotAsOf returns the end of the given UTC date, or now if the date is today.
+func snapshotAsOf(date string, now time.Time) (time.Time, error) {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected YYYY-MM-DD, got %q", date)
+	}
+	endOfDay := day.AddDate(0, 0, 1).Add(-time.Second)
+	if endOfDay.After(now) {
+		if day.After(now) {
+			return time.Time{}, fmt.Errorf("%s is in the future", date)
+		}
+		return now.UTC().Truncate(time.Second), nil
 	}
+	return endOfDay, nil
 }
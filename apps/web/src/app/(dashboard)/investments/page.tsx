@@ -78,8 +78,8 @@ type SortColumn =
 type SortDirection = "asc" | "desc" | null;
 
 function HoldingCard({ investment, onClick }: { investment: Investment; onClick: () => void }) {
-  const marketValue = Math.round(investment.quantity * investment.current_price);
-  const unrealizedGL = marketValue - investment.cost_basis;
+  const marketValue = investment.current_value;
+  const unrealizedGL = investment.unrealized_gain_loss;
   const isUnrealizedPositive = unrealizedGL >= 0;
   const isRealizedPositive = investment.realized_gain_loss >= 0;
 
@@ -204,14 +204,12 @@ function AllHoldingsTable() {
           bVal = b.current_price;
           break;
         case "market_value":
-          aVal = Math.round(a.quantity * a.current_price);
-          bVal = Math.round(b.quantity * b.current_price);
+          aVal = a.current_value;
+          bVal = b.current_value;
           break;
         case "unrealized_gl":
-          aVal =
-            Math.round(a.quantity * a.current_price) - a.cost_basis;
-          bVal =
-            Math.round(b.quantity * b.current_price) - b.cost_basis;
+          aVal = a.unrealized_gain_loss;
+          bVal = b.unrealized_gain_loss;
           break;
         case "realized_gl":
           aVal = a.realized_gain_loss;
@@ -349,10 +347,8 @@ function AllHoldingsTable() {
                 </TableHeader>
                 <TableBody>
                   {sortedInvestments.map((inv) => {
-                    const marketValue = Math.round(
-                      inv.quantity * inv.current_price
-                    );
-                    const gainLoss = marketValue - inv.cost_basis;
+                    const marketValue = inv.current_value;
+                    const gainLoss = inv.unrealized_gain_loss;
                     const isPositive = gainLoss >= 0;
                     return (
                       <TableRow
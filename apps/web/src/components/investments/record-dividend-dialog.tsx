@@ -151,6 +151,7 @@ export function RecordDividendDialog({
               <SelectContent>
                 <SelectItem value="cash">Cash</SelectItem>
                 <SelectItem value="stock">Stock</SelectItem>
+                <SelectItem value="return_of_capital">Return of capital</SelectItem>
                 <SelectItem value="special">Special</SelectItem>
               </SelectContent>
             </Select>
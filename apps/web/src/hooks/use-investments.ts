@@ -8,6 +8,7 @@ import type {
   PortfolioResponse,
   InvestmentTransactionResponse,
   AddInvestmentRequest,
+  AddInvestmentResponse,
   RecordBuyRequest,
   RecordSellRequest,
   RecordDividendRequest,
@@ -98,7 +99,7 @@ export function useAddInvestment() {
   const queryClient = useQueryClient();
   return useMutation({
     mutationFn: async (data: AddInvestmentRequest) => {
-      const res = await apiClient.post<InvestmentResponse>(
+      const res = await apiClient.post<AddInvestmentResponse>(
         "/api/v1/investments",
         data
       );
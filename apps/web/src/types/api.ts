@@ -1,16 +1,23 @@
 import type {
   Account,
+  AccountGroup,
+  AssetType,
   Budget,
   BudgetPeriod,
+  BudgetBurndown,
   BudgetProgress,
+  ShareRole,
   Category,
   Investment,
   InvestmentTransaction,
   PortfolioSummary,
+  TaxReport,
+  AccountType,
   Security,
   Transaction,
   User,
   TransactionType,
+  TransactionStatus,
   CategoryType,
 } from "./models";
 
@@ -19,13 +26,15 @@ export interface PageResponse<T> {
   data: T[];
   page: number;
   page_size: number;
-  total_items: number;
-  total_pages: number;
+  total_items: number; // -1 when requested with with_total=false
+  total_pages: number; // 0 when requested with with_total=false
+  meta?: { warning?: string }; // set when page_size was clamped to 100
 }
 
 export interface PaginationParams {
   page?: number;
   page_size?: number;
+  with_total?: boolean; // default true; false skips counting
 }
 
 // Error response (matches backend { error: { code, message } })
@@ -36,6 +45,18 @@ export interface ApiError {
   };
 }
 
+// Entry of GET /meta/errors, the catalog of every error code
+export interface ErrorCatalogEntry {
+  code: string;
+  status: number; // HTTP status the code is answered with
+  message: string; // default message; responses may be more specific
+  hint: string;
+}
+
+export interface ErrorCatalogResponse {
+  errors: ErrorCatalogEntry[];
+}
+
 // Auth requests
 export interface LoginRequest {
   email: string;
@@ -65,6 +86,30 @@ export interface ProfileResponse {
   user: User;
 }
 
+// Backup archive from GET /profile/backup; posted back unchanged to restore
+export interface BackupArchive {
+  format: "kuberan-backup";
+  schema_version: number;
+  created_at: string; // ISO 8601
+  checksums: Record<string, string>; // section name -> SHA-256
+  data: Record<string, unknown[]>;
+}
+
+export interface RestoreGroupProgress {
+  group: string;
+  status: "done" | "skipped" | "failed" | "pending";
+  restored: number;
+  balances_adjusted?: number;
+  error?: string;
+}
+
+// Restore report; when completed is false, posting the same archive resumes
+export interface RestoreReport {
+  archive_id: string;
+  completed: boolean;
+  groups: RestoreGroupProgress[];
+}
+
 // Single-item response wrappers (backend wraps single items in a key)
 export interface AccountResponse {
   account: Account;
@@ -74,6 +119,16 @@ export interface TransactionResponse {
   transaction: Transaction;
 }
 
+// Creating a transaction or transfer also returns the new account balances
+export interface CreateTransactionResponse extends TransactionResponse {
+  account_balance: number;
+}
+
+export interface CreateTransferResponse extends TransactionResponse {
+  from_account_balance: number;
+  to_account_balance?: number;
+}
+
 export interface CategoryResponse {
   category: Category;
 }
@@ -88,6 +143,9 @@ export interface CreateCashAccountRequest {
   description?: string;
   currency?: string; // ISO 4217, defaults to USD
   initial_balance?: number; // cents, >= 0
+  initial_balance_decimal?: string; // alternative to initial_balance, e.g. "12.34" in the account currency
+  allow_negative?: boolean; // defaults to true
+  min_balance?: number; // cents, >= 0; enforced when allow_negative is false
 }
 
 export interface CreateInvestmentAccountRequest {
@@ -111,6 +169,11 @@ export interface UpdateAccountRequest {
   name?: string;
   description?: string;
   is_active?: boolean;
+  exclude_from_reports?: boolean;
+  default_category_id?: string; // empty string clears it
+  group_id?: string; // empty string ungroups the account
+  allow_negative?: boolean; // cash accounts
+  min_balance?: number; // cash accounts, cents, >= 0
   broker?: string;
   account_number?: string;
   interest_rate?: number;
@@ -118,20 +181,58 @@ export interface UpdateAccountRequest {
   credit_limit?: number;
 }
 
+// Account group requests and responses
+export interface AccountGroupRequest {
+  name: string; // 1-100 chars
+}
+
+export interface ReorderAccountGroupsRequest {
+  group_ids: string[]; // every group, in the new order
+}
+
+// GET /accounts?grouped=true; the last group is the default "Ungrouped" one (empty id)
+export interface GroupedAccountsResponse {
+  groups: { group: AccountGroup; accounts: Account[] }[];
+}
+
+// Account sharing requests
+export interface CreateShareRequest {
+  email: string;
+  role: ShareRole;
+  account_ids?: string[]; // UUIDv7, omit to share all accounts
+}
+
 // Transaction requests
 export interface CreateTransactionRequest {
   account_id: string; // UUIDv7
   category_id?: string; // UUIDv7
   type: TransactionType;
-  amount: number; // cents, > 0
+  amount?: number; // minor units, > 0; required unless amount_decimal is set
+  amount_decimal?: string; // decimal in the account currency, e.g. "12.34" (USD) or "1500" (JPY)
   description?: string;
-  date?: string; // ISO 8601
+  date?: string; // ISO 8601, future dates are created pending
+  is_pending?: boolean; // hold off the balance change until settled
+  status?: TransactionStatus; // defaults to cleared
 }
 
 export interface CreateTransferRequest {
   from_account_id: string; // UUIDv7
   to_account_id: string; // UUIDv7
-  amount: number; // cents, > 0
+  amount?: number; // minor units, > 0; required unless amount_decimal is set
+  amount_decimal?: string; // decimal in the source account currency
+  description?: string;
+  date?: string; // ISO 8601
+}
+
+export interface SplitTransferLeg {
+  to_account_id: string; // UUIDv7, each destination at most once
+  amount?: number; // minor units, > 0; required unless amount_decimal is set
+  amount_decimal?: string; // decimal in the source account currency
+}
+
+export interface CreateSplitTransferRequest {
+  from_account_id: string; // UUIDv7
+  legs: SplitTransferLeg[]; // at least two; their total must fit the source balance
   description?: string;
   date?: string; // ISO 8601
 }
@@ -140,9 +241,53 @@ export interface UpdateTransactionRequest {
   account_id?: string; // UUIDv7
   category_id?: string | null; // UUIDv7
   type?: TransactionType;
-  amount?: number; // cents, > 0
+  amount?: number; // minor units, > 0
+  amount_decimal?: string; // decimal in the transaction account currency
   description?: string;
   date?: string; // ISO 8601
+  status?: TransactionStatus; // the only field that may change on transfers
+}
+
+export interface BulkFilter {
+  from_date: string; // ISO 8601
+  to_date: string; // ISO 8601
+  type?: TransactionType; // income, expense or transfer
+  category_id?: string; // UUIDv7
+  uncategorized?: boolean; // only transactions without a category; not with category_id
+  min_amount?: number;
+  max_amount?: number;
+  account_id?: string; // UUIDv7
+  pending?: boolean;
+}
+
+// Dry run (default): send ids or filter. Execute: dry_run false with only confirmation_token.
+export interface BulkDeleteTransactionsRequest {
+  dry_run?: boolean;
+  ids?: string[]; // UUIDv7, at most 10000
+  filter?: BulkFilter;
+  confirmation_token?: string;
+}
+
+export interface BulkDeletePreview {
+  count: number;
+  total_amount: number; // minor units
+  confirmation_token: string;
+  expires_at: string;
+}
+
+export interface BulkDeleteResult {
+  deleted: number;
+}
+
+// Sets the category on the income and expenses of the user's own accounts that
+// match the filter and the category's type. A null category_id clears it.
+export interface BulkCategorizeTransactionsRequest {
+  filter: BulkFilter;
+  category_id: string | null; // UUIDv7
+}
+
+export interface BulkCategorizeResult {
+  updated: number;
 }
 
 export interface TransactionFilters extends PaginationParams {
@@ -150,12 +295,15 @@ export interface TransactionFilters extends PaginationParams {
   to_date?: string;
   type?: TransactionType;
   category_id?: string; // UUIDv7
+  uncategorized?: boolean;
   min_amount?: number;
   max_amount?: number;
+  status?: TransactionStatus;
 }
 
 export interface UserTransactionFilters extends TransactionFilters {
   account_id?: string; // UUIDv7
+  pending?: boolean;
 }
 
 // Category requests
@@ -185,6 +333,10 @@ export interface BudgetProgressResponse {
   progress: BudgetProgress;
 }
 
+export interface BudgetBurndownResponse {
+  burndown: BudgetBurndown;
+}
+
 // Budget requests
 export interface CreateBudgetRequest {
   category_id: string; // UUIDv7
@@ -206,6 +358,8 @@ export interface UpdateBudgetRequest {
 export interface BudgetFilters extends PaginationParams {
   is_active?: boolean;
   period?: BudgetPeriod;
+  include?: 'progress';
+  include_pending?: boolean; // with include=progress, default true
 }
 
 // Chart/analytics response types
@@ -219,22 +373,139 @@ export interface SpendingByCategoryItem {
 
 export interface SpendingByCategory {
   items: SpendingByCategoryItem[];
+  total_spent: number; // cents; total income in income mode
+  mode: "expense" | "income";
+  from_date: string; // ISO 8601
+  to_date: string; // ISO 8601
+}
+
+export interface SpendingByCurrencyItem {
+  currency: string; // original currency, or the account's currency when none was recorded
+  original_total: number; // minor units of currency
+  total: number; // cents as booked in the accounts' currencies
+  count: number;
+}
+
+export interface SpendingByCurrency {
+  items: SpendingByCurrencyItem[];
   total_spent: number; // cents
   from_date: string; // ISO 8601
   to_date: string; // ISO 8601
 }
 
+export interface MonthlyCategoryExpense {
+  category_id: string | null;
+  category_name: string;
+  total: number; // cents
+}
+
 export interface MonthlySummaryItem {
   month: string; // "2026-02"
   income: number; // cents
   expenses: number; // cents
+  categories?: MonthlyCategoryExpense[]; // only with ?with_categories=true
+}
+
+export interface SpendingByAccountItem {
+  account_id: string;
+  account_name: string;
+  is_active: boolean;
+  total: number; // cents
+  count: number;
+}
+
+export interface SpendingByAccount {
+  items: SpendingByAccountItem[];
+  total_spent: number; // cents
+  from_date: string; // ISO 8601
+  to_date: string; // ISO 8601
 }
 
+export type SpendingGranularity = "day" | "week" | "month";
+
 export interface DailySpendingItem {
-  date: string; // "2026-02-01"
+  date: string; // "2026-02-01", first day of the day/week/month bucket
   total: number; // cents
 }
 
+export interface StatementAccount {
+  account_id: string; // UUIDv7
+  name: string;
+  type: AccountType;
+  currency: string;
+  opening_balance: number; // minor units
+  closing_balance: number; // minor units
+  cleared_balance: number; // minor units, closing balance less transactions not yet cleared
+}
+
+export interface StatementBudget extends BudgetProgress {
+  name: string;
+  category_name: string;
+  period: BudgetPeriod;
+  exceeded: boolean;
+}
+
+// Monthly statement from GET /statements/:month
+export interface MonthlyStatement {
+  month: string; // "2025-10"
+  accounts: StatementAccount[];
+  income: number; // cents
+  expenses: number; // cents
+  net: number; // cents
+  top_categories: SpendingByCategoryItem[];
+  budgets: StatementBudget[];
+  investments: {
+    opening_value: number; // cents
+    closing_value: number; // cents
+    change: number; // cents
+  };
+  transaction_count: number;
+}
+
+export interface MonthlyStatementResponse {
+  statement: MonthlyStatement;
+}
+
+// Usage stats; dates and busiest_weekday are null without activity
+export interface UsageStats {
+  transaction_count: number;
+  first_activity: string | null; // ISO 8601
+  last_activity: string | null; // ISO 8601
+  average_amount: number; // cents
+  busiest_weekday: string | null; // e.g. "Monday", UTC
+}
+
+export interface AccountUsageStats extends UsageStats {
+  account_id: string; // UUIDv7
+  name: string;
+  type: AccountType;
+  currency: string;
+  is_active: boolean;
+}
+
+export interface CategoryUsageStats extends UsageStats {
+  category_id: string; // UUIDv7
+  name: string;
+  type: CategoryType;
+}
+
+export interface AccountStatsResponse {
+  accounts: AccountUsageStats[];
+}
+
+export interface CategoryStatsResponse {
+  categories: CategoryUsageStats[];
+}
+
+// Entry of GET /accounts/:id/timeline; the record matching kind is set
+export type AccountTimelineEntry =
+  | { kind: "transaction"; date: string; transaction: Transaction }
+  | {
+      kind: "investment_transaction";
+      date: string;
+      investment_transaction: InvestmentTransaction;
+    };
+
 // Investment response wrappers
 export interface InvestmentResponse {
   investment: Investment;
@@ -252,50 +523,87 @@ export interface PortfolioResponse {
   portfolio: PortfolioSummary;
 }
 
+export interface TaxReportResponse {
+  report: TaxReport;
+}
+
 // Investment requests
 export interface AddInvestmentRequest {
   account_id: string; // UUIDv7
-  security_id: string; // UUIDv7
+  security_id?: string; // UUIDv7; or give symbol, name and asset_type instead
+  symbol?: string; // finds or adds a catalog security, with name and asset_type
+  name?: string;
+  asset_type?: AssetType;
+  currency?: string; // ISO 4217, for a security given by symbol; defaults to USD
+  exchange?: string; // for a security given by symbol
   quantity: number; // float, > 0
-  purchase_price: number; // cents, > 0
+  purchase_price?: number; // minor units, > 0; required unless purchase_price_decimal is set
+  purchase_price_decimal?: string; // decimal in the security currency
   wallet_address?: string;
   date?: string; // ISO 8601, defaults to now
   fee?: number; // cents, >= 0, defaults to 0
   notes?: string; // max 500, defaults to "Initial purchase"
+  from_account_id?: string; // UUIDv7, cash account debited for the purchase
+  holding_notes?: string; // max 2000, stored on the holding
+  target_price?: number; // cents, >= 0
+  force_new?: boolean; // open a separate holding instead of adding to an existing one
+}
+
+export interface AddInvestmentResponse {
+  investment: Investment;
+  merged: boolean; // true when the purchase was recorded as a buy on an existing holding
+}
+
+export interface UpdateInvestmentRequest {
+  notes?: string; // max 2000
+  target_price?: number; // cents, >= 0; 0 clears the target
 }
 
 export interface RecordBuyRequest {
   date: string; // ISO 8601
   quantity: number; // float, > 0
-  price_per_unit: number; // cents, > 0
+  price_per_unit?: number; // minor units, > 0; required unless price_per_unit_decimal is set
+  price_per_unit_decimal?: string; // decimal in the security currency
   fee?: number; // cents, >= 0
   notes?: string;
+  from_account_id?: string; // UUIDv7, cash account debited for the purchase
+  confirm_price?: boolean; // record a price the API flagged with PRICE_DEVIATION_WARNING
 }
 
 export interface RecordSellRequest {
   date: string; // ISO 8601
   quantity: number; // float, > 0
-  price_per_unit: number; // cents, > 0
+  price_per_unit?: number; // minor units, > 0; required unless price_per_unit_decimal is set
+  price_per_unit_decimal?: string; // decimal in the security currency
   fee?: number; // cents, >= 0
   notes?: string;
+  confirm_price?: boolean; // record a price the API flagged with PRICE_DEVIATION_WARNING
 }
 
 export interface RecordDividendRequest {
   date: string; // ISO 8601
-  amount: number; // cents, > 0
+  amount?: number; // minor units, > 0; required unless amount_decimal is set
+  amount_decimal?: string; // decimal in the security currency
   dividend_type?: string;
   notes?: string;
+  external_ref?: string; // idempotency key, max 100 chars
 }
 
 export interface RecordSplitRequest {
   date: string; // ISO 8601
-  split_ratio: number; // float, > 0
+  split_ratio: number; // float, > 0; below 1 is a reverse split
   notes?: string;
+  external_ref?: string; // idempotency key, max 100 chars
+  rounding?: "none" | "down" | "nearest"; // rounding of the new quantity, default none
+  cash_in_lieu_price?: number; // minor units per post-split unit; pays the rounded-away fraction as a dividend
 }
 
 // Security filters
 export interface SecurityFilters extends PaginationParams {
   search?: string;
+  asset_type?: AssetType;
+  exchange?: string; // matched case-insensitively
+  currency?: string; // ISO 4217
 }
 
 // Portfolio snapshot filters
@@ -303,3 +611,40 @@ export interface PortfolioSnapshotFilters extends PaginationParams {
   from_date: string;
   to_date: string;
 }
+
+// Portfolio vs benchmark comparison; returns are percentages
+export interface BenchmarkParams {
+  security_id: string;
+  from_date: string;
+  to_date: string;
+}
+
+export interface BenchmarkComparison {
+  benchmark_security_id: string;
+  benchmark_symbol: string;
+  from: string;
+  to: string;
+  portfolio_start: number;
+  portfolio_end: number;
+  portfolio_return: number;
+  benchmark_start: number;
+  benchmark_end: number;
+  benchmark_return: number;
+  difference: number; // portfolio minus benchmark, in percentage points
+}
+
+// Exchange rates: one unit of `from` buys `rate` units of `to`
+export interface ExchangeRateParams {
+  base?: string; // ISO 4217; resolves base→every currency the way the server converts
+}
+
+export interface ExchangeRateQuote {
+  from: string;
+  to: string;
+  rate: number;
+  as_of: string; // ISO 8601; for cross rates via USD, the older leg's date
+}
+
+export interface ExchangeRatesResponse {
+  rates: ExchangeRateQuote[];
+}
@@ -14,9 +14,11 @@ export interface BaseModel {
 export interface User {
   id: string; // UUIDv7
   email: string;
+  username?: string | null; // optional login name; changeable once per 30 days
   first_name: string;
   last_name: string;
   is_active?: boolean;
+  is_demo?: boolean; // generated demo account, purged periodically
   last_login_at?: string | null; // ISO 8601
 }
 
@@ -31,6 +33,14 @@ export interface Account extends BaseModel {
   balance: number; // cents
   currency: string; // ISO 4217
   is_active: boolean;
+  opening_date?: string; // ISO 8601, set when the account was backdated at creation
+  archived_at?: string; // ISO 8601, set when the account is deactivated
+  archived_balance?: number; // cents, balance at archival time
+  exclude_from_reports: boolean; // left out of net worth and spending reports
+  default_category_id?: string; // applied to new uncategorized transactions
+  group_id?: string; // UUIDv7, absent for ungrouped accounts
+  allow_negative: boolean; // cash accounts; when false, outflows may not go below min_balance
+  min_balance: number; // cents, cash accounts
   broker?: string; // investment accounts
   account_number?: string; // investment accounts
   interest_rate?: number; // debt/credit_card accounts (float)
@@ -38,9 +48,33 @@ export interface Account extends BaseModel {
   credit_limit?: number; // credit_card accounts (cents)
 }
 
+// Account groups
+export interface AccountGroup extends BaseModel {
+  user_id: string; // UUIDv7
+  name: string;
+  sort_order: number;
+}
+
+// Account sharing
+export type ShareRole = "viewer" | "editor";
+
+export interface AccountShare extends BaseModel {
+  owner_id: string; // UUIDv7
+  shared_with_id: string; // UUIDv7
+  role: ShareRole;
+  all_accounts: boolean;
+  accepted_at?: string; // ISO 8601, absent while pending
+  account_ids?: string[]; // UUIDv7, when all_accounts is false
+}
+
 // Transaction types
 export type TransactionType = "income" | "expense" | "transfer" | "investment";
 
+export type TransactionDirection = "in" | "out";
+
+// Reconciliation with the bank; unrelated to is_pending
+export type TransactionStatus = "pending" | "cleared";
+
 export interface Transaction extends BaseModel {
   user_id: string; // UUIDv7
   account_id: string; // UUIDv7
@@ -49,7 +83,14 @@ export interface Transaction extends BaseModel {
   amount: number; // cents, always positive
   description: string;
   date: string; // ISO 8601
+  is_pending: boolean; // not yet applied to the account balance
+  status: TransactionStatus; // cleared once the bank has posted it
+  original_amount?: number; // minor units of original_currency; reference only, amount drives the balance
+  original_currency?: string; // ISO 4217, set together with original_amount
+  flagged: boolean; // computed: amount reaches the user's large_transaction_threshold
+  direction?: TransactionDirection; // computed relative to the listed account(s); amount is always positive. Absent for transfers between two listed accounts
   to_account_id?: string | null; // UUIDv7, for transfers
+  transfer_group_id?: string; // shared by the legs of a split transfer; deleting one deletes all
   account?: Account; // preloaded relation
   to_account?: Account | null; // preloaded relation for transfers
   category?: Category | null; // preloaded relation
@@ -91,6 +132,43 @@ export interface BudgetProgress {
   spent: number; // cents
   remaining: number; // cents
   percentage: number; // float, (spent/budgeted)*100
+  period_start: string; // ISO 8601, period start or the budget's start_date if later
+  period_end: string; // ISO 8601, period end or the budget's end_date if earlier
+  pace: BudgetPace; // spend so far extrapolated to the whole period
+}
+
+export type BudgetPaceStatus = "on_track" | "at_risk" | "over";
+
+export interface BudgetPace {
+  days_elapsed: number; // including today; 0 before the period starts
+  days_in_period: number;
+  projected_spend: number; // cents, spent / days_elapsed * days_in_period
+  status: BudgetPaceStatus;
+}
+
+// A budget listed with include=progress
+export interface BudgetWithProgress extends Budget {
+  spent: number; // cents
+  remaining: number; // cents
+  percentage: number; // float, (spent/budgeted)*100
+}
+
+export interface BudgetBurndownPoint {
+  date: string; // ISO 8601, midnight starting the day
+  spent: number; // cents, spent that day
+  cumulative: number; // cents, spent from period start through this day
+}
+
+export interface BudgetBurndown {
+  budget_id: string; // UUIDv7
+  budgeted: number; // cents
+  spent: number; // cents, settled spend so far
+  projected_spend: number; // cents, spent / days_elapsed * days_in_period
+  period_start: string; // ISO 8601
+  period_end: string; // ISO 8601
+  days_elapsed: number; // including today
+  days_in_period: number;
+  series: BudgetBurndownPoint[]; // one point per day up to today
 }
 
 // Asset types
@@ -110,12 +188,28 @@ export interface Security extends BaseModel {
   property_type?: string; // REITs
 }
 
+// The caller's open position in a security, summed across accounts
+export interface SecurityHolding {
+  quantity: number; // float
+  cost_basis: number; // cents
+  current_value: number; // cents
+  gain_loss: number; // cents
+}
+
+// Security as listed by GET /securities
+export interface SecurityWithHolding extends Security {
+  holding: SecurityHolding | null;
+}
+
 // Security price (time-series, no soft deletes)
+export type PriceSource = "yahoo" | "coingecko" | "bursa" | "manual";
+
 export interface SecurityPrice {
   id: string; // UUIDv7
   security_id: string; // UUIDv7
   price: number; // cents
   recorded_at: string; // ISO 8601
+  source?: PriceSource; // absent for prices recorded before sources were tracked
   security?: Security;
 }
 
@@ -137,7 +231,18 @@ export interface Investment extends BaseModel {
   cost_basis: number; // cents
   realized_gain_loss: number; // cents — accumulated realized P&L from sells
   current_price: number; // cents per unit, populated at query time from security_prices
+  current_price_as_of: string | null; // ISO 8601 recorded_at of the price above; null when never priced
   wallet_address?: string; // crypto
+  notes: string; // free-form holding notes, e.g. investment thesis
+  target_price: number | null; // cents per unit the user is watching for
+  at_target: boolean; // true once current_price reaches target_price
+  price_pending: boolean; // true until the security has a recorded price
+  current_value: number; // cents, quantity * current_price; 0 while price_pending
+  unrealized_gain_loss: number; // cents, current_value - cost_basis
+  gain_loss_pct: number; // percent of cost_basis; 0 when cost_basis is 0
+  day_change: number | null; // cents, value change since the previous price; null with only one price
+  total_fees: number; // cents, fees paid on buys and sells; 0 outside GET /investments/:id
+  total_dividends: number; // cents, dividends received; 0 outside GET /investments/:id
   security: Security; // preloaded relation
   account?: Account; // preloaded relation
 }
@@ -150,6 +255,9 @@ export type InvestmentTransactionType =
   | "split"
   | "transfer";
 
+// How a sell's cost basis is matched; chosen per user on the profile
+export type CostBasisMethod = "average" | "fifo";
+
 export interface InvestmentTransaction extends BaseModel {
   investment_id: string; // UUIDv7
   type: InvestmentTransactionType;
@@ -160,16 +268,78 @@ export interface InvestmentTransaction extends BaseModel {
   fee: number; // cents
   notes: string;
   realized_gain_loss: number; // cents — realized P&L for this specific sell
+  cost_basis_method?: CostBasisMethod; // sells only: method used for realized_gain_loss
   split_ratio?: number; // float, for splits
+  split_remainder?: number; // float, post-split quantity rounded away (negative when rounded up)
   dividend_type?: string; // for dividends
+  cash_transaction_id?: string; // UUIDv7, transfer that funded a buy
+  external_ref?: string; // source-provided idempotency key
+  running_quantity: number; // float, units held after this transaction (0 outside the per-investment list)
+  running_cost_basis: number; // cents, cost basis after this transaction
   investment?: Investment; // preloaded relation
 }
 
+export interface PortfolioHolding {
+  investment_id: string; // UUIDv7
+  security_id: string; // UUIDv7
+  symbol: string;
+  asset_type: AssetType;
+  quantity: number;
+  currency: string; // the security's currency
+  value_currency: string; // currency of the latest price; differs from currency when it was converted
+  value: number; // minor units of value_currency
+  cost_basis: number; // minor units of currency
+  exchange_rate: number | null; // value_currency → portfolio currency; null without a rate
+  converted_value: number | null; // minor units of the portfolio currency
+  sparkline?: Sparkline; // only with ?include=sparklines and a recorded price
+}
+
+export interface SparklineClose {
+  date: string; // YYYY-MM-DD (UTC)
+  price: number; // minor units of the security's currency
+}
+
+export interface Sparkline {
+  closes: SparklineClose[]; // up to 7 daily closes, oldest first
+  change_pct: number | null; // first to last close; null with fewer than two
+}
+
 export interface PortfolioSummary {
+  currency: string; // user's base currency; every total is in it
   total_value: number; // cents
   total_cost_basis: number; // cents
   total_gain_loss: number; // cents
   gain_loss_pct: number; // float percentage
   total_realized_gain_loss: number; // cents
+  total_dividends: number; // cents
+  total_return: number; // cents, realized + unrealized + dividends
   holdings_by_type: Record<AssetType, { value: number; count: number }>;
+  holdings: PortfolioHolding[];
+  unconverted_currencies: string[]; // holdings in these are left out of totals
+}
+
+export type HoldingPeriod = "short" | "long";
+
+export interface TaxReportSale {
+  transaction_id: string; // UUIDv7
+  investment_id: string; // UUIDv7
+  symbol: string;
+  currency: string;
+  sold_at: string; // ISO 8601
+  acquired_at: string; // ISO 8601, earliest matched buy
+  quantity: number;
+  proceeds: number; // cents
+  cost_basis: number; // cents, FIFO-matched
+  realized_gain: number; // cents
+  holding_period: HoldingPeriod;
+}
+
+export interface TaxReport {
+  year: number;
+  sales: TaxReportSale[];
+  total_proceeds: number; // cents
+  total_cost_basis: number; // cents
+  short_term_gain: number; // cents
+  long_term_gain: number; // cents
+  total_gain: number; // cents
 }